@@ -0,0 +1,168 @@
+// Package apicontract checks HTTP responses against the committed public
+// OpenAPI document (docs/api/openapi.public.json), so a handler whose status
+// codes or payloads drift from its swag annotations fails a test rather than
+// an integrator's generated client.
+//
+// Schema validation is kin-openapi's openapi3filter, the library the apispec
+// tool already builds the document with. On top of it, Check requires every
+// non-error status to be declared by the operation itself: the public spec
+// gives each operation a default ErrorResponse, and falling back to it would
+// let a GET that answers 202 pass as an "unmodeled error".
+package apicontract
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+)
+
+// ErrNoOperation is returned by Check for a request the document does not
+// describe, such as an internal-only route or a method the path lacks.
+var ErrNoOperation = errors.New("apicontract: no operation in the document")
+
+// publicSpec is the public document's path relative to the repository root.
+var publicSpec = filepath.Join("docs", "api", "openapi.public.json")
+
+// Checker validates responses against one OpenAPI document.
+type Checker struct {
+	doc    *openapi3.T
+	router routers.Router
+}
+
+// Load reads and validates the document at path.
+func Load(path string) (*Checker, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("apicontract: %w", err)
+	}
+	return New(data)
+}
+
+// LoadPublic loads the committed public document, searching upward from the
+// working directory so tests in any package can find it.
+func LoadPublic() (*Checker, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("apicontract: %w", err)
+	}
+	for {
+		path := filepath.Join(dir, publicSpec)
+		if _, err := os.Stat(path); err == nil {
+			return Load(path)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, fmt.Errorf("apicontract: %s not found above the working directory", publicSpec)
+		}
+		dir = parent
+	}
+}
+
+// New parses and validates an OpenAPI 3 document.
+func New(data []byte) (*Checker, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("apicontract: parse document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("apicontract: invalid document: %w", err)
+	}
+	// The servers name production hosts; tests serve from httptest, so
+	// routes match on the path alone.
+	doc.Servers = nil
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("apicontract: build router: %w", err)
+	}
+	return &Checker{doc: doc, router: router}, nil
+}
+
+// Operations lists every operation in the document as "METHOD /path/{param}",
+// sorted.
+func (c *Checker) Operations() []string {
+	var ops []string
+	for path, item := range c.doc.Paths.Map() {
+		for method := range item.Operations() {
+			ops = append(ops, method+" "+path)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// Check validates one response to req and returns the operation it belongs
+// to. The status must be declared by the operation (4xx and 5xx may fall
+// back to default), a body must match the declared schema, and a response
+// that declares no content must have an empty body. It returns
+// ErrNoOperation when the document does not describe req.
+func (c *Checker) Check(req *http.Request, status int, header http.Header, body []byte) (string, error) {
+	route, params, err := c.router.FindRoute(req)
+	if err != nil {
+		var rerr *routers.RouteError
+		if errors.As(err, &rerr) {
+			return "", ErrNoOperation
+		}
+		return "", fmt.Errorf("apicontract: find route: %w", err)
+	}
+	op := route.Method + " " + route.Path
+
+	declared := route.Operation.Responses.Status(status)
+	if declared == nil && status < http.StatusBadRequest {
+		return op, fmt.Errorf("%s: status %d is not declared (declared: %s)",
+			op, status, strings.Join(declaredStatuses(route.Operation), ", "))
+	}
+	if declared == nil {
+		declared = route.Operation.Responses.Default()
+	}
+	if declared != nil && declared.Value != nil && len(declared.Value.Content) == 0 && len(body) > 0 {
+		return op, fmt.Errorf("%s: status %d declares no body but returned %q", op, status, truncate(body))
+	}
+
+	input := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: params,
+			Route:      route,
+		},
+		Status: status,
+		Header: header,
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+		},
+	}
+	input.SetBodyBytes(body)
+	if err := openapi3filter.ValidateResponse(req.Context(), input); err != nil {
+		return op, fmt.Errorf("%s: status %d: %w (body %q)", op, status, err, truncate(body))
+	}
+	return op, nil
+}
+
+// declaredStatuses lists the response keys of op, sorted.
+func declaredStatuses(op *openapi3.Operation) []string {
+	var keys []string
+	for k := range op.Responses.Map() {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// truncate keeps failure messages readable when a body is large.
+func truncate(body []byte) string {
+	const limit = 512
+	if len(body) <= limit {
+		return string(body)
+	}
+	return string(body[:limit]) + "..."
+}
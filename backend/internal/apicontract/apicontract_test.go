@@ -0,0 +1,191 @@
+package apicontract
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSpec is a two-operation document shaped like the public spec: typed
+// successes, a discriminated oneOf, and a default error envelope.
+const testSpec = `{
+  "openapi": "3.0.3",
+  "info": {"title": "test", "version": "1"},
+  "servers": [{"url": "https://app.example"}],
+  "paths": {
+    "/things/{id}": {
+      "parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}],
+      "get": {
+        "responses": {
+          "200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Thing"}}}},
+          "404": {"description": "Not found", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}},
+          "default": {"description": "Unmodeled", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}}
+        }
+      },
+      "delete": {
+        "responses": {
+          "204": {"description": "deleted"},
+          "default": {"description": "Unmodeled", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Thing": {
+        "type": "object",
+        "required": ["id", "name", "tag"],
+        "properties": {
+          "id": {"type": "integer"},
+          "name": {"type": "string"},
+          "note": {"type": "string", "nullable": true},
+          "tag": {"$ref": "#/components/schemas/Tag"}
+        }
+      },
+      "Tag": {
+        "oneOf": [{"$ref": "#/components/schemas/RfidTag"}, {"$ref": "#/components/schemas/BarcodeTag"}],
+        "discriminator": {"propertyName": "tag_type", "mapping": {"rfid": "#/components/schemas/RfidTag", "barcode": "#/components/schemas/BarcodeTag"}}
+      },
+      "RfidTag": {
+        "type": "object",
+        "required": ["tag_type", "value"],
+        "properties": {"tag_type": {"type": "string", "enum": ["rfid"]}, "value": {"type": "string", "minLength": 1}}
+      },
+      "BarcodeTag": {
+        "type": "object",
+        "required": ["tag_type", "value"],
+        "properties": {"tag_type": {"type": "string", "enum": ["barcode"]}, "value": {"type": "string", "minLength": 1}}
+      },
+      "ErrorResponse": {
+        "type": "object",
+        "required": ["error"],
+        "properties": {
+          "error": {
+            "type": "object",
+            "required": ["type", "title", "status"],
+            "properties": {"type": {"type": "string"}, "title": {"type": "string"}, "status": {"type": "integer"}}
+          }
+        }
+      }
+    }
+  }
+}`
+
+const validThing = `{"id": 7, "name": "Forklift", "note": null, "tag": {"tag_type": "rfid", "value": "E280"}}`
+
+func newTestChecker(t *testing.T) *Checker {
+	t.Helper()
+	c, err := New([]byte(testSpec))
+	require.NoError(t, err)
+	return c
+}
+
+func jsonHeader() http.Header {
+	return http.Header{"Content-Type": []string{"application/json"}}
+}
+
+func TestCheck(t *testing.T) {
+	c := newTestChecker(t)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"valid success", http.MethodGet, "/things/7", 200, validThing, ""},
+		{"declared error", http.MethodGet, "/things/7", 404, `{"error": {"type": "not_found", "title": "Not found", "status": 404}}`, ""},
+		{"error via default", http.MethodGet, "/things/7", 429, `{"error": {"type": "rate_limited", "title": "Slow down", "status": 429}}`, ""},
+		{"no content", http.MethodDelete, "/things/7", 204, "", ""},
+		{"undeclared success", http.MethodGet, "/things/7", 202, validThing, "status 202 is not declared"},
+		{"missing required field", http.MethodGet, "/things/7", 200, `{"id": 7, "tag": {"tag_type": "rfid", "value": "E280"}}`, "name"},
+		{"wrong type", http.MethodGet, "/things/7", 200, `{"id": "7", "name": "Forklift", "tag": {"tag_type": "rfid", "value": "E280"}}`, "id"},
+		{"null for non-nullable", http.MethodGet, "/things/7", 200, `{"id": 7, "name": null, "tag": {"tag_type": "rfid", "value": "E280"}}`, "name"},
+		{"unknown discriminator", http.MethodGet, "/things/7", 200, `{"id": 7, "name": "Forklift", "tag": {"tag_type": "nfc", "value": "x"}}`, "tag_type"},
+		{"bad error envelope", http.MethodGet, "/things/7", 500, `{"message": "boom"}`, "error"},
+		{"body on no-content status", http.MethodDelete, "/things/7", 204, `{}`, "declares no body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			op, err := c.Check(req, tt.status, jsonHeader(), []byte(tt.body))
+			assert.Equal(t, tt.method+" /things/{id}", op)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCheck_NoOperation(t *testing.T) {
+	c := newTestChecker(t)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/internal/things", nil),
+		httptest.NewRequest(http.MethodPut, "/things/7", nil),
+	} {
+		_, err := c.Check(req, 200, jsonHeader(), []byte(`{}`))
+		assert.ErrorIs(t, err, ErrNoOperation, "%s %s", req.Method, req.URL.Path)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	c := newTestChecker(t)
+	rec := c.Recorder()
+	h := rec.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/things/1":
+			_, _ = w.Write([]byte(validThing))
+		case r.URL.Path == "/things/2":
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(validThing))
+		default:
+			_, _ = w.Write([]byte(`"internal"`))
+		}
+	}))
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	// Responses pass through unchanged whether or not they conform.
+	w := serve("/things/1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, validThing, w.Body.String())
+	assert.Equal(t, http.StatusAccepted, serve("/things/2").Code)
+	assert.Equal(t, `"internal"`, serve("/internal").Body.String())
+
+	failures := rec.Failures()
+	require.Len(t, failures, 1)
+	assert.Contains(t, failures[0].Error(), "status 202 is not declared")
+	assert.Equal(t, []string{"DELETE /things/{id}"}, rec.Unexercised())
+	assert.True(t, strings.HasPrefix(rec.String(), "GET /things/{id} [200]"), rec.String())
+}
+
+// TestPublicSpec loads the committed public document, so a regenerated spec
+// that no longer parses or validates fails here first.
+func TestPublicSpec(t *testing.T) {
+	c, err := LoadPublic()
+	require.NoError(t, err)
+
+	ops := c.Operations()
+	assert.Contains(t, ops, "GET /api/v1/assets")
+	assert.Contains(t, ops, "POST /api/v1/oauth/token")
+	// Reads are synchronous; a GET that documents 202 is annotation drift.
+	for path, item := range c.doc.Paths.Map() {
+		if item.Get != nil {
+			assert.Nil(t, item.Get.Responses.Status(http.StatusAccepted), "GET %s declares 202", path)
+		}
+	}
+}
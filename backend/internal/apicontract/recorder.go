@@ -0,0 +1,94 @@
+package apicontract
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Recorder is test middleware that checks every response it serves and
+// remembers which operations answered with which statuses, so a suite can
+// assert both that nothing drifted and that every operation was exercised.
+type Recorder struct {
+	checker *Checker
+
+	mu       sync.Mutex
+	seen     map[string]map[int]bool
+	failures []error
+}
+
+// Recorder returns a Recorder checking against c's document.
+func (c *Checker) Recorder() *Recorder {
+	return &Recorder{checker: c, seen: map[string]map[int]bool{}}
+}
+
+// Wrap serves next and checks each response before passing it on. Requests
+// the document does not describe are served unchecked.
+func (r *Recorder) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rec := httptest.NewRecorder()
+		next.ServeHTTP(rec, req)
+
+		op, err := r.checker.Check(req, rec.Code, rec.Header(), rec.Body.Bytes())
+		r.mu.Lock()
+		switch {
+		case errors.Is(err, ErrNoOperation):
+		case err != nil:
+			r.failures = append(r.failures, err)
+		default:
+			if r.seen[op] == nil {
+				r.seen[op] = map[int]bool{}
+			}
+			r.seen[op][rec.Code] = true
+		}
+		r.mu.Unlock()
+
+		maps.Copy(w.Header(), rec.Header())
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(rec.Body.Bytes())
+	})
+}
+
+// Failures returns every contract violation seen so far.
+func (r *Recorder) Failures() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.failures)
+}
+
+// Unexercised lists the document's operations that have not yet answered
+// with a 2xx status that passed its check.
+func (r *Recorder) Unexercised() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var missing []string
+	for _, op := range r.checker.Operations() {
+		ok := false
+		for status := range r.seen[op] {
+			if status >= 200 && status < 300 {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			missing = append(missing, op)
+		}
+	}
+	return missing
+}
+
+// String summarises what the recorder has seen, for test failure output.
+func (r *Recorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var b strings.Builder
+	for _, op := range slices.Sorted(maps.Keys(r.seen)) {
+		fmt.Fprintf(&b, "%s %v\n", op, slices.Sorted(maps.Keys(r.seen[op])))
+	}
+	return b.String()
+}
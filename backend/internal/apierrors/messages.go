@@ -19,6 +19,7 @@ const (
 	AssetDeleteFailed     = "Failed to delete asset"
 	AssetListFailed       = "Failed to list assets"
 	AssetCountFailed      = "Failed to count assets"
+	AssetSignatureMissing = "Assignment not found or not signed"
 )
 
 // Bulk import error messages
@@ -128,6 +129,7 @@ const (
 	MemberNotFound             = "Member not found"
 	MemberRemoveFailed         = "Failed to remove member"
 	MemberLastAdmin            = "Cannot remove or demote the last admin"
+	MemberIsOwner              = "Cannot remove or demote the organization owner; transfer ownership first"
 	MemberSelfRemoval          = "Cannot remove yourself"
 	MemberInvalidRole          = "Invalid role"
 )
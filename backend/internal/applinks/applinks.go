@@ -0,0 +1,175 @@
+// Package applinks decides where the links the backend mails out (password
+// reset, invitation, email change) or prints (location signage) point. A
+// link's path is always built here; a caller may only suggest the origin,
+// and a suggestion outside the environment's allow-list and the org's own
+// link domains falls back to the environment's base URL. That keeps a
+// forged request from turning a TrakRF email into a link to someone else's
+// site.
+package applinks
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultBaseURL is the frontend origin when APP_BASE_URL is unset.
+const DefaultBaseURL = "https://app.trakrf.id"
+
+// Config is the environment's link policy.
+type Config struct {
+	// BaseURL is the origin links point at unless a request names another
+	// allowed one (APP_BASE_URL).
+	BaseURL string
+	// AllowedOrigins are further origins a request may ask for, such as
+	// preview deployments or a local frontend (APP_LINK_ALLOWED_ORIGINS).
+	AllowedOrigins []string
+	// TenantDomain is the domain whose subdomains name orgs by identifier,
+	// {identifier}.<TenantDomain> (APP_TENANT_DOMAIN). Empty means the
+	// BaseURL host; see TenantHost.
+	TenantDomain string
+}
+
+// ConfigFromEnv reads the link policy:
+//
+//	APP_BASE_URL              frontend origin    (default https://app.trakrf.id)
+//	APP_LINK_ALLOWED_ORIGINS  comma-separated further origins
+//	APP_TENANT_DOMAIN         domain of per-org subdomains (default the APP_BASE_URL host)
+//
+// Every origin must be a bare https origin (http is allowed for localhost);
+// APP_TENANT_DOMAIN is a bare domain name such as trakrf.app.
+func ConfigFromEnv() (Config, error) {
+	c := Config{BaseURL: DefaultBaseURL}
+	if raw := strings.TrimSpace(os.Getenv("APP_BASE_URL")); raw != "" {
+		o, err := NormalizeOrigin(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("APP_BASE_URL: %w", err)
+		}
+		c.BaseURL = o
+	}
+	for _, raw := range strings.Split(os.Getenv("APP_LINK_ALLOWED_ORIGINS"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		o, err := NormalizeOrigin(raw)
+		if err != nil {
+			return Config{}, fmt.Errorf("APP_LINK_ALLOWED_ORIGINS: %w", err)
+		}
+		c.AllowedOrigins = append(c.AllowedOrigins, o)
+	}
+	if raw := strings.ToLower(strings.TrimSpace(os.Getenv("APP_TENANT_DOMAIN"))); raw != "" {
+		u, err := url.Parse("https://" + raw)
+		if err != nil || u.Host != raw || u.Port() != "" || !strings.Contains(raw, ".") {
+			return Config{}, fmt.Errorf("APP_TENANT_DOMAIN: %q is not a domain name like trakrf.app", raw)
+		}
+		c.TenantDomain = raw
+	}
+	return c, nil
+}
+
+// TenantHost is the domain whose single-label subdomains name orgs:
+// TenantDomain, or the BaseURL host when that is unset.
+func (c Config) TenantHost() string {
+	if c.TenantDomain != "" {
+		return c.TenantDomain
+	}
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	u, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// FromEnv is ConfigFromEnv falling back to the defaults on a malformed
+// value. config.Load has already refused to boot on one, so this only
+// matters to tests and tools.
+func FromEnv() Config {
+	c, err := ConfigFromEnv()
+	if err != nil {
+		return Config{BaseURL: DefaultBaseURL}
+	}
+	return c
+}
+
+// NormalizeOrigin returns raw as a lowercase scheme://host[:port] origin.
+// It must be https, or http on localhost, with no credentials, path, query
+// or fragment.
+func NormalizeOrigin(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" || u.User != nil || u.Opaque != "" ||
+		(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%q is not an origin like https://app.example.com", raw)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	switch {
+	case scheme == "https":
+	case scheme == "http" && isLocalhost(u.Hostname()):
+	default:
+		return "", fmt.Errorf("%q must use https (http is only allowed for localhost)", raw)
+	}
+	return scheme + "://" + host, nil
+}
+
+// originOf returns the origin of a URL or origin string, or "".
+func originOf(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	o, err := NormalizeOrigin(u.Scheme + "://" + u.Host)
+	if err != nil {
+		return ""
+	}
+	return o
+}
+
+func isLocalhost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Origin picks the origin for a link. requested is what the caller asked for
+// (an Origin header, or a full URL of which only the origin is used); it wins
+// when it is the base URL, one of AllowedOrigins, or one of orgOrigins.
+// Anything else yields BaseURL.
+func (c Config) Origin(requested string, orgOrigins []string) string {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	o := originOf(requested)
+	if o == "" {
+		return base
+	}
+	if o == base || slices.Contains(c.AllowedOrigins, o) || slices.Contains(orgOrigins, o) {
+		return o
+	}
+	return base
+}
+
+// PasswordResetURL is the reset page under origin; the mailer appends
+// ?token=.
+func PasswordResetURL(origin string) string { return origin + "/#reset-password" }
+
+// EmailChangeURL is the email confirmation page under origin; the mailer
+// appends ?token=.
+func EmailChangeURL(origin string) string { return origin + "/#confirm-email" }
+
+// LocationURL is a location's page under origin, the deep link printed on
+// location signage.
+func LocationURL(origin string, locationID int) string {
+	return origin + "/locations/" + strconv.Itoa(locationID)
+}
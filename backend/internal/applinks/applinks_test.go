@@ -0,0 +1,103 @@
+package applinks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeOrigin(t *testing.T) {
+	for raw, want := range map[string]string{
+		"https://App.TrakRF.id":  "https://app.trakrf.id",
+		"https://app.trakrf.id/": "https://app.trakrf.id",
+		"http://localhost:5173":  "http://localhost:5173",
+		"http://127.0.0.1:8080":  "http://127.0.0.1:8080",
+		"https://rfid.acme.com":  "https://rfid.acme.com",
+	} {
+		got, err := NormalizeOrigin(raw)
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, got, raw)
+	}
+	for _, raw := range []string{
+		"app.trakrf.id",
+		"http://app.trakrf.id",
+		"https://app.trakrf.id/reset",
+		"https://app.trakrf.id?x=1",
+		"https://user:pw@app.trakrf.id",
+		"javascript:alert(1)",
+		"",
+	} {
+		_, err := NormalizeOrigin(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("APP_BASE_URL", "")
+	t.Setenv("APP_LINK_ALLOWED_ORIGINS", "")
+	t.Setenv("APP_TENANT_DOMAIN", "")
+	c, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Config{BaseURL: DefaultBaseURL}, c)
+
+	t.Setenv("APP_BASE_URL", "https://preview.trakrf.id")
+	t.Setenv("APP_LINK_ALLOWED_ORIGINS", "http://localhost:5173, https://pr-12.trakrf.id")
+	c, err = ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "https://preview.trakrf.id", c.BaseURL)
+	assert.Equal(t, []string{"http://localhost:5173", "https://pr-12.trakrf.id"}, c.AllowedOrigins)
+
+	t.Setenv("APP_LINK_ALLOWED_ORIGINS", "https://ok.trakrf.id,evil.example")
+	_, err = ConfigFromEnv()
+	assert.ErrorContains(t, err, "APP_LINK_ALLOWED_ORIGINS")
+}
+
+func TestConfigFromEnv_TenantDomain(t *testing.T) {
+	t.Setenv("APP_BASE_URL", "https://app.trakrf.id")
+	t.Setenv("APP_LINK_ALLOWED_ORIGINS", "")
+
+	t.Setenv("APP_TENANT_DOMAIN", "")
+	c, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "app.trakrf.id", c.TenantHost(), "defaults to the base URL host")
+
+	t.Setenv("APP_TENANT_DOMAIN", " TrakRF.app ")
+	c, err = ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "trakrf.app", c.TenantHost())
+
+	for _, raw := range []string{"https://trakrf.app", "trakrf.app:443", "trakrf.app/x", "localhost"} {
+		t.Setenv("APP_TENANT_DOMAIN", raw)
+		_, err = ConfigFromEnv()
+		assert.ErrorContains(t, err, "APP_TENANT_DOMAIN", raw)
+	}
+}
+
+func TestConfig_Origin(t *testing.T) {
+	c := Config{BaseURL: "https://app.trakrf.id", AllowedOrigins: []string{"http://localhost:5173"}}
+	org := []string{"https://rfid.acme.com"}
+
+	cases := map[string]string{
+		"":                                      "https://app.trakrf.id",
+		"https://app.trakrf.id":                 "https://app.trakrf.id",
+		"http://localhost:5173":                 "http://localhost:5173",
+		"https://rfid.acme.com":                 "https://rfid.acme.com",
+		"https://evil.example":                  "https://app.trakrf.id",
+		"https://evil.example/#reset-password":  "https://app.trakrf.id",
+		"https://app.trakrf.id.evil.example":    "https://app.trakrf.id",
+		"https://rfid.acme.com/any/path?x=1":    "https://rfid.acme.com",
+		"http://app.trakrf.id/#reset-password":  "https://app.trakrf.id",
+		"not a url at all":                      "https://app.trakrf.id",
+		"https://user@rfid.acme.com/#reset-pwd": "https://rfid.acme.com",
+	}
+	for requested, want := range cases {
+		assert.Equal(t, want, c.Origin(requested, org), requested)
+	}
+	assert.Equal(t, "https://app.trakrf.id", c.Origin("https://rfid.acme.com", nil),
+		"an org's domain is only honored for that org")
+}
+
+func TestLocationURL(t *testing.T) {
+	assert.Equal(t, "https://rfid.acme.com/locations/482913", LocationURL("https://rfid.acme.com", 482913))
+}
@@ -0,0 +1,62 @@
+// Package carriers enriches transfer orders with third-party carrier
+// tracking. Each carrier the server can poll is a Tracker registered under
+// its carrier code; the Poller job claims orders whose tracking is due,
+// asks their carrier for the shipment's status and ETA, and records the
+// answer on the order. Orders naming a carrier without a tracker keep their
+// tracking number for reference and are never polled.
+package carriers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+)
+
+// ErrNotFound is returned by a Tracker when the carrier does not know the
+// tracking number (yet: labels are often created before the first scan).
+var ErrNotFound = errors.New("tracking number not found by the carrier")
+
+// Tracker is one carrier's tracking API.
+type Tracker interface {
+	// Track returns the shipment's current status, normalized to the
+	// transferorder.Tracking* statuses.
+	Track(ctx context.Context, trackingNumber string) (transferorder.TrackingUpdate, error)
+}
+
+// Registry maps carrier codes (lowercase) to their trackers.
+type Registry map[string]Tracker
+
+// Carriers returns the registered carrier codes, sorted.
+func (r Registry) Carriers() []string {
+	out := make([]string, 0, len(r))
+	for code := range r {
+		out = append(out, code)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// FromEnv builds the registry from:
+//
+//	CARRIER_DHL_API_KEY   DHL Shipment Tracking - Unified API key
+//	CARRIER_DHL_BASE_URL  overrides https://api-eu.dhl.com
+//
+// It returns an empty registry when no carrier is configured, which
+// disables the poller. A carrier that is configured but malformed is an
+// error, so the server refuses to boot instead of silently not tracking.
+func FromEnv() (Registry, error) {
+	r := Registry{}
+	if key := strings.TrimSpace(os.Getenv("CARRIER_DHL_API_KEY")); key != "" {
+		dhl, err := NewDHL(key, strings.TrimSpace(os.Getenv("CARRIER_DHL_BASE_URL")))
+		if err != nil {
+			return nil, fmt.Errorf("CARRIER_DHL_BASE_URL: %w", err)
+		}
+		r[CarrierDHL] = dhl
+	}
+	return r, nil
+}
@@ -0,0 +1,143 @@
+package carriers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+)
+
+// CarrierDHL is DHL's carrier code.
+const CarrierDHL = "dhl"
+
+const (
+	dhlDefaultBaseURL = "https://api-eu.dhl.com"
+	dhlTimeout        = 15 * time.Second
+	// dhlLocalLayout is how DHL renders timestamps it has no zone for.
+	dhlLocalLayout = "2006-01-02T15:04:05"
+)
+
+// DHL polls the DHL Shipment Tracking - Unified API, which covers Express,
+// Parcel and Freight shipments under one endpoint.
+type DHL struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewDHL builds the DHL tracker. An empty baseURL uses DHL's production
+// host.
+func NewDHL(apiKey, baseURL string) (*DHL, error) {
+	if baseURL == "" {
+		baseURL = dhlDefaultBaseURL
+	} else if u, err := url.Parse(baseURL); err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+		return nil, fmt.Errorf("dhl base url must be an absolute http(s) URL")
+	}
+	return &DHL{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: dhlTimeout},
+	}, nil
+}
+
+type dhlResponse struct {
+	Shipments []struct {
+		Status struct {
+			Timestamp   string `json:"timestamp"`
+			StatusCode  string `json:"statusCode"`
+			Status      string `json:"status"`
+			Description string `json:"description"`
+		} `json:"status"`
+		EstimatedTimeOfDelivery string `json:"estimatedTimeOfDelivery"`
+	} `json:"shipments"`
+}
+
+// Track looks trackingNumber up and maps DHL's status codes (pre-transit,
+// transit, delivered, failure, unknown) onto the normalized statuses.
+func (d *DHL) Track(ctx context.Context, trackingNumber string) (transferorder.TrackingUpdate, error) {
+	var u transferorder.TrackingUpdate
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		d.baseURL+"/track/shipments?trackingNumber="+url.QueryEscape(trackingNumber), nil)
+	if err != nil {
+		return u, err
+	}
+	req.Header.Set("DHL-API-Key", d.apiKey)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return u, fmt.Errorf("dhl: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return u, fmt.Errorf("dhl: read response: %w", err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return u, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return u, fmt.Errorf("dhl: %s: %s", resp.Status, truncate(string(body), 200))
+	}
+
+	var parsed dhlResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return u, fmt.Errorf("dhl: decode response: %w", err)
+	}
+	if len(parsed.Shipments) == 0 {
+		return u, ErrNotFound
+	}
+	sh := parsed.Shipments[0]
+	u.Status = dhlStatus(sh.Status.StatusCode)
+	u.Detail = sh.Status.Description
+	if u.Detail == "" {
+		u.Detail = sh.Status.Status
+	}
+	u.EventAt = dhlTime(sh.Status.Timestamp)
+	u.ETA = dhlTime(sh.EstimatedTimeOfDelivery)
+	return u, nil
+}
+
+func dhlStatus(code string) string {
+	switch code {
+	case "pre-transit":
+		return transferorder.TrackingPreTransit
+	case "transit":
+		return transferorder.TrackingInTransit
+	case "delivered":
+		return transferorder.TrackingDelivered
+	case "failure":
+		return transferorder.TrackingException
+	default:
+		return transferorder.TrackingUnknown
+	}
+}
+
+// dhlTime parses a DHL timestamp, which carries a zone offset for most
+// services and none for some; zoneless ones are taken as UTC. Unparseable
+// or empty values are nil.
+func dhlTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		if t, err = time.Parse(dhlLocalLayout, s); err != nil {
+			return nil
+		}
+	}
+	return &t
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
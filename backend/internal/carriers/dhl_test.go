@@ -0,0 +1,80 @@
+package carriers
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+)
+
+func TestNewDHL_Validates(t *testing.T) {
+	_, err := NewDHL("key", "/track")
+	assert.Error(t, err)
+
+	d, err := NewDHL("key", "")
+	require.NoError(t, err)
+	assert.Equal(t, dhlDefaultBaseURL, d.baseURL)
+}
+
+func TestDHL_Track(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.Header.Get("DHL-API-Key"))
+		assert.Equal(t, "/track/shipments", r.URL.Path)
+		switch r.URL.Query().Get("trackingNumber") {
+		case "00340434292135100186":
+			io.WriteString(w, `{"shipments":[{"id":"00340434292135100186","status":{
+				"timestamp":"2026-05-02T09:15:00+02:00","statusCode":"transit","status":"transit",
+				"description":"The shipment has been processed in the destination parcel center"},
+				"estimatedTimeOfDelivery":"2026-05-03T18:00:00"}]}`)
+		case "1234567890":
+			io.WriteString(w, `{"shipments":[{"status":{"statusCode":"delivered","status":"DELIVERED"}}]}`)
+		case "quota":
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"title":"Too many requests"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	d, err := NewDHL("key", srv.URL)
+	require.NoError(t, err)
+
+	u, err := d.Track(t.Context(), "00340434292135100186")
+	require.NoError(t, err)
+	assert.Equal(t, transferorder.TrackingInTransit, u.Status)
+	assert.Equal(t, "The shipment has been processed in the destination parcel center", u.Detail)
+	require.NotNil(t, u.EventAt)
+	assert.Equal(t, time.Date(2026, 5, 2, 7, 15, 0, 0, time.UTC), u.EventAt.UTC())
+	require.NotNil(t, u.ETA)
+	assert.Equal(t, time.Date(2026, 5, 3, 18, 0, 0, 0, time.UTC), *u.ETA, "zoneless times are UTC")
+
+	u, err = d.Track(t.Context(), "1234567890")
+	require.NoError(t, err)
+	assert.Equal(t, transferorder.TrackingDelivered, u.Status)
+	assert.Equal(t, "DELIVERED", u.Detail, "status stands in for a missing description")
+	assert.Nil(t, u.ETA)
+
+	_, err = d.Track(t.Context(), "nope")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = d.Track(t.Context(), "quota")
+	assert.ErrorContains(t, err, "429")
+}
+
+func TestDHLStatus(t *testing.T) {
+	for code, want := range map[string]string{
+		"pre-transit": transferorder.TrackingPreTransit,
+		"transit":     transferorder.TrackingInTransit,
+		"delivered":   transferorder.TrackingDelivered,
+		"failure":     transferorder.TrackingException,
+		"unknown":     transferorder.TrackingUnknown,
+		"":            transferorder.TrackingUnknown,
+	} {
+		assert.Equal(t, want, dhlStatus(code), code)
+	}
+}
@@ -0,0 +1,11 @@
+package carriers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricPolls = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "carrier_tracking_polls_total",
+	Help: "Carrier tracking polls of transfer orders, by carrier and result.",
+}, []string{"carrier", "result"}) // ok, not_found, failed
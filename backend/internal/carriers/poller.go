@@ -0,0 +1,91 @@
+package carriers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+)
+
+const (
+	claimBatch = 20
+	// claimLease covers one batch of carrier calls; an order whose poll dies
+	// mid-run becomes due again when it runs out.
+	claimLease = 5 * time.Minute
+	// pollEvery is how long an order waits between polls. Carriers update a
+	// shipment a few times a day, and their APIs are rate limited.
+	pollEvery = 30 * time.Minute
+	// maxErrorLen bounds the error text kept on an order.
+	maxErrorLen = 1024
+)
+
+// pollStore is the storage surface the poller needs; *storage.Storage
+// satisfies it.
+type pollStore interface {
+	ClaimDueTransferOrderTracking(ctx context.Context, carriers []string, limit int, lease time.Duration) ([]transferorder.TrackedOrder, error)
+	RecordTransferOrderTracking(ctx context.Context, o transferorder.TrackedOrder, u transferorder.TrackingUpdate, next time.Duration) error
+	RecordTransferOrderTrackingError(ctx context.Context, o transferorder.TrackedOrder, msg string, next time.Duration) error
+}
+
+// Poller is the carrier tracking job.
+type Poller struct {
+	store    pollStore
+	registry Registry
+	log      zerolog.Logger
+}
+
+// NewPoller builds the tracking job over the registry's carriers.
+func NewPoller(store pollStore, registry Registry, log *zerolog.Logger) *Poller {
+	return &Poller{
+		store:    store,
+		registry: registry,
+		log:      log.With().Str("component", "carriers").Logger(),
+	}
+}
+
+// Run is the job run: claim due orders and poll each until none are due. A
+// carrier's failures are recorded on the order, not returned; only storage
+// errors fail the job.
+func (p *Poller) Run(ctx context.Context) error {
+	carriers := p.registry.Carriers()
+	if len(carriers) == 0 {
+		return nil
+	}
+	for {
+		batch, err := p.store.ClaimDueTransferOrderTracking(ctx, carriers, claimBatch, claimLease)
+		if err != nil {
+			return err
+		}
+		for _, o := range batch {
+			if err := p.poll(ctx, o); err != nil {
+				return err
+			}
+		}
+		if len(batch) < claimBatch {
+			return nil
+		}
+	}
+}
+
+func (p *Poller) poll(ctx context.Context, o transferorder.TrackedOrder) error {
+	u, err := p.registry[o.Carrier].Track(ctx, o.TrackingNumber)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		result := "failed"
+		if errors.Is(err, ErrNotFound) {
+			result = "not_found"
+		} else {
+			p.log.Warn().Err(err).Int("transfer_order_id", o.ID).Int("org_id", o.OrgID).
+				Str("carrier", o.Carrier).Msg("carrier tracking poll failed")
+		}
+		metricPolls.WithLabelValues(o.Carrier, result).Inc()
+		return p.store.RecordTransferOrderTrackingError(ctx, o, truncate(err.Error(), maxErrorLen), pollEvery)
+	}
+	metricPolls.WithLabelValues(o.Carrier, "ok").Inc()
+	return p.store.RecordTransferOrderTracking(ctx, o, u, pollEvery)
+}
@@ -0,0 +1,97 @@
+package carriers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+)
+
+type fakePollStore struct {
+	due      []transferorder.TrackedOrder
+	carriers []string
+	updates  map[int]transferorder.TrackingUpdate
+	errors   map[int]string
+	claimErr error
+}
+
+func (f *fakePollStore) ClaimDueTransferOrderTracking(_ context.Context, carriers []string, limit int, _ time.Duration) ([]transferorder.TrackedOrder, error) {
+	if f.claimErr != nil {
+		return nil, f.claimErr
+	}
+	f.carriers = carriers
+	n := min(limit, len(f.due))
+	batch := f.due[:n]
+	f.due = f.due[n:]
+	return batch, nil
+}
+
+func (f *fakePollStore) RecordTransferOrderTracking(_ context.Context, o transferorder.TrackedOrder, u transferorder.TrackingUpdate, next time.Duration) error {
+	f.updates[o.ID] = u
+	return nil
+}
+
+func (f *fakePollStore) RecordTransferOrderTrackingError(_ context.Context, o transferorder.TrackedOrder, msg string, next time.Duration) error {
+	f.errors[o.ID] = msg
+	return nil
+}
+
+type fakeTracker map[string]error
+
+func (f fakeTracker) Track(_ context.Context, number string) (transferorder.TrackingUpdate, error) {
+	if err := f[number]; err != nil {
+		return transferorder.TrackingUpdate{}, err
+	}
+	return transferorder.TrackingUpdate{Status: transferorder.TrackingInTransit, Detail: number}, nil
+}
+
+func newTestPoller(store *fakePollStore) *Poller {
+	log := zerolog.Nop()
+	return NewPoller(store, Registry{"dhl": fakeTracker{
+		"lost":  ErrNotFound,
+		"error": errors.New("dhl: 503 Service Unavailable"),
+	}}, &log)
+}
+
+func TestPoller_RecordsUpdatesAndErrors(t *testing.T) {
+	store := &fakePollStore{
+		updates: map[int]transferorder.TrackingUpdate{}, errors: map[int]string{},
+		due: []transferorder.TrackedOrder{
+			{ID: 1, OrgID: 5, Carrier: "dhl", TrackingNumber: "ok"},
+			{ID: 2, OrgID: 5, Carrier: "dhl", TrackingNumber: "lost"},
+			{ID: 3, OrgID: 6, Carrier: "dhl", TrackingNumber: "error"},
+		},
+	}
+	require.NoError(t, newTestPoller(store).Run(t.Context()))
+
+	assert.Equal(t, []string{"dhl"}, store.carriers)
+	assert.Equal(t, transferorder.TrackingInTransit, store.updates[1].Status)
+	assert.Equal(t, ErrNotFound.Error(), store.errors[2])
+	assert.Equal(t, "dhl: 503 Service Unavailable", store.errors[3])
+}
+
+func TestPoller_DrainsEveryBatch(t *testing.T) {
+	store := &fakePollStore{updates: map[int]transferorder.TrackingUpdate{}, errors: map[int]string{}}
+	for i := range claimBatch + 3 {
+		store.due = append(store.due, transferorder.TrackedOrder{ID: i + 1, Carrier: "dhl", TrackingNumber: "ok"})
+	}
+	require.NoError(t, newTestPoller(store).Run(t.Context()))
+	assert.Len(t, store.updates, claimBatch+3)
+}
+
+func TestPoller_ClaimErrorFailsTheRun(t *testing.T) {
+	store := &fakePollStore{claimErr: errors.New("db down")}
+	assert.Error(t, newTestPoller(store).Run(t.Context()))
+}
+
+func TestPoller_NoCarriersIsANoop(t *testing.T) {
+	store := &fakePollStore{claimErr: errors.New("should not be called")}
+	log := zerolog.Nop()
+	assert.NoError(t, NewPoller(store, Registry{}, &log).Run(t.Context()))
+}
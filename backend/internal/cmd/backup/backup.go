@@ -0,0 +1,97 @@
+// Package backup takes a logical backup of the trakrf schema as a one-shot
+// command, meant for a daily CronJob: it dumps every table, uploads the dump
+// to BACKUP_BUCKET, restores it into a scratch schema to prove it loads, and
+// records the run for /readyz (see dbbackup). A failed run exits non-zero
+// and is reported to Sentry when SENTRY_DSN is set.
+//
+// Like migrate, it connects with PG_URL and must run as the migrate role:
+// the dump reads every org's rows and the restore creates a schema.
+package backup
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/internal/dbbackup"
+	"github.com/trakrf/platform/backend/internal/errorreport"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/objectstore"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+// options is the parsed backup invocation.
+type options struct {
+	// noUpload dumps and verifies without uploading, for local runs.
+	noUpload bool
+}
+
+func parseArgs(args []string) (options, error) {
+	var o options
+	fs := flag.NewFlagSet("backup", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.BoolVar(&o.noUpload, "no-upload", false, "dump and verify without uploading")
+	if err := fs.Parse(args); err != nil {
+		return options{}, fmt.Errorf("backup: %w", err)
+	}
+	if fs.NArg() != 0 {
+		return options{}, fmt.Errorf("backup: unexpected arguments: %v", fs.Args())
+	}
+	return o, nil
+}
+
+// Run takes one backup (see the package doc).
+func Run(ctx context.Context, info buildinfo.Info, args []string) error {
+	log := logger.Get()
+
+	opts, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	var objects objectstore.Store
+	prefix := ""
+	if !opts.noUpload {
+		cfg, err := dbbackup.ConfigFromEnv()
+		if err != nil {
+			return err
+		}
+		objects, prefix = objectstore.NewS3(cfg.Store), cfg.Prefix
+	}
+
+	if dsn := os.Getenv("SENTRY_DSN"); dsn != "" {
+		err := sentry.Init(sentry.ClientOptions{
+			Dsn:         dsn,
+			Environment: os.Getenv("APP_ENV"),
+			Release:     info.Version,
+			BeforeSend:  errorreport.BeforeSend,
+		})
+		if err != nil {
+			log.Warn().Err(err).Msg("Sentry initialization failed")
+		} else {
+			errorreport.SetSink(errorreport.SentrySink{})
+			defer sentry.Flush(2 * time.Second)
+		}
+	}
+
+	store, err := storage.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	pool, ok := store.Pool().(*pgxpool.Pool)
+	if !ok {
+		return fmt.Errorf("backup needs a pgxpool connection")
+	}
+
+	log.Info().Str("version", info.Version).Bool("upload", objects != nil).Msg("Starting backup")
+	_, err = dbbackup.NewJob(store, pool, objects, prefix, log).Run(ctx)
+	return err
+}
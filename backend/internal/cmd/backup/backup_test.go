@@ -0,0 +1,19 @@
+package backup
+
+import "testing"
+
+func TestParseArgs(t *testing.T) {
+	o, err := parseArgs(nil)
+	if err != nil || o.noUpload {
+		t.Fatalf("defaults = %+v, %v", o, err)
+	}
+	o, err = parseArgs([]string{"--no-upload"})
+	if err != nil || !o.noUpload {
+		t.Fatalf("--no-upload = %+v, %v", o, err)
+	}
+	for _, args := range [][]string{{"--bogus"}, {"extra"}} {
+		if _, err := parseArgs(args); err == nil {
+			t.Errorf("parseArgs(%v) err = nil, want error", args)
+		}
+	}
+}
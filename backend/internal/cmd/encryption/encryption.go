@@ -0,0 +1,113 @@
+// Package encryption is the `server encryption` command, the operator side
+// of field encryption (see fieldcrypt): status reports which key each
+// encrypted value is under, and rotate re-encrypts every value under the
+// primary key of FIELD_ENCRYPTION_KEYS, so an old key can be dropped.
+//
+// It connects with PG_URL. api_keys and connectors have no row security,
+// so any role that can write them will do.
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/internal/connectors"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/fieldcrypt"
+)
+
+// Usage documents the encryption subcommands.
+const Usage = `usage: server encryption [status | rotate]
+  status  count encrypted values by key id (default); "" is plaintext, or
+          connector credentials still under CONNECTOR_VAULT_KEY
+  rotate  re-encrypt every value not under the primary key of
+          FIELD_ENCRYPTION_KEYS; safe to re-run
+
+Password reset tokens are keyed digests and cannot be re-encrypted; keep an
+old key in FIELD_ENCRYPTION_KEYS for an hour after rotating, until every
+token issued under it has expired.`
+
+type action int
+
+const (
+	actionStatus action = iota
+	actionRotate
+)
+
+func parseArgs(args []string) (action, error) {
+	if len(args) == 0 {
+		return actionStatus, nil
+	}
+	if len(args) > 1 {
+		return 0, fmt.Errorf("encryption %s takes no arguments", args[0])
+	}
+	switch args[0] {
+	case "status":
+		return actionStatus, nil
+	case "rotate":
+		return actionRotate, nil
+	default:
+		return 0, fmt.Errorf("unknown encryption subcommand: %q", args[0])
+	}
+}
+
+// Run executes the encryption subcommand in args (see Usage).
+func Run(ctx context.Context, info buildinfo.Info, args []string) error {
+	log := logger.Get()
+
+	act, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+	keys, err := fieldcrypt.FromEnv(ctx)
+	if err != nil {
+		return err
+	}
+	if act == actionRotate && keys == nil {
+		return fmt.Errorf("encryption rotate: FIELD_ENCRYPTION_KEYS is unset")
+	}
+
+	store, err := storage.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	if act == actionRotate {
+		store.EnableFieldEncryption(keys)
+		vault, err := connectors.VaultFromEnv(keys)
+		if err != nil {
+			return err
+		}
+		log.Info().Str("version", info.Version).Str("primary_key", keys.PrimaryID()).Msg("Rotating encrypted columns")
+		n, err := store.RotateAPIKeySecretHashes(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info().Int64("rewritten", n).Msg("api_keys.secret_hash rotated")
+		n, err = store.RotateConnectorCredentials(ctx, vault.Reseal)
+		if err != nil {
+			return err
+		}
+		log.Info().Int64("rewritten", n).Msg("connectors.credentials rotated")
+	}
+
+	columns, err := store.FieldEncryptionStatus(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range columns {
+		ids := make([]string, 0, len(c.ByKey))
+		for id := range c.ByKey {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			log.Info().Str("column", c.Column).Str("key", id).Int64("values", c.ByKey[id]).Msg("Encryption status")
+		}
+	}
+	return nil
+}
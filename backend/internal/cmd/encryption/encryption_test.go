@@ -0,0 +1,26 @@
+package encryption
+
+import "testing"
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    action
+		wantErr bool
+	}{
+		{nil, actionStatus, false},
+		{[]string{"status"}, actionStatus, false},
+		{[]string{"rotate"}, actionRotate, false},
+		{[]string{"rotate", "now"}, 0, true},
+		{[]string{"bogus"}, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseArgs(tt.args)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("parseArgs(%v) err = %v, wantErr = %v", tt.args, err, tt.wantErr)
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseArgs(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package loadtest is the load-test command: it runs the loadtest
+// scenarios against a running server, prints their latency percentiles,
+// and fails when one is over its p95 budget or error rate. It is one-shot
+// like seed, and needs no database, only the API.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/internal/loadtest"
+	"github.com/trakrf/platform/backend/internal/logger"
+)
+
+// Config is the parsed load-test invocation.
+type Config struct {
+	BaseURL   string
+	Token     string
+	ReaderKey string
+	Scenarios []string
+	Run       loadtest.Config
+	Timeout   time.Duration
+	JSONPath  string
+}
+
+// scenarioNames are the scenarios --scenarios accepts, in run order.
+var scenarioNames = []string{"scan_ingest", "asset_list", "bulk_import"}
+
+func parseArgs(args []string) (Config, error) {
+	c := Config{Run: loadtest.DefaultConfig()}
+	var scenarios string
+	fs := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&c.BaseURL, "base-url", "http://localhost:8080", "server to load")
+	fs.StringVar(&c.Token, "token", os.Getenv("LOADTEST_TOKEN"), "session bearer token (default $LOADTEST_TOKEN)")
+	fs.StringVar(&c.ReaderKey, "reader-key", os.Getenv("LOADTEST_READER_KEY"), "reader credential for scan_ingest (default $LOADTEST_READER_KEY)")
+	fs.StringVar(&scenarios, "scenarios", strings.Join(scenarioNames, ","), "comma-separated scenarios to run")
+	fs.IntVar(&c.Run.Rate, "rate", c.Run.Rate, "requests started per second, per scenario")
+	fs.DurationVar(&c.Run.Duration, "duration", c.Run.Duration, "how long each scenario runs")
+	fs.IntVar(&c.Run.Workers, "workers", c.Run.Workers, "requests in flight at most")
+	fs.DurationVar(&c.Timeout, "timeout", 10*time.Second, "per-request timeout")
+	fs.StringVar(&c.JSONPath, "json", "", "also write the report as JSON to this file")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, fmt.Errorf("loadtest: %w", err)
+	}
+	if fs.NArg() != 0 {
+		return Config{}, fmt.Errorf("loadtest: unexpected arguments: %v", fs.Args())
+	}
+	if c.Token == "" {
+		return Config{}, fmt.Errorf("loadtest: --token or LOADTEST_TOKEN is required")
+	}
+	for _, name := range strings.Split(scenarios, ",") {
+		name = strings.TrimSpace(name)
+		if !slices.Contains(scenarioNames, name) {
+			return Config{}, fmt.Errorf("loadtest: unknown scenario %q; known: %s", name, strings.Join(scenarioNames, ", "))
+		}
+		c.Scenarios = append(c.Scenarios, name)
+	}
+	if err := c.Run.Check(); err != nil {
+		return Config{}, fmt.Errorf("loadtest: %w", err)
+	}
+	if c.Timeout <= 0 {
+		return Config{}, fmt.Errorf("loadtest: --timeout must be positive, got %s", c.Timeout)
+	}
+	return c, nil
+}
+
+// Run loads the server described by args (see parseArgs), one scenario
+// after another, and returns an error naming every scenario that failed its
+// budget, so the command exits non-zero for a CI gate.
+func Run(ctx context.Context, info buildinfo.Info, args []string) error {
+	log := logger.Get()
+
+	cfg, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	client := loadtest.NewClient(cfg.BaseURL, cfg.Token, cfg.ReaderKey, cfg.Timeout, cfg.Run.Workers)
+	// The run id tags this run's fixtures and imports.
+	run := strconv.FormatInt(time.Now().Unix(), 36)
+
+	var results []loadtest.Result
+	for _, sc := range loadtest.Scenarios(run) {
+		if !slices.Contains(cfg.Scenarios, sc.Name) {
+			continue
+		}
+		log.Info().Str("version", info.Version).Str("scenario", sc.Name).Str("base_url", cfg.BaseURL).
+			Int("rate", cfg.Run.Rate).Dur("duration", cfg.Run.Duration).Msg("Running load-test scenario")
+		r, err := loadtest.Run(ctx, client, sc, cfg.Run)
+		if err != nil {
+			return err
+		}
+		results = append(results, r)
+	}
+
+	if err := loadtest.WriteTable(os.Stdout, results); err != nil {
+		return err
+	}
+	if cfg.JSONPath != "" {
+		f, err := os.Create(cfg.JSONPath)
+		if err != nil {
+			return err
+		}
+		if err := loadtest.WriteJSON(f, results); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, r := range results {
+		if err := r.Check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
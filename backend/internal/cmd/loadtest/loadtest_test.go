@@ -0,0 +1,49 @@
+package loadtest
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseArgs(t *testing.T) {
+	t.Setenv("LOADTEST_TOKEN", "")
+	t.Setenv("LOADTEST_READER_KEY", "")
+
+	if _, err := parseArgs(nil); err == nil {
+		t.Error("parseArgs without a token: err = nil, want error")
+	}
+
+	t.Setenv("LOADTEST_TOKEN", "tok")
+	c, err := parseArgs(nil)
+	if err != nil {
+		t.Fatalf("defaults: %v", err)
+	}
+	if c.Token != "tok" || c.BaseURL != "http://localhost:8080" || !slices.Equal(c.Scenarios, scenarioNames) {
+		t.Errorf("defaults = %+v", c)
+	}
+
+	c, err = parseArgs([]string{"--base-url", "https://staging.example", "--scenarios", "asset_list, scan_ingest",
+		"--rate", "50", "--duration", "1m", "--workers", "8", "--reader-key", "rk", "--json", "out.json"})
+	if err != nil {
+		t.Fatalf("flags: %v", err)
+	}
+	if c.BaseURL != "https://staging.example" || !slices.Equal(c.Scenarios, []string{"asset_list", "scan_ingest"}) ||
+		c.Run.Rate != 50 || c.Run.Duration != time.Minute || c.Run.Workers != 8 || c.ReaderKey != "rk" || c.JSONPath != "out.json" {
+		t.Errorf("flags = %+v", c)
+	}
+
+	for _, args := range [][]string{
+		{"--scenarios", "checkout"},
+		{"--rate", "0"},
+		{"--duration", "0s"},
+		{"--workers", "0"},
+		{"--timeout", "0s"},
+		{"--bogus"},
+		{"extra"},
+	} {
+		if _, err := parseArgs(args); err == nil {
+			t.Errorf("parseArgs(%v) err = nil, want error", args)
+		}
+	}
+}
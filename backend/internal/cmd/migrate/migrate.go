@@ -1,13 +1,24 @@
 // Package migrate runs embedded database migrations as a one-shot command.
-// It opens its own pgxpool using PG_URL, applies pending migrations via
+// It opens its own pgxpool using PG_URL, applies or inspects migrations via
 // golang-migrate, logs the result, and returns. It does not start an HTTP
 // server or any long-running goroutines.
+//
+// The server never migrates on its own (see TRA-85 in migrations/README.md),
+// so this command is the only writer of the schema. golang-migrate holds a
+// Postgres advisory lock for the duration of up/down/force, so concurrent
+// invocations — several replicas' init containers, or an operator racing a
+// deploy job — serialize instead of interleaving DDL.
 package migrate
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -20,12 +31,129 @@ import (
 	"github.com/trakrf/platform/backend/migrations"
 )
 
-// Run applies all pending embedded migrations to the database identified
-// by the PG_URL environment variable, then returns. A nil return means
-// success (including the "no pending migrations" case).
-func Run(ctx context.Context, info buildinfo.Info) error {
+// Usage documents the migrate subcommands.
+const Usage = `usage: server migrate [up | expand | contract | down [N] | status | force VERSION | create NAME | lint]
+  up             apply all pending migrations (default)
+  expand         apply pending migrations up to the first contract migration;
+                 run before rolling out a release
+  contract       apply all pending migrations, contract ones included; run
+                 once every replica runs the new release
+  down [N]       roll back the last N migrations (default 1)
+  status         report the applied version, dirty flag and pending count
+  force VERSION  record VERSION as applied and clear the dirty flag; runs no SQL
+  create NAME    write the next-numbered up/down pair into MIGRATIONS_DIR
+                 (default ./migrations); needs no database
+  lint           check the migrations in MIGRATIONS_DIR for destructive
+                 statements outside contract migrations; needs no database
+
+up, expand and contract refuse to run while the embedded migrations fail
+lint.`
+
+type action int
+
+const (
+	actionUp action = iota
+	actionDown
+	actionStatus
+	actionForce
+	actionCreate
+	actionExpand
+	actionContract
+	actionLint
+)
+
+// request is a parsed migrate invocation. n is the step count for down and
+// the version for force; name is the migration name for create.
+type request struct {
+	action action
+	n      int
+	name   string
+}
+
+var migrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+var argumentlessActions = map[string]action{
+	"up":       actionUp,
+	"expand":   actionExpand,
+	"contract": actionContract,
+	"status":   actionStatus,
+	"lint":     actionLint,
+}
+
+func parseArgs(args []string) (request, error) {
+	if len(args) == 0 {
+		return request{action: actionUp}, nil
+	}
+
+	sub, rest := args[0], args[1:]
+	switch sub {
+	case "up", "expand", "contract", "status", "lint":
+		if len(rest) != 0 {
+			return request{}, fmt.Errorf("migrate %s takes no arguments", sub)
+		}
+		return request{action: argumentlessActions[sub]}, nil
+	case "down":
+		if len(rest) > 1 {
+			return request{}, fmt.Errorf("migrate down takes at most one argument")
+		}
+		n := 1
+		if len(rest) == 1 {
+			v, err := strconv.Atoi(rest[0])
+			if err != nil || v < 1 {
+				return request{}, fmt.Errorf("migrate down: step count must be a positive integer, got %q", rest[0])
+			}
+			n = v
+		}
+		return request{action: actionDown, n: n}, nil
+	case "force":
+		if len(rest) != 1 {
+			return request{}, fmt.Errorf("migrate force requires a version")
+		}
+		v, err := strconv.Atoi(rest[0])
+		if err != nil || v < 0 {
+			return request{}, fmt.Errorf("migrate force: version must be a non-negative integer, got %q", rest[0])
+		}
+		return request{action: actionForce, n: v}, nil
+	case "create":
+		if len(rest) != 1 {
+			return request{}, fmt.Errorf("migrate create requires a name")
+		}
+		if !migrationName.MatchString(rest[0]) {
+			return request{}, fmt.Errorf("migrate create: name must be lower_snake_case, got %q", rest[0])
+		}
+		return request{action: actionCreate, name: rest[0]}, nil
+	default:
+		return request{}, fmt.Errorf("unknown migrate subcommand: %q", sub)
+	}
+}
+
+// Run executes the migrate subcommand in args (see Usage) against the
+// database identified by the PG_URL environment variable. A nil return means
+// success, including the "no pending migrations" case.
+func Run(ctx context.Context, info buildinfo.Info, args []string) error {
 	log := logger.Get()
 
+	req, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	dir := os.Getenv("MIGRATIONS_DIR")
+	if dir == "" {
+		dir = "migrations"
+	}
+	if req.action == actionLint {
+		return lint(os.DirFS(dir), dir)
+	}
+	if req.action == actionCreate {
+		up, down, err := create(dir, req.name)
+		if err != nil {
+			return err
+		}
+		log.Info().Str("up", up).Str("down", down).Msg("Created migration")
+		return nil
+	}
+
 	pgURL := os.Getenv("PG_URL")
 	if pgURL == "" {
 		return fmt.Errorf("PG_URL environment variable not set")
@@ -65,6 +193,34 @@ func Run(ctx context.Context, info buildinfo.Info) error {
 	}
 	defer m.Close()
 
+	switch req.action {
+	case actionStatus:
+		return status(m)
+	case actionForce:
+		log.Warn().Int("version", req.n).Msg("Forcing migration version")
+		if err := m.Force(req.n); err != nil {
+			return fmt.Errorf("force failed: %w", err)
+		}
+		log.Info().Int("version", req.n).Msg("Migration version forced")
+		return nil
+	case actionDown:
+		log.Info().Str("version", info.Version).Int("steps", req.n).Msg("Rolling back migrations")
+		if err := m.Steps(-req.n); err != nil {
+			return fmt.Errorf("rollback failed: %w", err)
+		}
+		migrationVersion, dirty, _ := m.Version()
+		log.Info().Uint("version", migrationVersion).Bool("dirty", dirty).Msg("Rollback complete")
+		return nil
+	}
+
+	if err := lint(migrations.FS, "embedded migrations"); err != nil {
+		return err
+	}
+
+	if req.action == actionExpand {
+		return expand(m, info)
+	}
+
 	log.Info().Str("version", info.Version).Str("commit", info.Commit).Msg("Starting migrations")
 
 	err = m.Up()
@@ -80,3 +236,143 @@ func Run(ctx context.Context, info buildinfo.Info) error {
 		return fmt.Errorf("migration failed: %w", err)
 	}
 }
+
+// status logs the applied version against the embedded set. A dirty schema
+// is reported as an error so scripts and CI can gate on the exit code.
+func status(m *migrate.Migrate) error {
+	log := logger.Get()
+
+	applied, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		applied, err = 0, nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+
+	versions, err := migrations.Versions()
+	if err != nil {
+		return fmt.Errorf("failed to list embedded migrations: %w", err)
+	}
+	var pending int
+	var latest uint
+	for _, v := range versions {
+		if v > applied {
+			pending++
+		}
+		latest = max(latest, v)
+	}
+
+	log.Info().
+		Uint("version", applied).
+		Uint("latest", latest).
+		Int("pending", pending).
+		Bool("dirty", dirty).
+		Msg("Migration status")
+
+	if dirty {
+		return fmt.Errorf("schema is dirty at version %d; fix it by hand, then run `server migrate force %d`", applied, applied)
+	}
+	return nil
+}
+
+// lint logs every violation of migrations.Lint in fsys, named where in
+// log lines, and fails when there are any.
+func lint(fsys fs.FS, where string) error {
+	log := logger.Get()
+
+	violations, err := migrations.Lint(fsys)
+	if err != nil {
+		return fmt.Errorf("failed to lint %s: %w", where, err)
+	}
+	for _, v := range violations {
+		log.Error().Str("file", v.File).Int("line", v.Line).Str("rule", v.Rule).Str("sql", v.SQL).
+			Msg("Destructive statement in an expand migration")
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%s: %d destructive statement(s) outside contract migrations; move them to a migration marked `-- migrate:phase contract`", where, len(violations))
+	}
+	log.Info().Str("migrations", where).Msg("Migration lint clean")
+	return nil
+}
+
+// expand applies the pending migrations that precede the first pending
+// contract migration, so the release still running keeps working.
+func expand(m *migrate.Migrate, info buildinfo.Info) error {
+	log := logger.Get()
+
+	applied, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		applied, err = 0, nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("schema is dirty at version %d; fix it by hand, then run `server migrate force %d`", applied, applied)
+	}
+
+	list, err := migrations.Migrations()
+	if err != nil {
+		return fmt.Errorf("failed to list embedded migrations: %w", err)
+	}
+	target, held := expandTarget(list, applied)
+	if held != nil {
+		log.Info().Uint("version", held.Version).Str("file", held.Name).
+			Msg("Holding back contract migration until `server migrate contract`")
+	}
+	if target <= applied {
+		log.Info().Uint("version", applied).Msg("No pending expand migrations")
+		return nil
+	}
+
+	log.Info().Str("version", info.Version).Uint("target", target).Msg("Starting expand migrations")
+	if err := m.Migrate(target); err != nil {
+		return fmt.Errorf("migration failed: %w", err)
+	}
+	log.Info().Uint("version", target).Msg("Expand migrations complete")
+	return nil
+}
+
+// expandTarget returns the version expand migrates to from applied, and the
+// first pending contract migration it stops before, if any.
+func expandTarget(list []migrations.Migration, applied uint) (uint, *migrations.Migration) {
+	target := applied
+	for i, mig := range list {
+		if mig.Version <= applied {
+			continue
+		}
+		if mig.Phase == migrations.PhaseContract {
+			return target, &list[i]
+		}
+		target = mig.Version
+	}
+	return target, nil
+}
+
+// create writes empty up/down files for the next version after the highest
+// one in dir and returns their paths.
+func create(dir, name string) (string, string, error) {
+	latest, err := migrations.LatestVersionIn(os.DirFS(dir))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	base := fmt.Sprintf("%06d_%s", latest+1, name)
+	up := filepath.Join(dir, base+".up.sql")
+	down := filepath.Join(dir, base+".down.sql")
+	for _, path := range []string{up, down} {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create migration: %w", err)
+		}
+		_, err = f.WriteString("SET search_path = trakrf, public;\n")
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return up, down, nil
+}
@@ -2,16 +2,19 @@ package migrate
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/migrations"
 )
 
 func TestRun_MissingPGURL(t *testing.T) {
 	t.Setenv("PG_URL", "")
 
-	err := Run(context.Background(), buildinfo.Info{Version: "test"})
+	err := Run(context.Background(), buildinfo.Info{Version: "test"}, nil)
 	if err == nil {
 		t.Fatal("expected error when PG_URL is empty, got nil")
 	}
@@ -19,3 +22,130 @@ func TestRun_MissingPGURL(t *testing.T) {
 		t.Errorf("expected error mentioning PG_URL, got: %v", err)
 	}
 }
+
+func TestParseArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    request
+		wantErr bool
+	}{
+		{"bare migrate -> up", nil, request{action: actionUp}, false},
+		{"up", []string{"up"}, request{action: actionUp}, false},
+		{"status", []string{"status"}, request{action: actionStatus}, false},
+		{"expand", []string{"expand"}, request{action: actionExpand}, false},
+		{"contract", []string{"contract"}, request{action: actionContract}, false},
+		{"lint", []string{"lint"}, request{action: actionLint}, false},
+		{"expand with extra arg", []string{"expand", "80"}, request{}, true},
+		{"down defaults to one step", []string{"down"}, request{action: actionDown, n: 1}, false},
+		{"down N", []string{"down", "3"}, request{action: actionDown, n: 3}, false},
+		{"force version", []string{"force", "33"}, request{action: actionForce, n: 33}, false},
+		{"create name", []string{"create", "add_widgets"}, request{action: actionCreate, name: "add_widgets"}, false},
+		{"up with extra arg", []string{"up", "1"}, request{}, true},
+		{"down zero", []string{"down", "0"}, request{}, true},
+		{"down non-numeric", []string{"down", "all"}, request{}, true},
+		{"force without version", []string{"force"}, request{}, true},
+		{"force negative", []string{"force", "-1"}, request{}, true},
+		{"create without name", []string{"create"}, request{}, true},
+		{"create bad name", []string{"create", "Add Widgets"}, request{}, true},
+		{"unknown", []string{"drop"}, request{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseArgs(%v) err = %v, wantErr = %v", tt.args, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseArgs(%v) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_CreateNeedsNoDatabase(t *testing.T) {
+	t.Setenv("PG_URL", "")
+	dir := t.TempDir()
+	t.Setenv("MIGRATIONS_DIR", dir)
+	for _, f := range []string{"000041_existing.up.sql", "000041_existing.down.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, f), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := Run(context.Background(), buildinfo.Info{}, []string{"create", "add_widgets"}); err != nil {
+		t.Fatalf("Run create: %v", err)
+	}
+	for _, f := range []string{"000042_add_widgets.up.sql", "000042_add_widgets.down.sql"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Errorf("expected %s: %v", f, err)
+		}
+	}
+
+	// Same name again gets the next version rather than clobbering.
+	if err := Run(context.Background(), buildinfo.Info{}, []string{"create", "add_widgets"}); err != nil {
+		t.Fatalf("second Run create: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "000043_add_widgets.up.sql")); err != nil {
+		t.Errorf("expected 000043: %v", err)
+	}
+}
+
+func TestRun_LintNeedsNoDatabase(t *testing.T) {
+	t.Setenv("PG_URL", "")
+	dir := t.TempDir()
+	t.Setenv("MIGRATIONS_DIR", dir)
+	write := func(name, sql string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(sql), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("000100_add_grade.up.sql", "ALTER TABLE assets ADD COLUMN grade INT;")
+	if err := Run(context.Background(), buildinfo.Info{}, []string{"lint"}); err != nil {
+		t.Fatalf("Run lint on a clean directory: %v", err)
+	}
+
+	write("000101_drop_legacy.up.sql", "ALTER TABLE assets DROP COLUMN legacy;")
+	if err := Run(context.Background(), buildinfo.Info{}, []string{"lint"}); err == nil {
+		t.Fatal("expected lint to fail on a DROP COLUMN in an expand migration")
+	}
+
+	write("000101_drop_legacy.up.sql", "-- migrate:phase contract\nALTER TABLE assets DROP COLUMN legacy;")
+	if err := Run(context.Background(), buildinfo.Info{}, []string{"lint"}); err != nil {
+		t.Fatalf("Run lint with the drop in a contract migration: %v", err)
+	}
+}
+
+func TestExpandTarget(t *testing.T) {
+	list := []migrations.Migration{
+		{Version: 80, Phase: migrations.PhaseExpand},
+		{Version: 81, Phase: migrations.PhaseExpand},
+		{Version: 82, Phase: migrations.PhaseContract},
+		{Version: 83, Phase: migrations.PhaseExpand},
+	}
+	tests := []struct {
+		applied  uint
+		target   uint
+		heldBack uint
+	}{
+		{applied: 79, target: 81, heldBack: 82},
+		{applied: 81, target: 81, heldBack: 82},
+		{applied: 82, target: 83},
+		{applied: 83, target: 83},
+	}
+	for _, tt := range tests {
+		target, held := expandTarget(list, tt.applied)
+		if target != tt.target {
+			t.Errorf("expandTarget(%d) target = %d, want %d", tt.applied, target, tt.target)
+		}
+		var heldBack uint
+		if held != nil {
+			heldBack = held.Version
+		}
+		if heldBack != tt.heldBack {
+			t.Errorf("expandTarget(%d) held back %d, want %d", tt.applied, heldBack, tt.heldBack)
+		}
+	}
+}
@@ -0,0 +1,369 @@
+// Package seed creates a demo organization populated with a realistic
+// location tree, a few thousand tagged assets, and synthetic scan history,
+// for sales demos and local frontend development. It is a one-shot command
+// like migrate: it opens storage from PG_URL, writes, logs a summary, and
+// returns.
+//
+// Generation is deterministic for a given --seed, so two runs against fresh
+// databases produce the same demo.
+package seed
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+// Config is the parsed seed invocation.
+type Config struct {
+	OrgIdentifier string
+	OrgName       string
+	Assets        int
+	Days          int
+	Seed          uint64
+	AdminEmail    string
+}
+
+func parseArgs(args []string) (Config, error) {
+	var c Config
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&c.OrgIdentifier, "org", "demo", "identifier of the demo org to create")
+	fs.StringVar(&c.OrgName, "name", "TrakRF Demo", "display name of the demo org")
+	fs.IntVar(&c.Assets, "assets", 2500, "number of assets to create")
+	fs.IntVar(&c.Days, "days", 30, "days of synthetic scan history")
+	fs.Uint64Var(&c.Seed, "seed", 1, "random seed; the same seed yields the same demo")
+	fs.StringVar(&c.AdminEmail, "admin-email", "", "existing user to add to the org as admin")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, fmt.Errorf("seed: %w", err)
+	}
+	if fs.NArg() != 0 {
+		return Config{}, fmt.Errorf("seed: unexpected arguments: %v", fs.Args())
+	}
+	if c.OrgIdentifier == "" {
+		return Config{}, fmt.Errorf("seed: --org must not be empty")
+	}
+	if c.Assets < 1 || c.Assets > 100000 {
+		return Config{}, fmt.Errorf("seed: --assets must be between 1 and 100000, got %d", c.Assets)
+	}
+	if c.Days < 0 || c.Days > 365 {
+		return Config{}, fmt.Errorf("seed: --days must be between 0 and 365, got %d", c.Days)
+	}
+	return c, nil
+}
+
+// Run creates the demo org described by args (see parseArgs). It refuses to
+// touch an org that already exists, so rerunning is safe.
+func Run(ctx context.Context, info buildinfo.Info, args []string) error {
+	log := logger.Get()
+
+	cfg, err := parseArgs(args)
+	if err != nil {
+		return err
+	}
+
+	store, err := storage.New(ctx)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	existing, err := store.GetOrganizationByIdentifier(ctx, cfg.OrgIdentifier)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("organization %q already exists; pass --org to seed a different one", cfg.OrgIdentifier)
+	}
+
+	log.Info().Str("version", info.Version).Str("org", cfg.OrgIdentifier).
+		Int("assets", cfg.Assets).Int("days", cfg.Days).Msg("Seeding demo org")
+
+	org, err := store.CreateOrganization(ctx, cfg.OrgName, cfg.OrgIdentifier)
+	if err != nil {
+		return err
+	}
+	// Demo orgs never expire.
+	if _, err := store.UpdateOrgEntitlement(ctx, org.ID, true, nil); err != nil {
+		return err
+	}
+
+	if cfg.AdminEmail != "" {
+		u, err := store.GetUserByEmail(ctx, cfg.AdminEmail)
+		if err != nil {
+			return err
+		}
+		if u == nil {
+			log.Warn().Str("email", cfg.AdminEmail).Msg("Admin user not found; sign up first, then add them to the org")
+		} else if err := store.AddUserToOrg(ctx, org.ID, u.ID, models.RoleAdmin); err != nil {
+			return err
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))
+	now := time.Now().UTC().Truncate(time.Minute)
+	start := now.AddDate(0, 0, -cfg.Days)
+
+	// Locations, parents first so each child can reference its parent's id.
+	locIDs := make(map[string]int)
+	sites := demoSites()
+	for _, loc := range flattenSites(sites) {
+		var parentID *int
+		if loc.parent != "" {
+			id := locIDs[loc.parent]
+			parentID = &id
+		}
+		created, err := store.CreateLocation(ctx, location.Location{
+			OrgID:       org.ID,
+			ExternalKey: loc.key,
+			Name:        loc.name,
+			ParentID:    parentID,
+			ValidFrom:   start,
+			IsActive:    true,
+		})
+		if err != nil {
+			return err
+		}
+		locIDs[loc.key] = created.ID
+	}
+
+	assets := generateAssets(rng, cfg.Assets, cfg.Seed, sites)
+	seedAssets := make([]storage.SeedAsset, len(assets))
+	for i, a := range assets {
+		seedAssets[i] = a.SeedAsset
+	}
+	assetIDs, err := store.SeedAssets(ctx, org.ID, start, seedAssets)
+	if err != nil {
+		return err
+	}
+
+	history := generateHistory(rng, assets, sites, start, now, cfg.Days)
+	scans := make([]storage.SeedScan, len(history))
+	for i, h := range history {
+		scans[i] = storage.SeedScan{
+			Timestamp:  h.at,
+			AssetID:    assetIDs[h.assetKey],
+			LocationID: locIDs[h.locationKey],
+		}
+	}
+	n, err := store.SeedAssetScans(ctx, org.ID, scans)
+	if err != nil {
+		return err
+	}
+
+	log.Info().
+		Int("org_id", org.ID).
+		Str("org", cfg.OrgIdentifier).
+		Int("locations", len(locIDs)).
+		Int("assets", len(assetIDs)).
+		Int64("scans", n).
+		Msg("Demo org seeded; asset locations appear once the asset_scan_latest refresh policy runs (~30s)")
+	return nil
+}
+
+// site is one facility in the demo tree. Its areas sit under it; scans land on
+// the leaves (areas without bays, and bays).
+type site struct {
+	key, name string
+	areas     []area
+}
+
+type area struct {
+	suffix, name string
+	bays         int
+}
+
+func demoSites() []site {
+	areas := []area{
+		{"RCV", "Receiving", 0},
+		{"WH", "Warehouse", 6},
+		{"SHP", "Shipping", 0},
+		{"MNT", "Maintenance Shop", 0},
+		{"OFC", "Office", 0},
+	}
+	return []site{
+		{"AUS", "Austin Distribution Center", areas},
+		{"RNO", "Reno Fulfillment Center", areas},
+		{"CHI", "Chicago Service Depot", areas[:4]},
+	}
+}
+
+type locationSpec struct {
+	key, name, parent string
+}
+
+// flattenSites returns every location in parent-before-child order.
+func flattenSites(sites []site) []locationSpec {
+	var out []locationSpec
+	for _, s := range sites {
+		out = append(out, locationSpec{key: s.key, name: s.name})
+		for _, a := range s.areas {
+			areaKey := s.key + "-" + a.suffix
+			out = append(out, locationSpec{key: areaKey, name: s.name + " " + a.name, parent: s.key})
+			for b := 1; b <= a.bays; b++ {
+				out = append(out, locationSpec{
+					key:    fmt.Sprintf("%s-%02d", areaKey, b),
+					name:   fmt.Sprintf("%s Bay %02d", a.name, b),
+					parent: areaKey,
+				})
+			}
+		}
+	}
+	return out
+}
+
+// leaves returns the scannable location keys of s.
+func (s site) leaves() []string {
+	var out []string
+	for _, a := range s.areas {
+		areaKey := s.key + "-" + a.suffix
+		if a.bays == 0 {
+			out = append(out, areaKey)
+			continue
+		}
+		for b := 1; b <= a.bays; b++ {
+			out = append(out, fmt.Sprintf("%s-%02d", areaKey, b))
+		}
+	}
+	return out
+}
+
+// category drives asset naming and how busy an asset's scan history is.
+// weight is the relative share of the asset population; movesPerDay the mean
+// number of sightings on a day the asset is active; activeDays the chance it
+// is seen at all on a given day.
+type category struct {
+	prefix, name string
+	makers       []string
+	weight       int
+	movesPerDay  float64
+	activeDays   float64
+}
+
+var categories = []category{
+	{"RT", "Returnable Tote", []string{"Orbis", "Buckhorn"}, 40, 3, 0.8},
+	{"PLT", "Plastic Pallet", []string{"Rehrig", "CHEP"}, 20, 2, 0.6},
+	{"CC", "Cage Cart", []string{"Wanzl", "Akro-Mils"}, 10, 2, 0.7},
+	{"PJ", "Pallet Jack", []string{"Crown", "Toyota"}, 6, 3, 0.9},
+	{"FL", "Forklift", []string{"Hyster", "Toyota", "Crown"}, 4, 4, 0.95},
+	{"HS", "Handheld Scanner", []string{"Zebra", "Honeywell"}, 8, 2, 0.9},
+	{"LT", "Laptop", []string{"Dell", "Lenovo"}, 6, 1, 0.5},
+	{"TK", "Tool Kit", []string{"Snap-on", "Milwaukee"}, 4, 1, 0.4},
+	{"TW", "Torque Wrench", []string{"CDI", "Norbar"}, 2, 1, 0.3},
+}
+
+type demoAsset struct {
+	storage.SeedAsset
+	category *category
+	home     int // index into sites
+}
+
+// generateAssets spreads n assets across the categories by weight and the
+// sites round-robin. EPCs embed the seed and a sequence number, so they are
+// unique within and across runs with different seeds.
+func generateAssets(rng *rand.Rand, n int, seed uint64, sites []site) []demoAsset {
+	total := 0
+	for _, c := range categories {
+		total += c.weight
+	}
+
+	counters := make(map[string]int, len(categories))
+	out := make([]demoAsset, n)
+	for i := range out {
+		pick := rng.IntN(total)
+		c := &categories[0]
+		for j := range categories {
+			if pick < categories[j].weight {
+				c = &categories[j]
+				break
+			}
+			pick -= categories[j].weight
+		}
+		counters[c.prefix]++
+		seq := counters[c.prefix]
+		home := i % len(sites)
+		maker := c.makers[rng.IntN(len(c.makers))]
+
+		out[i] = demoAsset{
+			SeedAsset: storage.SeedAsset{
+				ExternalKey: fmt.Sprintf("%s-%05d", c.prefix, seq),
+				Name:        fmt.Sprintf("%s %04d", c.name, seq),
+				Description: fmt.Sprintf("%s %s, home site %s", maker, c.name, sites[home].name),
+				Metadata: map[string]any{
+					"category":     c.name,
+					"manufacturer": maker,
+					"home_site":    sites[home].key,
+				},
+				TagValue: fmt.Sprintf("E2801160%08X%08X", uint32(seed), uint32(i+1)),
+			},
+			category: c,
+			home:     home,
+		}
+	}
+	return out
+}
+
+type sighting struct {
+	at          time.Time
+	assetKey    string
+	locationKey string
+}
+
+// generateHistory walks each asset around its home site over days, during
+// working hours, with an occasional transfer to another site. The last day
+// always has a sighting so every asset has a current location; sightings
+// that would fall outside [start, end] are clamped to it.
+func generateHistory(rng *rand.Rand, assets []demoAsset, sites []site, start, end time.Time, days int) []sighting {
+	leaves := make([][]string, len(sites))
+	for i, s := range sites {
+		leaves[i] = s.leaves()
+	}
+
+	var out []sighting
+	for _, a := range assets {
+		siteIdx := a.home
+		for d := 0; d <= days; d++ {
+			if d < days && rng.Float64() >= a.category.activeDays {
+				continue
+			}
+			// 2% of active days the asset is transferred and seen at another site.
+			if len(sites) > 1 && rng.Float64() < 0.02 {
+				siteIdx = (siteIdx + 1 + rng.IntN(len(sites)-1)) % len(sites)
+			}
+			moves := 1 + rng.IntN(int(2*a.category.movesPerDay))
+			times := make([]time.Duration, moves)
+			for m := range times {
+				// 06:00–18:00 UTC working day.
+				times[m] = 6*time.Hour + time.Duration(rng.IntN(12*60))*time.Minute
+			}
+			sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+			day := start.Truncate(24*time.Hour).AddDate(0, 0, d)
+			for _, t := range times {
+				at := day.Add(t)
+				if at.Before(start) {
+					at = start
+				}
+				if at.After(end) {
+					at = end
+				}
+				out = append(out, sighting{
+					at:          at,
+					assetKey:    a.ExternalKey,
+					locationKey: leaves[siteIdx][rng.IntN(len(leaves[siteIdx]))],
+				})
+			}
+		}
+	}
+	return out
+}
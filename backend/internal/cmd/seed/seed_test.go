@@ -0,0 +1,104 @@
+package seed
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+)
+
+func TestParseArgs(t *testing.T) {
+	c, err := parseArgs(nil)
+	if err != nil {
+		t.Fatalf("defaults: %v", err)
+	}
+	if c.OrgIdentifier != "demo" || c.Assets != 2500 || c.Days != 30 || c.Seed != 1 {
+		t.Errorf("defaults = %+v", c)
+	}
+
+	c, err = parseArgs([]string{"--org", "acme-demo", "--assets", "10", "--days", "7", "--seed", "42", "--admin-email", "a@example.com"})
+	if err != nil {
+		t.Fatalf("flags: %v", err)
+	}
+	if c.OrgIdentifier != "acme-demo" || c.Assets != 10 || c.Days != 7 || c.Seed != 42 || c.AdminEmail != "a@example.com" {
+		t.Errorf("flags = %+v", c)
+	}
+
+	for _, args := range [][]string{
+		{"--assets", "0"},
+		{"--days", "-1"},
+		{"--org", ""},
+		{"--bogus"},
+		{"extra"},
+	} {
+		if _, err := parseArgs(args); err == nil {
+			t.Errorf("parseArgs(%v) err = nil, want error", args)
+		}
+	}
+}
+
+func TestFlattenSites_ParentsFirst(t *testing.T) {
+	seen := map[string]bool{}
+	for _, loc := range flattenSites(demoSites()) {
+		if seen[loc.key] {
+			t.Fatalf("duplicate location key %s", loc.key)
+		}
+		if loc.parent != "" && !seen[loc.parent] {
+			t.Fatalf("%s listed before its parent %s", loc.key, loc.parent)
+		}
+		seen[loc.key] = true
+	}
+	for _, s := range demoSites() {
+		for _, leaf := range s.leaves() {
+			if !seen[leaf] {
+				t.Errorf("leaf %s is not a created location", leaf)
+			}
+		}
+	}
+}
+
+func TestGenerate_DeterministicAndUnique(t *testing.T) {
+	sites := demoSites()
+	a := generateAssets(rand.New(rand.NewPCG(7, 7)), 500, 7, sites)
+	b := generateAssets(rand.New(rand.NewPCG(7, 7)), 500, 7, sites)
+
+	keys := map[string]bool{}
+	epcs := map[string]bool{}
+	for i := range a {
+		if a[i].ExternalKey != b[i].ExternalKey || a[i].TagValue != b[i].TagValue {
+			t.Fatalf("asset %d differs between runs with the same seed", i)
+		}
+		if keys[a[i].ExternalKey] || epcs[a[i].TagValue] {
+			t.Fatalf("duplicate key or EPC at asset %d: %s %s", i, a[i].ExternalKey, a[i].TagValue)
+		}
+		keys[a[i].ExternalKey] = true
+		epcs[a[i].TagValue] = true
+		if len(a[i].TagValue) != 24 {
+			t.Errorf("EPC %q is not 96-bit hex", a[i].TagValue)
+		}
+	}
+}
+
+func TestGenerateHistory_BoundedAndCurrent(t *testing.T) {
+	sites := demoSites()
+	rng := rand.New(rand.NewPCG(1, 1))
+	assets := generateAssets(rng, 50, 1, sites)
+	end := time.Date(2026, 3, 10, 9, 30, 0, 0, time.UTC)
+	start := end.AddDate(0, 0, -14)
+
+	history := generateHistory(rng, assets, sites, start, end, 14)
+
+	lastDay := map[string]bool{}
+	for _, h := range history {
+		if h.at.Before(start) || h.at.After(end) {
+			t.Fatalf("sighting at %v outside [%v, %v]", h.at, start, end)
+		}
+		if h.at.After(end.Truncate(24 * time.Hour)) {
+			lastDay[h.assetKey] = true
+		}
+	}
+	for _, a := range assets {
+		if !lastDay[a.ExternalKey] {
+			t.Errorf("asset %s has no sighting on the last day", a.ExternalKey)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+//go:build integration
+
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/apicontract"
+	"github.com/trakrf/platform/backend/internal/models/apikey"
+	"github.com/trakrf/platform/backend/internal/testutil"
+	"github.com/trakrf/platform/backend/internal/util/apisecret"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// TestOpenAPIContract_PublicSurface drives every operation in
+// openapi.public.json through the production router over a real database,
+// as an API-key integrator would, and checks every response (status code
+// and body) against the document. A handler whose payload or status drifts
+// from its annotations fails here; so does a new public operation this tour
+// does not yet exercise.
+func TestOpenAPIContract_PublicSurface(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-key")
+	ctx := context.Background()
+
+	spec, err := apicontract.LoadPublic()
+	require.NoError(t, err)
+	db := testutil.SetupTestDBFull(t)
+	rec := spec.Recorder()
+	h := rec.Wrap(setupRealRouter(t, db.Store))
+
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	var userID int
+	require.NoError(t, db.AdminPool.QueryRow(ctx, `
+		INSERT INTO trakrf.users (name, email, password_hash)
+		VALUES ('contract', 'contract@example.com', 'stub') RETURNING id`,
+	).Scan(&userID))
+
+	scopes := []string{"assets:read", "assets:write", "locations:read", "locations:write", "tracking:read"}
+	secret, err := apisecret.Generate()
+	require.NoError(t, err)
+	key, err := db.Store.CreateAPIKey(ctx, orgID, "contract", apisecret.Hash(secret), scopes, apikey.Creator{UserID: &userID}, nil)
+	require.NoError(t, err)
+	exp := time.Now().Add(15 * time.Minute)
+	token, err := jwt.GenerateAccessToken(key.JTI, orgID, scopes, &exp)
+	require.NoError(t, err)
+
+	// send issues one request and requires the wanted status; the recorder
+	// checks the response against the document on the way out.
+	send := func(bearer, method, path, contentType string, body any, want int) map[string]any {
+		t.Helper()
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		req := httptest.NewRequest(method, path, &buf)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		require.Equal(t, want, w.Code, "%s %s: %s", method, path, w.Body.String())
+
+		var out map[string]any
+		if w.Body.Len() > 0 {
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out), "%s %s: %s", method, path, w.Body.String())
+		}
+		return out
+	}
+	do := func(method, path string, body any, want int) map[string]any {
+		t.Helper()
+		contentType := ""
+		if body != nil {
+			contentType = "application/json"
+		}
+		return send(token, method, path, contentType, body, want)
+	}
+	patch := func(path string, body any) map[string]any {
+		t.Helper()
+		return send(token, http.MethodPatch, path, "application/merge-patch+json", body, http.StatusOK)
+	}
+	// dataID returns the id of the resource in a {"data": {...}} envelope.
+	dataID := func(resp map[string]any) string {
+		t.Helper()
+		data, ok := resp["data"].(map[string]any)
+		require.True(t, ok, "no data object in %v", resp)
+		id, ok := data["id"].(float64)
+		require.True(t, ok, "no numeric data.id in %v", resp)
+		return strconv.Itoa(int(id))
+	}
+
+	// Credentials and org.
+	send("", http.MethodPost, "/api/v1/oauth/token", "application/json", map[string]string{
+		"grant_type":    "client_credentials",
+		"client_id":     key.JTI,
+		"client_secret": secret,
+	}, http.StatusOK)
+	do(http.MethodGet, "/api/v1/orgs/me", nil, http.StatusOK)
+
+	// Locations: a warehouse with one bay.
+	wh := dataID(do(http.MethodPost, "/api/v1/locations", map[string]any{
+		"external_key": "CT-WH",
+		"name":         "Contract Warehouse",
+		"tags":         []map[string]string{{"tag_type": "rfid", "value": "E28011700000020C0A7A0001"}},
+	}, http.StatusCreated))
+	bay := dataID(do(http.MethodPost, "/api/v1/locations", map[string]any{
+		"external_key":        "CT-BAY",
+		"name":                "Contract Bay",
+		"parent_external_key": "CT-WH",
+	}, http.StatusCreated))
+
+	do(http.MethodGet, "/api/v1/locations", nil, http.StatusOK)
+	do(http.MethodGet, "/api/v1/locations/"+bay, nil, http.StatusOK)
+	do(http.MethodGet, "/api/v1/locations/"+bay+"/ancestors", nil, http.StatusOK)
+	do(http.MethodGet, "/api/v1/locations/"+wh+"/children", nil, http.StatusOK)
+	do(http.MethodGet, "/api/v1/locations/"+wh+"/descendants", nil, http.StatusOK)
+	patch("/api/v1/locations/"+bay, map[string]any{"name": "Contract Bay 1"})
+	do(http.MethodPost, "/api/v1/locations/"+bay+"/rename", map[string]any{"external_key": "CT-BAY-1"}, http.StatusOK)
+	locTag := dataID(do(http.MethodPost, "/api/v1/locations/"+bay+"/tags",
+		map[string]string{"tag_type": "barcode", "value": "CT-BAY-1-LABEL"}, http.StatusCreated))
+	do(http.MethodDelete, "/api/v1/locations/"+bay+"/tags/"+locTag, nil, http.StatusNoContent)
+
+	// Assets.
+	asset := dataID(do(http.MethodPost, "/api/v1/assets", map[string]any{
+		"external_key": "CT-FL",
+		"name":         "Contract Forklift",
+		"tags":         []map[string]string{{"tag_type": "rfid", "value": "E28011700000020C0A7A0002"}},
+	}, http.StatusCreated))
+
+	do(http.MethodGet, "/api/v1/assets", nil, http.StatusOK)
+	do(http.MethodGet, "/api/v1/assets/"+asset, nil, http.StatusOK)
+	patch("/api/v1/assets/"+asset, map[string]any{"description": "Counterbalance"})
+	do(http.MethodPost, "/api/v1/assets/"+asset+"/rename", map[string]any{"external_key": "CT-FL-1"}, http.StatusOK)
+	assetTag := dataID(do(http.MethodPost, "/api/v1/assets/"+asset+"/tags",
+		map[string]string{"tag_type": "ble", "value": "AA:BB:CC:DD:EE:01"}, http.StatusCreated))
+	do(http.MethodDelete, "/api/v1/assets/"+asset+"/tags/"+assetTag, nil, http.StatusNoContent)
+	do(http.MethodGet, "/api/v1/assets/"+asset+"/history", nil, http.StatusOK)
+	do(http.MethodGet, "/api/v1/reports/asset-locations", nil, http.StatusOK)
+
+	// Documented errors carry the envelope too.
+	do(http.MethodGet, "/api/v1/assets/999999999", nil, http.StatusNotFound)
+	do(http.MethodPost, "/api/v1/assets", map[string]any{}, http.StatusBadRequest)
+	do(http.MethodPost, "/api/v1/locations", map[string]any{"name": "Dup", "external_key": "CT-WH"}, http.StatusConflict)
+
+	do(http.MethodDelete, "/api/v1/assets/"+asset, nil, http.StatusNoContent)
+	do(http.MethodDelete, "/api/v1/locations/"+bay, nil, http.StatusNoContent)
+
+	for _, err := range rec.Failures() {
+		t.Error(err)
+	}
+	assert.Empty(t, rec.Unexercised(), "public operations with no checked 2xx response; observed:\n%s", rec)
+}
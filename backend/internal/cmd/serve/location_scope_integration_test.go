@@ -0,0 +1,162 @@
+//go:build integration
+
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/testutil"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// TestLocationAccessPolicies_NegativePaths drives the production router as a
+// manager granted only the LSC-A subtree of a LSC-ROOT > {LSC-A, LSC-B}
+// tree, and asserts nothing outside LSC-A is reachable through parent
+// resolution, ancestors, the asset endpoints or the asset reports.
+func TestLocationAccessPolicies_NegativePaths(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-key")
+
+	db := testutil.SetupTestDBFull(t)
+	r := setupRealRouter(t, db.Store)
+
+	send := func(token, method, path string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		if body != nil {
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		req := httptest.NewRequest(method, path, &buf).WithContext(ctx)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	admin := seedTenant(t, db, send, testutil.NewOrgFactory(), "lsc")
+	create := func(path string, body any) int {
+		t.Helper()
+		w := send(admin.token, http.MethodPost, path, body)
+		require.Equal(t, http.StatusCreated, w.Code, "seed %s: %s", path, w.Body.String())
+		var resp struct {
+			Data struct {
+				ID int `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.ID
+	}
+	root := create("/api/v1/locations", map[string]any{"external_key": "LSC-ROOT", "name": "LSC Root"})
+	granted := create("/api/v1/locations", map[string]any{"external_key": "LSC-A", "name": "LSC A", "parent_id": root})
+	outside := create("/api/v1/locations", map[string]any{"external_key": "LSC-B", "name": "LSC B", "parent_id": root})
+	shelf := create("/api/v1/locations", map[string]any{"external_key": "LSC-A1", "name": "LSC A1", "parent_id": granted})
+	inAsset := create("/api/v1/assets", map[string]any{"external_key": "LSC-IN", "name": "LSC In"})
+	outAsset := create("/api/v1/assets", map[string]any{"external_key": "LSC-OUT", "name": "LSC Out"})
+
+	// LSC-IN passed through LSC-B before arriving in LSC-A.
+	scans := testutil.NewScanFactory(admin.org)
+	scans.Create(t, db.AdminPool, inAsset, outside)
+	scans.Create(t, db.AdminPool, inAsset, granted)
+	scans.Create(t, db.AdminPool, outAsset, outside)
+	testutil.RefreshAssetScanLatest(t, db.AdminPool)
+
+	ctx := context.Background()
+	var manager int
+	require.NoError(t, db.AdminPool.QueryRow(ctx, `
+		INSERT INTO trakrf.users (name, email, password_hash)
+		VALUES ('LSC Manager', 'lsc-manager@example.com', 'stub') RETURNING id`).Scan(&manager))
+	_, err := db.AdminPool.Exec(ctx, `
+		INSERT INTO trakrf.org_users (org_id, user_id, role, status)
+		VALUES ($1, $2, 'manager', 'active')`, admin.org, manager)
+	require.NoError(t, err)
+	_, err = db.AdminPool.Exec(ctx, `
+		INSERT INTO trakrf.location_access_policies (org_id, location_id, user_id)
+		VALUES ($1, $2, $3)`, admin.org, granted, manager)
+	require.NoError(t, err)
+	token, err := jwt.Generate(manager, "lsc-manager@example.com", &admin.org)
+	require.NoError(t, err)
+
+	t.Run("create under an outside parent", func(t *testing.T) {
+		w := send(token, http.MethodPost, "/api/v1/locations",
+			map[string]any{"external_key": "LSC-B1", "name": "LSC B1", "parent_id": outside})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "fk_not_found")
+
+		w = send(token, http.MethodPost, "/api/v1/locations",
+			map[string]any{"external_key": "LSC-B2", "name": "LSC B2", "parent_external_key": "LSC-B"})
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "fk_not_found")
+	})
+
+	t.Run("create a root", func(t *testing.T) {
+		w := send(token, http.MethodPost, "/api/v1/locations",
+			map[string]any{"external_key": "LSC-C", "name": "LSC C"})
+		assert.Equal(t, http.StatusForbidden, w.Code, w.Body.String())
+	})
+
+	t.Run("create inside the grant", func(t *testing.T) {
+		w := send(token, http.MethodPost, "/api/v1/locations",
+			map[string]any{"external_key": "LSC-A2", "name": "LSC A2", "parent_id": granted})
+		assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	})
+
+	t.Run("ancestors stop at the granted root", func(t *testing.T) {
+		w := send(token, http.MethodGet, "/api/v1/locations/"+strconv.Itoa(shelf)+"/ancestors", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "LSC-A")
+		assert.NotContains(t, w.Body.String(), "LSC-ROOT")
+	})
+
+	t.Run("asset list", func(t *testing.T) {
+		w := send(token, http.MethodGet, "/api/v1/assets", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "LSC-IN")
+		assert.NotContains(t, w.Body.String(), "LSC-OUT")
+	})
+
+	t.Run("asset by id", func(t *testing.T) {
+		w := send(token, http.MethodGet, "/api/v1/assets/"+strconv.Itoa(outAsset), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+		w = send(token, http.MethodDelete, "/api/v1/assets/"+strconv.Itoa(outAsset), nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+		w = send(token, http.MethodGet, "/api/v1/assets/"+strconv.Itoa(inAsset), nil)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	})
+
+	t.Run("asset-locations report", func(t *testing.T) {
+		w := send(token, http.MethodGet, "/api/v1/reports/asset-locations", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "LSC-IN")
+		assert.NotContains(t, w.Body.String(), "LSC-OUT")
+		assert.Contains(t, w.Body.String(), `"total_count":1`)
+	})
+
+	t.Run("asset history", func(t *testing.T) {
+		w := send(token, http.MethodGet, "/api/v1/assets/"+strconv.Itoa(outAsset)+"/history", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+		w = send(token, http.MethodGet, "/api/v1/assets/"+strconv.Itoa(inAsset)+"/history", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), "LSC-A")
+		assert.NotContains(t, w.Body.String(), "LSC-B")
+		assert.Contains(t, w.Body.String(), `"total_count":1`)
+	})
+
+	// The admin is exempt from location access policies.
+	w := send(admin.token, http.MethodGet, "/api/v1/assets/"+strconv.Itoa(outAsset), nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
@@ -0,0 +1,65 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/dbbackup"
+	healthhandler "github.com/trakrf/platform/backend/internal/handlers/health"
+	"github.com/trakrf/platform/backend/internal/ingest"
+	"github.com/trakrf/platform/backend/internal/services/email"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/migrations"
+)
+
+// addReadinessChecks registers the /readyz dependency checks beyond the
+// database ping NewHandler adds itself. The schema check is critical — a pod
+// whose binary expects a newer schema than the database has would fail
+// requests. Email and MQTT are not: without them the pod still serves the
+// API, so they only mark it degraded. subscriber is nil when MQTT is
+// disabled, in which case no MQTT check is registered. The backup check is
+// not critical either: a failed or stale backup needs an operator, not a
+// restart. backupMaxAge is 0 when the environment takes no backups, in which
+// case it is not registered. The platform has no object-storage dependency
+// yet, so there is nothing to check for it.
+func addReadinessChecks(h *healthhandler.Handler, store *storage.Storage, emailClient *email.Client, subscriber *ingest.Subscriber, backupMaxAge time.Duration) {
+	h.AddCheck("migrations", true, func(ctx context.Context) error {
+		want, err := migrations.LatestVersion()
+		if err != nil {
+			return err
+		}
+		have, dirty, err := store.SchemaVersion(ctx)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("schema version %d is dirty", have)
+		}
+		// A newer schema is fine: migrations run ahead of the rollout.
+		if have < want {
+			return fmt.Errorf("schema version %d is behind expected %d", have, want)
+		}
+		return nil
+	})
+
+	h.AddCheck("email", false, func(context.Context) error {
+		return emailClient.ConfigError()
+	})
+
+	if subscriber != nil {
+		h.AddCheck("mqtt", false, func(context.Context) error {
+			if !subscriber.Connected() {
+				return errors.New("broker not connected")
+			}
+			return nil
+		})
+	}
+
+	if backupMaxAge > 0 {
+		h.AddCheck("backup", false, func(ctx context.Context) error {
+			return dbbackup.Check(ctx, store, backupMaxAge, time.Now())
+		})
+	}
+}
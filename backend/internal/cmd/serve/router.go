@@ -14,15 +14,32 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
 
+	"github.com/trakrf/platform/backend/internal/applinks"
+	adminhandler "github.com/trakrf/platform/backend/internal/handlers/admin"
+	approvalshandler "github.com/trakrf/platform/backend/internal/handlers/approvals"
+	assetdisposalshandler "github.com/trakrf/platform/backend/internal/handlers/assetdisposals"
 	assetshandler "github.com/trakrf/platform/backend/internal/handlers/assets"
+	assettransfershandler "github.com/trakrf/platform/backend/internal/handlers/assettransfers"
 	authhandler "github.com/trakrf/platform/backend/internal/handlers/auth"
+	connectorshandler "github.com/trakrf/platform/backend/internal/handlers/connectors"
+	dashboardshandler "github.com/trakrf/platform/backend/internal/handlers/dashboards"
+	deviceshandler "github.com/trakrf/platform/backend/internal/handlers/devices"
+	dockdoorshandler "github.com/trakrf/platform/backend/internal/handlers/dockdoors"
+	documentshandler "github.com/trakrf/platform/backend/internal/handlers/documents"
+	epcishandler "github.com/trakrf/platform/backend/internal/handlers/epcis"
 	frontendhandler "github.com/trakrf/platform/backend/internal/handlers/frontend"
 	healthhandler "github.com/trakrf/platform/backend/internal/handlers/health"
+	identifiershandler "github.com/trakrf/platform/backend/internal/handlers/identifiers"
+	integrationshandler "github.com/trakrf/platform/backend/internal/handlers/integrations"
 	inventoryhandler "github.com/trakrf/platform/backend/internal/handlers/inventory"
+	kioskhandler "github.com/trakrf/platform/backend/internal/handlers/kiosk"
 	kitshandler "github.com/trakrf/platform/backend/internal/handlers/kits"
+	labelprintershandler "github.com/trakrf/platform/backend/internal/handlers/labelprinters"
+	locationpolicieshandler "github.com/trakrf/platform/backend/internal/handlers/locationpolicies"
 	locationshandler "github.com/trakrf/platform/backend/internal/handlers/locations"
 	lookuphandler "github.com/trakrf/platform/backend/internal/handlers/lookup"
 	musteringhandler "github.com/trakrf/platform/backend/internal/handlers/mustering"
+	offlinesynchandler "github.com/trakrf/platform/backend/internal/handlers/offlinesync"
 	orgshandler "github.com/trakrf/platform/backend/internal/handlers/orgs"
 	outputdeviceshandler "github.com/trakrf/platform/backend/internal/handlers/outputdevices"
 	readerconfighandler "github.com/trakrf/platform/backend/internal/handlers/readerconfig"
@@ -30,11 +47,17 @@ import (
 	reportshandler "github.com/trakrf/platform/backend/internal/handlers/reports"
 	scandeviceshandler "github.com/trakrf/platform/backend/internal/handlers/scandevices"
 	scanpointshandler "github.com/trakrf/platform/backend/internal/handlers/scanpoints"
+	sensorshandler "github.com/trakrf/platform/backend/internal/handlers/sensors"
+	stockhandler "github.com/trakrf/platform/backend/internal/handlers/stock"
 	"github.com/trakrf/platform/backend/internal/handlers/swaggerspec"
+	teamshandler "github.com/trakrf/platform/backend/internal/handlers/teams"
 	testhandler "github.com/trakrf/platform/backend/internal/handlers/testhandler"
+	transferordershandler "github.com/trakrf/platform/backend/internal/handlers/transferorders"
 	usershandler "github.com/trakrf/platform/backend/internal/handlers/users"
 	"github.com/trakrf/platform/backend/internal/logger"
 	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/approval"
 	"github.com/trakrf/platform/backend/internal/ratelimit"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
@@ -58,6 +81,26 @@ func setupRouter(
 	readstreamHandler *readstreamhandler.Handler,
 	musteringHandler *musteringhandler.Handler,
 	kitsHandler *kitshandler.Handler,
+	teamsHandler *teamshandler.Handler,
+	locationPoliciesHandler *locationpolicieshandler.Handler,
+	integrationsHandler *integrationshandler.Handler,
+	connectorsHandler *connectorshandler.Handler,
+	epcisHandler *epcishandler.Handler,
+	sensorsHandler *sensorshandler.Handler,
+	offlineSyncHandler *offlinesynchandler.Handler,
+	devicesHandler *deviceshandler.Handler,
+	assetTransfersHandler *assettransfershandler.Handler,
+	kioskHandler *kioskhandler.Handler,
+	identifiersHandler *identifiershandler.Handler,
+	stockHandler *stockhandler.Handler,
+	assetDisposalsHandler *assetdisposalshandler.Handler,
+	labelPrintersHandler *labelprintershandler.Handler,
+	dockDoorsHandler *dockdoorshandler.Handler,
+	transferOrdersHandler *transferordershandler.Handler,
+	documentsHandler *documentshandler.Handler,
+	dashboardsHandler *dashboardshandler.Handler,
+	approvalsHandler *approvalshandler.Handler,
+	adminHandler *adminhandler.Handler,
 	testHandler *testhandler.Handler,
 	store *storage.Storage,
 ) *chi.Mux {
@@ -80,10 +123,15 @@ func setupRouter(
 	rl := ratelimit.NewLimiter(ratelimit.DefaultConfig())
 
 	r.Use(middleware.RequestID)
+	r.Use(middleware.Locale)
 	r.Use(logger.Middleware)
 	r.Use(sentryhttp.New(sentryhttp.Options{Repanic: true}).Handle)
 	r.Use(middleware.Recovery)
 	r.Use(middleware.CORS)
+	// Bound every request body (1 MiB default, per-route overrides in
+	// middleware/bodylimit.go) before any handler reads it.
+	r.Use(middleware.MaxBodySize)
+	r.Use(middleware.Compress)
 	r.Use(middleware.APIv1DefaultRateLimitHeaders(rl))
 	// ContentType is intentionally NOT global. Applying it globally would
 	// reject POST/PUT/PATCH probes against retired and static-only paths
@@ -100,6 +148,7 @@ func setupRouter(
 	r.Handle("/icon-*", http.HandlerFunc(frontendHandler.ServeFrontend))
 	r.Handle("/logo.png", http.HandlerFunc(frontendHandler.ServeFrontend))
 	r.Handle("/manifest.json", http.HandlerFunc(frontendHandler.ServeFrontend))
+	r.Handle("/manifest.webmanifest", http.HandlerFunc(frontendHandler.ServeFrontend))
 	r.Handle("/og-image.png", http.HandlerFunc(frontendHandler.ServeFrontend))
 	// TRA-481: curl-able SPA build metadata, generated by a Vite plugin at
 	// build time. Specific route entry so the SPA fallback doesn't swallow it.
@@ -112,8 +161,8 @@ func setupRouter(
 	// are /api/openapi.{json,yaml} (TRA-693 / BB30 §2.3); the /api/v1/
 	// variants and the root-path aliases redirect so codegen tools probing
 	// them don't fork on a duplicated payload.
-	r.Get("/api/openapi.json", swaggerspec.ServePublicJSON)
-	r.Get("/api/openapi.yaml", swaggerspec.ServePublicYAML)
+	r.With(middleware.ConditionalGET).Get("/api/openapi.json", swaggerspec.ServePublicJSON)
+	r.With(middleware.ConditionalGET).Get("/api/openapi.yaml", swaggerspec.ServePublicYAML)
 	r.Get("/api/v1/openapi.json", func(w http.ResponseWriter, req *http.Request) {
 		http.Redirect(w, req, "/api/openapi.json", http.StatusMovedPermanently)
 	})
@@ -146,20 +195,39 @@ func setupRouter(
 	// gate exactly those routes (this group also carries must-stay-open writes:
 	// org/user/api-key mgmt, current-org switch, output test/reset).
 	paidGate := middleware.SubscriptionRequired(store)
+	// One banner for every authenticated group, so they share its cache of
+	// sandbox flags.
+	sandboxBanner := middleware.SandboxBanner(store)
+	// Likewise one host resolver, shared with the SPA route below. Session
+	// requests on an org subdomain or custom domain are scoped to that org.
+	hostOrg := middleware.HostOrg(store, applinks.FromEnv())
+	scopeToHostOrg := middleware.ScopeToHostOrg(store)
+	// One read-only switch for every group that carries mutations, sharing
+	// its cache of maintenance windows. Sign-in stays open.
+	readOnly := middleware.ReadOnlyMode(store)
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Auth)
+		r.Use(hostOrg)
+		r.Use(scopeToHostOrg)
+		r.Use(readOnly)
 		r.Use(middleware.SentryContext)
+		r.Use(sandboxBanner)
 		r.Use(middleware.ContentType)
 
-		orgsHandler.RegisterRoutes(r, store)
+		orgsHandler.RegisterRoutes(r, store, store, store)
 		orgsHandler.RegisterMeRoutes(r)
-		usersHandler.RegisterRoutes(r)
+		usersHandler.RegisterRoutes(r, store)
 		assetsHandler.RegisterRoutes(r, paidGate)
+		// Bulk delete is manager+ and subject to the org's approval rule.
+		r.With(middleware.RequireCurrentOrgRole(store, models.RoleManager), paidGate,
+			middleware.RequireApproval(store, approval.OpAssetsBulkDelete)).
+			Post("/api/v1/assets/bulk-delete", assetsHandler.BulkDelete)
 		inventoryHandler.RegisterRoutes(r)
 		reportsHandler.RegisterRoutes(r)
 		// Internal-only scan device/point management (not public API).
 		scanDevicesHandler.RegisterRoutes(r, paidGate)
+		scanDevicesHandler.RegisterCredentialRoutes(r, store)
 		scanPointsHandler.RegisterRoutes(r, paidGate)
 		// Internal-only output device management (not public API).
 		outputDevicesHandler.RegisterRoutes(r, paidGate)
@@ -176,6 +244,46 @@ func setupRouter(
 		// Operator gate resolves the org from JWT claims, NOT a URL param —
 		// these routes have no :orgId, so RequireOrgOperator would 400 (TRA-1033).
 		kitsHandler.RegisterRoutes(r, paidGate, middleware.RequireCurrentOrgOperator(store))
+		// Departments/teams: CRUD, membership, and asset/location assignment.
+		// Org-implicit routes, so role gates resolve the org from JWT claims.
+		teamsHandler.RegisterRoutes(r, store)
+		// Location access policies (subtree grants), org-admin only.
+		locationPoliciesHandler.RegisterRoutes(r, store)
+		// ERP connectors (config, credentials, sync runs), org-admin only.
+		connectorsHandler.RegisterRoutes(r, store)
+		// Cold-chain sensor thresholds; member read, admin write.
+		sensorsHandler.RegisterRoutes(r, store)
+		// The signed-in user's mobile app push registrations.
+		devicesHandler.RegisterRoutes(r)
+		// Cross-org asset transfers (initiate, approve, execute), org-admin only.
+		assetTransfersHandler.RegisterRoutes(r, store)
+		// Kiosk display token management, org-admin only.
+		kioskHandler.RegisterRoutes(r, store)
+		// Tag assignment history, for tags recycled across assets.
+		identifiersHandler.RegisterRoutes(r)
+		// Consumable stock levels and adjustments; member read, operator
+		// adjust, admin configure.
+		stockHandler.RegisterRoutes(r, store)
+		// Asset disposal requests; operator request/execute, admin approval.
+		assetDisposalsHandler.RegisterRoutes(r, store)
+		// Label printers and print jobs; member read, operator print, admin
+		// configure.
+		labelPrintersHandler.RegisterRoutes(r, store)
+		// Dock doors pairing scan points for direction detection; member
+		// read, admin configure.
+		dockDoorsHandler.RegisterRoutes(r, store)
+		transferOrdersHandler.RegisterRoutes(r, store)
+		// Document vault: insurance, leases, calibration certs; member read,
+		// operator file and upload.
+		documentsHandler.RegisterRoutes(r, store)
+		// Custom dashboards: saved widget queries evaluated server-side;
+		// private to the owner until shared.
+		dashboardsHandler.RegisterRoutes(r, store)
+		// Approval rules and requests for operations that need a second
+		// admin's sign-off.
+		approvalsHandler.RegisterRoutes(r, store)
+		// Superadmin-only redacted view of the boot configuration.
+		adminHandler.RegisterRoutes(r, store)
 
 		r.Get("/swagger/openapi.internal.json", swaggerspec.ServeJSON)
 		r.Get("/swagger/openapi.internal.yaml", swaggerspec.ServeYAML)
@@ -196,6 +304,7 @@ func setupRouter(
 		middleware.DefaultRateLimitHeaders(rl),
 		middleware.APIKeyAuth(store),
 		middleware.RateLimit(rl, allowTestRateLimitBypass),
+		sandboxBanner,
 		middleware.RejectQueryParams(),
 	).Get("/api/v1/orgs/me", orgsHandler.GetOrgMe)
 
@@ -204,8 +313,12 @@ func setupRouter(
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.DefaultRateLimitHeaders(rl))
 		r.Use(middleware.EitherAuth(store))
+		r.Use(hostOrg)
+		r.Use(scopeToHostOrg)
+		r.Use(readOnly)
 		r.Use(middleware.RateLimit(rl, allowTestRateLimitBypass))
 		r.Use(middleware.SentryContext)
+		r.Use(sandboxBanner)
 		r.Use(middleware.ContentType)
 		orgsHandler.RegisterAPIKeyRoutes(r, store)
 	})
@@ -214,24 +327,59 @@ func setupRouter(
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.DefaultRateLimitHeaders(rl))
 		r.Use(middleware.EitherAuth(store))
+		r.Use(hostOrg)
+		r.Use(scopeToHostOrg)
 		r.Use(middleware.RateLimit(rl, allowTestRateLimitBypass))
 		r.Use(middleware.SentryContext)
-
-		r.With(middleware.RequireScope("assets:read")).Get("/api/v1/assets", assetsHandler.ListAssets)
-		r.With(middleware.RequireScope("assets:read"), middleware.RejectQueryParams()).Get("/api/v1/assets/{asset_id}", assetsHandler.GetAsset)
-
-		r.With(middleware.RequireScope("locations:read")).Get("/api/v1/locations", locationsHandler.ListLocations)
-		r.With(middleware.RequireScope("locations:read"), middleware.RejectQueryParams()).Get("/api/v1/locations/{location_id}", locationsHandler.GetLocation)
+		r.Use(sandboxBanner)
+
+		// ConditionalGET on the list/tree reads: big orgs re-poll multi-MB
+		// responses that rarely change, so a matching ETag answers with 304.
+		r.With(middleware.RequireScope("assets:read"), middleware.ConditionalGET).Get("/api/v1/assets", assetsHandler.ListAssets)
+		r.With(middleware.RequireScope("assets:read"), middleware.RejectQueryParams("as_of")).Get("/api/v1/assets/{asset_id}", assetsHandler.GetAsset)
+		r.With(middleware.RequireScope("assets:read")).Get("/api/v1/assets/{asset_id}/assignments", assetsHandler.ListAssignments)
+		r.With(middleware.RequireScope("assets:read")).Get("/api/v1/assets/{asset_id}/assignments/{assignment_id}/signature", assetsHandler.GetAssignmentSignature)
+		r.With(middleware.RequireScope("assets:read")).Get("/api/v1/assets/{asset_id}/condition-reports", assetsHandler.ListConditionReports)
+		r.With(middleware.RequireScope("assets:read")).Get("/api/v1/assets/{asset_id}/financials", assetsHandler.GetFinancials)
+
+		r.With(middleware.RequireScope("locations:read"), middleware.ConditionalGET).Get("/api/v1/locations", locationsHandler.ListLocations)
+		r.With(middleware.RequireScope("locations:read"), middleware.RejectQueryParams("as_of")).Get("/api/v1/locations/{location_id}", locationsHandler.GetLocation)
 		r.With(middleware.RequireScope("locations:read")).Get("/api/v1/locations/{location_id}/ancestors", locationsHandler.GetAncestors)
 		r.With(middleware.RequireScope("locations:read")).Get("/api/v1/locations/{location_id}/children", locationsHandler.GetChildren)
-		r.With(middleware.RequireScope("locations:read")).Get("/api/v1/locations/{location_id}/descendants", locationsHandler.GetDescendants)
+		r.With(middleware.RequireScope("locations:read"), middleware.ConditionalGET).Get("/api/v1/locations/{location_id}/descendants", locationsHandler.GetDescendants)
+		r.With(middleware.RequireScope("locations:read"), middleware.RejectQueryParams("subtree")).Get("/api/v1/locations/{location_id}/signage", locationsHandler.GetSignage)
+		r.With(middleware.RequireScope("locations:read")).Get("/api/v1/locations/{location_id}/schedule", locationsHandler.GetSchedule)
 
 		// tracking:read gates both the asset movement history (time-series)
 		// and the current-locations snapshot. The shared scope models the
 		// "where things are and have been" surface: an integrator scoping a
 		// key for live tracking gets both forms of locate-the-asset read.
-		r.With(middleware.RequireScope("tracking:read")).Get("/api/v1/reports/asset-locations", reportsHandler.ListCurrentLocations)
-		r.With(middleware.RequireScope("tracking:read")).Get("/api/v1/assets/{asset_id}/history", reportsHandler.GetAssetHistory)
+		r.With(middleware.RequireScope("tracking:read"), middleware.ConditionalGET).Get("/api/v1/reports/asset-locations", reportsHandler.ListCurrentLocations)
+		r.With(middleware.RequireScope("tracking:read"), middleware.ConditionalGET).Get("/api/v1/assets/{asset_id}/history", reportsHandler.GetAssetHistory)
+
+		// GS1 EPCIS 2.0 event query: the same scan history, in the standard
+		// format supply-chain partners consume.
+		r.With(middleware.RequireScope("tracking:read")).Get("/api/v1/epcis/events", epcisHandler.Events)
+
+		// Condition sensor history and the alerts raised from it.
+		r.With(middleware.RequireScope("sensors:read")).Get("/api/v1/assets/{asset_id}/sensor-readings", sensorsHandler.ListReadings)
+		r.With(middleware.RequireScope("sensors:read")).Get("/api/v1/sensor-alerts", sensorsHandler.ListAlerts)
+
+		// Offline sync change feed for the handheld app.
+		r.With(middleware.RequireScope("assets:read"), middleware.RequireScope("locations:read")).Get("/api/v1/sync/changes", offlineSyncHandler.Changes)
+	})
+
+	// Zapier/Make polling triggers — API-key auth only: these exist for
+	// no-code tools holding a key, not for the frontend.
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.DefaultRateLimitHeaders(rl))
+		r.Use(middleware.APIKeyAuth(store))
+		r.Use(middleware.RateLimit(rl, allowTestRateLimitBypass))
+		r.Use(middleware.SentryContext)
+		r.Use(sandboxBanner)
+
+		r.With(middleware.RequireScope("assets:read")).Get("/api/v1/integrations/zapier/new-assets", integrationsHandler.NewAssets)
+		r.With(middleware.RequireScope("tracking:read")).Get("/api/v1/integrations/zapier/moved-assets", integrationsHandler.MovedAssets)
 	})
 
 	// TRA-397 public write surface — accepts API-key OR session auth via EitherAuth.
@@ -242,30 +390,85 @@ func setupRouter(
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.DefaultRateLimitHeaders(rl))
 		r.Use(middleware.EitherAuth(store))
+		r.Use(hostOrg)
+		r.Use(scopeToHostOrg)
 		r.Use(middleware.WriteAudit)
+		r.Use(readOnly)
 		r.Use(middleware.SubscriptionRequired(store)) // TRA-947: 402 on not-entitled paid mutation
 		r.Use(middleware.RateLimit(rl, allowTestRateLimitBypass))
 		r.Use(middleware.SentryContext)
+		r.Use(sandboxBanner)
 		r.Use(middleware.ContentType)
 
 		// Assets
-		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post("/api/v1/assets", assetsHandler.Create)
+		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams("auto_identifier")).Post("/api/v1/assets", assetsHandler.Create)
+		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post(middleware.AssetValidatePath, assetsHandler.ValidateAssets)
 		r.With(middleware.RequireScope("assets:write"), middleware.RequireMergePatchCT, middleware.RejectQueryParams()).Patch("/api/v1/assets/{asset_id}", assetsHandler.Update)
 		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Delete("/api/v1/assets/{asset_id}", assetsHandler.Delete)
 		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post("/api/v1/assets/{asset_id}/rename", assetsHandler.Rename)
+		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post("/api/v1/assets/{asset_id}/transfer", assetsHandler.Transfer)
+		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post("/api/v1/assets/{asset_id}/check-in", assetsHandler.CheckIn)
+		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post("/api/v1/assets/{asset_id}/condition-reports", assetsHandler.CreateConditionReport)
+		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post("/api/v1/assets/{asset_id}/condition-reports/{report_id}/photos", assetsHandler.AddConditionPhoto)
+		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Put("/api/v1/assets/{asset_id}/financials", assetsHandler.PutFinancials)
+		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post("/api/v1/assets/{asset_id}/share", assetsHandler.Share)
 		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Post("/api/v1/assets/{asset_id}/tags", assetsHandler.AddTag)
 		r.With(middleware.RequireScope("assets:write"), middleware.RejectQueryParams()).Delete("/api/v1/assets/{asset_id}/tags/{tag_id}", assetsHandler.RemoveTag)
 
 		// Locations
 		r.With(middleware.RequireScope("locations:write"), middleware.RejectQueryParams()).Post("/api/v1/locations", locationsHandler.Create)
+		// Declarative whole-tree sync for layouts kept in git.
+		r.With(middleware.RequireScope("locations:write"), middleware.RejectQueryParams("dry_run")).Put("/api/v1/locations:apply", locationsHandler.Apply)
 		r.With(middleware.RequireScope("locations:write"), middleware.RequireMergePatchCT, middleware.RejectQueryParams()).Patch("/api/v1/locations/{location_id}", locationsHandler.Update)
 		r.With(middleware.RequireScope("locations:write"), middleware.RejectQueryParams()).Delete("/api/v1/locations/{location_id}", locationsHandler.Delete)
 		r.With(middleware.RequireScope("locations:write"), middleware.RejectQueryParams()).Post("/api/v1/locations/{location_id}/rename", locationsHandler.Rename)
+		r.With(middleware.RequireScope("locations:write"), middleware.RejectQueryParams()).Put("/api/v1/locations/{location_id}/schedule", locationsHandler.PutSchedule)
 		r.With(middleware.RequireScope("locations:write"), middleware.RejectQueryParams()).Post("/api/v1/locations/{location_id}/tags", locationsHandler.AddTag)
 		r.With(middleware.RequireScope("locations:write"), middleware.RejectQueryParams()).Delete("/api/v1/locations/{location_id}/tags/{tag_id}", locationsHandler.RemoveTag)
 
-		// Inventory (scan writes)
-		r.With(middleware.RequireScope("scans:write"), middleware.RejectQueryParams()).Post("/api/v1/inventory/save", inventoryHandler.Save)
+		// Inventory (scan writes). Session callers must also present a
+		// registered reader's credential, unless the org has turned on session
+		// scans; an API key is its own.
+		scanCredential := middleware.RequireScanCredential(store)
+		r.With(middleware.RequireScope("scans:write"), middleware.RejectQueryParams(), scanCredential).Post("/api/v1/inventory/save", inventoryHandler.Save)
+		r.With(middleware.RequireScope("scans:write"), middleware.RejectQueryParams(), scanCredential).Post("/api/v1/epcis/capture", epcisHandler.Capture)
+
+		// Sensor readings from loggers attached to assets
+		r.With(middleware.RequireScope("sensors:write"), middleware.RejectQueryParams()).Post("/api/v1/sensor-readings", sensorsHandler.Ingest)
+
+		// Edits the handheld app queued while offline
+		r.With(middleware.RequireScope("assets:write"), middleware.RequireScope("locations:write"), middleware.RejectQueryParams()).Post("/api/v1/sync/mutations", offlineSyncHandler.Mutations)
+	})
+
+	// Public asset share links. No auth: the signed, expiring token in the
+	// path is the credential and only reaches a limited view of one asset.
+	r.With(
+		middleware.DefaultRateLimitHeaders(rl),
+		middleware.SentryContext,
+		middleware.RejectQueryParams(),
+	).Get("/api/v1/shared/assets/{token}", assetsHandler.GetShared)
+
+	// Org export downloads. No auth: the short-lived signed token in the
+	// path is the credential, minted for an org admin by GET .../exports/{id}.
+	r.With(
+		middleware.DefaultRateLimitHeaders(rl),
+		middleware.SentryContext,
+		middleware.RejectQueryParams(),
+	).Get("/api/v1/org-exports/{token}", orgsHandler.DownloadExport)
+
+	// Certificate-on-demand check for a TLS-terminating proxy in front of
+	// custom domains. No auth: it only confirms a hostname is verified.
+	r.With(
+		middleware.DefaultRateLimitHeaders(rl),
+		middleware.SentryContext,
+	).Get("/api/v1/custom-domains/tls-check", orgsHandler.CheckCustomDomainTLS)
+
+	// Kiosk displays. No session or API key: the kiosk token, checked by the
+	// handler, is the credential and reads only its own location.
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.DefaultRateLimitHeaders(rl))
+		r.Use(middleware.SentryContext)
+		kioskHandler.RegisterKioskRoutes(r)
 	})
 
 	// TRA-555 / TRA-554: Internal /by-id/ families removed. Public
@@ -306,6 +509,7 @@ func setupRouter(
 		register405Static(r, "/api/v1/users/me/current-org", []string{http.MethodPost})
 		register405Static(r, "/api/v1/reports/asset-locations", []string{http.MethodGet})
 		register405Static(r, "/api/v1/assets/bulk", []string{http.MethodPost})
+		register405Static(r, middleware.AssetValidatePath, []string{http.MethodPost})
 		register405Static(r, "/api/v1/assets/bulk/{jobId}", []string{http.MethodGet})
 	})
 
@@ -352,7 +556,9 @@ func setupRouter(
 		r.With(middleware.DefaultRateLimitHeaders(rl)).MethodFunc(m, "/api/*", apiCatchall)
 	}
 
-	r.Get("/*", func(w http.ResponseWriter, r *http.Request) {
+	// A load on an org subdomain or verified custom domain carries that org,
+	// so the SPA is served in its branding.
+	r.With(hostOrg).Get("/*", func(w http.ResponseWriter, r *http.Request) {
 		frontendHandler.ServeSPA(w, r, "frontend/dist/index.html")
 	})
 
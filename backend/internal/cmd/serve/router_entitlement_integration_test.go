@@ -10,34 +10,11 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
-	"testing/fstest"
-	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/require"
 
-	"github.com/trakrf/platform/backend/internal/alarm"
-	"github.com/trakrf/platform/backend/internal/alarm/shelly"
-	"github.com/trakrf/platform/backend/internal/buildinfo"
-	assetshandler "github.com/trakrf/platform/backend/internal/handlers/assets"
-	authhandler "github.com/trakrf/platform/backend/internal/handlers/auth"
-	frontendhandler "github.com/trakrf/platform/backend/internal/handlers/frontend"
-	healthhandler "github.com/trakrf/platform/backend/internal/handlers/health"
-	inventoryhandler "github.com/trakrf/platform/backend/internal/handlers/inventory"
-	locationshandler "github.com/trakrf/platform/backend/internal/handlers/locations"
-	lookuphandler "github.com/trakrf/platform/backend/internal/handlers/lookup"
-	orgshandler "github.com/trakrf/platform/backend/internal/handlers/orgs"
-	outputdeviceshandler "github.com/trakrf/platform/backend/internal/handlers/outputdevices"
-	readstreamhandler "github.com/trakrf/platform/backend/internal/handlers/readstream"
-	reportshandler "github.com/trakrf/platform/backend/internal/handlers/reports"
-	scandeviceshandler "github.com/trakrf/platform/backend/internal/handlers/scandevices"
-	scanpointshandler "github.com/trakrf/platform/backend/internal/handlers/scanpoints"
-	testhandler "github.com/trakrf/platform/backend/internal/handlers/testhandler"
-	usershandler "github.com/trakrf/platform/backend/internal/handlers/users"
-	authservice "github.com/trakrf/platform/backend/internal/services/auth"
-	orgsservice "github.com/trakrf/platform/backend/internal/services/orgs"
-	readstreamsvc "github.com/trakrf/platform/backend/internal/services/readstream"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/testutil"
 	"github.com/trakrf/platform/backend/internal/util/jwt"
@@ -45,31 +22,11 @@ import (
 
 // setupRealRouter builds the full production router (setupRouter) wired against
 // a real DB-backed *storage.Storage so the entitlement gate runs its real
-// OrgIsEntitled query end-to-end. Mirrors setupTestRouter in serve_test.go but
-// swaps the empty stub store for the live one.
+// OrgIsEntitled query end-to-end. Shares newTestRouter with setupTestRouter in
+// serve_test.go but swaps the empty stub store for the live one.
 func setupRealRouter(t *testing.T, store *storage.Storage) *chi.Mux {
 	t.Helper()
-
-	authSvc := authservice.NewService(nil, store, nil)
-	orgsSvc := orgsservice.NewService(nil, store, nil)
-
-	authHandler := authhandler.NewHandler(authSvc, store)
-	orgsHandler := orgshandler.NewHandler(store, orgsSvc, authSvc)
-	usersHandler := usershandler.NewHandler(store)
-	assetsHandler := assetshandler.NewHandler(store)
-	locationsHandler := locationshandler.NewHandler(store)
-	inventoryHandler := inventoryhandler.NewHandler(store)
-	reportsHandler := reportshandler.NewHandler(store)
-	scanDevicesHandler := scandeviceshandler.NewHandler(store, nil)
-	scanPointsHandler := scanpointshandler.NewHandler(store)
-	outputDevicesHandler := outputdeviceshandler.NewHandler(store, alarm.NewDispatcher(shelly.New(0), nil), 0)
-	lookupHandler := lookuphandler.NewHandler(store)
-	healthHandler := healthhandler.NewHandler(nil, buildinfo.Info{Version: "test"}, time.Now())
-	frontendHandler := frontendhandler.NewHandler(fstest.MapFS{}, "frontend/dist", "")
-	readstreamHandler := readstreamhandler.NewHandler(readstreamsvc.New())
-	testHandler := testhandler.NewHandler(store)
-
-	return setupRouter(authHandler, orgsHandler, usersHandler, assetsHandler, locationsHandler, inventoryHandler, reportsHandler, scanDevicesHandler, scanPointsHandler, outputDevicesHandler, lookupHandler, healthHandler, frontendHandler, readstreamHandler, testHandler, store)
+	return newTestRouter(t, store)
 }
 
 // sessionToken mints a real session JWT (passes middleware.Auth / EitherAuth and
@@ -5,24 +5,54 @@ import (
 	"io/fs"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/getsentry/sentry-go"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/trakrf/platform/backend/internal/alarm"
 	"github.com/trakrf/platform/backend/internal/alarm/shelly"
+	"github.com/trakrf/platform/backend/internal/applinks"
 	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/internal/carriers"
+	"github.com/trakrf/platform/backend/internal/config"
+	"github.com/trakrf/platform/backend/internal/connectors"
+	"github.com/trakrf/platform/backend/internal/custody"
+	"github.com/trakrf/platform/backend/internal/dashboards"
+	"github.com/trakrf/platform/backend/internal/dbbackup"
+	"github.com/trakrf/platform/backend/internal/direction"
+	"github.com/trakrf/platform/backend/internal/documents"
+	"github.com/trakrf/platform/backend/internal/errorreport"
+	"github.com/trakrf/platform/backend/internal/eventexport"
+	"github.com/trakrf/platform/backend/internal/events"
 	"github.com/trakrf/platform/backend/internal/geofence"
+	adminhandler "github.com/trakrf/platform/backend/internal/handlers/admin"
+	approvalshandler "github.com/trakrf/platform/backend/internal/handlers/approvals"
+	assetdisposalshandler "github.com/trakrf/platform/backend/internal/handlers/assetdisposals"
 	assetshandler "github.com/trakrf/platform/backend/internal/handlers/assets"
+	assettransfershandler "github.com/trakrf/platform/backend/internal/handlers/assettransfers"
 	authhandler "github.com/trakrf/platform/backend/internal/handlers/auth"
+	connectorshandler "github.com/trakrf/platform/backend/internal/handlers/connectors"
+	dashboardshandler "github.com/trakrf/platform/backend/internal/handlers/dashboards"
+	deviceshandler "github.com/trakrf/platform/backend/internal/handlers/devices"
+	dockdoorshandler "github.com/trakrf/platform/backend/internal/handlers/dockdoors"
+	documentshandler "github.com/trakrf/platform/backend/internal/handlers/documents"
+	epcishandler "github.com/trakrf/platform/backend/internal/handlers/epcis"
 	frontendhandler "github.com/trakrf/platform/backend/internal/handlers/frontend"
 	healthhandler "github.com/trakrf/platform/backend/internal/handlers/health"
+	identifiershandler "github.com/trakrf/platform/backend/internal/handlers/identifiers"
+	integrationshandler "github.com/trakrf/platform/backend/internal/handlers/integrations"
 	inventoryhandler "github.com/trakrf/platform/backend/internal/handlers/inventory"
+	kioskhandler "github.com/trakrf/platform/backend/internal/handlers/kiosk"
 	kitshandler "github.com/trakrf/platform/backend/internal/handlers/kits"
+	labelprintershandler "github.com/trakrf/platform/backend/internal/handlers/labelprinters"
+	locationpolicieshandler "github.com/trakrf/platform/backend/internal/handlers/locationpolicies"
 	locationshandler "github.com/trakrf/platform/backend/internal/handlers/locations"
 	lookuphandler "github.com/trakrf/platform/backend/internal/handlers/lookup"
 	musteringhandler "github.com/trakrf/platform/backend/internal/handlers/mustering"
+	offlinesynchandler "github.com/trakrf/platform/backend/internal/handlers/offlinesync"
 	orgshandler "github.com/trakrf/platform/backend/internal/handlers/orgs"
 	outputdeviceshandler "github.com/trakrf/platform/backend/internal/handlers/outputdevices"
 	readerconfighandler "github.com/trakrf/platform/backend/internal/handlers/readerconfig"
@@ -30,19 +60,35 @@ import (
 	reportshandler "github.com/trakrf/platform/backend/internal/handlers/reports"
 	scandeviceshandler "github.com/trakrf/platform/backend/internal/handlers/scandevices"
 	scanpointshandler "github.com/trakrf/platform/backend/internal/handlers/scanpoints"
+	sensorshandler "github.com/trakrf/platform/backend/internal/handlers/sensors"
+	stockhandler "github.com/trakrf/platform/backend/internal/handlers/stock"
+	teamshandler "github.com/trakrf/platform/backend/internal/handlers/teams"
 	testhandler "github.com/trakrf/platform/backend/internal/handlers/testhandler"
+	transferordershandler "github.com/trakrf/platform/backend/internal/handlers/transferorders"
 	usershandler "github.com/trakrf/platform/backend/internal/handlers/users"
 	"github.com/trakrf/platform/backend/internal/ingest"
+	"github.com/trakrf/platform/backend/internal/jobs"
+	"github.com/trakrf/platform/backend/internal/labels"
 	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/middleware"
 	"github.com/trakrf/platform/backend/internal/mustering"
+	"github.com/trakrf/platform/backend/internal/objectstore"
+	"github.com/trakrf/platform/backend/internal/orgexport"
+	"github.com/trakrf/platform/backend/internal/positioning"
+	"github.com/trakrf/platform/backend/internal/push"
 	"github.com/trakrf/platform/backend/internal/readercontrol"
+	"github.com/trakrf/platform/backend/internal/retention"
+	"github.com/trakrf/platform/backend/internal/secrets"
 	authservice "github.com/trakrf/platform/backend/internal/services/auth"
+	bulkimportservice "github.com/trakrf/platform/backend/internal/services/bulkimport"
 	"github.com/trakrf/platform/backend/internal/services/email"
 	orgsservice "github.com/trakrf/platform/backend/internal/services/orgs"
 	readstreamsvc "github.com/trakrf/platform/backend/internal/services/readstream"
 	"github.com/trakrf/platform/backend/internal/services/topicroute"
 	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/fieldcrypt"
 	"github.com/trakrf/platform/backend/internal/util/jwt"
+	"github.com/trakrf/platform/backend/internal/webhooks"
 )
 
 // Run starts the long-lived HTTP server process. It blocks until ctx is
@@ -51,38 +97,40 @@ import (
 // frontendFS is the embedded React bundle. The dispatcher owns the go:embed
 // directive because its path (frontend/dist) cannot be reached from this
 // package's subtree.
-func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
+//
+// secretsLoader, when the dispatcher loaded credentials from a secrets
+// manager, is refreshed in the background so rotated credentials reach the
+// running server; nil when SECRETS_PROVIDER is unset.
+func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS, secretsLoader *secrets.Loader) error {
 	startTime := time.Now()
 	log := logger.Get()
 
-	// Fail fast: a deployed environment must never sign tokens with a known-weak
-	// secret (unset → dev fallback, or the "change-me" chart default), which
-	// would let anyone forge a Bearer for any org. Refuse to boot instead.
-	if err := jwt.ValidateSecret(); err != nil {
-		log.Error().Err(err).Msg("Refusing to start: insecure JWT_SECRET")
+	// Fail fast on any bad setting, reporting all of them at once. This includes
+	// the JWT_SECRET check: a deployed environment must never sign tokens with a
+	// known-weak secret, which would let anyone forge a Bearer for any org.
+	cfg, err := config.Load()
+	if err != nil {
+		log.Error().Err(err).Msg("Refusing to start: invalid configuration")
 		return err
 	}
 
-	if dsn := os.Getenv("SENTRY_DSN"); dsn != "" {
+	if cfg.SentryDSN != "" {
 		err := sentry.Init(sentry.ClientOptions{
-			Dsn:           dsn,
-			Environment:   os.Getenv("APP_ENV"),
+			Dsn:           cfg.SentryDSN,
+			Environment:   cfg.Env,
 			Release:       info.Version,
 			EnableTracing: false,
+			BeforeSend:    errorreport.BeforeSend,
 		})
 		if err != nil {
 			log.Warn().Err(err).Msg("Sentry initialization failed")
 		} else {
+			errorreport.SetSink(errorreport.SentrySink{})
 			log.Info().Msg("Sentry initialized")
 		}
 	}
 	defer sentry.Flush(2 * time.Second)
 
-	port := os.Getenv("BACKEND_PORT")
-	if port == "" {
-		port = "8080"
-	}
-
 	store, err := storage.New(ctx)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to initialize storage")
@@ -91,6 +139,163 @@ func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
 	defer store.Close()
 	log.Info().Msg("Storage initialized")
 
+	// Encryption at rest for sensitive columns (see fieldcrypt). Unset
+	// FIELD_ENCRYPTION_KEYS leaves them in plaintext; it must only be set
+	// once migration 000079 has widened api_keys.secret_hash.
+	fieldKeys, err := fieldcrypt.FromEnv(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid field encryption configuration")
+		return err
+	}
+	if fieldKeys != nil {
+		store.EnableFieldEncryption(fieldKeys)
+		log.Info().Str("primary_key", fieldKeys.PrimaryID()).Msg("Field encryption enabled")
+	}
+
+	// Pool stats are read at scrape time; see storage.NewPoolCollector.
+	if pool, ok := store.Pool().(*pgxpool.Pool); ok {
+		prometheus.MustRegister(storage.NewPoolCollector(pool))
+	}
+
+	// Background jobs. Outbox consumers run here: writes queue webhook
+	// deliveries (and export events) in their own transaction, and these jobs
+	// drain them, so nothing is lost if the process dies right after commit.
+	jobRunner := jobs.NewRunner(log)
+	webhookDispatcher := webhooks.NewDispatcher(store, log)
+	jobRunner.Every("webhook_delivery", 2*time.Second, webhookDispatcher.Deliver)
+
+	// Enterprise event export (Kafka REST Proxy / NATS). Disabled when
+	// EVENT_EXPORT_SINK is unset; when set, writes also queue to the outbox
+	// table, so it must be enabled before anything can write.
+	exportCfg, err := eventexport.ConfigFromEnv()
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid event export configuration")
+		return err
+	}
+	if exportCfg.Enabled() {
+		sink, err := eventexport.NewSink(exportCfg)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to build event export sink")
+			return err
+		}
+		defer sink.Close()
+		store.EnableEventOutbox()
+		exporter := eventexport.New(exportCfg, store, sink)
+		jobRunner.Every("event_export", exportCfg.PollInterval, exporter.Drain)
+		jobRunner.Every("event_outbox_prune", time.Hour, exporter.Prune)
+		log.Info().Str("sink", exportCfg.Sink).Str("topic", exportCfg.Topic).Msg("Event export enabled")
+	}
+
+	// Bulk imports interrupted by a replica shutting down are parked in the
+	// database; resume them now and keep polling for ones other replicas park.
+	bulkImportSvc := bulkimportservice.NewService(store)
+	if err := bulkImportSvc.ResumeInterrupted(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to resume interrupted bulk imports")
+	}
+	jobRunner.Every("bulk_import_resume", 30*time.Second, bulkImportSvc.ResumeInterrupted)
+
+	// Per-org retention: prune audit (webhook delivery) and scan history rows
+	// past each org's plan window or its shorter configured window.
+	jobRunner.Every("retention_janitor", time.Hour, retention.NewJanitor(store, log).Run)
+
+	// Org data exports: build queued backup archives and drop expired ones.
+	jobRunner.Every("org_export", 10*time.Second, orgexport.NewJob(store, log).Run)
+
+	// Document vault: raise document.expiring / document.expired alerts as
+	// documents enter their reminder window and pass their expiry date.
+	jobRunner.Every("document_expiry", 15*time.Minute, documents.NewNotifier(store, log).Run)
+
+	// Dashboards: keep cached widget results within the refresh interval
+	// for the queries dashboards are reading.
+	jobRunner.Every("dashboard_metrics", time.Minute, dashboards.NewRefresher(store, log).Run)
+
+	// Label printing: send queued print jobs' ZPL to networked printers.
+	// Printers on private networks need LABEL_PRINTER_NETWORKS.
+	printerPolicy, err := labels.PolicyFromEnv()
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid label printer configuration")
+		return err
+	}
+	jobRunner.Every("label_print", 2*time.Second, labels.NewJob(store, labels.NewTCPSender(10*time.Second, printerPolicy), log).Run)
+
+	// ERP connector sync. Disabled when neither CONNECTOR_VAULT_KEY nor
+	// FIELD_ENCRYPTION_KEYS is set: without a key stored credentials cannot be
+	// opened, so there is nothing to run.
+	connectorVault, err := connectors.VaultFromEnv(fieldKeys)
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid connector vault configuration")
+		return err
+	}
+	if connectorVault != nil {
+		jobRunner.Every("connector_sync", 30*time.Second, connectors.NewSyncer(store, connectorVault, log).Run)
+	}
+
+	// Carrier tracking on transfer orders. Disabled when no carrier API is
+	// configured (CARRIER_DHL_API_KEY unset).
+	carrierRegistry, err := carriers.FromEnv()
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid carrier tracking configuration")
+		return err
+	}
+	if len(carrierRegistry) > 0 {
+		jobRunner.Every("carrier_tracking", time.Minute, carriers.NewPoller(store, carrierRegistry, log).Run)
+	}
+
+	// Mobile push: "asset overdue" notices to registered devices. Disabled
+	// when neither FCM nor APNs credentials are set.
+	pusher, err := push.FromEnv()
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid push notification configuration")
+		return err
+	}
+	if pusher != nil {
+		jobRunner.Every("overdue_push", time.Minute, push.NewNotifier(store, pusher, log).Run)
+	}
+
+	// Secrets manager credentials: re-read them so rotations reach the
+	// running server. JWT_SECRET and PG_URL are read where they are used;
+	// the email client is rebuilt below. A rotated JWT_SECRET must pass the
+	// boot-time weak-secret check, and the one it replaces keeps verifying
+	// tokens for a session lifetime.
+	if secretsLoader != nil {
+		secretsLoader.Validate("JWT_SECRET", jwt.CheckSecret)
+		secretsLoader.OnReplace("JWT_SECRET", func(previous string) {
+			jwt.RetainPreviousSecret(previous, time.Duration(jwt.GetExpirationSeconds())*time.Second)
+		})
+		jobRunner.Every("secrets_refresh", secretsLoader.Interval(), secretsLoader.Refresh)
+	}
+
+	jobRunner.Start(ctx)
+	defer jobRunner.Stop()
+
+	// Backups run from the `backup` CronJob, not here; /readyz reports the
+	// latest run when BACKUP_MAX_AGE is set.
+	backupMaxAge, err := dbbackup.MaxAgeFromEnv()
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid backup configuration")
+		return err
+	}
+
+	// Chain-of-custody documents are served unsigned when
+	// CUSTODY_SIGNING_KEY is unset.
+	custodySigner, err := custody.SignerFromEnv()
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid custody signing configuration")
+		return err
+	}
+
+	// Avatar and condition photo uploads go to an S3-compatible bucket.
+	// Disabled (the upload endpoints answer 503) when OBJECT_STORE_BUCKET is
+	// unset.
+	objectStore, err := objectstore.FromEnv()
+	if err != nil {
+		log.Error().Err(err).Msg("Invalid object store configuration")
+		return err
+	}
+	if objectStore == nil {
+		log.Info().Msg("Object store disabled (OBJECT_STORE_BUCKET unset)")
+	}
+
 	// TRA-900: in-backend MQTT subscriber (replaces the RC ingester + the
 	// process_tag_scans trigger). Disabled when MQTT_URL is unset, so local
 	// dev / tests / pre-cutover prod stay inert.
@@ -118,6 +323,21 @@ func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
 		log.Warn().Err(err).Msg("initial topic registry load failed; ticker will retry")
 	}
 
+	// Cross-replica change feed: storage writes pg_notify on commit and every
+	// replica LISTENs, so a scan device edited through one replica re-routes
+	// MQTT topics on all of them without waiting for the reconcile ticker.
+	// Resync (after a listener reconnect) reconciles too, covering the gap.
+	if pool, ok := store.Pool().(*pgxpool.Pool); ok {
+		eventBus := events.NewBus(events.ConnectFromPool(pool), log)
+		eventBus.Subscribe(func(ctx context.Context, _ events.Event) {
+			if err := topicRegistry.Reconcile(ctx); err != nil {
+				log.Warn().Err(err).Msg("topic registry reconcile on change event failed")
+			}
+		}, events.ScanDeviceCreated, events.ScanDeviceUpdated, events.ScanDeviceDeleted)
+		eventBus.Start(ctx)
+		defer eventBus.Stop()
+	}
+
 	// TRA-978: the mustering engine + SSE broadcaster are always constructed so
 	// the mustering REST/SSE/simulate surface serves regardless of whether MQTT
 	// ingestion is on (the simulator drives the same Evaluate path directly). The
@@ -134,6 +354,8 @@ func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
 	// TRA-993: cloud reader-control RPC client. Only constructed when the broker
 	// is configured; nil otherwise so the reader-config endpoints report 503.
 	var readerClient *readercontrol.Client
+	// Kept for the /readyz MQTT check; nil when ingestion is disabled.
+	var subscriber *ingest.Subscriber
 	if mqttCfg.Enabled() {
 		// TRA-906: dedicated publish client on the same broker (reuses MQTT_URL).
 		alarmPublisher, stopPublisher := alarm.NewMQTTPublisher(mqttCfg, log)
@@ -156,10 +378,14 @@ func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
 		defer geofenceEngine.Stop()
 
 		// TRA-978: prepend geofence to the fan-out so the subscriber drives both
-		// geofence and mustering off the same membership-passing reads.
-		musterEvaluators = ingest.MultiEvaluator{geofenceEngine, musterEngine}
+		// geofence and mustering off the same membership-passing reads. The
+		// positioning engine turns BLE reads into zone changes for orgs that
+		// enable it; it is a no-op for everyone else. The direction engine
+		// records dock door crossings for orgs with dock doors.
+		musterEvaluators = ingest.MultiEvaluator{geofenceEngine, musterEngine,
+			positioning.NewEngine(store, log), direction.NewEngine(store, log)}
 
-		subscriber := ingest.NewSubscriber(mqttCfg, store, topicRegistry, musterEvaluators, readBroadcaster, log)
+		subscriber = ingest.NewSubscriber(mqttCfg, store, topicRegistry, musterEvaluators, readBroadcaster, log)
 		if err := subscriber.Start(); err != nil {
 			log.Error().Err(err).Msg("Failed to start MQTT subscriber")
 			return err
@@ -193,17 +419,25 @@ func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
 	}
 
 	emailClient := email.NewClient()
+	if secretsLoader != nil {
+		secretsLoader.OnChange(func(names []string) {
+			log.Info().Strs("variables", names).Msg("Credentials refreshed from secrets manager")
+			if cfg, err := email.MailerConfigFromEnv(); err == nil {
+				emailClient.Reconfigure(cfg)
+			}
+		})
+	}
 	authSvc := authservice.NewService(store.Pool().(*pgxpool.Pool), store, emailClient)
-	orgsSvc := orgsservice.NewService(store.Pool().(*pgxpool.Pool), store, emailClient)
+	orgsSvc := orgsservice.NewService(store, emailClient)
 	log.Info().Msg("Services initialized")
 
 	authHandler := authhandler.NewHandler(authSvc, store)
 	orgsHandler := orgshandler.NewHandler(store, orgsSvc, authSvc)
-	usersHandler := usershandler.NewHandler(store)
-	assetsHandler := assetshandler.NewHandler(store)
+	usersHandler := usershandler.NewHandler(store, objectStore)
+	assetsHandler := assetshandler.NewHandlerWithBulkImport(store, bulkImportSvc, emailClient, objectStore)
 	locationsHandler := locationshandler.NewHandler(store)
 	inventoryHandler := inventoryhandler.NewHandler(store)
-	reportsHandler := reportshandler.NewHandler(store)
+	reportsHandler := reportshandler.NewHandlerWithSigner(store, custodySigner)
 	scanDevicesHandler := scandeviceshandler.NewHandler(store, topicRegistry)
 	scanPointsHandler := scanpointshandler.NewHandler(store)
 	// 2s test-fire pulse: long enough for an operator to see the strobe, short
@@ -219,34 +453,62 @@ func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
 	readerConfigHandler := readerconfighandler.NewHandler(store, readerRPC)
 	lookupHandler := lookuphandler.NewHandler(store)
 	healthHandler := healthhandler.NewHandler(store.Pool().(*pgxpool.Pool), info, startTime)
+	addReadinessChecks(healthHandler, store, emailClient, subscriber, backupMaxAge)
 	// TRA-924: Live Reads is now served by the org-enforced SSE endpoint, so the
 	// browser no longer receives broker URL/creds — the readerFeed runtime config
 	// is gone.
-	frontendHandler := frontendhandler.NewHandler(frontendFS, "frontend/dist", os.Getenv("ENVIRONMENT_LABEL"))
+	// White-label orgs get their theme injected into index.html on their
+	// subdomain, /o/<identifier> path and link domains.
+	frontendHandler := frontendhandler.NewHandler(frontendFS, "frontend/dist", os.Getenv("ENVIRONMENT_LABEL")).
+		WithThemes(store, applinks.FromEnv().BaseURL)
 	readstreamHandler := readstreamhandler.NewHandler(readBroadcaster)
 	// TRA-978: mustering handler shares the engine, broadcaster, evaluator fan-out
 	// (for simulate), and the Live Reads feed (so simulate's RSSI reaches Locate).
 	musteringHandler := musteringhandler.NewHandler(musterEngine, musterBroadcaster, store, musterEvaluators, readBroadcaster)
 	// TRA-1032: internal kit commission/verify/lookup endpoints.
 	kitsHandler := kitshandler.NewHandler(store)
+	teamsHandler := teamshandler.NewHandler(store)
+	locationPoliciesHandler := locationpolicieshandler.NewHandler(store)
+	integrationsHandler := integrationshandler.NewHandler(store)
+	connectorsHandler := connectorshandler.NewHandler(store, connectorVault)
+	epcisHandler := epcishandler.NewHandler(store)
+	sensorsHandler := sensorshandler.NewHandler(store)
+	offlineSyncHandler := offlinesynchandler.NewHandler(store)
+	devicesHandler := deviceshandler.NewHandler(store)
+	assetTransfersHandler := assettransfershandler.NewHandler(store)
+	kioskHandler := kioskhandler.NewHandler(store)
+	identifiersHandler := identifiershandler.NewHandler(store)
+	stockHandler := stockhandler.NewHandler(store)
+	assetDisposalsHandler := assetdisposalshandler.NewHandler(store)
+	labelPrintersHandler := labelprintershandler.NewHandler(store, printerPolicy)
+	dockDoorsHandler := dockdoorshandler.NewHandler(store)
+	transferOrdersHandler := transferordershandler.NewHandler(store)
+	documentsHandler := documentshandler.NewHandler(store, objectStore)
+	dashboardsHandler := dashboardshandler.NewHandler(store)
+	approvalsHandler := approvalshandler.NewHandler(store)
 	testHandler := testhandler.NewHandler(store)
+	adminHandler := adminhandler.NewHandler(cfg, store)
 	log.Info().Msg("Handlers initialized")
 
-	r := setupRouter(authHandler, orgsHandler, usersHandler, assetsHandler, locationsHandler, inventoryHandler, reportsHandler, scanDevicesHandler, scanPointsHandler, outputDevicesHandler, readerConfigHandler, lookupHandler, healthHandler, frontendHandler, readstreamHandler, musteringHandler, kitsHandler, testHandler, store)
+	r := setupRouter(authHandler, orgsHandler, usersHandler, assetsHandler, locationsHandler, inventoryHandler, reportsHandler, scanDevicesHandler, scanPointsHandler, outputDevicesHandler, readerConfigHandler, lookupHandler, healthHandler, frontendHandler, readstreamHandler, musteringHandler, kitsHandler, teamsHandler, locationPoliciesHandler, integrationsHandler, connectorsHandler, epcisHandler, sensorsHandler, offlineSyncHandler, devicesHandler, assetTransfersHandler, kioskHandler, identifiersHandler, stockHandler, assetDisposalsHandler, labelPrintersHandler, dockDoorsHandler, transferOrdersHandler, documentsHandler, dashboardsHandler, approvalsHandler, adminHandler, testHandler, store)
 	log.Info().Msg("Routes registered")
 
 	server := &http.Server{
-		Addr:         ":" + port,
-		Handler:      r,
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		Addr: ":" + strconv.Itoa(cfg.Port),
+		// ReadTimeout/WriteTimeout are the tight defaults; Timeouts raises
+		// them on the long-running routes. SecurityHeaders sits outermost
+		// so even a timed-out or rejected response carries them.
+		Handler: middleware.SecurityHeaders(cfg.Security)(
+			middleware.Timeouts(cfg.Server.WriteTimeout, cfg.Server.LongRequestTimeout)(r)),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
 	}
 
 	serverErr := make(chan error, 1)
 	go func() {
 		log.Info().
-			Str("port", port).
+			Int("port", cfg.Port).
 			Str("version", info.Version).
 			Str("commit", info.Commit).
 			Str("tag", info.Tag).
@@ -267,7 +529,7 @@ func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
 	}
 
 	log.Info().Msg("Shutting down gracefully...")
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
@@ -277,6 +539,13 @@ func Run(ctx context.Context, info buildinfo.Info, frontendFS fs.FS) error {
 
 	<-serverErr
 
+	// With no more uploads arriving, park in-flight imports for resume.
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer drainCancel()
+	if err := bulkImportSvc.Shutdown(drainCtx); err != nil {
+		log.Error().Err(err).Msg("Bulk imports did not drain")
+	}
+
 	log.Info().Msg("Server stopped")
 	return nil
 }
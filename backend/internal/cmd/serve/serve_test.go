@@ -1,6 +1,9 @@
 package serve
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -12,15 +15,33 @@ import (
 	"github.com/trakrf/platform/backend/internal/alarm"
 	"github.com/trakrf/platform/backend/internal/alarm/shelly"
 	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/internal/config"
+	"github.com/trakrf/platform/backend/internal/connectors"
+	adminhandler "github.com/trakrf/platform/backend/internal/handlers/admin"
+	approvalshandler "github.com/trakrf/platform/backend/internal/handlers/approvals"
+	assetdisposalshandler "github.com/trakrf/platform/backend/internal/handlers/assetdisposals"
 	assetshandler "github.com/trakrf/platform/backend/internal/handlers/assets"
+	assettransfershandler "github.com/trakrf/platform/backend/internal/handlers/assettransfers"
 	authhandler "github.com/trakrf/platform/backend/internal/handlers/auth"
+	connectorshandler "github.com/trakrf/platform/backend/internal/handlers/connectors"
+	dashboardshandler "github.com/trakrf/platform/backend/internal/handlers/dashboards"
+	deviceshandler "github.com/trakrf/platform/backend/internal/handlers/devices"
+	dockdoorshandler "github.com/trakrf/platform/backend/internal/handlers/dockdoors"
+	documentshandler "github.com/trakrf/platform/backend/internal/handlers/documents"
+	epcishandler "github.com/trakrf/platform/backend/internal/handlers/epcis"
 	frontendhandler "github.com/trakrf/platform/backend/internal/handlers/frontend"
 	healthhandler "github.com/trakrf/platform/backend/internal/handlers/health"
+	identifiershandler "github.com/trakrf/platform/backend/internal/handlers/identifiers"
+	integrationshandler "github.com/trakrf/platform/backend/internal/handlers/integrations"
 	inventoryhandler "github.com/trakrf/platform/backend/internal/handlers/inventory"
+	kioskhandler "github.com/trakrf/platform/backend/internal/handlers/kiosk"
 	kitshandler "github.com/trakrf/platform/backend/internal/handlers/kits"
+	labelprintershandler "github.com/trakrf/platform/backend/internal/handlers/labelprinters"
+	locationpolicieshandler "github.com/trakrf/platform/backend/internal/handlers/locationpolicies"
 	locationshandler "github.com/trakrf/platform/backend/internal/handlers/locations"
 	lookuphandler "github.com/trakrf/platform/backend/internal/handlers/lookup"
 	musteringhandler "github.com/trakrf/platform/backend/internal/handlers/mustering"
+	offlinesynchandler "github.com/trakrf/platform/backend/internal/handlers/offlinesync"
 	orgshandler "github.com/trakrf/platform/backend/internal/handlers/orgs"
 	outputdeviceshandler "github.com/trakrf/platform/backend/internal/handlers/outputdevices"
 	readerconfighandler "github.com/trakrf/platform/backend/internal/handlers/readerconfig"
@@ -28,12 +49,20 @@ import (
 	reportshandler "github.com/trakrf/platform/backend/internal/handlers/reports"
 	scandeviceshandler "github.com/trakrf/platform/backend/internal/handlers/scandevices"
 	scanpointshandler "github.com/trakrf/platform/backend/internal/handlers/scanpoints"
+	sensorshandler "github.com/trakrf/platform/backend/internal/handlers/sensors"
+	stockhandler "github.com/trakrf/platform/backend/internal/handlers/stock"
+	teamshandler "github.com/trakrf/platform/backend/internal/handlers/teams"
 	testhandler "github.com/trakrf/platform/backend/internal/handlers/testhandler"
+	transferordershandler "github.com/trakrf/platform/backend/internal/handlers/transferorders"
 	usershandler "github.com/trakrf/platform/backend/internal/handlers/users"
 	"github.com/trakrf/platform/backend/internal/ingest"
+	"github.com/trakrf/platform/backend/internal/labels"
 	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/middleware"
 	"github.com/trakrf/platform/backend/internal/mustering"
+	"github.com/trakrf/platform/backend/internal/readerrpc"
 	authservice "github.com/trakrf/platform/backend/internal/services/auth"
+	bulkimportservice "github.com/trakrf/platform/backend/internal/services/bulkimport"
 	orgsservice "github.com/trakrf/platform/backend/internal/services/orgs"
 	readstreamsvc "github.com/trakrf/platform/backend/internal/services/readstream"
 	"github.com/trakrf/platform/backend/internal/storage"
@@ -41,22 +70,63 @@ import (
 
 func setupTestRouter(t *testing.T) *chi.Mux {
 	t.Helper()
+	return newTestRouter(t, &storage.Storage{})
+}
+
+// testObjects stands in for the S3 bucket: uploads succeed and go nowhere.
+type testObjects struct{}
+
+func (testObjects) Put(context.Context, string, string, []byte) error { return nil }
+func (testObjects) Delete(context.Context, string) error              { return nil }
+func (testObjects) URL(key string) string                             { return "https://objects.test/" + key }
+func (testObjects) KeyForURL(u string) (string, bool) {
+	return strings.CutPrefix(u, "https://objects.test/")
+}
+
+// errNoReaders is what every reader RPC fails with under test.
+var errNoReaders = errors.New("no reader broker under test")
+
+// testReaders is a reader RPC client with no readers behind it.
+type testReaders struct{}
+
+func (testReaders) GetCapabilities(context.Context, string) (readerrpc.Capabilities, error) {
+	return readerrpc.Capabilities{}, errNoReaders
+}
+
+func (testReaders) GetOperProfile(context.Context, string, bool) (readerrpc.ReaderConfig, error) {
+	return readerrpc.ReaderConfig{}, errNoReaders
+}
+
+func (testReaders) SetOperProfile(context.Context, string, readerrpc.ReaderConfig, bool) (readerrpc.SetConfigResult, error) {
+	return readerrpc.SetConfigResult{}, errNoReaders
+}
+
+// newTestRouter builds the production router (setupRouter) over store, with
+// every handler wired the way serve does. External services are stand-ins:
+// an object store and connector vault that work, and a reader broker with
+// no readers, so every route reaches its handler's own checks.
+func newTestRouter(t *testing.T, store *storage.Storage) *chi.Mux {
+	t.Helper()
+
+	vault, err := connectors.NewVault(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("connector vault: %v", err)
+	}
 
-	store := &storage.Storage{}
 	authSvc := authservice.NewService(nil, store, nil)
-	orgsSvc := orgsservice.NewService(nil, store, nil)
+	orgsSvc := orgsservice.NewService(store, nil)
 
 	authHandler := authhandler.NewHandler(authSvc, store)
 	orgsHandler := orgshandler.NewHandler(store, orgsSvc, authSvc)
-	usersHandler := usershandler.NewHandler(store)
-	assetsHandler := assetshandler.NewHandler(store)
+	usersHandler := usershandler.NewHandler(store, testObjects{})
+	assetsHandler := assetshandler.NewHandlerWithBulkImport(store, bulkimportservice.NewService(store), nil, testObjects{})
 	locationsHandler := locationshandler.NewHandler(store)
 	inventoryHandler := inventoryhandler.NewHandler(store)
 	reportsHandler := reportshandler.NewHandler(store)
 	scanDevicesHandler := scandeviceshandler.NewHandler(store, nil)
 	scanPointsHandler := scanpointshandler.NewHandler(store)
 	outputDevicesHandler := outputdeviceshandler.NewHandler(store, alarm.NewDispatcher(shelly.New(0), nil), 0)
-	readerConfigHandler := readerconfighandler.NewHandler(store, nil)
+	readerConfigHandler := readerconfighandler.NewHandler(store, testReaders{})
 	lookupHandler := lookuphandler.NewHandler(store)
 	healthHandler := healthhandler.NewHandler(nil, buildinfo.Info{Version: "test"}, time.Now())
 	frontendHandler := frontendhandler.NewHandler(fstest.MapFS{}, "frontend/dist", "")
@@ -65,9 +135,29 @@ func setupTestRouter(t *testing.T) *chi.Mux {
 	musterEngine := mustering.NewEngine(store, musterBC, logger.Get())
 	musteringHandler := musteringhandler.NewHandler(musterEngine, musterBC, store, ingest.MultiEvaluator{musterEngine}, nil)
 	kitsHandler := kitshandler.NewHandler(store)
+	teamsHandler := teamshandler.NewHandler(store)
+	locationPoliciesHandler := locationpolicieshandler.NewHandler(store)
+	integrationsHandler := integrationshandler.NewHandler(store)
+	connectorsHandler := connectorshandler.NewHandler(store, vault)
+	epcisHandler := epcishandler.NewHandler(store)
+	sensorsHandler := sensorshandler.NewHandler(store)
+	offlineSyncHandler := offlinesynchandler.NewHandler(store)
+	devicesHandler := deviceshandler.NewHandler(store)
+	assetTransfersHandler := assettransfershandler.NewHandler(store)
+	kioskHandler := kioskhandler.NewHandler(store)
+	identifiersHandler := identifiershandler.NewHandler(store)
+	stockHandler := stockhandler.NewHandler(store)
+	assetDisposalsHandler := assetdisposalshandler.NewHandler(store)
+	labelPrintersHandler := labelprintershandler.NewHandler(store, labels.DefaultPolicy())
+	dockDoorsHandler := dockdoorshandler.NewHandler(store)
+	transferOrdersHandler := transferordershandler.NewHandler(store)
+	documentsHandler := documentshandler.NewHandler(store, testObjects{})
+	dashboardsHandler := dashboardshandler.NewHandler(store)
+	approvalsHandler := approvalshandler.NewHandler(store)
+	adminHandler := adminhandler.NewHandler(config.Config{}, store)
 	testHandler := testhandler.NewHandler(store)
 
-	return setupRouter(authHandler, orgsHandler, usersHandler, assetsHandler, locationsHandler, inventoryHandler, reportsHandler, scanDevicesHandler, scanPointsHandler, outputDevicesHandler, readerConfigHandler, lookupHandler, healthHandler, frontendHandler, readstreamHandler, musteringHandler, kitsHandler, testHandler, store)
+	return setupRouter(authHandler, orgsHandler, usersHandler, assetsHandler, locationsHandler, inventoryHandler, reportsHandler, scanDevicesHandler, scanPointsHandler, outputDevicesHandler, readerConfigHandler, lookupHandler, healthHandler, frontendHandler, readstreamHandler, musteringHandler, kitsHandler, teamsHandler, locationPoliciesHandler, integrationsHandler, connectorsHandler, epcisHandler, sensorsHandler, offlineSyncHandler, devicesHandler, assetTransfersHandler, kioskHandler, identifiersHandler, stockHandler, assetDisposalsHandler, labelPrintersHandler, dockDoorsHandler, transferOrdersHandler, documentsHandler, dashboardsHandler, approvalsHandler, adminHandler, testHandler, store)
 }
 
 func TestRouterSetup(t *testing.T) {
@@ -92,6 +182,7 @@ func TestRouterRegistration(t *testing.T) {
 		path   string
 	}{
 		{"GET", "/healthz"},
+		{"GET", "/livez"},
 		{"GET", "/readyz"},
 		{"GET", "/health"},
 		{"GET", "/metrics"},
@@ -100,6 +191,9 @@ func TestRouterRegistration(t *testing.T) {
 		{"POST", "/api/v1/auth/forgot-password"},
 		{"POST", "/api/v1/auth/reset-password"},
 		{"POST", "/api/v1/auth/accept-invite"},
+		{"POST", "/api/v1/auth/confirm-email-change"},
+		{"POST", "/api/v1/me/password"},
+		{"POST", "/api/v1/me/email"},
 		{"GET", "/api/v1/orgs"},
 		{"POST", "/api/v1/orgs"},
 		{"GET", "/api/v1/orgs/1/members"},
@@ -109,9 +203,44 @@ func TestRouterRegistration(t *testing.T) {
 		{"POST", "/api/v1/orgs/1/invitations"},
 		{"DELETE", "/api/v1/orgs/1/invitations/5"},
 		{"POST", "/api/v1/orgs/1/invitations/5/resend"},
+		{"GET", "/api/v1/orgs/1/link-domains"},
+		{"PATCH", "/api/v1/orgs/1/link-domains"},
 		{"GET", "/api/v1/users/me"},
 		{"POST", "/api/v1/users/me/current-org"},
+		{"GET", "/api/v1/me/preferences"},
+		{"PUT", "/api/v1/me/preferences"},
+		{"PATCH", "/api/v1/me/profile"},
+		{"PUT", "/api/v1/me/avatar"},
+		{"DELETE", "/api/v1/me/avatar"},
 		{"GET", "/api/v1/users"},
+		{"GET", "/api/v1/admin/config"},
+		{"POST", "/api/v1/scan-devices/3/credential"},
+		{"DELETE", "/api/v1/scan-devices/3/credential"},
+		{"GET", "/api/v1/identifiers/4/history"},
+		{"GET", "/api/v1/reports/expiring"},
+		{"GET", "/api/v1/assets/5/consumable"},
+		{"PUT", "/api/v1/assets/5/consumable"},
+		{"DELETE", "/api/v1/assets/5/consumable"},
+		{"GET", "/api/v1/assets/5/stock"},
+		{"GET", "/api/v1/assets/5/stock/adjustments"},
+		{"POST", "/api/v1/assets/5/stock/adjustments"},
+		{"GET", "/api/v1/stock-alerts"},
+		{"GET", "/api/v1/asset-disposals"},
+		{"POST", "/api/v1/asset-disposals"},
+		{"GET", "/api/v1/asset-disposals/6"},
+		{"POST", "/api/v1/asset-disposals/6/approve"},
+		{"POST", "/api/v1/asset-disposals/6/reject"},
+		{"POST", "/api/v1/asset-disposals/6/cancel"},
+		{"POST", "/api/v1/asset-disposals/6/execute"},
+		{"POST", "/api/v1/assets/bulk-delete"},
+		{"GET", "/api/v1/approval-rules"},
+		{"PUT", "/api/v1/approval-rules/org.settings"},
+		{"DELETE", "/api/v1/approval-rules/org.settings"},
+		{"GET", "/api/v1/approvals"},
+		{"GET", "/api/v1/approvals/6"},
+		{"POST", "/api/v1/approvals/6/approve"},
+		{"POST", "/api/v1/approvals/6/reject"},
+		{"POST", "/api/v1/approvals/6/cancel"},
 		{"GET", "/api/v1/reads/stream"},
 		{"GET", "/api/v1/mustering/stream"},
 		{"GET", "/api/v1/mustering/status"},
@@ -489,3 +618,35 @@ func TestRouter_UnknownAPIPath_Returns404(t *testing.T) {
 		})
 	}
 }
+
+// Security headers wrap the router in serve.go; API and SPA responses both
+// carry them, with the policy matching the kind of response.
+func TestSecurityHeaders_APIAndSPARoutes(t *testing.T) {
+	h := middleware.SecurityHeaders(middleware.SecurityHeadersConfig{
+		HSTSMaxAge: 31536000,
+		SPAPolicy:  middleware.DefaultSPAPolicy,
+	})(setupTestRouter(t))
+
+	for _, tc := range []struct {
+		path string
+		csp  string
+	}{
+		{"/api/v1/orgs", "default-src 'none'; frame-ancestors 'none'"},
+		{"/api/v1/no-such-route", "default-src 'none'; frame-ancestors 'none'"},
+		{"/dashboard", middleware.DefaultSPAPolicy},
+	} {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		for name, want := range map[string]string{
+			"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+			"X-Content-Type-Options":    "nosniff",
+			"Referrer-Policy":           "strict-origin-when-cross-origin",
+			"X-Frame-Options":           "DENY",
+			"Content-Security-Policy":   tc.csp,
+		} {
+			if got := rec.Header().Get(name); got != want {
+				t.Errorf("GET %s: %s = %q, want %q", tc.path, name, got, want)
+			}
+		}
+	}
+}
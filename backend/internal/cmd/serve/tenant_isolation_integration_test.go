@@ -0,0 +1,596 @@
+//go:build integration
+
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/orgexport"
+	"github.com/trakrf/platform/backend/internal/services/email"
+	"github.com/trakrf/platform/backend/internal/testutil"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// tenantMarker is embedded in every name, key and tag value org B's
+// fixtures carry, so a response that leaks any of them is caught by a
+// substring match regardless of its shape.
+const tenantMarker = "tntb"
+
+// isolationOverwrite is the name, title or note org A's writes carry, so a
+// write that lands on an org B fixture shows up in B's readback.
+const isolationOverwrite = "tnta overwrite"
+
+// isolationRuleOperation is the approval rule org B configures; it gates
+// only bulk deletes, so the sweep's other writes are not parked.
+const isolationRuleOperation = "assets.bulk_delete"
+
+// isolationExempt lists /api/v1 route prefixes the verifier does not drive:
+// pre-auth and token-bearer surfaces that have no caller org, the
+// superadmin operator surface that is cross-org by design, and SSE streams
+// that hold the connection open.
+var isolationExempt = []string{
+	"/api/v1/auth/",
+	"/api/v1/admin/",
+	"/api/v1/oauth/",
+	"/api/v1/shared/",
+	"/api/v1/org-exports/",
+	"/api/v1/openapi.",
+	"/api/v1/reads/stream",
+	"/api/v1/mustering/stream",
+}
+
+// isolationOrgImplicit are routes whose path parameter is not an id: they
+// act on the caller's own org, so org A's request succeeds against A and
+// the readback checks B's copy is untouched.
+var isolationOrgImplicit = map[string]bool{
+	"PUT /api/v1/approval-rules/{operation}": true,
+}
+
+// isolationMethodNotAllowed are methods the router refuses outright on a
+// parameterized path (register405Static), before any org lookup.
+var isolationMethodNotAllowed = map[string]bool{
+	"POST /api/v1/assets/bulk/{jobId}":   true,
+	"PUT /api/v1/assets/bulk/{jobId}":    true,
+	"PATCH /api/v1/assets/bulk/{jobId}":  true,
+	"DELETE /api/v1/assets/bulk/{jobId}": true,
+}
+
+// rawBody is a request body sent as is under its own Content-Type, for the
+// upload routes that do not take JSON.
+type rawBody struct {
+	contentType string
+	data        []byte
+}
+
+// isolationPNG is the smallest body http.DetectContentType calls image/png.
+var isolationPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00")
+
+// isolationBodies returns a valid request body for every parameterized
+// write route, built from org A's own fixtures, so a route that reaches its
+// handler fails on org B's resource rather than on validation. A nil entry
+// means the route takes no body.
+func isolationBodies(a *tenant) map[string]any {
+	note := map[string]any{"note": isolationOverwrite}
+	name := map[string]any{"name": isolationOverwrite}
+	return map[string]any{
+		"PUT /api/v1/approval-rules/{operation}": map[string]any{"threshold": 50},
+
+		"POST /api/v1/approvals/{approval_id}/approve": note,
+		"POST /api/v1/approvals/{approval_id}/cancel":  note,
+		"POST /api/v1/approvals/{approval_id}/reject":  note,
+
+		"POST /api/v1/asset-disposals/{disposal_id}/approve": note,
+		"POST /api/v1/asset-disposals/{disposal_id}/cancel":  note,
+		"POST /api/v1/asset-disposals/{disposal_id}/execute": nil,
+		"POST /api/v1/asset-disposals/{disposal_id}/reject":  note,
+
+		"POST /api/v1/asset-transfers/{transfer_id}/approve": nil,
+		"POST /api/v1/asset-transfers/{transfer_id}/cancel":  nil,
+		"POST /api/v1/asset-transfers/{transfer_id}/execute": nil,
+		"POST /api/v1/asset-transfers/{transfer_id}/reject":  nil,
+
+		"POST /api/v1/assets/bulk/{jobId}":  nil,
+		"PUT /api/v1/assets/bulk/{jobId}":   nil,
+		"PATCH /api/v1/assets/bulk/{jobId}": nil,
+
+		"PATCH /api/v1/assets/{asset_id}":                                     name,
+		"POST /api/v1/assets/{asset_id}/check-in":                             note,
+		"POST /api/v1/assets/{asset_id}/condition-reports":                    map[string]any{"rating": 1, "notes": isolationOverwrite},
+		"POST /api/v1/assets/{asset_id}/condition-reports/{report_id}/photos": rawBody{"image/png", isolationPNG},
+		"PUT /api/v1/assets/{asset_id}/consumable":                            map[string]any{"unit": "box", "min_level": 1},
+		"PUT /api/v1/assets/{asset_id}/financials":                            map[string]any{"currency": "USD", "purchase_cost": json.Number("100.00")},
+		"POST /api/v1/assets/{asset_id}/rename":                               map[string]any{"external_key": "TNTA-RENAMED"},
+		"POST /api/v1/assets/{asset_id}/share":                                map[string]any{"expires_in_days": 1},
+		"POST /api/v1/assets/{asset_id}/stock/adjustments":                    map[string]any{"location_identifier": "TNTA-WH", "delta": 1},
+		"POST /api/v1/assets/{asset_id}/tags":                                 map[string]any{"tag_type": "barcode", "value": "TNTA-OVERWRITE-TAG"},
+		"POST /api/v1/assets/{asset_id}/transfer":                             map[string]any{"owner_user_id": a.user, "note": isolationOverwrite},
+
+		"PATCH /api/v1/connectors/{connector_id}":     name,
+		"POST /api/v1/connectors/{connector_id}/sync": nil,
+		"PATCH /api/v1/dashboards/{dashboard_id}":     name,
+		"PATCH /api/v1/dock-doors/{dock_door_id}":     name,
+		"PATCH /api/v1/documents/{document_id}":       map[string]any{"title": isolationOverwrite},
+		"PUT /api/v1/documents/{document_id}/file":    rawBody{"application/pdf", []byte("%PDF-1.4\n%tnta\n")},
+		"PATCH /api/v1/label-printers/{printer_id}":   name,
+
+		"PATCH /api/v1/locations/{location_id}":        name,
+		"POST /api/v1/locations/{location_id}/rename":  map[string]any{"external_key": "TNTA-RENAMED-WH"},
+		"PUT /api/v1/locations/{location_id}/schedule": map[string]any{"time_zone": "UTC", "shifts": []any{map[string]any{"name": "Day", "start": "06:00", "end": "14:00"}}},
+		"POST /api/v1/locations/{location_id}/tags":    map[string]any{"tag_type": "barcode", "value": "TNTA-OVERWRITE-LOC-TAG"},
+
+		"POST /api/v1/mustering/events/{id}/all-clear":          nil,
+		"POST /api/v1/mustering/events/{id}/cancel":             nil,
+		"PATCH /api/v1/mustering/events/{id}/entries/{entryId}": map[string]any{"action": "mark_safe", "note": isolationOverwrite},
+		"POST /api/v1/mustering/events/{id}/unlock":             nil,
+
+		"PUT /api/v1/orgs/{id}":                                            name,
+		"DELETE /api/v1/orgs/{id}":                                         map[string]any{"confirm_name": "TNTA Org"},
+		"POST /api/v1/orgs/{id}/api-keys":                                  map[string]any{"name": isolationOverwrite, "scopes": []string{"assets:read"}},
+		"PATCH /api/v1/orgs/{id}/app-theme":                                map[string]any{"primary_color": "#0f766e"},
+		"PATCH /api/v1/orgs/{id}/currency-settings":                        map[string]any{"currency": "EUR", "locale": "de-DE"},
+		"POST /api/v1/orgs/{id}/custom-domains":                            map[string]any{"hostname": "track.tnta.example"},
+		"POST /api/v1/orgs/{id}/custom-domains/{domain_id}/verify":         nil,
+		"PATCH /api/v1/orgs/{id}/email-branding":                           map[string]any{"primary_color": "#0f766e"},
+		"PATCH /api/v1/orgs/{id}/entitlement":                              map[string]any{"subscription_enabled": false},
+		"POST /api/v1/orgs/{id}/export":                                    map[string]any{"format": "json"},
+		"PATCH /api/v1/orgs/{id}/geofence-defaults":                        map[string]any{"rssi_threshold": -70},
+		"PATCH /api/v1/orgs/{id}/identifier-settings":                      map[string]any{"deleted_key_policy": "block"},
+		"PATCH /api/v1/orgs/{id}/identifier-template":                      map[string]any{"prefix": "TNTA-", "padding": 4, "next_value": 1},
+		"POST /api/v1/orgs/{id}/import":                                    map[string]any{"format_version": orgexport.FormatVersion, "org_id": a.org, "tables": map[string]any{}},
+		"POST /api/v1/orgs/{id}/invitations":                               map[string]any{"email": "tnta-invitee@example.com", "role": "viewer"},
+		"POST /api/v1/orgs/{id}/invitations/{inviteId}/resend":             nil,
+		"PATCH /api/v1/orgs/{id}/link-domains":                             map[string]any{"origins": []string{"https://tnta.example"}},
+		"PUT /api/v1/orgs/{id}/members/{userId}":                           map[string]any{"role": "viewer"},
+		"PUT /api/v1/orgs/{id}/partner":                                    map[string]any{"partner_id": nil},
+		"PATCH /api/v1/orgs/{id}/personnel-settings":                       map[string]any{"anonymize_reports": true},
+		"PATCH /api/v1/orgs/{id}/positioning":                              map[string]any{"enabled": true},
+		"PATCH /api/v1/orgs/{id}/retention":                                map[string]any{"audit_days": 30},
+		"POST /api/v1/orgs/{id}/sandbox":                                   nil,
+		"PATCH /api/v1/orgs/{id}/scan-settings":                            map[string]any{"session_scans": true},
+		"POST /api/v1/orgs/{id}/service-accounts":                          name,
+		"PATCH /api/v1/orgs/{id}/tag-settings":                             map[string]any{"unique_across_types": true},
+		"PATCH /api/v1/orgs/{id}/team-settings":                            map[string]any{"strict_mode": true},
+		"POST /api/v1/orgs/{id}/transfer-ownership":                        map[string]any{"user_id": a.user},
+		"POST /api/v1/orgs/{id}/transfer-ownership/accept":                 nil,
+		"POST /api/v1/orgs/{id}/transfer-ownership/cancel":                 nil,
+		"POST /api/v1/orgs/{id}/transfer-ownership/decline":                nil,
+		"POST /api/v1/orgs/{id}/webhook-deliveries/{deliveryId}/redeliver": nil,
+		"POST /api/v1/orgs/{id}/webhooks":                                  map[string]any{"url": "https://93.184.216.34/tnta", "event_types": []string{}},
+
+		"PATCH /api/v1/output-devices/{output_device_id}":      name,
+		"POST /api/v1/output-devices/{output_device_id}/reset": nil,
+		"POST /api/v1/output-devices/{output_device_id}/test":  nil,
+
+		"PATCH /api/v1/scan-devices/{scan_device_id}":               name,
+		"POST /api/v1/scan-devices/{scan_device_id}/credential":     nil,
+		"PATCH /api/v1/scan-devices/{scan_device_id}/reader-config": map[string]any{"dwell_ms": 500},
+		"POST /api/v1/scan-devices/{scan_device_id}/scan-points":    name,
+		"PATCH /api/v1/scan-points/{scan_point_id}":                 name,
+
+		"PUT /api/v1/sensor-thresholds/{threshold_id}": map[string]any{"asset_type": "reefer_pallet", "metric": "temperature", "max_value": 8},
+
+		"PATCH /api/v1/teams/{team_id}":                       name,
+		"PUT /api/v1/teams/{team_id}/assets/{asset_id}":       nil,
+		"PUT /api/v1/teams/{team_id}/locations/{location_id}": nil,
+		"PUT /api/v1/teams/{team_id}/members/{user_id}":       map[string]any{"role": "member"},
+
+		"POST /api/v1/transfer-orders/{transfer_order_id}/cancel":  nil,
+		"POST /api/v1/transfer-orders/{transfer_order_id}/receive": nil,
+		"POST /api/v1/transfer-orders/{transfer_order_id}/scans":   map[string]any{"stage": "origin", "epcs": []string{"E28011700000000000000001"}},
+		"POST /api/v1/transfer-orders/{transfer_order_id}/ship":    nil,
+		"PUT /api/v1/transfer-orders/{transfer_order_id}/tracking": map[string]any{"carrier": "dhl", "tracking_number": "00340434292135100186"},
+
+		"PUT /api/v1/users/{id}": name,
+	}
+}
+
+// tenant is one org's caller identity and the resources seeded in it.
+type tenant struct {
+	org, user int
+	token     string
+	// fixtures maps a path parameter name to the id of the fixture it
+	// names. Parameters shared by two surfaces ({id}, a location's
+	// {tag_id}) are resolved by param.
+	fixtures map[string]int
+	jti      string
+}
+
+// param resolves a route's path parameter to this tenant's fixture.
+func (tn *tenant) param(route, name string) (string, bool) {
+	id, ok := tn.fixtures[name]
+	switch name {
+	case "jti":
+		return tn.jti, tn.jti != ""
+	case "operation":
+		return isolationRuleOperation, true
+	case "name":
+		return email.TemplateInvitation, true
+	case "userId", "user_id":
+		id, ok = tn.user, true
+	case "tag_id":
+		if strings.HasPrefix(route, "/api/v1/locations/") {
+			id, ok = tn.fixtures["location_tag_id"]
+		}
+	case "id":
+		switch {
+		case strings.HasPrefix(route, "/api/v1/orgs/"):
+			id, ok = tn.org, true
+		case strings.HasPrefix(route, "/api/v1/users/"):
+			id, ok = tn.user, true
+		case strings.HasPrefix(route, "/api/v1/mustering/events/"):
+			id, ok = tn.fixtures["muster_event_id"]
+		}
+	}
+	return strconv.Itoa(id), ok
+}
+
+// TestTenantIsolation drives every org-scoped route in the production router
+// as an admin of org A, aimed at org B's resources, and asserts nothing of
+// B's comes back and nothing of B's changes:
+//
+//   - every GET without path parameters (the lists, reports, lookups) must
+//     not mention a B fixture;
+//   - every route whose path names a B resource, sent a valid body, must be
+//     refused with 403 or 404;
+//   - afterwards B still reads back every fixture unchanged.
+//
+// Every path parameter must resolve to a B fixture and every parameterized
+// write must have a body in isolationBodies, so a new route fails here until
+// it is covered.
+func TestTenantIsolation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-key")
+
+	db := testutil.SetupTestDBFull(t)
+	r := setupRealRouter(t, db.Store)
+
+	send := func(token, method, path string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		var buf bytes.Buffer
+		ct := "application/json"
+		if method == http.MethodPatch {
+			ct = "application/merge-patch+json"
+		}
+		switch b := body.(type) {
+		case nil:
+		case rawBody:
+			buf.Write(b.data)
+			ct = b.contentType
+		default:
+			require.NoError(t, json.NewEncoder(&buf).Encode(body))
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		req := httptest.NewRequest(method, path, &buf).WithContext(ctx)
+		req.Header.Set("Authorization", "Bearer "+token)
+		if body != nil || method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch {
+			req.Header.Set("Content-Type", ct)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	orgs := testutil.NewOrgFactory()
+	// Org C only receives B's asset transfer, so A is neither party to it.
+	orgs.WithName("TNTC Org").WithIdentifier("tntc-org").Create(t, db.AdminPool)
+	a := seedTenant(t, db, send, orgs, "tnta")
+	b := seedTenant(t, db, send, orgs, tenantMarker)
+
+	// Sanity: A's own data is visible, so an absent B marker means
+	// filtered rather than an empty response.
+	w := send(a.token, http.MethodGet, "/api/v1/assets", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Contains(t, strings.ToLower(w.Body.String()), "tnta")
+
+	var routes []string
+	require.NoError(t, chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, method+" "+route)
+		return nil
+	}))
+	slices.Sort(routes)
+
+	bodies := isolationBodies(a)
+	exercised := 0
+	for _, mr := range routes {
+		method, route, _ := strings.Cut(mr, " ")
+		if !isolationCandidate(method, route) {
+			continue
+		}
+
+		params := pathParamRE.FindAllString(route, -1)
+		if len(params) == 0 && method != http.MethodGet {
+			continue // org-implicit writes act on the caller's own org
+		}
+		path, seeded := route, true
+		for _, p := range params {
+			name := strings.Trim(p, "{}")
+			v, ok := b.param(route, name)
+			if !ok {
+				t.Errorf("%s: no org-B fixture for {%s}; seed one in seedTenant", mr, name)
+				seeded = false
+			}
+			path = strings.Replace(path, p, v, 1)
+		}
+		if !seeded {
+			continue
+		}
+
+		body, ok := bodies[mr]
+		if !ok && len(params) > 0 && method != http.MethodGet && method != http.MethodDelete {
+			t.Errorf("%s: no request body; add one to isolationBodies", mr)
+			continue
+		}
+		w := send(a.token, method, path, body)
+		exercised++
+
+		assert.NotContains(t, strings.ToLower(w.Body.String()), tenantMarker,
+			"%s %s as org A leaked org B data (status %d)", method, path, w.Code)
+		switch {
+		case len(params) == 0:
+		case isolationOrgImplicit[mr]:
+			assert.Equal(t, http.StatusOK, w.Code, "%s %s as org A: %s", method, path, w.Body.String())
+		case isolationMethodNotAllowed[mr]:
+			assert.Equal(t, http.StatusMethodNotAllowed, w.Code, "%s %s as org A: %s", method, path, w.Body.String())
+		default:
+			assert.Contains(t, []int{http.StatusForbidden, http.StatusNotFound}, w.Code,
+				"%s %s as org A against org B: want 403 or 404: %s", method, path, w.Body.String())
+		}
+	}
+	t.Logf("tenant isolation: %d requests", exercised)
+
+	// B still sees every fixture as it created it.
+	org := "/api/v1/orgs/" + strconv.Itoa(b.org)
+	fixture := func(prefix, name string) string {
+		return prefix + "/" + strconv.Itoa(b.fixtures[name])
+	}
+	for _, path := range []string{
+		org,
+		fixture(org+"/exports", "exportId"),
+		fixture(org+"/webhook-deliveries", "deliveryId"),
+		fixture("/api/v1/assets", "asset_id"),
+		fixture("/api/v1/assets", "asset_id") + "/consumable",
+		fixture("/api/v1/locations", "location_id"),
+		fixture("/api/v1/teams", "team_id"),
+		fixture("/api/v1/dashboards", "dashboard_id"),
+		fixture("/api/v1/scan-devices", "scan_device_id"),
+		fixture("/api/v1/scan-points", "scan_point_id"),
+		fixture("/api/v1/connectors", "connector_id"),
+		fixture("/api/v1/dock-doors", "dock_door_id"),
+		fixture("/api/v1/documents", "document_id"),
+		fixture("/api/v1/kits", "kit_id"),
+		fixture("/api/v1/label-printers", "printer_id"),
+		fixture("/api/v1/print-jobs", "print_job_id"),
+		fixture("/api/v1/output-devices", "output_device_id"),
+	} {
+		w := send(b.token, http.MethodGet, path, nil)
+		if assert.Equal(t, http.StatusOK, w.Code, "org B lost %s: %s", path, w.Body.String()) {
+			assert.NotContains(t, w.Body.String(), isolationOverwrite, "org A overwrote %s", path)
+		}
+	}
+
+	// Decisions org A tried to take left B's requests where B put them.
+	for path, status := range map[string]string{
+		fixture("/api/v1/approvals", "approval_id"):             "pending",
+		fixture("/api/v1/asset-transfers", "transfer_id"):       "pending",
+		fixture("/api/v1/asset-disposals", "disposal_id"):       "pending",
+		fixture("/api/v1/transfer-orders", "transfer_order_id"): "open",
+		fixture("/api/v1/mustering/events", "muster_event_id"):  "active",
+	} {
+		w := send(b.token, http.MethodGet, path, nil)
+		if assert.Equal(t, http.StatusOK, w.Code, "org B lost %s: %s", path, w.Body.String()) {
+			assert.Contains(t, w.Body.String(), `"status":"`+status+`"`, "org A changed the status of %s", path)
+		}
+	}
+
+	// Fixtures without a read of their own are still listed.
+	for path, name := range map[string]string{
+		org + "/api-keys":         "key_id",
+		org + "/custom-domains":   "domain_id",
+		org + "/invitations":      "inviteId",
+		org + "/service-accounts": "serviceAccountId",
+		org + "/webhooks":         "webhookId",
+		fixture("/api/v1/assets", "asset_id") + "/condition-reports": "report_id",
+		fixture("/api/v1/assets", "asset_id") + "/assignments":       "assignment_id",
+		"/api/v1/kiosk-tokens":      "kiosk_token_id",
+		"/api/v1/location-policies": "policy_id",
+		"/api/v1/sensor-thresholds": "threshold_id",
+		"/api/v1/users/me/devices":  "device_id",
+	} {
+		w := send(b.token, http.MethodGet, path, nil)
+		if assert.Equal(t, http.StatusOK, w.Code, "%s: %s", path, w.Body.String()) {
+			assert.Contains(t, w.Body.String(), fmt.Sprintf(`"id":%d`, b.fixtures[name]), "org B lost its %s from %s", name, path)
+		}
+	}
+	w = send(b.token, http.MethodGet, org+"/members", nil)
+	assert.Contains(t, w.Body.String(), "tntb@example.com", "org B lost its admin")
+
+	w = send(b.token, http.MethodGet, fixture("/api/v1/assets", "asset_id"), nil)
+	assert.Contains(t, w.Body.String(), "TNTB-FL", "org B asset lost its external key")
+	assert.Contains(t, w.Body.String(), "TNTB-ASSET-TAG", "org B asset lost its tag")
+	w = send(b.token, http.MethodGet, fixture("/api/v1/locations", "location_id"), nil)
+	assert.Contains(t, w.Body.String(), "TNTB-WH", "org B location lost its external key")
+	assert.Contains(t, w.Body.String(), "TNTB-LOCATION-TAG", "org B location lost its tag")
+	w = send(b.token, http.MethodGet, "/api/v1/approval-rules", nil)
+	assert.Contains(t, w.Body.String(), `"threshold":1`, "org A changed org B's approval rule")
+}
+
+// isolationCandidate reports whether a walked route is an org-scoped API
+// route the verifier should drive.
+func isolationCandidate(method, route string) bool {
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+	if !strings.HasPrefix(route, "/api/v1/") || strings.HasSuffix(route, "*") {
+		return false
+	}
+	for _, prefix := range isolationExempt {
+		if strings.HasPrefix(route, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// seedTenant creates an org with an admin user and one of each resource a
+// route path can name: through the API as that user where a create route
+// exists, and in SQL for the rest. Every name and key carries marker so
+// leaks are detectable by substring.
+func seedTenant(t *testing.T, db *testutil.TestDB, send func(token, method, path string, body any) *httptest.ResponseRecorder, orgs *testutil.OrgFactory, marker string) *tenant {
+	t.Helper()
+	ctx := context.Background()
+	upper := strings.ToUpper(marker)
+
+	tn := &tenant{
+		org:      orgs.WithName(upper+" Org").WithIdentifier(marker+"-org").Create(t, db.AdminPool),
+		fixtures: map[string]int{},
+	}
+	require.NoError(t, db.AdminPool.QueryRow(ctx, `
+		INSERT INTO trakrf.users (name, email, password_hash)
+		VALUES ($1, $2, 'stub') RETURNING id`,
+		upper+" Admin", marker+"@example.com",
+	).Scan(&tn.user))
+	_, err := db.AdminPool.Exec(ctx, `
+		INSERT INTO trakrf.org_users (org_id, user_id, role, status)
+		VALUES ($1, $2, 'admin', 'active')`, tn.org, tn.user)
+	require.NoError(t, err)
+	tn.token, err = jwt.Generate(tn.user, marker+"@example.com", &tn.org)
+	require.NoError(t, err)
+
+	create := func(path string, body any) int {
+		t.Helper()
+		w := send(tn.token, http.MethodPost, path, body)
+		require.Contains(t, []int{http.StatusCreated, http.StatusAccepted}, w.Code, "seed %s: %s", path, w.Body.String())
+		var resp struct {
+			Data struct {
+				ID int `json:"id"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		require.NotZero(t, resp.Data.ID, "seed %s: %s", path, w.Body.String())
+		return resp.Data.ID
+	}
+	insert := func(query string, args ...any) int {
+		t.Helper()
+		var id int
+		require.NoError(t, db.AdminPool.QueryRow(ctx, query, args...).Scan(&id))
+		return id
+	}
+	f := tn.fixtures
+	org := "/api/v1/orgs/" + strconv.Itoa(tn.org)
+
+	f["location_id"] = create("/api/v1/locations", map[string]any{"external_key": upper + "-WH", "name": upper + " Warehouse"})
+	dock := create("/api/v1/locations", map[string]any{"external_key": upper + "-DOCK", "name": upper + " Dock"})
+	asset := "/api/v1/assets/"
+	f["location_tag_id"] = create("/api/v1/locations/"+strconv.Itoa(f["location_id"])+"/tags",
+		map[string]string{"tag_type": "barcode", "value": upper + "-LOCATION-TAG"})
+	f["asset_id"] = create("/api/v1/assets", map[string]any{"external_key": upper + "-FL", "name": upper + " Forklift"})
+	asset += strconv.Itoa(f["asset_id"])
+	f["tag_id"] = create(asset+"/tags", map[string]string{"tag_type": "barcode", "value": upper + "-ASSET-TAG"})
+	f["identifier_id"] = f["tag_id"]
+	f["team_id"] = create("/api/v1/teams", map[string]any{"name": upper + " Team"})
+	f["dashboard_id"] = create("/api/v1/dashboards", map[string]any{"name": upper + " Dashboard", "shared": true, "widgets": []any{}})
+	f["scan_device_id"] = create("/api/v1/scan-devices", map[string]any{"name": upper + " Reader", "type": "csl_cs463"})
+	points := "/api/v1/scan-devices/" + strconv.Itoa(f["scan_device_id"]) + "/scan-points"
+	f["scan_point_id"] = create(points, map[string]any{"name": upper + " Antenna", "location_id": f["location_id"]})
+	inner := create(points, map[string]any{"name": upper + " Inner Antenna", "location_id": f["location_id"]})
+	f["key_id"] = create(org+"/api-keys", map[string]any{"name": upper + " Key", "scopes": []string{"assets:read"}})
+	require.NoError(t, db.AdminPool.QueryRow(ctx,
+		`SELECT jti::text FROM trakrf.api_keys WHERE id = $1`, f["key_id"]).Scan(&tn.jti))
+	f["domain_id"] = create(org+"/custom-domains", map[string]any{"hostname": "track." + marker + ".example"})
+
+	w := send(tn.token, http.MethodPut, asset+"/consumable", map[string]any{"unit": "box", "min_level": 1})
+	require.Less(t, w.Code, http.StatusMultipleChoices, "seed consumable: %s", w.Body.String())
+	w = send(tn.token, http.MethodPost, asset+"/stock/adjustments", map[string]any{"location_identifier": upper + "-WH", "delta": 5})
+	require.Less(t, w.Code, http.StatusMultipleChoices, "seed stock adjustment: %s", w.Body.String())
+	f["report_id"] = create(asset+"/condition-reports", map[string]any{"rating": 4, "notes": upper + " inspection"})
+	f["assignment_id"] = insert(`
+		INSERT INTO trakrf.asset_assignments (org_id, asset_id, action, user_id, performed_by)
+		VALUES ($1, $2, 'check_out', $3, $3) RETURNING id`, tn.org, f["asset_id"], tn.user)
+	f["jobId"] = insert(`
+		INSERT INTO trakrf.bulk_import_jobs (org_id, status) VALUES ($1, 'completed') RETURNING id`, tn.org)
+
+	f["connector_id"] = create("/api/v1/connectors", map[string]any{
+		"kind": "netsuite", "name": upper + " NetSuite",
+		"config": map[string]any{"account_id": "1234567"},
+		"credentials": map[string]any{
+			"consumer_key": marker + "-ck", "consumer_secret": marker + "-cs",
+			"token_id": marker + "-ti", "token_secret": marker + "-ts",
+		},
+	})
+	f["dock_door_id"] = create("/api/v1/dock-doors", map[string]any{
+		"location_id": f["location_id"], "name": upper + " Door",
+		"outer_scan_point_id": f["scan_point_id"], "inner_scan_point_id": inner,
+	})
+	f["document_id"] = create("/api/v1/documents", map[string]any{"type": "insurance", "title": upper + " Policy"})
+	f["kiosk_token_id"] = create("/api/v1/kiosk-tokens", map[string]any{"name": upper + " Lobby", "location_id": f["location_id"]})
+	f["kit_id"] = create("/api/v1/kits", map[string]any{
+		"label": upper + "-KIT",
+		"members": []map[string]any{
+			{"epc": fmt.Sprintf("E28011%018X", tn.org*10+1)},
+			{"epc": fmt.Sprintf("E28011%018X", tn.org*10+2)},
+		},
+	})
+	f["printer_id"] = create("/api/v1/label-printers", map[string]any{
+		"location_id": f["location_id"], "name": upper + " Zebra", "host": "203.0.113.9",
+	})
+	f["print_job_id"] = create("/api/v1/print-jobs", map[string]any{"printer_id": f["printer_id"], "asset_ids": []int{f["asset_id"]}})
+	f["policy_id"] = create("/api/v1/location-policies", map[string]any{"location_id": f["location_id"], "team_id": f["team_id"]})
+	f["output_device_id"] = create("/api/v1/output-devices", map[string]any{"name": upper + " Beacon", "base_url": "http://192.0.2.10"})
+	f["threshold_id"] = create("/api/v1/sensor-thresholds", map[string]any{"asset_type": marker + "_reefer", "metric": "temperature", "max_value": 8})
+	f["transfer_order_id"] = create("/api/v1/transfer-orders", map[string]any{
+		"reference": upper + "-TO", "origin_location_id": f["location_id"],
+		"destination_location_id": dock, "asset_ids": []int{f["asset_id"]},
+	})
+	f["transfer_id"] = create("/api/v1/asset-transfers", map[string]any{"asset_id": f["asset_id"], "target_org_identifier": "tntc-org"})
+	f["disposal_id"] = create("/api/v1/asset-disposals", map[string]any{"asset_id": f["asset_id"], "reason": upper + " end of life", "method": "scrapped"})
+	f["device_id"] = create("/api/v1/users/me/devices", map[string]any{"platform": "android", "push_token": marker + "-push-token"})
+	f["muster_event_id"] = create("/api/v1/mustering/events", map[string]any{"window_minutes": 15})
+	f["entryId"] = insert(`
+		INSERT INTO trakrf.muster_event_entries (org_id, muster_event_id, asset_id, label)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (muster_event_id, asset_id) DO UPDATE SET label = EXCLUDED.label
+		RETURNING id`, tn.org, f["muster_event_id"], f["asset_id"], upper+" Forklift")
+
+	f["exportId"] = create(org+"/export", map[string]any{"format": "json"})
+	f["inviteId"] = create(org+"/invitations", map[string]any{"email": marker + "-invitee@example.com", "role": "operator"})
+	f["serviceAccountId"] = create(org+"/service-accounts", map[string]any{"name": upper + " Robot"})
+	f["webhookId"] = create(org+"/webhooks", map[string]any{"url": "https://93.184.216.34/" + marker, "event_types": []string{}})
+	f["deliveryId"] = insert(`
+		INSERT INTO trakrf.webhook_deliveries (org_id, endpoint_id, event_type, payload)
+		VALUES ($1, $2, 'asset.updated', '{}') RETURNING id`, tn.org, f["webhookId"])
+	f["partnerId"] = insert(`INSERT INTO trakrf.partners (name) VALUES ($1) RETURNING id`, upper+" Partner")
+	_, err = db.AdminPool.Exec(ctx,
+		`INSERT INTO trakrf.partner_admins (partner_id, user_id) VALUES ($1, $2)`, f["partnerId"], tn.user)
+	require.NoError(t, err)
+	f["approval_id"] = insert(`
+		INSERT INTO trakrf.approval_requests
+			(org_id, operation, method, path, body_sha256, summary, requested_by, expires_at)
+		VALUES ($1, 'org.settings', 'PATCH', $2, $3, $4, $5, now() + interval '1 day')
+		RETURNING id`,
+		tn.org, org+"/retention", strings.Repeat("0", 64), upper+" retention change", tn.user)
+
+	// Last, so it parks nothing above.
+	w = send(tn.token, http.MethodPut, "/api/v1/approval-rules/"+isolationRuleOperation, map[string]any{"threshold": 1})
+	require.Equal(t, http.StatusOK, w.Code, "seed approval rule: %s", w.Body.String())
+
+	// One scan, so the history and location reports have a row to leak.
+	testutil.NewScanFactory(tn.org).Create(t, db.AdminPool, f["asset_id"], f["location_id"])
+	testutil.RefreshAssetScanLatest(t, db.AdminPool)
+	return tn
+}
@@ -0,0 +1,239 @@
+// Package config loads and validates the server's environment-driven settings
+// in one place at startup. Load collects every problem it finds rather than
+// stopping at the first, so an operator fixing a broken deploy sees the whole
+// list in one boot log instead of one error per restart.
+//
+// Subsystems with their own knobs (the pool, event export, ingest, geofence)
+// keep their ConfigFromEnv readers; Load reuses the ones that fail hard so a
+// bad value is reported alongside the rest.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/services/email"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// Config is the validated server configuration.
+type Config struct {
+	// Env is APP_ENV: "" for local development, "test" for CI, otherwise the
+	// deployed environment name (preview, staging, prod).
+	Env       string
+	Port      int
+	SentryDSN string
+	Database  Database
+	JWT       JWT
+	Email     Email
+	CORS      CORS
+	Links     applinks.Config
+	Security  middleware.SecurityHeadersConfig
+	Server    Server
+}
+
+// Database holds the Postgres connection settings.
+type Database struct {
+	URL  string
+	Pool storage.PoolConfig
+}
+
+// JWT holds the token signing settings.
+type JWT struct {
+	Secret     string
+	Expiration time.Duration
+}
+
+// Email holds the mailer settings (provider, sender, provider credentials).
+// A provider without its credentials cannot send; readiness reports it.
+type Email struct {
+	email.MailerConfig
+	OrgCreateNotifyAddr string
+}
+
+// CORS holds the allowed browser origin: "*" (the default), "disabled", or a
+// single scheme://host[:port] origin.
+type CORS struct {
+	Origin string
+}
+
+// Server holds the HTTP server timeouts. ReadTimeout and WriteTimeout bound
+// ordinary requests; LongRequestTimeout replaces both on the routes
+// middleware.Timeouts lists as long-running.
+type Server struct {
+	ReadTimeout        time.Duration
+	WriteTimeout       time.Duration
+	LongRequestTimeout time.Duration
+	IdleTimeout        time.Duration
+	ShutdownTimeout    time.Duration
+}
+
+// Load reads and validates the configuration from the environment:
+//
+//	APP_ENV                   deployment name (unset = local)
+//	BACKEND_PORT              1-65535                 (default 8080)
+//	PG_URL                    postgres URL            (required)
+//	PG_POOL_*                 see storage.PoolConfigFromEnv
+//	JWT_SECRET                required outside local/test
+//	JWT_EXPIRATION            positive seconds        (default 3600)
+//	EMAIL_PROVIDER            resend|smtp|ses|log     (default resend)
+//	EMAIL_FROM, RESEND_API_KEY, SMTP_*, SES_REGION, AWS_*  see email.MailerConfigFromEnv
+//	ORG_CREATE_NOTIFY_ADDR    optional address
+//	BACKEND_CORS_ORIGIN       *|disabled|origin       (default *)
+//	APP_BASE_URL, APP_LINK_ALLOWED_ORIGINS, APP_TENANT_DOMAIN  see applinks.ConfigFromEnv
+//	SECURITY_HSTS_MAX_AGE, SECURITY_CSP*    see middleware.SecurityHeadersConfigFromEnv
+//	BACKEND_READ_TIMEOUT      dur                     (default 10s)
+//	BACKEND_WRITE_TIMEOUT     dur                     (default 10s)
+//	BACKEND_LONG_REQUEST_TIMEOUT dur                  (default 10m)
+//	BACKEND_IDLE_TIMEOUT      dur                     (default 120s)
+//	BACKEND_SHUTDOWN_TIMEOUT  dur                     (default 5s)
+//	SENTRY_DSN                unset disables Sentry
+//
+// With SECRETS_PROVIDER set, PG_URL, JWT_SECRET and the email credentials
+// may come from a secrets manager instead; they are in the environment by
+// the time Load runs (see secrets.FromEnv).
+//
+// The returned error joins one descriptive error per bad setting.
+func Load() (Config, error) {
+	var errs []error
+	c := Config{
+		Env:       os.Getenv("APP_ENV"),
+		Port:      8080,
+		SentryDSN: os.Getenv("SENTRY_DSN"),
+		JWT: JWT{
+			Secret:     os.Getenv("JWT_SECRET"),
+			Expiration: time.Hour,
+		},
+		Email: Email{
+			OrgCreateNotifyAddr: os.Getenv("ORG_CREATE_NOTIFY_ADDR"),
+		},
+		CORS: CORS{Origin: "*"},
+		Server: Server{
+			ReadTimeout:        10 * time.Second,
+			WriteTimeout:       10 * time.Second,
+			LongRequestTimeout: 10 * time.Minute,
+			IdleTimeout:        120 * time.Second,
+			ShutdownTimeout:    5 * time.Second,
+		},
+	}
+
+	if raw := os.Getenv("BACKEND_PORT"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 65535 {
+			errs = append(errs, fmt.Errorf("BACKEND_PORT must be a port number between 1 and 65535, got %q", raw))
+		} else {
+			c.Port = n
+		}
+	}
+
+	c.Database.URL = os.Getenv("PG_URL")
+	if c.Database.URL == "" {
+		errs = append(errs, fmt.Errorf("PG_URL must be set"))
+	} else if _, err := pgxpool.ParseConfig(c.Database.URL); err != nil {
+		errs = append(errs, fmt.Errorf("PG_URL is not a valid connection string: %w", err))
+	}
+	pool, err := storage.PoolConfigFromEnv()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	c.Database.Pool = pool
+
+	if err := jwt.ValidateSecret(); err != nil {
+		errs = append(errs, err)
+	}
+	if raw := os.Getenv("JWT_EXPIRATION"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			errs = append(errs, fmt.Errorf("JWT_EXPIRATION must be a positive number of seconds, got %q", raw))
+		} else {
+			c.JWT.Expiration = time.Duration(n) * time.Second
+		}
+	}
+
+	mailer, err := email.MailerConfigFromEnv()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	c.Email.MailerConfig = mailer
+	if addr := c.Email.OrgCreateNotifyAddr; addr != "" && !strings.Contains(addr, "@") {
+		errs = append(errs, fmt.Errorf("ORG_CREATE_NOTIFY_ADDR must be an email address, got %q", addr))
+	}
+
+	if raw := os.Getenv("BACKEND_CORS_ORIGIN"); raw != "" {
+		if err := validateOrigin(raw); err != nil {
+			errs = append(errs, err)
+		} else {
+			c.CORS.Origin = raw
+		}
+	}
+
+	links, err := applinks.ConfigFromEnv()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	c.Links = links
+
+	security, err := middleware.SecurityHeadersConfigFromEnv()
+	if err != nil {
+		errs = append(errs, err)
+	}
+	c.Security = security
+
+	for _, d := range []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"BACKEND_READ_TIMEOUT", &c.Server.ReadTimeout},
+		{"BACKEND_WRITE_TIMEOUT", &c.Server.WriteTimeout},
+		{"BACKEND_LONG_REQUEST_TIMEOUT", &c.Server.LongRequestTimeout},
+		{"BACKEND_IDLE_TIMEOUT", &c.Server.IdleTimeout},
+		{"BACKEND_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout},
+	} {
+		raw := os.Getenv(d.name)
+		if raw == "" {
+			continue
+		}
+		v, err := time.ParseDuration(raw)
+		if err != nil || v <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be a positive duration, got %q", d.name, raw))
+			continue
+		}
+		*d.dst = v
+	}
+
+	if c.SentryDSN != "" {
+		if u, err := url.Parse(c.SentryDSN); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("SENTRY_DSN is not a valid DSN URL"))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+	return c, nil
+}
+
+// validateOrigin accepts the CORS middleware's sentinels or a bare origin.
+// A trailing path or slash would never match a browser's Origin header, so
+// it is rejected rather than silently breaking every cross-origin call.
+func validateOrigin(origin string) error {
+	if origin == "*" || origin == "disabled" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return fmt.Errorf("BACKEND_CORS_ORIGIN must be *, disabled, or an origin like https://app.example.com, got %q", origin)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedMark replaces secret values in the redacted view.
+const redactedMark = "[redacted]"
+
+// View is the redacted, JSON-friendly form of Config served to superadmins.
+// Secrets are reported only as set or unset; URLs keep their host so an
+// operator can tell which database or Sentry project a replica points at.
+type View struct {
+	Env       string       `json:"env"`
+	Port      int          `json:"port"`
+	SentryDSN string       `json:"sentry_dsn"`
+	Database  DatabaseView `json:"database"`
+	JWT       JWTView      `json:"jwt"`
+	Email     EmailView    `json:"email"`
+	CORS      CORSView     `json:"cors"`
+	Links     LinksView    `json:"links"`
+	Security  SecurityView `json:"security"`
+	Server    ServerView   `json:"server"`
+}
+
+type DatabaseView struct {
+	URL                string `json:"url"`
+	MaxConns           int32  `json:"max_conns"`
+	MinConns           int32  `json:"min_conns"`
+	MaxConnLifetime    string `json:"max_conn_lifetime"`
+	MaxConnIdleTime    string `json:"max_conn_idle_time"`
+	HealthCheckPeriod  string `json:"health_check_period"`
+	StatementCacheMode string `json:"statement_cache_mode"`
+	SlowQueryThreshold string `json:"slow_query_threshold"`
+}
+
+type JWTView struct {
+	Secret            string `json:"secret"`
+	ExpirationSeconds int    `json:"expiration_seconds"`
+}
+
+type EmailView struct {
+	Provider            string `json:"provider"`
+	From                string `json:"from"`
+	ResendAPIKey        string `json:"resend_api_key"`
+	SMTPHost            string `json:"smtp_host"`
+	SMTPPort            int    `json:"smtp_port"`
+	SMTPUsername        string `json:"smtp_username"`
+	SMTPPassword        string `json:"smtp_password"`
+	SESRegion           string `json:"ses_region"`
+	SESAccessKeyID      string `json:"ses_access_key_id"`
+	SESSecretAccessKey  string `json:"ses_secret_access_key"`
+	OrgCreateNotifyAddr string `json:"org_create_notify_addr"`
+}
+
+type CORSView struct {
+	Origin string `json:"origin"`
+}
+
+type LinksView struct {
+	BaseURL        string   `json:"base_url"`
+	AllowedOrigins []string `json:"allowed_origins"`
+}
+
+type SecurityView struct {
+	HSTSMaxAge    int    `json:"hsts_max_age"`
+	SPAPolicy     string `json:"spa_policy"`
+	CSPReportOnly bool   `json:"csp_report_only"`
+}
+
+type ServerView struct {
+	ReadTimeout        string `json:"read_timeout"`
+	WriteTimeout       string `json:"write_timeout"`
+	LongRequestTimeout string `json:"long_request_timeout"`
+	IdleTimeout        string `json:"idle_timeout"`
+	ShutdownTimeout    string `json:"shutdown_timeout"`
+}
+
+// Redacted returns c with every secret masked.
+func (c Config) Redacted() View {
+	return View{
+		Env:       c.Env,
+		Port:      c.Port,
+		SentryDSN: redactURL(c.SentryDSN),
+		Database: DatabaseView{
+			URL:                redactURL(c.Database.URL),
+			MaxConns:           c.Database.Pool.MaxConns,
+			MinConns:           c.Database.Pool.MinConns,
+			MaxConnLifetime:    c.Database.Pool.MaxConnLifetime.String(),
+			MaxConnIdleTime:    c.Database.Pool.MaxConnIdleTime.String(),
+			HealthCheckPeriod:  c.Database.Pool.HealthCheckPeriod.String(),
+			StatementCacheMode: c.Database.Pool.StatementCacheMode.String(),
+			SlowQueryThreshold: c.Database.Pool.SlowQueryThreshold.String(),
+		},
+		JWT: JWTView{
+			Secret:            redactSecret(c.JWT.Secret),
+			ExpirationSeconds: int(c.JWT.Expiration.Seconds()),
+		},
+		Email: EmailView{
+			Provider:            c.Email.Provider,
+			From:                c.Email.From,
+			ResendAPIKey:        redactSecret(c.Email.ResendAPIKey),
+			SMTPHost:            c.Email.SMTPHost,
+			SMTPPort:            c.Email.SMTPPort,
+			SMTPUsername:        c.Email.SMTPUsername,
+			SMTPPassword:        redactSecret(c.Email.SMTPPassword),
+			SESRegion:           c.Email.SESRegion,
+			SESAccessKeyID:      c.Email.SESAccessKeyID,
+			SESSecretAccessKey:  redactSecret(c.Email.SESSecretAccessKey),
+			OrgCreateNotifyAddr: c.Email.OrgCreateNotifyAddr,
+		},
+		CORS: CORSView{Origin: c.CORS.Origin},
+		Links: LinksView{
+			BaseURL:        c.Links.BaseURL,
+			AllowedOrigins: c.Links.AllowedOrigins,
+		},
+		Security: SecurityView{
+			HSTSMaxAge:    c.Security.HSTSMaxAge,
+			SPAPolicy:     c.Security.SPAPolicy,
+			CSPReportOnly: c.Security.ReportOnly,
+		},
+		Server: ServerView{
+			ReadTimeout:        c.Server.ReadTimeout.String(),
+			WriteTimeout:       c.Server.WriteTimeout.String(),
+			LongRequestTimeout: c.Server.LongRequestTimeout.String(),
+			IdleTimeout:        c.Server.IdleTimeout.String(),
+			ShutdownTimeout:    c.Server.ShutdownTimeout.String(),
+		},
+	}
+}
+
+// redactSecret reports only whether a secret is set.
+func redactSecret(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redactedMark
+}
+
+// redactURL keeps scheme, host and path but drops credentials and the query
+// string, which for PG_URL can carry a password or sslkey path. A value that
+// does not parse is masked entirely.
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return redactedMark
+	}
+	hadUser := u.User != nil
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	s := u.String()
+	if hadUser {
+		s = strings.Replace(s, "://", "://"+redactedMark+"@", 1)
+	}
+	return s
+}
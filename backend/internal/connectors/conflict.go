@@ -0,0 +1,42 @@
+package connectors
+
+import (
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/connector"
+)
+
+// Action is what a sync does with one asset/record pair.
+type Action int
+
+const (
+	ActionNone Action = iota
+	ActionPull        // write the remote record onto the asset
+	ActionPush        // write the asset onto the remote record
+)
+
+// Resolve picks the action for a pair given which sides changed since the
+// last sync. A one-sided change flows to the other side; a change on both is
+// a conflict settled by policy. newest_wins compares modification times and
+// breaks ties toward the remote, the ledger of record for fixed assets.
+func Resolve(policy string, localChanged, remoteChanged bool, localAt, remoteAt time.Time) Action {
+	switch {
+	case !localChanged && !remoteChanged:
+		return ActionNone
+	case !localChanged:
+		return ActionPull
+	case !remoteChanged:
+		return ActionPush
+	}
+	switch policy {
+	case connector.ConflictLocalWins:
+		return ActionPush
+	case connector.ConflictNewestWins:
+		if localAt.After(remoteAt) {
+			return ActionPush
+		}
+		return ActionPull
+	default:
+		return ActionPull
+	}
+}
@@ -0,0 +1,50 @@
+// Package connectors syncs assets with external systems of record (ERPs).
+// A connector row holds the kind-specific config, vault-sealed credentials,
+// a field mapping and a conflict policy; the Syncer job claims connectors as
+// they come due, pulls remote changes onto assets and pushes asset changes
+// back, recording each run. Per-asset sync state lives in
+// connector_asset_links, so a run that dies mid-way is simply redone by the
+// next one.
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/connector"
+)
+
+// Remote is one external system's asset records.
+type Remote interface {
+	// Changed returns records modified after since (every record when since
+	// is nil), oldest modification first.
+	Changed(ctx context.Context, since *time.Time) ([]connector.RemoteRecord, error)
+	// Create inserts a record and returns its id and modification time.
+	Create(ctx context.Context, fields map[string]any) (connector.RemoteRecord, error)
+	// Update writes fields onto a record and returns its new modification
+	// time.
+	Update(ctx context.Context, id string, fields map[string]any) (connector.RemoteRecord, error)
+}
+
+// New builds the Remote for a connector kind from its config and decrypted
+// credential JSON. It is also how the API validates both before storing them.
+func New(kind string, config, credentials []byte) (Remote, error) {
+	switch kind {
+	case connector.KindNetSuite:
+		return NewNetSuite(config, credentials)
+	default:
+		return nil, fmt.Errorf("unknown connector kind %q", kind)
+	}
+}
+
+// DefaultMapping is the field mapping a connector of kind gets when created
+// without one.
+func DefaultMapping(kind string) []connector.FieldMapping {
+	switch kind {
+	case connector.KindNetSuite:
+		return netSuiteDefaultMapping()
+	default:
+		return nil
+	}
+}
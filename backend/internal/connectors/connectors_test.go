@@ -0,0 +1,184 @@
+package connectors
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/connector"
+	"github.com/trakrf/platform/backend/internal/util/fieldcrypt"
+)
+
+func testVault(t *testing.T) *Vault {
+	t.Helper()
+	v, err := NewVault(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	return v
+}
+
+func TestVault_RoundTripIsOrgBound(t *testing.T) {
+	v := testVault(t)
+	sealed, err := v.Seal(42, []byte(`{"token_secret":"s3cret"}`))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "s3cret")
+
+	plain, err := v.Open(42, sealed)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"token_secret":"s3cret"}`, string(plain))
+
+	_, err = v.Open(43, sealed)
+	assert.ErrorIs(t, err, ErrVaultSealed)
+	_, err = v.Open(42, sealed[:4])
+	assert.ErrorIs(t, err, ErrVaultSealed)
+}
+
+func TestVault_ResealMovesLegacyCredentialsOntoKeyring(t *testing.T) {
+	legacy := testVault(t)
+	sealed, err := legacy.Seal(42, []byte("s3cret"))
+	require.NoError(t, err)
+
+	keys, err := fieldcrypt.NewKeyring([]fieldcrypt.Key{{ID: "k1", Secret: bytes.Repeat([]byte{8}, 32)}})
+	require.NoError(t, err)
+	v := testVault(t)
+	v.keys = keys
+
+	resealed, changed, err := v.Reseal(42, sealed)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.True(t, fieldcrypt.IsEncrypted(string(resealed)))
+	plain, err := v.Open(42, resealed)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", string(plain))
+	_, err = v.Open(43, resealed)
+	assert.ErrorIs(t, err, ErrVaultSealed)
+
+	_, changed, err = v.Reseal(42, resealed)
+	require.NoError(t, err)
+	assert.False(t, changed, "already under the primary key")
+
+	_, err = legacy.Open(42, resealed)
+	assert.ErrorIs(t, err, ErrVaultSealed, "a vault without the keyring cannot open it")
+}
+
+func TestVaultFromEnv(t *testing.T) {
+	t.Setenv("CONNECTOR_VAULT_KEY", "")
+	v, err := VaultFromEnv(nil)
+	require.NoError(t, err)
+	assert.Nil(t, v, "unset key disables connectors")
+
+	t.Setenv("CONNECTOR_VAULT_KEY", "c2hvcnQ=")
+	_, err = VaultFromEnv(nil)
+	assert.Error(t, err, "a short key refuses to boot")
+
+	t.Setenv("CONNECTOR_VAULT_KEY", "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=")
+	v, err = VaultFromEnv(nil)
+	require.NoError(t, err)
+	assert.NotNil(t, v)
+
+	keys, err := fieldcrypt.NewKeyring([]fieldcrypt.Key{{ID: "k1", Secret: bytes.Repeat([]byte{8}, 32)}})
+	require.NoError(t, err)
+	t.Setenv("CONNECTOR_VAULT_KEY", "")
+	v, err = VaultFromEnv(keys)
+	require.NoError(t, err)
+	assert.NotNil(t, v, "the field encryption keyring alone enables connectors")
+}
+
+func TestResolve(t *testing.T) {
+	older := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	cases := []struct {
+		name              string
+		policy            string
+		local, remote     bool
+		localAt, remoteAt time.Time
+		want              Action
+	}{
+		{"unchanged", connector.ConflictRemoteWins, false, false, older, older, ActionNone},
+		{"remote only", connector.ConflictLocalWins, false, true, older, older, ActionPull},
+		{"local only", connector.ConflictRemoteWins, true, false, older, older, ActionPush},
+		{"conflict remote wins", connector.ConflictRemoteWins, true, true, newer, older, ActionPull},
+		{"conflict local wins", connector.ConflictLocalWins, true, true, older, newer, ActionPush},
+		{"conflict newest local", connector.ConflictNewestWins, true, true, newer, older, ActionPush},
+		{"conflict newest remote", connector.ConflictNewestWins, true, true, older, newer, ActionPull},
+		{"conflict newest tie", connector.ConflictNewestWins, true, true, older, older, ActionPull},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Resolve(tc.policy, tc.local, tc.remote, tc.localAt, tc.remoteAt))
+		})
+	}
+}
+
+func TestValidateMapping(t *testing.T) {
+	require.NoError(t, ValidateMapping(DefaultMapping(connector.KindNetSuite)))
+
+	for name, m := range map[string][]connector.FieldMapping{
+		"no external_key":     {{Local: FieldName, Remote: "altname"}},
+		"external_key pushed": {{Local: FieldExternalKey, Remote: "name", Direction: connector.DirectionPush}},
+		"unknown local":       {{Local: FieldExternalKey, Remote: "name"}, {Local: "owner", Remote: "custodian"}},
+		"local twice":         {{Local: FieldExternalKey, Remote: "name"}, {Local: FieldExternalKey, Remote: "altname"}},
+		"remote twice":        {{Local: FieldExternalKey, Remote: "name"}, {Local: FieldName, Remote: "name"}},
+		"negated non-bool":    {{Local: FieldExternalKey, Remote: "!name"}},
+		"empty metadata key":  {{Local: FieldExternalKey, Remote: "name"}, {Local: "metadata.", Remote: "serial"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, ValidateMapping(m))
+		})
+	}
+}
+
+func TestToLocal(t *testing.T) {
+	m := append(DefaultMapping(connector.KindNetSuite),
+		connector.FieldMapping{Local: FieldCostCenter, Remote: "department", Direction: connector.DirectionPull},
+		connector.FieldMapping{Local: "metadata.serial", Remote: "custrecord_assetserialno"},
+		connector.FieldMapping{Local: "metadata.push_only", Remote: "custrecord_x", Direction: connector.DirectionPush},
+	)
+	f := ToLocal(m, map[string]any{
+		"name":                     "FAM00042",
+		"altname":                  "Forklift 3",
+		"custrecord_assetdescr":    nil,
+		"isinactive":               "F",
+		"department":               map[string]any{"id": "7", "refName": "Warehouse"},
+		"custrecord_assetserialno": "SN-1",
+		"custrecord_x":             "ignored",
+	})
+	require.NotNil(t, f.ExternalKey)
+	assert.Equal(t, "FAM00042", *f.ExternalKey)
+	assert.Equal(t, "Forklift 3", *f.Name)
+	assert.Equal(t, "", *f.Description, "a null remote description clears")
+	require.NotNil(t, f.IsActive)
+	assert.True(t, *f.IsActive, "isinactive=F is active")
+	assert.Equal(t, "Warehouse", *f.CostCenter)
+	assert.Equal(t, map[string]any{"serial": "SN-1"}, f.Metadata)
+
+	empty := ToLocal(m, map[string]any{"name": "FAM00043"})
+	assert.Nil(t, empty.Name, "absent remote fields stay unmapped")
+	assert.Nil(t, empty.IsActive)
+}
+
+func TestToRemote(t *testing.T) {
+	cc := "CC-4100"
+	m := append(DefaultMapping(connector.KindNetSuite),
+		connector.FieldMapping{Local: FieldCostCenter, Remote: "department", Direction: connector.DirectionPull},
+		connector.FieldMapping{Local: "metadata.serial", Remote: "custrecord_assetserialno"},
+	)
+	got := ToRemote(m, asset.Asset{
+		ExternalKey: "FAM00042",
+		Name:        "Forklift 3",
+		Description: "Main warehouse",
+		IsActive:    false,
+		CostCenter:  &cc,
+		Metadata:    map[string]any{"serial": "SN-1"},
+	})
+	assert.Equal(t, map[string]any{
+		"name":                     "FAM00042",
+		"altname":                  "Forklift 3",
+		"custrecord_assetdescr":    "Main warehouse",
+		"isinactive":               true,
+		"custrecord_assetserialno": "SN-1",
+	}, got, "pull-only department is not pushed")
+}
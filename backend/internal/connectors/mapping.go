@@ -0,0 +1,178 @@
+package connectors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/connector"
+)
+
+// Mappable asset fields. Anything else must be metadata.<key>.
+const (
+	FieldExternalKey = "external_key"
+	FieldName        = "name"
+	FieldDescription = "description"
+	FieldIsActive    = "is_active"
+	FieldCostCenter  = "cost_center"
+	metadataPrefix   = "metadata."
+)
+
+// ValidateMapping checks a field mapping: known local fields, each local and
+// remote field mapped at most once, and external_key present and pulled, since
+// it is how a first sync matches remote records to existing assets.
+func ValidateMapping(m []connector.FieldMapping) error {
+	locals := map[string]bool{}
+	remotes := map[string]bool{}
+	var key *connector.FieldMapping
+	for i, f := range m {
+		if !isLocalField(f.Local) {
+			return fmt.Errorf("field_mapping[%d]: unknown local field %q", i, f.Local)
+		}
+		remote := strings.TrimPrefix(f.Remote, "!")
+		if remote == "" {
+			return fmt.Errorf("field_mapping[%d]: remote field is required", i)
+		}
+		if strings.HasPrefix(f.Remote, "!") && f.Local != FieldIsActive {
+			return fmt.Errorf("field_mapping[%d]: only is_active may use a negated (!) remote field", i)
+		}
+		if locals[f.Local] {
+			return fmt.Errorf("field_mapping[%d]: local field %q is mapped twice", i, f.Local)
+		}
+		if remotes[remote] {
+			return fmt.Errorf("field_mapping[%d]: remote field %q is mapped twice", i, remote)
+		}
+		locals[f.Local], remotes[remote] = true, true
+		if f.Local == FieldExternalKey {
+			key = &m[i]
+		}
+	}
+	if key == nil || !pulls(*key) {
+		return fmt.Errorf("field_mapping must pull external_key")
+	}
+	return nil
+}
+
+func isLocalField(f string) bool {
+	switch f {
+	case FieldExternalKey, FieldName, FieldDescription, FieldIsActive, FieldCostCenter:
+		return true
+	}
+	return strings.HasPrefix(f, metadataPrefix) && len(f) > len(metadataPrefix)
+}
+
+func pulls(f connector.FieldMapping) bool  { return f.Direction != connector.DirectionPush }
+func pushes(f connector.FieldMapping) bool { return f.Direction != connector.DirectionPull }
+
+// ToLocal maps a remote record's fields onto asset fields. Only pulled
+// mappings apply; remote fields absent from the record stay unmapped.
+func ToLocal(m []connector.FieldMapping, fields map[string]any) connector.AssetFields {
+	var out connector.AssetFields
+	for _, f := range m {
+		if !pulls(f) {
+			continue
+		}
+		negate := strings.HasPrefix(f.Remote, "!")
+		v, ok := fields[strings.TrimPrefix(f.Remote, "!")]
+		if !ok {
+			continue
+		}
+		switch f.Local {
+		case FieldExternalKey:
+			if s := remoteString(v); s != "" {
+				out.ExternalKey = &s
+			}
+		case FieldName:
+			if s := remoteString(v); s != "" {
+				out.Name = &s
+			}
+		case FieldDescription:
+			s := remoteString(v)
+			out.Description = &s
+		case FieldCostCenter:
+			if s := remoteString(v); s != "" {
+				out.CostCenter = &s
+			}
+		case FieldIsActive:
+			if b, ok := remoteBool(v); ok {
+				b = b != negate
+				out.IsActive = &b
+			}
+		default:
+			if out.Metadata == nil {
+				out.Metadata = map[string]any{}
+			}
+			out.Metadata[strings.TrimPrefix(f.Local, metadataPrefix)] = v
+		}
+	}
+	return out
+}
+
+// ToRemote maps an asset onto remote record fields. Only pushed mappings
+// apply; a metadata key the asset does not carry is left off.
+func ToRemote(m []connector.FieldMapping, a asset.Asset) map[string]any {
+	out := map[string]any{}
+	meta, _ := a.Metadata.(map[string]any)
+	for _, f := range m {
+		if !pushes(f) {
+			continue
+		}
+		remote := strings.TrimPrefix(f.Remote, "!")
+		switch f.Local {
+		case FieldExternalKey:
+			out[remote] = a.ExternalKey
+		case FieldName:
+			out[remote] = a.Name
+		case FieldDescription:
+			out[remote] = a.Description
+		case FieldCostCenter:
+			out[remote] = a.CostCenter
+		case FieldIsActive:
+			out[remote] = a.IsActive != strings.HasPrefix(f.Remote, "!")
+		default:
+			if v, ok := meta[strings.TrimPrefix(f.Local, metadataPrefix)]; ok {
+				out[remote] = v
+			}
+		}
+	}
+	return out
+}
+
+// remoteString flattens a remote value to text. Reference fields arrive as
+// {"id": ..., "refName": ...} objects and flatten to their display name.
+func remoteString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case bool:
+		return strconv.FormatBool(t)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	case map[string]any:
+		if s, ok := t["refName"].(string); ok {
+			return s
+		}
+		return remoteString(t["id"])
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+// remoteBool accepts JSON booleans and NetSuite's "T"/"F" strings.
+func remoteBool(v any) (bool, bool) {
+	switch t := v.(type) {
+	case bool:
+		return t, true
+	case string:
+		switch strings.ToLower(t) {
+		case "t", "true":
+			return true, true
+		case "f", "false":
+			return false, true
+		}
+	}
+	return false, false
+}
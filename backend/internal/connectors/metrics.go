@@ -0,0 +1,11 @@
+package connectors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "connector_sync_runs_total",
+	Help: "Connector sync runs, by connector kind and status.",
+}, []string{"kind", "status"}) // succeeded, partial, failed
@@ -0,0 +1,325 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/connector"
+	"github.com/trakrf/platform/backend/internal/util/netguard"
+)
+
+const (
+	// netSuiteFixedAssetType is the Fixed Asset Management bundle's asset
+	// record.
+	netSuiteFixedAssetType = "customrecord_ncfar_asset"
+	netSuitePageSize       = 1000
+	netSuiteTimeout        = 30 * time.Second
+	// netSuiteTimeLayout is what the SuiteQL TO_CHAR below renders.
+	netSuiteTimeLayout = "2006-01-02 15:04:05"
+	// modifiedColumn carries each row's lastmodified in a parseable form;
+	// SuiteQL's default date rendering follows the user's preferences.
+	modifiedColumn = "trakrf_lastmodified"
+)
+
+var (
+	recordTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,98}$`)
+	// accountIDPattern keeps the account id a single host label, since it
+	// becomes part of the SuiteTalk host name.
+	accountIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+)
+
+// NetSuiteConfig is a netsuite connector's non-secret config.
+type NetSuiteConfig struct {
+	// AccountID is the NetSuite account, e.g. 1234567 or 1234567_SB1.
+	AccountID string `json:"account_id"`
+	// RecordType is the asset record to sync; defaults to the Fixed Asset
+	// Management bundle's customrecord_ncfar_asset.
+	RecordType string `json:"record_type,omitempty"`
+	// Timezone is the account's IANA time zone, which SuiteQL timestamps are
+	// rendered in. Defaults to UTC.
+	Timezone string `json:"timezone,omitempty"`
+	// BaseURL overrides the account's SuiteTalk REST host. It must be an
+	// https URL on netsuite.com.
+	BaseURL string `json:"base_url,omitempty"`
+}
+
+// NetSuiteCredentials are token-based authentication (OAuth 1.0a) keys from a
+// NetSuite integration record and access token.
+type NetSuiteCredentials struct {
+	ConsumerKey    string `json:"consumer_key"`
+	ConsumerSecret string `json:"consumer_secret"`
+	TokenID        string `json:"token_id"`
+	TokenSecret    string `json:"token_secret"`
+}
+
+// NetSuite reads changed records through SuiteQL and writes through the REST
+// record API, signing every request with token-based authentication.
+type NetSuite struct {
+	baseURL    string
+	realm      string
+	recordType string
+	loc        *time.Location
+	creds      NetSuiteCredentials
+	client     *http.Client
+	now        func() time.Time
+}
+
+// NewNetSuite validates a netsuite connector's config and credentials.
+func NewNetSuite(config, credentials []byte) (*NetSuite, error) {
+	var cfg NetSuiteConfig
+	if err := json.Unmarshal(config, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid netsuite config: %w", err)
+	}
+	var creds NetSuiteCredentials
+	if err := json.Unmarshal(credentials, &creds); err != nil {
+		return nil, fmt.Errorf("invalid netsuite credentials: %w", err)
+	}
+	if cfg.AccountID == "" {
+		return nil, fmt.Errorf("netsuite config requires account_id")
+	}
+	if !accountIDPattern.MatchString(cfg.AccountID) {
+		return nil, fmt.Errorf("netsuite account_id %q is not an account id", cfg.AccountID)
+	}
+	if creds.ConsumerKey == "" || creds.ConsumerSecret == "" || creds.TokenID == "" || creds.TokenSecret == "" {
+		return nil, fmt.Errorf("netsuite credentials require consumer_key, consumer_secret, token_id and token_secret")
+	}
+	if cfg.RecordType == "" {
+		cfg.RecordType = netSuiteFixedAssetType
+	}
+	if !recordTypePattern.MatchString(cfg.RecordType) {
+		return nil, fmt.Errorf("netsuite record_type %q is not a record type id", cfg.RecordType)
+	}
+	loc := time.UTC
+	if cfg.Timezone != "" {
+		l, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("netsuite timezone: %w", err)
+		}
+		loc = l
+	}
+	base := cfg.BaseURL
+	if base == "" {
+		host := strings.ReplaceAll(strings.ToLower(cfg.AccountID), "_", "-")
+		base = "https://" + host + ".suitetalk.api.netsuite.com"
+	} else if !netSuiteHost(base) {
+		return nil, fmt.Errorf("netsuite base_url must be an https URL on netsuite.com")
+	}
+	return &NetSuite{
+		baseURL:    strings.TrimRight(base, "/"),
+		realm:      strings.ToUpper(strings.ReplaceAll(cfg.AccountID, "-", "_")),
+		recordType: cfg.RecordType,
+		loc:        loc,
+		creds:      creds,
+		client:     &http.Client{Timeout: netSuiteTimeout, Transport: netguard.NewTransport()},
+		now:        time.Now,
+	}, nil
+}
+
+// netSuiteHost reports whether base is an https URL on netsuite.com, where
+// the sync job may send the connector's credentials.
+func netSuiteHost(base string) bool {
+	u, err := url.Parse(base)
+	if err != nil || u.Scheme != "https" || u.User != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "netsuite.com" || strings.HasSuffix(host, ".netsuite.com")
+}
+
+func netSuiteDefaultMapping() []connector.FieldMapping {
+	return []connector.FieldMapping{
+		{Local: FieldExternalKey, Remote: "name", Direction: connector.DirectionBoth},
+		{Local: FieldName, Remote: "altname", Direction: connector.DirectionBoth},
+		{Local: FieldDescription, Remote: "custrecord_assetdescr", Direction: connector.DirectionBoth},
+		{Local: FieldIsActive, Remote: "!isinactive", Direction: connector.DirectionBoth},
+	}
+}
+
+// Changed pages through SuiteQL for records modified after since.
+func (n *NetSuite) Changed(ctx context.Context, since *time.Time) ([]connector.RemoteRecord, error) {
+	where := ""
+	if since != nil {
+		where = fmt.Sprintf(" WHERE t.lastmodified > TO_TIMESTAMP('%s', 'YYYY-MM-DD HH24:MI:SS')",
+			since.In(n.loc).Format(netSuiteTimeLayout))
+	}
+	q := fmt.Sprintf("SELECT t.*, TO_CHAR(t.lastmodified, 'YYYY-MM-DD HH24:MI:SS') AS %s FROM %s t%s ORDER BY t.lastmodified, t.id",
+		modifiedColumn, n.recordType, where)
+
+	var out []connector.RemoteRecord
+	for offset := 0; ; offset += netSuitePageSize {
+		page, more, err := n.suiteQL(ctx, q, offset)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, page...)
+		if !more {
+			return out, nil
+		}
+	}
+}
+
+// Create POSTs a new record; NetSuite answers 204 with its URL in Location.
+func (n *NetSuite) Create(ctx context.Context, fields map[string]any) (connector.RemoteRecord, error) {
+	resp, err := n.do(ctx, http.MethodPost, n.baseURL+"/services/rest/record/v1/"+n.recordType, fields)
+	if err != nil {
+		return connector.RemoteRecord{}, err
+	}
+	resp.Body.Close()
+	id := path.Base(resp.Header.Get("Location"))
+	if id == "" || id == "." || id == "/" {
+		return connector.RemoteRecord{}, fmt.Errorf("netsuite create returned no record location")
+	}
+	return n.modified(ctx, id)
+}
+
+// Update PATCHes fields onto a record.
+func (n *NetSuite) Update(ctx context.Context, id string, fields map[string]any) (connector.RemoteRecord, error) {
+	resp, err := n.do(ctx, http.MethodPatch, n.baseURL+"/services/rest/record/v1/"+n.recordType+"/"+url.PathEscape(id), fields)
+	if err != nil {
+		return connector.RemoteRecord{}, err
+	}
+	resp.Body.Close()
+	return n.modified(ctx, id)
+}
+
+// modified reads back a record's lastmodified after a write, so the next pull
+// does not mistake our own write for a remote change.
+func (n *NetSuite) modified(ctx context.Context, id string) (connector.RemoteRecord, error) {
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return connector.RemoteRecord{}, fmt.Errorf("netsuite record id %q is not numeric", id)
+	}
+	q := fmt.Sprintf("SELECT t.id, TO_CHAR(t.lastmodified, 'YYYY-MM-DD HH24:MI:SS') AS %s FROM %s t WHERE t.id = %s",
+		modifiedColumn, n.recordType, id)
+	page, _, err := n.suiteQL(ctx, q, 0)
+	if err != nil {
+		return connector.RemoteRecord{}, err
+	}
+	if len(page) == 0 {
+		return connector.RemoteRecord{}, fmt.Errorf("netsuite record %s not found after write", id)
+	}
+	return page[0], nil
+}
+
+func (n *NetSuite) suiteQL(ctx context.Context, q string, offset int) ([]connector.RemoteRecord, bool, error) {
+	u := fmt.Sprintf("%s/services/rest/query/v1/suiteql?limit=%d&offset=%d", n.baseURL, netSuitePageSize, offset)
+	resp, err := n.do(ctx, http.MethodPost, u, map[string]string{"q": q})
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Items   []map[string]any `json:"items"`
+		HasMore bool             `json:"hasMore"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, false, fmt.Errorf("decode netsuite suiteql response: %w", err)
+	}
+	out := make([]connector.RemoteRecord, 0, len(body.Items))
+	for _, item := range body.Items {
+		rec := connector.RemoteRecord{ID: remoteString(item["id"]), Fields: item}
+		if s, ok := item[modifiedColumn].(string); ok {
+			if t, err := time.ParseInLocation(netSuiteTimeLayout, s, n.loc); err == nil {
+				rec.UpdatedAt = t
+			}
+		}
+		delete(item, modifiedColumn)
+		delete(item, "links")
+		out = append(out, rec)
+	}
+	return out, body.HasMore, nil
+}
+
+// do sends a signed JSON request and returns the response on 2xx. The caller
+// closes the body.
+func (n *NetSuite) do(ctx context.Context, method, rawURL string, payload any) (*http.Response, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", n.authorization(method, req.URL, hex.EncodeToString(nonce), n.now()))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "transient")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("netsuite %s: %w", method, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("netsuite %s returned %d: %s", method, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return resp, nil
+}
+
+// authorization builds the OAuth 1.0a (HMAC-SHA256) header NetSuite
+// token-based authentication expects.
+func (n *NetSuite) authorization(method string, u *url.URL, nonce string, at time.Time) string {
+	oauth := map[string]string{
+		"oauth_consumer_key":     n.creds.ConsumerKey,
+		"oauth_token":            n.creds.TokenID,
+		"oauth_signature_method": "HMAC-SHA256",
+		"oauth_timestamp":        strconv.FormatInt(at.Unix(), 10),
+		"oauth_nonce":            nonce,
+		"oauth_version":          "1.0",
+	}
+
+	var params []string
+	for k, v := range oauth {
+		params = append(params, oauthEscape(k)+"="+oauthEscape(v))
+	}
+	for k, vs := range u.Query() {
+		for _, v := range vs {
+			params = append(params, oauthEscape(k)+"="+oauthEscape(v))
+		}
+	}
+	sort.Strings(params)
+	base := strings.ToUpper(method) + "&" +
+		oauthEscape(u.Scheme+"://"+u.Host+u.EscapedPath()) + "&" +
+		oauthEscape(strings.Join(params, "&"))
+
+	mac := hmac.New(sha256.New, []byte(oauthEscape(n.creds.ConsumerSecret)+"&"+oauthEscape(n.creds.TokenSecret)))
+	mac.Write([]byte(base))
+	oauth["oauth_signature"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	keys := make([]string, 0, len(oauth))
+	for k := range oauth {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := []string{`realm="` + n.realm + `"`}
+	for _, k := range keys {
+		parts = append(parts, k+`="`+oauthEscape(oauth[k])+`"`)
+	}
+	return "OAuth " + strings.Join(parts, ",")
+}
+
+// oauthEscape is RFC 3986 percent-encoding, as OAuth 1.0a requires.
+func oauthEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
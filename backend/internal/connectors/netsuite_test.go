@@ -0,0 +1,133 @@
+package connectors
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNetSuiteCreds = `{"consumer_key":"ck","consumer_secret":"cs","token_id":"tk","token_secret":"ts"}`
+
+func TestNewNetSuite_Validates(t *testing.T) {
+	for name, tc := range map[string]struct{ config, creds string }{
+		"missing account":   {`{}`, testNetSuiteCreds},
+		"missing secret":    {`{"account_id":"123"}`, `{"consumer_key":"ck","token_id":"tk","token_secret":"ts"}`},
+		"bad record type":   {`{"account_id":"123","record_type":"x; DROP"}`, testNetSuiteCreds},
+		"bad timezone":      {`{"account_id":"123","timezone":"Mars/Olympus"}`, testNetSuiteCreds},
+		"relative base url": {`{"account_id":"123","base_url":"/rest"}`, testNetSuiteCreds},
+		"http base url":     {`{"account_id":"123","base_url":"http://123.suitetalk.api.netsuite.com"}`, testNetSuiteCreds},
+		"foreign base url":  {`{"account_id":"123","base_url":"https://169.254.169.254"}`, testNetSuiteCreds},
+		"lookalike host":    {`{"account_id":"123","base_url":"https://evilnetsuite.com"}`, testNetSuiteCreds},
+		"userinfo base url": {`{"account_id":"123","base_url":"https://x.netsuite.com@evil.example"}`, testNetSuiteCreds},
+		"account with path": {`{"account_id":"evil.example/x"}`, testNetSuiteCreds},
+		"account with at":   {`{"account_id":"x@evil.example#"}`, testNetSuiteCreds},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewNetSuite([]byte(tc.config), []byte(tc.creds))
+			assert.Error(t, err)
+		})
+	}
+
+	n, err := NewNetSuite([]byte(`{"account_id":"1234567_SB1"}`), []byte(testNetSuiteCreds))
+	require.NoError(t, err)
+	assert.Equal(t, "https://1234567-sb1.suitetalk.api.netsuite.com", n.baseURL)
+	assert.Equal(t, "1234567_SB1", n.realm)
+	assert.Equal(t, netSuiteFixedAssetType, n.recordType)
+
+	n, err = NewNetSuite([]byte(`{"account_id":"123","base_url":"https://123.restlets.api.netsuite.com/"}`), []byte(testNetSuiteCreds))
+	require.NoError(t, err)
+	assert.Equal(t, "https://123.restlets.api.netsuite.com", n.baseURL)
+}
+
+// testNetSuite points a connector at a local test server, which base_url
+// validation and the client's transport would otherwise refuse.
+func testNetSuite(t *testing.T, config string, srv *httptest.Server) *NetSuite {
+	t.Helper()
+	n, err := NewNetSuite([]byte(config), []byte(testNetSuiteCreds))
+	require.NoError(t, err)
+	n.baseURL = srv.URL
+	n.client = srv.Client()
+	return n
+}
+
+func TestNetSuite_ChangedPagesSuiteQL(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), `OAuth realm="123",oauth_consumer_key="ck"`))
+		assert.Contains(t, r.Header.Get("Authorization"), `oauth_signature_method="HMAC-SHA256"`)
+		assert.Equal(t, "/services/rest/query/v1/suiteql", r.URL.Path)
+		var body struct{ Q string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, body.Q)
+		if r.URL.Query().Get("offset") == "0" {
+			io.WriteString(w, `{"items":[{"id":"7","name":"FAM7","trakrf_lastmodified":"2026-03-01 09:30:00","links":[]}],"hasMore":true}`)
+			return
+		}
+		io.WriteString(w, `{"items":[{"id":"8","name":"FAM8","trakrf_lastmodified":"2026-03-01 10:00:00"}],"hasMore":false}`)
+	}))
+	defer srv.Close()
+
+	n := testNetSuite(t, `{"account_id":"123","timezone":"America/New_York"}`, srv)
+
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	recs, err := n.Changed(t.Context(), &since)
+	require.NoError(t, err)
+
+	require.Len(t, queries, 2)
+	assert.Contains(t, queries[0], "FROM customrecord_ncfar_asset t WHERE t.lastmodified > TO_TIMESTAMP('2026-03-01 07:00:00'",
+		"the cursor is rendered in the account time zone")
+	require.Len(t, recs, 2)
+	assert.Equal(t, "7", recs[0].ID)
+	assert.Equal(t, time.Date(2026, 3, 1, 14, 30, 0, 0, time.UTC), recs[0].UpdatedAt.UTC())
+	assert.Equal(t, map[string]any{"id": "7", "name": "FAM7"}, recs[0].Fields)
+}
+
+func TestNetSuite_CreateReadsBackModified(t *testing.T) {
+	var created map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/services/rest/record/v1/customrecord_ncfar_asset":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.Header().Set("Location", "https://x/services/rest/record/v1/customrecord_ncfar_asset/99")
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/services/rest/query/v1/suiteql":
+			io.WriteString(w, `{"items":[{"id":"99","trakrf_lastmodified":"2026-03-02 08:00:00"}],"hasMore":false}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	n := testNetSuite(t, `{"account_id":"123"}`, srv)
+
+	rec, err := n.Create(t.Context(), map[string]any{"name": "TOOL-9"})
+	require.NoError(t, err)
+	assert.Equal(t, "TOOL-9", created["name"])
+	assert.Equal(t, "99", rec.ID)
+	assert.Equal(t, time.Date(2026, 3, 2, 8, 0, 0, 0, time.UTC), rec.UpdatedAt)
+}
+
+func TestNetSuite_ErrorStatusSurfacesDetail(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		io.WriteString(w, `{"o:errorDetails":[{"detail":"Invalid login attempt."}]}`)
+	}))
+	defer srv.Close()
+
+	n := testNetSuite(t, `{"account_id":"123"}`, srv)
+	_, err := n.Update(t.Context(), "5", map[string]any{"altname": "x"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+	assert.Contains(t, err.Error(), "Invalid login attempt.")
+}
+
+func TestOAuthEscape(t *testing.T) {
+	assert.Equal(t, "a%20b%2Bc~d%2F", oauthEscape("a b+c~d/"))
+}
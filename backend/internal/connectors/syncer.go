@@ -0,0 +1,277 @@
+package connectors
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/models/connector"
+)
+
+const (
+	claimBatch = 5
+	// claimLease must outlast a full sync of a large register so a slow run
+	// is not re-claimed by another replica mid-flight.
+	claimLease = 30 * time.Minute
+	// maxErrorLen bounds the error text kept on a run.
+	maxErrorLen = 1024
+)
+
+// syncStore is the storage surface the syncer needs; *storage.Storage
+// satisfies it.
+type syncStore interface {
+	ClaimDueConnectors(ctx context.Context, limit int, lease time.Duration) ([]connector.Connector, error)
+	ListConnectorAssets(ctx context.Context, orgID, connectorID int) ([]connector.LocalAsset, error)
+	ApplyConnectorPull(ctx context.Context, orgID, connectorID int, assetID *int, f connector.AssetFields, rec connector.RemoteRecord) (int, error)
+	SaveConnectorLink(ctx context.Context, l connector.Link) error
+	FinishConnectorSync(ctx context.Context, c connector.Connector, run connector.SyncRun, cursor *time.Time) error
+}
+
+// Syncer is the connector sync job.
+type Syncer struct {
+	store     syncStore
+	vault     *Vault
+	log       zerolog.Logger
+	now       func() time.Time
+	newRemote func(kind string, config, credentials []byte) (Remote, error)
+}
+
+// NewSyncer builds the sync job. vault opens the stored credentials.
+func NewSyncer(store syncStore, vault *Vault, log *zerolog.Logger) *Syncer {
+	return &Syncer{
+		store:     store,
+		vault:     vault,
+		log:       log.With().Str("component", "connectors").Logger(),
+		now:       time.Now,
+		newRemote: New,
+	}
+}
+
+// Run is the job run: claim due connectors and sync each until none are due.
+// A connector's own failures are recorded on its run, not returned; only
+// storage errors fail the job.
+func (s *Syncer) Run(ctx context.Context) error {
+	for {
+		batch, err := s.store.ClaimDueConnectors(ctx, claimBatch, claimLease)
+		if err != nil {
+			return err
+		}
+		for _, c := range batch {
+			run, cursor := s.sync(ctx, c)
+			metricRuns.WithLabelValues(c.Kind, run.Status).Inc()
+			if run.Status != connector.RunSucceeded {
+				s.log.Warn().Int("connector_id", c.ID).Int("org_id", c.OrgID).Str("status", run.Status).
+					Int("failures", run.Failures).Str("error", deref(run.Error)).Msg("connector sync did not fully succeed")
+			}
+			if err := s.store.FinishConnectorSync(ctx, c, run, cursor); err != nil {
+				return err
+			}
+		}
+		if len(batch) < claimBatch {
+			return nil
+		}
+	}
+}
+
+// syncState is one run's bookkeeping.
+type syncState struct {
+	c      connector.Connector
+	remote Remote
+	run    connector.SyncRun
+	// handled holds asset ids already reconciled this run, so the push pass
+	// skips them.
+	handled map[int]bool
+}
+
+func (st *syncState) fail(err error) {
+	st.run.Failures++
+	msg := err.Error()
+	if len(msg) > maxErrorLen {
+		msg = msg[:maxErrorLen]
+	}
+	st.run.Error = &msg
+}
+
+// sync runs one connector: pull remote changes, then push local ones. It
+// returns the run and the remote cursor to store (nil to keep the old one).
+func (s *Syncer) sync(ctx context.Context, c connector.Connector) (connector.SyncRun, *time.Time) {
+	st := &syncState{c: c, handled: map[int]bool{}}
+	st.run.StartedAt = s.now()
+	finish := func(cursor *time.Time) (connector.SyncRun, *time.Time) {
+		st.run.FinishedAt = s.now()
+		switch {
+		case st.run.Failures == 0:
+			st.run.Status = connector.RunSucceeded
+		case st.run.Pulled+st.run.Pushed > 0:
+			st.run.Status = connector.RunPartial
+		default:
+			st.run.Status = connector.RunFailed
+		}
+		return st.run, cursor
+	}
+
+	creds, err := s.vault.Open(c.OrgID, c.Credentials)
+	if err == nil {
+		st.remote, err = s.newRemote(c.Kind, c.Config, creds)
+	}
+	if err != nil {
+		st.fail(err)
+		return finish(nil)
+	}
+	locals, err := s.store.ListConnectorAssets(ctx, c.OrgID, c.ID)
+	if err != nil {
+		st.fail(err)
+		return finish(nil)
+	}
+
+	var cursor *time.Time
+	if c.Direction != connector.DirectionPush {
+		cursor, err = s.pull(ctx, st, locals)
+		if err != nil {
+			st.fail(err)
+			return finish(nil)
+		}
+	}
+	if c.Direction != connector.DirectionPull {
+		s.push(ctx, st, locals)
+	}
+	return finish(cursor)
+}
+
+// pull reconciles every remote record changed since the cursor. A record
+// pairs with the asset linked to it, else with an unlinked asset of the same
+// external_key, else creates an asset. It returns the new cursor, or nil if
+// any record failed to apply, so the next run retries from the old one.
+func (s *Syncer) pull(ctx context.Context, st *syncState, locals []connector.LocalAsset) (*time.Time, error) {
+	records, err := st.remote.Changed(ctx, st.c.RemoteCursor)
+	if err != nil {
+		return nil, fmt.Errorf("read remote changes: %w", err)
+	}
+
+	byRemote := map[string]*connector.LocalAsset{}
+	byKey := map[string]*connector.LocalAsset{}
+	for i := range locals {
+		la := &locals[i]
+		if la.Link != nil {
+			byRemote[la.Link.RemoteID] = la
+		} else {
+			byKey[la.Asset.ExternalKey] = la
+		}
+	}
+
+	cursor := st.c.RemoteCursor
+	failed := false
+	for _, rec := range records {
+		if cursor == nil || rec.UpdatedAt.After(*cursor) {
+			at := rec.UpdatedAt
+			cursor = &at
+		}
+		fields := ToLocal(st.c.FieldMapping, rec.Fields)
+
+		la := byRemote[rec.ID]
+		if la == nil && fields.ExternalKey != nil {
+			la = byKey[*fields.ExternalKey]
+			delete(byKey, *fields.ExternalKey)
+		}
+		if la == nil {
+			if _, err := s.store.ApplyConnectorPull(ctx, st.c.OrgID, st.c.ID, nil, fields, rec); err != nil {
+				st.fail(err)
+				failed = true
+				continue
+			}
+			st.run.Pulled++
+			continue
+		}
+
+		st.handled[la.Asset.ID] = true
+		// An asset meeting its record for the first time counts as changed
+		// on both sides, so the conflict policy picks the starting values.
+		localChanged, remoteChanged := true, true
+		if la.Link != nil {
+			localChanged = la.Asset.UpdatedAt.After(la.Link.LocalSyncedAt)
+			remoteChanged = rec.UpdatedAt.After(la.Link.RemoteUpdatedAt)
+		}
+		if st.c.Direction == connector.DirectionPull {
+			localChanged = false
+		}
+		if localChanged && remoteChanged {
+			st.run.Conflicts++
+		}
+
+		switch Resolve(st.c.ConflictPolicy, localChanged, remoteChanged, la.Asset.UpdatedAt, rec.UpdatedAt) {
+		case ActionPull:
+			if _, err := s.store.ApplyConnectorPull(ctx, st.c.OrgID, st.c.ID, &la.Asset.ID, fields, rec); err != nil {
+				st.fail(err)
+				failed = true
+				continue
+			}
+			st.run.Pulled++
+		case ActionPush:
+			if err := s.pushOne(ctx, st, *la, &rec.ID); err != nil {
+				st.fail(err)
+				continue
+			}
+			st.run.Pushed++
+		}
+	}
+	if failed {
+		return nil, nil
+	}
+	return cursor, nil
+}
+
+// push sends assets changed since their last sync (and assets never synced)
+// that the pull pass did not already reconcile.
+func (s *Syncer) push(ctx context.Context, st *syncState, locals []connector.LocalAsset) {
+	for _, la := range locals {
+		if st.handled[la.Asset.ID] {
+			continue
+		}
+		var remoteID *string
+		if la.Link != nil {
+			if !la.Asset.UpdatedAt.After(la.Link.LocalSyncedAt) {
+				continue
+			}
+			remoteID = &la.Link.RemoteID
+		}
+		if err := s.pushOne(ctx, st, la, remoteID); err != nil {
+			st.fail(err)
+			continue
+		}
+		st.run.Pushed++
+	}
+}
+
+// pushOne writes an asset to its remote record (creating one when remoteID
+// is nil) and records the link.
+func (s *Syncer) pushOne(ctx context.Context, st *syncState, la connector.LocalAsset, remoteID *string) error {
+	fields := ToRemote(st.c.FieldMapping, la.Asset)
+	var (
+		rec connector.RemoteRecord
+		err error
+	)
+	if remoteID == nil {
+		rec, err = st.remote.Create(ctx, fields)
+	} else {
+		rec, err = st.remote.Update(ctx, *remoteID, fields)
+	}
+	if err != nil {
+		return fmt.Errorf("push asset %s: %w", la.Asset.ExternalKey, err)
+	}
+	return s.store.SaveConnectorLink(ctx, connector.Link{
+		ConnectorID:     st.c.ID,
+		AssetID:         la.Asset.ID,
+		RemoteID:        rec.ID,
+		RemoteUpdatedAt: rec.UpdatedAt,
+		LocalSyncedAt:   la.Asset.UpdatedAt,
+	})
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,248 @@
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/connector"
+)
+
+type pulledWrite struct {
+	assetID *int
+	fields  connector.AssetFields
+	rec     connector.RemoteRecord
+}
+
+type fakeSyncStore struct {
+	due      []connector.Connector
+	locals   []connector.LocalAsset
+	pulled   []pulledWrite
+	links    []connector.Link
+	runs     []connector.SyncRun
+	cursors  []*time.Time
+	nextID   int
+	applyErr error
+}
+
+func (f *fakeSyncStore) ClaimDueConnectors(_ context.Context, limit int, _ time.Duration) ([]connector.Connector, error) {
+	n := min(limit, len(f.due))
+	out := f.due[:n]
+	f.due = f.due[n:]
+	return out, nil
+}
+
+func (f *fakeSyncStore) ListConnectorAssets(context.Context, int, int) ([]connector.LocalAsset, error) {
+	return f.locals, nil
+}
+
+func (f *fakeSyncStore) ApplyConnectorPull(_ context.Context, _, _ int, assetID *int, fields connector.AssetFields, rec connector.RemoteRecord) (int, error) {
+	if f.applyErr != nil {
+		return 0, f.applyErr
+	}
+	f.pulled = append(f.pulled, pulledWrite{assetID: assetID, fields: fields, rec: rec})
+	if assetID != nil {
+		return *assetID, nil
+	}
+	f.nextID++
+	return f.nextID, nil
+}
+
+func (f *fakeSyncStore) SaveConnectorLink(_ context.Context, l connector.Link) error {
+	f.links = append(f.links, l)
+	return nil
+}
+
+func (f *fakeSyncStore) FinishConnectorSync(_ context.Context, _ connector.Connector, run connector.SyncRun, cursor *time.Time) error {
+	f.runs = append(f.runs, run)
+	f.cursors = append(f.cursors, cursor)
+	return nil
+}
+
+type fakeRemote struct {
+	changed []connector.RemoteRecord
+	since   *time.Time
+	created []map[string]any
+	updated map[string]map[string]any
+	at      time.Time
+}
+
+func (r *fakeRemote) Changed(_ context.Context, since *time.Time) ([]connector.RemoteRecord, error) {
+	r.since = since
+	return r.changed, nil
+}
+
+func (r *fakeRemote) Create(_ context.Context, fields map[string]any) (connector.RemoteRecord, error) {
+	r.created = append(r.created, fields)
+	return connector.RemoteRecord{ID: "new-" + strconv.Itoa(len(r.created)), UpdatedAt: r.at}, nil
+}
+
+func (r *fakeRemote) Update(_ context.Context, id string, fields map[string]any) (connector.RemoteRecord, error) {
+	r.updated[id] = fields
+	return connector.RemoteRecord{ID: id, UpdatedAt: r.at}, nil
+}
+
+var (
+	t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	t1 = t0.Add(time.Hour)
+	t2 = t0.Add(2 * time.Hour)
+)
+
+func testSyncer(t *testing.T, store *fakeSyncStore, remote *fakeRemote) (*Syncer, connector.Connector) {
+	t.Helper()
+	v := testVault(t)
+	sealed, err := v.Seal(1, []byte(`{}`))
+	require.NoError(t, err)
+	log := zerolog.New(io.Discard)
+	s := NewSyncer(store, v, &log)
+	s.now = func() time.Time { return t2 }
+	s.newRemote = func(string, []byte, []byte) (Remote, error) { return remote, nil }
+	c := connector.Connector{
+		ID:             10,
+		OrgID:          1,
+		Kind:           connector.KindNetSuite,
+		Config:         json.RawMessage(`{}`),
+		Credentials:    sealed,
+		FieldMapping:   DefaultMapping(connector.KindNetSuite),
+		Direction:      connector.DirectionBoth,
+		ConflictPolicy: connector.ConflictRemoteWins,
+	}
+	return s, c
+}
+
+func local(id int, key string, updatedAt time.Time, link *connector.Link) connector.LocalAsset {
+	return connector.LocalAsset{
+		Asset: asset.Asset{ID: id, ExternalKey: key, Name: key, IsActive: true, UpdatedAt: updatedAt},
+		Link:  link,
+	}
+}
+
+func record(id, key string, at time.Time) connector.RemoteRecord {
+	return connector.RemoteRecord{ID: id, UpdatedAt: at, Fields: map[string]any{"name": key, "altname": "Remote " + key}}
+}
+
+func TestSync_FirstRunMatchesCreatesAndPushes(t *testing.T) {
+	store := &fakeSyncStore{nextID: 100, locals: []connector.LocalAsset{
+		local(1, "FAM1", t1, nil),   // matches remote 501 by external_key
+		local(2, "TOOL-9", t1, nil), // only local: created remotely
+	}}
+	remote := &fakeRemote{at: t2, updated: map[string]map[string]any{}, changed: []connector.RemoteRecord{
+		record("501", "FAM1", t0),
+		record("502", "FAM2", t1), // only remote: created locally
+	}}
+	s, c := testSyncer(t, store, remote)
+	store.due = []connector.Connector{c}
+
+	require.NoError(t, s.Run(context.Background()))
+
+	require.Len(t, store.runs, 1)
+	run := store.runs[0]
+	assert.Equal(t, connector.RunSucceeded, run.Status)
+	assert.Equal(t, 2, run.Pulled)
+	assert.Equal(t, 1, run.Pushed)
+	assert.Equal(t, 1, run.Conflicts, "a first pairing is settled by the conflict policy")
+
+	require.Len(t, store.pulled, 2)
+	require.NotNil(t, store.pulled[0].assetID)
+	assert.Equal(t, 1, *store.pulled[0].assetID, "remote_wins pulls onto the matched asset")
+	assert.Nil(t, store.pulled[1].assetID, "unmatched record creates an asset")
+
+	require.Len(t, remote.created, 1)
+	assert.Equal(t, "TOOL-9", remote.created[0]["name"])
+	require.Len(t, store.links, 1)
+	assert.Equal(t, connector.Link{ConnectorID: 10, AssetID: 2, RemoteID: "new-1", RemoteUpdatedAt: t2, LocalSyncedAt: t1}, store.links[0])
+
+	require.NotNil(t, store.cursors[0])
+	assert.Equal(t, t1, *store.cursors[0], "cursor advances to the newest remote change")
+}
+
+func TestSync_LinkedChanges(t *testing.T) {
+	link := func(assetID int, remoteID string) *connector.Link {
+		return &connector.Link{ConnectorID: 10, AssetID: assetID, RemoteID: remoteID, RemoteUpdatedAt: t0, LocalSyncedAt: t0}
+	}
+	store := &fakeSyncStore{locals: []connector.LocalAsset{
+		local(1, "A", t0, link(1, "r1")), // remote changed only
+		local(2, "B", t1, link(2, "r2")), // local changed only
+		local(3, "C", t2, link(3, "r3")), // both changed
+		local(4, "D", t0, link(4, "r4")), // unchanged
+	}}
+	remote := &fakeRemote{at: t2, updated: map[string]map[string]any{}, changed: []connector.RemoteRecord{
+		record("r1", "A", t1),
+		record("r3", "C", t1),
+	}}
+	s, c := testSyncer(t, store, remote)
+	c.ConflictPolicy = connector.ConflictLocalWins
+	c.RemoteCursor = &t0
+	store.due = []connector.Connector{c}
+
+	require.NoError(t, s.Run(context.Background()))
+
+	assert.Equal(t, &t0, remote.since)
+	run := store.runs[0]
+	assert.Equal(t, 1, run.Pulled)
+	assert.Equal(t, 2, run.Pushed)
+	assert.Equal(t, 1, run.Conflicts)
+	require.Len(t, store.pulled, 1)
+	assert.Equal(t, "r1", store.pulled[0].rec.ID)
+	assert.Contains(t, remote.updated, "r2")
+	assert.Contains(t, remote.updated, "r3", "local_wins pushes the conflicting asset")
+	assert.NotContains(t, remote.updated, "r4")
+	assert.Empty(t, remote.created)
+}
+
+func TestSync_PullOnlyNeverPushes(t *testing.T) {
+	store := &fakeSyncStore{locals: []connector.LocalAsset{
+		local(1, "A", t2, &connector.Link{ConnectorID: 10, AssetID: 1, RemoteID: "r1", RemoteUpdatedAt: t0, LocalSyncedAt: t0}),
+		local(2, "B", t1, nil),
+	}}
+	remote := &fakeRemote{at: t2, updated: map[string]map[string]any{}, changed: []connector.RemoteRecord{record("r1", "A", t1)}}
+	s, c := testSyncer(t, store, remote)
+	c.Direction = connector.DirectionPull
+	c.ConflictPolicy = connector.ConflictLocalWins
+	store.due = []connector.Connector{c}
+
+	require.NoError(t, s.Run(context.Background()))
+
+	run := store.runs[0]
+	assert.Equal(t, 1, run.Pulled)
+	assert.Zero(t, run.Pushed)
+	assert.Zero(t, run.Conflicts, "local edits do not conflict when nothing is pushed")
+	assert.Empty(t, remote.created)
+	assert.Empty(t, remote.updated)
+}
+
+func TestSync_FailedPullKeepsCursor(t *testing.T) {
+	store := &fakeSyncStore{applyErr: assert.AnError}
+	remote := &fakeRemote{at: t2, updated: map[string]map[string]any{}, changed: []connector.RemoteRecord{record("r1", "A", t1)}}
+	s, c := testSyncer(t, store, remote)
+	store.due = []connector.Connector{c}
+
+	require.NoError(t, s.Run(context.Background()))
+
+	run := store.runs[0]
+	assert.Equal(t, connector.RunFailed, run.Status)
+	assert.Equal(t, 1, run.Failures)
+	require.NotNil(t, run.Error)
+	assert.Nil(t, store.cursors[0], "a record that failed to apply is retried next run")
+}
+
+func TestSync_UnreadableCredentialsFailRun(t *testing.T) {
+	store := &fakeSyncStore{}
+	s, c := testSyncer(t, store, &fakeRemote{})
+	c.OrgID = 2 // sealed for org 1
+	store.due = []connector.Connector{c}
+
+	require.NoError(t, s.Run(context.Background()))
+
+	require.Len(t, store.runs, 1)
+	assert.Equal(t, connector.RunFailed, store.runs[0].Status)
+	assert.Contains(t, *store.runs[0].Error, "cannot be decrypted")
+}
@@ -0,0 +1,135 @@
+package connectors
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/util/fieldcrypt"
+)
+
+// ErrVaultSealed is returned when sealed credentials cannot be opened: the
+// vault key changed, or the ciphertext was moved to another org.
+var ErrVaultSealed = stderrors.New("connector credentials cannot be decrypted with the configured vault key")
+
+// Vault seals connector credentials with AES-256-GCM before they are stored.
+// The org id is bound in as additional data, so ciphertext copied onto
+// another org's connector fails to open.
+//
+// With a field encryption keyring the vault seals under the keyring's
+// primary key, in fieldcrypt's format, so credentials rotate with the other
+// encrypted columns. Credentials sealed under CONNECTOR_VAULT_KEY still open
+// until Reseal has moved them onto the keyring.
+type Vault struct {
+	aead cipher.AEAD
+	keys *fieldcrypt.Keyring
+}
+
+// NewVault builds a vault from a 32-byte key.
+func NewVault(key []byte) (*Vault, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("vault key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Vault{aead: aead}, nil
+}
+
+// VaultFromEnv reads CONNECTOR_VAULT_KEY, a base64-encoded 32-byte key, and
+// pairs it with keys, the field encryption keyring (nil when
+// FIELD_ENCRYPTION_KEYS is unset). With neither it returns a nil vault and
+// disables connectors; a malformed key is an error so the server refuses to
+// boot rather than storing credentials it cannot read back.
+func VaultFromEnv(keys *fieldcrypt.Keyring) (*Vault, error) {
+	raw := strings.TrimSpace(os.Getenv("CONNECTOR_VAULT_KEY"))
+	if raw == "" {
+		if keys == nil {
+			return nil, nil
+		}
+		return &Vault{keys: keys}, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("CONNECTOR_VAULT_KEY is not valid base64: %w", err)
+	}
+	v, err := NewVault(key)
+	if err != nil {
+		return nil, fmt.Errorf("CONNECTOR_VAULT_KEY: %w", err)
+	}
+	v.keys = keys
+	return v, nil
+}
+
+// Seal encrypts plaintext for orgID: under the keyring's primary key when
+// the vault has one, otherwise as nonce || ciphertext under the vault key.
+func (v *Vault) Seal(orgID int, plaintext []byte) ([]byte, error) {
+	if v.keys != nil {
+		sealed, err := v.keys.Encrypt(plaintext, string(orgAAD(orgID)))
+		return []byte(sealed), err
+	}
+	nonce := make([]byte, v.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return v.aead.Seal(nonce, nonce, plaintext, orgAAD(orgID)), nil
+}
+
+// Open decrypts what Seal produced for the same orgID, under either key.
+func (v *Vault) Open(orgID int, sealed []byte) ([]byte, error) {
+	if fieldcrypt.IsEncrypted(string(sealed)) {
+		if v.keys == nil {
+			return nil, ErrVaultSealed
+		}
+		plaintext, err := v.keys.Decrypt(string(sealed), string(orgAAD(orgID)))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrVaultSealed, err)
+		}
+		return plaintext, nil
+	}
+	if v.aead == nil {
+		return nil, ErrVaultSealed
+	}
+	n := v.aead.NonceSize()
+	if len(sealed) < n {
+		return nil, ErrVaultSealed
+	}
+	plaintext, err := v.aead.Open(nil, sealed[:n], sealed[n:], orgAAD(orgID))
+	if err != nil {
+		return nil, ErrVaultSealed
+	}
+	return plaintext, nil
+}
+
+// Reseal re-encrypts sealed under the keyring's primary key, reporting
+// whether it changed anything: credentials already under the primary key,
+// or a vault without a keyring, are left as they are.
+func (v *Vault) Reseal(orgID int, sealed []byte) ([]byte, bool, error) {
+	if v.keys == nil || v.keys.Current(string(sealed)) {
+		return sealed, false, nil
+	}
+	plaintext, err := v.Open(orgID, sealed)
+	if err != nil {
+		return nil, false, err
+	}
+	resealed, err := v.Seal(orgID, plaintext)
+	if err != nil {
+		return nil, false, err
+	}
+	return resealed, true, nil
+}
+
+func orgAAD(orgID int) []byte {
+	return []byte("trakrf-connector:" + strconv.Itoa(orgID))
+}
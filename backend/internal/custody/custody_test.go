@@ -0,0 +1,167 @@
+package custody
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+func testRecord() report.CustodyRecord {
+	bay, value := "BAY-1", "E280-0001"
+	tagType := "rfid"
+	status := "approved"
+	at := time.Date(2026, 5, 1, 8, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	until := at.Add(3 * time.Hour)
+	return report.CustodyRecord{
+		OrgID:   1,
+		OrgName: "Acme",
+		Asset:   report.CustodySubject{AssetID: 7, ExternalKey: "FORK-7", Name: "Forklift (7)"},
+		Events: []report.CustodyEvent{
+			{Kind: report.CustodyTagAssigned, At: at, TagType: &tagType, TagValue: &value},
+			{Kind: report.CustodyLocation, At: at, Until: &until, Scans: 12, LocationExternalKey: &bay},
+			{Kind: report.CustodyDisposalDecided, At: until, Status: &status,
+				Actor: &report.CustodyActor{UserID: 3, Name: "Zoë", Email: "z@x"}},
+		},
+	}
+}
+
+func testBuild(t *testing.T) *Document {
+	t.Helper()
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	doc, err := Build(testRecord(), from, from.AddDate(0, 0, 7), from.AddDate(0, 0, 8),
+		report.CustodyActor{UserID: 2, Email: "auditor@x"})
+	require.NoError(t, err)
+	return doc
+}
+
+func TestBuild_ChainsAndSurvivesRoundTrip(t *testing.T) {
+	doc := testBuild(t)
+	require.Len(t, doc.Entries, 3)
+	assert.Equal(t, 1, doc.Entries[0].Seq)
+	assert.Equal(t, "sha256:"+doc.Entries[2].Hash, doc.Digest)
+	assert.Equal(t, time.UTC, doc.Entries[0].At.Location())
+	require.NoError(t, Verify(doc))
+
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var back Document
+	require.NoError(t, json.Unmarshal(b, &back))
+	assert.NoError(t, Verify(&back))
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	cases := map[string]func(d *Document){
+		"edited entry":   func(d *Document) { d.Entries[1].Scans = 1 },
+		"dropped entry":  func(d *Document) { d.Entries = append(d.Entries[:1], d.Entries[2:]...) },
+		"edited header":  func(d *Document) { d.Asset.ExternalKey = "FORK-8" },
+		"swapped digest": func(d *Document) { d.Digest = "sha256:" + d.Entries[0].Hash },
+	}
+	for name, tamper := range cases {
+		t.Run(name, func(t *testing.T) {
+			doc := testBuild(t)
+			tamper(doc)
+			assert.ErrorIs(t, Verify(doc), ErrTampered)
+		})
+	}
+}
+
+func TestSigner(t *testing.T) {
+	s, err := NewSigner(bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+	other, err := NewSigner(bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+
+	doc := testBuild(t)
+	assert.ErrorIs(t, s.Verify(doc), ErrBadSignature, "unsigned")
+
+	s.Sign(doc)
+	require.NotNil(t, doc.Signature)
+	assert.Equal(t, s.KeyID(), doc.Signature.KeyID)
+	assert.NoError(t, s.Verify(doc))
+	assert.ErrorIs(t, other.Verify(doc), ErrBadSignature)
+
+	doc.Digest = "sha256:00"
+	assert.ErrorIs(t, s.Verify(doc), ErrBadSignature)
+
+	_, err = NewSigner([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestSignerFromEnv(t *testing.T) {
+	t.Setenv("CUSTODY_SIGNING_KEY", "")
+	s, err := SignerFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	t.Setenv("CUSTODY_SIGNING_KEY", "not base64!")
+	_, err = SignerFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("CUSTODY_SIGNING_KEY", "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE=")
+	s, err = SignerFromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, s)
+	assert.NotEmpty(t, s.PublicKey())
+}
+
+func TestRenderPDF(t *testing.T) {
+	doc := testBuild(t)
+	out := RenderPDF(doc)
+
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+	assert.True(t, bytes.HasSuffix(out, []byte("%%EOF\n")))
+	assert.Contains(t, string(out), doc.Digest)
+	assert.Contains(t, string(out), `Forklift \(7\)`, "parentheses escaped")
+	assert.Contains(t, string(out), "Zo\xeb", "Latin-1 mapped to WinAnsi")
+	assert.Contains(t, string(out), "/Count 1")
+}
+
+func TestRenderPDF_Signatures(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 40, 10))
+	img.Set(3, 3, color.NRGBA{A: 0xff})
+	var raw bytes.Buffer
+	require.NoError(t, png.Encode(&raw, img))
+
+	strokes := &asset.Signature{SignerName: "Dana", Width: 400, Height: 100,
+		Strokes: [][]asset.SignaturePoint{{{X: 10, Y: 10}, {X: 390, Y: 90}}, {{X: 50, Y: 50}}}}
+	pngSig := &asset.Signature{SignerName: "Lee", ImagePNG: base64.StdEncoding.EncodeToString(raw.Bytes())}
+	rec := testRecord()
+	at := rec.Events[2].At
+	for i, sig := range []*asset.Signature{strokes, pngSig} {
+		rec.Events = append(rec.Events, report.CustodyEvent{
+			Kind: report.CustodyCheckOut, At: at.Add(time.Duration(i+1) * time.Minute),
+			Signature: &report.CustodySignature{SignerName: sig.SignerName, Format: sig.Format(), SHA256: sig.Digest(), Payload: sig},
+		})
+	}
+	from := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	doc, err := Build(rec, from, from.AddDate(0, 0, 7), from.AddDate(0, 0, 8), report.CustodyActor{UserID: 2})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), `"x":`, "payload is not part of the document")
+	assert.Contains(t, string(b), strokes.Digest())
+
+	out := string(RenderPDF(doc))
+	assert.Contains(t, out, `signed by Dana \(strokes, sha256 `+strokes.Digest())
+	assert.Contains(t, out, " m ", "strokes drawn as paths")
+	assert.Contains(t, out, "/XObject << /Im0 ")
+	assert.Contains(t, out, "/Subtype /Image /Width 40 /Height 10")
+
+	// Re-signing the payload changes its digest, and with it the chain.
+	edited := *doc
+	edited.Entries = append([]Entry(nil), doc.Entries...)
+	edited.Entries[3].Signature = &report.CustodySignature{SignerName: "Dana", Format: "strokes", SHA256: pngSig.Digest()}
+	assert.ErrorIs(t, Verify(&edited), ErrTampered)
+}
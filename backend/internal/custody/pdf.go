@@ -0,0 +1,322 @@
+package custody
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+// PDF layout: A4 portrait in points, one Courier text column.
+const (
+	pdfPageWidth  = 595
+	pdfPageHeight = 842
+	pdfMargin     = 48
+	pdfFontSize   = 8
+	pdfLeading    = 11
+	pdfLineChars  = 110
+	pdfPageLines  = (pdfPageHeight - 2*pdfMargin) / pdfLeading
+	// A signature box takes pdfSigLines lines of the column.
+	pdfSigLines  = 6
+	pdfSigWidth  = 200
+	pdfSigHeight = pdfSigLines*pdfLeading - 6
+)
+
+// pdfLine is one line of the column: text, or a signature box when sig is
+// set.
+type pdfLine struct {
+	text string
+	sig  *asset.Signature
+}
+
+func (l pdfLine) height() int {
+	if l.sig != nil {
+		return pdfSigLines
+	}
+	return 1
+}
+
+// RenderPDF renders doc as a plain-text PDF certificate: the header, digest
+// and signature, then one line per entry with its hash, followed by the
+// captured signature for signed check-outs and check-ins. The PDF is a
+// human-readable rendering only; the JSON document is what Verify checks,
+// and the digest printed here lets a reader match the two.
+func RenderPDF(doc *Document) []byte {
+	var lines []pdfLine
+	add := func(text ...string) {
+		for _, t := range text {
+			lines = append(lines, pdfLine{text: t})
+		}
+	}
+	add("CHAIN OF CUSTODY CERTIFICATE",
+		"",
+		fmt.Sprintf("Asset:        %s - %s (id %d)", doc.Asset.ExternalKey, doc.Asset.Name, doc.Asset.AssetID),
+		fmt.Sprintf("Organization: %s (id %d)", doc.OrgName, doc.OrgID),
+		fmt.Sprintf("Period:       %s to %s", pdfTime(doc.From), pdfTime(doc.To)),
+		fmt.Sprintf("Generated:    %s by %s", pdfTime(doc.GeneratedAt), doc.GeneratedBy.Email),
+		fmt.Sprintf("Format:       %s, %d entries", doc.Format, len(doc.Entries)),
+		"Digest:       "+doc.Digest)
+	if doc.Asset.DisposedAt != nil {
+		add("Disposed:     " + pdfTime(*doc.Asset.DisposedAt))
+	}
+	if doc.Asset.DeletedAt != nil {
+		add("Deleted:      " + pdfTime(*doc.Asset.DeletedAt))
+	}
+	if doc.Signature != nil {
+		add(fmt.Sprintf("Signature:    %s key %s", doc.Signature.Alg, doc.Signature.KeyID),
+			"              "+doc.Signature.Value)
+	} else {
+		add("Signature:    unsigned")
+	}
+	add("", "Each entry's hash chains the previous hash with the entry; the last hash is the digest.", "")
+
+	for _, e := range doc.Entries {
+		add(pdfWrap(fmt.Sprintf("#%-5d %s  %s", e.Seq, pdfTime(e.At), describe(e.CustodyEvent)))...)
+		add("       sha256 " + e.Hash)
+		if sig := e.Signature; sig != nil {
+			add(pdfWrap(fmt.Sprintf("       signed by %s (%s, sha256 %s)", sig.SignerName, sig.Format, sig.SHA256))...)
+			if sig.Payload != nil {
+				lines = append(lines, pdfLine{sig: sig.Payload})
+			}
+		}
+	}
+	if len(doc.Entries) == 0 {
+		add("No custody events in this period.")
+	}
+
+	var pages [][]pdfLine
+	var page []pdfLine
+	used := 0
+	for _, l := range lines {
+		if used+l.height() > pdfPageLines-2 {
+			pages = append(pages, page)
+			page, used = nil, 0
+		}
+		page = append(page, l)
+		used += l.height()
+	}
+	return writePDF(append(pages, page), doc.Digest)
+}
+
+// describe renders one event as a line of text.
+func describe(e report.CustodyEvent) string {
+	var b strings.Builder
+	b.WriteString(e.Kind)
+	switch e.Kind {
+	case report.CustodyLocation:
+		b.WriteString(" " + pdfLocation(e))
+		if e.Until != nil {
+			fmt.Fprintf(&b, " until %s", pdfTime(*e.Until))
+		}
+		fmt.Fprintf(&b, " (%d scans)", e.Scans)
+	case report.CustodyTagAssigned, report.CustodyTagUnassigned:
+		fmt.Fprintf(&b, " %s %s", deref(e.TagType), deref(e.TagValue))
+	}
+	if e.Status != nil {
+		b.WriteString(" " + *e.Status)
+	}
+	if e.Actor != nil {
+		b.WriteString(" by ")
+		if e.Actor.Name != "" {
+			b.WriteString(e.Actor.Name + " ")
+		}
+		fmt.Fprintf(&b, "<%s> (user %d)", e.Actor.Email, e.Actor.UserID)
+	}
+	if e.Detail != nil {
+		b.WriteString(" - " + *e.Detail)
+	}
+	return b.String()
+}
+
+func pdfLocation(e report.CustodyEvent) string {
+	switch {
+	case e.LocationExternalKey != nil && e.LocationName != nil:
+		return fmt.Sprintf("at %s - %s", *e.LocationExternalKey, *e.LocationName)
+	case e.LocationExternalKey != nil:
+		return "at " + *e.LocationExternalKey
+	default:
+		return "at unknown location"
+	}
+}
+
+func pdfTime(t time.Time) string { return t.UTC().Format("2006-01-02 15:04:05Z") }
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// pdfWrap splits a line longer than pdfLineChars, indenting continuations.
+func pdfWrap(line string) []string {
+	var out []string
+	r := []rune(line)
+	for len(r) > pdfLineChars {
+		out = append(out, string(r[:pdfLineChars]))
+		r = append([]rune("       "), r[pdfLineChars:]...)
+	}
+	return append(out, string(r))
+}
+
+// writePDF writes a minimal PDF 1.4 file: a catalog, a page tree, the
+// built-in Courier font and one content stream per page, with a footer
+// carrying the page number and digest. Signature images follow the pages
+// as image XObjects.
+func writePDF(pages [][]pdfLine, digest string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1-3 are fixed; page i is object 4+2i, its content 5+2i, and
+	// image k is object 4+2*len(pages)+k.
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+
+	var images []pdfImage
+	for i, lines := range pages {
+		var s bytes.Buffer
+		var xobjects []string
+		y := pdfPageHeight - pdfMargin
+		for _, l := range lines {
+			y -= l.height() * pdfLeading
+			if l.sig == nil {
+				fmt.Fprintf(&s, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n", pdfFontSize, pdfMargin, y, pdfEscape(l.text))
+				continue
+			}
+			x0, y0 := pdfMargin+56, y+3
+			fmt.Fprintf(&s, "q 0.5 w %d %d %d %d re S Q\n", x0, y0, pdfSigWidth, pdfSigHeight)
+			if l.sig.ImagePNG != "" {
+				img, ok := decodePDFImage(l.sig.ImagePNG)
+				if !ok {
+					fmt.Fprintf(&s, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n", pdfFontSize, x0+4, y0+4, "[signature image unreadable]")
+					continue
+				}
+				name := fmt.Sprintf("Im%d", len(images))
+				xobjects = append(xobjects, fmt.Sprintf("/%s %d 0 R", name, 4+2*len(pages)+len(images)))
+				images = append(images, img)
+				w, h, dx, dy := pdfFit(float64(img.width), float64(img.height))
+				fmt.Fprintf(&s, "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", w, h, float64(x0)+dx, float64(y0)+dy, name)
+				continue
+			}
+			w, h, dx, dy := pdfFit(l.sig.Width, l.sig.Height)
+			scale := w / l.sig.Width
+			s.WriteString("q 1 J 1 j 0.8 w\n")
+			for _, stroke := range l.sig.Strokes {
+				for k, p := range stroke {
+					op := "l"
+					if k == 0 {
+						op = "m"
+					}
+					fmt.Fprintf(&s, "%.2f %.2f %s ", float64(x0)+dx+p.X*scale, float64(y0)+dy+h-p.Y*scale, op)
+				}
+				if len(stroke) == 1 {
+					p := stroke[0]
+					fmt.Fprintf(&s, "%.2f %.2f l ", float64(x0)+dx+p.X*scale, float64(y0)+dy+h-p.Y*scale)
+				}
+				s.WriteString("S\n")
+			}
+			s.WriteString("Q\n")
+		}
+		fmt.Fprintf(&s, "BT /F1 %d Tf %d %d Td (%s) Tj ET\n", pdfFontSize, pdfMargin, pdfMargin/2,
+			pdfEscape(fmt.Sprintf("Page %d of %d    %s", i+1, len(pages), digest)))
+
+		resources := "/Font << /F1 3 0 R >>"
+		if len(xobjects) > 0 {
+			resources += " /XObject << " + strings.Join(xobjects, " ") + " >>"
+		}
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << %s >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, resources, 5+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", s.Len(), s.String()))
+	}
+	for _, img := range images {
+		obj(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream",
+			img.width, img.height, len(img.data), img.data))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// pdfFit scales a w x h canvas to fit the signature box, keeping its aspect
+// ratio, and returns the scaled size and its offset to centre it.
+func pdfFit(w, h float64) (sw, sh, dx, dy float64) {
+	scale := min((pdfSigWidth-4)/w, (pdfSigHeight-4)/h)
+	sw, sh = w*scale, h*scale
+	return sw, sh, (pdfSigWidth - sw) / 2, (pdfSigHeight - sh) / 2
+}
+
+// pdfImage is a signature image as Flate-compressed 8-bit RGB samples.
+type pdfImage struct {
+	width, height int
+	data          []byte
+}
+
+// decodePDFImage decodes a base64 PNG and flattens it onto white, since a
+// DeviceRGB image has no alpha.
+func decodePDFImage(b64 string) (pdfImage, bool) {
+	raw, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return pdfImage{}, false
+	}
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return pdfImage{}, false
+	}
+	bounds := img.Bounds()
+	var out bytes.Buffer
+	zw := zlib.NewWriter(&out)
+	row := make([]byte, 0, 3*bounds.Dx())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row = row[:0]
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			row = append(row, byte((r+0xffff-a)>>8), byte((g+0xffff-a)>>8), byte((b+0xffff-a)>>8))
+		}
+		_, _ = zw.Write(row)
+	}
+	_ = zw.Close()
+	return pdfImage{width: bounds.Dx(), height: bounds.Dy(), data: out.Bytes()}, true
+}
+
+// pdfEscape escapes a string literal and maps it to WinAnsi: Latin-1 runes
+// pass through as single bytes, anything else becomes '?'.
+func pdfEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
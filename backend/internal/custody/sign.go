@@ -0,0 +1,88 @@
+package custody
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ErrBadSignature is returned by Signer.Verify when a document is unsigned,
+// signed by another key, or its signature does not cover its digest.
+var ErrBadSignature = errors.New("custody document signature is invalid")
+
+// SignatureAlg is the only signature algorithm documents use.
+const SignatureAlg = "Ed25519"
+
+// Signer signs custody document digests with an Ed25519 key, so anyone
+// holding the published public key can check a document offline.
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// NewSigner builds a signer from a 32-byte Ed25519 seed.
+func NewSigner(seed []byte) (*Signer, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", ed25519.SeedSize, len(seed))
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+	sum := sha256.Sum256(key.Public().(ed25519.PublicKey))
+	return &Signer{key: key, keyID: hex.EncodeToString(sum[:8])}, nil
+}
+
+// SignerFromEnv reads CUSTODY_SIGNING_KEY, a base64-encoded 32-byte Ed25519
+// seed. Unset returns a nil signer and documents are served unsigned; a
+// malformed key is an error so the server refuses to boot rather than
+// silently dropping signatures.
+func SignerFromEnv() (*Signer, error) {
+	raw := strings.TrimSpace(os.Getenv("CUSTODY_SIGNING_KEY"))
+	if raw == "" {
+		return nil, nil
+	}
+	seed, err := base64.StdEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, fmt.Errorf("CUSTODY_SIGNING_KEY is not valid base64: %w", err)
+	}
+	s, err := NewSigner(seed)
+	if err != nil {
+		return nil, fmt.Errorf("CUSTODY_SIGNING_KEY: %w", err)
+	}
+	return s, nil
+}
+
+// KeyID identifies the signing key: the first 8 bytes, in hex, of the
+// SHA-256 of its public key.
+func (s *Signer) KeyID() string { return s.keyID }
+
+// PublicKey returns the base64 Ed25519 public key.
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// Sign signs doc's digest, replacing any existing signature.
+func (s *Signer) Sign(doc *Document) {
+	doc.Signature = &Signature{
+		Alg:   SignatureAlg,
+		KeyID: s.keyID,
+		Value: base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, []byte(doc.Digest))),
+	}
+}
+
+// Verify checks doc's signature against s's key. It does not check the hash
+// chain; see Verify.
+func (s *Signer) Verify(doc *Document) error {
+	sig := doc.Signature
+	if sig == nil || sig.Alg != SignatureAlg || sig.KeyID != s.keyID {
+		return ErrBadSignature
+	}
+	raw, err := base64.StdEncoding.DecodeString(sig.Value)
+	if err != nil || !ed25519.Verify(s.key.Public().(ed25519.PublicKey), []byte(doc.Digest), raw) {
+		return ErrBadSignature
+	}
+	return nil
+}
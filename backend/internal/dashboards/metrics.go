@@ -0,0 +1,11 @@
+package dashboards
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricRefreshes = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "dashboard_metric_refreshes_total",
+	Help: "Cached dashboard widget results handled by the refresh job, by outcome.",
+}, []string{"outcome"}) // refreshed, dropped
@@ -0,0 +1,68 @@
+// Package dashboards keeps the dashboard metric cache warm. The Refresher's
+// Run job finds the orgs holding cached widget results older than the
+// refresh interval, recomputes those a dashboard has read recently and drops
+// the rest, so page loads are answered from the cache rather than by
+// recounting a large org's assets and scans.
+//
+// Rows being refreshed are locked and skipped by other replicas; one org's
+// failure does not stop the rest.
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// refresherStore is the storage surface the refresher needs;
+// *storage.Storage satisfies it.
+type refresherStore interface {
+	ListOrgsWithDueDashboardMetrics(ctx context.Context, now time.Time) ([]int, error)
+	RefreshDashboardMetrics(ctx context.Context, orgID int, now time.Time) (refreshed, dropped int, err error)
+}
+
+// Refresher recomputes every org's due dashboard metrics.
+type Refresher struct {
+	store refresherStore
+	log   zerolog.Logger
+	now   func() time.Time
+}
+
+// NewRefresher builds the dashboard metrics job over store.
+func NewRefresher(store refresherStore, log *zerolog.Logger) *Refresher {
+	return &Refresher{
+		store: store,
+		log:   log.With().Str("component", "dashboards").Logger(),
+		now:   time.Now,
+	}
+}
+
+// Run refreshes every due metric once. The returned error joins the per-org
+// failures.
+func (r *Refresher) Run(ctx context.Context) error {
+	now := r.now()
+	orgs, err := r.store.ListOrgsWithDueDashboardMetrics(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, orgID := range orgs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		refreshed, dropped, err := r.store.RefreshDashboardMetrics(ctx, orgID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("org %d: %w", orgID, err))
+			continue
+		}
+		metricRefreshes.WithLabelValues("refreshed").Add(float64(refreshed))
+		metricRefreshes.WithLabelValues("dropped").Add(float64(dropped))
+		r.log.Debug().Int("org_id", orgID).Int("refreshed", refreshed).Int("dropped", dropped).
+			Msg("refreshed dashboard metrics")
+	}
+	return errors.Join(errs...)
+}
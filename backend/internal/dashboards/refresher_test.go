@@ -0,0 +1,66 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	orgs      []int
+	listErr   error
+	failOrg   int
+	refreshed map[int]time.Time
+	listedAt  time.Time
+}
+
+func (f *fakeStore) ListOrgsWithDueDashboardMetrics(_ context.Context, now time.Time) ([]int, error) {
+	f.listedAt = now
+	return f.orgs, f.listErr
+}
+
+func (f *fakeStore) RefreshDashboardMetrics(_ context.Context, orgID int, now time.Time) (int, int, error) {
+	if orgID == f.failOrg {
+		return 0, 0, errors.New("db down")
+	}
+	f.refreshed[orgID] = now
+	return 3, 1, nil
+}
+
+func testRefresher(store refresherStore, now time.Time) *Refresher {
+	log := zerolog.Nop()
+	r := NewRefresher(store, &log)
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func TestRun_RefreshesEachOrgAtOneInstant(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{orgs: []int{1, 2}, refreshed: map[int]time.Time{}}
+
+	require.NoError(t, testRefresher(store, now).Run(t.Context()))
+
+	assert.Equal(t, now, store.listedAt)
+	assert.Equal(t, map[int]time.Time{1: now, 2: now}, store.refreshed)
+}
+
+func TestRun_OneOrgFailingDoesNotStopTheRest(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{orgs: []int{1, 2, 3}, failOrg: 2, refreshed: map[int]time.Time{}}
+
+	err := testRefresher(store, now).Run(t.Context())
+
+	assert.ErrorContains(t, err, "org 2")
+	assert.Contains(t, store.refreshed, 1)
+	assert.Contains(t, store.refreshed, 3)
+}
+
+func TestRun_ListErrorFailsTheRun(t *testing.T) {
+	store := &fakeStore{listErr: errors.New("db down")}
+	assert.Error(t, testRefresher(store, time.Now()).Run(t.Context()))
+}
@@ -0,0 +1,92 @@
+package dbbackup
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/objectstore"
+	"github.com/trakrf/platform/backend/internal/util/awssig"
+)
+
+// Config configures where backups go.
+type Config struct {
+	// Store is the bucket dumps are uploaded to. It must not be the public
+	// uploads bucket.
+	Store objectstore.Config
+	// Prefix is prepended to every dump's key.
+	Prefix string
+}
+
+// ConfigFromEnv reads:
+//
+//	BACKUP_BUCKET    bucket for dumps (required)
+//	BACKUP_PREFIX    key prefix                    (default trakrf/)
+//	BACKUP_REGION    region   (or OBJECT_STORE_REGION, AWS_REGION)
+//	BACKUP_ENDPOINT  S3 API URL (or OBJECT_STORE_ENDPOINT; default https://s3.<region>.amazonaws.com)
+//	AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN
+//
+// BACKUP_BUCKET may not name OBJECT_STORE_BUCKET: uploads are served from
+// that bucket's public URL, and a dump holds every org's data.
+func ConfigFromEnv() (Config, error) {
+	c := Config{
+		Store: objectstore.Config{
+			Bucket:   strings.TrimSpace(os.Getenv("BACKUP_BUCKET")),
+			Region:   firstEnv("BACKUP_REGION", "OBJECT_STORE_REGION", "AWS_REGION"),
+			Endpoint: strings.TrimRight(firstEnv("BACKUP_ENDPOINT", "OBJECT_STORE_ENDPOINT"), "/"),
+			Credentials: awssig.Credentials{
+				AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			},
+		},
+		Prefix: "trakrf/",
+	}
+	if v, ok := os.LookupEnv("BACKUP_PREFIX"); ok {
+		c.Prefix = strings.TrimLeft(v, "/")
+	}
+
+	switch {
+	case c.Store.Bucket == "":
+		return Config{}, fmt.Errorf("BACKUP_BUCKET must be set")
+	case c.Store.Bucket == strings.TrimSpace(os.Getenv("OBJECT_STORE_BUCKET")):
+		return Config{}, fmt.Errorf("BACKUP_BUCKET must not be OBJECT_STORE_BUCKET, which is served publicly")
+	case c.Store.Region == "":
+		return Config{}, fmt.Errorf("BACKUP_REGION, OBJECT_STORE_REGION or AWS_REGION must be set")
+	case c.Store.Credentials.AccessKeyID == "" || c.Store.Credentials.SecretAccessKey == "":
+		return Config{}, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	if c.Store.Endpoint == "" {
+		c.Store.Endpoint = "https://s3." + c.Store.Region + ".amazonaws.com"
+	}
+	if u, err := url.Parse(c.Store.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return Config{}, fmt.Errorf("BACKUP_ENDPOINT must be an http(s) URL, got %q", c.Store.Endpoint)
+	}
+	return c, nil
+}
+
+// MaxAgeFromEnv reads BACKUP_MAX_AGE, the age of the last successful backup
+// past which /readyz reports the backup check failing. Unset returns 0: the
+// environment takes no backups and the check is not registered.
+func MaxAgeFromEnv() (time.Duration, error) {
+	raw := os.Getenv("BACKUP_MAX_AGE")
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := time.ParseDuration(raw)
+	if err != nil || v <= 0 {
+		return 0, fmt.Errorf("BACKUP_MAX_AGE must be a positive duration, got %q", raw)
+	}
+	return v, nil
+}
+
+func firstEnv(names ...string) string {
+	for _, n := range names {
+		if v := os.Getenv(n); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,74 @@
+package dbbackup
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setBackupEnv(t *testing.T) {
+	t.Setenv("BACKUP_BUCKET", "trakrf-backups")
+	t.Setenv("AWS_REGION", "us-east-2")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	for _, k := range []string{"BACKUP_REGION", "BACKUP_ENDPOINT", "OBJECT_STORE_BUCKET", "OBJECT_STORE_REGION", "OBJECT_STORE_ENDPOINT"} {
+		t.Setenv(k, "")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	setBackupEnv(t)
+	c, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "trakrf-backups", c.Store.Bucket)
+	assert.Equal(t, "us-east-2", c.Store.Region)
+	assert.Equal(t, "https://s3.us-east-2.amazonaws.com", c.Store.Endpoint)
+	assert.Equal(t, "trakrf/", c.Prefix)
+
+	t.Setenv("OBJECT_STORE_ENDPOINT", "http://minio:9000/")
+	t.Setenv("BACKUP_PREFIX", "/prod/")
+	c, err = ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "http://minio:9000", c.Store.Endpoint, "falls back to the object store endpoint")
+	assert.Equal(t, "prod/", c.Prefix)
+}
+
+func TestConfigFromEnv_Rejects(t *testing.T) {
+	tests := []struct {
+		name, key, value, wantErr string
+	}{
+		{"no bucket", "BACKUP_BUCKET", "", "BACKUP_BUCKET must be set"},
+		{"public uploads bucket", "OBJECT_STORE_BUCKET", "trakrf-backups", "served publicly"},
+		{"no region", "AWS_REGION", "", "AWS_REGION must be set"},
+		{"no credentials", "AWS_SECRET_ACCESS_KEY", "", "AWS_SECRET_ACCESS_KEY must be set"},
+		{"bad endpoint", "BACKUP_ENDPOINT", "minio:9000", "http(s) URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setBackupEnv(t)
+			t.Setenv(tt.key, tt.value)
+			_, err := ConfigFromEnv()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMaxAgeFromEnv(t *testing.T) {
+	t.Setenv("BACKUP_MAX_AGE", "")
+	v, err := MaxAgeFromEnv()
+	require.NoError(t, err)
+	assert.Zero(t, v, "unset disables the check")
+
+	t.Setenv("BACKUP_MAX_AGE", "26h")
+	v, err = MaxAgeFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 26*time.Hour, v)
+
+	for _, bad := range []string{"daily", "0s", "-1h"} {
+		t.Setenv("BACKUP_MAX_AGE", bad)
+		_, err := MaxAgeFromEnv()
+		assert.Error(t, err, bad)
+	}
+}
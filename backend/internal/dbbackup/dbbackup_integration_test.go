@@ -0,0 +1,110 @@
+//go:build integration
+
+package dbbackup_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/dbbackup"
+	"github.com/trakrf/platform/backend/internal/models/backup"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+// bucket is an in-memory objectstore.Store.
+type bucket struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (b *bucket) Put(_ context.Context, key, _ string, body []byte) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.objects[key] = body
+	return nil
+}
+func (b *bucket) Delete(context.Context, string) error { return nil }
+func (b *bucket) URL(key string) string                { return "s3://test/" + key }
+func (b *bucket) KeyForURL(string) (string, bool)      { return "", false }
+
+func TestJob_DumpsUploadsAndVerifies(t *testing.T) {
+	store := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+	pool := store.Pool().(*pgxpool.Pool)
+	log := zerolog.Nop()
+
+	orgID := testutil.NewOrgFactory().Create(t, pool)
+	locs := testutil.NewLocationFactory(orgID)
+	wh := locs.WithExternalKey("WH-1").Create(t, pool)
+	locs.WithParent(wh).Create(t, pool)
+
+	objects := &bucket{objects: map[string][]byte{}}
+	run, err := dbbackup.NewJob(store, pool, objects, "test/", &log).Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, backup.StatusSucceeded, run.Status)
+	require.NotNil(t, run.SchemaVersion)
+	assert.Positive(t, run.TableCount)
+	assert.GreaterOrEqual(t, run.RowCount, int64(3), "the org and its two locations at least")
+	require.NotNil(t, run.ObjectKey)
+	assert.Regexp(t, `^test/trakrf-\d{8}T\d{6}Z\.sql\.gz$`, *run.ObjectKey)
+
+	archive := objects.objects[*run.ObjectKey]
+	require.NotEmpty(t, archive)
+	assert.EqualValues(t, len(archive), run.SizeBytes)
+	zr, err := gzip.NewReader(bytes.NewReader(archive))
+	require.NoError(t, err)
+	script, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Contains(t, string(script), `COPY "trakrf"."locations" (`)
+	assert.Contains(t, string(script), "WH-1")
+	assert.NotContains(t, string(script), `"schema_migrations"`)
+
+	var scratch bool
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)`, dbbackup.ScratchSchema).Scan(&scratch))
+	assert.False(t, scratch, "the scratch schema is dropped after the restore")
+
+	last, ok, err := store.LatestBackupRuns(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, last)
+	assert.Equal(t, run.ID, last.ID)
+	assert.Equal(t, run.ID, ok.ID)
+	assert.NoError(t, dbbackup.Check(ctx, store, time.Hour, *last.FinishedAt))
+}
+
+func TestJob_RecordsFailure(t *testing.T) {
+	store := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+	pool := store.Pool().(*pgxpool.Pool)
+	log := zerolog.Nop()
+
+	good, err := dbbackup.NewJob(store, pool, nil, "", &log).Run(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, good.ObjectKey, "no upload without a store")
+
+	objects := &bucket{err: errors.New("status 403: AccessDenied")}
+	run, err := dbbackup.NewJob(store, pool, objects, "", &log).Run(ctx)
+	require.ErrorContains(t, err, "AccessDenied")
+	assert.Equal(t, backup.StatusFailed, run.Status)
+
+	last, ok, err := store.LatestBackupRuns(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, run.ID, last.ID)
+	require.NotNil(t, last.Error)
+	assert.Contains(t, *last.Error, "AccessDenied")
+	assert.Equal(t, good.ID, ok.ID, "the last success is still reported")
+
+	err = dbbackup.Check(ctx, store, time.Hour, *last.FinishedAt)
+	assert.ErrorContains(t, err, "AccessDenied")
+}
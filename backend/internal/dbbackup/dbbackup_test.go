@@ -0,0 +1,94 @@
+package dbbackup
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/backup"
+)
+
+type fakeStatus struct {
+	last, ok *backup.Run
+	err      error
+}
+
+func (f fakeStatus) LatestBackupRuns(context.Context) (*backup.Run, *backup.Run, error) {
+	return f.last, f.ok, f.err
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	finished := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+	msg := "upload dump: s3 PUT: status 403"
+	recent := &backup.Run{Status: backup.StatusSucceeded, StartedAt: now.Add(-2 * time.Hour), FinishedAt: finished(time.Hour)}
+	stale := &backup.Run{Status: backup.StatusSucceeded, StartedAt: now.Add(-49 * time.Hour), FinishedAt: finished(48 * time.Hour)}
+	failed := &backup.Run{Status: backup.StatusFailed, StartedAt: now.Add(-time.Hour), FinishedAt: finished(time.Minute), Error: &msg}
+
+	tests := []struct {
+		name    string
+		store   fakeStatus
+		wantErr string
+	}{
+		{"recent success", fakeStatus{last: recent, ok: recent}, ""},
+		{"stale success", fakeStatus{last: stale, ok: stale}, "more than 26h0m0s"},
+		{"latest run failed", fakeStatus{last: failed, ok: recent}, "failed: " + msg},
+		{"never succeeded", fakeStatus{}, "no backup has succeeded yet"},
+		{"lookup error", fakeStatus{err: errors.New("boom")}, "boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(context.Background(), tt.store, 26*time.Hour, now)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestCopyData_StopsAtTerminator(t *testing.T) {
+	br := bufio.NewReader(strings.NewReader("1\ta\n2\tb\\nc\n\\.\nCOPY next\n"))
+	data := &copyData{r: br}
+	got, err := io.ReadAll(data)
+	require.NoError(t, err)
+	assert.Equal(t, "1\ta\n2\tb\\nc\n", string(got))
+	assert.Equal(t, 3, data.lines)
+
+	rest, _ := br.ReadString('\n')
+	assert.Equal(t, "COPY next\n", rest, "the reader is left at the line after \\.")
+}
+
+func TestCopyData_Unterminated(t *testing.T) {
+	_, err := io.ReadAll(&copyData{r: bufio.NewReader(strings.NewReader("1\ta\n"))})
+	assert.ErrorContains(t, err, "not terminated")
+}
+
+func TestCompare(t *testing.T) {
+	dumped := []Table{{"assets", 3}, {"locations", 1}}
+
+	assert.NoError(t, compare(dumped, []Table{{"assets", 3}, {"locations", 1}}))
+	assert.ErrorContains(t, compare(dumped, []Table{{"assets", 2}, {"locations", 1}}), "dumped 3 rows, restored 2")
+	assert.ErrorContains(t, compare(dumped, []Table{{"assets", 3}}), "locations was dumped but not restored")
+	assert.ErrorContains(t, compare(dumped, []Table{{"assets", 3}, {"locations", 1}, {"extra", 0}}), "restored 3 tables, dumped 2")
+}
+
+func TestCopyHeader(t *testing.T) {
+	m := copyHeader.FindStringSubmatch(`COPY "trakrf"."odd""name" ("id", "a, b") FROM stdin;`)
+	require.NotNil(t, m)
+	assert.Equal(t, `"trakrf"`, m[1])
+	assert.Equal(t, `"odd""name"`, m[2])
+	assert.Equal(t, `"id", "a, b"`, m[3])
+}
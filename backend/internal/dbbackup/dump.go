@@ -0,0 +1,239 @@
+package dbbackup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Schema is the schema a backup dumps.
+const Schema = "trakrf"
+
+// ScratchSchema is where Restore loads a dump. It is dropped before and
+// after every restore.
+const ScratchSchema = "trakrf_backup_verify"
+
+// Table is one table's entry in a dump.
+type Table struct {
+	Name string
+	Rows int64
+}
+
+// Header describes a dump; it is written as the dump's leading comment.
+type Header struct {
+	SchemaVersion uint
+	TakenAt       time.Time
+}
+
+// Dump writes a data-only dump of Schema to w as a psql script: one COPY
+// block per table, then setval for each sequence. The schema itself is not
+// included; a restore runs against a database migrated to the same version.
+// Everything is read in one repeatable-read transaction, so the dump is a
+// consistent snapshot. Row security is off for the transaction, so a role
+// that would only see some rows fails instead of dumping them silently.
+//
+// Generated columns are left out, and partitions are read through their
+// parent (a hypertable's chunks through the hypertable). schema_migrations
+// is left out too; the version is in the header.
+func Dump(ctx context.Context, conn *pgx.Conn, h Header, w io.Writer) ([]Table, error) {
+	tx, err := conn.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("begin dump transaction: %w", err)
+	}
+	defer tx.Rollback(context.WithoutCancel(ctx))
+	if _, err := tx.Exec(ctx, `SET LOCAL row_security = off`); err != nil {
+		return nil, err
+	}
+
+	type source struct {
+		name    string
+		columns []string
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT c.relname, array_agg(a.attname::text ORDER BY a.attnum)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0
+		                   AND NOT a.attisdropped AND a.attgenerated = ''
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		  AND c.relname <> 'schema_migrations'
+		GROUP BY c.relname
+		ORDER BY c.relname`, Schema)
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	sources, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (source, error) {
+		var s source
+		return s, row.Scan(&s.name, &s.columns)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "-- TrakRF logical backup of schema %s\n", Schema)
+	fmt.Fprintf(bw, "-- schema version: %d\n", h.SchemaVersion)
+	fmt.Fprintf(bw, "-- taken at: %s\n", h.TakenAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(bw, "-- Restore as a superuser into a database migrated to the same version.\n")
+	fmt.Fprintf(bw, "SET session_replication_role = replica;\n\n")
+
+	tables := make([]Table, 0, len(sources))
+	for _, s := range sources {
+		name := pgx.Identifier{Schema, s.name}.Sanitize()
+		cols := make([]string, len(s.columns))
+		for i, c := range s.columns {
+			cols[i] = pgx.Identifier{c}.Sanitize()
+		}
+		list := strings.Join(cols, ", ")
+
+		fmt.Fprintf(bw, "COPY %s (%s) FROM stdin;\n", name, list)
+		tag, err := tx.Conn().PgConn().CopyTo(ctx, bw, fmt.Sprintf("COPY (SELECT %s FROM %s) TO STDOUT", list, name))
+		if err != nil {
+			return nil, fmt.Errorf("dump %s: %w", s.name, err)
+		}
+		fmt.Fprintf(bw, "\\.\n\n")
+		tables = append(tables, Table{Name: s.name, Rows: tag.RowsAffected()})
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT c.relname
+		FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind = 'S'
+		ORDER BY c.relname`, Schema)
+	if err != nil {
+		return nil, fmt.Errorf("list sequences: %w", err)
+	}
+	sequences, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("list sequences: %w", err)
+	}
+	for _, seq := range sequences {
+		name := pgx.Identifier{Schema, seq}.Sanitize()
+		var last int64
+		var called bool
+		if err := tx.QueryRow(ctx, "SELECT last_value, is_called FROM "+name).Scan(&last, &called); err != nil {
+			return nil, fmt.Errorf("dump sequence %s: %w", seq, err)
+		}
+		fmt.Fprintf(bw, "SELECT pg_catalog.setval('%s', %d, %t);\n",
+			strings.ReplaceAll(name, "'", "''"), last, called)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return nil, fmt.Errorf("write dump: %w", err)
+	}
+	return tables, nil
+}
+
+var copyHeader = regexp.MustCompile(`^COPY ("(?:[^"]|"")+")\.("(?:[^"]|"")+") \((.+)\) FROM stdin;$`)
+
+// Restore loads a dump written by Dump into ScratchSchema and returns the
+// rows loaded per table. Each table is created there with its columns'
+// types in the live schema, without constraints, so the check is that
+// every row parses into the current types. ScratchSchema is dropped
+// afterwards, so this needs CREATE on the database.
+func Restore(ctx context.Context, conn *pgx.Conn, r io.Reader) ([]Table, error) {
+	scratch := pgx.Identifier{ScratchSchema}.Sanitize()
+	if _, err := conn.Exec(ctx, "DROP SCHEMA IF EXISTS "+scratch+" CASCADE"); err != nil {
+		return nil, fmt.Errorf("drop scratch schema: %w", err)
+	}
+	if _, err := conn.Exec(ctx, "CREATE SCHEMA "+scratch); err != nil {
+		return nil, fmt.Errorf("create scratch schema: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), "DROP SCHEMA IF EXISTS "+scratch+" CASCADE")
+
+	br := bufio.NewReader(r)
+	var tables []Table
+	for lineNo := 1; ; lineNo++ {
+		line, err := br.ReadString('\n')
+		if err == io.EOF && line == "" {
+			return tables, nil
+		}
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("read dump: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case line == "", strings.HasPrefix(line, "--"),
+			strings.HasPrefix(line, "SET "), strings.HasPrefix(line, "SELECT pg_catalog.setval("):
+			continue
+		}
+		m := copyHeader.FindStringSubmatch(line)
+		if m == nil {
+			return nil, fmt.Errorf("dump line %d: unexpected statement: %.80s", lineNo, line)
+		}
+		source, table, cols := m[1]+"."+m[2], m[2], m[3]
+		name := strings.ReplaceAll(strings.Trim(table, `"`), `""`, `"`)
+
+		if _, err := conn.Exec(ctx, fmt.Sprintf("CREATE TABLE %s.%s AS SELECT %s FROM %s WITH NO DATA",
+			scratch, table, cols, source)); err != nil {
+			return nil, fmt.Errorf("create scratch table %s: %w", name, err)
+		}
+		data := &copyData{r: br}
+		tag, err := conn.PgConn().CopyFrom(ctx, data, fmt.Sprintf("COPY %s.%s (%s) FROM STDIN", scratch, table, cols))
+		if err != nil {
+			return nil, fmt.Errorf("restore %s: %w", name, err)
+		}
+		lineNo += data.lines
+		tables = append(tables, Table{Name: name, Rows: tag.RowsAffected()})
+	}
+}
+
+// copyData reads one COPY block's data lines, up to the \. terminator.
+// Text-format COPY escapes newlines inside values, so every row is one line.
+type copyData struct {
+	r     *bufio.Reader
+	buf   []byte
+	lines int
+	done  bool
+}
+
+func (c *copyData) Read(p []byte) (int, error) {
+	for len(c.buf) == 0 {
+		if c.done {
+			return 0, io.EOF
+		}
+		line, err := c.r.ReadBytes('\n')
+		if err != nil {
+			return 0, fmt.Errorf("COPY data not terminated by \\.: %w", err)
+		}
+		c.lines++
+		if bytes.Equal(line, []byte("\\.\n")) {
+			c.done = true
+			continue
+		}
+		c.buf = line
+	}
+	n := copy(p, c.buf)
+	c.buf = c.buf[n:]
+	return n, nil
+}
+
+// compare checks that restored holds every dumped table with the same row
+// count.
+func compare(dumped, restored []Table) error {
+	got := make(map[string]int64, len(restored))
+	for _, t := range restored {
+		got[t.Name] = t.Rows
+	}
+	for _, t := range dumped {
+		rows, ok := got[t.Name]
+		switch {
+		case !ok:
+			return fmt.Errorf("table %s was dumped but not restored", t.Name)
+		case rows != t.Rows:
+			return fmt.Errorf("table %s: dumped %d rows, restored %d", t.Name, t.Rows, rows)
+		}
+	}
+	if len(restored) != len(dumped) {
+		return fmt.Errorf("restored %d tables, dumped %d", len(restored), len(dumped))
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+// Package direction infers which way tagged assets pass through dock doors.
+// It sits on the ingest fan-out seam (ingest.ReadEvaluator) beside geofence,
+// mustering and positioning.
+//
+// A dock door pairs an outer (yard-side) and an inner (building-side) scan
+// point. A tag crossing the door is heard by both antennas, often
+// alternately, so each (door, asset) keeps a pass: reads no further apart
+// than the door's window belong to one pass. A pass first heard on one side
+// and later on the other is one crossing — outer then inner is inbound,
+// inner then outer outbound — recorded once as a dock_movements row however
+// many reads follow. When one message has the tag on both antennas, the side
+// that heard it loudest counts.
+//
+// State is in-memory and per-process, so single-replica only — the same
+// constraint as geofence/mustering/positioning (TRA-907). A restart in the
+// middle of a pass loses that crossing.
+package direction
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/models/dockdoor"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+// cacheTTL bounds how long an org's doors are trusted before a lazy
+// refresh, so door edits take effect within half a minute without a DB
+// round-trip per message.
+const cacheTTL = 30 * time.Second
+
+// engineStore is the storage surface the engine needs; *storage.Storage
+// satisfies it. Narrowed so engine_test.go can inject a fake.
+type engineStore interface {
+	ListDockDoors(ctx context.Context, orgID, locationID int) ([]dockdoor.Door, error)
+	RecordDockMovement(ctx context.Context, orgID int, m dockdoor.Movement) (*dockdoor.Movement, error)
+}
+
+// side is one scan point's place in a door.
+type side struct {
+	door  *dockdoor.Door
+	outer bool
+}
+
+// passKey identifies one asset's passes through one door.
+type passKey struct{ door, asset int }
+
+// pass is a run of reads of one asset at one door.
+type pass struct {
+	fromOuter bool
+	firstAt   time.Time
+	lastAt    time.Time
+	crossed   bool
+}
+
+// orgState is the per-org door cache plus the open passes.
+type orgState struct {
+	mu sync.Mutex
+
+	sides    map[int]side // scan_point_id -> side of an active door
+	loadedAt time.Time
+
+	passes map[passKey]*pass
+}
+
+// Engine implements ingest.ReadEvaluator.
+type Engine struct {
+	store engineStore
+	log   zerolog.Logger
+	now   func() time.Time
+
+	mu     sync.Mutex
+	states map[int]*orgState
+}
+
+// NewEngine builds an engine over real storage.
+func NewEngine(store *storage.Storage, log *zerolog.Logger) *Engine {
+	return newEngine(store, log)
+}
+
+// newEngine is the test-friendly constructor over the narrow interface.
+func newEngine(store engineStore, log *zerolog.Logger) *Engine {
+	return &Engine{
+		store:  store,
+		log:    log.With().Str("component", "direction").Logger(),
+		now:    time.Now,
+		states: map[int]*orgState{},
+	}
+}
+
+func (e *Engine) state(orgID int) *orgState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	st := e.states[orgID]
+	if st == nil {
+		st = &orgState{passes: map[passKey]*pass{}}
+		e.states[orgID] = st
+	}
+	return st
+}
+
+// ensureLoaded refreshes the org's doors once cacheTTL has passed and drops
+// the passes that have gone quiet. On a lookup error the previous doors are
+// kept (and retried on the next message); an org never loaded has none.
+func (e *Engine) ensureLoaded(ctx context.Context, orgID int, st *orgState, at time.Time) {
+	if !st.loadedAt.IsZero() && e.now().Sub(st.loadedAt) < cacheTTL {
+		return
+	}
+	doors, err := e.store.ListDockDoors(ctx, orgID, 0)
+	if err != nil {
+		e.log.Warn().Err(err).Int("org_id", orgID).Msg("dock door lookup failed")
+		metricErrors.Inc()
+		return
+	}
+	st.sides = map[int]side{}
+	windows := map[int]time.Duration{}
+	for i := range doors {
+		d := &doors[i]
+		if !d.IsActive {
+			continue
+		}
+		st.sides[d.OuterScanPointID] = side{door: d, outer: true}
+		st.sides[d.InnerScanPointID] = side{door: d}
+		windows[d.ID] = d.Window()
+	}
+	for k, p := range st.passes {
+		if w, ok := windows[k.door]; !ok || at.Sub(p.lastAt) > w {
+			delete(st.passes, k)
+		}
+	}
+	st.loadedAt = e.now()
+}
+
+// heard is the side that heard one asset loudest at one door in a message.
+type heard struct {
+	side side
+	rssi int
+}
+
+// Evaluate folds one message's reads at dock door scan points into their
+// passes and records the crossings they complete. Reads elsewhere are
+// ignored. Never returns an error: writes are best-effort, and a crossing
+// whose write fails is not retried.
+func (e *Engine) Evaluate(ctx context.Context, orgID int, tagScanID int64, receivedAt time.Time, reads []storage.ResolvedRead) {
+	if len(reads) == 0 {
+		return
+	}
+	st := e.state(orgID)
+	st.mu.Lock()
+	e.ensureLoaded(ctx, orgID, st, receivedAt)
+	if len(st.sides) == 0 {
+		st.mu.Unlock()
+		return
+	}
+
+	var order []passKey
+	loudest := map[passKey]heard{}
+	for _, rd := range reads {
+		sd, ok := st.sides[rd.ScanPointID]
+		if !ok {
+			continue
+		}
+		key := passKey{door: sd.door.ID, asset: rd.AssetID}
+		h, seen := loudest[key]
+		if !seen {
+			order = append(order, key)
+			loudest[key] = heard{side: sd, rssi: rd.RSSI}
+			continue
+		}
+		// RSSI 0 is "not reported": any reported strength beats it.
+		if rd.RSSI != 0 && (h.rssi == 0 || rd.RSSI > h.rssi) {
+			h.side, h.rssi = sd, rd.RSSI
+			loudest[key] = h
+		}
+	}
+
+	var moves []dockdoor.Movement
+	for _, key := range order {
+		h := loudest[key]
+		p := st.passes[key]
+		if p == nil || receivedAt.Sub(p.lastAt) > h.side.door.Window() {
+			st.passes[key] = &pass{fromOuter: h.side.outer, firstAt: receivedAt, lastAt: receivedAt}
+			continue
+		}
+		if receivedAt.After(p.lastAt) {
+			p.lastAt = receivedAt
+		}
+		if p.crossed || h.side.outer == p.fromOuter {
+			continue
+		}
+		p.crossed = true
+		moves = append(moves, dockdoor.Movement{
+			DockDoorID:  key.door,
+			AssetID:     key.asset,
+			Direction:   dockdoor.Direction(p.fromOuter),
+			FirstReadAt: p.firstAt,
+			CrossedAt:   receivedAt,
+			TagScanID:   tagScanID,
+		})
+	}
+	st.mu.Unlock()
+
+	// Writes happen outside the org lock so DB latency never serializes the
+	// org's other messages behind this one.
+	for _, m := range moves {
+		if _, err := e.store.RecordDockMovement(ctx, orgID, m); err != nil {
+			e.log.Error().Err(err).Int("org_id", orgID).Int("asset_id", m.AssetID).
+				Int("dock_door_id", m.DockDoorID).Msg("dock movement write failed")
+			metricErrors.Inc()
+			continue
+		}
+		metricMovements.WithLabelValues(m.Direction).Inc()
+		e.log.Debug().Int("org_id", orgID).Int("asset_id", m.AssetID).
+			Int("dock_door_id", m.DockDoorID).Str("direction", m.Direction).Msg("asset crossed dock door")
+	}
+}
@@ -0,0 +1,158 @@
+package direction
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/dockdoor"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+type fakeStore struct {
+	doors    []dockdoor.Door
+	lists    int
+	moves    []dockdoor.Movement
+	writeErr error
+}
+
+func (f *fakeStore) ListDockDoors(context.Context, int, int) ([]dockdoor.Door, error) {
+	f.lists++
+	return f.doors, nil
+}
+func (f *fakeStore) RecordDockMovement(_ context.Context, _ int, m dockdoor.Movement) (*dockdoor.Movement, error) {
+	if f.writeErr != nil {
+		return nil, f.writeErr
+	}
+	f.moves = append(f.moves, m)
+	return &m, nil
+}
+
+// Door 40 pairs scan points 1 (outer) and 2 (inner) with a 10s window; door
+// 41 (3 outer, 4 inner) is inactive. Scan point 9 is on no door.
+func newTestEngine() (*Engine, *fakeStore) {
+	store := &fakeStore{doors: []dockdoor.Door{
+		{ID: 40, OuterScanPointID: 1, InnerScanPointID: 2, WindowSeconds: 10, IsActive: true},
+		{ID: 41, OuterScanPointID: 3, InnerScanPointID: 4, WindowSeconds: 10},
+	}}
+	log := zerolog.Nop()
+	e := newEngine(store, &log)
+	e.now = func() time.Time { return t0 }
+	return e, store
+}
+
+var t0 = time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+
+func at(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+
+func rfid(assetID, scanPointID, rssi int) storage.ResolvedRead {
+	return storage.ResolvedRead{AssetID: assetID, ScanPointID: scanPointID, RSSI: rssi}
+}
+
+func TestEvaluate_OuterThenInnerIsInbound(t *testing.T) {
+	e, store := newTestEngine()
+	ctx := context.Background()
+
+	e.Evaluate(ctx, 1, 100, at(0), []storage.ResolvedRead{rfid(7, 1, -60)})
+	e.Evaluate(ctx, 1, 101, at(1), []storage.ResolvedRead{rfid(7, 1, -55)})
+	assert.Empty(t, store.moves, "one side only is not a crossing")
+
+	e.Evaluate(ctx, 1, 102, at(2), []storage.ResolvedRead{rfid(7, 2, -58)})
+	require.Len(t, store.moves, 1)
+	m := store.moves[0]
+	assert.Equal(t, 40, m.DockDoorID)
+	assert.Equal(t, 7, m.AssetID)
+	assert.Equal(t, dockdoor.DirectionInbound, m.Direction)
+	assert.Equal(t, at(0), m.FirstReadAt)
+	assert.Equal(t, at(2), m.CrossedAt)
+	assert.Equal(t, int64(102), m.TagScanID)
+
+	// The antennas keep hearing the tag alternately: still the same pass.
+	e.Evaluate(ctx, 1, 103, at(3), []storage.ResolvedRead{rfid(7, 1, -70)})
+	e.Evaluate(ctx, 1, 104, at(4), []storage.ResolvedRead{rfid(7, 2, -50)})
+	assert.Len(t, store.moves, 1, "a pass is one crossing")
+}
+
+func TestEvaluate_InnerThenOuterIsOutbound(t *testing.T) {
+	e, store := newTestEngine()
+	ctx := context.Background()
+
+	e.Evaluate(ctx, 1, 100, at(0), []storage.ResolvedRead{rfid(7, 2, -60), rfid(8, 2, -60)})
+	e.Evaluate(ctx, 1, 101, at(3), []storage.ResolvedRead{rfid(7, 1, -60), rfid(8, 9, -60)})
+	require.Len(t, store.moves, 1, "asset 8 was read off the door")
+	assert.Equal(t, 7, store.moves[0].AssetID)
+	assert.Equal(t, dockdoor.DirectionOutbound, store.moves[0].Direction)
+}
+
+func TestEvaluate_LoudestSideOfAMessageCounts(t *testing.T) {
+	e, store := newTestEngine()
+	ctx := context.Background()
+
+	// Both antennas hear the tag; the inner one is louder, so the pass starts
+	// inside.
+	e.Evaluate(ctx, 1, 100, at(0), []storage.ResolvedRead{rfid(7, 1, -70), rfid(7, 2, -50)})
+	e.Evaluate(ctx, 1, 101, at(1), []storage.ResolvedRead{rfid(7, 2, -65), rfid(7, 1, -45)})
+	require.Len(t, store.moves, 1)
+	assert.Equal(t, dockdoor.DirectionOutbound, store.moves[0].Direction)
+}
+
+func TestEvaluate_WindowSplitsPasses(t *testing.T) {
+	e, store := newTestEngine()
+	ctx := context.Background()
+
+	// Read outside, then nothing for longer than the window: the inner read
+	// starts a new pass rather than completing a crossing.
+	e.Evaluate(ctx, 1, 100, at(0), []storage.ResolvedRead{rfid(7, 1, -60)})
+	e.Evaluate(ctx, 1, 101, at(11), []storage.ResolvedRead{rfid(7, 2, -60)})
+	assert.Empty(t, store.moves)
+
+	// ...which the tag then leaves through the outer antenna.
+	e.Evaluate(ctx, 1, 102, at(15), []storage.ResolvedRead{rfid(7, 1, -60)})
+	require.Len(t, store.moves, 1)
+	assert.Equal(t, dockdoor.DirectionOutbound, store.moves[0].Direction)
+	assert.Equal(t, at(11), store.moves[0].FirstReadAt)
+}
+
+func TestEvaluate_InactiveDoorsAndOtherOrgsAreIgnored(t *testing.T) {
+	e, store := newTestEngine()
+	ctx := context.Background()
+
+	e.Evaluate(ctx, 1, 100, at(0), []storage.ResolvedRead{rfid(7, 3, -60)})
+	e.Evaluate(ctx, 1, 101, at(1), []storage.ResolvedRead{rfid(7, 4, -60)})
+	assert.Empty(t, store.moves, "door 41 is inactive")
+
+	// Passes are per org: org 2 hearing the inner side does not complete
+	// org 1's pass.
+	e.Evaluate(ctx, 1, 102, at(2), []storage.ResolvedRead{rfid(7, 1, -60)})
+	e.Evaluate(ctx, 2, 103, at(3), []storage.ResolvedRead{rfid(7, 2, -60)})
+	assert.Empty(t, store.moves)
+}
+
+func TestEvaluate_DoorsAreCached(t *testing.T) {
+	e, store := newTestEngine()
+	ctx := context.Background()
+
+	e.Evaluate(ctx, 1, 100, at(0), []storage.ResolvedRead{rfid(7, 1, -60)})
+	e.Evaluate(ctx, 1, 101, at(1), []storage.ResolvedRead{rfid(7, 2, -60)})
+	assert.Equal(t, 1, store.lists)
+
+	e.now = func() time.Time { return t0.Add(cacheTTL) }
+	e.Evaluate(ctx, 1, 102, at(2), []storage.ResolvedRead{rfid(7, 2, -60)})
+	assert.Equal(t, 2, store.lists, "refreshed after the TTL")
+	assert.Len(t, store.moves, 1, "the open pass survives the refresh")
+}
+
+func TestEvaluate_WriteErrorDoesNotPanic(t *testing.T) {
+	e, store := newTestEngine()
+	store.writeErr = errors.New("db down")
+	ctx := context.Background()
+
+	e.Evaluate(ctx, 1, 100, at(0), []storage.ResolvedRead{rfid(7, 1, -60)})
+	e.Evaluate(ctx, 1, 101, at(1), []storage.ResolvedRead{rfid(7, 2, -60)})
+	assert.Empty(t, store.moves)
+}
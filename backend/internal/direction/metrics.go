@@ -0,0 +1,19 @@
+package direction
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Counters live on the default registry, which serve's /metrics handler exposes.
+var (
+	metricMovements = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dock_movements_recorded_total",
+		Help: "Dock door crossings recorded by the direction engine, by direction.",
+	}, []string{"direction"}) // inbound, outbound
+
+	metricErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dock_direction_errors_total",
+		Help: "Lookup and write errors in the direction engine (best-effort; do not block ingestion).",
+	})
+)
@@ -0,0 +1,11 @@
+package documents
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricNotices = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "document_expiry_notices_total",
+	Help: "Document expiry notices raised, by kind.",
+}, []string{"kind"}) // expiring, expired
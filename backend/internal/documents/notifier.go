@@ -0,0 +1,70 @@
+// Package documents runs the document vault's expiry alerts. The Notifier's
+// Run job finds the orgs holding a document that entered its reminder window
+// or passed its expiry date, and raises document.expiring / document.expired
+// for it through the event pipeline, so org webhooks and the event export
+// see it like any other alert.
+//
+// Each notice is stamped on the document in the transaction that raises it,
+// so it fires once across replicas; one org's failure does not stop the rest.
+package documents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// notifierStore is the storage surface the notifier needs; *storage.Storage
+// satisfies it.
+type notifierStore interface {
+	ListOrgsWithDueDocumentNotices(ctx context.Context, now time.Time) ([]int, error)
+	NotifyDocumentExpiry(ctx context.Context, orgID int, now time.Time) (expiring, expired int, err error)
+}
+
+// Notifier raises the expiry notices of every org's documents.
+type Notifier struct {
+	store notifierStore
+	log   zerolog.Logger
+	now   func() time.Time
+}
+
+// NewNotifier builds the document expiry job over store.
+func NewNotifier(store notifierStore, log *zerolog.Logger) *Notifier {
+	return &Notifier{
+		store: store,
+		log:   log.With().Str("component", "documents").Logger(),
+		now:   time.Now,
+	}
+}
+
+// Run raises every due notice once. The returned error joins the per-org
+// failures.
+func (n *Notifier) Run(ctx context.Context) error {
+	now := n.now()
+	orgs, err := n.store.ListOrgsWithDueDocumentNotices(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, orgID := range orgs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		expiring, expired, err := n.store.NotifyDocumentExpiry(ctx, orgID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("org %d: %w", orgID, err))
+			continue
+		}
+		metricNotices.WithLabelValues("expiring").Add(float64(expiring))
+		metricNotices.WithLabelValues("expired").Add(float64(expired))
+		if expiring > 0 || expired > 0 {
+			n.log.Info().Int("org_id", orgID).Int("expiring", expiring).Int("expired", expired).
+				Msg("raised document expiry notices")
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,66 @@
+package documents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	orgs     []int
+	listErr  error
+	failOrg  int
+	notified map[int]time.Time
+	listedAt time.Time
+}
+
+func (f *fakeStore) ListOrgsWithDueDocumentNotices(_ context.Context, now time.Time) ([]int, error) {
+	f.listedAt = now
+	return f.orgs, f.listErr
+}
+
+func (f *fakeStore) NotifyDocumentExpiry(_ context.Context, orgID int, now time.Time) (int, int, error) {
+	if orgID == f.failOrg {
+		return 0, 0, errors.New("db down")
+	}
+	f.notified[orgID] = now
+	return 1, 1, nil
+}
+
+func testNotifier(store notifierStore, now time.Time) *Notifier {
+	log := zerolog.Nop()
+	n := NewNotifier(store, &log)
+	n.now = func() time.Time { return now }
+	return n
+}
+
+func TestRun_NotifiesEachOrgAtOneInstant(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{orgs: []int{1, 2}, notified: map[int]time.Time{}}
+
+	require.NoError(t, testNotifier(store, now).Run(t.Context()))
+
+	assert.Equal(t, now, store.listedAt)
+	assert.Equal(t, map[int]time.Time{1: now, 2: now}, store.notified)
+}
+
+func TestRun_OneOrgFailingDoesNotStopTheRest(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	store := &fakeStore{orgs: []int{1, 2, 3}, failOrg: 2, notified: map[int]time.Time{}}
+
+	err := testNotifier(store, now).Run(t.Context())
+
+	assert.ErrorContains(t, err, "org 2")
+	assert.Contains(t, store.notified, 1)
+	assert.Contains(t, store.notified, 3)
+}
+
+func TestRun_ListErrorFailsTheRun(t *testing.T) {
+	store := &fakeStore{listErr: errors.New("db down")}
+	assert.Error(t, testNotifier(store, time.Now()).Run(t.Context()))
+}
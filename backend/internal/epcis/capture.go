@@ -0,0 +1,174 @@
+package epcis
+
+import (
+	"fmt"
+	"time"
+
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+)
+
+// Lookup is the set of identifiers a capture document references, for
+// resolution against the org's tags, assets and locations in one pass.
+type Lookup struct {
+	AssetTags    []string // normalized tag values
+	AssetKeys    []string
+	LocationTags []string // normalized tag values
+	LocationKeys []string
+}
+
+// Resolved maps the identifiers of a Lookup to live asset and location ids.
+// Tag maps are keyed by normalized tag value.
+type Resolved struct {
+	AssetsByTag    map[string]int
+	AssetsByKey    map[string]int
+	LocationsByTag map[string]int
+	LocationsByKey map[string]int
+}
+
+// CapturedScan is one asset_scans row to insert from a captured event.
+type CapturedScan struct {
+	Timestamp  time.Time
+	AssetID    int
+	LocationID *int
+}
+
+// ref is a parsed EPC or location identifier: candidate tag values, or a
+// trakrf external key.
+type ref struct {
+	tags []string
+	key  string
+}
+
+func parseEPC(uri string) (ref, bool) {
+	if key, ok := parsePrivate(uri, "asset"); ok {
+		return ref{key: key}, true
+	}
+	tags, ok := TagValues(uri)
+	return ref{tags: tags}, ok
+}
+
+func parseLocation(uri string) (ref, bool) {
+	if key, ok := parsePrivate(uri, "location"); ok {
+		return ref{key: key}, true
+	}
+	tags, ok := TagValues(uri)
+	return ref{tags: tags}, ok
+}
+
+func (r ref) resolve(byTag, byKey map[string]int) (int, bool) {
+	if r.key != "" {
+		id, ok := byKey[r.key]
+		return id, ok
+	}
+	for _, t := range r.tags {
+		if id, ok := byTag[t]; ok {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func eventField(i int, name string) string {
+	return fmt.Sprintf("epcisBody.eventList[%d].%s", i, name)
+}
+
+func invalid(field, msg string) modelerrors.FieldError {
+	return modelerrors.FieldError{Field: field, Code: "invalid_value", Message: msg}
+}
+
+// CaptureLookup validates captured events and collects the identifiers they
+// reference. Only OBSERVE and ADD ObjectEvents are accepted: both record that
+// the objects were seen at the event's bizLocation.
+func CaptureLookup(events []ObjectEvent) (Lookup, []modelerrors.FieldError) {
+	var l Lookup
+	var problems []modelerrors.FieldError
+	add := func(r ref, tags, keys *[]string) {
+		if r.key != "" {
+			*keys = append(*keys, r.key)
+		} else {
+			*tags = append(*tags, r.tags...)
+		}
+	}
+
+	for i, ev := range events {
+		if ev.Type != EventTypeObject {
+			problems = append(problems, invalid(eventField(i, "type"),
+				fmt.Sprintf("event type %q is not supported; only ObjectEvent is captured", ev.Type)))
+			continue
+		}
+		if ev.Action != ActionObserve && ev.Action != ActionAdd {
+			problems = append(problems, invalid(eventField(i, "action"),
+				fmt.Sprintf("action %q is not supported; use OBSERVE or ADD", ev.Action)))
+		}
+		if ev.EventTime.IsZero() {
+			problems = append(problems, modelerrors.FieldError{Field: eventField(i, "eventTime"),
+				Code: "required", Message: "eventTime is required"})
+		}
+		if len(ev.EPCList) == 0 {
+			problems = append(problems, modelerrors.FieldError{Field: eventField(i, "epcList"),
+				Code: "required", Message: "epcList must name at least one EPC"})
+		}
+		for j, epc := range ev.EPCList {
+			r, ok := parseEPC(epc)
+			if !ok {
+				problems = append(problems, invalid(eventField(i, fmt.Sprintf("epcList[%d]", j)),
+					fmt.Sprintf("%q is not a 96-bit EPC URI or trakrf asset URI", epc)))
+				continue
+			}
+			add(r, &l.AssetTags, &l.AssetKeys)
+		}
+		if ev.BizLocation != nil {
+			r, ok := parseLocation(ev.BizLocation.ID)
+			if !ok {
+				problems = append(problems, invalid(eventField(i, "bizLocation.id"),
+					fmt.Sprintf("%q is not an SGLN or trakrf location URI", ev.BizLocation.ID)))
+				continue
+			}
+			add(r, &l.LocationTags, &l.LocationKeys)
+		}
+	}
+	return l, problems
+}
+
+// CaptureScans maps validated events to scan rows, one per (event time,
+// asset). Identifiers that resolved to nothing are reported rather than
+// skipped so a partner's typo does not silently drop sightings.
+func CaptureScans(events []ObjectEvent, res Resolved) ([]CapturedScan, []modelerrors.FieldError) {
+	var scans []CapturedScan
+	var problems []modelerrors.FieldError
+	type key struct {
+		at      int64
+		assetID int
+	}
+	seen := map[key]bool{}
+
+	for i, ev := range events {
+		var locationID *int
+		if ev.BizLocation != nil {
+			r, _ := parseLocation(ev.BizLocation.ID)
+			id, ok := r.resolve(res.LocationsByTag, res.LocationsByKey)
+			if !ok {
+				problems = append(problems, invalid(eventField(i, "bizLocation.id"),
+					fmt.Sprintf("location %q not found", ev.BizLocation.ID)))
+				continue
+			}
+			locationID = &id
+		}
+		for j, epc := range ev.EPCList {
+			r, _ := parseEPC(epc)
+			id, ok := r.resolve(res.AssetsByTag, res.AssetsByKey)
+			if !ok {
+				problems = append(problems, invalid(eventField(i, fmt.Sprintf("epcList[%d]", j)),
+					fmt.Sprintf("no asset is tagged %q", epc)))
+				continue
+			}
+			k := key{ev.EventTime.UnixNano(), id}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			scans = append(scans, CapturedScan{Timestamp: ev.EventTime, AssetID: id, LocationID: locationID})
+		}
+	}
+	return scans, problems
+}
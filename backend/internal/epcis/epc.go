@@ -0,0 +1,245 @@
+package epcis
+
+import (
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// scheme describes one GS1 96-bit EPC binary encoding (GS1 TDS 2.x §14):
+// header(8) filter(3) partition(3) company prefix + reference, then an
+// optional numeric tail (serial or extension).
+type scheme struct {
+	name      string
+	header    byte
+	fieldBits int // company prefix + reference
+	tailBits  int
+	refDigits int // company prefix + reference digits; 0 = reference is not padded
+}
+
+var schemes = []scheme{
+	{name: "sgtin", header: 0x30, fieldBits: 44, tailBits: 38, refDigits: 13},
+	{name: "sgln", header: 0x32, fieldBits: 41, tailBits: 41, refDigits: 12},
+	{name: "grai", header: 0x33, fieldBits: 44, tailBits: 38, refDigits: 12},
+	{name: "giai", header: 0x34, fieldBits: 82},
+}
+
+// companyBits is indexed by partition value; the company prefix has 12-p digits.
+var companyBits = [7]int{40, 37, 34, 30, 27, 24, 20}
+
+var pow10 = func() [20]uint64 {
+	var p [20]uint64
+	p[0] = 1
+	for i := 1; i < len(p); i++ {
+		p[i] = p[i-1] * 10
+	}
+	return p
+}()
+
+// NormalizeTagValue mirrors trakrf.normalize_tag_value: uppercase, drop
+// non-hex characters, strip leading zeros keeping at least one digit.
+func NormalizeTagValue(v string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(v) {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'F') {
+			b.WriteRune(r)
+		}
+	}
+	s := strings.TrimLeft(b.String(), "0")
+	if s == "" && b.Len() > 0 {
+		return "0"
+	}
+	return s
+}
+
+var hexValue = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
+// EPCURI renders an RFID tag value as an EPC URI: the pure identity URI
+// (urn:epc:id:...) when it is a valid SGTIN-96, SGLN-96, GRAI-96 or GIAI-96,
+// otherwise the raw URI (urn:epc:raw:<bits>.x<HEX>). ok is false for values
+// that are not hex, which have no EPC form.
+func EPCURI(tagValue string) (uri string, ok bool) {
+	v := strings.TrimSpace(tagValue)
+	if v == "" || !hexValue.MatchString(v) {
+		return "", false
+	}
+	v = strings.ToUpper(v)
+	if len(v) == 24 {
+		if id, ok := decode96(v); ok {
+			return id, true
+		}
+	}
+	return fmt.Sprintf("urn:epc:raw:%d.x%s", len(v)*4, v), true
+}
+
+func decode96(v string) (string, bool) {
+	raw, err := hex.DecodeString(v)
+	if err != nil || len(raw) != 12 {
+		return "", false
+	}
+	for _, sc := range schemes {
+		if raw[0] != sc.header {
+			continue
+		}
+		p := int(bitsAt(raw, 11, 3))
+		if p >= len(companyBits) {
+			return "", false
+		}
+		cd, cb := 12-p, companyBits[p]
+		company := bitsAt(raw, 14, cb)
+		ref := bitsAt(raw, 14+cb, sc.fieldBits-cb)
+		if company >= pow10[cd] {
+			return "", false
+		}
+		if sc.refDigits == 0 {
+			return fmt.Sprintf("urn:epc:id:%s:%0*d.%d", sc.name, cd, company, ref), true
+		}
+		rd := sc.refDigits - cd
+		if ref >= pow10[rd] {
+			return "", false
+		}
+		refStr := ""
+		if rd > 0 {
+			refStr = fmt.Sprintf("%0*d", rd, ref)
+		}
+		tail := bitsAt(raw, 14+sc.fieldBits, sc.tailBits)
+		return fmt.Sprintf("urn:epc:id:%s:%0*d.%s.%d", sc.name, cd, company, refStr, tail), true
+	}
+	return "", false
+}
+
+// TagValues returns the normalized tag values an EPC URI can be stored as.
+// A pure identity URI omits the filter value, so it yields one candidate per
+// filter; tag (urn:epc:tag:<scheme>-96:...) and raw URIs yield exactly one.
+// ok is false for URIs that are not EPCs or not 96-bit encodable.
+func TagValues(uri string) ([]string, bool) {
+	switch {
+	case strings.HasPrefix(uri, "urn:epc:raw:"):
+		_, x, found := strings.Cut(strings.TrimPrefix(uri, "urn:epc:raw:"), ".x")
+		if !found || x == "" || !hexValue.MatchString(x) {
+			return nil, false
+		}
+		return []string{NormalizeTagValue(x)}, true
+	case strings.HasPrefix(uri, "urn:epc:tag:"):
+		name, rest, found := strings.Cut(strings.TrimPrefix(uri, "urn:epc:tag:"), "-96:")
+		if !found {
+			return nil, false
+		}
+		filter, fields, found := strings.Cut(rest, ".")
+		f, err := strconv.Atoi(filter)
+		if !found || err != nil || f < 0 || f > 7 {
+			return nil, false
+		}
+		v, ok := encode96(name, f, fields)
+		if !ok {
+			return nil, false
+		}
+		return []string{v}, true
+	case strings.HasPrefix(uri, "urn:epc:id:"):
+		name, fields, found := strings.Cut(strings.TrimPrefix(uri, "urn:epc:id:"), ":")
+		if !found {
+			return nil, false
+		}
+		out := make([]string, 0, 8)
+		for f := 0; f <= 7; f++ {
+			v, ok := encode96(name, f, fields)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, v)
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// encode96 is the inverse of decode96 for the dot-separated URI fields.
+func encode96(name string, filter int, fields string) (string, bool) {
+	var sc *scheme
+	for i := range schemes {
+		if schemes[i].name == name {
+			sc = &schemes[i]
+		}
+	}
+	if sc == nil {
+		return "", false
+	}
+	parts := strings.Split(fields, ".")
+	want := 3
+	if sc.refDigits == 0 {
+		want = 2
+	}
+	if len(parts) != want {
+		return "", false
+	}
+	cd := len(parts[0])
+	p := 12 - cd
+	if p < 0 || p >= len(companyBits) {
+		return "", false
+	}
+	cb := companyBits[p]
+	if sc.refDigits != 0 && len(parts[1]) != sc.refDigits-cd {
+		return "", false
+	}
+	company, ok := parseDigits(parts[0], cd)
+	if !ok {
+		return "", false
+	}
+	ref, ok := parseDigits(parts[1], len(parts[1]))
+	if !ok || (sc.refDigits == 0 && (parts[1] == "" || (parts[1][0] == '0' && parts[1] != "0"))) {
+		return "", false
+	}
+	if ref >= 1<<(sc.fieldBits-cb) {
+		return "", false
+	}
+	var tail uint64
+	if sc.tailBits > 0 {
+		if parts[2] == "" || (len(parts[2]) > 1 && parts[2][0] == '0') {
+			return "", false
+		}
+		if tail, ok = parseDigits(parts[2], len(parts[2])); !ok || tail >= 1<<sc.tailBits {
+			return "", false
+		}
+	}
+
+	raw := make([]byte, 12)
+	raw[0] = sc.header
+	putBits(raw, 8, 3, uint64(filter))
+	putBits(raw, 11, 3, uint64(p))
+	putBits(raw, 14, cb, company)
+	putBits(raw, 14+cb, sc.fieldBits-cb, ref)
+	putBits(raw, 14+sc.fieldBits, sc.tailBits, tail)
+	return strings.ToUpper(hex.EncodeToString(raw)), true
+}
+
+// parseDigits parses s as exactly n decimal digits; an empty s is 0 when n is 0.
+func parseDigits(s string, n int) (uint64, bool) {
+	if len(s) != n || n > 19 {
+		return 0, false
+	}
+	if n == 0 {
+		return 0, true
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	return v, err == nil
+}
+
+func bitsAt(b []byte, off, n int) uint64 {
+	var v uint64
+	for i := off; i < off+n; i++ {
+		v = v<<1 | uint64(b[i/8]>>(7-i%8)&1)
+	}
+	return v
+}
+
+func putBits(b []byte, off, n int, v uint64) {
+	for i := n - 1; i >= 0; i-- {
+		pos := off + i
+		if v&1 == 1 {
+			b[pos/8] |= 1 << (7 - pos%8)
+		}
+		v >>= 1
+	}
+}
@@ -0,0 +1,208 @@
+// Package epcis maps asset scans to and from GS1 EPCIS 2.0 ObjectEvents
+// (JSON/JSON-LD binding) so supply-chain partners can consume our sightings,
+// and contribute their own, in the standard format.
+//
+// Each asset_scans row is one OBSERVE event. Assets are identified by the EPC
+// URIs of their RFID tags (pure identity for GS1 96-bit encodings, raw
+// otherwise) and fall back to a trakrf asset URI when they carry no RFID tag;
+// locations use the SGLN of an SGLN-96 tag when they have one.
+package epcis
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ContextURL    = "https://ref.gs1.org/standards/epcis/2.0.0/epcis-context.jsonld"
+	SchemaVersion = "2.0"
+	// Version is sent as the GS1-EPCIS-Version and GS1-CBV-Version headers.
+	Version = "2.0.0"
+	// Namespace prefixes trakrf identifiers and the trakrf: extension fields.
+	Namespace = "https://trakrf.id/epcis/"
+
+	EventTypeObject  = "ObjectEvent"
+	ActionObserve    = "OBSERVE"
+	ActionAdd        = "ADD"
+	BizStepObserving = "observing"
+)
+
+// Context is the @context of every document we emit: the EPCIS 2.0 context
+// plus the trakrf extension prefix.
+var Context = []any{ContextURL, map[string]string{"trakrf": Namespace}}
+
+// Ref is an EPCIS readPoint or bizLocation.
+type Ref struct {
+	ID string `json:"id"`
+}
+
+// ObjectEvent is the subset of the EPCIS 2.0 ObjectEvent we produce and
+// accept. Unknown fields in captured documents are ignored.
+type ObjectEvent struct {
+	Type                string     `json:"type"`
+	EventID             string     `json:"eventID,omitempty"`
+	EventTime           time.Time  `json:"eventTime"`
+	EventTimeZoneOffset string     `json:"eventTimeZoneOffset"`
+	RecordTime          *time.Time `json:"recordTime,omitempty"`
+	EPCList             []string   `json:"epcList"`
+	Action              string     `json:"action"`
+	BizStep             string     `json:"bizStep,omitempty"`
+	ReadPoint           *Ref       `json:"readPoint,omitempty"`
+	BizLocation         *Ref       `json:"bizLocation,omitempty"`
+	AssetIdentifier     string     `json:"trakrf:assetIdentifier,omitempty"`
+}
+
+// Document is an EPCISDocument, the capture payload.
+type Document struct {
+	Context       any       `json:"@context"`
+	Type          string    `json:"type"`
+	SchemaVersion string    `json:"schemaVersion"`
+	CreationDate  time.Time `json:"creationDate"`
+	EPCISBody     struct {
+		EventList []ObjectEvent `json:"eventList"`
+	} `json:"epcisBody"`
+}
+
+// QueryDocument is an EPCISQueryDocument, the query response.
+type QueryDocument struct {
+	Context       any       `json:"@context"`
+	Type          string    `json:"type"`
+	SchemaVersion string    `json:"schemaVersion"`
+	CreationDate  time.Time `json:"creationDate"`
+	EPCISBody     QueryBody `json:"epcisBody"`
+}
+
+type QueryBody struct {
+	QueryResults QueryResults `json:"queryResults"`
+}
+
+type QueryResults struct {
+	QueryName   string      `json:"queryName"`
+	ResultsBody ResultsBody `json:"resultsBody"`
+}
+
+type ResultsBody struct {
+	EventList []ObjectEvent `json:"eventList"`
+}
+
+// NewQueryDocument wraps events as the result of a SimpleEventQuery.
+func NewQueryDocument(events []ObjectEvent, now time.Time) QueryDocument {
+	return QueryDocument{
+		Context:       Context,
+		Type:          "EPCISQueryDocument",
+		SchemaVersion: SchemaVersion,
+		CreationDate:  now.UTC(),
+		EPCISBody: QueryBody{QueryResults: QueryResults{
+			QueryName:   "SimpleEventQuery",
+			ResultsBody: ResultsBody{EventList: events},
+		}},
+	}
+}
+
+// AssetURI identifies an asset with no RFID tag by its external key.
+func AssetURI(externalKey string) string {
+	return Namespace + "asset/" + url.PathEscape(externalKey)
+}
+
+// LocationURI identifies a location by its external key.
+func LocationURI(externalKey string) string {
+	return Namespace + "location/" + url.PathEscape(externalKey)
+}
+
+// ScanPointURI identifies a scan point (the EPCIS read point) by its identifier.
+func ScanPointURI(identifier string) string {
+	return Namespace + "scan-point/" + url.PathEscape(identifier)
+}
+
+// parsePrivate returns the key of a trakrf URI of the given kind.
+func parsePrivate(uri, kind string) (string, bool) {
+	rest, ok := strings.CutPrefix(uri, Namespace+kind+"/")
+	if !ok || rest == "" {
+		return "", false
+	}
+	key, err := url.PathUnescape(rest)
+	return key, err == nil
+}
+
+// Scan is one asset_scans row with the identifiers needed to render it.
+type Scan struct {
+	Timestamp           time.Time
+	RecordedAt          time.Time
+	AssetID             int
+	AssetExternalKey    string
+	AssetTags           []string // live RFID tag values
+	LocationExternalKey *string
+	LocationTags        []string // live RFID tag values
+	ScanPointIdentifier *string
+}
+
+// Cursor is a position in the scan stream: scans strictly after
+// (At, AssetID) come next. asset_scans is unique on (timestamp, asset_id)
+// within an org.
+type Cursor struct {
+	At      time.Time
+	AssetID int
+}
+
+// Filter narrows a scan query. Nil slices do not filter; an empty non-nil
+// slice matches nothing.
+type Filter struct {
+	From        *time.Time // inclusive
+	To          *time.Time // exclusive
+	AssetIDs    []int
+	LocationIDs []int
+	After       *Cursor
+	Limit       int
+}
+
+// NewObjectEvent renders a scan as an OBSERVE ObjectEvent.
+func NewObjectEvent(s Scan) ObjectEvent {
+	ev := ObjectEvent{
+		Type:                EventTypeObject,
+		EventID:             fmt.Sprintf("%sevent/%d-%d", Namespace, s.AssetID, s.Timestamp.UnixNano()),
+		EventTime:           s.Timestamp.UTC(),
+		EventTimeZoneOffset: "+00:00",
+		EPCList:             []string{},
+		Action:              ActionObserve,
+		BizStep:             BizStepObserving,
+		AssetIdentifier:     s.AssetExternalKey,
+	}
+	if !s.RecordedAt.IsZero() {
+		rt := s.RecordedAt.UTC()
+		ev.RecordTime = &rt
+	}
+
+	seen := map[string]bool{}
+	for _, v := range s.AssetTags {
+		if uri, ok := EPCURI(v); ok && !seen[uri] {
+			seen[uri] = true
+			ev.EPCList = append(ev.EPCList, uri)
+		}
+	}
+	if len(ev.EPCList) == 0 {
+		ev.EPCList = append(ev.EPCList, AssetURI(s.AssetExternalKey))
+	}
+
+	if loc := locationID(s); loc != "" {
+		ev.BizLocation = &Ref{ID: loc}
+		ev.ReadPoint = &Ref{ID: loc}
+	}
+	if s.ScanPointIdentifier != nil {
+		ev.ReadPoint = &Ref{ID: ScanPointURI(*s.ScanPointIdentifier)}
+	}
+	return ev
+}
+
+func locationID(s Scan) string {
+	for _, v := range s.LocationTags {
+		if uri, ok := EPCURI(v); ok && strings.HasPrefix(uri, "urn:epc:id:sgln:") {
+			return uri
+		}
+	}
+	if s.LocationExternalKey != nil {
+		return LocationURI(*s.LocationExternalKey)
+	}
+	return ""
+}
@@ -0,0 +1,223 @@
+package epcis
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// GS1 TDS worked example: urn:epc:tag:sgtin-96:3.0614141.812345.6789.
+const tdsSGTIN = "3074257BF7194E4000001A85"
+
+func TestEPCURI(t *testing.T) {
+	cases := map[string]string{
+		tdsSGTIN:                   "urn:epc:id:sgtin:0614141.812345.6789",
+		"3074257bf7194e4000001a85": "urn:epc:id:sgtin:0614141.812345.6789",
+		"E28011606000020A1B2C3D4E": "urn:epc:raw:96.xE28011606000020A1B2C3D4E",
+		"ABCD":                     "urn:epc:raw:16.xABCD",
+		"307FFFFFFFFFFFFFFFFFFFFF": "urn:epc:raw:96.x307FFFFFFFFFFFFFFFFFFFFF", // partition 7 is reserved
+	}
+	for in, want := range cases {
+		got, ok := EPCURI(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, ok := EPCURI("ASSET-0001")
+	assert.False(t, ok, "non-hex values have no EPC form")
+}
+
+func TestTagValues_RoundTrip(t *testing.T) {
+	got, ok := TagValues("urn:epc:tag:sgtin-96:3.0614141.812345.6789")
+	require.True(t, ok)
+	assert.Equal(t, []string{tdsSGTIN}, got)
+
+	for _, uri := range []string{
+		"urn:epc:id:sgtin:0614141.812345.6789",
+		"urn:epc:id:sgln:0614141.12345.400",
+		"urn:epc:id:sgln:061414112345..0", // partition 0: no location reference digits
+		"urn:epc:id:grai:0614141.12345.400",
+		"urn:epc:id:giai:0614141.5678",
+	} {
+		t.Run(uri, func(t *testing.T) {
+			candidates, ok := TagValues(uri)
+			require.True(t, ok)
+			require.Len(t, candidates, 8, "one candidate per filter value")
+			for _, v := range candidates {
+				back, ok := EPCURI(v)
+				require.True(t, ok)
+				assert.Equal(t, uri, back)
+			}
+		})
+	}
+
+	got, ok = TagValues("urn:epc:raw:96.x00E2801160")
+	require.True(t, ok)
+	assert.Equal(t, []string{"E2801160"}, got, "raw values are normalized like tags.normalized_value")
+
+	for _, uri := range []string{
+		"urn:epc:id:sgtin:0614141.81234.6789",   // reference digits do not complete a GTIN
+		"urn:epc:id:sgtin:0614141.812345.06789", // serial with a leading zero is not 96-bit encodable
+		"urn:epc:id:sgtin:0614141.812345.ABC",
+		"urn:epc:id:giai:0614141.0567",
+		"urn:epc:id:sscc:0614141.1234567890",
+		"urn:epc:tag:sgtin-96:8.0614141.812345.6789",
+		"https://example.com/asset/1",
+	} {
+		_, ok := TagValues(uri)
+		assert.False(t, ok, uri)
+	}
+}
+
+func TestNormalizeTagValue(t *testing.T) {
+	assert.Equal(t, "E2801160", NormalizeTagValue("00e2:80:11:60"))
+	assert.Equal(t, "0", NormalizeTagValue("0000"))
+	assert.Equal(t, "", NormalizeTagValue("xyz"))
+}
+
+func TestNewObjectEvent(t *testing.T) {
+	at := time.Date(2026, 3, 1, 9, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	wh, sp := "WH/01", "DOCK-1"
+	ev := NewObjectEvent(Scan{
+		Timestamp:           at,
+		RecordedAt:          at.Add(time.Second),
+		AssetID:             42,
+		AssetExternalKey:    "PALLET-42",
+		AssetTags:           []string{tdsSGTIN, "3074257bf7194e4000001a85", "E2801160"},
+		LocationExternalKey: &wh,
+		ScanPointIdentifier: &sp,
+	})
+
+	assert.Equal(t, EventTypeObject, ev.Type)
+	assert.Equal(t, ActionObserve, ev.Action)
+	assert.Equal(t, at.UTC(), ev.EventTime)
+	assert.Equal(t, "+00:00", ev.EventTimeZoneOffset)
+	assert.Equal(t, []string{"urn:epc:id:sgtin:0614141.812345.6789", "urn:epc:raw:32.xE2801160"}, ev.EPCList)
+	assert.Equal(t, "https://trakrf.id/epcis/location/WH%2F01", ev.BizLocation.ID)
+	assert.Equal(t, "https://trakrf.id/epcis/scan-point/DOCK-1", ev.ReadPoint.ID)
+	assert.Equal(t, "PALLET-42", ev.AssetIdentifier)
+
+	untagged := NewObjectEvent(Scan{Timestamp: at, AssetID: 7, AssetExternalKey: "TOOL 7",
+		LocationTags: []string{"ABCD", mustTag(t, "urn:epc:id:sgln:0614141.12345.400")}})
+	assert.Equal(t, []string{"https://trakrf.id/epcis/asset/TOOL%207"}, untagged.EPCList)
+	assert.Equal(t, "urn:epc:id:sgln:0614141.12345.400", untagged.BizLocation.ID, "an SGLN tag identifies the location")
+	assert.Equal(t, untagged.BizLocation, untagged.ReadPoint)
+
+	b, err := json.Marshal(NewQueryDocument([]ObjectEvent{untagged}, at))
+	require.NoError(t, err)
+	assert.Contains(t, string(b), `"type":"EPCISQueryDocument"`)
+	assert.Contains(t, string(b), `"queryName":"SimpleEventQuery"`)
+	assert.Contains(t, string(b), `"trakrf:assetIdentifier":"TOOL 7"`)
+}
+
+func mustTag(t *testing.T, uri string) string {
+	t.Helper()
+	v, ok := TagValues(uri)
+	require.True(t, ok)
+	return v[3]
+}
+
+func TestCapture(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []ObjectEvent{
+		{Type: EventTypeObject, Action: ActionObserve, EventTime: at,
+			EPCList:     []string{"urn:epc:id:sgtin:0614141.812345.6789", "https://trakrf.id/epcis/asset/TOOL%207"},
+			BizLocation: &Ref{ID: "https://trakrf.id/epcis/location/WH-01"}},
+		{Type: EventTypeObject, Action: ActionAdd, EventTime: at,
+			EPCList: []string{"urn:epc:raw:96.x" + tdsSGTIN}},
+	}
+
+	lookup, problems := CaptureLookup(events)
+	require.Empty(t, problems)
+	assert.Len(t, lookup.AssetTags, 9)
+	assert.Equal(t, []string{"TOOL 7"}, lookup.AssetKeys)
+	assert.Equal(t, []string{"WH-01"}, lookup.LocationKeys)
+
+	scans, problems := CaptureScans(events, Resolved{
+		AssetsByTag:    map[string]int{tdsSGTIN: 1},
+		AssetsByKey:    map[string]int{"TOOL 7": 2},
+		LocationsByKey: map[string]int{"WH-01": 10},
+	})
+	require.Empty(t, problems)
+	loc := 10
+	assert.Equal(t, []CapturedScan{
+		{Timestamp: at, AssetID: 1, LocationID: &loc},
+		{Timestamp: at, AssetID: 2, LocationID: &loc},
+	}, scans, "the second event re-sights asset 1 at the same instant")
+
+	_, problems = CaptureScans(events, Resolved{AssetsByTag: map[string]int{}, AssetsByKey: map[string]int{"TOOL 7": 2}})
+	require.Len(t, problems, 2)
+	assert.Equal(t, "epcisBody.eventList[0].bizLocation.id", problems[0].Field)
+	assert.Equal(t, "epcisBody.eventList[1].epcList[0]", problems[1].Field)
+}
+
+func TestCaptureLookup_Rejects(t *testing.T) {
+	_, problems := CaptureLookup([]ObjectEvent{
+		{Type: "AggregationEvent"},
+		{Type: EventTypeObject, Action: "DELETE", EPCList: []string{"urn:epc:id:sgtin:1.2.3"}},
+	})
+	fields := []string{}
+	for _, p := range problems {
+		fields = append(fields, p.Field)
+	}
+	assert.Equal(t, []string{
+		"epcisBody.eventList[0].type",
+		"epcisBody.eventList[1].action",
+		"epcisBody.eventList[1].eventTime",
+		"epcisBody.eventList[1].epcList[0]",
+	}, fields)
+}
+
+// FuzzEPCURI checks that any tag value renders without panicking and that a
+// pure identity URI maps back to the tag it came from: capture resolves
+// sightings through TagValues, so a lossy decode would lose them. Run with:
+//
+//	go test -run '^$' -fuzz FuzzEPCURI ./internal/epcis/
+func FuzzEPCURI(f *testing.F) {
+	for _, s := range []string{tdsSGTIN, "E28011606000020A1B2C3D4E", "ABCD", "307FFFFFFFFFFFFFFFFFFFFF", "3274257BF7194E4000001A85", "3474257BF7194E4000001A85", "", "xyz"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		uri, ok := EPCURI(value)
+		if !ok {
+			return
+		}
+		values, ok := TagValues(uri)
+		if !ok {
+			t.Fatalf("EPCURI(%q) = %q, which TagValues rejects", value, uri)
+		}
+		want := NormalizeTagValue(value)
+		for _, v := range values {
+			if v == want || NormalizeTagValue(v) == want {
+				return
+			}
+		}
+		t.Fatalf("EPCURI(%q) = %q, whose tag values %v miss %q", value, uri, values, want)
+	})
+}
+
+// FuzzTagValues checks that no captured URI panics the parser and that
+// every candidate it yields is hex already in normalized form.
+func FuzzTagValues(f *testing.F) {
+	for _, s := range []string{
+		"urn:epc:id:sgtin:0614141.812345.6789", "urn:epc:tag:sgtin-96:3.0614141.812345.6789",
+		"urn:epc:raw:96.x3074257BF7194E4000001A85", "urn:epc:id:giai:0614141.12345", "urn:epc:id:sgln:0614141.12345.0",
+		"urn:epc:tag:sgtin-96:9.0614141.812345.6789", "urn:epc:id:sgtin:.", "urn:epc:raw:.x",
+	} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, uri string) {
+		values, ok := TagValues(uri)
+		if !ok {
+			return
+		}
+		for _, v := range values {
+			if !hexValue.MatchString(v) || NormalizeTagValue(v) != v {
+				t.Fatalf("TagValues(%q) yields %q", uri, v)
+			}
+		}
+	})
+}
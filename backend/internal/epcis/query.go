@@ -0,0 +1,78 @@
+package epcis
+
+import (
+	"fmt"
+	"strings"
+
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+)
+
+// QueryLookup parses the MATCH_epc and EQ_bizLocation values of an event
+// query. Only exact identifiers are supported, not EPC patterns
+// (urn:epc:idpat:...).
+func QueryLookup(epcs, locations []string) (Lookup, []modelerrors.FieldError) {
+	var l Lookup
+	var problems []modelerrors.FieldError
+	for _, v := range epcs {
+		if strings.HasPrefix(v, "urn:epc:idpat:") {
+			problems = append(problems, invalid("MATCH_epc", "EPC patterns are not supported; pass exact EPC URIs"))
+			continue
+		}
+		r, ok := parseEPC(v)
+		if !ok {
+			problems = append(problems, invalid("MATCH_epc",
+				fmt.Sprintf("%q is not a 96-bit EPC URI or trakrf asset URI", v)))
+			continue
+		}
+		if r.key != "" {
+			l.AssetKeys = append(l.AssetKeys, r.key)
+		} else {
+			l.AssetTags = append(l.AssetTags, r.tags...)
+		}
+	}
+	for _, v := range locations {
+		r, ok := parseLocation(v)
+		if !ok {
+			problems = append(problems, invalid("EQ_bizLocation",
+				fmt.Sprintf("%q is not an SGLN or trakrf location URI", v)))
+			continue
+		}
+		if r.key != "" {
+			l.LocationKeys = append(l.LocationKeys, r.key)
+		} else {
+			l.LocationTags = append(l.LocationTags, r.tags...)
+		}
+	}
+	return l, problems
+}
+
+// AssetIDs returns the assets the given EPCs resolved to, for Filter.AssetIDs:
+// nil when no EPCs were given, empty when none of them matched.
+func (res Resolved) AssetIDs(epcs []string) []int {
+	if len(epcs) == 0 {
+		return nil
+	}
+	ids := []int{}
+	for _, v := range epcs {
+		r, _ := parseEPC(v)
+		if id, ok := r.resolve(res.AssetsByTag, res.AssetsByKey); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// LocationIDs is AssetIDs for bizLocation identifiers.
+func (res Resolved) LocationIDs(locations []string) []int {
+	if len(locations) == 0 {
+		return nil
+	}
+	ids := []int{}
+	for _, v := range locations {
+		r, _ := parseLocation(v)
+		if id, ok := r.resolve(res.LocationsByTag, res.LocationsByKey); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
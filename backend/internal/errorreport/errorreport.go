@@ -0,0 +1,137 @@
+// Package errorreport sends panics and unexpected errors to an external error
+// sink. Sentry is the only sink today; with none configured, reports are
+// dropped and the caller's own log line is the only record.
+//
+// Every report carries tags for correlation: request_id for HTTP requests,
+// job and ids for background work. Auth headers and cookies never leave the
+// process (see BeforeSend).
+package errorreport
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getsentry/sentry-go"
+
+	"github.com/trakrf/platform/backend/internal/util/pii"
+)
+
+// Tags are searchable key/value pairs attached to a report.
+type Tags map[string]string
+
+// Sink receives reports. Implementations must be safe for concurrent use.
+type Sink interface {
+	CapturePanic(ctx context.Context, recovered any, tags Tags)
+	CaptureError(ctx context.Context, err error, tags Tags)
+}
+
+var (
+	mu   sync.RWMutex
+	sink Sink
+)
+
+// SetSink installs s as the sink; nil turns reporting off.
+func SetSink(s Sink) {
+	mu.Lock()
+	defer mu.Unlock()
+	sink = s
+}
+
+func current() Sink {
+	mu.RLock()
+	defer mu.RUnlock()
+	return sink
+}
+
+// Panic reports a value recovered from a panic.
+func Panic(ctx context.Context, recovered any, tags Tags) {
+	if s := current(); s != nil {
+		s.CapturePanic(ctx, recovered, tags)
+	}
+}
+
+// Error reports an error that should never happen in normal operation.
+func Error(ctx context.Context, err error, tags Tags) {
+	if s := current(); s != nil && err != nil {
+		s.CaptureError(ctx, err, tags)
+	}
+}
+
+// SentrySink reports to Sentry through the hub on the context (set per request
+// by sentryhttp, already carrying the request and user), or a clone of the
+// current hub outside a request. Initialise the SDK with BeforeSend.
+type SentrySink struct{}
+
+func (SentrySink) CapturePanic(ctx context.Context, recovered any, tags Tags) {
+	hub := hubFor(ctx)
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+		hub.RecoverWithContext(ctx, recovered)
+	})
+}
+
+func (SentrySink) CaptureError(ctx context.Context, err error, tags Tags) {
+	hub := hubFor(ctx)
+	hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+		hub.CaptureException(err)
+	})
+}
+
+func hubFor(ctx context.Context) *sentry.Hub {
+	if hub := sentry.GetHubFromContext(ctx); hub != nil {
+		return hub
+	}
+	return sentry.CurrentHub().Clone()
+}
+
+// scrubbedHeaders never reach the sink, whatever the SDK's PII settings.
+var scrubbedHeaders = map[string]struct{}{
+	"authorization":       {},
+	"proxy-authorization": {},
+	"cookie":              {},
+	"set-cookie":          {},
+	"x-api-key":           {},
+}
+
+// BeforeSend is the Sentry BeforeSend hook. It strips credentials from an
+// event's request (auth headers, cookies, a token in the query string),
+// redacts personal data from its message and error text (see pii), and
+// drops http.ErrAbortHandler, which a handler panics with on purpose to abort
+// a response it has already started.
+func BeforeSend(event *sentry.Event, hint *sentry.EventHint) *sentry.Event {
+	if hint != nil && hint.RecoveredException == http.ErrAbortHandler {
+		return nil
+	}
+	if event == nil {
+		return event
+	}
+	event.Message = pii.Redact(event.Message)
+	for i := range event.Exception {
+		event.Exception[i].Value = pii.Redact(event.Exception[i].Value)
+	}
+	if event.Request == nil {
+		return event
+	}
+	for k := range event.Request.Headers {
+		if _, ok := scrubbedHeaders[strings.ToLower(k)]; ok {
+			delete(event.Request.Headers, k)
+		}
+	}
+	event.Request.Cookies = ""
+	if strings.Contains(strings.ToLower(event.Request.QueryString), "token") {
+		event.Request.QueryString = "[scrubbed]"
+	}
+	return event
+}
+
+// RequestTags are the tags for a report made while serving r.
+func RequestTags(r *http.Request, requestID string) Tags {
+	return Tags{
+		"request_id": requestID,
+		"method":     r.Method,
+		"path":       r.URL.Path,
+	}
+}
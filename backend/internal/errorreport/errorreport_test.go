@@ -0,0 +1,82 @@
+package errorreport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	panics []any
+	errs   []error
+	tags   []Tags
+}
+
+func (s *recordingSink) CapturePanic(_ context.Context, recovered any, tags Tags) {
+	s.panics = append(s.panics, recovered)
+	s.tags = append(s.tags, tags)
+}
+
+func (s *recordingSink) CaptureError(_ context.Context, err error, tags Tags) {
+	s.errs = append(s.errs, err)
+	s.tags = append(s.tags, tags)
+}
+
+func TestReportsGoToTheSink(t *testing.T) {
+	rec := &recordingSink{}
+	SetSink(rec)
+	t.Cleanup(func() { SetSink(nil) })
+
+	Panic(context.Background(), "boom", Tags{"job": "outbox"})
+	Error(context.Background(), errors.New("bad"), Tags{"request_id": "r1"})
+	Error(context.Background(), nil, nil)
+
+	assert.Equal(t, []any{"boom"}, rec.panics)
+	require.Len(t, rec.errs, 1, "a nil error is not reported")
+	assert.EqualError(t, rec.errs[0], "bad")
+	assert.Equal(t, []Tags{{"job": "outbox"}, {"request_id": "r1"}}, rec.tags)
+}
+
+func TestNoSinkIsANoOp(t *testing.T) {
+	SetSink(nil)
+	assert.NotPanics(t, func() {
+		Panic(context.Background(), "boom", nil)
+		Error(context.Background(), errors.New("bad"), nil)
+	})
+}
+
+func TestBeforeSend_ScrubsCredentials(t *testing.T) {
+	ev := &sentry.Event{Request: &sentry.Request{
+		Headers: map[string]string{
+			"Authorization": "Bearer secret",
+			"X-Api-Key":     "k",
+			"Cookie":        "session=s",
+			"Accept":        "application/json",
+		},
+		Cookies:     "session=s",
+		QueryString: "token=abc",
+	}}
+
+	out := BeforeSend(ev, &sentry.EventHint{})
+	require.NotNil(t, out)
+	assert.Equal(t, map[string]string{"Accept": "application/json"}, out.Request.Headers)
+	assert.Empty(t, out.Request.Cookies)
+	assert.Equal(t, "[scrubbed]", out.Request.QueryString)
+}
+
+func TestBeforeSend_DropsDeliberateAbort(t *testing.T) {
+	ev := &sentry.Event{}
+	assert.Nil(t, BeforeSend(ev, &sentry.EventHint{RecoveredException: http.ErrAbortHandler}))
+	assert.Same(t, ev, BeforeSend(ev, &sentry.EventHint{RecoveredException: "boom"}))
+}
+
+func TestRequestTags(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/v1/assets", nil)
+	assert.Equal(t, Tags{"request_id": "r1", "method": "POST", "path": "/api/v1/assets"}, RequestTags(r, "r1"))
+}
@@ -0,0 +1,40 @@
+package errorreport
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/trakrf/platform/backend/internal/logger"
+)
+
+// Safely runs fn, isolating the caller from its panics. A panic is recovered,
+// logged with its stack, reported with tags, and then passed to onPanic (if
+// non-nil) so the caller can fail whatever fn was working on — a job left
+// "processing" by a dead goroutine is never picked up again.
+func Safely(ctx context.Context, tags Tags, fn func(), onPanic func(recovered any)) {
+	defer func() {
+		p := recover()
+		if p == nil {
+			return
+		}
+		ev := logger.Ctx(ctx).Error().
+			Interface("panic", p).
+			Str("stack", string(debug.Stack()))
+		for k, v := range tags {
+			ev = ev.Str(k, v)
+		}
+		ev.Msg("Panic recovered in background work")
+		Panic(ctx, p, tags)
+		if onPanic != nil {
+			onPanic(p)
+		}
+	}()
+	fn()
+}
+
+// SafeGo runs fn on a new goroutine under Safely. Use it for every goroutine
+// that outlives the code starting it: an unrecovered panic there takes the
+// whole process down.
+func SafeGo(ctx context.Context, tags Tags, fn func(), onPanic func(recovered any)) {
+	go Safely(ctx, tags, fn, onPanic)
+}
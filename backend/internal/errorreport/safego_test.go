@@ -0,0 +1,49 @@
+package errorreport
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafely_RecoversReportsAndCallsOnPanic(t *testing.T) {
+	rec := &recordingSink{}
+	SetSink(rec)
+	t.Cleanup(func() { SetSink(nil) })
+
+	var got any
+	assert.NotPanics(t, func() {
+		Safely(context.Background(), Tags{"job": "import"}, func() { panic("boom") },
+			func(p any) { got = p })
+	})
+
+	assert.Equal(t, "boom", got)
+	assert.Equal(t, []any{"boom"}, rec.panics)
+	assert.Equal(t, []Tags{{"job": "import"}}, rec.tags)
+}
+
+func TestSafely_NoPanic(t *testing.T) {
+	ran, called := false, false
+	Safely(context.Background(), nil, func() { ran = true }, func(any) { called = true })
+	assert.True(t, ran)
+	assert.False(t, called, "onPanic only runs after a panic")
+
+	assert.NotPanics(t, func() {
+		Safely(context.Background(), nil, func() { panic("boom") }, nil)
+	}, "onPanic is optional")
+}
+
+func TestSafeGo_IsolatesThePanic(t *testing.T) {
+	done := make(chan any, 1)
+	SafeGo(context.Background(), nil, func() { panic("boom") }, func(p any) { done <- p })
+
+	select {
+	case p := <-done:
+		require.Equal(t, "boom", p)
+	case <-time.After(time.Second):
+		t.Fatal("onPanic was not called")
+	}
+}
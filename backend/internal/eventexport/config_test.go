@@ -0,0 +1,54 @@
+package eventexport
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigFromEnv_DisabledByDefault(t *testing.T) {
+	t.Setenv("EVENT_EXPORT_SINK", "")
+	c, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.False(t, c.Enabled())
+}
+
+func TestConfigFromEnv_NATS(t *testing.T) {
+	t.Setenv("EVENT_EXPORT_SINK", "NATS")
+	t.Setenv("EVENT_EXPORT_URL", "nats://u:p@nats:4222")
+	t.Setenv("EVENT_EXPORT_TOPIC", "acme.trakrf")
+	t.Setenv("EVENT_EXPORT_BATCH_SIZE", "25")
+	t.Setenv("EVENT_EXPORT_POLL_INTERVAL", "5s")
+
+	c, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, c.Enabled())
+	assert.Equal(t, SinkNATS, c.Sink)
+	assert.Equal(t, "acme.trakrf", c.Topic)
+	assert.Equal(t, 25, c.BatchSize)
+	assert.Equal(t, 5*time.Second, c.PollInterval)
+	assert.Equal(t, DefaultConfig().Retention, c.Retention)
+}
+
+func TestConfigFromEnv_RejectsInvalid(t *testing.T) {
+	cases := map[string]map[string]string{
+		"unknown sink":       {"EVENT_EXPORT_SINK": "rabbitmq", "EVENT_EXPORT_URL": "amqp://x"},
+		"missing url":        {"EVENT_EXPORT_SINK": "kafka", "EVENT_EXPORT_URL": ""},
+		"kafka non-http url": {"EVENT_EXPORT_SINK": "kafka", "EVENT_EXPORT_URL": "kafka://broker:9092"},
+		"nats http url":      {"EVENT_EXPORT_SINK": "nats", "EVENT_EXPORT_URL": "http://nats:4222"},
+		"topic with space":   {"EVENT_EXPORT_SINK": "nats", "EVENT_EXPORT_URL": "nats://n:4222", "EVENT_EXPORT_TOPIC": "a b"},
+		"zero batch":         {"EVENT_EXPORT_SINK": "nats", "EVENT_EXPORT_URL": "nats://n:4222", "EVENT_EXPORT_BATCH_SIZE": "0"},
+		"bad interval":       {"EVENT_EXPORT_SINK": "nats", "EVENT_EXPORT_URL": "nats://n:4222", "EVENT_EXPORT_POLL_INTERVAL": "soon"},
+	}
+	for name, env := range cases {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range env {
+				t.Setenv(k, v)
+			}
+			_, err := ConfigFromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Package eventexport ships committed changes to a customer's Kafka or NATS so
+// WMS/ERP systems can subscribe to TrakRF instead of polling the API. Storage
+// writes each change to trakrf.event_outbox inside the mutating transaction;
+// the Exporter drains that table in id order and marks rows published only
+// after the sink acknowledged them. Delivery is at-least-once: a crash between
+// the broker ack and the published stamp resends the batch, so consumers
+// dedupe on the envelope id.
+package eventexport
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+// outboxStore is the storage surface the exporter needs; *storage.Storage
+// satisfies it. Narrowed so unit tests can inject a fake.
+type outboxStore interface {
+	DrainOutbox(ctx context.Context, limit int, fn func(ctx context.Context, batch []storage.OutboxEvent) error) (int, error)
+	PruneOutbox(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// Envelope is the canonical exported event. Data is the entity row as of the
+// committing transaction (for scan.recorded, the resolved read).
+type Envelope struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	OrgID      int             `json:"org_id"`
+	EntityID   *int            `json:"entity_id,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// Exporter drains the outbox into a Sink. Its Drain and Prune methods are
+// jobs.Func runs; serve schedules them on the job runner.
+type Exporter struct {
+	cfg   Config
+	store outboxStore
+	sink  Sink
+}
+
+// New builds an exporter. The caller owns sink and closes it on shutdown.
+func New(cfg Config, store outboxStore, sink Sink) *Exporter {
+	return &Exporter{cfg: cfg, store: store, sink: sink}
+}
+
+// Drain publishes full batches back to back until the outbox is caught up. A
+// sink failure leaves the batch pending and is returned so the job runner
+// backs off; the outbox buffers events during a broker outage.
+func (e *Exporter) Drain(ctx context.Context) error {
+	for {
+		n, err := e.store.DrainOutbox(ctx, e.cfg.BatchSize, e.send)
+		if err != nil {
+			metricFailures.Inc()
+			return err
+		}
+		metricPublished.Add(float64(n))
+		if n < e.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+// Prune deletes published rows older than the retention window.
+func (e *Exporter) Prune(ctx context.Context) error {
+	n, err := e.store.PruneOutbox(ctx, time.Now().Add(-e.cfg.Retention))
+	if err != nil {
+		return err
+	}
+	metricPruned.Add(float64(n))
+	return nil
+}
+
+func (e *Exporter) send(ctx context.Context, batch []storage.OutboxEvent) error {
+	msgs := make([]Message, len(batch))
+	for i, ev := range batch {
+		value, err := json.Marshal(Envelope{
+			ID:         ev.ID,
+			Type:       string(ev.Type),
+			OrgID:      ev.OrgID,
+			EntityID:   ev.EntityID,
+			OccurredAt: ev.CreatedAt.UTC(),
+			Data:       ev.Data,
+		})
+		if err != nil {
+			return err
+		}
+		msgs[i] = Message{Key: strconv.Itoa(ev.OrgID), Value: value}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.cfg.Timeout)
+	defer cancel()
+	return e.sink.Publish(ctx, e.cfg.Topic, msgs)
+}
@@ -0,0 +1,103 @@
+package eventexport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+// fakeOutbox mimics DrainOutbox: it hands out pending rows in batches and
+// removes them only when fn succeeds.
+type fakeOutbox struct {
+	pending []storage.OutboxEvent
+}
+
+func (f *fakeOutbox) DrainOutbox(ctx context.Context, limit int, fn func(context.Context, []storage.OutboxEvent) error) (int, error) {
+	n := min(limit, len(f.pending))
+	if n == 0 {
+		return 0, nil
+	}
+	if err := fn(ctx, f.pending[:n]); err != nil {
+		return 0, err
+	}
+	f.pending = f.pending[n:]
+	return n, nil
+}
+
+func (f *fakeOutbox) PruneOutbox(context.Context, time.Time) (int64, error) { return 0, nil }
+
+type recordingSink struct {
+	batches [][]Message
+	err     error
+}
+
+func (s *recordingSink) Publish(_ context.Context, _ string, msgs []Message) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, msgs)
+	return nil
+}
+
+func (s *recordingSink) Close() error { return nil }
+
+func testExporter(store outboxStore, sink Sink, batch int) *Exporter {
+	cfg := DefaultConfig()
+	cfg.Sink = SinkNATS
+	cfg.BatchSize = batch
+	return New(cfg, store, sink)
+}
+
+func outboxRows(n int) []storage.OutboxEvent {
+	rows := make([]storage.OutboxEvent, n)
+	for i := range rows {
+		id := 100 + i
+		rows[i] = storage.OutboxEvent{
+			ID:        int64(i + 1),
+			OrgID:     7,
+			Type:      events.AssetCreated,
+			EntityID:  &id,
+			Data:      json.RawMessage(`{"name":"forklift"}`),
+			CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	}
+	return rows
+}
+
+func TestExporterDrain_SendsAllBatchesInOrder(t *testing.T) {
+	store := &fakeOutbox{pending: outboxRows(5)}
+	sink := &recordingSink{}
+
+	require.NoError(t, testExporter(store, sink, 2).Drain(context.Background()))
+
+	require.Len(t, sink.batches, 3)
+	assert.Len(t, sink.batches[0], 2)
+	assert.Len(t, sink.batches[2], 1)
+	assert.Empty(t, store.pending)
+
+	var env Envelope
+	require.NoError(t, json.Unmarshal(sink.batches[0][0].Value, &env))
+	assert.Equal(t, int64(1), env.ID)
+	assert.Equal(t, "asset.created", env.Type)
+	assert.Equal(t, 7, env.OrgID)
+	assert.Equal(t, 100, *env.EntityID)
+	assert.JSONEq(t, `{"name":"forklift"}`, string(env.Data))
+	assert.Equal(t, "7", sink.batches[0][0].Key, "events are keyed by org")
+}
+
+func TestExporterDrain_SinkFailureKeepsOutbox(t *testing.T) {
+	store := &fakeOutbox{pending: outboxRows(3)}
+	sink := &recordingSink{err: errors.New("broker down")}
+
+	err := testExporter(store, sink, 10).Drain(context.Background())
+	require.Error(t, err)
+	assert.Len(t, store.pending, 3, "unacknowledged rows stay pending for retry")
+}
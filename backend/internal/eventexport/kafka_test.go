@@ -0,0 +1,67 @@
+package eventexport
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKafkaRESTSink_ProducesRecords(t *testing.T) {
+	var gotPath, gotType, gotUser string
+	var gotBody map[string][]kafkaRecord
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotType = r.Header.Get("Content-Type")
+		gotUser, _, _ = r.BasicAuth()
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &gotBody))
+		w.Write([]byte(`{"offsets":[{"partition":0,"offset":7,"error_code":null,"error":null}]}`))
+	}))
+	defer srv.Close()
+
+	u := "http://svc:secret@" + srv.Listener.Addr().String()
+	sink, err := newKafkaRESTSink(u, time.Second)
+	require.NoError(t, err)
+
+	err = sink.Publish(context.Background(), "trakrf.events", []Message{{Key: "42", Value: []byte(`{"id":1}`)}})
+	require.NoError(t, err)
+	assert.Equal(t, "/topics/trakrf.events", gotPath)
+	assert.Equal(t, "application/vnd.kafka.json.v2+json", gotType)
+	assert.Equal(t, "svc", gotUser)
+	require.Len(t, gotBody["records"], 1)
+	assert.Equal(t, "42", gotBody["records"][0].Key)
+	assert.JSONEq(t, `{"id":1}`, string(gotBody["records"][0].Value))
+}
+
+func TestKafkaRESTSink_RecordErrorFailsBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"offsets":[{"partition":null,"offset":null,"error_code":50002,"error":"leader not available"}]}`))
+	}))
+	defer srv.Close()
+
+	sink, err := newKafkaRESTSink(srv.URL, time.Second)
+	require.NoError(t, err)
+	err = sink.Publish(context.Background(), "t", []Message{{Key: "1", Value: []byte(`{}`)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "leader not available")
+}
+
+func TestKafkaRESTSink_HTTPErrorFailsBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error_code":40401,"message":"Topic not found"}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	sink, err := newKafkaRESTSink(srv.URL, time.Second)
+	require.NoError(t, err)
+	err = sink.Publish(context.Background(), "missing", []Message{{Key: "1", Value: []byte(`{}`)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 404")
+}
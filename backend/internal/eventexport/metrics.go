@@ -0,0 +1,23 @@
+package eventexport
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "event_export_published_total",
+		Help: "Outbox events acknowledged by the export sink.",
+	})
+
+	metricFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "event_export_failures_total",
+		Help: "Outbox drain attempts that failed (sink or database); the batch is retried.",
+	})
+
+	metricPruned = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "event_export_pruned_total",
+		Help: "Published outbox rows deleted after the retention window.",
+	})
+)
@@ -0,0 +1,213 @@
+package eventexport
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// natsSink speaks the NATS client text protocol directly (INFO / CONNECT /
+// PUB / PING / PONG) over one connection. The exporter only ever publishes,
+// so this is the whole client surface it needs. A batch counts as delivered
+// once the server answers the PING that follows it: NATS processes a
+// connection's commands in order, so the PONG proves every preceding PUB was
+// accepted. JetStream streams bound to the subject make that durable.
+type natsSink struct {
+	addr    string
+	useTLS  bool
+	host    string
+	user    string
+	pass    string
+	token   string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func newNATSSink(rawURL string, timeout time.Duration) (*natsSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse nats url: %w", err)
+	}
+	s := &natsSink{
+		addr:    u.Host,
+		useTLS:  u.Scheme == "tls",
+		host:    u.Hostname(),
+		timeout: timeout,
+	}
+	if u.Port() == "" {
+		s.addr = net.JoinHostPort(u.Hostname(), "4222")
+	}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			s.user, s.pass = u.User.Username(), pass
+		} else {
+			s.token = u.User.Username()
+		}
+	}
+	return s, nil
+}
+
+// natsConnectOptions is the CONNECT payload. verbose=false suppresses +OK
+// after every PUB; errors still arrive as -ERR.
+type natsConnectOptions struct {
+	Verbose   bool   `json:"verbose"`
+	Pedantic  bool   `json:"pedantic"`
+	Name      string `json:"name"`
+	Lang      string `json:"lang"`
+	Version   string `json:"version"`
+	Protocol  int    `json:"protocol"`
+	User      string `json:"user,omitempty"`
+	Pass      string `json:"pass,omitempty"`
+	AuthToken string `json:"auth_token,omitempty"`
+}
+
+func (s *natsSink) Publish(ctx context.Context, topic string, msgs []Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureConn(ctx); err != nil {
+		return err
+	}
+	if err := s.publish(ctx, topic, msgs); err != nil {
+		// The connection state is unknown after a failure; start clean next time.
+		s.closeConn()
+		return err
+	}
+	return nil
+}
+
+func (s *natsSink) publish(ctx context.Context, topic string, msgs []Message) error {
+	s.setDeadline(ctx)
+
+	w := bufio.NewWriter(s.conn)
+	for _, m := range msgs {
+		fmt.Fprintf(w, "PUB %s %d\r\n", topic, len(m.Value))
+		w.Write(m.Value)
+		w.WriteString("\r\n")
+	}
+	w.WriteString("PING\r\n")
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("nats publish: %w", err)
+	}
+	return s.awaitPong()
+}
+
+func (s *natsSink) ensureConn(ctx context.Context) error {
+	if s.conn != nil {
+		return nil
+	}
+
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if s.useTLS {
+		td := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}}
+		conn, err = td.DialContext(ctx, "tcp", s.addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", s.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("nats dial %s: %w", s.addr, err)
+	}
+	s.conn = conn
+	s.r = bufio.NewReader(conn)
+	s.setDeadline(ctx)
+
+	line, err := s.readLine()
+	if err != nil {
+		s.closeConn()
+		return fmt.Errorf("nats read INFO: %w", err)
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		s.closeConn()
+		return fmt.Errorf("nats: expected INFO, got %q", line)
+	}
+
+	opts, _ := json.Marshal(natsConnectOptions{
+		Name:      "trakrf-event-export",
+		Lang:      "go",
+		Version:   "1",
+		Protocol:  1,
+		User:      s.user,
+		Pass:      s.pass,
+		AuthToken: s.token,
+	})
+	if _, err := fmt.Fprintf(s.conn, "CONNECT %s\r\nPING\r\n", opts); err != nil {
+		s.closeConn()
+		return fmt.Errorf("nats CONNECT: %w", err)
+	}
+	// Authorization failures surface as -ERR before the PONG.
+	if err := s.awaitPong(); err != nil {
+		s.closeConn()
+		return err
+	}
+	return nil
+}
+
+// awaitPong reads until the server's PONG, answering server PINGs and
+// skipping INFO updates on the way. -ERR fails the batch.
+func (s *natsSink) awaitPong() error {
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return fmt.Errorf("nats await PONG: %w", err)
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := s.conn.Write([]byte("PONG\r\n")); err != nil {
+				return fmt.Errorf("nats reply PONG: %w", err)
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		case line == "+OK", strings.HasPrefix(line, "INFO "):
+			// ignore
+		default:
+			return fmt.Errorf("nats: unexpected %q", line)
+		}
+	}
+}
+
+func (s *natsSink) readLine() (string, error) {
+	line, err := s.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (s *natsSink) setDeadline(ctx context.Context) {
+	deadline := time.Now().Add(s.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	s.conn.SetDeadline(deadline)
+}
+
+func (s *natsSink) closeConn() {
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+		s.r = nil
+	}
+}
+
+func (s *natsSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeConn()
+	return nil
+}
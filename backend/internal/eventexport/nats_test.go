@@ -0,0 +1,120 @@
+package eventexport
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNATS is a single-connection NATS server speaking just enough protocol
+// for the sink: INFO, CONNECT, PUB, PING. errOnPub makes it answer the first
+// PUB with -ERR instead of accepting it.
+type fakeNATS struct {
+	ln       net.Listener
+	connect  chan string
+	pubs     chan string
+	errOnPub bool
+}
+
+func startFakeNATS(t *testing.T, errOnPub bool) *fakeNATS {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f := &fakeNATS{ln: ln, connect: make(chan string, 1), pubs: make(chan string, 16), errOnPub: errOnPub}
+	t.Cleanup(func() { ln.Close() })
+	go f.serve()
+	return f
+}
+
+func (f *fakeNATS) url() string { return "nats://tok@" + f.ln.Addr().String() }
+
+func (f *fakeNATS) serve() {
+	for {
+		conn, err := f.ln.Accept()
+		if err != nil {
+			return
+		}
+		go f.handle(conn)
+	}
+}
+
+func (f *fakeNATS) handle(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	fmt.Fprint(conn, "INFO {\"server_id\":\"fake\"}\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			f.connect <- strings.TrimPrefix(line, "CONNECT ")
+		case strings.HasPrefix(line, "PUB "):
+			parts := strings.Fields(line)
+			n, _ := strconv.Atoi(parts[len(parts)-1])
+			buf := make([]byte, n+2)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			if f.errOnPub {
+				fmt.Fprint(conn, "-ERR 'Permissions Violation for Publish'\r\n")
+				return
+			}
+			f.pubs <- parts[1] + " " + string(buf[:n])
+		case line == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		}
+	}
+}
+
+func TestNATSSink_PublishesBatchAfterHandshake(t *testing.T) {
+	srv := startFakeNATS(t, false)
+	sink, err := newNATSSink(srv.url(), time.Second)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	err = sink.Publish(context.Background(), "trakrf.events", []Message{
+		{Key: "1", Value: []byte(`{"id":1}`)},
+		{Key: "1", Value: []byte(`{"id":2}`)},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, <-srv.connect, `"auth_token":"tok"`)
+	assert.Equal(t, `trakrf.events {"id":1}`, <-srv.pubs)
+	assert.Equal(t, `trakrf.events {"id":2}`, <-srv.pubs)
+}
+
+func TestNATSSink_ServerErrorFailsBatch(t *testing.T) {
+	srv := startFakeNATS(t, true)
+	sink, err := newNATSSink(srv.url(), time.Second)
+	require.NoError(t, err)
+	defer sink.Close()
+
+	err = sink.Publish(context.Background(), "trakrf.events", []Message{{Value: []byte(`{}`)}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Permissions Violation")
+	assert.Nil(t, sink.conn, "a failed batch drops the connection")
+}
+
+func TestNATSSink_DialFailure(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	sink, err := newNATSSink("nats://"+addr, 200*time.Millisecond)
+	require.NoError(t, err)
+	err = sink.Publish(context.Background(), "trakrf.events", []Message{{Value: []byte(`{}`)}})
+	assert.Error(t, err)
+}
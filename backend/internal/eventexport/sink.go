@@ -0,0 +1,34 @@
+package eventexport
+
+import (
+	"context"
+	"fmt"
+)
+
+// Message is one exported event on the wire. Key is the partitioning key
+// (the org id, so one org's events stay ordered on a Kafka partition).
+type Message struct {
+	Key   string
+	Value []byte
+}
+
+// Sink delivers a batch to the broker. A nil return means the broker
+// acknowledged every message; any error means the whole batch is retried, so
+// consumers may see duplicates (dedupe on the envelope id).
+type Sink interface {
+	Publish(ctx context.Context, topic string, msgs []Message) error
+	Close() error
+}
+
+// NewSink builds the sink cfg names. Connections are opened lazily on first
+// publish, so a broker that is down at boot does not block startup.
+func NewSink(cfg Config) (Sink, error) {
+	switch cfg.Sink {
+	case SinkNATS:
+		return newNATSSink(cfg.URL, cfg.Timeout)
+	case SinkKafka:
+		return newKafkaRESTSink(cfg.URL, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown event export sink %q", cfg.Sink)
+	}
+}
@@ -0,0 +1,182 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+)
+
+// Resync is dispatched to every subscriber after the listener reconnects.
+// Notifications sent while the connection was down are lost, so subscribers
+// holding derived state should treat it as "reload everything".
+const Resync Type = "bus.resync"
+
+const (
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// Handler consumes one event. Handlers run on the listener goroutine, in
+// subscription order; a slow handler delays the ones after it, so anything
+// expensive should hand off to its own goroutine.
+type Handler func(ctx context.Context, ev Event)
+
+// ConnectFunc opens the dedicated LISTEN connection.
+type ConnectFunc func(ctx context.Context) (*pgx.Conn, error)
+
+// ConnectFromPool returns a ConnectFunc that opens a standalone connection with
+// the pool's connection settings. LISTEN pins a session for its lifetime, so it
+// must not borrow (and starve) a pooled connection.
+func ConnectFromPool(pool *pgxpool.Pool) ConnectFunc {
+	return func(ctx context.Context) (*pgx.Conn, error) {
+		return pgx.ConnectConfig(ctx, pool.Config().ConnConfig)
+	}
+}
+
+type subscription struct {
+	types   map[Type]bool // nil = every type
+	handler Handler
+}
+
+// Bus LISTENs on Channel and fans notifications out to subscribers.
+type Bus struct {
+	connect ConnectFunc
+	log     zerolog.Logger
+
+	mu   sync.RWMutex
+	subs []subscription
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewBus builds a stopped bus. Subscribe, then Start.
+func NewBus(connect ConnectFunc, log *zerolog.Logger) *Bus {
+	return &Bus{
+		connect: connect,
+		log:     log.With().Str("component", "events").Logger(),
+	}
+}
+
+// Subscribe registers h for the given types, or for every type (including
+// Resync) when none are given.
+func (b *Bus) Subscribe(h Handler, types ...Type) {
+	sub := subscription{handler: h}
+	if len(types) > 0 {
+		sub.types = make(map[Type]bool, len(types)+1)
+		for _, t := range types {
+			sub.types[t] = true
+		}
+		// Filtered subscribers still need to know they may have missed events.
+		sub.types[Resync] = true
+	}
+	b.mu.Lock()
+	b.subs = append(b.subs, sub)
+	b.mu.Unlock()
+}
+
+// Start launches the listener goroutine. It returns immediately; connection
+// failures are logged and retried with backoff rather than failing boot.
+func (b *Bus) Start(ctx context.Context) {
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.done = make(chan struct{})
+	go b.run(ctx)
+}
+
+// Stop cancels the listener and waits for it to exit.
+func (b *Bus) Stop() {
+	if b.cancel == nil {
+		return
+	}
+	b.cancel()
+	<-b.done
+}
+
+func (b *Bus) run(ctx context.Context) {
+	defer close(b.done)
+
+	backoff := minBackoff
+	connected := false
+	for {
+		err := b.listen(ctx, func() {
+			backoff = minBackoff
+			if connected {
+				metricReconnects.Inc()
+				b.Dispatch(ctx, Event{Type: Resync, At: time.Now().UTC()})
+			}
+			connected = true
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		b.log.Warn().Err(err).Dur("retry_in", backoff).Msg("event listener disconnected")
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// listen holds one LISTEN session until it fails or ctx is canceled.
+// onListening fires once the LISTEN is in place.
+func (b *Bus) listen(ctx context.Context, onListening func()) error {
+	conn, err := b.connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{Channel}.Sanitize()); err != nil {
+		return err
+	}
+	b.log.Info().Str("channel", Channel).Msg("event listener started")
+	onListening()
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(n.Payload), &ev); err != nil {
+			metricReceived.WithLabelValues("invalid").Inc()
+			b.log.Warn().Err(err).Str("payload", n.Payload).Msg("dropping malformed event")
+			continue
+		}
+		metricReceived.WithLabelValues(string(ev.Type)).Inc()
+		b.Dispatch(ctx, ev)
+	}
+}
+
+// Dispatch delivers ev to the matching in-process subscribers. A panicking
+// handler is recovered and logged so one bad consumer cannot kill the feed.
+func (b *Bus) Dispatch(ctx context.Context, ev Event) {
+	b.mu.RLock()
+	subs := make([]subscription, len(b.subs))
+	copy(subs, b.subs)
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		if sub.types != nil && !sub.types[ev.Type] {
+			continue
+		}
+		b.deliver(ctx, sub.handler, ev)
+	}
+}
+
+func (b *Bus) deliver(ctx context.Context, h Handler, ev Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			metricHandlerPanics.Inc()
+			b.log.Error().Interface("panic", r).Str("type", string(ev.Type)).Msg("event handler panicked")
+		}
+	}()
+	h(ctx, ev)
+}
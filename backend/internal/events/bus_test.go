@@ -0,0 +1,46 @@
+package events
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func testBus() *Bus {
+	log := zerolog.New(io.Discard)
+	return NewBus(nil, &log)
+}
+
+func TestDispatch_FiltersByType(t *testing.T) {
+	b := testBus()
+	var all, devices []Type
+	b.Subscribe(func(_ context.Context, ev Event) { all = append(all, ev.Type) })
+	b.Subscribe(func(_ context.Context, ev Event) { devices = append(devices, ev.Type) },
+		ScanDeviceCreated, ScanDeviceDeleted)
+
+	ctx := context.Background()
+	for _, typ := range []Type{AssetCreated, ScanDeviceCreated, LocationDeleted, ScanDeviceDeleted, Resync} {
+		b.Dispatch(ctx, Event{Type: typ})
+	}
+
+	assert.Equal(t, []Type{AssetCreated, ScanDeviceCreated, LocationDeleted, ScanDeviceDeleted, Resync}, all)
+	assert.Equal(t, []Type{ScanDeviceCreated, ScanDeviceDeleted, Resync}, devices,
+		"filtered subscribers still receive Resync")
+}
+
+func TestDispatch_RecoversHandlerPanic(t *testing.T) {
+	b := testBus()
+	var got int
+	b.Subscribe(func(context.Context, Event) { panic("boom") })
+	b.Subscribe(func(context.Context, Event) { got++ })
+
+	assert.NotPanics(t, func() { b.Dispatch(context.Background(), Event{Type: AssetUpdated}) })
+	assert.Equal(t, 1, got, "handlers after a panicking one still run")
+}
+
+func TestStop_BeforeStartIsNoop(t *testing.T) {
+	assert.NotPanics(t, testBus().Stop)
+}
@@ -0,0 +1,146 @@
+// Package events is the cross-replica change feed. Storage writes publish an
+// Event with pg_notify inside the writing transaction, so Postgres delivers it
+// to every LISTENing backend replica only if (and when) the write commits.
+// Each replica runs one Bus that LISTENs on Channel and fans notifications out
+// to in-process subscribers (cache invalidators, live streams, dispatchers).
+//
+// NOTIFY is fire-and-forget: a replica that is disconnected when an event is
+// sent never sees it. Consumers must treat events as hints and keep their own
+// periodic reconcile as the safety net.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Channel is the Postgres NOTIFY channel every replica LISTENs on.
+const Channel = "trakrf_events"
+
+// Type names a change. The "<entity>.<verb>" shape lets subscribers filter by
+// exact type.
+type Type string
+
+const (
+	AssetCreated      Type = "asset.created"
+	AssetUpdated      Type = "asset.updated"
+	AssetDeleted      Type = "asset.deleted"
+	LocationCreated   Type = "location.created"
+	LocationUpdated   Type = "location.updated"
+	LocationDeleted   Type = "location.deleted"
+	ScanDeviceCreated Type = "scan_device.created"
+	ScanDeviceUpdated Type = "scan_device.updated"
+	ScanDeviceDeleted Type = "scan_device.deleted"
+
+	// Sensor alerts open when a condition reading leaves its threshold and
+	// resolve on the first in-range reading after it.
+	SensorAlertOpened   Type = "sensor_alert.opened"
+	SensorAlertResolved Type = "sensor_alert.resolved"
+
+	// Stock alerts open when a consumable's quantity at a location drops
+	// below its minimum level and resolve when the level recovers.
+	StockAlertOpened   Type = "stock_alert.opened"
+	StockAlertResolved Type = "stock_alert.resolved"
+
+	// AssetOverdue fires once per due date when an asset's metadata.due_at
+	// passes; the push notifier raises it as it notifies the asset's techs.
+	AssetOverdue Type = "asset.overdue"
+
+	// DockMovementRecorded fires when an asset is seen crossing a dock door,
+	// inbound or outbound.
+	DockMovementRecorded Type = "dock_movement.recorded"
+
+	// DocumentExpiring fires once when a vault document enters its reminder
+	// window, DocumentExpired once when its expiry date passes. Renewing the
+	// document re-arms both.
+	DocumentExpiring Type = "document.expiring"
+	DocumentExpired  Type = "document.expired"
+
+	// ScanRecorded is export-only: it goes to the event outbox (one per
+	// persisted asset_scans row) but is never NOTIFYed, since ingest volume
+	// would swamp the in-process subscribers.
+	ScanRecorded Type = "scan.recorded"
+)
+
+// EntityTypes is every entity change type, in a stable order. These are the
+// types org webhooks can subscribe to.
+var EntityTypes = []Type{
+	AssetCreated, AssetUpdated, AssetDeleted,
+	LocationCreated, LocationUpdated, LocationDeleted,
+	ScanDeviceCreated, ScanDeviceUpdated, ScanDeviceDeleted,
+	SensorAlertOpened, SensorAlertResolved,
+	StockAlertOpened, StockAlertResolved,
+	AssetOverdue,
+	DockMovementRecorded,
+	DocumentExpiring, DocumentExpired,
+}
+
+// IsEntityType reports whether t is one of EntityTypes.
+func IsEntityType(t Type) bool {
+	for _, et := range EntityTypes {
+		if t == et {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the NOTIFY payload. It deliberately carries identifiers only —
+// NOTIFY payloads are capped at 8000 bytes and subscribers re-read whatever
+// state they need under their own org context.
+type Event struct {
+	Type     Type      `json:"type"`
+	OrgID    int       `json:"org_id"`
+	EntityID int       `json:"entity_id,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// Execer is the subset of pgx.Tx / pgxpool.Pool that Publish needs.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// Publish queues ev for delivery on Channel. Called with a transaction, the
+// notification is delivered on commit and discarded on rollback.
+func Publish(ctx context.Context, db Execer, ev Event) error {
+	if ev.At.IsZero() {
+		ev.At = time.Now().UTC()
+	}
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+	if _, err := db.Exec(ctx, "SELECT pg_notify($1, $2)", Channel, string(payload)); err != nil {
+		return fmt.Errorf("publish %s event: %w", ev.Type, err)
+	}
+	return nil
+}
+
+// PublishAll queues evs like Publish, in a single statement. It is for writes
+// that touch many entities at once, where a round trip per event would cost
+// more than the write itself.
+func PublishAll(ctx context.Context, db Execer, evs []Event) error {
+	if len(evs) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	payloads := make([]string, len(evs))
+	for i, ev := range evs {
+		if ev.At.IsZero() {
+			ev.At = now
+		}
+		payload, err := json.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("marshal event: %w", err)
+		}
+		payloads[i] = string(payload)
+	}
+	if _, err := db.Exec(ctx, "SELECT pg_notify($1, p) FROM unnest($2::text[]) AS p", Channel, payloads); err != nil {
+		return fmt.Errorf("publish %s events: %w", evs[0].Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package events
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "events_received_total",
+		Help: "Change events received over LISTEN/NOTIFY, by type (\"invalid\" for undecodable payloads).",
+	}, []string{"type"})
+
+	metricReconnects = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "events_listener_reconnects_total",
+		Help: "Times the LISTEN connection was re-established (each triggers a resync).",
+	})
+
+	metricHandlerPanics = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "events_handler_panics_total",
+		Help: "Event handlers that panicked (recovered; the feed keeps running).",
+	})
+)
@@ -222,7 +222,7 @@ func (e *Engine) recordFire(ctx context.Context, orgID int, tagScanID int64, rec
 		TagScanID:   tagScanID,
 		FiredAt:     receivedAt,
 	}); err != nil {
-		e.log.Error().Err(err).Int("org_id", orgID).Str("epc", rd.EPC).Msg("alarm_events write failed")
+		e.log.Error().Err(err).Int("org_id", orgID).Int("asset_id", rd.AssetID).Msg("alarm_events write failed")
 		metricEventWriteErrors.Inc()
 	}
 
@@ -231,7 +231,6 @@ func (e *Engine) recordFire(ctx context.Context, orgID int, tagScanID int64, rec
 		Int("org_id", orgID).
 		Int("asset_id", rd.AssetID).
 		Int("scan_point_id", rd.ScanPointID).
-		Str("epc", rd.EPC).
 		Int("rssi", rd.RSSI).
 		Msg("geofence rule fired")
 }
@@ -0,0 +1,183 @@
+// Package admin serves superadmin-only operator surfaces that are not tied to
+// a single resource handler.
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/config"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/maintenance"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// MaintenanceStorage is the storage surface the maintenance endpoints need
+// (mockable).
+type MaintenanceStorage interface {
+	ListMaintenanceWindows(ctx context.Context) ([]maintenance.Window, error)
+	CreateMaintenanceWindow(ctx context.Context, req maintenance.CreateWindowRequest, createdBy int) (*maintenance.Window, error)
+	EndMaintenanceWindow(ctx context.Context, id int) (*maintenance.Window, error)
+}
+
+type Handler struct {
+	cfg     config.Config
+	storage MaintenanceStorage
+}
+
+func NewHandler(cfg config.Config, storage MaintenanceStorage) *Handler {
+	return &Handler{cfg: cfg, storage: storage}
+}
+
+// @Summary Effective server configuration (superadmin)
+// @Description Superadmin-only. Returns the configuration this replica booted
+// @Description with; secrets are reported as "[redacted]" when set.
+// @Tags admin,internal
+// @ID admin.config
+// @Produce json
+// @Success 200 {object} map[string]any "data: config.View"
+// @Failure 401 {object} map[string]any "unauthenticated"
+// @Failure 403 {object} map[string]any "not a superadmin"
+// @Security SessionAuth
+// @Router /api/v1/admin/config [get]
+// GetConfig returns the redacted configuration. Authorization is enforced
+// upstream by RequireSuperadmin.
+func (h *Handler) GetConfig(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": h.cfg.Redacted()})
+}
+
+// @Summary List maintenance windows (superadmin)
+// @Description Superadmin-only. Windows that have not ended, in force or
+// @Description scheduled, soonest first.
+// @Tags admin,internal
+// @ID admin.maintenance_windows.list
+// @Produce json
+// @Success 200 {object} map[string]any "data: []maintenance.Window"
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/maintenance-windows [get]
+func (h *Handler) ListMaintenanceWindows(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	list, err := h.storage.ListMaintenanceWindows(r.Context())
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary Open a maintenance window (superadmin)
+// @Description Superadmin-only. While the window is in force, mutations are
+// @Description rejected with 503 and Retry-After: for `org_id` only, or for
+// @Description every org when it is omitted. Reads, sign-in and these admin
+// @Description endpoints stay open. Without `ends_at` the window lasts until
+// @Description it is ended. Replicas pick it up within 5 seconds.
+// @Tags admin,internal
+// @ID admin.maintenance_windows.create
+// @Accept json
+// @Produce json
+// @Param request body maintenance.CreateWindowRequest true "Maintenance window"
+// @Success 201 {object} map[string]any "data: maintenance.Window"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/maintenance-windows [post]
+func (h *Handler) CreateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Authentication required", reqID)
+		return
+	}
+	var req maintenance.CreateWindowRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if req.EndsAt != nil && req.StartsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "ends_at", Code: "invalid_value", Message: "ends_at must be after starts_at",
+		}})
+		return
+	}
+	mw, err := h.storage.CreateMaintenanceWindow(r.Context(), req, claims.UserID)
+	if errors.Is(err, storage.ErrMaintenanceOrgNotFound) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "org_id", Code: "fk_not_found", Message: err.Error(),
+		}})
+		return
+	}
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/admin/maintenance-windows/"+strconv.Itoa(mw.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": mw})
+}
+
+// @Summary End a maintenance window (superadmin)
+// @Description Superadmin-only. Ends a window in force now, reopening writes,
+// @Description or cancels a scheduled one. The window is kept as a record.
+// @Tags admin,internal
+// @ID admin.maintenance_windows.end
+// @Produce json
+// @Param window_id path int true "Maintenance window id"
+// @Success 200 {object} map[string]any "data: maintenance.Window"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/maintenance-windows/{window_id}/end [post]
+func (h *Handler) EndMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("window_id", chi.URLParam(r, "window_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	mw, err := h.storage.EndMaintenanceWindow(r.Context(), id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if mw == nil {
+		httputil.Respond404(w, r, "maintenance window not found or already ended", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": mw})
+}
+
+// RegisterRoutes registers the admin endpoints behind RequireSuperadmin.
+// The maintenance-window paths start with middleware.MaintenanceAdminPrefix,
+// so ReadOnlyMode never blocks them.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	superadmin := middleware.RequireSuperadmin(store)
+	r.With(superadmin).Get("/api/v1/admin/config", h.GetConfig)
+	r.With(superadmin).Get("/api/v1/admin/maintenance-windows", h.ListMaintenanceWindows)
+	r.With(superadmin).Post("/api/v1/admin/maintenance-windows", h.CreateMaintenanceWindow)
+	r.With(superadmin).Post("/api/v1/admin/maintenance-windows/{window_id}/end", h.EndMaintenanceWindow)
+}
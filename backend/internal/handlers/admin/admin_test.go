@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/config"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/maintenance"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+type mockMaintenanceStorage struct {
+	created   *maintenance.CreateWindowRequest
+	createdBy int
+	createErr error
+	open      map[int]bool
+}
+
+func (m *mockMaintenanceStorage) ListMaintenanceWindows(ctx context.Context) ([]maintenance.Window, error) {
+	return []maintenance.Window{}, nil
+}
+
+func (m *mockMaintenanceStorage) CreateMaintenanceWindow(ctx context.Context, req maintenance.CreateWindowRequest, createdBy int) (*maintenance.Window, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created, m.createdBy = &req, createdBy
+	return &maintenance.Window{ID: 9, OrgID: req.OrgID, Reason: req.Reason, StartsAt: time.Now()}, nil
+}
+
+func (m *mockMaintenanceStorage) EndMaintenanceWindow(ctx context.Context, id int) (*maintenance.Window, error) {
+	if !m.open[id] {
+		return nil, nil
+	}
+	now := time.Now()
+	return &maintenance.Window{ID: id, EndsAt: &now}, nil
+}
+
+// serve routes through chi without RequireSuperadmin so the handlers are
+// exercised directly.
+func serve(h *Handler, method, target, body string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	r.Post("/api/v1/admin/maintenance-windows", h.CreateMaintenanceWindow)
+	r.Post("/api/v1/admin/maintenance-windows/{window_id}/end", h.EndMaintenanceWindow)
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(middleware.WithUserClaimsForTest(req.Context(), &jwt.Claims{UserID: 1, Email: "ops@example.com"}))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateMaintenanceWindow(t *testing.T) {
+	store := &mockMaintenanceStorage{}
+	h := NewHandler(config.Config{}, store)
+
+	w := serve(h, http.MethodPost, "/api/v1/admin/maintenance-windows", `{"org_id": 42, "reason": "Data repair"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, "/api/v1/admin/maintenance-windows/9", w.Header().Get("Location"))
+	require.NotNil(t, store.created)
+	assert.Equal(t, 42, *store.created.OrgID)
+	assert.Equal(t, 1, store.createdBy)
+}
+
+func TestCreateMaintenanceWindow_Validation(t *testing.T) {
+	h := NewHandler(config.Config{}, &mockMaintenanceStorage{})
+
+	w := serve(h, http.MethodPost, "/api/v1/admin/maintenance-windows", `{}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"reason"`)
+
+	w = serve(h, http.MethodPost, "/api/v1/admin/maintenance-windows",
+		`{"reason": "x", "starts_at": "2026-10-16T10:00:00Z", "ends_at": "2026-10-16T09:00:00Z"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"ends_at"`)
+
+	h = NewHandler(config.Config{}, &mockMaintenanceStorage{createErr: storage.ErrMaintenanceOrgNotFound})
+	w = serve(h, http.MethodPost, "/api/v1/admin/maintenance-windows", `{"org_id": 404, "reason": "x"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "fk_not_found")
+}
+
+func TestEndMaintenanceWindow(t *testing.T) {
+	h := NewHandler(config.Config{}, &mockMaintenanceStorage{open: map[int]bool{5: true}})
+
+	w := serve(h, http.MethodPost, "/api/v1/admin/maintenance-windows/5/end", "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	w = serve(h, http.MethodPost, "/api/v1/admin/maintenance-windows/6/end", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = serve(h, http.MethodPost, "/api/v1/admin/maintenance-windows/abc/end", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,373 @@
+// Package approvals serves two-person approval of sensitive operations: the
+// org's approval rules, and the approval requests parked by
+// middleware.RequireApproval when a gated operation matches a rule.
+package approvals
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/approval"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const listLimit = 200
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+var statuses = []string{
+	approval.StatusPending, approval.StatusApproved, approval.StatusRejected,
+	approval.StatusCancelled, approval.StatusUsed, approval.StatusExpired,
+}
+
+// ApprovalStorage is the storage surface the handler needs (mockable).
+type ApprovalStorage interface {
+	ListApprovalRules(ctx context.Context, orgID int) ([]approval.Rule, error)
+	SetApprovalRule(ctx context.Context, orgID int, op string, req approval.RuleRequest) (*approval.Rule, error)
+	DeleteApprovalRule(ctx context.Context, orgID int, op string) (bool, error)
+	ListApprovalRequests(ctx context.Context, orgID int, f approval.ListFilter) ([]approval.Request, error)
+	GetApprovalRequest(ctx context.Context, orgID, id int) (*approval.Request, error)
+	DecideApprovalRequest(ctx context.Context, orgID, id, userID int, action string, note *string) (*approval.Request, error)
+}
+
+type Handler struct {
+	storage ApprovalStorage
+}
+
+func NewHandler(storage ApprovalStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the approval routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Rules are admin-only. Any member can
+// read requests, since whoever made one needs to see when it is approved;
+// approving and rejecting is admin-only, and only the requester can cancel.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(admin).Get("/api/v1/approval-rules", h.ListRules)
+	r.With(admin).Put("/api/v1/approval-rules/{operation}", h.SetRule)
+	r.With(admin).Delete("/api/v1/approval-rules/{operation}", h.DeleteRule)
+
+	r.With(member).Get("/api/v1/approvals", h.List)
+	r.With(member).Get("/api/v1/approvals/{approval_id}", h.Get)
+	r.With(admin).Post("/api/v1/approvals/{approval_id}/approve", h.decide(approval.ActionApprove))
+	r.With(admin).Post("/api/v1/approvals/{approval_id}/reject", h.decide(approval.ActionReject))
+	r.With(member).Post("/api/v1/approvals/{approval_id}/cancel", h.decide(approval.ActionCancel))
+}
+
+// caller returns the request's org and session user, answering the error
+// itself when either is missing.
+func caller(w http.ResponseWriter, r *http.Request, reqID string) (orgID, userID int, ok bool) {
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return 0, 0, false
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return 0, 0, false
+	}
+	return orgID, claims.UserID, true
+}
+
+// parseOperation reads the operation path param, answering 400 itself when
+// it names no gateable operation.
+func parseOperation(w http.ResponseWriter, r *http.Request, reqID string) (string, bool) {
+	op := chi.URLParam(r, "operation")
+	if !approval.IsOperation(op) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "operation", Code: "invalid_value",
+			Message: "operation must be one of: " + strings.Join(approval.Operations, ", "),
+		}})
+		return "", false
+	}
+	return op, true
+}
+
+// parseApprovalID reads the approval_id path param, answering 400 itself.
+func parseApprovalID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("approval_id", chi.URLParam(r, "approval_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// @Summary  List approval rules
+// @Description The operations that need a second admin's approval in the current organization. Operations without a rule run without approval.
+// @Tags     approvals,internal
+// @ID       approval_rules.list
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []approval.Rule"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/approval-rules [get]
+func (h *Handler) ListRules(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	rules, err := h.storage.ListApprovalRules(r.Context(), orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": rules})
+}
+
+// @Summary  Require approval for an operation
+// @Description Creates or replaces the rule for `operation`: `assets.bulk_delete` (POST /api/v1/assets/bulk-delete), `org.settings` (PUT /api/v1/orgs/{id} and the org settings PATCHes), `org.admin_grant` (a member role change to admin) or `org.ownership_transfer` (POST /api/v1/orgs/{id}/transfer-ownership). `threshold` applies to `assets.bulk_delete` only: deletes of at most that many assets run without approval; leave it out to gate every bulk delete. `ttl_hours` (default 72) is how long a request has to be approved and used before it expires.
+// @Tags     approvals,internal
+// @ID       approval_rules.set
+// @Accept   json
+// @Produce  json
+// @Param    operation path string true "Operation" Enums(assets.bulk_delete, org.settings, org.admin_grant, org.ownership_transfer)
+// @Param    request body approval.RuleRequest true "Rule"
+// @Success  200 {object} map[string]any "data: approval.Rule"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/approval-rules/{operation} [put]
+func (h *Handler) SetRule(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	op, ok := parseOperation(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req approval.RuleRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if req.Threshold != nil && op != approval.OpAssetsBulkDelete {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "threshold", Code: "invalid_value",
+			Message: "threshold applies only to " + approval.OpAssetsBulkDelete,
+		}})
+		return
+	}
+
+	rule, err := h.storage.SetApprovalRule(r.Context(), orgID, op, req)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": rule})
+}
+
+// @Summary  Stop requiring approval for an operation
+// @Description Removes the rule; the operation runs without approval from then on. Requests already parked keep their status, and approved ones can still be used until they expire.
+// @Tags     approvals,internal
+// @ID       approval_rules.delete
+// @Param    operation path string true "Operation" Enums(assets.bulk_delete, org.settings, org.admin_grant, org.ownership_transfer)
+// @Success  204
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/approval-rules/{operation} [delete]
+func (h *Handler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	op, ok := parseOperation(w, r, reqID)
+	if !ok {
+		return
+	}
+	deleted, err := h.storage.DeleteApprovalRule(r.Context(), orgID, op)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "approval rule not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary  List approval requests
+// @Description The org's approval requests, newest first, at most 200. A pending or approved request reads as `expired` once its expires_at has passed.
+// @Tags     approvals,internal
+// @ID       approvals.list
+// @Produce  json
+// @Param    status query string false "Only this status" Enums(pending, approved, rejected, cancelled, used, expired)
+// @Param    operation query string false "Only this operation" Enums(assets.bulk_delete, org.settings, org.admin_grant, org.ownership_transfer)
+// @Success  200 {object} map[string]any "data: []approval.Request"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/approvals [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	f := approval.ListFilter{Limit: listLimit}
+	q := r.URL.Query()
+	if st := q.Get("status"); st != "" {
+		if !slices.Contains(statuses, st) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field: "status", Code: "invalid_value",
+				Message: "status must be one of: " + strings.Join(statuses, ", "),
+			}})
+			return
+		}
+		f.Status = st
+	}
+	if op := q.Get("operation"); op != "" {
+		if !approval.IsOperation(op) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field: "operation", Code: "invalid_value",
+				Message: "operation must be one of: " + strings.Join(approval.Operations, ", "),
+			}})
+			return
+		}
+		f.Operation = op
+	}
+
+	list, err := h.storage.ListApprovalRequests(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Get an approval request
+// @Tags     approvals,internal
+// @ID       approvals.get
+// @Produce  json
+// @Param    approval_id path int true "Approval request id"
+// @Success  200 {object} map[string]any "data: approval.Request"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/approvals/{approval_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseApprovalID(w, r, reqID)
+	if !ok {
+		return
+	}
+	a, err := h.storage.GetApprovalRequest(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if a == nil {
+		httputil.Respond404(w, r, "approval request not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": a})
+}
+
+// decide returns the handler for approve, reject and cancel.
+//
+// @Summary  Approve, reject or cancel an approval request
+// @Description approve and reject are admin-only, need a pending request, and must come from an admin other than the requester. cancel is for the requester and works on a pending or approved request. Expired requests cannot be acted on. After approval, the requester sends the original request again with the `X-Approval-ID` header to run it. The optional note is recorded as decision_note.
+// @Tags     approvals,internal
+// @ID       approvals.decide
+// @Accept   json
+// @Produce  json
+// @Param    approval_id path int true "Approval request id"
+// @Param    action path string true "approve, reject or cancel" Enums(approve, reject, cancel)
+// @Param    request body approval.DecisionRequest false "Decision note"
+// @Success  200 {object} map[string]any "data: approval.Request"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse "deciding your own request, or cancelling someone else's"
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "the request's status does not allow the action"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/approvals/{approval_id}/{action} [post]
+func (h *Handler) decide(action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+		orgID, userID, ok := caller(w, r, reqID)
+		if !ok {
+			return
+		}
+		id, ok := parseApprovalID(w, r, reqID)
+		if !ok {
+			return
+		}
+		var req approval.DecisionRequest
+		if r.ContentLength != 0 {
+			if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+				httputil.RespondDecodeError(w, r, err, reqID)
+				return
+			}
+			if err := validate.Struct(req); err != nil {
+				httputil.RespondValidationError(w, r, err, reqID)
+				return
+			}
+		}
+		a, err := h.storage.DecideApprovalRequest(r.Context(), orgID, id, userID, action, req.Note)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrApprovalSelfDecision), errors.Is(err, storage.ErrApprovalNotRequester):
+				httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden, err.Error(), reqID)
+			case errors.Is(err, storage.ErrApprovalState):
+				httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+			default:
+				httputil.RespondStorageError(w, r, err, reqID)
+			}
+			return
+		}
+		if a == nil {
+			httputil.Respond404(w, r, "approval request not found", reqID)
+			return
+		}
+		httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": a})
+	}
+}
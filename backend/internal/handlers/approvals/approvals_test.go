@@ -0,0 +1,168 @@
+package approvals
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/approval"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockApprovalStorage struct {
+	setOp     string
+	setReq    *approval.RuleRequest
+	deleted   bool
+	filter    approval.ListFilter
+	action    string
+	note      *string
+	decideErr error
+}
+
+func (m *mockApprovalStorage) ListApprovalRules(ctx context.Context, orgID int) ([]approval.Rule, error) {
+	return []approval.Rule{}, nil
+}
+
+func (m *mockApprovalStorage) SetApprovalRule(ctx context.Context, orgID int, op string, req approval.RuleRequest) (*approval.Rule, error) {
+	m.setOp, m.setReq = op, &req
+	return &approval.Rule{OrgID: orgID, Operation: op, Threshold: req.Threshold, TTLHours: approval.DefaultTTLHours}, nil
+}
+
+func (m *mockApprovalStorage) DeleteApprovalRule(ctx context.Context, orgID int, op string) (bool, error) {
+	return m.deleted, nil
+}
+
+func (m *mockApprovalStorage) ListApprovalRequests(ctx context.Context, orgID int, f approval.ListFilter) ([]approval.Request, error) {
+	m.filter = f
+	return []approval.Request{}, nil
+}
+
+func (m *mockApprovalStorage) GetApprovalRequest(ctx context.Context, orgID, id int) (*approval.Request, error) {
+	if id != 9 {
+		return nil, nil
+	}
+	return &approval.Request{ID: 9}, nil
+}
+
+func (m *mockApprovalStorage) DecideApprovalRequest(ctx context.Context, orgID, id, userID int, action string, note *string) (*approval.Request, error) {
+	m.action, m.note = action, note
+	if m.decideErr != nil {
+		return nil, m.decideErr
+	}
+	if id != 9 {
+		return nil, nil
+	}
+	return &approval.Request{ID: 9, Status: approval.StatusApproved}, nil
+}
+
+// serve routes through chi without the role middleware so handler-level
+// checks are exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Put("/api/v1/approval-rules/{operation}", h.SetRule)
+		r.Delete("/api/v1/approval-rules/{operation}", h.DeleteRule)
+		r.Get("/api/v1/approvals", h.List)
+		r.Get("/api/v1/approvals/{approval_id}", h.Get)
+		r.Post("/api/v1/approvals/{approval_id}/approve", h.decide(approval.ActionApprove))
+	}, req)
+}
+
+func TestSetRule(t *testing.T) {
+	cases := []struct {
+		name string
+		op   string
+		body string
+		want int
+	}{
+		{"bulk delete with threshold", "assets.bulk_delete", `{"threshold":50,"ttl_hours":24}`, http.StatusOK},
+		{"settings", "org.settings", `{}`, http.StatusOK},
+		{"threshold on settings", "org.settings", `{"threshold":5}`, http.StatusBadRequest},
+		{"unknown operation", "org.delete", `{}`, http.StatusBadRequest},
+		{"ttl out of range", "org.admin_grant", `{"ttl_hours":0}`, http.StatusBadRequest},
+		{"negative threshold", "assets.bulk_delete", `{"threshold":-1}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockApprovalStorage{}
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPut, "/api/v1/approval-rules/"+c.op, c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.want == http.StatusOK && m.setOp != c.op {
+				t.Errorf("op = %q", m.setOp)
+			}
+		})
+	}
+}
+
+func TestDeleteRule(t *testing.T) {
+	w := serve(NewHandler(&mockApprovalStorage{deleted: true}), testutil.NewRequest(t, http.MethodDelete, "/api/v1/approval-rules/org.settings", ""))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d", w.Code)
+	}
+	w = serve(NewHandler(&mockApprovalStorage{}), testutil.NewRequest(t, http.MethodDelete, "/api/v1/approval-rules/org.settings", ""))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", w.Code)
+	}
+}
+
+func TestList_Filters(t *testing.T) {
+	m := &mockApprovalStorage{}
+	w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/approvals?status=expired&operation=org.settings", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.filter.Status != approval.StatusExpired || m.filter.Operation != approval.OpOrgSettings {
+		t.Errorf("filter = %+v", m.filter)
+	}
+
+	for _, q := range []string{"status=gone", "operation=org.delete"} {
+		w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/approvals?"+q, ""))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d", q, w.Code)
+		}
+	}
+}
+
+func TestGet_NotFound(t *testing.T) {
+	w := serve(NewHandler(&mockApprovalStorage{}), testutil.NewRequest(t, http.MethodGet, "/api/v1/approvals/8", ""))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", w.Code)
+	}
+}
+
+func TestDecide(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"no body", "", nil, http.StatusOK},
+		{"with note", `{"note":"Checked with finance"}`, nil, http.StatusOK},
+		{"own request", "", storage.ErrApprovalSelfDecision, http.StatusForbidden},
+		{"expired", "", storage.ErrApprovalState, http.StatusConflict},
+		{"unknown field", `{"comment":"x"}`, nil, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockApprovalStorage{decideErr: c.err}
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/approvals/9/approve", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.want == http.StatusBadRequest {
+				return
+			}
+			if m.action != approval.ActionApprove {
+				t.Errorf("action = %q", m.action)
+			}
+			if (c.body != "") != (m.note != nil) {
+				t.Errorf("note = %v", m.note)
+			}
+		})
+	}
+}
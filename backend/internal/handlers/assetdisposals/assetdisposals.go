@@ -0,0 +1,330 @@
+// Package assetdisposals serves the workflow that retires an asset that has
+// been sold, scrapped, lost or otherwise left the org: an operator requests
+// the disposal, an org admin approves or rejects it, and an operator
+// executes it once the asset has gone.
+package assetdisposals
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/assetdisposal"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const listLimit = 200
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+var statuses = []string{
+	assetdisposal.StatusPending, assetdisposal.StatusApproved, assetdisposal.StatusRejected,
+	assetdisposal.StatusCancelled, assetdisposal.StatusCompleted,
+}
+
+// DisposalStorage is the storage surface the handler needs (mockable).
+type DisposalStorage interface {
+	CreateAssetDisposal(ctx context.Context, orgID, userID int, req assetdisposal.CreateRequest) (*assetdisposal.Disposal, error)
+	ListAssetDisposals(ctx context.Context, orgID int, f assetdisposal.ListFilter) ([]assetdisposal.Disposal, error)
+	GetAssetDisposal(ctx context.Context, orgID, id int) (*assetdisposal.Disposal, error)
+	DecideAssetDisposal(ctx context.Context, orgID, id, userID int, action string, note *string) (*assetdisposal.Disposal, error)
+	ExecuteAssetDisposal(ctx context.Context, orgID, id, userID int) (*assetdisposal.Disposal, error)
+}
+
+type Handler struct {
+	storage DisposalStorage
+}
+
+func NewHandler(storage DisposalStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the disposal routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Any member can read disposals;
+// operators request, cancel and execute them; approving or rejecting is
+// admin-only, so no asset is retired without an admin's sign-off.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	operator := middleware.RequireCurrentOrgOperator(store)
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(member).Get("/api/v1/asset-disposals", h.List)
+	r.With(operator).Post("/api/v1/asset-disposals", h.Create)
+	r.With(member).Get("/api/v1/asset-disposals/{disposal_id}", h.Get)
+	r.With(admin).Post("/api/v1/asset-disposals/{disposal_id}/approve", h.decide(assetdisposal.ActionApprove))
+	r.With(admin).Post("/api/v1/asset-disposals/{disposal_id}/reject", h.decide(assetdisposal.ActionReject))
+	r.With(operator).Post("/api/v1/asset-disposals/{disposal_id}/cancel", h.decide(assetdisposal.ActionCancel))
+	r.With(operator).Post("/api/v1/asset-disposals/{disposal_id}/execute", h.Execute)
+}
+
+// caller returns the request's org and session user, answering the error
+// itself when either is missing.
+func caller(w http.ResponseWriter, r *http.Request, reqID string) (orgID, userID int, ok bool) {
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return 0, 0, false
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return 0, 0, false
+	}
+	return orgID, claims.UserID, true
+}
+
+// parseDisposalID reads the disposal_id path param, answering 400 itself.
+func parseDisposalID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("disposal_id", chi.URLParam(r, "disposal_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// respondDisposalError maps the storage sentinels to their status codes.
+func respondDisposalError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	switch {
+	case errors.Is(err, storage.ErrAssetDisposalAssetNotFound):
+		httputil.Respond404(w, r, "asset not found", reqID)
+	case errors.Is(err, storage.ErrAssetDisposalOpen),
+		errors.Is(err, storage.ErrAssetDisposed),
+		errors.Is(err, storage.ErrAssetDisposalState):
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+	default:
+		httputil.RespondStorageError(w, r, err, reqID)
+	}
+}
+
+// @Summary  Request an asset disposal
+// @Description Opens a pending disposal of one of the org's assets with the reason and how it is leaving (`method`). Nothing changes until an org admin approves and an operator executes it. An asset can have only one pending or approved disposal at a time.
+// @Tags     asset-disposals,internal
+// @ID       asset_disposals.create
+// @Accept   json
+// @Produce  json
+// @Param    request body assetdisposal.CreateRequest true "Disposal"
+// @Success  201 {object} map[string]any "data: assetdisposal.Disposal"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "asset not found"
+// @Failure  409 {object} modelerrors.ErrorResponse "the asset already has an open disposal or has been disposed of"
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-disposals [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req assetdisposal.CreateRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	d, err := h.storage.CreateAssetDisposal(r.Context(), orgID, userID, req)
+	if err != nil {
+		respondDisposalError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": d})
+}
+
+// @Summary  List asset disposals
+// @Description The org's disposals, newest first, at most 200.
+// @Tags     asset-disposals,internal
+// @ID       asset_disposals.list
+// @Produce  json
+// @Param    asset_id query int false "Only this asset" minimum(1) format(int64)
+// @Param    status query string false "Only this status" Enums(pending, approved, rejected, cancelled, completed)
+// @Success  200 {object} map[string]any "data: []assetdisposal.Disposal"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-disposals [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	f := assetdisposal.ListFilter{Limit: listLimit}
+	q := r.URL.Query()
+	if v := q.Get("asset_id"); v != "" {
+		id, err := httputil.ParseSurrogateID("asset_id", v)
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		f.AssetID = &id
+	}
+	if st := q.Get("status"); st != "" {
+		if !slices.Contains(statuses, st) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field: "status", Code: "invalid_value",
+				Message: "status must be one of: " + strings.Join(statuses, ", "),
+			}})
+			return
+		}
+		f.Status = st
+	}
+
+	list, err := h.storage.ListAssetDisposals(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Get an asset disposal
+// @Tags     asset-disposals,internal
+// @ID       asset_disposals.get
+// @Produce  json
+// @Param    disposal_id path int true "Disposal id"
+// @Success  200 {object} map[string]any "data: assetdisposal.Disposal"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-disposals/{disposal_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseDisposalID(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, err := h.storage.GetAssetDisposal(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "asset disposal not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// decide returns the handler for approve, reject and cancel.
+//
+// @Summary  Approve, reject or cancel an asset disposal
+// @Description approve and reject are admin-only and need a pending disposal. cancel works on a pending or approved disposal. The optional note is recorded as decision_note.
+// @Tags     asset-disposals,internal
+// @ID       asset_disposals.decide
+// @Accept   json
+// @Produce  json
+// @Param    disposal_id path int true "Disposal id"
+// @Param    action path string true "approve, reject or cancel" Enums(approve, reject, cancel)
+// @Param    request body assetdisposal.DecisionRequest false "Decision note"
+// @Success  200 {object} map[string]any "data: assetdisposal.Disposal"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "the disposal's status does not allow the action"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-disposals/{disposal_id}/{action} [post]
+func (h *Handler) decide(action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+		orgID, userID, ok := caller(w, r, reqID)
+		if !ok {
+			return
+		}
+		id, ok := parseDisposalID(w, r, reqID)
+		if !ok {
+			return
+		}
+		var req assetdisposal.DecisionRequest
+		if r.ContentLength != 0 {
+			if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+				httputil.RespondDecodeError(w, r, err, reqID)
+				return
+			}
+			if err := validate.Struct(req); err != nil {
+				httputil.RespondValidationError(w, r, err, reqID)
+				return
+			}
+		}
+		d, err := h.storage.DecideAssetDisposal(r.Context(), orgID, id, userID, action, req.Note)
+		if err != nil {
+			respondDisposalError(w, r, err, reqID)
+			return
+		}
+		if d == nil {
+			httputil.Respond404(w, r, "asset disposal not found", reqID)
+			return
+		}
+		httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+	}
+}
+
+// @Summary  Execute an approved asset disposal
+// @Description Retires the asset: it is marked disposed and inactive, and from then on reads of its tags are dropped by every scan path (fixed readers, handheld saves, EPCIS capture), so a stray read cannot put it back at a location. The asset and its scan history stay readable.
+// @Tags     asset-disposals,internal
+// @ID       asset_disposals.execute
+// @Produce  json
+// @Param    disposal_id path int true "Disposal id"
+// @Success  200 {object} map[string]any "data: assetdisposal.Disposal"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "the disposal is not approved"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-disposals/{disposal_id}/execute [post]
+func (h *Handler) Execute(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseDisposalID(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, err := h.storage.ExecuteAssetDisposal(r.Context(), orgID, id, userID)
+	if err != nil {
+		respondDisposalError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "asset disposal not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
@@ -0,0 +1,172 @@
+package assetdisposals
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/assetdisposal"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockDisposalStorage struct {
+	created   *assetdisposal.CreateRequest
+	createErr error
+	filter    assetdisposal.ListFilter
+	action    string
+	note      *string
+	decideErr error
+	execErr   error
+}
+
+func (m *mockDisposalStorage) CreateAssetDisposal(ctx context.Context, orgID, userID int, req assetdisposal.CreateRequest) (*assetdisposal.Disposal, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created = &req
+	return &assetdisposal.Disposal{ID: 9, AssetID: req.AssetID, Method: req.Method, Status: assetdisposal.StatusPending}, nil
+}
+
+func (m *mockDisposalStorage) ListAssetDisposals(ctx context.Context, orgID int, f assetdisposal.ListFilter) ([]assetdisposal.Disposal, error) {
+	m.filter = f
+	return []assetdisposal.Disposal{}, nil
+}
+
+func (m *mockDisposalStorage) GetAssetDisposal(ctx context.Context, orgID, id int) (*assetdisposal.Disposal, error) {
+	if id != 9 {
+		return nil, nil
+	}
+	return &assetdisposal.Disposal{ID: 9}, nil
+}
+
+func (m *mockDisposalStorage) DecideAssetDisposal(ctx context.Context, orgID, id, userID int, action string, note *string) (*assetdisposal.Disposal, error) {
+	m.action, m.note = action, note
+	if m.decideErr != nil {
+		return nil, m.decideErr
+	}
+	if id != 9 {
+		return nil, nil
+	}
+	return &assetdisposal.Disposal{ID: 9}, nil
+}
+
+func (m *mockDisposalStorage) ExecuteAssetDisposal(ctx context.Context, orgID, id, userID int) (*assetdisposal.Disposal, error) {
+	if m.execErr != nil {
+		return nil, m.execErr
+	}
+	return &assetdisposal.Disposal{ID: id, Status: assetdisposal.StatusCompleted}, nil
+}
+
+// serve routes through chi without the role middleware so handler-level
+// checks are exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Get("/api/v1/asset-disposals", h.List)
+		r.Post("/api/v1/asset-disposals", h.Create)
+		r.Get("/api/v1/asset-disposals/{disposal_id}", h.Get)
+		r.Post("/api/v1/asset-disposals/{disposal_id}/approve", h.decide(assetdisposal.ActionApprove))
+		r.Post("/api/v1/asset-disposals/{disposal_id}/execute", h.Execute)
+	}, req)
+}
+
+func TestCreate(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		createErr error
+		want      int
+	}{
+		{"happy", `{"asset_id":3,"reason":"Mast cracked","method":"scrapped"}`, nil, http.StatusCreated},
+		{"missing reason", `{"asset_id":3,"method":"scrapped"}`, nil, http.StatusBadRequest},
+		{"unknown method", `{"asset_id":3,"reason":"Mast cracked","method":"burned"}`, nil, http.StatusBadRequest},
+		{"asset not found", `{"asset_id":3,"reason":"Mast cracked","method":"scrapped"}`, storage.ErrAssetDisposalAssetNotFound, http.StatusNotFound},
+		{"already open", `{"asset_id":3,"reason":"Mast cracked","method":"scrapped"}`, storage.ErrAssetDisposalOpen, http.StatusConflict},
+		{"already disposed", `{"asset_id":3,"reason":"Mast cracked","method":"scrapped"}`, storage.ErrAssetDisposed, http.StatusConflict},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockDisposalStorage{createErr: c.createErr}
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/asset-disposals", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.want == http.StatusCreated && (m.created.AssetID != 3 || m.created.Method != "scrapped") {
+				t.Errorf("created = %+v", m.created)
+			}
+		})
+	}
+}
+
+func TestList_Filters(t *testing.T) {
+	m := &mockDisposalStorage{}
+	w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/asset-disposals?asset_id=3&status=approved", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.filter.AssetID == nil || *m.filter.AssetID != 3 || m.filter.Status != assetdisposal.StatusApproved {
+		t.Errorf("filter = %+v", m.filter)
+	}
+
+	for _, q := range []string{"asset_id=x", "status=gone"} {
+		w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/asset-disposals?"+q, ""))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d", q, w.Code)
+		}
+	}
+}
+
+func TestGet_NotFound(t *testing.T) {
+	w := serve(NewHandler(&mockDisposalStorage{}), testutil.NewRequest(t, http.MethodGet, "/api/v1/asset-disposals/8", ""))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", w.Code)
+	}
+}
+
+func TestDecide(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"no body", "", nil, http.StatusOK},
+		{"with note", `{"note":"Approved at review"}`, nil, http.StatusOK},
+		{"wrong state", "", storage.ErrAssetDisposalState, http.StatusConflict},
+		{"unknown field", `{"comment":"x"}`, nil, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockDisposalStorage{decideErr: c.err}
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/asset-disposals/9/approve", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.want == http.StatusBadRequest {
+				return
+			}
+			if m.action != assetdisposal.ActionApprove {
+				t.Errorf("action = %q", m.action)
+			}
+			if (c.body != "") != (m.note != nil) {
+				t.Errorf("note = %v", m.note)
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	w := serve(NewHandler(&mockDisposalStorage{}), testutil.NewRequest(t, http.MethodPost, "/api/v1/asset-disposals/9/execute", ""))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"completed"`) {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = serve(NewHandler(&mockDisposalStorage{execErr: storage.ErrAssetDisposalState}),
+		testutil.NewRequest(t, http.MethodPost, "/api/v1/asset-disposals/9/execute", ""))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
@@ -1,8 +1,10 @@
 package assets
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +16,7 @@ import (
 	"github.com/trakrf/platform/backend/internal/models/asset"
 	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/objectstore"
 	"github.com/trakrf/platform/backend/internal/services/bulkimport"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
@@ -29,12 +32,26 @@ var validate = func() *validator.Validate {
 type Handler struct {
 	storage           *storage.Storage
 	bulkImportService *bulkimport.Service
+	notifier          transferNotifier
+	// objects holds condition report photos; nil when no object store is
+	// configured.
+	objects objectstore.Store
 }
 
 func NewHandler(storage *storage.Storage) *Handler {
+	return NewHandlerWithBulkImport(storage, bulkimport.NewService(storage), nil, nil)
+}
+
+// NewHandlerWithBulkImport lets the server share one bulk import service
+// between the upload endpoint and its shutdown drain / resume job. notifier
+// emails the new owner on an ownership transfer; nil disables the email.
+// objects stores condition report photos; nil disables photo uploads.
+func NewHandlerWithBulkImport(storage *storage.Storage, bulkImportService *bulkimport.Service, notifier transferNotifier, objects objectstore.Store) *Handler {
 	return &Handler{
 		storage:           storage,
-		bulkImportService: bulkimport.NewService(storage),
+		bulkImportService: bulkImportService,
+		notifier:          notifier,
+		objects:           objects,
 	}
 }
 
@@ -71,11 +88,17 @@ var PublicRejectCreateFields = map[string]httputil.FieldRejectPolicy{
 // @Description  server-assigned external_key in the format `ASSET-NNNN` (per-organization sequence).
 // @Description  A caller-supplied external_key that collides with an existing asset returns 409.
 // @Description
+// @Description  With `auto_identifier=true` the external_key is instead drawn from the organization's
+// @Description  identifier template (prefix, zero-padded width, next value; `ASSET-NNNN` until an admin
+// @Description  sets one). The draw is atomic with the insert: concurrent creates never receive the same
+// @Description  identifier, and identifiers already in use are skipped. external_key must then be omitted.
+// @Description
 // @Description  Returns the created asset with its assigned tags. The Location response header contains the path of the created resource (resolve against the request URL per RFC 7231 §7.1.2).
 // @Tags         assets,public
 // @ID           assets.create
 // @Accept       json
 // @Produce      json
+// @Param        auto_identifier  query  bool  false  "Assign external_key from the org's identifier template"  default(false)
 // @Param        request  body  asset.CreateAssetWithTagsRequest  true  "Asset to create with optional tags"
 // @Success      201  {object}  assets.CreateAssetResponse
 // @Header       201  {string}  Location  "Path of the created resource (resolve against request URL per RFC 7231 §7.1.2)"
@@ -126,26 +149,7 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	// absent-key case; explicit null on a non-nullable field is "you sent
 	// a bad value," not "you forgot to include this." Accumulate every
 	// violation in one response (BB32 §D3 pattern).
-	var nullViolations []modelerrors.FieldError
-	for _, f := range []string{"valid_from", "is_active", "metadata"} {
-		if _, ok := explicitNulls[f]; ok {
-			nullViolations = append(nullViolations, modelerrors.FieldError{
-				Field:   f,
-				Code:    "invalid_value",
-				Message: fmt.Sprintf("%s cannot be null; omit the field to use the server default, or provide a value", f),
-			})
-		}
-	}
-	for _, f := range []string{"name", "external_key"} {
-		if _, ok := explicitNulls[f]; ok {
-			nullViolations = append(nullViolations, modelerrors.FieldError{
-				Field:   f,
-				Code:    "invalid_value",
-				Message: fmt.Sprintf("%s cannot be null; provide a string value", f),
-			})
-		}
-	}
-	if len(nullViolations) > 0 {
+	if nullViolations := createNullViolations(explicitNulls); len(nullViolations) > 0 {
 		httputil.WriteValidationError(w, r, requestID, nullViolations)
 		return
 	}
@@ -174,10 +178,27 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	// the pattern check with 400 invalid_value. The struct field is non-pointer
 	// so encoding/json cannot distinguish absent from explicit-empty on its own
 	// — presentKeys carries that signal.
-	if _, present := presentKeys["external_key"]; present {
-		type extKeyCheck struct {
-			ExternalKey string `json:"external_key" validate:"min=1,max=255,external_key_pattern"`
+	if raw := r.URL.Query().Get("auto_identifier"); raw != "" {
+		auto, err := strconv.ParseBool(raw)
+		if err != nil {
+			httputil.WriteValidationError(w, r, requestID, []modelerrors.FieldError{{
+				Field:   "auto_identifier",
+				Code:    "invalid_value",
+				Message: "auto_identifier must be true or false",
+			}})
+			return
+		}
+		if _, present := presentKeys["external_key"]; auto && present {
+			httputil.WriteValidationError(w, r, requestID, []modelerrors.FieldError{{
+				Field:   "external_key",
+				Code:    "invalid_context",
+				Message: "external_key must be omitted when auto_identifier=true",
+			}})
+			return
 		}
+		request.AutoIdentifier = auto
+	}
+	if _, present := presentKeys["external_key"]; present {
 		if err := validate.Struct(extKeyCheck{ExternalKey: request.ExternalKey}); err != nil {
 			httputil.RespondValidationErrorWithPresence(w, r, err, requestID, presentKeys, explicitNulls)
 			return
@@ -201,12 +222,20 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		httputil.WriteValidationError(w, r, requestID, []modelerrors.FieldError{*fe})
 		return
 	}
+	if fe := personFlagError(request.Metadata); fe != nil {
+		httputil.WriteValidationError(w, r, requestID, []modelerrors.FieldError{*fe})
+		return
+	}
+
+	if request.OwnerUserID != nil && !handler.requireOrgMember(w, r, requestID, orgID, *request.OwnerUserID) {
+		return
+	}
 
 	request.OrgID = orgID
 
 	result, err := handler.storage.CreateAssetWithTags(r.Context(), request)
 	if err != nil {
-		if strings.Contains(err.Error(), "already exist") {
+		if errors.Is(err, storage.ErrIdentifierSequenceExhausted) || strings.Contains(err.Error(), "already exist") {
 			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict,
 				err.Error(), requestID)
 
@@ -220,6 +249,37 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": asset.ToPublicAssetView(*result)})
 }
 
+// extKeyCheck validates an external_key sent explicitly on create, where
+// the request struct's omitempty would let an empty string through.
+type extKeyCheck struct {
+	ExternalKey string `json:"external_key" validate:"min=1,max=255,external_key_pattern"`
+}
+
+// createNullViolations reports the non-nullable create fields sent as an
+// explicit null.
+func createNullViolations(explicitNulls map[string]struct{}) []modelerrors.FieldError {
+	var out []modelerrors.FieldError
+	for _, f := range []string{"valid_from", "is_active", "metadata"} {
+		if _, ok := explicitNulls[f]; ok {
+			out = append(out, modelerrors.FieldError{
+				Field:   f,
+				Code:    "invalid_value",
+				Message: fmt.Sprintf("%s cannot be null; omit the field to use the server default, or provide a value", f),
+			})
+		}
+	}
+	for _, f := range []string{"name", "external_key"} {
+		if _, ok := explicitNulls[f]; ok {
+			out = append(out, modelerrors.FieldError{
+				Field:   f,
+				Code:    "invalid_value",
+				Message: fmt.Sprintf("%s cannot be null; provide a string value", f),
+			})
+		}
+	}
+	return out
+}
+
 // @Summary      Update an asset
 // @Description  Apply a JSON Merge Patch (RFC 7396) to an asset. Only fields included in the request body are changed; fields set to `null` clear the corresponding nullable column. Omitted fields are left unchanged. Every accepted PATCH — empty body (`{}`), verbatim echo of current values, partial mutation, or full mutation — advances `updated_at` on success (filesystem `touch` semantics). Read-only fields are uniformly governed by the accept-if-matches, reject-if-differs rule: a value matching the current resource state is silently normalized out (so a verbatim GET → PATCH round-trip succeeds without manual scrubbing), and a differing value returns 400. The rejection `code` splits the two semantic classes: server-managed fields (`id`, `created_at`, `updated_at`, `deleted_at`) return `code: read_only` — they have no public mutation path. Fields mutable via a sub-resource verb (`external_key`, `tags`) return `code: invalid_context` and the detail names the correct verb: mutate `external_key` via POST /assets/{asset_id}/rename; mutate `tags` via POST /assets/{asset_id}/tags and DELETE /assets/{asset_id}/tags/{tag_id}. The `tags` collection is compared as a set on full tag content — array ordering is not significant; differing set membership or differing field values on a matching id returns 400 `invalid_context`. Asset location is not part of the asset resource — it is scan-derived fact data, read through GET /api/v1/reports/asset-locations or GET /api/v1/assets/{asset_id}/history; `location_id` / `location_external_key` in a request body are rejected 400 `read_only`.
 // @Tags         assets,public
@@ -319,6 +379,9 @@ func (handler *Handler) doUpdate(w http.ResponseWriter, req *http.Request, orgID
 	if _, ok := explicitNulls["description"]; ok {
 		request.ClearDescription = true
 	}
+	if _, ok := explicitNulls["cost_center"]; ok {
+		request.ClearCostCenter = true
+	}
 
 	// TRA-699 (BB31 §2): natural-key echo check. external_key is read-only on
 	// PATCH but accepts a verbatim echo of the current value as a silent
@@ -427,6 +490,20 @@ func (handler *Handler) doUpdate(w http.ResponseWriter, req *http.Request, orgID
 			})
 		}
 	}
+	if _, present := presentKeys["owner_user_id"]; present {
+		// Ownership changes go through POST /transfer so membership is
+		// checked and the new owner is notified; PATCH only accepts an echo.
+		_, isNull := explicitNulls["owner_user_id"]
+		matched := (isNull && current.OwnerUserID == nil) ||
+			(request.OwnerUserID != nil && current.OwnerUserID != nil && *request.OwnerUserID == *current.OwnerUserID)
+		if !matched {
+			echoViolations = append(echoViolations, modelerrors.FieldError{
+				Field:   "owner_user_id",
+				Code:    "invalid_context",
+				Message: `owner_user_id is immutable via PATCH; use POST /api/v1/assets/{asset_id}/transfer with body {"owner_user_id": <user id>} to change it`,
+			})
+		}
+	}
 	if len(echoViolations) > 0 {
 		// TRA-702 / BB32 D2: detail must echo fields[0].Message — the inline
 		// literal "validation failed" buried the redirect-to-/rename message
@@ -440,6 +517,9 @@ func (handler *Handler) doUpdate(w http.ResponseWriter, req *http.Request, orgID
 	request.ExternalKey = nil
 	delete(presentKeys, "external_key")
 	delete(explicitNulls, "external_key")
+	request.OwnerUserID = nil
+	delete(presentKeys, "owner_user_id")
+	delete(explicitNulls, "owner_user_id")
 
 	if err := validate.Struct(request); err != nil {
 		httputil.RespondValidationErrorWithPresence(w, req, err, reqID, presentKeys, explicitNulls)
@@ -468,6 +548,12 @@ func (handler *Handler) doUpdate(w http.ResponseWriter, req *http.Request, orgID
 		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
 		return
 	}
+	if request.Metadata != nil {
+		if fe := personFlagError(*request.Metadata); fe != nil {
+			httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
+			return
+		}
+	}
 
 	result, err := handler.storage.UpdateAsset(req.Context(), orgID, id, request)
 	if err != nil {
@@ -665,8 +751,14 @@ func (handler *Handler) Rename(w http.ResponseWriter, req *http.Request) {
 // @Param external_key          query []string false "filter by asset external_key, equality match (may repeat for any-of)" collectionFormat(multi)
 // @Param is_active             query bool   false "filter by active flag"
 // @Param include_deleted       query bool   false "when true, include soft-deleted rows in the response. deleted_at is populated for those rows. Orthogonal to is_active." default(false)
+// @Param owner_user_id         query []int  false "filter by owning user id (may repeat for any-of)" collectionFormat(multi)
+// @Param cost_center           query []string false "filter by cost center, equality match (may repeat for any-of)" collectionFormat(multi)
+// @Param team_id               query []int  false "filter by assigned team id (may repeat for any-of)" collectionFormat(multi)
 // @Param q                     query string false "substring search (case-insensitive) on name, external_key, description, and active tag values"
+// @Param as_of                 query string false "RFC 3339 instant to evaluate the validity window at instead of now, e.g. 2026-01-01T00:00:00Z" format(date-time)
+// @Param filter                query string false "filter expression, e.g. `metadata.manufacturer eq \"Acme\" and created_at gt 2024-01-01`. Operators eq, ne, gt, ge, lt, le, contains, in; combine with and, or, not and parentheses. Fields: external_key, name, description, cost_center, id, owner_user_id, team_id, is_active, valid_from, valid_to, created_at, updated_at, metadata.<key>"
 // @Param sort                  query []string false "comma-separated; prefix '-' for DESC" collectionFormat(csv) Enums(external_key, -external_key, name, -name, created_at, -created_at, updated_at, -updated_at)
+// @Param fields                query []string false "sparse fieldset: comma-separated item keys to return (id is always included). Omitting tags also skips loading them." collectionFormat(csv)
 // @Success 200 {object} assets.ListAssetsResponse
 // @Header  200 {integer} X-RateLimit-Limit     "Steady-state requests/min for this API key"
 // @Header  200 {integer} X-RateLimit-Remaining "Requests remaining before throttling; bounded by X-RateLimit-Limit"
@@ -689,14 +781,28 @@ func (handler *Handler) ListAssets(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	f, ok := parseAssetListFilter(w, req, reqID, []string{"owner_user_id"}, nil)
+	if !ok {
+		return
+	}
+
+	handler.writeAssetList(w, req, orgID, f)
+}
+
+// parseAssetListFilter parses the assets list query string into a ListFilter,
+// writing a 400 and returning ok=false on any invalid parameter. extraFilters
+// and extraFields name endpoint-specific filters and response keys on top of
+// the common set.
+func parseAssetListFilter(w http.ResponseWriter, req *http.Request, reqID string, extraFilters, extraFields []string) (asset.ListFilter, bool) {
 	params, err := httputil.ParseListParams(req, httputil.ListAllowlist{
-		Filters:     []string{"external_key", "is_active", "include_deleted", "q"},
+		Filters:     append([]string{"external_key", "is_active", "include_deleted", "q", "cost_center", "team_id", "filter", "as_of"}, extraFilters...),
 		BoolFilters: []string{"is_active", "include_deleted"},
 		Sorts:       []string{"external_key", "name", "created_at", "updated_at"},
+		Fields:      append(slices.Clone(asset.PublicFields), extraFields...),
 	})
 	if err != nil {
 		httputil.RespondListParamError(w, req, err, reqID)
-		return
+		return asset.ListFilter{}, false
 	}
 
 	// TRA-713 / BB33 F5+C2: external_key-style filters must enforce the
@@ -706,14 +812,37 @@ func (handler *Handler) ListAssets(w http.ResponseWriter, req *http.Request) {
 	// integration bugs at the boundary.
 	if fe := httputil.ValidateExternalKeyFilterValues("external_key", params.Filters["external_key"]); fe != nil {
 		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
-		return
+		return asset.ListFilter{}, false
+	}
+
+	filter, fe := httputil.ParseFilterParam(params.Filters["filter"], asset.FilterFields)
+	if fe != nil {
+		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
+		return asset.ListFilter{}, false
+	}
+
+	asOf, fe := httputil.ParseAsOfParam(params.Filters["as_of"])
+	if fe != nil {
+		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
+		return asset.ListFilter{}, false
 	}
 
 	f := asset.ListFilter{
+		AsOf:         asOf,
+		Filter:       filter,
+		Fields:       params.Fields,
 		ExternalKeys: params.Filters["external_key"],
+		CostCenters:  params.Filters["cost_center"],
 		Limit:        params.Limit,
 		Offset:       params.Offset,
 	}
+	var ok bool
+	if f.OwnerUserIDs, ok = parsePositiveIntFilter(w, req, reqID, "owner_user_id", params.Filters["owner_user_id"]); !ok {
+		return asset.ListFilter{}, false
+	}
+	if f.TeamIDs, ok = parsePositiveIntFilter(w, req, reqID, "team_id", params.Filters["team_id"]); !ok {
+		return asset.ListFilter{}, false
+	}
 	if vs, ok := params.Filters["is_active"]; ok && len(vs) > 0 {
 		b := vs[0] == "true"
 		f.IsActive = &b
@@ -727,6 +856,42 @@ func (handler *Handler) ListAssets(w http.ResponseWriter, req *http.Request) {
 	for _, s := range params.Sorts {
 		f.Sorts = append(f.Sorts, asset.ListSort{Field: s.Field, Desc: s.Desc})
 	}
+	return f, true
+}
+
+// parsePositiveIntFilter parses the repeated id filter name, writing a 400 and
+// returning ok=false on the first value that is not a positive integer.
+func parsePositiveIntFilter(w http.ResponseWriter, req *http.Request, reqID, name string, vs []string) ([]int, bool) {
+	if len(vs) == 0 {
+		return nil, true
+	}
+	out := make([]int, 0, len(vs))
+	for _, s := range vs {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{{
+				Field:   name,
+				Code:    "invalid_value",
+				Message: fmt.Sprintf("%s %q must be a positive integer", name, s),
+			}})
+			return nil, false
+		}
+		out = append(out, n)
+	}
+	return out, true
+}
+
+// writeAssetList runs f against storage, narrowed to the caller's team and
+// location scopes, and writes the ListAssetsResponse.
+func (handler *Handler) writeAssetList(w http.ResponseWriter, req *http.Request, orgID int, f asset.ListFilter) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	scope, subtree, err := handler.accessScopes(req, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	f.TeamScope, f.SubtreeScope = scope, subtree
 
 	items, err := handler.storage.ListAssetsFiltered(req.Context(), orgID, f)
 	if err != nil {
@@ -749,21 +914,22 @@ func (handler *Handler) ListAssets(w http.ResponseWriter, req *http.Request) {
 		out = append(out, asset.ToPublicAssetView(a))
 	}
 
-	httputil.WriteJSON(w, http.StatusOK, ListAssetsResponse{
+	httputil.WriteSparseJSON(w, http.StatusOK, ListAssetsResponse{
 		Data:       out,
-		Limit:      params.Limit,
-		Offset:     params.Offset,
+		Limit:      f.Limit,
+		Offset:     f.Offset,
 		TotalCount: total,
-	})
+	}, f.Fields)
 }
 
 // @Summary Get asset by canonical id
 // @Description Retrieve an asset by its canonical id. Returns 404 if the asset does not exist.
 // @Description
-// @Description Path-addressed retrieval bypasses the temporal-validity filter applied on list endpoints — any non-deleted asset is returned regardless of its `valid_from` / `valid_to` values. Use this endpoint when you have an id and need the row even if its effective window has elapsed.
+// @Description Path-addressed retrieval bypasses the temporal-validity filter applied on list endpoints — any non-deleted asset is returned regardless of its `valid_from` / `valid_to` values. Use this endpoint when you have an id and need the row even if its effective window has elapsed. Pass `as_of` to apply the window at that instant instead: an asset not effective then returns 404.
 // @Tags assets,public
 // @ID assets.get
 // @Param asset_id path int true "Asset id (canonical)" minimum(1) format(int64)
+// @Param as_of query string false "RFC 3339 instant; 404 unless the asset's validity window contains it" format(date-time)
 // @Success 200 {object} assets.GetAssetResponse
 // @Header  200 {integer} X-RateLimit-Limit     "Steady-state requests/min for this API key"
 // @Header  200 {integer} X-RateLimit-Remaining "Requests remaining before throttling; bounded by X-RateLimit-Limit"
@@ -791,6 +957,11 @@ func (handler *Handler) GetAsset(w http.ResponseWriter, req *http.Request) {
 		httputil.RespondPathParamError(w, req, err, reqID)
 		return
 	}
+	asOf, fe := httputil.ParseAsOfParam(req.URL.Query()["as_of"])
+	if fe != nil {
+		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
+		return
+	}
 
 	view, err := handler.storage.GetAssetViewWithTagsByID(req.Context(), orgID, id)
 	if err != nil {
@@ -799,10 +970,13 @@ func (handler *Handler) GetAsset(w http.ResponseWriter, req *http.Request) {
 
 		return
 	}
-	if view == nil {
+	if view == nil || (asOf != nil && !shared.EffectiveAt(view.ValidFrom, view.ValidTo, *asOf)) {
 		httputil.Respond404(w, req, apierrors.AssetNotFound, reqID)
 		return
 	}
+	if !handler.requireVisible(w, req, reqID, orgID, view.ID) {
+		return
+	}
 
 	httputil.WriteJSON(w, http.StatusOK, map[string]any{
 		"data": asset.ToPublicAssetView(*view),
@@ -853,9 +1027,10 @@ func (handler *Handler) AddTag(w http.ResponseWriter, r *http.Request) {
 }
 
 // doAddAssetTag decodes the tag body, validates it, and inserts via storage.
-// Caller must have already verified that (orgID, assetID) names a real asset
-// — storage.AddTagToAsset does NOT cross-check ownership before INSERT, so
-// skipping the pre-check would allow cross-org tag attachment.
+// Callers verify (orgID, assetID) up front for the 404; storage.AddTagToAsset
+// re-checks ownership in the INSERT and the database refuses a tag whose
+// asset is in another org, so a skipped or raced pre-check still cannot
+// attach across orgs.
 func (handler *Handler) doAddAssetTag(w http.ResponseWriter, r *http.Request, orgID, assetID int) {
 	requestID := middleware.GetRequestID(r.Context())
 
@@ -875,6 +1050,10 @@ func (handler *Handler) doAddAssetTag(w http.ResponseWriter, r *http.Request, or
 	}
 
 	tag, err := handler.storage.AddTagToAsset(r.Context(), orgID, assetID, request)
+	if errors.Is(err, storage.ErrTagTargetNotFound) {
+		httputil.Respond404(w, r, apierrors.AssetNotFound, requestID)
+		return
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "already exist") {
 			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict,
@@ -937,6 +1116,9 @@ func (handler *Handler) RemoveTag(w http.ResponseWriter, r *http.Request) {
 		httputil.Respond404(w, r, apierrors.AssetNotFound, requestID)
 		return
 	}
+	if !handler.requireVisible(w, r, requestID, orgID, a.ID) {
+		return
+	}
 
 	handler.doRemoveAssetTag(w, r, orgID, a.ID)
 }
@@ -992,6 +1174,9 @@ func (handler *Handler) parseAndVerifyAssetID(w http.ResponseWriter, req *http.R
 		httputil.Respond404(w, req, apierrors.AssetNotFound, reqID)
 		return 0, false
 	}
+	if !handler.requireVisible(w, req, reqID, orgID, a.ID) {
+		return 0, false
+	}
 
 	return a.ID, true
 }
@@ -1004,4 +1189,11 @@ func (handler *Handler) RegisterRoutes(r chi.Router, paidGate func(http.Handler)
 	// stays open (the gate self-skips non-mutating methods anyway).
 	r.With(paidGate).Post("/api/v1/assets/bulk", handler.UploadCSV)
 	r.Get("/api/v1/assets/bulk/{jobId}", handler.GetJobStatus)
+	r.Get("/api/v1/me/assets", handler.ListMyAssets)
+	// Assets with tags and current location, for the web app's asset table.
+	r.Get("/api/v1/assets/views", handler.ListAssetViews)
+	// Streamed CSV download of the asset list, unpaginated.
+	r.Get("/api/v1/assets/export", handler.ExportAssetsCSV)
+	// Probable duplicates, for cleanup after imports from several systems.
+	r.With(middleware.RejectQueryParams("min_score", "limit")).Get("/api/v1/assets/duplicates", handler.ListDuplicates)
 }
@@ -0,0 +1,168 @@
+package assets
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// @Summary      Check in an asset
+// @Description  **Required scope:** `assets:write`
+// @Description
+// @Description  Returns the asset from its owner: clears `owner_user_id` and records a check-in with the optional `note` and `signature` (a base64 PNG in `image_png`, or pen `strokes` on a `width` x `height` canvas). The signature appears in the asset's chain-of-custody documents. `condition` records a condition report (`rating` 1-5, `notes`) linked to the check-in. 409 when the asset has no owner. Check-outs are made with POST /api/v1/assets/{asset_id}/transfer.
+// @Tags         assets,public
+// @ID           assets.check_in
+// @Accept       json
+// @Produce      json
+// @Param        asset_id path  int                       true   "Asset id (canonical)" minimum(1) format(int64)
+// @Param        request  body  asset.CheckInAssetRequest false  "Note and signature"
+// @Success      200  {object}  map[string]any                "data: PublicAssetView"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      409  {object}  modelerrors.ErrorResponse     "conflict"
+// @Failure      415  {object}  modelerrors.ErrorResponse     "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse     "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:write]
+// @Router       /api/v1/assets/{asset_id}/check-in [post]
+func (handler *Handler) CheckIn(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	var request asset.CheckInAssetRequest
+	if req.ContentLength != 0 {
+		if err := httputil.DecodeJSONStrict(req, &request); err != nil {
+			httputil.RespondDecodeError(w, req, err, reqID)
+			return
+		}
+		if err := validate.Struct(request); err != nil {
+			httputil.RespondValidationError(w, req, err, reqID)
+			return
+		}
+	}
+	rec, ok := assignmentRecord(w, req, reqID, request.Note, request.Signature, request.Condition)
+	if !ok {
+		return
+	}
+
+	result, err := handler.storage.CheckInAsset(req.Context(), orgID, id, rec)
+	if errors.Is(err, storage.ErrAssetNotCheckedOut) {
+		httputil.WriteJSONError(w, req, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+		return
+	}
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	if result == nil {
+		httputil.Respond404(w, req, apierrors.AssetNotFound, reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": asset.ToPublicAssetView(*result)})
+}
+
+// @Summary      List asset check-outs and check-ins
+// @Description  **Required scope:** `assets:read`
+// @Description
+// @Description  The asset's check-outs (ownership transfers to a new owner) and check-ins, newest first. `has_signature` tells whether a signature was captured; fetch it from GET /api/v1/assets/{asset_id}/assignments/{assignment_id}/signature.
+// @Tags         assets,public
+// @ID           assets.assignments.list
+// @Produce      json
+// @Param        asset_id path int true "Asset id (canonical)" minimum(1) format(int64)
+// @Success      200  {object}  map[string]any                "data: []asset.Assignment"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:read]
+// @Router       /api/v1/assets/{asset_id}/assignments [get]
+func (handler *Handler) ListAssignments(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	out, err := handler.storage.ListAssetAssignments(req.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": out})
+}
+
+// @Summary      Get an assignment signature
+// @Description  **Required scope:** `assets:read`
+// @Description
+// @Description  The signature captured with a check-out or check-in, as submitted. 404 when the assignment does not exist or was not signed.
+// @Tags         assets,public
+// @ID           assets.assignments.signature
+// @Produce      json
+// @Param        asset_id      path int true "Asset id (canonical)" minimum(1) format(int64)
+// @Param        assignment_id path int true "Assignment id" minimum(1) format(int64)
+// @Success      200  {object}  map[string]any                "data: asset.Signature"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:read]
+// @Router       /api/v1/assets/{asset_id}/assignments/{assignment_id}/signature [get]
+func (handler *Handler) GetAssignmentSignature(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+	assignmentID, err := httputil.ParseSurrogateID("assignment_id", chi.URLParam(req, "assignment_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, req, err, reqID)
+		return
+	}
+
+	sig, err := handler.storage.GetAssignmentSignature(req.Context(), orgID, id, assignmentID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	if sig == nil {
+		httputil.Respond404(w, req, apierrors.AssetSignatureMissing, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": sig})
+}
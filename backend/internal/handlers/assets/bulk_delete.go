@@ -0,0 +1,91 @@
+package assets
+
+import (
+	"net/http"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// BulkDeleteRequest is the body of POST /api/v1/assets/bulk-delete. One
+// request deletes at most 1000 assets.
+type BulkDeleteRequest struct {
+	AssetIDs []int `json:"asset_ids" validate:"required,min=1,max=1000,dive,gt=0" example:"101,102"`
+}
+
+// BulkDeleteResult reports which of the requested assets were deleted.
+// NotFound holds ids that were missing, already deleted, or hidden from the
+// caller by strict team mode.
+type BulkDeleteResult struct {
+	Deleted  []int `json:"deleted"`
+	NotFound []int `json:"not_found"`
+}
+
+// BulkDeleteResponse is the typed envelope returned by
+// POST /api/v1/assets/bulk-delete.
+type BulkDeleteResponse struct {
+	Data BulkDeleteResult `json:"data"`
+}
+
+// @Summary Delete several assets
+// @Description Soft-deletes up to 1000 assets of the current organization at once, with their tags, as DELETE /api/v1/assets/{asset_id} does for one. Ids that are missing, already deleted, or hidden by strict team mode are reported in `not_found` rather than failing the request. When the organization has an `assets.bulk_delete` approval rule and the request exceeds its threshold, the request is not run: it is parked as a pending approval request and returned with 202. Once another admin approves it, send the identical request again with the `X-Approval-ID` header.
+// @Tags assets,internal
+// @ID assets.bulk_delete
+// @Accept json
+// @Produce json
+// @Param request body assets.BulkDeleteRequest true "Assets to delete"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} assets.BulkDeleteResponse
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the delete"
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "forbidden"
+// @Failure 409 {object} modelerrors.ErrorResponse "conflict"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security SessionAuth
+// @Router /api/v1/assets/bulk-delete [post]
+func (handler *Handler) BulkDelete(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	var request BulkDeleteRequest
+	if err := httputil.DecodeJSONStrict(req, &request); err != nil {
+		httputil.RespondDecodeError(w, req, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, req, err, reqID)
+		return
+	}
+
+	scope, subtree, err := handler.accessScopes(req, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+
+	deleted, err := handler.storage.DeleteAssets(req.Context(), orgID, request.AssetIDs, scope, subtree)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+
+	gone := make(map[int]bool, len(deleted))
+	for _, id := range deleted {
+		gone[id] = true
+	}
+	result := BulkDeleteResult{Deleted: deleted, NotFound: []int{}}
+	for _, id := range request.AssetIDs {
+		if !gone[id] {
+			result.NotFound = append(result.NotFound, id)
+			gone[id] = true
+		}
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, BulkDeleteResponse{Data: result})
+}
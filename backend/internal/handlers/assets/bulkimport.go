@@ -1,6 +1,7 @@
 package assets
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -106,6 +107,11 @@ func (handler *Handler) UploadCSV(w http.ResponseWriter, r *http.Request) {
 	orgID := *claims.CurrentOrgID
 
 	err := r.ParseMultipartForm(6 * 1024 * 1024)
+	var mbe *http.MaxBytesError
+	if errors.As(err, &mbe) {
+		httputil.Respond413(w, r, mbe.Limit, requestID)
+		return
+	}
 	if err != nil {
 		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
 			err.Error(), requestID)
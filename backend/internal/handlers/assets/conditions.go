@@ -0,0 +1,220 @@
+package assets
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// photoExtensions maps the accepted photo types to their key suffix.
+var photoExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/webp": "webp",
+}
+
+// conditionReportNotFound is the 404 detail for an unknown condition report.
+const conditionReportNotFound = "Condition report not found"
+
+// @Summary      Record an asset condition report
+// @Description  **Required scope:** `assets:write`
+// @Description
+// @Description  Records an ad hoc condition assessment: a `rating` from 1 (unusable) to 5 (as new) and optional `notes`. Attach photo evidence afterwards with POST /api/v1/assets/{asset_id}/condition-reports/{report_id}/photos. Condition reports can also be recorded with a check-out (POST .../transfer) or check-in (POST .../check-in) via their `condition` field.
+// @Tags         assets,public
+// @ID           assets.condition_reports.create
+// @Accept       json
+// @Produce      json
+// @Param        asset_id path  int                  true  "Asset id (canonical)" minimum(1) format(int64)
+// @Param        request  body  asset.ConditionInput true  "Rating and notes"
+// @Success      201  {object}  map[string]any                "data: asset.ConditionReport"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      415  {object}  modelerrors.ErrorResponse     "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse     "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:write]
+// @Router       /api/v1/assets/{asset_id}/condition-reports [post]
+func (handler *Handler) CreateConditionReport(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	var request asset.ConditionInput
+	if err := httputil.DecodeJSONStrict(req, &request); err != nil {
+		httputil.RespondDecodeError(w, req, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, req, err, reqID)
+		return
+	}
+	out, err := handler.storage.CreateConditionReport(req.Context(), orgID, id, performedBy(req), request)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": out})
+}
+
+// @Summary      List asset condition reports
+// @Description  **Required scope:** `assets:read`
+// @Description
+// @Description  The asset's condition reports with their photos, newest first. `assignment_id` is set on reports recorded with a check-out or check-in.
+// @Tags         assets,public
+// @ID           assets.condition_reports.list
+// @Produce      json
+// @Param        asset_id path int true "Asset id (canonical)" minimum(1) format(int64)
+// @Success      200  {object}  map[string]any                "data: []asset.ConditionReport"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:read]
+// @Router       /api/v1/assets/{asset_id}/condition-reports [get]
+func (handler *Handler) ListConditionReports(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	out, err := handler.storage.ListConditionReports(req.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": out})
+}
+
+// @Summary      Attach a photo to a condition report
+// @Description  **Required scope:** `assets:write`
+// @Description
+// @Description  Uploads one photo as the raw request body (PNG, JPEG or WebP, at most 10 MiB) and attaches it to the condition report. The photo is served from the object store at the returned `url`. A report holds at most 10 photos; 409 beyond that. 503 when photo storage is not configured.
+// @Tags         assets,public
+// @ID           assets.condition_reports.photos.create
+// @Accept       image/png
+// @Accept       image/jpeg
+// @Accept       image/webp
+// @Produce      json
+// @Param        asset_id  path int    true "Asset id (canonical)" minimum(1) format(int64)
+// @Param        report_id path int    true "Condition report id" minimum(1) format(int64)
+// @Param        photo     body string true "Image bytes"
+// @Success      201  {object}  map[string]any                "data: asset.ConditionPhoto"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      409  {object}  modelerrors.ErrorResponse     "conflict"
+// @Failure      413  {object}  modelerrors.ErrorResponse     "payload_too_large"
+// @Failure      415  {object}  modelerrors.ErrorResponse     "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse     "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Failure      503  {object}  modelerrors.ErrorResponse     "service_unavailable"
+// @Security     BearerAuth[assets:write]
+// @Router       /api/v1/assets/{asset_id}/condition-reports/{report_id}/photos [post]
+func (handler *Handler) AddConditionPhoto(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+	if handler.objects == nil {
+		httputil.WriteJSONError(w, req, http.StatusServiceUnavailable, modelerrors.ErrInternal,
+			"photo uploads are unavailable (object store not configured)", reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+	reportID, err := httputil.ParseSurrogateID("report_id", chi.URLParam(req, "report_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, req, err, reqID)
+		return
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			httputil.Respond413(w, req, mbe.Limit, reqID)
+			return
+		}
+		httputil.WriteJSONError(w, req, http.StatusBadRequest, modelerrors.ErrBadRequest,
+			"Failed to read request body", reqID)
+		return
+	}
+	// Trust the bytes, not the declared type: the object is served with
+	// this Content-Type to every viewer.
+	contentType := http.DetectContentType(body)
+	if len(body) == 0 || !slices.Contains(middleware.PhotoContentTypes, contentType) {
+		httputil.WriteJSONError(w, req, http.StatusBadRequest, modelerrors.ErrValidation,
+			"Request body must be a PNG, JPEG or WebP image", reqID)
+		return
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to upload photo", reqID)
+		return
+	}
+	key := fmt.Sprintf("conditions/%d/%d/%s.%s", orgID, reportID, hex.EncodeToString(suffix), photoExtensions[contentType])
+	if err := handler.objects.Put(req.Context(), key, contentType, body); err != nil {
+		logger.Get().Error().Err(err).Int("org_id", orgID).Msg("condition photo upload failed")
+		httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to upload photo", reqID)
+		return
+	}
+
+	photo, err := handler.storage.AddConditionPhoto(req.Context(), orgID, id, reportID, handler.objects.URL(key), contentType, len(body))
+	if err != nil || photo == nil {
+		if derr := handler.objects.Delete(req.Context(), key); derr != nil {
+			logger.Get().Warn().Err(derr).Str("key", key).Msg("failed to delete orphaned condition photo")
+		}
+	}
+	switch {
+	case errors.Is(err, storage.ErrConditionPhotoLimit):
+		httputil.WriteJSONError(w, req, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+	case err != nil:
+		httputil.RespondStorageError(w, req, err, reqID)
+	case photo == nil:
+		httputil.Respond404(w, req, conditionReportNotFound, reqID)
+	default:
+		httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": photo})
+	}
+}
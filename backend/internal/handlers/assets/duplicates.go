@@ -0,0 +1,86 @@
+package assets
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const (
+	defaultDuplicateMinScore = 0.5
+	defaultDuplicateLimit    = 50
+	maxDuplicateLimit        = 200
+)
+
+// ListDuplicatesResponse is the typed envelope returned by
+// GET /api/v1/assets/duplicates.
+type ListDuplicatesResponse struct {
+	Data []asset.DuplicateSuggestion `json:"data"`
+}
+
+// @Summary List probable duplicate assets
+// @Description Pairs of live assets in the current organization that are probably the same physical asset, typically left behind by bulk imports from more than one system. Each pair carries a score between 0 and 1 and the reasons behind it: `same_serial` (the same serial number in metadata under serial_number, serial, serialNumber or sn, ignoring case and punctuation), `shared_identifier` (the same tag value on both, one since removed — the trace of a tag re-imported onto a second record), and `similar_name` (names that differ by a few characters; names that differ in their numbers, such as "Forklift 3" and "Forklift 4", do not count). Reasons combine, so a pair with two reasons outranks either alone. `keep` is the older asset of the pair and `duplicate` the newer. Best pairs first.
+// @Tags assets,internal
+// @ID assets.duplicates
+// @Produce json
+// @Param min_score query number false "Lowest score returned" default(0.5) minimum(0) maximum(1)
+// @Param limit     query int    false "max 200" default(50) minimum(1) maximum(200)
+// @Success 200 {object} assets.ListDuplicatesResponse
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security SessionAuth
+// @Router /api/v1/assets/duplicates [get]
+func (handler *Handler) ListDuplicates(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	f := asset.DuplicateFilter{MinScore: defaultDuplicateMinScore, Limit: defaultDuplicateLimit}
+	q := req.URL.Query()
+	if s := q.Get("min_score"); s != "" {
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil || v < 0 || v > 1 {
+			httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{{
+				Field: "min_score", Code: "invalid_value",
+				Message: fmt.Sprintf("min_score %q must be a number between 0 and 1", s),
+			}})
+			return
+		}
+		f.MinScore = v
+	}
+	if s := q.Get("limit"); s != "" {
+		v, err := strconv.Atoi(s)
+		if err != nil || v < 1 || v > maxDuplicateLimit {
+			httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{{
+				Field: "limit", Code: "invalid_value",
+				Message: fmt.Sprintf("limit %q must be an integer between 1 and %d", s, maxDuplicateLimit),
+			}})
+			return
+		}
+		f.Limit = v
+	}
+
+	scope, subtree, err := handler.accessScopes(req, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	f.TeamScope, f.SubtreeScope = scope, subtree
+
+	out, err := handler.storage.FindAssetDuplicates(req.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, ListDuplicatesResponse{Data: out})
+}
@@ -0,0 +1,165 @@
+package assets
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// exportWriteWindow is how long each page of a CSV export may take to
+// write. The deadline is pushed out before every page, so a stalled client
+// is dropped within the window rather than holding the export open until the
+// route's long-request timeout (middleware.Timeouts) ends it.
+const exportWriteWindow = 30 * time.Second
+
+// @Summary Export assets as CSV
+// @Description Streams every asset matching the GET /api/v1/assets filters as CSV, with a header row, in id order. There is no pagination: limit, offset and sort are ignored. `fields` picks and orders the columns (default: every field). Tags are written as `type:value` joined by `;`, metadata as JSON. Cells a spreadsheet would read as a formula are prefixed with `'`. If the export fails part way the connection is closed without completing the response, so a truncated download never looks whole.
+// @Tags assets,internal
+// @ID assets.export
+// @Produce text/csv
+// @Param external_key    query []string false "filter by asset external_key, equality match (may repeat for any-of)" collectionFormat(multi)
+// @Param owner_user_id   query []int    false "filter by owning user id (may repeat for any-of)" collectionFormat(multi)
+// @Param cost_center     query []string false "filter by cost center, equality match (may repeat for any-of)" collectionFormat(multi)
+// @Param team_id         query []int    false "filter by assigned team id (may repeat for any-of)" collectionFormat(multi)
+// @Param is_active       query bool     false "filter by active flag"
+// @Param include_deleted query bool     false "when true, include soft-deleted rows" default(false)
+// @Param q               query string   false "substring search (case-insensitive) on name, external_key, description, and active tag values"
+// @Param filter          query string   false "filter expression, as on GET /api/v1/assets"
+// @Param fields          query []string false "comma-separated columns, in order" collectionFormat(csv)
+// @Success 200 {file} binary
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security SessionAuth
+// @Router /api/v1/assets/export [get]
+func (handler *Handler) ExportAssetsCSV(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	f, ok := parseAssetListFilter(w, req, reqID, []string{"owner_user_id"}, nil)
+	if !ok {
+		return
+	}
+	if f.TeamScope, f.SubtreeScope, err = handler.accessScopes(req, orgID); err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	columns := f.Fields
+	if len(columns) == 0 {
+		columns = asset.PublicFields
+	}
+
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="assets.csv"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write(columns)
+	row := make([]string, len(columns))
+	err = handler.storage.StreamAssets(req.Context(), orgID, f, func(page []asset.AssetView) error {
+		_ = rc.SetWriteDeadline(time.Now().Add(exportWriteWindow))
+		for _, a := range page {
+			v := asset.ToPublicAssetView(a)
+			for i, c := range columns {
+				row[i] = assetCSVCell(v, c)
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		return rc.Flush()
+	})
+	if err != nil {
+		// The 200 and part of the body are out. Abort the connection so
+		// the client sees a failed download rather than a short file.
+		logger.Get().Warn().Err(err).Str("request_id", reqID).Int("org_id", orgID).
+			Msg("asset CSV export aborted")
+		panic(http.ErrAbortHandler)
+	}
+	cw.Flush()
+}
+
+// assetCSVCell renders field of v as a CSV cell. Null is the empty cell.
+func assetCSVCell(v asset.PublicAssetView, field string) string {
+	switch field {
+	case "id":
+		return strconv.Itoa(v.ID)
+	case "external_key":
+		return csvText(v.ExternalKey)
+	case "name":
+		return csvText(v.Name)
+	case "description":
+		return csvTextPtr(v.Description)
+	case "metadata":
+		b, _ := json.Marshal(v.Metadata)
+		return csvText(string(b))
+	case "is_active":
+		return strconv.FormatBool(v.IsActive)
+	case "valid_from":
+		return shared.FormatPublicTime(v.ValidFrom.Time)
+	case "valid_to":
+		return csvTime(v.ValidTo)
+	case "created_at":
+		return shared.FormatPublicTime(v.CreatedAt.Time)
+	case "updated_at":
+		return shared.FormatPublicTime(v.UpdatedAt.Time)
+	case "deleted_at":
+		return csvTime(v.DeletedAt)
+	case "owner_user_id":
+		if v.OwnerUserID == nil {
+			return ""
+		}
+		return strconv.Itoa(*v.OwnerUserID)
+	case "cost_center":
+		return csvTextPtr(v.CostCenter)
+	case "tags":
+		parts := make([]string, len(v.Tags))
+		for i, t := range v.Tags {
+			parts[i] = t.TagType + ":" + t.Value
+		}
+		return csvText(strings.Join(parts, ";"))
+	}
+	return ""
+}
+
+// csvText neutralises user text a spreadsheet would evaluate as a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func csvTextPtr(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return csvText(*s)
+}
+
+func csvTime(t *shared.PublicTime) string {
+	if t == nil {
+		return ""
+	}
+	return shared.FormatPublicTime(t.Time)
+}
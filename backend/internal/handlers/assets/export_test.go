@@ -0,0 +1,48 @@
+package assets
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+)
+
+func TestAssetCSVCell(t *testing.T) {
+	desc := "=HYPERLINK(\"x\")"
+	owner := 42
+	validFrom := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	v := asset.ToPublicAssetView(asset.AssetView{
+		Asset: asset.Asset{
+			ID:          7,
+			ExternalKey: "forklift-3",
+			Name:        "-Forklift 3",
+			Description: desc,
+			ValidFrom:   validFrom,
+			Metadata:    map[string]any{"serial": "SN-1"},
+			IsActive:    true,
+			OwnerUserID: &owner,
+		},
+		Tags: []shared.Tag{{TagType: "rfid", Value: "E280"}, {TagType: "ble", Value: "AA:BB"}},
+	})
+
+	cases := map[string]string{
+		"id":            "7",
+		"external_key":  "forklift-3",
+		"name":          "'-Forklift 3",
+		"description":   "'" + desc,
+		"metadata":      `{"serial":"SN-1"}`,
+		"is_active":     "true",
+		"valid_from":    "2025-01-02T03:04:05.000Z",
+		"valid_to":      "",
+		"deleted_at":    "",
+		"owner_user_id": "42",
+		"cost_center":   "",
+		"tags":          "rfid:E280;ble:AA:BB",
+	}
+	for field, want := range cases {
+		assert.Equal(t, want, assetCSVCell(v, field), field)
+	}
+}
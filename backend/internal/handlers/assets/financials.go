@@ -0,0 +1,159 @@
+package assets
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// parseFinancialAmount parses one FinancialsInput amount in currency into
+// minor units, appending a field error to errs when it is not a
+// non-negative amount of that currency.
+func parseFinancialAmount(field string, n *json.Number, currency string, errs *[]modelerrors.FieldError) *int64 {
+	if n == nil {
+		return nil
+	}
+	m, err := shared.ParseMoney(n.String(), currency)
+	if err != nil {
+		*errs = append(*errs, modelerrors.FieldError{Field: field, Code: "invalid_value", Message: err.Error()})
+		return nil
+	}
+	if m.Amount < 0 {
+		*errs = append(*errs, modelerrors.FieldError{Field: field, Code: "invalid_value",
+			Message: field + " must not be negative"})
+		return nil
+	}
+	return &m.Amount
+}
+
+// orgCurrency is the org's currency setting, for writes that name none.
+func (handler *Handler) orgCurrency(req *http.Request, orgID int) (string, error) {
+	cs, err := handler.storage.GetCurrencySettings(req.Context(), orgID)
+	if err != nil || cs == nil {
+		return organization.DefaultCurrency, err
+	}
+	return cs.Currency, nil
+}
+
+// @Summary      Get asset financials
+// @Description  **Required scope:** `assets:read`
+// @Description
+// @Description  The asset's purchase cost and book value. Amounts are `{"amount": "1234.50", "currency": "USD"}` objects with the amount as a decimal string carrying exactly the currency's minor digits; an amount never set is null. An asset without financials reports the org's currency.
+// @Tags         assets,public
+// @ID           assets.financials.get
+// @Produce      json
+// @Param        asset_id path int true "Asset id (canonical)" minimum(1) format(int64)
+// @Success      200  {object}  map[string]any                "data: asset.Financials"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:read]
+// @Router       /api/v1/assets/{asset_id}/financials [get]
+func (handler *Handler) GetFinancials(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	out, err := handler.storage.GetAssetFinancials(req.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	if out == nil {
+		currency, err := handler.orgCurrency(req, orgID)
+		if err != nil {
+			httputil.RespondStorageError(w, req, err, reqID)
+			return
+		}
+		out = &asset.Financials{AssetID: id, Currency: currency}
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": out})
+}
+
+// @Summary      Set asset financials
+// @Description  **Required scope:** `assets:write`
+// @Description
+// @Description  Full-replace of the asset's purchase cost and book value. Amounts are decimal strings (JSON numbers are accepted) in `currency`, which defaults to the org's currency setting; both amounts share it. An amount with more decimal places than the currency has (e.g. yen cents) is rejected rather than rounded, as is a negative one. A null or omitted amount is cleared.
+// @Tags         assets,public
+// @ID           assets.financials.put
+// @Accept       json
+// @Produce      json
+// @Param        asset_id path  int                   true  "Asset id (canonical)" minimum(1) format(int64)
+// @Param        request  body  asset.FinancialsInput true  "Currency and amounts"
+// @Success      200  {object}  map[string]any                "data: asset.Financials"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      415  {object}  modelerrors.ErrorResponse     "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse     "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:write]
+// @Router       /api/v1/assets/{asset_id}/financials [put]
+func (handler *Handler) PutFinancials(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	var request asset.FinancialsInput
+	if err := httputil.DecodeJSONStrict(req, &request); err != nil {
+		httputil.RespondDecodeError(w, req, err, reqID)
+		return
+	}
+
+	currency := request.Currency
+	if currency == "" {
+		if currency, err = handler.orgCurrency(req, orgID); err != nil {
+			httputil.RespondStorageError(w, req, err, reqID)
+			return
+		}
+	}
+	if !shared.IsCurrency(currency) {
+		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{{
+			Field:   "currency",
+			Code:    "invalid_value",
+			Message: "currency must be a supported ISO 4217 code, e.g. USD",
+		}})
+		return
+	}
+	var errs []modelerrors.FieldError
+	purchaseCost := parseFinancialAmount("purchase_cost", request.PurchaseCost, currency, &errs)
+	bookValue := parseFinancialAmount("book_value", request.BookValue, currency, &errs)
+	if len(errs) > 0 {
+		httputil.WriteValidationError(w, req, reqID, errs)
+		return
+	}
+
+	out, err := handler.storage.SetAssetFinancials(req.Context(), orgID, id, currency, purchaseCost, bookValue)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": out})
+}
@@ -0,0 +1,223 @@
+package assets
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// transferNotifier sends the "an asset was transferred to you" email.
+// Satisfied by *email.Client.
+type transferNotifier interface {
+	SendAssetTransferNotification(toEmail, orgName, assetName, assetExternalKey, actor string) error
+}
+
+// requireOrgMember writes a 400 on owner_user_id and returns false when
+// userID is not a member of orgID. Assets may only be owned by members of
+// the org that holds them.
+func (handler *Handler) requireOrgMember(w http.ResponseWriter, r *http.Request, reqID string, orgID, userID int) bool {
+	_, err := handler.storage.GetUserOrgRole(r.Context(), userID, orgID)
+	if errors.Is(err, storage.ErrOrgUserNotFound) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field:   "owner_user_id",
+			Code:    "invalid_value",
+			Message: "owner_user_id must be a member of this organization",
+		}})
+		return false
+	}
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return false
+	}
+	return true
+}
+
+// @Summary      Transfer asset ownership
+// @Description  **Required scope:** `assets:write`
+// @Description
+// @Description  Assign the asset to a new owner. The owner must be a member of the organization. `owner_user_id` is immutable via PATCH; this operation is the only way to change it. When the owner actually changes, the new owner is notified by email; transferring to the current owner is a no-op that returns the asset unchanged.
+// @Description
+// @Description  A transfer to a new owner is recorded as a check-out, with the optional `note`, `signature` and `condition`. The signature is either a base64 PNG (`image_png`) or pen `strokes` on a `width` x `height` canvas, and appears in the asset's chain-of-custody documents. `condition` records a condition report (`rating` 1-5, `notes`) linked to the check-out; attach photos to it with POST /api/v1/assets/{asset_id}/condition-reports/{report_id}/photos.
+// @Tags         assets,public
+// @ID           assets.transfer
+// @Accept       json
+// @Produce      json
+// @Param        asset_id path  int                        true  "Asset id (canonical)" minimum(1) format(int64)
+// @Param        request  body  asset.TransferAssetRequest true  "New owner"
+// @Success      200  {object}  map[string]any                "data: PublicAssetView"
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      415  {object}  modelerrors.ErrorResponse     "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse     "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:write]
+// @Router       /api/v1/assets/{asset_id}/transfer [post]
+func (handler *Handler) Transfer(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	var request asset.TransferAssetRequest
+	explicitNulls, presentKeys, err := httputil.DecodeJSONStrictWithNullsTolerantAndPresence(req, &request, nil)
+	if err != nil {
+		httputil.RespondDecodeError(w, req, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationErrorWithPresence(w, req, err, reqID, presentKeys, explicitNulls)
+		return
+	}
+
+	if !handler.requireOrgMember(w, req, reqID, orgID, request.OwnerUserID) {
+		return
+	}
+
+	rec, ok := assignmentRecord(w, req, reqID, request.Note, request.Signature, request.Condition)
+	if !ok {
+		return
+	}
+
+	result, previous, err := handler.storage.TransferAssetOwnership(req.Context(), orgID, id, request.OwnerUserID, rec)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	if result == nil {
+		httputil.Respond404(w, req, apierrors.AssetNotFound, reqID)
+		return
+	}
+
+	if handler.notifier != nil && (previous == nil || *previous != request.OwnerUserID) {
+		// Fire-and-forget on a detached context so a slow or failing mail
+		// provider never delays or fails the transfer.
+		go handler.notifyTransfer(context.Background(), orgID, request.OwnerUserID, result.Name, result.ExternalKey, transferActor(req))
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": asset.ToPublicAssetView(*result)})
+}
+
+// assignmentRecord checks an optional signature and builds what a check-out
+// or check-in records. It writes a 400 on signature and returns false when
+// the signature is malformed.
+func assignmentRecord(w http.ResponseWriter, req *http.Request, reqID string, note *string, sig *asset.Signature, condition *asset.ConditionInput) (asset.AssignmentRecord, bool) {
+	rec := asset.AssignmentRecord{Note: note, Signature: sig, Condition: condition}
+	if sig != nil {
+		if err := sig.Check(); err != nil {
+			httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{{
+				Field:   "signature",
+				Code:    "invalid_value",
+				Message: err.Error(),
+			}})
+			return rec, false
+		}
+	}
+	rec.PerformedBy = performedBy(req)
+	return rec, true
+}
+
+// performedBy is the session user making the request, or nil for API-key
+// callers.
+func performedBy(req *http.Request) *int {
+	if middleware.GetAPIKeyPrincipal(req) != nil {
+		return nil
+	}
+	if claims := middleware.GetUserClaims(req); claims != nil {
+		return &claims.UserID
+	}
+	return nil
+}
+
+// transferActor names the caller for the notification email: the API key's
+// name for API-key requests, otherwise the session user's email.
+func transferActor(req *http.Request) string {
+	if p := middleware.GetAPIKeyPrincipal(req); p != nil {
+		return p.Name
+	}
+	if claims := middleware.GetUserClaims(req); claims != nil {
+		return claims.Email
+	}
+	return ""
+}
+
+// notifyTransfer emails the new owner. Best-effort: failures are logged,
+// never surfaced.
+func (handler *Handler) notifyTransfer(ctx context.Context, orgID, ownerID int, assetName, externalKey, actor string) {
+	log := logger.Get()
+
+	owner, err := handler.storage.GetUserByID(ctx, ownerID)
+	if err != nil || owner == nil {
+		log.Warn().Err(err).Int("user_id", ownerID).Msg("asset transfer: owner lookup failed; skipping notification")
+		return
+	}
+	org, err := handler.storage.GetOrganizationByID(ctx, orgID)
+	if err != nil || org == nil {
+		log.Warn().Err(err).Int("org_id", orgID).Msg("asset transfer: org lookup failed; skipping notification")
+		return
+	}
+	if err := handler.notifier.SendAssetTransferNotification(owner.Email, org.Name, assetName, externalKey, actor); err != nil {
+		log.Warn().Err(err).Int("user_id", ownerID).Msg("asset transfer: notification failed")
+	}
+}
+
+// @Summary List my assets
+// @Description Paginated list of assets in the current organization owned by the signed-in user. Accepts the same filters, sort, and search as GET /api/v1/assets except `owner_user_id`, which is fixed to the caller.
+// @Tags assets,internal
+// @ID assets.list_mine
+// @Accept json
+// @Produce json
+// @Param limit           query int      false "max 200" default(50) minimum(1) maximum(200)
+// @Param offset          query int      false "min 0"   default(0) minimum(0)
+// @Param external_key    query []string false "filter by asset external_key, equality match (may repeat for any-of)" collectionFormat(multi)
+// @Param cost_center     query []string false "filter by cost center, equality match (may repeat for any-of)" collectionFormat(multi)
+// @Param team_id         query []int    false "filter by assigned team id (may repeat for any-of)" collectionFormat(multi)
+// @Param is_active       query bool     false "filter by active flag"
+// @Param include_deleted query bool     false "when true, include soft-deleted rows" default(false)
+// @Param q               query string   false "substring search (case-insensitive) on name, external_key, description, and active tag values"
+// @Param sort            query []string false "comma-separated; prefix '-' for DESC" collectionFormat(csv)
+// @Success 200 {object} assets.ListAssetsResponse
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security SessionAuth
+// @Router /api/v1/me/assets [get]
+func (handler *Handler) ListMyAssets(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	claims := middleware.GetUserClaims(req)
+	if claims == nil {
+		httputil.Respond401(w, req, "Authentication required", reqID)
+		return
+	}
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	f, ok := parseAssetListFilter(w, req, reqID, nil, nil)
+	if !ok {
+		return
+	}
+	f.OwnerUserIDs = []int{claims.UserID}
+
+	handler.writeAssetList(w, req, orgID, f)
+}
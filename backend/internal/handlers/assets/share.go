@@ -0,0 +1,146 @@
+package assets
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// sharedAssetPath is the public, unauthenticated route a share link points at.
+const sharedAssetPath = "/api/v1/shared/assets/"
+
+// ShareLinkResponse is the typed envelope returned by POST /api/v1/assets/{asset_id}/share.
+type ShareLinkResponse struct {
+	Data asset.AssetShareLink `json:"data"`
+}
+
+// SharedAssetResponse is the typed envelope returned by GET /api/v1/shared/assets/{token}.
+type SharedAssetResponse struct {
+	Data asset.SharedAssetView `json:"data"`
+}
+
+// @Summary      Create a public share link for an asset
+// @Description  **Required scope:** `assets:write`
+// @Description
+// @Description  Returns a signed URL anyone can open without signing in, e.g. from a QR sticker or a customer-facing document. The page shows only the asset's external_key, name, description and active flag — nothing about the organization, its locations, tags, people or metadata. The link expires after `expires_in_days` (1-365, default 30). Links are not stored: each call mints a new one, and every link to an asset stops working once the asset is deleted.
+// @Tags         assets,public
+// @ID           assets.share
+// @Accept       json
+// @Produce      json
+// @Param        asset_id path  int                     true   "Asset id (canonical)" minimum(1) format(int64)
+// @Param        request  body  asset.ShareAssetRequest false  "Link lifetime"
+// @Success      201  {object}  assets.ShareLinkResponse
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      415  {object}  modelerrors.ErrorResponse     "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse     "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[assets:write]
+// @Router       /api/v1/assets/{asset_id}/share [post]
+func (handler *Handler) Share(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyAssetID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	var request asset.ShareAssetRequest
+	if req.ContentLength != 0 {
+		if err := httputil.DecodeJSONStrict(req, &request); err != nil {
+			httputil.RespondDecodeError(w, req, err, reqID)
+			return
+		}
+		if err := validate.Struct(request); err != nil {
+			httputil.RespondValidationError(w, req, err, reqID)
+			return
+		}
+	}
+	days := asset.DefaultShareDays
+	if request.ExpiresInDays != nil {
+		days = *request.ExpiresInDays
+	}
+
+	expiresAt := time.Now().UTC().Add(time.Duration(days) * 24 * time.Hour).Truncate(time.Second)
+	token, err := jwt.GenerateAssetShareToken(orgID, id, expiresAt)
+	if err != nil {
+		httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+
+	// The link's origin comes from the link policy, never the Host or
+	// X-Forwarded-Proto headers, so a forged request cannot mint a link to
+	// someone else's site.
+	ld, err := handler.storage.GetLinkDomains(req.Context(), orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	var orgOrigins []string
+	if ld != nil {
+		orgOrigins = ld.Origins
+	}
+	origin := applinks.FromEnv().Origin(req.Header.Get("Origin"), orgOrigins)
+
+	httputil.WriteJSON(w, http.StatusCreated, ShareLinkResponse{Data: asset.AssetShareLink{
+		URL:       origin + sharedAssetPath + token,
+		Token:     token,
+		ExpiresAt: shared.NewPublicTime(expiresAt),
+	}})
+}
+
+// @Summary      View a shared asset
+// @Description  Public and unauthenticated: the token is the credential. Returns the limited asset view a share link exposes. An expired, tampered or unknown token, or one whose asset has been deleted, answers 404.
+// @Tags         assets,public
+// @ID           assets.shared.get
+// @Produce      json
+// @Param        token path string true "Share token from POST /api/v1/assets/{asset_id}/share"
+// @Success      200  {object}  assets.SharedAssetResponse
+// @Failure      404  {object}  modelerrors.ErrorResponse     "not_found"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Router       /api/v1/shared/assets/{token} [get]
+func (handler *Handler) GetShared(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	// Every failure is the same 404 so the endpoint does not reveal whether a
+	// token was expired, forged, or pointed at a deleted asset.
+	claims, err := jwt.ValidateAssetShareToken(chi.URLParam(req, "token"))
+	if err != nil {
+		httputil.Respond404(w, req, apierrors.AssetNotFound, reqID)
+		return
+	}
+
+	id := claims.AssetID
+	a, err := handler.storage.GetAssetByID(req.Context(), claims.OrgID, &id)
+	if err != nil {
+		httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+	if a == nil {
+		httputil.Respond404(w, req, apierrors.AssetNotFound, reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, SharedAssetResponse{
+		Data: asset.ToSharedAssetView(*a, claims.ExpiresAt.Time),
+	})
+}
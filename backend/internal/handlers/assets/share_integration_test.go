@@ -0,0 +1,62 @@
+//go:build integration
+// +build integration
+
+package assets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+// The share link's origin comes from the link policy: a forged Host or
+// X-Forwarded-Proto header, or an Origin outside the allow-list, must not
+// end up in the URL handed to the caller.
+func TestShareAsset_LinkIgnoresRequestHost(t *testing.T) {
+	t.Setenv("JWT_SECRET", "share-link-test")
+	t.Setenv("APP_BASE_URL", "https://app.example.com")
+	t.Setenv("APP_LINK_ALLOWED_ORIGINS", "https://preview.example.com")
+
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+	id := seedRoundTripAsset(t, pool, orgID, "AST-SHARE", "Shareable")
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Post("/api/v1/assets/{asset_id}/share", NewHandler(store).Share)
+
+	share := func(origin string) string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/assets/%d/share", id), nil)
+		req.Host = "attacker.example.net"
+		req.Header.Set("X-Forwarded-Proto", "http")
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		req = withRoundTripOrgContext(req, orgID)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var resp ShareLinkResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return resp.Data.URL
+	}
+
+	assert.Regexp(t, `^https://app\.example\.com/api/v1/shared/assets/`, share(""))
+	assert.Regexp(t, `^https://app\.example\.com/api/v1/shared/assets/`, share("https://attacker.example.net"))
+	assert.Regexp(t, `^https://preview\.example\.com/api/v1/shared/assets/`, share("https://preview.example.com"))
+}
@@ -48,7 +48,7 @@ func TestAddAssetTag_DuplicateValue_Returns409NamingConflictingEntity(t *testing
 	assetB := testutil.CreateTestAsset(t, pool, orgID, "AST-B")
 
 	tagType := "rfid"
-	value := "E2000000HANDLER01"
+	value := "E2000000AB0D1E01"
 	_, err := store.AddTagToAsset(context.Background(), orgID, assetA.ID,
 		shared.TagRequest{TagType: &tagType, Value: value})
 	require.NoError(t, err)
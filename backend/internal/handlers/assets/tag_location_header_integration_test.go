@@ -69,7 +69,7 @@ func TestAddAssetTag_201_EmitsLocationHeader(t *testing.T) {
 	handler := NewHandler(store)
 	router := setupTagLocationHeaderRouter(handler)
 
-	body := strings.NewReader(`{"tag_type":"rfid","value":"E2-007707"}`)
+	body := strings.NewReader(`{"tag_type":"rfid","value":"E2007707"}`)
 	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/assets/%d/tags", assetID), body)
 	req.Header.Set("Content-Type", "application/json")
 	req = withTagLocationOrgContext(req, orgID)
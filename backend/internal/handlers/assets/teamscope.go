@@ -0,0 +1,66 @@
+package assets
+
+import (
+	"net/http"
+
+	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// accessScopes resolves the caller's strict-team-mode visibility and their
+// location access policy scope. API-key callers are org-wide integrations and
+// are never restricted by either.
+func (handler *Handler) accessScopes(req *http.Request, orgID int) (team.Scope, location.SubtreeScope, error) {
+	if middleware.GetAPIKeyPrincipal(req) != nil {
+		return team.Scope{}, location.SubtreeScope{}, nil
+	}
+	claims := middleware.GetUserClaims(req)
+	if claims == nil {
+		return team.Scope{}, location.SubtreeScope{}, nil
+	}
+	ts, err := handler.storage.TeamScopeForUser(req.Context(), orgID, claims.UserID)
+	if err != nil {
+		return team.Scope{}, location.SubtreeScope{}, err
+	}
+	ss, err := handler.storage.LocationSubtreeScopeForUser(req.Context(), orgID, claims.UserID)
+	if err != nil {
+		return team.Scope{}, location.SubtreeScope{}, err
+	}
+	return ts, ss, nil
+}
+
+// requireVisible writes a 404 and returns false when the asset is hidden
+// from the caller by strict team mode, or by location access policies that
+// do not cover its current location. Hidden assets are indistinguishable
+// from missing ones.
+func (handler *Handler) requireVisible(w http.ResponseWriter, req *http.Request, reqID string, orgID, assetID int) bool {
+	ts, ss, err := handler.accessScopes(req, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return false
+	}
+	visible := true
+	if ts.Restricted {
+		teamID, err := handler.storage.GetAssetTeamID(req.Context(), orgID, assetID)
+		if err != nil {
+			httputil.RespondStorageError(w, req, err, reqID)
+			return false
+		}
+		visible = ts.Allows(teamID)
+	}
+	if visible && ss.Restricted {
+		visible, err = handler.storage.IsAssetInSubtrees(req.Context(), orgID, assetID, ss.RootIDs)
+		if err != nil {
+			httputil.RespondStorageError(w, req, err, reqID)
+			return false
+		}
+	}
+	if !visible {
+		httputil.Respond404(w, req, apierrors.AssetNotFound, reqID)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,328 @@
+package assets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ValidateAssetsRequest is the body of POST /api/v1/assets/validate: asset
+// payloads as they would be sent to POST /api/v1/assets.
+type ValidateAssetsRequest struct {
+	Assets []json.RawMessage `json:"assets" validate:"required,min=1,max=10000" swaggertype:"array,object"`
+}
+
+// AssetValidationResult is one payload's outcome, by its position in the
+// batch. A field of "(item)" means the payload as a whole.
+type AssetValidationResult struct {
+	Index       int                      `json:"index" example:"0"`
+	ExternalKey string                   `json:"external_key,omitempty" example:"forklift-3"`
+	Valid       bool                     `json:"valid"`
+	Errors      []modelerrors.FieldError `json:"errors"`
+}
+
+// AssetValidationReport is the outcome of a batch, one result per payload
+// in request order.
+type AssetValidationReport struct {
+	Total   int                     `json:"total"`
+	Valid   int                     `json:"valid"`
+	Invalid int                     `json:"invalid"`
+	Results []AssetValidationResult `json:"results"`
+}
+
+// ValidateAssetsResponse is the typed envelope returned by
+// POST /api/v1/assets/validate.
+type ValidateAssetsResponse struct {
+	Data AssetValidationReport `json:"data"`
+}
+
+// @Summary Validate asset payloads without creating them
+// @Description **Required scope:** `assets:write`
+// @Description
+// @Description Pre-flights up to 10000 asset payloads — the bodies a sync job would send to POST /api/v1/assets — and reports per payload whether it would be accepted. Nothing is written. Each result lists every problem found, keyed like the POST's validation errors:
+// @Description
+// @Description - schema: unknown fields, explicit nulls, formats and lengths (`unknown_field`, `invalid_value`, `too_long`, ...).
+// @Description - location: `location_id` / `location_external_key` are `read_only` — asset location is scan-derived and never set by a write.
+// @Description - references: an `owner_user_id` that is not a member of the organization (`invalid_value`).
+// @Description - duplicates (`duplicate`): an external_key or tag value repeated within the batch, or already held by a live asset or tag of the organization (a tag value under any type when the organization requires tag values to be unique across types). Under the organization's `block` deleted-key policy, the external_key of a deleted asset counts as held.
+// @Description
+// @Description A payload that passes can still fail when sent if the organization changes in between. The request itself fails only when the envelope is malformed or holds more than 10000 payloads.
+// @Tags assets,public
+// @ID assets.validate
+// @Accept json
+// @Produce json
+// @Param request body assets.ValidateAssetsRequest true "Asset payloads to check"
+// @Success 200 {object} assets.ValidateAssetsResponse
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "forbidden"
+// @Failure 413 {object} modelerrors.ErrorResponse "payload_too_large"
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 429 {object} modelerrors.ErrorResponse "rate_limited"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security BearerAuth[assets:write]
+// @Router /api/v1/assets/validate [post]
+func (handler *Handler) ValidateAssets(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	var request ValidateAssetsRequest
+	if err := httputil.DecodeJSONStrict(req, &request); err != nil {
+		httputil.RespondDecodeError(w, req, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, req, err, reqID)
+		return
+	}
+
+	results := make([]AssetValidationResult, len(request.Assets))
+	decoded := make([]*asset.CreateAssetWithTagsRequest, len(request.Assets))
+	for i, raw := range request.Assets {
+		decoded[i], results[i].Errors = checkAssetPayload(raw)
+		results[i].Index = i
+		if decoded[i] != nil {
+			results[i].ExternalKey = decoded[i].ExternalKey
+		}
+	}
+
+	if err := handler.checkAssetBatchRefs(req, orgID, decoded, results); err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+
+	report := AssetValidationReport{Total: len(results), Results: results}
+	for i := range results {
+		results[i].Valid = len(results[i].Errors) == 0
+		if results[i].Errors == nil {
+			results[i].Errors = []modelerrors.FieldError{}
+		}
+		if results[i].Valid {
+			report.Valid++
+		} else {
+			report.Invalid++
+		}
+	}
+	httputil.WriteJSON(w, http.StatusOK, ValidateAssetsResponse{Data: report})
+}
+
+// checkAssetPayload applies the checks POST /api/v1/assets makes before
+// touching storage, collecting every violation instead of stopping at the
+// first. It returns the decoded payload when it decoded at all, for the
+// batch-wide checks.
+func checkAssetPayload(raw json.RawMessage) (*asset.CreateAssetWithTagsRequest, []modelerrors.FieldError) {
+	var errs []modelerrors.FieldError
+
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(raw, &fields) != nil || fields == nil {
+		return nil, []modelerrors.FieldError{{
+			Field:   "(item)",
+			Code:    "invalid_value",
+			Message: "each asset must be a JSON object",
+		}}
+	}
+	rejected := make([]string, 0, len(PublicRejectCreateFields))
+	for name := range PublicRejectCreateFields {
+		if _, ok := fields[name]; ok {
+			rejected = append(rejected, name)
+			delete(fields, name)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Strings(rejected)
+		for _, name := range rejected {
+			errs = append(errs, modelerrors.FieldError{
+				Field:   name,
+				Code:    PublicRejectCreateFields[name].Code,
+				Message: PublicRejectCreateFields[name].Message,
+			})
+		}
+		raw, _ = json.Marshal(fields)
+	}
+
+	var request asset.CreateAssetWithTagsRequest
+	explicitNulls, presentKeys, err := httputil.DecodeJSONObjectStrict(raw, &request)
+	if err != nil {
+		return nil, append(errs, decodeFieldErrors(err)...)
+	}
+	nullErrs := createNullViolations(explicitNulls)
+	errs = append(errs, nullErrs...)
+	// A field reported as null is not reported again by the validators.
+	reported := func(field string) bool {
+		return slices.ContainsFunc(nullErrs, func(fe modelerrors.FieldError) bool { return fe.Field == field })
+	}
+
+	_, keySent := presentKeys["external_key"]
+	if keySent && !reported("external_key") {
+		if err := validate.Struct(extKeyCheck{ExternalKey: request.ExternalKey}); err != nil {
+			errs = append(errs, httputil.ValidationFieldErrors(err, presentKeys, explicitNulls)...)
+		}
+	}
+	if err := validate.Struct(request); err != nil {
+		for _, fe := range httputil.ValidationFieldErrors(err, presentKeys, explicitNulls) {
+			// A sent external_key was checked, more strictly, above.
+			if (keySent && fe.Field == "external_key") || reported(fe.Field) {
+				continue
+			}
+			errs = append(errs, fe)
+		}
+	}
+
+	validFrom := time.Now().UTC()
+	if request.ValidFrom != nil {
+		validFrom = request.ValidFrom.ToTime()
+	}
+	var validTo *time.Time
+	if request.ValidTo != nil {
+		t := request.ValidTo.ToTime()
+		validTo = &t
+	}
+	if fe := httputil.ValidateValidityWindow(validFrom, validTo); fe != nil {
+		errs = append(errs, *fe)
+	}
+	if fe := personFlagError(request.Metadata); fe != nil {
+		errs = append(errs, *fe)
+	}
+	return &request, errs
+}
+
+// personFlagError rejects a metadata.person that is not a JSON boolean, so
+// whether an asset is a person is never left to string matching.
+func personFlagError(metadata map[string]any) *modelerrors.FieldError {
+	v, ok := metadata[asset.PersonMetadataKey]
+	if _, isBool := v.(bool); !ok || isBool {
+		return nil
+	}
+	return &modelerrors.FieldError{
+		Field:   "metadata",
+		Code:    "invalid_value",
+		Message: "metadata.person must be true or false",
+	}
+}
+
+// decodeFieldErrors turns a payload decode failure into field errors, as
+// RespondDecodeError does for a whole body.
+func decodeFieldErrors(err error) []modelerrors.FieldError {
+	var ufe *httputil.JSONUnknownFieldsError
+	if errors.As(err, &ufe) {
+		out := make([]modelerrors.FieldError, 0, len(ufe.Fields))
+		for _, name := range ufe.Fields {
+			out = append(out, modelerrors.FieldError{
+				Field:   name,
+				Code:    "unknown_field",
+				Message: fmt.Sprintf("unknown field %q in request body", name),
+			})
+		}
+		return out
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		field := typeErr.Field
+		if i := strings.LastIndex(field, "."); i >= 0 {
+			field = field[i+1:]
+		}
+		return []modelerrors.FieldError{{
+			Field:   field,
+			Code:    "invalid_value",
+			Message: fmt.Sprintf("%s has the wrong type or format", field),
+		}}
+	}
+	return []modelerrors.FieldError{{
+		Field:   "(item)",
+		Code:    "invalid_value",
+		Message: "asset is not a valid asset payload",
+	}}
+}
+
+// checkAssetBatchRefs adds the checks that need the whole batch or the
+// org's data: repeated external_keys and tag values, ones already in use,
+// and owners who are not members.
+func (handler *Handler) checkAssetBatchRefs(req *http.Request, orgID int, decoded []*asset.CreateAssetWithTagsRequest, results []AssetValidationResult) error {
+	var keys, tagValues []string
+	var owners []int
+	for _, a := range decoded {
+		if a == nil {
+			continue
+		}
+		if a.ExternalKey != "" {
+			keys = append(keys, a.ExternalKey)
+		}
+		for _, t := range a.Tags {
+			tagValues = append(tagValues, t.Value)
+		}
+		if a.OwnerUserID != nil {
+			owners = append(owners, *a.OwnerUserID)
+		}
+	}
+	refs, err := handler.storage.LookupAssetBatchRefs(req.Context(), orgID, keys, tagValues, owners)
+	if err != nil {
+		return err
+	}
+
+	firstKey := map[string]int{}
+	firstTag := map[string]int{}
+	for i, a := range decoded {
+		if a == nil {
+			continue
+		}
+		add := func(fe modelerrors.FieldError) { results[i].Errors = append(results[i].Errors, fe) }
+
+		if k := a.ExternalKey; k != "" {
+			if first, seen := firstKey[k]; seen {
+				add(modelerrors.FieldError{Field: "external_key", Code: "duplicate",
+					Message: fmt.Sprintf("external_key %s repeats item %d", k, first)})
+			} else {
+				firstKey[k] = i
+				if refs.ExternalKeys[k] {
+					add(modelerrors.FieldError{Field: "external_key", Code: "duplicate",
+						Message: fmt.Sprintf("an asset with external_key %s already exists", k)})
+				} else if refs.BlockedExternalKeys[k] {
+					add(modelerrors.FieldError{Field: "external_key", Code: "duplicate",
+						Message: fmt.Sprintf("external_key %s belongs to a deleted asset and may not be reused", k)})
+				}
+			}
+		}
+
+		for _, t := range a.Tags {
+			typ := t.GetType()
+			// With cross-type uniqueness a value is one identity whatever
+			// its type.
+			id := typ + ":" + t.Value
+			if refs.UniqueTagsAcrossTypes {
+				id = t.Value
+			}
+			if first, seen := firstTag[id]; seen {
+				add(modelerrors.FieldError{Field: "tags", Code: "duplicate",
+					Message: fmt.Sprintf("%s tag %s repeats item %d", typ, t.Value, first)})
+				continue
+			}
+			firstTag[id] = i
+			live := refs.TagTypes[t.Value]
+			if slices.Contains(live, typ) || (refs.UniqueTagsAcrossTypes && len(live) > 0) {
+				add(modelerrors.FieldError{Field: "tags", Code: "duplicate",
+					Message: fmt.Sprintf("tag value %s is already in use", t.Value)})
+			}
+		}
+
+		if a.OwnerUserID != nil && !refs.Members[*a.OwnerUserID] {
+			add(modelerrors.FieldError{Field: "owner_user_id", Code: "invalid_value",
+				Message: "owner_user_id must be a member of this organization"})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+//go:build integration
+// +build integration
+
+// POST /api/v1/assets/validate pre-flights asset payloads: per-item results,
+// batch and in-org duplicates, and nothing written.
+
+package assets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func postValidate(t *testing.T, handler *Handler, orgID int, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Post("/api/v1/assets/validate", handler.ValidateAssets)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/assets/validate", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = withExternalKeyOrgContext(req, orgID)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestValidateAssets_Batch(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+
+	existing := seedAssetForFilter(t, pool, orgID, "FL-EXISTING", "Existing")
+	_, err := pool.Exec(context.Background(), `
+		INSERT INTO trakrf.tags (org_id, type, value, asset_id) VALUES ($1, 'rfid', 'E2800001', $2)`,
+		orgID, existing)
+	require.NoError(t, err)
+
+	rec := postValidate(t, NewHandler(store), orgID, `{"assets": [
+		{"name": "Forklift 1", "external_key": "FL-1", "tags": [{"tag_type": "rfid", "value": "E2800002"}]},
+		{"name": "Forklift 1 again", "external_key": "FL-1"},
+		{"name": "Old forklift", "external_key": "FL-EXISTING"},
+		{"name": "Tagged twice", "tags": [{"tag_type": "rfid", "value": "E2800001"}]},
+		{"name": "Same tag", "tags": [{"tag_type": "rfid", "value": "E2800002"}]},
+		{"name": "Docked", "location_id": 7},
+		{"name": "Outsider", "owner_user_id": 987654321},
+		"not an object"
+	]}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var resp ValidateAssetsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	report := resp.Data
+	assert.Equal(t, 8, report.Total)
+	assert.Equal(t, 1, report.Valid)
+	assert.Equal(t, 7, report.Invalid)
+
+	want := []map[string]string{
+		{},
+		{"external_key": "duplicate"},
+		{"external_key": "duplicate"},
+		{"tags": "duplicate"},
+		{"tags": "duplicate"},
+		{"location_id": "read_only"},
+		{"owner_user_id": "invalid_value"},
+		{"(item)": "invalid_value"},
+	}
+	for i, r := range report.Results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, want[i], fieldCodes(r.Errors), "item %d: %+v", i, r.Errors)
+		assert.Equal(t, len(want[i]) == 0, r.Valid)
+	}
+
+	var n int
+	require.NoError(t, pool.QueryRow(context.Background(),
+		`SELECT count(*) FROM trakrf.assets WHERE org_id = $1`, orgID).Scan(&n))
+	assert.Equal(t, 1, n, "validation writes nothing")
+}
+
+func TestValidateAssets_EnvelopeErrors(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+	handler := NewHandler(store)
+
+	assert.Equal(t, http.StatusBadRequest, postValidate(t, handler, orgID, `{"assets": []}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postValidate(t, handler, orgID, `{"items": [{}]}`).Code)
+
+	var big bytes.Buffer
+	big.WriteString(`{"assets": [`)
+	for i := 0; i <= 10000; i++ {
+		if i > 0 {
+			big.WriteByte(',')
+		}
+		big.WriteString(`{}`)
+	}
+	big.WriteString(`]}`)
+	assert.Equal(t, http.StatusBadRequest, postValidate(t, handler, orgID, big.String()).Code)
+}
@@ -0,0 +1,60 @@
+package assets
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+)
+
+func fieldCodes(errs []modelerrors.FieldError) map[string]string {
+	out := map[string]string{}
+	for _, fe := range errs {
+		out[fe.Field] = fe.Code
+	}
+	return out
+}
+
+func TestCheckAssetPayload(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want map[string]string
+	}{
+		{"valid", `{"name":"Forklift 3","external_key":"forklift-3","tags":[{"tag_type":"rfid","value":"E280AB12"}]}`, map[string]string{}},
+		{"valid without key", `{"name":"Forklift 3"}`, map[string]string{}},
+		{"not an object", `["x"]`, map[string]string{"(item)": "invalid_value"}},
+		{"missing name", `{"external_key":"forklift-3"}`, map[string]string{"name": "required"}},
+		{"null name reported once", `{"name":null}`, map[string]string{"name": "invalid_value"}},
+		{"bad key pattern", `{"name":"Forklift","external_key":"fork lift"}`, map[string]string{"external_key": "invalid_value"}},
+		{"empty key", `{"name":"Forklift","external_key":""}`, map[string]string{"external_key": "too_short"}},
+		{"unknown fields", `{"name":"Forklift","colour":"red","size":3}`, map[string]string{"colour": "unknown_field", "size": "unknown_field"}},
+		{"location is read only", `{"name":"Forklift","location_id":12,"location_external_key":"DOCK"}`,
+			map[string]string{"location_id": "read_only", "location_external_key": "read_only"}},
+		{"location and schema together", `{"location_id":12}`, map[string]string{"location_id": "read_only", "name": "required"}},
+		{"wrong type", `{"name":"Forklift","is_active":"yes"}`, map[string]string{"is_active": "invalid_value"}},
+		{"inverted validity", `{"name":"Forklift","valid_from":"2026-01-02T00:00:00Z","valid_to":"2026-01-01T00:00:00Z"}`,
+			map[string]string{"valid_to": "invalid_value"}},
+		{"person flag", `{"name":"Dana","metadata":{"person":true}}`, map[string]string{}},
+		{"person flag not a boolean", `{"name":"Dana","metadata":{"person":"yes"}}`, map[string]string{"metadata": "invalid_value"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, errs := checkAssetPayload(json.RawMessage(c.body))
+			assert.Equal(t, c.want, fieldCodes(errs), "%+v", errs)
+		})
+	}
+}
+
+func TestCheckAssetPayload_ReturnsDecoded(t *testing.T) {
+	a, errs := checkAssetPayload(json.RawMessage(`{"name":"Forklift","external_key":"FL-1","location_id":3}`))
+	require.NotNil(t, a)
+	assert.Equal(t, "FL-1", a.ExternalKey)
+	assert.Len(t, errs, 1)
+
+	a, _ = checkAssetPayload(json.RawMessage(`{"name":"Forklift","bogus":1}`))
+	assert.Nil(t, a, "a payload that does not decode takes no part in the batch checks")
+}
@@ -0,0 +1,77 @@
+package assets
+
+import (
+	"net/http"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ListAssetViewsResponse is the typed envelope returned by
+// GET /api/v1/assets/views.
+type ListAssetViewsResponse struct {
+	Data       []asset.PublicAssetLocationView `json:"data"`
+	Limit      int                             `json:"limit"       example:"50"`
+	Offset     int                             `json:"offset"      example:"0"`
+	TotalCount int                             `json:"total_count" example:"100"`
+}
+
+// @Summary List assets with tags and current location
+// @Description The asset table of the web app: GET /api/v1/assets items, each with `current_location` — where the asset was last scanned, or null if it never was — so the table needs no per-asset follow-up requests. Accepts the same filters, sort, search and `fields` as GET /api/v1/assets; `fields` may also select `current_location`. Current locations lag scans by up to about a minute.
+// @Tags assets,internal
+// @ID assets.list_views
+// @Produce json
+// @Param limit           query int      false "max 200" default(50) minimum(1) maximum(200)
+// @Param offset          query int      false "min 0"   default(0) minimum(0)
+// @Param external_key    query []string false "filter by asset external_key, equality match (may repeat for any-of)" collectionFormat(multi)
+// @Param owner_user_id   query []int    false "filter by owning user id (may repeat for any-of)" collectionFormat(multi)
+// @Param cost_center     query []string false "filter by cost center, equality match (may repeat for any-of)" collectionFormat(multi)
+// @Param team_id         query []int    false "filter by assigned team id (may repeat for any-of)" collectionFormat(multi)
+// @Param is_active       query bool     false "filter by active flag"
+// @Param include_deleted query bool     false "when true, include soft-deleted rows" default(false)
+// @Param q               query string   false "substring search (case-insensitive) on name, external_key, description, and active tag values"
+// @Param filter          query string   false "filter expression, as on GET /api/v1/assets"
+// @Param sort            query []string false "comma-separated; prefix '-' for DESC" collectionFormat(csv)
+// @Param fields          query []string false "sparse fieldset: comma-separated item keys to return (id is always included)" collectionFormat(csv)
+// @Success 200 {object} assets.ListAssetViewsResponse
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security SessionAuth
+// @Router /api/v1/assets/views [get]
+func (handler *Handler) ListAssetViews(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	f, ok := parseAssetListFilter(w, req, reqID, []string{"owner_user_id"}, []string{"current_location"})
+	if !ok {
+		return
+	}
+	if f.TeamScope, f.SubtreeScope, err = handler.accessScopes(req, orgID); err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+
+	items, total, err := handler.storage.ListAssetViews(req.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+
+	out := make([]asset.PublicAssetLocationView, 0, len(items))
+	for _, a := range items {
+		out = append(out, asset.ToPublicAssetLocationView(a))
+	}
+	httputil.WriteSparseJSON(w, http.StatusOK, ListAssetViewsResponse{
+		Data:       out,
+		Limit:      f.Limit,
+		Offset:     f.Offset,
+		TotalCount: total,
+	}, f.Fields)
+}
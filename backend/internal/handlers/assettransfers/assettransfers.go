@@ -0,0 +1,327 @@
+// Package assettransfers serves the workflow that moves an asset from one org
+// to another, e.g. equipment sold between business units on the platform: the
+// sending org initiates, the receiving org approves or rejects, and the
+// sending org executes.
+package assettransfers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/assettransfer"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const listLimit = 200
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// TransferStorage is the storage surface the handler needs (mockable).
+type TransferStorage interface {
+	GetOrganizationByIdentifier(ctx context.Context, identifier string) (*organization.Organization, error)
+	CreateAssetTransfer(ctx context.Context, orgID, userID, targetOrgID int, req assettransfer.CreateRequest) (*assettransfer.Transfer, error)
+	ListAssetTransfers(ctx context.Context, orgID int, f assettransfer.ListFilter) ([]assettransfer.Transfer, error)
+	GetAssetTransfer(ctx context.Context, orgID, id int) (*assettransfer.Transfer, error)
+	DecideAssetTransfer(ctx context.Context, orgID, id, userID int, action string) (*assettransfer.Transfer, error)
+	ExecuteAssetTransfer(ctx context.Context, orgID, id, userID int) (*assettransfer.Transfer, error)
+}
+
+type Handler struct {
+	storage TransferStorage
+}
+
+func NewHandler(storage TransferStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the transfer routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Every route is admin-only: a transfer
+// gives an asset and its history away, and accepting one adds data to the org.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(admin).Get("/api/v1/asset-transfers", h.List)
+	r.With(admin).Post("/api/v1/asset-transfers", h.Create)
+	r.With(admin).Get("/api/v1/asset-transfers/{transfer_id}", h.Get)
+	r.With(admin).Post("/api/v1/asset-transfers/{transfer_id}/approve", h.decide(assettransfer.ActionApprove))
+	r.With(admin).Post("/api/v1/asset-transfers/{transfer_id}/reject", h.decide(assettransfer.ActionReject))
+	r.With(admin).Post("/api/v1/asset-transfers/{transfer_id}/cancel", h.decide(assettransfer.ActionCancel))
+	r.With(admin).Post("/api/v1/asset-transfers/{transfer_id}/execute", h.Execute)
+}
+
+// caller returns the request's org and session user, answering the error
+// itself when either is missing.
+func caller(w http.ResponseWriter, r *http.Request, reqID string) (orgID, userID int, ok bool) {
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return 0, 0, false
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return 0, 0, false
+	}
+	return orgID, claims.UserID, true
+}
+
+// respondTransferError maps the storage sentinels to their status codes.
+func respondTransferError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	switch {
+	case errors.Is(err, storage.ErrAssetTransferAssetNotFound):
+		httputil.Respond404(w, r, "asset not found", reqID)
+	case errors.Is(err, storage.ErrAssetTransferWrongSide):
+		httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden, err.Error(), reqID)
+	case errors.Is(err, storage.ErrAssetTransferOpen),
+		errors.Is(err, storage.ErrAssetTransferState),
+		errors.Is(err, storage.ErrAssetTransferConflict):
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+	case errors.Is(err, storage.ErrAssetTransferSandbox):
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "target_org_identifier", Code: "invalid_value", Message: err.Error(),
+		}})
+	default:
+		httputil.RespondStorageError(w, r, err, reqID)
+	}
+}
+
+// @Summary  Initiate an asset transfer to another org
+// @Description Opens a pending transfer of one of the caller's assets to the org named by target_org_identifier. Nothing moves until an admin of the receiving org approves and an admin of the sending org executes. With include_history, the asset's scan history is copied to the receiving org on execute. An asset can have only one pending or approved transfer at a time. Transfers between a sandbox org and a production org are rejected.
+// @Tags     asset-transfers,internal
+// @ID       asset_transfers.create
+// @Accept   json
+// @Produce  json
+// @Param    request body assettransfer.CreateRequest true "Transfer"
+// @Success  201 {object} map[string]any "data: assettransfer.Transfer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "asset or target org not found"
+// @Failure  409 {object} modelerrors.ErrorResponse "the asset already has an open transfer"
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-transfers [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req assettransfer.CreateRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	target, err := h.storage.GetOrganizationByIdentifier(r.Context(), req.TargetOrgIdentifier)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if target == nil {
+		httputil.Respond404(w, r, "target org not found", reqID)
+		return
+	}
+	if target.ID == orgID {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "target_org_identifier", Code: "invalid_value",
+			Message: "target_org_identifier must name another org",
+		}})
+		return
+	}
+
+	t, err := h.storage.CreateAssetTransfer(r.Context(), orgID, userID, target.ID, req)
+	if err != nil {
+		respondTransferError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": t})
+}
+
+// @Summary  List asset transfers
+// @Description Transfers the caller's org sends or receives, newest first, at most 200.
+// @Tags     asset-transfers,internal
+// @ID       asset_transfers.list
+// @Produce  json
+// @Param    direction query string false "incoming or outgoing; both when omitted" Enums(incoming, outgoing)
+// @Param    status query string false "Only this status" Enums(pending, approved, rejected, cancelled, completed)
+// @Success  200 {object} map[string]any "data: []assettransfer.Transfer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-transfers [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	f := assettransfer.ListFilter{Limit: listLimit}
+	q := r.URL.Query()
+	switch d := q.Get("direction"); d {
+	case "", assettransfer.DirectionIncoming, assettransfer.DirectionOutgoing:
+		f.Direction = d
+	default:
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "direction", Code: "invalid_value", Message: "direction must be one of: incoming, outgoing",
+		}})
+		return
+	}
+	switch st := q.Get("status"); st {
+	case "", assettransfer.StatusPending, assettransfer.StatusApproved, assettransfer.StatusRejected,
+		assettransfer.StatusCancelled, assettransfer.StatusCompleted:
+		f.Status = st
+	default:
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "status", Code: "invalid_value",
+			Message: "status must be one of: pending, approved, rejected, cancelled, completed",
+		}})
+		return
+	}
+
+	list, err := h.storage.ListAssetTransfers(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Get an asset transfer
+// @Tags     asset-transfers,internal
+// @ID       asset_transfers.get
+// @Produce  json
+// @Param    transfer_id path int true "Transfer id"
+// @Success  200 {object} map[string]any "data: assettransfer.Transfer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-transfers/{transfer_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, err := httputil.ParseSurrogateID("transfer_id", chi.URLParam(r, "transfer_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	t, err := h.storage.GetAssetTransfer(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if t == nil {
+		httputil.Respond404(w, r, "asset transfer not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": t})
+}
+
+// decide returns the handler for approve, reject and cancel.
+//
+// @Summary  Approve, reject or cancel an asset transfer
+// @Description approve and reject are for the receiving org and need a pending transfer. cancel is for the sending org and works on a pending or approved transfer.
+// @Tags     asset-transfers,internal
+// @ID       asset_transfers.decide
+// @Produce  json
+// @Param    transfer_id path int true "Transfer id"
+// @Param    action path string true "approve, reject or cancel" Enums(approve, reject, cancel)
+// @Success  200 {object} map[string]any "data: assettransfer.Transfer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse "the action belongs to the other org"
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "the transfer's status does not allow the action"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-transfers/{transfer_id}/{action} [post]
+func (h *Handler) decide(action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+		orgID, userID, ok := caller(w, r, reqID)
+		if !ok {
+			return
+		}
+		id, err := httputil.ParseSurrogateID("transfer_id", chi.URLParam(r, "transfer_id"))
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		t, err := h.storage.DecideAssetTransfer(r.Context(), orgID, id, userID, action)
+		if err != nil {
+			respondTransferError(w, r, err, reqID)
+			return
+		}
+		if t == nil {
+			httputil.Respond404(w, r, "asset transfer not found", reqID)
+			return
+		}
+		httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": t})
+	}
+}
+
+// @Summary  Execute an approved asset transfer
+// @Description Moves the asset to the receiving org in one step: it is removed from the sending org and recreated in the receiving org with the same external key, fields and tags, and, when the transfer includes history, its scan history (without the sender's locations and scan points). Owner, cost center and team do not carry over. The new asset's id is returned as target_asset_id. 409 when the receiving org already has a live asset with the external key or uses one of the tag values; the transfer stays approved so it can be retried.
+// @Tags     asset-transfers,internal
+// @ID       asset_transfers.execute
+// @Produce  json
+// @Param    transfer_id path int true "Transfer id"
+// @Success  200 {object} map[string]any "data: assettransfer.Transfer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse "only the sending org executes"
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/asset-transfers/{transfer_id}/execute [post]
+func (h *Handler) Execute(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, err := httputil.ParseSurrogateID("transfer_id", chi.URLParam(r, "transfer_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	t, err := h.storage.ExecuteAssetTransfer(r.Context(), orgID, id, userID)
+	if err != nil {
+		respondTransferError(w, r, err, reqID)
+		return
+	}
+	if t == nil {
+		httputil.Respond404(w, r, "asset transfer not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": t})
+}
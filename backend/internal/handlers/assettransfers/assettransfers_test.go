@@ -0,0 +1,179 @@
+package assettransfers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/assettransfer"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+const callerOrg = 42
+
+type mockTransferStorage struct {
+	created   *assettransfer.CreateRequest
+	createErr error
+	target    int
+	filter    assettransfer.ListFilter
+	action    string
+	decideErr error
+	execErr   error
+}
+
+func (m *mockTransferStorage) GetOrganizationByIdentifier(ctx context.Context, identifier string) (*organization.Organization, error) {
+	switch identifier {
+	case "acme-east":
+		return &organization.Organization{ID: 77, Identifier: identifier}, nil
+	case "self":
+		return &organization.Organization{ID: callerOrg, Identifier: identifier}, nil
+	}
+	return nil, nil
+}
+
+func (m *mockTransferStorage) CreateAssetTransfer(ctx context.Context, orgID, userID, targetOrgID int, req assettransfer.CreateRequest) (*assettransfer.Transfer, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created, m.target = &req, targetOrgID
+	return &assettransfer.Transfer{ID: 9, AssetID: req.AssetID, Status: assettransfer.StatusPending}, nil
+}
+
+func (m *mockTransferStorage) ListAssetTransfers(ctx context.Context, orgID int, f assettransfer.ListFilter) ([]assettransfer.Transfer, error) {
+	m.filter = f
+	return []assettransfer.Transfer{}, nil
+}
+
+func (m *mockTransferStorage) GetAssetTransfer(ctx context.Context, orgID, id int) (*assettransfer.Transfer, error) {
+	if id != 9 {
+		return nil, nil
+	}
+	return &assettransfer.Transfer{ID: 9}, nil
+}
+
+func (m *mockTransferStorage) DecideAssetTransfer(ctx context.Context, orgID, id, userID int, action string) (*assettransfer.Transfer, error) {
+	m.action = action
+	if m.decideErr != nil {
+		return nil, m.decideErr
+	}
+	if id != 9 {
+		return nil, nil
+	}
+	return &assettransfer.Transfer{ID: 9}, nil
+}
+
+func (m *mockTransferStorage) ExecuteAssetTransfer(ctx context.Context, orgID, id, userID int) (*assettransfer.Transfer, error) {
+	if m.execErr != nil {
+		return nil, m.execErr
+	}
+	newID := 500
+	return &assettransfer.Transfer{ID: id, Status: assettransfer.StatusCompleted, TargetAssetID: &newID}, nil
+}
+
+// serve routes through chi without the role middleware so handler-level
+// checks are exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Get("/api/v1/asset-transfers", h.List)
+		r.Post("/api/v1/asset-transfers", h.Create)
+		r.Get("/api/v1/asset-transfers/{transfer_id}", h.Get)
+		r.Post("/api/v1/asset-transfers/{transfer_id}/approve", h.decide(assettransfer.ActionApprove))
+		r.Post("/api/v1/asset-transfers/{transfer_id}/execute", h.Execute)
+	}, req)
+}
+
+func TestCreate(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		createErr error
+		want      int
+	}{
+		{"happy", `{"asset_id":3,"target_org_identifier":"acme-east","include_history":true}`, nil, http.StatusCreated},
+		{"unknown org", `{"asset_id":3,"target_org_identifier":"nowhere"}`, nil, http.StatusNotFound},
+		{"own org", `{"asset_id":3,"target_org_identifier":"self"}`, nil, http.StatusBadRequest},
+		{"missing asset", `{"target_org_identifier":"acme-east"}`, nil, http.StatusBadRequest},
+		{"asset not found", `{"asset_id":3,"target_org_identifier":"acme-east"}`, storage.ErrAssetTransferAssetNotFound, http.StatusNotFound},
+		{"already open", `{"asset_id":3,"target_org_identifier":"acme-east"}`, storage.ErrAssetTransferOpen, http.StatusConflict},
+		{"sandbox boundary", `{"asset_id":3,"target_org_identifier":"acme-east"}`, storage.ErrAssetTransferSandbox, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockTransferStorage{createErr: c.createErr}
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/asset-transfers", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.want == http.StatusCreated && (m.target != 77 || !m.created.IncludeHistory) {
+				t.Errorf("target = %d, created = %+v", m.target, m.created)
+			}
+		})
+	}
+}
+
+func TestList_Filters(t *testing.T) {
+	m := &mockTransferStorage{}
+	w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/asset-transfers?direction=incoming&status=pending", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.filter.Direction != assettransfer.DirectionIncoming || m.filter.Status != assettransfer.StatusPending {
+		t.Errorf("filter = %+v", m.filter)
+	}
+
+	for _, q := range []string{"direction=sideways", "status=lost"} {
+		w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/asset-transfers?"+q, ""))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d", q, w.Code)
+		}
+	}
+}
+
+func TestGet_NotFound(t *testing.T) {
+	w := serve(NewHandler(&mockTransferStorage{}), testutil.NewRequest(t, http.MethodGet, "/api/v1/asset-transfers/8", ""))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", w.Code)
+	}
+}
+
+func TestDecide(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"ok", nil, http.StatusOK},
+		{"wrong side", storage.ErrAssetTransferWrongSide, http.StatusForbidden},
+		{"wrong state", storage.ErrAssetTransferState, http.StatusConflict},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockTransferStorage{decideErr: c.err}
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/asset-transfers/9/approve", ""))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if m.action != assettransfer.ActionApprove {
+				t.Errorf("action = %q", m.action)
+			}
+		})
+	}
+}
+
+func TestExecute(t *testing.T) {
+	w := serve(NewHandler(&mockTransferStorage{}), testutil.NewRequest(t, http.MethodPost, "/api/v1/asset-transfers/9/execute", ""))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"target_asset_id":500`) {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = serve(NewHandler(&mockTransferStorage{execErr: storage.ErrAssetTransferConflict}),
+		testutil.NewRequest(t, http.MethodPost, "/api/v1/asset-transfers/9/execute", ""))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
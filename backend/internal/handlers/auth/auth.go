@@ -2,7 +2,6 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
 	stderrors "errors"
 	"fmt"
 	"net/http"
@@ -15,6 +14,7 @@ import (
 	"github.com/trakrf/platform/backend/internal/models/auth"
 	"github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/user"
 	authservice "github.com/trakrf/platform/backend/internal/services/auth"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
@@ -41,6 +41,9 @@ type authServicer interface {
 	GetInvitationInfo(ctx context.Context, token string) (*auth.InvitationInfoResponse, error)
 	MintAPITokenPair(ctx context.Context, jti string, scopes []string, orgID int, apiKeyID int64, userAgent, ip string) (accessToken, refreshSecret string, expiresIn int, err error)
 	RefreshAPIToken(ctx context.Context, presentedSecret, userAgent, ip string) (*authservice.APITokenResponse, error)
+	ChangePassword(ctx context.Context, userID int, orgID *int, currentPassword, newPassword, userAgent, ip string, comparePassword func(string, string) error, hashPassword func(string) (string, error), generateJWT func(int, string, *int) (string, error)) (*auth.RefreshResponse, error)
+	RequestEmailChange(ctx context.Context, userID int, newEmail, currentPassword, confirmURL string, comparePassword func(string, string) error) error
+	ConfirmEmailChange(ctx context.Context, token string) (*user.User, error)
 }
 
 // Ensure *authservice.Service satisfies authServicer at compile time.
@@ -180,8 +183,18 @@ func (handler *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": response})
 }
 
+// requestedOrigin is the origin a caller suggests for a mailed link: the URL
+// from the body when given, else the Origin header. The service only honors
+// it when it is an allowed link domain.
+func requestedOrigin(r *http.Request, fromBody string) string {
+	if fromBody != "" {
+		return fromBody
+	}
+	return r.Header.Get("Origin")
+}
+
 // @Summary Request password reset
-// @Description Send a password reset email if account exists
+// @Description Send a password reset email if account exists. The link is built server-side; reset_url (or else the Origin header) only picks its origin, and only if that origin is an allowed link domain.
 // @Tags auth,internal
 // @Accept json
 // @Produce json
@@ -192,10 +205,8 @@ func (handler *Handler) Login(w http.ResponseWriter, r *http.Request) {
 // @Router /api/v1/auth/forgot-password [post]
 func (handler *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	var request auth.ForgotPasswordRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, errors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -207,7 +218,7 @@ func (handler *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// ForgotPassword always returns nil to avoid leaking account existence
-	_ = handler.service.ForgotPassword(r.Context(), request.Email, request.ResetURL)
+	_ = handler.service.ForgotPassword(r.Context(), request.Email, requestedOrigin(r, request.ResetURL))
 
 	// Always return success to avoid leaking whether email exists
 	httputil.WriteJSON(w, http.StatusOK, auth.MessageResponse{
@@ -228,10 +239,8 @@ func (handler *Handler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
 // @Router /api/v1/auth/reset-password [post]
 func (handler *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	var request auth.ResetPasswordRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, errors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -284,10 +293,8 @@ func (handler *Handler) AcceptInvite(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request organization.AcceptInvitationRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, errors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -477,7 +484,10 @@ func (handler *Handler) RegisterRoutes(r chi.Router, jwtMiddleware func(http.Han
 	r.Post("/api/v1/auth/forgot-password", handler.ForgotPassword)
 	r.Post("/api/v1/auth/reset-password", handler.ResetPassword)
 	r.Get("/api/v1/auth/invitation-info", handler.GetInvitationInfo)
+	r.Post("/api/v1/auth/confirm-email-change", handler.ConfirmEmailChange)
 
 	// Protected auth routes
 	r.With(jwtMiddleware).Post("/api/v1/auth/accept-invite", handler.AcceptInvite)
+	r.With(jwtMiddleware).Post("/api/v1/me/password", handler.ChangePassword)
+	r.With(jwtMiddleware).Post("/api/v1/me/email", handler.ChangeEmail)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 	authmodels "github.com/trakrf/platform/backend/internal/models/auth"
 	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/user"
 	authservice "github.com/trakrf/platform/backend/internal/services/auth"
 )
 
@@ -22,6 +23,14 @@ type stubAuthService struct {
 	signupErr    error
 	loginResult  *authmodels.AuthResponse
 	loginErr     error
+
+	changePasswordResult *authmodels.RefreshResponse
+	changePasswordErr    error
+	changeEmailErr       error
+	confirmEmailResult   *user.User
+	confirmEmailErr      error
+	// linkOrigin records the origin the handler passed for a mailed link.
+	linkOrigin string
 }
 
 func (s *stubAuthService) Signup(_ context.Context, _ authmodels.SignupRequest, _, _ string, _ func(string) (string, error), _ func(int, string, *int) (string, error)) (*authmodels.AuthResponse, error) {
@@ -40,7 +49,8 @@ func (s *stubAuthService) Logout(_ context.Context, _ string) error {
 	return nil
 }
 
-func (s *stubAuthService) ForgotPassword(_ context.Context, _, _ string) error {
+func (s *stubAuthService) ForgotPassword(_ context.Context, _, origin string) error {
+	s.linkOrigin = origin
 	return nil
 }
 
@@ -64,6 +74,19 @@ func (s *stubAuthService) RefreshAPIToken(_ context.Context, _, _, _ string) (*a
 	return nil, nil
 }
 
+func (s *stubAuthService) ChangePassword(_ context.Context, _ int, _ *int, _, _, _, _ string, _ func(string, string) error, _ func(string) (string, error), _ func(int, string, *int) (string, error)) (*authmodels.RefreshResponse, error) {
+	return s.changePasswordResult, s.changePasswordErr
+}
+
+func (s *stubAuthService) RequestEmailChange(_ context.Context, _ int, _, _, origin string, _ func(string, string) error) error {
+	s.linkOrigin = origin
+	return s.changeEmailErr
+}
+
+func (s *stubAuthService) ConfirmEmailChange(_ context.Context, _ string) (*user.User, error) {
+	return s.confirmEmailResult, s.confirmEmailErr
+}
+
 // newTestHandler returns a Handler wired with the given stub service.
 func newTestHandler(svc authServicer) *Handler {
 	return &Handler{service: svc}
@@ -0,0 +1,177 @@
+package auth
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/auth"
+	"github.com/trakrf/platform/backend/internal/models/errors"
+	authservice "github.com/trakrf/platform/backend/internal/services/auth"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+	"github.com/trakrf/platform/backend/internal/util/password"
+)
+
+// currentPasswordIncorrect is the field error for a wrong current_password.
+var currentPasswordIncorrect = []errors.FieldError{{
+	Field:   "current_password",
+	Code:    "invalid_value",
+	Message: "current_password is incorrect",
+}}
+
+// @Summary Change the authenticated user's password
+// @Description Requires the current password. Every other session is signed out; the response carries a fresh token pair for this one.
+// @Tags auth,internal
+// @Accept json
+// @Produce json
+// @Param request body auth.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} auth.RefreshResponse
+// @Failure 400 {object} errors.ErrorResponse "Validation error or wrong current password"
+// @Failure 401 {object} errors.ErrorResponse "Not authenticated"
+// @Failure 415 {object} errors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /api/v1/me/password [post]
+func (handler *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", middleware.GetRequestID(r.Context()))
+		return
+	}
+	requestID := middleware.GetRequestID(r.Context())
+
+	var request auth.ChangePasswordRequest
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, requestID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, requestID)
+		return
+	}
+
+	response, err := handler.service.ChangePassword(r.Context(), claims.UserID, claims.CurrentOrgID,
+		request.CurrentPassword, request.NewPassword, r.UserAgent(), clientIP(r),
+		password.Compare, password.Hash, jwt.Generate)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, authservice.ErrCurrentPasswordIncorrect):
+			httputil.WriteValidationError(w, r, requestID, currentPasswordIncorrect)
+		case stderrors.Is(err, errors.ErrUserNotFound):
+			httputil.Respond401(w, r, "Session authentication required", requestID)
+		default:
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, errors.ErrInternal,
+				"Failed to change password", requestID)
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, response)
+}
+
+// @Summary Change the authenticated user's email address
+// @Description Requires the current password. Mails a confirmation link to new_email (confirm_url, or else the Origin header, only picks the link's origin, and only if it is an allowed link domain); the account keeps its current address until the link is followed via POST /api/v1/auth/confirm-email-change.
+// @Tags auth,internal
+// @Accept json
+// @Produce json
+// @Param request body auth.ChangeEmailRequest true "New email and current password"
+// @Success 202 {object} auth.MessageResponse
+// @Failure 400 {object} errors.ErrorResponse "Validation error or wrong current password"
+// @Failure 401 {object} errors.ErrorResponse "Not authenticated"
+// @Failure 409 {object} errors.ErrorResponse "Email already exists"
+// @Failure 415 {object} errors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security SessionAuth
+// @Router /api/v1/me/email [post]
+func (handler *Handler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", middleware.GetRequestID(r.Context()))
+		return
+	}
+	requestID := middleware.GetRequestID(r.Context())
+
+	var request auth.ChangeEmailRequest
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, requestID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, requestID)
+		return
+	}
+
+	err := handler.service.RequestEmailChange(r.Context(), claims.UserID, request.NewEmail,
+		request.CurrentPassword, requestedOrigin(r, request.ConfirmURL), password.Compare)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, authservice.ErrCurrentPasswordIncorrect):
+			httputil.WriteValidationError(w, r, requestID, currentPasswordIncorrect)
+		case stderrors.Is(err, authservice.ErrEmailUnchanged):
+			httputil.WriteValidationError(w, r, requestID, []errors.FieldError{{
+				Field:   "new_email",
+				Code:    "invalid_value",
+				Message: "new_email is already the account's email address",
+			}})
+		case stderrors.Is(err, errors.ErrUserDuplicateEmail):
+			httputil.WriteJSONError(w, r, http.StatusConflict, errors.ErrConflict,
+				"Email already exists", requestID)
+		case stderrors.Is(err, errors.ErrUserNotFound):
+			httputil.Respond401(w, r, "Session authentication required", requestID)
+		default:
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, errors.ErrInternal,
+				"Failed to change email", requestID)
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusAccepted, auth.MessageResponse{
+		Message: "A confirmation link has been sent to the new address",
+	})
+}
+
+// @Summary Confirm an email address change
+// @Description Applies the email change the mailed token names. Public: the token is the credential, so the link works from any browser.
+// @Tags auth,internal
+// @Accept json
+// @Produce json
+// @Param request body auth.ConfirmEmailChangeRequest true "Token from the confirmation link"
+// @Success 200 {object} map[string]any "data: user.User"
+// @Failure 400 {object} errors.ErrorResponse "Invalid or expired token"
+// @Failure 409 {object} errors.ErrorResponse "Email already exists"
+// @Failure 415 {object} errors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /api/v1/auth/confirm-email-change [post]
+func (handler *Handler) ConfirmEmailChange(w http.ResponseWriter, r *http.Request) {
+	requestID := middleware.GetRequestID(r.Context())
+
+	var request auth.ConfirmEmailChangeRequest
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, requestID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, requestID)
+		return
+	}
+
+	usr, err := handler.service.ConfirmEmailChange(r.Context(), request.Token)
+	if err != nil {
+		switch {
+		case stderrors.Is(err, storage.ErrEmailChangeTokenInvalid):
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, errors.ErrBadRequest,
+				"Invalid or expired email change link", requestID)
+		case stderrors.Is(err, errors.ErrUserDuplicateEmail):
+			httputil.WriteJSONError(w, r, http.StatusConflict, errors.ErrConflict,
+				"Email already exists", requestID)
+		default:
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, errors.ErrInternal,
+				"Failed to change email", requestID)
+		}
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": usr})
+}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	authmodels "github.com/trakrf/platform/backend/internal/models/auth"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/user"
+	authservice "github.com/trakrf/platform/backend/internal/services/auth"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+func meRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req.WithContext(middleware.WithUserClaimsForTest(req.Context(), &jwt.Claims{UserID: 7, Email: "a@b.io"}))
+}
+
+func TestChangePassword(t *testing.T) {
+	t.Run("requires a session", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/me/password", bytes.NewBufferString(`{}`))
+		w := httptest.NewRecorder()
+		newTestHandler(&stubAuthService{}).ChangePassword(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("short new password is a validation error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newTestHandler(&stubAuthService{}).ChangePassword(w,
+			meRequest(http.MethodPost, "/api/v1/me/password", `{"current_password":"old-password","new_password":"short"}`))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("wrong current password names the field", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newTestHandler(&stubAuthService{changePasswordErr: authservice.ErrCurrentPasswordIncorrect}).ChangePassword(w,
+			meRequest(http.MethodPost, "/api/v1/me/password", `{"current_password":"nope","new_password":"new-password"}`))
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		var body errorBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Len(t, body.Error.Fields, 1)
+		assert.Equal(t, "current_password", body.Error.Fields[0].Field)
+	})
+
+	t.Run("success returns a fresh token pair", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		svc := &stubAuthService{changePasswordResult: &authmodels.RefreshResponse{AccessToken: "a", RefreshToken: "r", ExpiresIn: 3600}}
+		newTestHandler(svc).ChangePassword(w,
+			meRequest(http.MethodPost, "/api/v1/me/password", `{"current_password":"old-password","new_password":"new-password"}`))
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp authmodels.RefreshResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, "r", resp.RefreshToken)
+	})
+}
+
+func TestChangeEmail(t *testing.T) {
+	const body = `{"new_email":"new@b.io","current_password":"pw","confirm_url":"https://app.trakrf.id/#confirm-email"}`
+	cases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"accepted", nil, http.StatusAccepted},
+		{"wrong password", authservice.ErrCurrentPasswordIncorrect, http.StatusBadRequest},
+		{"same address", authservice.ErrEmailUnchanged, http.StatusBadRequest},
+		{"address taken", modelerrors.ErrUserDuplicateEmail, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newTestHandler(&stubAuthService{changeEmailErr: tc.err}).ChangeEmail(w,
+				meRequest(http.MethodPost, "/api/v1/me/email", body))
+			assert.Equal(t, tc.want, w.Code, w.Body.String())
+		})
+	}
+
+	t.Run("without confirm_url the Origin header suggests the link origin", func(t *testing.T) {
+		svc := &stubAuthService{}
+		req := meRequest(http.MethodPost, "/api/v1/me/email", `{"new_email":"new@b.io","current_password":"pw"}`)
+		req.Header.Set("Origin", "https://staging.trakrf.id")
+		w := httptest.NewRecorder()
+		newTestHandler(svc).ChangeEmail(w, req)
+		assert.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+		assert.Equal(t, "https://staging.trakrf.id", svc.linkOrigin)
+	})
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	cases := []struct {
+		name string
+		svc  *stubAuthService
+		want int
+	}{
+		{"applied", &stubAuthService{confirmEmailResult: &user.User{ID: 7, Email: "new@b.io"}}, http.StatusOK},
+		{"bad token", &stubAuthService{confirmEmailErr: storage.ErrEmailChangeTokenInvalid}, http.StatusBadRequest},
+		{"address taken since", &stubAuthService{confirmEmailErr: modelerrors.ErrUserDuplicateEmail}, http.StatusConflict},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/confirm-email-change", bytes.NewBufferString(`{"token":"abc"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			newTestHandler(tc.svc).ConfirmEmailChange(w, req)
+			assert.Equal(t, tc.want, w.Code, w.Body.String())
+		})
+	}
+}
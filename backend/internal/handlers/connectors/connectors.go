@@ -0,0 +1,450 @@
+// Package connectors provides internal (session-authenticated), org-admin-only
+// endpoints for ERP connectors: configuring a connector (config, write-only
+// credentials, field mapping, conflict policy, schedule), triggering a sync
+// and reading its run history. The sync itself runs in the connector sync
+// job (internal/connectors).
+package connectors
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/connectors"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/connector"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// recentRuns is how many sync runs GET .../runs returns.
+const recentRuns = 50
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	return v
+}()
+
+// ConnectorStorage is the narrow storage surface the handler needs (mockable).
+type ConnectorStorage interface {
+	ListConnectors(ctx context.Context, orgID int) ([]connector.Connector, error)
+	GetConnector(ctx context.Context, orgID, id int) (*connector.Connector, error)
+	CreateConnector(ctx context.Context, c connector.Connector) (*connector.Connector, error)
+	UpdateConnector(ctx context.Context, orgID, id int, req connector.UpdateConnectorRequest, sealedCredentials []byte) (*connector.Connector, error)
+	DeleteConnector(ctx context.Context, orgID, id int) (bool, error)
+	RequestConnectorSync(ctx context.Context, orgID, id int) (bool, error)
+	ListConnectorSyncRuns(ctx context.Context, orgID, connectorID, limit int) ([]connector.SyncRun, error)
+}
+
+type Handler struct {
+	storage ConnectorStorage
+	vault   *connectors.Vault
+}
+
+// NewHandler builds the handler. vault may be nil when neither
+// CONNECTOR_VAULT_KEY nor FIELD_ENCRYPTION_KEYS is set; writes that carry
+// credentials then answer 503.
+func NewHandler(storage ConnectorStorage, vault *connectors.Vault) *Handler {
+	return &Handler{storage: storage, vault: vault}
+}
+
+// RegisterRoutes wires the connector routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Every route is org-admin only: a
+// connector holds ERP credentials and writes to every asset in the org.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(admin).Get("/api/v1/connectors", h.List)
+	r.With(admin).Post("/api/v1/connectors", h.Create)
+	r.With(admin).Get("/api/v1/connectors/{connector_id}", h.Get)
+	r.With(admin).Patch("/api/v1/connectors/{connector_id}", h.Update)
+	r.With(admin).Delete("/api/v1/connectors/{connector_id}", h.Delete)
+	r.With(admin).Post("/api/v1/connectors/{connector_id}/sync", h.Sync)
+	r.With(admin).Get("/api/v1/connectors/{connector_id}/runs", h.ListRuns)
+}
+
+func (h *Handler) respondVaultUnavailable(w http.ResponseWriter, r *http.Request, reqID string) {
+	httputil.WriteJSONError(w, r, http.StatusServiceUnavailable, modelerrors.ErrInternal,
+		"connectors are not configured on this server (no credential vault key is set)", reqID)
+}
+
+// parseConnectorID reads the connector_id path param, answering 400 itself.
+func parseConnectorID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("connector_id", chi.URLParam(r, "connector_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// @Summary  List ERP connectors
+// @Tags     connectors,internal
+// @ID       connectors.list
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []connector.Connector"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/connectors [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	list, err := h.storage.ListConnectors(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Create an ERP connector
+// @Description Credentials are kind-specific and write-only: they are encrypted at rest and never returned. For `netsuite`, config is `{"account_id", "record_type"?, "timezone"?}` and credentials are token-based-authentication keys `{"consumer_key", "consumer_secret", "token_id", "token_secret"}`. An omitted field_mapping takes the kind's default; the mapping must pull external_key, which pairs remote records with existing assets on the first sync. conflict_policy (default remote_wins) settles an asset and its record both changing between syncs. The first sync runs immediately.
+// @Tags     connectors,internal
+// @ID       connectors.create
+// @Accept   json
+// @Produce  json
+// @Param    request body connector.CreateConnectorRequest true "Connector"
+// @Success  201 {object} map[string]any "data: connector.Connector"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "A connector with this name already exists"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Failure  503 {object} modelerrors.ErrorResponse "Connector vault not configured"
+// @Security SessionAuth
+// @Router   /api/v1/connectors [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	var req connector.CreateConnectorRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if h.vault == nil {
+		h.respondVaultUnavailable(w, r, reqID)
+		return
+	}
+
+	c := connector.Connector{
+		OrgID:               orgID,
+		Kind:                req.Kind,
+		Name:                req.Name,
+		Config:              req.Config,
+		FieldMapping:        req.FieldMapping,
+		Direction:           req.Direction,
+		ConflictPolicy:      req.ConflictPolicy,
+		SyncIntervalSeconds: req.SyncIntervalSeconds,
+	}
+	if len(c.FieldMapping) == 0 {
+		c.FieldMapping = connectors.DefaultMapping(req.Kind)
+	}
+	if c.Direction == "" {
+		c.Direction = connector.DirectionBoth
+	}
+	if c.ConflictPolicy == "" {
+		c.ConflictPolicy = connector.ConflictRemoteWins
+	}
+	if c.SyncIntervalSeconds == 0 {
+		c.SyncIntervalSeconds = 3600
+	}
+	if err := connectors.ValidateMapping(c.FieldMapping); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation, err.Error(), reqID)
+		return
+	}
+	if _, err := connectors.New(req.Kind, req.Config, req.Credentials); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation, err.Error(), reqID)
+		return
+	}
+	c.Credentials, err = h.vault.Seal(orgID, req.Credentials)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to encrypt connector credentials", reqID)
+		return
+	}
+	if claims := middleware.GetUserClaims(r); claims != nil {
+		c.CreatedBy = &claims.UserID
+	}
+
+	created, err := h.storage.CreateConnector(r.Context(), c)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/connectors/"+strconv.Itoa(created.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": created})
+}
+
+// @Summary  Get an ERP connector
+// @Tags     connectors,internal
+// @ID       connectors.get
+// @Produce  json
+// @Param    connector_id path int true "Connector id" minimum(1) format(int64)
+// @Success  200 {object} map[string]any "data: connector.Connector"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/connectors/{connector_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseConnectorID(w, r, reqID)
+	if !ok {
+		return
+	}
+	c, err := h.storage.GetConnector(r.Context(), orgID, id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if c == nil {
+		httputil.Respond404(w, r, "connector not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": c})
+}
+
+// @Summary  Update an ERP connector
+// @Description Omitted fields are unchanged. credentials, when present, replaces the stored credentials wholesale. Changing config makes the next sync re-read every remote record.
+// @Tags     connectors,internal
+// @ID       connectors.update
+// @Accept   json
+// @Produce  json
+// @Param    connector_id path int true "Connector id" minimum(1) format(int64)
+// @Param    request body connector.UpdateConnectorRequest true "Fields to change"
+// @Success  200 {object} map[string]any "data: connector.Connector"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "A connector with this name already exists"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Failure  503 {object} modelerrors.ErrorResponse "Connector vault not configured"
+// @Security SessionAuth
+// @Router   /api/v1/connectors/{connector_id} [patch]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseConnectorID(w, r, reqID)
+	if !ok {
+		return
+	}
+	var req connector.UpdateConnectorRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if req.FieldMapping != nil {
+		if err := connectors.ValidateMapping(*req.FieldMapping); err != nil {
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation, err.Error(), reqID)
+			return
+		}
+	}
+
+	var sealed []byte
+	if req.Config != nil || req.Credentials != nil {
+		if h.vault == nil {
+			h.respondVaultUnavailable(w, r, reqID)
+			return
+		}
+		current, err := h.storage.GetConnector(r.Context(), orgID, id)
+		if err != nil {
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+			return
+		}
+		if current == nil {
+			httputil.Respond404(w, r, "connector not found", reqID)
+			return
+		}
+		config := current.Config
+		if req.Config != nil {
+			config = *req.Config
+		}
+		var creds json.RawMessage
+		if req.Credentials != nil {
+			creds = *req.Credentials
+		} else if creds, err = h.vault.Open(orgID, current.Credentials); err != nil {
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+				"stored credentials cannot be decrypted; supply credentials with this change", reqID)
+			return
+		}
+		if _, err := connectors.New(current.Kind, config, creds); err != nil {
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation, err.Error(), reqID)
+			return
+		}
+		if req.Credentials != nil {
+			if sealed, err = h.vault.Seal(orgID, creds); err != nil {
+				httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+					"Failed to encrypt connector credentials", reqID)
+				return
+			}
+		}
+	}
+
+	updated, err := h.storage.UpdateConnector(r.Context(), orgID, id, req, sealed)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if updated == nil {
+		httputil.Respond404(w, r, "connector not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": updated})
+}
+
+// @Summary  Delete an ERP connector
+// @Description Stops its syncs. Assets it created or updated are kept.
+// @Tags     connectors,internal
+// @ID       connectors.delete
+// @Param    connector_id path int true "Connector id" minimum(1) format(int64)
+// @Success  204 "deleted"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/connectors/{connector_id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseConnectorID(w, r, reqID)
+	if !ok {
+		return
+	}
+	deleted, err := h.storage.DeleteConnector(r.Context(), orgID, id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "connector not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary  Sync an ERP connector now
+// @Description Makes the connector due immediately; the sync job picks it up within seconds. Poll GET .../runs for the outcome.
+// @Tags     connectors,internal
+// @ID       connectors.sync
+// @Param    connector_id path int true "Connector id" minimum(1) format(int64)
+// @Success  202 "sync queued"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Connector not found or inactive"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/connectors/{connector_id}/sync [post]
+func (h *Handler) Sync(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseConnectorID(w, r, reqID)
+	if !ok {
+		return
+	}
+	queued, err := h.storage.RequestConnectorSync(r.Context(), orgID, id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if !queued {
+		httputil.Respond404(w, r, "active connector not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary  List an ERP connector's recent sync runs
+// @Tags     connectors,internal
+// @ID       connectors.runs
+// @Produce  json
+// @Param    connector_id path int true "Connector id" minimum(1) format(int64)
+// @Success  200 {object} map[string]any "data: []connector.SyncRun (newest 50)"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/connectors/{connector_id}/runs [get]
+func (h *Handler) ListRuns(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseConnectorID(w, r, reqID)
+	if !ok {
+		return
+	}
+	c, err := h.storage.GetConnector(r.Context(), orgID, id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if c == nil {
+		httputil.Respond404(w, r, "connector not found", reqID)
+		return
+	}
+	runs, err := h.storage.ListConnectorSyncRuns(r.Context(), orgID, id, recentRuns)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": runs})
+}
@@ -0,0 +1,188 @@
+package connectors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/connectors"
+	"github.com/trakrf/platform/backend/internal/models/connector"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockConnectorStorage struct {
+	current   *connector.Connector
+	createErr error
+	queued    bool
+
+	created      *connector.Connector
+	updateCalled bool
+	sealed       []byte
+}
+
+func (m *mockConnectorStorage) ListConnectors(ctx context.Context, orgID int) ([]connector.Connector, error) {
+	return []connector.Connector{}, nil
+}
+
+func (m *mockConnectorStorage) GetConnector(ctx context.Context, orgID, id int) (*connector.Connector, error) {
+	return m.current, nil
+}
+
+func (m *mockConnectorStorage) CreateConnector(ctx context.Context, c connector.Connector) (*connector.Connector, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	c.ID = 12
+	m.created = &c
+	return &c, nil
+}
+
+func (m *mockConnectorStorage) UpdateConnector(ctx context.Context, orgID, id int, req connector.UpdateConnectorRequest, sealedCredentials []byte) (*connector.Connector, error) {
+	m.updateCalled = true
+	m.sealed = sealedCredentials
+	return m.current, nil
+}
+
+func (m *mockConnectorStorage) DeleteConnector(ctx context.Context, orgID, id int) (bool, error) {
+	return false, nil
+}
+
+func (m *mockConnectorStorage) RequestConnectorSync(ctx context.Context, orgID, id int) (bool, error) {
+	return m.queued, nil
+}
+
+func (m *mockConnectorStorage) ListConnectorSyncRuns(ctx context.Context, orgID, connectorID, limit int) ([]connector.SyncRun, error) {
+	return []connector.SyncRun{}, nil
+}
+
+func testVault(t *testing.T) *connectors.Vault {
+	t.Helper()
+	v, err := connectors.NewVault(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatalf("vault: %v", err)
+	}
+	return v
+}
+
+// serve routes through chi without the role middleware so handler logic is
+// exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Post("/api/v1/connectors", h.Create)
+		r.Patch("/api/v1/connectors/{connector_id}", h.Update)
+		r.Post("/api/v1/connectors/{connector_id}/sync", h.Sync)
+	}, req)
+}
+
+const createBody = `{"kind":"netsuite","name":"NetSuite prod","config":{"account_id":"1234567"},
+	"credentials":{"consumer_key":"ck","consumer_secret":"cs","token_id":"tk","token_secret":"ts"}}`
+
+func TestCreate_SealsCredentialsAndDefaults(t *testing.T) {
+	mock := &mockConnectorStorage{}
+	vault := testVault(t)
+	w := serve(NewHandler(mock, vault), testutil.NewRequest(t, http.MethodPost, "/api/v1/connectors", createBody))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Location"); got != "/api/v1/connectors/12" {
+		t.Errorf("Location = %q", got)
+	}
+	c := mock.created
+	if bytes.Contains(c.Credentials, []byte("ts")) {
+		t.Error("credentials stored in plaintext")
+	}
+	plain, err := vault.Open(42, c.Credentials)
+	if err != nil || !bytes.Contains(plain, []byte(`"token_secret":"ts"`)) {
+		t.Errorf("sealed credentials do not open: %s, %v", plain, err)
+	}
+	if c.Direction != connector.DirectionBoth || c.ConflictPolicy != connector.ConflictRemoteWins ||
+		c.SyncIntervalSeconds != 3600 || len(c.FieldMapping) == 0 || c.CreatedBy == nil {
+		t.Errorf("defaults not applied: %+v", c)
+	}
+	if strings.Contains(w.Body.String(), "token_secret") {
+		t.Error("response leaks credentials")
+	}
+}
+
+func TestCreate_InvalidCredentials400(t *testing.T) {
+	mock := &mockConnectorStorage{}
+	body := `{"kind":"netsuite","name":"x","config":{"account_id":"1"},"credentials":{"consumer_key":"ck"}}`
+	w := serve(NewHandler(mock, testVault(t)), testutil.NewRequest(t, http.MethodPost, "/api/v1/connectors", body))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if mock.created != nil {
+		t.Error("storage should not be called")
+	}
+}
+
+func TestCreate_MappingWithoutExternalKey400(t *testing.T) {
+	var body map[string]any
+	if err := json.Unmarshal([]byte(createBody), &body); err != nil {
+		t.Fatal(err)
+	}
+	body["field_mapping"] = []map[string]string{{"local": "name", "remote": "altname"}}
+	b, _ := json.Marshal(body)
+	w := serve(NewHandler(&mockConnectorStorage{}, testVault(t)), testutil.NewRequest(t, http.MethodPost, "/api/v1/connectors", string(b)))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreate_NoVault503(t *testing.T) {
+	w := serve(NewHandler(&mockConnectorStorage{}, nil), testutil.NewRequest(t, http.MethodPost, "/api/v1/connectors", createBody))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreate_Duplicate409(t *testing.T) {
+	mock := &mockConnectorStorage{createErr: errors.New("connector with name NetSuite prod already exists")}
+	w := serve(NewHandler(mock, testVault(t)), testutil.NewRequest(t, http.MethodPost, "/api/v1/connectors", createBody))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdate_ConfigRevalidatedAgainstStoredCredentials(t *testing.T) {
+	vault := testVault(t)
+	sealed, err := vault.Seal(42, []byte(`{"consumer_key":"ck","consumer_secret":"cs","token_id":"tk","token_secret":"ts"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mock := &mockConnectorStorage{current: &connector.Connector{ID: 12, OrgID: 42, Kind: connector.KindNetSuite,
+		Config: json.RawMessage(`{"account_id":"1"}`), Credentials: sealed}}
+
+	w := serve(NewHandler(mock, vault), testutil.NewRequest(t, http.MethodPatch, "/api/v1/connectors/12", `{"config":{"record_type":"Bad Type"}}`))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if mock.updateCalled {
+		t.Error("storage should not be called")
+	}
+
+	w = serve(NewHandler(mock, vault), testutil.NewRequest(t, http.MethodPatch, "/api/v1/connectors/12", `{"config":{"account_id":"2"}}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if mock.sealed != nil {
+		t.Error("credentials should be left as stored")
+	}
+}
+
+func TestSync_InactiveOrMissing404(t *testing.T) {
+	w := serve(NewHandler(&mockConnectorStorage{}, nil), testutil.NewRequest(t, http.MethodPost, "/api/v1/connectors/12/sync", ""))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	w = serve(NewHandler(&mockConnectorStorage{queued: true}, nil), testutil.NewRequest(t, http.MethodPost, "/api/v1/connectors/12/sync", ""))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
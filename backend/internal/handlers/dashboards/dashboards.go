@@ -0,0 +1,568 @@
+// Package dashboards serves custom dashboards: named grids of widgets whose
+// saved queries the server evaluates against a fixed catalog of sources. A
+// dashboard is private to its owner until shared with the org, and an org
+// admin can make one shared dashboard the org's default.
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/dashboard"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// DashboardStorage is the storage surface the handler needs (mockable).
+type DashboardStorage interface {
+	CreateDashboard(ctx context.Context, orgID, userID int, req dashboard.CreateRequest) (*dashboard.Dashboard, error)
+	ListDashboards(ctx context.Context, orgID, userID int) ([]dashboard.Dashboard, error)
+	GetDashboard(ctx context.Context, orgID, userID, id int) (*dashboard.Dashboard, error)
+	GetDefaultDashboard(ctx context.Context, orgID int) (*dashboard.Dashboard, error)
+	UpdateDashboard(ctx context.Context, orgID, id int, req dashboard.UpdateRequest) (*dashboard.Dashboard, error)
+	DeleteDashboard(ctx context.Context, orgID, id int) (bool, error)
+	DashboardMetrics(ctx context.Context, orgID int, queries []dashboard.Query, now time.Time, refresh bool) ([]dashboard.Result, error)
+}
+
+type Handler struct {
+	storage DashboardStorage
+}
+
+func NewHandler(storage DashboardStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the dashboard routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Any member can build dashboards;
+// who may change a given one is checked per dashboard.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+
+	r.With(member).Get("/api/v1/dashboards", h.List)
+	r.With(member).Post("/api/v1/dashboards", h.Create)
+	r.With(member).Get("/api/v1/dashboards/sources", h.Sources)
+	r.With(member).Post("/api/v1/dashboards/query", h.Query)
+	r.With(member).Get("/api/v1/dashboards/default", h.GetDefault)
+	r.With(member).Get("/api/v1/dashboards/default/data", h.DefaultData)
+	r.With(member).Get("/api/v1/dashboards/{dashboard_id}", h.Get)
+	r.With(member).Patch("/api/v1/dashboards/{dashboard_id}", h.Update)
+	r.With(member).Delete("/api/v1/dashboards/{dashboard_id}", h.Delete)
+	r.With(member).Get("/api/v1/dashboards/{dashboard_id}/data", h.Data)
+}
+
+// caller returns the request's org and session user, answering the error
+// itself when either is missing.
+func caller(w http.ResponseWriter, r *http.Request, reqID string) (orgID, userID int, ok bool) {
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return 0, 0, false
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return 0, 0, false
+	}
+	return orgID, claims.UserID, true
+}
+
+// parseID reads the dashboard_id path param, answering 400 itself.
+func parseID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("dashboard_id", chi.URLParam(r, "dashboard_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// isAdmin reports whether the caller is an org admin (role resolved by the
+// route middleware).
+func isAdmin(r *http.Request) bool {
+	role, ok := middleware.GetOrgRole(r.Context())
+	return ok && role == models.RoleAdmin
+}
+
+// canEdit reports whether userID may change d: its owner can, and so can an
+// org admin once it is shared.
+func canEdit(r *http.Request, d *dashboard.Dashboard, userID int) bool {
+	if d.OwnerUserID != nil && *d.OwnerUserID == userID {
+		return true
+	}
+	return d.Shared && isAdmin(r)
+}
+
+// respondCheckError answers a dashboard.FieldError as a 400 on its field.
+func respondCheckError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	var fe *dashboard.FieldError
+	if errors.As(err, &fe) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: fe.Field, Code: "invalid_value", Message: fe.Message,
+		}})
+		return
+	}
+	httputil.RespondStorageError(w, r, err, reqID)
+}
+
+// lookup loads a dashboard the caller can see, answering 404 itself.
+func (h *Handler) lookup(w http.ResponseWriter, r *http.Request, reqID string, orgID, userID int) (*dashboard.Dashboard, bool) {
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return nil, false
+	}
+	d, err := h.storage.GetDashboard(r.Context(), orgID, userID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return nil, false
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "dashboard not found", reqID)
+		return nil, false
+	}
+	return d, true
+}
+
+// defaultDashboard is the org's default dashboard, or dashboard.Builtin
+// when it has none.
+func (h *Handler) defaultDashboard(ctx context.Context, orgID int) (*dashboard.Dashboard, error) {
+	d, err := h.storage.GetDefaultDashboard(ctx, orgID)
+	if err != nil || d != nil {
+		return d, err
+	}
+	b := dashboard.Builtin()
+	return &b, nil
+}
+
+// parseRefresh reads the refresh query param, answering 400 itself.
+func parseRefresh(w http.ResponseWriter, r *http.Request, reqID string) (refresh, ok bool) {
+	v := r.URL.Query().Get("refresh")
+	if v == "" {
+		return false, true
+	}
+	refresh, err := strconv.ParseBool(v)
+	if err != nil {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "refresh", Code: "invalid_value", Message: "refresh must be true or false",
+		}})
+		return false, false
+	}
+	return refresh, true
+}
+
+// evaluate answers every widget query of d from the metric cache. A widget
+// that no longer fits the source catalog gets an error instead of failing
+// the whole dashboard.
+func (h *Handler) evaluate(ctx context.Context, orgID int, d *dashboard.Dashboard, refresh bool) ([]dashboard.WidgetResult, error) {
+	out := make([]dashboard.WidgetResult, len(d.Widgets))
+	queries := []dashboard.Query{}
+	idx := []int{}
+	for i, wgt := range d.Widgets {
+		out[i].WidgetID = wgt.ID
+		if err := wgt.Query.Check(""); err != nil {
+			out[i].Error = err.Error()
+			continue
+		}
+		queries = append(queries, wgt.Query)
+		idx = append(idx, i)
+	}
+	if len(queries) == 0 {
+		return out, nil
+	}
+	results, err := h.storage.DashboardMetrics(ctx, orgID, queries, time.Now(), refresh)
+	if err != nil {
+		return nil, err
+	}
+	for j, i := range idx {
+		out[i].Result = &results[j]
+	}
+	return out, nil
+}
+
+// @Summary  List dashboards
+// @Description The caller's own dashboards and the org's shared ones, the org default first and the rest by name.
+// @Tags     dashboards,internal
+// @ID       dashboards.list
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []dashboard.Dashboard"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	list, err := h.storage.ListDashboards(r.Context(), orgID, userID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Create a dashboard
+// @Description Saves a dashboard owned by the caller, private unless `shared`. Each widget is a query over one of the sources from GET /api/v1/dashboards/sources, a visualization (`number` for an ungrouped query, `bar`, `line`, `pie` or `table` for a grouped one) and a place on the 12-column grid. `is_default` makes it the org's default, replacing the previous one; it implies `shared` and is for org admins only.
+// @Tags     dashboards,internal
+// @ID       dashboards.create
+// @Accept   json
+// @Produce  json
+// @Param    request body dashboard.CreateRequest true "Dashboard"
+// @Success  201 {object} map[string]any "data: dashboard.Dashboard"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse "is_default from a non-admin"
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req dashboard.CreateRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if err := dashboard.CheckWidgets(req.Widgets); err != nil {
+		respondCheckError(w, r, err, reqID)
+		return
+	}
+	if req.IsDefault && !isAdmin(r) {
+		httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden,
+			"only an org admin can set the default dashboard", reqID)
+		return
+	}
+
+	d, err := h.storage.CreateDashboard(r.Context(), orgID, userID, req)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": d})
+}
+
+// @Summary  List dashboard query sources
+// @Description The sources a widget query can count, each with the groups it can be broken down by, whether `range_days` windows it and whether `location_id` filters it.
+// @Tags     dashboards,internal
+// @ID       dashboards.sources
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []dashboard.Source"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards/sources [get]
+func (h *Handler) Sources(w http.ResponseWriter, r *http.Request) {
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": dashboard.Sources})
+}
+
+// @Summary  Evaluate a dashboard query
+// @Description Evaluates one widget query without saving it, to preview a widget while it is edited. An ungrouped query answers `value`; a grouped one answers `series`, largest group first, or by day for a `day` group, which covers every day of the window. Results come from the org's metric cache, as for a dashboard's data.
+// @Tags     dashboards,internal
+// @ID       dashboards.query
+// @Accept   json
+// @Produce  json
+// @Param    request body  dashboard.Query true  "Query"
+// @Param    refresh query bool            false "Recompute the result unless it is under 30 seconds old"
+// @Success  200 {object} map[string]any "data: dashboard.Result"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards/query [post]
+func (h *Handler) Query(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var q dashboard.Query
+	if err := httputil.DecodeJSONStrict(r, &q); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(q); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if err := q.Check(""); err != nil {
+		respondCheckError(w, r, err, reqID)
+		return
+	}
+
+	refresh, ok := parseRefresh(w, r, reqID)
+	if !ok {
+		return
+	}
+	results, err := h.storage.DashboardMetrics(r.Context(), orgID, []dashboard.Query{q}, time.Now(), refresh)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": results[0]})
+}
+
+// @Summary  Get the default dashboard
+// @Description The org's default dashboard or, when no admin has chosen one, the built-in overview (`id` 0), so the console always has a dashboard to open.
+// @Tags     dashboards,internal
+// @ID       dashboards.default
+// @Produce  json
+// @Success  200 {object} map[string]any "data: dashboard.Dashboard"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards/default [get]
+func (h *Handler) GetDefault(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, err := h.defaultDashboard(r.Context(), orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary  Evaluate the default dashboard
+// @Description Evaluates every widget of the dashboard GET /api/v1/dashboards/default answers, from the metric cache as for any dashboard's data.
+// @Tags     dashboards,internal
+// @ID       dashboards.default.data
+// @Produce  json
+// @Param    refresh query bool false "Recompute results unless they are under 30 seconds old"
+// @Success  200 {object} map[string]any "data: []dashboard.WidgetResult"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards/default/data [get]
+func (h *Handler) DefaultData(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	refresh, ok := parseRefresh(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, err := h.defaultDashboard(r.Context(), orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	results, err := h.evaluate(r.Context(), orgID, d, refresh)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": results})
+}
+
+// @Summary  Get a dashboard
+// @Tags     dashboards,internal
+// @ID       dashboards.get
+// @Produce  json
+// @Param    dashboard_id path int true "Dashboard id"
+// @Success  200 {object} map[string]any "data: dashboard.Dashboard"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "not found, or another member's private dashboard"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards/{dashboard_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, ok := h.lookup(w, r, reqID, orgID, userID)
+	if !ok {
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary  Evaluate a dashboard
+// @Description Evaluates every widget query of the dashboard, in widget order. A widget saved against a source or group that no longer exists answers an `error` rather than a `result`. Results come from the org's metric cache, which a background job recomputes every 5 minutes for queries read in the last day, so a page load does not recount a large org. Each result carries `computed_at` and `stale`, set once it is over 5 minutes old; a result over 30 minutes old is recomputed before answering. `refresh=true` recomputes every result not computed in the last 30 seconds.
+// @Tags     dashboards,internal
+// @ID       dashboards.data
+// @Produce  json
+// @Param    dashboard_id path  int  true  "Dashboard id"
+// @Param    refresh      query bool false "Recompute results unless they are under 30 seconds old"
+// @Success  200 {object} map[string]any "data: []dashboard.WidgetResult"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards/{dashboard_id}/data [get]
+func (h *Handler) Data(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	refresh, ok := parseRefresh(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, ok := h.lookup(w, r, reqID, orgID, userID)
+	if !ok {
+		return
+	}
+	results, err := h.evaluate(r.Context(), orgID, d, refresh)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": results})
+}
+
+// @Summary  Update a dashboard
+// @Description Omitted fields are left unchanged; `widgets` replaces the whole layout. The owner can change a dashboard, and so can an org admin once it is shared. Only an org admin can make a dashboard the default or stop it being the default, which unsharing the default also does.
+// @Tags     dashboards,internal
+// @ID       dashboards.update
+// @Accept   json
+// @Produce  json
+// @Param    dashboard_id path int true "Dashboard id"
+// @Param    request body dashboard.UpdateRequest true "Fields to update"
+// @Success  200 {object} map[string]any "data: dashboard.Dashboard"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards/{dashboard_id} [patch]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req dashboard.UpdateRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if req.Widgets != nil {
+		if err := dashboard.CheckWidgets(*req.Widgets); err != nil {
+			respondCheckError(w, r, err, reqID)
+			return
+		}
+	}
+	if req.IsDefault != nil && *req.IsDefault && req.Shared != nil && !*req.Shared {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "shared", Code: "invalid_value", Message: "the default dashboard must be shared",
+		}})
+		return
+	}
+
+	d, ok := h.lookup(w, r, reqID, orgID, userID)
+	if !ok {
+		return
+	}
+	if !canEdit(r, d, userID) {
+		httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden,
+			"only the owner or an org admin can change this dashboard", reqID)
+		return
+	}
+	defaultChange := req.IsDefault != nil && *req.IsDefault != d.IsDefault
+	unshareDefault := d.IsDefault && req.Shared != nil && !*req.Shared
+	if (defaultChange || unshareDefault) && !isAdmin(r) {
+		httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden,
+			"only an org admin can change the default dashboard", reqID)
+		return
+	}
+
+	d, err := h.storage.UpdateDashboard(r.Context(), orgID, d.ID, req)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "dashboard not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary  Delete a dashboard
+// @Description The owner can delete a dashboard, and so can an org admin once it is shared. Deleting the default leaves the org on the built-in overview.
+// @Tags     dashboards,internal
+// @ID       dashboards.delete
+// @Param    dashboard_id path int true "Dashboard id"
+// @Success  204 "dashboard deleted"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dashboards/{dashboard_id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, ok := h.lookup(w, r, reqID, orgID, userID)
+	if !ok {
+		return
+	}
+	if !canEdit(r, d, userID) {
+		httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden,
+			"only the owner or an org admin can delete this dashboard", reqID)
+		return
+	}
+	found, err := h.storage.DeleteDashboard(r.Context(), orgID, d.ID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "dashboard not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,252 @@
+package dashboards
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/dashboard"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+// The caller is user 1. Dashboard 9 is theirs, 10 is user 2's and shared,
+// 11 is user 2's and the org default; any other id is not visible.
+type mockDashboardStorage struct {
+	created   *dashboard.CreateRequest
+	updated   *dashboard.UpdateRequest
+	deleted   int
+	evalled   []dashboard.Query
+	refresh   bool
+	noDefault bool
+}
+
+func owned(id, owner int) *dashboard.Dashboard {
+	return &dashboard.Dashboard{ID: id, OwnerUserID: &owner, Widgets: []dashboard.Widget{}}
+}
+
+func (m *mockDashboardStorage) CreateDashboard(ctx context.Context, orgID, userID int, req dashboard.CreateRequest) (*dashboard.Dashboard, error) {
+	m.created = &req
+	return owned(12, userID), nil
+}
+
+func (m *mockDashboardStorage) ListDashboards(ctx context.Context, orgID, userID int) ([]dashboard.Dashboard, error) {
+	return []dashboard.Dashboard{*owned(9, 1)}, nil
+}
+
+func (m *mockDashboardStorage) GetDashboard(ctx context.Context, orgID, userID, id int) (*dashboard.Dashboard, error) {
+	switch id {
+	case 9:
+		d := owned(9, 1)
+		d.Widgets = []dashboard.Widget{
+			{ID: "assets", Visualization: dashboard.VisualizationNumber, Query: dashboard.Query{Source: dashboard.SourceAssets}},
+			{ID: "gone", Visualization: dashboard.VisualizationBar, Query: dashboard.Query{Source: "invoices", GroupBy: "month"}},
+		}
+		return d, nil
+	case 10:
+		d := owned(10, 2)
+		d.Shared = true
+		return d, nil
+	case 11:
+		d := owned(11, 2)
+		d.Shared, d.IsDefault = true, true
+		return d, nil
+	}
+	return nil, nil
+}
+
+func (m *mockDashboardStorage) GetDefaultDashboard(ctx context.Context, orgID int) (*dashboard.Dashboard, error) {
+	if m.noDefault {
+		return nil, nil
+	}
+	return m.GetDashboard(ctx, orgID, 1, 11)
+}
+
+func (m *mockDashboardStorage) UpdateDashboard(ctx context.Context, orgID, id int, req dashboard.UpdateRequest) (*dashboard.Dashboard, error) {
+	m.updated = &req
+	return owned(id, 1), nil
+}
+
+func (m *mockDashboardStorage) DeleteDashboard(ctx context.Context, orgID, id int) (bool, error) {
+	m.deleted = id
+	return true, nil
+}
+
+func (m *mockDashboardStorage) DashboardMetrics(ctx context.Context, orgID int, queries []dashboard.Query, now time.Time, refresh bool) ([]dashboard.Result, error) {
+	m.evalled, m.refresh = queries, refresh
+	out := make([]dashboard.Result, len(queries))
+	for i := range out {
+		n := int64(i + 5)
+		out[i].Value = &n
+	}
+	return out, nil
+}
+
+// roles answers every role lookup with role.
+type roles struct{ role models.OrgRole }
+
+func (s roles) GetUserOrgRole(ctx context.Context, userID, orgID int) (models.OrgRole, error) {
+	return s.role, nil
+}
+
+func (s roles) IsUserSuperadmin(ctx context.Context, userID int) (bool, error) { return false, nil }
+
+// serve routes through the real routes, so the handler sees the caller's
+// org role as role.
+func serve(h *Handler, role models.OrgRole, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		h.RegisterRoutes(r, roles{role})
+	}, req)
+}
+
+const numberWidget = `{"id":"a","title":"Assets","visualization":"number","query":{"source":"assets"},"layout":{"x":0,"y":0,"w":3,"h":2}}`
+
+func TestCreate(t *testing.T) {
+	cases := []struct {
+		name  string
+		role  models.OrgRole
+		body  string
+		want  int
+		field string
+	}{
+		{"private", models.RoleViewer, `{"name":"Mine","widgets":[` + numberWidget + `]}`, http.StatusCreated, ""},
+		{"no name", models.RoleViewer, `{"widgets":[]}`, http.StatusBadRequest, "name"},
+		{"unknown visualization", models.RoleViewer, `{"name":"x","widgets":[{"id":"a","title":"A","visualization":"gauge","query":{"source":"assets"},"layout":{"w":3,"h":2}}]}`, http.StatusBadRequest, "visualization"},
+		{"group the source lacks", models.RoleViewer, `{"name":"x","widgets":[{"id":"a","title":"A","visualization":"bar","query":{"source":"assets","group_by":"day"},"layout":{"w":3,"h":2}}]}`, http.StatusBadRequest, "widgets[0].query.group_by"},
+		{"default from a member", models.RoleOperator, `{"name":"Ops","is_default":true}`, http.StatusForbidden, ""},
+		{"default from an admin", models.RoleAdmin, `{"name":"Ops","is_default":true}`, http.StatusCreated, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockDashboardStorage{}
+			w := serve(NewHandler(m), c.role, testutil.NewRequest(t, http.MethodPost, "/api/v1/dashboards", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.field != "" && !strings.Contains(w.Body.String(), `"field":"`+c.field+`"`) {
+				t.Errorf("want field %s, body = %s", c.field, w.Body.String())
+			}
+			if (m.created != nil) != (c.want == http.StatusCreated) {
+				t.Errorf("created = %+v", m.created)
+			}
+		})
+	}
+}
+
+func TestUpdate_Permissions(t *testing.T) {
+	cases := []struct {
+		name string
+		role models.OrgRole
+		id   string
+		body string
+		want int
+	}{
+		{"owner", models.RoleViewer, "9", `{"name":"Renamed"}`, http.StatusOK},
+		{"owner sets the default", models.RoleViewer, "9", `{"is_default":true}`, http.StatusForbidden},
+		{"admin sets the default", models.RoleAdmin, "9", `{"is_default":true}`, http.StatusOK},
+		{"member on another's shared", models.RoleManager, "10", `{"name":"Mine now"}`, http.StatusForbidden},
+		{"admin on another's shared", models.RoleAdmin, "10", `{"name":"Tidied"}`, http.StatusOK},
+		{"owner unshares the default", models.RoleViewer, "11", `{"shared":false}`, http.StatusForbidden},
+		{"default yet unshared", models.RoleAdmin, "10", `{"is_default":true,"shared":false}`, http.StatusBadRequest},
+		{"not visible", models.RoleAdmin, "8", `{"name":"x"}`, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockDashboardStorage{}
+			w := serve(NewHandler(m), c.role, testutil.NewRequest(t, http.MethodPatch, "/api/v1/dashboards/"+c.id, c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if (m.updated != nil) != (c.want == http.StatusOK) {
+				t.Errorf("updated = %+v", m.updated)
+			}
+		})
+	}
+}
+
+func TestDelete_Permissions(t *testing.T) {
+	m := &mockDashboardStorage{}
+	if w := serve(NewHandler(m), models.RoleOperator, testutil.NewRequest(t, http.MethodDelete, "/api/v1/dashboards/10", "")); w.Code != http.StatusForbidden {
+		t.Errorf("member on another's: %d", w.Code)
+	}
+	if w := serve(NewHandler(m), models.RoleViewer, testutil.NewRequest(t, http.MethodDelete, "/api/v1/dashboards/9", "")); w.Code != http.StatusNoContent || m.deleted != 9 {
+		t.Errorf("owner: %d, deleted %d", w.Code, m.deleted)
+	}
+	if w := serve(NewHandler(m), models.RoleAdmin, testutil.NewRequest(t, http.MethodDelete, "/api/v1/dashboards/8", "")); w.Code != http.StatusNotFound {
+		t.Errorf("not visible: %d", w.Code)
+	}
+}
+
+func TestData_EvaluatesEachWidget(t *testing.T) {
+	m := &mockDashboardStorage{}
+	w := serve(NewHandler(m), models.RoleViewer, testutil.NewRequest(t, http.MethodGet, "/api/v1/dashboards/9/data", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Data []dashboard.WidgetResult `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.evalled) != 1 || m.evalled[0].Source != dashboard.SourceAssets || m.refresh {
+		t.Errorf("evaluated %+v (refresh %v), want only the valid widget from the cache", m.evalled, m.refresh)
+	}
+	if len(body.Data) != 2 {
+		t.Fatalf("data = %+v", body.Data)
+	}
+	if r := body.Data[0]; r.WidgetID != "assets" || r.Result == nil || r.Result.Value == nil || *r.Result.Value != 5 {
+		t.Errorf("assets = %+v", r)
+	}
+	if r := body.Data[1]; r.WidgetID != "gone" || r.Result != nil || r.Error == "" {
+		t.Errorf("gone = %+v", r)
+	}
+}
+
+func TestDefault_FallsBackToBuiltin(t *testing.T) {
+	m := &mockDashboardStorage{}
+	w := serve(NewHandler(m), models.RoleViewer, testutil.NewRequest(t, http.MethodGet, "/api/v1/dashboards/default", ""))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"id":11`) {
+		t.Errorf("org default: %d %s", w.Code, w.Body.String())
+	}
+
+	m.noDefault = true
+	w = serve(NewHandler(m), models.RoleViewer, testutil.NewRequest(t, http.MethodGet, "/api/v1/dashboards/default/data", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("builtin data: %d %s", w.Code, w.Body.String())
+	}
+	if len(m.evalled) != len(dashboard.Builtin().Widgets) {
+		t.Errorf("evaluated %d queries, want the builtin's %d", len(m.evalled), len(dashboard.Builtin().Widgets))
+	}
+}
+
+func TestQuery(t *testing.T) {
+	m := &mockDashboardStorage{}
+	w := serve(NewHandler(m), models.RoleViewer, testutil.NewRequest(t, http.MethodPost, "/api/v1/dashboards/query", `{"source":"scans","group_by":"day","range_days":30}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if len(m.evalled) != 1 || m.evalled[0].RangeDays != 30 {
+		t.Errorf("evaluated %+v", m.evalled)
+	}
+	for _, body := range []string{`{"source":"invoices"}`, `{"source":"documents","range_days":7}`, `{"source":"scans","range_days":400}`} {
+		if w := serve(NewHandler(m), models.RoleViewer, testutil.NewRequest(t, http.MethodPost, "/api/v1/dashboards/query", body)); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d", body, w.Code)
+		}
+	}
+}
+
+func TestData_Refresh(t *testing.T) {
+	m := &mockDashboardStorage{}
+	if w := serve(NewHandler(m), models.RoleViewer, testutil.NewRequest(t, http.MethodGet, "/api/v1/dashboards/9/data?refresh=true", "")); w.Code != http.StatusOK || !m.refresh {
+		t.Errorf("refresh: status = %d, refresh = %v", w.Code, m.refresh)
+	}
+	if w := serve(NewHandler(m), models.RoleViewer, testutil.NewRequest(t, http.MethodGet, "/api/v1/dashboards/default/data?refresh=soon", "")); w.Code != http.StatusBadRequest {
+		t.Errorf("bad refresh: status = %d", w.Code)
+	}
+}
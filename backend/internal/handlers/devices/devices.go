@@ -0,0 +1,154 @@
+// Package devices serves the mobile app's push registrations: the signed-in
+// user registers each install's FCM / APNs token so the push notifier can
+// reach them.
+package devices
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/device"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// DeviceStorage is the storage surface the handler needs (mockable).
+type DeviceStorage interface {
+	RegisterMobileDevice(ctx context.Context, userID int, req device.RegisterRequest) (*device.Device, error)
+	ListMobileDevices(ctx context.Context, userID int) ([]device.Device, error)
+	DeleteMobileDevice(ctx context.Context, userID, id int) (bool, error)
+}
+
+type Handler struct {
+	storage DeviceStorage
+}
+
+func NewHandler(storage DeviceStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the device routes onto r. Mount inside the session-auth
+// (middleware.Auth) group: devices belong to the signed-in user, not to an
+// org, so there is no role gate and no API-key access.
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/api/v1/users/me/devices", h.List)
+	r.Post("/api/v1/users/me/devices", h.Register)
+	r.Delete("/api/v1/users/me/devices/{device_id}", h.Delete)
+}
+
+// userID returns the session user, writing 401 when there is none.
+func userID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return 0, false
+	}
+	return claims.UserID, true
+}
+
+// @Summary  Register a mobile device for push notifications
+// @Description Records the app install's push token (FCM registration token on android, APNs device token on ios) for the signed-in user. Call on every launch and whenever the push service rotates the token: registering a known token refreshes it rather than adding a device, and moves it to the caller if another user registered it before. Registered devices receive "asset overdue" notifications for assets the caller owns; for an unowned asset the members of its team are notified, and for an asset with neither the org's managers and admins.
+// @Tags     users,internal
+// @ID       users.devices.register
+// @Accept   json
+// @Produce  json
+// @Param    request body device.RegisterRequest true "Device"
+// @Success  201 {object} map[string]any "data: device.Device"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/users/me/devices [post]
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	uid, ok := userID(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req device.RegisterRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	d, err := h.storage.RegisterMobileDevice(r.Context(), uid, req)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": d})
+}
+
+// @Summary  List the caller's mobile devices
+// @Tags     users,internal
+// @ID       users.devices.list
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []device.Device"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/users/me/devices [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	uid, ok := userID(w, r, reqID)
+	if !ok {
+		return
+	}
+	list, err := h.storage.ListMobileDevices(r.Context(), uid)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Unregister a mobile device
+// @Description Stops push notifications to the device. The app calls it on sign-out.
+// @Tags     users,internal
+// @ID       users.devices.delete
+// @Param    device_id path int true "Device id"
+// @Success  204
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/users/me/devices/{device_id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	uid, ok := userID(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, err := httputil.ParseSurrogateID("device_id", chi.URLParam(r, "device_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	deleted, err := h.storage.DeleteMobileDevice(r.Context(), uid, id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "device not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
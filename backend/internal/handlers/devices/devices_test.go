@@ -0,0 +1,101 @@
+package devices
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/device"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+type mockDeviceStorage struct {
+	registered *device.RegisterRequest
+	deleteUser int
+}
+
+func (m *mockDeviceStorage) RegisterMobileDevice(ctx context.Context, userID int, req device.RegisterRequest) (*device.Device, error) {
+	m.registered = &req
+	return &device.Device{ID: 5, UserID: userID, Platform: req.Platform, PushToken: req.PushToken}, nil
+}
+
+func (m *mockDeviceStorage) ListMobileDevices(ctx context.Context, userID int) ([]device.Device, error) {
+	return []device.Device{}, nil
+}
+
+func (m *mockDeviceStorage) DeleteMobileDevice(ctx context.Context, userID, id int) (bool, error) {
+	m.deleteUser = userID
+	return id == 5, nil
+}
+
+func newRequest(method, target, body string, signedIn bool) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if !signedIn {
+		return req
+	}
+	claims := &jwt.Claims{UserID: 7, Email: "tech@example.com"}
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserClaimsKey, claims))
+}
+
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRegister(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"android", `{"platform":"android","push_token":"fcm-token","device_name":"Zebra TC58","app_version":"2.4.1"}`, http.StatusCreated},
+		{"ios", `{"platform":"ios","push_token":"a1b2c3"}`, http.StatusCreated},
+		{"unknown platform", `{"platform":"windows","push_token":"x"}`, http.StatusBadRequest},
+		{"missing token", `{"platform":"ios"}`, http.StatusBadRequest},
+		{"unknown field", `{"platform":"ios","push_token":"x","org_id":1}`, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockDeviceStorage{}
+			w := serve(NewHandler(m), newRequest(http.MethodPost, "/api/v1/users/me/devices", c.body, true))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.want == http.StatusCreated && (m.registered == nil || !strings.Contains(w.Body.String(), `"id":5`)) {
+				t.Errorf("registered = %+v, body = %s", m.registered, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRegister_RequiresSession(t *testing.T) {
+	w := serve(NewHandler(&mockDeviceStorage{}),
+		newRequest(http.MethodPost, "/api/v1/users/me/devices", `{"platform":"ios","push_token":"x"}`, false))
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDelete(t *testing.T) {
+	m := &mockDeviceStorage{}
+	w := serve(NewHandler(m), newRequest(http.MethodDelete, "/api/v1/users/me/devices/5", "", true))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.deleteUser != 7 {
+		t.Errorf("deleted as user %d", m.deleteUser)
+	}
+
+	w = serve(NewHandler(m), newRequest(http.MethodDelete, "/api/v1/users/me/devices/6", "", true))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,286 @@
+// Package dockdoors serves dock door configuration: org admins pair an outer
+// and an inner scan point across each door so the direction engine can tell
+// inbound from outbound crossings. The crossings themselves are reported by
+// GET /api/v1/reports/dock-movements.
+package dockdoors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/dockdoor"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// DoorStorage is the storage surface the handler needs (mockable).
+type DoorStorage interface {
+	CreateDockDoor(ctx context.Context, orgID int, req dockdoor.CreateDoorRequest) (*dockdoor.Door, error)
+	GetDockDoor(ctx context.Context, orgID, id int) (*dockdoor.Door, error)
+	ListDockDoors(ctx context.Context, orgID, locationID int) ([]dockdoor.Door, error)
+	UpdateDockDoor(ctx context.Context, orgID, id int, req dockdoor.UpdateDoorRequest) (*dockdoor.Door, error)
+	DeleteDockDoor(ctx context.Context, orgID, id int) (bool, error)
+}
+
+type Handler struct {
+	storage DoorStorage
+}
+
+func NewHandler(storage DoorStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the dock door routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Any member can read doors; changing
+// them is admin-only, like the scan points they pair.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(member).Get("/api/v1/dock-doors", h.List)
+	r.With(admin).Post("/api/v1/dock-doors", h.Create)
+	r.With(member).Get("/api/v1/dock-doors/{dock_door_id}", h.Get)
+	r.With(admin).Patch("/api/v1/dock-doors/{dock_door_id}", h.Update)
+	r.With(admin).Delete("/api/v1/dock-doors/{dock_door_id}", h.Delete)
+}
+
+// parseID reads the dock_door_id path param, answering 400 itself.
+func parseID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("dock_door_id", chi.URLParam(r, "dock_door_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// respondDoorError maps the storage sentinels to their field errors.
+func respondDoorError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	var fe modelerrors.FieldError
+	switch {
+	case errors.Is(err, storage.ErrDockDoorLocationNotFound):
+		fe = modelerrors.FieldError{Field: "location_id", Code: "fk_not_found", Message: err.Error()}
+	case errors.Is(err, storage.ErrDockDoorScanPointNotFound):
+		fe = modelerrors.FieldError{Field: "outer_scan_point_id", Code: "fk_not_found", Message: err.Error()}
+	case errors.Is(err, storage.ErrDockDoorScanPointInUse):
+		fe = modelerrors.FieldError{Field: "outer_scan_point_id", Code: "duplicate", Message: err.Error()}
+	case errors.Is(err, storage.ErrDockDoorSameScanPoint):
+		fe = modelerrors.FieldError{Field: "inner_scan_point_id", Code: "invalid_value", Message: err.Error()}
+	default:
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{fe})
+}
+
+// @Summary  List dock doors
+// @Description The org's dock doors by name.
+// @Tags     dock-doors,internal
+// @ID       dock_doors.list
+// @Produce  json
+// @Param    location_id query int false "Only doors at this location" minimum(1) format(int64)
+// @Success  200 {object} map[string]any "data: []dockdoor.Door"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dock-doors [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	locationID := 0
+	if v := r.URL.Query().Get("location_id"); v != "" {
+		id, err := httputil.ParseSurrogateID("location_id", v)
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		locationID = id
+	}
+	list, err := h.storage.ListDockDoors(r.Context(), orgID, locationID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Register a dock door
+// @Description Pairs two of the org's scan points across a dock door at one of its locations: `outer_scan_point_id` is the antenna on the yard or trailer side, `inner_scan_point_id` the one on the building side. A tag heard first outside and then inside is recorded as an inbound movement (receiving), the reverse as outbound (shipping). Reads of one tag no more than `window_seconds` apart (10 by default) belong to one pass through the door, which records at most one movement. A scan point belongs to at most one door.
+// @Tags     dock-doors,internal
+// @ID       dock_doors.create
+// @Accept   json
+// @Produce  json
+// @Param    request body dockdoor.CreateDoorRequest true "Dock door"
+// @Success  201 {object} map[string]any "data: dockdoor.Door"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dock-doors [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	var req dockdoor.CreateDoorRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	d, err := h.storage.CreateDockDoor(r.Context(), orgID, req)
+	if err != nil {
+		respondDoorError(w, r, err, reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/dock-doors/"+strconv.Itoa(d.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": d})
+}
+
+// @Summary  Get a dock door
+// @Tags     dock-doors,internal
+// @ID       dock_doors.get
+// @Produce  json
+// @Param    dock_door_id path int true "Dock door id"
+// @Success  200 {object} map[string]any "data: dockdoor.Door"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dock-doors/{dock_door_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, err := h.storage.GetDockDoor(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "dock door not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary  Update a dock door
+// @Description Omitted fields are left unchanged. Swapping `outer_scan_point_id` and `inner_scan_point_id` reverses which crossings count as inbound. An inactive door records no movements and keeps the ones it has. Changes reach the direction engine within 30 seconds.
+// @Tags     dock-doors,internal
+// @ID       dock_doors.update
+// @Accept   json
+// @Produce  json
+// @Param    dock_door_id path int true "Dock door id"
+// @Param    request body dockdoor.UpdateDoorRequest true "Fields to update"
+// @Success  200 {object} map[string]any "data: dockdoor.Door"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dock-doors/{dock_door_id} [patch]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+	var req dockdoor.UpdateDoorRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	d, err := h.storage.UpdateDockDoor(r.Context(), orgID, id, req)
+	if err != nil {
+		respondDoorError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "dock door not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary  Delete a dock door
+// @Description Deletes the door and its recorded movements. Deactivate it instead (`is_active=false`) to keep them.
+// @Tags     dock-doors,internal
+// @ID       dock_doors.delete
+// @Param    dock_door_id path int true "Dock door id"
+// @Success  204
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/dock-doors/{dock_door_id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+	deleted, err := h.storage.DeleteDockDoor(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "dock door not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
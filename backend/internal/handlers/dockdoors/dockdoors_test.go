@@ -0,0 +1,138 @@
+package dockdoors
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/dockdoor"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockDoorStorage struct {
+	doors      map[int]*dockdoor.Door
+	created    *dockdoor.CreateDoorRequest
+	writeErr   error
+	locationID int
+}
+
+func newMock() *mockDoorStorage {
+	return &mockDoorStorage{doors: map[int]*dockdoor.Door{
+		5: {ID: 5, OuterScanPointID: 1, InnerScanPointID: 2, WindowSeconds: 10, IsActive: true},
+	}}
+}
+
+func (m *mockDoorStorage) CreateDockDoor(ctx context.Context, orgID int, req dockdoor.CreateDoorRequest) (*dockdoor.Door, error) {
+	if m.writeErr != nil {
+		return nil, m.writeErr
+	}
+	m.created = &req
+	return &dockdoor.Door{ID: 9, LocationID: req.LocationID}, nil
+}
+
+func (m *mockDoorStorage) GetDockDoor(ctx context.Context, orgID, id int) (*dockdoor.Door, error) {
+	return m.doors[id], nil
+}
+
+func (m *mockDoorStorage) ListDockDoors(ctx context.Context, orgID, locationID int) ([]dockdoor.Door, error) {
+	m.locationID = locationID
+	return []dockdoor.Door{}, nil
+}
+
+func (m *mockDoorStorage) UpdateDockDoor(ctx context.Context, orgID, id int, req dockdoor.UpdateDoorRequest) (*dockdoor.Door, error) {
+	if m.writeErr != nil {
+		return nil, m.writeErr
+	}
+	return m.doors[id], nil
+}
+
+func (m *mockDoorStorage) DeleteDockDoor(ctx context.Context, orgID, id int) (bool, error) {
+	return m.doors[id] != nil, nil
+}
+
+// serve routes through chi without the role middleware so handler-level
+// checks are exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Get("/api/v1/dock-doors", h.List)
+		r.Post("/api/v1/dock-doors", h.Create)
+		r.Get("/api/v1/dock-doors/{dock_door_id}", h.Get)
+		r.Patch("/api/v1/dock-doors/{dock_door_id}", h.Update)
+		r.Delete("/api/v1/dock-doors/{dock_door_id}", h.Delete)
+	}, req)
+}
+
+func TestCreate(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		writeErr error
+		want     int
+		field    string
+	}{
+		{"ok", `{"location_id":3,"name":"Dock 3","outer_scan_point_id":1,"inner_scan_point_id":2}`, nil, http.StatusCreated, ""},
+		{"window", `{"location_id":3,"name":"Dock 3","outer_scan_point_id":1,"inner_scan_point_id":2,"window_seconds":30}`, nil, http.StatusCreated, ""},
+		{"same scan point", `{"location_id":3,"name":"Dock 3","outer_scan_point_id":1,"inner_scan_point_id":1}`, nil, http.StatusBadRequest, "inner_scan_point_id"},
+		{"missing inner", `{"location_id":3,"name":"Dock 3","outer_scan_point_id":1}`, nil, http.StatusBadRequest, "inner_scan_point_id"},
+		{"window too long", `{"location_id":3,"name":"Dock 3","outer_scan_point_id":1,"inner_scan_point_id":2,"window_seconds":301}`, nil, http.StatusBadRequest, "window_seconds"},
+		{"foreign location", `{"location_id":3,"name":"Dock 3","outer_scan_point_id":1,"inner_scan_point_id":2}`, storage.ErrDockDoorLocationNotFound, http.StatusBadRequest, "location_id"},
+		{"foreign scan point", `{"location_id":3,"name":"Dock 3","outer_scan_point_id":1,"inner_scan_point_id":2}`, storage.ErrDockDoorScanPointNotFound, http.StatusBadRequest, "outer_scan_point_id"},
+		{"scan point in use", `{"location_id":3,"name":"Dock 3","outer_scan_point_id":1,"inner_scan_point_id":2}`, storage.ErrDockDoorScanPointInUse, http.StatusBadRequest, "outer_scan_point_id"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := newMock()
+			m.writeErr = c.writeErr
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/dock-doors", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.field != "" && !strings.Contains(w.Body.String(), `"field":"`+c.field+`"`) {
+				t.Errorf("want field %s, body = %s", c.field, w.Body.String())
+			}
+			if c.want == http.StatusCreated && w.Header().Get("Location") != "/api/v1/dock-doors/9" {
+				t.Errorf("Location = %q", w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestList_LocationFilter(t *testing.T) {
+	m := newMock()
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/dock-doors?location_id=3", "")); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.locationID != 3 {
+		t.Errorf("location_id = %d", m.locationID)
+	}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/dock-doors?location_id=x", "")); w.Code != http.StatusBadRequest {
+		t.Errorf("bad location_id: %d", w.Code)
+	}
+}
+
+func TestGetUpdateDelete(t *testing.T) {
+	if w := serve(NewHandler(newMock()), testutil.NewRequest(t, http.MethodGet, "/api/v1/dock-doors/5", "")); w.Code != http.StatusOK {
+		t.Fatalf("get: status = %d", w.Code)
+	}
+	if w := serve(NewHandler(newMock()), testutil.NewRequest(t, http.MethodGet, "/api/v1/dock-doors/8", "")); w.Code != http.StatusNotFound {
+		t.Fatalf("get missing: status = %d", w.Code)
+	}
+	if w := serve(NewHandler(newMock()), testutil.NewRequest(t, http.MethodPatch, "/api/v1/dock-doors/8", `{"is_active":false}`)); w.Code != http.StatusNotFound {
+		t.Fatalf("update missing: status = %d", w.Code)
+	}
+	m := newMock()
+	m.writeErr = storage.ErrDockDoorSameScanPoint
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPatch, "/api/v1/dock-doors/5", `{"inner_scan_point_id":1}`)); w.Code != http.StatusBadRequest {
+		t.Fatalf("update same scan point: status = %d", w.Code)
+	}
+	if w := serve(NewHandler(newMock()), testutil.NewRequest(t, http.MethodDelete, "/api/v1/dock-doors/5", "")); w.Code != http.StatusNoContent {
+		t.Fatalf("delete: status = %d", w.Code)
+	}
+	if w := serve(NewHandler(newMock()), testutil.NewRequest(t, http.MethodDelete, "/api/v1/dock-doors/8", "")); w.Code != http.StatusNotFound {
+		t.Fatalf("delete missing: status = %d", w.Code)
+	}
+}
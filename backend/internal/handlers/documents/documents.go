@@ -0,0 +1,448 @@
+// Package documents serves the document vault: insurance policies, leases,
+// calibration certificates and similar papers filed against an asset or the
+// org, each with an optional scanned copy in the object store and an expiry
+// date that raises document.expiring and document.expired alerts.
+package documents
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/document"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/objectstore"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const listLimit = 200
+
+// fileExtensions maps the accepted file types to their key suffix.
+var fileExtensions = map[string]string{
+	"application/pdf": "pdf",
+	"image/png":       "png",
+	"image/jpeg":      "jpg",
+}
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// DocumentStorage is the storage surface the handler needs (mockable).
+type DocumentStorage interface {
+	CreateDocument(ctx context.Context, orgID, userID int, req document.CreateRequest) (*document.Document, error)
+	GetDocument(ctx context.Context, orgID, id int) (*document.Document, error)
+	ListDocuments(ctx context.Context, orgID int, f document.ListFilter) ([]document.Document, error)
+	UpdateDocument(ctx context.Context, orgID, id int, req document.UpdateRequest) (*document.Document, error)
+	SetDocumentFile(ctx context.Context, orgID, id int, file document.File) (*document.Document, *string, error)
+	DeleteDocument(ctx context.Context, orgID, id int) (bool, *string, error)
+}
+
+type Handler struct {
+	storage DocumentStorage
+	objects objectstore.Store
+}
+
+// NewHandler builds the handler. objects may be nil, which disables file
+// uploads (503) but leaves the rest of the vault working.
+func NewHandler(storage DocumentStorage, objects objectstore.Store) *Handler {
+	return &Handler{storage: storage, objects: objects}
+}
+
+// RegisterRoutes wires the document routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Any member can read documents;
+// filing and changing them is operator work.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	operator := middleware.RequireCurrentOrgOperator(store)
+
+	r.With(member).Get("/api/v1/documents", h.List)
+	r.With(operator).Post("/api/v1/documents", h.Create)
+	r.With(member).Get("/api/v1/documents/{document_id}", h.Get)
+	r.With(operator).Patch("/api/v1/documents/{document_id}", h.Update)
+	r.With(operator).Delete("/api/v1/documents/{document_id}", h.Delete)
+	r.With(operator).Put("/api/v1/documents/{document_id}/file", h.PutFile)
+}
+
+// caller returns the request's org and session user, answering the error
+// itself when either is missing.
+func caller(w http.ResponseWriter, r *http.Request, reqID string) (orgID, userID int, ok bool) {
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return 0, 0, false
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return 0, 0, false
+	}
+	return orgID, claims.UserID, true
+}
+
+// parseID reads the document_id path param, answering 400 itself.
+func parseID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("document_id", chi.URLParam(r, "document_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// respondDocumentError maps the storage sentinels to their status codes.
+func respondDocumentError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	switch {
+	case errors.Is(err, storage.ErrDocumentAssetNotFound):
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "asset_id", Code: "fk_not_found", Message: err.Error(),
+		}})
+	case errors.Is(err, storage.ErrDocumentDates):
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "expires_at", Code: "invalid_value", Message: err.Error(),
+		}})
+	default:
+		httputil.RespondStorageError(w, r, err, reqID)
+	}
+}
+
+// deleteFile removes a replaced or orphaned file object. It is best-effort:
+// no document points at it, so a failure only leaves an orphan behind.
+func (h *Handler) deleteFile(r *http.Request, orgID int, fileURL *string) {
+	if fileURL == nil || h.objects == nil {
+		return
+	}
+	key, ok := h.objects.KeyForURL(*fileURL)
+	if !ok {
+		return
+	}
+	if err := h.objects.Delete(r.Context(), key); err != nil {
+		logger.Get().Warn().Err(err).Int("org_id", orgID).Str("key", key).Msg("failed to delete document file")
+	}
+}
+
+// @Summary  File a document
+// @Description Files an insurance policy, lease, calibration certificate, warranty, registration or other document against the asset `asset_id`, or against the org when `asset_id` is omitted. With an `expires_at`, the document raises a `document.expiring` alert `remind_days` (default 30; 0 for none) before it expires and a `document.expired` alert when it does, delivered to org webhooks like the other alerts. Upload its scanned copy afterwards with PUT /api/v1/documents/{document_id}/file.
+// @Tags     documents,internal
+// @ID       documents.create
+// @Accept   json
+// @Produce  json
+// @Param    request body document.CreateRequest true "Document"
+// @Success  201 {object} map[string]any "data: document.Document"
+// @Failure  400 {object} modelerrors.ErrorResponse "validation_error, or the asset not found"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/documents [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req document.CreateRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	d, err := h.storage.CreateDocument(r.Context(), orgID, userID, req)
+	if err != nil {
+		respondDocumentError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": d})
+}
+
+// @Summary  List documents
+// @Description The org's documents, soonest expiry first and those that never expire last, at most 200. Documents of deleted assets are left out. `expiry_status` is `valid`, `expiring` (inside its reminder window) or `expired`, and absent for a document without an expiry date.
+// @Tags     documents,internal
+// @ID       documents.list
+// @Produce  json
+// @Param    asset_id query int    false "Only this asset's documents" minimum(1) format(int64)
+// @Param    org_only query bool   false "Only org-level documents, filed against no asset"
+// @Param    type     query string false "Only this type" Enums(insurance, lease, calibration_cert, warranty, registration, other)
+// @Success  200 {object} map[string]any "data: []document.Document"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/documents [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	f := document.ListFilter{Limit: listLimit}
+	q := r.URL.Query()
+	if v := q.Get("asset_id"); v != "" {
+		id, err := httputil.ParseSurrogateID("asset_id", v)
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		f.AssetID = id
+	}
+	if v := q.Get("org_only"); v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field: "org_only", Code: "invalid_value", Message: "org_only must be true or false",
+			}})
+			return
+		}
+		f.OrgOnly = b
+	}
+	if t := q.Get("type"); t != "" {
+		if !document.IsType(t) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field: "type", Code: "invalid_value",
+				Message: "type must be one of: insurance, lease, calibration_cert, warranty, registration, other",
+			}})
+			return
+		}
+		f.Type = t
+	}
+
+	list, err := h.storage.ListDocuments(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Get a document
+// @Tags     documents,internal
+// @ID       documents.get
+// @Produce  json
+// @Param    document_id path int true "Document id"
+// @Success  200 {object} map[string]any "data: document.Document"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/documents/{document_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+	d, err := h.storage.GetDocument(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "document not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary  Update a document
+// @Description Omitted fields are left unchanged. Setting `expires_at` or `remind_days`, as when a policy is renewed, re-arms the document's expiry alerts for the new date.
+// @Tags     documents,internal
+// @ID       documents.update
+// @Accept   json
+// @Produce  json
+// @Param    document_id path int true "Document id"
+// @Param    request body document.UpdateRequest true "Fields to update"
+// @Success  200 {object} map[string]any "data: document.Document"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/documents/{document_id} [patch]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req document.UpdateRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	d, err := h.storage.UpdateDocument(r.Context(), orgID, id, req)
+	if err != nil {
+		respondDocumentError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "document not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary  Delete a document
+// @Description Deletes the document and its scanned copy.
+// @Tags     documents,internal
+// @ID       documents.delete
+// @Param    document_id path int true "Document id"
+// @Success  204 "document deleted"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/documents/{document_id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+	found, fileURL, err := h.storage.DeleteDocument(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "document not found", reqID)
+		return
+	}
+	h.deleteFile(r, orgID, fileURL)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary  Upload a document's scanned copy
+// @Description The body is the raw file (PDF, PNG or JPEG, at most 20 MiB), sent with its Content-Type. It replaces any earlier copy and is served from the object store at the document's `file.url`. 503 when no object store is configured.
+// @Tags     documents,internal
+// @ID       documents.file.put
+// @Accept   application/pdf
+// @Accept   image/png
+// @Accept   image/jpeg
+// @Produce  json
+// @Param    document_id path int    true "Document id"
+// @Param    file        body string true "File bytes"
+// @Success  200 {object} map[string]any "data: document.Document"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  413 {object} modelerrors.ErrorResponse "payload_too_large"
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Failure  503 {object} modelerrors.ErrorResponse "service_unavailable"
+// @Security SessionAuth
+// @Router   /api/v1/documents/{document_id}/file [put]
+func (h *Handler) PutFile(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	if h.objects == nil {
+		httputil.WriteJSONError(w, r, http.StatusServiceUnavailable, modelerrors.ErrInternal,
+			"document uploads are unavailable (object store not configured)", reqID)
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			httputil.Respond413(w, r, mbe.Limit, reqID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
+			"Failed to read request body", reqID)
+		return
+	}
+	// Trust the bytes, not the declared type: the object is served with
+	// this Content-Type to every viewer.
+	contentType := http.DetectContentType(body)
+	if len(body) == 0 || !slices.Contains(middleware.DocumentContentTypes, contentType) {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			"Request body must be a PDF, PNG or JPEG file", reqID)
+		return
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to upload document file", reqID)
+		return
+	}
+	key := fmt.Sprintf("documents/%d/%d/%s.%s", orgID, id, hex.EncodeToString(suffix), fileExtensions[contentType])
+	if err := h.objects.Put(r.Context(), key, contentType, body); err != nil {
+		logger.Get().Error().Err(err).Int("org_id", orgID).Msg("document file upload failed")
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to upload document file", reqID)
+		return
+	}
+
+	fileURL := h.objects.URL(key)
+	d, previous, err := h.storage.SetDocumentFile(r.Context(), orgID, id, document.File{
+		URL: fileURL, ContentType: contentType, SizeBytes: len(body),
+	})
+	if err != nil || d == nil {
+		h.deleteFile(r, orgID, &fileURL)
+	}
+	switch {
+	case err != nil:
+		httputil.RespondStorageError(w, r, err, reqID)
+	case d == nil:
+		httputil.Respond404(w, r, "document not found", reqID)
+	default:
+		h.deleteFile(r, orgID, previous)
+		httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+	}
+}
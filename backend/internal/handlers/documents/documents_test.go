@@ -0,0 +1,237 @@
+package documents
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/document"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+type mockDocumentStorage struct {
+	created   *document.CreateRequest
+	createErr error
+	filter    document.ListFilter
+	updated   *document.UpdateRequest
+	file      *document.File
+	previous  *string
+}
+
+func (m *mockDocumentStorage) CreateDocument(ctx context.Context, orgID, userID int, req document.CreateRequest) (*document.Document, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created = &req
+	return &document.Document{ID: 9, Type: req.Type, Title: req.Title}, nil
+}
+
+func (m *mockDocumentStorage) GetDocument(ctx context.Context, orgID, id int) (*document.Document, error) {
+	if id != 9 {
+		return nil, nil
+	}
+	return &document.Document{ID: 9, Type: document.TypeInsurance}, nil
+}
+
+func (m *mockDocumentStorage) ListDocuments(ctx context.Context, orgID int, f document.ListFilter) ([]document.Document, error) {
+	m.filter = f
+	return []document.Document{}, nil
+}
+
+func (m *mockDocumentStorage) UpdateDocument(ctx context.Context, orgID, id int, req document.UpdateRequest) (*document.Document, error) {
+	if id != 9 {
+		return nil, nil
+	}
+	m.updated = &req
+	return &document.Document{ID: 9}, nil
+}
+
+func (m *mockDocumentStorage) SetDocumentFile(ctx context.Context, orgID, id int, file document.File) (*document.Document, *string, error) {
+	if id != 9 {
+		return nil, nil, nil
+	}
+	m.file = &file
+	return &document.Document{ID: 9, File: &file}, m.previous, nil
+}
+
+func (m *mockDocumentStorage) DeleteDocument(ctx context.Context, orgID, id int) (bool, *string, error) {
+	if id != 9 {
+		return false, nil, nil
+	}
+	return true, m.previous, nil
+}
+
+// fakeObjects records the keys put and deleted.
+type fakeObjects struct{ puts, deletes []string }
+
+func (f *fakeObjects) Put(_ context.Context, key, _ string, _ []byte) error {
+	f.puts = append(f.puts, key)
+	return nil
+}
+
+func (f *fakeObjects) Delete(_ context.Context, key string) error {
+	f.deletes = append(f.deletes, key)
+	return nil
+}
+
+func (f *fakeObjects) URL(key string) string { return "https://cdn.test/" + key }
+
+func (f *fakeObjects) KeyForURL(u string) (string, bool) {
+	return strings.CutPrefix(u, "https://cdn.test/")
+}
+
+// serve routes through chi without the role middleware so handler-level
+// checks are exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Get("/api/v1/documents", h.List)
+		r.Post("/api/v1/documents", h.Create)
+		r.Get("/api/v1/documents/{document_id}", h.Get)
+		r.Patch("/api/v1/documents/{document_id}", h.Update)
+		r.Delete("/api/v1/documents/{document_id}", h.Delete)
+		r.Put("/api/v1/documents/{document_id}/file", h.PutFile)
+	}, req)
+}
+
+func TestCreate(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		createErr error
+		want      int
+		field     string
+	}{
+		{"org document", `{"type":"lease","title":"Warehouse lease","expires_at":"2027-01-01T00:00:00Z"}`, nil, http.StatusCreated, ""},
+		{"asset document", `{"asset_id":5,"type":"calibration_cert","title":"Scale cert","remind_days":0}`, nil, http.StatusCreated, ""},
+		{"unknown type", `{"type":"passport","title":"P"}`, nil, http.StatusBadRequest, "type"},
+		{"no title", `{"type":"insurance"}`, nil, http.StatusBadRequest, "title"},
+		{"remind too far", `{"type":"insurance","title":"Fleet","remind_days":400}`, nil, http.StatusBadRequest, "remind_days"},
+		{"foreign asset", `{"asset_id":5,"type":"insurance","title":"Fleet"}`, storage.ErrDocumentAssetNotFound, http.StatusBadRequest, "asset_id"},
+		{"expires before issue", `{"type":"insurance","title":"Fleet"}`, storage.ErrDocumentDates, http.StatusBadRequest, "expires_at"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockDocumentStorage{createErr: c.createErr}
+			w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodPost, "/api/v1/documents", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.field != "" && !strings.Contains(w.Body.String(), `"field":"`+c.field+`"`) {
+				t.Errorf("want field %s, body = %s", c.field, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestList_Filters(t *testing.T) {
+	m := &mockDocumentStorage{}
+	if w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodGet, "/api/v1/documents?asset_id=5&type=insurance", "")); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.filter.AssetID != 5 || m.filter.Type != document.TypeInsurance || m.filter.OrgOnly || m.filter.Limit != listLimit {
+		t.Errorf("filter = %+v", m.filter)
+	}
+	if w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodGet, "/api/v1/documents?org_only=true", "")); w.Code != http.StatusOK || !m.filter.OrgOnly {
+		t.Errorf("org_only: status = %d, filter = %+v", w.Code, m.filter)
+	}
+	for _, q := range []string{"type=passport", "org_only=maybe", "asset_id=x"} {
+		if w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodGet, "/api/v1/documents?"+q, "")); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d", q, w.Code)
+		}
+	}
+}
+
+func TestGetUpdateDelete(t *testing.T) {
+	m := &mockDocumentStorage{}
+	if w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodGet, "/api/v1/documents/9", "")); w.Code != http.StatusOK {
+		t.Errorf("get: %d", w.Code)
+	}
+	if w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodGet, "/api/v1/documents/8", "")); w.Code != http.StatusNotFound {
+		t.Errorf("get missing: %d", w.Code)
+	}
+
+	w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodPatch, "/api/v1/documents/9", `{"expires_at":"2028-01-01T00:00:00Z"}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("update: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.updated == nil || m.updated.ExpiresAt == nil {
+		t.Errorf("updated = %+v", m.updated)
+	}
+	if w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodPatch, "/api/v1/documents/8", `{"title":"x"}`)); w.Code != http.StatusNotFound {
+		t.Errorf("update missing: %d", w.Code)
+	}
+
+	if w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodDelete, "/api/v1/documents/9", "")); w.Code != http.StatusNoContent {
+		t.Errorf("delete: %d", w.Code)
+	}
+	if w := serve(NewHandler(m, nil), testutil.NewRequest(t, http.MethodDelete, "/api/v1/documents/8", "")); w.Code != http.StatusNotFound {
+		t.Errorf("delete missing: %d", w.Code)
+	}
+}
+
+func fileRequest(target string, body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, target, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/pdf")
+	orgID := 42
+	claims := &jwt.Claims{UserID: 1, Email: "operator@example.com", CurrentOrgID: &orgID}
+	return req.WithContext(context.WithValue(req.Context(), middleware.UserClaimsKey, claims))
+}
+
+func TestPutFile(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n1 0 obj\n")
+
+	t.Run("no object store", func(t *testing.T) {
+		w := serve(NewHandler(&mockDocumentStorage{}, nil), fileRequest("/api/v1/documents/9/file", pdf))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want 503", w.Code)
+		}
+	})
+
+	t.Run("replaces the previous file", func(t *testing.T) {
+		previous := "https://cdn.test/documents/42/9/old.pdf"
+		m := &mockDocumentStorage{previous: &previous}
+		objects := &fakeObjects{}
+		w := serve(NewHandler(m, objects), fileRequest("/api/v1/documents/9/file", pdf))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+		}
+		if len(objects.puts) != 1 || !strings.HasPrefix(objects.puts[0], "documents/42/9/") || !strings.HasSuffix(objects.puts[0], ".pdf") {
+			t.Errorf("puts = %v", objects.puts)
+		}
+		if m.file == nil || m.file.ContentType != "application/pdf" || m.file.SizeBytes != len(pdf) {
+			t.Errorf("file = %+v", m.file)
+		}
+		if len(objects.deletes) != 1 || objects.deletes[0] != "documents/42/9/old.pdf" {
+			t.Errorf("deletes = %v", objects.deletes)
+		}
+	})
+
+	t.Run("body is not a document", func(t *testing.T) {
+		objects := &fakeObjects{}
+		w := serve(NewHandler(&mockDocumentStorage{}, objects), fileRequest("/api/v1/documents/9/file", []byte("<html><script>")))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", w.Code)
+		}
+		if len(objects.puts) != 0 {
+			t.Errorf("uploaded %v, want nothing", objects.puts)
+		}
+	})
+
+	t.Run("missing document deletes the upload", func(t *testing.T) {
+		objects := &fakeObjects{}
+		w := serve(NewHandler(&mockDocumentStorage{}, objects), fileRequest("/api/v1/documents/8/file", pdf))
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want 404", w.Code)
+		}
+		if len(objects.deletes) != 1 || objects.deletes[0] != objects.puts[0] {
+			t.Errorf("puts = %v, deletes = %v", objects.puts, objects.deletes)
+		}
+	})
+}
@@ -0,0 +1,296 @@
+// Package epcis serves the GS1 EPCIS 2.0 REST interface over asset scans:
+// an event query that renders scans as ObjectEvents and a capture endpoint
+// that records partners' ObjectEvents as scans. Mapping rules live in
+// internal/epcis.
+package epcis
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/epcis"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const (
+	defaultPerPage = 100
+	maxPerPage     = 1000
+	// maxCaptureEvents bounds one capture document; larger batches are split
+	// by the sender.
+	maxCaptureEvents = 1000
+)
+
+// EPCISStorage is the storage surface the handler needs (mockable).
+type EPCISStorage interface {
+	ListEPCISScans(ctx context.Context, orgID int, f epcis.Filter) ([]epcis.Scan, error)
+	ResolveEPCISIdentifiers(ctx context.Context, orgID int, l epcis.Lookup) (epcis.Resolved, error)
+	CaptureEPCISScans(ctx context.Context, orgID int, scans []epcis.CapturedScan) (int, error)
+}
+
+type Handler struct {
+	storage EPCISStorage
+	now     func() time.Time
+}
+
+func NewHandler(storage EPCISStorage) *Handler {
+	return &Handler{storage: storage, now: time.Now}
+}
+
+// CaptureResult reports the outcome of a capture. Duplicates counts sightings
+// that were already recorded (e.g. a replayed document).
+type CaptureResult struct {
+	Events     int `json:"events"     example:"2"`
+	Scans      int `json:"scans"      example:"5"`
+	Duplicates int `json:"duplicates" example:"0"`
+}
+
+// CaptureResponse is the envelope returned by POST /api/v1/epcis/capture.
+type CaptureResponse struct {
+	Data CaptureResult `json:"data"`
+}
+
+var errInvalidPageToken = errors.New("invalid page token")
+
+func encodePageToken(c epcis.Cursor) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(strconv.FormatInt(c.At.UnixNano(), 10) + ":" + strconv.Itoa(c.AssetID)))
+}
+
+func decodePageToken(s string) (*epcis.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, errInvalidPageToken
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 {
+		return nil, errInvalidPageToken
+	}
+	return &epcis.Cursor{At: time.Unix(0, n).UTC(), AssetID: i}, nil
+}
+
+func setVersionHeaders(w http.ResponseWriter) {
+	w.Header().Set("GS1-EPCIS-Version", epcis.Version)
+	w.Header().Set("GS1-CBV-Version", epcis.Version)
+}
+
+var queryParams = []string{"GE_eventTime", "LT_eventTime", "EQ_bizLocation", "MATCH_epc", "eventType", "perPage", "nextPageToken"}
+
+// @Summary      EPCIS event query
+// @Description  **Required scope:** `tracking:read`
+// @Description
+// @Description  GS1 EPCIS 2.0 SimpleEventQuery over asset scans. Each scan is returned as an `OBSERVE` ObjectEvent (bizStep `observing`) in an EPCISQueryDocument, oldest first. `epcList` holds the EPC URIs of the asset's RFID tags — pure identity (`urn:epc:id:sgtin:...`) for SGTIN-96, GRAI-96 and GIAI-96 tags, raw (`urn:epc:raw:96.x...`) otherwise — or `https://trakrf.id/epcis/asset/<identifier>` for assets without one. `bizLocation` is the SGLN of the location's SGLN-96 tag, or `https://trakrf.id/epcis/location/<identifier>`.
+// @Description
+// @Description  Filters: `GE_eventTime` (inclusive), `LT_eventTime` (exclusive), `EQ_bizLocation` and `MATCH_epc` (repeatable; exact identifiers only, no `urn:epc:idpat:` patterns). Follow the `Link: rel="next"` header to page; it carries `nextPageToken`.
+// @Tags         epcis,public
+// @ID           epcis.events
+// @Produce      json
+// @Param        GE_eventTime   query string   false "RFC 3339 start (inclusive)" format(date-time)
+// @Param        LT_eventTime   query string   false "RFC 3339 end (exclusive)" format(date-time)
+// @Param        EQ_bizLocation query []string false "bizLocation ids" collectionFormat(multi)
+// @Param        MATCH_epc      query []string false "EPC URIs" collectionFormat(multi)
+// @Param        eventType      query string   false "only ObjectEvent is produced"
+// @Param        perPage        query int      false "max 1000" default(100) minimum(1) maximum(1000)
+// @Param        nextPageToken  query string   false "token from the previous page's Link header"
+// @Success      200  {object}  epcis.QueryDocument
+// @Header       200  {string}  GS1-EPCIS-Version "2.0.0"
+// @Header       200  {string}  Link "next page: <...&nextPageToken=...>; rel=\"next\""
+// @Failure      400  {object}  modelerrors.ErrorResponse "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      429  {object}  modelerrors.ErrorResponse "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[tracking:read]
+// @Router       /api/v1/epcis/events [get]
+func (h *Handler) Events(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	invalid := func(field, msg string) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: field, Code: "invalid_value", Message: msg,
+		}})
+	}
+
+	q := r.URL.Query()
+	for key := range q {
+		known := false
+		for _, p := range queryParams {
+			known = known || key == p
+		}
+		if !known {
+			invalid(key, fmt.Sprintf("unknown query parameter %q; supported: %s", key, strings.Join(queryParams, ", ")))
+			return
+		}
+	}
+
+	f := epcis.Filter{Limit: defaultPerPage}
+	for _, p := range []struct {
+		name string
+		into **time.Time
+	}{{"GE_eventTime", &f.From}, {"LT_eventTime", &f.To}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				invalid(p.name, fmt.Sprintf("%s must be an RFC 3339 timestamp", p.name))
+				return
+			}
+			*p.into = &t
+		}
+	}
+	if v := q.Get("perPage"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPerPage {
+			invalid("perPage", fmt.Sprintf("perPage must be an integer between 1 and %d", maxPerPage))
+			return
+		}
+		f.Limit = n
+	}
+	if v := q.Get("nextPageToken"); v != "" {
+		c, err := decodePageToken(v)
+		if err != nil {
+			invalid("nextPageToken", "nextPageToken must come from a previous page's Link header")
+			return
+		}
+		f.After = c
+	}
+
+	setVersionHeaders(w)
+	if v := q.Get("eventType"); v != "" && !strings.Contains(v, epcis.EventTypeObject) {
+		httputil.WriteJSON(w, http.StatusOK, epcis.NewQueryDocument([]epcis.ObjectEvent{}, h.now()))
+		return
+	}
+
+	epcs, locations := q["MATCH_epc"], q["EQ_bizLocation"]
+	if len(epcs) > 0 || len(locations) > 0 {
+		lookup, problems := epcis.QueryLookup(epcs, locations)
+		if len(problems) > 0 {
+			httputil.WriteValidationError(w, r, reqID, problems)
+			return
+		}
+		res, err := h.storage.ResolveEPCISIdentifiers(r.Context(), orgID, lookup)
+		if err != nil {
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+			return
+		}
+		f.AssetIDs, f.LocationIDs = res.AssetIDs(epcs), res.LocationIDs(locations)
+	}
+
+	events := []epcis.ObjectEvent{}
+	if (f.AssetIDs == nil || len(f.AssetIDs) > 0) && (f.LocationIDs == nil || len(f.LocationIDs) > 0) {
+		scans, err := h.storage.ListEPCISScans(r.Context(), orgID, f)
+		if err != nil {
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+			return
+		}
+		for _, s := range scans {
+			events = append(events, epcis.NewObjectEvent(s))
+		}
+		if len(scans) == f.Limit {
+			last := scans[len(scans)-1]
+			next := r.URL.Query()
+			next.Set("nextPageToken", encodePageToken(epcis.Cursor{At: last.Timestamp, AssetID: last.AssetID}))
+			w.Header().Set("Link", fmt.Sprintf(`<%s?%s>; rel="next"`, r.URL.Path, next.Encode()))
+		}
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, epcis.NewQueryDocument(events, h.now()))
+}
+
+// @Summary      EPCIS capture
+// @Description  **Required scope:** `scans:write`
+// @Description
+// @Description  Records the ObjectEvents of a GS1 EPCIS 2.0 EPCISDocument as asset scans. Only `OBSERVE` and `ADD` ObjectEvents are accepted; each EPC in `epcList` must identify one of the org's assets by RFID tag (pure identity, tag or raw EPC URI) or `https://trakrf.id/epcis/asset/<identifier>`, and `bizLocation`, when present, one of its locations by SGLN or `https://trakrf.id/epcis/location/<identifier>`. Capture is all-or-nothing and synchronous: any invalid or unknown identifier rejects the whole document with 400, naming each offending field. Sightings already recorded for the same event time and asset are skipped, so replaying a document is safe. At most 1000 events per document.
+// @Tags         epcis,public
+// @ID           epcis.capture
+// @Accept       json
+// @Produce      json
+// @Param        request body epcis.Document true "EPCISDocument"
+// @Success      201  {object}  epcis.CaptureResponse
+// @Failure      400  {object}  modelerrors.ErrorResponse "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      415  {object}  modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[scans:write]
+// @Router       /api/v1/epcis/capture [post]
+func (h *Handler) Capture(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	var doc epcis.Document
+	if err := httputil.DecodeJSON(r, &doc); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	events := doc.EPCISBody.EventList
+	switch {
+	case doc.Type != "EPCISDocument":
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "type", Code: "invalid_value", Message: "type must be EPCISDocument",
+		}})
+		return
+	case len(events) == 0:
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "epcisBody.eventList", Code: "required", Message: "eventList must contain at least one event",
+		}})
+		return
+	case len(events) > maxCaptureEvents:
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "epcisBody.eventList", Code: "too_long",
+			Message: fmt.Sprintf("eventList must contain at most %d events", maxCaptureEvents),
+		}})
+		return
+	}
+
+	lookup, problems := epcis.CaptureLookup(events)
+	if len(problems) > 0 {
+		httputil.WriteValidationError(w, r, reqID, problems)
+		return
+	}
+	res, err := h.storage.ResolveEPCISIdentifiers(r.Context(), orgID, lookup)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	scans, problems := epcis.CaptureScans(events, res)
+	if len(problems) > 0 {
+		httputil.WriteValidationError(w, r, reqID, problems)
+		return
+	}
+
+	inserted, err := h.storage.CaptureEPCISScans(r.Context(), orgID, scans)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	setVersionHeaders(w)
+	httputil.WriteJSON(w, http.StatusCreated, CaptureResponse{Data: CaptureResult{
+		Events:     len(events),
+		Scans:      inserted,
+		Duplicates: len(scans) - inserted,
+	}})
+}
@@ -0,0 +1,169 @@
+package epcis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/epcis"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockStorage struct {
+	scans    []epcis.Scan
+	resolved epcis.Resolved
+	inserted int
+
+	listed   *epcis.Filter
+	lookup   *epcis.Lookup
+	captured []epcis.CapturedScan
+}
+
+func (m *mockStorage) ListEPCISScans(_ context.Context, _ int, f epcis.Filter) ([]epcis.Scan, error) {
+	m.listed = &f
+	return m.scans, nil
+}
+
+func (m *mockStorage) ResolveEPCISIdentifiers(_ context.Context, _ int, l epcis.Lookup) (epcis.Resolved, error) {
+	m.lookup = &l
+	return m.resolved, nil
+}
+
+func (m *mockStorage) CaptureEPCISScans(_ context.Context, _ int, scans []epcis.CapturedScan) (int, error) {
+	m.captured = scans
+	return m.inserted, nil
+}
+
+var t0 = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestEvents_RendersQueryDocumentAndPages(t *testing.T) {
+	mock := &mockStorage{scans: []epcis.Scan{
+		{Timestamp: t0, AssetID: 1, AssetExternalKey: "A", AssetTags: []string{"3074257BF7194E4000001A85"}},
+		{Timestamp: t0.Add(time.Minute), AssetID: 2, AssetExternalKey: "B"},
+	}}
+	w := httptest.NewRecorder()
+	NewHandler(mock).Events(w, testutil.NewRequest(t, http.MethodGet, "/api/v1/epcis/events?perPage=2&GE_eventTime=2026-03-01T00:00:00Z", ""))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, "2.0.0", w.Header().Get("GS1-EPCIS-Version"))
+	require.NotNil(t, mock.listed)
+	assert.Equal(t, 2, mock.listed.Limit)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *mock.listed.From)
+	assert.Nil(t, mock.listed.AssetIDs)
+
+	var doc struct {
+		Type      string `json:"type"`
+		EPCISBody struct {
+			QueryResults struct {
+				ResultsBody struct {
+					EventList []epcis.ObjectEvent `json:"eventList"`
+				} `json:"resultsBody"`
+			} `json:"queryResults"`
+		} `json:"epcisBody"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+	assert.Equal(t, "EPCISQueryDocument", doc.Type)
+	events := doc.EPCISBody.QueryResults.ResultsBody.EventList
+	require.Len(t, events, 2)
+	assert.Equal(t, []string{"urn:epc:id:sgtin:0614141.812345.6789"}, events[0].EPCList)
+
+	link := w.Header().Get("Link")
+	require.True(t, strings.HasPrefix(link, "</api/v1/epcis/events?"), link)
+	u, err := url.Parse(strings.TrimPrefix(strings.Split(link, ">")[0], "<"))
+	require.NoError(t, err)
+	assert.Equal(t, "2", u.Query().Get("perPage"), "the next link keeps the query's filters")
+	c, err := decodePageToken(u.Query().Get("nextPageToken"))
+	require.NoError(t, err)
+	assert.Equal(t, epcis.Cursor{At: t0.Add(time.Minute), AssetID: 2}, *c)
+}
+
+func TestEvents_LastPageHasNoLink(t *testing.T) {
+	mock := &mockStorage{scans: []epcis.Scan{{Timestamp: t0, AssetID: 1, AssetExternalKey: "A"}}}
+	w := httptest.NewRecorder()
+	NewHandler(mock).Events(w, testutil.NewRequest(t, http.MethodGet, "/api/v1/epcis/events", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Link"))
+}
+
+func TestEvents_UnmatchedFilterSkipsScanQuery(t *testing.T) {
+	mock := &mockStorage{resolved: epcis.Resolved{AssetsByTag: map[string]int{}, AssetsByKey: map[string]int{}}}
+	w := httptest.NewRecorder()
+	NewHandler(mock).Events(w, testutil.NewRequest(t, http.MethodGet,
+		"/api/v1/epcis/events?MATCH_epc="+url.QueryEscape("urn:epc:id:sgtin:0614141.812345.6789"), ""))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, mock.lookup)
+	assert.Len(t, mock.lookup.AssetTags, 8)
+	assert.Nil(t, mock.listed, "no asset matched, so nothing can")
+	assert.Contains(t, w.Body.String(), `"eventList":[]`)
+}
+
+func TestEvents_BadQuery400(t *testing.T) {
+	for _, q := range []string{
+		"limit=10",
+		"perPage=0",
+		"GE_eventTime=yesterday",
+		"nextPageToken=%21",
+		"MATCH_epc=" + url.QueryEscape("urn:epc:idpat:sgtin:0614141.*.*"),
+	} {
+		w := httptest.NewRecorder()
+		NewHandler(&mockStorage{}).Events(w, testutil.NewRequest(t, http.MethodGet, "/api/v1/epcis/events?"+q, ""))
+		assert.Equal(t, http.StatusBadRequest, w.Code, q)
+	}
+}
+
+const captureBody = `{
+	"@context": ["https://ref.gs1.org/standards/epcis/2.0.0/epcis-context.jsonld"],
+	"type": "EPCISDocument",
+	"schemaVersion": "2.0",
+	"creationDate": "2026-03-01T12:05:00Z",
+	"epcisBody": {"eventList": [{
+		"type": "ObjectEvent",
+		"eventTime": "2026-03-01T07:00:00-05:00",
+		"eventTimeZoneOffset": "-05:00",
+		"epcList": ["urn:epc:id:sgtin:0614141.812345.6789"],
+		"action": "OBSERVE",
+		"bizStep": "receiving",
+		"bizLocation": {"id": "https://trakrf.id/epcis/location/WH-01"}
+	}]}
+}`
+
+func TestCapture_RecordsScans(t *testing.T) {
+	mock := &mockStorage{inserted: 1, resolved: epcis.Resolved{
+		AssetsByTag:    map[string]int{"3074257BF7194E4000001A85": 5},
+		LocationsByKey: map[string]int{"WH-01": 9},
+	}}
+	w := httptest.NewRecorder()
+	NewHandler(mock).Capture(w, testutil.NewRequest(t, http.MethodPost, "/api/v1/epcis/capture", captureBody))
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.Len(t, mock.captured, 1)
+	assert.Equal(t, 5, mock.captured[0].AssetID)
+	assert.Equal(t, 9, *mock.captured[0].LocationID)
+	assert.True(t, t0.Equal(mock.captured[0].Timestamp))
+	assert.JSONEq(t, `{"data":{"events":1,"scans":1,"duplicates":0}}`, w.Body.String())
+}
+
+func TestCapture_UnknownEPC400(t *testing.T) {
+	mock := &mockStorage{resolved: epcis.Resolved{LocationsByKey: map[string]int{"WH-01": 9}}}
+	w := httptest.NewRecorder()
+	NewHandler(mock).Capture(w, testutil.NewRequest(t, http.MethodPost, "/api/v1/epcis/capture", captureBody))
+
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "epcisBody.eventList[0].epcList[0]")
+	assert.Nil(t, mock.captured, "capture is all-or-nothing")
+}
+
+func TestCapture_NotADocument400(t *testing.T) {
+	w := httptest.NewRecorder()
+	NewHandler(&mockStorage{}).Capture(w, testutil.NewRequest(t, http.MethodPost, "/api/v1/epcis/capture", `{"type":"EPCISQueryDocument"}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -1,12 +1,25 @@
 package frontend
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
+	"html"
 	"io/fs"
+	"log/slog"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/organization"
 )
 
 // appConfigPlaceholder is replaced in index.html at serve time with an inline
@@ -15,13 +28,33 @@ import (
 const appConfigPlaceholder = "<!--__APP_CONFIG__-->"
 
 type appConfig struct {
-	EnvironmentLabel string `json:"environmentLabel"`
+	EnvironmentLabel string    `json:"environmentLabel"`
+	Theme            *appTheme `json:"theme,omitempty"`
+}
+
+// appTheme is the white-label branding of a themed entry point, in the
+// SPA's camelCase.
+type appTheme struct {
+	OrgName      string `json:"orgName"`
+	LogoURL      string `json:"logoUrl,omitempty"`
+	PrimaryColor string `json:"primaryColor,omitempty"`
+	AccentColor  string `json:"accentColor,omitempty"`
+}
+
+// ThemeResolver finds the themed org behind an SPA entry point, by org
+// identifier or by link-domain origin. It returns nil when neither matches.
+type ThemeResolver interface {
+	FindBrandedApp(ctx context.Context, identifier, origin string) (*organization.BrandedApp, error)
 }
 
 type Handler struct {
-	fileServer      http.Handler
-	frontendFS      fs.FS
-	appConfigScript string
+	fileServer       http.Handler
+	assets           fs.FS
+	frontendFS       fs.FS
+	environmentLabel string
+	appConfigScript  string
+	themes           ThemeResolver
+	appHost          string
 }
 
 // NewHandler creates a new frontend handler instance. environmentLabel is the
@@ -36,29 +69,125 @@ func NewHandler(frontendFS fs.FS, distPath string, environmentLabel string) *Han
 	fileServer := http.FileServer(http.FS(subFS))
 
 	return &Handler{
-		fileServer:      cacheControlMiddleware(fileServer),
-		frontendFS:      frontendFS,
-		appConfigScript: buildAppConfigScript(environmentLabel),
+		fileServer:       cacheControlMiddleware(fileServer),
+		assets:           subFS,
+		frontendFS:       frontendFS,
+		environmentLabel: environmentLabel,
+		appConfigScript:  buildAppConfigScript(appConfig{EnvironmentLabel: environmentLabel}),
 	}
 }
 
+// WithThemes turns on per-org theming of index.html. A page is themed when it
+// is loaded from a verified custom domain (see middleware.CustomDomain),
+// under /o/<identifier>/, from <identifier>.<baseURL host>, or from one of an
+// org's link domains; every other load is served as before.
+func (h *Handler) WithThemes(resolver ThemeResolver, baseURL string) *Handler {
+	h.themes = resolver
+	if u, err := url.Parse(baseURL); err == nil {
+		h.appHost = strings.ToLower(u.Hostname())
+	}
+	return h
+}
+
 // buildAppConfigScript renders the inline script that publishes runtime config
 // onto window.__APP_CONFIG__. json.Marshal HTML-escapes '<' '>' '&' by default,
 // so any value containing "</script>" becomes "</script>" and cannot
 // break out of the inline <script> tag.
-func buildAppConfigScript(environmentLabel string) string {
-	b, err := json.Marshal(appConfig{EnvironmentLabel: environmentLabel})
+func buildAppConfigScript(cfg appConfig) string {
+	b, err := json.Marshal(cfg)
 	if err != nil {
 		b = []byte(`{"environmentLabel":""}`)
 	}
 	return "<script>window.__APP_CONFIG__ = " + string(b) + ";</script>"
 }
 
-// ServeFrontend handles serving static frontend assets.
+// ServeFrontend handles serving static frontend assets. A .br or .gz
+// sibling built alongside an asset is served in its place to clients that
+// accept that encoding.
 func (h *Handler) ServeFrontend(w http.ResponseWriter, r *http.Request) {
+	if ct, ok := contentTypes[path.Ext(r.URL.Path)]; ok {
+		w.Header().Set("Content-Type", ct)
+	}
+	if h.servePrecompressed(w, r) {
+		return
+	}
 	h.fileServer.ServeHTTP(w, r)
 }
 
+// contentTypes pins types the platform's MIME table may lack or get wrong:
+// browsers ignore a manifest served as anything else, and
+// WebAssembly.instantiateStreaming refuses a .wasm that is not application/wasm.
+var contentTypes = map[string]string{
+	".webmanifest": "application/manifest+json",
+	".wasm":        "application/wasm",
+}
+
+// precompressedEncodings are the sibling files looked for next to an asset,
+// in order of preference.
+var precompressedEncodings = []struct{ name, ext string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// servePrecompressed serves the best precompressed sibling of the requested
+// asset the client accepts, and reports whether it did. Vary is set whenever
+// a sibling exists, so shared caches keep the encodings apart.
+func (h *Handler) servePrecompressed(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+	if name == "" || name == "." {
+		return false
+	}
+
+	accepted := acceptedEncodings(r.Header.Get("Accept-Encoding"))
+	vary := false
+	for _, enc := range precompressedEncodings {
+		info, err := fs.Stat(h.assets, name+enc.ext)
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		if !vary {
+			w.Header().Add("Vary", "Accept-Encoding")
+			vary = true
+		}
+		if !accepted[enc.name] {
+			continue
+		}
+		if w.Header().Get("Content-Type") == "" {
+			if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+				w.Header().Set("Content-Type", ct)
+			}
+		}
+		w.Header().Set("Content-Encoding", enc.name)
+		setCacheHeaders(w.Header(), r.URL.Path)
+		http.ServeFileFS(w, r, h.assets, name+enc.ext)
+		return true
+	}
+	return false
+}
+
+// acceptedEncodings parses an Accept-Encoding header into the set of
+// codings with a non-zero quality.
+func acceptedEncodings(header string) map[string]bool {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		accepted[coding] = true
+	}
+	return accepted
+}
+
 // ServeSPA serves index.html for all frontend routes, injecting runtime config
 // in place of appConfigPlaceholder. index.html is read fresh per request and
 // served no-cache, so the injected config reflects the pod's current env.
@@ -71,7 +200,22 @@ func (h *Handler) ServeSPA(w http.ResponseWriter, r *http.Request, indexPath str
 
 	// Replace exactly one placeholder; a no-op if absent (fail-safe: served
 	// unchanged, window.__APP_CONFIG__ stays undefined → SPA defaults to no banner).
-	html := strings.Replace(string(indexHTML), appConfigPlaceholder, h.appConfigScript, 1)
+	page := string(indexHTML)
+	if theme := h.resolveTheme(r); theme != nil {
+		page = titlePattern.ReplaceAllLiteralString(page, "<title>"+html.EscapeString(theme.OrgName)+"</title>")
+		page = strings.Replace(page, appConfigPlaceholder,
+			buildAppConfigScript(appConfig{EnvironmentLabel: h.environmentLabel, Theme: theme}), 1)
+	} else {
+		page = strings.Replace(page, appConfigPlaceholder, h.appConfigScript, 1)
+	}
+
+	// The security headers middleware set the SPA's policy before routing;
+	// allow exactly the inline scripts this page carries.
+	for _, name := range []string{"Content-Security-Policy", "Content-Security-Policy-Report-Only"} {
+		if policy := w.Header().Get(name); policy != "" {
+			w.Header().Set(name, withInlineScriptHashes(policy, page))
+		}
+	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
 	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
@@ -79,7 +223,89 @@ func (h *Handler) ServeSPA(w http.ResponseWriter, r *http.Request, indexPath str
 	w.Header().Set("Expires", "0")
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(html))
+	w.Write([]byte(page))
+}
+
+var titlePattern = regexp.MustCompile(`(?s)<title>.*?</title>`)
+
+// resolveTheme returns the theme for the entry point r was made on, or nil.
+// A lookup failure serves the default page: branding is cosmetic and must
+// never keep the app from loading.
+func (h *Handler) resolveTheme(r *http.Request) *appTheme {
+	if h.themes == nil {
+		return nil
+	}
+	identifier, origin := h.themeEntryPoint(r)
+	if identifier == "" && origin == "" {
+		return nil
+	}
+	app, err := h.themes.FindBrandedApp(r.Context(), identifier, origin)
+	if err != nil {
+		slog.WarnContext(r.Context(), "app theme lookup failed", "identifier", identifier, "origin", origin, "error", err)
+		return nil
+	}
+	if app == nil {
+		return nil
+	}
+	return &appTheme{
+		OrgName:      app.OrgName,
+		LogoURL:      app.Theme.LogoURL,
+		PrimaryColor: app.Theme.PrimaryColor,
+		AccentColor:  app.Theme.AccentColor,
+	}
+}
+
+// themeEntryPoint returns the org identifier the request names, and for a
+// host other than the app's own, its https origin to match link domains.
+func (h *Handler) themeEntryPoint(r *http.Request) (identifier, origin string) {
+	if org := middleware.GetHostOrg(r.Context()); org != nil {
+		return org.Identifier, ""
+	}
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/o/"); ok {
+		identifier, _, _ = strings.Cut(rest, "/")
+	}
+
+	host := strings.ToLower(r.Host)
+	hostname := host
+	if hn, _, err := net.SplitHostPort(host); err == nil {
+		hostname = hn
+	}
+	if h.appHost == "" || hostname == "" || hostname == h.appHost {
+		return identifier, ""
+	}
+	if label, ok := strings.CutSuffix(hostname, "."+h.appHost); ok && !strings.Contains(label, ".") {
+		if identifier == "" {
+			identifier = label
+		}
+		return identifier, ""
+	}
+	return identifier, "https://" + host
+}
+
+// inlineScriptPattern matches a <script> element without a src attribute and
+// captures its body, which is what a CSP hash covers.
+var inlineScriptPattern = regexp.MustCompile(`(?s)<script(?:\s+type="[^"]*")?\s*>(.*?)</script>`)
+
+// withInlineScriptHashes adds a 'sha256-…' source for each inline script in
+// html to policy's script-src directive. A policy without script-src is
+// returned unchanged: its default-src then governs scripts, and widening
+// that would loosen every other fetch type too.
+func withInlineScriptHashes(policy, html string) string {
+	var sources []string
+	for _, m := range inlineScriptPattern.FindAllStringSubmatch(html, -1) {
+		sum := sha256.Sum256([]byte(m[1]))
+		sources = append(sources, "'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'")
+	}
+	if len(sources) == 0 {
+		return policy
+	}
+	directives := strings.Split(policy, ";")
+	for i, d := range directives {
+		if fields := strings.Fields(d); len(fields) > 0 && fields[0] == "script-src" {
+			directives[i] = strings.TrimRight(d, " ") + " " + strings.Join(sources, " ")
+		}
+	}
+	return strings.Join(directives, ";")
 }
 
 // RegisterRoutes registers frontend serving routes on the given router.
@@ -89,18 +315,29 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 
 func cacheControlMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		path := r.URL.Path
-
-		if path == "/" || path == "/index.html" || !strings.Contains(path, ".") {
-			w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
-			w.Header().Set("Pragma", "no-cache")
-			w.Header().Set("Expires", "0")
-		} else if strings.HasPrefix(path, "/assets/") {
-			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
-		} else {
-			w.Header().Set("Cache-Control", "public, max-age=3600")
-		}
-
+		setCacheHeaders(w.Header(), r.URL.Path)
 		next.ServeHTTP(w, r)
 	})
 }
+
+// hashedAssetPattern matches the content-hashed file names Vite emits under
+// /assets/ (index-BxT3k9aQ.js, react-vendor-C1xq_0Zp.js): an eight-character
+// base64url hash before the extension. A new build changes the name, so these
+// are safe to cache forever; an unhashed file under /assets/ is not.
+var hashedAssetPattern = regexp.MustCompile(`^/assets/(?:.+/)?[^/]+[-.][A-Za-z0-9_-]{8}\.[A-Za-z0-9]+$`)
+
+// setCacheHeaders applies the cache policy for a frontend path: the SPA shell
+// and deploy metadata are always revalidated, hashed assets are immutable,
+// and everything else (icons, manifest) is cached for an hour.
+func setCacheHeaders(h http.Header, urlPath string) {
+	switch {
+	case urlPath == "/" || urlPath == "/index.html" || urlPath == "/version.json" || !strings.Contains(urlPath, "."):
+		h.Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		h.Set("Pragma", "no-cache")
+		h.Set("Expires", "0")
+	case hashedAssetPattern.MatchString(urlPath):
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	default:
+		h.Set("Cache-Control", "public, max-age=3600")
+	}
+}
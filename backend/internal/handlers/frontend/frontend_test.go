@@ -1,12 +1,20 @@
 package frontend
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"io/fs"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"testing/fstest"
+
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/organization"
 )
 
 const testIndexHTML = `<!DOCTYPE html>
@@ -105,3 +113,267 @@ func TestNewHandler_SubFS(t *testing.T) {
 	}
 	_ = h
 }
+
+func TestServeSPA_HashesInlineScriptsIntoCSP(t *testing.T) {
+	const darkMode = "\n  document.documentElement.classList.add('dark');\n"
+	index := `<html><head><!--__APP_CONFIG__--><script>` + darkMode + `</script></head>
+<body><script type="module" src="/assets/index.js"></script></body></html>`
+	h := newTestHandler("preview", index)
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'self'; object-src 'none'")
+	h.ServeSPA(rec, httptest.NewRequest(http.MethodGet, "/", nil), "frontend/dist/index.html")
+
+	hash := func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return "'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+	}
+	configBody := strings.TrimSuffix(strings.TrimPrefix(h.appConfigScript, "<script>"), "</script>")
+	want := "default-src 'self'; script-src 'self' " + hash(configBody) + " " + hash(darkMode) + "; object-src 'none'"
+	if got := rec.Header().Get("Content-Security-Policy"); got != want {
+		t.Errorf("CSP\nwant: %s\ngot:  %s", want, got)
+	}
+}
+
+func TestServeSPA_NoCSPLeftAlone(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestHandler("preview", testIndexHTML).ServeSPA(rec, httptest.NewRequest(http.MethodGet, "/", nil), "frontend/dist/index.html")
+	if got := rec.Header().Get("Content-Security-Policy"); got != "" {
+		t.Errorf("unexpected CSP %q", got)
+	}
+}
+
+type fakeThemes struct {
+	apps          map[string]*organization.BrandedApp // by identifier or origin
+	err           error
+	gotIdentifier string
+	gotOrigin     string
+	lookups       int
+}
+
+func (f *fakeThemes) FindBrandedApp(_ context.Context, identifier, origin string) (*organization.BrandedApp, error) {
+	f.lookups++
+	f.gotIdentifier, f.gotOrigin = identifier, origin
+	if f.err != nil {
+		return nil, f.err
+	}
+	if app, ok := f.apps[identifier]; ok && identifier != "" {
+		return app, nil
+	}
+	return f.apps[origin], nil
+}
+
+const themedIndexHTML = `<html><head><title>TrakRF - Real-Time Asset Tracking</title><!--__APP_CONFIG__--></head></html>`
+
+func serveThemed(themes *fakeThemes, host, path string) *httptest.ResponseRecorder {
+	h := newTestHandler("", themedIndexHTML).WithThemes(themes, "https://app.trakrf.id")
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Host = host
+	h.ServeSPA(rec, req, "frontend/dist/index.html")
+	return rec
+}
+
+func TestServeSPA_ThemeEntryPoints(t *testing.T) {
+	acme := &organization.BrandedApp{OrgName: "Acme & Sons", Theme: organization.AppTheme{
+		LogoURL: "https://acme.example/logo.png", PrimaryColor: "#0f766e"}}
+	cases := []struct {
+		name, host, path         string
+		wantIdentifier, wantOrig string
+		themed                   bool
+	}{
+		{"path prefix", "app.trakrf.id", "/o/acme/", "acme", "", true},
+		{"subdomain", "acme.app.trakrf.id", "/", "acme", "", true},
+		{"link domain", "track.acme.example", "/", "", "https://track.acme.example", true},
+		{"app host is not looked up", "app.trakrf.id:443", "/", "", "", false},
+		{"unknown org", "app.trakrf.id", "/o/nobody", "nobody", "", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			themes := &fakeThemes{apps: map[string]*organization.BrandedApp{
+				"acme": acme, "https://track.acme.example": acme}}
+			body := serveThemed(themes, c.host, c.path).Body.String()
+
+			if c.wantIdentifier == "" && c.wantOrig == "" {
+				if themes.lookups != 0 {
+					t.Errorf("unexpected lookup (%q, %q)", themes.gotIdentifier, themes.gotOrigin)
+				}
+			} else if themes.gotIdentifier != c.wantIdentifier || themes.gotOrigin != c.wantOrig {
+				t.Errorf("lookup = (%q, %q), want (%q, %q)", themes.gotIdentifier, themes.gotOrigin, c.wantIdentifier, c.wantOrig)
+			}
+
+			wantConfig := `{"environmentLabel":"","theme":{"orgName":"Acme \u0026 Sons","logoUrl":"https://acme.example/logo.png","primaryColor":"#0f766e"}}`
+			if c.themed {
+				if !strings.Contains(body, wantConfig) {
+					t.Errorf("themed config missing:\n%s", body)
+				}
+				if !strings.Contains(body, "<title>Acme &amp; Sons</title>") {
+					t.Errorf("title not replaced:\n%s", body)
+				}
+			} else if strings.Contains(body, `"theme"`) || !strings.Contains(body, "<title>TrakRF") {
+				t.Errorf("unthemed load was themed:\n%s", body)
+			}
+		})
+	}
+}
+
+func TestServeSPA_CustomDomainThemesByHostOrg(t *testing.T) {
+	themes := &fakeThemes{apps: map[string]*organization.BrandedApp{"acme": {OrgName: "Acme"}}}
+	h := newTestHandler("", themedIndexHTML).WithThemes(themes, "https://app.trakrf.id")
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "track.acme.example"
+	var rec *httptest.ResponseRecorder
+	middleware.HostOrg(hostOrgs{"track.acme.example": {OrgID: 7, Identifier: "acme"}}, applinks.Config{BaseURL: "https://app.trakrf.id"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec = httptest.NewRecorder()
+			h.ServeSPA(rec, r, "frontend/dist/index.html")
+		})).ServeHTTP(httptest.NewRecorder(), req)
+
+	if themes.gotIdentifier != "acme" || themes.gotOrigin != "" {
+		t.Errorf("lookup = (%q, %q), want (\"acme\", \"\")", themes.gotIdentifier, themes.gotOrigin)
+	}
+	if !strings.Contains(rec.Body.String(), "<title>Acme</title>") {
+		t.Errorf("custom domain load was not themed:\n%s", rec.Body.String())
+	}
+}
+
+// hostOrgs is a test-only middleware.HostOrgResolver.
+type hostOrgs map[string]*organization.HostOrg
+
+func (m hostOrgs) ResolveCustomDomain(_ context.Context, hostname string) (*organization.HostOrg, error) {
+	return m[hostname], nil
+}
+
+func (m hostOrgs) ResolveOrgSubdomain(_ context.Context, identifier string) (*organization.HostOrg, error) {
+	return nil, nil
+}
+
+func TestServeSPA_ThemeLookupErrorServesDefault(t *testing.T) {
+	rec := serveThemed(&fakeThemes{err: errors.New("db down")}, "acme.app.trakrf.id", "/")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, `{"environmentLabel":""}`) {
+		t.Errorf("expected default config, got:\n%s", body)
+	}
+}
+
+func TestServeSPA_ThemedConfigHashedIntoCSP(t *testing.T) {
+	h := newTestHandler("", themedIndexHTML).WithThemes(&fakeThemes{apps: map[string]*organization.BrandedApp{
+		"acme": {OrgName: "Acme"}}}, "https://app.trakrf.id")
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Security-Policy", "script-src 'self'")
+	h.ServeSPA(rec, httptest.NewRequest(http.MethodGet, "/o/acme/", nil), "frontend/dist/index.html")
+
+	script := inlineScriptPattern.FindStringSubmatch(rec.Body.String())
+	if script == nil || !strings.Contains(script[1], `"orgName":"Acme"`) {
+		t.Fatalf("themed config script missing:\n%s", rec.Body.String())
+	}
+	sum := sha256.Sum256([]byte(script[1]))
+	want := "script-src 'self' 'sha256-" + base64.StdEncoding.EncodeToString(sum[:]) + "'"
+	if got := rec.Header().Get("Content-Security-Policy"); got != want {
+		t.Errorf("CSP\nwant: %s\ngot:  %s", want, got)
+	}
+}
+
+func serveAsset(h *Handler, path, acceptEncoding string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	h.ServeFrontend(rec, req)
+	return rec
+}
+
+func TestServeFrontend_CacheControl(t *testing.T) {
+	h := NewHandler(fstest.MapFS{
+		"frontend/dist/index.html":                      &fstest.MapFile{Data: []byte(testIndexHTML)},
+		"frontend/dist/version.json":                    &fstest.MapFile{Data: []byte(`{}`)},
+		"frontend/dist/logo.png":                        &fstest.MapFile{Data: []byte("png")},
+		"frontend/dist/assets/index-BxT3k9aQ.js":        &fstest.MapFile{Data: []byte("js")},
+		"frontend/dist/assets/react-vendor-C1xq_0Zp.js": &fstest.MapFile{Data: []byte("js")},
+		"frontend/dist/assets/mock-component.js":        &fstest.MapFile{Data: []byte("js")},
+	}, "frontend/dist", "")
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/", "no-cache, no-store, must-revalidate"},
+		{"/version.json", "no-cache, no-store, must-revalidate"},
+		{"/assets/index-BxT3k9aQ.js", "public, max-age=31536000, immutable"},
+		{"/assets/react-vendor-C1xq_0Zp.js", "public, max-age=31536000, immutable"},
+		{"/assets/mock-component.js", "public, max-age=3600"},
+		{"/logo.png", "public, max-age=3600"},
+	}
+	for _, tt := range tests {
+		if got := serveAsset(h, tt.path, "").Header().Get("Cache-Control"); got != tt.want {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestServeFrontend_ContentTypes(t *testing.T) {
+	h := NewHandler(fstest.MapFS{
+		"frontend/dist/manifest.webmanifest":         &fstest.MapFile{Data: []byte(`{"name":"TrakRF"}`)},
+		"frontend/dist/assets/decoder-0123abcd.wasm": &fstest.MapFile{Data: []byte("\x00asm")},
+	}, "frontend/dist", "")
+
+	for path, want := range map[string]string{
+		"/manifest.webmanifest":         "application/manifest+json",
+		"/assets/decoder-0123abcd.wasm": "application/wasm",
+	} {
+		rec := serveAsset(h, path, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d", path, rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != want {
+			t.Errorf("%s: Content-Type = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestServeFrontend_Precompressed(t *testing.T) {
+	h := NewHandler(fstest.MapFS{
+		"frontend/dist/assets/index-BxT3k9aQ.js":    &fstest.MapFile{Data: []byte("plain")},
+		"frontend/dist/assets/index-BxT3k9aQ.js.br": &fstest.MapFile{Data: []byte("brotli")},
+		"frontend/dist/assets/index-BxT3k9aQ.js.gz": &fstest.MapFile{Data: []byte("gzip")},
+		"frontend/dist/assets/style-0123abcd.css":   &fstest.MapFile{Data: []byte("css")},
+	}, "frontend/dist", "")
+
+	tests := []struct {
+		name, path, accept     string
+		wantBody, wantEncoding string
+		wantVary               bool
+	}{
+		{"prefers brotli", "/assets/index-BxT3k9aQ.js", "gzip, deflate, br", "brotli", "br", true},
+		{"falls back to gzip", "/assets/index-BxT3k9aQ.js", "gzip", "gzip", "gzip", true},
+		{"q=0 refuses an encoding", "/assets/index-BxT3k9aQ.js", "br;q=0, gzip;q=0.5", "gzip", "gzip", true},
+		{"identity when nothing accepted", "/assets/index-BxT3k9aQ.js", "", "plain", "", true},
+		{"no siblings", "/assets/style-0123abcd.css", "br, gzip", "css", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serveAsset(h, tt.path, tt.accept)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d", rec.Code)
+			}
+			if got := rec.Body.String(); got != tt.wantBody {
+				t.Errorf("body = %q, want %q", got, tt.wantBody)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if got := rec.Header().Values("Vary"); (len(got) == 1 && got[0] == "Accept-Encoding") != tt.wantVary {
+				t.Errorf("Vary = %q, want Accept-Encoding: %v", got, tt.wantVary)
+			}
+			if !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/") {
+				t.Errorf("Content-Type = %q, want the uncompressed asset's type", rec.Header().Get("Content-Type"))
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "public, max-age=31536000, immutable" {
+				t.Errorf("Cache-Control = %q", got)
+			}
+		})
+	}
+}
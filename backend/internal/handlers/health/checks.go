@@ -0,0 +1,94 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Check statuses reported per dependency and for /readyz overall. Degraded
+// means a non-critical dependency is down: the pod still takes traffic, but
+// some features (email, live ingest) are impaired.
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+// checkTimeout bounds each readiness check so one hung dependency cannot push
+// the probe past the kubelet's timeoutSeconds.
+const checkTimeout = 2 * time.Second
+
+// CheckFunc probes one dependency; a nil return means healthy.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name     string
+	critical bool
+	fn       CheckFunc
+}
+
+// CheckResult is one dependency's entry in the /readyz?verbose=1 body.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Critical  bool    `json:"critical"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// ReadyzResponse is the /readyz?verbose=1 body.
+type ReadyzResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// AddCheck registers a readiness check. A failing critical check fails
+// /readyz with 503 so Kubernetes stops routing to the pod; a failing
+// non-critical one only marks it degraded.
+func (h *Handler) AddCheck(name string, critical bool, fn CheckFunc) {
+	h.checks = append(h.checks, check{name: name, critical: critical, fn: fn})
+}
+
+// runChecks runs every registered check concurrently and folds the results
+// into an overall status.
+func (h *Handler) runChecks(ctx context.Context) ReadyzResponse {
+	resp := ReadyzResponse{Status: StatusOK, Checks: make(map[string]CheckResult, len(h.checks))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func(c check) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			start := time.Now()
+			err := c.fn(cctx)
+			res := CheckResult{
+				Status:    StatusOK,
+				Critical:  c.critical,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				res.Status = StatusUnavailable
+				res.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			resp.Checks[c.name] = res
+			switch {
+			case err == nil:
+			case c.critical:
+				resp.Status = StatusUnavailable
+			case resp.Status == StatusOK:
+				resp.Status = StatusDegraded
+			}
+		}(c)
+	}
+	wg.Wait()
+	return resp
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/internal/storage"
 )
 
 type Response struct {
@@ -29,55 +30,98 @@ type Response struct {
 	// mirrors BuildTime — the distinct json name is the contract BB
 	// tooling watches for deploy-lag detection.
 	SpecRefreshedAt string `json:"spec_refreshed_at"`
+	// Pool is the database connection-pool snapshot; omitted when the handler
+	// has no pool (unit tests).
+	Pool *storage.PoolStats `json:"pool,omitempty"`
+}
+
+// HealthzVerboseResponse is the /healthz?verbose=1 body: the liveness verdict
+// plus the connection-pool snapshot, for operators diagnosing saturation.
+type HealthzVerboseResponse struct {
+	Status string             `json:"status"`
+	Pool   *storage.PoolStats `json:"pool,omitempty"`
 }
 
 type Handler struct {
 	db        *pgxpool.Pool
 	info      buildinfo.Info
 	startTime time.Time
+	checks    []check
 }
 
+// NewHandler builds the health handler. A non-nil db registers the critical
+// "database" readiness check; callers add the rest with AddCheck.
 func NewHandler(db *pgxpool.Pool, info buildinfo.Info, startTime time.Time) *Handler {
-	return &Handler{
+	h := &Handler{
 		db:        db,
 		info:      info,
 		startTime: startTime,
 	}
+	if db != nil {
+		h.AddCheck("database", true, db.Ping)
+	}
+	return h
 }
 
-// Healthz is the liveness probe endpoint. Stays plaintext "ok" — K8s probes
+// Healthz is the liveness probe endpoint, also served as /livez. It checks
+// nothing beyond the process answering, so a dependency outage never gets
+// the pod restarted — that is /readyz's job. Stays plaintext "ok" — K8s probes
 // don't parse bodies and the build metadata lives on /health instead.
+// ?verbose=1 opts into a JSON body carrying the connection-pool stats; probes
+// never send it, so their contract is unchanged.
 func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
+	if v := r.URL.Query().Get("verbose"); v == "1" || v == "true" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(HealthzVerboseResponse{
+			Status: "ok",
+			Pool:   storage.NewPoolStats(h.db),
+		})
+		return
+	}
+
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("ok"))
 }
 
-// Readyz is the readiness probe endpoint.
+// Readyz is the readiness probe endpoint. It runs every registered check and
+// answers 503 when a critical one fails; a failing non-critical check leaves
+// the pod ready but reports "degraded". The body is the plaintext overall
+// status for probes, or ?verbose=1 for JSON with per-check status and latency.
 func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
-	defer cancel()
+	resp := h.runChecks(r.Context())
+	for name, res := range resp.Checks {
+		if res.Status != StatusOK {
+			slog.Error("Readiness check failed", "check", name, "critical", res.Critical, "error", res.Error)
+		}
+	}
+
+	code := http.StatusOK
+	if resp.Status == StatusUnavailable {
+		code = http.StatusServiceUnavailable
+	}
 
-	if err := h.db.Ping(ctx); err != nil {
-		slog.Error("Readiness check failed", "error", err)
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write([]byte("database unavailable"))
+	if v := r.URL.Query().Get("verbose"); v == "1" || v == "true" {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(resp)
 		return
 	}
 
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("ok"))
+	w.WriteHeader(code)
+	w.Write([]byte(resp.Status))
 }
 
 // @Summary Health check
@@ -116,6 +160,7 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 		Uptime:          uptime.String(),
 		Database:        dbStatus,
 		SpecRefreshedAt: h.info.BuildTime,
+		Pool:            storage.NewPoolStats(h.db),
 	}
 
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
@@ -125,6 +170,7 @@ func (h *Handler) Health(w http.ResponseWriter, r *http.Request) {
 
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Get("/healthz", h.Healthz)
+	r.Get("/livez", h.Healthz)
 	r.Get("/readyz", h.Readyz)
 	r.Get("/health", h.Health)
 	// /health.json is the canonical curl-able platform health surface; the
@@ -1,7 +1,9 @@
 package health
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -141,3 +143,141 @@ func TestHealthz_ReturnsPlainOK(t *testing.T) {
 		t.Errorf("content-type = %q, want text/plain", ct)
 	}
 }
+
+// TestHealthz_VerboseReturnsJSON covers the operator opt-in: ?verbose=1 swaps
+// the plaintext body for JSON carrying pool stats. With a nil pool (unit
+// tests) the pool block is omitted rather than serialized as null.
+func TestHealthz_VerboseReturnsJSON(t *testing.T) {
+	h := NewHandler(nil, buildinfo.Info{}, time.Now())
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz?verbose=1", nil)
+	rec := httptest.NewRecorder()
+	h.Healthz(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("content-type = %q, want application/json", ct)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp["status"] != "ok" {
+		t.Errorf("status = %v, want ok", resp["status"])
+	}
+	if _, ok := resp["pool"]; ok {
+		t.Errorf("pool present with nil db; want omitted")
+	}
+}
+
+// TestLivez_AliasesHealthz keeps /livez on the plaintext liveness contract.
+func TestLivez_AliasesHealthz(t *testing.T) {
+	h := NewHandler(nil, buildinfo.Info{}, time.Now())
+	h.AddCheck("email", true, func(context.Context) error { return errors.New("down") })
+
+	r := chi.NewRouter()
+	h.RegisterRoutes(r)
+
+	req := httptest.NewRequest(http.MethodGet, "/livez", nil)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200 (liveness must ignore readiness checks)", rec.Code)
+	}
+	if body := rec.Body.String(); body != "ok" {
+		t.Errorf("body = %q, want %q", body, "ok")
+	}
+}
+
+// TestReadyz_OverallStatus covers how per-check results fold into the probe
+// verdict: any critical failure is 503, non-critical failures only degrade.
+func TestReadyz_OverallStatus(t *testing.T) {
+	ok := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("down") }
+
+	tests := []struct {
+		name     string
+		critical CheckFunc
+		optional CheckFunc
+		wantCode int
+		wantBody string
+	}{
+		{"all ok", ok, ok, http.StatusOK, StatusOK},
+		{"non-critical down", ok, fail, http.StatusOK, StatusDegraded},
+		{"critical down", fail, ok, http.StatusServiceUnavailable, StatusUnavailable},
+		{"both down", fail, fail, http.StatusServiceUnavailable, StatusUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHandler(nil, buildinfo.Info{}, time.Now())
+			h.AddCheck("migrations", true, tt.critical)
+			h.AddCheck("mqtt", false, tt.optional)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rec := httptest.NewRecorder()
+			h.Readyz(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if body := rec.Body.String(); body != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+// TestReadyz_VerboseReportsChecks confirms ?verbose=1 carries each check's
+// status, criticality, latency, and error.
+func TestReadyz_VerboseReportsChecks(t *testing.T) {
+	h := NewHandler(nil, buildinfo.Info{}, time.Now())
+	h.AddCheck("migrations", true, func(context.Context) error { return nil })
+	h.AddCheck("email", false, func(context.Context) error { return errors.New("RESEND_API_KEY not set") })
+
+	req := httptest.NewRequest(http.MethodGet, "/readyz?verbose=1", nil)
+	rec := httptest.NewRecorder()
+	h.Readyz(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("content-type = %q, want application/json", ct)
+	}
+	var resp ReadyzResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Status != StatusDegraded {
+		t.Errorf("status = %q, want %q", resp.Status, StatusDegraded)
+	}
+	if got := resp.Checks["migrations"]; got.Status != StatusOK || !got.Critical {
+		t.Errorf("migrations = %+v, want ok and critical", got)
+	}
+	if got := resp.Checks["email"]; got.Status != StatusUnavailable || got.Critical || got.Error != "RESEND_API_KEY not set" {
+		t.Errorf("email = %+v, want unavailable, non-critical, with error", got)
+	}
+}
+
+// TestReadyz_CheckTimeout ensures a hung dependency is cut off at the
+// per-check timeout instead of stalling the probe.
+func TestReadyz_CheckTimeout(t *testing.T) {
+	h := NewHandler(nil, buildinfo.Info{}, time.Now())
+	h.AddCheck("hung", true, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "/readyz", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	h.Readyz(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+}
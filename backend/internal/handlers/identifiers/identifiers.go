@@ -0,0 +1,70 @@
+// Package identifiers serves the assignment history of tags (identifiers):
+// which asset or location a tag value was on, and when, across removals and
+// recycling onto new assets.
+package identifiers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// IdentifierStorage is the storage surface the handler needs (mockable).
+type IdentifierStorage interface {
+	GetTagHistory(ctx context.Context, orgID, tagID int) ([]shared.TagAssignment, bool, error)
+}
+
+type Handler struct {
+	storage IdentifierStorage
+}
+
+func NewHandler(storage IdentifierStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the identifier routes onto r. Mount inside the
+// session-auth (middleware.Auth) group.
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/api/v1/identifiers/{identifier_id}/history", h.History)
+}
+
+// @Summary  Identifier assignment history
+// @Description Every asset or location the identifier's value has been assigned to, oldest first. A tag recycled onto a new asset is a new identifier id with the same value, so the history of either id covers both; removed identifiers keep their history. Scans stay attributed to the asset the value was on when they were recorded.
+// @Tags     identifiers,internal
+// @ID       identifiers.history
+// @Produce  json
+// @Param    identifier_id path int true "Identifier (tag) id"
+// @Success  200 {object} map[string]any "data: []shared.TagAssignment"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/identifiers/{identifier_id}/history [get]
+func (h *Handler) History(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, err := httputil.ParseSurrogateID("identifier_id", chi.URLParam(r, "identifier_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	history, found, err := h.storage.GetTagHistory(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "identifier not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": history})
+}
@@ -0,0 +1,68 @@
+package identifiers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+type mockIdentifierStorage struct {
+	orgID int
+}
+
+func (m *mockIdentifierStorage) GetTagHistory(ctx context.Context, orgID, tagID int) ([]shared.TagAssignment, bool, error) {
+	m.orgID = orgID
+	if tagID != 7 && tagID != 8 {
+		return nil, false, nil
+	}
+	removed := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	oldAsset, newAsset := 100, 200
+	return []shared.TagAssignment{
+		{TagID: 7, TagType: "rfid", Value: "E280", AssetID: &oldAsset, AssignedAt: removed.AddDate(0, -2, 0), UnassignedAt: &removed},
+		{TagID: 8, TagType: "rfid", Value: "E280", AssetID: &newAsset, AssignedAt: removed.AddDate(0, 0, 1)},
+	}, true, nil
+}
+
+func getHistory(m *mockIdentifierStorage, target string) *httptest.ResponseRecorder {
+	r := chi.NewRouter()
+	NewHandler(m).RegisterRoutes(r)
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	orgID := 42
+	claims := &jwt.Claims{UserID: 1, Email: "user@example.com", CurrentOrgID: &orgID}
+	req = req.WithContext(context.WithValue(req.Context(), middleware.UserClaimsKey, claims))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHistory(t *testing.T) {
+	m := &mockIdentifierStorage{}
+	w := getHistory(m, "/api/v1/identifiers/7/history")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 42, m.orgID)
+
+	var body struct {
+		Data []shared.TagAssignment `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data, 2, "the recycled tag's assignments read as one history")
+	assert.Equal(t, 100, *body.Data[0].AssetID)
+	assert.NotNil(t, body.Data[0].UnassignedAt)
+	assert.Equal(t, 200, *body.Data[1].AssetID)
+	assert.Nil(t, body.Data[1].UnassignedAt)
+}
+
+func TestHistory_NotFoundAndBadID(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, getHistory(&mockIdentifierStorage{}, "/api/v1/identifiers/9/history").Code)
+	assert.Equal(t, http.StatusBadRequest, getHistory(&mockIdentifierStorage{}, "/api/v1/identifiers/abc/history").Code)
+}
@@ -0,0 +1,212 @@
+// Package integrations serves simplified polling triggers for no-code
+// automation tools (Zapier, Make). Each trigger is a newest-first list of flat
+// items with a unique id, plus an opaque cursor: pass the last next_cursor back
+// as ?cursor= and only items after it are returned.
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/integration"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const (
+	defaultTriggerLimit = 50
+	maxTriggerLimit     = 100
+)
+
+// TriggerStorage is the storage surface the triggers need (mockable).
+type TriggerStorage interface {
+	ListNewAssets(ctx context.Context, orgID int, after *integration.Cursor, limit int) ([]integration.NewAssetItem, error)
+	ListMovedAssets(ctx context.Context, orgID int, after *integration.Cursor, limit int) ([]integration.MovedAssetItem, error)
+}
+
+type Handler struct {
+	storage TriggerStorage
+}
+
+func NewHandler(storage TriggerStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// NewAssetsResponse is the envelope returned by the new-assets trigger.
+type NewAssetsResponse struct {
+	Data       []integration.NewAssetItem `json:"data"`
+	NextCursor string                     `json:"next_cursor" example:"MTc2MDYyNjgwMDAwMDAwMDAwMDo0Mg"`
+}
+
+// MovedAssetsResponse is the envelope returned by the moved-assets trigger.
+type MovedAssetsResponse struct {
+	Data       []integration.MovedAssetItem `json:"data"`
+	NextCursor string                       `json:"next_cursor" example:"MTc2MDYyNjgwMDAwMDAwMDAwMDo0Mg"`
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// encodeCursor renders (at, id) as the opaque cursor string.
+func encodeCursor(at time.Time, id int) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(strconv.FormatInt(at.UnixNano(), 10) + ":" + strconv.Itoa(id)))
+}
+
+func decodeCursor(s string) (*integration.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, errInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 {
+		return nil, errInvalidCursor
+	}
+	return &integration.Cursor{At: time.Unix(0, n).UTC(), ID: i}, nil
+}
+
+// parseTriggerQuery reads ?limit= and ?cursor=, rejecting anything else so a
+// mistyped parameter is not silently ignored. Writes a 400 and returns false
+// on bad input.
+func parseTriggerQuery(w http.ResponseWriter, r *http.Request, reqID string) (*integration.Cursor, int, bool) {
+	invalid := func(field, msg string) (*integration.Cursor, int, bool) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: field, Code: "invalid_value", Message: msg,
+		}})
+		return nil, 0, false
+	}
+
+	q := r.URL.Query()
+	for key := range q {
+		if key != "limit" && key != "cursor" {
+			return invalid(key, fmt.Sprintf("unknown query parameter %q; supported: limit, cursor", key))
+		}
+	}
+
+	limit := defaultTriggerLimit
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxTriggerLimit {
+			return invalid("limit", fmt.Sprintf("limit must be an integer between 1 and %d", maxTriggerLimit))
+		}
+		limit = n
+	}
+
+	var after *integration.Cursor
+	if v := q.Get("cursor"); v != "" {
+		c, err := decodeCursor(v)
+		if err != nil {
+			return invalid("cursor", "cursor must be a next_cursor value returned by this trigger")
+		}
+		after = c
+	}
+	return after, limit, true
+}
+
+// nextCursor is the cursor after the newest item, or the request's own cursor
+// when nothing new arrived so the caller can keep polling from the same spot.
+func nextCursor(r *http.Request, newest func() (time.Time, int), n int) string {
+	if n == 0 {
+		return r.URL.Query().Get("cursor")
+	}
+	at, id := newest()
+	return encodeCursor(at, id)
+}
+
+// @Summary      Zapier trigger: new assets
+// @Description  **Required scope:** `assets:read`
+// @Description
+// @Description  Polling trigger for automation tools. Returns assets created after `cursor`, newest first. Without a cursor, returns the most recently created assets (use this as the trigger's sample). With a cursor, returns the oldest `limit` assets after it, so polling repeatedly with each `next_cursor` walks a backlog without gaps. `next_cursor` echoes the request cursor when nothing new arrived. Each item's `id` is the asset id. Use GET /api/v1/orgs/me as the connection test.
+// @Tags         integrations,public
+// @ID           integrations.zapier.new_assets
+// @Produce      json
+// @Param        limit  query int    false "max 100" default(50) minimum(1) maximum(100)
+// @Param        cursor query string false "next_cursor from a previous poll"
+// @Success      200  {object}  integrations.NewAssetsResponse
+// @Failure      400  {object}  modelerrors.ErrorResponse "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      429  {object}  modelerrors.ErrorResponse "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[assets:read]
+// @Router       /api/v1/integrations/zapier/new-assets [get]
+func (h *Handler) NewAssets(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	after, limit, ok := parseTriggerQuery(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	items, err := h.storage.ListNewAssets(r.Context(), orgID, after, limit)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, NewAssetsResponse{
+		Data: items,
+		NextCursor: nextCursor(r, func() (time.Time, int) {
+			return items[0].CreatedAt, items[0].ID
+		}, len(items)),
+	})
+}
+
+// @Summary      Zapier trigger: moved assets
+// @Description  **Required scope:** `tracking:read`
+// @Description
+// @Description  Polling trigger for automation tools. Returns asset moves after `cursor`, newest first. A move is a scan that places an asset at a different location than its previous located scan; an asset's first sighting counts as a move with null `from_*` fields. Without a cursor, returns the most recent moves from the last 7 days (use this as the trigger's sample). With a cursor, returns the oldest `limit` moves after it; `next_cursor` echoes the request cursor when nothing new arrived. Each item's `id` is unique per move.
+// @Tags         integrations,public
+// @ID           integrations.zapier.moved_assets
+// @Produce      json
+// @Param        limit  query int    false "max 100" default(50) minimum(1) maximum(100)
+// @Param        cursor query string false "next_cursor from a previous poll"
+// @Success      200  {object}  integrations.MovedAssetsResponse
+// @Failure      400  {object}  modelerrors.ErrorResponse "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      429  {object}  modelerrors.ErrorResponse "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[tracking:read]
+// @Router       /api/v1/integrations/zapier/moved-assets [get]
+func (h *Handler) MovedAssets(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	after, limit, ok := parseTriggerQuery(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	items, err := h.storage.ListMovedAssets(r.Context(), orgID, after, limit)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, MovedAssetsResponse{
+		Data: items,
+		NextCursor: nextCursor(r, func() (time.Time, int) {
+			return items[0].MovedAt, items[0].AssetID
+		}, len(items)),
+	})
+}
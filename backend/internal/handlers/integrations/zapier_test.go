@@ -0,0 +1,96 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/integration"
+)
+
+type mockTriggerStorage struct {
+	newAssets []integration.NewAssetItem
+	gotAfter  *integration.Cursor
+	gotLimit  int
+}
+
+func (m *mockTriggerStorage) ListNewAssets(ctx context.Context, orgID int, after *integration.Cursor, limit int) ([]integration.NewAssetItem, error) {
+	m.gotAfter, m.gotLimit = after, limit
+	return m.newAssets, nil
+}
+
+func (m *mockTriggerStorage) ListMovedAssets(ctx context.Context, orgID int, after *integration.Cursor, limit int) ([]integration.MovedAssetItem, error) {
+	m.gotAfter, m.gotLimit = after, limit
+	return []integration.MovedAssetItem{}, nil
+}
+
+func apiKeyRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	return req.WithContext(middleware.WithAPIKeyPrincipalForTest(req.Context(), &middleware.APIKeyPrincipal{
+		OrgID: 42, Scopes: []string{"assets:read", "tracking:read"}, JTI: "jti-zap",
+	}))
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	at := time.Date(2026, 10, 16, 15, 4, 5, 123456000, time.UTC)
+	c, err := decodeCursor(encodeCursor(at, 42))
+	require.NoError(t, err)
+	assert.True(t, c.At.Equal(at))
+	assert.Equal(t, 42, c.ID)
+
+	for _, bad := range []string{"%%%", "bm9jb2xvbg", "YWJjOjQy", "MTIzOmFiYw"} {
+		_, err := decodeCursor(bad)
+		assert.ErrorIs(t, err, errInvalidCursor, bad)
+	}
+}
+
+func TestNewAssets_CursorAdvancesToNewest(t *testing.T) {
+	newest := time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)
+	mock := &mockTriggerStorage{newAssets: []integration.NewAssetItem{
+		{ID: 9, CreatedAt: newest},
+		{ID: 8, CreatedAt: newest.Add(-time.Minute)},
+	}}
+	w := httptest.NewRecorder()
+	NewHandler(mock).NewAssets(w, apiKeyRequest("/api/v1/integrations/zapier/new-assets?limit=2"))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Nil(t, mock.gotAfter)
+	assert.Equal(t, 2, mock.gotLimit)
+
+	var resp NewAssetsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, encodeCursor(newest, 9), resp.NextCursor)
+
+	// Polling with that cursor hands it to storage; an empty page echoes it.
+	mock.newAssets = []integration.NewAssetItem{}
+	w = httptest.NewRecorder()
+	NewHandler(mock).NewAssets(w, apiKeyRequest("/api/v1/integrations/zapier/new-assets?cursor="+resp.NextCursor))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NotNil(t, mock.gotAfter)
+	assert.Equal(t, 9, mock.gotAfter.ID)
+	assert.Equal(t, defaultTriggerLimit, mock.gotLimit)
+
+	var empty NewAssetsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &empty))
+	assert.Equal(t, resp.NextCursor, empty.NextCursor)
+}
+
+func TestTriggers_RejectBadQuery(t *testing.T) {
+	for _, target := range []string{
+		"/api/v1/integrations/zapier/moved-assets?limit=0",
+		"/api/v1/integrations/zapier/moved-assets?limit=101",
+		"/api/v1/integrations/zapier/moved-assets?cursor=garbage!",
+		"/api/v1/integrations/zapier/moved-assets?offset=10",
+	} {
+		w := httptest.NewRecorder()
+		NewHandler(&mockTriggerStorage{}).MovedAssets(w, apiKeyRequest(target))
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+	}
+}
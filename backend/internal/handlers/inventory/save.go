@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
@@ -12,6 +13,7 @@ import (
 	"github.com/trakrf/platform/backend/internal/middleware"
 	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/scanguard"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
 )
@@ -32,12 +34,14 @@ type InventoryStorage interface {
 // Handler handles inventory-related API requests
 type Handler struct {
 	storage InventoryStorage
+	budget  *scanguard.Budget
 }
 
 // NewHandler creates a new inventory handler
 func NewHandler(storage InventoryStorage) *Handler {
 	return &Handler{
 		storage: storage,
+		budget:  scanguard.NewBudget(scanguard.DefaultConfig()),
 	}
 }
 
@@ -49,6 +53,15 @@ func NewHandler(storage InventoryStorage) *Handler {
 type SaveRequest struct {
 	LocationIdentifier *string  `json:"location_identifier" validate:"required,min=1,max=255" example:"WH-01"`
 	AssetIdentifiers   []string `json:"asset_identifiers" validate:"required,min=1,dive,min=1,max=255" example:"ASSET-0001"`
+	// Counts records consumables counted at the location during the same
+	// cycle count. Each replaces the consumable's stock level there.
+	Counts []CountInput `json:"counts,omitempty" validate:"omitempty,max=1000,dive"`
+}
+
+// CountInput is one counted consumable in a save request.
+type CountInput struct {
+	AssetIdentifier string `json:"asset_identifier" validate:"required,min=1,max=255" example:"GLOVES-M"`
+	Quantity        *int   `json:"quantity" validate:"required,min=0" example:"40"`
 }
 
 // SaveResponse is the typed envelope returned on success by POST /api/v1/inventory/save.
@@ -58,16 +71,17 @@ type SaveResponse struct {
 
 // Save handles POST /api/v1/inventory/save
 // @Summary Save inventory scans
-// @Description Persist scanned RFID assets to the asset_scans hypertable
+// @Description Persist scanned RFID assets to the asset_scans hypertable. `counts` optionally records consumables counted at the same location; each count replaces the consumable's stock level there and may open or resolve its stock alert. Session callers must present a registered reader credential in the X-Reader-Key header unless the org has turned on session scans (scan-settings). Unknown asset identifiers reject the request and count against the unknown-tag budget of the reader, API key or user that submitted them; one that runs through it gets 429.
 // @Tags inventory,internal
 // @ID inventory.save
 // @Accept json
 // @Produce json
 // @Param request body SaveRequest true "Save request with location and asset identifiers"
+// @Param X-Reader-Key header string false "Reader credential of a registered scan device"
 // @Success 201 {object} inventory.SaveResponse
 // @Failure 400 {object} modelerrors.ErrorResponse "Invalid request"
 // @Failure 401 {object} modelerrors.ErrorResponse "Unauthorized"
-// @Failure 403 {object} modelerrors.ErrorResponse "Location or assets not owned by org"
+// @Failure 403 {object} modelerrors.ErrorResponse "Location or assets not owned by org, or missing or invalid reader credential"
 // @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
 // @Failure 429 {object} modelerrors.ErrorResponse "rate_limited"
 // @Header  429 {integer} Retry-After           "Seconds to wait before retrying"
@@ -112,8 +126,12 @@ func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
 	}
 	locationID := loc.ID
 
-	// Resolve asset_identifiers → numeric IDs (one query).
-	resolved, err := h.storage.GetAssetIDsByExternalKeys(r.Context(), orgID, request.AssetIdentifiers)
+	// Resolve asset_identifiers and counted identifiers → numeric IDs (one query).
+	idents := slices.Clone(request.AssetIdentifiers)
+	for _, c := range request.Counts {
+		idents = append(idents, c.AssetIdentifier)
+	}
+	resolved, err := h.storage.GetAssetIDsByExternalKeys(r.Context(), orgID, idents)
 	if err != nil {
 		httputil.RespondStorageError(w, r, err, requestID)
 		return
@@ -127,8 +145,28 @@ func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
 			missing = append(missing, ident)
 		}
 	}
-	if len(missing) > 0 {
-		fields := make([]modelerrors.FieldError, 0, len(missing))
+	counts := make([]storage.StockCount, 0, len(request.Counts))
+	var missingCounts []string
+	for _, c := range request.Counts {
+		if id, ok := resolved[c.AssetIdentifier]; ok {
+			counts = append(counts, storage.StockCount{AssetID: id, Quantity: *c.Quantity})
+		} else {
+			missingCounts = append(missingCounts, c.AssetIdentifier)
+		}
+	}
+	source := middleware.GetScanSource(r).Label()
+	if ok, retryAfter := h.budget.Record(source, len(assetIDs)+len(counts), len(missing)+len(missingCounts)); !ok {
+		logger.Get().Warn().
+			Str("request_id", requestID).
+			Int("org_id", orgID).
+			Str("source", source).
+			Int("unknown", len(missing)+len(missingCounts)).
+			Msg("unknown-tag budget exhausted; inventory save throttled")
+		httputil.Respond429(w, r, retryAfter, requestID)
+		return
+	}
+	if len(missing)+len(missingCounts) > 0 {
+		fields := make([]modelerrors.FieldError, 0, len(missing)+len(missingCounts))
 		for _, m := range missing {
 			fields = append(fields, modelerrors.FieldError{
 				Field:   "asset_identifiers",
@@ -136,6 +174,13 @@ func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
 				Message: fmt.Sprintf("asset_identifier %q not found", m),
 			})
 		}
+		for _, m := range missingCounts {
+			fields = append(fields, modelerrors.FieldError{
+				Field:   "counts",
+				Code:    "invalid_value",
+				Message: fmt.Sprintf("asset_identifier %q not found", m),
+			})
+		}
 		httputil.WriteValidationError(w, r, requestID, fields)
 
 		return
@@ -144,6 +189,8 @@ func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
 	result, err := h.storage.SaveInventoryScans(r.Context(), orgID, storage.SaveInventoryRequest{
 		LocationID: locationID,
 		AssetIDs:   assetIDs,
+		Counts:     counts,
+		UserID:     userID(r),
 	})
 
 	if err != nil {
@@ -164,6 +211,8 @@ func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
 				Ints("missing_asset_ids", accessErr.MissingAssetIDs).
 				Ints("soft_deleted_asset_ids", accessErr.SoftDeletedAssetIDs).
 				Ints("cross_org_asset_ids", accessErr.CrossOrgAssetIDs).
+				Ints("out_of_validity_asset_ids", accessErr.OutOfValidityAssetIDs).
+				Ints("disposed_asset_ids", accessErr.DisposedAssetIDs).
 				Str("request_id", requestID).
 				Str("error", accessErr.Error()).
 				Msg("Inventory save denied")
@@ -173,6 +222,14 @@ func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
 
 			return
 		}
+		if errors.Is(err, storage.ErrNotConsumable) {
+			httputil.WriteValidationError(w, r, requestID, []modelerrors.FieldError{{
+				Field:   "counts",
+				Code:    "invalid_value",
+				Message: "counts may only name consumable assets",
+			}})
+			return
+		}
 		httputil.RespondStorageError(w, r, err, requestID)
 		return
 	}
@@ -180,6 +237,14 @@ func (h *Handler) Save(w http.ResponseWriter, r *http.Request) {
 	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": result})
 }
 
+// userID is the signed-in user recorded on stock counts, nil for API keys.
+func userID(r *http.Request) *int {
+	if c := middleware.GetUserClaims(r); c != nil {
+		return &c.UserID
+	}
+	return nil
+}
+
 // RegisterRoutes is intentionally empty — POST /api/v1/inventory/save is
 // registered in internal/cmd/serve/router.go under the public write group
 // (EitherAuth + WriteAudit + RequireScope("scans:write")).
@@ -17,6 +17,8 @@ import (
 	"github.com/trakrf/platform/backend/internal/middleware"
 	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/ratelimit"
+	"github.com/trakrf/platform/backend/internal/scanguard"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/util/jwt"
 )
@@ -25,6 +27,7 @@ import (
 type mockInventoryStorage struct {
 	saveResult *storage.SaveInventoryResult
 	saveError  error
+	saved      *storage.SaveInventoryRequest
 
 	// Identifier resolution stubs.
 	locationByIdentifier      map[string]*location.LocationWithParent
@@ -35,6 +38,7 @@ type mockInventoryStorage struct {
 }
 
 func (m *mockInventoryStorage) SaveInventoryScans(ctx context.Context, orgID int, req storage.SaveInventoryRequest) (*storage.SaveInventoryResult, error) {
+	m.saved = &req
 	return m.saveResult, m.saveError
 }
 
@@ -684,4 +688,98 @@ func TestSave_IdentifierHappyPath_ResolvesAndSucceeds(t *testing.T) {
 	assert.Equal(t, 42, resp.Data.LocationID)
 }
 
+func TestSave_CountsResolvedAndPassedThrough(t *testing.T) {
+	mock := &mockInventoryStorage{
+		saveResult: &storage.SaveInventoryResult{Count: 1, LocationID: 42, Counted: 1},
+		locationByIdentifier: map[string]*location.LocationWithParent{
+			"WH-01": {LocationView: location.LocationView{Location: location.Location{ID: 42, ExternalKey: "WH-01"}}},
+		},
+		assetIDsByIdentifiers: map[string]int{"ASSET-1": 7, "GLOVES-M": 9},
+	}
+	handler := NewHandler(mock)
+	body := map[string]any{
+		"location_identifier": "WH-01",
+		"asset_identifiers":   []string{"ASSET-1"},
+		"counts":              []map[string]any{{"asset_identifier": "GLOVES-M", "quantity": 40}},
+	}
+	w := httptest.NewRecorder()
+	handler.Save(w, newTestRequest(t, body, 1))
+
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	require.NotNil(t, mock.saved)
+	assert.Equal(t, []storage.StockCount{{AssetID: 9, Quantity: 40}}, mock.saved.Counts)
+	require.NotNil(t, mock.saved.UserID)
+	assert.Equal(t, 1, *mock.saved.UserID)
+	assert.Contains(t, w.Body.String(), `"counted":1`)
+}
+
+func TestSave_CountsRejected(t *testing.T) {
+	cases := []struct {
+		name    string
+		counts  []map[string]any
+		saveErr error
+		want    string
+	}{
+		{"unknown asset", []map[string]any{{"asset_identifier": "GHOST", "quantity": 1}}, nil, "GHOST"},
+		{"negative quantity", []map[string]any{{"asset_identifier": "GLOVES-M", "quantity": -1}}, nil, "quantity"},
+		{"missing quantity", []map[string]any{{"asset_identifier": "GLOVES-M"}}, nil, "quantity"},
+		{"not consumable", []map[string]any{{"asset_identifier": "GLOVES-M", "quantity": 3}},
+			storage.ErrNotConsumable, "consumable"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mock := &mockInventoryStorage{
+				saveError: c.saveErr,
+				locationByIdentifier: map[string]*location.LocationWithParent{
+					"WH-01": {LocationView: location.LocationView{Location: location.Location{ID: 42, ExternalKey: "WH-01"}}},
+				},
+				assetIDsByIdentifiers: map[string]int{"ASSET-1": 7, "GLOVES-M": 9},
+			}
+			body := map[string]any{
+				"location_identifier": "WH-01",
+				"asset_identifiers":   []string{"ASSET-1"},
+				"counts":              c.counts,
+			}
+			w := httptest.NewRecorder()
+			NewHandler(mock).Save(w, newTestRequest(t, body, 1))
+
+			require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+			assert.Contains(t, w.Body.String(), c.want)
+		})
+	}
+}
+
 func ptr[T any](v T) *T { return &v }
+
+func TestSave_UnknownTagBudgetThrottlesReader(t *testing.T) {
+	mock := &mockInventoryStorage{
+		locationByIdentifier: map[string]*location.LocationWithParent{
+			"WH-01": {LocationView: location.LocationView{Location: location.Location{ID: 1, ExternalKey: "WH-01"}}},
+		},
+	}
+	handler := NewHandler(mock)
+	handler.budget = scanguard.NewBudget(ratelimit.Config{
+		RatePerMinute: 1,
+		Burst:         2,
+		IdleTTL:       time.Hour,
+		SweepInterval: time.Hour,
+		Clock:         ratelimit.NewFakeClock(time.Unix(1_700_000_000, 0)),
+	})
+	save := func() *httptest.ResponseRecorder {
+		req := newTestRequest(t, map[string]any{
+			"location_identifier": "WH-01",
+			"asset_identifiers":   []string{"PROBE-1", "PROBE-2"},
+		}, 1)
+		req = req.WithContext(middleware.WithScanSourceForTest(req.Context(), &middleware.ScanSource{DeviceID: 7}))
+		w := httptest.NewRecorder()
+		handler.Save(w, req)
+		return w
+	}
+
+	w := save()
+	assert.Equal(t, http.StatusBadRequest, w.Code, "unknown identifiers are rejected")
+	w = save()
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "the reader's unknown-tag budget is spent")
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.NotContains(t, w.Body.String(), "PROBE-1", "a throttled request reveals nothing")
+}
@@ -0,0 +1,252 @@
+// Package kiosk serves lobby and warehouse displays: admins mint long-lived
+// display tokens bound to one location, and a display holding one reads that
+// location's live inventory from the read-only /api/v1/kiosk surface without
+// a user session.
+package kiosk
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/kiosk"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/util/apisecret"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// KioskStorage is the storage surface the handler needs (mockable).
+type KioskStorage interface {
+	GetLocationByID(ctx context.Context, orgID, id int) (*location.Location, error)
+	CreateKioskToken(ctx context.Context, orgID, userID int, req kiosk.CreateRequest, tokenHash string) (*kiosk.Token, error)
+	ListKioskTokens(ctx context.Context, orgID int) ([]kiosk.Token, error)
+	RevokeKioskToken(ctx context.Context, orgID, id int) (bool, error)
+	GetKioskPrincipal(ctx context.Context, tokenHash string) (*kiosk.Principal, error)
+	GetKioskDashboard(ctx context.Context, p kiosk.Principal) (*kiosk.Dashboard, error)
+}
+
+type Handler struct {
+	storage KioskStorage
+}
+
+func NewHandler(storage KioskStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+type principalKey struct{}
+
+// RegisterRoutes wires token management onto r. Mount inside the session-auth
+// (middleware.Auth) group; minting and revoking display tokens is admin-only.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(admin).Get("/api/v1/kiosk-tokens", h.ListTokens)
+	r.With(admin).Post("/api/v1/kiosk-tokens", h.CreateToken)
+	r.With(admin).Delete("/api/v1/kiosk-tokens/{kiosk_token_id}", h.RevokeToken)
+}
+
+// RegisterKioskRoutes wires the display surface onto r. Mount outside every
+// auth group: the kiosk token is the only credential these routes accept.
+func (h *Handler) RegisterKioskRoutes(r chi.Router) {
+	r.With(h.requireKioskToken, middleware.ConditionalGET).Get("/api/v1/kiosk/dashboard", h.Dashboard)
+}
+
+// presentedToken reads the kiosk token from the Authorization header or, for
+// displays that can only be pointed at a URL, the token query parameter.
+func presentedToken(r *http.Request) string {
+	if v, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(v)
+	}
+	return r.URL.Query().Get("token")
+}
+
+// requireKioskToken resolves the presented kiosk token, answering 401 for a
+// missing, unknown or revoked one.
+func (h *Handler) requireKioskToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+		token := presentedToken(r)
+		if token == "" {
+			httputil.Respond401(w, r, "Kiosk token required", reqID)
+			return
+		}
+		p, err := h.storage.GetKioskPrincipal(r.Context(), apisecret.Hash(token))
+		if err != nil {
+			httputil.RespondStorageError(w, r, err, reqID)
+			return
+		}
+		if p == nil {
+			httputil.Respond401(w, r, "Invalid or revoked kiosk token", reqID)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, p)))
+	})
+}
+
+// @Summary  Kiosk dashboard
+// @Description For lobby and warehouse displays. Authenticate with a kiosk token, as `Authorization: Bearer <token>` or, for displays that can only open a URL, `?token=<token>`. Returns the token's location and the assets currently there (latest scan at that location), most recently seen first, at most 500. Poll it; an unchanged dashboard answers 304 to a matching If-None-Match.
+// @Tags     kiosk,internal
+// @ID       kiosk.dashboard
+// @Produce  json
+// @Param    token query string false "Kiosk token, when not sent as a Bearer header"
+// @Success  200 {object} map[string]any "data: kiosk.Dashboard"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Router   /api/v1/kiosk/dashboard [get]
+func (h *Handler) Dashboard(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	p, _ := r.Context().Value(principalKey{}).(*kiosk.Principal)
+	if p == nil {
+		httputil.Respond401(w, r, "Kiosk token required", reqID)
+		return
+	}
+	d, err := h.storage.GetKioskDashboard(r.Context(), *p)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "location not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary  Create a kiosk token
+// @Description Mints a long-lived, read-only display token for one of the org's locations. The token is returned once, in `token`; store it on the display. It only reads that location's dashboard and stays valid until revoked.
+// @Tags     kiosk,internal
+// @ID       kiosk.tokens.create
+// @Accept   json
+// @Produce  json
+// @Param    request body kiosk.CreateRequest true "Token"
+// @Success  201 {object} map[string]any "data: kiosk.CreateResponse"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/kiosk-tokens [post]
+func (h *Handler) CreateToken(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return
+	}
+
+	var req kiosk.CreateRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	loc, err := h.storage.GetLocationByID(r.Context(), orgID, req.LocationID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if loc == nil {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "location_id", Code: "invalid_value", Message: "location_id must be a location of this organization",
+		}})
+		return
+	}
+
+	secret, err := apisecret.Generate()
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	t, err := h.storage.CreateKioskToken(r.Context(), orgID, claims.UserID, req, apisecret.Hash(secret))
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": kiosk.CreateResponse{Token: *t, Secret: secret}})
+}
+
+// @Summary  List kiosk tokens
+// @Description The org's kiosk tokens, revoked ones included, newest first. Secrets are not returned.
+// @Tags     kiosk,internal
+// @ID       kiosk.tokens.list
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []kiosk.Token"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/kiosk-tokens [get]
+func (h *Handler) ListTokens(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	list, err := h.storage.ListKioskTokens(r.Context(), orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Revoke a kiosk token
+// @Description The display using it gets 401 from its next request.
+// @Tags     kiosk,internal
+// @ID       kiosk.tokens.revoke
+// @Param    kiosk_token_id path int true "Kiosk token id"
+// @Success  204
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/kiosk-tokens/{kiosk_token_id} [delete]
+func (h *Handler) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, err := httputil.ParseSurrogateID("kiosk_token_id", chi.URLParam(r, "kiosk_token_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	ok, err := h.storage.RevokeKioskToken(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !ok {
+		httputil.Respond404(w, r, "kiosk token not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,129 @@
+package kiosk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/models/kiosk"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/testutil"
+	"github.com/trakrf/platform/backend/internal/util/apisecret"
+)
+
+const goodToken = "trakrf_display"
+
+type mockKioskStorage struct {
+	createdHash string
+	dashboardOf *kiosk.Principal
+}
+
+func (m *mockKioskStorage) GetLocationByID(ctx context.Context, orgID, id int) (*location.Location, error) {
+	if id != 5 {
+		return nil, nil
+	}
+	return &location.Location{ID: 5, OrgID: orgID, Name: "Dock 4"}, nil
+}
+
+func (m *mockKioskStorage) CreateKioskToken(ctx context.Context, orgID, userID int, req kiosk.CreateRequest, tokenHash string) (*kiosk.Token, error) {
+	m.createdHash = tokenHash
+	return &kiosk.Token{ID: 9, Name: req.Name, LocationID: req.LocationID, LocationName: "Dock 4"}, nil
+}
+
+func (m *mockKioskStorage) ListKioskTokens(ctx context.Context, orgID int) ([]kiosk.Token, error) {
+	return []kiosk.Token{}, nil
+}
+
+func (m *mockKioskStorage) RevokeKioskToken(ctx context.Context, orgID, id int) (bool, error) {
+	return id == 9, nil
+}
+
+func (m *mockKioskStorage) GetKioskPrincipal(ctx context.Context, tokenHash string) (*kiosk.Principal, error) {
+	if tokenHash != apisecret.Hash(goodToken) {
+		return nil, nil
+	}
+	return &kiosk.Principal{TokenID: 9, OrgID: 42, LocationID: 5}, nil
+}
+
+func (m *mockKioskStorage) GetKioskDashboard(ctx context.Context, p kiosk.Principal) (*kiosk.Dashboard, error) {
+	m.dashboardOf = &p
+	return &kiosk.Dashboard{
+		Location: kiosk.DashboardLocation{ID: p.LocationID, Name: "Dock 4"},
+		Assets:   []kiosk.DashboardAsset{},
+	}, nil
+}
+
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		h.RegisterKioskRoutes(r)
+		r.Post("/api/v1/kiosk-tokens", h.CreateToken)
+		r.Delete("/api/v1/kiosk-tokens/{kiosk_token_id}", h.RevokeToken)
+	}, req)
+}
+
+func TestDashboard_Auth(t *testing.T) {
+	cases := []struct {
+		name   string
+		target string
+		header string
+		want   int
+	}{
+		{"bearer", "/api/v1/kiosk/dashboard", "Bearer " + goodToken, http.StatusOK},
+		{"query", "/api/v1/kiosk/dashboard?token=" + goodToken, "", http.StatusOK},
+		{"missing", "/api/v1/kiosk/dashboard", "", http.StatusUnauthorized},
+		{"unknown", "/api/v1/kiosk/dashboard?token=trakrf_other", "", http.StatusUnauthorized},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockKioskStorage{}
+			req := httptest.NewRequest(http.MethodGet, c.target, nil)
+			if c.header != "" {
+				req.Header.Set("Authorization", c.header)
+			}
+			w := serve(NewHandler(m), req)
+			require.Equal(t, c.want, w.Code, w.Body.String())
+			if c.want == http.StatusOK {
+				require.NotNil(t, m.dashboardOf)
+				assert.Equal(t, 5, m.dashboardOf.LocationID)
+				assert.Equal(t, 42, m.dashboardOf.OrgID)
+			}
+		})
+	}
+}
+
+func TestCreateToken(t *testing.T) {
+	m := &mockKioskStorage{}
+	w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/kiosk-tokens",
+		`{"name":"Dock 4 lobby TV","location_id":5}`))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	var body struct {
+		Data struct {
+			ID    int    `json:"id"`
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 9, body.Data.ID)
+	assert.True(t, strings.HasPrefix(body.Data.Token, "trakrf_"))
+	assert.Equal(t, apisecret.Hash(body.Data.Token), m.createdHash, "only the hash is stored")
+}
+
+func TestCreateToken_ForeignLocation(t *testing.T) {
+	w := serve(NewHandler(&mockKioskStorage{}), testutil.NewRequest(t, http.MethodPost, "/api/v1/kiosk-tokens",
+		`{"name":"TV","location_id":77}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+}
+
+func TestRevokeToken(t *testing.T) {
+	w := serve(NewHandler(&mockKioskStorage{}), testutil.NewRequest(t, http.MethodDelete, "/api/v1/kiosk-tokens/9", ""))
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = serve(NewHandler(&mockKioskStorage{}), testutil.NewRequest(t, http.MethodDelete, "/api/v1/kiosk-tokens/8", ""))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,516 @@
+// Package labelprinters serves label printing: org admins register networked
+// Zebra printers per location, operators queue print jobs of asset labels,
+// and anyone in the org can follow a job's status. Jobs are rendered to ZPL
+// here and sent to the printer by the label_print job.
+package labelprinters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/labels"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const listLimit = 200
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+var statuses = []string{
+	labelprint.StatusPending, labelprint.StatusPrinting, labelprint.StatusCompleted, labelprint.StatusFailed,
+}
+
+// PrintStorage is the storage surface the handler needs (mockable).
+type PrintStorage interface {
+	CreateLabelPrinter(ctx context.Context, orgID int, req labelprint.CreatePrinterRequest) (*labelprint.Printer, error)
+	GetLabelPrinter(ctx context.Context, orgID, id int) (*labelprint.Printer, error)
+	ListLabelPrinters(ctx context.Context, orgID, locationID int) ([]labelprint.Printer, error)
+	UpdateLabelPrinter(ctx context.Context, orgID, id int, req labelprint.UpdatePrinterRequest) (*labelprint.Printer, error)
+	DeleteLabelPrinter(ctx context.Context, orgID, id int) (bool, error)
+	ListLabelAssets(ctx context.Context, orgID int, ids []int) ([]labelprint.LabelAsset, error)
+	CreatePrintJob(ctx context.Context, orgID int, job labelprint.Job) (*labelprint.Job, error)
+	GetPrintJob(ctx context.Context, orgID, id int) (*labelprint.Job, error)
+	ListPrintJobs(ctx context.Context, orgID int, f labelprint.JobFilter) ([]labelprint.Job, error)
+}
+
+type Handler struct {
+	storage PrintStorage
+	policy  labels.Policy
+}
+
+// NewHandler returns a handler that accepts printers where policy allows
+// them, the same policy the label_print job's sender enforces.
+func NewHandler(storage PrintStorage, policy labels.Policy) *Handler {
+	return &Handler{storage: storage, policy: policy}
+}
+
+// RegisterRoutes wires the printer and print job routes onto r. Mount inside
+// the session-auth (middleware.Auth) group. Any member can read printers and
+// jobs; operators queue jobs; registering and changing printers is
+// admin-only, since a printer's host is where the server opens connections.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	operator := middleware.RequireCurrentOrgOperator(store)
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(member).Get("/api/v1/label-printers", h.ListPrinters)
+	r.With(admin).Post("/api/v1/label-printers", h.CreatePrinter)
+	r.With(member).Get("/api/v1/label-printers/{printer_id}", h.GetPrinter)
+	r.With(admin).Patch("/api/v1/label-printers/{printer_id}", h.UpdatePrinter)
+	r.With(admin).Delete("/api/v1/label-printers/{printer_id}", h.DeletePrinter)
+
+	r.With(member).Get("/api/v1/print-jobs", h.ListJobs)
+	r.With(operator).Post("/api/v1/print-jobs", h.CreateJob)
+	r.With(member).Get("/api/v1/print-jobs/{print_job_id}", h.GetJob)
+}
+
+// caller returns the request's org and session user, answering the error
+// itself when either is missing.
+func caller(w http.ResponseWriter, r *http.Request, reqID string) (orgID, userID int, ok bool) {
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return 0, 0, false
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return 0, 0, false
+	}
+	return orgID, claims.UserID, true
+}
+
+// parseID reads a surrogate-id path param, answering 400 itself.
+func parseID(w http.ResponseWriter, r *http.Request, name, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID(name, chi.URLParam(r, name))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// checkPrinter rejects a host or port the printer policy does not allow,
+// answering 400 itself. The sender checks the host again once resolved.
+func (h *Handler) checkPrinter(w http.ResponseWriter, r *http.Request, host *string, port *int, reqID string) bool {
+	var fields []modelerrors.FieldError
+	if host != nil {
+		if err := h.policy.CheckHost(r.Context(), *host); err != nil {
+			fields = append(fields, modelerrors.FieldError{
+				Field: "host", Code: "invalid_value", Message: "host " + err.Error(),
+			})
+		}
+	}
+	if port != nil && !h.policy.AllowPort(*port) {
+		fields = append(fields, modelerrors.FieldError{
+			Field: "port", Code: "invalid_value",
+			Message: fmt.Sprintf("port %d %s", *port, labels.ErrNotPrinterPort),
+		})
+	}
+	if len(fields) > 0 {
+		httputil.WriteValidationError(w, r, reqID, fields)
+		return false
+	}
+	return true
+}
+
+// respondPrinterError maps the storage sentinels to their status codes.
+func respondPrinterError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	if errors.Is(err, storage.ErrLabelPrinterLocationNotFound) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "location_id", Code: "fk_not_found", Message: "location not found",
+		}})
+		return
+	}
+	httputil.RespondStorageError(w, r, err, reqID)
+}
+
+// @Summary  List label printers
+// @Description The org's label printers by name.
+// @Tags     label-printing,internal
+// @ID       label_printers.list
+// @Produce  json
+// @Param    location_id query int false "Only printers at this location" minimum(1) format(int64)
+// @Success  200 {object} map[string]any "data: []labelprint.Printer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/label-printers [get]
+func (h *Handler) ListPrinters(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	locationID := 0
+	if v := r.URL.Query().Get("location_id"); v != "" {
+		id, err := httputil.ParseSurrogateID("location_id", v)
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		locationID = id
+	}
+	list, err := h.storage.ListLabelPrinters(r.Context(), orgID, locationID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Register a label printer
+// @Description Registers a networked ZPL printer (Zebra) at one of the org's locations. Jobs are sent over TCP to `host`:`port` (9100, the raw-print port, by default). The host must be a public address unless the deployment lists the private network it is on, and the port one of the deployment's raw-print ports. Labels are laid out for `dpi` (203 by default); with `encode_rfid` set, each label's inlay is written with the asset's RFID EPC.
+// @Tags     label-printing,internal
+// @ID       label_printers.create
+// @Accept   json
+// @Produce  json
+// @Param    request body labelprint.CreatePrinterRequest true "Printer"
+// @Success  201 {object} map[string]any "data: labelprint.Printer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/label-printers [post]
+func (h *Handler) CreatePrinter(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	var req labelprint.CreatePrinterRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	port := labelprint.DefaultPort
+	if req.Port != nil {
+		port = *req.Port
+	}
+	if !h.checkPrinter(w, r, &req.Host, &port, reqID) {
+		return
+	}
+	p, err := h.storage.CreateLabelPrinter(r.Context(), orgID, req)
+	if err != nil {
+		respondPrinterError(w, r, err, reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/label-printers/"+strconv.Itoa(p.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": p})
+}
+
+// @Summary  Get a label printer
+// @Tags     label-printing,internal
+// @ID       label_printers.get
+// @Produce  json
+// @Param    printer_id path int true "Printer id"
+// @Success  200 {object} map[string]any "data: labelprint.Printer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/label-printers/{printer_id} [get]
+func (h *Handler) GetPrinter(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "printer_id", reqID)
+	if !ok {
+		return
+	}
+	p, err := h.storage.GetLabelPrinter(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if p == nil {
+		httputil.Respond404(w, r, "label printer not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": p})
+}
+
+// @Summary  Update a label printer
+// @Description Omitted fields are left unchanged. An inactive printer keeps its queued jobs from being sent; they fail when next claimed.
+// @Tags     label-printing,internal
+// @ID       label_printers.update
+// @Accept   json
+// @Produce  json
+// @Param    printer_id path int true "Printer id"
+// @Param    request body labelprint.UpdatePrinterRequest true "Fields to update"
+// @Success  200 {object} map[string]any "data: labelprint.Printer"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/label-printers/{printer_id} [patch]
+func (h *Handler) UpdatePrinter(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "printer_id", reqID)
+	if !ok {
+		return
+	}
+	var req labelprint.UpdatePrinterRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if !h.checkPrinter(w, r, req.Host, req.Port, reqID) {
+		return
+	}
+	p, err := h.storage.UpdateLabelPrinter(r.Context(), orgID, id, req)
+	if err != nil {
+		respondPrinterError(w, r, err, reqID)
+		return
+	}
+	if p == nil {
+		httputil.Respond404(w, r, "label printer not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": p})
+}
+
+// @Summary  Delete a label printer
+// @Description Deletes the printer and its print jobs.
+// @Tags     label-printing,internal
+// @ID       label_printers.delete
+// @Param    printer_id path int true "Printer id"
+// @Success  204
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/label-printers/{printer_id} [delete]
+func (h *Handler) DeletePrinter(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "printer_id", reqID)
+	if !ok {
+		return
+	}
+	deleted, err := h.storage.DeleteLabelPrinter(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "label printer not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary  Queue a print job
+// @Description Renders a label for each asset (name, and a Code 128 barcode of its external key; on an RFID printer, the asset's active RFID tag is encoded to the inlay) as ZPL and queues it for the printer. The job is sent in the background: poll GET /api/v1/print-jobs/{print_job_id} for its status. A printer that cannot be reached is retried with a backoff, up to 5 attempts.
+// @Tags     label-printing,internal
+// @ID       print_jobs.create
+// @Accept   json
+// @Produce  json
+// @Param    request body labelprint.CreateJobRequest true "Print job"
+// @Success  202 {object} map[string]any "data: labelprint.Job"
+// @Failure  400 {object} modelerrors.ErrorResponse "invalid request, or an asset that is not the org's"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "printer not found"
+// @Failure  409 {object} modelerrors.ErrorResponse "the printer is inactive"
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/print-jobs [post]
+func (h *Handler) CreateJob(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	var req labelprint.CreateJobRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	copies := 1
+	if req.Copies != nil {
+		copies = *req.Copies
+	}
+
+	printer, err := h.storage.GetLabelPrinter(r.Context(), orgID, req.PrinterID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if printer == nil {
+		httputil.Respond404(w, r, "label printer not found", reqID)
+		return
+	}
+	if !printer.IsActive {
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, "label printer is inactive", reqID)
+		return
+	}
+
+	assets, err := h.storage.ListLabelAssets(r.Context(), orgID, req.AssetIDs)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if len(assets) < len(req.AssetIDs) {
+		var missing []string
+		for _, id := range req.AssetIDs {
+			if !slices.ContainsFunc(assets, func(a labelprint.LabelAsset) bool { return a.ID == id }) {
+				missing = append(missing, strconv.Itoa(id))
+			}
+		}
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "asset_ids", Code: "fk_not_found",
+			Message: fmt.Sprintf("assets not found: %s", strings.Join(missing, ", ")),
+		}})
+		return
+	}
+
+	job, err := h.storage.CreatePrintJob(r.Context(), orgID, labelprint.Job{
+		PrinterID:   printer.ID,
+		AssetIDs:    req.AssetIDs,
+		Copies:      copies,
+		LabelCount:  len(assets) * copies,
+		ZPL:         labels.RenderZPL(assets, printer.DPI, printer.EncodeRFID, copies),
+		RequestedBy: &userID,
+	})
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/print-jobs/"+strconv.Itoa(job.ID))
+	httputil.WriteJSON(w, http.StatusAccepted, map[string]any{"data": job})
+}
+
+// @Summary  List print jobs
+// @Description The org's print jobs, newest first, at most 200, without their ZPL.
+// @Tags     label-printing,internal
+// @ID       print_jobs.list
+// @Produce  json
+// @Param    printer_id query int false "Only this printer" minimum(1) format(int64)
+// @Param    status query string false "Only this status" Enums(pending, printing, completed, failed)
+// @Success  200 {object} map[string]any "data: []labelprint.Job"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/print-jobs [get]
+func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	f := labelprint.JobFilter{Limit: listLimit}
+	q := r.URL.Query()
+	if v := q.Get("printer_id"); v != "" {
+		id, err := httputil.ParseSurrogateID("printer_id", v)
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		f.PrinterID = id
+	}
+	if st := q.Get("status"); st != "" {
+		if !slices.Contains(statuses, st) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field: "status", Code: "invalid_value",
+				Message: "status must be one of: " + strings.Join(statuses, ", "),
+			}})
+			return
+		}
+		f.Status = st
+	}
+	list, err := h.storage.ListPrintJobs(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Get a print job's status
+// @Description The job with its status, attempts and last error, and the ZPL that was (or will be) sent.
+// @Tags     label-printing,internal
+// @ID       print_jobs.get
+// @Produce  json
+// @Param    print_job_id path int true "Print job id"
+// @Success  200 {object} map[string]any "data: labelprint.Job"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/print-jobs/{print_job_id} [get]
+func (h *Handler) GetJob(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, "print_job_id", reqID)
+	if !ok {
+		return
+	}
+	job, err := h.storage.GetPrintJob(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if job == nil {
+		httputil.Respond404(w, r, "print job not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": job})
+}
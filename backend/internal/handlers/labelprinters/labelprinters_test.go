@@ -0,0 +1,238 @@
+package labelprinters
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/labels"
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockPrintStorage struct {
+	printers  map[int]*labelprint.Printer
+	assets    []labelprint.LabelAsset
+	created   *labelprint.CreatePrinterRequest
+	createErr error
+	job       *labelprint.Job
+	filter    labelprint.JobFilter
+}
+
+func newMock() *mockPrintStorage {
+	return &mockPrintStorage{
+		printers: map[int]*labelprint.Printer{
+			5: {ID: 5, DPI: 203, IsActive: true},
+			6: {ID: 6, DPI: 203},
+		},
+		assets: []labelprint.LabelAsset{
+			{ID: 11, ExternalKey: "ASSET-0011", Name: "Forklift"},
+			{ID: 12, ExternalKey: "ASSET-0012", Name: "Scanner"},
+		},
+	}
+}
+
+func (m *mockPrintStorage) CreateLabelPrinter(ctx context.Context, orgID int, req labelprint.CreatePrinterRequest) (*labelprint.Printer, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created = &req
+	return &labelprint.Printer{ID: 9, LocationID: req.LocationID, Host: req.Host}, nil
+}
+
+func (m *mockPrintStorage) GetLabelPrinter(ctx context.Context, orgID, id int) (*labelprint.Printer, error) {
+	return m.printers[id], nil
+}
+
+func (m *mockPrintStorage) ListLabelPrinters(ctx context.Context, orgID, locationID int) ([]labelprint.Printer, error) {
+	return []labelprint.Printer{}, nil
+}
+
+func (m *mockPrintStorage) UpdateLabelPrinter(ctx context.Context, orgID, id int, req labelprint.UpdatePrinterRequest) (*labelprint.Printer, error) {
+	return m.printers[id], nil
+}
+
+func (m *mockPrintStorage) DeleteLabelPrinter(ctx context.Context, orgID, id int) (bool, error) {
+	return m.printers[id] != nil, nil
+}
+
+func (m *mockPrintStorage) ListLabelAssets(ctx context.Context, orgID int, ids []int) ([]labelprint.LabelAsset, error) {
+	out := []labelprint.LabelAsset{}
+	for _, id := range ids {
+		for _, a := range m.assets {
+			if a.ID == id {
+				out = append(out, a)
+			}
+		}
+	}
+	return out, nil
+}
+
+func (m *mockPrintStorage) CreatePrintJob(ctx context.Context, orgID int, job labelprint.Job) (*labelprint.Job, error) {
+	job.ID, job.OrgID, job.Status = 70, orgID, labelprint.StatusPending
+	m.job = &job
+	return &job, nil
+}
+
+func (m *mockPrintStorage) GetPrintJob(ctx context.Context, orgID, id int) (*labelprint.Job, error) {
+	if id != 70 {
+		return nil, nil
+	}
+	return &labelprint.Job{ID: 70, Status: labelprint.StatusPrinting}, nil
+}
+
+func (m *mockPrintStorage) ListPrintJobs(ctx context.Context, orgID int, f labelprint.JobFilter) ([]labelprint.Job, error) {
+	m.filter = f
+	return []labelprint.Job{}, nil
+}
+
+// serve routes through chi without the role middleware so handler-level
+// checks are exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Post("/api/v1/label-printers", h.CreatePrinter)
+		r.Patch("/api/v1/label-printers/{printer_id}", h.UpdatePrinter)
+		r.Delete("/api/v1/label-printers/{printer_id}", h.DeletePrinter)
+		r.Get("/api/v1/print-jobs", h.ListJobs)
+		r.Post("/api/v1/print-jobs", h.CreateJob)
+		r.Get("/api/v1/print-jobs/{print_job_id}", h.GetJob)
+	}, req)
+}
+
+// testPolicy puts the deployment's printers on 10.0.0.0/8, listening on the
+// JetDirect port or 6101.
+var testPolicy = labels.Policy{
+	PrivateNetworks: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+	Ports:           []int{9100, 6101},
+}
+
+func TestCreatePrinter(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		createErr error
+		want      int
+	}{
+		{"ip", `{"location_id":3,"name":"Dock 3","host":"10.20.0.31"}`, nil, http.StatusCreated},
+		{"hostname", `{"location_id":3,"name":"Dock 3","host":"zebra-dock3.local","port":6101,"dpi":300}`, nil, http.StatusCreated},
+		{"missing host", `{"location_id":3,"name":"Dock 3"}`, nil, http.StatusBadRequest},
+		{"bad host", `{"location_id":3,"name":"Dock 3","host":"http://10.0.0.1/"}`, nil, http.StatusBadRequest},
+		{"loopback", `{"location_id":3,"name":"Dock 3","host":"127.0.0.1"}`, nil, http.StatusBadRequest},
+		{"metadata service", `{"location_id":3,"name":"Dock 3","host":"169.254.169.254"}`, nil, http.StatusBadRequest},
+		{"bad dpi", `{"location_id":3,"name":"Dock 3","host":"10.0.0.1","dpi":150}`, nil, http.StatusBadRequest},
+		{"bad port", `{"location_id":3,"name":"Dock 3","host":"10.0.0.1","port":70000}`, nil, http.StatusBadRequest},
+		{"not a raw-print port", `{"location_id":3,"name":"Dock 3","host":"10.0.0.1","port":6379}`, nil, http.StatusBadRequest},
+		{"private network outside the policy", `{"location_id":3,"name":"Dock 3","host":"192.168.1.50"}`, nil, http.StatusBadRequest},
+		{"foreign location", `{"location_id":3,"name":"Dock 3","host":"10.0.0.1"}`, storage.ErrLabelPrinterLocationNotFound, http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := newMock()
+			m.createErr = c.createErr
+			w := serve(NewHandler(m, testPolicy), testutil.NewRequest(t, http.MethodPost, "/api/v1/label-printers", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.want == http.StatusCreated && w.Header().Get("Location") != "/api/v1/label-printers/9" {
+				t.Errorf("Location = %q", w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestUpdatePrinter(t *testing.T) {
+	w := serve(NewHandler(newMock(), testPolicy), testutil.NewRequest(t, http.MethodPatch, "/api/v1/label-printers/5", `{"host":"::1"}`))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("loopback host: status = %d", w.Code)
+	}
+	w = serve(NewHandler(newMock(), testPolicy), testutil.NewRequest(t, http.MethodPatch, "/api/v1/label-printers/5", `{"port":22}`))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("ssh port: status = %d", w.Code)
+	}
+	w = serve(NewHandler(newMock(), testPolicy), testutil.NewRequest(t, http.MethodPatch, "/api/v1/label-printers/8", `{"is_active":false}`))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("missing printer: status = %d", w.Code)
+	}
+}
+
+func TestDeletePrinter(t *testing.T) {
+	if w := serve(NewHandler(newMock(), testPolicy), testutil.NewRequest(t, http.MethodDelete, "/api/v1/label-printers/5", "")); w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if w := serve(NewHandler(newMock(), testPolicy), testutil.NewRequest(t, http.MethodDelete, "/api/v1/label-printers/8", "")); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", w.Code)
+	}
+}
+
+func TestCreateJob(t *testing.T) {
+	m := newMock()
+	w := serve(NewHandler(m, testPolicy), testutil.NewRequest(t, http.MethodPost, "/api/v1/print-jobs", `{"printer_id":5,"asset_ids":[12,11],"copies":2}`))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.job.LabelCount != 4 || m.job.Copies != 2 || m.job.RequestedBy == nil || *m.job.RequestedBy != 1 {
+		t.Errorf("job = %+v", m.job)
+	}
+	if i, j := strings.Index(m.job.ZPL, "ASSET-0012"), strings.Index(m.job.ZPL, "ASSET-0011"); i < 0 || j < i {
+		t.Errorf("labels not rendered in request order: %q", m.job.ZPL)
+	}
+	if w.Header().Get("Location") != "/api/v1/print-jobs/70" {
+		t.Errorf("Location = %q", w.Header().Get("Location"))
+	}
+}
+
+func TestCreateJob_Rejections(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"no assets", `{"printer_id":5,"asset_ids":[]}`, http.StatusBadRequest},
+		{"duplicate assets", `{"printer_id":5,"asset_ids":[11,11]}`, http.StatusBadRequest},
+		{"too many copies", `{"printer_id":5,"asset_ids":[11],"copies":11}`, http.StatusBadRequest},
+		{"unknown asset", `{"printer_id":5,"asset_ids":[11,13]}`, http.StatusBadRequest},
+		{"unknown printer", `{"printer_id":8,"asset_ids":[11]}`, http.StatusNotFound},
+		{"inactive printer", `{"printer_id":6,"asset_ids":[11]}`, http.StatusConflict},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := newMock()
+			w := serve(NewHandler(m, testPolicy), testutil.NewRequest(t, http.MethodPost, "/api/v1/print-jobs", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if m.job != nil {
+				t.Errorf("job queued: %+v", m.job)
+			}
+		})
+	}
+}
+
+func TestListJobs_Filters(t *testing.T) {
+	m := newMock()
+	w := serve(NewHandler(m, testPolicy), testutil.NewRequest(t, http.MethodGet, "/api/v1/print-jobs?printer_id=5&status=failed", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.filter.PrinterID != 5 || m.filter.Status != labelprint.StatusFailed || m.filter.Limit != listLimit {
+		t.Errorf("filter = %+v", m.filter)
+	}
+	if w := serve(NewHandler(m, testPolicy), testutil.NewRequest(t, http.MethodGet, "/api/v1/print-jobs?status=queued", "")); w.Code != http.StatusBadRequest {
+		t.Errorf("bad status: %d", w.Code)
+	}
+}
+
+func TestGetJob(t *testing.T) {
+	w := serve(NewHandler(newMock(), testPolicy), testutil.NewRequest(t, http.MethodGet, "/api/v1/print-jobs/70", ""))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"printing"`) {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if w := serve(NewHandler(newMock(), testPolicy), testutil.NewRequest(t, http.MethodGet, "/api/v1/print-jobs/71", "")); w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d", w.Code)
+	}
+}
@@ -0,0 +1,188 @@
+// Package locationpolicies provides internal (session-authenticated),
+// org-admin-only endpoints for location access policies: grants that confine a
+// user, or every member of a team, to a location subtree. Enforcement lives in
+// the locations handler and storage list predicates.
+package locationpolicies
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// PolicyStorage is the narrow storage surface the handler needs (mockable).
+type PolicyStorage interface {
+	ListLocationAccessPolicies(ctx context.Context, orgID int) ([]location.AccessPolicy, error)
+	CreateLocationAccessPolicy(ctx context.Context, orgID int, req location.CreateAccessPolicyRequest) (*location.AccessPolicy, error)
+	DeleteLocationAccessPolicy(ctx context.Context, orgID, policyID int) (bool, error)
+	GetUserOrgRole(ctx context.Context, userID, orgID int) (models.OrgRole, error)
+}
+
+type Handler struct {
+	storage PolicyStorage
+}
+
+func NewHandler(storage PolicyStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the policy routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Every route is org-admin only: a
+// policy changes what other members can see.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(admin).Get("/api/v1/location-policies", h.List)
+	r.With(admin).Post("/api/v1/location-policies", h.Create)
+	r.With(admin).Delete("/api/v1/location-policies/{policy_id}", h.Delete)
+}
+
+// @Summary  List location access policies
+// @Tags     locations,internal
+// @ID       location_policies.list
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []location.AccessPolicy"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/location-policies [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	policies, err := h.storage.ListLocationAccessPolicies(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": policies})
+}
+
+// @Summary  Grant a location subtree to a user or team
+// @Description Exactly one of `user_id` and `team_id`. Once a member holds any policy (directly or through a team) they only see locations at or below their granted roots; members with no policy are unaffected, and org admins are never confined. Ancestors of a granted root stay readable through GET /locations/{location_id}/ancestors as breadcrumb context.
+// @Tags     locations,internal
+// @ID       location_policies.create
+// @Accept   json
+// @Produce  json
+// @Param    request body location.CreateAccessPolicyRequest true "Subtree root and grantee"
+// @Success  201 {object} map[string]any "data: location.AccessPolicy"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Location or team not found"
+// @Failure  409 {object} modelerrors.ErrorResponse "The grantee already holds a policy on this location"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/location-policies [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	var req location.CreateAccessPolicyRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if (req.UserID == nil) == (req.TeamID == nil) {
+		msg := "exactly one of user_id and team_id is required"
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{
+			{Field: "user_id", Code: "ambiguous_fields", Message: msg},
+			{Field: "team_id", Code: "ambiguous_fields", Message: msg},
+		})
+		return
+	}
+	if req.UserID != nil {
+		if _, err := h.storage.GetUserOrgRole(r.Context(), *req.UserID, orgID); err != nil {
+			if errors.Is(err, storage.ErrOrgUserNotFound) {
+				httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+					Field:   "user_id",
+					Code:    "invalid_value",
+					Message: "user_id must be a member of this organization",
+				}})
+				return
+			}
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+			return
+		}
+	}
+	created, err := h.storage.CreateLocationAccessPolicy(r.Context(), orgID, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exist") {
+			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if created == nil {
+		httputil.Respond404(w, r, "location or team not found", reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/location-policies/"+strconv.Itoa(created.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": created})
+}
+
+// @Summary  Revoke a location access policy
+// @Tags     locations,internal
+// @ID       location_policies.delete
+// @Param    policy_id path int true "Policy id" minimum(1) format(int64)
+// @Success  204 "deleted"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/location-policies/{policy_id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, err := httputil.ParseSurrogateID("policy_id", chi.URLParam(r, "policy_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	deleted, err := h.storage.DeleteLocationAccessPolicy(r.Context(), orgID, id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "location access policy not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+package locationpolicies
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockPolicyStorage struct {
+	createResult *location.AccessPolicy
+	createErr    error
+	orgRoleErr   error
+	deleteOK     bool
+
+	createCalled bool
+}
+
+func (m *mockPolicyStorage) ListLocationAccessPolicies(ctx context.Context, orgID int) ([]location.AccessPolicy, error) {
+	return []location.AccessPolicy{}, nil
+}
+
+func (m *mockPolicyStorage) CreateLocationAccessPolicy(ctx context.Context, orgID int, req location.CreateAccessPolicyRequest) (*location.AccessPolicy, error) {
+	m.createCalled = true
+	return m.createResult, m.createErr
+}
+
+func (m *mockPolicyStorage) DeleteLocationAccessPolicy(ctx context.Context, orgID, policyID int) (bool, error) {
+	return m.deleteOK, nil
+}
+
+func (m *mockPolicyStorage) GetUserOrgRole(ctx context.Context, userID, orgID int) (models.OrgRole, error) {
+	return models.RoleViewer, m.orgRoleErr
+}
+
+// serve routes through chi without the role middleware so handler logic is
+// exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Post("/api/v1/location-policies", h.Create)
+		r.Delete("/api/v1/location-policies/{policy_id}", h.Delete)
+	}, req)
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestCreate_UserGrantee(t *testing.T) {
+	mock := &mockPolicyStorage{createResult: &location.AccessPolicy{ID: 9, LocationID: 3, UserID: intPtr(5)}}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPost, "/api/v1/location-policies",
+		location.CreateAccessPolicyRequest{LocationID: 3, UserID: intPtr(5)}))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Location"); got != "/api/v1/location-policies/9" {
+		t.Errorf("Location = %q", got)
+	}
+}
+
+func TestCreate_RequiresExactlyOneGrantee(t *testing.T) {
+	for name, req := range map[string]location.CreateAccessPolicyRequest{
+		"neither": {LocationID: 3},
+		"both":    {LocationID: 3, UserID: intPtr(5), TeamID: intPtr(2)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mock := &mockPolicyStorage{}
+			w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPost, "/api/v1/location-policies", req))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			if mock.createCalled {
+				t.Error("storage should not be called")
+			}
+		})
+	}
+}
+
+func TestCreate_NonOrgMember400(t *testing.T) {
+	mock := &mockPolicyStorage{orgRoleErr: storage.ErrOrgUserNotFound}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPost, "/api/v1/location-policies",
+		location.CreateAccessPolicyRequest{LocationID: 3, UserID: intPtr(5)}))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreate_LocationOrTeamNotFound404(t *testing.T) {
+	w := serve(NewHandler(&mockPolicyStorage{}), testutil.NewRequest(t, http.MethodPost, "/api/v1/location-policies",
+		location.CreateAccessPolicyRequest{LocationID: 3, TeamID: intPtr(2)}))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreate_Duplicate409(t *testing.T) {
+	mock := &mockPolicyStorage{createErr: errors.New("a policy for this grantee on location 3 already exists")}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPost, "/api/v1/location-policies",
+		location.CreateAccessPolicyRequest{LocationID: 3, TeamID: intPtr(2)}))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestDelete_NotFound404(t *testing.T) {
+	w := serve(NewHandler(&mockPolicyStorage{}), testutil.NewRequest(t, http.MethodDelete, "/api/v1/location-policies/4", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,92 @@
+package locations
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// accessScopes resolves the caller's strict-team-mode visibility and their
+// location access policy scope. API-key callers are org-wide integrations and
+// are never restricted by either.
+func (handler *Handler) accessScopes(req *http.Request, orgID int) (team.Scope, location.SubtreeScope, error) {
+	if middleware.GetAPIKeyPrincipal(req) != nil {
+		return team.Scope{}, location.SubtreeScope{}, nil
+	}
+	claims := middleware.GetUserClaims(req)
+	if claims == nil {
+		return team.Scope{}, location.SubtreeScope{}, nil
+	}
+	ts, err := handler.storage.TeamScopeForUser(req.Context(), orgID, claims.UserID)
+	if err != nil {
+		return team.Scope{}, location.SubtreeScope{}, err
+	}
+	ss, err := handler.storage.LocationSubtreeScopeForUser(req.Context(), orgID, claims.UserID)
+	if err != nil {
+		return team.Scope{}, location.SubtreeScope{}, err
+	}
+	return ts, ss, nil
+}
+
+// requireVisible writes a 404 and returns false when the location is hidden
+// from the caller by strict team mode or by their location access policies.
+// Hidden locations are indistinguishable from missing ones.
+func (handler *Handler) requireVisible(w http.ResponseWriter, req *http.Request, reqID string, orgID, locationID int) bool {
+	visible, err := handler.isVisible(req, orgID, locationID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return false
+	}
+	if !visible {
+		httputil.Respond404(w, req, apierrors.LocationNotFound, reqID)
+		return false
+	}
+	return true
+}
+
+// isVisible reports whether the location is visible to the caller under
+// strict team mode and their location access policies.
+func (handler *Handler) isVisible(req *http.Request, orgID, locationID int) (bool, error) {
+	ts, ss, err := handler.accessScopes(req, orgID)
+	if err != nil {
+		return false, err
+	}
+	if ts.Restricted {
+		teamID, err := handler.storage.GetLocationTeamID(req.Context(), orgID, locationID)
+		if err != nil {
+			return false, err
+		}
+		if !ts.Allows(teamID) {
+			return false, nil
+		}
+	}
+	if ss.Restricted {
+		return handler.storage.IsLocationInSubtrees(req.Context(), orgID, locationID, ss.RootIDs)
+	}
+	return true, nil
+}
+
+// requireRootAllowed writes a 403 and returns false when the caller's
+// location access policies forbid a location at the top of the tree: a
+// created root, or a location moved out from under its parent, would sit
+// outside every subtree they were granted. A granted root itself may be
+// promoted, since it stays inside its own grant.
+func (handler *Handler) requireRootAllowed(w http.ResponseWriter, req *http.Request, reqID string, orgID int, locationID *int) bool {
+	_, ss, err := handler.accessScopes(req, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return false
+	}
+	if !ss.Restricted || (locationID != nil && slices.Contains(ss.RootIDs, *locationID)) {
+		return true
+	}
+	httputil.WriteJSONError(w, req, http.StatusForbidden, modelerrors.ErrForbidden,
+		"Your location access policies only allow locations inside the subtrees you were granted", reqID)
+	return false
+}
@@ -0,0 +1,105 @@
+package locations
+
+import (
+	stderrors "errors"
+	"io"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ApplyLocationsResponse is the typed envelope returned by
+// PUT /api/v1/locations:apply.
+type ApplyLocationsResponse struct {
+	Data location.ApplyResult `json:"data"`
+}
+
+// @Summary      Apply a desired-state location hierarchy
+// @Description  **Required scope:** `locations:write`
+// @Description
+// @Description  Declarative, idempotent sync of the location hierarchy, for layouts kept under version control. The body is the whole tree as it should be — `{"locations": [{"external_key", "name", "description", "children": [...]}]}` — as JSON or, with `Content-Type: application/yaml`, as the same document in YAML.
+// @Description
+// @Description  Locations are matched by `external_key`. A location the tree names but the organization lacks is created; one under a different parent is moved; one with a different `name` or `description` is updated (an omitted description clears it). To rename a location's `external_key`, give the new key and set `renamed_from` to the old one; once applied, `renamed_from` is a no-op and may stay in the file. Tags, validity window, active flag and placed assets are never changed.
+// @Description
+// @Description  Live locations the tree does not mention are left alone and listed in `unmanaged`; nothing is deleted. Everything is applied in one transaction. Applying the same tree twice reports no changes the second time. `dry_run=true` returns the plan without writing anything.
+// @Tags         locations,public
+// @ID           locations.apply
+// @Accept       json
+// @Accept       application/yaml
+// @Produce      json
+// @Param        dry_run  query  bool                     false  "Return the plan without applying it"
+// @Param        request  body   location.ApplyRequest    true   "Desired location tree"
+// @Success      200  {object}  locations.ApplyLocationsResponse
+// @Failure      400  {object}  modelerrors.ErrorResponse     "bad_request"
+// @Failure      401  {object}  modelerrors.ErrorResponse     "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse     "forbidden"
+// @Failure      409  {object}  modelerrors.ErrorResponse     "conflict"
+// @Failure      413  {object}  modelerrors.ErrorResponse     "payload_too_large"
+// @Failure      415  {object}  modelerrors.ErrorResponse     "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse     "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse     "internal_error"
+// @Security     BearerAuth[locations:write]
+// @Router       /api/v1/locations:apply [put]
+func (handler *Handler) Apply(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	// The tree spans the whole org, so a caller confined to part of it by
+	// strict team mode or a location access policy cannot apply one.
+	ts, ss, err := handler.accessScopes(r, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if ts.Restricted || ss.Restricted {
+		httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden,
+			"your access is limited to part of the location tree; applying the whole tree needs org-wide access", reqID)
+		return
+	}
+
+	var request location.ApplyRequest
+	if middleware.IsYAMLContentType(r.Header.Get("Content-Type")) {
+		dec := yaml.NewDecoder(r.Body)
+		dec.KnownFields(true)
+		if err := dec.Decode(&request); err != nil && !stderrors.Is(err, io.EOF) {
+			var mbe *http.MaxBytesError
+			if stderrors.As(err, &mbe) {
+				httputil.Respond413(w, r, mbe.Limit, reqID)
+				return
+			}
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
+				"invalid YAML: "+err.Error(), reqID)
+			return
+		}
+	} else if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if errs := location.CheckApplyTree(request.Locations); len(errs) > 0 {
+		httputil.WriteValidationError(w, r, reqID, errs)
+		return
+	}
+
+	result, err := handler.storage.ApplyLocationTree(r.Context(), orgID, request.Locations, dryRun)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, ApplyLocationsResponse{Data: *result})
+}
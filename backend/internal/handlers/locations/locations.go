@@ -1,6 +1,7 @@
 package locations
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -45,6 +46,12 @@ func NewHandler(storage *storage.Storage) *Handler {
 // Both nil → nil (no parent). When parent_id is set it is used; otherwise
 // parent_external_key is resolved via lookup.
 //
+// A parent hidden from the caller (strict team mode, location access
+// policies) resolves as not found, so a location cannot be placed outside
+// what the caller may see. currentParent, the parent a PATCHed location
+// already has, is exempt so a GET → PATCH round-trip of a granted root
+// still succeeds.
+//
 // TRA-674 / BB27 F2 / TRA-681: a nonexistent surrogate `parent_id`
 // returns the same envelope shape as a nonexistent natural-key
 // `parent_external_key` — both surface keyed on the offending field as
@@ -52,7 +59,7 @@ func NewHandler(storage *storage.Storage) *Handler {
 // path reached the storage layer and tripped the FK constraint,
 // surfacing as 500 internal_error.
 func (handler *Handler) resolveParent(
-	r *http.Request, orgID int, parentID *int, parentExternalKey *string,
+	r *http.Request, orgID int, parentID *int, parentExternalKey *string, currentParent *int,
 ) (*int, *modelerrors.FieldError) {
 	hasID := parentID != nil
 	hasExt := parentExternalKey != nil && *parentExternalKey != ""
@@ -69,7 +76,13 @@ func (handler *Handler) resolveParent(
 				Message: err.Error(),
 			}
 		}
-		if parent == nil {
+		visible := parent != nil
+		if visible && (currentParent == nil || *currentParent != parent.ID) {
+			if visible, err = handler.isVisible(r, orgID, parent.ID); err != nil {
+				return nil, &modelerrors.FieldError{Field: "parent_id", Code: "internal_error", Message: err.Error()}
+			}
+		}
+		if !visible {
 			return nil, &modelerrors.FieldError{
 				Field:   "parent_id",
 				Code:    "fk_not_found",
@@ -80,6 +93,10 @@ func (handler *Handler) resolveParent(
 	}
 
 	parent, err := handler.storage.GetLocationByExternalKey(r.Context(), orgID, *parentExternalKey)
+	visible := err == nil && parent != nil
+	if visible && (currentParent == nil || *currentParent != parent.ID) {
+		visible, err = handler.isVisible(r, orgID, parent.ID)
+	}
 	if err != nil {
 		return nil, &modelerrors.FieldError{
 			Field:   "parent_external_key",
@@ -87,7 +104,7 @@ func (handler *Handler) resolveParent(
 			Message: err.Error(),
 		}
 	}
-	if parent == nil {
+	if !visible {
 		return nil, &modelerrors.FieldError{
 			Field:   "parent_external_key",
 			Code:    "fk_not_found",
@@ -213,7 +230,7 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 			// Both non-null: resolve the natural key and verify it matches
 			// the supplied surrogate. resolveParent returns fk_not_found if
 			// the natural key doesn't exist.
-			extResolved, fErr := handler.resolveParent(r, orgID, nil, request.ParentExternalKey)
+			extResolved, fErr := handler.resolveParent(r, orgID, nil, request.ParentExternalKey, nil)
 			if fErr != nil {
 				if fErr.Code == "internal_error" {
 					httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, fErr.Message, requestID)
@@ -247,7 +264,7 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resolved, fErr := handler.resolveParent(r, orgID, request.ParentID, request.ParentExternalKey)
+	resolved, fErr := handler.resolveParent(r, orgID, request.ParentID, request.ParentExternalKey, nil)
 	if fErr != nil {
 		if fErr.Code == "internal_error" {
 			httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
@@ -262,6 +279,9 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	request.ParentID = resolved
+	if resolved == nil && !handler.requireRootAllowed(w, r, requestID, orgID, nil) {
+		return
+	}
 
 	if request.IsActive == nil {
 		t := true
@@ -554,7 +574,7 @@ func (handler *Handler) doUpdate(w http.ResponseWriter, req *http.Request, orgID
 			// Both non-null: resolve the natural key and check it
 			// matches the supplied surrogate. resolveParent returns
 			// fk_not_found if the natural key doesn't exist.
-			extResolved, fErr := handler.resolveParent(req, orgID, nil, request.ParentExternalKey)
+			extResolved, fErr := handler.resolveParent(req, orgID, nil, request.ParentExternalKey, current.ParentID)
 			if fErr != nil {
 				if fErr.Code == "internal_error" {
 					httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal, fErr.Message, reqID)
@@ -615,7 +635,7 @@ func (handler *Handler) doUpdate(w http.ResponseWriter, req *http.Request, orgID
 	// resolution used at create time. resolveParent returns the resolved
 	// surrogate id (or a fk_not_found field error if the natural key has
 	// no matching live row).
-	resolved, fErr := handler.resolveParent(req, orgID, request.ParentID, request.ParentExternalKey)
+	resolved, fErr := handler.resolveParent(req, orgID, request.ParentID, request.ParentExternalKey, current.ParentID)
 	if fErr != nil {
 		if fErr.Code == "internal_error" {
 			httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal,
@@ -630,6 +650,9 @@ func (handler *Handler) doUpdate(w http.ResponseWriter, req *http.Request, orgID
 		return
 	}
 	request.ParentID = resolved
+	if request.ClearParentID && !handler.requireRootAllowed(w, req, reqID, orgID, &id) {
+		return
+	}
 
 	// TRA-770 BB58 F1: when the PATCH actually changes parent_id to a non-null
 	// value, reject any assignment that would create a cycle. Routes both the
@@ -894,10 +917,14 @@ type ListDescendantsResponse struct {
 // @Param parent_id            query []int    false "filter by parent id (canonical, may repeat); mutually exclusive with parent_external_key (400 ambiguous_fields if both supplied)" collectionFormat(multi)
 // @Param parent_external_key query []string false "filter by parent's external_key (may repeat); mutually exclusive with parent_id (400 ambiguous_fields if both supplied)" collectionFormat(multi)
 // @Param external_key         query []string false "filter by location external_key, equality match (may repeat for any-of)" collectionFormat(multi)
+// @Param team_id             query []int    false "filter by assigned team id (may repeat for any-of)" collectionFormat(multi)
 // @Param is_active           query bool   false "filter by active flag"
 // @Param include_deleted     query bool   false "when true, include soft-deleted rows in the response. deleted_at is populated for those rows. Orthogonal to is_active." default(false)
 // @Param q                   query string false "substring search (case-insensitive) on name, external_key, description, and active tag values"
-// @Param sort                query []string false "comma-separated, prefix '-' for DESC" collectionFormat(csv) Enums(external_key, -external_key, name, -name, created_at, -created_at)
+// @Param as_of               query string false "RFC 3339 instant to evaluate the validity window at instead of now, e.g. 2026-01-01T00:00:00Z" format(date-time)
+// @Param filter              query string false "filter expression, e.g. `metadata.zone eq \"cold\" and created_at gt 2024-01-01`. Operators eq, ne, gt, ge, lt, le, contains, in; combine with and, or, not and parentheses. Fields: external_key, name, description, parent_external_key, id, parent_id, team_id, is_active, valid_from, valid_to, created_at, updated_at, metadata.<key>"
+// @Param sort                query []string false "comma-separated, prefix '-' for DESC" collectionFormat(csv) Enums(external_key, -external_key, name, -name, created_at, -created_at, updated_at, -updated_at)
+// @Param fields              query []string false "sparse fieldset: comma-separated item keys to return (id is always included). Omitting tags also skips loading them." collectionFormat(csv)
 // @Success 200 {object} locations.ListLocationsResponse
 // @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
 // @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
@@ -917,9 +944,10 @@ func (handler *Handler) ListLocations(w http.ResponseWriter, req *http.Request)
 	}
 
 	params, err := httputil.ParseListParams(req, httputil.ListAllowlist{
-		Filters:     []string{"parent_id", "parent_external_key", "external_key", "is_active", "include_deleted", "q"},
+		Filters:     []string{"parent_id", "parent_external_key", "external_key", "is_active", "include_deleted", "q", "team_id", "filter", "as_of"},
 		BoolFilters: []string{"is_active", "include_deleted"},
-		Sorts:       []string{"external_key", "name", "created_at"},
+		Sorts:       []string{"external_key", "name", "created_at", "updated_at"},
+		Fields:      location.PublicFields,
 	})
 	if err != nil {
 		httputil.RespondListParamError(w, req, err, reqID)
@@ -955,7 +983,22 @@ func (handler *Handler) ListLocations(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
+	filter, fe := httputil.ParseFilterParam(params.Filters["filter"], location.FilterFields)
+	if fe != nil {
+		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
+		return
+	}
+
+	asOf, fe := httputil.ParseAsOfParam(params.Filters["as_of"])
+	if fe != nil {
+		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
+		return
+	}
+
 	f := location.ListFilter{
+		AsOf:               asOf,
+		Filter:             filter,
+		Fields:             params.Fields,
 		ParentExternalKeys: params.Filters["parent_external_key"],
 		ExternalKeys:       params.Filters["external_key"],
 		Limit:              params.Limit,
@@ -976,6 +1019,21 @@ func (handler *Handler) ListLocations(w http.ResponseWriter, req *http.Request)
 			f.ParentIDs = append(f.ParentIDs, n)
 		}
 	}
+	if vs, ok := params.Filters["team_id"]; ok && len(vs) > 0 {
+		f.TeamIDs = make([]int, 0, len(vs))
+		for _, s := range vs {
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 1 {
+				httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{{
+					Field:   "team_id",
+					Code:    "invalid_value",
+					Message: fmt.Sprintf("team_id %q must be a positive integer", s),
+				}})
+				return
+			}
+			f.TeamIDs = append(f.TeamIDs, n)
+		}
+	}
 	if vs, ok := params.Filters["is_active"]; ok && len(vs) > 0 {
 		b := vs[0] == "true"
 		f.IsActive = &b
@@ -989,6 +1047,10 @@ func (handler *Handler) ListLocations(w http.ResponseWriter, req *http.Request)
 	for _, s := range params.Sorts {
 		f.Sorts = append(f.Sorts, location.ListSort{Field: s.Field, Desc: s.Desc})
 	}
+	if f.TeamScope, f.SubtreeScope, err = handler.accessScopes(req, orgID); err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
 
 	items, err := handler.storage.ListLocationsFiltered(req.Context(), orgID, f)
 	if err != nil {
@@ -1011,21 +1073,22 @@ func (handler *Handler) ListLocations(w http.ResponseWriter, req *http.Request)
 		out = append(out, location.ToPublicLocationView(l))
 	}
 
-	httputil.WriteJSON(w, http.StatusOK, ListLocationsResponse{
+	httputil.WriteSparseJSON(w, http.StatusOK, ListLocationsResponse{
 		Data:       out,
 		Limit:      params.Limit,
 		Offset:     params.Offset,
 		TotalCount: total,
-	})
+	}, params.Fields)
 }
 
 // @Summary Get location by ID
 // @Description Retrieve a location by its canonical ID. Returns 404 if not found.
 // @Description
-// @Description Path-addressed retrieval bypasses the temporal-validity filter applied on list endpoints — any non-deleted location is returned regardless of its `valid_from` / `valid_to` values. Use this endpoint when you have an id and need the row even if its effective window has elapsed.
+// @Description Path-addressed retrieval bypasses the temporal-validity filter applied on list endpoints — any non-deleted location is returned regardless of its `valid_from` / `valid_to` values. Use this endpoint when you have an id and need the row even if its effective window has elapsed. Pass `as_of` to apply the window at that instant instead: a location not effective then returns 404.
 // @Tags locations,public
 // @ID locations.get
 // @Param location_id path int true "Location ID" minimum(1) format(int64)
+// @Param as_of query string false "RFC 3339 instant; 404 unless the location's validity window contains it" format(date-time)
 // @Success 200 {object} locations.GetLocationResponse
 // @Failure 400 {object} modelerrors.ErrorResponse
 // @Failure 401 {object} modelerrors.ErrorResponse
@@ -1049,6 +1112,11 @@ func (handler *Handler) GetLocation(w http.ResponseWriter, req *http.Request) {
 		httputil.RespondPathParamError(w, req, err, reqID)
 		return
 	}
+	asOf, fe := httputil.ParseAsOfParam(req.URL.Query()["as_of"])
+	if fe != nil {
+		httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{*fe})
+		return
+	}
 
 	view, err := handler.storage.GetLocationViewByID(req.Context(), orgID, id)
 	if err != nil {
@@ -1057,10 +1125,13 @@ func (handler *Handler) GetLocation(w http.ResponseWriter, req *http.Request) {
 
 		return
 	}
-	if view == nil {
+	if view == nil || (asOf != nil && !shared.EffectiveAt(view.ValidFrom, view.ValidTo, *asOf)) {
 		httputil.Respond404(w, req, apierrors.LocationNotFound, reqID)
 		return
 	}
+	if !handler.requireVisible(w, req, reqID, orgID, view.ID) {
+		return
+	}
 
 	withParent := location.LocationWithParent{LocationView: *view}
 	if view.ParentID != nil {
@@ -1114,7 +1185,13 @@ func (handler *Handler) GetAncestors(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	results, err := handler.storage.ListAncestorsPaginated(req.Context(), orgID, id, params.Limit, params.Offset)
+	// Ancestors above the caller's granted subtrees stay hidden.
+	_, scope, err := handler.accessScopes(req, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	results, err := handler.storage.ListAncestorsPaginated(req.Context(), orgID, id, scope, params.Limit, params.Offset)
 	if err != nil {
 		httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal,
 			err.Error(), reqID)
@@ -1122,7 +1199,7 @@ func (handler *Handler) GetAncestors(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	total, err := handler.storage.CountAncestors(req.Context(), orgID, id)
+	total, err := handler.storage.CountAncestors(req.Context(), orgID, id, scope)
 	if err != nil {
 		httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal,
 			err.Error(), reqID)
@@ -1348,6 +1425,10 @@ func (handler *Handler) doAddLocationTag(w http.ResponseWriter, r *http.Request,
 	}
 
 	tag, err := handler.storage.AddTagToLocation(r.Context(), orgID, locationID, request)
+	if errors.Is(err, storage.ErrTagTargetNotFound) {
+		httputil.Respond404(w, r, apierrors.LocationNotFound, requestID)
+		return
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "already exist") {
 			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict,
@@ -1458,6 +1539,9 @@ func (handler *Handler) parseAndVerifyLocationID(w http.ResponseWriter, req *htt
 		httputil.Respond404(w, req, apierrors.LocationNotFound, reqID)
 		return 0, false
 	}
+	if !handler.requireVisible(w, req, reqID, orgID, loc.ID) {
+		return 0, false
+	}
 
 	return loc.ID, true
 }
@@ -0,0 +1,160 @@
+package locations
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// checkSchedule reports what the struct tags cannot: that the time zone is
+// a known IANA zone, shift times are HH:MM, and shift names and weekdays do
+// not repeat.
+func checkSchedule(in location.ScheduleInput) []modelerrors.FieldError {
+	var errs []modelerrors.FieldError
+	if in.TimeZone != nil {
+		if _, err := time.LoadLocation(*in.TimeZone); err != nil || *in.TimeZone == "Local" || *in.TimeZone == "" {
+			errs = append(errs, modelerrors.FieldError{
+				Field:   "time_zone",
+				Code:    "invalid_value",
+				Message: "time_zone must be an IANA time zone such as America/Chicago",
+			})
+		}
+	}
+	names := map[string]bool{}
+	for i, sh := range in.Shifts {
+		for _, c := range []struct{ field, value string }{{"start", sh.Start}, {"end", sh.End}} {
+			if _, err := location.ParseClock(c.value); err != nil {
+				errs = append(errs, modelerrors.FieldError{
+					Field: fmt.Sprintf("shifts[%d].%s", i, c.field), Code: "invalid_value",
+					Message: fmt.Sprintf("%s must be an HH:MM time such as 06:00", c.field),
+				})
+			}
+		}
+		if names[sh.Name] {
+			errs = append(errs, modelerrors.FieldError{
+				Field: fmt.Sprintf("shifts[%d].name", i), Code: "duplicate",
+				Message: fmt.Sprintf("shift %q is defined twice", sh.Name),
+			})
+		}
+		names[sh.Name] = true
+		days := slices.Clone(sh.Weekdays)
+		slices.Sort(days)
+		if len(slices.Compact(days)) != len(sh.Weekdays) {
+			errs = append(errs, modelerrors.FieldError{
+				Field: fmt.Sprintf("shifts[%d].weekdays", i), Code: "duplicate",
+				Message: "weekdays must not repeat a day",
+			})
+		}
+	}
+	return errs
+}
+
+// @Summary Get a location's schedule
+// @Description **Required scope:** `locations:read`
+// @Description
+// @Description The location's reporting time zone and shifts. `time_zone` is the IANA zone set on this location, null when it inherits; `effective_time_zone` is the one reports use — its own, else the nearest ancestor's, else UTC. Shifts are recurring periods in that local time; an `end` at or before `start` runs past midnight.
+// @Tags locations,public
+// @ID locations.schedule.get
+// @Produce json
+// @Param location_id path int true "Location ID" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: location.Schedule"
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "forbidden"
+// @Failure 404 {object} modelerrors.ErrorResponse "not_found"
+// @Failure 429 {object} modelerrors.ErrorResponse "rate_limited"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security BearerAuth[locations:read]
+// @Router /api/v1/locations/{location_id}/schedule [get]
+func (handler *Handler) GetSchedule(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyLocationID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	out, err := handler.storage.GetLocationSchedule(req.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	if out == nil {
+		httputil.Respond404(w, req, apierrors.LocationNotFound, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": out})
+}
+
+// @Summary Set a location's schedule
+// @Description **Required scope:** `locations:write`
+// @Description
+// @Description Full-replace of the location's time zone and shifts. A null or omitted `time_zone` inherits the parent's. Each shift has a unique `name`, local `start` and `end` times (HH:MM; an end at or before the start runs into the next day) and the ISO `weekdays` it starts on, 1 (Monday) to 7 (Sunday), every day when empty. At most 24 shifts. Daily, shift and shift presence reports (GET /api/v1/reports/locations/{location_id}/activity and .../shift-presence) and utilization seen-days use the effective zone, so changing it re-buckets past sightings too.
+// @Tags locations,public
+// @ID locations.schedule.put
+// @Accept json
+// @Produce json
+// @Param location_id path int                    true "Location ID" minimum(1) format(int64)
+// @Param request     body location.ScheduleInput true "Time zone and shifts"
+// @Success 200 {object} map[string]any "data: location.Schedule"
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "forbidden"
+// @Failure 404 {object} modelerrors.ErrorResponse "not_found"
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 429 {object} modelerrors.ErrorResponse "rate_limited"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security BearerAuth[locations:write]
+// @Router /api/v1/locations/{location_id}/schedule [put]
+func (handler *Handler) PutSchedule(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyLocationID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	var request location.ScheduleInput
+	if err := httputil.DecodeJSONStrict(req, &request); err != nil {
+		httputil.RespondDecodeError(w, req, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, req, err, reqID)
+		return
+	}
+	if errs := checkSchedule(request); len(errs) > 0 {
+		httputil.WriteValidationError(w, req, reqID, errs)
+		return
+	}
+
+	out, err := handler.storage.SetLocationSchedule(req.Context(), orgID, id, request)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	if out == nil {
+		httputil.Respond404(w, req, apierrors.LocationNotFound, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": out})
+}
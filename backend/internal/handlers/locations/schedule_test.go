@@ -0,0 +1,42 @@
+package locations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trakrf/platform/backend/internal/models/location"
+)
+
+func TestCheckSchedule(t *testing.T) {
+	zone := func(s string) *string { return &s }
+	fields := func(in location.ScheduleInput) []string {
+		var out []string
+		for _, fe := range checkSchedule(in) {
+			out = append(out, fe.Field)
+		}
+		return out
+	}
+
+	assert.Empty(t, fields(location.ScheduleInput{}), "inherit with no shifts")
+	assert.Empty(t, fields(location.ScheduleInput{
+		TimeZone: zone("Europe/Berlin"),
+		Shifts: []location.Shift{
+			{Name: "Day", Start: "06:00", End: "14:00", Weekdays: []int{1, 2, 3, 4, 5}},
+			{Name: "Night", Start: "22:00", End: "06:00"},
+		},
+	}))
+
+	assert.Equal(t, []string{"time_zone"}, fields(location.ScheduleInput{TimeZone: zone("Mars/Olympus")}))
+	assert.Equal(t, []string{"time_zone"}, fields(location.ScheduleInput{TimeZone: zone("Local")}))
+	assert.Equal(t, []string{"time_zone"}, fields(location.ScheduleInput{TimeZone: zone("")}))
+	assert.Equal(t, []string{"shifts[0].start", "shifts[0].end"}, fields(location.ScheduleInput{
+		Shifts: []location.Shift{{Name: "Day", Start: "6am", End: "24:00"}},
+	}))
+	assert.Equal(t, []string{"shifts[1].name", "shifts[1].weekdays"}, fields(location.ScheduleInput{
+		Shifts: []location.Shift{
+			{Name: "Day", Start: "06:00", End: "14:00"},
+			{Name: "Day", Start: "14:00", End: "22:00", Weekdays: []int{1, 1}},
+		},
+	}))
+}
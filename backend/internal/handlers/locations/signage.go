@@ -0,0 +1,156 @@
+package locations
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/signage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// MaxSignageLocations bounds one signage PDF (84 pages).
+const MaxSignageLocations = 500
+
+// @Summary Print location signage
+// @Description **Required scope:** `locations:read`
+// @Description
+// @Description A printable A4 PDF of location signs, six to a page with cut lines: each sign is a QR code of the location's deep link (`{origin}/locations/{location_id}`) with its name, external key and parent path. With `subtree=true` the location's descendants follow it in tree order, at most 500 signs in all. The link's origin is the request's Origin header when it is an allowed link domain of the environment or the org, else the app's base URL.
+// @Tags locations,public
+// @ID locations.signage
+// @Param location_id path  int  true  "Location ID" minimum(1) format(int64)
+// @Param subtree     query bool false "include every descendant" default(false)
+// @Produce application/pdf
+// @Success 200 {file} file "signage PDF"
+// @Failure 400 {object} modelerrors.ErrorResponse "bad_request"
+// @Failure 401 {object} modelerrors.ErrorResponse "unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "forbidden"
+// @Failure 404 {object} modelerrors.ErrorResponse "not_found"
+// @Failure 429 {object} modelerrors.ErrorResponse "rate_limited"
+// @Failure 500 {object} modelerrors.ErrorResponse "internal_error"
+// @Security BearerAuth[locations:read]
+// @Router /api/v1/locations/{location_id}/signage [get]
+func (handler *Handler) GetSignage(w http.ResponseWriter, req *http.Request) {
+	reqID := middleware.GetRequestID(req.Context())
+
+	orgID, err := middleware.GetRequestOrgID(req)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, req, reqID)
+		return
+	}
+
+	id, ok := handler.parseAndVerifyLocationID(w, req, orgID, reqID)
+	if !ok {
+		return
+	}
+
+	subtree := false
+	if raw := req.URL.Query().Get("subtree"); raw != "" {
+		if subtree, err = strconv.ParseBool(strings.TrimSpace(raw)); err != nil {
+			httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{{
+				Field:   "subtree",
+				Code:    "invalid_value",
+				Message: "subtree must be true or false",
+			}})
+			return
+		}
+	}
+	if subtree && handler.guardTreeCycle(w, req, orgID, id, reqID) {
+		return
+	}
+
+	ctx := req.Context()
+	root, err := handler.storage.GetLocationByID(ctx, orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	if root == nil {
+		httputil.Respond404(w, req, apierrors.LocationNotFound, reqID)
+		return
+	}
+	_, scope, err := handler.accessScopes(req, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	ancestors, err := handler.storage.GetAncestors(ctx, orgID, id, scope)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	ld, err := handler.storage.GetLinkDomains(ctx, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, req, err, reqID)
+		return
+	}
+	var orgOrigins []string
+	if ld != nil {
+		orgOrigins = ld.Origins
+	}
+	origin := applinks.FromEnv().Origin(req.Header.Get("Origin"), orgOrigins)
+
+	rootPath := make([]string, len(ancestors))
+	for i, a := range ancestors {
+		rootPath[i] = a.Name
+	}
+	signs := []signage.Sign{{
+		URL: applinks.LocationURL(origin, root.ID), Name: root.Name, ExternalKey: root.ExternalKey, Path: rootPath,
+	}}
+	if subtree {
+		descendants, err := handler.storage.ListDescendantsPaginated(ctx, orgID, id, MaxSignageLocations, 0)
+		if err != nil {
+			httputil.RespondStorageError(w, req, err, reqID)
+			return
+		}
+		if len(descendants) >= MaxSignageLocations {
+			httputil.WriteValidationError(w, req, reqID, []modelerrors.FieldError{{
+				Field:   "subtree",
+				Code:    "too_large",
+				Message: fmt.Sprintf("the subtree has more than %d locations; print a smaller subtree", MaxSignageLocations),
+			}})
+			return
+		}
+		// Tree order puts every parent before its children, so each path
+		// extends its parent's.
+		paths := map[int][]string{root.ID: rootPath}
+		names := map[int]string{root.ID: root.Name}
+		for _, d := range descendants {
+			var path []string
+			if p := d.ParentID; p != nil {
+				path = append(append([]string{}, paths[*p]...), names[*p])
+			}
+			paths[d.ID], names[d.ID] = path, d.Name
+			signs = append(signs, signage.Sign{
+				URL: applinks.LocationURL(origin, d.ID), Name: d.Name, ExternalKey: d.ExternalKey, Path: path,
+			})
+		}
+	}
+
+	pdf, err := signage.RenderPDF(signs)
+	if err != nil {
+		httputil.WriteJSONError(w, req, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="signage-%s.pdf"`, safeFilename(root.ExternalKey)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(pdf)
+}
+
+// safeFilename keeps the letters, digits, dots, dashes and underscores of
+// an external key for a download name.
+func safeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
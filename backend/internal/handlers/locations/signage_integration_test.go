@@ -0,0 +1,95 @@
+//go:build integration
+// +build integration
+
+// GET /api/v1/locations/{id}/signage renders a location's sign, or with
+// subtree=true its descendants' too, as one PDF.
+
+package locations
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func getSignage(t *testing.T, handler *Handler, orgID, id int, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Get("/api/v1/locations/{location_id}/signage", handler.GetSignage)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/locations/%d/signage%s", id, query), nil)
+	req = withDeleteConflictOrgContext(req, orgID)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetSignage_Single(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+
+	site := seedLocationDC(t, pool, orgID, "SITE-1", "Site", nil)
+	dock := seedLocationDC(t, pool, orgID, "DOCK-1", "Dock", &site)
+
+	rec := getSignage(t, NewHandler(store), orgID, dock, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="signage-DOCK-1.pdf"`, rec.Header().Get("Content-Disposition"))
+
+	body := rec.Body.Bytes()
+	assert.True(t, bytes.HasPrefix(body, []byte("%PDF-")))
+	assert.Contains(t, string(body), fmt.Sprintf("/locations/%d)", dock))
+	assert.Contains(t, string(body), "(Site)")
+	assert.NotContains(t, string(body), fmt.Sprintf("/locations/%d)", site))
+}
+
+func TestGetSignage_Subtree(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+
+	site := seedLocationDC(t, pool, orgID, "SITE-2", "Site", nil)
+	hall := seedLocationDC(t, pool, orgID, "HALL-2", "Hall", &site)
+	bay := seedLocationDC(t, pool, orgID, "BAY-2", "Bay", &hall)
+
+	rec := getSignage(t, NewHandler(store), orgID, site, "?subtree=true")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	body := string(rec.Body.Bytes())
+	for _, id := range []int{site, hall, bay} {
+		assert.Contains(t, body, fmt.Sprintf("/locations/%d)", id))
+	}
+	assert.Contains(t, body, "(Site / Hall)")
+}
+
+func TestGetSignage_Errors(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+
+	site := seedLocationDC(t, pool, orgID, "SITE-3", "Site", nil)
+	handler := NewHandler(store)
+
+	rec := getSignage(t, handler, orgID, site, "?subtree=maybe")
+	assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+	rec = getSignage(t, handler, orgID, site+1000000, "")
+	assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+}
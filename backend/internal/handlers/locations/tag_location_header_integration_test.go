@@ -65,7 +65,7 @@ func TestAddLocationTag_201_EmitsLocationHeader(t *testing.T) {
 	handler := NewHandler(store)
 	router := setupLocationTagLocationHeaderRouter(handler)
 
-	body := strings.NewReader(`{"tag_type":"rfid","value":"E2-707-LOC"}`)
+	body := strings.NewReader(`{"tag_type":"rfid","value":"E2070700"}`)
 	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/v1/locations/%d/tags", locID), body)
 	req.Header.Set("Content-Type", "application/json")
 	req = withLocationTagLocationOrgContext(req, orgID)
@@ -1,37 +1,62 @@
 package lookup
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/logger"
 	"github.com/trakrf/platform/backend/internal/middleware"
 	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/scanguard"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
 )
 
 type Handler struct {
 	storage *storage.Storage
+	budget  *scanguard.Budget
 }
 
 func NewHandler(storage *storage.Storage) *Handler {
 	return &Handler{
 		storage: storage,
+		budget:  scanguard.NewBudget(scanguard.DefaultConfig()),
 	}
 }
 
+// meter records a lookup's known and unknown tag values against the scan
+// source. When the source has run through its unknown-tag budget it writes
+// 429 and reports false; the caller must then withhold the results.
+func (h *Handler) meter(w http.ResponseWriter, r *http.Request, orgID, known, unknown int, requestID string) bool {
+	source := middleware.GetScanSource(r).Label()
+	ok, retryAfter := h.budget.Record(source, known, unknown)
+	if !ok {
+		logger.Get().Warn().
+			Str("request_id", requestID).
+			Int("org_id", orgID).
+			Str("source", source).
+			Int("unknown", unknown).
+			Msg("unknown-tag budget exhausted; tag lookup throttled")
+		httputil.Respond429(w, r, retryAfter, requestID)
+	}
+	return ok
+}
+
 // @Summary Lookup entity by tag
-// @Description Find an asset or location by tag value
+// @Description Find an asset or location by tag value. Session callers must present a registered reader credential in the X-Reader-Key header unless the org has turned on session scans (scan-settings); a reader, API key or user that submits too many unknown tag values is throttled with 429.
 // @Tags lookup,internal
 // @Accept json
 // @Produce json
 // @Param type query string true "Tag type (rfid, ble, barcode)"
 // @Param value query string true "Tag value to search for"
+// @Param X-Reader-Key header string false "Reader credential of a registered scan device"
 // @Success 200 {object} map[string]any "data: storage.LookupResult"
 // @Failure 400 {object} modelerrors.ErrorResponse "Missing required parameters"
+// @Failure 403 {object} modelerrors.ErrorResponse "Missing, invalid or revoked reader credential"
 // @Failure 404 {object} modelerrors.ErrorResponse "No entity found with this tag"
+// @Failure 429 {object} modelerrors.ErrorResponse "Unknown-tag budget exhausted"
+// @Header  429 {integer} Retry-After "Seconds to wait before retrying"
 // @Failure 500 {object} modelerrors.ErrorResponse "Internal server error"
 // @Security SessionAuth
 // @Router /api/v1/lookup/tag [get]
@@ -70,6 +95,14 @@ func (h *Handler) LookupByTag(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	known, unknown := 1, 0
+	if result == nil {
+		known, unknown = 0, 1
+	}
+	if !h.meter(w, r, orgID, known, unknown, requestID) {
+		return
+	}
+
 	if result == nil {
 		httputil.Respond404(w, r, apierrors.LookupNotFound, requestID)
 		return
@@ -85,14 +118,18 @@ type BatchLookupRequest struct {
 }
 
 // @Summary Batch lookup entities by tags
-// @Description Find assets or locations by multiple tag values
+// @Description Find assets or locations by multiple tag values. Values the org does not know are omitted from the result. Session callers must present a registered reader credential in the X-Reader-Key header unless the org has turned on session scans (scan-settings); a reader, API key or user that submits too many unknown tag values is throttled with 429.
 // @Tags lookup,internal
 // @Accept json
 // @Produce json
 // @Param request body BatchLookupRequest true "Batch lookup request"
+// @Param X-Reader-Key header string false "Reader credential of a registered scan device"
 // @Success 200 {object} map[string]any "data: map[string]*storage.LookupResult"
 // @Failure 400 {object} modelerrors.ErrorResponse "Invalid request body or missing required fields"
+// @Failure 403 {object} modelerrors.ErrorResponse "Missing, invalid or revoked reader credential"
 // @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 429 {object} modelerrors.ErrorResponse "Unknown-tag budget exhausted"
+// @Header  429 {integer} Retry-After "Seconds to wait before retrying"
 // @Failure 500 {object} modelerrors.ErrorResponse "Internal server error"
 // @Security SessionAuth
 // @Router /api/v1/lookup/tags [post]
@@ -107,10 +144,8 @@ func (h *Handler) LookupByTags(w http.ResponseWriter, r *http.Request) {
 	orgID := *claims.CurrentOrgID
 
 	var req BatchLookupRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			"invalid request body", requestID)
-
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, requestID)
 		return
 	}
 
@@ -144,10 +179,25 @@ func (h *Handler) LookupByTags(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	unknown := 0
+	for _, v := range req.Values {
+		if results[v] == nil {
+			unknown++
+		}
+	}
+	if !h.meter(w, r, orgID, len(req.Values)-unknown, unknown, requestID) {
+		return
+	}
+
 	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": results})
 }
 
+// RegisterRoutes wires the tag lookups onto r. Both are bound to their source
+// by RequireScanCredential so unknown-tag lookups are throttled per reader,
+// API key or user.
 func (h *Handler) RegisterRoutes(r chi.Router) {
-	r.Get("/api/v1/lookup/tag", h.LookupByTag)
-	r.Post("/api/v1/lookup/tags", h.LookupByTags)
+	scanCredential := middleware.RequireScanCredential(h.storage)
+
+	r.With(scanCredential).Get("/api/v1/lookup/tag", h.LookupByTag)
+	r.With(scanCredential).Post("/api/v1/lookup/tags", h.LookupByTags)
 }
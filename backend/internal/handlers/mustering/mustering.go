@@ -162,22 +162,8 @@ func (h *Handler) ListEvents(w http.ResponseWriter, r *http.Request) {
 // GetEvent returns one event with entries + counts (+ report when completed).
 func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
 	reqID := middleware.GetRequestID(r.Context())
-	orgID, _, _, ok := h.userClaims(w, r, reqID)
-	if !ok {
-		return
-	}
-	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
-	if err != nil {
-		httputil.RespondPathParamError(w, r, err, reqID)
-		return
-	}
-	ev, err := h.store.GetMusterEvent(r.Context(), orgID, id)
-	if err != nil {
-		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
-		return
-	}
+	_, ev := h.loadEvent(w, r, reqID)
 	if ev == nil {
-		httputil.Respond404(w, r, "muster event not found", reqID)
 		return
 	}
 	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": ev})
@@ -0,0 +1,131 @@
+package mustering
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/muster"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// rollCallColumns is the header of the roll-call CSV export.
+var rollCallColumns = []string{
+	"asset_id", "label", "status", "accounted",
+	"expected_location", "muster_location", "first_muster_seen_at",
+	"verified_at", "verified_by", "marked_safe_at", "marked_safe_by", "marked_safe_note",
+}
+
+// loadEvent resolves the {id} event for the request's org, writing the error
+// response and returning nil when it cannot.
+func (h *Handler) loadEvent(w http.ResponseWriter, r *http.Request, reqID string) (int, *muster.Event) {
+	orgID, _, _, ok := h.userClaims(w, r, reqID)
+	if !ok {
+		return 0, nil
+	}
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, nil
+	}
+	ev, err := h.store.GetMusterEvent(r.Context(), orgID, id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return 0, nil
+	}
+	if ev == nil {
+		httputil.Respond404(w, r, "muster event not found", reqID)
+		return 0, nil
+	}
+	return orgID, ev
+}
+
+// GetRollCall returns the event's people split into accounted and
+// unaccounted. Polled during an active event it is the live roll call; the
+// stream carries the same changes as deltas.
+func (h *Handler) GetRollCall(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	_, ev := h.loadEvent(w, r, reqID)
+	if ev == nil {
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": muster.NewRollCall(*ev)})
+}
+
+// ExportRollCall downloads the event's roll call as CSV, one row per person
+// in entry order, with location names and who verified or marked each one
+// safe. Exported after all-clear it is the event's final record.
+func (h *Handler) ExportRollCall(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, ev := h.loadEvent(w, r, reqID)
+	if ev == nil {
+		return
+	}
+	zones, err := h.store.ListZones(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	names := make(map[int]string, len(zones))
+	for _, z := range zones {
+		names[z.LocationID] = z.Name
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="muster-event-%d.csv"`, ev.ID))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	cw := csv.NewWriter(w)
+	_ = cw.Write(rollCallColumns)
+	for _, en := range ev.Entries {
+		_ = cw.Write(rollCallRow(en, names))
+	}
+	cw.Flush()
+}
+
+// rollCallRow renders one entry in rollCallColumns order. Locations are
+// written by name, falling back to the id for one no longer live.
+func rollCallRow(en muster.Entry, names map[int]string) []string {
+	loc := func(id *int) string {
+		if id == nil {
+			return ""
+		}
+		if n, ok := names[*id]; ok {
+			return csvText(n)
+		}
+		return strconv.Itoa(*id)
+	}
+	ts := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	user := func(id *int) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.Itoa(*id)
+	}
+	return []string{
+		strconv.Itoa(en.AssetID), csvText(en.Label), en.Status, strconv.FormatBool(en.Accounted()),
+		loc(en.ExpectedLocationID), loc(en.MusterLocationID), ts(en.FirstMusterSeenAt),
+		ts(en.VerifiedAt), user(en.VerifiedBy), ts(en.MarkedSafeAt), user(en.MarkedSafeBy), csvText(en.MarkedSafeNote),
+	}
+}
+
+// csvText neutralises user text a spreadsheet would evaluate as a formula.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
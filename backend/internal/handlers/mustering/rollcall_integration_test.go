@@ -0,0 +1,80 @@
+//go:build integration
+
+package mustering_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/muster"
+)
+
+// TestRollCall_LiveSplitAndExport: with half the people at a muster point the
+// roll call splits them, and the export after all-clear lists everyone.
+func TestRollCall_LiveSplitAndExport(t *testing.T) {
+	r, db, orgID := newMusterServer(t)
+
+	rr := doJSON(t, r, orgID, http.MethodPost, "/api/v1/mustering/seed", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	rr = doJSON(t, r, orgID, http.MethodPost, "/api/v1/mustering/events", map[string]any{"window_minutes": 60})
+	require.Equal(t, http.StatusCreated, rr.Code, rr.Body.String())
+	var created struct {
+		Data muster.Event `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &created))
+	ev := created.Data
+	require.Greater(t, len(ev.Entries), 1)
+
+	mp, err := db.Store.GetLocationByExternalKey(context.Background(), orgID, "MUSTER-MP-001")
+	require.NoError(t, err)
+	require.NotNil(t, mp)
+	arrived := len(ev.Entries) / 2
+	var sightings []map[string]int
+	for _, en := range ev.Entries[:arrived] {
+		sightings = append(sightings, map[string]int{"asset_id": en.AssetID, "location_id": mp.ID})
+	}
+	rr = doJSON(t, r, orgID, http.MethodPost, "/api/v1/mustering/simulate", map[string]any{"sightings": sightings})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	base := "/api/v1/mustering/events/" + strconv.Itoa(ev.ID)
+	rr = doJSON(t, r, orgID, http.MethodGet, base+"/roll-call", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var rc struct {
+		Data muster.RollCall `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &rc))
+	require.Equal(t, "active", rc.Data.Status)
+	require.Len(t, rc.Data.Accounted, arrived)
+	require.Len(t, rc.Data.Unaccounted, len(ev.Entries)-arrived)
+	for _, en := range rc.Data.Unaccounted {
+		require.Equal(t, "missing", en.Status)
+	}
+
+	rr = doJSON(t, r, orgID, http.MethodPost, base+"/all-clear", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	rr = doJSON(t, r, orgID, http.MethodGet, base+"/roll-call/export", nil)
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	require.Contains(t, rr.Header().Get("Content-Type"), "text/csv")
+	rows, err := csv.NewReader(strings.NewReader(rr.Body.String())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, len(ev.Entries)+1, "a header and one row per person")
+	require.Equal(t, "accounted", rows[0][3])
+	atMuster := 0
+	for _, row := range rows[1:] {
+		if row[5] == mp.Name {
+			atMuster++
+		}
+	}
+	require.Equal(t, arrived, atMuster, "muster points are written by name")
+
+	rr = doJSON(t, r, orgID, http.MethodGet, "/api/v1/mustering/events/999999999/roll-call/export", nil)
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
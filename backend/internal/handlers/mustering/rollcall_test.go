@@ -0,0 +1,31 @@
+package mustering
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/muster"
+)
+
+func TestRollCallRow(t *testing.T) {
+	at := time.Date(2026, 10, 16, 9, 30, 0, 0, time.FixedZone("CDT", -5*3600))
+	en := muster.Entry{
+		AssetID:            42,
+		Label:              "=Dana",
+		Status:             "safe_manual",
+		ExpectedLocationID: intPtr(3),
+		MusterLocationID:   intPtr(99),
+		MarkedSafeBy:       intPtr(5),
+		MarkedSafeAt:       &at,
+		MarkedSafeNote:     "working remotely",
+	}
+	row := rollCallRow(en, map[int]string{3: "Warehouse"})
+	require.Len(t, row, len(rollCallColumns))
+	require.Equal(t, []string{
+		"42", "'=Dana", "safe_manual", "true",
+		"Warehouse", "99", "",
+		"", "", "2026-10-16T14:30:00Z", "5", "working remotely",
+	}, row)
+}
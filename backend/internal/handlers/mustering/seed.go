@@ -155,7 +155,7 @@ func (h *Handler) ensurePersonAsset(ctx context.Context, orgID int, extKey, name
 		OrgID:       orgID,
 		ExternalKey: extKey,
 		Name:        name,
-		Metadata:    map[string]any{"person": true},
+		Metadata:    map[string]any{asset.PersonMetadataKey: true},
 		IsActive:    true,
 	})
 	if err != nil {
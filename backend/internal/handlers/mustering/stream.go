@@ -100,6 +100,8 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Post("/api/v1/mustering/events", h.CreateEvent)
 	r.Get("/api/v1/mustering/events", h.ListEvents)
 	r.Get("/api/v1/mustering/events/{id}", h.GetEvent)
+	r.Get("/api/v1/mustering/events/{id}/roll-call", h.GetRollCall)
+	r.Get("/api/v1/mustering/events/{id}/roll-call/export", h.ExportRollCall)
 	r.Post("/api/v1/mustering/events/{id}/all-clear", h.AllClear)
 	r.Post("/api/v1/mustering/events/{id}/cancel", h.Cancel)
 	r.Post("/api/v1/mustering/events/{id}/unlock", h.Unlock)
@@ -0,0 +1,358 @@
+package offlinesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/offline"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+)
+
+// checkMutations enforces the per-op shape rules the struct tags cannot
+// express. Field names carry the mutation's index so the device knows which
+// queued entry is malformed.
+func checkMutations(ms []offline.Mutation) []modelerrors.FieldError {
+	var problems []modelerrors.FieldError
+	add := func(i int, field, code, msg string) {
+		problems = append(problems, modelerrors.FieldError{
+			Field: fmt.Sprintf("mutations[%d].%s", i, field), Code: code, Message: msg,
+		})
+	}
+	seen := map[string]bool{}
+	for i, m := range ms {
+		if seen[m.ClientMutationID] {
+			add(i, "client_mutation_id", "invalid_value", "client_mutation_id is repeated within the request")
+		}
+		seen[m.ClientMutationID] = true
+
+		switch {
+		case m.Op == offline.OpCreate && m.ID != nil:
+			add(i, "id", "invalid_context", "create takes no id; the server assigns it")
+		case m.Op == offline.OpCreate && m.ExternalKey == "":
+			add(i, "external_key", "required", "create must name the external_key later mutations refer to")
+		case m.Op != offline.OpCreate && (m.ID == nil) == (m.ExternalKey == ""):
+			add(i, "id", "ambiguous_fields", "name the target by exactly one of id or external_key")
+		}
+
+		switch m.Op {
+		case offline.OpCreate, offline.OpUpdate:
+			if m.Fields == nil {
+				add(i, "fields", "required", m.Op+" requires fields")
+			} else if m.Op == offline.OpCreate && m.Fields.Name == nil {
+				add(i, "fields.name", "required", "create requires fields.name")
+			}
+			if m.Tag != nil {
+				add(i, "tag", "invalid_context", m.Op+" takes no tag; queue an add_tag mutation")
+			}
+		case offline.OpAddTag, offline.OpRemoveTag:
+			if m.Tag == nil {
+				add(i, "tag", "required", m.Op+" requires tag")
+			}
+			if m.Fields != nil {
+				add(i, "fields", "invalid_context", m.Op+" takes no fields")
+			}
+		case offline.OpDelete:
+			if m.Fields != nil || m.Tag != nil {
+				add(i, "fields", "invalid_context", "delete takes no fields or tag")
+			}
+		}
+
+		if m.Fields != nil {
+			if m.Entity == offline.EntityAsset && m.Fields.ParentExternalKey != nil {
+				add(i, "fields.parent_external_key", "invalid_context", "parent_external_key applies to locations")
+			}
+			if m.Entity == offline.EntityLocation && m.Fields.Metadata != nil {
+				add(i, "fields.metadata", "invalid_context", "metadata applies to assets")
+			}
+		}
+	}
+	return problems
+}
+
+// batch carries one request's state across its mutations.
+type batch struct {
+	h      *Handler
+	orgID  int
+	scope  offline.Scope
+	policy string
+	// versions maps an entity to the updated_at this request's own edits left
+	// it at, so a queue of edits to one entity — all made against the copy
+	// the device last pulled — does not conflict with itself.
+	versions map[string]time.Time
+}
+
+func versionKey(entity string, id int) string {
+	return entity + ":" + strconv.Itoa(id)
+}
+
+// remember records the version an applied mutation produced.
+func (b *batch) remember(m offline.Mutation, res *offline.MutationResult) {
+	if res.Status == offline.StatusApplied && res.ID != nil && res.UpdatedAt != nil {
+		b.versions[versionKey(m.Entity, *res.ID)] = *res.UpdatedAt
+	}
+}
+
+// stale reports whether the server copy changed after the copy m was made
+// against, other than by this request's own earlier edits.
+func (b *batch) stale(m offline.Mutation, t *offline.Target) bool {
+	if m.BaseUpdatedAt == nil {
+		return false
+	}
+	if v, ok := b.versions[versionKey(m.Entity, t.ID)]; ok && t.UpdatedAt.Equal(v) {
+		return false
+	}
+	return t.UpdatedAt.After(*m.BaseUpdatedAt)
+}
+
+func conflict(res *offline.MutationResult, reason, msg string) *offline.MutationResult {
+	res.Status, res.Reason, res.Message = offline.StatusConflict, reason, msg
+	return res
+}
+
+func rejected(res *offline.MutationResult, reason, msg string) *offline.MutationResult {
+	res.Status, res.Reason, res.Message = offline.StatusRejected, reason, msg
+	return res
+}
+
+// apply runs one mutation. ctx carries the mutation's transaction, so the
+// target lock and every write below commit together with the recorded
+// outcome. Only unexpected storage failures are returned as errors; anything
+// the device can act on is an outcome.
+func (b *batch) apply(ctx context.Context, m offline.Mutation) (*offline.MutationResult, error) {
+	res := &offline.MutationResult{ClientMutationID: m.ClientMutationID, Entity: m.Entity}
+	if m.Op == offline.OpCreate {
+		return b.create(ctx, m, res)
+	}
+
+	t, err := b.h.storage.LockSyncTarget(ctx, b.orgID, m.Entity, m.ID, m.ExternalKey, b.scope)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return rejected(res, offline.ReasonNotFound, m.Entity+" not found"), nil
+	}
+	res.ID = &t.ID
+
+	if t.DeletedAt != nil {
+		if m.Op == offline.OpDelete || m.Op == offline.OpRemoveTag {
+			res.Status = offline.StatusApplied
+			return res, nil
+		}
+		res.ServerDeletedAt = t.DeletedAt
+		return conflict(res, offline.ReasonDeleted, m.Entity+" was deleted on the server"), nil
+	}
+
+	if (m.Op == offline.OpUpdate || m.Op == offline.OpDelete) &&
+		b.policy != offline.PolicyClientWins && b.stale(m, t) {
+		res.ServerUpdatedAt = &t.UpdatedAt
+		return conflict(res, offline.ReasonStale, m.Entity+" was changed on the server after base_updated_at"), nil
+	}
+
+	switch m.Op {
+	case offline.OpUpdate:
+		err = b.update(ctx, m, t, res)
+	case offline.OpDelete:
+		if m.Entity == offline.EntityAsset {
+			_, err = b.h.storage.DeleteAsset(ctx, b.orgID, t.ID)
+		} else {
+			_, err = b.h.storage.DeleteLocation(ctx, b.orgID, t.ID)
+		}
+	case offline.OpAddTag:
+		err = b.addTag(ctx, m, t)
+	case offline.OpRemoveTag:
+		err = b.removeTag(ctx, m, t)
+	}
+	if err != nil {
+		return outcomeForError(res, err)
+	}
+	if res.Status == "" {
+		res.Status = offline.StatusApplied
+	}
+	return res, nil
+}
+
+func (b *batch) create(ctx context.Context, m offline.Mutation, res *offline.MutationResult) (*offline.MutationResult, error) {
+	existing, err := b.h.storage.LockSyncTarget(ctx, b.orgID, m.Entity, nil, m.ExternalKey, b.scope)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.DeletedAt == nil {
+		res.ID = &existing.ID
+		res.ServerUpdatedAt = &existing.UpdatedAt
+		return conflict(res, offline.ReasonExists, fmt.Sprintf("%s %q already exists", m.Entity, m.ExternalKey)), nil
+	}
+
+	f := m.Fields
+	active := true
+	if f.IsActive != nil {
+		active = *f.IsActive
+	}
+	validFrom := &shared.FlexibleDate{Time: b.h.now().UTC()}
+
+	if m.Entity == offline.EntityAsset {
+		req := asset.CreateAssetWithTagsRequest{CreateAssetRequest: asset.CreateAssetRequest{
+			OrgID:       b.orgID,
+			ExternalKey: m.ExternalKey,
+			Name:        *f.Name,
+			Description: f.Description,
+			ValidFrom:   validFrom,
+			IsActive:    &active,
+		}}
+		if f.Metadata != nil {
+			req.Metadata = *f.Metadata
+		}
+		v, err := b.h.storage.CreateAssetWithTags(ctx, req)
+		if err != nil {
+			return outcomeForError(res, err)
+		}
+		res.Status, res.ID, res.UpdatedAt = offline.StatusApplied, &v.ID, &v.UpdatedAt
+		return res, nil
+	}
+
+	req := location.CreateLocationWithTagsRequest{CreateLocationRequest: location.CreateLocationRequest{
+		Name:        *f.Name,
+		ExternalKey: m.ExternalKey,
+		Description: f.Description,
+		ValidFrom:   validFrom,
+		IsActive:    &active,
+	}}
+	if f.ParentExternalKey != nil {
+		parent, err := b.parent(ctx, *f.ParentExternalKey)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			return rejected(res, offline.ReasonNotFound,
+				fmt.Sprintf("parent_external_key %q not found", *f.ParentExternalKey)), nil
+		}
+		req.ParentID = &parent.ID
+	}
+	v, err := b.h.storage.CreateLocationWithTags(ctx, b.orgID, req)
+	if err != nil {
+		return outcomeForError(res, err)
+	}
+	res.Status, res.ID, res.UpdatedAt = offline.StatusApplied, &v.ID, v.UpdatedAt
+	return res, nil
+}
+
+// parent resolves a live parent location by external key, nil when there is
+// none the caller can see.
+func (b *batch) parent(ctx context.Context, externalKey string) (*offline.Target, error) {
+	p, err := b.h.storage.LockSyncTarget(ctx, b.orgID, offline.EntityLocation, nil, externalKey, b.scope)
+	if err != nil || p == nil || p.DeletedAt != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (b *batch) update(ctx context.Context, m offline.Mutation, t *offline.Target, res *offline.MutationResult) error {
+	f := m.Fields
+	if m.Entity == offline.EntityAsset {
+		v, err := b.h.storage.UpdateAsset(ctx, b.orgID, t.ID, asset.UpdateAssetRequest{
+			Name:        f.Name,
+			Description: f.Description,
+			Metadata:    f.Metadata,
+			IsActive:    f.IsActive,
+		})
+		if err != nil {
+			return err
+		}
+		if v != nil {
+			res.UpdatedAt = &v.UpdatedAt
+		}
+		return nil
+	}
+
+	req := location.UpdateLocationRequest{
+		Name:        f.Name,
+		Description: f.Description,
+		IsActive:    f.IsActive,
+	}
+	if f.ParentExternalKey != nil {
+		parent, err := b.parent(ctx, *f.ParentExternalKey)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			rejected(res, offline.ReasonNotFound, fmt.Sprintf("parent_external_key %q not found", *f.ParentExternalKey))
+			return nil
+		}
+		cycle, err := b.h.storage.WouldCreateLocationCycle(ctx, b.orgID, t.ID, parent.ID)
+		if err != nil {
+			return err
+		}
+		if cycle {
+			rejected(res, offline.ReasonInvalid, "parent_external_key would make the location its own ancestor")
+			return nil
+		}
+		req.ParentID = &parent.ID
+	}
+	v, err := b.h.storage.UpdateLocation(ctx, b.orgID, t.ID, req)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		res.UpdatedAt = v.UpdatedAt
+	}
+	return nil
+}
+
+// addTag attaches the tag unless the target already carries it, so a replayed
+// add after a lost outcome record is harmless.
+func (b *batch) addTag(ctx context.Context, m offline.Mutation, t *offline.Target) error {
+	tagType := m.Tag.GetType()
+	existing, err := b.h.storage.FindEntityTagID(ctx, b.orgID, m.Entity, t.ID, tagType, m.Tag.Value)
+	if err != nil || existing != 0 {
+		return err
+	}
+	if m.Entity == offline.EntityAsset {
+		_, err = b.h.storage.AddTagToAsset(ctx, b.orgID, t.ID, *m.Tag)
+	} else {
+		_, err = b.h.storage.AddTagToLocation(ctx, b.orgID, t.ID, *m.Tag)
+	}
+	return err
+}
+
+// removeTag detaches the tag by type and value: tags added offline have no
+// id on the device.
+func (b *batch) removeTag(ctx context.Context, m offline.Mutation, t *offline.Target) error {
+	tagID, err := b.h.storage.FindEntityTagID(ctx, b.orgID, m.Entity, t.ID, m.Tag.GetType(), m.Tag.Value)
+	if err != nil || tagID == 0 {
+		return err
+	}
+	if m.Entity == offline.EntityAsset {
+		_, err = b.h.storage.RemoveAssetTag(ctx, b.orgID, t.ID, tagID)
+	} else {
+		_, err = b.h.storage.RemoveLocationTag(ctx, b.orgID, t.ID, tagID)
+	}
+	return err
+}
+
+// outcomeForError turns a write failure the device can act on into an
+// outcome: uniqueness collisions (an external key or tag value taken on the
+// server) are conflicts; values the database refuses are rejections. Anything
+// else is returned as an error.
+func outcomeForError(res *offline.MutationResult, err error) (*offline.MutationResult, error) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "23505":
+			return conflict(res, offline.ReasonExists, "conflicts with an existing record"), nil
+		case pgErr.Code == "23514", strings.HasPrefix(pgErr.Code, "22"):
+			return rejected(res, offline.ReasonInvalid, "contains data that cannot be stored as-is"), nil
+		}
+		return nil, err
+	}
+	if strings.Contains(err.Error(), "already exist") {
+		return conflict(res, offline.ReasonExists, err.Error()), nil
+	}
+	return nil, err
+}
@@ -0,0 +1,276 @@
+// Package offlinesync serves the handheld app's offline sync protocol: a
+// cursor-paged change feed of the org's assets, locations and tags, and a
+// mutation endpoint that replays edits queued while the device had no signal,
+// reporting per-mutation conflicts instead of failing the upload.
+package offlinesync
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/offline"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// SyncStorage is the storage surface the handler needs (mockable).
+type SyncStorage interface {
+	TeamScopeForUser(ctx context.Context, orgID, userID int) (team.Scope, error)
+	LocationSubtreeScopeForUser(ctx context.Context, orgID, userID int) (location.SubtreeScope, error)
+	ListSyncChanges(ctx context.Context, orgID int, f offline.ChangesFilter) (*offline.Changes, error)
+	RunSyncMutation(ctx context.Context, orgID int, clientMutationID string, apply func(ctx context.Context) (*offline.MutationResult, error)) (*offline.MutationResult, error)
+	LockSyncTarget(ctx context.Context, orgID int, entity string, id *int, externalKey string, scope offline.Scope) (*offline.Target, error)
+	FindEntityTagID(ctx context.Context, orgID int, entity string, entityID int, tagType, value string) (int, error)
+	WouldCreateLocationCycle(ctx context.Context, orgID, locationID, proposedParentID int) (bool, error)
+
+	CreateAssetWithTags(ctx context.Context, request asset.CreateAssetWithTagsRequest) (*asset.AssetView, error)
+	UpdateAsset(ctx context.Context, orgID, id int, request asset.UpdateAssetRequest) (*asset.AssetView, error)
+	DeleteAsset(ctx context.Context, orgID, id int) (bool, error)
+	AddTagToAsset(ctx context.Context, orgID, assetID int, req shared.TagRequest) (*shared.Tag, error)
+	RemoveAssetTag(ctx context.Context, orgID, assetID, tagID int) (bool, error)
+
+	CreateLocationWithTags(ctx context.Context, orgID int, request location.CreateLocationWithTagsRequest) (*location.LocationWithParent, error)
+	UpdateLocation(ctx context.Context, orgID, id int, request location.UpdateLocationRequest) (*location.LocationWithParent, error)
+	DeleteLocation(ctx context.Context, orgID, id int) (bool, error)
+	AddTagToLocation(ctx context.Context, orgID, locationID int, req shared.TagRequest) (*shared.Tag, error)
+	RemoveLocationTag(ctx context.Context, orgID, locationID, tagID int) (bool, error)
+}
+
+type Handler struct {
+	storage SyncStorage
+	now     func() time.Time
+}
+
+func NewHandler(storage SyncStorage) *Handler {
+	return &Handler{storage: storage, now: time.Now}
+}
+
+// ChangesResponse is the envelope returned by GET /api/v1/sync/changes.
+type ChangesResponse struct {
+	Data offline.Changes `json:"data"`
+}
+
+// MutationsResponse is the envelope returned by POST /api/v1/sync/mutations.
+type MutationsResponse struct {
+	Data MutationsResult `json:"data"`
+}
+
+// MutationsResult lists one outcome per submitted mutation, in order.
+type MutationsResult struct {
+	Results []offline.MutationResult `json:"results"`
+}
+
+// scope resolves the caller's visibility. API-key callers are org-wide
+// integrations and are never restricted.
+func (h *Handler) scope(r *http.Request, orgID int) (offline.Scope, error) {
+	if middleware.GetAPIKeyPrincipal(r) != nil {
+		return offline.Scope{}, nil
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		return offline.Scope{}, nil
+	}
+	ts, err := h.storage.TeamScopeForUser(r.Context(), orgID, claims.UserID)
+	if err != nil {
+		return offline.Scope{}, err
+	}
+	ss, err := h.storage.LocationSubtreeScopeForUser(r.Context(), orgID, claims.UserID)
+	if err != nil {
+		return offline.Scope{}, err
+	}
+	return offline.Scope{Team: ts, Locations: ss}, nil
+}
+
+func encodeCursor(c offline.Cursor) string {
+	return base64.RawURLEncoding.EncodeToString(
+		[]byte(strconv.FormatInt(c.At.UnixNano(), 10) + ":" + strconv.Itoa(c.ID)))
+}
+
+func decodeCursor(s string) (*offline.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, errInvalidCursor
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	i, err := strconv.Atoi(id)
+	if err != nil || i < 0 {
+		return nil, errInvalidCursor
+	}
+	return &offline.Cursor{At: time.Unix(0, n).UTC(), ID: i}, nil
+}
+
+// @Summary      Pull offline sync changes
+// @Description  **Required scopes:** `assets:read` and `locations:read`
+// @Description
+// @Description  Returns the org's assets, locations and tags changed after `since`, oldest first, for a device keeping an offline copy. Omit `since` for a full sync: every live row, no tombstones. Pass the returned `next_cursor` as `since` on the next pull and keep pulling while `has_more` is true. A tombstone means drop the entity — it was deleted or is no longer visible to the caller — and a tombstoned asset or location drops its tags with it. Changes from the last few seconds are held back until in-flight writes have committed, so a pull right after a write may not include it yet. When the caller's team membership or location access changes, start over with a full sync. Timestamps keep full precision; send `updated_at` back unchanged as a mutation's `base_updated_at`.
+// @Tags         sync,public
+// @ID           sync.changes
+// @Produce      json
+// @Param        since  query  string  false  "Cursor from a previous pull"
+// @Param        limit  query  int     false  "Max changes per page (default 500, max 1000)" minimum(1) maximum(1000)
+// @Success      200  {object}  offlinesync.ChangesResponse
+// @Failure      400  {object}  modelerrors.ErrorResponse "validation_error"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[assets:read]
+// @Router       /api/v1/sync/changes [get]
+func (h *Handler) Changes(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	invalid := func(field, msg string) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: field, Code: "invalid_value", Message: msg,
+		}})
+	}
+	q := r.URL.Query()
+	for key := range q {
+		if key != "since" && key != "limit" {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field: key, Code: "unknown_field", Message: "unknown query parameter " + key,
+			}})
+			return
+		}
+	}
+	f := offline.ChangesFilter{Limit: offline.DefaultChangesLimit}
+	since := q.Get("since")
+	if since != "" {
+		if f.After, err = decodeCursor(since); err != nil {
+			invalid("since", "since must be a next_cursor returned by this endpoint")
+			return
+		}
+	}
+	if raw := q.Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > offline.MaxChangesLimit {
+			invalid("limit", "limit must be an integer between 1 and 1000")
+			return
+		}
+		f.Limit = n
+	}
+
+	if f.Scope, err = h.scope(r, orgID); err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+
+	changes, err := h.storage.ListSyncChanges(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	changes.NextCursor = since
+	if changes.Last != nil {
+		changes.NextCursor = encodeCursor(*changes.Last)
+	}
+	httputil.WriteJSON(w, http.StatusOK, ChangesResponse{Data: *changes})
+}
+
+// @Summary      Push offline mutations
+// @Description  **Required scopes:** `assets:write` and `locations:write`
+// @Description
+// @Description  Replays edits a device queued while offline, in order. Each mutation creates, updates or deletes an asset or location, or adds or removes one of its tags. The target is named by `id`, or by `external_key` for entities created offline. Creates must name their `external_key`.
+// @Description
+// @Description  Every mutation carries a client-generated `client_mutation_id`. Its outcome is recorded, so re-sending a mutation (after a lost response) returns the recorded outcome with `replayed: true` instead of applying it twice. Outcomes are kept for 30 days.
+// @Description
+// @Description  Updates and deletes carry `base_updated_at`, the `updated_at` of the copy the edit was made against. When the server copy has changed since, the default `conflict_policy` `server_wins` skips the mutation and reports `status: conflict`, `reason: stale` with the server's `updated_at`; `client_wins` applies it anyway. Editing an entity deleted on the server is always a conflict (`reason: deleted`); deleting it again, or removing a tag it no longer has, is applied as a no-op. Creating an entity whose external key is already in use is a conflict (`reason: exists`) carrying the existing entity's id. A mutation that cannot be applied as sent is `status: rejected`.
+// @Description
+// @Description  The request only fails as a whole when it is malformed (400). At most 200 mutations per request.
+// @Tags         sync,public
+// @ID           sync.mutations
+// @Accept       json
+// @Produce      json
+// @Param        request body offline.MutationsRequest true "Queued mutations"
+// @Success      200  {object}  offlinesync.MutationsResponse
+// @Failure      400  {object}  modelerrors.ErrorResponse "validation_error"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      415  {object}  modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[assets:write]
+// @Router       /api/v1/sync/mutations [post]
+func (h *Handler) Mutations(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	var req offline.MutationsRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if problems := checkMutations(req.Mutations); len(problems) > 0 {
+		httputil.WriteValidationError(w, r, reqID, problems)
+		return
+	}
+
+	scope, err := h.scope(r, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+
+	b := &batch{
+		h:        h,
+		orgID:    orgID,
+		scope:    scope,
+		policy:   req.ConflictPolicy,
+		versions: map[string]time.Time{},
+	}
+	results := make([]offline.MutationResult, 0, len(req.Mutations))
+	for _, m := range req.Mutations {
+		res, err := h.storage.RunSyncMutation(r.Context(), orgID, m.ClientMutationID,
+			func(ctx context.Context) (*offline.MutationResult, error) {
+				return b.apply(ctx, m)
+			})
+		if err != nil {
+			// Earlier mutations have committed and are recorded, so the
+			// device can resend the whole queue.
+			httputil.RespondStorageError(w, r, err, reqID)
+			return
+		}
+		b.remember(m, res)
+		results = append(results, *res)
+	}
+	httputil.WriteJSON(w, http.StatusOK, MutationsResponse{Data: MutationsResult{Results: results}})
+}
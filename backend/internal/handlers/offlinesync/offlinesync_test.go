@@ -0,0 +1,295 @@
+package offlinesync
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/offline"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+var testNow = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+// mockSyncStorage keeps assets keyed by id; every update advances the row's
+// updated_at by a second.
+type mockSyncStorage struct {
+	assets   map[int]*offline.Target
+	recorded map[string]offline.MutationResult
+
+	changesFilter *offline.ChangesFilter
+	changes       *offline.Changes
+	updates       []int
+	nextID        int
+}
+
+func newMock() *mockSyncStorage {
+	return &mockSyncStorage{assets: map[int]*offline.Target{}, recorded: map[string]offline.MutationResult{}, nextID: 100}
+}
+
+func (m *mockSyncStorage) TeamScopeForUser(ctx context.Context, orgID, userID int) (team.Scope, error) {
+	return team.Scope{}, nil
+}
+
+func (m *mockSyncStorage) LocationSubtreeScopeForUser(ctx context.Context, orgID, userID int) (location.SubtreeScope, error) {
+	return location.SubtreeScope{}, nil
+}
+
+func (m *mockSyncStorage) ListSyncChanges(ctx context.Context, orgID int, f offline.ChangesFilter) (*offline.Changes, error) {
+	m.changesFilter = &f
+	if m.changes != nil {
+		return m.changes, nil
+	}
+	return &offline.Changes{}, nil
+}
+
+func (m *mockSyncStorage) RunSyncMutation(ctx context.Context, orgID int, id string, apply func(ctx context.Context) (*offline.MutationResult, error)) (*offline.MutationResult, error) {
+	if res, ok := m.recorded[id]; ok {
+		res.Replayed = true
+		return &res, nil
+	}
+	res, err := apply(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.recorded[id] = *res
+	return res, nil
+}
+
+func (m *mockSyncStorage) LockSyncTarget(ctx context.Context, orgID int, entity string, id *int, externalKey string, scope offline.Scope) (*offline.Target, error) {
+	for _, t := range m.assets {
+		if (id != nil && t.ID == *id) || (id == nil && t.ExternalKey == externalKey) {
+			c := *t
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockSyncStorage) FindEntityTagID(ctx context.Context, orgID int, entity string, entityID int, tagType, value string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockSyncStorage) WouldCreateLocationCycle(ctx context.Context, orgID, locationID, proposedParentID int) (bool, error) {
+	return false, nil
+}
+
+func (m *mockSyncStorage) CreateAssetWithTags(ctx context.Context, req asset.CreateAssetWithTagsRequest) (*asset.AssetView, error) {
+	m.nextID++
+	m.assets[m.nextID] = &offline.Target{ID: m.nextID, ExternalKey: req.ExternalKey, UpdatedAt: testNow}
+	return &asset.AssetView{Asset: asset.Asset{ID: m.nextID, ExternalKey: req.ExternalKey, UpdatedAt: testNow}}, nil
+}
+
+func (m *mockSyncStorage) UpdateAsset(ctx context.Context, orgID, id int, req asset.UpdateAssetRequest) (*asset.AssetView, error) {
+	t := m.assets[id]
+	t.UpdatedAt = t.UpdatedAt.Add(time.Second)
+	m.updates = append(m.updates, id)
+	return &asset.AssetView{Asset: asset.Asset{ID: id, UpdatedAt: t.UpdatedAt}}, nil
+}
+
+func (m *mockSyncStorage) DeleteAsset(ctx context.Context, orgID, id int) (bool, error) {
+	return true, nil
+}
+
+func (m *mockSyncStorage) AddTagToAsset(ctx context.Context, orgID, assetID int, req shared.TagRequest) (*shared.Tag, error) {
+	return &shared.Tag{}, nil
+}
+
+func (m *mockSyncStorage) RemoveAssetTag(ctx context.Context, orgID, assetID, tagID int) (bool, error) {
+	return true, nil
+}
+
+func (m *mockSyncStorage) CreateLocationWithTags(ctx context.Context, orgID int, req location.CreateLocationWithTagsRequest) (*location.LocationWithParent, error) {
+	return &location.LocationWithParent{}, nil
+}
+
+func (m *mockSyncStorage) UpdateLocation(ctx context.Context, orgID, id int, req location.UpdateLocationRequest) (*location.LocationWithParent, error) {
+	return &location.LocationWithParent{}, nil
+}
+
+func (m *mockSyncStorage) DeleteLocation(ctx context.Context, orgID, id int) (bool, error) {
+	return true, nil
+}
+
+func (m *mockSyncStorage) AddTagToLocation(ctx context.Context, orgID, locationID int, req shared.TagRequest) (*shared.Tag, error) {
+	return &shared.Tag{}, nil
+}
+
+func (m *mockSyncStorage) RemoveLocationTag(ctx context.Context, orgID, locationID, tagID int) (bool, error) {
+	return true, nil
+}
+
+func newHandler(m *mockSyncStorage) *Handler {
+	h := NewHandler(m)
+	h.now = func() time.Time { return testNow }
+	return h
+}
+
+// serve routes through chi without the auth/scope middleware so handler
+// logic is exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Get("/api/v1/sync/changes", h.Changes)
+		r.Post("/api/v1/sync/mutations", h.Mutations)
+	}, req)
+}
+
+func results(t *testing.T, w *httptest.ResponseRecorder) []offline.MutationResult {
+	t.Helper()
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var resp MutationsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data.Results
+}
+
+func TestChanges_CursorRoundTrip(t *testing.T) {
+	last := offline.Cursor{At: testNow.Add(123456 * time.Microsecond), ID: 77}
+	m := newMock()
+	m.changes = &offline.Changes{Last: &last, HasMore: true}
+	w := serve(newHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/sync/changes?limit=50", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.changesFilter.After != nil || m.changesFilter.Limit != 50 {
+		t.Errorf("full sync filter = %+v", m.changesFilter)
+	}
+	var resp ChangesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	m.changes = &offline.Changes{}
+	w = serve(newHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/sync/changes?since="+resp.Data.NextCursor, ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if got := m.changesFilter.After; got == nil || !got.At.Equal(last.At) || got.ID != 77 {
+		t.Errorf("after = %+v, want %+v", got, last)
+	}
+	// An empty page hands back the cursor it was read from.
+	if !strings.Contains(w.Body.String(), `"next_cursor":"`+resp.Data.NextCursor+`"`) {
+		t.Errorf("body = %s", w.Body.String())
+	}
+}
+
+func TestChanges_RejectsBadQuery(t *testing.T) {
+	for _, q := range []string{"since=not-a-cursor", "limit=0", "limit=5000", "cursor=abc"} {
+		w := serve(newHandler(newMock()), testutil.NewRequest(t, http.MethodGet, "/api/v1/sync/changes?"+q, ""))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d", q, w.Code)
+		}
+	}
+}
+
+func TestMutations_StaleUpdateFollowsPolicy(t *testing.T) {
+	base := testNow.Format(time.RFC3339Nano)
+	body := func(policy string) string {
+		return `{"conflict_policy":"` + policy + `","mutations":[{"client_mutation_id":"m-` + policy +
+			`","entity":"asset","op":"update","id":7,"base_updated_at":"` + base + `","fields":{"name":"Pallet 7"}}]}`
+	}
+
+	m := newMock()
+	m.assets[7] = &offline.Target{ID: 7, ExternalKey: "P7", UpdatedAt: testNow.Add(time.Minute)}
+	res := results(t, serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sync/mutations", body("server_wins"))))
+	if res[0].Status != offline.StatusConflict || res[0].Reason != offline.ReasonStale || res[0].ServerUpdatedAt == nil {
+		t.Fatalf("server_wins result = %+v", res[0])
+	}
+	if len(m.updates) != 0 {
+		t.Fatal("stale update must not be applied under server_wins")
+	}
+
+	res = results(t, serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sync/mutations", body("client_wins"))))
+	if res[0].Status != offline.StatusApplied || len(m.updates) != 1 {
+		t.Fatalf("client_wins result = %+v, updates = %v", res[0], m.updates)
+	}
+}
+
+func TestMutations_QueuedEditsDoNotConflictWithEachOther(t *testing.T) {
+	m := newMock()
+	m.assets[7] = &offline.Target{ID: 7, ExternalKey: "P7", UpdatedAt: testNow}
+	base := testNow.Format(time.RFC3339Nano)
+	body := `{"mutations":[
+		{"client_mutation_id":"a","entity":"asset","op":"update","id":7,"base_updated_at":"` + base + `","fields":{"name":"Pallet 7"}},
+		{"client_mutation_id":"b","entity":"asset","op":"update","id":7,"base_updated_at":"` + base + `","fields":{"is_active":false}}]}`
+
+	res := results(t, serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sync/mutations", body)))
+	if res[0].Status != offline.StatusApplied || res[1].Status != offline.StatusApplied {
+		t.Fatalf("results = %+v", res)
+	}
+
+	// Replaying the queue returns the recorded outcomes without re-applying.
+	res = results(t, serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sync/mutations", body)))
+	if !res[0].Replayed || !res[1].Replayed || len(m.updates) != 2 {
+		t.Fatalf("replay results = %+v, updates = %v", res, m.updates)
+	}
+}
+
+func TestMutations_CreateThenEditByExternalKey(t *testing.T) {
+	m := newMock()
+	deleted := testNow.Add(-time.Hour)
+	m.assets[8] = &offline.Target{ID: 8, ExternalKey: "GONE", UpdatedAt: deleted, DeletedAt: &deleted}
+	body := `{"mutations":[
+		{"client_mutation_id":"c1","entity":"asset","op":"create","external_key":"NEW-1","fields":{"name":"New"}},
+		{"client_mutation_id":"c2","entity":"asset","op":"update","external_key":"NEW-1","fields":{"name":"Renamed"}},
+		{"client_mutation_id":"c3","entity":"asset","op":"create","external_key":"NEW-1","fields":{"name":"Again"}},
+		{"client_mutation_id":"c4","entity":"asset","op":"update","id":8,"fields":{"name":"x"}},
+		{"client_mutation_id":"c5","entity":"asset","op":"delete","id":8},
+		{"client_mutation_id":"c6","entity":"asset","op":"delete","id":9}]}`
+
+	res := results(t, serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sync/mutations", body)))
+	want := []struct{ status, reason string }{
+		{offline.StatusApplied, ""},
+		{offline.StatusApplied, ""},
+		{offline.StatusConflict, offline.ReasonExists},
+		{offline.StatusConflict, offline.ReasonDeleted},
+		{offline.StatusApplied, ""},
+		{offline.StatusRejected, offline.ReasonNotFound},
+	}
+	for i, w := range want {
+		if res[i].Status != w.status || res[i].Reason != w.reason {
+			t.Errorf("result %d = %+v, want %s/%s", i, res[i], w.status, w.reason)
+		}
+	}
+	if *res[2].ID != *res[0].ID {
+		t.Errorf("exists conflict should name the created asset, got %+v", res[2])
+	}
+}
+
+func TestMutations_RejectsMalformedBatch(t *testing.T) {
+	cases := map[string]string{
+		"create without key":   `{"mutations":[{"client_mutation_id":"a","entity":"asset","op":"create","fields":{"name":"x"}}]}`,
+		"update both targets":  `{"mutations":[{"client_mutation_id":"a","entity":"asset","op":"update","id":1,"external_key":"A","fields":{"name":"x"}}]}`,
+		"add_tag without tag":  `{"mutations":[{"client_mutation_id":"a","entity":"asset","op":"add_tag","id":1}]}`,
+		"metadata on location": `{"mutations":[{"client_mutation_id":"a","entity":"location","op":"update","id":1,"fields":{"metadata":{}}}]}`,
+		"repeated id": `{"mutations":[{"client_mutation_id":"a","entity":"asset","op":"delete","id":1},` +
+			`{"client_mutation_id":"a","entity":"asset","op":"delete","id":2}]}`,
+		"bad op":         `{"mutations":[{"client_mutation_id":"a","entity":"asset","op":"upsert","id":1}]}`,
+		"bad policy":     `{"conflict_policy":"merge","mutations":[{"client_mutation_id":"a","entity":"asset","op":"delete","id":1}]}`,
+		"empty mutation": `{"mutations":[]}`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			m := newMock()
+			w := serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sync/mutations", body))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			if len(m.recorded) != 0 {
+				t.Error("nothing should be applied")
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package orgs
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -68,10 +67,8 @@ func (h *Handler) UpdateEntitlement(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request organization.UpdateEntitlementRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
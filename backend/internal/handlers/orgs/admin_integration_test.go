@@ -44,15 +44,14 @@ func seedSessionUser(t *testing.T, pool *pgxpool.Pool, email string, superadmin
 // session auth + RequireSuperadmin, registered via the orgs handler.
 func newAdminOrgRouter(t *testing.T, store *storage.Storage) *chi.Mux {
 	t.Helper()
-	pool := store.Pool().(*pgxpool.Pool)
-	service := orgsservice.NewService(pool, store, nil)
+	service := orgsservice.NewService(store, nil)
 	handler := orgs.NewHandler(store, service, nil)
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.Auth)
 		r.Use(middleware.ContentType)
-		handler.RegisterRoutes(r, store)
+		handler.RegisterRoutes(r, store, store, store)
 	})
 	return r
 }
@@ -1,7 +1,6 @@
 package orgs
 
 import (
-	"encoding/json"
 	stderrors "errors"
 	"net/http"
 
@@ -32,7 +31,7 @@ type ListAPIKeysResponse struct {
 }
 
 // @Summary Create a new API key for an organization
-// @Description Creates API credentials scoped to the target org and returns an opaque {client_id, client_secret}. The client_secret is shown exactly once and stored only as a hash — exchange it at POST /oauth/token (grant_type=client_credentials) for a short-lived Bearer access token. Accepts either session-admin or an API key with the keys:admin scope.
+// @Description Creates API credentials scoped to the target org and returns an opaque {client_id, client_secret}. Set service_account_id to have the key owned by a service account, so requests made with it are attributed to that account in audit logs rather than to the person who minted it. The client_secret is shown exactly once and stored only as a hash — exchange it at POST /oauth/token (grant_type=client_credentials) for a short-lived Bearer access token. Accepts either session-admin or an API key with the keys:admin scope.
 // @Tags api-keys,internal
 // @ID api_keys.create
 // @Accept json
@@ -83,10 +82,8 @@ func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req apikey.CreateAPIKeyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			"Invalid JSON body", reqID)
-
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
 		return
 	}
 	if err := validate.Struct(req); err != nil {
@@ -101,6 +98,22 @@ func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
+	if req.ServiceAccountID != nil {
+		sa, err := h.storage.GetServiceAccount(r.Context(), orgID, *req.ServiceAccountID)
+		if err != nil {
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+				"Failed to resolve service account", reqID)
+			return
+		}
+		if sa == nil {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "service_account_id",
+				Code:    "invalid_value",
+				Message: "service_account_id must be a service account in this organization",
+			}})
+			return
+		}
+	}
 
 	// Soft cap
 	count, err := h.storage.CountActiveAPIKeys(r.Context(), orgID)
@@ -127,8 +140,8 @@ func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	key, err := h.storage.CreateAPIKey(r.Context(), orgID, req.Name, apisecret.Hash(secret),
-		req.Scopes, creator, req.ExpiresAt)
+	key, err := h.storage.CreateServiceAccountAPIKey(r.Context(), orgID, req.ServiceAccountID,
+		req.Name, apisecret.Hash(secret), req.Scopes, creator, req.ExpiresAt)
 	if err != nil {
 		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
 			"Failed to create api key", reqID)
@@ -137,13 +150,14 @@ func (h *Handler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	}
 
 	resp := apikey.APIKeyCreateResponse{
-		ClientID:     key.JTI,
-		ClientSecret: secret,
-		ID:           key.ID,
-		Name:         key.Name,
-		Scopes:       key.Scopes,
-		CreatedAt:    key.CreatedAt,
-		ExpiresAt:    key.ExpiresAt,
+		ClientID:         key.JTI,
+		ClientSecret:     secret,
+		ID:               key.ID,
+		Name:             key.Name,
+		Scopes:           key.Scopes,
+		CreatedAt:        key.CreatedAt,
+		ExpiresAt:        key.ExpiresAt,
+		ServiceAccountID: key.ServiceAccountID,
 	}
 	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": resp})
 }
@@ -197,15 +211,16 @@ func (h *Handler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
 	items := make([]apikey.APIKeyListItem, 0, len(keys))
 	for _, k := range keys {
 		items = append(items, apikey.APIKeyListItem{
-			ID:             k.ID,
-			JTI:            k.JTI,
-			Name:           k.Name,
-			Scopes:         k.Scopes,
-			CreatedBy:      k.CreatedBy,
-			CreatedByKeyID: k.CreatedByKeyID,
-			CreatedAt:      k.CreatedAt,
-			ExpiresAt:      k.ExpiresAt,
-			LastUsedAt:     k.LastUsedAt,
+			ID:               k.ID,
+			JTI:              k.JTI,
+			Name:             k.Name,
+			Scopes:           k.Scopes,
+			CreatedBy:        k.CreatedBy,
+			CreatedByKeyID:   k.CreatedByKeyID,
+			ServiceAccountID: k.ServiceAccountID,
+			CreatedAt:        k.CreatedAt,
+			ExpiresAt:        k.ExpiresAt,
+			LastUsedAt:       k.LastUsedAt,
 		})
 	}
 
@@ -22,6 +22,7 @@ import (
 	"github.com/trakrf/platform/backend/internal/handlers/orgs"
 	"github.com/trakrf/platform/backend/internal/middleware"
 	"github.com/trakrf/platform/backend/internal/models/apikey"
+	"github.com/trakrf/platform/backend/internal/models/serviceaccount"
 	orgsservice "github.com/trakrf/platform/backend/internal/services/orgs"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/testutil"
@@ -54,8 +55,7 @@ func seedAdminUser(t *testing.T, pool *pgxpool.Pool, orgID int) (int, string) {
 // group separate from the session-only org subtree.
 func newAdminRouter(t *testing.T, store *storage.Storage) *chi.Mux {
 	t.Helper()
-	pool := store.Pool().(*pgxpool.Pool)
-	service := orgsservice.NewService(pool, store, nil)
+	service := orgsservice.NewService(store, nil)
 	handler := orgs.NewHandler(store, service, nil)
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
@@ -374,7 +374,7 @@ func TestCreateAPIKey_EmptyBody_CleanMessage(t *testing.T) {
 	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
 	assert.Equal(t, "bad_request", resp.Error.Type)
 	assert.Equal(t, "Bad request", resp.Error.Title, "title is fixed per error.type (TRA-579 D-6)")
-	assert.Equal(t, "Invalid JSON body", resp.Error.Detail,
+	assert.Equal(t, "Request body is not valid JSON", resp.Error.Detail,
 		"detail carries the per-call message; runtime decoder text must not leak (asserts above)")
 }
 
@@ -880,3 +880,50 @@ func TestRevokeAPIKey_KeyRevokesItself_ByJTI(t *testing.T) {
 	r.ServeHTTP(w2, req2)
 	assert.Equal(t, http.StatusUnauthorized, w2.Code)
 }
+
+func TestCreateAPIKey_OwnedByServiceAccount(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-crud")
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	userID, sessionToken := seedAdminUser(t, pool, orgID)
+
+	sa, err := store.CreateServiceAccount(context.Background(), orgID, &userID,
+		serviceaccount.CreateServiceAccountRequest{Name: "ERP sync"})
+	require.NoError(t, err)
+
+	r := newAdminRouter(t, store)
+	post := func(body map[string]any) *httptest.ResponseRecorder {
+		buf, _ := json.Marshal(body)
+		req := httptest.NewRequest(http.MethodPost,
+			fmt.Sprintf("/api/v1/orgs/%d/api-keys", orgID), bytes.NewReader(buf))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+sessionToken)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(map[string]any{"name": "erp", "scopes": []string{"assets:read"}, "service_account_id": sa.ID})
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var envelope struct {
+		Data apikey.APIKeyCreateResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	require.NotNil(t, envelope.Data.ServiceAccountID)
+	assert.Equal(t, sa.ID, *envelope.Data.ServiceAccountID)
+
+	// Unknown service account → 400 on the field.
+	w = post(map[string]any{"name": "erp2", "scopes": []string{"assets:read"}, "service_account_id": sa.ID + 1})
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	assert.Contains(t, w.Body.String(), "service_account_id")
+
+	// Deleting the account revokes its keys.
+	deleted, err := store.DeleteServiceAccount(context.Background(), orgID, sa.ID)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	key, err := store.GetAPIKeyByJTI(context.Background(), envelope.Data.ClientID)
+	require.NoError(t, err)
+	assert.NotNil(t, key.RevokedAt)
+}
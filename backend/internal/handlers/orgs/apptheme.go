@@ -0,0 +1,120 @@
+package orgs
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// validateAppTheme checks the provided (non-empty) theme fields. Colors are
+// injected into the page as CSS custom properties, so only #rrggbb passes.
+func validateAppTheme(t organization.AppTheme) error {
+	if t.LogoURL != "" {
+		u, err := url.Parse(t.LogoURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" || len(t.LogoURL) > 2048 {
+			return fmt.Errorf("logo_url must be an https URL of at most 2048 characters")
+		}
+	}
+	if t.PrimaryColor != "" && !hexColorPattern.MatchString(t.PrimaryColor) {
+		return fmt.Errorf("primary_color must be a hex color like #1d4ed8")
+	}
+	if t.AccentColor != "" && !hexColorPattern.MatchString(t.AccentColor) {
+		return fmt.Errorf("accent_color must be a hex color like #f59e0b")
+	}
+	return nil
+}
+
+// @Summary Get an organization's app theme
+// @Description Internal-only. Returns the logo and colors the web app loads with on the org's white-label entry points (its link domains, <identifier>.<app host> and /o/<identifier>). Empty fields use the TrakRF defaults.
+// @Tags orgs,internal
+// @ID orgs.app_theme.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.AppTheme"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/app-theme [get]
+// GetAppTheme returns the org's app theme.
+func (h *Handler) GetAppTheme(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	theme, err := h.storage.GetAppTheme(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get app theme", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if theme == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": theme})
+}
+
+// @Summary Replace an organization's app theme
+// @Description Internal-only. Full-replace: omitted fields fall back to the TrakRF defaults. logo_url must be https; primary_color and accent_color are #rrggbb. Setting a theme, even an empty one, turns on the org's white-label entry points; the org name becomes visible on them.
+// @Tags orgs,internal
+// @ID orgs.app_theme.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.AppTheme true "App theme"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} map[string]any "data: organization.AppTheme"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/app-theme [patch]
+// PatchAppTheme replaces the org's app theme.
+func (h *Handler) PatchAppTheme(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.AppTheme
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := validateAppTheme(req); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			err.Error(), middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdateAppTheme(r.Context(), id, req); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update app theme", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": req})
+}
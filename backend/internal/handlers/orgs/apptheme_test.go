@@ -0,0 +1,32 @@
+package orgs
+
+import (
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+func TestValidateAppTheme(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      organization.AppTheme
+		wantErr bool
+	}{
+		{"all empty ok", organization.AppTheme{}, false},
+		{"valid full", organization.AppTheme{LogoURL: "https://acme.example/logo.png",
+			PrimaryColor: "#0F766e", AccentColor: "#f59e0b"}, false},
+		{"http logo", organization.AppTheme{LogoURL: "http://acme.example/logo.png"}, true},
+		{"javascript logo", organization.AppTheme{LogoURL: "javascript:alert(1)"}, true},
+		{"short primary", organization.AppTheme{PrimaryColor: "#fff"}, true},
+		{"named accent", organization.AppTheme{AccentColor: "red"}, true},
+		{"css injection", organization.AppTheme{AccentColor: "#000000;}body{display:none"}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateAppTheme(c.in)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got err=%v", c.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package orgs
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// validateCurrencySettings checks the currency is a supported ISO 4217 code
+// and the locale one reports can format with.
+func validateCurrencySettings(s organization.CurrencySettings) error {
+	if !shared.IsCurrency(s.Currency) {
+		return fmt.Errorf("currency must be a supported ISO 4217 code, e.g. USD")
+	}
+	if !shared.IsLocale(s.Locale) {
+		return fmt.Errorf("locale %q is not supported", s.Locale)
+	}
+	return nil
+}
+
+// @Summary Get an organization's currency settings
+// @Description Internal-only. Returns the currency financial fields default to when a write does not name one (USD unless set) and the locale reports format amounts with (en-US unless set).
+// @Tags orgs,internal
+// @ID orgs.currency_settings.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.CurrencySettings"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/currency-settings [get]
+// GetCurrencySettings returns the org's currency settings.
+func (h *Handler) GetCurrencySettings(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	s, err := h.storage.GetCurrencySettings(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get currency settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if s == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": s})
+}
+
+// @Summary Replace an organization's currency settings
+// @Description Internal-only. Full-replace. Changing the currency affects later writes only: stored amounts keep the currency they were written in, and reports total each currency separately.
+// @Tags orgs,internal
+// @ID orgs.currency_settings.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.CurrencySettings true "Currency settings"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} map[string]any "data: organization.CurrencySettings"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/currency-settings [patch]
+// PatchCurrencySettings replaces the org's currency settings.
+func (h *Handler) PatchCurrencySettings(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.CurrencySettings
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := validateCurrencySettings(req); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			err.Error(), middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdateCurrencySettings(r.Context(), id, req); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update currency settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": req})
+}
@@ -0,0 +1,32 @@
+package orgs
+
+import (
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+func TestValidateCurrencySettings(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      organization.CurrencySettings
+		wantErr bool
+	}{
+		{"defaults ok", organization.CurrencySettings{Currency: "USD", Locale: "en-US"}, false},
+		{"euro in germany", organization.CurrencySettings{Currency: "EUR", Locale: "de-DE"}, false},
+		{"yen", organization.CurrencySettings{Currency: "JPY", Locale: "ja-JP"}, false},
+		{"missing currency", organization.CurrencySettings{Locale: "en-US"}, true},
+		{"lowercase currency", organization.CurrencySettings{Currency: "usd", Locale: "en-US"}, true},
+		{"unknown currency", organization.CurrencySettings{Currency: "XYZ", Locale: "en-US"}, true},
+		{"missing locale", organization.CurrencySettings{Currency: "USD"}, true},
+		{"unknown locale", organization.CurrencySettings{Currency: "USD", Locale: "tlh"}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateCurrencySettings(c.in)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got err=%v", c.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package orgs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// maxCustomDomains caps how many hostnames one org may claim.
+const maxCustomDomains = 10
+
+var hostnameLabelPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// normalizeCustomHostname validates raw as a public DNS hostname the org may
+// claim and returns it lowercase without a trailing dot. Hostnames under the
+// environment's own domain (app.trakrf.id, *.trakrf.id) are TrakRF's and are
+// refused.
+func normalizeCustomHostname(raw, baseURL string) (string, error) {
+	host := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(raw)), ".")
+	labels := strings.Split(host, ".")
+	if len(host) > 253 || len(labels) < 2 || !isAlphaLabel(labels[len(labels)-1]) {
+		return "", fmt.Errorf("hostname must be a domain name like track.acme.example")
+	}
+	for _, l := range labels {
+		if !hostnameLabelPattern.MatchString(l) {
+			return "", fmt.Errorf("hostname must be a domain name like track.acme.example")
+		}
+	}
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		own := strings.ToLower(u.Hostname())
+		if parts := strings.SplitN(own, ".", 2); len(parts) == 2 && strings.Contains(parts[1], ".") {
+			own = parts[1]
+		}
+		if host == own || strings.HasSuffix(host, "."+own) {
+			return "", fmt.Errorf("hostname must not be under %s", own)
+		}
+	}
+	return host, nil
+}
+
+func isAlphaLabel(l string) bool {
+	return l != "" && strings.Trim(l, "abcdefghijklmnopqrstuvwxyz") == ""
+}
+
+// newVerificationToken returns a random token for a custom domain's TXT
+// record.
+func newVerificationToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// @Summary List an organization's custom domains
+// @Description Internal-only. Lists the hostnames the org serves the app from, each with the DNS TXT record that verifies it. Only verified domains resolve to the org.
+// @Tags orgs,internal
+// @ID orgs.custom_domains.list
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: []organization.CustomDomain"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/custom-domains [get]
+// ListCustomDomains returns the org's custom domains.
+func (h *Handler) ListCustomDomains(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	domains, err := h.storage.ListCustomDomains(r.Context(), id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": domains})
+}
+
+// @Summary Add a custom domain
+// @Description Internal-only. Claims a hostname for the org, unverified. Publish verification_value in a TXT record named verification_record, point the hostname at TrakRF, then call verify. A hostname under TrakRF's own domain is refused.
+// @Tags orgs,internal
+// @ID orgs.custom_domains.create
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.CreateCustomDomainRequest true "Custom domain"
+// @Success 201 {object} map[string]any "data: organization.CustomDomain"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse "Hostname already in use"
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/custom-domains [post]
+// CreateCustomDomain claims a hostname for the org.
+func (h *Handler) CreateCustomDomain(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	var request organization.CreateCustomDomainRequest
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	hostname, err := normalizeCustomHostname(request.Hostname, applinks.FromEnv().BaseURL)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation, err.Error(), reqID)
+		return
+	}
+
+	existing, err := h.storage.ListCustomDomains(r.Context(), id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if len(existing) >= maxCustomDomains {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			fmt.Sprintf("an organization may have at most %d custom domains", maxCustomDomains), reqID)
+		return
+	}
+
+	token, err := newVerificationToken()
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to create custom domain", reqID)
+		return
+	}
+	d, err := h.storage.CreateCustomDomain(r.Context(), id, hostname, token)
+	if err != nil {
+		if errors.Is(err, storage.ErrCustomDomainTaken) {
+			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+			return
+		}
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": d})
+}
+
+// @Summary Verify a custom domain
+// @Description Internal-only. Looks up the domain's verification TXT record and, when it carries verification_value, marks the domain verified so it resolves to the org. Safe to repeat. 409 when another org verified the hostname first.
+// @Tags orgs,internal
+// @ID orgs.custom_domains.verify
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param domain_id path int true "Custom domain id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.CustomDomain"
+// @Failure 400 {object} modelerrors.ErrorResponse "TXT record not found"
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse "Hostname verified by another organization"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/custom-domains/{domain_id}/verify [post]
+// VerifyCustomDomain checks a custom domain's TXT record.
+func (h *Handler) VerifyCustomDomain(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	domainID, err := httputil.ParseSurrogateID("domain_id", chi.URLParam(r, "domain_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	d, err := h.storage.GetCustomDomain(r.Context(), id, domainID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "Custom domain not found", reqID)
+		return
+	}
+
+	records, err := h.lookupTXT(r.Context(), d.VerificationRecord)
+	var dnsErr *net.DNSError
+	if err != nil && !(errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			fmt.Sprintf("could not look up %s: %v", d.VerificationRecord, err), reqID)
+		return
+	}
+	if !slices.Contains(records, d.VerificationValue) {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			fmt.Sprintf("TXT record %s does not contain %s yet; DNS changes can take a while to appear",
+				d.VerificationRecord, d.VerificationValue), reqID)
+		return
+	}
+
+	d, err = h.storage.MarkCustomDomainVerified(r.Context(), id, domainID)
+	if err != nil {
+		if errors.Is(err, storage.ErrCustomDomainTaken) {
+			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict,
+				"hostname is already verified by another organization", reqID)
+			return
+		}
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if d == nil {
+		httputil.Respond404(w, r, "Custom domain not found", reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": d})
+}
+
+// @Summary Remove a custom domain
+// @Description Internal-only. The hostname stops resolving to the org within a minute.
+// @Tags orgs,internal
+// @ID orgs.custom_domains.delete
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param domain_id path int true "Custom domain id" minimum(1) format(int64)
+// @Success 204
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/custom-domains/{domain_id} [delete]
+// DeleteCustomDomain removes one of the org's custom domains.
+func (h *Handler) DeleteCustomDomain(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	domainID, err := httputil.ParseSurrogateID("domain_id", chi.URLParam(r, "domain_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	found, err := h.storage.DeleteCustomDomain(r.Context(), id, domainID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "Custom domain not found", reqID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Check whether a hostname may get a TLS certificate
+// @Description Public and unauthenticated. Answers 200 when domain is a verified custom domain of a live org and 404 otherwise. Meant as the "ask" hook of a TLS-terminating proxy that issues certificates on demand (Caddy on_demand_tls), so certificates are only requested for domains a customer has proven.
+// @Tags orgs,internal
+// @ID custom_domains.tls_check
+// @Param domain query string true "Hostname the proxy wants a certificate for"
+// @Success 200
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Router /api/v1/custom-domains/tls-check [get]
+// CheckCustomDomainTLS reports whether the proxy may obtain a certificate
+// for the queried hostname.
+func (h *Handler) CheckCustomDomainTLS(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	domain := strings.TrimSuffix(strings.ToLower(r.URL.Query().Get("domain")), ".")
+	if domain == "" {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			"domain is required", reqID)
+		return
+	}
+
+	org, err := h.storage.ResolveCustomDomain(r.Context(), domain)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if org == nil {
+		httputil.Respond404(w, r, "Not a verified custom domain", reqID)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,37 @@
+package orgs
+
+import "testing"
+
+func TestNormalizeCustomHostname(t *testing.T) {
+	const base = "https://app.trakrf.id"
+	cases := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"track.acme.example", "track.acme.example", false},
+		{" Track.Acme.Example. ", "track.acme.example", false},
+		{"acme.example", "acme.example", false},
+		{"xn--bcher-kva.example", "xn--bcher-kva.example", false},
+		{"localhost", "", true},
+		{"10.0.0.1", "", true},
+		{"https://track.acme.example", "", true},
+		{"track.acme.example:8443", "", true},
+		{"-track.acme.example", "", true},
+		{"*.acme.example", "", true},
+		{"app.trakrf.id", "", true},
+		{"acme.trakrf.id", "", true},
+		{"trakrf.id", "", true},
+		{"nottrakrf.id", "nottrakrf.id", false},
+	}
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got, err := normalizeCustomHostname(c.in, base)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got err=%v", c.wantErr, err)
+			}
+			if got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
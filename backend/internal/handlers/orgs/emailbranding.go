@@ -0,0 +1,202 @@
+package orgs
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/i18n"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/services/email"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// validateEmailBranding checks the provided (non-empty) branding fields.
+// Empty fields mean "use the TrakRF default" and are always allowed.
+func validateEmailBranding(eb organization.EmailBranding) error {
+	if eb.LogoURL != "" {
+		u, err := url.Parse(eb.LogoURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" || len(eb.LogoURL) > 2048 {
+			return fmt.Errorf("logo_url must be an https URL of at most 2048 characters")
+		}
+	}
+	if eb.PrimaryColor != "" && !hexColorPattern.MatchString(eb.PrimaryColor) {
+		return fmt.Errorf("primary_color must be a hex color like #1d4ed8")
+	}
+	if eb.ReplyTo != "" {
+		addr, err := mail.ParseAddress(eb.ReplyTo)
+		if err != nil || addr.Name != "" || addr.Address != strings.TrimSpace(eb.ReplyTo) {
+			return fmt.Errorf("reply_to must be a plain email address")
+		}
+	}
+	return nil
+}
+
+// @Summary Get an organization's email branding
+// @Description Internal-only. Returns the logo, accent color and reply-to address applied to the org's invitation emails. Empty fields use the TrakRF defaults.
+// @Tags orgs,internal
+// @ID orgs.email_branding.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.EmailBranding"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/email-branding [get]
+// GetEmailBranding returns the org's email branding.
+func (h *Handler) GetEmailBranding(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	eb, err := h.storage.GetEmailBranding(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get email branding", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if eb == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": eb})
+}
+
+// @Summary Replace an organization's email branding
+// @Description Internal-only. Full-replace: omitted fields fall back to the TrakRF defaults. logo_url must be https; primary_color is #rrggbb; reply_to is a plain email address that receives replies to invitations.
+// @Tags orgs,internal
+// @ID orgs.email_branding.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.EmailBranding true "Email branding"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} map[string]any "data: organization.EmailBranding"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/email-branding [patch]
+// PatchEmailBranding replaces the org's email branding.
+func (h *Handler) PatchEmailBranding(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.EmailBranding
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := validateEmailBranding(req); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			err.Error(), middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdateEmailBranding(r.Context(), id, req); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update email branding", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": req})
+}
+
+// @Summary Preview a transactional email
+// @Description Internal-only. Renders a templated email (password_reset, invitation or email_change) with the org's branding and placeholder data. locale defaults to the request's Accept-Language.
+// @Tags orgs,internal
+// @ID orgs.email_templates.preview
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param name path string true "Template name" Enums(password_reset, invitation, email_change)
+// @Param locale query string false "Locale" Enums(en, es, fr)
+// @Success 200 {object} map[string]any "data: email.Rendered"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/email-templates/{name}/preview [get]
+// PreviewEmailTemplate renders one templated email as the org's members
+// would receive it.
+func (h *Handler) PreviewEmailTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	name := chi.URLParam(r, "name")
+	if !slices.Contains(email.TemplateNames(), name) {
+		httputil.Respond404(w, r, "Email template not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	locale := i18n.FromContext(r.Context())
+	if q := r.URL.Query().Get("locale"); q != "" {
+		if !slices.Contains(i18n.Supported(), q) {
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+				fmt.Sprintf("locale must be one of: %s", strings.Join(i18n.Supported(), ", ")),
+				middleware.GetRequestID(r.Context()))
+			return
+		}
+		locale = q
+	}
+
+	org, err := h.storage.GetOrganizationByID(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get organization", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if org == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+	opts, err := h.service.EmailOptions(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get email branding", middleware.GetRequestID(r.Context()))
+		return
+	}
+	opts.Locale = locale
+
+	rendered, err := email.Preview(name, org.Name, opts)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to render email template", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": rendered})
+}
@@ -0,0 +1,34 @@
+package orgs
+
+import (
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+func TestValidateEmailBranding(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      organization.EmailBranding
+		wantErr bool
+	}{
+		{"all empty ok", organization.EmailBranding{}, false},
+		{"valid full", organization.EmailBranding{LogoURL: "https://acme.example/logo.png",
+			PrimaryColor: "#0F766e", ReplyTo: "ops@acme.example"}, false},
+		{"http logo", organization.EmailBranding{LogoURL: "http://acme.example/logo.png"}, true},
+		{"javascript logo", organization.EmailBranding{LogoURL: "javascript:alert(1)"}, true},
+		{"short color", organization.EmailBranding{PrimaryColor: "#fff"}, true},
+		{"named color", organization.EmailBranding{PrimaryColor: "red"}, true},
+		{"css injection", organization.EmailBranding{PrimaryColor: "#000000;display:none"}, true},
+		{"reply-to with name", organization.EmailBranding{ReplyTo: "Ops <ops@acme.example>"}, true},
+		{"reply-to not an address", organization.EmailBranding{ReplyTo: "ops"}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateEmailBranding(c.in)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got err=%v", c.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package orgs
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+const (
+	// exportDownloadPath is the public route a download URL points at.
+	exportDownloadPath = "/api/v1/org-exports/"
+	// exportDownloadTTL is how long a minted download URL works. Reading the
+	// export again mints a fresh one.
+	exportDownloadTTL = 15 * time.Minute
+)
+
+// withDownloadURL sets e's download URL when it is completed and unexpired.
+func withDownloadURL(r *http.Request, e *organization.Export) error {
+	if e.Status != organization.ExportStatusCompleted || e.ExpiresAt == nil || !e.ExpiresAt.After(time.Now()) {
+		return nil
+	}
+	exp := time.Now().Add(exportDownloadTTL)
+	if e.ExpiresAt.Before(exp) {
+		exp = *e.ExpiresAt
+	}
+	token, err := jwt.GenerateOrgExportToken(e.OrgID, e.ID, exp)
+	if err != nil {
+		return err
+	}
+	// The origin comes from the link policy, never the Host header.
+	url := applinks.FromEnv().Origin(r.Header.Get("Origin"), nil) + exportDownloadPath + token
+	e.DownloadURL = &url
+	return nil
+}
+
+// @Summary Export an organization's data
+// @Description Internal-only. Queues a full backup of the organization: locations, assets, tags, scan history, teams, kits, alarms, musters, sensors, members and service accounts. Credentials (password hashes, API keys, tokens, webhook and connector secrets) are not exported. format json (default) builds one gzipped JSON document keyed by table; sql builds a gzipped psql script that re-inserts the rows. Poll GET /api/v1/orgs/{id}/exports/{exportId} until status is completed, then fetch its download_url. Archives are kept for 7 days. Only one export per organization may be pending or running at a time.
+// @Tags orgs,internal
+// @ID orgs.exports.create
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.CreateExportRequest false "Archive format"
+// @Success 202 {object} map[string]any "data: organization.Export"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse "An export is already in progress"
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/export [post]
+// CreateExport handles POST /api/v1/orgs/{id}/export.
+func (h *Handler) CreateExport(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	var req organization.CreateExportRequest
+	if r.ContentLength != 0 {
+		if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+			httputil.RespondDecodeError(w, r, err, reqID)
+			return
+		}
+		if err := validate.Struct(req); err != nil {
+			httputil.RespondValidationError(w, r, err, reqID)
+			return
+		}
+	}
+	format := req.Format
+	if format == "" {
+		format = organization.ExportFormatJSON
+	}
+
+	var requestedBy *int
+	if claims := middleware.GetUserClaims(r); claims != nil {
+		requestedBy = &claims.UserID
+	}
+
+	export, err := h.storage.CreateOrgExport(r.Context(), orgID, requestedBy, format)
+	if stderrors.Is(err, storage.ErrOrgExportInProgress) {
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+		return
+	}
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to queue organization export", reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/orgs/"+strconv.Itoa(orgID)+"/exports/"+strconv.Itoa(export.ID))
+	httputil.WriteJSON(w, http.StatusAccepted, map[string]any{"data": export})
+}
+
+// @Summary List an organization's data exports
+// @Description Internal-only. Exports newest first, including failed ones until they expire. download_url is only set when reading a single export.
+// @Tags orgs,internal
+// @ID orgs.exports.list
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: []organization.Export"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/exports [get]
+// ListExports handles GET /api/v1/orgs/{id}/exports.
+func (h *Handler) ListExports(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	exports, err := h.storage.ListOrgExports(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to list organization exports", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": exports})
+}
+
+// @Summary Get an organization data export
+// @Description Internal-only. Once status is completed, download_url is a signed link to the gzipped archive that anyone holding it can fetch without signing in; it works for 15 minutes, and reading the export again mints a new one.
+// @Tags orgs,internal
+// @ID orgs.exports.get
+// @Produce json
+// @Param id       path int true "Organization id" minimum(1) format(int64)
+// @Param exportId path int true "Export id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.Export"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/exports/{exportId} [get]
+// GetExport handles GET /api/v1/orgs/{id}/exports/{exportId}.
+func (h *Handler) GetExport(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	exportID, err := httputil.ParseSurrogateID("exportId", chi.URLParam(r, "exportId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	export, err := h.storage.GetOrgExport(r.Context(), orgID, exportID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get organization export", reqID)
+		return
+	}
+	if export == nil {
+		httputil.Respond404(w, r, "Export not found", reqID)
+		return
+	}
+	if err := withDownloadURL(r, export); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to sign export download URL", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": export})
+}
+
+// @Summary Download an organization data export
+// @Description Public and unauthenticated: the token is the credential. Streams the gzipped archive. An expired, tampered or unknown token, or one whose export has expired, answers 404.
+// @Tags orgs,internal
+// @ID orgs.exports.download
+// @Produce application/gzip
+// @Param token path string true "Token from an export's download_url"
+// @Success 200 {file} binary
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Router /api/v1/org-exports/{token} [get]
+// DownloadExport handles GET /api/v1/org-exports/{token}.
+func (h *Handler) DownloadExport(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	claims, err := jwt.ValidateOrgExportToken(chi.URLParam(r, "token"))
+	if err != nil {
+		httputil.Respond404(w, r, "Export not found", reqID)
+		return
+	}
+
+	export, err := h.storage.GetOrgExport(r.Context(), claims.OrgID, claims.ExportID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get organization export", reqID)
+		return
+	}
+	var archive []byte
+	if export != nil {
+		archive, err = h.storage.GetOrgExportArchive(r.Context(), claims.OrgID, claims.ExportID)
+		if err != nil {
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+				"Failed to read organization export", reqID)
+			return
+		}
+	}
+	if archive == nil {
+		httputil.Respond404(w, r, "Export not found", reqID)
+		return
+	}
+
+	ext := "json"
+	if export.Format == organization.ExportFormatSQL {
+		ext = "sql"
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="trakrf-org-%d-export-%d.%s.gz"`,
+		export.OrgID, export.ID, ext))
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(archive)
+}
@@ -1,7 +1,6 @@
 package orgs
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -87,7 +86,9 @@ func (h *Handler) GetGeofenceDefaults(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Organization id" minimum(1) format(int64)
 // @Param request body organization.GeofenceDefaults true "Org geofence defaults"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
 // @Success 200 {object} map[string]any "data: GeofenceDefaultsView"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
 // @Failure 400 {object} modelerrors.ErrorResponse
 // @Failure 401 {object} modelerrors.ErrorResponse
 // @Failure 403 {object} modelerrors.ErrorResponse
@@ -105,9 +106,8 @@ func (h *Handler) PatchGeofenceDefaults(w http.ResponseWriter, r *http.Request)
 	}
 
 	var req organization.GeofenceDefaults
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
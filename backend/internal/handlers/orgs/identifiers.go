@@ -0,0 +1,218 @@
+package orgs
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// validateIdentifierTemplate checks that every identifier t formats is a
+// valid external_key.
+func validateIdentifierTemplate(t organization.IdentifierTemplate) error {
+	if len(t.Prefix) > 32 {
+		return fmt.Errorf("prefix must be at most 32 characters")
+	}
+	if t.Prefix != "" && !httputil.ExternalKeyPattern.MatchString(t.Prefix) {
+		return fmt.Errorf("prefix may contain only letters, digits and hyphens")
+	}
+	if t.Padding < 1 || t.Padding > 12 {
+		return fmt.Errorf("padding must be between 1 and 12")
+	}
+	if t.NextValue < 1 {
+		return fmt.Errorf("next_value must be >= 1")
+	}
+	return nil
+}
+
+// @Summary Get an organization's identifier template
+// @Description Internal-only. Returns the template POST /api/v1/assets?auto_identifier=true names new assets with: prefix, zero-padded width and the next sequence value. An org that never set one gets ASSET-NNNN continuing after its highest ASSET- key.
+// @Tags orgs,internal
+// @ID orgs.identifier_template.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.IdentifierTemplate"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/identifier-template [get]
+// GetIdentifierTemplate returns the org's identifier template.
+func (h *Handler) GetIdentifierTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	t, err := h.storage.GetIdentifierTemplate(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get identifier template", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if t == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": t})
+}
+
+// @Summary Replace an organization's identifier template
+// @Description Internal-only. Full-replace. Lowering next_value is allowed: identifiers already held by a live asset are skipped when drawn.
+// @Tags orgs,internal
+// @ID orgs.identifier_template.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.IdentifierTemplate true "Identifier template"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} map[string]any "data: organization.IdentifierTemplate"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/identifier-template [patch]
+// PatchIdentifierTemplate replaces the org's identifier template.
+func (h *Handler) PatchIdentifierTemplate(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.IdentifierTemplate
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := validateIdentifierTemplate(req); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			err.Error(), middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdateIdentifierTemplate(r.Context(), id, req); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update identifier template", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": req})
+}
+
+// validateIdentifierSettings checks the deleted-key policy is a known one.
+func validateIdentifierSettings(s organization.IdentifierSettings) error {
+	switch s.DeletedKeyPolicy {
+	case organization.DeletedKeyAllowReuse, organization.DeletedKeyBlock, organization.DeletedKeyAutoRestore:
+		return nil
+	}
+	return fmt.Errorf("deleted_key_policy must be one of allow_reuse, block, auto_restore")
+}
+
+// @Summary Get an organization's identifier settings
+// @Description Internal-only. Returns the deleted-key policy: what creating an asset or location does when the external_key sent belongs to a deleted one. allow_reuse (the default) creates a new record; block rejects the create with 409; auto_restore restores the most recently deleted record with that key, overwritten with the create's fields and tags. Minted keys are never matched against deleted records.
+// @Tags orgs,internal
+// @ID orgs.identifier_settings.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.IdentifierSettings"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/identifier-settings [get]
+// GetIdentifierSettings returns the org's identifier settings.
+func (h *Handler) GetIdentifierSettings(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	s, err := h.storage.GetIdentifierSettings(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get identifier settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if s == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+	s.DeletedKeyPolicy = s.Policy()
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": s})
+}
+
+// @Summary Replace an organization's identifier settings
+// @Description Internal-only. Full-replace. The policy applies to creates from then on, including bulk imports; deleted records are not touched until a create names their key.
+// @Tags orgs,internal
+// @ID orgs.identifier_settings.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.IdentifierSettings true "Identifier settings"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} map[string]any "data: organization.IdentifierSettings"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/identifier-settings [patch]
+// PatchIdentifierSettings replaces the org's identifier settings.
+func (h *Handler) PatchIdentifierSettings(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.IdentifierSettings
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := validateIdentifierSettings(req); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			err.Error(), middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdateIdentifierSettings(r.Context(), id, req); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update identifier settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": req})
+}
@@ -0,0 +1,46 @@
+package orgs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+func TestValidateIdentifierTemplate(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      organization.IdentifierTemplate
+		wantErr bool
+	}{
+		{"default ok", organization.IdentifierTemplate{Prefix: "ASSET-", Padding: 4, NextValue: 1}, false},
+		{"no prefix ok", organization.IdentifierTemplate{Padding: 6, NextValue: 100}, false},
+		{"long prefix", organization.IdentifierTemplate{Prefix: strings.Repeat("A", 33), Padding: 4, NextValue: 1}, true},
+		{"underscore prefix", organization.IdentifierTemplate{Prefix: "WH_", Padding: 4, NextValue: 1}, true},
+		{"space prefix", organization.IdentifierTemplate{Prefix: "WH ", Padding: 4, NextValue: 1}, true},
+		{"zero padding", organization.IdentifierTemplate{Prefix: "A-", Padding: 0, NextValue: 1}, true},
+		{"wide padding", organization.IdentifierTemplate{Prefix: "A-", Padding: 13, NextValue: 1}, true},
+		{"zero next", organization.IdentifierTemplate{Prefix: "A-", Padding: 4, NextValue: 0}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateIdentifierTemplate(c.in)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got err=%v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestValidateIdentifierSettings(t *testing.T) {
+	for _, p := range []string{organization.DeletedKeyAllowReuse, organization.DeletedKeyBlock, organization.DeletedKeyAutoRestore} {
+		if err := validateIdentifierSettings(organization.IdentifierSettings{DeletedKeyPolicy: p}); err != nil {
+			t.Fatalf("%s: unexpected error %v", p, err)
+		}
+	}
+	for _, p := range []string{"", "restore", "BLOCK"} {
+		if err := validateIdentifierSettings(organization.IdentifierSettings{DeletedKeyPolicy: p}); err == nil {
+			t.Fatalf("%q: expected an error", p)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package orgs
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/orgexport"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// @Summary Import an organization data export
+// @Description Internal-only. Recreates the locations (with their hierarchy), assets and tags of a JSON export archive in this organization, e.g. to promote a staging org's setup to production. The body is the archive as downloaded — gzipped or not — sent as application/json. Rows are matched by external_key (locations, assets) and by tag type and value, so re-importing an archive updates what the last import created instead of duplicating it; ids are remapped onto this organization's. Deleted rows are skipped, as are asset owners and teams, which do not carry across organizations. Rows the archive does not mention are left alone. Everything is applied in one transaction: any conflict (e.g. a tag value already used under another type here) rolls back the whole import. dry_run=true reports the counts without writing anything. SQL archives are not accepted; restore those with psql.
+// @Tags orgs,internal
+// @ID orgs.import
+// @Accept json
+// @Produce json
+// @Param id      path  int  true  "Organization id" minimum(1) format(int64)
+// @Param dry_run query bool false "Report counts without writing"
+// @Success 200 {object} map[string]any "data: organization.ImportResult"
+// @Failure 400 {object} modelerrors.ErrorResponse "Not a supported export archive"
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse
+// @Failure 413 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/import [post]
+// Import handles POST /api/v1/orgs/{id}/import.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+
+	data, err := orgexport.ReadArchive(r.Body)
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if stderrors.As(err, &mbe) {
+			httputil.Respond413(w, r, mbe.Limit, reqID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest, err.Error(), reqID)
+		return
+	}
+
+	result, err := h.storage.ImportOrgData(r.Context(), orgID, *data, dryRun)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": result})
+}
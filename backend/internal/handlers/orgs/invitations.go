@@ -1,7 +1,6 @@
 package orgs
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -76,10 +75,8 @@ func (h *Handler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req organization.CreateInvitationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -90,14 +87,9 @@ func (h *Handler) CreateInvitation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get frontend origin for building invite link
-	// Falls back to production URL if Origin header is missing
-	baseURL := r.Header.Get("Origin")
-	if baseURL == "" {
-		baseURL = "https://app.trakrf.id"
-	}
-
-	resp, err := h.service.CreateInvitation(r.Context(), orgID, req, claims.UserID, baseURL)
+	// The Origin header only suggests where the invite link points; the
+	// service checks it against the allowed link domains.
+	resp, err := h.service.CreateInvitation(r.Context(), orgID, req, claims.UserID, r.Header.Get("Origin"))
 	if err != nil {
 		switch err.Error() {
 		case "already_member":
@@ -186,14 +178,7 @@ func (h *Handler) ResendInvitation(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get frontend origin for building invite link
-	// Falls back to production URL if Origin header is missing
-	baseURL := r.Header.Get("Origin")
-	if baseURL == "" {
-		baseURL = "https://app.trakrf.id"
-	}
-
-	newExpiry, err := h.service.ResendInvitation(r.Context(), inviteID, orgID, baseURL)
+	newExpiry, err := h.service.ResendInvitation(r.Context(), inviteID, orgID, r.Header.Get("Origin"))
 	if err != nil {
 		if err.Error() == "invitation not found" {
 			httputil.Respond404(w, r, apierrors.InvitationNotFound, middleware.GetRequestID(r.Context()))
@@ -0,0 +1,121 @@
+package orgs
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// normalizeLinkDomains validates ld and returns it with every origin in
+// canonical form and duplicates dropped.
+func normalizeLinkDomains(ld organization.LinkDomains) (organization.LinkDomains, error) {
+	if len(ld.Origins) > 10 {
+		return ld, fmt.Errorf("origins may list at most 10 origins")
+	}
+	out := organization.LinkDomains{Origins: []string{}}
+	for _, raw := range ld.Origins {
+		o, err := applinks.NormalizeOrigin(raw)
+		if err != nil {
+			return ld, fmt.Errorf("origins: %w", err)
+		}
+		if !slices.Contains(out.Origins, o) {
+			out.Origins = append(out.Origins, o)
+		}
+	}
+	return out, nil
+}
+
+// @Summary Get an organization's link domains
+// @Description Internal-only. Lists the extra frontend origins the org's emailed links (invitations, password resets, email changes) may point at. The environment's own origins are always allowed.
+// @Tags orgs,internal
+// @ID orgs.link_domains.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.LinkDomains"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/link-domains [get]
+// GetLinkDomains returns the org's link domains.
+func (h *Handler) GetLinkDomains(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	ld, err := h.storage.GetLinkDomains(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get link domains", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if ld == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": ld})
+}
+
+// @Summary Replace an organization's link domains (superadmin)
+// @Description Superadmin-only. Full-replace of the origins (https, at most 10) the org's emailed links may point at. Links go out from TrakRF's sender, so a domain is only added once TrakRF has confirmed the org controls it.
+// @Tags orgs,internal
+// @ID orgs.link_domains.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.LinkDomains true "Link domains"
+// @Success 200 {object} map[string]any "data: organization.LinkDomains"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/link-domains [patch]
+// PatchLinkDomains replaces the org's link domains.
+func (h *Handler) PatchLinkDomains(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.LinkDomains
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	ld, err := normalizeLinkDomains(req)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			err.Error(), middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdateLinkDomains(r.Context(), id, ld); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update link domains", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": ld})
+}
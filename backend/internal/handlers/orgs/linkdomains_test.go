@@ -0,0 +1,44 @@
+package orgs
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+func TestNormalizeLinkDomains(t *testing.T) {
+	got, err := normalizeLinkDomains(organization.LinkDomains{
+		Origins: []string{"https://Portal.Acme.example/", "https://portal.acme.example", "http://localhost:5173"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"https://portal.acme.example", "http://localhost:5173"}
+	if !reflect.DeepEqual(got.Origins, want) {
+		t.Fatalf("got %v, want %v", got.Origins, want)
+	}
+
+	got, err = normalizeLinkDomains(organization.LinkDomains{})
+	if err != nil || got.Origins == nil || len(got.Origins) != 0 {
+		t.Fatalf("empty list: got %v, %v", got.Origins, err)
+	}
+
+	tooMany := make([]string, 11)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("https://p%d.acme.example", i)
+	}
+	for name, origins := range map[string][]string{
+		"http":      {"http://acme.example"},
+		"path":      {"https://acme.example/reset"},
+		"query":     {"https://acme.example?next=x"},
+		"userinfo":  {"https://user@acme.example"},
+		"no scheme": {"acme.example"},
+		"too many":  tooMany,
+	} {
+		if _, err := normalizeLinkDomains(organization.LinkDomains{Origins: origins}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
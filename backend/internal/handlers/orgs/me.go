@@ -1,7 +1,6 @@
 package orgs
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 
@@ -84,10 +83,8 @@ func (h *Handler) SetCurrentOrg(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request organization.SetCurrentOrgRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
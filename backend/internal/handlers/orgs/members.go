@@ -1,7 +1,6 @@
 package orgs
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -53,7 +52,9 @@ func (h *Handler) ListMembers(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Organization id" minimum(1) format(int64)
 // @Param userId path int true "User id" minimum(1) format(int64)
 // @Param request body organization.UpdateMemberRoleRequest true "New role"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
 // @Success 200 {object} map[string]any "message: Role updated"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
 // @Failure 400 {object} modelerrors.ErrorResponse
 // @Failure 401 {object} modelerrors.ErrorResponse
 // @Failure 403 {object} modelerrors.ErrorResponse
@@ -77,10 +78,8 @@ func (h *Handler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request organization.UpdateMemberRoleRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -111,6 +110,12 @@ func (h *Handler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
 
 			return
 		}
+		if err.Error() == "cannot demote the owner" {
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
+				apierrors.MemberIsOwner, middleware.GetRequestID(r.Context()))
+
+			return
+		}
 		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
 			apierrors.MemberUpdateFailed, middleware.GetRequestID(r.Context()))
 
@@ -128,7 +133,7 @@ func (h *Handler) UpdateMemberRole(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Organization id" minimum(1) format(int64)
 // @Param userId path int true "User id" minimum(1) format(int64)
 // @Success 200 {object} map[string]any "message: Member removed"
-// @Failure 400 {object} modelerrors.ErrorResponse "Self-removal or last-admin"
+// @Failure 400 {object} modelerrors.ErrorResponse "Self-removal, last-admin or owner"
 // @Failure 401 {object} modelerrors.ErrorResponse
 // @Failure 403 {object} modelerrors.ErrorResponse
 // @Failure 404 {object} modelerrors.ErrorResponse
@@ -173,6 +178,12 @@ func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
 
 			return
 		}
+		if err.Error() == "cannot remove the owner" {
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
+				apierrors.MemberIsOwner, middleware.GetRequestID(r.Context()))
+
+			return
+		}
 		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
 			apierrors.MemberRemoveFailed, middleware.GetRequestID(r.Context()))
 
@@ -2,7 +2,7 @@ package orgs
 
 import (
 	"context"
-	"encoding/json"
+	"net"
 	"net/http"
 	"strconv"
 
@@ -10,6 +10,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/trakrf/platform/backend/internal/apierrors"
 	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/approval"
 	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/models/organization"
 	orgsservice "github.com/trakrf/platform/backend/internal/services/orgs"
@@ -34,13 +35,16 @@ type Handler struct {
 	storage *storage.Storage
 	service *orgsservice.Service
 	minter  tokenMinter
+	// lookupTXT resolves custom domain verification records; tests swap it
+	// out for a fake.
+	lookupTXT func(ctx context.Context, name string) ([]string, error)
 }
 
 // NewHandler constructs an orgs HTTP handler. minter is used by SetCurrentOrg
 // to issue a fresh access+refresh pair scoped to the newly-selected org. It
 // may be nil for test fixtures that do not exercise SetCurrentOrg.
 func NewHandler(storage *storage.Storage, service *orgsservice.Service, minter tokenMinter) *Handler {
-	return &Handler{storage: storage, service: service, minter: minter}
+	return &Handler{storage: storage, service: service, minter: minter, lookupTXT: net.DefaultResolver.LookupTXT}
 }
 
 // @Summary List organizations the authenticated user belongs to
@@ -96,10 +100,8 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request organization.CreateOrganizationRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -173,7 +175,9 @@ func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Organization id" minimum(1) format(int64)
 // @Param request body organization.UpdateOrganizationRequest true "Update payload"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
 // @Success 200 {object} map[string]any "data: organization.Organization"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
 // @Failure 400 {object} modelerrors.ErrorResponse
 // @Failure 401 {object} modelerrors.ErrorResponse
 // @Failure 403 {object} modelerrors.ErrorResponse
@@ -191,10 +195,8 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request organization.UpdateOrganizationRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -252,10 +254,8 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request organization.DeleteOrganizationRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -291,10 +291,15 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 // chi's MethodNotAllowed determination runs. Flat registration keeps each
 // method registered at the parent mux level so wrong methods short-circuit
 // to the root MethodNotAllowed handler without auth running.
-func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore, approvals middleware.ApprovalStore, partners middleware.PartnerAdminStore) {
 	member := middleware.RequireOrgMember(store)
 	admin := middleware.RequireOrgAdmin(store)
 	superadmin := middleware.RequireSuperadmin(store)
+	// Settings writes, admin grants and ownership transfers can be made to
+	// need a second admin's approval by the org's approval rules.
+	settings := middleware.RequireOrgApproval(approvals, approval.OpOrgSettings)
+	adminGrant := middleware.RequireOrgApproval(approvals, approval.OpOrgAdminGrant)
+	ownershipTransfer := middleware.RequireOrgApproval(approvals, approval.OpOrgOwnershipTransfer)
 
 	// Public routes (any authenticated user)
 	r.Get("/api/v1/orgs", h.List)
@@ -308,26 +313,147 @@ func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
 	r.With(superadmin).Get("/api/v1/admin/orgs", h.ListAllOrgs)
 	r.With(superadmin).Patch("/api/v1/orgs/{id}/entitlement", h.UpdateEntitlement)
 
+	// Partners: the billing entities that own orgs. Superadmin-only, since
+	// moving an org changes who is billed for it.
+	r.With(superadmin).Get("/api/v1/admin/partners", h.ListPartners)
+	r.With(superadmin).Post("/api/v1/admin/partners", h.CreatePartner)
+	r.With(superadmin).Get("/api/v1/admin/partners/{partnerId}", h.GetPartner)
+	r.With(superadmin).Get("/api/v1/admin/partners/{partnerId}/orgs", h.ListPartnerOrgs)
+	r.With(superadmin).Put("/api/v1/orgs/{id}/partner", h.SetOrgPartner)
+	r.With(superadmin).Get("/api/v1/admin/partners/{partnerId}/admins", h.ListPartnerAdmins)
+	r.With(superadmin).Post("/api/v1/admin/partners/{partnerId}/admins", h.AddPartnerAdmin)
+	r.With(superadmin).Delete("/api/v1/admin/partners/{partnerId}/admins/{userId}", h.RemovePartnerAdmin)
+
+	// Partner roll-up reporting: partner admins read aggregates across every
+	// org the partner owns without being members of those orgs.
+	r.Get("/api/v1/partners", h.ListMyPartners)
+	r.With(middleware.RequirePartnerAdmin(partners)).Get("/api/v1/partners/{partnerId}/rollup", h.GetPartnerRollup)
+
 	// Protected routes (require org membership/admin)
 	r.With(member).Get("/api/v1/orgs/{id}", h.Get)
-	r.With(admin).Put("/api/v1/orgs/{id}", h.Update)
+	r.With(admin, settings).Put("/api/v1/orgs/{id}", h.Update)
 	r.With(admin).Delete("/api/v1/orgs/{id}", h.Delete)
 
 	// Geofence tuning defaults (TRA-955), internal-only. Read by any member;
 	// write is admin-only (org-wide blast radius, same tier as PUT /orgs/{id}).
 	r.With(member).Get("/api/v1/orgs/{id}/geofence-defaults", h.GetGeofenceDefaults)
-	r.With(admin).Patch("/api/v1/orgs/{id}/geofence-defaults", h.PatchGeofenceDefaults)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/geofence-defaults", h.PatchGeofenceDefaults)
+
+	// Data retention windows. Read by any member; write is admin-only since
+	// shortening a window permanently deletes history on the janitor's next run.
+	r.With(member).Get("/api/v1/orgs/{id}/retention", h.GetRetention)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/retention", h.PatchRetention)
+
+	// Strict team mode. Read by any member; write is admin-only since turning
+	// it on narrows what every non-admin member can see.
+	r.With(member).Get("/api/v1/orgs/{id}/team-settings", h.GetTeamSettings)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/team-settings", h.PatchTeamSettings)
+
+	// Tag settings. Read by any member; write is admin-only since enabling
+	// cross-type uniqueness rejects tag writes across the whole org.
+	r.With(member).Get("/api/v1/orgs/{id}/tag-settings", h.GetTagSettings)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/tag-settings", h.PatchTagSettings)
+
+	// Session scans without a reader credential. Read by any member; write
+	// is admin-only since turning it on lets every member submit tag values.
+	r.With(member).Get("/api/v1/orgs/{id}/scan-settings", h.GetScanSettings)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/scan-settings", h.PatchScanSettings)
+
+	// Identifier template for auto-assigned asset keys. Read by any member;
+	// write is admin-only since it names every auto-identified asset.
+	r.With(member).Get("/api/v1/orgs/{id}/identifier-template", h.GetIdentifierTemplate)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/identifier-template", h.PatchIdentifierTemplate)
+
+	// Deleted-key policy for asset and location creates. Read by any member;
+	// write is admin-only since it decides whether deleted records come back.
+	r.With(member).Get("/api/v1/orgs/{id}/identifier-settings", h.GetIdentifierSettings)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/identifier-settings", h.PatchIdentifierSettings)
+
+	// Currency and report locale for financial fields. Read by any member;
+	// write is admin-only since it sets the default for every money write.
+	r.With(member).Get("/api/v1/orgs/{id}/currency-settings", h.GetCurrencySettings)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/currency-settings", h.PatchCurrencySettings)
+
+	// Privacy of person-assets in reports. Read by any member; write is
+	// admin-only since it decides whether reports name people.
+	r.With(member).Get("/api/v1/orgs/{id}/personnel-settings", h.GetPersonnelSettings)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/personnel-settings", h.PatchPersonnelSettings)
+
+	// Email branding for invitations. Read by any member; write and the
+	// template preview are admin-only, like the other org-wide settings.
+	r.With(member).Get("/api/v1/orgs/{id}/email-branding", h.GetEmailBranding)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/email-branding", h.PatchEmailBranding)
+	r.With(admin).Get("/api/v1/orgs/{id}/email-templates/{name}/preview", h.PreviewEmailTemplate)
+
+	// White-label theme for the web app. Read by any member; write is
+	// admin-only, like email branding.
+	r.With(member).Get("/api/v1/orgs/{id}/app-theme", h.GetAppTheme)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/app-theme", h.PatchAppTheme)
+
+	// Link domains for emailed links. Readable by admins; only a superadmin
+	// may add one, since the links go out under TrakRF's sender.
+	r.With(admin).Get("/api/v1/orgs/{id}/link-domains", h.GetLinkDomains)
+	r.With(superadmin).Patch("/api/v1/orgs/{id}/link-domains", h.PatchLinkDomains)
+
+	// Custom domains the app is served from. Admin-only: a domain only
+	// resolves once its DNS proves the org controls it.
+	r.With(admin).Get("/api/v1/orgs/{id}/custom-domains", h.ListCustomDomains)
+	r.With(admin, settings).Post("/api/v1/orgs/{id}/custom-domains", h.CreateCustomDomain)
+	r.With(admin).Post("/api/v1/orgs/{id}/custom-domains/{domain_id}/verify", h.VerifyCustomDomain)
+	r.With(admin, settings).Delete("/api/v1/orgs/{id}/custom-domains/{domain_id}", h.DeleteCustomDomain)
+
+	// BLE zone estimation. Read by any member; write is admin-only since
+	// enabling it changes how every BLE gateway read is recorded.
+	r.With(member).Get("/api/v1/orgs/{id}/positioning", h.GetPositioning)
+	r.With(admin, settings).Patch("/api/v1/orgs/{id}/positioning", h.PatchPositioning)
 
 	// Member management routes
 	r.With(member).Get("/api/v1/orgs/{id}/members", h.ListMembers)
-	r.With(admin).Put("/api/v1/orgs/{id}/members/{userId}", h.UpdateMemberRole)
+	r.With(admin, adminGrant).Put("/api/v1/orgs/{id}/members/{userId}", h.UpdateMemberRole)
 	r.With(admin).Delete("/api/v1/orgs/{id}/members/{userId}", h.RemoveMember)
 
+	// Ownership. Proposing a transfer is admin-only (the storage layer further
+	// restricts it to the owner); the new owner, who may not be an admin yet,
+	// accepts or declines as a member.
+	r.With(member).Get("/api/v1/orgs/{id}/ownership", h.GetOwnership)
+	r.With(admin, ownershipTransfer).Post("/api/v1/orgs/{id}/transfer-ownership", h.TransferOwnership)
+	r.With(member).Post("/api/v1/orgs/{id}/transfer-ownership/accept", h.decideOwnershipTransfer(organization.TransferActionAccept))
+	r.With(member).Post("/api/v1/orgs/{id}/transfer-ownership/decline", h.decideOwnershipTransfer(organization.TransferActionDecline))
+	r.With(member).Post("/api/v1/orgs/{id}/transfer-ownership/cancel", h.decideOwnershipTransfer(organization.TransferActionCancel))
+
 	// Invitation routes (admin only)
 	r.With(admin).Get("/api/v1/orgs/{id}/invitations", h.ListInvitations)
 	r.With(admin).Post("/api/v1/orgs/{id}/invitations", h.CreateInvitation)
 	r.With(admin).Delete("/api/v1/orgs/{id}/invitations/{inviteId}", h.CancelInvitation)
 	r.With(admin).Post("/api/v1/orgs/{id}/invitations/{inviteId}/resend", h.ResendInvitation)
+
+	// Webhook endpoints (admin only): receivers hold a signing secret and see
+	// every subscribed change in the org.
+	r.With(admin).Get("/api/v1/orgs/{id}/webhooks", h.ListWebhooks)
+	r.With(admin).Post("/api/v1/orgs/{id}/webhooks", h.CreateWebhook)
+	r.With(admin).Delete("/api/v1/orgs/{id}/webhooks/{webhookId}", h.DeleteWebhook)
+
+	// Webhook delivery log and replay (admin only): payloads carry the same
+	// org-wide data the endpoints receive.
+	r.With(admin).Get("/api/v1/orgs/{id}/webhook-deliveries", h.ListWebhookDeliveries)
+	r.With(admin).Get("/api/v1/orgs/{id}/webhook-deliveries/{deliveryId}", h.GetWebhookDelivery)
+	r.With(admin).Post("/api/v1/orgs/{id}/webhook-deliveries/{deliveryId}/redeliver", h.RedeliverWebhook)
+
+	// Data export and import (admin only): an archive holds the whole org,
+	// and an import rewrites its locations, assets and tags.
+	r.With(admin).Post("/api/v1/orgs/{id}/export", h.CreateExport)
+	r.With(admin).Get("/api/v1/orgs/{id}/exports", h.ListExports)
+	r.With(admin).Get("/api/v1/orgs/{id}/exports/{exportId}", h.GetExport)
+	r.With(admin).Post("/api/v1/orgs/{id}/import", h.Import)
+
+	// Sandbox clone (admin only): the copy holds the org's whole structure.
+	r.With(admin).Post("/api/v1/orgs/{id}/sandbox", h.CreateSandbox)
+
+	// Service accounts (admin only): they own API keys, so creating or
+	// deleting one is on the same tier as key management.
+	r.With(admin).Get("/api/v1/orgs/{id}/service-accounts", h.ListServiceAccounts)
+	r.With(admin).Post("/api/v1/orgs/{id}/service-accounts", h.CreateServiceAccount)
+	r.With(admin).Delete("/api/v1/orgs/{id}/service-accounts/{serviceAccountId}", h.DeleteServiceAccount)
 }
 
 // RegisterAPIKeyRoutes registers the /api/v1/orgs/{id}/api-keys endpoints.
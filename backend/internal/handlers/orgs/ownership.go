@@ -0,0 +1,210 @@
+package orgs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// auditOwnershipTransfer writes the audit log line for a transfer event. The
+// transfer row is the durable record; the log line ties it to the request.
+func auditOwnershipTransfer(r *http.Request, action string, actorID int, t *organization.OwnershipTransfer) {
+	ev := logger.Get().Info().
+		Str("event", "org.ownership_transfer."+action).
+		Int("org_id", t.OrgID).
+		Int("transfer_id", t.ID).
+		Int("actor_user_id", actorID).
+		Str("previous_owner_role", t.PreviousOwnerRole).
+		Str("request_id", middleware.GetRequestID(r.Context()))
+	if t.FromUserID != nil {
+		ev = ev.Int("from_user_id", *t.FromUserID)
+	}
+	if t.ToUserID != nil {
+		ev = ev.Int("to_user_id", *t.ToUserID)
+	}
+	ev.Msg("organization ownership transfer " + action)
+}
+
+// @Summary Get an organization's owner
+// @Description Returns the org's owner and the pending ownership transfer, if any, so the new owner can see a transfer waiting for them.
+// @Tags orgs,internal
+// @ID orgs.ownership.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.Ownership"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/ownership [get]
+// GetOwnership returns the org's owner and pending transfer.
+func (h *Handler) GetOwnership(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	o, err := h.storage.GetOrgOwnership(r.Context(), orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if o == nil {
+		httputil.Respond404(w, r, "Organization not found", reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": o})
+}
+
+// @Summary Transfer ownership of an organization
+// @Description Proposes handing the org to another member. Only the owner can propose, or any admin of an org with no recorded owner. Nothing changes until the new owner accepts with POST /api/v1/orgs/{id}/transfer-ownership/accept within 7 days; then they become the owner and an admin, and the previous owner is demoted to `previous_owner_role` (default `manager`). An org has at most one pending transfer. When the org has an `org.ownership_transfer` approval rule, the proposal is parked for a second admin's approval and returned with 202.
+// @Tags orgs,internal
+// @ID orgs.ownership.transfer
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.TransferOwnershipRequest true "New owner"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 201 {object} map[string]any "data: organization.OwnershipTransfer"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the transfer"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse "Caller is not the owner"
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse "A transfer is already pending"
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/transfer-ownership [post]
+// TransferOwnership proposes a new owner for the org.
+func (h *Handler) TransferOwnership(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return
+	}
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	var request organization.TransferOwnershipRequest
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	t, err := h.storage.CreateOwnershipTransfer(r.Context(), orgID, claims.UserID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrOwnershipNotOwner):
+			httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden, err.Error(), reqID)
+		case errors.Is(err, storage.ErrOwnershipTargetNotMember), errors.Is(err, storage.ErrOwnershipTargetIsOwner):
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "user_id",
+				Code:    "invalid_value",
+				Message: err.Error(),
+			}})
+		case errors.Is(err, storage.ErrOwnershipTransferPending):
+			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+		default:
+			httputil.RespondStorageError(w, r, err, reqID)
+		}
+		return
+	}
+	if t == nil {
+		httputil.Respond404(w, r, "Organization not found", reqID)
+		return
+	}
+
+	auditOwnershipTransfer(r, "requested", claims.UserID, t)
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": t})
+}
+
+// @Summary Accept, decline or cancel an ownership transfer
+// @Description Acts on the org's pending ownership transfer. The new owner accepts or declines it; the current owner cancels it. Accepting makes the caller the owner and an admin and demotes the previous owner, in one step.
+// @Tags orgs,internal
+// @ID orgs.ownership.decide
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param action path string true "accept, decline or cancel" Enums(accept, decline, cancel)
+// @Success 200 {object} map[string]any "data: organization.OwnershipTransfer"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse "Caller may not take this action"
+// @Failure 404 {object} modelerrors.ErrorResponse "No pending transfer"
+// @Failure 409 {object} modelerrors.ErrorResponse "The transfer is no longer pending, or the new owner left the org"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/transfer-ownership/{action} [post]
+// decideOwnershipTransfer returns the handler for one transfer action.
+func (h *Handler) decideOwnershipTransfer(action string) http.HandlerFunc {
+	audit := map[string]string{
+		organization.TransferActionAccept:  "accepted",
+		organization.TransferActionDecline: "declined",
+		organization.TransferActionCancel:  "cancelled",
+	}[action]
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+		claims := middleware.GetUserClaims(r)
+		if claims == nil {
+			httputil.Respond401(w, r, "Session authentication required", reqID)
+			return
+		}
+		orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+
+		o, err := h.storage.GetOrgOwnership(r.Context(), orgID)
+		if err != nil {
+			httputil.RespondStorageError(w, r, err, reqID)
+			return
+		}
+		if o == nil || o.PendingTransfer == nil {
+			httputil.Respond404(w, r, "No pending ownership transfer", reqID)
+			return
+		}
+
+		t, err := h.storage.DecideOwnershipTransfer(r.Context(), orgID, o.PendingTransfer.ID, claims.UserID, action)
+		if err != nil {
+			switch {
+			case errors.Is(err, storage.ErrOwnershipNotOwner), errors.Is(err, storage.ErrOwnershipNotRecipient):
+				httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden, err.Error(), reqID)
+			case errors.Is(err, storage.ErrOwnershipTransferState), errors.Is(err, storage.ErrOwnershipTargetNotMember):
+				httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+			default:
+				httputil.RespondStorageError(w, r, err, reqID)
+			}
+			return
+		}
+		if t == nil {
+			httputil.Respond404(w, r, "No pending ownership transfer", reqID)
+			return
+		}
+
+		auditOwnershipTransfer(r, audit, claims.UserID, t)
+		httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": t})
+	}
+}
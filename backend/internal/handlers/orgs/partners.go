@@ -0,0 +1,431 @@
+package orgs
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// @Summary List partners (superadmin)
+// @Description Superadmin-only. Partners are the billing entities that own orgs: a reseller, or a customer billed once for several orgs. Each carries the number of live orgs it owns.
+// @Tags orgs,internal
+// @ID partners.list
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]any "data: []organization.Partner"
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/partners [get]
+// ListPartners returns every partner. Authorization is enforced upstream by
+// RequireSuperadmin.
+func (h *Handler) ListPartners(w http.ResponseWriter, r *http.Request) {
+	partners, err := h.storage.ListPartners(r.Context())
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": partners})
+}
+
+// @Summary Create a partner (superadmin)
+// @Tags orgs,internal
+// @ID partners.create
+// @Accept json
+// @Produce json
+// @Param request body organization.CreatePartnerRequest true "Partner"
+// @Success 201 {object} map[string]any "data: organization.Partner"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse "Identifier already taken"
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/partners [post]
+// CreatePartner creates a partner. Authorization is enforced upstream by
+// RequireSuperadmin.
+func (h *Handler) CreatePartner(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	var request organization.CreatePartnerRequest
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	p, err := h.storage.CreatePartner(r.Context(), request)
+	if err != nil {
+		if errors.Is(err, storage.ErrPartnerIdentifierTaken) {
+			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+			return
+		}
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": p})
+}
+
+// @Summary Get a partner (superadmin)
+// @Tags orgs,internal
+// @ID partners.get
+// @Accept json
+// @Produce json
+// @Param partnerId path int true "Partner id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.Partner"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/partners/{partnerId} [get]
+// GetPartner returns one partner. Authorization is enforced upstream by
+// RequireSuperadmin.
+func (h *Handler) GetPartner(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("partnerId", chi.URLParam(r, "partnerId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	p, err := h.storage.GetPartner(r.Context(), id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if p == nil {
+		httputil.Respond404(w, r, "Partner not found", reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": p})
+}
+
+// @Summary List a partner's organizations (superadmin)
+// @Tags orgs,internal
+// @ID partners.orgs
+// @Accept json
+// @Produce json
+// @Param partnerId path int true "Partner id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: []organization.AdminOrgListItem"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/partners/{partnerId}/orgs [get]
+// ListPartnerOrgs returns the orgs a partner owns. Authorization is enforced
+// upstream by RequireSuperadmin.
+func (h *Handler) ListPartnerOrgs(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("partnerId", chi.URLParam(r, "partnerId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	orgs, found, err := h.storage.ListPartnerOrgs(r.Context(), id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "Partner not found", reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": orgs})
+}
+
+// @Summary Move an organization to another partner (superadmin)
+// @Description Superadmin-only. Sets the partner that owns and is billed for the org; a null `partner_id` detaches it so it is billed on its own. The target partner must be active.
+// @Tags orgs,internal
+// @ID orgs.admin.partner
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.SetOrgPartnerRequest true "Partner"
+// @Success 200 {object} map[string]any "data: organization.AdminOrgListItem"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/partner [put]
+// SetOrgPartner moves an org between partners. Authorization is enforced
+// upstream by RequireSuperadmin.
+func (h *Handler) SetOrgPartner(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	var request organization.SetOrgPartnerRequest
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	org, err := h.storage.SetOrgPartner(r.Context(), orgID, request.PartnerID)
+	if err != nil {
+		if errors.Is(err, storage.ErrPartnerNotFound) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "partner_id",
+				Code:    "invalid_value",
+				Message: "partner_id must name an active partner",
+			}})
+			return
+		}
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if org == nil {
+		httputil.Respond404(w, r, "Organization not found", reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": org})
+}
+
+// @Summary List a partner's admins (superadmin)
+// @Tags orgs,internal
+// @ID partners.admins.list
+// @Accept json
+// @Produce json
+// @Param partnerId path int true "Partner id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: []organization.PartnerAdmin"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/partners/{partnerId}/admins [get]
+// ListPartnerAdmins returns a partner's admins. Authorization is enforced
+// upstream by RequireSuperadmin.
+func (h *Handler) ListPartnerAdmins(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("partnerId", chi.URLParam(r, "partnerId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	admins, err := h.storage.ListPartnerAdmins(r.Context(), id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": admins})
+}
+
+// @Summary Make a user a partner admin (superadmin)
+// @Description Superadmin-only. A partner admin can read roll-up reports across every org the partner owns, without being a member of those orgs. Granting an existing admin again is a no-op.
+// @Tags orgs,internal
+// @ID partners.admins.add
+// @Accept json
+// @Produce json
+// @Param partnerId path int true "Partner id" minimum(1) format(int64)
+// @Param request body organization.AddPartnerAdminRequest true "User"
+// @Success 201 {object} map[string]any "data: organization.PartnerAdmin"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/partners/{partnerId}/admins [post]
+// AddPartnerAdmin grants a user admin rights on a partner. Authorization is
+// enforced upstream by RequireSuperadmin.
+func (h *Handler) AddPartnerAdmin(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return
+	}
+	id, err := httputil.ParseSurrogateID("partnerId", chi.URLParam(r, "partnerId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	var request organization.AddPartnerAdminRequest
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(request); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	a, err := h.storage.AddPartnerAdmin(r.Context(), id, request.UserID, claims.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrPartnerAdminUser) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "user_id",
+				Code:    "invalid_value",
+				Message: "user_id must name an existing user",
+			}})
+			return
+		}
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if a == nil {
+		httputil.Respond404(w, r, "Partner not found", reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": a})
+}
+
+// @Summary Revoke a partner admin (superadmin)
+// @Tags orgs,internal
+// @ID partners.admins.remove
+// @Param partnerId path int true "Partner id" minimum(1) format(int64)
+// @Param userId path int true "User id" minimum(1) format(int64)
+// @Success 204
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/admin/partners/{partnerId}/admins/{userId} [delete]
+// RemovePartnerAdmin revokes a user's admin rights on a partner.
+// Authorization is enforced upstream by RequireSuperadmin.
+func (h *Handler) RemovePartnerAdmin(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("partnerId", chi.URLParam(r, "partnerId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	userID, err := httputil.ParseSurrogateID("userId", chi.URLParam(r, "userId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	removed, err := h.storage.RemovePartnerAdmin(r.Context(), id, userID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !removed {
+		httputil.Respond404(w, r, "Partner admin not found", reqID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List the partners the caller administers
+// @Tags orgs,internal
+// @ID partners.mine
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]any "data: []organization.Partner"
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/partners [get]
+// ListMyPartners returns the partners the session user administers.
+func (h *Handler) ListMyPartners(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return
+	}
+
+	partners, err := h.storage.ListUserPartners(r.Context(), claims.UserID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": partners})
+}
+
+// @Summary Roll-up report across a partner's organizations
+// @Description Totals across every live org the partner owns, with a per-org breakdown: assets, active assets, assets seen by a reader within the last `days` days, utilization (seen / active), and open sensor and stock alerts. Readable by the partner's admins and superadmins.
+// @Tags orgs,internal
+// @ID partners.rollup
+// @Accept json
+// @Produce json
+// @Param partnerId path int true "Partner id" minimum(1) format(int64)
+// @Param days query int false "Utilization window in days" default(30) minimum(1) maximum(365)
+// @Success 200 {object} map[string]any "data: organization.PartnerRollup"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/partners/{partnerId}/rollup [get]
+// GetPartnerRollup returns the roll-up report for a partner. Authorization
+// is enforced upstream by RequirePartnerAdmin.
+func (h *Handler) GetPartnerRollup(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	id, err := httputil.ParseSurrogateID("partnerId", chi.URLParam(r, "partnerId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	days := organization.DefaultRollupDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > organization.MaxRollupDays {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "days",
+				Code:    "invalid_value",
+				Message: "days must be an integer from 1 to 365",
+			}})
+			return
+		}
+		days = n
+	}
+
+	rollup, err := h.storage.GetPartnerRollup(r.Context(), id, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if rollup == nil {
+		httputil.Respond404(w, r, "Partner not found", reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": rollup})
+}
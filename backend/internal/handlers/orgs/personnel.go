@@ -0,0 +1,94 @@
+package orgs
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// @Summary Get an organization's personnel settings
+// @Description Internal-only. Returns how the org treats person-assets (assets with `metadata.person` = true, e.g. badged staff tracked for mustering): whether location reports show them under a pseudonym. How long their scans are kept is set with the retention settings' `personnel_scan_days`.
+// @Tags orgs,internal
+// @ID orgs.personnel_settings.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: organization.PersonnelSettings"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/personnel-settings [get]
+// GetPersonnelSettings returns the org's personnel settings.
+func (h *Handler) GetPersonnelSettings(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	s, err := h.storage.GetPersonnelSettings(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get personnel settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if s == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": s})
+}
+
+// @Summary Replace an organization's personnel settings
+// @Description Internal-only. Full-replace. With `anonymize_reports`, the asset-locations report, shift presence report and kiosk dashboards show each person-asset as `Person <asset_id>` with external_key `person-<asset_id>`, and the asset-locations `q` and `asset_external_key` filters no longer match person-assets. Mustering roll calls keep real names.
+// @Tags orgs,internal
+// @ID orgs.personnel_settings.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.PersonnelSettings true "Personnel settings"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} map[string]any "data: organization.PersonnelSettings"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/personnel-settings [patch]
+// PatchPersonnelSettings replaces the org's personnel settings.
+func (h *Handler) PatchPersonnelSettings(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.PersonnelSettings
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdatePersonnelSettings(r.Context(), id, req); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update personnel settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": req})
+}
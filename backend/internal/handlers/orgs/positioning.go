@@ -0,0 +1,135 @@
+package orgs
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/positioning"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// PositioningView is the GET/PATCH payload: the stored org settings plus the
+// system defaults, so the UI can render unset tuning fields as
+// "blank = system default (X)".
+type PositioningView struct {
+	Settings       organization.PositioningSettings `json:"settings"`
+	SystemDefaults organization.PositioningSettings `json:"system_defaults"`
+}
+
+func positioningView(s organization.PositioningSettings) PositioningView {
+	return PositioningView{Settings: s, SystemDefaults: positioning.SystemSettings()}
+}
+
+// validatePositioningSettings checks the provided (non-nil) tuning fields.
+// nil fields mean "use the system default" and are always allowed.
+func validatePositioningSettings(s organization.PositioningSettings) error {
+	if s.SmoothingFactor != nil && (*s.SmoothingFactor <= 0 || *s.SmoothingFactor > 1) {
+		return fmt.Errorf("smoothing_factor must be greater than 0 and at most 1")
+	}
+	if s.HysteresisDB != nil && (*s.HysteresisDB < 0 || *s.HysteresisDB > 40) {
+		return fmt.Errorf("hysteresis_db must be between 0 and 40")
+	}
+	if s.MinDwellSeconds != nil && (*s.MinDwellSeconds < 0 || *s.MinDwellSeconds > 3600) {
+		return fmt.Errorf("min_dwell_seconds must be between 0 and 3600")
+	}
+	if s.StaleSeconds != nil && (*s.StaleSeconds < 1 || *s.StaleSeconds > 3600) {
+		return fmt.Errorf("stale_seconds must be between 1 and 3600")
+	}
+	if s.HeartbeatSeconds != nil && (*s.HeartbeatSeconds < 10 || *s.HeartbeatSeconds > 86400) {
+		return fmt.Errorf("heartbeat_seconds must be between 10 and 86400")
+	}
+	return nil
+}
+
+// @Summary Get an organization's BLE positioning settings
+// @Description Internal-only. Returns whether BLE zone estimation is on, the org's tuning, and the system defaults. Gateways map to zones through their scan point's location; scan point metadata.rssi_offset (dB) calibrates a gateway.
+// @Tags orgs,internal
+// @ID orgs.positioning.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: PositioningView"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/positioning [get]
+// GetPositioning returns the org's BLE positioning settings.
+func (h *Handler) GetPositioning(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	ps, err := h.storage.GetPositioningSettings(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get positioning settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if ps == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": positioningView(*ps)})
+}
+
+// @Summary Replace an organization's BLE positioning settings
+// @Description Internal-only. Full-replace: omitted/null tuning fields fall back to the system default. While enabled, BLE gateway reads stop writing one scan per advertisement; the positioning engine writes a scan when a beacon's estimated zone changes, plus a heartbeat while it stays put. Changes take effect within 30 seconds.
+// @Tags orgs,internal
+// @ID orgs.positioning.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.PositioningSettings true "Positioning settings"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} map[string]any "data: PositioningView"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/positioning [patch]
+// PatchPositioning replaces the org's BLE positioning settings.
+func (h *Handler) PatchPositioning(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.PositioningSettings
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := validatePositioningSettings(req); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			err.Error(), middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdatePositioningSettings(r.Context(), id, req); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update positioning settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": positioningView(req)})
+}
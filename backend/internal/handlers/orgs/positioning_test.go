@@ -0,0 +1,43 @@
+package orgs
+
+import (
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+func fp(v float64) *float64 { return &v }
+
+func TestValidatePositioningSettings(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      organization.PositioningSettings
+		wantErr bool
+	}{
+		{"all nil ok", organization.PositioningSettings{Enabled: true}, false},
+		{"valid full", organization.PositioningSettings{Enabled: true, SmoothingFactor: fp(0.5), HysteresisDB: ip(4),
+			MinDwellSeconds: ip(0), StaleSeconds: ip(20), HeartbeatSeconds: ip(60)}, false},
+		{"smoothing zero", organization.PositioningSettings{SmoothingFactor: fp(0)}, true},
+		{"smoothing one ok", organization.PositioningSettings{SmoothingFactor: fp(1)}, false},
+		{"smoothing above one", organization.PositioningSettings{SmoothingFactor: fp(1.5)}, true},
+		{"hysteresis negative", organization.PositioningSettings{HysteresisDB: ip(-1)}, true},
+		{"dwell negative", organization.PositioningSettings{MinDwellSeconds: ip(-1)}, true},
+		{"stale zero", organization.PositioningSettings{StaleSeconds: ip(0)}, true},
+		{"heartbeat too short", organization.PositioningSettings{HeartbeatSeconds: ip(5)}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validatePositioningSettings(c.in)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got err=%v", c.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestPositioningView_IncludesSystemDefaults(t *testing.T) {
+	v := positioningView(organization.PositioningSettings{})
+	if v.SystemDefaults.HysteresisDB == nil || *v.SystemDefaults.HysteresisDB != 6 || *v.SystemDefaults.StaleSeconds != 30 {
+		t.Fatalf("system defaults wrong: %+v", v.SystemDefaults)
+	}
+}
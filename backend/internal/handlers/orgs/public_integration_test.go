@@ -44,7 +44,7 @@ func TestGetOrgMe_ValidAPIKey(t *testing.T) {
 	token, err := jwt.GenerateAccessToken(key.JTI, orgID, []string{"assets:read"}, &exp)
 	require.NoError(t, err)
 
-	service := orgsservice.NewService(pool, store, nil)
+	service := orgsservice.NewService(store, nil)
 	handler := orgs.NewHandler(store, service, nil)
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
@@ -79,12 +79,11 @@ func TestGetOrgMe_SessionTokenRejected(t *testing.T) {
 	t.Setenv("JWT_SECRET", "test-secret-public")
 	store, cleanup := testutil.SetupTestDB(t)
 	defer cleanup()
-	pool := store.Pool().(*pgxpool.Pool)
 
 	sessionToken, err := jwt.Generate(1, "u@e.com", intPtr(42))
 	require.NoError(t, err)
 
-	service := orgsservice.NewService(pool, store, nil)
+	service := orgsservice.NewService(store, nil)
 	handler := orgs.NewHandler(store, service, nil)
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
@@ -0,0 +1,140 @@
+package orgs
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// RetentionView is the GET/PATCH payload: the org's stored settings, the
+// plan limits they may not exceed, and the windows the janitor enforces.
+type RetentionView struct {
+	Settings  organization.RetentionSettings `json:"settings"`
+	Limits    organization.RetentionPolicy   `json:"limits"`
+	Effective organization.RetentionPolicy   `json:"effective"`
+}
+
+func retentionView(o organization.OrgRetention) RetentionView {
+	return RetentionView{Settings: o.Settings, Limits: o.Limits, Effective: o.Effective()}
+}
+
+// validateRetentionSettings checks the provided (non-nil) windows against the
+// plan limits. nil fields mean "use the plan window" and are always allowed.
+func validateRetentionSettings(s organization.RetentionSettings, limits organization.RetentionPolicy) error {
+	if s.AuditDays != nil && (*s.AuditDays < 1 || *s.AuditDays > limits.AuditDays) {
+		return fmt.Errorf("audit_days must be between 1 and %d", limits.AuditDays)
+	}
+	if s.ScanDays != nil && (*s.ScanDays < 1 || *s.ScanDays > limits.ScanDays) {
+		return fmt.Errorf("scan_days must be between 1 and %d", limits.ScanDays)
+	}
+	if s.PersonnelScanDays != nil && (*s.PersonnelScanDays < 1 || *s.PersonnelScanDays > limits.ScanDays) {
+		return fmt.Errorf("personnel_scan_days must be between 1 and %d", limits.ScanDays)
+	}
+	return nil
+}
+
+// @Summary Get an organization's data retention settings
+// @Description Internal-only. Returns the org's retention settings, its plan limits, and the effective windows the retention janitor enforces (audit = webhook deliveries, scans = asset scan history, personnel scans = the scan history of person-assets, never kept longer than scans).
+// @Tags orgs,internal
+// @ID orgs.retention.get
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: RetentionView"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/retention [get]
+// GetRetention returns the org's retention settings and plan limits.
+func (h *Handler) GetRetention(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	o, err := h.storage.GetOrgRetention(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get retention settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if o == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": retentionView(*o)})
+}
+
+// @Summary Replace an organization's data retention settings
+// @Description Internal-only. Full-replace: omitted/null fields fall back to the plan window. A window may be shortened below the plan's, never lengthened. Shortening takes effect on the janitor's next hourly run and deletes the aged-out data permanently.
+// @Tags orgs,internal
+// @ID orgs.retention.patch
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body organization.RetentionSettings true "Org retention settings"
+// @Param X-Approval-ID header int false "Id of the approved request being replayed"
+// @Success 200 {object} map[string]any "data: RetentionView"
+// @Success 202 {object} map[string]any "data: approval.Request, when the org's approval rules park the change"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/retention [patch]
+// PatchRetention replaces the org's retention settings.
+func (h *Handler) PatchRetention(w http.ResponseWriter, r *http.Request) {
+	id, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	var req organization.RetentionSettings
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	o, err := h.storage.GetOrgRetention(r.Context(), id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get retention settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if o == nil {
+		httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := validateRetentionSettings(req, o.Limits); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			err.Error(), middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	if err := h.storage.UpdateOrgRetentionSettings(r.Context(), id, req); err != nil {
+		if err.Error() == "organization not found" {
+			httputil.Respond404(w, r, "Organization not found", middleware.GetRequestID(r.Context()))
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update retention settings", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	o.Settings = req
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": retentionView(*o)})
+}
@@ -0,0 +1,34 @@
+package orgs
+
+import (
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+func TestValidateRetentionSettings(t *testing.T) {
+	limits := organization.RetentionPolicy{AuditDays: 90, ScanDays: 365}
+	cases := []struct {
+		name    string
+		in      organization.RetentionSettings
+		wantErr bool
+	}{
+		{"all nil ok", organization.RetentionSettings{}, false},
+		{"shorter ok", organization.RetentionSettings{AuditDays: ip(30), ScanDays: ip(7)}, false},
+		{"at limit ok", organization.RetentionSettings{AuditDays: ip(90), ScanDays: ip(365)}, false},
+		{"audit zero", organization.RetentionSettings{AuditDays: ip(0)}, true},
+		{"audit over plan", organization.RetentionSettings{AuditDays: ip(91)}, true},
+		{"scan over plan", organization.RetentionSettings{ScanDays: ip(366)}, true},
+		{"personnel shorter ok", organization.RetentionSettings{PersonnelScanDays: ip(14)}, false},
+		{"personnel zero", organization.RetentionSettings{PersonnelScanDays: ip(0)}, true},
+		{"personnel over plan", organization.RetentionSettings{PersonnelScanDays: ip(366)}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateRetentionSettings(c.in, limits)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got err=%v", c.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package orgs
+
+import (
+	stderrors "errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// @Summary Clone an organization into a sandbox
+// @Description Internal-only. Creates a new sandbox organization, named after this one, with the caller as its only admin and a copy of this organization's live locations (with their hierarchy), assets and tags. Scan history, members, API keys, webhooks, connectors and devices are not copied. A sandbox is write-isolated: its changes are never sent to the event export or to ERP connectors, and assets cannot be transferred between it and a production organization. Every authenticated response served in a sandbox carries the X-TrakRF-Sandbox: true header, and the organization's is_sandbox field is true. A sandbox cannot itself be cloned.
+// @Tags orgs,internal
+// @ID orgs.sandbox.create
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 201 {object} map[string]any "data: organization.Organization"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse "The organization is already a sandbox"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/sandbox [post]
+// CreateSandbox handles POST /api/v1/orgs/{id}/sandbox.
+func (h *Handler) CreateSandbox(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return
+	}
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	org, err := h.storage.CreateSandboxOrg(r.Context(), orgID, claims.UserID)
+	if stderrors.Is(err, storage.ErrSandboxOfSandbox) {
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+		return
+	}
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to create sandbox organization", reqID)
+		return
+	}
+	if org == nil {
+		httputil.Respond404(w, r, "organization not found", reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/orgs/"+strconv.Itoa(org.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": org})
+}
@@ -0,0 +1,140 @@
+package orgs
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/serviceaccount"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// @Summary List an organization's service accounts
+// @Description Internal-only. Service accounts are non-human principals that own API keys; active_key_count counts their unrevoked keys.
+// @Tags orgs,internal
+// @ID orgs.service_accounts.list
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: []serviceaccount.ServiceAccount"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/service-accounts [get]
+// ListServiceAccounts handles GET /api/v1/orgs/{id}/service-accounts.
+func (h *Handler) ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	accounts, err := h.storage.ListServiceAccounts(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to list service accounts", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": accounts})
+}
+
+// @Summary Create a service account
+// @Description Internal-only. A service account has no email or password and cannot sign in; mint keys for it with POST /api/v1/orgs/{id}/api-keys and service_account_id. Its keys keep working when the admin who created them leaves the org.
+// @Tags orgs,internal
+// @ID orgs.service_accounts.create
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body serviceaccount.CreateServiceAccountRequest true "Service account"
+// @Success 201 {object} map[string]any "data: serviceaccount.ServiceAccount"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse "Name already in use"
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/service-accounts [post]
+// CreateServiceAccount handles POST /api/v1/orgs/{id}/service-accounts.
+func (h *Handler) CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	var req serviceaccount.CreateServiceAccountRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	var createdBy *int
+	if claims := middleware.GetUserClaims(r); claims != nil {
+		createdBy = &claims.UserID
+	}
+
+	account, err := h.storage.CreateServiceAccount(r.Context(), orgID, createdBy, req)
+	if err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to create service account", reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/orgs/"+strconv.Itoa(orgID)+"/service-accounts/"+strconv.Itoa(account.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": account})
+}
+
+// @Summary Delete a service account
+// @Description Internal-only. Revokes every API key the account owns.
+// @Tags orgs,internal
+// @ID orgs.service_accounts.delete
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param serviceAccountId path int true "Service account id" minimum(1) format(int64)
+// @Success 204
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/service-accounts/{serviceAccountId} [delete]
+// DeleteServiceAccount handles DELETE /api/v1/orgs/{id}/service-accounts/{serviceAccountId}.
+func (h *Handler) DeleteServiceAccount(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	accountID, err := httputil.ParseSurrogateID("serviceAccountId", chi.URLParam(r, "serviceAccountId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	deleted, err := h.storage.DeleteServiceAccount(r.Context(), orgID, accountID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to delete service account", reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "Service account not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,229 @@
+package orgs
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/webhook"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ListWebhookDeliveriesResponse is the typed envelope returned by
+// GET /api/v1/orgs/{id}/webhook-deliveries.
+type ListWebhookDeliveriesResponse struct {
+	Data       []webhook.DeliveryLogEntry `json:"data"`
+	Limit      int                        `json:"limit"       example:"50"`
+	Offset     int                        `json:"offset"      example:"0"`
+	TotalCount int                        `json:"total_count" example:"100"`
+}
+
+// parseDeliveryLogFilter maps the list query onto a webhook.DeliveryLogFilter,
+// writing a 400 and returning false on a malformed value.
+func parseDeliveryLogFilter(w http.ResponseWriter, r *http.Request, reqID string, params httputil.ListParams) (webhook.DeliveryLogFilter, bool) {
+	f := webhook.DeliveryLogFilter{Limit: params.Limit, Offset: params.Offset}
+	invalid := func(field, msg string) (webhook.DeliveryLogFilter, bool) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: field, Code: "invalid_value", Message: msg,
+		}})
+		return f, false
+	}
+	first := func(key string) string {
+		if vs := params.Filters[key]; len(vs) > 0 {
+			return vs[0]
+		}
+		return ""
+	}
+
+	if v := first("endpoint_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id < 1 {
+			return invalid("endpoint_id", "endpoint_id must be a positive integer")
+		}
+		f.EndpointID = id
+	}
+	f.EventType = first("event_type")
+	switch v := first("status"); v {
+	case "", webhook.StatusPending, webhook.StatusDelivered, webhook.StatusFailed:
+		f.Status = v
+	default:
+		return invalid("status", "status must be one of pending, delivered, failed")
+	}
+	for _, field := range []string{"from", "to"} {
+		v := first(field)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return invalid(field, fmt.Sprintf("Invalid '%s' timestamp; expected RFC 3339, e.g. 2026-04-21T15:00:00Z", field))
+		}
+		if field == "from" {
+			f.From = &t
+		} else {
+			f.To = &t
+		}
+	}
+	return f, true
+}
+
+// @Summary Browse an organization's webhook delivery log
+// @Description Internal-only. Every queued delivery, newest first, with its status, attempt count, last receiver response, and the X-TrakRF-Signature header of its most recent POST. Payloads are omitted here; fetch a single delivery to see one. from/to bound the time the event was queued (from inclusive, to exclusive). Rows age out per the org's audit retention.
+// @Tags orgs,internal
+// @ID orgs.webhook_deliveries.list
+// @Produce json
+// @Param id          path  int    true  "Organization id" minimum(1) format(int64)
+// @Param endpoint_id query int    false "filter by webhook endpoint id"
+// @Param event_type  query string false "filter by event type, e.g. asset.updated"
+// @Param status      query string false "filter by delivery status" Enums(pending, delivered, failed)
+// @Param from        query string false "queued at or after (RFC 3339)"
+// @Param to          query string false "queued before (RFC 3339)"
+// @Param limit       query int    false "max 200" default(50) minimum(1) maximum(200)
+// @Param offset      query int    false "min 0"   default(0) minimum(0)
+// @Success 200 {object} orgs.ListWebhookDeliveriesResponse
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/webhook-deliveries [get]
+// ListWebhookDeliveries handles GET /api/v1/orgs/{id}/webhook-deliveries.
+func (h *Handler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	params, err := httputil.ParseListParams(r, httputil.ListAllowlist{
+		Filters: []string{"endpoint_id", "event_type", "status", "from", "to"},
+	})
+	if err != nil {
+		httputil.RespondListParamError(w, r, err, reqID)
+		return
+	}
+	f, ok := parseDeliveryLogFilter(w, r, reqID, params)
+	if !ok {
+		return
+	}
+
+	entries, err := h.storage.ListWebhookDeliveries(r.Context(), orgID, f)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to list webhook deliveries", reqID)
+		return
+	}
+	total, err := h.storage.CountWebhookDeliveries(r.Context(), orgID, f)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to count webhook deliveries", reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, ListWebhookDeliveriesResponse{
+		Data:       entries,
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		TotalCount: total,
+	})
+}
+
+// @Summary Inspect a webhook delivery
+// @Description Internal-only. The delivery log entry with the exact JSON payload that was (or will be) POSTed.
+// @Tags orgs,internal
+// @ID orgs.webhook_deliveries.get
+// @Produce json
+// @Param id         path int true "Organization id" minimum(1) format(int64)
+// @Param deliveryId path int true "Delivery id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: webhook.DeliveryLogEntry"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/webhook-deliveries/{deliveryId} [get]
+// GetWebhookDelivery handles GET /api/v1/orgs/{id}/webhook-deliveries/{deliveryId}.
+func (h *Handler) GetWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, deliveryID, ok := parseDeliveryPath(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	entry, err := h.storage.GetWebhookDelivery(r.Context(), orgID, deliveryID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get webhook delivery", reqID)
+		return
+	}
+	if entry == nil {
+		httputil.Respond404(w, r, "Webhook delivery not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": entry})
+}
+
+// @Summary Re-deliver a webhook event
+// @Description Internal-only. Queues a new delivery of the same payload to the same endpoint; the original delivery is left untouched. The replay is signed afresh at send time with the endpoint's current secret and carries an X-TrakRF-Replay-Of header naming the original delivery id. Returns the queued delivery.
+// @Tags orgs,internal
+// @ID orgs.webhook_deliveries.redeliver
+// @Produce json
+// @Param id         path int true "Organization id" minimum(1) format(int64)
+// @Param deliveryId path int true "Delivery id to replay" minimum(1) format(int64)
+// @Success 202 {object} map[string]any "data: webhook.DeliveryLogEntry"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 409 {object} modelerrors.ErrorResponse "Endpoint deleted or inactive"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/webhook-deliveries/{deliveryId}/redeliver [post]
+// RedeliverWebhook handles POST /api/v1/orgs/{id}/webhook-deliveries/{deliveryId}/redeliver.
+func (h *Handler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, deliveryID, ok := parseDeliveryPath(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	entry, err := h.storage.RedeliverWebhook(r.Context(), orgID, deliveryID)
+	if stderrors.Is(err, storage.ErrWebhookEndpointGone) {
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict,
+			"The delivery's webhook endpoint has been deleted or deactivated", reqID)
+		return
+	}
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to re-deliver webhook", reqID)
+		return
+	}
+	if entry == nil {
+		httputil.Respond404(w, r, "Webhook delivery not found", reqID)
+		return
+	}
+	w.Header().Set("Location", fmt.Sprintf("/api/v1/orgs/%d/webhook-deliveries/%d", orgID, entry.ID))
+	httputil.WriteJSON(w, http.StatusAccepted, map[string]any{"data": entry})
+}
+
+func parseDeliveryPath(w http.ResponseWriter, r *http.Request, reqID string) (int, int64, bool) {
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, 0, false
+	}
+	deliveryID, err := httputil.ParseSurrogateID("deliveryId", chi.URLParam(r, "deliveryId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, 0, false
+	}
+	return orgID, int64(deliveryID), true
+}
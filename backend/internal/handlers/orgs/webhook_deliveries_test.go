@@ -0,0 +1,48 @@
+package orgs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+func TestParseDeliveryLogFilter(t *testing.T) {
+	cases := []struct {
+		name   string
+		query  string
+		wantOK bool
+	}{
+		{"empty", "", true},
+		{"all filters", "endpoint_id=7&event_type=asset.updated&status=failed&from=2026-10-16T15:00:00Z&to=2026-10-16T16:00:00Z", true},
+		{"bad endpoint", "endpoint_id=abc", false},
+		{"zero endpoint", "endpoint_id=0", false},
+		{"bad status", "status=retrying", false},
+		{"bad from", "from=3pm", false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/orgs/1/webhook-deliveries?"+c.query, nil)
+			params, err := httputil.ParseListParams(r, httputil.ListAllowlist{
+				Filters: []string{"endpoint_id", "event_type", "status", "from", "to"},
+			})
+			if err != nil {
+				t.Fatalf("ParseListParams: %v", err)
+			}
+			w := httptest.NewRecorder()
+			f, ok := parseDeliveryLogFilter(w, r, "req", params)
+			if ok != c.wantOK {
+				t.Fatalf("ok = %v, want %v (body %s)", ok, c.wantOK, w.Body.String())
+			}
+			if !ok && w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", w.Code)
+			}
+			if c.name == "all filters" {
+				if f.EndpointID != 7 || f.Status != "failed" || f.EventType != "asset.updated" || f.From == nil || f.To == nil {
+					t.Errorf("filter = %+v", f)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package orgs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/webhook"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+	"github.com/trakrf/platform/backend/internal/util/netguard"
+)
+
+// generateWebhookSecret returns "whsec_" + 64 hex chars of randomness.
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// validateWebhookRequest checks what the struct tags cannot: an absolute
+// http(s) URL off the server's own network, and known event types.
+func validateWebhookRequest(ctx context.Context, req webhook.CreateEndpointRequest) string {
+	if err := netguard.CheckURL(ctx, req.URL); err != nil {
+		return "url " + err.Error()
+	}
+	for _, t := range req.EventTypes {
+		if !events.IsEntityType(events.Type(t)) {
+			return "Unknown event type: " + t
+		}
+	}
+	return ""
+}
+
+// @Summary List an organization's webhook endpoints
+// @Description Internal-only. Signing secrets are never returned after creation.
+// @Tags orgs,internal
+// @ID orgs.webhooks.list
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Success 200 {object} map[string]any "data: []webhook.Endpoint"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/webhooks [get]
+// ListWebhooks handles GET /api/v1/orgs/{id}/webhooks.
+func (h *Handler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	endpoints, err := h.storage.ListWebhookEndpoints(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to list webhooks", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": endpoints})
+}
+
+// @Summary Register a webhook endpoint
+// @Description Internal-only. Events are POSTed as JSON with an X-TrakRF-Signature header (t=<unix>,v1=<hex HMAC-SHA256(secret, "<unix>.<body>")>). The secret is returned exactly once. Empty event_types subscribes to every type.
+// @Tags orgs,internal
+// @ID orgs.webhooks.create
+// @Accept json
+// @Produce json
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param request body webhook.CreateEndpointRequest true "Endpoint"
+// @Success 201 {object} map[string]any "data: webhook.CreateEndpointResponse"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/webhooks [post]
+// CreateWebhook handles POST /api/v1/orgs/{id}/webhooks.
+func (h *Handler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	var req webhook.CreateEndpointRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if msg := validateWebhookRequest(r.Context(), req); msg != "" {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation, msg, reqID)
+		return
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to generate webhook secret", reqID)
+		return
+	}
+
+	endpoint, err := h.storage.CreateWebhookEndpoint(r.Context(), orgID, req.URL, secret, req.EventTypes)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to create webhook", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{
+		"data": webhook.CreateEndpointResponse{Endpoint: *endpoint, Secret: secret},
+	})
+}
+
+// @Summary Delete a webhook endpoint
+// @Description Internal-only. Pending deliveries to the endpoint are failed.
+// @Tags orgs,internal
+// @ID orgs.webhooks.delete
+// @Param id path int true "Organization id" minimum(1) format(int64)
+// @Param webhookId path int true "Webhook endpoint id" minimum(1) format(int64)
+// @Success 204
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 403 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/orgs/{id}/webhooks/{webhookId} [delete]
+// DeleteWebhook handles DELETE /api/v1/orgs/{id}/webhooks/{webhookId}.
+func (h *Handler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := httputil.ParseSurrogateID("id", chi.URLParam(r, "id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	webhookID, err := httputil.ParseSurrogateID("webhookId", chi.URLParam(r, "webhookId"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	deleted, err := h.storage.DeleteWebhookEndpoint(r.Context(), orgID, webhookID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to delete webhook", reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "Webhook not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
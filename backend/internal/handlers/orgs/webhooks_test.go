@@ -0,0 +1,48 @@
+package orgs
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/webhook"
+)
+
+func TestValidateWebhookRequest(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      webhook.CreateEndpointRequest
+		wantErr bool
+	}{
+		{"https ok", webhook.CreateEndpointRequest{URL: "https://erp.example.com/hooks"}, false},
+		{"http ok", webhook.CreateEndpointRequest{URL: "http://93.184.216.34:8080/hooks"}, false},
+		{"private rejected", webhook.CreateEndpointRequest{URL: "http://10.0.0.5:8080/hooks"}, true},
+		{"loopback rejected", webhook.CreateEndpointRequest{URL: "http://127.0.0.1:8080/hooks"}, true},
+		{"metadata rejected", webhook.CreateEndpointRequest{URL: "http://169.254.169.254/latest/meta-data/"}, true},
+		{"localhost rejected", webhook.CreateEndpointRequest{URL: "http://localhost/hooks"}, true},
+		{"known types ok", webhook.CreateEndpointRequest{URL: "https://x.io", EventTypes: []string{"asset.created", "location.deleted"}}, false},
+		{"ftp rejected", webhook.CreateEndpointRequest{URL: "ftp://x.io/hook"}, true},
+		{"relative rejected", webhook.CreateEndpointRequest{URL: "/hooks"}, true},
+		{"unknown type", webhook.CreateEndpointRequest{URL: "https://x.io", EventTypes: []string{"asset.exploded"}}, true},
+		{"export-only type", webhook.CreateEndpointRequest{URL: "https://x.io", EventTypes: []string{"scan.recorded"}}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			msg := validateWebhookRequest(context.Background(), c.in)
+			if (msg != "") != c.wantErr {
+				t.Fatalf("validateWebhookRequest(%+v) = %q, wantErr %v", c.in, msg, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateWebhookSecret(t *testing.T) {
+	a, err := generateWebhookSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := generateWebhookSecret()
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 || a == b {
+		t.Fatalf("unexpected secrets %q, %q", a, b)
+	}
+}
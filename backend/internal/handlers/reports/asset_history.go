@@ -90,6 +90,24 @@ func (h *Handler) GetAssetHistory(w http.ResponseWriter, r *http.Request) {
 		httputil.Respond404(w, r, apierrors.ReportAssetNotFound, reqID)
 		return
 	}
+	// A caller limited to location subtrees sees only assets currently
+	// inside them, and only the history rows at locations inside them.
+	scope, err := h.subtreeScope(r, orgID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if scope.Restricted {
+		inside, err := h.storage.IsAssetInSubtrees(r.Context(), orgID, assetRow.ID, scope.RootIDs)
+		if err != nil {
+			httputil.RespondStorageError(w, r, err, reqID)
+			return
+		}
+		if !inside {
+			httputil.Respond404(w, r, apierrors.ReportAssetNotFound, reqID)
+			return
+		}
+	}
 
 	params, err := httputil.ParseListParams(r, httputil.ListAllowlist{
 		Filters: []string{"from", "to"},
@@ -100,7 +118,7 @@ func (h *Handler) GetAssetHistory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	filter := report.AssetHistoryFilter{Limit: params.Limit, Offset: params.Offset}
+	filter := report.AssetHistoryFilter{Limit: params.Limit, Offset: params.Offset, SubtreeScope: scope}
 	if vs, ok := params.Filters["from"]; ok && len(vs) > 0 {
 		t, err := time.Parse(time.RFC3339Nano, vs[0])
 		if err != nil {
@@ -0,0 +1,93 @@
+package reports
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ListAssetValuesResponse is the typed envelope returned by
+// GET /api/v1/reports/asset-values.
+type ListAssetValuesResponse struct {
+	Locale     string                   `json:"locale"      example:"en-US"`
+	Totals     []report.AssetValueTotal `json:"totals"`
+	Data       []report.AssetValueItem  `json:"data"`
+	Limit      int                      `json:"limit"       example:"50"`
+	Offset     int                      `json:"offset"      example:"0"`
+	TotalCount int                      `json:"total_count" example:"100"`
+}
+
+// @Summary Asset valuation
+// @Description Live assets with financials (PUT /api/v1/assets/{asset_id}/financials), highest book value first, and per-currency totals of purchase cost and book value across all of them. Amounts in different currencies are totalled separately, never converted. Every amount carries its decimal `amount` and `currency` plus `formatted`, rendered in the org's locale (GET /api/v1/orgs/{id}/currency-settings). `currency` keeps only amounts in that currency.
+// @Tags reports,internal
+// @ID reports.asset_values
+// @Param currency query string false "ISO 4217 code; only assets valued in this currency"
+// @Param limit    query int    false "max 200" default(50) minimum(1) maximum(200)
+// @Param offset   query int    false "min 0"   default(0) minimum(0)
+// @Success 200 {object} reports.ListAssetValuesResponse
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/reports/asset-values [get]
+func (h *Handler) ListAssetValues(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	params, err := httputil.ParseListParams(r, httputil.ListAllowlist{
+		Filters: []string{"currency"},
+	})
+	if err != nil {
+		httputil.RespondListParamError(w, r, err, reqID)
+		return
+	}
+
+	filter := report.AssetValueFilter{Locale: organization.DefaultLocale, Limit: params.Limit, Offset: params.Offset}
+	if vs := params.Filters["currency"]; len(vs) > 0 {
+		filter.Currency = strings.TrimSpace(vs[0])
+		if !shared.IsCurrency(filter.Currency) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "currency",
+				Code:    "invalid_value",
+				Message: "currency must be a supported ISO 4217 code, e.g. USD",
+			}})
+			return
+		}
+	}
+	settings, err := h.storage.GetCurrencySettings(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+	if settings != nil {
+		filter.Locale = settings.Locale
+	}
+
+	items, totals, total, err := h.storage.ListAssetValues(r.Context(), orgID, filter)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, ListAssetValuesResponse{
+		Locale:     filter.Locale,
+		Totals:     totals,
+		Data:       items,
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		TotalCount: total,
+	})
+}
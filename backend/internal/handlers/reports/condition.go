@@ -0,0 +1,107 @@
+package reports
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ListConditionResponse is the typed envelope returned by
+// GET /api/v1/reports/condition.
+type ListConditionResponse struct {
+	Below      int                         `json:"below"       example:"3"`
+	Data       []report.ConditionBelowItem `json:"data"`
+	Limit      int                         `json:"limit"       example:"50"`
+	Offset     int                         `json:"offset"      example:"0"`
+	TotalCount int                         `json:"total_count" example:"100"`
+}
+
+// @Summary Assets in poor condition
+// @Description Assets whose latest condition report rates them below `below` (ratings run 1-5), lowest rating first then most recent. Each row carries the rating before it; `dropped` is true when that earlier report was at or above the threshold, and `dropped_only=true` keeps only those — assets this report took below the line. `since` keeps assets whose latest report is at or after that instant. Deleted assets are left out.
+// @Tags reports,internal
+// @ID reports.condition
+// @Param below        query int    false "rating threshold, 2-5" default(3) minimum(2) maximum(5)
+// @Param dropped_only query bool   false "only assets whose previous report was at or above the threshold" default(false)
+// @Param since        query string false "RFC 3339; only latest reports at or after this instant" format(date-time)
+// @Param limit        query int    false "max 200" default(50) minimum(1) maximum(200)
+// @Param offset       query int    false "min 0"   default(0) minimum(0)
+// @Success 200 {object} reports.ListConditionResponse
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/reports/condition [get]
+func (h *Handler) ListCondition(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	params, err := httputil.ParseListParams(r, httputil.ListAllowlist{
+		Filters: []string{"below", "dropped_only", "since"},
+	})
+	if err != nil {
+		httputil.RespondListParamError(w, r, err, reqID)
+		return
+	}
+
+	filter := report.ConditionBelowFilter{Below: report.DefaultConditionThreshold, Limit: params.Limit, Offset: params.Offset}
+	if vs := params.Filters["below"]; len(vs) > 0 {
+		n, err := strconv.Atoi(strings.TrimSpace(vs[0]))
+		if err != nil || n <= asset.ConditionRatingMin || n > asset.ConditionRatingMax {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "below",
+				Code:    "invalid_value",
+				Message: fmt.Sprintf("below must be an integer from %d to %d", asset.ConditionRatingMin+1, asset.ConditionRatingMax),
+			}})
+			return
+		}
+		filter.Below = n
+	}
+	if vs := params.Filters["dropped_only"]; len(vs) > 0 {
+		b, err := strconv.ParseBool(strings.TrimSpace(vs[0]))
+		if err != nil {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "dropped_only",
+				Code:    "invalid_value",
+				Message: "dropped_only must be true or false",
+			}})
+			return
+		}
+		filter.DroppedOnly = b
+	}
+	if vs := params.Filters["since"]; len(vs) > 0 {
+		since, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(vs[0]))
+		if err != nil {
+			respondInvalidTimestamp(w, r, "since", reqID)
+			return
+		}
+		filter.Since = &since
+	}
+
+	items, total, err := h.storage.ListConditionBelow(r.Context(), orgID, filter)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, ListConditionResponse{
+		Below:      filter.Below,
+		Data:       items,
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		TotalCount: total,
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/custody"
 	"github.com/trakrf/platform/backend/internal/middleware"
 	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/models/report"
@@ -16,11 +17,18 @@ import (
 // Handler handles report-related API requests
 type Handler struct {
 	storage *storage.Storage
+	signer  *custody.Signer
 }
 
 // NewHandler creates a new reports handler
 func NewHandler(storage *storage.Storage) *Handler {
-	return &Handler{storage: storage}
+	return NewHandlerWithSigner(storage, nil)
+}
+
+// NewHandlerWithSigner creates a reports handler whose chain-of-custody
+// documents can be signed with signer; nil serves them unsigned.
+func NewHandlerWithSigner(storage *storage.Storage, signer *custody.Signer) *Handler {
+	return &Handler{storage: storage, signer: signer}
 }
 
 // ListCurrentLocationsResponse is the typed envelope returned by
@@ -38,6 +46,8 @@ type ListCurrentLocationsResponse struct {
 // @Description Rows are produced from `scan_event` history and reflect the most recent observed location per asset. **Assets that have never been scanned do not appear in this report** — they exist in `/api/v1/assets` but have no derived location row until at least one scan event has been observed, so this endpoint's `total_count` can lag `/api/v1/assets` `total_count` for newly-onboarded inventory. Use `/api/v1/assets` directly if you need a complete asset roster including never-scanned assets.
 // @Description
 // @Description Temporal validity is applied to both joined entities. Assets whose effective window is past or future are excluded entirely. Locations whose effective window is past or future surface with null `location_id` / `location_external_key` while the parent asset row remains visible. Soft-deleted locations are projected the same way here — null on the report row — even though the identifier still lives on the location row; reports endpoints intentionally hide tombstoned anchor points from scan-derived summaries. Use the locations endpoint with `include_deleted=true` to retrieve the underlying identifier.
+// @Description
+// @Description Organizations tracking people with badges can anonymize them: person-assets (`metadata.person` = true) then appear with `asset_external_key` `person-<asset_id>`, and the `q` and `asset_external_key` filters do not match them.
 // @Tags reports,public
 // @ID reports.asset-locations
 // @Param limit                 query int    false "max 200"   default(50) minimum(1) maximum(200)
@@ -168,6 +178,10 @@ func (h *Handler) ListCurrentLocations(w http.ResponseWriter, r *http.Request) {
 	for _, s := range params.Sorts {
 		filter.Sorts = append(filter.Sorts, report.CurrentLocationSort{Field: s.Field, Desc: s.Desc})
 	}
+	if filter.SubtreeScope, err = h.subtreeScope(r, orgID); err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
 
 	items, err := h.storage.ListCurrentLocations(r.Context(), orgID, filter)
 	if err != nil {
@@ -197,6 +211,19 @@ func (h *Handler) ListCurrentLocations(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// RegisterRoutes is intentionally empty — reports routes are registered in
-// internal/cmd/serve/router.go across the public and session-only groups.
-func (h *Handler) RegisterRoutes(r chi.Router) {}
+// RegisterRoutes wires the session-only reports. The public reports are
+// registered in internal/cmd/serve/router.go under their API-key scopes.
+func (h *Handler) RegisterRoutes(r chi.Router) {
+	r.Get("/api/v1/reports/expiring", h.ListExpiring)
+	r.Get("/api/v1/reports/expiring-documents", h.ListExpiringDocuments)
+	r.Get("/api/v1/reports/utilization", h.GetUtilization)
+	r.Get("/api/v1/reports/inventory", h.ListInventory)
+	r.Get("/api/v1/reports/condition", h.ListCondition)
+	r.Get("/api/v1/reports/asset-values", h.ListAssetValues)
+	r.Get("/api/v1/reports/dock-movements", h.ListDockMovements)
+	r.Get("/api/v1/reports/locations/{location_id}/activity", h.GetLocationActivity)
+	r.Get("/api/v1/reports/locations/{location_id}/shift-presence", h.GetShiftPresence)
+	r.Get("/api/v1/assets/{asset_id}/custody", h.GetCustody)
+	r.Get("/api/v1/custody/signing-key", h.GetCustodySigningKey)
+	r.Post(middleware.CustodyVerifyPath, h.VerifyCustody)
+}
@@ -0,0 +1,63 @@
+//go:build integration
+// +build integration
+
+package reports
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+// TestListCurrentLocations_Golden pins the full /reports/asset-locations
+// payload for a small fleet: a moved asset reports its latest location, a
+// never-scanned asset is absent, and rows come newest-first. Regenerate
+// testdata/golden/asset_locations.json with UPDATE_GOLDEN=1.
+func TestListCurrentLocations_Golden(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.NewOrgFactory().Create(t, pool)
+
+	locs := testutil.NewLocationFactory(orgID)
+	wh := locs.WithExternalKey("WH-1").WithName("Warehouse 1").Create(t, pool)
+	bay := locs.WithParent(wh).WithExternalKey("BAY-1").WithName("Bay 1").Create(t, pool)
+
+	asset := func(key string) int {
+		f := testutil.NewAssetFactory(orgID).WithIdentifier(key).WithName(key).WithValidTo(nil)
+		f.ValidFrom = testutil.FixtureEpoch
+		id := f.Create(t, pool)
+		testutil.NewIdentifierFactory(orgID).ForAsset(id).Create(t, pool)
+		return id
+	}
+	fl1, fl2 := asset("FL-1"), asset("FL-2")
+	asset("FL-3") // never scanned
+
+	scans := testutil.NewScanFactory(orgID)
+	scans.Create(t, pool, fl1, wh)
+	t1 := scans.Create(t, pool, fl1, bay)
+	t2 := scans.Create(t, pool, fl2, wh)
+	testutil.RefreshAssetScanLatest(t, pool)
+
+	router := setupTemporalReportsRouter(NewHandler(store))
+	req := withReportsOrg(httptest.NewRequest(http.MethodGet, "/api/v1/reports/asset-locations", nil), orgID)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+
+	testutil.AssertGoldenJSON(t, "asset_locations", w.Body.Bytes(),
+		testutil.Alias(wh, "$WH_1"),
+		testutil.Alias(bay, "$BAY_1"),
+		testutil.Alias(fl1, "$FL_1"),
+		testutil.Alias(fl2, "$FL_2"),
+		testutil.Alias(shared.FormatPublicTime(t1), "$T1"),
+		testutil.Alias(shared.FormatPublicTime(t2), "$T2"),
+	)
+}
@@ -0,0 +1,226 @@
+package reports
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/apierrors"
+	"github.com/trakrf/platform/backend/internal/custody"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// CustodySigningKey is the public half of the key custody documents are
+// signed with.
+type CustodySigningKey struct {
+	Alg       string `json:"alg" example:"Ed25519"`
+	KeyID     string `json:"key_id"`
+	PublicKey string `json:"public_key"` // base64
+}
+
+// CustodyVerification is the result of POST /api/v1/custody/verify. Valid
+// means the hash chain is intact; SignatureValid is set only for signed
+// documents.
+type CustodyVerification struct {
+	Valid          bool   `json:"valid"`
+	Signed         bool   `json:"signed"`
+	SignatureValid *bool  `json:"signature_valid,omitempty"`
+	Error          string `json:"error,omitempty"`
+}
+
+// @Summary Chain-of-custody certificate
+// @Description Builds a tamper-evident chain-of-custody document for an asset over [`from`, `to`): its stays at locations from scan history (consecutive scans at one location collapse into one entry), tags attached and removed, check-outs and check-ins with the SHA-256 of any captured signature, and the sign-offs of the users who requested, decided and executed its disposals and inter-org transfers. Deleted assets, locations and tags are included as recorded.
+// @Description
+// @Description Entries are hash-chained with SHA-256 from a hash of the document header; the last hash is the `digest`. With `sign=true` the digest is signed with the server's Ed25519 key (see GET /api/v1/custody/signing-key); 503 when no key is configured. `format=pdf` renders the same document as a printable certificate showing the digest and the captured signatures; keep the JSON to verify it with POST /api/v1/custody/verify.
+// @Tags reports,internal
+// @ID reports.custody
+// @Param asset_id path  int    true  "asset id"
+// @Param from     query string true  "RFC 3339 start, inclusive" format(date-time)
+// @Param to       query string false "RFC 3339 end, exclusive; default now, at most 366 days after from" format(date-time)
+// @Param format   query string false "json or pdf" Enums(json, pdf) default(json)
+// @Param sign     query bool   false "sign the digest" default(false)
+// @Produce json
+// @Produce application/pdf
+// @Success 200 {object} map[string]any "data: custody.Document"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Failure 503 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/assets/{asset_id}/custody [get]
+func (h *Handler) GetCustody(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return
+	}
+
+	id, err := httputil.ParseSurrogateID("asset_id", chi.URLParam(r, "asset_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	q := r.URL.Query()
+	from, err := time.Parse(time.RFC3339Nano, q.Get("from"))
+	if err != nil {
+		respondInvalidTimestamp(w, r, "from", reqID)
+		return
+	}
+	now := time.Now()
+	to := now
+	if raw := q.Get("to"); raw != "" {
+		if to, err = time.Parse(time.RFC3339Nano, raw); err != nil {
+			respondInvalidTimestamp(w, r, "to", reqID)
+			return
+		}
+	}
+	if !from.Before(to) || to.Sub(from) > report.MaxCustodyRangeDays*24*time.Hour {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field:   "to",
+			Code:    "invalid_value",
+			Message: fmt.Sprintf("to must be after from and at most %d days later", report.MaxCustodyRangeDays),
+		}})
+		return
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "pdf" {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field:   "format",
+			Code:    "invalid_value",
+			Message: "format must be json or pdf",
+		}})
+		return
+	}
+	sign := false
+	if raw := q.Get("sign"); raw != "" {
+		if sign, err = strconv.ParseBool(raw); err != nil {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "sign",
+				Code:    "invalid_value",
+				Message: "sign must be true or false",
+			}})
+			return
+		}
+	}
+	if sign && h.signer == nil {
+		httputil.WriteJSONError(w, r, http.StatusServiceUnavailable, modelerrors.ErrInternal,
+			"custody signing is unavailable (signing key not configured)", reqID)
+		return
+	}
+
+	rec, err := h.storage.GetCustodyRecord(r.Context(), orgID, id, from, to)
+	if errors.Is(err, storage.ErrCustodyTooLarge) {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field:   "from",
+			Code:    "invalid_value",
+			Message: err.Error(),
+		}})
+		return
+	}
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+	if rec == nil {
+		httputil.Respond404(w, r, apierrors.ReportAssetNotFound, reqID)
+		return
+	}
+
+	doc, err := custody.Build(*rec, from, to, now, report.CustodyActor{UserID: claims.UserID, Email: claims.Email})
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+	if sign {
+		h.signer.Sign(doc)
+	}
+
+	if format == "pdf" {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="custody-asset-%d.pdf"`, id))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(custody.RenderPDF(doc))
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": doc})
+}
+
+// @Summary Custody signing key
+// @Description The Ed25519 public key custody documents are signed with, for verifying signatures offline: the signature is over the UTF-8 bytes of the document's `digest` string. 503 when no key is configured.
+// @Tags reports,internal
+// @ID reports.custody.signing-key
+// @Success 200 {object} map[string]any "data: reports.CustodySigningKey"
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 503 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/custody/signing-key [get]
+func (h *Handler) GetCustodySigningKey(w http.ResponseWriter, r *http.Request) {
+	if h.signer == nil {
+		httputil.WriteJSONError(w, r, http.StatusServiceUnavailable, modelerrors.ErrInternal,
+			"custody signing is unavailable (signing key not configured)", middleware.GetRequestID(r.Context()))
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": CustodySigningKey{
+		Alg:       custody.SignatureAlg,
+		KeyID:     h.signer.KeyID(),
+		PublicKey: h.signer.PublicKey(),
+	}})
+}
+
+// @Summary Verify a custody document
+// @Description Checks a chain-of-custody document — the `data` object returned by GET /api/v1/assets/{asset_id}/custody — against its hashes and, when signed, against the server's signing key. A mismatch is reported in the 200 body, naming the first entry that fails, rather than as an error.
+// @Tags reports,internal
+// @ID reports.custody.verify
+// @Accept json
+// @Param request body custody.Document true "custody document"
+// @Success 200 {object} map[string]any "data: reports.CustodyVerification"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 413 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/custody/verify [post]
+func (h *Handler) VerifyCustody(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	var doc custody.Document
+	if err := httputil.DecodeJSONStrict(r, &doc); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+
+	out := CustodyVerification{Valid: true, Signed: doc.Signature != nil}
+	if err := custody.Verify(&doc); err != nil {
+		out.Valid = false
+		out.Error = err.Error()
+	}
+	if out.Signed {
+		ok := h.signer != nil && h.signer.Verify(&doc) == nil
+		out.SignatureValid = &ok
+		if !ok && out.Error == "" {
+			out.Error = custody.ErrBadSignature.Error()
+		}
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": out})
+}
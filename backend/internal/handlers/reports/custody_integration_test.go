@@ -0,0 +1,101 @@
+//go:build integration
+// +build integration
+
+package reports
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/custody"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestGetCustody_ChainsStaysTagsAndSignOffs(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+	orgID := testutil.CreateTestAccount(t, pool)
+	now := time.Now().UTC()
+	start := now.AddDate(0, 0, -30)
+
+	asset := seedAssetForReports(t, pool, orgID, "CUST-A1", start, nil)
+	bay := seedLocationForReports(t, pool, orgID, "CUST-BAY", start, nil)
+	yard := seedLocationForReports(t, pool, orgID, "CUST-YARD", start, nil)
+	_, err := pool.Exec(ctx, `
+		INSERT INTO trakrf.tags (org_id, asset_id, type, value) VALUES ($1, $2, 'rfid', 'E280CUST01')`, orgID, asset)
+	require.NoError(t, err)
+
+	seedScan(t, pool, orgID, asset, bay, now.Add(-5*time.Hour))
+	seedScan(t, pool, orgID, asset, bay, now.Add(-4*time.Hour))
+	seedScan(t, pool, orgID, asset, yard, now.Add(-3*time.Hour))
+	seedScan(t, pool, orgID, asset, bay, now.Add(-2*time.Hour))
+
+	var userID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('Approver', 'approver@x', 'stub') RETURNING id`,
+	).Scan(&userID))
+	_, err = pool.Exec(ctx, `
+		INSERT INTO trakrf.asset_disposals (org_id, asset_id, reason, method, status, requested_by, decided_by, decided_at, created_at)
+		VALUES ($1, $2, 'end of lease', 'returned', 'approved', $3, $3, $4, $5)`,
+		orgID, asset, userID, now.Add(-time.Hour), now.Add(-90*time.Minute))
+	require.NoError(t, err)
+
+	signer, err := custody.NewSigner(bytes.Repeat([]byte{9}, 32))
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	NewHandlerWithSigner(store, signer).RegisterRoutes(r)
+
+	path := "/api/v1/assets/" + strconv.Itoa(asset) + "/custody?sign=true&from=" + url.QueryEscape(now.Add(-24*time.Hour).Format(time.RFC3339))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, withReportsOrg(httptest.NewRequest(http.MethodGet, path, nil), orgID))
+	require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+
+	var resp struct {
+		Data custody.Document `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	doc := &resp.Data
+	require.NoError(t, custody.Verify(doc))
+	require.NoError(t, signer.Verify(doc))
+
+	var kinds []string
+	for _, e := range doc.Entries {
+		kinds = append(kinds, e.Kind)
+	}
+	assert.Equal(t, []string{
+		report.CustodyLocation, report.CustodyLocation, report.CustodyLocation,
+		report.CustodyDisposalRequested, report.CustodyDisposalDecided, report.CustodyTagAssigned,
+	}, kinds, "the bay-yard-bay scans collapse into three stays")
+	assert.Equal(t, 2, doc.Entries[0].Scans)
+	require.NotNil(t, doc.Entries[4].Actor)
+	assert.Equal(t, "approver@x", doc.Entries[4].Actor.Email)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, withReportsOrg(httptest.NewRequest(http.MethodGet, path+"&format=pdf", nil), orgID))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.True(t, bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, withReportsOrg(httptest.NewRequest(http.MethodGet,
+		"/api/v1/assets/"+strconv.Itoa(asset)+"/custody", nil), orgID))
+	assert.Equal(t, http.StatusBadRequest, w.Code, "from is required")
+}
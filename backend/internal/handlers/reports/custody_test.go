@@ -0,0 +1,96 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/custody"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+func custodyTestDoc(t *testing.T, signer *custody.Signer) []byte {
+	t.Helper()
+	key := "BAY-1"
+	at := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	doc, err := custody.Build(report.CustodyRecord{
+		OrgID: 1, OrgName: "Acme",
+		Asset:  report.CustodySubject{AssetID: 7, ExternalKey: "FORK-7", Name: "Forklift"},
+		Events: []report.CustodyEvent{{Kind: report.CustodyLocation, At: at, Scans: 3, LocationExternalKey: &key}},
+	}, at.Add(-time.Hour), at.Add(time.Hour), at.Add(2*time.Hour), report.CustodyActor{UserID: 2, Email: "a@x"})
+	require.NoError(t, err)
+	if signer != nil {
+		signer.Sign(doc)
+	}
+	b, err := json.Marshal(doc)
+	require.NoError(t, err)
+	return b
+}
+
+func postCustodyVerify(t *testing.T, h *Handler, body []byte) CustodyVerification {
+	t.Helper()
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Post(middleware.CustodyVerifyPath, h.VerifyCustody)
+
+	req := httptest.NewRequest(http.MethodPost, middleware.CustodyVerifyPath, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+
+	var resp struct {
+		Data CustodyVerification `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestVerifyCustody(t *testing.T) {
+	signer, err := custody.NewSigner(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	h := NewHandlerWithSigner(nil, signer)
+
+	got := postCustodyVerify(t, h, custodyTestDoc(t, nil))
+	assert.True(t, got.Valid)
+	assert.False(t, got.Signed)
+	assert.Nil(t, got.SignatureValid)
+
+	signed := custodyTestDoc(t, signer)
+	got = postCustodyVerify(t, h, signed)
+	assert.True(t, got.Valid)
+	require.NotNil(t, got.SignatureValid)
+	assert.True(t, *got.SignatureValid)
+
+	tampered := bytes.Replace(signed, []byte(`"scans":3`), []byte(`"scans":4`), 1)
+	got = postCustodyVerify(t, h, tampered)
+	assert.False(t, got.Valid)
+	assert.Contains(t, got.Error, "entry 1")
+
+	got = postCustodyVerify(t, NewHandler(nil), signed)
+	assert.True(t, got.Valid)
+	require.NotNil(t, got.SignatureValid)
+	assert.False(t, *got.SignatureValid, "no key to check against")
+}
+
+func TestGetCustodySigningKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/custody/signing-key", nil)
+	w := httptest.NewRecorder()
+	NewHandler(nil).GetCustodySigningKey(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	signer, err := custody.NewSigner(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	NewHandlerWithSigner(nil, signer).GetCustodySigningKey(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), signer.KeyID())
+}
@@ -0,0 +1,90 @@
+package reports
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const (
+	defaultExpiringWithinDays = 30
+	maxExpiringWithinDays     = 365
+)
+
+// ListExpiringResponse is the typed envelope returned by
+// GET /api/v1/reports/expiring.
+type ListExpiringResponse struct {
+	Data       []report.ExpiringRecord `json:"data"`
+	Limit      int                     `json:"limit"       example:"50"`
+	Offset     int                     `json:"offset"      example:"0"`
+	TotalCount int                     `json:"total_count" example:"100"`
+}
+
+// @Summary List records about to expire
+// @Description Assets, locations and tags whose `valid_to` falls within the next `within_days` days, soonest first. Once `valid_to` passes, a record drops out of default lists and scans resolving to it are rejected, so this is the list to renew or retire ahead of time. Soft-deleted and already-expired records are excluded.
+// @Tags reports,internal
+// @ID reports.expiring
+// @Param within_days query int false "look-ahead window in days" default(30) minimum(1) maximum(365)
+// @Param limit       query int false "max 200" default(50) minimum(1) maximum(200)
+// @Param offset      query int false "min 0"   default(0) minimum(0)
+// @Success 200 {object} reports.ListExpiringResponse
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/reports/expiring [get]
+func (h *Handler) ListExpiring(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	params, err := httputil.ParseListParams(r, httputil.ListAllowlist{
+		Filters: []string{"within_days"},
+	})
+	if err != nil {
+		httputil.RespondListParamError(w, r, err, reqID)
+		return
+	}
+
+	days := defaultExpiringWithinDays
+	if vs, ok := params.Filters["within_days"]; ok && len(vs) > 0 {
+		n, err := strconv.Atoi(vs[0])
+		if err != nil || n < 1 || n > maxExpiringWithinDays {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "within_days",
+				Code:    "invalid_value",
+				Message: "within_days must be an integer from 1 to 365",
+			}})
+			return
+		}
+		days = n
+	}
+
+	items, total, err := h.storage.ListExpiringRecords(r.Context(), orgID, report.ExpiringFilter{
+		Until:  time.Now().AddDate(0, 0, days),
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	})
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, ListExpiringResponse{
+		Data:       items,
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		TotalCount: total,
+	})
+}
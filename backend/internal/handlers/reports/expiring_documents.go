@@ -0,0 +1,105 @@
+package reports
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/document"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ListExpiringDocumentsResponse is the typed envelope returned by
+// GET /api/v1/reports/expiring-documents.
+type ListExpiringDocumentsResponse struct {
+	Counts     report.ExpiringDocumentCounts `json:"counts"`
+	Data       []report.ExpiringDocument     `json:"data"`
+	Limit      int                           `json:"limit"       example:"50"`
+	Offset     int                           `json:"offset"      example:"0"`
+	TotalCount int                           `json:"total_count" example:"100"`
+}
+
+// @Summary List expired and expiring documents
+// @Description The org's vault documents (GET /api/v1/documents), asset and org-level alike, that have expired or expire within the next `within_days` days, soonest first: the list of policies, leases and certificates to renew. `status` is `expired` once `expires_at` has passed and `expiring` before; `days_left` counts whole days to go and is negative once expired. `counts` totals the report by status. Documents of deleted assets are left out.
+// @Tags reports,internal
+// @ID reports.expiring_documents
+// @Param within_days query int    false "look-ahead window in days" default(30) minimum(1) maximum(365)
+// @Param type        query string false "only this document type" Enums(insurance, lease, calibration_cert, warranty, registration, other)
+// @Param limit       query int    false "max 200" default(50) minimum(1) maximum(200)
+// @Param offset      query int    false "min 0"   default(0) minimum(0)
+// @Success 200 {object} reports.ListExpiringDocumentsResponse
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/reports/expiring-documents [get]
+func (h *Handler) ListExpiringDocuments(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	params, err := httputil.ParseListParams(r, httputil.ListAllowlist{
+		Filters: []string{"within_days", "type"},
+	})
+	if err != nil {
+		httputil.RespondListParamError(w, r, err, reqID)
+		return
+	}
+
+	days := defaultExpiringWithinDays
+	if vs, ok := params.Filters["within_days"]; ok && len(vs) > 0 {
+		n, err := strconv.Atoi(vs[0])
+		if err != nil || n < 1 || n > maxExpiringWithinDays {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "within_days",
+				Code:    "invalid_value",
+				Message: "within_days must be an integer from 1 to 365",
+			}})
+			return
+		}
+		days = n
+	}
+
+	now := time.Now()
+	filter := report.ExpiringDocumentFilter{
+		Now:    now,
+		Until:  now.AddDate(0, 0, days),
+		Limit:  params.Limit,
+		Offset: params.Offset,
+	}
+	if vs := params.Filters["type"]; len(vs) > 0 {
+		t := strings.TrimSpace(vs[0])
+		if !document.IsType(t) {
+			httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+				Field:   "type",
+				Code:    "invalid_value",
+				Message: "type must be one of: insurance, lease, calibration_cert, warranty, registration, other",
+			}})
+			return
+		}
+		filter.Type = t
+	}
+
+	items, counts, total, err := h.storage.ListExpiringDocuments(r.Context(), orgID, filter)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, ListExpiringDocumentsResponse{
+		Counts:     counts,
+		Data:       items,
+		Limit:      params.Limit,
+		Offset:     params.Offset,
+		TotalCount: total,
+	})
+}
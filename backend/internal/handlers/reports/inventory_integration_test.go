@@ -0,0 +1,76 @@
+//go:build integration
+// +build integration
+
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestListInventory_ReconstructsPastLocations(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	now := time.Now().UTC()
+	start := now.AddDate(0, 0, -30)
+	at := now.AddDate(0, 0, -10)
+
+	moved := seedAssetForReports(t, pool, orgID, "INV-MOVED", start, nil)
+	gone := seedAssetForReports(t, pool, orgID, "INV-GONE", start, nil)
+	late := seedAssetForReports(t, pool, orgID, "INV-LATE", start, nil)
+	bay := seedLocationForReports(t, pool, orgID, "INV-BAY", start, nil)
+	yard := seedLocationForReports(t, pool, orgID, "INV-YARD", start, nil)
+
+	seedScan(t, pool, orgID, moved, yard, at.Add(-48*time.Hour))
+	seedScan(t, pool, orgID, moved, bay, at.Add(-time.Hour))
+	seedScan(t, pool, orgID, moved, yard, at.Add(time.Hour))
+	seedScan(t, pool, orgID, gone, yard, at.Add(-24*time.Hour))
+	seedScan(t, pool, orgID, late, bay, at.Add(24*time.Hour))
+	// Deleted since: still on the books at the snapshot instant.
+	_, err := pool.Exec(context.Background(),
+		`UPDATE trakrf.assets SET deleted_at = $2 WHERE id = $1`, gone, at.Add(48*time.Hour))
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	NewHandler(store).RegisterRoutes(r)
+
+	req := withReportsOrg(httptest.NewRequest(http.MethodGet,
+		"/api/v1/reports/inventory?at="+url.QueryEscape(at.Format(time.RFC3339)), nil), orgID)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+
+	var resp ListInventoryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 2, resp.TotalCount, "assets first scanned after at are omitted")
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, "INV-MOVED", resp.Data[0].AssetExternalKey)
+	require.NotNil(t, resp.Data[0].LocationExternalKey)
+	assert.Equal(t, "INV-BAY", *resp.Data[0].LocationExternalKey, "location at the instant, not now")
+	assert.Equal(t, "INV-GONE", resp.Data[1].AssetExternalKey)
+
+	for _, bad := range []string{"", "yesterday", now.Add(time.Hour).Format(time.RFC3339)} {
+		req = withReportsOrg(httptest.NewRequest(http.MethodGet,
+			"/api/v1/reports/inventory?at="+url.QueryEscape(bad), nil), orgID)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, "at=%q", bad)
+	}
+}
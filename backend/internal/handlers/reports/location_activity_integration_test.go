@@ -0,0 +1,179 @@
+//go:build integration
+// +build integration
+
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestGetLocationActivity_BucketsInLocalTime(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	now := time.Now()
+	start := now.AddDate(0, 0, -90)
+
+	chicago, err := time.LoadLocation("America/Chicago")
+	require.NoError(t, err)
+	local := now.In(chicago)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, chicago)
+
+	site := seedLocationForReports(t, pool, orgID, "TZ-SITE", start, nil)
+	dock := seedLocationForReports(t, pool, orgID, "TZ-DOCK", start, nil)
+	_, err = pool.Exec(context.Background(),
+		`UPDATE trakrf.locations SET parent_location_id = $1 WHERE id = $2`, site, dock)
+	require.NoError(t, err)
+
+	tz := "America/Chicago"
+	_, err = store.SetLocationSchedule(context.Background(), orgID, site, location.ScheduleInput{TimeZone: &tz})
+	require.NoError(t, err)
+	sched, err := store.SetLocationSchedule(context.Background(), orgID, dock, location.ScheduleInput{
+		Shifts: []location.Shift{{Name: "Night", Start: "22:00", End: "06:00"}},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, sched.TimeZone)
+	assert.Equal(t, tz, sched.EffectiveTimeZone, "the dock inherits the site's zone")
+	require.Len(t, sched.Shifts, 1)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, sched.Shifts[0].Weekdays)
+
+	// 23:30 last night in Chicago is already today in UTC.
+	forklift := seedAssetForReports(t, pool, orgID, "TZ-FORKLIFT", start, nil)
+	seedScan(t, pool, orgID, forklift, dock, midnight.Add(-30*time.Minute))
+	testutil.RefreshAssetScanLatest(t, pool)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	NewHandler(store).RegisterRoutes(r)
+
+	req := withReportsOrg(httptest.NewRequest(http.MethodGet,
+		"/api/v1/reports/locations/"+strconv.Itoa(dock)+"/activity?days=2", nil), orgID)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+
+	var resp struct {
+		Data report.LocationActivityReport `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	got := resp.Data
+	assert.Equal(t, tz, got.TimeZone)
+	assert.True(t, got.From.Equal(midnight.AddDate(0, 0, -1)), "window starts at local midnight")
+	assert.Equal(t, 1, got.AssetsSeen)
+
+	yesterday := midnight.AddDate(0, 0, -1).Format("2006-01-02")
+	require.Len(t, got.Daily, 2)
+	assert.Equal(t, yesterday, got.Daily[0].Date)
+	assert.Equal(t, 1, got.Daily[0].AssetsSeen, "the sighting lands on the local date")
+	assert.Equal(t, 0, got.Daily[1].AssetsSeen)
+
+	require.NotEmpty(t, got.Shifts)
+	assert.Equal(t, yesterday, got.Shifts[0].Date)
+	assert.Equal(t, "Night", got.Shifts[0].Shift)
+	assert.Equal(t, 1, got.Shifts[0].AssetsSeen)
+	assert.Equal(t, 1.0, got.Shifts[0].Utilization)
+	assert.True(t, got.Shifts[0].Start.Equal(midnight.Add(-2*time.Hour)))
+
+	req = withReportsOrg(httptest.NewRequest(http.MethodGet,
+		"/api/v1/reports/locations/"+strconv.Itoa(dock)+"/activity?days=93", nil), orgID)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	req = withReportsOrg(httptest.NewRequest(http.MethodGet, "/api/v1/reports/locations/999999999/activity", nil), orgID)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestGetShiftPresence_ListsAssetsAndOptionallyPersonnel(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	now := time.Now()
+	start := now.AddDate(0, 0, -90)
+	midnight := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
+
+	dock := seedLocationForReports(t, pool, orgID, "SP-DOCK", start, nil)
+	_, err := store.SetLocationSchedule(context.Background(), orgID, dock, location.ScheduleInput{
+		Shifts: []location.Shift{
+			{Name: "Night", Start: "22:00", End: "06:00"},
+			{Name: "Day", Start: "06:00", End: "14:00"},
+		},
+	})
+	require.NoError(t, err)
+
+	forklift := seedAssetForReports(t, pool, orgID, "SP-FORKLIFT", start, nil)
+	badge := seedAssetForReports(t, pool, orgID, "SP-BADGE", start, nil)
+	_, err = pool.Exec(context.Background(),
+		`UPDATE trakrf.assets SET metadata = '{"person":true}' WHERE id = $1`, badge)
+	require.NoError(t, err)
+	seedScan(t, pool, orgID, forklift, dock, midnight.Add(-90*time.Minute))
+	seedScan(t, pool, orgID, forklift, dock, midnight.Add(-30*time.Minute))
+	seedScan(t, pool, orgID, badge, dock, midnight.Add(-60*time.Minute))
+	testutil.RefreshAssetScanLatest(t, pool)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	NewHandler(store).RegisterRoutes(r)
+	get := func(query string) report.ShiftPresenceReport {
+		t.Helper()
+		req := withReportsOrg(httptest.NewRequest(http.MethodGet,
+			"/api/v1/reports/locations/"+strconv.Itoa(dock)+"/shift-presence?"+query, nil), orgID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+		var resp struct {
+			Data report.ShiftPresenceReport `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	got := get("days=2&shift=Night")
+	require.NotEmpty(t, got.Shifts)
+	night := got.Shifts[0]
+	assert.Equal(t, "Night", night.Shift)
+	assert.Equal(t, 1, night.AssetCount)
+	assert.Equal(t, 0, night.PersonnelCount)
+	require.Len(t, night.Present, 1)
+	assert.Equal(t, forklift, night.Present[0].AssetID)
+	assert.Equal(t, 2, night.Present[0].MinutesSeen)
+	for _, occ := range got.Shifts {
+		assert.Equal(t, "Night", occ.Shift)
+	}
+
+	got = get("days=2&shift=Night&include_personnel=true")
+	assert.True(t, got.IncludesPersonnel)
+	night = got.Shifts[0]
+	assert.Equal(t, 1, night.PersonnelCount)
+	require.Len(t, night.Present, 2)
+	assert.Equal(t, forklift, night.Present[0].AssetID, "equipment first")
+	assert.True(t, night.Present[1].Person)
+
+	req := withReportsOrg(httptest.NewRequest(http.MethodGet,
+		"/api/v1/reports/locations/"+strconv.Itoa(dock)+"/shift-presence?include_personnel=maybe", nil), orgID)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
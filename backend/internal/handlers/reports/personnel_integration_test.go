@@ -0,0 +1,108 @@
+//go:build integration
+// +build integration
+
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestListCurrentLocations_AnonymizesPersonnel(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	now := time.Now().UTC()
+	dayAgo := now.Add(-24 * time.Hour)
+
+	dock := seedLocationForReports(t, pool, orgID, "ANON-DOCK", dayAgo, nil)
+	forklift := seedAssetForReports(t, pool, orgID, "ANON-FORKLIFT", dayAgo, nil)
+	badge := seedAssetForReports(t, pool, orgID, "EMP-0042", dayAgo, nil)
+	_, err := pool.Exec(context.Background(),
+		`UPDATE trakrf.assets SET metadata = '{"person":true}' WHERE id = $1`, badge)
+	require.NoError(t, err)
+	seedScan(t, pool, orgID, forklift, dock, now.Add(-5*time.Minute))
+	seedScan(t, pool, orgID, badge, dock, now.Add(-2*time.Minute))
+	testutil.RefreshAssetScanLatest(t, pool)
+
+	router := setupTemporalReportsRouter(NewHandler(store))
+	list := func(query string) currLocResp {
+		t.Helper()
+		req := withReportsOrg(httptest.NewRequest(http.MethodGet, "/api/v1/reports/asset-locations?"+query, nil), orgID)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+		var resp currLocResp
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := list("")
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, "EMP-0042", resp.Data[0].AssetExternalKey, "named until the org anonymizes")
+
+	require.NoError(t, store.UpdatePersonnelSettings(context.Background(), orgID,
+		organization.PersonnelSettings{AnonymizeReports: true}))
+
+	resp = list("")
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, badge, resp.Data[0].AssetID)
+	assert.Equal(t, "person-"+strconv.Itoa(badge), resp.Data[0].AssetExternalKey)
+	assert.Equal(t, "ANON-FORKLIFT", resp.Data[1].AssetExternalKey, "equipment keeps its key")
+
+	resp = list("q=EMP-0042")
+	assert.Empty(t, resp.Data, "a masked person is not found by what the mask hides")
+	assert.Equal(t, 0, resp.TotalCount)
+	resp = list("asset_external_key=EMP-0042")
+	assert.Empty(t, resp.Data)
+	resp = list("asset_id=" + strconv.Itoa(badge))
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "person-"+strconv.Itoa(badge), resp.Data[0].AssetExternalKey)
+}
+
+func TestPrunePersonnelScans_OnlyPersons(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	now := time.Now().UTC()
+	start := now.AddDate(0, 0, -90)
+
+	dock := seedLocationForReports(t, pool, orgID, "PRUNE-DOCK", start, nil)
+	forklift := seedAssetForReports(t, pool, orgID, "PRUNE-FORKLIFT", start, nil)
+	badge := seedAssetForReports(t, pool, orgID, "PRUNE-BADGE", start, nil)
+	_, err := pool.Exec(context.Background(),
+		`UPDATE trakrf.assets SET metadata = '{"person":true}' WHERE id = $1`, badge)
+	require.NoError(t, err)
+	seedScan(t, pool, orgID, forklift, dock, now.AddDate(0, 0, -30))
+	seedScan(t, pool, orgID, badge, dock, now.AddDate(0, 0, -30))
+	seedScan(t, pool, orgID, badge, dock, now.AddDate(0, 0, -1))
+
+	n, err := store.PrunePersonnelScans(context.Background(), orgID, now.AddDate(0, 0, -14))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), n, "only the person's old scan goes")
+
+	var left int
+	require.NoError(t, pool.QueryRow(context.Background(),
+		`SELECT count(*) FROM trakrf.asset_scans WHERE org_id = $1`, orgID).Scan(&left))
+	assert.Equal(t, 2, left)
+
+	_, err = pool.Exec(context.Background(),
+		`UPDATE trakrf.assets SET metadata = '{"person":"yes"}' WHERE id = $1`, forklift)
+	assert.Error(t, err, "metadata.person must be a boolean")
+}
@@ -0,0 +1,21 @@
+package reports
+
+import (
+	"net/http"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/location"
+)
+
+// subtreeScope resolves the caller's location access policy scope. API-key
+// callers are org-wide integrations and are never restricted.
+func (h *Handler) subtreeScope(r *http.Request, orgID int) (location.SubtreeScope, error) {
+	if middleware.GetAPIKeyPrincipal(r) != nil {
+		return location.SubtreeScope{}, nil
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		return location.SubtreeScope{}, nil
+	}
+	return h.storage.LocationSubtreeScopeForUser(r.Context(), orgID, claims.UserID)
+}
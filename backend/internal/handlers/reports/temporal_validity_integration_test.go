@@ -52,31 +52,19 @@ func withReportsOrg(req *http.Request, orgID int) *http.Request {
 
 func seedAssetForReports(t *testing.T, pool *pgxpool.Pool, orgID int, externalKey string, validFrom time.Time, validTo *time.Time) int {
 	t.Helper()
-	var id int
-	require.NoError(t, pool.QueryRow(context.Background(), `
-		INSERT INTO trakrf.assets (org_id, external_key, name, description, valid_from, valid_to, is_active)
-		VALUES ($1, $2, $2, '', $3, $4, true) RETURNING id
-	`, orgID, externalKey, validFrom, validTo).Scan(&id))
-	return id
+	f := testutil.NewAssetFactory(orgID).WithIdentifier(externalKey).WithName(externalKey).WithDescription("").WithValidTo(validTo)
+	f.ValidFrom = validFrom
+	return f.Create(t, pool)
 }
 
 func seedLocationForReports(t *testing.T, pool *pgxpool.Pool, orgID int, externalKey string, validFrom time.Time, validTo *time.Time) int {
 	t.Helper()
-	var id int
-	require.NoError(t, pool.QueryRow(context.Background(), `
-		INSERT INTO trakrf.locations (org_id, external_key, name, description, valid_from, valid_to, is_active)
-		VALUES ($1, $2, $2, '', $3, $4, true) RETURNING id
-	`, orgID, externalKey, validFrom, validTo).Scan(&id))
-	return id
+	return testutil.NewLocationFactory(orgID).WithExternalKey(externalKey).WithValidity(validFrom, validTo).Create(t, pool)
 }
 
 func seedScan(t *testing.T, pool *pgxpool.Pool, orgID, assetID, locationID int, ts time.Time) {
 	t.Helper()
-	_, err := pool.Exec(context.Background(), `
-		INSERT INTO trakrf.asset_scans (org_id, asset_id, location_id, timestamp)
-		VALUES ($1, $2, $3, $4)
-	`, orgID, assetID, locationID, ts)
-	require.NoError(t, err)
+	testutil.NewScanFactory(orgID).At(ts).Create(t, pool, assetID, locationID)
 }
 
 func TestListCurrentLocations_TemporalValidity_FiltersAssetsAndLocations(t *testing.T) {
@@ -215,3 +203,52 @@ func TestListCurrentLocations_LocationExternalKey_SlashRejected400(t *testing.T)
 	assert.Equal(t, "location_external_key", resp.Error.Fields[0].Field)
 	assert.Equal(t, "invalid_value", resp.Error.Fields[0].Code)
 }
+
+func TestListExpiring_ReturnsRecordsClosingWithinWindow(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	now := time.Now().UTC()
+	yesterday := now.Add(-24 * time.Hour)
+	inWeek := now.Add(7 * 24 * time.Hour)
+	inTwoMonths := now.Add(60 * 24 * time.Hour)
+
+	soonAsset := seedAssetForReports(t, pool, orgID, "EXP-A-SOON", yesterday, &inWeek)
+	seedAssetForReports(t, pool, orgID, "EXP-A-LATER", yesterday, &inTwoMonths)
+	seedAssetForReports(t, pool, orgID, "EXP-A-OPEN", yesterday, nil)
+	seedAssetForReports(t, pool, orgID, "EXP-A-GONE", yesterday.Add(-time.Hour), &yesterday)
+	soonLoc := seedLocationForReports(t, pool, orgID, "EXP-L-SOON", yesterday, &inWeek)
+
+	handler := NewHandler(store)
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	handler.RegisterRoutes(r)
+
+	get := func(query string) *httptest.ResponseRecorder {
+		req := withReportsOrg(httptest.NewRequest(http.MethodGet, "/api/v1/reports/expiring"+query, nil), orgID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("")
+	require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+	var resp ListExpiringResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, 2, resp.TotalCount)
+	got := map[string]int{}
+	for _, it := range resp.Data {
+		got[it.EntityType] = it.EntityID
+	}
+	assert.Equal(t, map[string]int{"asset": soonAsset, "location": soonLoc}, got)
+
+	w = get("?within_days=90")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 3, resp.TotalCount)
+
+	assert.Equal(t, http.StatusBadRequest, get("?within_days=0").Code)
+	assert.Equal(t, http.StatusBadRequest, get("?within_days=soon").Code)
+}
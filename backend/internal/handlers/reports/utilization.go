@@ -0,0 +1,87 @@
+package reports
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const (
+	defaultUtilizationDays = 30
+	maxUtilizationDays     = 365
+	defaultIdleLimit       = 50
+	maxIdleLimit           = 200
+)
+
+// @Summary Asset utilization
+// @Description Classifies each live asset as active or idle by how many distinct days a reader saw it in the last `days` days — calendar days in the time zone of the location it was seen at (see GET /api/v1/locations/{location_id}/schedule): active at `min_seen_days` or more, idle below. Totals are broken down by asset type (`metadata.asset_type`, null when unset) and by the location each asset was last seen at, least utilized first. `idle_assets` lists up to `limit` idle assets, never-seen and longest-unseen first — the equipment to consider returning or no longer renting. `moves` counts location changes between sightings within the window.
+// @Tags reports,internal
+// @ID reports.utilization
+// @Param days          query int false "window in days"                           default(30) minimum(1) maximum(365)
+// @Param min_seen_days query int false "distinct days seen to count as active"     default(1)  minimum(1)
+// @Param limit         query int false "max idle assets listed, max 200"           default(50) minimum(1) maximum(200)
+// @Success 200 {object} map[string]any "data: report.UtilizationReport"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/reports/utilization [get]
+func (h *Handler) GetUtilization(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	q := r.URL.Query()
+	days, ok := utilizationParam(w, r, reqID, q.Get("days"), "days", defaultUtilizationDays, maxUtilizationDays)
+	if !ok {
+		return
+	}
+	minSeen, ok := utilizationParam(w, r, reqID, q.Get("min_seen_days"), "min_seen_days", 1, days)
+	if !ok {
+		return
+	}
+	limit, ok := utilizationParam(w, r, reqID, q.Get("limit"), "limit", defaultIdleLimit, maxIdleLimit)
+	if !ok {
+		return
+	}
+
+	result, err := h.storage.GetUtilizationReport(r.Context(), orgID, report.UtilizationFilter{
+		Since:       time.Now().AddDate(0, 0, -days),
+		MinSeenDays: minSeen,
+		IdleLimit:   limit,
+	})
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			err.Error(), reqID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": result})
+}
+
+// utilizationParam parses an optional integer query parameter in [1, max],
+// writing a 400 and returning false when it is out of range.
+func utilizationParam(w http.ResponseWriter, r *http.Request, reqID, raw, field string, def, max int) (int, bool) {
+	if raw == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 || n > max {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field:   field,
+			Code:    "invalid_value",
+			Message: field + " must be an integer from 1 to " + strconv.Itoa(max),
+		}})
+		return 0, false
+	}
+	return n, true
+}
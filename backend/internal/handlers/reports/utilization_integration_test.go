@@ -0,0 +1,83 @@
+//go:build integration
+// +build integration
+
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestGetUtilization_ClassifiesActiveAndIdle(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	orgID := testutil.CreateTestAccount(t, pool)
+	now := time.Now().UTC()
+	start := now.AddDate(0, 0, -90)
+
+	busy := seedAssetForReports(t, pool, orgID, "UTIL-BUSY", start, nil)
+	stale := seedAssetForReports(t, pool, orgID, "UTIL-STALE", start, nil)
+	never := seedAssetForReports(t, pool, orgID, "UTIL-NEVER", start, nil)
+	_, err := pool.Exec(context.Background(),
+		`UPDATE trakrf.assets SET metadata = '{"asset_type":"forklift"}' WHERE id = ANY($1)`, []int{busy, stale})
+	require.NoError(t, err)
+	bay := seedLocationForReports(t, pool, orgID, "UTIL-BAY", start, nil)
+	yard := seedLocationForReports(t, pool, orgID, "UTIL-YARD", start, nil)
+
+	seedScan(t, pool, orgID, busy, bay, now.AddDate(0, 0, -3))
+	seedScan(t, pool, orgID, busy, yard, now.AddDate(0, 0, -2))
+	seedScan(t, pool, orgID, busy, bay, now.Add(-time.Hour))
+	seedScan(t, pool, orgID, stale, yard, now.AddDate(0, 0, -60))
+	testutil.RefreshAssetScanLatest(t, pool)
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	NewHandler(store).RegisterRoutes(r)
+
+	req := withReportsOrg(httptest.NewRequest(http.MethodGet, "/api/v1/reports/utilization?days=30&min_seen_days=2", nil), orgID)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, "body: %s", w.Body.String())
+
+	var resp struct {
+		Data report.UtilizationReport `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	got := resp.Data
+	assert.Equal(t, 3, got.Totals.Assets)
+	assert.Equal(t, 1, got.Totals.Active)
+	assert.Equal(t, 2, got.Totals.Moves)
+
+	require.Len(t, got.IdleAssets, 2)
+	assert.Equal(t, never, got.IdleAssets[0].AssetID, "never-seen assets lead")
+	assert.Equal(t, stale, got.IdleAssets[1].AssetID)
+	require.NotNil(t, got.IdleAssets[1].LocationExternalKey)
+	assert.Equal(t, "UTIL-YARD", *got.IdleAssets[1].LocationExternalKey, "idle assets keep their last location")
+
+	require.Len(t, got.ByType, 2)
+	for _, g := range got.ByType {
+		if g.Type != nil && *g.Type == "forklift" {
+			assert.Equal(t, 0.5, g.Utilization)
+		}
+	}
+
+	req = withReportsOrg(httptest.NewRequest(http.MethodGet, "/api/v1/reports/utilization?days=400", nil), orgID)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
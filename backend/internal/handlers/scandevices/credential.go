@@ -0,0 +1,105 @@
+package scandevices
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/scandevice"
+	"github.com/trakrf/platform/backend/internal/util/apisecret"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// RegisterCredentialRoutes wires reader credential issue/revoke onto r,
+// org-admin only. Mount inside the session-auth (middleware.Auth) group.
+func (h *Handler) RegisterCredentialRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(admin).Post("/api/v1/scan-devices/{scan_device_id}/credential", h.IssueCredential)
+	r.With(admin).Delete("/api/v1/scan-devices/{scan_device_id}/credential", h.RevokeCredential)
+}
+
+// @Summary  Issue a reader credential
+// @Description Issues the device a new reader credential, replacing any previous one. The credential is returned only in this response; scans made over a user session must present it in the X-Reader-Key header unless the org has turned on session scans.
+// @Tags     scandevices,internal
+// @ID       scandevices.credential.issue
+// @Produce  json
+// @Param    scan_device_id path int true "Scan device id"
+// @Success  201 {object} map[string]any "data: scandevice.CredentialResponse"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/scan-devices/{scan_device_id}/credential [post]
+func (h *Handler) IssueCredential(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, err := httputil.ParseSurrogateID("scan_device_id", chi.URLParam(r, "scan_device_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+
+	secret, err := apisecret.Generate()
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	issuedAt, err := h.storage.IssueScanDeviceCredential(r.Context(), orgID, id, apisecret.Hash(secret))
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if issuedAt == nil {
+		httputil.Respond404(w, r, "scan device not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": scandevice.CredentialResponse{
+		ScanDeviceID: id, Credential: secret, IssuedAt: *issuedAt,
+	}})
+}
+
+// @Summary  Revoke a reader credential
+// @Description Scans presenting the device's credential get 403 from the next request.
+// @Tags     scandevices,internal
+// @ID       scandevices.credential.revoke
+// @Param    scan_device_id path int true "Scan device id"
+// @Success  204
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/scan-devices/{scan_device_id}/credential [delete]
+func (h *Handler) RevokeCredential(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, err := httputil.ParseSurrogateID("scan_device_id", chi.URLParam(r, "scan_device_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	ok, err := h.storage.RevokeScanDeviceCredential(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !ok {
+		httputil.Respond404(w, r, "scan device not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
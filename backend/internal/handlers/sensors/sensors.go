@@ -0,0 +1,518 @@
+// Package sensors serves condition-sensor (cold-chain) data: the public
+// reading ingest endpoint loggers post to, reading history and alert reads,
+// and the internal org-scoped threshold management that drives alerts.
+package sensors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/sensor"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+	// maxClockSkew is how far ahead of server time a logger's recorded_at may
+	// be before the reading is rejected as a bad clock.
+	maxClockSkew = 5 * time.Minute
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	return v
+}()
+
+// SensorStorage is the storage surface the handler needs (mockable).
+type SensorStorage interface {
+	GetAssetIDsByExternalKeys(ctx context.Context, orgID int, externalKeys []string) (map[string]int, error)
+	IngestSensorReadings(ctx context.Context, orgID int, readings []sensor.NewReading) (*sensor.IngestResult, error)
+	ListSensorReadings(ctx context.Context, orgID, assetID int, f sensor.ReadingFilter) ([]sensor.Reading, bool, error)
+	ListSensorAlerts(ctx context.Context, orgID int, f sensor.AlertFilter) ([]sensor.Alert, error)
+	ListSensorThresholds(ctx context.Context, orgID int) ([]sensor.Threshold, error)
+	CreateSensorThreshold(ctx context.Context, orgID int, req sensor.ThresholdRequest) (*sensor.Threshold, error)
+	UpdateSensorThreshold(ctx context.Context, orgID, id int, req sensor.ThresholdRequest) (*sensor.Threshold, error)
+	DeleteSensorThreshold(ctx context.Context, orgID, id int) (bool, error)
+}
+
+type Handler struct {
+	storage SensorStorage
+	now     func() time.Time
+}
+
+func NewHandler(storage SensorStorage) *Handler {
+	return &Handler{storage: storage, now: time.Now}
+}
+
+// IngestResponse is the envelope returned by POST /api/v1/sensor-readings.
+type IngestResponse struct {
+	Data sensor.IngestResult `json:"data"`
+}
+
+// RegisterRoutes wires the internal threshold routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Any member can read thresholds;
+// changing them is admin-only since it opens and resolves alerts org-wide.
+// The public ingest and read routes are mounted with the API-key surfaces.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(member).Get("/api/v1/sensor-thresholds", h.ListThresholds)
+	r.With(admin).Post("/api/v1/sensor-thresholds", h.CreateThreshold)
+	r.With(admin).Put("/api/v1/sensor-thresholds/{threshold_id}", h.UpdateThreshold)
+	r.With(admin).Delete("/api/v1/sensor-thresholds/{threshold_id}", h.DeleteThreshold)
+}
+
+// @Summary      Ingest sensor readings
+// @Description  **Required scope:** `sensors:write`
+// @Description
+// @Description  Records condition readings (temperature, humidity, ...) from loggers attached to assets. Each reading names its asset by external key. `metric` is lowercase snake case. Readings already stored for the same asset, metric and `recorded_at` are skipped, so re-uploading a logger's buffer is safe. A reading outside the threshold for its asset's type (`metadata.asset_type`) opens a sensor alert; the next in-range reading resolves it. Both changes are delivered to webhooks as `sensor_alert.opened` and `sensor_alert.resolved`. The request is all-or-nothing: an unknown asset rejects the whole batch with 400. At most 1000 readings per request.
+// @Tags         sensors,public
+// @ID           sensors.readings.ingest
+// @Accept       json
+// @Produce      json
+// @Param        request body sensor.IngestRequest true "Readings"
+// @Success      201  {object}  sensors.IngestResponse
+// @Failure      400  {object}  modelerrors.ErrorResponse "validation_error"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      415  {object}  modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure      429  {object}  modelerrors.ErrorResponse "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[sensors:write]
+// @Router       /api/v1/sensor-readings [post]
+func (h *Handler) Ingest(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+
+	var req sensor.IngestRequest
+	if err := httputil.DecodeJSON(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	var problems []modelerrors.FieldError
+	latest := h.now().Add(maxClockSkew)
+	keys := make([]string, 0, len(req.Readings))
+	seen := map[string]bool{}
+	for i, rd := range req.Readings {
+		if !sensor.ValidMetric(rd.Metric) {
+			problems = append(problems, modelerrors.FieldError{
+				Field: fmt.Sprintf("readings[%d].metric", i), Code: "invalid_value",
+				Message: "metric must be lowercase snake case (e.g. temperature, relative_humidity)",
+			})
+		}
+		if rd.RecordedAt.After(latest) {
+			problems = append(problems, modelerrors.FieldError{
+				Field: fmt.Sprintf("readings[%d].recorded_at", i), Code: "invalid_value",
+				Message: "recorded_at must not be in the future",
+			})
+		}
+		if !seen[rd.AssetIdentifier] {
+			seen[rd.AssetIdentifier] = true
+			keys = append(keys, rd.AssetIdentifier)
+		}
+	}
+	if len(problems) > 0 {
+		httputil.WriteValidationError(w, r, reqID, problems)
+		return
+	}
+
+	resolved, err := h.storage.GetAssetIDsByExternalKeys(r.Context(), orgID, keys)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	readings := make([]sensor.NewReading, 0, len(req.Readings))
+	for i, rd := range req.Readings {
+		assetID, ok := resolved[rd.AssetIdentifier]
+		if !ok {
+			problems = append(problems, modelerrors.FieldError{
+				Field: fmt.Sprintf("readings[%d].asset_identifier", i), Code: "invalid_value",
+				Message: fmt.Sprintf("asset_identifier %q not found", rd.AssetIdentifier),
+			})
+			continue
+		}
+		readings = append(readings, sensor.NewReading{
+			AssetID:    assetID,
+			Metric:     rd.Metric,
+			Value:      *rd.Value,
+			Unit:       rd.Unit,
+			DeviceID:   rd.DeviceID,
+			RecordedAt: rd.RecordedAt.UTC(),
+		})
+	}
+	if len(problems) > 0 {
+		httputil.WriteValidationError(w, r, reqID, problems)
+		return
+	}
+
+	res, err := h.storage.IngestSensorReadings(r.Context(), orgID, readings)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, IngestResponse{Data: *res})
+}
+
+// parseLimit reads the limit query param, answering 400 itself.
+func parseLimit(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return defaultLimit, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > maxLimit {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "limit", Code: "invalid_value",
+			Message: fmt.Sprintf("limit must be an integer between 1 and %d", maxLimit),
+		}})
+		return 0, false
+	}
+	return n, true
+}
+
+// @Summary      List an asset's sensor readings
+// @Description  **Required scope:** `sensors:read`
+// @Description
+// @Description  Newest first. `from` (inclusive) and `to` (exclusive) bound recorded_at.
+// @Tags         sensors,public
+// @ID           sensors.readings.list
+// @Produce      json
+// @Param        asset_id path int true "Asset id" minimum(1) format(int64)
+// @Param        metric query string false "Only this metric" example(temperature)
+// @Param        from query string false "RFC 3339 lower bound on recorded_at"
+// @Param        to query string false "RFC 3339 upper bound on recorded_at"
+// @Param        limit query int false "Max readings (1-1000, default 100)"
+// @Success      200  {object}  map[string]any "data: []sensor.Reading"
+// @Failure      400  {object}  modelerrors.ErrorResponse "validation_error"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      404  {object}  modelerrors.ErrorResponse "not_found"
+// @Failure      429  {object}  modelerrors.ErrorResponse "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[sensors:read]
+// @Router       /api/v1/assets/{asset_id}/sensor-readings [get]
+func (h *Handler) ListReadings(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	assetID, err := httputil.ParseSurrogateID("asset_id", chi.URLParam(r, "asset_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	limit, ok := parseLimit(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	f := sensor.ReadingFilter{Limit: limit}
+	q := r.URL.Query()
+	if v := q.Get("metric"); v != "" {
+		f.Metric = &v
+	}
+	for _, p := range []struct {
+		name string
+		into **time.Time
+	}{{"from", &f.From}, {"to", &f.To}} {
+		if v := q.Get(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339Nano, v)
+			if err != nil {
+				httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+					Field: p.name, Code: "invalid_value",
+					Message: fmt.Sprintf("%s must be an RFC 3339 timestamp", p.name),
+				}})
+				return
+			}
+			*p.into = &t
+		}
+	}
+
+	readings, found, err := h.storage.ListSensorReadings(r.Context(), orgID, assetID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "asset not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": readings})
+}
+
+// @Summary      List sensor alerts
+// @Description  **Required scope:** `sensors:read`
+// @Description
+// @Description  Most recently opened first. `status=open` lists excursions still in progress; `status=resolved` only closed ones.
+// @Tags         sensors,public
+// @ID           sensors.alerts.list
+// @Produce      json
+// @Param        asset_id query int false "Only this asset" minimum(1) format(int64)
+// @Param        status query string false "open or resolved" Enums(open, resolved)
+// @Param        limit query int false "Max alerts (1-1000, default 100)"
+// @Success      200  {object}  map[string]any "data: []sensor.Alert"
+// @Failure      400  {object}  modelerrors.ErrorResponse "validation_error"
+// @Failure      401  {object}  modelerrors.ErrorResponse "unauthorized"
+// @Failure      403  {object}  modelerrors.ErrorResponse "forbidden"
+// @Failure      429  {object}  modelerrors.ErrorResponse "rate_limited"
+// @Failure      500  {object}  modelerrors.ErrorResponse "internal_error"
+// @Security     BearerAuth[sensors:read]
+// @Router       /api/v1/sensor-alerts [get]
+func (h *Handler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	limit, ok := parseLimit(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	f := sensor.AlertFilter{Limit: limit}
+	q := r.URL.Query()
+	if v := q.Get("asset_id"); v != "" {
+		id, err := httputil.ParseSurrogateID("asset_id", v)
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		f.AssetID = &id
+	}
+	switch q.Get("status") {
+	case "":
+	case "open":
+		open := true
+		f.Open = &open
+	case "resolved":
+		open := false
+		f.Open = &open
+	default:
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "status", Code: "invalid_value", Message: "status must be one of: open, resolved",
+		}})
+		return
+	}
+
+	alerts, err := h.storage.ListSensorAlerts(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": alerts})
+}
+
+// validateThreshold checks what the struct tags cannot.
+func validateThreshold(req sensor.ThresholdRequest) string {
+	switch {
+	case !sensor.ValidMetric(req.Metric):
+		return "metric must be lowercase snake case (e.g. temperature, relative_humidity)"
+	case req.MinValue == nil && req.MaxValue == nil:
+		return "at least one of min_value and max_value is required"
+	case req.MinValue != nil && req.MaxValue != nil && *req.MinValue > *req.MaxValue:
+		return "min_value must not be greater than max_value"
+	}
+	return ""
+}
+
+// decodeThreshold decodes and validates a threshold body, answering 400
+// itself.
+func decodeThreshold(w http.ResponseWriter, r *http.Request, reqID string) (sensor.ThresholdRequest, bool) {
+	var req sensor.ThresholdRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return req, false
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return req, false
+	}
+	if msg := validateThreshold(req); msg != "" {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation, msg, reqID)
+		return req, false
+	}
+	return req, true
+}
+
+// parseThresholdID reads the threshold_id path param, answering 400 itself.
+func parseThresholdID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("threshold_id", chi.URLParam(r, "threshold_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// @Summary  List sensor thresholds
+// @Tags     sensors,internal
+// @ID       sensors.thresholds.list
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []sensor.Threshold"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/sensor-thresholds [get]
+func (h *Handler) ListThresholds(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	list, err := h.storage.ListSensorThresholds(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Create a sensor threshold
+// @Description Sets the allowed range of one metric for every asset whose metadata.asset_type matches. Either bound may be omitted (e.g. frozen goods set only max_value). A value equal to a bound is in range.
+// @Tags     sensors,internal
+// @ID       sensors.thresholds.create
+// @Accept   json
+// @Produce  json
+// @Param    request body sensor.ThresholdRequest true "Threshold"
+// @Success  201 {object} map[string]any "data: sensor.Threshold"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "A threshold for this asset_type and metric already exists"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/sensor-thresholds [post]
+func (h *Handler) CreateThreshold(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	req, ok := decodeThreshold(w, r, reqID)
+	if !ok {
+		return
+	}
+	created, err := h.storage.CreateSensorThreshold(r.Context(), orgID, req)
+	if err != nil {
+		h.respondThresholdError(w, r, err, reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/sensor-thresholds/"+strconv.Itoa(created.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": created})
+}
+
+// @Summary  Replace a sensor threshold
+// @Description Alerts open against the threshold are resolved; the next reading reopens one if it is still out of range under the new limits.
+// @Tags     sensors,internal
+// @ID       sensors.thresholds.update
+// @Accept   json
+// @Produce  json
+// @Param    threshold_id path int true "Threshold id" minimum(1) format(int64)
+// @Param    request body sensor.ThresholdRequest true "Threshold"
+// @Success  200 {object} map[string]any "data: sensor.Threshold"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "A threshold for this asset_type and metric already exists"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/sensor-thresholds/{threshold_id} [put]
+func (h *Handler) UpdateThreshold(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseThresholdID(w, r, reqID)
+	if !ok {
+		return
+	}
+	req, ok := decodeThreshold(w, r, reqID)
+	if !ok {
+		return
+	}
+	updated, err := h.storage.UpdateSensorThreshold(r.Context(), orgID, id, req)
+	if err != nil {
+		h.respondThresholdError(w, r, err, reqID)
+		return
+	}
+	if updated == nil {
+		httputil.Respond404(w, r, "sensor threshold not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": updated})
+}
+
+// @Summary  Delete a sensor threshold
+// @Description Alerts open against the threshold are resolved. Alert history is kept.
+// @Tags     sensors,internal
+// @ID       sensors.thresholds.delete
+// @Param    threshold_id path int true "Threshold id" minimum(1) format(int64)
+// @Success  204 "deleted"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/sensor-thresholds/{threshold_id} [delete]
+func (h *Handler) DeleteThreshold(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	id, ok := parseThresholdID(w, r, reqID)
+	if !ok {
+		return
+	}
+	deleted, err := h.storage.DeleteSensorThreshold(r.Context(), orgID, id)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "sensor threshold not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondThresholdError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	if errors.Is(err, storage.ErrSensorThresholdExists) {
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+}
@@ -0,0 +1,192 @@
+package sensors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/sensor"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockSensorStorage struct {
+	assets    map[string]int
+	createErr error
+
+	ingested    []sensor.NewReading
+	alertFilter *sensor.AlertFilter
+}
+
+func (m *mockSensorStorage) GetAssetIDsByExternalKeys(ctx context.Context, orgID int, keys []string) (map[string]int, error) {
+	out := map[string]int{}
+	for _, k := range keys {
+		if id, ok := m.assets[k]; ok {
+			out[k] = id
+		}
+	}
+	return out, nil
+}
+
+func (m *mockSensorStorage) IngestSensorReadings(ctx context.Context, orgID int, readings []sensor.NewReading) (*sensor.IngestResult, error) {
+	m.ingested = readings
+	return &sensor.IngestResult{Accepted: len(readings), AlertsOpened: 1}, nil
+}
+
+func (m *mockSensorStorage) ListSensorReadings(ctx context.Context, orgID, assetID int, f sensor.ReadingFilter) ([]sensor.Reading, bool, error) {
+	return []sensor.Reading{}, true, nil
+}
+
+func (m *mockSensorStorage) ListSensorAlerts(ctx context.Context, orgID int, f sensor.AlertFilter) ([]sensor.Alert, error) {
+	m.alertFilter = &f
+	return []sensor.Alert{}, nil
+}
+
+func (m *mockSensorStorage) ListSensorThresholds(ctx context.Context, orgID int) ([]sensor.Threshold, error) {
+	return []sensor.Threshold{}, nil
+}
+
+func (m *mockSensorStorage) CreateSensorThreshold(ctx context.Context, orgID int, req sensor.ThresholdRequest) (*sensor.Threshold, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	return &sensor.Threshold{ID: 9, OrgID: orgID, AssetType: req.AssetType, Metric: req.Metric,
+		MinValue: req.MinValue, MaxValue: req.MaxValue}, nil
+}
+
+func (m *mockSensorStorage) UpdateSensorThreshold(ctx context.Context, orgID, id int, req sensor.ThresholdRequest) (*sensor.Threshold, error) {
+	return nil, nil
+}
+
+func (m *mockSensorStorage) DeleteSensorThreshold(ctx context.Context, orgID, id int) (bool, error) {
+	return false, nil
+}
+
+var testNow = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func newHandler(m *mockSensorStorage) *Handler {
+	h := NewHandler(m)
+	h.now = func() time.Time { return testNow }
+	return h
+}
+
+// serve routes through chi without the auth/scope middleware so handler
+// logic is exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Post("/api/v1/sensor-readings", h.Ingest)
+		r.Get("/api/v1/sensor-alerts", h.ListAlerts)
+		r.Post("/api/v1/sensor-thresholds", h.CreateThreshold)
+		r.Put("/api/v1/sensor-thresholds/{threshold_id}", h.UpdateThreshold)
+	}, req)
+}
+
+func reading(asset, metric string, value float64, at time.Time) string {
+	return fmt.Sprintf(`{"asset_identifier":%q,"metric":%q,"value":%v,"unit":"C","recorded_at":%q}`,
+		asset, metric, value, at.Format(time.RFC3339))
+}
+
+func TestIngest_ResolvesAssetsAndStores(t *testing.T) {
+	m := &mockSensorStorage{assets: map[string]int{"PALLET-1": 101}}
+	body := `{"readings":[` + reading("PALLET-1", "temperature", 4.5, testNow.Add(-time.Minute)) + `,` +
+		reading("PALLET-1", "temperature", 9.5, testNow) + `]}`
+	w := serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sensor-readings", body))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if len(m.ingested) != 2 || m.ingested[0].AssetID != 101 || m.ingested[1].Value != 9.5 {
+		t.Fatalf("ingested = %+v", m.ingested)
+	}
+	if !strings.Contains(w.Body.String(), `"alerts_opened":1`) {
+		t.Errorf("body = %s", w.Body.String())
+	}
+}
+
+func TestIngest_RejectsWholeBatch(t *testing.T) {
+	cases := []struct {
+		name, body, want string
+	}{
+		{"unknown asset",
+			`{"readings":[` + reading("PALLET-1", "temperature", 4, testNow) + `,` + reading("NOPE", "temperature", 4, testNow) + `]}`,
+			"readings[1].asset_identifier"},
+		{"bad metric",
+			`{"readings":[` + reading("PALLET-1", "Temp C", 4, testNow) + `]}`,
+			"readings[0].metric"},
+		{"future reading",
+			`{"readings":[` + reading("PALLET-1", "temperature", 4, testNow.Add(time.Hour)) + `]}`,
+			"readings[0].recorded_at"},
+		{"missing value",
+			`{"readings":[{"asset_identifier":"PALLET-1","metric":"temperature","recorded_at":"2026-10-01T11:00:00Z"}]}`,
+			"value"},
+		{"empty batch", `{"readings":[]}`, "readings"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockSensorStorage{assets: map[string]int{"PALLET-1": 101}}
+			w := serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sensor-readings", c.body))
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), c.want) {
+				t.Errorf("body %s does not name %s", w.Body.String(), c.want)
+			}
+			if m.ingested != nil {
+				t.Error("storage should not be called")
+			}
+		})
+	}
+}
+
+func TestListAlerts_StatusFilter(t *testing.T) {
+	m := &mockSensorStorage{}
+	w := serve(newHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/sensor-alerts?status=open&limit=5", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.alertFilter.Open == nil || !*m.alertFilter.Open || m.alertFilter.Limit != 5 {
+		t.Errorf("filter = %+v", m.alertFilter)
+	}
+
+	w = serve(newHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/sensor-alerts?status=closed", ""))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateThreshold(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		err  error
+		want int
+	}{
+		{"range", `{"asset_type":"reefer_pallet","metric":"temperature","min_value":2,"max_value":8}`, nil, http.StatusCreated},
+		{"max only", `{"asset_type":"frozen","metric":"temperature","max_value":-18}`, nil, http.StatusCreated},
+		{"no bounds", `{"asset_type":"frozen","metric":"temperature"}`, nil, http.StatusBadRequest},
+		{"inverted", `{"asset_type":"frozen","metric":"temperature","min_value":8,"max_value":2}`, nil, http.StatusBadRequest},
+		{"bad metric", `{"asset_type":"frozen","metric":"Temp","max_value":2}`, nil, http.StatusBadRequest},
+		{"duplicate", `{"asset_type":"frozen","metric":"temperature","max_value":-18}`, storage.ErrSensorThresholdExists, http.StatusConflict},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockSensorStorage{createErr: c.err}
+			w := serve(newHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/sensor-thresholds", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestUpdateThreshold_NotFound(t *testing.T) {
+	body := `{"asset_type":"frozen","metric":"temperature","max_value":-18}`
+	w := serve(newHandler(&mockSensorStorage{}), testutil.NewRequest(t, http.MethodPut, "/api/v1/sensor-thresholds/5", body))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,447 @@
+// Package stock serves consumable assets: marking an asset as tracked by
+// quantity, its stock level per location, the adjustment ledger that moves
+// those levels, and the alerts raised when a location falls below the
+// consumable's minimum.
+package stock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/stock"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	return v
+}()
+
+// StockStorage is the storage surface the handler needs (mockable).
+type StockStorage interface {
+	GetLocationByExternalKey(ctx context.Context, orgID int, identifier string) (*location.LocationWithParent, error)
+	GetConsumable(ctx context.Context, orgID, assetID int) (*stock.Consumable, error)
+	SetConsumable(ctx context.Context, orgID, assetID int, req stock.ConsumableRequest) (*stock.Consumable, error)
+	DeleteConsumable(ctx context.Context, orgID, assetID int) (bool, error)
+	ListStockLevels(ctx context.Context, orgID, assetID int) ([]stock.Level, bool, error)
+	AdjustStock(ctx context.Context, orgID, assetID int, adj stock.NewAdjustment) (*stock.Adjustment, error)
+	ListStockAdjustments(ctx context.Context, orgID, assetID, limit int) ([]stock.Adjustment, bool, error)
+	ListStockAlerts(ctx context.Context, orgID int, f stock.AlertFilter) ([]stock.Alert, error)
+}
+
+type Handler struct {
+	storage StockStorage
+}
+
+func NewHandler(storage StockStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the stock routes onto r. Mount inside the session-auth
+// (middleware.Auth) group. Any member can read stock; operators record
+// adjustments; marking an asset consumable or changing its minimum is
+// admin-only since it opens and resolves alerts.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	operator := middleware.RequireCurrentOrgOperator(store)
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(member).Get("/api/v1/assets/{asset_id}/consumable", h.GetConsumable)
+	r.With(admin).Put("/api/v1/assets/{asset_id}/consumable", h.SetConsumable)
+	r.With(admin).Delete("/api/v1/assets/{asset_id}/consumable", h.DeleteConsumable)
+	r.With(member).Get("/api/v1/assets/{asset_id}/stock", h.ListLevels)
+	r.With(member).Get("/api/v1/assets/{asset_id}/stock/adjustments", h.ListAdjustments)
+	r.With(operator).Post("/api/v1/assets/{asset_id}/stock/adjustments", h.Adjust)
+	r.With(member).Get("/api/v1/stock-alerts", h.ListAlerts)
+}
+
+// parseAssetID reads the asset_id path param, answering 400 itself.
+func parseAssetID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("asset_id", chi.URLParam(r, "asset_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// parseLimit reads the limit query param, answering 400 itself.
+func parseLimit(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	v := r.URL.Query().Get("limit")
+	if v == "" {
+		return defaultLimit, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > maxLimit {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "limit", Code: "invalid_value",
+			Message: fmt.Sprintf("limit must be an integer between 1 and %d", maxLimit),
+		}})
+		return 0, false
+	}
+	return n, true
+}
+
+// @Summary  Get an asset's consumable settings
+// @Tags     stock,internal
+// @ID       stock.consumable.get
+// @Produce  json
+// @Param    asset_id path int true "Asset id" minimum(1) format(int64)
+// @Success  200 {object} map[string]any "data: stock.Consumable"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Asset is not a consumable"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/assets/{asset_id}/consumable [get]
+func (h *Handler) GetConsumable(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	assetID, ok := parseAssetID(w, r, reqID)
+	if !ok {
+		return
+	}
+	c, err := h.storage.GetConsumable(r.Context(), orgID, assetID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if c == nil {
+		httputil.Respond404(w, r, "consumable not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": c})
+}
+
+// @Summary  Mark an asset as consumable
+// @Description Tracks the asset by quantity per location instead of individually, or updates an existing consumable. `min_level` sets the level below which a location raises a stock alert; null disables alerts. Changing it re-checks every location at once.
+// @Tags     stock,internal
+// @ID       stock.consumable.set
+// @Accept   json
+// @Produce  json
+// @Param    asset_id path int true "Asset id" minimum(1) format(int64)
+// @Param    request body stock.ConsumableRequest true "Consumable settings"
+// @Success  200 {object} map[string]any "data: stock.Consumable"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/assets/{asset_id}/consumable [put]
+func (h *Handler) SetConsumable(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	assetID, ok := parseAssetID(w, r, reqID)
+	if !ok {
+		return
+	}
+	var req stock.ConsumableRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	c, err := h.storage.SetConsumable(r.Context(), orgID, assetID, req)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if c == nil {
+		httputil.Respond404(w, r, "asset not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": c})
+}
+
+// @Summary  Stop tracking an asset by quantity
+// @Description Drops the asset's stock levels and resolves its open stock alerts. The adjustment ledger and alert history are kept.
+// @Tags     stock,internal
+// @ID       stock.consumable.delete
+// @Param    asset_id path int true "Asset id" minimum(1) format(int64)
+// @Success  204 "deleted"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/assets/{asset_id}/consumable [delete]
+func (h *Handler) DeleteConsumable(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	assetID, ok := parseAssetID(w, r, reqID)
+	if !ok {
+		return
+	}
+	deleted, err := h.storage.DeleteConsumable(r.Context(), orgID, assetID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "consumable not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary  List a consumable's stock levels
+// @Description One entry per location the consumable has been stocked at, by location external key. `below_min` flags locations short of the consumable's `min_level`.
+// @Tags     stock,internal
+// @ID       stock.levels.list
+// @Produce  json
+// @Param    asset_id path int true "Asset id" minimum(1) format(int64)
+// @Success  200 {object} map[string]any "data: []stock.Level"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Asset is not a consumable"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/assets/{asset_id}/stock [get]
+func (h *Handler) ListLevels(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	assetID, ok := parseAssetID(w, r, reqID)
+	if !ok {
+		return
+	}
+	levels, found, err := h.storage.ListStockLevels(r.Context(), orgID, assetID)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "consumable not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": levels})
+}
+
+// @Summary  List a consumable's stock adjustments
+// @Description Newest first, across all locations.
+// @Tags     stock,internal
+// @ID       stock.adjustments.list
+// @Produce  json
+// @Param    asset_id path int true "Asset id" minimum(1) format(int64)
+// @Param    limit query int false "Max adjustments (1-1000, default 100)"
+// @Success  200 {object} map[string]any "data: []stock.Adjustment"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/assets/{asset_id}/stock/adjustments [get]
+func (h *Handler) ListAdjustments(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	assetID, ok := parseAssetID(w, r, reqID)
+	if !ok {
+		return
+	}
+	limit, ok := parseLimit(w, r, reqID)
+	if !ok {
+		return
+	}
+	list, found, err := h.storage.ListStockAdjustments(r.Context(), orgID, assetID, limit)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "consumable not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Adjust a consumable's stock
+// @Description Records one change to the consumable's level at a location. Send `delta` to add or remove stock (receipts, issues, write-offs) or `quantity` to record a count that replaces the level; exactly one is required. A change that leaves the location below the consumable's `min_level` opens a stock alert and one that restores it resolves the alert, delivered to webhooks as `stock_alert.opened` and `stock_alert.resolved`.
+// @Tags     stock,internal
+// @ID       stock.adjustments.create
+// @Accept   json
+// @Produce  json
+// @Param    asset_id path int true "Asset id" minimum(1) format(int64)
+// @Param    request body stock.AdjustmentRequest true "Adjustment"
+// @Success  201 {object} map[string]any "data: stock.Adjustment"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Asset is not a consumable"
+// @Failure  409 {object} modelerrors.ErrorResponse "Adjustment would take the stock level below zero"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/assets/{asset_id}/stock/adjustments [post]
+func (h *Handler) Adjust(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	assetID, ok := parseAssetID(w, r, reqID)
+	if !ok {
+		return
+	}
+	var req stock.AdjustmentRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if (req.Delta == nil) == (req.Quantity == nil) {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			"exactly one of delta and quantity is required", reqID)
+		return
+	}
+
+	loc, err := h.storage.GetLocationByExternalKey(r.Context(), orgID, req.LocationIdentifier)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if loc == nil {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "location_identifier", Code: "invalid_value",
+			Message: fmt.Sprintf("location_identifier %q not found", req.LocationIdentifier),
+		}})
+		return
+	}
+
+	adj := stock.NewAdjustment{LocationID: loc.ID, Kind: stock.KindAdjust, Reason: req.Reason}
+	if req.Quantity != nil {
+		adj.Kind = stock.KindCount
+		adj.Quantity = *req.Quantity
+	} else {
+		adj.Delta = *req.Delta
+	}
+	if c := middleware.GetUserClaims(r); c != nil {
+		adj.UserID = &c.UserID
+	}
+
+	created, err := h.storage.AdjustStock(r.Context(), orgID, assetID, adj)
+	switch {
+	case errors.Is(err, storage.ErrNotConsumable):
+		httputil.Respond404(w, r, "consumable not found", reqID)
+		return
+	case errors.Is(err, storage.ErrInsufficientStock):
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+		return
+	case err != nil:
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if created == nil {
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "location_identifier", Code: "invalid_value",
+			Message: fmt.Sprintf("location_identifier %q not found", req.LocationIdentifier),
+		}})
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": created})
+}
+
+// @Summary  List stock alerts
+// @Description Most recently opened first. `status=open` lists locations still short of their minimum; `status=resolved` only restored ones.
+// @Tags     stock,internal
+// @ID       stock.alerts.list
+// @Produce  json
+// @Param    asset_id query int false "Only this asset" minimum(1) format(int64)
+// @Param    status query string false "open or resolved" Enums(open, resolved)
+// @Param    limit query int false "Max alerts (1-1000, default 100)"
+// @Success  200 {object} map[string]any "data: []stock.Alert"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/stock-alerts [get]
+func (h *Handler) ListAlerts(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	limit, ok := parseLimit(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	f := stock.AlertFilter{Limit: limit}
+	q := r.URL.Query()
+	if v := q.Get("asset_id"); v != "" {
+		id, err := httputil.ParseSurrogateID("asset_id", v)
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		f.AssetID = &id
+	}
+	switch q.Get("status") {
+	case "":
+	case "open":
+		open := true
+		f.Open = &open
+	case "resolved":
+		open := false
+		f.Open = &open
+	default:
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "status", Code: "invalid_value", Message: "status must be one of: open, resolved",
+		}})
+		return
+	}
+
+	alerts, err := h.storage.ListStockAlerts(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": alerts})
+}
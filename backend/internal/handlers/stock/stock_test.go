@@ -0,0 +1,153 @@
+package stock
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/stock"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockStockStorage struct {
+	locations map[string]int
+	adjustErr error
+
+	adjusted    *stock.NewAdjustment
+	alertFilter *stock.AlertFilter
+}
+
+func (m *mockStockStorage) GetLocationByExternalKey(ctx context.Context, orgID int, identifier string) (*location.LocationWithParent, error) {
+	id, ok := m.locations[identifier]
+	if !ok {
+		return nil, nil
+	}
+	return &location.LocationWithParent{LocationView: location.LocationView{Location: location.Location{ID: id, ExternalKey: identifier}}}, nil
+}
+
+func (m *mockStockStorage) GetConsumable(ctx context.Context, orgID, assetID int) (*stock.Consumable, error) {
+	return nil, nil
+}
+
+func (m *mockStockStorage) SetConsumable(ctx context.Context, orgID, assetID int, req stock.ConsumableRequest) (*stock.Consumable, error) {
+	return &stock.Consumable{AssetID: assetID, OrgID: orgID, Unit: stock.DefaultUnit, MinLevel: req.MinLevel}, nil
+}
+
+func (m *mockStockStorage) DeleteConsumable(ctx context.Context, orgID, assetID int) (bool, error) {
+	return false, nil
+}
+
+func (m *mockStockStorage) ListStockLevels(ctx context.Context, orgID, assetID int) ([]stock.Level, bool, error) {
+	return []stock.Level{}, false, nil
+}
+
+func (m *mockStockStorage) AdjustStock(ctx context.Context, orgID, assetID int, adj stock.NewAdjustment) (*stock.Adjustment, error) {
+	m.adjusted = &adj
+	if m.adjustErr != nil {
+		return nil, m.adjustErr
+	}
+	return &stock.Adjustment{ID: 3, AssetID: assetID, LocationID: adj.LocationID, Kind: adj.Kind, Delta: adj.Delta}, nil
+}
+
+func (m *mockStockStorage) ListStockAdjustments(ctx context.Context, orgID, assetID, limit int) ([]stock.Adjustment, bool, error) {
+	return []stock.Adjustment{}, false, nil
+}
+
+func (m *mockStockStorage) ListStockAlerts(ctx context.Context, orgID int, f stock.AlertFilter) ([]stock.Alert, error) {
+	m.alertFilter = &f
+	return []stock.Alert{}, nil
+}
+
+// serve routes through chi without the role middleware so handler logic is
+// exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Get("/api/v1/assets/{asset_id}/consumable", h.GetConsumable)
+		r.Put("/api/v1/assets/{asset_id}/consumable", h.SetConsumable)
+		r.Get("/api/v1/assets/{asset_id}/stock", h.ListLevels)
+		r.Post("/api/v1/assets/{asset_id}/stock/adjustments", h.Adjust)
+		r.Get("/api/v1/stock-alerts", h.ListAlerts)
+	}, req)
+}
+
+func TestAdjust(t *testing.T) {
+	cases := []struct {
+		name     string
+		body     string
+		err      error
+		want     int
+		wantKind string
+	}{
+		{"delta", `{"location_identifier":"WH-01","delta":-5,"reason":"issued"}`, nil, http.StatusCreated, stock.KindAdjust},
+		{"count", `{"location_identifier":"WH-01","quantity":40}`, nil, http.StatusCreated, stock.KindCount},
+		{"neither", `{"location_identifier":"WH-01"}`, nil, http.StatusBadRequest, ""},
+		{"both", `{"location_identifier":"WH-01","delta":1,"quantity":4}`, nil, http.StatusBadRequest, ""},
+		{"negative count", `{"location_identifier":"WH-01","quantity":-1}`, nil, http.StatusBadRequest, ""},
+		{"unknown location", `{"location_identifier":"NOPE","delta":1}`, nil, http.StatusBadRequest, ""},
+		{"not consumable", `{"location_identifier":"WH-01","delta":1}`, storage.ErrNotConsumable, http.StatusNotFound, stock.KindAdjust},
+		{"insufficient", `{"location_identifier":"WH-01","delta":-99}`, storage.ErrInsufficientStock, http.StatusConflict, stock.KindAdjust},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockStockStorage{locations: map[string]int{"WH-01": 7}, adjustErr: c.err}
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/assets/5/stock/adjustments", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.wantKind == "" {
+				if m.adjusted != nil {
+					t.Error("storage should not be called")
+				}
+				return
+			}
+			if m.adjusted == nil || m.adjusted.Kind != c.wantKind || m.adjusted.LocationID != 7 {
+				t.Fatalf("adjusted = %+v", m.adjusted)
+			}
+			if m.adjusted.UserID == nil || *m.adjusted.UserID != 1 {
+				t.Errorf("user id = %v", m.adjusted.UserID)
+			}
+		})
+	}
+}
+
+func TestSetConsumable_RejectsNegativeMinLevel(t *testing.T) {
+	w := serve(NewHandler(&mockStockStorage{}), testutil.NewRequest(t, http.MethodPut, "/api/v1/assets/5/consumable", `{"min_level":-1}`))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	w = serve(NewHandler(&mockStockStorage{}), testutil.NewRequest(t, http.MethodPut, "/api/v1/assets/5/consumable", `{"unit":"box","min_level":10}`))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"min_level":10`) {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReads_NotConsumable(t *testing.T) {
+	for _, path := range []string{"/api/v1/assets/5/consumable", "/api/v1/assets/5/stock"} {
+		w := serve(NewHandler(&mockStockStorage{}), testutil.NewRequest(t, http.MethodGet, path, ""))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, body = %s", path, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestListAlerts_Filters(t *testing.T) {
+	m := &mockStockStorage{}
+	w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/stock-alerts?asset_id=5&status=open&limit=5", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	f := m.alertFilter
+	if f.AssetID == nil || *f.AssetID != 5 || f.Open == nil || !*f.Open || f.Limit != 5 {
+		t.Errorf("filter = %+v", f)
+	}
+
+	w = serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/stock-alerts?status=closed", ""))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
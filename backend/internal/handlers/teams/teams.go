@@ -0,0 +1,500 @@
+// Package teams provides internal (session-authenticated) endpoints for
+// departments/teams within an org: team CRUD, team membership, and assigning
+// assets and locations to a team. Strict team mode itself is an org setting
+// (PATCH /api/v1/orgs/{id}/team-settings); list and read enforcement lives in
+// the assets and locations handlers.
+package teams
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// TeamStorage is the narrow storage surface the handler needs (mockable).
+type TeamStorage interface {
+	ListTeams(ctx context.Context, orgID int) ([]team.Team, error)
+	GetTeam(ctx context.Context, orgID, teamID int) (*team.TeamWithMembers, error)
+	CreateTeam(ctx context.Context, orgID int, req team.CreateTeamRequest) (*team.Team, error)
+	UpdateTeam(ctx context.Context, orgID, teamID int, req team.UpdateTeamRequest) (*team.Team, error)
+	DeleteTeam(ctx context.Context, orgID, teamID int) (bool, error)
+	SetTeamMember(ctx context.Context, orgID, teamID, userID int, role string) (bool, error)
+	RemoveTeamMember(ctx context.Context, orgID, teamID, userID int) (bool, error)
+	GetTeamMemberRole(ctx context.Context, orgID, teamID, userID int) (string, error)
+	GetUserOrgRole(ctx context.Context, userID, orgID int) (models.OrgRole, error)
+	AssignAssetToTeam(ctx context.Context, orgID, teamID, assetID int) (bool, error)
+	UnassignAssetFromTeam(ctx context.Context, orgID, teamID, assetID int) (bool, error)
+	AssignLocationToTeam(ctx context.Context, orgID, teamID, locationID int) (bool, error)
+	UnassignLocationFromTeam(ctx context.Context, orgID, teamID, locationID int) (bool, error)
+}
+
+type Handler struct {
+	storage TeamStorage
+}
+
+func NewHandler(storage TeamStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the team routes onto r. Mount inside the session-auth
+// (middleware.Auth) group. Any org member can read teams. Creating and
+// deleting a team is org-admin only; renaming it and managing its members is
+// open to org admins and the team's own admins (checked in the handler).
+// Assigning assets and locations is an asset-management action and requires
+// Manager+.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	manager := middleware.RequireCurrentOrgRole(store, models.RoleManager)
+	admin := middleware.RequireCurrentOrgRole(store, models.RoleAdmin)
+
+	r.With(member).Get("/api/v1/teams", h.List)
+	r.With(member).Get("/api/v1/teams/{team_id}", h.Get)
+	r.With(admin).Post("/api/v1/teams", h.Create)
+	r.With(member).Patch("/api/v1/teams/{team_id}", h.Update)
+	r.With(admin).Delete("/api/v1/teams/{team_id}", h.Delete)
+
+	r.With(member).Put("/api/v1/teams/{team_id}/members/{user_id}", h.SetMember)
+	r.With(member).Delete("/api/v1/teams/{team_id}/members/{user_id}", h.RemoveMember)
+
+	r.With(manager).Put("/api/v1/teams/{team_id}/assets/{asset_id}", h.AssignAsset)
+	r.With(manager).Delete("/api/v1/teams/{team_id}/assets/{asset_id}", h.UnassignAsset)
+	r.With(manager).Put("/api/v1/teams/{team_id}/locations/{location_id}", h.AssignLocation)
+	r.With(manager).Delete("/api/v1/teams/{team_id}/locations/{location_id}", h.UnassignLocation)
+}
+
+// @Summary  List teams
+// @Tags     teams,internal
+// @ID       teams.list
+// @Produce  json
+// @Success  200 {object} map[string]any "data: []team.Team"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	teams, err := h.storage.ListTeams(r.Context(), orgID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": teams})
+}
+
+// @Summary  Get a team (members + assignment counts)
+// @Tags     teams,internal
+// @ID       teams.get
+// @Produce  json
+// @Param    team_id path int true "Team id" minimum(1) format(int64)
+// @Success  200 {object} map[string]any "data: team.TeamWithMembers"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, teamID, ok := parseTeamPath(w, r, reqID)
+	if !ok {
+		return
+	}
+	t, err := h.storage.GetTeam(r.Context(), orgID, teamID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+		return
+	}
+	if t == nil {
+		httputil.Respond404(w, r, "team not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": t})
+}
+
+// @Summary  Create a team
+// @Tags     teams,internal
+// @ID       teams.create
+// @Accept   json
+// @Produce  json
+// @Param    request body team.CreateTeamRequest true "Team name and optional description"
+// @Success  201 {object} map[string]any "data: team.Team"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "A team with this name already exists"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return
+	}
+	var req team.CreateTeamRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	created, err := h.storage.CreateTeam(r.Context(), orgID, req)
+	if err != nil {
+		writeTeamError(w, r, err, reqID)
+		return
+	}
+	w.Header().Set("Location", "/api/v1/teams/"+strconv.Itoa(created.ID))
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": created})
+}
+
+// @Summary  Update a team
+// @Description Partial update of name and description. Allowed for org admins and the team's admins.
+// @Tags     teams,internal
+// @ID       teams.update
+// @Accept   json
+// @Produce  json
+// @Param    team_id path int                    true "Team id" minimum(1) format(int64)
+// @Param    request body team.UpdateTeamRequest true "Fields to change"
+// @Success  200 {object} map[string]any "data: team.Team"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "A team with this name already exists"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id} [patch]
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, teamID, ok := parseTeamPath(w, r, reqID)
+	if !ok {
+		return
+	}
+	if !h.requireTeamAdmin(w, r, reqID, orgID, teamID) {
+		return
+	}
+	var req team.UpdateTeamRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	updated, err := h.storage.UpdateTeam(r.Context(), orgID, teamID, req)
+	if err != nil {
+		writeTeamError(w, r, err, reqID)
+		return
+	}
+	if updated == nil {
+		httputil.Respond404(w, r, "team not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": updated})
+}
+
+// @Summary  Delete a team
+// @Description Removes the team and its memberships. Assets and locations assigned to it become unassigned.
+// @Tags     teams,internal
+// @ID       teams.delete
+// @Param    team_id path int true "Team id" minimum(1) format(int64)
+// @Success  204 "deleted"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id} [delete]
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, teamID, ok := parseTeamPath(w, r, reqID)
+	if !ok {
+		return
+	}
+	deleted, err := h.storage.DeleteTeam(r.Context(), orgID, teamID)
+	if err != nil {
+		writeTeamError(w, r, err, reqID)
+		return
+	}
+	if !deleted {
+		httputil.Respond404(w, r, "team not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary  Add a team member or change their team role
+// @Description Idempotent. The user must be a member of the organization. Allowed for org admins and the team's admins.
+// @Tags     teams,internal
+// @ID       teams.members.set
+// @Accept   json
+// @Produce  json
+// @Param    team_id path int                   true "Team id" minimum(1) format(int64)
+// @Param    user_id path int                   true "User id" minimum(1) format(int64)
+// @Param    request body team.SetMemberRequest true "Team role"
+// @Success  204 "member set"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id}/members/{user_id} [put]
+func (h *Handler) SetMember(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, teamID, ok := parseTeamPath(w, r, reqID)
+	if !ok {
+		return
+	}
+	userID, err := httputil.ParseSurrogateID("user_id", chi.URLParam(r, "user_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	if !h.requireTeamAdmin(w, r, reqID, orgID, teamID) {
+		return
+	}
+	var req team.SetMemberRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+	if _, err := h.storage.GetUserOrgRole(r.Context(), userID, orgID); err != nil {
+		if errors.Is(err, storage.ErrOrgUserNotFound) {
+			httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+				"user is not a member of this organization", reqID)
+			return
+		}
+		writeTeamError(w, r, err, reqID)
+		return
+	}
+	set, err := h.storage.SetTeamMember(r.Context(), orgID, teamID, userID, req.Role)
+	if err != nil {
+		writeTeamError(w, r, err, reqID)
+		return
+	}
+	if !set {
+		httputil.Respond404(w, r, "team not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary  Remove a team member
+// @Description Allowed for org admins and the team's admins.
+// @Tags     teams,internal
+// @ID       teams.members.remove
+// @Param    team_id path int true "Team id" minimum(1) format(int64)
+// @Param    user_id path int true "User id" minimum(1) format(int64)
+// @Success  204 "removed"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id}/members/{user_id} [delete]
+func (h *Handler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, teamID, ok := parseTeamPath(w, r, reqID)
+	if !ok {
+		return
+	}
+	userID, err := httputil.ParseSurrogateID("user_id", chi.URLParam(r, "user_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	if !h.requireTeamAdmin(w, r, reqID, orgID, teamID) {
+		return
+	}
+	removed, err := h.storage.RemoveTeamMember(r.Context(), orgID, teamID, userID)
+	if err != nil {
+		writeTeamError(w, r, err, reqID)
+		return
+	}
+	if !removed {
+		httputil.Respond404(w, r, "team member not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary  Assign an asset to a team
+// @Description Idempotent. An asset belongs to at most one team; assigning it here moves it from any previous team. Requires Manager+.
+// @Tags     teams,internal
+// @ID       teams.assets.assign
+// @Param    team_id  path int true "Team id" minimum(1) format(int64)
+// @Param    asset_id path int true "Asset id" minimum(1) format(int64)
+// @Success  204 "assigned"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Team or asset not found"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id}/assets/{asset_id} [put]
+func (h *Handler) AssignAsset(w http.ResponseWriter, r *http.Request) {
+	h.assignment(w, r, "asset_id", "team or asset not found", h.storage.AssignAssetToTeam)
+}
+
+// @Summary  Unassign an asset from a team
+// @Tags     teams,internal
+// @ID       teams.assets.unassign
+// @Param    team_id  path int true "Team id" minimum(1) format(int64)
+// @Param    asset_id path int true "Asset id" minimum(1) format(int64)
+// @Success  204 "unassigned"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Asset is not assigned to this team"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id}/assets/{asset_id} [delete]
+func (h *Handler) UnassignAsset(w http.ResponseWriter, r *http.Request) {
+	h.assignment(w, r, "asset_id", "asset is not assigned to this team", h.storage.UnassignAssetFromTeam)
+}
+
+// @Summary  Assign a location to a team
+// @Description Idempotent. A location belongs to at most one team; assigning it here moves it from any previous team. Descendant locations are not assigned implicitly. Requires Manager+.
+// @Tags     teams,internal
+// @ID       teams.locations.assign
+// @Param    team_id     path int true "Team id" minimum(1) format(int64)
+// @Param    location_id path int true "Location id" minimum(1) format(int64)
+// @Success  204 "assigned"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Team or location not found"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id}/locations/{location_id} [put]
+func (h *Handler) AssignLocation(w http.ResponseWriter, r *http.Request) {
+	h.assignment(w, r, "location_id", "team or location not found", h.storage.AssignLocationToTeam)
+}
+
+// @Summary  Unassign a location from a team
+// @Tags     teams,internal
+// @ID       teams.locations.unassign
+// @Param    team_id     path int true "Team id" minimum(1) format(int64)
+// @Param    location_id path int true "Location id" minimum(1) format(int64)
+// @Success  204 "unassigned"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse "Location is not assigned to this team"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/teams/{team_id}/locations/{location_id} [delete]
+func (h *Handler) UnassignLocation(w http.ResponseWriter, r *http.Request) {
+	h.assignment(w, r, "location_id", "location is not assigned to this team", h.storage.UnassignLocationFromTeam)
+}
+
+// assignment is the shared body of the asset/location (un)assign routes:
+// parse {team_id} and the resource id param, run op, 204 or 404.
+func (h *Handler) assignment(w http.ResponseWriter, r *http.Request, idParam, notFound string,
+	op func(ctx context.Context, orgID, teamID, id int) (bool, error)) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, teamID, ok := parseTeamPath(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, err := httputil.ParseSurrogateID(idParam, chi.URLParam(r, idParam))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return
+	}
+	done, err := op(r.Context(), orgID, teamID, id)
+	if err != nil {
+		writeTeamError(w, r, err, reqID)
+		return
+	}
+	if !done {
+		httputil.Respond404(w, r, notFound, reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseTeamPath resolves the org from the session and parses {team_id}.
+func parseTeamPath(w http.ResponseWriter, r *http.Request, reqID string) (orgID, teamID int, ok bool) {
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return 0, 0, false
+	}
+	teamID, err = httputil.ParseSurrogateID("team_id", chi.URLParam(r, "team_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, 0, false
+	}
+	return orgID, teamID, true
+}
+
+// requireTeamAdmin writes a 403 and returns false unless the caller is an org
+// admin (role resolved by the route middleware) or an admin of this team.
+func (h *Handler) requireTeamAdmin(w http.ResponseWriter, r *http.Request, reqID string, orgID, teamID int) bool {
+	if role, ok := middleware.GetOrgRole(r.Context()); ok && role == models.RoleAdmin {
+		return true
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return false
+	}
+	role, err := h.storage.GetTeamMemberRole(r.Context(), orgID, teamID, claims.UserID)
+	if err != nil {
+		writeTeamError(w, r, err, reqID)
+		return false
+	}
+	if role != team.RoleAdmin {
+		httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden,
+			"Requires org admin or team admin", reqID)
+		return false
+	}
+	return true
+}
+
+// writeTeamError maps storage errors: duplicate team name → 409, everything
+// else → 500.
+func writeTeamError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	if strings.Contains(err.Error(), "already exist") {
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+		return
+	}
+	httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal, err.Error(), reqID)
+}
@@ -0,0 +1,193 @@
+package teams
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockTeamStorage struct {
+	createResult *team.Team
+	createErr    error
+	teamRole     string
+	orgRoleErr   error
+	setOK        bool
+	assignOK     bool
+
+	gotSetRole  string
+	gotAssignID int
+}
+
+func (m *mockTeamStorage) ListTeams(ctx context.Context, orgID int) ([]team.Team, error) {
+	return []team.Team{}, nil
+}
+
+func (m *mockTeamStorage) GetTeam(ctx context.Context, orgID, teamID int) (*team.TeamWithMembers, error) {
+	return nil, nil
+}
+
+func (m *mockTeamStorage) CreateTeam(ctx context.Context, orgID int, req team.CreateTeamRequest) (*team.Team, error) {
+	return m.createResult, m.createErr
+}
+
+func (m *mockTeamStorage) UpdateTeam(ctx context.Context, orgID, teamID int, req team.UpdateTeamRequest) (*team.Team, error) {
+	return &team.Team{ID: teamID, Name: *req.Name}, nil
+}
+
+func (m *mockTeamStorage) DeleteTeam(ctx context.Context, orgID, teamID int) (bool, error) {
+	return true, nil
+}
+
+func (m *mockTeamStorage) SetTeamMember(ctx context.Context, orgID, teamID, userID int, role string) (bool, error) {
+	m.gotSetRole = role
+	return m.setOK, nil
+}
+
+func (m *mockTeamStorage) RemoveTeamMember(ctx context.Context, orgID, teamID, userID int) (bool, error) {
+	return true, nil
+}
+
+func (m *mockTeamStorage) GetTeamMemberRole(ctx context.Context, orgID, teamID, userID int) (string, error) {
+	return m.teamRole, nil
+}
+
+func (m *mockTeamStorage) GetUserOrgRole(ctx context.Context, userID, orgID int) (models.OrgRole, error) {
+	return models.RoleViewer, m.orgRoleErr
+}
+
+func (m *mockTeamStorage) AssignAssetToTeam(ctx context.Context, orgID, teamID, assetID int) (bool, error) {
+	m.gotAssignID = assetID
+	return m.assignOK, nil
+}
+
+func (m *mockTeamStorage) UnassignAssetFromTeam(ctx context.Context, orgID, teamID, assetID int) (bool, error) {
+	return m.assignOK, nil
+}
+
+func (m *mockTeamStorage) AssignLocationToTeam(ctx context.Context, orgID, teamID, locationID int) (bool, error) {
+	return m.assignOK, nil
+}
+
+func (m *mockTeamStorage) UnassignLocationFromTeam(ctx context.Context, orgID, teamID, locationID int) (bool, error) {
+	return m.assignOK, nil
+}
+
+// serve routes through chi without the role middleware so handler-level
+// checks are exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Post("/api/v1/teams", h.Create)
+		r.Patch("/api/v1/teams/{team_id}", h.Update)
+		r.Put("/api/v1/teams/{team_id}/members/{user_id}", h.SetMember)
+		r.Put("/api/v1/teams/{team_id}/assets/{asset_id}", h.AssignAsset)
+	}, req)
+}
+
+func TestCreate_Happy(t *testing.T) {
+	mock := &mockTeamStorage{createResult: &team.Team{ID: 7, Name: "Maintenance"}}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPost, "/api/v1/teams",
+		team.CreateTeamRequest{Name: "Maintenance"}))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Location"); got != "/api/v1/teams/7" {
+		t.Errorf("Location = %q", got)
+	}
+}
+
+func TestCreate_DuplicateName409(t *testing.T) {
+	mock := &mockTeamStorage{createErr: errors.New("failed to create team: team with name Maintenance already exists")}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPost, "/api/v1/teams",
+		team.CreateTeamRequest{Name: "Maintenance"}))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreate_ConflictDetailCarriesNoPersonalData(t *testing.T) {
+	mock := &mockTeamStorage{createErr: errors.New(`failed to create team: ERROR: duplicate key value violates ` +
+		`unique constraint "teams_org_name_key" (SQLSTATE 23505): Key (org_id, name)=(1, dana.ortiz@acme.co) already exists.`)}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPost, "/api/v1/teams",
+		team.CreateTeamRequest{Name: "dana.ortiz@acme.co"}))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "dana.ortiz") {
+		t.Errorf("detail echoes the conflicting value: %s", w.Body.String())
+	}
+}
+
+func TestCreate_MissingName400(t *testing.T) {
+	w := serve(NewHandler(&mockTeamStorage{}), testutil.NewRequest(t, http.MethodPost, "/api/v1/teams", map[string]any{}))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdate_TeamMemberForbidden(t *testing.T) {
+	mock := &mockTeamStorage{teamRole: team.RoleMember}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPatch, "/api/v1/teams/5",
+		map[string]any{"name": "Ops"}))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdate_TeamAdminAllowed(t *testing.T) {
+	mock := &mockTeamStorage{teamRole: team.RoleAdmin}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPatch, "/api/v1/teams/5",
+		map[string]any{"name": "Ops"}))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetMember_NonOrgMember400(t *testing.T) {
+	mock := &mockTeamStorage{teamRole: team.RoleAdmin, orgRoleErr: storage.ErrOrgUserNotFound}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPut, "/api/v1/teams/5/members/9",
+		team.SetMemberRequest{Role: team.RoleMember}))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetMember_InvalidRole400(t *testing.T) {
+	mock := &mockTeamStorage{teamRole: team.RoleAdmin, setOK: true}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPut, "/api/v1/teams/5/members/9",
+		team.SetMemberRequest{Role: "owner"}))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetMember_Happy(t *testing.T) {
+	mock := &mockTeamStorage{teamRole: team.RoleAdmin, setOK: true}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPut, "/api/v1/teams/5/members/9",
+		team.SetMemberRequest{Role: team.RoleAdmin}))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if mock.gotSetRole != team.RoleAdmin {
+		t.Errorf("role = %q", mock.gotSetRole)
+	}
+}
+
+func TestAssignAsset_NotFound404(t *testing.T) {
+	mock := &mockTeamStorage{assignOK: false}
+	w := serve(NewHandler(mock), testutil.NewRequest(t, http.MethodPut, "/api/v1/teams/5/assets/11", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if mock.gotAssignID != 11 {
+		t.Errorf("asset id = %d", mock.gotAssignID)
+	}
+}
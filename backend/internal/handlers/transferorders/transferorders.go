@@ -0,0 +1,452 @@
+// Package transferorders serves transfer orders: planned moves of a set of
+// assets between two of the org's locations, verified by scanning at the
+// origin before shipping and at the destination on arrival, with a report of
+// what went missing or turned up unexpectedly at either end.
+package transferorders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const listLimit = 200
+
+var validate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+	return v
+}()
+
+// OrderStorage is the storage surface the handler needs (mockable).
+type OrderStorage interface {
+	CreateTransferOrder(ctx context.Context, orgID, userID int, req transferorder.CreateRequest) (*transferorder.Order, error)
+	GetTransferOrder(ctx context.Context, orgID, id int) (*transferorder.Order, error)
+	ListTransferOrders(ctx context.Context, orgID int, f transferorder.ListFilter) ([]transferorder.Order, error)
+	VerifyTransferOrder(ctx context.Context, orgID, id int, req transferorder.VerifyRequest) (*transferorder.VerifyResult, error)
+	TransitionTransferOrder(ctx context.Context, orgID, id, userID int, action string) (*transferorder.Order, error)
+	SetTransferOrderTracking(ctx context.Context, orgID, id int, req transferorder.SetTrackingRequest) (*transferorder.Order, error)
+	ClearTransferOrderTracking(ctx context.Context, orgID, id int) (bool, error)
+}
+
+type Handler struct {
+	storage OrderStorage
+}
+
+func NewHandler(storage OrderStorage) *Handler {
+	return &Handler{storage: storage}
+}
+
+// RegisterRoutes wires the transfer order routes onto r. Mount inside the
+// session-auth (middleware.Auth) group. Any member can read orders;
+// creating, scanning and moving them along is operator work.
+func (h *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	member := middleware.RequireCurrentOrgRole(store, models.RoleViewer)
+	operator := middleware.RequireCurrentOrgOperator(store)
+
+	r.With(member).Get("/api/v1/transfer-orders", h.List)
+	r.With(operator).Post("/api/v1/transfer-orders", h.Create)
+	r.With(member).Get("/api/v1/transfer-orders/{transfer_order_id}", h.Get)
+	r.With(member).Get("/api/v1/transfer-orders/{transfer_order_id}/discrepancies", h.Discrepancies)
+	r.With(operator).Post("/api/v1/transfer-orders/{transfer_order_id}/scans", h.Verify)
+	r.With(operator).Post("/api/v1/transfer-orders/{transfer_order_id}/ship", h.transition(transferorder.ActionShip))
+	r.With(operator).Post("/api/v1/transfer-orders/{transfer_order_id}/receive", h.transition(transferorder.ActionReceive))
+	r.With(operator).Post("/api/v1/transfer-orders/{transfer_order_id}/cancel", h.transition(transferorder.ActionCancel))
+	r.With(operator).Put("/api/v1/transfer-orders/{transfer_order_id}/tracking", h.SetTracking)
+	r.With(operator).Delete("/api/v1/transfer-orders/{transfer_order_id}/tracking", h.ClearTracking)
+}
+
+// caller returns the request's org and session user, answering the error
+// itself when either is missing.
+func caller(w http.ResponseWriter, r *http.Request, reqID string) (orgID, userID int, ok bool) {
+	orgID, err := middleware.GetRequestOrgID(r)
+	if err != nil {
+		httputil.RespondMissingOrgContext(w, r, reqID)
+		return 0, 0, false
+	}
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", reqID)
+		return 0, 0, false
+	}
+	return orgID, claims.UserID, true
+}
+
+// parseID reads the transfer_order_id path param, answering 400 itself.
+func parseID(w http.ResponseWriter, r *http.Request, reqID string) (int, bool) {
+	id, err := httputil.ParseSurrogateID("transfer_order_id", chi.URLParam(r, "transfer_order_id"))
+	if err != nil {
+		httputil.RespondPathParamError(w, r, err, reqID)
+		return 0, false
+	}
+	return id, true
+}
+
+// respondOrderError maps the storage sentinels to their status codes.
+func respondOrderError(w http.ResponseWriter, r *http.Request, err error, reqID string) {
+	switch {
+	case errors.Is(err, storage.ErrTransferOrderLocationNotFound):
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "origin_location_id", Code: "fk_not_found", Message: err.Error(),
+		}})
+	case errors.Is(err, storage.ErrTransferOrderAssetNotFound):
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "asset_ids", Code: "fk_not_found", Message: err.Error(),
+		}})
+	case errors.Is(err, storage.ErrTransferOrderState):
+		httputil.WriteJSONError(w, r, http.StatusConflict, modelerrors.ErrConflict, err.Error(), reqID)
+	default:
+		httputil.RespondStorageError(w, r, err, reqID)
+	}
+}
+
+// @Summary  Create a transfer order
+// @Description Opens an order to move `asset_ids` from the origin to the destination location. Scan it at the origin (POST .../scans with stage origin), ship it, scan it at the destination (stage destination) and receive it; GET .../discrepancies then lists the assets missing or unexpected at either end.
+// @Tags     transfer-orders,internal
+// @ID       transfer_orders.create
+// @Accept   json
+// @Produce  json
+// @Param    request body transferorder.CreateRequest true "Transfer order"
+// @Success  201 {object} map[string]any "data: transferorder.Order"
+// @Failure  400 {object} modelerrors.ErrorResponse "validation_error, or a location or asset not found"
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/transfer-orders [post]
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, userID, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req transferorder.CreateRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	o, err := h.storage.CreateTransferOrder(r.Context(), orgID, userID, req)
+	if err != nil {
+		respondOrderError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusCreated, map[string]any{"data": o})
+}
+
+// @Summary  List transfer orders
+// @Description The org's transfer orders, newest first, at most 200, without their items.
+// @Tags     transfer-orders,internal
+// @ID       transfer_orders.list
+// @Produce  json
+// @Param    status query string false "Only this status" Enums(open, in_transit, received, cancelled)
+// @Param    location_id query int false "Only orders from or to this location" minimum(1) format(int64)
+// @Success  200 {object} map[string]any "data: []transferorder.Order"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/transfer-orders [get]
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	f := transferorder.ListFilter{Limit: listLimit}
+	q := r.URL.Query()
+	switch st := q.Get("status"); st {
+	case "", transferorder.StatusOpen, transferorder.StatusInTransit, transferorder.StatusReceived,
+		transferorder.StatusCancelled:
+		f.Status = st
+	default:
+		httputil.WriteValidationError(w, r, reqID, []modelerrors.FieldError{{
+			Field: "status", Code: "invalid_value",
+			Message: "status must be one of: open, in_transit, received, cancelled",
+		}})
+		return
+	}
+	if v := q.Get("location_id"); v != "" {
+		id, err := httputil.ParseSurrogateID("location_id", v)
+		if err != nil {
+			httputil.RespondPathParamError(w, r, err, reqID)
+			return
+		}
+		f.LocationID = id
+	}
+
+	list, err := h.storage.ListTransferOrders(r.Context(), orgID, f)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": list})
+}
+
+// @Summary  Get a transfer order
+// @Description The order with its items: the expected assets, then any unexpected ones a verification scan found, each with when it was scanned at the origin and at the destination.
+// @Tags     transfer-orders,internal
+// @ID       transfer_orders.get
+// @Produce  json
+// @Param    transfer_order_id path int true "Transfer order id"
+// @Success  200 {object} map[string]any "data: transferorder.Order"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/transfer-orders/{transfer_order_id} [get]
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+	o, err := h.storage.GetTransferOrder(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if o == nil {
+		httputil.Respond404(w, r, "transfer order not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": o})
+}
+
+// @Summary  Transfer order discrepancies
+// @Description Compares the order's scans with its expected assets: expected assets not scanned at the origin or the destination are missing there, and assets scanned at either end that the order did not list are extra. Destination lists stay empty until the order has shipped.
+// @Tags     transfer-orders,internal
+// @ID       transfer_orders.discrepancies
+// @Produce  json
+// @Param    transfer_order_id path int true "Transfer order id"
+// @Success  200 {object} map[string]any "data: transferorder.DiscrepancyReport"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/transfer-orders/{transfer_order_id}/discrepancies [get]
+func (h *Handler) Discrepancies(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+	o, err := h.storage.GetTransferOrder(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if o == nil {
+		httputil.Respond404(w, r, "transfer order not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": transferorder.Discrepancies(*o)})
+}
+
+// @Summary  Verify a transfer order by scan
+// @Description Records the EPCs read at one end of the move: `origin` while the order is open, `destination` while it is in transit. Expected assets found are stamped as scanned at that end (`matched`); assets the order did not list are added to it as unexpected (`extra`); EPCs that resolve to no asset come back in `unknown_epcs`. `remaining` counts the expected assets still unscanned at that end. Scans can be repeated.
+// @Tags     transfer-orders,internal
+// @ID       transfer_orders.verify
+// @Accept   json
+// @Produce  json
+// @Param    transfer_order_id path int true "Transfer order id"
+// @Param    request body transferorder.VerifyRequest true "Scan"
+// @Success  200 {object} map[string]any "data: transferorder.VerifyResult"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "the order's status does not allow scans at this stage"
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/transfer-orders/{transfer_order_id}/scans [post]
+func (h *Handler) Verify(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req transferorder.VerifyRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	res, err := h.storage.VerifyTransferOrder(r.Context(), orgID, id, req)
+	if err != nil {
+		respondOrderError(w, r, err, reqID)
+		return
+	}
+	if res == nil {
+		httputil.Respond404(w, r, "transfer order not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": res})
+}
+
+// transition returns the handler for ship, receive and cancel.
+//
+// @Summary  Ship, receive or cancel a transfer order
+// @Description ship needs an open order, receive an in-transit one, and cancel either. Discrepancies do not block a transition; they stay on the order's discrepancy report.
+// @Tags     transfer-orders,internal
+// @ID       transfer_orders.transition
+// @Produce  json
+// @Param    transfer_order_id path int true "Transfer order id"
+// @Param    action path string true "ship, receive or cancel" Enums(ship, receive, cancel)
+// @Success  200 {object} map[string]any "data: transferorder.Order"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "the order's status does not allow the action"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/transfer-orders/{transfer_order_id}/{action} [post]
+func (h *Handler) transition(action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetRequestID(r.Context())
+		orgID, userID, ok := caller(w, r, reqID)
+		if !ok {
+			return
+		}
+		id, ok := parseID(w, r, reqID)
+		if !ok {
+			return
+		}
+		o, err := h.storage.TransitionTransferOrder(r.Context(), orgID, id, userID, action)
+		if err != nil {
+			respondOrderError(w, r, err, reqID)
+			return
+		}
+		if o == nil {
+			httputil.Respond404(w, r, "transfer order not found", reqID)
+			return
+		}
+		httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": o})
+	}
+}
+
+// @Summary  Attach carrier tracking to a transfer order
+// @Description Records the carrier and tracking number of an open or in-transit order shipped by a third party, replacing any earlier ones. When the server has a tracker for the carrier (dhl when configured), it polls the carrier about every 30 minutes and keeps the shipment's status, latest event and ETA in the order's `tracking`, until the carrier reports it delivered or the order is received or cancelled. Other carrier codes are stored for reference only. The order's own status still moves by ship and receive.
+// @Tags     transfer-orders,internal
+// @ID       transfer_orders.tracking.set
+// @Accept   json
+// @Produce  json
+// @Param    transfer_order_id path int true "Transfer order id"
+// @Param    request body transferorder.SetTrackingRequest true "Carrier tracking"
+// @Success  200 {object} map[string]any "data: transferorder.Order"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  409 {object} modelerrors.ErrorResponse "the order is received or cancelled"
+// @Failure  415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/transfer-orders/{transfer_order_id}/tracking [put]
+func (h *Handler) SetTracking(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+
+	var req transferorder.SetTrackingRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, reqID)
+		return
+	}
+	if err := validate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, reqID)
+		return
+	}
+
+	o, err := h.storage.SetTransferOrderTracking(r.Context(), orgID, id, req)
+	if err != nil {
+		respondOrderError(w, r, err, reqID)
+		return
+	}
+	if o == nil {
+		httputil.Respond404(w, r, "transfer order not found", reqID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": o})
+}
+
+// @Summary  Remove carrier tracking from a transfer order
+// @Tags     transfer-orders,internal
+// @ID       transfer_orders.tracking.clear
+// @Param    transfer_order_id path int true "Transfer order id"
+// @Success  204 "tracking removed"
+// @Failure  400 {object} modelerrors.ErrorResponse
+// @Failure  401 {object} modelerrors.ErrorResponse
+// @Failure  403 {object} modelerrors.ErrorResponse
+// @Failure  404 {object} modelerrors.ErrorResponse
+// @Failure  500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router   /api/v1/transfer-orders/{transfer_order_id}/tracking [delete]
+func (h *Handler) ClearTracking(w http.ResponseWriter, r *http.Request) {
+	reqID := middleware.GetRequestID(r.Context())
+	orgID, _, ok := caller(w, r, reqID)
+	if !ok {
+		return
+	}
+	id, ok := parseID(w, r, reqID)
+	if !ok {
+		return
+	}
+	found, err := h.storage.ClearTransferOrderTracking(r.Context(), orgID, id)
+	if err != nil {
+		httputil.RespondStorageError(w, r, err, reqID)
+		return
+	}
+	if !found {
+		httputil.Respond404(w, r, "transfer order not found", reqID)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
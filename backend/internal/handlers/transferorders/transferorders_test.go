@@ -0,0 +1,250 @@
+package transferorders
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+type mockOrderStorage struct {
+	created   *transferorder.CreateRequest
+	createErr error
+	filter    transferorder.ListFilter
+	verified  *transferorder.VerifyRequest
+	verifyErr error
+	action    string
+	actionErr error
+	tracking  *transferorder.SetTrackingRequest
+}
+
+// Order 9 is in transit: asset 1 arrived, asset 2 never left, and asset 3
+// was loaded without being on the order.
+func order9() *transferorder.Order {
+	at := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	return &transferorder.Order{ID: 9, Status: transferorder.StatusInTransit, ShippedAt: &at, Items: []transferorder.Item{
+		{AssetID: 1, Expected: true, OriginScannedAt: &at, DestinationScannedAt: &at},
+		{AssetID: 2, Expected: true},
+		{AssetID: 3, OriginScannedAt: &at},
+	}}
+}
+
+func (m *mockOrderStorage) CreateTransferOrder(ctx context.Context, orgID, userID int, req transferorder.CreateRequest) (*transferorder.Order, error) {
+	if m.createErr != nil {
+		return nil, m.createErr
+	}
+	m.created = &req
+	return &transferorder.Order{ID: 9, Status: transferorder.StatusOpen}, nil
+}
+
+func (m *mockOrderStorage) GetTransferOrder(ctx context.Context, orgID, id int) (*transferorder.Order, error) {
+	if id != 9 {
+		return nil, nil
+	}
+	return order9(), nil
+}
+
+func (m *mockOrderStorage) ListTransferOrders(ctx context.Context, orgID int, f transferorder.ListFilter) ([]transferorder.Order, error) {
+	m.filter = f
+	return []transferorder.Order{}, nil
+}
+
+func (m *mockOrderStorage) VerifyTransferOrder(ctx context.Context, orgID, id int, req transferorder.VerifyRequest) (*transferorder.VerifyResult, error) {
+	m.verified = &req
+	if m.verifyErr != nil {
+		return nil, m.verifyErr
+	}
+	if id != 9 {
+		return nil, nil
+	}
+	return &transferorder.VerifyResult{OrderID: 9, Stage: req.Stage}, nil
+}
+
+func (m *mockOrderStorage) TransitionTransferOrder(ctx context.Context, orgID, id, userID int, action string) (*transferorder.Order, error) {
+	m.action = action
+	if m.actionErr != nil {
+		return nil, m.actionErr
+	}
+	if id != 9 {
+		return nil, nil
+	}
+	return order9(), nil
+}
+
+func (m *mockOrderStorage) SetTransferOrderTracking(ctx context.Context, orgID, id int, req transferorder.SetTrackingRequest) (*transferorder.Order, error) {
+	if m.actionErr != nil {
+		return nil, m.actionErr
+	}
+	if id != 9 {
+		return nil, nil
+	}
+	m.tracking = &req
+	o := order9()
+	o.Tracking = &transferorder.Tracking{Carrier: req.Carrier, TrackingNumber: req.TrackingNumber}
+	return o, nil
+}
+
+func (m *mockOrderStorage) ClearTransferOrderTracking(ctx context.Context, orgID, id int) (bool, error) {
+	return id == 9, nil
+}
+
+// serve routes through chi without the role middleware so handler-level
+// checks are exercised directly.
+func serve(h *Handler, req *http.Request) *httptest.ResponseRecorder {
+	return testutil.Serve(func(r chi.Router) {
+		r.Get("/api/v1/transfer-orders", h.List)
+		r.Post("/api/v1/transfer-orders", h.Create)
+		r.Get("/api/v1/transfer-orders/{transfer_order_id}", h.Get)
+		r.Get("/api/v1/transfer-orders/{transfer_order_id}/discrepancies", h.Discrepancies)
+		r.Post("/api/v1/transfer-orders/{transfer_order_id}/scans", h.Verify)
+		r.Post("/api/v1/transfer-orders/{transfer_order_id}/ship", h.transition(transferorder.ActionShip))
+		r.Post("/api/v1/transfer-orders/{transfer_order_id}/cancel", h.transition(transferorder.ActionCancel))
+		r.Put("/api/v1/transfer-orders/{transfer_order_id}/tracking", h.SetTracking)
+		r.Delete("/api/v1/transfer-orders/{transfer_order_id}/tracking", h.ClearTracking)
+	}, req)
+}
+
+func TestCreate(t *testing.T) {
+	cases := []struct {
+		name      string
+		body      string
+		createErr error
+		want      int
+		field     string
+	}{
+		{"ok", `{"origin_location_id":3,"destination_location_id":4,"asset_ids":[1,2]}`, nil, http.StatusCreated, ""},
+		{"same location", `{"origin_location_id":3,"destination_location_id":3,"asset_ids":[1]}`, nil, http.StatusBadRequest, "destination_location_id"},
+		{"no assets", `{"origin_location_id":3,"destination_location_id":4,"asset_ids":[]}`, nil, http.StatusBadRequest, "asset_ids"},
+		{"duplicate assets", `{"origin_location_id":3,"destination_location_id":4,"asset_ids":[1,1]}`, nil, http.StatusBadRequest, "asset_ids"},
+		{"foreign location", `{"origin_location_id":3,"destination_location_id":4,"asset_ids":[1]}`, storage.ErrTransferOrderLocationNotFound, http.StatusBadRequest, "origin_location_id"},
+		{"foreign asset", `{"origin_location_id":3,"destination_location_id":4,"asset_ids":[1]}`, storage.ErrTransferOrderAssetNotFound, http.StatusBadRequest, "asset_ids"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := &mockOrderStorage{createErr: c.createErr}
+			w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/transfer-orders", c.body))
+			if w.Code != c.want {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, c.want, w.Body.String())
+			}
+			if c.field != "" && !strings.Contains(w.Body.String(), `"field":"`+c.field+`"`) {
+				t.Errorf("want field %s, body = %s", c.field, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestList_Filters(t *testing.T) {
+	m := &mockOrderStorage{}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/transfer-orders?status=in_transit&location_id=3", "")); w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.filter.Status != transferorder.StatusInTransit || m.filter.LocationID != 3 || m.filter.Limit != listLimit {
+		t.Errorf("filter = %+v", m.filter)
+	}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodGet, "/api/v1/transfer-orders?status=lost", "")); w.Code != http.StatusBadRequest {
+		t.Errorf("bad status: %d", w.Code)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	m := &mockOrderStorage{}
+	w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/transfer-orders/9/scans", `{"stage":"destination","epcs":["E1","E2"]}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.verified == nil || len(m.verified.EPCs) != 2 {
+		t.Errorf("verified = %+v", m.verified)
+	}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/transfer-orders/9/scans", `{"stage":"dock","epcs":["E1"]}`)); w.Code != http.StatusBadRequest {
+		t.Errorf("bad stage: %d", w.Code)
+	}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/transfer-orders/8/scans", `{"stage":"origin","epcs":["E1"]}`)); w.Code != http.StatusNotFound {
+		t.Errorf("missing order: %d", w.Code)
+	}
+	m.verifyErr = storage.ErrTransferOrderState
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/transfer-orders/9/scans", `{"stage":"origin","epcs":["E1"]}`)); w.Code != http.StatusConflict {
+		t.Errorf("wrong stage for status: %d", w.Code)
+	}
+}
+
+func TestTransition(t *testing.T) {
+	m := &mockOrderStorage{}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/transfer-orders/9/ship", "")); w.Code != http.StatusOK {
+		t.Fatalf("ship: status = %d", w.Code)
+	}
+	if m.action != transferorder.ActionShip {
+		t.Errorf("action = %q", m.action)
+	}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/transfer-orders/8/cancel", "")); w.Code != http.StatusNotFound {
+		t.Errorf("cancel missing: status = %d", w.Code)
+	}
+	m.actionErr = storage.ErrTransferOrderState
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPost, "/api/v1/transfer-orders/9/ship", "")); w.Code != http.StatusConflict {
+		t.Errorf("ship twice: status = %d", w.Code)
+	}
+}
+
+func TestDiscrepancies(t *testing.T) {
+	w := serve(NewHandler(&mockOrderStorage{}), testutil.NewRequest(t, http.MethodGet, "/api/v1/transfer-orders/9/discrepancies", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Data transferorder.DiscrepancyReport `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	r := body.Data
+	if len(r.MissingAtOrigin) != 1 || r.MissingAtOrigin[0].AssetID != 2 {
+		t.Errorf("missing at origin = %+v", r.MissingAtOrigin)
+	}
+	if len(r.ExtraAtOrigin) != 1 || r.ExtraAtOrigin[0].AssetID != 3 {
+		t.Errorf("extra at origin = %+v", r.ExtraAtOrigin)
+	}
+	if len(r.MissingAtDestination) != 1 || r.MissingAtDestination[0].AssetID != 2 {
+		t.Errorf("missing at destination = %+v", r.MissingAtDestination)
+	}
+	if w := serve(NewHandler(&mockOrderStorage{}), testutil.NewRequest(t, http.MethodGet, "/api/v1/transfer-orders/8/discrepancies", "")); w.Code != http.StatusNotFound {
+		t.Errorf("missing order: %d", w.Code)
+	}
+}
+
+func TestTracking(t *testing.T) {
+	m := &mockOrderStorage{}
+	w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPut, "/api/v1/transfer-orders/9/tracking", `{"carrier":"DHL","tracking_number":"00340434292135100186"}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("set: status = %d, body = %s", w.Code, w.Body.String())
+	}
+	if m.tracking == nil || m.tracking.TrackingNumber != "00340434292135100186" {
+		t.Errorf("tracking = %+v", m.tracking)
+	}
+	if !strings.Contains(w.Body.String(), `"tracking":{"carrier":"DHL"`) {
+		t.Errorf("body = %s", w.Body.String())
+	}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPut, "/api/v1/transfer-orders/9/tracking", `{"carrier":"dhl"}`)); w.Code != http.StatusBadRequest {
+		t.Errorf("missing tracking number: %d", w.Code)
+	}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPut, "/api/v1/transfer-orders/8/tracking", `{"carrier":"dhl","tracking_number":"1"}`)); w.Code != http.StatusNotFound {
+		t.Errorf("missing order: %d", w.Code)
+	}
+	m.actionErr = storage.ErrTransferOrderState
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodPut, "/api/v1/transfer-orders/9/tracking", `{"carrier":"dhl","tracking_number":"1"}`)); w.Code != http.StatusConflict {
+		t.Errorf("received order: %d", w.Code)
+	}
+
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodDelete, "/api/v1/transfer-orders/9/tracking", "")); w.Code != http.StatusNoContent {
+		t.Errorf("clear: %d", w.Code)
+	}
+	if w := serve(NewHandler(m), testutil.NewRequest(t, http.MethodDelete, "/api/v1/transfer-orders/8/tracking", "")); w.Code != http.StatusNotFound {
+		t.Errorf("clear missing: %d", w.Code)
+	}
+}
@@ -0,0 +1,150 @@
+package users
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/trakrf/platform/backend/internal/i18n"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/user"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// prefsValidate reports preference fields by their JSON names, so the field
+// errors line up with the request body.
+var prefsValidate = func() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	return v
+}()
+
+// validatePreferences checks what the struct tags cannot: that timezone is a
+// known IANA zone and locale is one the API speaks.
+func validatePreferences(p user.Preferences) []modelerrors.FieldError {
+	var fields []modelerrors.FieldError
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil || p.Timezone == "Local" {
+			fields = append(fields, modelerrors.FieldError{
+				Field:   "timezone",
+				Code:    "invalid_value",
+				Message: "timezone must be an IANA time zone such as America/Chicago",
+			})
+		}
+	}
+	if p.Locale != "" && !slices.Contains(i18n.Supported(), p.Locale) {
+		fields = append(fields, modelerrors.FieldError{
+			Field:   "locale",
+			Code:    "invalid_value",
+			Message: fmt.Sprintf("locale must be one of: %s", strings.Join(i18n.Supported(), ", ")),
+		})
+	}
+	return fields
+}
+
+// @Summary Get the authenticated user's preferences
+// @Description Returns the caller's timezone, locale, notification channels, default org and table densities. Unset fields mean the app default.
+// @Tags users,internal
+// @ID users.preferences.get
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]any "data: user.Preferences"
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/me/preferences [get]
+// GetPreferences returns the authenticated user's preferences.
+func (handler *Handler) GetPreferences(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	prefs, err := handler.storage.GetUserPreferences(r.Context(), claims.UserID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get preferences", middleware.GetRequestID(r.Context()))
+		return
+	}
+	if prefs == nil {
+		httputil.Respond404(w, r, "User not found", middleware.GetRequestID(r.Context()))
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": prefs})
+}
+
+// @Summary Replace the authenticated user's preferences
+// @Description Full-replace: omitted fields revert to the app default. timezone is an IANA zone; locale is en, es or fr; default_org_id must be an org the caller belongs to; table_densities values are compact, comfortable or spacious (at most 50 tables).
+// @Tags users,internal
+// @ID users.preferences.put
+// @Accept json
+// @Produce json
+// @Param request body user.Preferences true "Preferences"
+// @Success 200 {object} map[string]any "data: user.Preferences"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/me/preferences [put]
+// PutPreferences replaces the authenticated user's preferences.
+func (handler *Handler) PutPreferences(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", middleware.GetRequestID(r.Context()))
+		return
+	}
+	requestID := middleware.GetRequestID(r.Context())
+
+	var req user.Preferences
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, requestID)
+		return
+	}
+
+	if err := prefsValidate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, requestID)
+		return
+	}
+	if fields := validatePreferences(req); len(fields) > 0 {
+		httputil.WriteValidationError(w, r, requestID, fields)
+		return
+	}
+	if req.DefaultOrgID != nil {
+		if _, err := handler.storage.GetUserOrgRole(r.Context(), claims.UserID, *req.DefaultOrgID); err != nil {
+			if errors.Is(err, storage.ErrOrgUserNotFound) {
+				httputil.WriteValidationError(w, r, requestID, []modelerrors.FieldError{{
+					Field:   "default_org_id",
+					Code:    "fk_not_found",
+					Message: "default_org_id is not an organization you belong to",
+				}})
+				return
+			}
+			httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+				"Failed to update preferences", requestID)
+			return
+		}
+	}
+
+	if err := handler.storage.UpdateUserPreferences(r.Context(), claims.UserID, req); err != nil {
+		if errors.Is(err, modelerrors.ErrUserNotFound) {
+			httputil.Respond404(w, r, "User not found", requestID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update preferences", requestID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": req})
+}
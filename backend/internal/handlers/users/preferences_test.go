@@ -0,0 +1,57 @@
+package users
+
+import (
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/models/user"
+)
+
+func TestValidatePreferences(t *testing.T) {
+	cases := []struct {
+		name       string
+		in         user.Preferences
+		wantFields []string
+	}{
+		{"empty ok", user.Preferences{}, nil},
+		{"valid", user.Preferences{Timezone: "America/Chicago", Locale: "es"}, nil},
+		{"utc ok", user.Preferences{Timezone: "UTC"}, nil},
+		{"unknown timezone", user.Preferences{Timezone: "Mars/Olympus"}, []string{"timezone"}},
+		{"local is not a zone", user.Preferences{Timezone: "Local"}, []string{"timezone"}},
+		{"unsupported locale", user.Preferences{Locale: "de"}, []string{"locale"}},
+		{"both", user.Preferences{Timezone: "nope", Locale: "xx"}, []string{"timezone", "locale"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := validatePreferences(c.in)
+			if len(got) != len(c.wantFields) {
+				t.Fatalf("got %d field errors (%+v), want %v", len(got), got, c.wantFields)
+			}
+			for i, f := range got {
+				if f.Field != c.wantFields[i] {
+					t.Errorf("field %d = %q, want %q", i, f.Field, c.wantFields[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPreferencesStructTags(t *testing.T) {
+	cases := []struct {
+		name    string
+		in      user.Preferences
+		wantErr bool
+	}{
+		{"densities ok", user.Preferences{TableDensities: map[string]string{"assets": "compact", "locations": "spacious"}}, false},
+		{"unknown density", user.Preferences{TableDensities: map[string]string{"assets": "tiny"}}, true},
+		{"empty table key", user.Preferences{TableDensities: map[string]string{"": "compact"}}, true},
+		{"default org zero", user.Preferences{DefaultOrgID: new(int)}, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := prefsValidate.Struct(c.in)
+			if c.wantErr != (err != nil) {
+				t.Fatalf("wantErr=%v got err=%v", c.wantErr, err)
+			}
+		})
+	}
+}
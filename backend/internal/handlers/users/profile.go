@@ -0,0 +1,228 @@
+package users
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/user"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// avatarExtensions maps the accepted avatar types to their key suffix.
+var avatarExtensions = map[string]string{
+	"image/png":  "png",
+	"image/jpeg": "jpg",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// @Summary Update the authenticated user's profile
+// @Description Changes the display name. Email and password have their own endpoints because they need confirmation.
+// @Tags users,internal
+// @ID users.profile.patch
+// @Accept json
+// @Produce json
+// @Param request body user.UpdateProfileRequest true "Profile fields"
+// @Success 200 {object} map[string]any "data: user.User"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/me/profile [patch]
+// UpdateProfile applies a partial update to the authenticated user's profile.
+func (handler *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", middleware.GetRequestID(r.Context()))
+		return
+	}
+	requestID := middleware.GetRequestID(r.Context())
+
+	var req user.UpdateProfileRequest
+	if err := httputil.DecodeJSONStrict(r, &req); err != nil {
+		httputil.RespondDecodeError(w, r, err, requestID)
+		return
+	}
+	if req.Name != nil {
+		trimmed := strings.TrimSpace(*req.Name)
+		req.Name = &trimmed
+	}
+	if err := prefsValidate.Struct(req); err != nil {
+		httputil.RespondValidationError(w, r, err, requestID)
+		return
+	}
+
+	usr, err := handler.storage.UpdateUser(r.Context(), claims.UserID, user.UpdateUserRequest{Name: req.Name})
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to update profile", requestID)
+		return
+	}
+	if usr == nil {
+		httputil.Respond404(w, r, "User not found", requestID)
+		return
+	}
+
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": usr})
+}
+
+// @Summary Upload the authenticated user's avatar
+// @Description The body is the raw image (PNG, JPEG, GIF or WebP, at most 2 MiB), sent with its image Content-Type. The previous avatar is replaced. 503 when no object store is configured.
+// @Tags users,internal
+// @ID users.avatar.put
+// @Accept image/png,image/jpeg,image/gif,image/webp
+// @Produce json
+// @Success 200 {object} map[string]any "data: user.User"
+// @Failure 400 {object} modelerrors.ErrorResponse
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 413 {object} modelerrors.ErrorResponse
+// @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Failure 503 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/me/avatar [put]
+// PutAvatar stores an uploaded image as the authenticated user's avatar.
+func (handler *Handler) PutAvatar(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", middleware.GetRequestID(r.Context()))
+		return
+	}
+	requestID := middleware.GetRequestID(r.Context())
+	if handler.objects == nil {
+		httputil.WriteJSONError(w, r, http.StatusServiceUnavailable, modelerrors.ErrInternal,
+			"avatar uploads are unavailable (object store not configured)", requestID)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			httputil.Respond413(w, r, mbe.Limit, requestID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
+			"Failed to read request body", requestID)
+		return
+	}
+	if len(body) == 0 {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			"Request body must be an image", requestID)
+		return
+	}
+	// Trust the bytes, not the declared type: the object is served with
+	// this Content-Type to every viewer.
+	contentType := http.DetectContentType(body)
+	if !slices.Contains(middleware.AvatarContentTypes, contentType) {
+		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrValidation,
+			"Request body must be a PNG, JPEG, GIF or WebP image", requestID)
+		return
+	}
+
+	suffix := make([]byte, 16)
+	if _, err := rand.Read(suffix); err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to upload avatar", requestID)
+		return
+	}
+	key := fmt.Sprintf("avatars/%d/%s.%s", claims.UserID, hex.EncodeToString(suffix), avatarExtensions[contentType])
+	if err := handler.objects.Put(r.Context(), key, contentType, body); err != nil {
+		logger.Get().Error().Err(err).Int("user_id", claims.UserID).Msg("avatar upload failed")
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to upload avatar", requestID)
+		return
+	}
+
+	avatarURL := handler.objects.URL(key)
+	previous, err := handler.storage.UpdateUserAvatar(r.Context(), claims.UserID, &avatarURL)
+	if err != nil {
+		handler.deleteAvatar(r, claims.UserID, &avatarURL)
+		if errors.Is(err, modelerrors.ErrUserNotFound) {
+			httputil.Respond404(w, r, "User not found", requestID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to upload avatar", requestID)
+		return
+	}
+	handler.deleteAvatar(r, claims.UserID, previous)
+
+	handler.respondUser(w, r, claims.UserID, requestID)
+}
+
+// @Summary Remove the authenticated user's avatar
+// @Tags users,internal
+// @ID users.avatar.delete
+// @Produce json
+// @Success 200 {object} map[string]any "data: user.User"
+// @Failure 401 {object} modelerrors.ErrorResponse
+// @Failure 404 {object} modelerrors.ErrorResponse
+// @Failure 500 {object} modelerrors.ErrorResponse
+// @Security SessionAuth
+// @Router /api/v1/me/avatar [delete]
+// DeleteAvatar clears the authenticated user's avatar.
+func (handler *Handler) DeleteAvatar(w http.ResponseWriter, r *http.Request) {
+	claims := middleware.GetUserClaims(r)
+	if claims == nil {
+		httputil.Respond401(w, r, "Session authentication required", middleware.GetRequestID(r.Context()))
+		return
+	}
+	requestID := middleware.GetRequestID(r.Context())
+
+	previous, err := handler.storage.UpdateUserAvatar(r.Context(), claims.UserID, nil)
+	if err != nil {
+		if errors.Is(err, modelerrors.ErrUserNotFound) {
+			httputil.Respond404(w, r, "User not found", requestID)
+			return
+		}
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to remove avatar", requestID)
+		return
+	}
+	handler.deleteAvatar(r, claims.UserID, previous)
+
+	handler.respondUser(w, r, claims.UserID, requestID)
+}
+
+// deleteAvatar removes a replaced avatar object. It is best-effort: the user
+// row no longer points at it, so a failure only leaves an orphan behind.
+func (handler *Handler) deleteAvatar(r *http.Request, userID int, avatarURL *string) {
+	if avatarURL == nil || handler.objects == nil {
+		return
+	}
+	key, ok := handler.objects.KeyForURL(*avatarURL)
+	if !ok {
+		return
+	}
+	if err := handler.objects.Delete(r.Context(), key); err != nil {
+		logger.Get().Warn().Err(err).Int("user_id", userID).Str("key", key).Msg("failed to delete replaced avatar")
+	}
+}
+
+// respondUser writes the user's current record.
+func (handler *Handler) respondUser(w http.ResponseWriter, r *http.Request, userID int, requestID string) {
+	usr, err := handler.storage.GetUserByID(r.Context(), userID)
+	if err != nil {
+		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
+			"Failed to get user", requestID)
+		return
+	}
+	if usr == nil {
+		httputil.Respond404(w, r, "User not found", requestID)
+		return
+	}
+	httputil.WriteJSON(w, http.StatusOK, map[string]any{"data": usr})
+}
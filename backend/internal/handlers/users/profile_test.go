@@ -0,0 +1,66 @@
+package users
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// fakeObjects records Put calls; nothing here reaches storage.
+type fakeObjects struct{ puts int }
+
+func (f *fakeObjects) Put(context.Context, string, string, []byte) error { f.puts++; return nil }
+func (f *fakeObjects) Delete(context.Context, string) error              { return nil }
+func (f *fakeObjects) URL(key string) string                             { return "https://cdn.test/" + key }
+func (f *fakeObjects) KeyForURL(string) (string, bool)                   { return "", false }
+
+func avatarRequest(body []byte) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, middleware.AvatarUploadPath, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "image/png")
+	return req.WithContext(middleware.WithUserClaimsForTest(req.Context(), &jwt.Claims{UserID: 7}))
+}
+
+func TestPutAvatar_Rejections(t *testing.T) {
+	t.Run("no object store", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewHandler(nil, nil).PutAvatar(w, avatarRequest([]byte("\x89PNG\r\n\x1a\n")))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Fatalf("status = %d, want 503", w.Code)
+		}
+	})
+
+	t.Run("body is not an image", func(t *testing.T) {
+		objects := &fakeObjects{}
+		w := httptest.NewRecorder()
+		NewHandler(nil, objects).PutAvatar(w, avatarRequest([]byte("<svg onload=alert(1)>")))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", w.Code)
+		}
+		if objects.puts != 0 {
+			t.Fatalf("uploaded %d objects, want none", objects.puts)
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewHandler(nil, &fakeObjects{}).PutAvatar(w, avatarRequest(nil))
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", w.Code)
+		}
+	})
+
+	t.Run("over the cap", func(t *testing.T) {
+		req := avatarRequest(bytes.Repeat([]byte("a"), 10))
+		w := httptest.NewRecorder()
+		req.Body = http.MaxBytesReader(w, req.Body, 4)
+		NewHandler(nil, &fakeObjects{}).PutAvatar(w, req)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("status = %d, want 413", w.Code)
+		}
+	})
+}
@@ -1,7 +1,6 @@
 package users
 
 import (
-	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
@@ -13,6 +12,7 @@ import (
 	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/models/shared"
 	"github.com/trakrf/platform/backend/internal/models/user"
+	"github.com/trakrf/platform/backend/internal/objectstore"
 	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
 )
@@ -26,11 +26,14 @@ type ListResponse struct {
 
 type Handler struct {
 	storage *storage.Storage
+	// objects holds uploaded avatars; nil when no object store is
+	// configured, which turns avatar uploads into 503s.
+	objects objectstore.Store
 }
 
-// NewHandler creates a new users handler instance.
-func NewHandler(storage *storage.Storage) *Handler {
-	return &Handler{storage: storage}
+// NewHandler creates a new users handler instance. objects may be nil.
+func NewHandler(storage *storage.Storage, objects objectstore.Store) *Handler {
+	return &Handler{storage: storage, objects: objects}
 }
 
 // @Summary List users
@@ -40,8 +43,12 @@ func NewHandler(storage *storage.Storage) *Handler {
 // @Produce json
 // @Param page query int false "Page number" default(1)
 // @Param per_page query int false "Items per page" default(20)
+// @Param sort query []string false "comma-separated, prefix '-' for DESC" collectionFormat(csv) Enums(email, -email, name, -name, created_at, -created_at, updated_at, -updated_at, last_login_at, -last_login_at)
+// @Param fields query []string false "sparse fieldset: comma-separated item keys to return (id is always included)" collectionFormat(csv)
 // @Success 200 {object} users.ListResponse
+// @Failure 400 {object} modelerrors.ErrorResponse "Invalid sort or fields"
 // @Failure 401 {object} modelerrors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "Superadmin privileges required"
 // @Failure 500 {object} modelerrors.ErrorResponse "Internal server error"
 // @Security SessionAuth
 // @Router /api/v1/users [get]
@@ -58,7 +65,22 @@ func (handler *Handler) List(w http.ResponseWriter, r *http.Request) {
 
 	offset := (page - 1) * perPage
 
-	users, total, err := handler.storage.ListUsers(r.Context(), perPage, offset)
+	sorts, err := httputil.ParseSort(r.URL.Query().Get("sort"), user.ListSorts)
+	if err != nil {
+		httputil.RespondListParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+	fields, err := httputil.ParseFields(r.URL.Query().Get("fields"), user.ListFields)
+	if err != nil {
+		httputil.RespondListParamError(w, r, err, middleware.GetRequestID(r.Context()))
+		return
+	}
+	userSorts := make([]user.ListSort, 0, len(sorts))
+	for _, s := range sorts {
+		userSorts = append(userSorts, user.ListSort{Field: s.Field, Desc: s.Desc})
+	}
+
+	users, total, err := handler.storage.ListUsers(r.Context(), perPage, offset, userSorts)
 	if err != nil {
 		httputil.WriteJSONError(w, r, http.StatusInternalServerError, modelerrors.ErrInternal,
 			apierrors.UserListFailed, middleware.GetRequestID(r.Context()))
@@ -75,7 +97,7 @@ func (handler *Handler) List(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	httputil.WriteJSON(w, http.StatusOK, resp)
+	httputil.WriteSparseJSON(w, http.StatusOK, resp, fields)
 }
 
 // @Summary Get user
@@ -87,6 +109,7 @@ func (handler *Handler) List(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} map[string]any "data: user.User"
 // @Failure 400 {object} modelerrors.ErrorResponse "Invalid user ID"
 // @Failure 401 {object} modelerrors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "Superadmin privileges required"
 // @Failure 404 {object} modelerrors.ErrorResponse "User not found"
 // @Failure 500 {object} modelerrors.ErrorResponse "Internal server error"
 // @Security SessionAuth
@@ -123,6 +146,7 @@ func (handler *Handler) Get(w http.ResponseWriter, r *http.Request) {
 // @Success 201 {object} map[string]any "data: user.User"
 // @Failure 400 {object} modelerrors.ErrorResponse "Invalid JSON or validation error"
 // @Failure 401 {object} modelerrors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "Superadmin privileges required"
 // @Failure 409 {object} modelerrors.ErrorResponse "Email already exists"
 // @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
 // @Failure 500 {object} modelerrors.ErrorResponse "Internal server error"
@@ -130,10 +154,8 @@ func (handler *Handler) Get(w http.ResponseWriter, r *http.Request) {
 // @Router /api/v1/users [post]
 func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var request user.CreateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -172,6 +194,7 @@ func (handler *Handler) Create(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} map[string]any "data: user.User"
 // @Failure 400 {object} modelerrors.ErrorResponse "Invalid ID, JSON, or validation error"
 // @Failure 401 {object} modelerrors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "Superadmin privileges required"
 // @Failure 404 {object} modelerrors.ErrorResponse "User not found"
 // @Failure 409 {object} modelerrors.ErrorResponse "Email already exists"
 // @Failure 415 {object} modelerrors.ErrorResponse "unsupported_media_type"
@@ -186,10 +209,8 @@ func (handler *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var request user.UpdateUserRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		httputil.WriteJSONError(w, r, http.StatusBadRequest, modelerrors.ErrBadRequest,
-			err.Error(), middleware.GetRequestID(r.Context()))
-
+	if err := httputil.DecodeJSONStrict(r, &request); err != nil {
+		httputil.RespondDecodeError(w, r, err, middleware.GetRequestID(r.Context()))
 		return
 	}
 
@@ -231,6 +252,7 @@ func (handler *Handler) Update(w http.ResponseWriter, r *http.Request) {
 // @Success 204 "No content"
 // @Failure 400 {object} modelerrors.ErrorResponse "Invalid user ID"
 // @Failure 401 {object} modelerrors.ErrorResponse "Unauthorized"
+// @Failure 403 {object} modelerrors.ErrorResponse "Superadmin privileges required"
 // @Failure 404 {object} modelerrors.ErrorResponse "User not found"
 // @Failure 500 {object} modelerrors.ErrorResponse "Internal server error"
 // @Security SessionAuth
@@ -256,11 +278,21 @@ func (handler *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// RegisterRoutes registers user endpoints on the given router.
-func (handler *Handler) RegisterRoutes(r chi.Router) {
-	r.Get("/api/v1/users", handler.List)
-	r.Get("/api/v1/users/{id}", handler.Get)
-	r.Post("/api/v1/users", handler.Create)
-	r.Put("/api/v1/users/{id}", handler.Update)
-	r.Delete("/api/v1/users/{id}", handler.Delete)
+// RegisterRoutes registers user endpoints on the given router. The user
+// directory spans every org, so its CRUD routes are superadmin-only; an org
+// admin manages people through /api/v1/orgs/{id}/members instead.
+func (handler *Handler) RegisterRoutes(r chi.Router, store middleware.OrgRoleStore) {
+	superadmin := middleware.RequireSuperadmin(store)
+	r.With(superadmin).Get("/api/v1/users", handler.List)
+	r.With(superadmin).Get("/api/v1/users/{id}", handler.Get)
+	r.With(superadmin).Post("/api/v1/users", handler.Create)
+	r.With(superadmin).Put("/api/v1/users/{id}", handler.Update)
+	r.With(superadmin).Delete("/api/v1/users/{id}", handler.Delete)
+
+	// The caller's own preferences; session auth supplies the user.
+	r.Get("/api/v1/me/preferences", handler.GetPreferences)
+	r.Put("/api/v1/me/preferences", handler.PutPreferences)
+	r.Patch("/api/v1/me/profile", handler.UpdateProfile)
+	r.Put(middleware.AvatarUploadPath, handler.PutAvatar)
+	r.Delete(middleware.AvatarUploadPath, handler.DeleteAvatar)
 }
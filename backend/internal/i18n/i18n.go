@@ -0,0 +1,187 @@
+// Package i18n translates user-facing API messages. Messages are keyed by
+// their English text — the apierrors constants and the fixed details in
+// httputil — so call sites keep writing English and translation happens once,
+// where the error envelope is rendered. A key may contain %s placeholders;
+// a message that matches the key's shape is translated with the captured
+// values substituted into the translation in order.
+//
+// Unknown messages and unsupported locales fall back to the English text, so
+// a missing catalog entry degrades to today's behavior rather than an error.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Default is the locale of the source messages.
+const Default = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// supported lists the negotiable locales; Default must stay first so the
+// matcher falls back to it.
+var supported = []language.Tag{language.English, language.Spanish, language.French}
+
+var matcher = language.NewMatcher(supported)
+
+// template is a catalog key containing %s placeholders, compiled to a
+// pattern that captures each placeholder's value.
+type template struct {
+	key         string
+	pattern     *regexp.Regexp
+	translation string
+}
+
+type catalog struct {
+	exact     map[string]string
+	templates []template
+}
+
+var catalogs = mustLoad()
+
+func mustLoad() map[string]catalog {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	out := make(map[string]catalog, len(entries))
+	for _, e := range entries {
+		raw, err := localeFS.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var m map[string]string
+		if err := json.Unmarshal(raw, &m); err != nil {
+			panic(fmt.Sprintf("i18n: %s: %v", e.Name(), err))
+		}
+		c := catalog{exact: map[string]string{}}
+		for key, tr := range m {
+			if !strings.Contains(key, "%s") {
+				c.exact[key] = tr
+				continue
+			}
+			parts := strings.Split(key, "%s")
+			for i, p := range parts {
+				parts[i] = regexp.QuoteMeta(p)
+			}
+			c.templates = append(c.templates, template{
+				key:         key,
+				pattern:     regexp.MustCompile("^" + strings.Join(parts, "(.+?)") + "$"),
+				translation: tr,
+			})
+		}
+		// Longest key first, so "sent to %s. Please sign up…" wins over the
+		// shorter "sent to %s" whose trailing capture would also match it.
+		sort.Slice(c.templates, func(i, j int) bool {
+			a, b := c.templates[i].key, c.templates[j].key
+			if len(a) != len(b) {
+				return len(a) > len(b)
+			}
+			return a < b
+		})
+		out[strings.TrimSuffix(e.Name(), ".json")] = c
+	}
+	return out
+}
+
+// Supported returns the locales Negotiate can return, Default first.
+func Supported() []string {
+	out := make([]string, len(supported))
+	for i, t := range supported {
+		out[i] = t.String()
+	}
+	return out
+}
+
+// Negotiate picks the best supported locale for an Accept-Language header.
+// An empty or unparseable header yields Default.
+func Negotiate(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return Default
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, idx, conf := matcher.Match(tags...)
+	if conf == language.No {
+		return Default
+	}
+	return supported[idx].String()
+}
+
+// T translates msg into locale, returning msg unchanged when there is no
+// catalog entry for it.
+func T(locale, msg string) string {
+	c, ok := catalogs[locale]
+	if !ok || msg == "" {
+		return msg
+	}
+	if tr, ok := c.exact[msg]; ok {
+		return tr
+	}
+	for _, t := range c.templates {
+		m := t.pattern.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		// Captures are translated too, so a composed message such as
+		// "<field message> (and 2 more validation errors)" comes out fully
+		// localized; values with no entry (names, ids) pass through.
+		args := make([]any, len(m)-1)
+		for i, v := range m[1:] {
+			args[i] = T(locale, v)
+		}
+		return fmt.Sprintf(t.translation, args...)
+	}
+	return msg
+}
+
+// Tf translates format, a catalog key that may contain %s placeholders, and
+// substitutes args into the translation. Unlike T it never re-parses the
+// composed message, so argument values (user-supplied names) are neither
+// translated nor able to shift which placeholder a value lands in.
+func Tf(locale, format string, args ...any) string {
+	tr := format
+	if c, ok := catalogs[locale]; ok {
+		if v, ok := c.exact[format]; ok {
+			tr = v
+		} else {
+			for _, t := range c.templates {
+				if t.key == format {
+					tr = t.translation
+					break
+				}
+			}
+		}
+	}
+	if len(args) == 0 {
+		return tr
+	}
+	return fmt.Sprintf(tr, args...)
+}
+
+type ctxKey struct{}
+
+// WithLocale returns ctx carrying locale.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, locale)
+}
+
+// FromContext returns the request's negotiated locale, or Default.
+func FromContext(ctx context.Context) string {
+	if l, ok := ctx.Value(ctxKey{}).(string); ok && l != "" {
+		return l
+	}
+	return Default
+}
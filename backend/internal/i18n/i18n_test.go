@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                         "en",
+		"es":                       "es",
+		"es-MX,es;q=0.9,en;q=0.8":  "es",
+		"fr-CA":                    "fr",
+		"de-DE,fr;q=0.5":           "fr",
+		"de-DE":                    "en",
+		"en-GB,fr;q=0.9":           "en",
+		"not a language header;;;": "en",
+	}
+	for header, want := range cases {
+		assert.Equal(t, want, Negotiate(header), header)
+	}
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "Activo no encontrado", T("es", "Asset not found"))
+	assert.Equal(t, "Actif introuvable", T("fr", "Asset not found"))
+	assert.Equal(t, "Asset not found", T("en", "Asset not found"))
+	assert.Equal(t, "Asset not found", T("de", "Asset not found"))
+	assert.Equal(t, "no catalog entry", T("es", "no catalog entry"))
+}
+
+func TestT_Templates(t *testing.T) {
+	assert.Equal(t, "ID de activo no válido: abc", T("es", "Invalid Asset ID: abc"))
+	assert.Equal(t,
+		"Cette invitation a été envoyée à a@b.io. Veuillez vous inscrire avec cette adresse e-mail.",
+		T("fr", "This invitation was sent to a@b.io. Please sign up with that email address."))
+	assert.Equal(t,
+		"name es obligatorio (y 2 errores de validación más)",
+		T("es", "name is required (and 2 more validation errors)"))
+}
+
+func TestTf(t *testing.T) {
+	assert.Equal(t, "ID de activo no válido: abc", Tf("es", "Invalid Asset ID: %s", "abc"))
+	assert.Equal(t, "Invalid Asset ID: abc", Tf("de", "Invalid Asset ID: %s", "abc"))
+	assert.Equal(t, "Activo no encontrado", Tf("es", "Asset not found"))
+	// The argument is substituted verbatim, not translated.
+	assert.Equal(t, "ID de activo no válido: Asset not found", Tf("es", "Invalid Asset ID: %s", "Asset not found"))
+}
+
+func TestCatalogsCoverSameKeys(t *testing.T) {
+	es, fr := catalogs["es"], catalogs["fr"]
+	assert.Equal(t, len(es.exact), len(fr.exact))
+	assert.Equal(t, len(es.templates), len(fr.templates))
+	for k := range es.exact {
+		_, ok := fr.exact[k]
+		assert.True(t, ok, "fr catalog is missing %q", k)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Default, FromContext(context.Background()))
+	assert.Equal(t, "fr", FromContext(WithLocale(context.Background(), "fr")))
+}
@@ -27,5 +27,10 @@ var (
 	metricReadsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
 		Name: "ingest_reads_dropped_total",
 		Help: "Parsed reads dropped during derivation, by reason.",
-	}, []string{"reason"}) // no_scan_point, no_asset, conflict
+	}, []string{"reason"}) // no_scan_point, no_asset, not_valid, disposed, conflict
+
+	metricReadsDeferred = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ingest_reads_deferred_to_positioning_total",
+		Help: "BLE reads resolved but left to the positioning engine instead of written to asset_scans.",
+	})
 )
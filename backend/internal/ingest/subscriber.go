@@ -80,6 +80,13 @@ func (s *Subscriber) Unsubscribe(topic string) {
 	s.log.Info().Str("topic", topic).Msg("unsubscribed")
 }
 
+// Connected reports whether the broker connection is currently up. Paho
+// reconnects on its own, so this is a point-in-time view for the readiness
+// probe rather than a terminal state.
+func (s *Subscriber) Connected() bool {
+	return s.client != nil && s.client.IsConnected()
+}
+
 // Start begins connecting in the background and returns immediately — it never
 // blocks server startup on broker availability. ConnectRetry + AutoReconnect
 // keep the client trying until the broker is reachable, then OnConnect
@@ -224,6 +231,7 @@ func (s *Subscriber) handleMessage(_ mqtt.Client, m mqtt.Message) {
 	for reason, n := range res.Dropped {
 		metricReadsDropped.WithLabelValues(reason).Add(float64(n))
 	}
+	metricReadsDeferred.Add(float64(res.Deferred))
 
 	// 5. Geofence evaluation (TRA-901). Best-effort and outside the derivation
 	// transaction: a slow/failed alarm path must never lose a scan. Only the
@@ -245,6 +253,7 @@ func (s *Subscriber) logMessageProcessed(topic string, orgID int, res storage.Pe
 	s.log.Debug().
 		Str("topic", topic).Int("org_id", orgID).
 		Int("parsed", parsed).Int("inserted", res.Inserted).
+		Int("deferred", res.Deferred).
 		Interface("dropped", res.Dropped).
 		Msg("ingest message processed")
 }
@@ -0,0 +1,123 @@
+// Package jobs runs the backend's periodic background work (outbox drains,
+// webhook delivery, pruning) on one small framework instead of each feature
+// hand-rolling its own ticker goroutine. Every job gets the same lifecycle,
+// panic isolation, error backoff and metrics.
+//
+// Jobs are at-least-once by construction: a job's durable state lives in the
+// database (outbox rows, delivery rows), so a run that dies mid-way is simply
+// picked up by the next run — on this replica or another.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/errorreport"
+)
+
+// Func is one run of a job. A non-nil error backs the job off before its next
+// run; returning nil keeps the regular interval.
+type Func func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	maxWait  time.Duration
+	fn       Func
+}
+
+// Runner owns a set of periodic jobs. Register with Every, then Start; Stop
+// cancels every job and waits for in-flight runs to return.
+type Runner struct {
+	log  zerolog.Logger
+	jobs []job
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRunner builds an empty, stopped runner.
+func NewRunner(log *zerolog.Logger) *Runner {
+	return &Runner{log: log.With().Str("component", "jobs").Logger()}
+}
+
+// Every registers fn to run every interval. After a failed run the wait
+// doubles, up to the larger of 10×interval and one minute, and resets on the
+// next success. Must be called before Start.
+func (r *Runner) Every(name string, interval time.Duration, fn Func) {
+	r.jobs = append(r.jobs, job{
+		name:     name,
+		interval: interval,
+		maxWait:  max(10*interval, time.Minute),
+		fn:       fn,
+	})
+}
+
+// Start launches one goroutine per registered job.
+func (r *Runner) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+	for _, j := range r.jobs {
+		r.wg.Add(1)
+		go r.loop(ctx, j)
+	}
+	r.log.Info().Int("jobs", len(r.jobs)).Msg("job runner started")
+}
+
+// Stop cancels every job and waits for them to return. Safe to call without
+// Start.
+func (r *Runner) Stop() {
+	if r.cancel == nil {
+		return
+	}
+	r.cancel()
+	r.wg.Wait()
+}
+
+func (r *Runner) loop(ctx context.Context, j job) {
+	defer r.wg.Done()
+	log := r.log.With().Str("job", j.name).Logger()
+
+	wait := j.interval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		err := r.run(ctx, j)
+		if err == nil {
+			wait = j.interval
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		wait = min(wait*2, j.maxWait)
+		log.Warn().Err(err).Dur("retry_in", wait).Msg("job run failed")
+	}
+}
+
+// run executes one run of j, converting a panic into an error so a bad run
+// backs off like any other failure instead of killing the process.
+func (r *Runner) run(ctx context.Context, j job) (err error) {
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			metricPanics.WithLabelValues(j.name).Inc()
+			errorreport.Panic(ctx, p, errorreport.Tags{"job": j.name})
+			err = fmt.Errorf("panic: %v", p)
+		}
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		metricRuns.WithLabelValues(j.name, result).Inc()
+		metricDuration.WithLabelValues(j.name).Observe(time.Since(start).Seconds())
+	}()
+	return j.fn(ctx)
+}
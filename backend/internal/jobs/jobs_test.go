@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRunner() *Runner {
+	log := zerolog.New(io.Discard)
+	return NewRunner(&log)
+}
+
+func TestRunner_RunsJobRepeatedly(t *testing.T) {
+	r := testRunner()
+	var runs atomic.Int32
+	r.Every("tick", time.Millisecond, func(context.Context) error {
+		runs.Add(1)
+		return nil
+	})
+	r.Start(context.Background())
+	require.Eventually(t, func() bool { return runs.Load() >= 3 }, time.Second, time.Millisecond)
+	r.Stop()
+
+	after := runs.Load()
+	time.Sleep(5 * time.Millisecond)
+	assert.Equal(t, after, runs.Load(), "no runs after Stop returns")
+}
+
+func TestRunner_PanicIsRecoveredAndRetried(t *testing.T) {
+	r := testRunner()
+	var runs atomic.Int32
+	r.Every("flaky", time.Millisecond, func(context.Context) error {
+		if runs.Add(1) == 1 {
+			panic("boom")
+		}
+		return nil
+	})
+	r.Start(context.Background())
+	defer r.Stop()
+	require.Eventually(t, func() bool { return runs.Load() >= 2 }, time.Second, time.Millisecond,
+		"a panicking run must not stop the job")
+}
+
+func TestRunner_RunReportsErrors(t *testing.T) {
+	r := testRunner()
+	errBoom := errors.New("boom")
+	err := r.run(context.Background(), job{name: "x", fn: func(context.Context) error { return errBoom }})
+	assert.ErrorIs(t, err, errBoom)
+
+	err = r.run(context.Background(), job{name: "x", fn: func(context.Context) error { panic("p") }})
+	assert.EqualError(t, err, "panic: p")
+}
+
+func TestRunner_StopWithoutStart(t *testing.T) {
+	assert.NotPanics(t, testRunner().Stop)
+}
@@ -0,0 +1,24 @@
+package jobs
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "jobs_runs_total",
+		Help: "Background job runs, by job and result.",
+	}, []string{"job", "result"}) // ok, error
+
+	metricPanics = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "jobs_panics_total",
+		Help: "Background job runs that panicked (recovered and counted as errors).",
+	}, []string{"job"})
+
+	metricDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "jobs_run_duration_seconds",
+		Help:    "Background job run duration.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"job"})
+)
@@ -0,0 +1,118 @@
+// Package labels prints asset labels on networked Zebra printers. RenderZPL
+// turns assets into ZPL when a print job is queued; the Job's Run claims
+// queued jobs and streams their ZPL to the printer's raw-print port, putting
+// a job back in the queue with a backoff when the printer cannot be reached
+// and failing it once its attempts run out.
+//
+// A claim is a lease: a job whose replica dies mid-send is claimed again
+// once the lease runs out, so a label can be printed twice but a job is
+// never lost.
+package labels
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+)
+
+const (
+	// claimLease must outlast the sender's connect and write timeouts.
+	claimLease = 2 * time.Minute
+	// MaxAttempts is how many times a job is sent before it fails.
+	MaxAttempts = 5
+	// retryBase is the wait after the first failed attempt; each later one
+	// doubles it.
+	retryBase = 30 * time.Second
+	// maxErrorLen bounds the error text kept on a job.
+	maxErrorLen = 1024
+)
+
+// Store is the storage surface the job needs; *storage.Storage satisfies it.
+type Store interface {
+	ClaimPrintJob(ctx context.Context, lease time.Duration) (*labelprint.Job, error)
+	GetLabelPrinter(ctx context.Context, orgID, id int) (*labelprint.Printer, error)
+	CompletePrintJob(ctx context.Context, id int) error
+	RetryPrintJob(ctx context.Context, id int, msg string, at time.Time) error
+	FailPrintJob(ctx context.Context, id int, msg string) error
+}
+
+// Job is the label_print job.
+type Job struct {
+	store  Store
+	sender Sender
+	log    zerolog.Logger
+	now    func() time.Time
+}
+
+// NewJob builds the print job over store, sending with sender.
+func NewJob(store Store, sender Sender, log *zerolog.Logger) *Job {
+	return &Job{
+		store:  store,
+		sender: sender,
+		log:    log.With().Str("component", "labels").Logger(),
+		now:    time.Now,
+	}
+}
+
+// Run is the job run: send queued jobs until none are due. A job's own
+// failure is recorded on it, not returned; only storage errors fail the run.
+func (j *Job) Run(ctx context.Context) error {
+	for {
+		job, err := j.store.ClaimPrintJob(ctx, claimLease)
+		if err != nil {
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+		if err := j.print(ctx, *job); err != nil {
+			return err
+		}
+	}
+}
+
+func (j *Job) print(ctx context.Context, job labelprint.Job) error {
+	printer, err := j.store.GetLabelPrinter(ctx, job.OrgID, job.PrinterID)
+	if err != nil {
+		return err
+	}
+	if printer == nil || !printer.IsActive {
+		return j.fail(ctx, job, "printer is inactive or no longer exists")
+	}
+
+	if err := j.sender.Send(ctx, printer.Addr(), job.ZPL); err != nil {
+		if ctx.Err() != nil {
+			// Shutting down: leave the claim to lapse so another run retries.
+			return ctx.Err()
+		}
+		metricSendErrors.Inc()
+		msg := truncate(err.Error())
+		if job.Attempts >= MaxAttempts {
+			return j.fail(ctx, job, msg)
+		}
+		j.log.Info().Err(err).Int("print_job_id", job.ID).Int("attempt", job.Attempts).Msg("printer unreachable, will retry")
+		return j.store.RetryPrintJob(ctx, job.ID, msg, j.now().Add(retryBase<<max(job.Attempts-1, 0)))
+	}
+
+	metricJobs.WithLabelValues(labelprint.StatusCompleted).Inc()
+	metricLabels.Add(float64(job.LabelCount))
+	j.log.Info().Int("print_job_id", job.ID).Int("org_id", job.OrgID).Int("printer_id", printer.ID).
+		Int("labels", job.LabelCount).Msg("print job sent")
+	return j.store.CompletePrintJob(ctx, job.ID)
+}
+
+func (j *Job) fail(ctx context.Context, job labelprint.Job, msg string) error {
+	metricJobs.WithLabelValues(labelprint.StatusFailed).Inc()
+	j.log.Warn().Int("print_job_id", job.ID).Int("org_id", job.OrgID).Str("error", msg).Msg("print job failed")
+	return j.store.FailPrintJob(ctx, job.ID, msg)
+}
+
+func truncate(msg string) string {
+	if len(msg) > maxErrorLen {
+		return msg[:maxErrorLen]
+	}
+	return msg
+}
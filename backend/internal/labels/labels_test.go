@@ -0,0 +1,221 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+)
+
+type fakeStore struct {
+	jobs      []labelprint.Job
+	printers  map[int]*labelprint.Printer
+	completed []int
+	retried   map[int]time.Time
+	failed    map[int]string
+}
+
+func newFakeStore(jobs ...labelprint.Job) *fakeStore {
+	return &fakeStore{
+		jobs: jobs,
+		printers: map[int]*labelprint.Printer{
+			7: {ID: 7, Host: "10.0.0.5", Port: 9100, IsActive: true},
+		},
+		retried: map[int]time.Time{},
+		failed:  map[int]string{},
+	}
+}
+
+func (f *fakeStore) ClaimPrintJob(context.Context, time.Duration) (*labelprint.Job, error) {
+	if len(f.jobs) == 0 {
+		return nil, nil
+	}
+	j := f.jobs[0]
+	f.jobs = f.jobs[1:]
+	j.Attempts++
+	return &j, nil
+}
+
+func (f *fakeStore) GetLabelPrinter(_ context.Context, _ int, id int) (*labelprint.Printer, error) {
+	return f.printers[id], nil
+}
+
+func (f *fakeStore) CompletePrintJob(_ context.Context, id int) error {
+	f.completed = append(f.completed, id)
+	return nil
+}
+
+func (f *fakeStore) RetryPrintJob(_ context.Context, id int, _ string, at time.Time) error {
+	f.retried[id] = at
+	return nil
+}
+
+func (f *fakeStore) FailPrintJob(_ context.Context, id int, msg string) error {
+	f.failed[id] = msg
+	return nil
+}
+
+type fakeSender struct {
+	sent map[string]string
+	err  error
+}
+
+func (s *fakeSender) Send(_ context.Context, addr, zpl string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent[addr] = zpl
+	return nil
+}
+
+func newTestJob(store Store, sender Sender, now time.Time) *Job {
+	log := zerolog.Nop()
+	j := NewJob(store, sender, &log)
+	j.now = func() time.Time { return now }
+	return j
+}
+
+func TestRun_SendsQueuedJobs(t *testing.T) {
+	store := newFakeStore(labelprint.Job{ID: 1, OrgID: 3, PrinterID: 7, ZPL: "^XA^XZ", LabelCount: 1})
+	sender := &fakeSender{sent: map[string]string{}}
+
+	require.NoError(t, newTestJob(store, sender, time.Now()).Run(context.Background()))
+
+	assert.Equal(t, []int{1}, store.completed)
+	assert.Equal(t, "^XA^XZ", sender.sent["10.0.0.5:9100"])
+}
+
+func TestRun_UnreachablePrinterIsRetriedWithBackoff(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	store := newFakeStore(
+		labelprint.Job{ID: 1, PrinterID: 7},
+		labelprint.Job{ID: 2, PrinterID: 7, Attempts: 2},
+	)
+	sender := &fakeSender{err: errors.New("connection refused")}
+
+	require.NoError(t, newTestJob(store, sender, now).Run(context.Background()))
+
+	assert.Equal(t, now.Add(30*time.Second), store.retried[1])
+	assert.Equal(t, now.Add(2*time.Minute), store.retried[2])
+	assert.Empty(t, store.completed)
+}
+
+func TestRun_FailsAfterLastAttempt(t *testing.T) {
+	store := newFakeStore(labelprint.Job{ID: 1, PrinterID: 7, Attempts: MaxAttempts - 1})
+	sender := &fakeSender{err: errors.New("connection refused")}
+
+	require.NoError(t, newTestJob(store, sender, time.Now()).Run(context.Background()))
+
+	assert.Equal(t, "connection refused", store.failed[1])
+	assert.Empty(t, store.retried)
+}
+
+func TestRun_InactiveOrMissingPrinterFailsTheJob(t *testing.T) {
+	store := newFakeStore(
+		labelprint.Job{ID: 1, PrinterID: 7},
+		labelprint.Job{ID: 2, PrinterID: 99},
+	)
+	store.printers[7].IsActive = false
+	sender := &fakeSender{sent: map[string]string{}}
+
+	require.NoError(t, newTestJob(store, sender, time.Now()).Run(context.Background()))
+
+	assert.Contains(t, store.failed, 1)
+	assert.Contains(t, store.failed, 2)
+	assert.Empty(t, sender.sent)
+}
+
+var testPolicy = Policy{PrivateNetworks: []netip.Prefix{netip.MustParsePrefix("10.20.0.0/16")}, Ports: []int{9100}}
+
+func TestTCPSender_RefusesLoopback(t *testing.T) {
+	err := NewTCPSender(time.Second, testPolicy).Send(context.Background(), "127.0.0.1:9100", "^XA^XZ")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotPrinterAddr)
+}
+
+func TestTCPSender_RefusesUnlistedPrivateNetworkAndPort(t *testing.T) {
+	// 10.30.0.5 is private but outside the policy's printer networks; 6379
+	// is Redis, not a raw-print port. Both fail before any connection.
+	err := NewTCPSender(time.Second, testPolicy).Send(context.Background(), "10.30.0.5:9100", "^XA^XZ")
+	assert.ErrorIs(t, err, ErrNotPrinterAddr)
+	err = NewTCPSender(time.Second, testPolicy).Send(context.Background(), "10.20.0.31:6379", "^XA^XZ")
+	assert.ErrorIs(t, err, ErrNotPrinterPort)
+}
+
+func TestPolicy_AllowAddr(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"203.0.113.9":      true,
+		"93.184.216.34":    true,
+		"10.20.0.31":       true,
+		"10.30.0.5":        false,
+		"192.168.1.50":     false,
+		"172.16.0.10":      false,
+		"100.100.100.200":  false,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"169.254.169.254":  false,
+		"0.0.0.0":          false,
+		"224.0.0.1":        false,
+		"::ffff:127.0.0.1": false,
+		"::ffff:10.20.0.9": true,
+	} {
+		assert.Equal(t, want, testPolicy.AllowAddr(netip.MustParseAddr(addr)), addr)
+	}
+	assert.False(t, DefaultPolicy().AllowAddr(netip.MustParseAddr("10.20.0.31")), "no printer networks by default")
+}
+
+func TestPolicy_CheckHost(t *testing.T) {
+	defer func(orig func(context.Context, string, string) ([]netip.Addr, error)) { lookup = orig }(lookup)
+	lookup = func(_ context.Context, _, host string) ([]netip.Addr, error) {
+		switch host {
+		case "zebra-dock3.corp":
+			return []netip.Addr{netip.MustParseAddr("10.20.0.31")}, nil
+		case "redis.corp":
+			return []netip.Addr{netip.MustParseAddr("10.40.0.7")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	for host, wantErr := range map[string]bool{
+		"10.20.0.31":        false,
+		"zebra-dock3.corp":  false,
+		"unresolvable.corp": false,
+		"10.40.0.7":         true,
+		"redis.corp":        true,
+		"localhost":         true,
+		"169.254.169.254":   true,
+	} {
+		err := testPolicy.CheckHost(context.Background(), host)
+		assert.Equal(t, wantErr, err != nil, "%s: %v", host, err)
+	}
+}
+
+func TestPolicyFromEnv(t *testing.T) {
+	t.Setenv("LABEL_PRINTER_NETWORKS", "")
+	t.Setenv("LABEL_PRINTER_PORTS", "")
+	p, err := PolicyFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPolicy(), p)
+
+	t.Setenv("LABEL_PRINTER_NETWORKS", "10.20.0.0/16, 192.168.50.7/24")
+	t.Setenv("LABEL_PRINTER_PORTS", "9100,6101")
+	p, err = PolicyFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{netip.MustParsePrefix("10.20.0.0/16"), netip.MustParsePrefix("192.168.50.0/24")}, p.PrivateNetworks)
+	assert.Equal(t, []int{9100, 6101}, p.Ports)
+
+	t.Setenv("LABEL_PRINTER_PORTS", "9100,jetdirect")
+	_, err = PolicyFromEnv()
+	assert.Error(t, err)
+	t.Setenv("LABEL_PRINTER_PORTS", "")
+	t.Setenv("LABEL_PRINTER_NETWORKS", "10.20.0.0")
+	_, err = PolicyFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,23 @@
+package labels
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricJobs = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "label_print_jobs_total",
+		Help: "Label print jobs finished, by outcome.",
+	}, []string{"status"}) // status: completed, failed
+
+	metricSendErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "label_print_send_errors_total",
+		Help: "Attempts to send a print job that could not reach the printer.",
+	})
+
+	metricLabels = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "label_print_labels_total",
+		Help: "Labels sent to printers, copies included.",
+	})
+)
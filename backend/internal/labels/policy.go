@@ -0,0 +1,143 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+	"github.com/trakrf/platform/backend/internal/util/netguard"
+)
+
+// ErrNotPrinterAddr is returned for a printer host outside the deployment's
+// printer networks.
+var ErrNotPrinterAddr = errors.New("must be a public address or on one of the deployment's printer networks")
+
+// ErrNotPrinterPort is returned for a port that is not a raw-print port.
+var ErrNotPrinterPort = errors.New("is not one of the deployment's raw-print ports")
+
+// lookupTimeout bounds the save-time resolution of a printer's host.
+const lookupTimeout = 3 * time.Second
+
+// lookup resolves a host; tests replace it.
+var lookup = net.DefaultResolver.LookupNetIP
+
+// Policy is where the deployment lets printers be. A printer's host is
+// org-supplied and the job writes tenant-controlled bytes to it, so it gets
+// the webhook rule (netguard): public addresses only, unless the deployment
+// names the private networks its printers sit on. Loopback, link-local and
+// multicast addresses are refused even then.
+type Policy struct {
+	// PrivateNetworks are the non-public ranges printers may be on
+	// (LABEL_PRINTER_NETWORKS).
+	PrivateNetworks []netip.Prefix
+	// Ports are the raw-print ports a printer may listen on
+	// (LABEL_PRINTER_PORTS).
+	Ports []int
+}
+
+// DefaultPolicy allows public printers on the JetDirect port only.
+func DefaultPolicy() Policy {
+	return Policy{Ports: []int{labelprint.DefaultPort}}
+}
+
+// PolicyFromEnv reads the printer policy:
+//
+//	LABEL_PRINTER_NETWORKS  comma-separated CIDRs printers may be on besides public addresses (default none)
+//	LABEL_PRINTER_PORTS     comma-separated raw-print ports (default 9100)
+func PolicyFromEnv() (Policy, error) {
+	p := DefaultPolicy()
+	for _, raw := range strings.Split(os.Getenv("LABEL_PRINTER_NETWORKS"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		prefix, err := netip.ParsePrefix(raw)
+		if err != nil {
+			return Policy{}, fmt.Errorf("LABEL_PRINTER_NETWORKS: %w", err)
+		}
+		p.PrivateNetworks = append(p.PrivateNetworks, prefix.Masked())
+	}
+	if raw := strings.TrimSpace(os.Getenv("LABEL_PRINTER_PORTS")); raw != "" {
+		p.Ports = nil
+		for _, s := range strings.Split(raw, ",") {
+			port, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || port < 1 || port > 65535 {
+				return Policy{}, fmt.Errorf("LABEL_PRINTER_PORTS: %q is not a port", s)
+			}
+			p.Ports = append(p.Ports, port)
+		}
+	}
+	return p, nil
+}
+
+// AllowAddr reports whether a printer can be at ip.
+func (p Policy) AllowAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if netguard.PublicAddr(ip) {
+		return true
+	}
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	return slices.ContainsFunc(p.PrivateNetworks, func(n netip.Prefix) bool { return n.Contains(ip) })
+}
+
+// AllowPort reports whether a printer can listen on port.
+func (p Policy) AllowPort(port int) bool {
+	return slices.Contains(p.Ports, port)
+}
+
+// CheckHost validates a printer's host when it is saved: an address, or the
+// addresses a name resolves to, that AllowAddr accepts. A name that does not
+// resolve right now is accepted; the sender's dial check still applies.
+func (p Policy) CheckHost(ctx context.Context, host string) error {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !p.AllowAddr(ip) {
+			return ErrNotPrinterAddr
+		}
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrNotPrinterAddr
+	}
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+	addrs, err := lookup(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range addrs {
+		if !p.AllowAddr(ip) {
+			return ErrNotPrinterAddr
+		}
+	}
+	return nil
+}
+
+// control is the sender's net.Dialer Control hook. It runs on the resolved
+// address, so a name that re-resolves elsewhere after the printer was saved
+// is still refused.
+func (p Policy) control(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !p.AllowAddr(ap.Addr()) {
+		return fmt.Errorf("refusing to connect to %s: %w", ap.Addr(), ErrNotPrinterAddr)
+	}
+	if !p.AllowPort(int(ap.Port())) {
+		return fmt.Errorf("refusing to connect to port %d: %w", ap.Port(), ErrNotPrinterPort)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package labels
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Sender delivers a job's ZPL to a printer.
+type Sender interface {
+	Send(ctx context.Context, addr, zpl string) error
+}
+
+// TCPSender writes ZPL to a printer's raw-print port, the way Zebra network
+// printers take jobs: open a connection, write, close. It only connects to
+// addresses and ports its Policy allows, so a printer's host cannot be
+// pointed at the server's own network or the cloud metadata service.
+type TCPSender struct {
+	Timeout time.Duration
+	Policy  Policy
+}
+
+// NewTCPSender returns a TCPSender that gives up on a printer after timeout,
+// for connecting and for writing alike.
+func NewTCPSender(timeout time.Duration, policy Policy) *TCPSender {
+	return &TCPSender{Timeout: timeout, Policy: policy}
+}
+
+// Send implements Sender.
+func (s *TCPSender) Send(ctx context.Context, addr, zpl string) error {
+	d := net.Dialer{Timeout: s.Timeout, Control: s.Policy.control}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("connect to printer: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.SetWriteDeadline(time.Now().Add(s.Timeout)); err != nil {
+		return fmt.Errorf("write to printer: %w", err)
+	}
+	if _, err := conn.Write([]byte(zpl)); err != nil {
+		return fmt.Errorf("write to printer: %w", err)
+	}
+	if err := conn.Close(); err != nil {
+		return fmt.Errorf("write to printer: %w", err)
+	}
+	return nil
+}
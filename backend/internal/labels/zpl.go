@@ -0,0 +1,79 @@
+package labels
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+)
+
+// Label geometry at 203 dpi, the Zebra default; other resolutions scale it.
+// A label is 2" x 1": the asset name on top, a Code 128 barcode of the
+// external key (with its text) below.
+const (
+	baseDPI       = 203
+	labelWidthIn  = 2
+	labelHeightIn = 1
+	marginDots    = 16
+	nameFontDots  = 30
+	moduleDots    = 2
+	barcodeDots   = 80
+)
+
+// RenderZPL returns the ZPL for one label per asset, each printed copies
+// times. When encodeRFID is set, an asset whose RFID EPC is a valid hex
+// word string has it written to the label's inlay; other labels are printed
+// without encoding.
+func RenderZPL(assets []labelprint.LabelAsset, dpi int, encodeRFID bool, copies int) string {
+	if dpi <= 0 {
+		dpi = baseDPI
+	}
+	if copies < 1 {
+		copies = 1
+	}
+	scale := func(dots int) int { return (dots*dpi + baseDPI/2) / baseDPI }
+	width, height := labelWidthIn*dpi, labelHeightIn*dpi
+	margin, font := scale(marginDots), scale(nameFontDots)
+	module := max(scale(moduleDots), 1)
+
+	var b strings.Builder
+	for _, a := range assets {
+		b.WriteString("^XA\n^CI28\n")
+		fmt.Fprintf(&b, "^PW%d\n^LL%d\n", width, height)
+		fmt.Fprintf(&b, "^FO%d,%d^A0N,%d,%d^FB%d,1,0,L^FH^FD%s^FS\n",
+			margin, margin, font, font, width-2*margin, fieldData(a.Name))
+		fmt.Fprintf(&b, "^FO%d,%d^BY%d^BCN,%d,Y,N,N^FH^FD%s^FS\n",
+			margin, 2*margin+font, module, scale(barcodeDots), fieldData(a.ExternalKey))
+		if encodeRFID && a.EPC != nil && ValidEPC(*a.EPC) {
+			fmt.Fprintf(&b, "^RFW,H^FD%s^FS\n", strings.ToUpper(*a.EPC))
+		}
+		fmt.Fprintf(&b, "^PQ%d\n^XZ\n", copies)
+	}
+	return b.String()
+}
+
+// ValidEPC reports whether epc can be written to an inlay: hex, a whole
+// number of 16-bit words, at most 496 bits (the largest EPC bank).
+func ValidEPC(epc string) bool {
+	if epc == "" || len(epc)%4 != 0 || len(epc) > 124 {
+		return false
+	}
+	for _, c := range epc {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldData escapes s for a ^FH field: the command prefixes and the hex
+// indicator itself are written as _XX, and line breaks become spaces.
+func fieldData(s string) string {
+	return strings.NewReplacer(
+		"_", "_5F",
+		"^", "_5E",
+		"~", "_7E",
+		"\r", " ",
+		"\n", " ",
+	).Replace(s)
+}
@@ -0,0 +1,60 @@
+package labels
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestRenderZPL_OneLabelPerAsset(t *testing.T) {
+	zpl := RenderZPL([]labelprint.LabelAsset{
+		{ID: 1, ExternalKey: "ASSET-0001", Name: "Forklift"},
+		{ID: 2, ExternalKey: "ASSET-0002", Name: "Pallet jack"},
+	}, 203, false, 3)
+
+	assert.Equal(t, 2, strings.Count(zpl, "^XA"))
+	assert.Equal(t, 2, strings.Count(zpl, "^XZ"))
+	assert.Equal(t, 2, strings.Count(zpl, "^PQ3\n"))
+	assert.Contains(t, zpl, "^PW406\n^LL203\n")
+	assert.Contains(t, zpl, "^FH^FDForklift^FS")
+	assert.Contains(t, zpl, "^BCN,80,Y,N,N^FH^FDASSET-0002^FS")
+	assert.NotContains(t, zpl, "^RFW")
+}
+
+func TestRenderZPL_ScalesWithDPI(t *testing.T) {
+	zpl := RenderZPL([]labelprint.LabelAsset{{ExternalKey: "K", Name: "N"}}, 600, false, 1)
+	assert.Contains(t, zpl, "^PW1200\n^LL600\n")
+	assert.Contains(t, zpl, "^A0N,89,89")
+	assert.Contains(t, zpl, "^BY6^BCN,236,")
+}
+
+func TestRenderZPL_EscapesFieldData(t *testing.T) {
+	zpl := RenderZPL([]labelprint.LabelAsset{{ExternalKey: "A_1", Name: "^XZ~JR\nnext"}}, 203, false, 1)
+	assert.Contains(t, zpl, "^FD_5EXZ_7EJR next^FS")
+	assert.Contains(t, zpl, "^FDA_5F1^FS")
+	assert.Equal(t, 1, strings.Count(zpl, "^XZ"), "a name must not end the label")
+}
+
+func TestRenderZPL_EncodesValidEPCs(t *testing.T) {
+	zpl := RenderZPL([]labelprint.LabelAsset{
+		{ExternalKey: "A", Name: "a", EPC: ptr("e28011700000020f3c4a5b6d")},
+		{ExternalKey: "B", Name: "b", EPC: ptr("not hex")},
+		{ExternalKey: "C", Name: "c"},
+	}, 203, true, 1)
+	assert.Equal(t, 1, strings.Count(zpl, "^RFW"))
+	assert.Contains(t, zpl, "^RFW,H^FDE28011700000020F3C4A5B6D^FS")
+}
+
+func TestValidEPC(t *testing.T) {
+	assert.True(t, ValidEPC("3034257BF7194E4000001A85"))
+	assert.True(t, ValidEPC("abcd"))
+	assert.False(t, ValidEPC(""))
+	assert.False(t, ValidEPC("abc"), "not a whole word")
+	assert.False(t, ValidEPC("zzzz"))
+	assert.False(t, ValidEPC(strings.Repeat("0", 128)), "larger than the EPC bank")
+}
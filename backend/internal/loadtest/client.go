@@ -0,0 +1,89 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+)
+
+// Client is the target API and the credentials the scenarios send.
+type Client struct {
+	BaseURL string
+	// Token is sent as the bearer token: a session JWT, since bulk import
+	// is session-only.
+	Token string
+	// ReaderKey is a registered reader credential, sent on scan requests,
+	// which a session alone may not make.
+	ReaderKey string
+	HTTP      *http.Client
+}
+
+// NewClient returns a Client for baseURL whose requests time out after
+// timeout. Idle connections are kept per worker, as a real client fleet
+// keeps them.
+func NewClient(baseURL, token, readerKey string, timeout time.Duration, workers int) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = workers
+	return &Client{
+		BaseURL:   strings.TrimRight(baseURL, "/"),
+		Token:     token,
+		ReaderKey: readerKey,
+		HTTP:      &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// NewRequest builds an authenticated request for path under BaseURL.
+func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// newScanRequest is NewRequest carrying the reader credential, when set.
+func (c *Client) newScanRequest(ctx context.Context, path string, body []byte) (*http.Request, error) {
+	req, err := c.NewRequest(ctx, http.MethodPost, path, bytes.NewReader(body), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	if c.ReaderKey != "" {
+		req.Header.Set(middleware.ReaderKeyHeader, c.ReaderKey)
+	}
+	return req, nil
+}
+
+// postJSON posts v to path for scenario setup, failing on any non-2xx.
+func (c *Client) postJSON(ctx context.Context, path string, v any) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := c.NewRequest(ctx, http.MethodPost, path, bytes.NewReader(body), "application/json")
+	if err != nil {
+		return err
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST %s: %s: %s", path, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+// Package loadtest drives the API hot paths at a constant request rate and
+// reports their latency percentiles, so a regression shows up as a blown
+// p95 budget before a deploy rather than as a slow production.
+//
+// It is a small vegeta-style driver: a pacer issues requests at Config.Rate
+// for Config.Duration onto a fixed pool of workers, and every response's
+// latency and status are collected into a Result. A tick that finds every
+// worker busy is dropped rather than queued, so an overloaded server shows
+// up as drops instead of as latency hidden in the generator. Scenarios
+// (see scenarios.go) build the requests; the load-test command runs them
+// and fails when one is over budget.
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Scenario is one hot path under load.
+type Scenario struct {
+	Name string
+	// P95Budget is the 95th-percentile latency the scenario must stay
+	// under for the run to pass.
+	P95Budget time.Duration
+	// Setup creates the fixtures the requests need, once before the run.
+	// It may be nil.
+	Setup func(ctx context.Context, c *Client) error
+	// Request builds the i-th request of the run.
+	Request func(ctx context.Context, c *Client, i int) (*http.Request, error)
+}
+
+// Config shapes a run.
+type Config struct {
+	// Rate is the number of requests started per second.
+	Rate int
+	// Duration is how long requests are started for; in-flight ones are
+	// then waited for.
+	Duration time.Duration
+	// Workers caps the requests in flight at once.
+	Workers int
+}
+
+// DefaultConfig is a short run well under the current capacity cliff
+// (see load-tests/README.md), suited to a pre-deploy gate.
+func DefaultConfig() Config {
+	return Config{Rate: 20, Duration: 30 * time.Second, Workers: 50}
+}
+
+// Check reports whether c can drive a run.
+func (c Config) Check() error {
+	switch {
+	case c.Rate < 1:
+		return fmt.Errorf("rate must be at least 1 request per second, got %d", c.Rate)
+	case c.Duration <= 0:
+		return fmt.Errorf("duration must be positive, got %s", c.Duration)
+	case c.Workers < 1:
+		return fmt.Errorf("workers must be at least 1, got %d", c.Workers)
+	}
+	return nil
+}
+
+// sample is one request's outcome. status is 0 when no response came back.
+type sample struct {
+	latency time.Duration
+	status  int
+	err     error
+}
+
+// Run drives sc against c as cfg describes and summarizes the outcome. An
+// error means the run could not start (bad config, failed setup); failed
+// requests are counted in the Result instead.
+func Run(ctx context.Context, c *Client, sc Scenario, cfg Config) (Result, error) {
+	if err := cfg.Check(); err != nil {
+		return Result{}, err
+	}
+	if sc.Setup != nil {
+		if err := sc.Setup(ctx, c); err != nil {
+			return Result{}, fmt.Errorf("%s setup: %w", sc.Name, err)
+		}
+	}
+
+	jobs := make(chan int)
+	samples := make(chan sample, cfg.Workers)
+	var wg sync.WaitGroup
+	for range cfg.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				samples <- hit(ctx, c, sc, i)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(samples)
+	}()
+
+	dropped := make(chan int, 1)
+	go func() {
+		dropped <- pace(ctx, jobs, cfg)
+	}()
+
+	var all []sample
+	for s := range samples {
+		all = append(all, s)
+	}
+	return summarize(sc, all, <-dropped), nil
+}
+
+// pace hands out request numbers at cfg.Rate for cfg.Duration, dropping a
+// tick no worker is free for, then closes jobs. It returns the drop count.
+func pace(ctx context.Context, jobs chan<- int, cfg Config) int {
+	defer close(jobs)
+	ticker := time.NewTicker(time.Second / time.Duration(cfg.Rate))
+	defer ticker.Stop()
+	deadline := time.NewTimer(cfg.Duration)
+	defer deadline.Stop()
+
+	dropped := 0
+	for i := 0; ; {
+		select {
+		case <-ctx.Done():
+			return dropped
+		case <-deadline.C:
+			return dropped
+		case <-ticker.C:
+			select {
+			case jobs <- i:
+				i++
+			default:
+				dropped++
+			}
+		}
+	}
+}
+
+// hit sends the i-th request of sc and times it to the end of the body.
+func hit(ctx context.Context, c *Client, sc Scenario, i int) sample {
+	req, err := sc.Request(ctx, c, i)
+	if err != nil {
+		return sample{err: err}
+	}
+	start := time.Now()
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return sample{latency: time.Since(start), err: err}
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return sample{latency: time.Since(start), status: resp.StatusCode, err: err}
+}
+
+// Result is a scenario run's outcome. Latencies cover every request that got
+// a response, successful or not.
+type Result struct {
+	Scenario  string
+	Requests  int
+	Errors    int
+	Dropped   int
+	Statuses  map[int]int
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	Max       time.Duration
+	P95Budget time.Duration
+}
+
+// ErrorRate is the share of attempted requests, dropped ones included, that
+// did not get a 2xx.
+func (r Result) ErrorRate() float64 {
+	attempts := r.Requests + r.Dropped
+	if attempts == 0 {
+		return 0
+	}
+	return float64(r.Errors+r.Dropped) / float64(attempts)
+}
+
+func summarize(sc Scenario, samples []sample, dropped int) Result {
+	r := Result{
+		Scenario:  sc.Name,
+		Requests:  len(samples),
+		Dropped:   dropped,
+		Statuses:  map[int]int{},
+		P95Budget: sc.P95Budget,
+	}
+	var latencies []time.Duration
+	for _, s := range samples {
+		if s.status != 0 {
+			r.Statuses[s.status]++
+			latencies = append(latencies, s.latency)
+		}
+		if s.err != nil || s.status < 200 || s.status > 299 {
+			r.Errors++
+		}
+	}
+	slices.Sort(latencies)
+	r.P50 = percentile(latencies, 50)
+	r.P95 = percentile(latencies, 95)
+	r.P99 = percentile(latencies, 99)
+	if len(latencies) > 0 {
+		r.Max = latencies[len(latencies)-1]
+	}
+	return r
+}
+
+// percentile is the nearest-rank p-th percentile of sorted, zero when it is
+// empty.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
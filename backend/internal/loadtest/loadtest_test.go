@@ -0,0 +1,149 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/middleware"
+)
+
+func TestRun_CountsResponses(t *testing.T) {
+	var hits atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer tok", r.Header.Get("Authorization"))
+		// Every fifth request fails.
+		if hits.Add(1)%5 == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	setups := 0
+	sc := Scenario{
+		Name:      "ping",
+		P95Budget: time.Second,
+		Setup: func(context.Context, *Client) error {
+			setups++
+			return nil
+		},
+		Request: func(ctx context.Context, c *Client, _ int) (*http.Request, error) {
+			return c.NewRequest(ctx, http.MethodGet, "/ping", nil, "")
+		},
+	}
+	c := NewClient(srv.URL+"/", "tok", "", time.Second, 4)
+	r, err := Run(context.Background(), c, sc, Config{Rate: 200, Duration: 200 * time.Millisecond, Workers: 4})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, setups)
+	assert.Equal(t, "ping", r.Scenario)
+	assert.Greater(t, r.Requests, 10)
+	assert.Equal(t, int(hits.Load()), r.Requests)
+	assert.Equal(t, r.Requests, r.Statuses[200]+r.Statuses[500])
+	assert.Equal(t, r.Statuses[500], r.Errors)
+	assert.LessOrEqual(t, r.P50, r.P95)
+	assert.LessOrEqual(t, r.P95, r.Max)
+}
+
+func TestRun_RejectsBadConfig(t *testing.T) {
+	_, err := Run(context.Background(), NewClient("http://x", "", "", time.Second, 1), AssetList(), Config{Rate: 0, Duration: time.Second, Workers: 1})
+	require.Error(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	assert.Equal(t, 95*time.Millisecond, percentile(sorted, 95))
+	assert.Equal(t, 100*time.Millisecond, percentile(sorted, 100))
+	assert.Equal(t, 7*time.Millisecond, percentile([]time.Duration{7 * time.Millisecond}, 95))
+	assert.Zero(t, percentile(nil, 95))
+}
+
+func TestResultCheck(t *testing.T) {
+	ok := Result{Scenario: "s", Requests: 100, P95: 100 * time.Millisecond, P95Budget: 500 * time.Millisecond}
+	assert.NoError(t, ok.Check())
+
+	slow := ok
+	slow.P95 = 600 * time.Millisecond
+	assert.ErrorContains(t, slow.Check(), "p95")
+
+	failing := ok
+	failing.Errors = 2
+	assert.ErrorContains(t, failing.Check(), "error rate")
+
+	overloaded := ok
+	overloaded.Dropped = 5
+	assert.ErrorContains(t, overloaded.Check(), "error rate", "dropped requests count against the error budget")
+}
+
+func TestScanIngest_Request(t *testing.T) {
+	c := NewClient("http://api", "tok", "reader-secret", time.Second, 1)
+	req, err := ScanIngest("run1").Request(context.Background(), c, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/api/v1/inventory/save", req.URL.Path)
+	assert.Equal(t, "reader-secret", req.Header.Get(middleware.ReaderKeyHeader))
+	var body struct {
+		Location string   `json:"location_identifier"`
+		Assets   []string `json:"asset_identifiers"`
+	}
+	require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+	assert.Equal(t, "lt-run1-loc", body.Location)
+	assert.Len(t, body.Assets, ScanBatch)
+}
+
+func TestBulkImport_Request(t *testing.T) {
+	c := NewClient("http://api", "tok", "", time.Second, 1)
+	req, err := BulkImport("run1").Request(context.Background(), c, 3)
+	require.NoError(t, err)
+
+	require.NoError(t, req.ParseMultipartForm(1<<20))
+	f, header, err := req.FormFile("file")
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(header.Filename, ".csv"))
+	assert.Equal(t, "text/csv", header.Header.Get("Content-Type"))
+
+	raw, err := io.ReadAll(f)
+	require.NoError(t, err)
+	rows, err := csv.NewReader(bytes.NewReader(raw)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, ImportRows+1)
+	assert.Equal(t, []string{"external_key", "name"}, rows[0])
+	assert.Equal(t, "lt-run1-imp-3-000", rows[1][0])
+}
+
+func TestWriteReports(t *testing.T) {
+	results := []Result{{
+		Scenario: "asset_list", Requests: 10, Statuses: map[int]int{200: 10},
+		P50: 12 * time.Millisecond, P95: 40 * time.Millisecond, P95Budget: 500 * time.Millisecond,
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, WriteTable(&table, results))
+	assert.Contains(t, table.String(), "asset_list")
+	assert.Contains(t, table.String(), "40.0ms")
+
+	var out bytes.Buffer
+	require.NoError(t, WriteJSON(&out, results))
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 1)
+	assert.Equal(t, 40.0, decoded[0]["p95_ms"])
+	assert.Equal(t, true, decoded[0]["pass"])
+}
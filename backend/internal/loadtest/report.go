@@ -0,0 +1,92 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// MaxErrorRate is the share of failed or dropped requests a scenario may
+// have and still pass, as in the k6 scenario.
+const MaxErrorRate = 0.01
+
+// Check reports why r fails the gate: p95 over budget, or too many errors.
+// Nil means it passes.
+func (r Result) Check() error {
+	var errs []error
+	if r.P95Budget > 0 && r.P95 > r.P95Budget {
+		errs = append(errs, fmt.Errorf("%s: p95 %s is over its %s budget", r.Scenario, r.P95.Round(time.Millisecond), r.P95Budget))
+	}
+	if rate := r.ErrorRate(); rate > MaxErrorRate {
+		errs = append(errs, fmt.Errorf("%s: error rate %.1f%% is over %.1f%%", r.Scenario, rate*100, MaxErrorRate*100))
+	}
+	return errors.Join(errs...)
+}
+
+// WriteTable writes results as an aligned table, one scenario per line.
+func WriteTable(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SCENARIO\tREQUESTS\tERRORS\tDROPPED\tP50\tP95\tP99\tMAX\tBUDGET\tRESULT")
+	for _, r := range results {
+		verdict := "ok"
+		if r.Check() != nil {
+			verdict = "FAIL"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Scenario, r.Requests, r.Errors, r.Dropped,
+			ms(r.P50), ms(r.P95), ms(r.P99), ms(r.Max), ms(r.P95Budget), verdict)
+	}
+	return tw.Flush()
+}
+
+func ms(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", millis(d))
+}
+
+// reportJSON is a Result as the JSON report writes it, latencies in
+// milliseconds, for CI to archive and compare across runs.
+type reportJSON struct {
+	Scenario    string      `json:"scenario"`
+	Requests    int         `json:"requests"`
+	Errors      int         `json:"errors"`
+	Dropped     int         `json:"dropped"`
+	ErrorRate   float64     `json:"error_rate"`
+	Statuses    map[int]int `json:"statuses"`
+	P50Ms       float64     `json:"p50_ms"`
+	P95Ms       float64     `json:"p95_ms"`
+	P99Ms       float64     `json:"p99_ms"`
+	MaxMs       float64     `json:"max_ms"`
+	P95BudgetMs float64     `json:"p95_budget_ms"`
+	Pass        bool        `json:"pass"`
+}
+
+// WriteJSON writes results as a JSON array.
+func WriteJSON(w io.Writer, results []Result) error {
+	out := make([]reportJSON, len(results))
+	for i, r := range results {
+		out[i] = reportJSON{
+			Scenario:    r.Scenario,
+			Requests:    r.Requests,
+			Errors:      r.Errors,
+			Dropped:     r.Dropped,
+			ErrorRate:   r.ErrorRate(),
+			Statuses:    r.Statuses,
+			P50Ms:       millis(r.P50),
+			P95Ms:       millis(r.P95),
+			P99Ms:       millis(r.P99),
+			MaxMs:       millis(r.Max),
+			P95BudgetMs: millis(r.P95Budget),
+			Pass:        r.Check() == nil,
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
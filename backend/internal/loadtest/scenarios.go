@@ -0,0 +1,123 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Budgets match the k6 thresholds in load-tests/k6/scenario.js: reads
+// p95 < 500ms, writes p95 < 1s.
+const (
+	readBudget  = 500 * time.Millisecond
+	writeBudget = time.Second
+)
+
+// ScanBatch is how many assets one ScanIngest request reports, a handheld
+// sweep of a shelf.
+const ScanBatch = 25
+
+// ImportRows is how many assets one BulkImport upload carries.
+const ImportRows = 100
+
+// Scenarios returns the named scenarios, in run order. run tags the
+// fixtures and imported assets of this run so runs never collide.
+func Scenarios(run string) []Scenario {
+	return []Scenario{ScanIngest(run), AssetList(), BulkImport(run)}
+}
+
+// ScanIngest posts inventory saves of ScanBatch assets at one location, the
+// handheld ingest path. Setup creates the location and assets.
+func ScanIngest(run string) Scenario {
+	location := "lt-" + run + "-loc"
+	assets := make([]string, ScanBatch)
+	for i := range assets {
+		assets[i] = fmt.Sprintf("lt-%s-scan-%02d", run, i)
+	}
+	body, _ := json.Marshal(map[string]any{
+		"location_identifier": location,
+		"asset_identifiers":   assets,
+	})
+	return Scenario{
+		Name:      "scan_ingest",
+		P95Budget: writeBudget,
+		Setup: func(ctx context.Context, c *Client) error {
+			if err := c.postJSON(ctx, "/api/v1/locations", map[string]string{
+				"external_key": location, "name": "Load test " + run,
+			}); err != nil {
+				return err
+			}
+			for _, key := range assets {
+				if err := c.postJSON(ctx, "/api/v1/assets", map[string]string{
+					"external_key": key, "name": "Load test " + key,
+				}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Request: func(ctx context.Context, c *Client, _ int) (*http.Request, error) {
+			return c.newScanRequest(ctx, "/api/v1/inventory/save", body)
+		},
+	}
+}
+
+// AssetList pages through the first page of assets, the most common read.
+func AssetList() Scenario {
+	return Scenario{
+		Name:      "asset_list",
+		P95Budget: readBudget,
+		Request: func(ctx context.Context, c *Client, _ int) (*http.Request, error) {
+			return c.NewRequest(ctx, http.MethodGet, "/api/v1/assets?limit=50", nil, "")
+		},
+	}
+}
+
+// BulkImport uploads a CSV of ImportRows new assets. The import itself is
+// asynchronous, so this times the upload and job creation, which is what a
+// burst of imports contends on.
+func BulkImport(run string) Scenario {
+	return Scenario{
+		Name:      "bulk_import",
+		P95Budget: writeBudget,
+		Request: func(ctx context.Context, c *Client, i int) (*http.Request, error) {
+			body, contentType, err := importCSV(run, i)
+			if err != nil {
+				return nil, err
+			}
+			return c.NewRequest(ctx, http.MethodPost, "/api/v1/assets/bulk", body, contentType)
+		},
+	}
+}
+
+// importCSV is the multipart upload of the i-th BulkImport request.
+func importCSV(run string, i int) (*bytes.Buffer, string, error) {
+	var csv strings.Builder
+	csv.WriteString("external_key,name\n")
+	for row := range ImportRows {
+		fmt.Fprintf(&csv, "lt-%s-imp-%d-%03d,Load test import %d-%d\n", run, i, row, i, row)
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="loadtest-%d.csv"`, i))
+	h.Set("Content-Type", "text/csv")
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write([]byte(csv.String())); err != nil {
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return &body, w.FormDataContentType(), nil
+}
@@ -3,6 +3,7 @@ package logger
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -26,6 +27,9 @@ type Config struct {
 	SanitizeIPs    bool
 	MaxBodySize    int
 	Version        string
+	// DebugSampleRate is the fraction of requests, 0 to 1, logged at debug
+	// whatever Level says (LOG_DEBUG_SAMPLE_RATE). 0 turns sampling off.
+	DebugSampleRate float64
 }
 
 func DetectEnvironment() Environment {
@@ -66,6 +70,7 @@ func NewConfig(version string) *Config {
 		cfg.SanitizeEmails = getBoolEnv("LOG_SANITIZE_EMAILS", false)
 		cfg.SanitizeIPs = getBoolEnv("LOG_SANITIZE_IPS", false)
 		cfg.MaxBodySize = getIntEnv("LOG_MAX_BODY_SIZE", 1000)
+		cfg.DebugSampleRate = getFloatEnv("LOG_DEBUG_SAMPLE_RATE", 0)
 
 	case EnvStaging:
 		cfg.Level = getEnvOrDefault("LOG_LEVEL", "info")
@@ -76,6 +81,7 @@ func NewConfig(version string) *Config {
 		cfg.SanitizeEmails = getBoolEnv("LOG_SANITIZE_EMAILS", true)
 		cfg.SanitizeIPs = getBoolEnv("LOG_SANITIZE_IPS", true)
 		cfg.MaxBodySize = getIntEnv("LOG_MAX_BODY_SIZE", 0)
+		cfg.DebugSampleRate = getFloatEnv("LOG_DEBUG_SAMPLE_RATE", 0)
 
 	case EnvProd:
 		cfg.Level = getEnvOrDefault("LOG_LEVEL", "warn")
@@ -86,6 +92,7 @@ func NewConfig(version string) *Config {
 		cfg.SanitizeEmails = getBoolEnv("LOG_SANITIZE_EMAILS", true)
 		cfg.SanitizeIPs = getBoolEnv("LOG_SANITIZE_IPS", true)
 		cfg.MaxBodySize = getIntEnv("LOG_MAX_BODY_SIZE", 0)
+		cfg.DebugSampleRate = getFloatEnv("LOG_DEBUG_SAMPLE_RATE", 0)
 	}
 
 	return cfg
@@ -117,3 +124,15 @@ func getIntEnv(key string, defaultVal int) int {
 	}
 	return result
 }
+
+func getFloatEnv(key string, defaultVal float64) float64 {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	result, err := strconv.ParseFloat(val, 64)
+	if err != nil {
+		return defaultVal
+	}
+	return result
+}
@@ -322,3 +322,23 @@ func TestGetIntEnv(t *testing.T) {
 		})
 	}
 }
+
+func TestGetFloatEnv(t *testing.T) {
+	tests := []struct {
+		name         string
+		envValue     string
+		defaultValue float64
+		expected     float64
+	}{
+		{name: "Returns float value when env is valid", envValue: "0.25", defaultValue: 0, expected: 0.25},
+		{name: "Returns default when env not set", envValue: "", defaultValue: 0.5, expected: 0.5},
+		{name: "Returns default when env is invalid", envValue: "some", defaultValue: 0.5, expected: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TEST_FLOAT", tt.envValue)
+			assert.Equal(t, tt.expected, getFloatEnv("TEST_FLOAT", tt.defaultValue))
+		})
+	}
+}
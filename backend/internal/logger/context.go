@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+type loggerKey struct{}
+
+// Ctx returns the request-scoped logger Middleware put on ctx, or the global
+// logger outside a request. Lines logged through it carry the request's id,
+// route and, once authenticated, user and org.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*zerolog.Logger); ok {
+		return l
+	}
+	return Get()
+}
+
+// WithLogger returns a copy of ctx carrying l for Ctx.
+func WithLogger(ctx context.Context, l *zerolog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// AddFields adds fields to the request-scoped logger on ctx, so every later
+// line of the request, including "Request completed", carries them. It is a
+// no-op outside a request. Call it from the request's goroutine.
+func AddFields(ctx context.Context, fn func(zerolog.Context) zerolog.Context) {
+	if l, ok := ctx.Value(loggerKey{}).(*zerolog.Logger); ok {
+		l.UpdateContext(fn)
+	}
+}
+
+// SetUser records the authenticated user, and their current org if known, on
+// the request's log lines.
+func SetUser(ctx context.Context, userID int, orgID *int) {
+	AddFields(ctx, func(c zerolog.Context) zerolog.Context {
+		c = c.Int("user_id", userID)
+		if orgID != nil {
+			c = c.Int("org_id", *orgID)
+		}
+		return c
+	})
+}
+
+// SetOrg records the org a request acts for on its log lines.
+func SetOrg(ctx context.Context, orgID int) {
+	AddFields(ctx, func(c zerolog.Context) zerolog.Context {
+		return c.Int("org_id", orgID)
+	})
+}
@@ -1,18 +1,36 @@
 package logger
 
 import (
+	stdlog "log"
 	"os"
 	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+
+	"github.com/trakrf/platform/backend/internal/util/pii"
 )
 
 var globalLogger *zerolog.Logger
 
+// debugSampleRate is Config.DebugSampleRate, read by Middleware.
+var debugSampleRate float64
+
 func Initialize(cfg *Config) *zerolog.Logger {
 	level := parseLevel(cfg.Level)
-	zerolog.SetGlobalLevel(level)
+	debugSampleRate = cfg.DebugSampleRate
+	// The global level is a floor no logger can go below, so sampled
+	// requests need it at debug; the logger's own level filters the rest.
+	if debugSampleRate > 0 && level > zerolog.DebugLevel {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	} else {
+		zerolog.SetGlobalLevel(level)
+	}
+
+	// Every line is redacted of personal data and credentials on its way
+	// out (see pii), whichever logger wrote it: slog's default handler
+	// writes through the standard log package.
+	stdlog.SetOutput(pii.NewWriter(os.Stderr))
 
 	var logger zerolog.Logger
 	if cfg.Format == "console" {
@@ -21,9 +39,9 @@ func Initialize(cfg *Config) *zerolog.Logger {
 			TimeFormat: time.RFC3339,
 			NoColor:    !cfg.ColorOutput,
 		}
-		logger = zerolog.New(output).With().Timestamp().Logger()
+		logger = zerolog.New(pii.NewWriter(output)).With().Timestamp().Logger()
 	} else {
-		logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
+		logger = zerolog.New(pii.NewWriter(os.Stdout)).With().Timestamp().Logger()
 	}
 
 	if cfg.IncludeCaller {
@@ -40,6 +58,8 @@ func Initialize(cfg *Config) *zerolog.Logger {
 		logger = logger.With().Stack().Logger()
 	}
 
+	logger = logger.Level(level)
+
 	globalLogger = &logger
 	log.Logger = logger
 
@@ -2,8 +2,12 @@ package logger
 
 import (
 	"context"
+	"math/rand/v2"
 	"net/http"
 	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
 )
 
 type contextKey string
@@ -17,10 +21,22 @@ func getRequestID(ctx context.Context) string {
 	return ""
 }
 
+// Middleware logs each request and puts a request-scoped logger on its
+// context (see Ctx). Every line from that logger carries request_id, method,
+// path and remote_ip; the auth middlewares add user_id and org_id (SetUser,
+// SetOrg) once they know them. "Request completed" also carries the matched
+// route pattern, status and latency.
+//
+// A request picked by the debug sample rate (Config.DebugSampleRate) logs at
+// debug whatever the configured level, and is marked debug_sampled.
 func Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		requestID := getRequestID(r.Context())
+		if requestID == "" {
+			// middleware.RequestID runs first and echoes the id it settled on.
+			requestID = w.Header().Get("X-Request-ID")
+		}
 
 		logger := Get().With().
 			Str("request_id", requestID).
@@ -28,6 +44,10 @@ func Middleware(next http.Handler) http.Handler {
 			Str("path", r.URL.Path).
 			Str("remote_ip", r.RemoteAddr).
 			Logger()
+		if sampleDebug() {
+			logger = logger.Level(zerolog.DebugLevel).With().Bool("debug_sampled", true).Logger()
+		}
+		r = r.WithContext(WithLogger(r.Context(), &logger))
 
 		logger.Debug().
 			Interface("headers", SanitizeHeaders(r.Header)).
@@ -37,22 +57,34 @@ func Middleware(next http.Handler) http.Handler {
 		next.ServeHTTP(wrapped, r)
 		duration := time.Since(start)
 
-		logEvent := logger.Info().
-			Int("status", wrapped.statusCode).
-			Dur("duration_ms", duration).
-			Int64("duration_ms_int", duration.Milliseconds())
-
+		logEvent := logger.Info()
 		if wrapped.statusCode >= 400 {
-			logEvent = logger.Warn().
-				Int("status", wrapped.statusCode).
-				Dur("duration_ms", duration).
-				Int64("duration_ms_int", duration.Milliseconds())
+			logEvent = logger.Warn()
 		}
-
-		logEvent.Msg("Request completed")
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if route := rctx.RoutePattern(); route != "" {
+				logEvent = logEvent.Str("route", route)
+			}
+		}
+		logEvent.
+			Int("status", wrapped.statusCode).
+			Dur("duration_ms", duration).
+			Int64("duration_ms_int", duration.Milliseconds()).
+			Msg("Request completed")
 	})
 }
 
+// sampleDebug reports whether this request is one of the debug sample.
+func sampleDebug() bool {
+	switch {
+	case debugSampleRate <= 0:
+		return false
+	case debugSampleRate >= 1:
+		return true
+	}
+	return rand.Float64() < debugSampleRate
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
@@ -1,12 +1,18 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetRequestID(t *testing.T) {
@@ -296,3 +302,104 @@ func TestMiddlewareChaining(t *testing.T) {
 		assert.Equal(t, "test-value", rec.Header().Get("X-Custom-Header"))
 	})
 }
+
+// captureLines points the global logger at a buffer, at level, and returns a
+// func decoding the JSON lines written so far.
+func captureLines(t *testing.T, level zerolog.Level) func() []map[string]any {
+	t.Helper()
+	prevLogger, prevRate, prevGlobal := globalLogger, debugSampleRate, zerolog.GlobalLevel()
+	t.Cleanup(func() {
+		globalLogger, debugSampleRate = prevLogger, prevRate
+		zerolog.SetGlobalLevel(prevGlobal)
+	})
+	zerolog.SetGlobalLevel(zerolog.DebugLevel)
+
+	var buf bytes.Buffer
+	SetForTest(zerolog.New(&buf).Level(level))
+	return func() []map[string]any {
+		var lines []map[string]any
+		for _, raw := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			if raw == "" {
+				continue
+			}
+			var m map[string]any
+			require.NoError(t, json.Unmarshal([]byte(raw), &m))
+			lines = append(lines, m)
+		}
+		return lines
+	}
+}
+
+func TestMiddleware_RequestScopedFields(t *testing.T) {
+	lines := captureLines(t, zerolog.InfoLevel)
+	debugSampleRate = 0
+
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-ID", "req-123")
+			next.ServeHTTP(w, r)
+		})
+	})
+	r.Use(Middleware)
+	r.Get("/api/v1/assets/{id}", func(w http.ResponseWriter, r *http.Request) {
+		orgID := 7
+		SetUser(r.Context(), 42, &orgID)
+		Ctx(r.Context()).Info().Msg("handler line")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/api/v1/assets/99", nil))
+
+	got := lines()
+	require.Len(t, got, 2)
+	handlerLine, completed := got[0], got[1]
+	assert.Equal(t, "handler line", handlerLine["message"])
+	assert.Equal(t, "req-123", handlerLine["request_id"])
+	assert.EqualValues(t, 42, handlerLine["user_id"])
+	assert.EqualValues(t, 7, handlerLine["org_id"])
+
+	assert.Equal(t, "Request completed", completed["message"])
+	assert.Equal(t, "warn", completed["level"])
+	assert.Equal(t, "/api/v1/assets/{id}", completed["route"])
+	assert.EqualValues(t, http.StatusNotFound, completed["status"])
+	assert.EqualValues(t, 42, completed["user_id"])
+	assert.EqualValues(t, 7, completed["org_id"])
+	assert.Contains(t, completed, "duration_ms_int")
+	assert.NotContains(t, completed, "debug_sampled")
+}
+
+func TestMiddleware_DebugSampling(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Ctx(r.Context()).Debug().Msg("handler debug")
+	})
+
+	t.Run("Sampled request logs at debug", func(t *testing.T) {
+		lines := captureLines(t, zerolog.WarnLevel)
+		debugSampleRate = 1
+
+		Middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/x", nil))
+
+		got := lines()
+		require.Len(t, got, 3)
+		assert.Equal(t, "Request received", got[0]["message"])
+		assert.Equal(t, "handler debug", got[1]["message"])
+		assert.Equal(t, true, got[1]["debug_sampled"])
+	})
+
+	t.Run("Unsampled request keeps the configured level", func(t *testing.T) {
+		lines := captureLines(t, zerolog.WarnLevel)
+		debugSampleRate = 0
+
+		Middleware(handler).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/x", nil))
+
+		assert.Empty(t, lines())
+	})
+}
+
+func TestCtx_OutsideRequest(t *testing.T) {
+	assert.Same(t, Get(), Ctx(context.Background()))
+	// No request logger: a no-op rather than a panic.
+	SetOrg(context.Background(), 1)
+}
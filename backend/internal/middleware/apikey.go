@@ -6,6 +6,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/trakrf/platform/backend/internal/errorreport"
 	"github.com/trakrf/platform/backend/internal/logger"
 	"github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/storage"
@@ -20,11 +21,15 @@ import (
 // test-handler-minted schemathesis key without re-fetching the row. The bypass
 // is APP_ENV-gated at router build time — Name itself is informational here.
 // TRA-677.
+//
+// ServiceAccountID is set when the key is owned by a service account; audit
+// logging attributes the call to that account rather than to the key alone.
 type APIKeyPrincipal struct {
-	OrgID  int
-	Scopes []string
-	JTI    string
-	Name   string
+	OrgID            int
+	Scopes           []string
+	JTI              string
+	Name             string
+	ServiceAccountID *int
 }
 
 const APIKeyPrincipalKey contextKey = "api_key_principal"
@@ -80,18 +85,21 @@ func APIKeyAuth(store *storage.Storage) func(http.Handler) http.Handler {
 			}
 
 			// Fire-and-forget last_used_at bump. Logs but doesn't fail the request.
-			go func(jti string) {
+			jti := key.JTI
+			errorreport.SafeGo(context.Background(), errorreport.Tags{"job": "api_key_last_used"}, func() {
 				if err := store.UpdateAPIKeyLastUsed(context.Background(), jti); err != nil {
 					logger.Get().Error().Err(err).Str("jti", jti).Msg("last_used_at update failed")
 				}
-			}(key.JTI)
+			}, nil)
 
 			principal := &APIKeyPrincipal{
-				OrgID:  key.OrgID,
-				Scopes: key.Scopes,
-				JTI:    key.JTI,
-				Name:   key.Name,
+				OrgID:            key.OrgID,
+				Scopes:           key.Scopes,
+				JTI:              key.JTI,
+				Name:             key.Name,
+				ServiceAccountID: key.ServiceAccountID,
 			}
+			logger.SetOrg(r.Context(), principal.OrgID)
 			ctx := context.WithValue(r.Context(), APIKeyPrincipalKey, principal)
 			logger.Get().Info().
 				Int("org_id", principal.OrgID).
@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/models/approval"
+	apierrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ApprovalStore holds approval rules and requests. Satisfied by
+// *storage.Storage.
+type ApprovalStore interface {
+	GetApprovalRule(ctx context.Context, orgID int, op string) (*approval.Rule, error)
+	CreateApprovalRequest(ctx context.Context, orgID, userID int, req approval.NewRequest) (*approval.Request, error)
+	ConsumeApproval(ctx context.Context, orgID, id, userID int, req approval.NewRequest) (bool, error)
+}
+
+// RequireApproval gates op behind the current org's approval rule. Mount it
+// after the route's role gate. It:
+//   - passes through when the org has no rule for op, or the request falls
+//     under the rule's threshold (see approval.Applies),
+//   - without an X-Approval-ID header, parks the request as a pending
+//     approval request and answers 202 with it instead of running it,
+//   - with X-Approval-ID, spends that approval and runs the request, or
+//     answers 409 when the approval is not approved, has expired, was made
+//     by someone else, or was for a different method, path or body.
+//
+// The approval is spent once the request is let through, whatever the
+// handler then answers.
+func RequireApproval(store ApprovalStore, op string) func(http.Handler) http.Handler {
+	return approvalGate(store, op, func(r *http.Request) (int, bool) {
+		orgID, err := GetRequestOrgID(r)
+		return orgID, err == nil
+	})
+}
+
+// RequireOrgApproval is RequireApproval for routes that name the org in the
+// :orgId or :id URL parameter, like RequireOrgMember.
+func RequireOrgApproval(store ApprovalStore, op string) func(http.Handler) http.Handler {
+	return approvalGate(store, op, func(r *http.Request) (int, bool) {
+		s := chi.URLParam(r, "orgId")
+		if s == "" {
+			s = chi.URLParam(r, "id")
+		}
+		orgID, err := strconv.Atoi(s)
+		return orgID, err == nil
+	})
+}
+
+func approvalGate(store ApprovalStore, op string, resolveOrg func(*http.Request) (int, bool)) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := GetRequestID(r.Context())
+
+			// Approvals are between people; the role gate in front has
+			// already turned away anyone else.
+			claims := GetUserClaims(r)
+			if claims == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			orgID, ok := resolveOrg(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			rule, err := store.GetApprovalRule(r.Context(), orgID, op)
+			if err != nil {
+				httputil.WriteJSONError(w, r, http.StatusInternalServerError,
+					apierrors.ErrInternal, "Failed to check approval rules", requestID)
+				return
+			}
+			if rule == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				httputil.RespondDecodeError(w, r, err, requestID)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			// Malformed bodies go on to the handler's own validation error.
+			if (len(body) > 0 && !json.Valid(body)) || !approval.Applies(op, body, rule.Threshold) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			sum := sha256.Sum256(body)
+			req := approval.NewRequest{
+				Operation:  op,
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Body:       body,
+				BodySHA256: hex.EncodeToString(sum[:]),
+				Summary:    approval.Summary(op, r.Method, r.URL.Path, body),
+				TTLHours:   rule.TTLHours,
+			}
+
+			if h := r.Header.Get(approval.HeaderApprovalID); h != "" {
+				id, err := strconv.Atoi(h)
+				if err != nil || id < 1 {
+					httputil.WriteJSONError(w, r, http.StatusBadRequest, apierrors.ErrBadRequest,
+						fmt.Sprintf("%s must be an approval request id", approval.HeaderApprovalID), requestID)
+					return
+				}
+				used, err := store.ConsumeApproval(r.Context(), orgID, id, claims.UserID, req)
+				if err != nil {
+					httputil.WriteJSONError(w, r, http.StatusInternalServerError,
+						apierrors.ErrInternal, "Failed to check approval", requestID)
+					return
+				}
+				if !used {
+					httputil.WriteJSONError(w, r, http.StatusConflict, apierrors.ErrConflict,
+						"The approval is not approved, has expired, was already used, or does not match this request",
+						requestID)
+					return
+				}
+				logger.Get().Info().
+					Int("org_id", orgID).
+					Int("user_id", claims.UserID).
+					Int("approval_id", id).
+					Str("operation", op).
+					Str("request_id", requestID).
+					Msg("Approved operation let through")
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			created, err := store.CreateApprovalRequest(r.Context(), orgID, claims.UserID, req)
+			if err != nil {
+				httputil.WriteJSONError(w, r, http.StatusInternalServerError,
+					apierrors.ErrInternal, "Failed to create approval request", requestID)
+				return
+			}
+			httputil.WriteJSON(w, http.StatusAccepted, map[string]any{"data": created})
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package middleware_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/approval"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// fakeApprovalStore is a test-only ApprovalStore.
+type fakeApprovalStore struct {
+	rule     *approval.Rule
+	created  *approval.NewRequest
+	consumed *approval.NewRequest
+	usable   bool
+}
+
+func (f *fakeApprovalStore) GetApprovalRule(ctx context.Context, orgID int, op string) (*approval.Rule, error) {
+	return f.rule, nil
+}
+
+func (f *fakeApprovalStore) CreateApprovalRequest(ctx context.Context, orgID, userID int, req approval.NewRequest) (*approval.Request, error) {
+	f.created = &req
+	return &approval.Request{ID: 7, OrgID: orgID, Operation: req.Operation, Status: approval.StatusPending}, nil
+}
+
+func (f *fakeApprovalStore) ConsumeApproval(ctx context.Context, orgID, id, userID int, req approval.NewRequest) (bool, error) {
+	f.consumed = &req
+	return f.usable && id == 7, nil
+}
+
+func approvalRequest(body, approvalID string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/assets/bulk-delete", strings.NewReader(body))
+	if approvalID != "" {
+		r.Header.Set(approval.HeaderApprovalID, approvalID)
+	}
+	orgID := 42
+	claims := &jwt.Claims{UserID: 1, CurrentOrgID: &orgID}
+	return r.WithContext(context.WithValue(r.Context(), middleware.UserClaimsKey, claims))
+}
+
+// echoBody is a next-handler that records the body it was given.
+func echoBody(got *string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		*got = string(b)
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestRequireApproval_NoRulePasses(t *testing.T) {
+	store := &fakeApprovalStore{}
+	var got string
+	w := httptest.NewRecorder()
+	middleware.RequireApproval(store, approval.OpAssetsBulkDelete)(echoBody(&got)).
+		ServeHTTP(w, approvalRequest(`{"asset_ids":[1,2,3]}`, ""))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"asset_ids":[1,2,3]}`, got)
+	assert.Nil(t, store.created)
+}
+
+func TestRequireApproval_UnderThresholdPasses(t *testing.T) {
+	five := 5
+	store := &fakeApprovalStore{rule: &approval.Rule{Threshold: &five, TTLHours: 72}}
+	var got string
+	w := httptest.NewRecorder()
+	middleware.RequireApproval(store, approval.OpAssetsBulkDelete)(echoBody(&got)).
+		ServeHTTP(w, approvalRequest(`{"asset_ids":[1,2,3]}`, ""))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"asset_ids":[1,2,3]}`, got, "the body must be restored for the handler")
+	assert.Nil(t, store.created)
+}
+
+func TestRequireApproval_ParksGatedRequest(t *testing.T) {
+	store := &fakeApprovalStore{rule: &approval.Rule{TTLHours: 24}}
+	var reached bool
+	w := httptest.NewRecorder()
+	middleware.RequireApproval(store, approval.OpAssetsBulkDelete)(nextReached(&reached)).
+		ServeHTTP(w, approvalRequest(`{"asset_ids":[1,2,3]}`, ""))
+
+	assert.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	assert.False(t, reached)
+	require.NotNil(t, store.created)
+	assert.Equal(t, "/api/v1/assets/bulk-delete", store.created.Path)
+	assert.Equal(t, 24, store.created.TTLHours)
+	assert.Equal(t, "Delete 3 assets", store.created.Summary)
+	assert.Len(t, store.created.BodySHA256, 64)
+	assert.Contains(t, w.Body.String(), `"status":"pending"`)
+}
+
+func TestRequireApproval_ReplayWithApproval(t *testing.T) {
+	store := &fakeApprovalStore{rule: &approval.Rule{TTLHours: 24}, usable: true}
+	var got string
+	w := httptest.NewRecorder()
+	middleware.RequireApproval(store, approval.OpAssetsBulkDelete)(echoBody(&got)).
+		ServeHTTP(w, approvalRequest(`{"asset_ids":[1,2,3]}`, "7"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, `{"asset_ids":[1,2,3]}`, got)
+	require.NotNil(t, store.consumed)
+	assert.Nil(t, store.created, "a replay must not park a new request")
+
+	for _, id := range []string{"8", "abc"} {
+		reached := false
+		w := httptest.NewRecorder()
+		middleware.RequireApproval(store, approval.OpAssetsBulkDelete)(nextReached(&reached)).
+			ServeHTTP(w, approvalRequest(`{"asset_ids":[1,2,3]}`, id))
+		assert.False(t, reached, id)
+		assert.Contains(t, []int{http.StatusConflict, http.StatusBadRequest}, w.Code, id)
+	}
+}
+
+func TestRequireApproval_MalformedBodyReachesHandler(t *testing.T) {
+	store := &fakeApprovalStore{rule: &approval.Rule{TTLHours: 24}}
+	var reached bool
+	w := httptest.NewRecorder()
+	middleware.RequireApproval(store, approval.OpAssetsBulkDelete)(nextReached(&reached)).
+		ServeHTTP(w, approvalRequest(`{"asset_ids":`, ""))
+
+	assert.True(t, reached)
+	assert.Nil(t, store.created)
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"path"
+
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// DefaultMaxBodyBytes caps every request body not listed in bodyLimits.
+// The largest JSON write the app sends (an inventory save of a few thousand
+// tags) is well under this.
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// bodyLimits raises the cap on routes that legitimately take larger bodies.
+// The bulk CSV upload allows the importer's 5 MB file limit plus headroom
+// for the multipart envelope; anything bigger is rejected before it is
+// spooled to a temp file. An avatar is a single image of at most 2 MiB. A
+// chain-of-custody document posted back for verification may hold
+// report.MaxCustodyEvents entries, and an asset validation batch up to
+// 10000 asset payloads.
+var bodyLimits = map[string]int64{
+	bulkCSVUploadPath: 6 << 20,
+	AvatarUploadPath:  2 << 20,
+	CustodyVerifyPath: 16 << 20,
+	AssetValidatePath: 16 << 20,
+}
+
+// CustodyVerifyPath takes a whole chain-of-custody document.
+const CustodyVerifyPath = "/api/v1/custody/verify"
+
+// AssetValidatePath takes a batch of asset payloads to pre-flight.
+const AssetValidatePath = "/api/v1/assets/validate"
+
+// orgImportPathPattern matches POST /api/v1/orgs/{id}/import, which takes a
+// whole org export archive (usually gzipped).
+const orgImportPathPattern = "/api/v1/orgs/*/import"
+
+// bodyLimitPatterns is bodyLimits for routes with path parameters, matched
+// with path.Match. A condition photo is one phone-camera image; a document
+// file a scanned multi-page PDF.
+var bodyLimitPatterns = map[string]int64{
+	orgImportPathPattern:      64 << 20,
+	ConditionPhotoPathPattern: 10 << 20,
+	DocumentFilePathPattern:   20 << 20,
+}
+
+// MaxBodyBytesFor returns the body cap applied to urlPath.
+func MaxBodyBytesFor(urlPath string) int64 {
+	if n, ok := bodyLimits[urlPath]; ok {
+		return n
+	}
+	for pattern, n := range bodyLimitPatterns {
+		if ok, _ := path.Match(pattern, urlPath); ok {
+			return n
+		}
+	}
+	return DefaultMaxBodyBytes
+}
+
+// MaxBodySize bounds request bodies so an oversized upload cannot exhaust
+// memory or disk. A declared Content-Length over the cap is rejected with 413
+// up front; otherwise the body is wrapped in http.MaxBytesReader, whose
+// *http.MaxBytesError the decode helpers turn into the same 413.
+func MaxBodySize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		limit := MaxBodyBytesFor(r.URL.Path)
+		if r.ContentLength > limit {
+			httputil.Respond413(w, r, limit, GetRequestID(r.Context()))
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	var readErr error
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	})
+	h := MaxBodySize(next)
+
+	t.Run("declared length over cap is rejected up front", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/assets", strings.NewReader(strings.Repeat("a", int(DefaultMaxBodyBytes)+1)))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Fatalf("status = %d, want 413", w.Code)
+		}
+	})
+
+	t.Run("undeclared length is cut off while reading", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/assets", strings.NewReader(strings.Repeat("a", int(DefaultMaxBodyBytes)+1)))
+		r.ContentLength = -1
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		var mbe *http.MaxBytesError
+		if !errors.As(readErr, &mbe) {
+			t.Fatalf("read error = %v, want *http.MaxBytesError", readErr)
+		}
+	})
+
+	t.Run("bulk upload gets the larger cap", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, bulkCSVUploadPath, strings.NewReader(strings.Repeat("a", int(DefaultMaxBodyBytes)+1)))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK || readErr != nil {
+			t.Fatalf("status = %d, err = %v; want 200 and a full read", w.Code, readErr)
+		}
+	})
+
+	t.Run("org import gets the larger cap by pattern", func(t *testing.T) {
+		if got := MaxBodyBytesFor("/api/v1/orgs/12345/import"); got != 64<<20 {
+			t.Fatalf("import cap = %d, want %d", got, 64<<20)
+		}
+		if got := MaxBodyBytesFor("/api/v1/orgs/12345/members"); got != DefaultMaxBodyBytes {
+			t.Fatalf("members cap = %d, want default", got)
+		}
+		if got := MaxBodyBytesFor("/api/v1/assets/1/condition-reports/2/photos"); got != 10<<20 {
+			t.Fatalf("condition photo cap = %d, want %d", got, 10<<20)
+		}
+		if got := MaxBodyBytesFor("/api/v1/documents/5/file"); got != 20<<20 {
+			t.Fatalf("document file cap = %d, want %d", got, 20<<20)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestConditionalGET(t *testing.T) {
+	body := `{"data":[{"id":1}]}`
+	h := ConditionalGET(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/assets", nil))
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || w.Body.String() != body {
+		t.Fatalf("first GET = %d %q, want 200 with body", w.Code, w.Body.String())
+	}
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("ETag = %q, want a weak tag", etag)
+	}
+
+	for _, inm := range []string{etag, strings.TrimPrefix(etag, "W/"), `"other", ` + etag, "*"} {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/assets", nil)
+		r.Header.Set("If-None-Match", inm)
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Fatalf("If-None-Match %q = %d with %d-byte body, want empty 304", inm, w.Code, w.Body.Len())
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/assets", nil)
+	r.Header.Set("If-None-Match", `W/"stale"`)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || w.Body.String() != body {
+		t.Fatalf("stale If-None-Match = %d, want 200 with body", w.Code)
+	}
+}
+
+func TestConditionalGET_PassesErrorsThrough(t *testing.T) {
+	h := ConditionalGET(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"error":{}}`)
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/assets/9", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+		t.Fatalf("got %d with ETag %q, want 404 without ETag", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestCompress(t *testing.T) {
+	payload := strings.Repeat(`{"name":"Pallet jack"},`, 200)
+	h := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, payload)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/locations", nil)
+	r.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", w.Header().Get("Content-Encoding"))
+	}
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil || string(got) != payload {
+		t.Fatalf("decompressed body mismatch (err %v)", err)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/api/v1/locations", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Header().Get("Content-Encoding") != "" || w.Body.String() != payload {
+		t.Fatal("client without Accept-Encoding must get the identity body")
+	}
+}
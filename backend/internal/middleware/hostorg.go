@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+const hostOrgKey contextKey = "host_org"
+
+// hostOrgCacheTTL bounds how long a resolved (or unknown) hostname is
+// remembered. A newly verified or deleted domain takes effect within it.
+const hostOrgCacheTTL = time.Minute
+
+// hostOrgCacheSize caps the cache so a stream of forged Host headers cannot
+// grow it without bound; a full cache is simply emptied.
+const hostOrgCacheSize = 1024
+
+// HostOrgResolver maps a request host to an org. Satisfied by
+// *storage.Storage.
+type HostOrgResolver interface {
+	// ResolveCustomDomain finds the org that verified hostname.
+	ResolveCustomDomain(ctx context.Context, hostname string) (*organization.HostOrg, error)
+	// ResolveOrgSubdomain finds the live org with this identifier.
+	ResolveOrgSubdomain(ctx context.Context, identifier string) (*organization.HostOrg, error)
+}
+
+type hostOrgEntry struct {
+	org     *organization.HostOrg
+	expires time.Time
+}
+
+// HostOrg resolves the request's Host to an org and stores it for
+// GetHostOrg. {identifier}.<links.TenantHost()> names an org by identifier;
+// any other host is looked up among verified custom domains. The BaseURL
+// host, localhost, IP literals and single-label hosts are not looked up.
+// The Host header is used as received: a proxy in front must pass the
+// client's Host through rather than rewrite it. A lookup failure leaves the
+// request unresolved; it never fails the request.
+//
+// On its own it only identifies the org a page is served for; see
+// ScopeToHostOrg for making it the session's org.
+func HostOrg(resolver HostOrgResolver, links applinks.Config) func(http.Handler) http.Handler {
+	var appHost string
+	if u, err := url.Parse(links.BaseURL); err == nil {
+		appHost = strings.ToLower(u.Hostname())
+	}
+	tenantSuffix := "." + links.TenantHost()
+
+	var mu sync.Mutex
+	cache := map[string]hostOrgEntry{}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hostname := requestHostname(r)
+			if hostname == appHost || !isHostOrgCandidate(hostname) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			now := time.Now()
+			mu.Lock()
+			entry, ok := cache[hostname]
+			mu.Unlock()
+			if !ok || now.After(entry.expires) {
+				var org *organization.HostOrg
+				var err error
+				if label, sub := strings.CutSuffix(hostname, tenantSuffix); sub && !strings.Contains(label, ".") {
+					org, err = resolver.ResolveOrgSubdomain(r.Context(), label)
+					if org != nil {
+						org.Hostname = hostname
+					}
+				} else {
+					org, err = resolver.ResolveCustomDomain(r.Context(), hostname)
+				}
+				if err != nil {
+					slog.WarnContext(r.Context(), "host org lookup failed", "hostname", hostname, "error", err)
+					next.ServeHTTP(w, r)
+					return
+				}
+				entry = hostOrgEntry{org: org, expires: now.Add(hostOrgCacheTTL)}
+				mu.Lock()
+				if len(cache) >= hostOrgCacheSize {
+					clear(cache)
+				}
+				cache[hostname] = entry
+				mu.Unlock()
+			}
+
+			if entry.org != nil {
+				r = r.WithContext(context.WithValue(r.Context(), hostOrgKey, entry.org))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// GetHostOrg returns the org the request's host resolved to, or nil when it
+// did not arrive on an org subdomain or custom domain.
+func GetHostOrg(ctx context.Context) *organization.HostOrg {
+	org, _ := ctx.Value(hostOrgKey).(*organization.HostOrg)
+	return org
+}
+
+// ScopeToHostOrg makes the host's org the current org of a session request,
+// so one token serves every org a user belongs to and may carry no org at
+// all. Apply it after the auth middleware and HostOrg. A request whose user
+// is neither a member of the host's org nor a superadmin gets 403. API-key
+// requests and requests without a host org pass through unchanged: an API
+// key is bound to its own org.
+func ScopeToHostOrg(store OrgRoleStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host := GetHostOrg(r.Context())
+			claims := GetUserClaims(r)
+			if host == nil || claims == nil ||
+				(claims.CurrentOrgID != nil && *claims.CurrentOrgID == host.OrgID) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			requestID := GetRequestID(ctx)
+			isSuperadmin, err := store.IsUserSuperadmin(ctx, claims.UserID)
+			if err != nil {
+				logger.Get().Error().
+					Err(err).
+					Int("user_id", claims.UserID).
+					Str("request_id", requestID).
+					Msg("Failed to check superadmin status")
+				httputil.WriteJSONError(w, r, http.StatusInternalServerError,
+					errors.ErrInternal, "Failed to check permissions", requestID)
+				return
+			}
+			if !isSuperadmin {
+				if _, err := store.GetUserOrgRole(ctx, claims.UserID, host.OrgID); err != nil {
+					if err.Error() == ErrOrgUserNotFound.Error() {
+						logAccessDenied(claims.UserID, host.OrgID, "member", r)
+						httputil.WriteJSONError(w, r, http.StatusForbidden,
+							errors.ErrForbidden, "You are not a member of the organization at "+host.Hostname, requestID)
+						return
+					}
+					logger.Get().Error().
+						Err(err).
+						Int("user_id", claims.UserID).
+						Int("org_id", host.OrgID).
+						Str("request_id", requestID).
+						Msg("Failed to get user org role")
+					httputil.WriteJSONError(w, r, http.StatusInternalServerError,
+						errors.ErrInternal, "Failed to check permissions", requestID)
+					return
+				}
+			}
+
+			scoped := *claims
+			orgID := host.OrgID
+			scoped.CurrentOrgID = &orgID
+			logger.SetUser(ctx, scoped.UserID, scoped.CurrentOrgID)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(ctx, UserClaimsKey, &scoped)))
+		})
+	}
+}
+
+// requestHostname is r.Host lowercased, without port or trailing dot.
+func requestHostname(r *http.Request) string {
+	host := strings.ToLower(r.Host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+func isHostOrgCandidate(hostname string) bool {
+	if hostname == "" || hostname == "localhost" || strings.HasSuffix(hostname, ".localhost") {
+		return false
+	}
+	if net.ParseIP(strings.Trim(hostname, "[]")) != nil {
+		return false
+	}
+	return strings.Contains(hostname, ".")
+}
@@ -0,0 +1,192 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+var testLinks = applinks.Config{BaseURL: "https://app.trakrf.id", TenantDomain: "trakrf.app"}
+
+// fakeHostOrgs is a test-only HostOrgResolver.
+type fakeHostOrgs struct {
+	domains    map[string]*organization.HostOrg
+	subdomains map[string]*organization.HostOrg
+	err        error
+	calls      []string
+}
+
+func (f *fakeHostOrgs) ResolveCustomDomain(ctx context.Context, hostname string) (*organization.HostOrg, error) {
+	f.calls = append(f.calls, "domain:"+hostname)
+	return f.domains[hostname], f.err
+}
+
+func (f *fakeHostOrgs) ResolveOrgSubdomain(ctx context.Context, identifier string) (*organization.HostOrg, error) {
+	f.calls = append(f.calls, "subdomain:"+identifier)
+	return f.subdomains[identifier], f.err
+}
+
+// serveOnHost runs one request for host through the middleware and returns
+// the host org the next handler saw.
+func serveOnHost(t *testing.T, mw func(http.Handler) http.Handler, host string) *organization.HostOrg {
+	t.Helper()
+	var got *organization.HostOrg
+	reached := false
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = host
+	mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		got = middleware.GetHostOrg(r.Context())
+	})).ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, reached, "request must always reach the next handler")
+	return got
+}
+
+func TestHostOrg_ResolvesVerifiedCustomDomain(t *testing.T) {
+	acme := &organization.HostOrg{OrgID: 7, Identifier: "acme", Hostname: "track.acme.example"}
+	res := &fakeHostOrgs{domains: map[string]*organization.HostOrg{"track.acme.example": acme}}
+	mw := middleware.HostOrg(res, testLinks)
+
+	assert.Equal(t, acme, serveOnHost(t, mw, "Track.Acme.Example:443"))
+	assert.Equal(t, acme, serveOnHost(t, mw, "track.acme.example."))
+	assert.Equal(t, []string{"domain:track.acme.example"}, res.calls, "answers are cached per hostname")
+}
+
+func TestHostOrg_ResolvesTenantSubdomain(t *testing.T) {
+	res := &fakeHostOrgs{subdomains: map[string]*organization.HostOrg{"acme": {OrgID: 7, Identifier: "acme"}}}
+	mw := middleware.HostOrg(res, testLinks)
+
+	got := serveOnHost(t, mw, "acme.trakrf.app")
+	assert.Equal(t, &organization.HostOrg{OrgID: 7, Identifier: "acme", Hostname: "acme.trakrf.app"}, got)
+	assert.Nil(t, serveOnHost(t, mw, "eu.acme.trakrf.app"), "only single-label subdomains name an org")
+	assert.Equal(t, []string{"subdomain:acme", "domain:eu.acme.trakrf.app"}, res.calls)
+}
+
+func TestHostOrg_TenantDomainDefaultsToAppHost(t *testing.T) {
+	res := &fakeHostOrgs{}
+	mw := middleware.HostOrg(res, applinks.Config{BaseURL: "https://app.trakrf.id"})
+
+	serveOnHost(t, mw, "acme.app.trakrf.id")
+	assert.Equal(t, []string{"subdomain:acme"}, res.calls)
+}
+
+func TestHostOrg_UnknownHostIsCachedToo(t *testing.T) {
+	res := &fakeHostOrgs{}
+	mw := middleware.HostOrg(res, testLinks)
+
+	assert.Nil(t, serveOnHost(t, mw, "unknown.example"))
+	assert.Nil(t, serveOnHost(t, mw, "unknown.example"))
+	assert.Len(t, res.calls, 1)
+}
+
+func TestHostOrg_SkipsOwnAndLocalHosts(t *testing.T) {
+	res := &fakeHostOrgs{}
+	mw := middleware.HostOrg(res, testLinks)
+
+	for _, host := range []string{"app.trakrf.id", "APP.trakrf.id:8443", "localhost:8080", "127.0.0.1", "[::1]:8080", "backend"} {
+		assert.Nil(t, serveOnHost(t, mw, host), host)
+	}
+	assert.Empty(t, res.calls)
+}
+
+func TestHostOrg_LookupErrorPassesThrough(t *testing.T) {
+	res := &fakeHostOrgs{err: errors.New("db down")}
+	mw := middleware.HostOrg(res, testLinks)
+
+	assert.Nil(t, serveOnHost(t, mw, "track.acme.example"))
+	assert.Nil(t, serveOnHost(t, mw, "track.acme.example"))
+	assert.Len(t, res.calls, 2, "failures are not cached")
+}
+
+// fakeMemberships is a test-only OrgRoleStore.
+type fakeMemberships struct {
+	roles      map[int]models.OrgRole // org id -> role of the caller
+	superadmin bool
+}
+
+func (f *fakeMemberships) GetUserOrgRole(ctx context.Context, userID, orgID int) (models.OrgRole, error) {
+	if role, ok := f.roles[orgID]; ok {
+		return role, nil
+	}
+	return "", middleware.ErrOrgUserNotFound
+}
+
+func (f *fakeMemberships) IsUserSuperadmin(ctx context.Context, userID int) (bool, error) {
+	return f.superadmin, nil
+}
+
+// serveScoped runs a session request whose token carries tokenOrg through
+// HostOrg and ScopeToHostOrg on host, where acme.trakrf.app is org 7. It
+// returns the status and the current org the next handler saw.
+func serveScoped(t *testing.T, store middleware.OrgRoleStore, claims *jwt.Claims, host string) (int, *int) {
+	t.Helper()
+	res := &fakeHostOrgs{subdomains: map[string]*organization.HostOrg{
+		"acme": {OrgID: 7, Identifier: "acme"},
+	}}
+	var seen *int
+	h := middleware.HostOrg(res, testLinks)(middleware.ScopeToHostOrg(store)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if c := middleware.GetUserClaims(r); c != nil {
+				seen = c.CurrentOrgID
+			}
+		})))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/assets", nil)
+	req.Host = host
+	if claims != nil {
+		req = req.WithContext(middleware.WithUserClaimsForTest(req.Context(), claims))
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code, seen
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestScopeToHostOrg_MemberIsRescoped(t *testing.T) {
+	store := &fakeMemberships{roles: map[int]models.OrgRole{7: models.RoleViewer}}
+
+	code, seen := serveScoped(t, store, &jwt.Claims{UserID: 3, CurrentOrgID: intPtr(4)}, "acme.trakrf.app")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, intPtr(7), seen)
+
+	code, seen = serveScoped(t, store, &jwt.Claims{UserID: 3}, "acme.trakrf.app")
+	assert.Equal(t, http.StatusOK, code, "an org-agnostic token takes the host's org")
+	assert.Equal(t, intPtr(7), seen)
+}
+
+func TestScopeToHostOrg_NonMemberForbidden(t *testing.T) {
+	store := &fakeMemberships{roles: map[int]models.OrgRole{4: models.RoleAdmin}}
+
+	code, seen := serveScoped(t, store, &jwt.Claims{UserID: 3, CurrentOrgID: intPtr(4)}, "acme.trakrf.app")
+	assert.Equal(t, http.StatusForbidden, code)
+	assert.Nil(t, seen)
+}
+
+func TestScopeToHostOrg_SuperadminAllowed(t *testing.T) {
+	store := &fakeMemberships{superadmin: true}
+
+	code, seen := serveScoped(t, store, &jwt.Claims{UserID: 1, CurrentOrgID: intPtr(4)}, "acme.trakrf.app")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, intPtr(7), seen)
+}
+
+func TestScopeToHostOrg_PassesThroughWithoutHostOrgOrSession(t *testing.T) {
+	store := &fakeMemberships{}
+
+	code, seen := serveScoped(t, store, &jwt.Claims{UserID: 3, CurrentOrgID: intPtr(4)}, "app.trakrf.id")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, intPtr(4), seen, "the token's org stands on the app host")
+
+	code, seen = serveScoped(t, store, nil, "acme.trakrf.app")
+	assert.Equal(t, http.StatusOK, code, "API-key requests keep their key's org")
+	assert.Nil(t, seen)
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -13,7 +15,9 @@ import (
 
 	"github.com/getsentry/sentry-go"
 	"github.com/oklog/ulid/v2"
+	"github.com/trakrf/platform/backend/internal/errorreport"
 	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/models/approval"
 	"github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
 	"github.com/trakrf/platform/backend/internal/util/jwt"
@@ -38,11 +42,17 @@ func RequestID(next http.Handler) http.Handler {
 	})
 }
 
-// Recovery catches panics and returns a 500 error response.
+// Recovery catches panics, reports them to the error sink tagged with the
+// request id, and returns a 500 error response.
 func Recovery(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
+				// A handler that has already started its response aborts it
+				// this way; let net/http close the connection.
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
 				requestID := GetRequestID(r.Context())
 
 				// Use zerolog instead of slog
@@ -52,6 +62,7 @@ func Recovery(next http.Handler) http.Handler {
 					Str("path", r.URL.Path).
 					Str("method", r.Method).
 					Msg("Panic recovered")
+				errorreport.Panic(r.Context(), err, errorreport.RequestTags(r, requestID))
 
 				httputil.WriteJSONError(w, r, http.StatusInternalServerError,
 					errors.ErrInternal, "Internal server error", requestID)
@@ -85,7 +96,7 @@ func CORS(next http.Handler) http.Handler {
 			// route (chi auto-serves it) and no route uses PUT. The prior list
 			// was a stale generic default that advertised PUT and omitted HEAD.
 			w.Header().Set("Access-Control-Allow-Methods", "GET, HEAD, POST, PATCH, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, "+ReaderKeyHeader+", "+approval.HeaderApprovalID)
 			w.Header().Set("Access-Control-Max-Age", "3600")
 
 			if r.Method == "OPTIONS" {
@@ -118,6 +129,48 @@ const oauthTokenPath = "/api/v1/oauth/token"
 // detail names both rather than under-reporting application/json (TRA-883).
 const oauthTokenUnsupportedMediaDetail = "Content-Type must be application/json or application/x-www-form-urlencoded"
 
+// epcisCapturePath additionally accepts application/ld+json, the media type
+// GS1 EPCIS 2.0 clients send capture documents with.
+const epcisCapturePath = "/api/v1/epcis/capture"
+
+// locationsApplyPath additionally accepts YAML, the format customers keep
+// their warehouse layouts in under version control.
+const locationsApplyPath = "/api/v1/locations:apply"
+
+// AvatarUploadPath takes the raw image bytes of a profile picture, so it
+// accepts the image types in AvatarContentTypes instead of JSON.
+const AvatarUploadPath = "/api/v1/me/avatar"
+
+// AvatarContentTypes are the image formats an avatar may be uploaded as.
+var AvatarContentTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp"}
+
+// avatarUnsupportedMediaDetail is the 415 detail for AvatarUploadPath.
+const avatarUnsupportedMediaDetail = "Content-Type must be image/png, image/jpeg, image/gif or image/webp"
+
+// ConditionPhotoPathPattern (matched with path.Match) takes the raw image
+// bytes of a condition report photo, so it accepts the image types in
+// PhotoContentTypes instead of JSON.
+const ConditionPhotoPathPattern = "/api/v1/assets/*/condition-reports/*/photos"
+
+// PhotoContentTypes are the image formats a condition photo may be uploaded
+// as.
+var PhotoContentTypes = []string{"image/png", "image/jpeg", "image/webp"}
+
+// photoUnsupportedMediaDetail is the 415 detail for ConditionPhotoPathPattern.
+const photoUnsupportedMediaDetail = "Content-Type must be image/png, image/jpeg or image/webp"
+
+// DocumentFilePathPattern (matched with path.Match) takes the raw bytes of a
+// document's scanned copy, so it accepts the types in DocumentContentTypes
+// instead of JSON.
+const DocumentFilePathPattern = "/api/v1/documents/*/file"
+
+// DocumentContentTypes are the formats a document's scanned copy may be
+// uploaded as.
+var DocumentContentTypes = []string{"application/pdf", "image/png", "image/jpeg"}
+
+// documentUnsupportedMediaDetail is the 415 detail for DocumentFilePathPattern.
+const documentUnsupportedMediaDetail = "Content-Type must be application/pdf, image/png or image/jpeg"
+
 // ContentType enforces declared Content-Type per method (BB32 D4 / TRA-703).
 // The public docs commit to a strict per-method matrix on every write
 // endpoint, and missing or otherwise-unlisted Content-Type returns 415 with
@@ -136,7 +189,11 @@ const oauthTokenUnsupportedMediaDetail = "Content-Type must be application/json
 // multipart/form-data and is the only path on which that media type is
 // accepted. Sending multipart to any public POST endpoint returns 415,
 // matching the public docs' "any other media type … returns 415 regardless
-// of method" promise.
+// of method" promise. The EPCIS capture endpoint (POST /api/v1/epcis/capture)
+// also accepts application/ld+json, and the declarative location apply (PUT
+// /api/v1/locations:apply) also accepts application/yaml. Condition report
+// photo uploads take raw image bytes, and document file uploads raw PDF or
+// image bytes.
 func ContentType(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost && r.Method != http.MethodPut && r.Method != http.MethodPatch {
@@ -169,6 +226,48 @@ func ContentType(next http.Handler) http.Handler {
 			return
 		}
 
+		if r.Method == http.MethodPost && r.URL.Path == epcisCapturePath &&
+			(ct == "application/ld+json" || ct == "application/ld+json; charset=utf-8") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method == http.MethodPut && r.URL.Path == locationsApplyPath && IsYAMLContentType(ct) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Method == http.MethodPut && r.URL.Path == AvatarUploadPath {
+			if slices.Contains(AvatarContentTypes, ct) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			httputil.Respond415Detail(w, r, avatarUnsupportedMediaDetail, GetRequestID(r.Context()))
+			return
+		}
+
+		if r.Method == http.MethodPost {
+			if ok, _ := path.Match(ConditionPhotoPathPattern, r.URL.Path); ok {
+				if slices.Contains(PhotoContentTypes, ct) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				httputil.Respond415Detail(w, r, photoUnsupportedMediaDetail, GetRequestID(r.Context()))
+				return
+			}
+		}
+
+		if r.Method == http.MethodPut {
+			if ok, _ := path.Match(DocumentFilePathPattern, r.URL.Path); ok {
+				if slices.Contains(DocumentContentTypes, ct) {
+					next.ServeHTTP(w, r)
+					return
+				}
+				httputil.Respond415Detail(w, r, documentUnsupportedMediaDetail, GetRequestID(r.Context()))
+				return
+			}
+		}
+
 		allowed := false
 		switch r.Method {
 		case http.MethodPost, http.MethodPut:
@@ -185,6 +284,17 @@ func ContentType(next http.Handler) http.Handler {
 	})
 }
 
+// IsYAMLContentType reports whether ct names a YAML media type. There is no
+// single registered one in common use, so the usual spellings are accepted.
+func IsYAMLContentType(ct string) bool {
+	mt, _, _ := strings.Cut(ct, ";")
+	switch strings.TrimSpace(mt) {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+	return false
+}
+
 // RejectQueryParams returns a per-route middleware that rejects requests
 // carrying any query parameter whose key is not in `allowed`. Attach to
 // endpoints that do not run through httputil.ParseListParams (single-resource
@@ -286,6 +396,7 @@ func Auth(next http.Handler) http.Handler {
 			return
 		}
 
+		logger.SetUser(r.Context(), claims.UserID, claims.CurrentOrgID)
 		ctx := context.WithValue(r.Context(), UserClaimsKey, claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/trakrf/platform/backend/internal/errorreport"
 	apierrors "github.com/trakrf/platform/backend/internal/models/errors"
 )
 
@@ -180,6 +182,84 @@ func TestContentType(t *testing.T) {
 			expectedStatus: http.StatusUnsupportedMediaType,
 			description:    "Form-encoded not allowed",
 		},
+		{
+			name:           "PUT to /api/v1/locations:apply with application/yaml",
+			method:         http.MethodPut,
+			path:           "/api/v1/locations:apply",
+			contentType:    "application/yaml",
+			expectedStatus: http.StatusOK,
+			description:    "Location apply accepts YAML",
+		},
+		{
+			name:           "PUT to /api/v1/locations:apply with application/json",
+			method:         http.MethodPut,
+			path:           "/api/v1/locations:apply",
+			contentType:    "application/json",
+			expectedStatus: http.StatusOK,
+			description:    "Location apply accepts JSON",
+		},
+		{
+			name:           "PUT to /api/v1/me/avatar with image/png",
+			method:         http.MethodPut,
+			path:           "/api/v1/me/avatar",
+			contentType:    "image/png",
+			expectedStatus: http.StatusOK,
+			description:    "Avatar upload accepts images",
+		},
+		{
+			name:           "PUT to /api/v1/me/avatar with application/json",
+			method:         http.MethodPut,
+			path:           "/api/v1/me/avatar",
+			contentType:    "application/json",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			description:    "Avatar upload takes raw image bytes only",
+		},
+		{
+			name:           "POST condition photo with image/jpeg",
+			method:         http.MethodPost,
+			path:           "/api/v1/assets/12/condition-reports/34/photos",
+			contentType:    "image/jpeg",
+			expectedStatus: http.StatusOK,
+			description:    "Condition photo upload accepts images",
+		},
+		{
+			name:           "POST condition photo with application/json",
+			method:         http.MethodPost,
+			path:           "/api/v1/assets/12/condition-reports/34/photos",
+			contentType:    "application/json",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			description:    "Condition photo upload takes raw image bytes only",
+		},
+		{
+			name:           "PUT document file with application/pdf",
+			method:         http.MethodPut,
+			path:           "/api/v1/documents/56/file",
+			contentType:    "application/pdf",
+			expectedStatus: http.StatusOK,
+			description:    "Document file upload accepts PDFs",
+		},
+		{
+			name:           "PUT document file with application/json",
+			method:         http.MethodPut,
+			path:           "/api/v1/documents/56/file",
+			contentType:    "application/json",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			description:    "Document file upload takes raw file bytes only",
+		},
+		{
+			name:           "PUT elsewhere with image/png",
+			method:         http.MethodPut,
+			contentType:    "image/png",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			description:    "Images are only accepted on the avatar, photo and document file uploads",
+		},
+		{
+			name:           "PUT elsewhere with application/yaml",
+			method:         http.MethodPut,
+			contentType:    "application/yaml",
+			expectedStatus: http.StatusUnsupportedMediaType,
+			description:    "YAML is only accepted on the location apply",
+		},
 		// DELETE requests - Content-Type not checked
 		{
 			name:           "DELETE request with any Content-Type",
@@ -357,6 +437,28 @@ func TestContentType_OAuthTokenAcceptsFormUrlencoded(t *testing.T) {
 	}
 }
 
+// TestContentType_EPCISCaptureAcceptsJSONLD verifies application/ld+json is
+// accepted on the EPCIS capture path only.
+func TestContentType_EPCISCaptureAcceptsJSONLD(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for path, want := range map[string]int{
+		"/api/v1/epcis/capture":  http.StatusOK,
+		"/api/v1/inventory/save": http.StatusUnsupportedMediaType,
+	} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/ld+json")
+		rr := httptest.NewRecorder()
+
+		ContentType(next).ServeHTTP(rr, req)
+
+		if rr.Code != want {
+			t.Errorf("%s: expected %d, got %d", path, want, rr.Code)
+		}
+	}
+}
+
 // TestContentType_OAuthTokenUnsupportedMediaNamesBothTypes verifies the 415
 // detail on /api/v1/oauth/token names BOTH accepted media types, not just
 // application/json — the endpoint genuinely accepts form-urlencoded as well
@@ -655,3 +757,38 @@ func TestCORS_EnabledOriginShortCircuitsOptions(t *testing.T) {
 		t.Errorf("Access-Control-Allow-Methods must not advertise PUT — no route uses it")
 	}
 }
+
+type panicSink struct {
+	recovered any
+	tags      errorreport.Tags
+}
+
+func (s *panicSink) CapturePanic(_ context.Context, recovered any, tags errorreport.Tags) {
+	s.recovered, s.tags = recovered, tags
+}
+
+func (s *panicSink) CaptureError(context.Context, error, errorreport.Tags) {}
+
+func TestRecovery_ReportsPanicWithRequestID(t *testing.T) {
+	sink := &panicSink{}
+	errorreport.SetSink(sink)
+	t.Cleanup(func() { errorreport.SetSink(nil) })
+
+	h := RequestID(Recovery(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})))
+	req := httptest.NewRequest("GET", "/api/v1/assets", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	if sink.recovered != "boom" {
+		t.Fatalf("reported %v, want boom", sink.recovered)
+	}
+	if sink.tags["request_id"] != "req-1" || sink.tags["path"] != "/api/v1/assets" {
+		t.Fatalf("tags = %v", sink.tags)
+	}
+}
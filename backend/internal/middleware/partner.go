@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// PartnerAdminStore defines the storage methods needed by RequirePartnerAdmin.
+type PartnerAdminStore interface {
+	IsUserSuperadmin(ctx context.Context, userID int) (bool, error)
+	IsPartnerAdmin(ctx context.Context, partnerID, userID int) (bool, error)
+}
+
+// RequirePartnerAdmin checks that the session user administers the partner
+// named by the :partnerId URL parameter. Partner admins need no membership
+// in the partner's orgs; superadmins pass as on every cross-org surface.
+func RequirePartnerAdmin(store PartnerAdminStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			requestID := GetRequestID(ctx)
+
+			claims := GetUserClaims(r)
+			if claims == nil {
+				httputil.Respond401(w, r, "Session authentication required", requestID)
+				return
+			}
+
+			partnerID, err := httputil.ParseSurrogateID("partnerId", chi.URLParam(r, "partnerId"))
+			if err != nil {
+				httputil.RespondPathParamError(w, r, err, requestID)
+				return
+			}
+
+			ok, err := store.IsPartnerAdmin(ctx, partnerID, claims.UserID)
+			if err == nil && !ok {
+				ok, err = store.IsUserSuperadmin(ctx, claims.UserID)
+			}
+			if err != nil {
+				logger.Get().Error().
+					Err(err).
+					Int("user_id", claims.UserID).
+					Int("partner_id", partnerID).
+					Str("request_id", requestID).
+					Msg("Failed to check partner admin")
+				httputil.WriteJSONError(w, r, http.StatusInternalServerError,
+					errors.ErrInternal, "Failed to check permissions", requestID)
+				return
+			}
+
+			if !ok {
+				logger.Get().Warn().
+					Int("user_id", claims.UserID).
+					Int("partner_id", partnerID).
+					Str("path", r.URL.Path).
+					Str("method", r.Method).
+					Str("request_id", requestID).
+					Msg("Partner admin access denied")
+				httputil.WriteJSONError(w, r, http.StatusForbidden,
+					errors.ErrForbidden, "Partner admin privileges required", requestID)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
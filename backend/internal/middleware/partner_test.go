@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// fakePartnerAdminStore is a test-only PartnerAdminStore: user 1 administers
+// partner 5, user 2 is a superadmin.
+type fakePartnerAdminStore struct{}
+
+func (fakePartnerAdminStore) IsUserSuperadmin(ctx context.Context, userID int) (bool, error) {
+	return userID == 2, nil
+}
+
+func (fakePartnerAdminStore) IsPartnerAdmin(ctx context.Context, partnerID, userID int) (bool, error) {
+	return partnerID == 5 && userID == 1, nil
+}
+
+func TestRequirePartnerAdmin(t *testing.T) {
+	cases := []struct {
+		name   string
+		userID int
+		path   string
+		want   int
+	}{
+		{"partner admin", 1, "/api/v1/partners/5/rollup", http.StatusOK},
+		{"admin of another partner", 1, "/api/v1/partners/6/rollup", http.StatusForbidden},
+		{"superadmin", 2, "/api/v1/partners/6/rollup", http.StatusOK},
+		{"regular user", 3, "/api/v1/partners/5/rollup", http.StatusForbidden},
+		{"bad id", 1, "/api/v1/partners/abc/rollup", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			var reached bool
+			r.With(middleware.RequirePartnerAdmin(fakePartnerAdminStore{})).
+				Get("/api/v1/partners/{partnerId}/rollup", nextReached(&reached).ServeHTTP)
+
+			req := httptest.NewRequest(http.MethodGet, c.path, nil)
+			claims := &jwt.Claims{UserID: c.userID}
+			req = req.WithContext(context.WithValue(req.Context(), middleware.UserClaimsKey, claims))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, c.want, w.Code, w.Body.String())
+			assert.Equal(t, c.want == http.StatusOK, reached)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/trakrf/platform/backend/internal/models/maintenance"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// maintenanceCacheTTL bounds how long the window list is reused. Opening or
+// ending a window takes effect on every replica within it.
+const maintenanceCacheTTL = 5 * time.Second
+
+// maintenanceRetryAfter is the Retry-After sent for a window with no end.
+const maintenanceRetryAfter = 5 * time.Minute
+
+// MaintenanceAdminPrefix is never made read-only, so a superadmin can always
+// open or end the window that blocks everything else. Other admin writes are
+// blocked like any other.
+const MaintenanceAdminPrefix = "/api/v1/admin/maintenance-windows"
+
+// MaintenanceSource lists the maintenance windows that have not ended.
+// Satisfied by *storage.Storage (ListMaintenanceWindows).
+type MaintenanceSource interface {
+	ListMaintenanceWindows(ctx context.Context) ([]maintenance.Window, error)
+}
+
+// ReadOnlyMode rejects mutations with 503 and Retry-After while a
+// maintenance window covers the request's org, or every org. Apply it after
+// the auth middleware of a route group; reads always pass. The request's org
+// is the one an /api/v1/orgs/{id} route names, else the caller's current
+// org; requests with neither are covered by global windows only. The window
+// list is cached for maintenanceCacheTTL; a lookup failure keeps the last
+// list (or none), so the switch never fails a request on its own.
+func ReadOnlyMode(source MaintenanceSource) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	var windows []maintenance.Window
+	var expires time.Time
+
+	// The lookup runs outside the lock so a slow database does not queue
+	// every mutation behind it; concurrent refreshes may both query, and
+	// the last one to finish wins.
+	current := func(ctx context.Context, now time.Time) []maintenance.Window {
+		mu.Lock()
+		cached, fresh := windows, now.Before(expires)
+		mu.Unlock()
+		if fresh {
+			return cached
+		}
+		list, err := source.ListMaintenanceWindows(ctx)
+		if err != nil {
+			slog.WarnContext(ctx, "maintenance window lookup failed", "error", err)
+			return cached
+		}
+		mu.Lock()
+		windows, expires = list, now.Add(maintenanceCacheTTL)
+		mu.Unlock()
+		return list
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isMutation(r.Method) || strings.HasPrefix(r.URL.Path, MaintenanceAdminPrefix) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			orgID := maintenanceTargetOrg(r)
+			now := time.Now()
+
+			var blocking *maintenance.Window
+			var retryAfter time.Duration
+			for _, mw := range current(r.Context(), now) {
+				if !mw.ActiveAt(now) || !mw.Covers(orgID) {
+					continue
+				}
+				wait := maintenanceRetryAfter
+				if mw.EndsAt != nil {
+					wait = mw.EndsAt.Sub(now)
+				}
+				if blocking == nil || wait > retryAfter {
+					blocking, retryAfter = &mw, wait
+				}
+			}
+			if blocking == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scope := "TrakRF is"
+			if blocking.OrgID != nil {
+				scope = "This organization is"
+			}
+			httputil.Respond503(w, r, retryAfter,
+				scope+" read-only for maintenance: "+blocking.Reason,
+				GetRequestID(r.Context()))
+		})
+	}
+}
+
+// maintenanceTargetOrg is the org a mutation targets: the one named by the
+// :orgId or :id parameter of an /api/v1/orgs/ route, else the caller's
+// current org, else 0.
+func maintenanceTargetOrg(r *http.Request) int {
+	if strings.HasPrefix(r.URL.Path, "/api/v1/orgs/") {
+		s := chi.URLParam(r, "orgId")
+		if s == "" {
+			s = chi.URLParam(r, "id")
+		}
+		if orgID, err := strconv.Atoi(s); err == nil {
+			return orgID
+		}
+	}
+	orgID, _ := GetRequestOrgID(r)
+	return orgID
+}
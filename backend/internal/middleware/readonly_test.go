@@ -0,0 +1,174 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/maintenance"
+)
+
+// fakeWindows is a test-only MaintenanceSource.
+type fakeWindows struct {
+	windows []maintenance.Window
+	err     error
+	calls   int
+}
+
+func (f *fakeWindows) ListMaintenanceWindows(ctx context.Context) ([]maintenance.Window, error) {
+	f.calls++
+	return f.windows, f.err
+}
+
+func serveReadOnly(src middleware.MaintenanceSource, method, path string, orgID int) (*httptest.ResponseRecorder, bool) {
+	var reached bool
+	r := httptest.NewRequest(method, path, nil)
+	if orgID != 0 {
+		r = withOrg(r, orgID)
+	}
+	w := httptest.NewRecorder()
+	middleware.ReadOnlyMode(src)(nextReached(&reached)).ServeHTTP(w, r)
+	return w, reached
+}
+
+func TestReadOnlyMode_GlobalWindowBlocksMutations(t *testing.T) {
+	ends := time.Now().Add(90 * time.Second)
+	src := &fakeWindows{windows: []maintenance.Window{
+		{ID: 1, Reason: "Database upgrade", StartsAt: time.Now().Add(-time.Minute), EndsAt: &ends},
+	}}
+
+	w, reached := serveReadOnly(src, http.MethodPost, "/api/v1/assets", 42)
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "90", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "Database upgrade")
+
+	_, reached = serveReadOnly(src, http.MethodDelete, "/api/v1/assets/1", 0)
+	assert.False(t, reached, "requests without an org are covered by global windows")
+
+	_, reached = serveReadOnly(src, http.MethodGet, "/api/v1/assets", 42)
+	assert.True(t, reached, "reads stay open")
+}
+
+func TestReadOnlyMode_OrgWindowBlocksOnlyThatOrg(t *testing.T) {
+	org := 42
+	src := &fakeWindows{windows: []maintenance.Window{
+		{ID: 1, OrgID: &org, Reason: "Data repair", StartsAt: time.Now().Add(-time.Minute)},
+	}}
+
+	w, reached := serveReadOnly(src, http.MethodPatch, "/api/v1/assets/1", 42)
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "300", w.Header().Get("Retry-After"), "an open-ended window asks for a retry in five minutes")
+
+	_, reached = serveReadOnly(src, http.MethodPatch, "/api/v1/assets/1", 7)
+	assert.True(t, reached)
+}
+
+func TestReadOnlyMode_InactiveWindowsAndAdminRoutesPass(t *testing.T) {
+	past := time.Now().Add(-time.Second)
+	src := &fakeWindows{windows: []maintenance.Window{
+		{ID: 1, Reason: "Scheduled", StartsAt: time.Now().Add(time.Hour)},
+		{ID: 2, Reason: "Over", StartsAt: time.Now().Add(-time.Hour), EndsAt: &past},
+	}}
+	_, reached := serveReadOnly(src, http.MethodPost, "/api/v1/assets", 42)
+	assert.True(t, reached, "scheduled and ended windows do not block")
+
+	src.windows = append(src.windows, maintenance.Window{ID: 3, Reason: "Now", StartsAt: time.Now().Add(-time.Minute)})
+	_, reached = serveReadOnly(src, http.MethodPost, "/api/v1/admin/maintenance-windows/3/end", 0)
+	assert.True(t, reached, "a superadmin can always end a window")
+
+	_, reached = serveReadOnly(src, http.MethodPost, "/api/v1/admin/partners", 0)
+	assert.False(t, reached, "other admin writes are blocked")
+}
+
+func TestReadOnlyMode_OrgRoutesUseTheRouteOrg(t *testing.T) {
+	org := 42
+	src := &fakeWindows{windows: []maintenance.Window{
+		{ID: 1, OrgID: &org, Reason: "Data repair", StartsAt: time.Now().Add(-time.Minute)},
+	}}
+	var reached bool
+	r := chi.NewRouter()
+	r.With(middleware.ReadOnlyMode(src)).Post("/api/v1/orgs/{id}/api-keys", nextReached(&reached).ServeHTTP)
+
+	// The caller's current org is 7, but the route targets org 42.
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, withOrg(httptest.NewRequest(http.MethodPost, "/api/v1/orgs/42/api-keys", nil), 7))
+	assert.False(t, reached)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	r.ServeHTTP(httptest.NewRecorder(), withOrg(httptest.NewRequest(http.MethodPost, "/api/v1/orgs/7/api-keys", nil), 42))
+	assert.True(t, reached, "a window on the caller's current org does not cover another org's routes")
+}
+
+func TestReadOnlyMode_CachesAndFailsOpen(t *testing.T) {
+	src := &fakeWindows{err: errors.New("db down")}
+	mw := middleware.ReadOnlyMode(src)
+
+	for i := 0; i < 3; i++ {
+		var reached bool
+		mw(nextReached(&reached)).ServeHTTP(httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodPost, "/api/v1/assets", nil))
+		assert.True(t, reached, "a failed lookup never blocks writes")
+	}
+	assert.Equal(t, 3, src.calls, "failures are not cached")
+
+	src.err = nil
+	for i := 0; i < 3; i++ {
+		var reached bool
+		mw(nextReached(&reached)).ServeHTTP(httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodPost, "/api/v1/assets", nil))
+	}
+	assert.Equal(t, 4, src.calls, "the list is reused within the TTL")
+}
+
+// slowWindows blocks its first lookup until release is closed.
+type slowWindows struct {
+	mu      sync.Mutex
+	calls   int
+	release chan struct{}
+}
+
+func (s *slowWindows) ListMaintenanceWindows(ctx context.Context) ([]maintenance.Window, error) {
+	s.mu.Lock()
+	s.calls++
+	first := s.calls == 1
+	s.mu.Unlock()
+	if first {
+		<-s.release
+	}
+	return nil, nil
+}
+
+func TestReadOnlyMode_SlowLookupDoesNotHoldTheLock(t *testing.T) {
+	src := &slowWindows{release: make(chan struct{})}
+	mw := middleware.ReadOnlyMode(src)
+	serve := func() {
+		var reached bool
+		mw(nextReached(&reached)).ServeHTTP(httptest.NewRecorder(),
+			httptest.NewRequest(http.MethodPost, "/api/v1/assets", nil))
+	}
+
+	go serve()
+	assert.Eventually(t, func() bool {
+		src.mu.Lock()
+		defer src.mu.Unlock()
+		return src.calls == 1
+	}, time.Second, time.Millisecond)
+
+	done := make(chan struct{})
+	go func() { serve(); close(done) }()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Error("a request waited on another request's lookup")
+	}
+	close(src.release)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"sync"
+)
+
+// SandboxHeader is set to "true" on every authenticated response served for a
+// sandbox org, so integrators and the UI can tell test data from production
+// without another request.
+const SandboxHeader = "X-TrakRF-Sandbox"
+
+// SandboxChecker reports whether an org is a sandbox. Satisfied by
+// *storage.Storage (OrgIsSandbox).
+type SandboxChecker interface {
+	OrgIsSandbox(ctx context.Context, orgID int) (bool, error)
+}
+
+// SandboxBanner sets SandboxHeader on responses for sandbox orgs. Apply it
+// after the auth middleware of a route group. An org's sandbox flag is fixed
+// when it is created, so answers are cached for the life of the process. A
+// lookup failure only drops the header; it never fails the request.
+func SandboxBanner(checker SandboxChecker) func(http.Handler) http.Handler {
+	var cache sync.Map // org id -> bool
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			orgID, err := GetRequestOrgID(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			sandbox, ok := cache.Load(orgID)
+			if !ok {
+				isSandbox, err := checker.OrgIsSandbox(r.Context(), orgID)
+				if err != nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+				cache.Store(orgID, isSandbox)
+				sandbox = isSandbox
+			}
+			if sandbox.(bool) {
+				w.Header().Set(SandboxHeader, "true")
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trakrf/platform/backend/internal/middleware"
+)
+
+// fakeSandboxChecker is a test-only SandboxChecker.
+type fakeSandboxChecker struct {
+	sandbox bool
+	err     error
+	calls   int
+}
+
+func (f *fakeSandboxChecker) OrgIsSandbox(ctx context.Context, orgID int) (bool, error) {
+	f.calls++
+	return f.sandbox, f.err
+}
+
+func TestSandboxBanner_SetsHeaderForSandboxOrg(t *testing.T) {
+	chk := &fakeSandboxChecker{sandbox: true}
+	h := middleware.SandboxBanner(chk)(nextReached(new(bool)))
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, withOrg(httptest.NewRequest(http.MethodGet, "/api/v1/assets", nil), 42))
+		assert.Equal(t, "true", w.Header().Get(middleware.SandboxHeader))
+	}
+	assert.Equal(t, 1, chk.calls, "the sandbox flag is cached per org")
+}
+
+func TestSandboxBanner_NoHeaderForProductionOrg(t *testing.T) {
+	chk := &fakeSandboxChecker{sandbox: false}
+	w := httptest.NewRecorder()
+	middleware.SandboxBanner(chk)(nextReached(new(bool))).
+		ServeHTTP(w, withOrg(httptest.NewRequest(http.MethodGet, "/api/v1/assets", nil), 42))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get(middleware.SandboxHeader))
+}
+
+func TestSandboxBanner_LookupErrorPassesThrough(t *testing.T) {
+	chk := &fakeSandboxChecker{err: errors.New("db down")}
+	var reached bool
+	w := httptest.NewRecorder()
+	middleware.SandboxBanner(chk)(nextReached(&reached)).
+		ServeHTTP(w, withOrg(httptest.NewRequest(http.MethodPost, "/api/v1/assets", nil), 42))
+
+	assert.True(t, reached)
+	assert.Empty(t, w.Header().Get(middleware.SandboxHeader))
+}
+
+func TestSandboxBanner_NoOrgContextSkipsLookup(t *testing.T) {
+	chk := &fakeSandboxChecker{sandbox: true}
+	w := httptest.NewRecorder()
+	middleware.SandboxBanner(chk)(nextReached(new(bool))).
+		ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/assets", nil))
+
+	assert.Zero(t, chk.calls)
+	assert.Empty(t, w.Header().Get(middleware.SandboxHeader))
+}
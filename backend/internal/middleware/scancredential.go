@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/logger"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/scandevice"
+	"github.com/trakrf/platform/backend/internal/util/apisecret"
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+// ReaderKeyHeader carries a scan device's reader credential on scans made
+// over a user session by a fixed reader's host.
+const ReaderKeyHeader = "X-Reader-Key"
+
+// ScanCredentialStore resolves reader credentials and the org's scan
+// settings (mockable).
+type ScanCredentialStore interface {
+	GetScanReaderByCredential(ctx context.Context, orgID int, credentialHash string) (*scandevice.Reader, error)
+	GetScanSettings(ctx context.Context, orgID int) (*organization.ScanSettings, error)
+}
+
+// ScanSource is what a scan request was submitted with: a registered reader
+// (DeviceID), an API key (APIKeyJTI), or, in an org with session scans on, a
+// browser session with neither (UserID). Exactly one is set.
+type ScanSource struct {
+	DeviceID  int
+	APIKeyJTI string
+	UserID    int
+}
+
+// Label names the source for metrics and logs: "scan_device:<id>",
+// "api_key:<jti>" or "user:<id>", or "unbound" for a nil source (a handler
+// mounted without RequireScanCredential, as in unit tests).
+func (s *ScanSource) Label() string {
+	switch {
+	case s == nil:
+		return "unbound"
+	case s.APIKeyJTI != "":
+		return "api_key:" + s.APIKeyJTI
+	case s.UserID != 0:
+		return "user:" + strconv.Itoa(s.UserID)
+	}
+	return "scan_device:" + strconv.Itoa(s.DeviceID)
+}
+
+const scanSourceKey contextKey = "scan_source"
+
+// GetScanSource returns the source RequireScanCredential resolved, or nil.
+func GetScanSource(r *http.Request) *ScanSource {
+	s, _ := r.Context().Value(scanSourceKey).(*ScanSource)
+	return s
+}
+
+// WithScanSourceForTest attaches a scan source to the context.
+// Exported for tests only.
+func WithScanSourceForTest(ctx context.Context, s *ScanSource) context.Context {
+	return context.WithValue(ctx, scanSourceKey, s)
+}
+
+// RequireScanCredential binds a scan to what produced it, so the unknown-tag
+// budget (scanguard) is kept per reader, API key or user. An API-key call is
+// its own credential. A session call must present the credential of one of
+// the current org's active scan devices in ReaderKeyHeader; without it any
+// signed-in user could submit or look up arbitrary tag values. Only an org
+// that has turned on session scans (organization.ScanSettings) lets a session
+// call without it through, bound to the signed-in user. Mount after Auth or
+// EitherAuth.
+func RequireScanCredential(store ScanCredentialStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reqID := GetRequestID(r.Context())
+			if p := GetAPIKeyPrincipal(r); p != nil {
+				src := &ScanSource{APIKeyJTI: p.JTI}
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scanSourceKey, src)))
+				return
+			}
+
+			orgID, err := GetRequestOrgID(r)
+			if err != nil {
+				httputil.RespondMissingOrgContext(w, r, reqID)
+				return
+			}
+			key := strings.TrimSpace(r.Header.Get(ReaderKeyHeader))
+			if key == "" {
+				claims := GetUserClaims(r)
+				if claims == nil {
+					httputil.RespondMissingOrgContext(w, r, reqID)
+					return
+				}
+				ss, err := store.GetScanSettings(r.Context(), orgID)
+				if err != nil {
+					httputil.RespondStorageError(w, r, err, reqID)
+					return
+				}
+				if ss == nil || !ss.SessionScans {
+					httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden,
+						"Scans require a registered reader credential ("+ReaderKeyHeader+" header) or an API key", reqID)
+					return
+				}
+				src := &ScanSource{UserID: claims.UserID}
+				next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scanSourceKey, src)))
+				return
+			}
+			rd, err := store.GetScanReaderByCredential(r.Context(), orgID, apisecret.Hash(key))
+			if err != nil {
+				httputil.RespondStorageError(w, r, err, reqID)
+				return
+			}
+			if rd == nil {
+				logger.Get().Warn().
+					Str("request_id", reqID).
+					Int("org_id", orgID).
+					Str("path", r.URL.Path).
+					Msg("scan with unknown or revoked reader credential")
+				httputil.WriteJSONError(w, r, http.StatusForbidden, modelerrors.ErrForbidden,
+					"Invalid or revoked reader credential", reqID)
+				return
+			}
+
+			src := &ScanSource{DeviceID: rd.DeviceID}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scanSourceKey, src)))
+		})
+	}
+}
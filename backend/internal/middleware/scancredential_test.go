@@ -0,0 +1,120 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trakrf/platform/backend/internal/middleware"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/scandevice"
+	"github.com/trakrf/platform/backend/internal/util/apisecret"
+	"github.com/trakrf/platform/backend/internal/util/jwt"
+)
+
+// fakeScanCredentialStore is a test-only ScanCredentialStore knowing one
+// credential of org 42's device 7, and whether org 42 has session scans on.
+type fakeScanCredentialStore struct {
+	hash         string
+	sessionScans bool
+	calls        int
+}
+
+func (f *fakeScanCredentialStore) GetScanReaderByCredential(ctx context.Context, orgID int, credentialHash string) (*scandevice.Reader, error) {
+	f.calls++
+	if orgID != 42 || credentialHash != f.hash {
+		return nil, nil
+	}
+	return &scandevice.Reader{DeviceID: 7, OrgID: 42, Name: "Dock door 1"}, nil
+}
+
+func (f *fakeScanCredentialStore) GetScanSettings(ctx context.Context, orgID int) (*organization.ScanSettings, error) {
+	if orgID != 42 {
+		return nil, nil
+	}
+	return &organization.ScanSettings{SessionScans: f.sessionScans}, nil
+}
+
+func sessionScanRequest(orgID int, readerKey string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/lookup/tags", nil)
+	r = r.WithContext(middleware.WithUserClaimsForTest(r.Context(), &jwt.Claims{UserID: 1, CurrentOrgID: &orgID}))
+	if readerKey != "" {
+		r.Header.Set(middleware.ReaderKeyHeader, readerKey)
+	}
+	return r
+}
+
+func serveScanCredential(store *fakeScanCredentialStore, r *http.Request) (*httptest.ResponseRecorder, *middleware.ScanSource) {
+	var src *middleware.ScanSource
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		src = middleware.GetScanSource(r)
+		w.WriteHeader(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	middleware.RequireScanCredential(store)(next).ServeHTTP(w, r)
+	return w, src
+}
+
+func TestRequireScanCredential_SessionWithReaderKeyNeedsRegisteredReader(t *testing.T) {
+	store := &fakeScanCredentialStore{hash: apisecret.Hash("trakrf_reader")}
+
+	w, src := serveScanCredential(store, sessionScanRequest(42, "trakrf_reader"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, src) {
+		assert.Equal(t, "scan_device:7", src.Label())
+	}
+
+	w, _ = serveScanCredential(store, sessionScanRequest(42, "trakrf_revoked"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	// Another org's session cannot borrow the reader.
+	w, _ = serveScanCredential(store, sessionScanRequest(43, "trakrf_reader"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestRequireScanCredential_SessionWithoutReaderKeyRefusedByDefault(t *testing.T) {
+	store := &fakeScanCredentialStore{hash: apisecret.Hash("trakrf_reader")}
+
+	w, src := serveScanCredential(store, sessionScanRequest(42, ""))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), middleware.ReaderKeyHeader)
+	assert.Nil(t, src)
+}
+
+// An org that turns on session scans lets the SPA scan without a reader
+// credential; those scans are bound to, and throttled per, the signed-in
+// user.
+func TestRequireScanCredential_SessionScansOptInBindsToUser(t *testing.T) {
+	store := &fakeScanCredentialStore{hash: apisecret.Hash("trakrf_reader"), sessionScans: true}
+
+	w, src := serveScanCredential(store, sessionScanRequest(42, ""))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, store.calls, "no reader lookup without a reader credential")
+	if assert.NotNil(t, src) {
+		assert.Equal(t, "user:1", src.Label())
+	}
+}
+
+func TestRequireScanCredential_APIKeyIsItsOwnCredential(t *testing.T) {
+	store := &fakeScanCredentialStore{}
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/inventory/save", nil)
+	r = r.WithContext(middleware.WithAPIKeyPrincipalForTest(r.Context(), &middleware.APIKeyPrincipal{OrgID: 42, JTI: "k1"}))
+
+	w, src := serveScanCredential(store, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, store.calls, "no reader lookup for API keys")
+	if assert.NotNil(t, src) {
+		assert.Equal(t, "api_key:k1", src.Label())
+	}
+}
+
+func TestRequireScanCredential_NoOrgContext(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/lookup/tags", nil)
+	r = r.WithContext(middleware.WithUserClaimsForTest(r.Context(), &jwt.Claims{UserID: 1}))
+	r.Header.Set(middleware.ReaderKeyHeader, "trakrf_reader")
+
+	w, _ := serveScanCredential(&fakeScanCredentialStore{}, r)
+	assert.NotEqual(t, http.StatusOK, w.Code)
+}
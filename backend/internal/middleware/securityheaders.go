@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// DefaultSPAPolicy is the Content-Security-Policy for the embedded SPA. Styles
+// allow 'unsafe-inline' for the component library's style attributes;
+// connect-src is open to https/wss for MQTT brokers and Sentry. The frontend
+// handler adds the hashes of index.html's inline scripts to script-src.
+const DefaultSPAPolicy = "default-src 'self'; script-src 'self'; style-src 'self' 'unsafe-inline'; " +
+	"img-src 'self' data: blob: https:; font-src 'self' data:; connect-src 'self' https: wss:; " +
+	"worker-src 'self' blob:; manifest-src 'self'; object-src 'none'; base-uri 'self'; " +
+	"form-action 'self'; frame-ancestors 'none'"
+
+// apiPolicy is the Content-Security-Policy for API and probe responses: they
+// are data, never documents, so nothing may load and nothing may frame them.
+const apiPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// swaggerPolicy lets the internal Swagger UI run its inline bootstrap script.
+const swaggerPolicy = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; " +
+	"img-src 'self' data:; object-src 'none'; frame-ancestors 'none'"
+
+// defaultHSTSMaxAge is one year, the minimum the preload list accepts.
+const defaultHSTSMaxAge = 365 * 24 * 60 * 60
+
+// apiPathPrefixes get apiPolicy; every other path is the SPA or its assets.
+var apiPathPrefixes = []string{"/api/", "/metrics", "/healthz", "/livez", "/readyz", "/health"}
+
+// SecurityHeadersConfig is the per-environment security header policy.
+type SecurityHeadersConfig struct {
+	// HSTSMaxAge is the Strict-Transport-Security max-age in seconds; 0
+	// omits the header.
+	HSTSMaxAge int
+	// SPAPolicy is the SPA's Content-Security-Policy; empty omits it.
+	SPAPolicy string
+	// ReportOnly sends SPAPolicy as Content-Security-Policy-Report-Only, for
+	// trying a tighter policy in one environment before enforcing it.
+	ReportOnly bool
+}
+
+// SecurityHeadersConfigFromEnv reads the security header policy:
+//
+//	SECURITY_HSTS_MAX_AGE     seconds, 0 disables  (default 1 year when deployed, 0 local/test)
+//	SECURITY_CSP              SPA policy, or "disabled"  (default DefaultSPAPolicy)
+//	SECURITY_CSP_REPORT_ONLY  true|false           (default false)
+//
+// HSTS is off by default locally so a developer's browser does not pin
+// localhost to https.
+func SecurityHeadersConfigFromEnv() (SecurityHeadersConfig, error) {
+	c := SecurityHeadersConfig{SPAPolicy: DefaultSPAPolicy}
+	if env := os.Getenv("APP_ENV"); env != "" && env != "test" {
+		c.HSTSMaxAge = defaultHSTSMaxAge
+	}
+	if raw := os.Getenv("SECURITY_HSTS_MAX_AGE"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return SecurityHeadersConfig{}, fmt.Errorf("SECURITY_HSTS_MAX_AGE must be a non-negative number of seconds, got %q", raw)
+		}
+		c.HSTSMaxAge = n
+	}
+	switch raw := strings.TrimSpace(os.Getenv("SECURITY_CSP")); {
+	case raw == "":
+	case raw == "disabled":
+		c.SPAPolicy = ""
+	case strings.ContainsAny(raw, "\r\n"):
+		return SecurityHeadersConfig{}, fmt.Errorf("SECURITY_CSP must be a single-line policy")
+	default:
+		c.SPAPolicy = raw
+	}
+	if raw := os.Getenv("SECURITY_CSP_REPORT_ONLY"); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return SecurityHeadersConfig{}, fmt.Errorf("SECURITY_CSP_REPORT_ONLY must be true or false, got %q", raw)
+		}
+		c.ReportOnly = v
+	}
+	return c, nil
+}
+
+// SecurityHeaders stamps every response with HSTS (when configured),
+// nosniff, a strict Referrer-Policy and frame denial, plus a
+// Content-Security-Policy chosen by path: apiPolicy for the API and probes,
+// swaggerPolicy for the Swagger UI and cfg.SPAPolicy for the SPA.
+func SecurityHeaders(cfg SecurityHeadersConfig) func(http.Handler) http.Handler {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", cfg.HSTSMaxAge)
+	}
+	spaHeader := "Content-Security-Policy"
+	if cfg.ReportOnly {
+		spaHeader = "Content-Security-Policy-Report-Only"
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			if hsts != "" {
+				h.Set("Strict-Transport-Security", hsts)
+			}
+			h.Set("X-Content-Type-Options", "nosniff")
+			h.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			h.Set("X-Frame-Options", "DENY")
+			switch {
+			case isAPIPath(r.URL.Path):
+				h.Set("Content-Security-Policy", apiPolicy)
+			case strings.HasPrefix(r.URL.Path, "/swagger/"):
+				h.Set("Content-Security-Policy", swaggerPolicy)
+			case cfg.SPAPolicy != "":
+				h.Set(spaHeader, cfg.SPAPolicy)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isAPIPath(urlPath string) bool {
+	for _, p := range apiPathPrefixes {
+		if urlPath == p || strings.HasPrefix(urlPath, p) && (strings.HasSuffix(p, "/") || urlPath[len(p)] == '/') {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveWithSecurityHeaders(cfg SecurityHeadersConfig, path string) http.Header {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	rec := httptest.NewRecorder()
+	SecurityHeaders(cfg)(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Header()
+}
+
+func TestSecurityHeaders_APIAndSPA(t *testing.T) {
+	cfg := SecurityHeadersConfig{HSTSMaxAge: 31536000, SPAPolicy: DefaultSPAPolicy}
+
+	for _, path := range []string{"/api/v1/assets", "/api/openapi.json", "/healthz", "/metrics"} {
+		h := serveWithSecurityHeaders(cfg, path)
+		assert.Equal(t, "max-age=31536000; includeSubDomains", h.Get("Strict-Transport-Security"), path)
+		assert.Equal(t, "nosniff", h.Get("X-Content-Type-Options"), path)
+		assert.Equal(t, "strict-origin-when-cross-origin", h.Get("Referrer-Policy"), path)
+		assert.Equal(t, "DENY", h.Get("X-Frame-Options"), path)
+		assert.Equal(t, apiPolicy, h.Get("Content-Security-Policy"), path)
+	}
+
+	for _, path := range []string{"/", "/assets/list", "/healthcheck-page", "/assets/index-abc.js"} {
+		h := serveWithSecurityHeaders(cfg, path)
+		assert.Equal(t, "nosniff", h.Get("X-Content-Type-Options"), path)
+		assert.Equal(t, "DENY", h.Get("X-Frame-Options"), path)
+		assert.Equal(t, DefaultSPAPolicy, h.Get("Content-Security-Policy"), path)
+	}
+
+	assert.Equal(t, swaggerPolicy, serveWithSecurityHeaders(cfg, "/swagger/index.html").Get("Content-Security-Policy"))
+}
+
+func TestSecurityHeaders_Configurable(t *testing.T) {
+	h := serveWithSecurityHeaders(SecurityHeadersConfig{}, "/")
+	assert.Empty(t, h.Get("Strict-Transport-Security"))
+	assert.Empty(t, h.Get("Content-Security-Policy"))
+	assert.Equal(t, "nosniff", h.Get("X-Content-Type-Options"))
+
+	h = serveWithSecurityHeaders(SecurityHeadersConfig{SPAPolicy: "default-src 'self'", ReportOnly: true}, "/")
+	assert.Empty(t, h.Get("Content-Security-Policy"))
+	assert.Equal(t, "default-src 'self'", h.Get("Content-Security-Policy-Report-Only"))
+	// The API policy is always enforced.
+	h = serveWithSecurityHeaders(SecurityHeadersConfig{SPAPolicy: "default-src 'self'", ReportOnly: true}, "/api/v1/assets")
+	assert.Equal(t, apiPolicy, h.Get("Content-Security-Policy"))
+}
+
+func TestSecurityHeadersConfigFromEnv(t *testing.T) {
+	for _, k := range []string{"APP_ENV", "SECURITY_HSTS_MAX_AGE", "SECURITY_CSP", "SECURITY_CSP_REPORT_ONLY"} {
+		t.Setenv(k, "")
+	}
+
+	c, err := SecurityHeadersConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 0, c.HSTSMaxAge, "no HSTS locally")
+	assert.Equal(t, DefaultSPAPolicy, c.SPAPolicy)
+
+	t.Setenv("APP_ENV", "prod")
+	c, err = SecurityHeadersConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, defaultHSTSMaxAge, c.HSTSMaxAge)
+
+	t.Setenv("SECURITY_HSTS_MAX_AGE", "0")
+	t.Setenv("SECURITY_CSP", "disabled")
+	t.Setenv("SECURITY_CSP_REPORT_ONLY", "true")
+	c, err = SecurityHeadersConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, SecurityHeadersConfig{ReportOnly: true}, c)
+
+	t.Setenv("SECURITY_HSTS_MAX_AGE", "-5")
+	_, err = SecurityHeadersConfigFromEnv()
+	assert.ErrorContains(t, err, "SECURITY_HSTS_MAX_AGE")
+
+	t.Setenv("SECURITY_HSTS_MAX_AGE", "")
+	t.Setenv("SECURITY_CSP_REPORT_ONLY", "sometimes")
+	_, err = SecurityHeadersConfigFromEnv()
+	assert.ErrorContains(t, err, "SECURITY_CSP_REPORT_ONLY")
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"time"
+)
+
+// longRequestPaths are routes whose requests legitimately outlast the
+// server's read and write timeouts: big uploads, streamed downloads and
+// reports over long histories. Matched with path.Match.
+var longRequestPaths = []string{
+	bulkCSVUploadPath,
+	"/api/v1/assets/export",
+	"/api/v1/org-exports/*",
+	orgImportPathPattern,
+	"/api/v1/reports/*",
+	"/api/v1/assets/*/history",
+	"/api/v1/epcis/events",
+}
+
+// streamPaths are the server-sent event streams. They stay open for as long
+// as the client listens, clear their own write deadline, and end when the
+// client goes away, so they get no deadline at all.
+var streamPaths = []string{
+	"/api/v1/reads/stream",
+	"/api/v1/mustering/stream",
+}
+
+// Timeouts gives each request a deadline on its context, so work a client can
+// no longer receive (a slow query, a retry loop) is cancelled. Ordinary
+// routes get standard, the server's write timeout, which keeps CRUD tight.
+// Routes in longRequestPaths get long instead, and the connection's read and
+// write deadlines, which the server set from its own timeouts when the
+// request arrived, are pushed out to match. Streams get no deadline.
+func Timeouts(standard, long time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			d := standard
+			switch {
+			case matchesAny(streamPaths, r.URL.Path):
+				next.ServeHTTP(w, r)
+				return
+			case matchesAny(longRequestPaths, r.URL.Path):
+				d = long
+				// Best effort: a test recorder has no connection deadlines.
+				rc := http.NewResponseController(w)
+				deadline := time.Now().Add(long)
+				_ = rc.SetReadDeadline(deadline)
+				_ = rc.SetWriteDeadline(deadline)
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func matchesAny(patterns []string, urlPath string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, urlPath); ok {
+			return true
+		}
+	}
+	return false
+}
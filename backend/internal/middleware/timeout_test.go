@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimeouts(t *testing.T) {
+	var deadline time.Time
+	var hasDeadline bool
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, hasDeadline = r.Context().Deadline()
+	})
+	h := Timeouts(10*time.Second, 10*time.Minute)(next)
+
+	for _, tc := range []struct {
+		path string
+		want time.Duration // 0 = no deadline
+	}{
+		{"/api/v1/assets", 10 * time.Second},
+		{"/api/v1/assets/42", 10 * time.Second},
+		{"/api/v1/assets/bulk", 10 * time.Minute},
+		{"/api/v1/assets/export", 10 * time.Minute},
+		{"/api/v1/orgs/7/import", 10 * time.Minute},
+		{"/api/v1/org-exports/some.token", 10 * time.Minute},
+		{"/api/v1/reports/asset-locations", 10 * time.Minute},
+		{"/api/v1/assets/42/history", 10 * time.Minute},
+		{"/api/v1/reads/stream", 0},
+	} {
+		t.Run(tc.path, func(t *testing.T) {
+			start := time.Now()
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+			if tc.want == 0 {
+				if hasDeadline {
+					t.Fatalf("deadline set on a stream")
+				}
+				return
+			}
+			if !hasDeadline {
+				t.Fatalf("no deadline set")
+			}
+			if got := deadline.Sub(start); got < tc.want-time.Second || got > tc.want+time.Second {
+				t.Fatalf("deadline in %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -29,6 +29,10 @@ func (s *statusRecorder) Write(b []byte) (int, error) {
 // WriteAudit logs one structured line per write request: principal, org, method,
 // path, status, request_id. Intended to be mounted only on the public write
 // route group — does not itself enforce any auth or scope.
+//
+// Calls made with a service-account-owned key log principal
+// "service_account:<id>" plus the key's jti as api_key, so integrations read
+// distinctly from both people and unowned keys.
 func WriteAudit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		rec := &statusRecorder{ResponseWriter: w}
@@ -37,11 +41,16 @@ func WriteAudit(next http.Handler) http.Handler {
 			recovered := recover()
 
 			principal := "anonymous"
+			apiKey := ""
 			orgID := 0
 
 			if p := GetAPIKeyPrincipal(r); p != nil {
 				principal = "api_key:" + p.JTI
 				orgID = p.OrgID
+				if p.ServiceAccountID != nil {
+					principal = "service_account:" + strconv.Itoa(*p.ServiceAccountID)
+					apiKey = p.JTI
+				}
 			} else if c := GetUserClaims(r); c != nil {
 				principal = "user:" + strconv.Itoa(c.UserID)
 				if c.CurrentOrgID != nil {
@@ -64,10 +73,13 @@ func WriteAudit(next http.Handler) http.Handler {
 				status = http.StatusOK
 			}
 
-			logger.Get().Info().
+			ev := logger.Get().Info().
 				Str("event", "api.write").
-				Str("principal", principal).
-				Int("org_id", orgID).
+				Str("principal", principal)
+			if apiKey != "" {
+				ev = ev.Str("api_key", apiKey)
+			}
+			ev.Int("org_id", orgID).
 				Str("method", r.Method).
 				Str("path", r.URL.Path).
 				Int("status", status).
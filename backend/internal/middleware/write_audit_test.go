@@ -130,3 +130,32 @@ func TestWriteAudit_LogsEvenWhenHandlerPanics(t *testing.T) {
 	// Fixed behavior: status is 500 when the handler panics.
 	assert.EqualValues(t, 500, line["status"], "panic must log status=500, not 200")
 }
+
+func TestWriteAudit_LogsServiceAccountPrincipal(t *testing.T) {
+	var buf bytes.Buffer
+	prev := logger.Get()
+	defer logger.SetForTest(*prev)
+	logger.SetForTest(zerolog.New(&buf))
+
+	handler := middleware.WriteAudit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	saID := 314
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/assets/7", strings.NewReader(`{}`))
+	req = req.WithContext(middleware.WithAPIKeyPrincipalForTest(req.Context(), &middleware.APIKeyPrincipal{
+		OrgID:            42,
+		Scopes:           []string{"assets:write"},
+		JTI:              "jti-sa",
+		ServiceAccountID: &saID,
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "service_account:314", line["principal"])
+	assert.Equal(t, "jti-sa", line["api_key"])
+	assert.EqualValues(t, 42, line["org_id"])
+}
@@ -15,57 +15,66 @@ var ValidScopes = map[string]bool{
 	"locations:read":  true,
 	"locations:write": true,
 	"tracking:read":   true,
+	"sensors:read":    true,
+	"sensors:write":   true,
 	"keys:admin":      true,
 }
 
 // APIKey is the row as stored. Full JWT is NOT stored — only the jti for revocation.
 // Exactly one of CreatedBy / CreatedByKeyID is non-nil (DB CHECK enforced).
+// ServiceAccountID is the owner, independent of who minted the key.
 type APIKey struct {
-	ID             int        `json:"id"`
-	JTI            string     `json:"jti"`
-	SecretHash     string     `json:"-"` // SHA-256 of the opaque client_secret; never serialized
-	OrgID          int        `json:"org_id"`
-	Name           string     `json:"name"`
-	Scopes         []string   `json:"scopes"`
-	CreatedBy      *int       `json:"created_by"`
-	CreatedByKeyID *int       `json:"created_by_key_id"`
-	CreatedAt      time.Time  `json:"created_at"`
-	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
-	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
-	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	ID               int        `json:"id"`
+	JTI              string     `json:"jti"`
+	SecretHash       string     `json:"-"` // SHA-256 of the opaque client_secret; never serialized
+	OrgID            int        `json:"org_id"`
+	Name             string     `json:"name"`
+	Scopes           []string   `json:"scopes"`
+	CreatedBy        *int       `json:"created_by"`
+	CreatedByKeyID   *int       `json:"created_by_key_id"`
+	ServiceAccountID *int       `json:"service_account_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
 }
 
-// CreateAPIKeyRequest is the POST body from the admin UI.
+// CreateAPIKeyRequest is the POST body from the admin UI. ServiceAccountID,
+// when set, makes the key belong to that service account rather than to the
+// person minting it.
 type CreateAPIKeyRequest struct {
-	Name      string     `json:"name"      validate:"required,min=1,max=255"`
-	Scopes    []string   `json:"scopes"    validate:"required,min=1"`
-	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Name             string     `json:"name"      validate:"required,min=1,max=255"`
+	Scopes           []string   `json:"scopes"    validate:"required,min=1"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	ServiceAccountID *int       `json:"service_account_id,omitempty" validate:"omitempty,gt=0"`
 }
 
 // APIKeyCreateResponse is returned ONCE from POST. client_secret is the opaque
 // secret shown exactly once and never persisted in plaintext; client_id is the
 // row's jti, used as the client_credentials client_id at POST /oauth/token.
 type APIKeyCreateResponse struct {
-	ClientID     string     `json:"client_id"`
-	ClientSecret string     `json:"client_secret"`
-	ID           int        `json:"id"`
-	Name         string     `json:"name"`
-	Scopes       []string   `json:"scopes"`
-	CreatedAt    time.Time  `json:"created_at"`
-	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	ClientID         string     `json:"client_id"`
+	ClientSecret     string     `json:"client_secret"`
+	ID               int        `json:"id"`
+	Name             string     `json:"name"`
+	Scopes           []string   `json:"scopes"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	ServiceAccountID *int       `json:"service_account_id,omitempty"`
 }
 
 // APIKeyListItem is what GET returns — never includes the JWT.
 type APIKeyListItem struct {
-	ID             int        `json:"id"`
-	JTI            string     `json:"jti"`
-	Name           string     `json:"name"`
-	Scopes         []string   `json:"scopes"`
-	CreatedBy      *int       `json:"created_by"`
-	CreatedByKeyID *int       `json:"created_by_key_id"`
-	CreatedAt      time.Time  `json:"created_at"`
-	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
-	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	ID               int        `json:"id"`
+	JTI              string     `json:"jti"`
+	Name             string     `json:"name"`
+	Scopes           []string   `json:"scopes"`
+	CreatedBy        *int       `json:"created_by"`
+	CreatedByKeyID   *int       `json:"created_by_key_id"`
+	ServiceAccountID *int       `json:"service_account_id"`
+	CreatedAt        time.Time  `json:"created_at"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
 }
 
 // ActiveKeyCap is the per-org soft cap enforced by the POST handler.
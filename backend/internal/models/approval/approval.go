@@ -0,0 +1,177 @@
+// Package approval holds the models for two-person approval of sensitive
+// operations: an org admin configures which operations need a second admin's
+// sign-off, a gated request that matches a rule is parked as a pending
+// approval request, and once a different admin approves it the requester
+// replays the request with the X-Approval-ID header.
+package approval
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// HeaderApprovalID carries the id of an approved request when it is replayed.
+const HeaderApprovalID = "X-Approval-ID"
+
+// Operations that can be made to require approval.
+const (
+	// OpAssetsBulkDelete is POST /api/v1/assets/bulk-delete. A rule's
+	// threshold exempts deletes of at most that many assets.
+	OpAssetsBulkDelete = "assets.bulk_delete"
+	// OpOrgSettings is any write to the org record or its settings.
+	OpOrgSettings = "org.settings"
+	// OpOrgAdminGrant is a member role change that makes someone an admin.
+	OpOrgAdminGrant = "org.admin_grant"
+	// OpOrgOwnershipTransfer is POST /api/v1/orgs/{id}/transfer-ownership,
+	// which hands control of the org to another member.
+	OpOrgOwnershipTransfer = "org.ownership_transfer"
+)
+
+// Operations lists every operation a rule can name.
+var Operations = []string{OpAssetsBulkDelete, OpOrgSettings, OpOrgAdminGrant, OpOrgOwnershipTransfer}
+
+// IsOperation reports whether op names a gateable operation.
+func IsOperation(op string) bool {
+	for _, o := range Operations {
+		if o == op {
+			return true
+		}
+	}
+	return false
+}
+
+// Request statuses. pending → approved → used is the happy path; a pending
+// request can be rejected by an admin, and a pending or approved one
+// cancelled by its requester. Expired is never stored: a pending or approved
+// request reads as expired once expires_at has passed.
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusCancelled = "cancelled"
+	StatusUsed      = "used"
+	StatusExpired   = "expired"
+)
+
+// Actions on a request.
+const (
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+	ActionCancel  = "cancel"
+)
+
+// DefaultTTLHours is how long a request stays usable when a rule does not
+// say otherwise.
+const DefaultTTLHours = 72
+
+// Rule makes an operation require approval in an org.
+type Rule struct {
+	OrgID     int       `json:"org_id"`
+	Operation string    `json:"operation" example:"assets.bulk_delete"`
+	Threshold *int      `json:"threshold,omitempty" example:"50"`
+	TTLHours  int       `json:"ttl_hours" example:"72"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RuleRequest is the body of PUT /api/v1/approval-rules/{operation}.
+type RuleRequest struct {
+	Threshold *int `json:"threshold,omitempty" validate:"omitempty,gte=0" example:"50"`
+	TTLHours  *int `json:"ttl_hours,omitempty" validate:"omitempty,min=1,max=720" example:"72"`
+}
+
+// Request is one parked operation awaiting, or holding, approval. Body is
+// the original request body, so approvers can see exactly what will run.
+type Request struct {
+	ID           int             `json:"id"`
+	OrgID        int             `json:"org_id"`
+	Operation    string          `json:"operation" example:"org.settings"`
+	Method       string          `json:"method" example:"PATCH"`
+	Path         string          `json:"path" example:"/api/v1/orgs/12/retention"`
+	Body         json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+	Summary      string          `json:"summary" example:"PATCH /api/v1/orgs/12/retention"`
+	Status       string          `json:"status" example:"pending"`
+	RequestedBy  *int            `json:"requested_by,omitempty"`
+	DecidedBy    *int            `json:"decided_by,omitempty"`
+	DecidedAt    *time.Time      `json:"decided_at,omitempty"`
+	DecisionNote *string         `json:"decision_note,omitempty"`
+	ExpiresAt    time.Time       `json:"expires_at"`
+	UsedAt       *time.Time      `json:"used_at,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
+// NewRequest describes a gated request: what it is, and the exact method,
+// path and body a replay must match.
+type NewRequest struct {
+	Operation  string
+	Method     string
+	Path       string
+	Body       []byte
+	BodySHA256 string
+	Summary    string
+	TTLHours   int
+}
+
+// DecisionRequest is the optional body of approve, reject and cancel.
+type DecisionRequest struct {
+	Note *string `json:"note,omitempty" validate:"omitempty,min=1,max=1024,no_control_chars" example:"Confirmed with the site lead"`
+}
+
+// ListFilter selects requests for GET /api/v1/approvals.
+type ListFilter struct {
+	Status    string
+	Operation string
+	Limit     int
+}
+
+// Applies reports whether a request for op with body falls under a rule
+// with threshold. Bodies that do not parse are let through so the handler
+// can reject them with its usual validation error.
+func Applies(op string, body []byte, threshold *int) bool {
+	switch op {
+	case OpAssetsBulkDelete:
+		var b struct {
+			AssetIDs []int `json:"asset_ids"`
+		}
+		if json.Unmarshal(body, &b) != nil {
+			return false
+		}
+		return threshold == nil || len(b.AssetIDs) > *threshold
+	case OpOrgAdminGrant:
+		var b struct {
+			Role string `json:"role"`
+		}
+		if json.Unmarshal(body, &b) != nil {
+			return false
+		}
+		return b.Role == "admin"
+	case OpOrgSettings, OpOrgOwnershipTransfer:
+		return true
+	}
+	return false
+}
+
+// Summary describes a gated request for approvers.
+func Summary(op, method, path string, body []byte) string {
+	switch op {
+	case OpAssetsBulkDelete:
+		var b struct {
+			AssetIDs []int `json:"asset_ids"`
+		}
+		if json.Unmarshal(body, &b) == nil {
+			return fmt.Sprintf("Delete %d assets", len(b.AssetIDs))
+		}
+	case OpOrgAdminGrant:
+		return "Grant the admin role: " + method + " " + path
+	case OpOrgOwnershipTransfer:
+		var b struct {
+			UserID int `json:"user_id"`
+		}
+		if json.Unmarshal(body, &b) == nil {
+			return fmt.Sprintf("Transfer ownership to user %d", b.UserID)
+		}
+	}
+	return method + " " + path
+}
@@ -0,0 +1,43 @@
+package approval
+
+import "testing"
+
+func TestApplies(t *testing.T) {
+	two := 2
+	cases := []struct {
+		name      string
+		op        string
+		body      string
+		threshold *int
+		want      bool
+	}{
+		{"bulk delete without threshold", OpAssetsBulkDelete, `{"asset_ids":[1]}`, nil, true},
+		{"bulk delete at threshold", OpAssetsBulkDelete, `{"asset_ids":[1,2]}`, &two, false},
+		{"bulk delete over threshold", OpAssetsBulkDelete, `{"asset_ids":[1,2,3]}`, &two, true},
+		{"bulk delete unparseable", OpAssetsBulkDelete, `{"asset_ids":"all"}`, nil, false},
+		{"settings always", OpOrgSettings, `{"name":"Acme"}`, nil, true},
+		{"admin grant", OpOrgAdminGrant, `{"role":"admin"}`, nil, true},
+		{"other role change", OpOrgAdminGrant, `{"role":"operator"}`, nil, false},
+		{"ownership transfer always", OpOrgOwnershipTransfer, `{"user_id":7}`, nil, true},
+		{"unknown operation", "org.delete", `{}`, nil, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := Applies(c.op, []byte(c.body), c.threshold); got != c.want {
+				t.Fatalf("Applies(%q, %s) = %v, want %v", c.op, c.body, got, c.want)
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	if got := Summary(OpAssetsBulkDelete, "POST", "/api/v1/assets/bulk-delete", []byte(`{"asset_ids":[1,2,3]}`)); got != "Delete 3 assets" {
+		t.Errorf("bulk delete summary = %q", got)
+	}
+	if got := Summary(OpOrgSettings, "PATCH", "/api/v1/orgs/5/retention", nil); got != "PATCH /api/v1/orgs/5/retention" {
+		t.Errorf("settings summary = %q", got)
+	}
+	if got := Summary(OpOrgOwnershipTransfer, "POST", "/api/v1/orgs/5/transfer-ownership", []byte(`{"user_id":7}`)); got != "Transfer ownership to user 7" {
+		t.Errorf("ownership transfer summary = %q", got)
+	}
+}
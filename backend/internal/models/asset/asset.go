@@ -3,8 +3,11 @@ package asset
 import (
 	"time"
 
+	"github.com/trakrf/platform/backend/internal/models/location"
 	"github.com/trakrf/platform/backend/internal/models/org"
 	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/util/filterexpr"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
 )
 
@@ -22,6 +25,8 @@ type Asset struct {
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
 	DeletedAt   *time.Time `json:"deleted_at"`
+	OwnerUserID *int       `json:"owner_user_id"`
+	CostCenter  *string    `json:"cost_center"`
 }
 
 // LocationReadOnlyMessage is the detail returned when a caller tries to set
@@ -50,6 +55,10 @@ type CreateAssetRequest struct {
 	ValidTo     *shared.FlexibleDate `json:"valid_to,omitempty" swaggertype:"string" example:"2026-01-01T00:00:00Z"`
 	Metadata    map[string]any       `json:"metadata,omitempty"`
 	IsActive    *bool                `json:"is_active,omitempty" example:"true"`
+	// owner_user_id must name a member of the caller's org. After create,
+	// ownership changes only through POST /assets/{asset_id}/transfer.
+	OwnerUserID *int    `json:"owner_user_id,omitempty" validate:"omitempty,gt=0" example:"42"`
+	CostCenter  *string `json:"cost_center,omitempty" validate:"omitempty,min=1,max=64,no_control_chars" example:"CC-4100"`
 }
 
 // PublicReadOnlyFields names the JSON keys on PublicAssetView that the PATCH
@@ -121,8 +130,14 @@ type UpdateAssetRequest struct {
 	// (TRA-614 / TRA-468). Not decoded from JSON directly.
 	ClearDescription bool            `json:"-" swaggerignore:"true"`
 	ClearValidTo     bool            `json:"-" swaggerignore:"true"`
+	ClearCostCenter  bool            `json:"-" swaggerignore:"true"`
 	Metadata         *map[string]any `json:"metadata"`
 	IsActive         *bool           `json:"is_active" example:"true"`
+	CostCenter       *string         `json:"cost_center" validate:"omitempty,min=1,max=64,no_control_chars" example:"CC-4100"`
+	// Ownership echo field, policed like ExternalKey: a value matching the
+	// current owner is a silent no-op; a differing one is 400 naming
+	// POST /assets/{asset_id}/transfer. Nilled before storage update.
+	OwnerUserID *int `json:"owner_user_id" swaggerignore:"true"`
 }
 
 // PublicRejectPatchFields names the JSON keys that PATCH /api/v1/assets/{id}
@@ -157,6 +172,17 @@ type RenameAssetRequest struct {
 	ExternalKey string `json:"external_key" validate:"required,min=1,max=255,external_key_pattern" example:"ASSET-0042"`
 }
 
+// TransferAssetRequest is the body of POST /api/v1/assets/{asset_id}/transfer.
+// The new owner must be a member of the asset's org and is notified by email.
+// The transfer is recorded as a check-out, with the handover's signature
+// when one was captured.
+type TransferAssetRequest struct {
+	OwnerUserID int             `json:"owner_user_id" validate:"required,gt=0" example:"42"`
+	Note        *string         `json:"note,omitempty" validate:"omitempty,max=1000"`
+	Signature   *Signature      `json:"signature,omitempty"`
+	Condition   *ConditionInput `json:"condition,omitempty"`
+}
+
 type AssetListResponse struct {
 	Data       []Asset           `json:"data"`
 	Pagination shared.Pagination `json:"pagination"`
@@ -167,9 +193,28 @@ type AssetView struct {
 	Tags []shared.Tag `json:"tags"`
 }
 
+// CurrentLocationRef is the location an asset was last scanned at.
+type CurrentLocationRef struct {
+	ID          int               `json:"id" example:"7"`
+	ExternalKey string            `json:"external_key" example:"dock-1"`
+	Name        string            `json:"name" example:"Dock 1"`
+	LastSeen    shared.PublicTime `json:"last_seen"`
+}
+
+// AssetLocationView is an asset with its tags and current location, the row
+// of the asset views list. CurrentLocation is nil for an asset never scanned,
+// or last scanned at a location since deleted.
+type AssetLocationView struct {
+	AssetView
+	CurrentLocation *CurrentLocationRef
+}
+
 type CreateAssetWithTagsRequest struct {
 	CreateAssetRequest
 	Tags []shared.TagRequest `json:"tags,omitempty" validate:"omitempty,dive"`
+	// AutoIdentifier draws external_key from the org's identifier template
+	// (POST /api/v1/assets?auto_identifier=true); ExternalKey is then empty.
+	AutoIdentifier bool `json:"-" swaggerignore:"true"`
 }
 
 type AssetViewListResponse struct {
@@ -177,20 +222,57 @@ type AssetViewListResponse struct {
 	Pagination shared.Pagination `json:"pagination"`
 }
 
+// FilterFields are the fields the assets list `filter` parameter accepts.
+var FilterFields = filterexpr.Fields{
+	"external_key":  filterexpr.String,
+	"name":          filterexpr.String,
+	"description":   filterexpr.String,
+	"cost_center":   filterexpr.String,
+	"id":            filterexpr.Number,
+	"owner_user_id": filterexpr.Number,
+	"team_id":       filterexpr.Number,
+	"is_active":     filterexpr.Bool,
+	"valid_from":    filterexpr.Time,
+	"valid_to":      filterexpr.Time,
+	"created_at":    filterexpr.Time,
+	"updated_at":    filterexpr.Time,
+	"metadata":      filterexpr.JSON,
+}
+
 // ListFilter carries the optional filters the assets list endpoint supports.
 type ListFilter struct {
 	// Equality match on a.external_key (any-of). Single value yields the
 	// natural-key lookup that lives on the collection per TRA-600.
 	ExternalKeys []string
 	IsActive     *bool
+	// Any-of match on a.owner_user_id / a.cost_center.
+	OwnerUserIDs []int
+	CostCenters  []string
 	Q            *string // substring match (case-insensitive) on name, external_key, description, and active tag values
+	// Any-of match on a.team_id.
+	TeamIDs []int
+	// TeamScope is the caller's strict-mode visibility; when restricted it
+	// hides every row not assigned to one of the caller's teams.
+	TeamScope team.Scope
+	// SubtreeScope is the caller's location access policy; when restricted
+	// it hides every asset whose current location is outside the granted
+	// subtrees.
+	SubtreeScope location.SubtreeScope
 	// IncludeDeleted relaxes the default a.deleted_at IS NULL filter so
 	// soft-deleted rows are returned alongside live rows. Orthogonal to
 	// IsActive (TRA-659 / BB25 A3). Temporal validity still applies.
 	IncludeDeleted bool
-	Sorts          []ListSort
-	Limit          int
-	Offset         int
+	// AsOf evaluates temporal validity (valid_from/valid_to) at this instant
+	// instead of now, listing the rows that were or will be effective then.
+	AsOf *time.Time
+	// Filter is the parsed `filter` query parameter, ANDed with the rest.
+	Filter filterexpr.Expr
+	// Fields is the response's sparse fieldset; when set without "tags",
+	// tags are not loaded.
+	Fields []string
+	Sorts  []ListSort
+	Limit  int
+	Offset int
 }
 
 // ListSort is one (field, direction) entry.
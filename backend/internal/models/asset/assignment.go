@@ -0,0 +1,128 @@
+package asset
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image/png"
+	"time"
+)
+
+// Assignment actions: a check-out assigns the asset to an owner, a check-in
+// clears it.
+const (
+	AssignmentCheckOut = "check_out"
+	AssignmentCheckIn  = "check_in"
+)
+
+// Signature payload bounds.
+const (
+	MaxSignatureImageBytes = 256 << 10
+	MaxSignatureImageSide  = 2000
+	MaxSignatureStrokes    = 100
+	MaxSignaturePoints     = 5000
+)
+
+// SignaturePoint is one pen sample, in the signature's canvas coordinates
+// (origin top left).
+type SignaturePoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Signature is an electronic signature captured at a handover: either a PNG
+// image (base64) or the pen strokes on a Width x Height canvas, never both.
+type Signature struct {
+	SignerName string             `json:"signer_name" validate:"required,min=1,max=255" example:"Dana Ortiz"`
+	ImagePNG   string             `json:"image_png,omitempty" validate:"required_without=Strokes,excluded_with=Strokes,omitempty,base64"`
+	Width      float64            `json:"width,omitempty" validate:"required_with=Strokes,omitempty,gt=0,lte=10000" example:"400"`
+	Height     float64            `json:"height,omitempty" validate:"required_with=Strokes,omitempty,gt=0,lte=10000" example:"150"`
+	Strokes    [][]SignaturePoint `json:"strokes,omitempty" validate:"omitempty,max=100,dive,min=1"`
+}
+
+// Check validates what the struct tags cannot: the image decodes as a PNG
+// within the size bounds, and every stroke point lies on the canvas.
+func (s *Signature) Check() error {
+	if s.ImagePNG != "" {
+		raw, err := base64.StdEncoding.DecodeString(s.ImagePNG)
+		if err != nil {
+			return errors.New("image_png must be base64")
+		}
+		if len(raw) > MaxSignatureImageBytes {
+			return fmt.Errorf("image_png must be at most %d bytes", MaxSignatureImageBytes)
+		}
+		cfg, err := png.DecodeConfig(bytes.NewReader(raw))
+		if err != nil || cfg.Width == 0 || cfg.Height == 0 {
+			return errors.New("image_png must be a PNG image")
+		}
+		if cfg.Width > MaxSignatureImageSide || cfg.Height > MaxSignatureImageSide {
+			return fmt.Errorf("image_png must be at most %dx%d pixels", MaxSignatureImageSide, MaxSignatureImageSide)
+		}
+		return nil
+	}
+	points := 0
+	for _, stroke := range s.Strokes {
+		points += len(stroke)
+		for _, p := range stroke {
+			if p.X < 0 || p.Y < 0 || p.X > s.Width || p.Y > s.Height {
+				return errors.New("strokes must lie within width and height")
+			}
+		}
+	}
+	if points > MaxSignaturePoints {
+		return fmt.Errorf("strokes may hold at most %d points", MaxSignaturePoints)
+	}
+	return nil
+}
+
+// Format is "png" or "strokes".
+func (s *Signature) Format() string {
+	if s.ImagePNG != "" {
+		return "png"
+	}
+	return "strokes"
+}
+
+// Digest is the hex SHA-256 of the signature's JSON, which custody documents
+// chain so the signature cannot be swapped without breaking them.
+func (s *Signature) Digest() string {
+	b, _ := json.Marshal(s)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Assignment is one check-out or check-in of an asset. UserID is the new
+// owner on check-out and the returning owner on check-in; PerformedBy is the
+// session user who recorded it, null for API-key callers. The signature
+// itself is served by GET .../assignments/{assignment_id}/signature.
+type Assignment struct {
+	ID           int       `json:"id"`
+	AssetID      int       `json:"asset_id"`
+	Action       string    `json:"action" example:"check_out"`
+	UserID       *int      `json:"user_id"`
+	PerformedBy  *int      `json:"performed_by"`
+	Note         *string   `json:"note,omitempty"`
+	SignerName   *string   `json:"signer_name,omitempty"`
+	HasSignature bool      `json:"has_signature"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AssignmentRecord is what a check-out or check-in records besides the owner
+// change, including an optional condition assessment.
+type AssignmentRecord struct {
+	PerformedBy *int
+	Note        *string
+	Signature   *Signature
+	Condition   *ConditionInput
+}
+
+// CheckInAssetRequest is the body of POST /api/v1/assets/{asset_id}/check-in.
+type CheckInAssetRequest struct {
+	Note      *string         `json:"note,omitempty" validate:"omitempty,max=1000"`
+	Signature *Signature      `json:"signature,omitempty"`
+	Condition *ConditionInput `json:"condition,omitempty"`
+}
@@ -0,0 +1,54 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pngBase64(t *testing.T, w, h int) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, w, h))))
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestSignatureCheck(t *testing.T) {
+	line := [][]SignaturePoint{{{X: 0, Y: 0}, {X: 100, Y: 40}}}
+	cases := map[string]struct {
+		sig     Signature
+		wantErr string
+	}{
+		"png":             {sig: Signature{ImagePNG: pngBase64(t, 300, 100)}},
+		"strokes":         {sig: Signature{Width: 100, Height: 40, Strokes: line}},
+		"not a png":       {sig: Signature{ImagePNG: base64.StdEncoding.EncodeToString([]byte("GIF89a"))}, wantErr: "must be a PNG"},
+		"png too large":   {sig: Signature{ImagePNG: pngBase64(t, MaxSignatureImageSide+1, 10)}, wantErr: "pixels"},
+		"off canvas":      {sig: Signature{Width: 50, Height: 40, Strokes: line}, wantErr: "within width"},
+		"too many points": {sig: Signature{Width: 100, Height: 40, Strokes: [][]SignaturePoint{make([]SignaturePoint, MaxSignaturePoints+1)}}, wantErr: "at most"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.sig.Check()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.wantErr)
+		})
+	}
+}
+
+func TestSignatureDigest_CoversPayload(t *testing.T) {
+	a := Signature{SignerName: "Dana", Width: 10, Height: 10, Strokes: [][]SignaturePoint{{{X: 1, Y: 1}}}}
+	b := a
+	b.Strokes = [][]SignaturePoint{{{X: 2, Y: 1}}}
+	assert.Equal(t, "strokes", a.Format())
+	assert.Len(t, a.Digest(), 64)
+	assert.NotEqual(t, a.Digest(), b.Digest())
+}
@@ -0,0 +1,43 @@
+package asset
+
+import "time"
+
+// Condition ratings run from ConditionRatingMin (unusable) to
+// ConditionRatingMax (as new).
+const (
+	ConditionRatingMin = 1
+	ConditionRatingMax = 5
+)
+
+// MaxConditionPhotos caps the photos attached to one condition report.
+const MaxConditionPhotos = 10
+
+// ConditionInput is a condition assessment as submitted, ad hoc or with a
+// check-out or check-in.
+type ConditionInput struct {
+	Rating int     `json:"rating" validate:"required,min=1,max=5" example:"4"`
+	Notes  *string `json:"notes,omitempty" validate:"omitempty,max=2000" example:"Scuffed housing, works"`
+}
+
+// ConditionPhoto is one photo attached to a condition report, served from
+// the object store.
+type ConditionPhoto struct {
+	ID          int       `json:"id"`
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type" example:"image/jpeg"`
+	SizeBytes   int       `json:"size_bytes"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ConditionReport is a condition assessment of an asset. AssignmentID is set
+// when it was recorded with a check-out or check-in.
+type ConditionReport struct {
+	ID           int              `json:"id"`
+	AssetID      int              `json:"asset_id"`
+	AssignmentID *int             `json:"assignment_id"`
+	Rating       int              `json:"rating" example:"4"`
+	Notes        *string          `json:"notes,omitempty"`
+	ReportedBy   *int             `json:"reported_by"`
+	Photos       []ConditionPhoto `json:"photos"`
+	CreatedAt    time.Time        `json:"created_at"`
+}
@@ -0,0 +1,271 @@
+package asset
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/team"
+)
+
+// Reasons a pair of assets is suggested as duplicates.
+const (
+	DuplicateReasonSerial     = "same_serial"
+	DuplicateReasonIdentifier = "shared_identifier"
+	DuplicateReasonName       = "similar_name"
+)
+
+// Signal weights. Each is the score a pair gets from that signal alone;
+// signals combine as independent evidence, so two weak ones outrank either.
+const (
+	serialWeight     = 0.9
+	identifierWeight = 0.8
+	// nameWeight scales name similarity: identical names alone score 0.6,
+	// below the serial and identifier signals, since "Forklift" is common.
+	nameWeight = 0.6
+	// minNameSimilarity is the similarity a name pair needs to count at all.
+	minNameSimilarity = 0.85
+	// maxGroup bounds how many assets sharing a serial or a name prefix are
+	// compared pairwise. A bigger group is a placeholder serial ("N/A") or a
+	// naming scheme, not a set of duplicates.
+	maxGroup = 500
+)
+
+// DuplicateCandidate is a live asset as duplicate detection compares it.
+type DuplicateCandidate struct {
+	ID          int
+	ExternalKey string
+	Name        string
+	// Serial is the asset's serial number from metadata, "" when it has none.
+	Serial    string
+	CreatedAt time.Time
+}
+
+// IdentifierOverlap is a tag value carried by two live assets, at least one
+// of them on a tag since soft-deleted (live tag values are unique per org).
+// It is what re-importing an asset under a new key leaves behind.
+type IdentifierOverlap struct {
+	AssetA int
+	AssetB int
+	Type   string
+	Value  string
+}
+
+// DuplicateAssetRef names one asset of a suggested pair.
+type DuplicateAssetRef struct {
+	ID          int    `json:"id" example:"42"`
+	ExternalKey string `json:"external_key" example:"forklift-3"`
+	Name        string `json:"name" example:"Forklift 3"`
+}
+
+// DuplicateSuggestion is a pair of assets that are probably the same thing.
+// Keep is the older asset, the natural survivor of a merge.
+type DuplicateSuggestion struct {
+	Score       float64           `json:"score" example:"0.96"`
+	Reasons     []string          `json:"reasons" example:"same_serial"`
+	Keep        DuplicateAssetRef `json:"keep"`
+	Duplicate   DuplicateAssetRef `json:"duplicate"`
+	Serial      *string           `json:"serial,omitempty" example:"SN-0042"`
+	Identifiers []string          `json:"identifiers,omitempty" example:"rfid:E2801160"`
+}
+
+// DuplicateFilter narrows a duplicate scan.
+type DuplicateFilter struct {
+	MinScore float64
+	Limit    int
+	// TeamScope hides assets outside the caller's teams in strict mode; a
+	// hidden asset is never half of a suggested pair.
+	TeamScope team.Scope
+	// SubtreeScope likewise hides assets currently outside the caller's
+	// granted location subtrees.
+	SubtreeScope location.SubtreeScope
+}
+
+// FindDuplicates scores every pair of candidates that shares a serial, an
+// identifier or a near-identical name, and returns the pairs scoring at
+// least minScore, best first.
+func FindDuplicates(cands []DuplicateCandidate, overlaps []IdentifierOverlap, minScore float64) []DuplicateSuggestion {
+	byID := make(map[int]*DuplicateCandidate, len(cands))
+	for i := range cands {
+		byID[cands[i].ID] = &cands[i]
+	}
+
+	type pairKey struct{ lo, hi int }
+	type evidence struct {
+		serial      string
+		identifiers []string
+		nameSim     float64
+	}
+	pairs := map[pairKey]*evidence{}
+	get := func(a, b int) *evidence {
+		k := pairKey{min(a, b), max(a, b)}
+		e, ok := pairs[k]
+		if !ok {
+			e = &evidence{}
+			pairs[k] = e
+		}
+		return e
+	}
+
+	bySerial := map[string][]int{}
+	for _, c := range cands {
+		// "SN-0042" and "sn 0042" are the same serial.
+		if s := normalizeName(c.Serial); s != "" {
+			bySerial[s] = append(bySerial[s], c.ID)
+		}
+	}
+	for _, ids := range bySerial {
+		if len(ids) > maxGroup {
+			continue
+		}
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				get(ids[i], ids[j]).serial = byID[ids[i]].Serial
+			}
+		}
+	}
+
+	for _, o := range overlaps {
+		if byID[o.AssetA] == nil || byID[o.AssetB] == nil || o.AssetA == o.AssetB {
+			continue
+		}
+		e := get(o.AssetA, o.AssetB)
+		e.identifiers = append(e.identifiers, o.Type+":"+o.Value)
+	}
+
+	blocks := map[string][]int{}
+	norm := make(map[int]string, len(cands))
+	for _, c := range cands {
+		n := normalizeName(c.Name)
+		if n == "" {
+			continue
+		}
+		norm[c.ID] = n
+		prefix := n
+		if r := []rune(n); len(r) > 3 {
+			prefix = string(r[:3])
+		}
+		blocks[prefix] = append(blocks[prefix], c.ID)
+	}
+	for _, ids := range blocks {
+		if len(ids) > maxGroup {
+			continue
+		}
+		for i := range ids {
+			for j := i + 1; j < len(ids); j++ {
+				a, b := norm[ids[i]], norm[ids[j]]
+				// "Forklift 3" and "Forklift 4" are two forklifts.
+				if digits(a) != digits(b) {
+					continue
+				}
+				if sim := nameSimilarity(a, b); sim >= minNameSimilarity {
+					get(ids[i], ids[j]).nameSim = sim
+				}
+			}
+		}
+	}
+
+	out := []DuplicateSuggestion{}
+	for k, e := range pairs {
+		miss := 1.0
+		var reasons []string
+		if e.serial != "" {
+			miss *= 1 - serialWeight
+			reasons = append(reasons, DuplicateReasonSerial)
+		}
+		if len(e.identifiers) > 0 {
+			miss *= 1 - identifierWeight
+			reasons = append(reasons, DuplicateReasonIdentifier)
+		}
+		if e.nameSim > 0 {
+			miss *= 1 - nameWeight*e.nameSim
+			reasons = append(reasons, DuplicateReasonName)
+		}
+		score := math.Round((1-miss)*100) / 100
+		if score < minScore {
+			continue
+		}
+
+		keep, dup := byID[k.lo], byID[k.hi]
+		if dup.CreatedAt.Before(keep.CreatedAt) {
+			keep, dup = dup, keep
+		}
+		s := DuplicateSuggestion{
+			Score:     score,
+			Reasons:   reasons,
+			Keep:      DuplicateAssetRef{ID: keep.ID, ExternalKey: keep.ExternalKey, Name: keep.Name},
+			Duplicate: DuplicateAssetRef{ID: dup.ID, ExternalKey: dup.ExternalKey, Name: dup.Name},
+		}
+		if e.serial != "" {
+			serial := e.serial
+			s.Serial = &serial
+		}
+		if len(e.identifiers) > 0 {
+			sort.Strings(e.identifiers)
+			s.Identifiers = e.identifiers
+		}
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Score != out[j].Score {
+			return out[i].Score > out[j].Score
+		}
+		if out[i].Keep.ID != out[j].Keep.ID {
+			return out[i].Keep.ID < out[j].Keep.ID
+		}
+		return out[i].Duplicate.ID < out[j].Duplicate.ID
+	})
+	return out
+}
+
+// normalizeName lowercases s and drops everything but letters and digits.
+func normalizeName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// digits returns the digits of s in order.
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// nameSimilarity is 1 minus the edit distance between a and b over the
+// longer length: 1 for equal names, near 0 for unrelated ones.
+func nameSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
@@ -0,0 +1,51 @@
+package asset
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDuplicates_ScoresAndOrdersPairs(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cands := []DuplicateCandidate{
+		{ID: 1, ExternalKey: "fl-1", Name: "Forklift Blue", Serial: "SN-0042", CreatedAt: t0},
+		{ID: 2, ExternalKey: "erp-77", Name: "forklift blue", Serial: "sn 0042", CreatedAt: t0.Add(time.Hour)},
+		{ID: 3, ExternalKey: "cart-9", Name: "Cart", CreatedAt: t0.Add(-time.Hour)},
+		{ID: 4, ExternalKey: "cart-9b", Name: "Trolley", CreatedAt: t0},
+		{ID: 5, ExternalKey: "fl-3", Name: "Forklift 3", CreatedAt: t0},
+		{ID: 6, ExternalKey: "fl-4", Name: "Forklift 4", CreatedAt: t0},
+	}
+	overlaps := []IdentifierOverlap{{AssetA: 3, AssetB: 4, Type: "rfid", Value: "E2801160"}}
+
+	out := FindDuplicates(cands, overlaps, 0.5)
+
+	require.Len(t, out, 2, "numbered names are not duplicates")
+	assert.Equal(t, 1, out[0].Keep.ID)
+	assert.Equal(t, 2, out[0].Duplicate.ID)
+	assert.Equal(t, []string{DuplicateReasonSerial, DuplicateReasonName}, out[0].Reasons)
+	assert.Equal(t, 0.96, out[0].Score)
+	require.NotNil(t, out[0].Serial)
+
+	assert.Equal(t, 3, out[1].Keep.ID, "the older asset is kept")
+	assert.Equal(t, []string{DuplicateReasonIdentifier}, out[1].Reasons)
+	assert.Equal(t, []string{"rfid:E2801160"}, out[1].Identifiers)
+	assert.Equal(t, 0.8, out[1].Score)
+}
+
+func TestFindDuplicates_MinScoreFiltersWeakPairs(t *testing.T) {
+	cands := []DuplicateCandidate{
+		{ID: 1, Name: "Pallet Jack"},
+		{ID: 2, Name: "Pallet Jacks"},
+	}
+	assert.Len(t, FindDuplicates(cands, nil, 0.5), 1)
+	assert.Empty(t, FindDuplicates(cands, nil, 0.9))
+}
+
+func TestFindDuplicates_IgnoresOverlapsWithUnknownAssets(t *testing.T) {
+	cands := []DuplicateCandidate{{ID: 1, Name: "A"}}
+	out := FindDuplicates(cands, []IdentifierOverlap{{AssetA: 1, AssetB: 99, Type: "rfid", Value: "x"}}, 0)
+	assert.Empty(t, out)
+}
@@ -0,0 +1,27 @@
+package asset
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/shared"
+)
+
+// Financials is an asset's financial fields, both in Currency. An amount
+// never set is null, as is UpdatedAt when neither ever was.
+type Financials struct {
+	AssetID      int           `json:"asset_id"`
+	Currency     string        `json:"currency" example:"USD"`
+	PurchaseCost *shared.Money `json:"purchase_cost"`
+	BookValue    *shared.Money `json:"book_value"`
+	UpdatedAt    *time.Time    `json:"updated_at"`
+}
+
+// FinancialsInput is the body of PUT /api/v1/assets/{asset_id}/financials.
+// Amounts are decimal strings (or JSON numbers) in Currency, which defaults
+// to the org's currency; null or omitted clears one.
+type FinancialsInput struct {
+	Currency     string       `json:"currency,omitempty" example:"USD"`
+	PurchaseCost *json.Number `json:"purchase_cost" swaggertype:"string" example:"1234.50"`
+	BookValue    *json.Number `json:"book_value" swaggertype:"string" example:"900.00"`
+}
@@ -0,0 +1,8 @@
+package asset
+
+// PersonMetadataKey is the metadata key that makes an asset a person-asset:
+// a badged person rather than equipment, set as {"person": true}. Person-
+// assets drive mustering and get the org's personnel privacy controls: their
+// own scan retention window and pseudonyms in reports. The value must be a
+// JSON boolean.
+const PersonMetadataKey = "person"
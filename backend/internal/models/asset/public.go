@@ -14,6 +14,8 @@ import (
 // description and valid_to are always emitted (null when unset) per
 // TRA-610 / BB18 §1.8 audit alignment with PublicLocationView.
 //
+// owner_user_id and cost_center are always emitted, null when unset.
+//
 // deleted_at is always emitted (null for live rows, populated for
 // soft-deleted rows surfaced via ?include_deleted=true) per TRA-659 / BB25
 // A3. Per-resource views use the unprefixed `deleted_at` (TRA-679 / BB27 S7
@@ -32,9 +34,19 @@ type PublicAssetView struct {
 	CreatedAt   shared.PublicTime  `json:"created_at"`
 	UpdatedAt   shared.PublicTime  `json:"updated_at"`
 	DeletedAt   *shared.PublicTime `json:"deleted_at"`
+	OwnerUserID *int               `json:"owner_user_id" example:"42"`
+	CostCenter  *string            `json:"cost_center" example:"CC-4100"`
 	Tags        []shared.Tag       `json:"tags"`
 }
 
+// PublicFields are the PublicAssetView keys a list `fields=` parameter can
+// select.
+var PublicFields = []string{
+	"id", "external_key", "name", "description", "metadata", "is_active",
+	"valid_from", "valid_to", "created_at", "updated_at", "deleted_at",
+	"owner_user_id", "cost_center", "tags",
+}
+
 // ToPublicAssetView projects an AssetView to the public HTTP shape.
 func ToPublicAssetView(a AssetView) PublicAssetView {
 	// Normalize nil metadata to {} so POST and GET emit the same shape.
@@ -59,6 +71,23 @@ func ToPublicAssetView(a AssetView) PublicAssetView {
 		CreatedAt:   shared.NewPublicTime(a.CreatedAt),
 		UpdatedAt:   shared.NewPublicTime(a.UpdatedAt),
 		DeletedAt:   shared.PublicTimePtr(a.DeletedAt),
+		OwnerUserID: a.OwnerUserID,
+		CostCenter:  a.CostCenter,
 		Tags:        a.Tags,
 	}
 }
+
+// PublicAssetLocationView is a PublicAssetView with the asset's current
+// location, the item shape of GET /api/v1/assets/views.
+type PublicAssetLocationView struct {
+	PublicAssetView
+	CurrentLocation *CurrentLocationRef `json:"current_location"`
+}
+
+// ToPublicAssetLocationView projects an AssetLocationView to the HTTP shape.
+func ToPublicAssetLocationView(a AssetLocationView) PublicAssetLocationView {
+	return PublicAssetLocationView{
+		PublicAssetView: ToPublicAssetView(a.AssetView),
+		CurrentLocation: a.CurrentLocation,
+	}
+}
@@ -116,3 +116,62 @@ func TestPublicAssetView_ValidToEmittedWhenPopulated(t *testing.T) {
 
 	assert.Equal(t, "2026-01-01T00:00:00.000Z", parsed["valid_to"])
 }
+
+// Ownership fields are always emitted (null when unset) so clients can
+// distinguish "unowned" from an older server that lacks the fields.
+func TestToPublicAssetView_OwnerAndCostCenter(t *testing.T) {
+	owner := 42
+	cc := "CC-100"
+
+	cases := []struct {
+		name      string
+		in        Asset
+		wantOwner any
+		wantCC    any
+	}{
+		{"unset emits null", Asset{ExternalKey: "A-1", Name: "a"}, nil, nil},
+		{"set emits value", Asset{ExternalKey: "A-1", Name: "a", OwnerUserID: &owner, CostCenter: &cc}, float64(42), "CC-100"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := json.Marshal(ToPublicAssetView(AssetView{Asset: tc.in}))
+			require.NoError(t, err)
+
+			var parsed map[string]any
+			require.NoError(t, json.Unmarshal(data, &parsed))
+
+			v, present := parsed["owner_user_id"]
+			assert.True(t, present, "owner_user_id must be present")
+			assert.Equal(t, tc.wantOwner, v)
+			v, present = parsed["cost_center"]
+			assert.True(t, present, "cost_center must be present")
+			assert.Equal(t, tc.wantCC, v)
+		})
+	}
+}
+
+// A share link is public: the shared view must carry the asset's descriptive
+// fields only, never org-scoped ones.
+func TestToSharedAssetView_OmitsOrgData(t *testing.T) {
+	owner := 42
+	cc := "CC-4100"
+	exp := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	got := ToSharedAssetView(Asset{
+		ID: 7, OrgID: 3, ExternalKey: "FORK-007", Name: "Forklift 7",
+		Metadata: map[string]any{"site": "plant-2"}, IsActive: true,
+		OwnerUserID: &owner, CostCenter: &cc,
+	}, exp)
+
+	data, err := json.Marshal(got)
+	require.NoError(t, err)
+	var parsed map[string]any
+	require.NoError(t, json.Unmarshal(data, &parsed))
+
+	keys := make([]string, 0, len(parsed))
+	for k := range parsed {
+		keys = append(keys, k)
+	}
+	assert.ElementsMatch(t, []string{"external_key", "name", "description", "is_active", "expires_at"}, keys)
+	assert.Nil(t, parsed["description"])
+	assert.Equal(t, "FORK-007", parsed["external_key"])
+}
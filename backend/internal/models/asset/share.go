@@ -0,0 +1,56 @@
+package asset
+
+import (
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/shared"
+)
+
+// Share link lifetimes, in days.
+const (
+	DefaultShareDays = 30
+	MaxShareDays     = 365
+)
+
+// ShareAssetRequest is the body of POST /api/v1/assets/{asset_id}/share. The
+// body is optional; an empty one gets a link valid for DefaultShareDays.
+type ShareAssetRequest struct {
+	ExpiresInDays *int `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=365" example:"30"`
+}
+
+// AssetShareLink is a signed public URL to an asset's SharedAssetView. The
+// token is the last path segment of URL; it is returned separately for
+// callers that render their own link (e.g. into a QR code).
+type AssetShareLink struct {
+	URL       string            `json:"url" example:"https://app.trakrf.id/api/v1/shared/assets/eyJhbGciOi..."`
+	Token     string            `json:"token"`
+	ExpiresAt shared.PublicTime `json:"expires_at"`
+}
+
+// SharedAssetView is what a share link shows to anyone holding it: the
+// asset's own descriptive fields and nothing about the org, its locations,
+// tags, people or metadata.
+type SharedAssetView struct {
+	ExternalKey string            `json:"external_key" example:"ASSET-0042"`
+	Name        string            `json:"name" example:"Forklift 3"`
+	Description *string           `json:"description"`
+	IsActive    bool              `json:"is_active"`
+	ExpiresAt   shared.PublicTime `json:"expires_at"`
+}
+
+// ToSharedAssetView projects a to the public share shape. expiresAt is the
+// link's expiry, so embedding pages can say how long it stays valid.
+func ToSharedAssetView(a Asset, expiresAt time.Time) SharedAssetView {
+	var desc *string
+	if a.Description != "" {
+		s := a.Description
+		desc = &s
+	}
+	return SharedAssetView{
+		ExternalKey: a.ExternalKey,
+		Name:        a.Name,
+		Description: desc,
+		IsActive:    a.IsActive,
+		ExpiresAt:   shared.NewPublicTime(expiresAt),
+	}
+}
@@ -0,0 +1,68 @@
+// Package assetdisposal holds the models for retiring an asset: an operator
+// requests the disposal, an org admin approves it, and an operator executes
+// it once the asset has gone.
+package assetdisposal
+
+import "time"
+
+// Disposal statuses. pending → approved → completed is the happy path; a
+// pending disposal can be rejected by an admin, and a pending or approved
+// one cancelled.
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusCancelled = "cancelled"
+	StatusCompleted = "completed"
+)
+
+// Actions on a disposal.
+const (
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+	ActionCancel  = "cancel"
+	ActionExecute = "execute"
+)
+
+// Methods are the ways an asset leaves the org.
+var Methods = []string{"sold", "scrapped", "recycled", "donated", "returned", "lost", "stolen", "other"}
+
+// Disposal is one disposal request. AssetExternalKey and AssetName are read
+// from the asset for display.
+type Disposal struct {
+	ID               int        `json:"id"`
+	OrgID            int        `json:"org_id"`
+	AssetID          int        `json:"asset_id"`
+	AssetExternalKey string     `json:"asset_external_key" example:"forklift-3"`
+	AssetName        string     `json:"asset_name" example:"Forklift 3"`
+	Reason           string     `json:"reason" example:"Mast cracked; not economical to repair"`
+	Method           string     `json:"method" example:"scrapped"`
+	Status           string     `json:"status" example:"pending"`
+	RequestedBy      *int       `json:"requested_by,omitempty"`
+	DecidedBy        *int       `json:"decided_by,omitempty"`
+	DecidedAt        *time.Time `json:"decided_at,omitempty"`
+	DecisionNote     *string    `json:"decision_note,omitempty"`
+	ExecutedBy       *int       `json:"executed_by,omitempty"`
+	ExecutedAt       *time.Time `json:"executed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// CreateRequest is the body of POST /api/v1/asset-disposals.
+type CreateRequest struct {
+	AssetID int    `json:"asset_id" validate:"required,gt=0"`
+	Reason  string `json:"reason" validate:"required,min=1,max=1024,no_control_chars" example:"Mast cracked; not economical to repair"`
+	Method  string `json:"method" validate:"required,oneof=sold scrapped recycled donated returned lost stolen other" example:"scrapped"`
+}
+
+// DecisionRequest is the optional body of approve, reject and cancel.
+type DecisionRequest struct {
+	Note *string `json:"note,omitempty" validate:"omitempty,min=1,max=1024,no_control_chars" example:"Approved at the quarterly asset review"`
+}
+
+// ListFilter selects disposals for GET /api/v1/asset-disposals.
+type ListFilter struct {
+	AssetID *int
+	Status  string
+	Limit   int
+}
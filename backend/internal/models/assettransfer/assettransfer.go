@@ -0,0 +1,77 @@
+// Package assettransfer holds the models for moving an asset from one org to
+// another: the sending org initiates, the receiving org approves, and the
+// sending org executes.
+package assettransfer
+
+import "time"
+
+// Transfer statuses. pending → approved → completed is the happy path; a
+// pending transfer can be rejected by the receiving org, and a pending or
+// approved one cancelled by the sending org.
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusCancelled = "cancelled"
+	StatusCompleted = "completed"
+)
+
+// Actions on a transfer.
+const (
+	ActionApprove = "approve"
+	ActionReject  = "reject"
+	ActionCancel  = "cancel"
+	ActionExecute = "execute"
+)
+
+// List directions, relative to the caller's org.
+const (
+	DirectionIncoming = "incoming"
+	DirectionOutgoing = "outgoing"
+)
+
+// OrgRef names an org on either side of a transfer.
+type OrgRef struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Identifier string `json:"identifier"`
+}
+
+// Transfer is one asset transfer as both orgs see it. AssetExternalKey and
+// AssetName are a snapshot taken when the transfer was initiated.
+type Transfer struct {
+	ID               int        `json:"id"`
+	SourceOrg        OrgRef     `json:"source_org"`
+	TargetOrg        OrgRef     `json:"target_org"`
+	AssetID          int        `json:"asset_id"`
+	AssetExternalKey string     `json:"asset_external_key" example:"forklift-3"`
+	AssetName        string     `json:"asset_name" example:"Forklift 3"`
+	IncludeHistory   bool       `json:"include_history"`
+	Note             *string    `json:"note,omitempty"`
+	Status           string     `json:"status" example:"pending"`
+	RequestedBy      *int       `json:"requested_by,omitempty"`
+	DecidedBy        *int       `json:"decided_by,omitempty"`
+	DecidedAt        *time.Time `json:"decided_at,omitempty"`
+	ExecutedBy       *int       `json:"executed_by,omitempty"`
+	ExecutedAt       *time.Time `json:"executed_at,omitempty"`
+	TargetAssetID    *int       `json:"target_asset_id,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// CreateRequest is the body of POST /api/v1/asset-transfers. The receiving
+// org is named by its identifier.
+type CreateRequest struct {
+	AssetID             int     `json:"asset_id" validate:"required,gt=0"`
+	TargetOrgIdentifier string  `json:"target_org_identifier" validate:"required,min=1,max=255" example:"acme-east"`
+	IncludeHistory      bool    `json:"include_history"`
+	Note                *string `json:"note,omitempty" validate:"omitempty,min=1,max=1024,no_control_chars"`
+}
+
+// ListFilter selects transfers for GET /api/v1/asset-transfers. An empty
+// Direction lists both.
+type ListFilter struct {
+	Direction string
+	Status    string
+	Limit     int
+}
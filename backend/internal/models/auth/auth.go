@@ -25,6 +25,9 @@ type SignupRequest struct {
 type LoginRequest struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
+	// OrgAgnostic asks for tokens bound to no org. Each request then takes
+	// its org from the org subdomain or custom domain it arrives on.
+	OrgAgnostic bool `json:"org_agnostic,omitempty"`
 }
 
 // AuthResponse contains an access JWT, a refresh token, the access TTL in
@@ -60,10 +63,12 @@ type LogoutRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
-// ForgotPasswordRequest for POST /api/v1/auth/forgot-password
+// ForgotPasswordRequest for POST /api/v1/auth/forgot-password. ResetURL is
+// optional and only its origin is used, and only when that origin is an
+// allowed link domain; the server builds the reset link itself.
 type ForgotPasswordRequest struct {
 	Email    string `json:"email" validate:"required,email"`
-	ResetURL string `json:"reset_url" validate:"required,url"`
+	ResetURL string `json:"reset_url,omitempty" validate:"omitempty,url"`
 }
 
 // ResetPasswordRequest for POST /api/v1/auth/reset-password
@@ -72,6 +77,26 @@ type ResetPasswordRequest struct {
 	Password string `json:"password" validate:"required,min=8"`
 }
 
+// ChangePasswordRequest for POST /api/v1/me/password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// ChangeEmailRequest for POST /api/v1/me/email. The change takes effect when
+// the link mailed to new_email is followed (ConfirmEmailChangeRequest).
+// ConfirmURL is treated like ForgotPasswordRequest.ResetURL.
+type ChangeEmailRequest struct {
+	NewEmail        string `json:"new_email" validate:"required,email,max=255"`
+	CurrentPassword string `json:"current_password" validate:"required"`
+	ConfirmURL      string `json:"confirm_url,omitempty" validate:"omitempty,url"`
+}
+
+// ConfirmEmailChangeRequest for POST /api/v1/auth/confirm-email-change
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
 // MessageResponse for simple success/error messages
 type MessageResponse struct {
 	Message string `json:"message"`
@@ -0,0 +1,27 @@
+// Package backup holds the model for backup runs: logical dumps of the
+// trakrf schema, each restored into a scratch schema to verify it.
+package backup
+
+import "time"
+
+// Run statuses.
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// Run is one run of the backup command.
+type Run struct {
+	ID            int64
+	Status        string
+	SchemaVersion *uint
+	// ObjectKey is the uploaded dump; nil when the run did not upload.
+	ObjectKey  *string
+	TableCount int
+	RowCount   int64
+	SizeBytes  int64
+	Error      *string
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
@@ -15,7 +15,7 @@ type ErrorDetail struct {
 type BulkImportJob struct {
 	ID            int           `json:"job_id"`
 	OrgID         int           `json:"org_id"`
-	Status        string        `json:"status"` // pending, processing, completed, failed
+	Status        string        `json:"status"` // pending, processing, interrupted, completed, failed
 	TotalRows     int           `json:"total_rows"`
 	ProcessedRows int           `json:"processed_rows"`
 	FailedRows    int           `json:"failed_rows"`
@@ -25,6 +25,18 @@ type BulkImportJob struct {
 	CompletedAt   *time.Time    `json:"completed_at,omitempty"`
 }
 
+// ResumeState is what an interrupted job saves so it can continue where it
+// stopped: the CSV records (header first), the index of the next validated
+// row to insert, and the progress counters accumulated so far.
+type ResumeState struct {
+	Records       [][]string
+	ResumeRow     int
+	ProcessedRows int
+	FailedRows    int
+	TagsCreated   int
+	Errors        []ErrorDetail
+}
+
 // CreateJobRequest is used when creating a new job (Phase 2 will use this)
 type CreateJobRequest struct {
 	OrgID     int `json:"org_id" validate:"required,min=1"`
@@ -0,0 +1,146 @@
+package connector
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+)
+
+// Connector kinds.
+const (
+	KindNetSuite = "netsuite"
+)
+
+// Sync directions, for a connector as a whole and for each field mapping.
+const (
+	DirectionPull = "pull"
+	DirectionPush = "push"
+	DirectionBoth = "both"
+)
+
+// Conflict policies decide which side wins when an asset and its remote
+// record both changed since the last sync.
+const (
+	ConflictRemoteWins = "remote_wins"
+	ConflictLocalWins  = "local_wins"
+	ConflictNewestWins = "newest_wins"
+)
+
+// Sync run statuses. A partial run synced some records and failed others.
+const (
+	RunSucceeded = "succeeded"
+	RunPartial   = "partial"
+	RunFailed    = "failed"
+)
+
+// FieldMapping pairs an asset field with a remote record field. Local is one
+// of external_key, name, description, is_active, cost_center, or
+// metadata.<key>. A Remote prefixed with "!" is a negated boolean (NetSuite's
+// isinactive maps to is_active as "!isinactive"). Direction defaults to both.
+type FieldMapping struct {
+	Local     string `json:"local"               validate:"required,max=128"`
+	Remote    string `json:"remote"              validate:"required,max=128"`
+	Direction string `json:"direction,omitempty" validate:"omitempty,oneof=pull push both"`
+}
+
+// Connector is an org's link to an external system of record. Credentials is
+// the vault-sealed credential JSON and is never serialized.
+type Connector struct {
+	ID                  int             `json:"id"`
+	OrgID               int             `json:"org_id"`
+	Kind                string          `json:"kind"`
+	Name                string          `json:"name"`
+	Config              json.RawMessage `json:"config" swaggertype:"object"`
+	Credentials         []byte          `json:"-"`
+	FieldMapping        []FieldMapping  `json:"field_mapping"`
+	Direction           string          `json:"direction"`
+	ConflictPolicy      string          `json:"conflict_policy"`
+	SyncIntervalSeconds int             `json:"sync_interval_seconds"`
+	IsActive            bool            `json:"is_active"`
+	NextSyncAt          time.Time       `json:"next_sync_at"`
+	RemoteCursor        *time.Time      `json:"-"`
+	LastSyncAt          *time.Time      `json:"last_sync_at"`
+	LastSyncStatus      *string         `json:"last_sync_status"`
+	LastError           *string         `json:"last_error"`
+	CreatedBy           *int            `json:"created_by"`
+	CreatedAt           time.Time       `json:"created_at"`
+	UpdatedAt           time.Time       `json:"updated_at"`
+}
+
+// CreateConnectorRequest is the POST /api/v1/connectors body. Credentials are
+// kind-specific and write-only; an empty FieldMapping takes the kind's
+// default mapping.
+type CreateConnectorRequest struct {
+	Kind                string          `json:"kind"                            validate:"required,oneof=netsuite"`
+	Name                string          `json:"name"                            validate:"required,min=1,max=255"`
+	Config              json.RawMessage `json:"config"                          validate:"required" swaggertype:"object"`
+	Credentials         json.RawMessage `json:"credentials"                     validate:"required" swaggertype:"object"`
+	FieldMapping        []FieldMapping  `json:"field_mapping,omitempty"         validate:"omitempty,dive"`
+	Direction           string          `json:"direction,omitempty"             validate:"omitempty,oneof=pull push both"`
+	ConflictPolicy      string          `json:"conflict_policy,omitempty"       validate:"omitempty,oneof=remote_wins local_wins newest_wins"`
+	SyncIntervalSeconds int             `json:"sync_interval_seconds,omitempty" validate:"omitempty,min=300,max=86400"`
+}
+
+// UpdateConnectorRequest is the PATCH /api/v1/connectors/{connector_id} body.
+// Omitted fields are unchanged; Credentials, when present, replaces the
+// stored credentials wholesale.
+type UpdateConnectorRequest struct {
+	Name                *string          `json:"name,omitempty"                  validate:"omitempty,min=1,max=255"`
+	Config              *json.RawMessage `json:"config,omitempty"                swaggertype:"object"`
+	Credentials         *json.RawMessage `json:"credentials,omitempty"           swaggertype:"object"`
+	FieldMapping        *[]FieldMapping  `json:"field_mapping,omitempty"         validate:"omitempty,dive"`
+	Direction           *string          `json:"direction,omitempty"             validate:"omitempty,oneof=pull push both"`
+	ConflictPolicy      *string          `json:"conflict_policy,omitempty"       validate:"omitempty,oneof=remote_wins local_wins newest_wins"`
+	SyncIntervalSeconds *int             `json:"sync_interval_seconds,omitempty" validate:"omitempty,min=300,max=86400"`
+	IsActive            *bool            `json:"is_active,omitempty"`
+}
+
+// SyncRun is one completed sync of a connector.
+type SyncRun struct {
+	ID          int64     `json:"id"`
+	ConnectorID int       `json:"connector_id"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	Status      string    `json:"status"`
+	Pulled      int       `json:"pulled"`
+	Pushed      int       `json:"pushed"`
+	Conflicts   int       `json:"conflicts"`
+	Failures    int       `json:"failures"`
+	Error       *string   `json:"error"`
+}
+
+// Link pairs an asset with its remote record, with each side's modification
+// time as of the last sync.
+type Link struct {
+	ConnectorID     int
+	AssetID         int
+	RemoteID        string
+	RemoteUpdatedAt time.Time
+	LocalSyncedAt   time.Time
+}
+
+// LocalAsset is a live asset as the sync job sees it: the asset plus its link
+// to this connector, if any.
+type LocalAsset struct {
+	Asset asset.Asset
+	Link  *Link
+}
+
+// AssetFields is the mapped, local-side view of a remote record. Nil fields
+// are not mapped and are left unchanged.
+type AssetFields struct {
+	ExternalKey *string
+	Name        *string
+	Description *string
+	IsActive    *bool
+	CostCenter  *string
+	Metadata    map[string]any
+}
+
+// RemoteRecord is one record read from or written to the remote system.
+type RemoteRecord struct {
+	ID        string
+	UpdatedAt time.Time
+	Fields    map[string]any
+}
@@ -0,0 +1,287 @@
+// Package dashboard holds the models for custom dashboards: named grids of
+// widgets, each a saved query over one of a fixed set of sources plus the
+// visualization it is drawn with. The server evaluates the queries, so a
+// widget never carries SQL.
+package dashboard
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Visualizations a widget can be drawn with. A number shows an ungrouped
+// count; the others show a grouped one.
+const (
+	VisualizationNumber = "number"
+	VisualizationBar    = "bar"
+	VisualizationLine   = "line"
+	VisualizationPie    = "pie"
+	VisualizationTable  = "table"
+)
+
+// Query sources.
+const (
+	SourceAssets         = "assets"
+	SourceScans          = "scans"
+	SourceStockAlerts    = "stock_alerts"
+	SourceSensorAlerts   = "sensor_alerts"
+	SourceDocuments      = "documents"
+	SourceTransferOrders = "transfer_orders"
+	SourceDockMovements  = "dock_movements"
+)
+
+// Source describes what a query over one source counts and how it can be
+// shaped.
+type Source struct {
+	Name        string   `json:"name" example:"scans"`
+	Description string   `json:"description" example:"Asset scans in the last range_days days"`
+	GroupBy     []string `json:"group_by" example:"day,location"`
+	// Windowed sources count only the last RangeDays days.
+	Windowed bool `json:"windowed"`
+	// LocationFilter sources honour Query.LocationID.
+	LocationFilter bool `json:"location_filter"`
+}
+
+// Sources is every query source, in display order.
+var Sources = []Source{
+	{Name: SourceAssets, Description: "Live assets, not deleted or disposed",
+		GroupBy: []string{"location", "team", "owner", "cost_center", "status"}},
+	{Name: SourceScans, Description: "Asset scans in the last range_days days",
+		GroupBy: []string{"day", "location"}, Windowed: true, LocationFilter: true},
+	{Name: SourceStockAlerts, Description: "Open stock alerts",
+		GroupBy: []string{"location"}, LocationFilter: true},
+	{Name: SourceSensorAlerts, Description: "Open sensor alerts",
+		GroupBy: []string{"metric", "condition"}},
+	{Name: SourceDocuments, Description: "Documents of live assets and the org",
+		GroupBy: []string{"type", "expiry_status"}},
+	{Name: SourceTransferOrders, Description: "Transfer orders",
+		GroupBy: []string{"status", "origin", "destination"}},
+	{Name: SourceDockMovements, Description: "Dock door crossings in the last range_days days",
+		GroupBy: []string{"day", "direction", "dock_door"}, Windowed: true, LocationFilter: true},
+}
+
+// LookupSource returns the source called name.
+func LookupSource(name string) (Source, bool) {
+	for _, s := range Sources {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Source{}, false
+}
+
+// Query limits.
+const (
+	DefaultRangeDays = 7
+	MaxRangeDays     = 366
+	DefaultLimit     = 10
+	MaxLimit         = 50
+	MaxWidgets       = 30
+	GridColumns      = 12
+)
+
+// Query is a saved widget query: a count of Source's rows, optionally
+// grouped. RangeDays (default 7) windows a windowed source; Limit (default
+// 10) caps the groups of a grouped one, except a day series, which covers
+// the whole window.
+type Query struct {
+	Source     string `json:"source" validate:"required,oneof=assets scans stock_alerts sensor_alerts documents transfer_orders dock_movements" example:"scans"`
+	GroupBy    string `json:"group_by,omitempty" validate:"omitempty,max=64" example:"day"`
+	RangeDays  int    `json:"range_days,omitempty" validate:"omitempty,min=1,max=366" example:"7"`
+	LocationID *int   `json:"location_id,omitempty" validate:"omitempty,gt=0"`
+	Limit      int    `json:"limit,omitempty" validate:"omitempty,min=1,max=50" example:"10"`
+}
+
+// WithDefaults fills in Limit and, for a windowed source, RangeDays when
+// unset.
+func (q Query) WithDefaults() Query {
+	if src, ok := LookupSource(q.Source); ok && src.Windowed && q.RangeDays == 0 {
+		q.RangeDays = DefaultRangeDays
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultLimit
+	}
+	return q
+}
+
+// FieldError is a query or widget that does not fit the source catalog or
+// the grid. Field is relative to the request body.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string { return e.Message }
+
+// Check reports the first way q does not fit its source, with fields under
+// prefix ("" for a bare query).
+func (q Query) Check(prefix string) error {
+	src, ok := LookupSource(q.Source)
+	if !ok {
+		return &FieldError{Field: prefix + "source", Message: fmt.Sprintf("unknown source %q", q.Source)}
+	}
+	if q.GroupBy != "" && !slices.Contains(src.GroupBy, q.GroupBy) {
+		return &FieldError{Field: prefix + "group_by", Message: fmt.Sprintf("%s can be grouped by: %v", src.Name, src.GroupBy)}
+	}
+	if q.RangeDays != 0 && !src.Windowed {
+		return &FieldError{Field: prefix + "range_days", Message: src.Name + " is not windowed"}
+	}
+	if q.LocationID != nil && !src.LocationFilter {
+		return &FieldError{Field: prefix + "location_id", Message: src.Name + " cannot be filtered by location"}
+	}
+	return nil
+}
+
+// Layout places a widget on the 12-column dashboard grid.
+type Layout struct {
+	X int `json:"x" validate:"min=0,max=11" example:"0"`
+	Y int `json:"y" validate:"min=0,max=1000" example:"0"`
+	W int `json:"w" validate:"min=1,max=12" example:"6"`
+	H int `json:"h" validate:"min=1,max=24" example:"4"`
+}
+
+// Widget is one tile of a dashboard. ID is chosen by the client and keys
+// the widget within its dashboard.
+type Widget struct {
+	ID            string `json:"id" validate:"required,min=1,max=64,no_control_chars" example:"scans-per-day"`
+	Title         string `json:"title" validate:"required,min=1,max=255,no_control_chars" example:"Scans per day"`
+	Visualization string `json:"visualization" validate:"required,oneof=number bar line pie table" example:"line"`
+	Query         Query  `json:"query"`
+	Layout        Layout `json:"layout"`
+}
+
+// CheckWidgets reports the first widget whose query does not fit its
+// source, whose visualization does not fit its query, that overflows the
+// grid or that reuses another's ID.
+func CheckWidgets(widgets []Widget) error {
+	seen := make(map[string]bool, len(widgets))
+	for i, w := range widgets {
+		prefix := fmt.Sprintf("widgets[%d].", i)
+		if seen[w.ID] {
+			return &FieldError{Field: prefix + "id", Message: fmt.Sprintf("duplicate widget id %q", w.ID)}
+		}
+		seen[w.ID] = true
+		if err := w.Query.Check(prefix + "query."); err != nil {
+			return err
+		}
+		if (w.Visualization == VisualizationNumber) != (w.Query.GroupBy == "") {
+			return &FieldError{Field: prefix + "visualization", Message: "a number shows an ungrouped query; other visualizations need a group_by"}
+		}
+		if w.Layout.X+w.Layout.W > GridColumns {
+			return &FieldError{Field: prefix + "layout.w", Message: "widget overflows the 12-column grid"}
+		}
+	}
+	return nil
+}
+
+// Dashboard is a named grid of widgets. It is visible to its owner and,
+// once Shared, to every org member; IsDefault marks the org's default.
+type Dashboard struct {
+	ID          int       `json:"id"`
+	OwnerUserID *int      `json:"owner_user_id,omitempty"`
+	Name        string    `json:"name" example:"Warehouse overview"`
+	Description *string   `json:"description,omitempty"`
+	Shared      bool      `json:"shared"`
+	IsDefault   bool      `json:"is_default"`
+	Widgets     []Widget  `json:"widgets"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateRequest is the body of POST /api/v1/dashboards. IsDefault implies
+// Shared and is for org admins only.
+type CreateRequest struct {
+	Name        string   `json:"name" validate:"required,min=1,max=255,no_control_chars" example:"Warehouse overview"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=4096"`
+	Shared      bool     `json:"shared"`
+	IsDefault   bool     `json:"is_default"`
+	Widgets     []Widget `json:"widgets" validate:"max=30,dive"`
+}
+
+// UpdateRequest is the body of PATCH /api/v1/dashboards/{id}; omitted fields
+// are left unchanged and Widgets, when set, replaces the whole layout.
+// Unsharing the default dashboard also stops it being the default.
+type UpdateRequest struct {
+	Name        *string   `json:"name,omitempty" validate:"omitempty,min=1,max=255,no_control_chars"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,max=4096"`
+	Shared      *bool     `json:"shared,omitempty"`
+	IsDefault   *bool     `json:"is_default,omitempty"`
+	Widgets     *[]Widget `json:"widgets,omitempty" validate:"omitempty,max=30,dive"`
+}
+
+// Point is one group of a grouped result. Key identifies the group (an id,
+// a status or a YYYY-MM-DD day; "" for rows with none) and Label names it.
+type Point struct {
+	Key   string `json:"key" example:"2026-05-01"`
+	Label string `json:"label" example:"2026-05-01"`
+	Value int64  `json:"value" example:"1520"`
+}
+
+// Result is an evaluated query: Value for an ungrouped one, Series for a
+// grouped one. Results are served from the metric cache: ComputedAt is when
+// the counts were taken, and Stale is set once that is more than
+// RefreshInterval ago, as when the refresh job is behind.
+type Result struct {
+	Value      *int64    `json:"value,omitempty" example:"42"`
+	Series     []Point   `json:"series,omitempty"`
+	ComputedAt time.Time `json:"computed_at"`
+	Stale      bool      `json:"stale"`
+}
+
+// Metric refresh policy. Widget results are cached per org and query. The
+// refresh job recomputes a cached result once it is RefreshInterval old, as
+// long as some dashboard asked for it within RetainFor; results nobody has
+// asked for in that long are dropped. A read recomputes inline only a result
+// older than MaxStaleness, or one older than MinRefreshAge when the caller
+// asks for a refresh.
+const (
+	RefreshInterval = 5 * time.Minute
+	MaxStaleness    = 30 * time.Minute
+	MinRefreshAge   = 30 * time.Second
+	RetainFor       = 24 * time.Hour
+)
+
+// NeedsCompute reports whether a result computed at computedAt must be
+// recomputed for a read at now, refresh being the caller's request for
+// fresh counts.
+func NeedsCompute(computedAt, now time.Time, refresh bool) bool {
+	age := now.Sub(computedAt)
+	if refresh {
+		return age >= MinRefreshAge
+	}
+	return age > MaxStaleness
+}
+
+// WidgetResult is one widget's evaluated query, or why it could not be
+// evaluated (a widget saved against a source that has since changed).
+type WidgetResult struct {
+	WidgetID string  `json:"widget_id"`
+	Result   *Result `json:"result,omitempty"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// Builtin is the dashboard served to an org that has not chosen a default:
+// the overview the console used to hard-code.
+func Builtin() Dashboard {
+	return Dashboard{
+		Name:   "Overview",
+		Shared: true,
+		Widgets: []Widget{
+			{ID: "assets", Title: "Assets", Visualization: VisualizationNumber,
+				Query: Query{Source: SourceAssets}, Layout: Layout{X: 0, Y: 0, W: 3, H: 2}},
+			{ID: "stock-alerts", Title: "Open stock alerts", Visualization: VisualizationNumber,
+				Query: Query{Source: SourceStockAlerts}, Layout: Layout{X: 3, Y: 0, W: 3, H: 2}},
+			{ID: "sensor-alerts", Title: "Open sensor alerts", Visualization: VisualizationNumber,
+				Query: Query{Source: SourceSensorAlerts}, Layout: Layout{X: 6, Y: 0, W: 3, H: 2}},
+			{ID: "transfers", Title: "Transfer orders", Visualization: VisualizationPie,
+				Query: Query{Source: SourceTransferOrders, GroupBy: "status"}, Layout: Layout{X: 9, Y: 0, W: 3, H: 4}},
+			{ID: "scans-per-day", Title: "Scans per day", Visualization: VisualizationLine,
+				Query: Query{Source: SourceScans, GroupBy: "day"}, Layout: Layout{X: 0, Y: 2, W: 9, H: 4}},
+			{ID: "assets-by-location", Title: "Assets by location", Visualization: VisualizationBar,
+				Query: Query{Source: SourceAssets, GroupBy: "location"}, Layout: Layout{X: 0, Y: 6, W: 6, H: 4}},
+			{ID: "documents", Title: "Documents", Visualization: VisualizationPie,
+				Query: Query{Source: SourceDocuments, GroupBy: "expiry_status"}, Layout: Layout{X: 6, Y: 6, W: 6, H: 4}},
+		},
+	}
+}
@@ -0,0 +1,106 @@
+package dashboard
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueryCheck(t *testing.T) {
+	loc := 3
+	cases := []struct {
+		name  string
+		q     Query
+		field string
+	}{
+		{"ungrouped", Query{Source: SourceAssets}, ""},
+		{"scans per day at a location", Query{Source: SourceScans, GroupBy: "day", RangeDays: 30, LocationID: &loc}, ""},
+		{"unknown source", Query{Source: "invoices"}, "source"},
+		{"group the source lacks", Query{Source: SourceAssets, GroupBy: "day"}, "group_by"},
+		{"window on an unwindowed source", Query{Source: SourceDocuments, RangeDays: 7}, "range_days"},
+		{"location on a source without one", Query{Source: SourceTransferOrders, LocationID: &loc}, "location_id"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.q.Check("")
+			var fe *FieldError
+			switch {
+			case c.field == "" && err != nil:
+				t.Fatalf("Check = %v, want nil", err)
+			case c.field != "" && (!errors.As(err, &fe) || fe.Field != c.field):
+				t.Fatalf("Check = %v, want field %s", err, c.field)
+			}
+		})
+	}
+}
+
+func TestCheckWidgets(t *testing.T) {
+	number := Widget{ID: "a", Visualization: VisualizationNumber, Query: Query{Source: SourceAssets}, Layout: Layout{W: 3, H: 2}}
+	bar := Widget{ID: "b", Visualization: VisualizationBar, Query: Query{Source: SourceAssets, GroupBy: "team"}, Layout: Layout{X: 3, W: 9, H: 4}}
+
+	cases := []struct {
+		name    string
+		widgets []Widget
+		field   string
+	}{
+		{"fits", []Widget{number, bar}, ""},
+		{"duplicate id", []Widget{number, number}, "widgets[1].id"},
+		{"grouped number", []Widget{{ID: "c", Visualization: VisualizationNumber, Query: bar.Query, Layout: bar.Layout}}, "widgets[0].visualization"},
+		{"ungrouped bar", []Widget{{ID: "c", Visualization: VisualizationBar, Query: number.Query, Layout: number.Layout}}, "widgets[0].visualization"},
+		{"overflows the grid", []Widget{{ID: "c", Visualization: VisualizationNumber, Query: number.Query, Layout: Layout{X: 10, W: 3, H: 1}}}, "widgets[0].layout.w"},
+		{"bad query", []Widget{number, {ID: "c", Visualization: VisualizationBar, Query: Query{Source: SourceScans, GroupBy: "team"}, Layout: number.Layout}}, "widgets[1].query.group_by"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := CheckWidgets(c.widgets)
+			var fe *FieldError
+			switch {
+			case c.field == "" && err != nil:
+				t.Fatalf("CheckWidgets = %v, want nil", err)
+			case c.field != "" && (!errors.As(err, &fe) || fe.Field != c.field):
+				t.Fatalf("CheckWidgets = %v, want field %s", err, c.field)
+			}
+		})
+	}
+}
+
+func TestBuiltinIsValid(t *testing.T) {
+	if err := CheckWidgets(Builtin().Widgets); err != nil {
+		t.Fatalf("builtin dashboard: %v", err)
+	}
+}
+
+func TestNeedsCompute(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		age     time.Duration
+		refresh bool
+		want    bool
+	}{
+		{"fresh", time.Minute, false, false},
+		{"stale but within bounds", 20 * time.Minute, false, false},
+		{"too stale to serve", 31 * time.Minute, false, true},
+		{"refresh of an old result", time.Minute, true, true},
+		{"refresh right after a compute", 10 * time.Second, true, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := NeedsCompute(now.Add(-c.age), now, c.refresh); got != c.want {
+				t.Errorf("NeedsCompute = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestWithDefaults_StillChecks(t *testing.T) {
+	for _, src := range Sources {
+		q := Query{Source: src.Name}.WithDefaults()
+		if err := q.Check(""); err != nil {
+			t.Errorf("%s: %v", src.Name, err)
+		}
+		if src.Windowed != (q.RangeDays == DefaultRangeDays) {
+			t.Errorf("%s: range_days = %d", src.Name, q.RangeDays)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package device holds the mobile app's push registrations: one per app
+// install, owned by the user who signed in on it.
+package device
+
+import "time"
+
+// Push platforms. Android installs hold an FCM registration token, iOS
+// installs an APNs device token.
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+)
+
+// DueAtKey is the asset metadata key holding when a checked-out asset is due
+// back (RFC 3339). Assets have no due-date column; integrators and the app set
+// metadata.due_at, and an asset still carrying a past due_at is overdue.
+const DueAtKey = "due_at"
+
+// Device is a registered app install.
+type Device struct {
+	ID         int       `json:"id"`
+	UserID     int       `json:"-"`
+	Platform   string    `json:"platform" example:"android"`
+	PushToken  string    `json:"push_token"`
+	DeviceName *string   `json:"device_name,omitempty" example:"Zebra TC58"`
+	AppVersion *string   `json:"app_version,omitempty" example:"2.4.1"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RegisterRequest is the body of POST /api/v1/users/me/devices. The app calls
+// it on every launch and whenever the push service rotates its token;
+// registering a known token refreshes it instead of adding a device.
+type RegisterRequest struct {
+	Platform   string  `json:"platform" validate:"required,oneof=ios android" example:"android"`
+	PushToken  string  `json:"push_token" validate:"required,min=1,max=4096,no_control_chars"`
+	DeviceName *string `json:"device_name,omitempty" validate:"omitempty,min=1,max=255,no_control_chars" example:"Zebra TC58"`
+	AppVersion *string `json:"app_version,omitempty" validate:"omitempty,min=1,max=64,no_control_chars" example:"2.4.1"`
+}
+
+// OverdueAsset is an asset whose due date passed, claimed for notification,
+// with the devices of everyone to notify.
+type OverdueAsset struct {
+	OrgID       int
+	AssetID     int
+	ExternalKey string
+	Name        string
+	DueAt       time.Time
+	Devices     []Device
+}
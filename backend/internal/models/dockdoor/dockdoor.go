@@ -0,0 +1,82 @@
+// Package dockdoor holds the models for dock door direction detection: a
+// door pairs an outer (yard-side) and an inner (building-side) scan point,
+// and the order in which the pair hears a tag gives the direction it moved.
+package dockdoor
+
+import "time"
+
+// Movement directions. A tag heard first on the outer scan point and then on
+// the inner one came in (receiving); the reverse went out (shipping).
+const (
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+)
+
+// Pass window bounds, in seconds. Reads of one tag at a door further apart
+// than the window start a new pass.
+const (
+	DefaultWindowSeconds = 10
+	MaxWindowSeconds     = 300
+)
+
+// Door is a dock door: two scan points across one opening.
+type Door struct {
+	ID               int       `json:"id"`
+	OrgID            int       `json:"org_id"`
+	LocationID       int       `json:"location_id"`
+	Name             string    `json:"name" example:"Dock 3"`
+	OuterScanPointID int       `json:"outer_scan_point_id"`
+	InnerScanPointID int       `json:"inner_scan_point_id"`
+	WindowSeconds    int       `json:"window_seconds" example:"10"`
+	IsActive         bool      `json:"is_active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Window is the door's pass window.
+func (d Door) Window() time.Duration {
+	if d.WindowSeconds <= 0 {
+		return DefaultWindowSeconds * time.Second
+	}
+	return time.Duration(d.WindowSeconds) * time.Second
+}
+
+// Direction is the direction of a pass first heard on the outer scan point
+// when fromOuter is set, and on the inner one otherwise.
+func Direction(fromOuter bool) string {
+	if fromOuter {
+		return DirectionInbound
+	}
+	return DirectionOutbound
+}
+
+// CreateDoorRequest is the body of POST /api/v1/dock-doors.
+type CreateDoorRequest struct {
+	LocationID       int    `json:"location_id" validate:"required,gt=0"`
+	Name             string `json:"name" validate:"required,min=1,max=255,no_control_chars" example:"Dock 3"`
+	OuterScanPointID int    `json:"outer_scan_point_id" validate:"required,gt=0"`
+	InnerScanPointID int    `json:"inner_scan_point_id" validate:"required,gt=0,nefield=OuterScanPointID"`
+	WindowSeconds    *int   `json:"window_seconds,omitempty" validate:"omitempty,min=1,max=300" example:"10"`
+}
+
+// UpdateDoorRequest is the body of PATCH /api/v1/dock-doors/{id}; omitted
+// fields are left unchanged.
+type UpdateDoorRequest struct {
+	LocationID       *int    `json:"location_id,omitempty" validate:"omitempty,gt=0"`
+	Name             *string `json:"name,omitempty" validate:"omitempty,min=1,max=255,no_control_chars"`
+	OuterScanPointID *int    `json:"outer_scan_point_id,omitempty" validate:"omitempty,gt=0"`
+	InnerScanPointID *int    `json:"inner_scan_point_id,omitempty" validate:"omitempty,gt=0"`
+	WindowSeconds    *int    `json:"window_seconds,omitempty" validate:"omitempty,min=1,max=300"`
+	IsActive         *bool   `json:"is_active,omitempty"`
+}
+
+// Movement is one crossing of a door, as the direction engine records it.
+type Movement struct {
+	ID          int       `json:"id"`
+	DockDoorID  int       `json:"dock_door_id"`
+	AssetID     int       `json:"asset_id"`
+	Direction   string    `json:"direction" example:"inbound"`
+	FirstReadAt time.Time `json:"first_read_at"`
+	CrossedAt   time.Time `json:"crossed_at"`
+	TagScanID   int64     `json:"-"`
+}
@@ -0,0 +1,118 @@
+// Package document holds the models for the document vault: insurance
+// policies, leases, calibration certificates and similar papers kept against
+// an asset or the org, whose expiry dates raise alerts ahead of time.
+package document
+
+import "time"
+
+// Document types.
+const (
+	TypeInsurance       = "insurance"
+	TypeLease           = "lease"
+	TypeCalibrationCert = "calibration_cert"
+	TypeWarranty        = "warranty"
+	TypeRegistration    = "registration"
+	TypeOther           = "other"
+)
+
+// Types is every document type, in display order.
+var Types = []string{TypeInsurance, TypeLease, TypeCalibrationCert, TypeWarranty, TypeRegistration, TypeOther}
+
+// IsType reports whether t is one of Types.
+func IsType(t string) bool {
+	for _, v := range Types {
+		if t == v {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultRemindDays is how long before expiry a document raises
+// document.expiring when the request does not say.
+const DefaultRemindDays = 30
+
+// Expiry statuses. A document without an expiry date has none.
+const (
+	ExpiryValid    = "valid"
+	ExpiryExpiring = "expiring"
+	ExpiryExpired  = "expired"
+)
+
+// ExpiryStatus is the status at now of a document expiring at expiresAt
+// (nil for never) that reminds remindDays ahead.
+func ExpiryStatus(expiresAt *time.Time, remindDays int, now time.Time) string {
+	switch {
+	case expiresAt == nil:
+		return ""
+	case !now.Before(*expiresAt):
+		return ExpiryExpired
+	case remindDays > 0 && !now.Before(expiresAt.AddDate(0, 0, -remindDays)):
+		return ExpiryExpiring
+	default:
+		return ExpiryValid
+	}
+}
+
+// File is a document's scanned copy, served from the object store.
+type File struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type" example:"application/pdf"`
+	SizeBytes   int    `json:"size_bytes" example:"183422"`
+}
+
+// Document is one document of an asset (AssetID set) or of the org.
+type Document struct {
+	ID           int        `json:"id"`
+	AssetID      *int       `json:"asset_id,omitempty"`
+	Type         string     `json:"type" example:"insurance"`
+	Title        string     `json:"title" example:"Fleet insurance 2026"`
+	Reference    *string    `json:"reference,omitempty" example:"POL-88231"`
+	Issuer       *string    `json:"issuer,omitempty" example:"Acme Mutual"`
+	IssuedAt     *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
+	RemindDays   int        `json:"remind_days" example:"30"`
+	ExpiryStatus string     `json:"expiry_status,omitempty" example:"valid"`
+	Notes        *string    `json:"notes,omitempty"`
+	File         *File      `json:"file,omitempty"`
+	CreatedBy    *int       `json:"created_by,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// CreateRequest is the body of POST /api/v1/documents. Omit AssetID for an
+// org-level document.
+type CreateRequest struct {
+	AssetID    *int       `json:"asset_id,omitempty" validate:"omitempty,gt=0"`
+	Type       string     `json:"type" validate:"required,oneof=insurance lease calibration_cert warranty registration other" example:"insurance"`
+	Title      string     `json:"title" validate:"required,min=1,max=255,no_control_chars" example:"Fleet insurance 2026"`
+	Reference  *string    `json:"reference,omitempty" validate:"omitempty,min=1,max=255,no_control_chars" example:"POL-88231"`
+	Issuer     *string    `json:"issuer,omitempty" validate:"omitempty,min=1,max=255,no_control_chars" example:"Acme Mutual"`
+	IssuedAt   *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RemindDays *int       `json:"remind_days,omitempty" validate:"omitempty,min=0,max=365" example:"30"`
+	Notes      *string    `json:"notes,omitempty" validate:"omitempty,max=4096"`
+}
+
+// UpdateRequest is the body of PATCH /api/v1/documents/{id}; omitted fields
+// are left unchanged. Setting ExpiresAt or RemindDays re-arms the expiry
+// alerts, as for a renewed policy.
+type UpdateRequest struct {
+	Type       *string    `json:"type,omitempty" validate:"omitempty,oneof=insurance lease calibration_cert warranty registration other"`
+	Title      *string    `json:"title,omitempty" validate:"omitempty,min=1,max=255,no_control_chars"`
+	Reference  *string    `json:"reference,omitempty" validate:"omitempty,min=1,max=255,no_control_chars"`
+	Issuer     *string    `json:"issuer,omitempty" validate:"omitempty,min=1,max=255,no_control_chars"`
+	IssuedAt   *time.Time `json:"issued_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RemindDays *int       `json:"remind_days,omitempty" validate:"omitempty,min=0,max=365"`
+	Notes      *string    `json:"notes,omitempty" validate:"omitempty,max=4096"`
+}
+
+// ListFilter selects GET /api/v1/documents: only AssetID's documents when
+// set, only org-level ones when OrgOnly, and only Type when set.
+type ListFilter struct {
+	AssetID int
+	OrgOnly bool
+	Type    string
+	Limit   int
+}
@@ -0,0 +1,37 @@
+package document
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiryStatus(t *testing.T) {
+	expires := time.Date(2026, 7, 31, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		name   string
+		at     *time.Time
+		remind int
+		now    time.Time
+		want   string
+	}{
+		{"no expiry date", nil, 30, expires, ""},
+		{"before the reminder window", &expires, 30, expires.AddDate(0, 0, -31), ExpiryValid},
+		{"reminder window opens", &expires, 30, expires.AddDate(0, 0, -30), ExpiryExpiring},
+		{"inside the reminder window", &expires, 30, expires.Add(-time.Hour), ExpiryExpiring},
+		{"no reminder window", &expires, 0, expires.Add(-time.Hour), ExpiryValid},
+		{"expires", &expires, 30, expires, ExpiryExpired},
+		{"long expired", &expires, 30, expires.AddDate(1, 0, 0), ExpiryExpired},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ExpiryStatus(tc.at, tc.remind, tc.now))
+		})
+	}
+}
+
+func TestIsType(t *testing.T) {
+	assert.True(t, IsType(TypeCalibrationCert))
+	assert.False(t, IsType("passport"))
+	assert.False(t, IsType(""))
+}
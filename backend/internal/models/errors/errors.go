@@ -18,6 +18,8 @@ const (
 	ErrUnsupportedMedia  ErrorType = "unsupported_media_type"
 	ErrMissingOrgContext ErrorType = "missing_org_context"
 	ErrPaymentRequired   ErrorType = "payment_required"
+	ErrPayloadTooLarge   ErrorType = "payload_too_large"
+	ErrUnavailable       ErrorType = "service_unavailable"
 )
 
 // FieldError describes a single field-level validation failure.
@@ -46,6 +48,9 @@ const (
 //     parameter is valid on the list sibling). Distinct from
 //     unknown_field, which is reserved for parameters the surface does
 //     not recognise at all. TRA-777 / BB62 F3.
+//   - duplicate: the value repeats another item of the same batch or is
+//     already held by an existing row (POST /assets/validate). The message
+//     names which. No params.
 //
 // Numeric values are float64 so that both integer constraints ("8") and
 // fractional constraints ("1.5") parse without loss. JSON numbers decode to
@@ -55,7 +60,7 @@ const (
 // Params is omitted entirely when no structured data is available.
 type FieldError struct {
 	Field   string         `json:"field"`
-	Code    string         `json:"code" example:"required" enums:"required,invalid_value,unknown_field,too_short,too_long,too_small,too_large,fk_not_found,ambiguous_fields,read_only,invalid_context" extensions:"x-extensible-enum=true"`
+	Code    string         `json:"code" example:"required" enums:"required,invalid_value,unknown_field,too_short,too_long,too_small,too_large,fk_not_found,ambiguous_fields,read_only,invalid_context,duplicate" extensions:"x-extensible-enum=true"`
 	Message string         `json:"message"`
 	Params  map[string]any `json:"params,omitempty"`
 }
@@ -77,13 +82,16 @@ type FieldError struct {
 // independently-importable schema name (e.g. ErrorEnvelope rather than
 // openapi-generator-cli's `ErrorResponseError`).
 type ErrorEnvelope struct {
-	Type      string       `json:"type" example:"validation_error" enums:"validation_error,bad_request,unauthorized,forbidden,not_found,conflict,rate_limited,internal_error,method_not_allowed,unsupported_media_type,missing_org_context,payment_required" extensions:"x-extensible-enum=true"`
+	Type      string       `json:"type" example:"validation_error" enums:"validation_error,bad_request,unauthorized,forbidden,not_found,conflict,rate_limited,internal_error,method_not_allowed,unsupported_media_type,missing_org_context,payment_required,payload_too_large" extensions:"x-extensible-enum=true"`
 	Title     string       `json:"title"`
 	Status    int          `json:"status"`
 	Detail    string       `json:"detail"`
 	Instance  string       `json:"instance"`
 	RequestID string       `json:"request_id"`
 	Fields    []FieldError `json:"fields,omitempty"`
+	// Locale is the language detail and fields[].message are written in,
+	// negotiated from Accept-Language (en, es, fr).
+	Locale string `json:"locale" example:"en"`
 }
 
 // ErrorResponse wraps ErrorEnvelope under the `error` key — the wire shape
@@ -125,6 +133,10 @@ func TitleForType(t ErrorType) string {
 		return "Missing org context"
 	case ErrPaymentRequired:
 		return "Payment required"
+	case ErrPayloadTooLarge:
+		return "Payload too large"
+	case ErrUnavailable:
+		return "Service unavailable"
 	}
 	return "Error"
 }
@@ -41,6 +41,9 @@ func TestTitleForType_PinnedPerType(t *testing.T) {
 		ErrMethodNotAllowed:  "Method not allowed",
 		ErrUnsupportedMedia:  "Unsupported media type",
 		ErrMissingOrgContext: "Missing org context",
+		ErrPaymentRequired:   "Payment required",
+		ErrPayloadTooLarge:   "Payload too large",
+		ErrUnavailable:       "Service unavailable",
 	}
 	for typ, want := range cases {
 		got := TitleForType(typ)
@@ -0,0 +1,42 @@
+// Package integration holds the flattened shapes served to no-code automation
+// tools (Zapier, Make). Fields are deliberately flat — those tools map one
+// level of keys into their UI — and every item carries a unique "id" the tool
+// dedupes on.
+package integration
+
+import "time"
+
+// Cursor is a position in a polling trigger's stream: items strictly after
+// (At, ID) are new.
+type Cursor struct {
+	At time.Time
+	ID int
+}
+
+// NewAssetItem is one item of the new-assets trigger. ID is the asset id.
+type NewAssetItem struct {
+	ID          int       `json:"id"`
+	ExternalKey string    `json:"external_key"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// MovedAssetItem is one item of the moved-assets trigger: an asset seen at a
+// location other than its previous one. ID is unique per move
+// ("<asset_id>-<unix nanos>"); the From* fields are null on an asset's first
+// sighting.
+type MovedAssetItem struct {
+	ID                      string    `json:"id"`
+	AssetID                 int       `json:"asset_id"`
+	AssetExternalKey        string    `json:"asset_external_key"`
+	AssetName               string    `json:"asset_name"`
+	FromLocationID          *int      `json:"from_location_id"`
+	FromLocationExternalKey *string   `json:"from_location_external_key"`
+	FromLocationName        *string   `json:"from_location_name"`
+	ToLocationID            int       `json:"to_location_id"`
+	ToLocationExternalKey   *string   `json:"to_location_external_key"`
+	ToLocationName          *string   `json:"to_location_name"`
+	MovedAt                 time.Time `json:"moved_at"`
+}
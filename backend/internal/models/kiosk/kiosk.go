@@ -0,0 +1,64 @@
+// Package kiosk holds the models for display tokens: long-lived, read-only
+// credentials that let a TV show one location's live inventory without a user
+// session.
+package kiosk
+
+import "time"
+
+// MaxDashboardAssets caps the assets a dashboard lists; Count still reports
+// the full number.
+const MaxDashboardAssets = 500
+
+// Token is a kiosk token as the managing admin sees it. The secret itself is
+// never returned after creation.
+type Token struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"name" example:"Dock 4 lobby TV"`
+	LocationID   int        `json:"location_id"`
+	LocationName string     `json:"location_name" example:"Dock 4"`
+	CreatedBy    *int       `json:"created_by,omitempty"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreateRequest is the body of POST /api/v1/kiosk-tokens.
+type CreateRequest struct {
+	Name       string `json:"name" validate:"required,min=1,max=255,no_control_chars" example:"Dock 4 lobby TV"`
+	LocationID int    `json:"location_id" validate:"required,gt=0"`
+}
+
+// CreateResponse carries the new token and, this one time, its secret.
+type CreateResponse struct {
+	Token
+	Secret string `json:"token" example:"trakrf_3f9a..."`
+}
+
+// Principal is what a presented kiosk token resolves to.
+type Principal struct {
+	TokenID    int
+	OrgID      int
+	LocationID int
+}
+
+// DashboardLocation names the location a kiosk shows.
+type DashboardLocation struct {
+	ID          int    `json:"id"`
+	ExternalKey string `json:"external_key" example:"DOCK-4"`
+	Name        string `json:"name" example:"Dock 4"`
+}
+
+// DashboardAsset is one asset last seen at the kiosk's location.
+type DashboardAsset struct {
+	ExternalKey string    `json:"external_key" example:"ASSET-0042"`
+	Name        string    `json:"name" example:"Forklift 3"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// Dashboard is the kiosk's view: what is at its location right now, most
+// recently seen first.
+type Dashboard struct {
+	Location DashboardLocation `json:"location"`
+	Count    int               `json:"count"`
+	Assets   []DashboardAsset  `json:"assets"`
+}
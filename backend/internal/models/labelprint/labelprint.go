@@ -0,0 +1,123 @@
+// Package labelprint holds the models for printing asset labels on networked
+// Zebra printers: printers registered per location, and the print jobs that
+// send ZPL to them.
+package labelprint
+
+import (
+	"net"
+	"strconv"
+	"time"
+)
+
+// Print job statuses. pending → printing → completed is the happy path; a
+// job whose printer cannot be reached goes back to pending until its
+// attempts run out, then fails.
+const (
+	StatusPending   = "pending"
+	StatusPrinting  = "printing"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+// DefaultPort is the raw-print (JetDirect) port Zebra printers listen on.
+const DefaultPort = 9100
+
+// Print job bounds.
+const (
+	MaxJobAssets = 100
+	MaxCopies    = 10
+)
+
+// Printer is a networked ZPL printer at one location.
+type Printer struct {
+	ID         int       `json:"id"`
+	OrgID      int       `json:"org_id"`
+	LocationID int       `json:"location_id"`
+	Name       string    `json:"name" example:"Dock 3 Zebra"`
+	Host       string    `json:"host" example:"10.20.0.31"`
+	Port       int       `json:"port" example:"9100"`
+	DPI        int       `json:"dpi" example:"203"`
+	EncodeRFID bool      `json:"encode_rfid"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Addr is the printer's host:port.
+func (p Printer) Addr() string {
+	if p.Port == 0 {
+		return joinHostPort(p.Host, DefaultPort)
+	}
+	return joinHostPort(p.Host, p.Port)
+}
+
+// CreatePrinterRequest is the body of POST /api/v1/label-printers.
+type CreatePrinterRequest struct {
+	LocationID int    `json:"location_id" validate:"required,gt=0"`
+	Name       string `json:"name" validate:"required,min=1,max=255,no_control_chars" example:"Dock 3 Zebra"`
+	Host       string `json:"host" validate:"required,hostname_rfc1123|ip" example:"10.20.0.31"`
+	Port       *int   `json:"port,omitempty" validate:"omitempty,min=1,max=65535" example:"9100"`
+	DPI        *int   `json:"dpi,omitempty" validate:"omitempty,oneof=203 300 600" example:"203"`
+	EncodeRFID bool   `json:"encode_rfid"`
+}
+
+// UpdatePrinterRequest is the body of PATCH /api/v1/label-printers/{id};
+// omitted fields are left unchanged.
+type UpdatePrinterRequest struct {
+	LocationID *int    `json:"location_id,omitempty" validate:"omitempty,gt=0"`
+	Name       *string `json:"name,omitempty" validate:"omitempty,min=1,max=255,no_control_chars"`
+	Host       *string `json:"host,omitempty" validate:"omitempty,hostname_rfc1123|ip"`
+	Port       *int    `json:"port,omitempty" validate:"omitempty,min=1,max=65535"`
+	DPI        *int    `json:"dpi,omitempty" validate:"omitempty,oneof=203 300 600"`
+	EncodeRFID *bool   `json:"encode_rfid,omitempty"`
+	IsActive   *bool   `json:"is_active,omitempty"`
+}
+
+// LabelAsset is what one asset label shows: its name and external key as
+// text and a Code 128 barcode, and, on RFID printers, its active RFID tag's
+// EPC written to the inlay.
+type LabelAsset struct {
+	ID          int
+	ExternalKey string
+	Name        string
+	EPC         *string
+}
+
+// Job is one print job. ZPL is the rendered labels, returned only by the
+// single-job GET.
+type Job struct {
+	ID          int        `json:"id"`
+	OrgID       int        `json:"org_id"`
+	PrinterID   int        `json:"printer_id"`
+	Status      string     `json:"status" example:"pending"`
+	AssetIDs    []int      `json:"asset_ids"`
+	Copies      int        `json:"copies" example:"1"`
+	LabelCount  int        `json:"label_count" example:"12"`
+	RequestedBy *int       `json:"requested_by,omitempty"`
+	Attempts    int        `json:"attempts"`
+	Error       *string    `json:"error,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	ZPL         string     `json:"zpl,omitempty"`
+}
+
+// CreateJobRequest is the body of POST /api/v1/print-jobs.
+type CreateJobRequest struct {
+	PrinterID int   `json:"printer_id" validate:"required,gt=0"`
+	AssetIDs  []int `json:"asset_ids" validate:"required,min=1,max=100,unique,dive,gt=0"`
+	Copies    *int  `json:"copies,omitempty" validate:"omitempty,min=1,max=10" example:"1"`
+}
+
+func joinHostPort(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// JobFilter narrows GET /api/v1/print-jobs; zero fields match everything.
+type JobFilter struct {
+	PrinterID int
+	Status    string
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,101 @@
+package location
+
+import (
+	"fmt"
+
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+)
+
+// MaxApplyNodes bounds the size of one declarative apply.
+const MaxApplyNodes = 10000
+
+// Apply change actions, in the order they are reported for one location.
+const (
+	ApplyActionCreate = "create"
+	ApplyActionRename = "rename"
+	ApplyActionMove   = "move"
+	ApplyActionUpdate = "update"
+)
+
+// ApplyNode is one location of a desired-state tree. A location is matched by
+// external_key; RenamedFrom names the key it had before, so changing a key in
+// the tree renames the location rather than creating a new one.
+type ApplyNode struct {
+	ExternalKey string      `json:"external_key" yaml:"external_key" validate:"required,min=1,max=255,external_key_pattern" example:"wh1"`
+	Name        string      `json:"name" yaml:"name" validate:"required,min=1,max=255,display_name" example:"Warehouse 1"`
+	Description string      `json:"description,omitempty" yaml:"description,omitempty" validate:"omitempty,max=1024,no_control_chars"`
+	RenamedFrom *string     `json:"renamed_from,omitempty" yaml:"renamed_from,omitempty" validate:"omitempty,min=1,max=255,external_key_pattern" example:"warehouse-1"`
+	Children    []ApplyNode `json:"children,omitempty" yaml:"children,omitempty" validate:"omitempty,dive"`
+}
+
+// ApplyRequest is the body of PUT /api/v1/locations:apply: the whole
+// location hierarchy as it should be, roots first.
+type ApplyRequest struct {
+	Locations []ApplyNode `json:"locations" yaml:"locations" validate:"dive"`
+}
+
+// ApplyChange is what an apply did, or would do, to one location.
+type ApplyChange struct {
+	ExternalKey string   `json:"external_key" example:"wh1"`
+	Actions     []string `json:"actions" example:"create"`
+	// RenamedFrom is the previous external_key of a renamed location.
+	RenamedFrom *string `json:"renamed_from,omitempty"`
+	// ParentExternalKey is the parent after the apply; null for a root.
+	ParentExternalKey *string `json:"parent_external_key"`
+}
+
+// ApplyResult is the response of PUT /api/v1/locations:apply. Applying the
+// same tree again reports no changes.
+type ApplyResult struct {
+	DryRun    bool          `json:"dry_run"`
+	Changes   []ApplyChange `json:"changes"`
+	Unchanged int           `json:"unchanged"`
+	// Unmanaged lists the live locations the tree does not mention. They are
+	// left as they are; delete them individually if they should go.
+	Unmanaged []string `json:"unmanaged"`
+}
+
+// CheckApplyTree reports the structural problems validation tags cannot see:
+// an external_key (or renamed_from) used twice, and a tree over
+// MaxApplyNodes. Field paths name the offending node, e.g.
+// locations[0].children[2].external_key.
+func CheckApplyTree(nodes []ApplyNode) []modelerrors.FieldError {
+	var errs []modelerrors.FieldError
+	seen := map[string]string{}
+	count := 0
+
+	claim := func(key, field string) {
+		if prev, ok := seen[key]; ok {
+			errs = append(errs, modelerrors.FieldError{
+				Field:   field,
+				Code:    "invalid_value",
+				Message: fmt.Sprintf("%q is already used by %s", key, prev),
+			})
+			return
+		}
+		seen[key] = field
+	}
+
+	var walk func(nodes []ApplyNode, path string)
+	walk = func(nodes []ApplyNode, path string) {
+		for i, n := range nodes {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			count++
+			claim(n.ExternalKey, p+".external_key")
+			if n.RenamedFrom != nil && *n.RenamedFrom != n.ExternalKey {
+				claim(*n.RenamedFrom, p+".renamed_from")
+			}
+			walk(n.Children, p+".children")
+		}
+	}
+	walk(nodes, "locations")
+
+	if count > MaxApplyNodes {
+		errs = append(errs, modelerrors.FieldError{
+			Field:   "locations",
+			Code:    "too_long",
+			Message: fmt.Sprintf("the tree has %d locations; at most %d can be applied at once", count, MaxApplyNodes),
+		})
+	}
+	return errs
+}
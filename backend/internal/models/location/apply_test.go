@@ -0,0 +1,47 @@
+package location
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckApplyTree_AcceptsUniqueKeys(t *testing.T) {
+	old := "wh-old"
+	errs := CheckApplyTree([]ApplyNode{
+		{ExternalKey: "wh1", Name: "Warehouse", RenamedFrom: &old, Children: []ApplyNode{
+			{ExternalKey: "wh1-a", Name: "Aisle A"},
+		}},
+		{ExternalKey: "wh2", Name: "Overflow"},
+	})
+	assert.Empty(t, errs)
+}
+
+func TestCheckApplyTree_RejectsDuplicateKeys(t *testing.T) {
+	wh2 := "wh2"
+	errs := CheckApplyTree([]ApplyNode{
+		{ExternalKey: "wh1", Name: "Warehouse", Children: []ApplyNode{
+			{ExternalKey: "dock", Name: "Dock"},
+		}},
+		{ExternalKey: "dock", Name: "Dock again"},
+		{ExternalKey: "wh3", Name: "Renamed", RenamedFrom: &wh2},
+		{ExternalKey: "wh2", Name: "Still here"},
+	})
+
+	require.Len(t, errs, 2)
+	assert.Equal(t, "locations[1].external_key", errs[0].Field)
+	assert.Contains(t, errs[0].Message, "locations[0].children[0].external_key")
+	assert.Equal(t, "locations[3].external_key", errs[1].Field)
+}
+
+func TestCheckApplyTree_RejectsOversizedTree(t *testing.T) {
+	nodes := make([]ApplyNode, MaxApplyNodes+1)
+	for i := range nodes {
+		nodes[i] = ApplyNode{ExternalKey: "loc-" + strconv.Itoa(i), Name: "n"}
+	}
+	errs := CheckApplyTree(nodes)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "too_long", errs[0].Code)
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/trakrf/platform/backend/internal/models/org"
 	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/util/filterexpr"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
 )
 
@@ -174,6 +176,23 @@ type LocationWithParent struct {
 	ParentExternalKey *string `json:"parent_external_key,omitempty"`
 }
 
+// FilterFields are the fields the locations list `filter` parameter accepts.
+var FilterFields = filterexpr.Fields{
+	"external_key":        filterexpr.String,
+	"name":                filterexpr.String,
+	"description":         filterexpr.String,
+	"parent_external_key": filterexpr.String,
+	"id":                  filterexpr.Number,
+	"parent_id":           filterexpr.Number,
+	"team_id":             filterexpr.Number,
+	"is_active":           filterexpr.Bool,
+	"valid_from":          filterexpr.Time,
+	"valid_to":            filterexpr.Time,
+	"created_at":          filterexpr.Time,
+	"updated_at":          filterexpr.Time,
+	"metadata":            filterexpr.JSON,
+}
+
 // ListFilter carries the optional filters the locations list endpoint supports.
 //
 // ParentIDs and ParentExternalKeys are mutually exclusive at the handler
@@ -187,13 +206,29 @@ type ListFilter struct {
 	ExternalKeys []string
 	IsActive     *bool
 	Q            *string
+	// Any-of match on l.team_id.
+	TeamIDs []int
+	// TeamScope is the caller's strict-mode visibility; when restricted it
+	// hides every row not assigned to one of the caller's teams.
+	TeamScope team.Scope
+	// SubtreeScope is the caller's location access policy scope; when
+	// restricted it hides every row outside the granted subtrees.
+	SubtreeScope SubtreeScope
 	// IncludeDeleted relaxes the default l.deleted_at IS NULL filter so
 	// soft-deleted rows are returned alongside live rows. Orthogonal to
 	// IsActive (TRA-659 / BB25 A3). Temporal validity still applies.
 	IncludeDeleted bool
-	Sorts          []ListSort
-	Limit          int
-	Offset         int
+	// AsOf evaluates temporal validity (valid_from/valid_to) at this instant
+	// instead of now, listing the rows that were or will be effective then.
+	AsOf *time.Time
+	// Filter is the parsed `filter` query parameter, ANDed with the rest.
+	Filter filterexpr.Expr
+	// Fields is the response's sparse fieldset; when set without "tags",
+	// tags are not loaded.
+	Fields []string
+	Sorts  []ListSort
+	Limit  int
+	Offset int
 }
 
 type ListSort struct {
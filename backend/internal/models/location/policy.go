@@ -0,0 +1,31 @@
+package location
+
+import "time"
+
+// AccessPolicy grants one user, or every member of one team, access to the
+// location subtree rooted at LocationID. Exactly one of UserID and TeamID is
+// set.
+type AccessPolicy struct {
+	ID                  int       `json:"id"`
+	LocationID          int       `json:"location_id"`
+	LocationExternalKey string    `json:"location_external_key"`
+	UserID              *int      `json:"user_id"`
+	TeamID              *int      `json:"team_id"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+// CreateAccessPolicyRequest names the subtree root and exactly one grantee
+// (the exactly-one rule is checked by the handler).
+type CreateAccessPolicyRequest struct {
+	LocationID int  `json:"location_id" validate:"required,gt=0"`
+	UserID     *int `json:"user_id,omitempty" validate:"omitempty,gt=0"`
+	TeamID     *int `json:"team_id,omitempty" validate:"omitempty,gt=0"`
+}
+
+// SubtreeScope is the location visibility one caller's access policies give
+// them. When Restricted, only locations at or below one of RootIDs are
+// visible.
+type SubtreeScope struct {
+	Restricted bool
+	RootIDs    []int
+}
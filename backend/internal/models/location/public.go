@@ -43,6 +43,14 @@ type PublicLocationView struct {
 	Tags              []shared.Tag       `json:"tags"`
 }
 
+// PublicFields are the PublicLocationView keys a list `fields=` parameter can
+// select.
+var PublicFields = []string{
+	"id", "external_key", "name", "description", "parent_id",
+	"parent_external_key", "is_active", "valid_from", "valid_to",
+	"created_at", "updated_at", "deleted_at", "tags",
+}
+
 func ToPublicLocationView(l LocationWithParent) PublicLocationView {
 	var desc *string
 	if l.Description != "" {
@@ -0,0 +1,52 @@
+package location
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTimeZone is the zone a location reports in when neither it nor any
+// ancestor sets one.
+const DefaultTimeZone = "UTC"
+
+// MaxShifts caps the shifts one location may define.
+const MaxShifts = 24
+
+// Shift is a recurring working period at a location, in its local time.
+type Shift struct {
+	Name string `json:"name" validate:"required,min=1,max=64,no_control_chars" example:"Day"`
+	// Start and End are local wall-clock times, HH:MM. An End at or before
+	// Start ends the next day, e.g. 22:00-06:00.
+	Start string `json:"start" validate:"required" example:"06:00"`
+	End   string `json:"end" validate:"required" example:"14:00"`
+	// Weekdays are the ISO days the shift starts on, 1 (Monday) to 7
+	// (Sunday). Empty means every day.
+	Weekdays []int `json:"weekdays" validate:"omitempty,max=7,dive,min=1,max=7" example:"1,2,3,4,5"`
+}
+
+// ParseClock parses an HH:MM wall-clock time.
+func ParseClock(s string) (time.Time, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an HH:MM time", s)
+	}
+	return t, nil
+}
+
+// Schedule is a location's reporting time zone and shifts.
+type Schedule struct {
+	LocationID int `json:"location_id"`
+	// TimeZone is the IANA zone set on this location; null inherits.
+	TimeZone *string `json:"time_zone" example:"America/Chicago"`
+	// EffectiveTimeZone is the zone reports use: TimeZone, else the nearest
+	// ancestor's, else UTC.
+	EffectiveTimeZone string  `json:"effective_time_zone" example:"America/Chicago"`
+	Shifts            []Shift `json:"shifts"`
+}
+
+// ScheduleInput is the body of PUT /api/v1/locations/{location_id}/schedule,
+// replacing the location's time zone and shifts.
+type ScheduleInput struct {
+	TimeZone *string `json:"time_zone" validate:"omitempty,max=64" example:"America/Chicago"`
+	Shifts   []Shift `json:"shifts" validate:"max=24,dive"`
+}
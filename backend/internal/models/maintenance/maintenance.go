@@ -0,0 +1,40 @@
+// Package maintenance holds the models for maintenance windows: periods
+// during which the API is read-only, for every org or for one.
+package maintenance
+
+import "time"
+
+// Window is a period during which mutations are rejected with 503.
+type Window struct {
+	ID int `json:"id"`
+	// OrgID is the org the window covers; nil covers every org.
+	OrgID    *int      `json:"org_id"`
+	Reason   string    `json:"reason" example:"Database upgrade"`
+	StartsAt time.Time `json:"starts_at"`
+	// EndsAt is when writes reopen; nil until the window is ended.
+	EndsAt    *time.Time `json:"ends_at"`
+	CreatedBy *int       `json:"created_by"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ActiveAt reports whether the window is in force at t.
+func (w Window) ActiveAt(t time.Time) bool {
+	return !t.Before(w.StartsAt) && (w.EndsAt == nil || t.Before(*w.EndsAt))
+}
+
+// Covers reports whether the window applies to orgID. Requests without an
+// org context are covered by global windows only; pass 0 for them.
+func (w Window) Covers(orgID int) bool {
+	return w.OrgID == nil || *w.OrgID == orgID
+}
+
+// CreateWindowRequest is the body of POST /api/v1/admin/maintenance-windows.
+type CreateWindowRequest struct {
+	// OrgID limits the window to one org; omit it for every org.
+	OrgID  *int   `json:"org_id,omitempty" validate:"omitempty,gt=0"`
+	Reason string `json:"reason" validate:"required,min=1,max=500,no_control_chars" example:"Database upgrade"`
+	// StartsAt defaults to now.
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	// EndsAt is optional: without it the window lasts until it is ended.
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+}
@@ -57,6 +57,46 @@ type Entry struct {
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
+// Accounted reports whether the entry's person is accounted for: seen at a
+// muster point, verified, or marked safe. Only missing people are not.
+func (e Entry) Accounted() bool {
+	return e.Status != "missing"
+}
+
+// RollCall is an event's entries split into the people accounted for and
+// those still missing, each list in entry order. While the event is active
+// it is live: entries move across as people reach muster points.
+type RollCall struct {
+	EventID     int        `json:"event_id"`
+	Status      string     `json:"status"`
+	StartedAt   time.Time  `json:"started_at"`
+	EndedAt     *time.Time `json:"ended_at,omitempty"`
+	Counts      Counts     `json:"counts"`
+	Accounted   []Entry    `json:"accounted"`
+	Unaccounted []Entry    `json:"unaccounted"`
+}
+
+// NewRollCall builds ev's roll call from its entries.
+func NewRollCall(ev Event) RollCall {
+	rc := RollCall{
+		EventID:     ev.ID,
+		Status:      ev.Status,
+		StartedAt:   ev.StartedAt,
+		EndedAt:     ev.EndedAt,
+		Counts:      ev.Counts,
+		Accounted:   []Entry{},
+		Unaccounted: []Entry{},
+	}
+	for _, en := range ev.Entries {
+		if en.Accounted() {
+			rc.Accounted = append(rc.Accounted, en)
+		} else {
+			rc.Unaccounted = append(rc.Unaccounted, en)
+		}
+	}
+	return rc
+}
+
 // Counts aggregates entry statuses for an event.
 type Counts struct {
 	Expected   int `json:"expected"`
@@ -0,0 +1,32 @@
+package muster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRollCall_SplitsByAccounted(t *testing.T) {
+	ev := Event{ID: 7, Status: "active", Entries: []Entry{
+		{ID: 1, Status: "missing"},
+		{ID: 2, Status: "at_muster"},
+		{ID: 3, Status: "verified"},
+		{ID: 4, Status: "safe_manual"},
+		{ID: 5, Status: "missing"},
+	}}
+	rc := NewRollCall(ev)
+	require.Equal(t, 7, rc.EventID)
+	ids := func(es []Entry) []int {
+		out := []int{}
+		for _, e := range es {
+			out = append(out, e.ID)
+		}
+		return out
+	}
+	require.Equal(t, []int{2, 3, 4}, ids(rc.Accounted))
+	require.Equal(t, []int{1, 5}, ids(rc.Unaccounted))
+
+	empty := NewRollCall(Event{ID: 8})
+	require.NotNil(t, empty.Accounted, "lists serialize as [] not null")
+	require.NotNil(t, empty.Unaccounted)
+}
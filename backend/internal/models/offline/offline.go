@@ -0,0 +1,208 @@
+// Package offline holds the offline sync models used by the handheld app:
+// the change feed it pulls to refresh its local copy of assets, locations and
+// their tags, and the queued mutations it pushes when it regains signal.
+package offline
+
+import (
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/models/team"
+)
+
+// Change feed page sizes.
+const (
+	DefaultChangesLimit = 500
+	MaxChangesLimit     = 1000
+)
+
+// MaxMutationsPerRequest bounds one POST /api/v1/sync/mutations body; a device
+// with a longer queue uploads it in order across several requests.
+const MaxMutationsPerRequest = 200
+
+// ReplayWindow is how long a mutation's outcome is kept for replay. A device
+// retrying a mutation older than this may apply it again.
+const ReplayWindow = 30 * 24 * time.Hour
+
+// Synced entity kinds.
+const (
+	EntityAsset    = "asset"
+	EntityLocation = "location"
+	EntityTag      = "tag"
+)
+
+// Mutation operations.
+const (
+	OpCreate    = "create"
+	OpUpdate    = "update"
+	OpDelete    = "delete"
+	OpAddTag    = "add_tag"
+	OpRemoveTag = "remove_tag"
+)
+
+// Conflict policies. Under server_wins an update or delete based on a stale
+// copy is not applied and is reported as a conflict; under client_wins it is
+// applied over the server's newer edit.
+const (
+	PolicyServerWins = "server_wins"
+	PolicyClientWins = "client_wins"
+)
+
+// Mutation outcomes.
+const (
+	StatusApplied  = "applied"
+	StatusConflict = "conflict"
+	StatusRejected = "rejected"
+)
+
+// Reasons a mutation was not applied.
+const (
+	ReasonStale    = "stale"
+	ReasonDeleted  = "deleted"
+	ReasonExists   = "exists"
+	ReasonNotFound = "not_found"
+	ReasonInvalid  = "invalid"
+)
+
+// Cursor is a position in the change feed: changes strictly after (At, ID)
+// are new. IDs are unique across assets, locations and tags, so one cursor
+// orders all three.
+type Cursor struct {
+	At time.Time
+	ID int
+}
+
+// Scope is the caller's visibility. Rows outside it are reported as
+// tombstones so a device drops anything it can no longer see.
+type Scope struct {
+	Team      team.Scope
+	Locations location.SubtreeScope
+}
+
+// ChangesFilter selects one page of the change feed. A nil After starts a
+// full sync, which lists only live rows.
+type ChangesFilter struct {
+	After *Cursor
+	Limit int
+	Scope Scope
+}
+
+// Asset is an asset as the device stores it.
+type Asset struct {
+	ID          int        `json:"id"`
+	ExternalKey string     `json:"external_key"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	IsActive    bool       `json:"is_active"`
+	Metadata    any        `json:"metadata"`
+	ValidFrom   time.Time  `json:"valid_from"`
+	ValidTo     *time.Time `json:"valid_to"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Location is a location as the device stores it.
+type Location struct {
+	ID          int        `json:"id"`
+	ExternalKey string     `json:"external_key"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	ParentID    *int       `json:"parent_id"`
+	IsActive    bool       `json:"is_active"`
+	ValidFrom   time.Time  `json:"valid_from"`
+	ValidTo     *time.Time `json:"valid_to"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// Tag is an RFID / BLE / barcode tag attached to an asset or a location.
+type Tag struct {
+	ID         int       `json:"id"`
+	TagType    string    `json:"tag_type"`
+	Value      string    `json:"value"`
+	AssetID    *int      `json:"asset_id"`
+	LocationID *int      `json:"location_id"`
+	IsActive   bool      `json:"is_active"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Tombstone tells the device to drop an entity: it was deleted, or it moved
+// out of the caller's scope. A tombstoned asset or location implies its tags.
+type Tombstone struct {
+	Entity    string    `json:"entity" example:"asset"`
+	ID        int       `json:"id"`
+	RemovedAt time.Time `json:"removed_at"`
+}
+
+// Changes is one page of the change feed. Timestamps keep full precision:
+// updated_at is echoed back as a mutation's base_updated_at.
+type Changes struct {
+	Assets     []Asset     `json:"assets"`
+	Locations  []Location  `json:"locations"`
+	Tags       []Tag       `json:"tags"`
+	Tombstones []Tombstone `json:"tombstones"`
+	// NextCursor is passed as ?since= on the next pull. On an empty page it
+	// repeats the cursor the page was read from.
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+	// Last is the position of the page's final change; nil on an empty page.
+	Last *Cursor `json:"-"`
+}
+
+// MutationFields are the editable fields of a create or update. Metadata
+// applies to assets and ParentExternalKey to locations.
+type MutationFields struct {
+	Name              *string         `json:"name,omitempty" validate:"omitempty,min=1,max=255,display_name" example:"Forklift 3"`
+	Description       *string         `json:"description,omitempty" validate:"omitempty,min=1,max=1024,no_control_chars"`
+	IsActive          *bool           `json:"is_active,omitempty"`
+	Metadata          *map[string]any `json:"metadata,omitempty"`
+	ParentExternalKey *string         `json:"parent_external_key,omitempty" validate:"omitempty,min=1,max=255,external_key_pattern" example:"wh1"`
+}
+
+// Mutation is one queued offline edit. The target is named by id or, for
+// entities the device created offline and has no id for yet, by external key.
+// Creates always name their external key: it is what later mutations in the
+// queue refer to.
+type Mutation struct {
+	ClientMutationID string `json:"client_mutation_id" validate:"required,min=1,max=128,no_control_chars" example:"3f9c2a1e-7d44-4e0b-9a51-2f1c8e6d0b7a"`
+	Entity           string `json:"entity" validate:"required,oneof=asset location" example:"asset"`
+	Op               string `json:"op" validate:"required,oneof=create update delete add_tag remove_tag" example:"update"`
+	ID               *int   `json:"id,omitempty" validate:"omitempty,gt=0"`
+	ExternalKey      string `json:"external_key,omitempty" validate:"omitempty,min=1,max=255,external_key_pattern" example:"forklift-3"`
+	// BaseUpdatedAt is the updated_at of the copy the edit was made against.
+	// Omit it only for entities created offline earlier in the queue.
+	BaseUpdatedAt *time.Time         `json:"base_updated_at,omitempty"`
+	Fields        *MutationFields    `json:"fields,omitempty"`
+	Tag           *shared.TagRequest `json:"tag,omitempty"`
+}
+
+// MutationsRequest is the body of POST /api/v1/sync/mutations. Mutations are
+// applied in order; each one commits on its own.
+type MutationsRequest struct {
+	ConflictPolicy string     `json:"conflict_policy,omitempty" validate:"omitempty,oneof=server_wins client_wins" example:"server_wins"`
+	Mutations      []Mutation `json:"mutations" validate:"required,min=1,max=200,dive"`
+}
+
+// MutationResult is the outcome of one mutation. A conflict carries the
+// server's version so the device can decide whether to re-apply the edit on
+// top of it after pulling the change feed.
+type MutationResult struct {
+	ClientMutationID string     `json:"client_mutation_id"`
+	Status           string     `json:"status" example:"applied"`
+	Reason           string     `json:"reason,omitempty" example:"stale"`
+	Message          string     `json:"message,omitempty"`
+	Entity           string     `json:"entity,omitempty"`
+	ID               *int       `json:"id,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+	ServerUpdatedAt  *time.Time `json:"server_updated_at,omitempty"`
+	ServerDeletedAt  *time.Time `json:"server_deleted_at,omitempty"`
+	// Replayed is set when the outcome was recorded by an earlier attempt.
+	Replayed bool `json:"replayed,omitempty"`
+}
+
+// Target is the current server state of a mutation's target.
+type Target struct {
+	ID          int
+	ExternalKey string
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+}
@@ -0,0 +1,22 @@
+package organization
+
+// AppTheme is how the web app looks when it is loaded on one of the org's
+// white-label entry points (a link domain, <identifier>.<app host>, or
+// /o/<identifier>), stored under organizations.metadata.app_theme. Only orgs
+// with a theme are resolved on those entry points; empty fields keep
+// TrakRF's defaults.
+type AppTheme struct {
+	// LogoURL is an https image shown in place of the TrakRF logo.
+	LogoURL string `json:"logo_url,omitempty" example:"https://acme.example/logo.png"`
+	// PrimaryColor (#rrggbb) replaces the brand color.
+	PrimaryColor string `json:"primary_color,omitempty" example:"#0f766e"`
+	// AccentColor (#rrggbb) replaces the secondary highlight color.
+	AccentColor string `json:"accent_color,omitempty" example:"#f59e0b"`
+}
+
+// BrandedApp is an org's theme as resolved for an SPA load, with the org
+// name the page is titled by.
+type BrandedApp struct {
+	OrgName string
+	Theme   AppTheme
+}
@@ -0,0 +1,28 @@
+package organization
+
+// Defaults for an org that never set its currency settings.
+const (
+	DefaultCurrency = "USD"
+	DefaultLocale   = "en-US"
+)
+
+// CurrencySettings is the org-wide money configuration, stored under
+// organizations.metadata.currency.
+type CurrencySettings struct {
+	// Currency is the ISO 4217 code financial fields default to when a
+	// write does not name one.
+	Currency string `json:"currency" example:"USD"`
+	// Locale is the BCP 47 tag reports format amounts with.
+	Locale string `json:"locale" example:"en-US"`
+}
+
+// WithDefaults is s with unset fields defaulted.
+func (s CurrencySettings) WithDefaults() CurrencySettings {
+	if s.Currency == "" {
+		s.Currency = DefaultCurrency
+	}
+	if s.Locale == "" {
+		s.Locale = DefaultLocale
+	}
+	return s
+}
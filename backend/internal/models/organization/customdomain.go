@@ -0,0 +1,36 @@
+package organization
+
+import "time"
+
+// CustomDomainRecordPrefix is prepended to a custom domain's hostname to name
+// the DNS TXT record that proves the org controls it.
+const CustomDomainRecordPrefix = "_trakrf-verify."
+
+// CustomDomainValuePrefix is prepended to the verification token to form the
+// TXT record's value.
+const CustomDomainValuePrefix = "trakrf-verify="
+
+// CustomDomain is a hostname the org serves the app from. It resolves to the
+// org only once verified, by publishing VerificationValue in a TXT record
+// named VerificationRecord.
+type CustomDomain struct {
+	ID                 int        `json:"id"`
+	Hostname           string     `json:"hostname" example:"track.acme.example"`
+	VerificationRecord string     `json:"verification_record" example:"_trakrf-verify.track.acme.example"`
+	VerificationValue  string     `json:"verification_value" example:"trakrf-verify=5d41402abc4b2a76b9719d911017c592"`
+	VerifiedAt         *time.Time `json:"verified_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// CreateCustomDomainRequest is the body of POST /api/v1/orgs/{id}/custom-domains.
+type CreateCustomDomainRequest struct {
+	Hostname string `json:"hostname" validate:"required,max=253" example:"track.acme.example"`
+}
+
+// HostOrg is the org a request's Host header resolved to through a verified
+// custom domain.
+type HostOrg struct {
+	OrgID      int
+	Identifier string
+	Hostname   string
+}
@@ -0,0 +1,14 @@
+package organization
+
+// EmailBranding is how the org's transactional emails (invitations) look and
+// where replies go, stored under organizations.metadata.email_branding.
+// Empty fields keep TrakRF's defaults.
+type EmailBranding struct {
+	// LogoURL is an https image shown in place of the TrakRF wordmark.
+	LogoURL string `json:"logo_url,omitempty" example:"https://acme.example/logo.png"`
+	// PrimaryColor (#rrggbb) tints the header rule and buttons.
+	PrimaryColor string `json:"primary_color,omitempty" example:"#0f766e"`
+	// ReplyTo receives replies to the org's emails instead of the no-reply
+	// sender.
+	ReplyTo string `json:"reply_to,omitempty" example:"ops@acme.example"`
+}
@@ -0,0 +1,41 @@
+package organization
+
+import "time"
+
+// Export archive formats.
+const (
+	ExportFormatJSON = "json"
+	ExportFormatSQL  = "sql"
+)
+
+// Export statuses. pending → running → completed | failed.
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// CreateExportRequest is the body of POST /api/v1/orgs/{id}/export. An empty
+// format means json.
+type CreateExportRequest struct {
+	Format string `json:"format,omitempty" validate:"omitempty,oneof=json sql" example:"json"`
+}
+
+// Export is one org data export. DownloadURL is set on a completed export
+// when it is read, and is valid for a few minutes.
+type Export struct {
+	ID          int        `json:"id"`
+	OrgID       int        `json:"org_id"`
+	Format      string     `json:"format" example:"json"`
+	Status      string     `json:"status" example:"completed"`
+	RequestedBy *int       `json:"requested_by,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	SizeBytes   *int64     `json:"size_bytes,omitempty"`
+	RowCount    *int64     `json:"row_count,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	DownloadURL *string    `json:"download_url,omitempty"`
+}
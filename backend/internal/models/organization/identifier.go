@@ -0,0 +1,51 @@
+package organization
+
+import "fmt"
+
+// IdentifierTemplate is how POST /api/v1/assets?auto_identifier=true names
+// a new asset: Prefix followed by the sequence value zero-padded to Padding
+// digits (growing past it), e.g. "ASSET-0042".
+type IdentifierTemplate struct {
+	Prefix string `json:"prefix" example:"ASSET-"`
+	// Padding is the minimum digit count, 1 to 12.
+	Padding int `json:"padding" example:"4"`
+	// NextValue is the sequence value the next auto-assigned identifier
+	// uses; values whose identifier is already taken are skipped.
+	NextValue int64 `json:"next_value" example:"42"`
+}
+
+// Format is the identifier for sequence value n.
+func (t IdentifierTemplate) Format(n int64) string {
+	return fmt.Sprintf("%s%0*d", t.Prefix, t.Padding, n)
+}
+
+// Deleted-key policies: what creating an asset or location does when its
+// external_key belongs to a soft-deleted one of the org.
+const (
+	// DeletedKeyAllowReuse creates a new record alongside the deleted one.
+	// It is the default.
+	DeletedKeyAllowReuse = "allow_reuse"
+	// DeletedKeyBlock rejects the create as a conflict, so a key is never
+	// reused for a different thing.
+	DeletedKeyBlock = "block"
+	// DeletedKeyAutoRestore restores the most recently deleted record with
+	// the key, overwritten with the create's fields, instead of creating one.
+	DeletedKeyAutoRestore = "auto_restore"
+)
+
+// IdentifierSettings is the org-wide external_key configuration, stored
+// under organizations.metadata.identifiers.
+type IdentifierSettings struct {
+	// DeletedKeyPolicy is allow_reuse, block or auto_restore. It applies to
+	// external_keys sent by the caller; minted ones never match a deleted
+	// record on purpose.
+	DeletedKeyPolicy string `json:"deleted_key_policy" example:"allow_reuse"`
+}
+
+// Policy is DeletedKeyPolicy with the default applied.
+func (s IdentifierSettings) Policy() string {
+	if s.DeletedKeyPolicy == "" {
+		return DeletedKeyAllowReuse
+	}
+	return s.DeletedKeyPolicy
+}
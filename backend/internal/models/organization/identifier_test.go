@@ -0,0 +1,20 @@
+package organization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifierTemplate_Format(t *testing.T) {
+	assert.Equal(t, "ASSET-0042", IdentifierTemplate{Prefix: "ASSET-", Padding: 4}.Format(42))
+	assert.Equal(t, "ASSET-12345", IdentifierTemplate{Prefix: "ASSET-", Padding: 4}.Format(12345))
+	assert.Equal(t, "7", IdentifierTemplate{Padding: 1}.Format(7))
+	assert.Equal(t, "WH-A-000001", IdentifierTemplate{Prefix: "WH-A-", Padding: 6}.Format(1))
+}
+
+func TestIdentifierSettings_Policy(t *testing.T) {
+	assert.Equal(t, DeletedKeyAllowReuse, IdentifierSettings{}.Policy())
+	assert.Equal(t, DeletedKeyBlock, IdentifierSettings{DeletedKeyPolicy: DeletedKeyBlock}.Policy())
+	assert.Equal(t, DeletedKeyAutoRestore, IdentifierSettings{DeletedKeyPolicy: DeletedKeyAutoRestore}.Policy())
+}
@@ -0,0 +1,76 @@
+package organization
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ImportLocation is a location row read from an export archive. ID and
+// ParentLocationID are the source org's ids; an import remaps them.
+type ImportLocation struct {
+	ID               int             `json:"id"`
+	ExternalKey      string          `json:"external_key"`
+	Name             string          `json:"name"`
+	Description      *string         `json:"description"`
+	ParentLocationID *int            `json:"parent_location_id"`
+	ValidFrom        time.Time       `json:"valid_from"`
+	ValidTo          *time.Time      `json:"valid_to"`
+	IsActive         bool            `json:"is_active"`
+	Metadata         json.RawMessage `json:"metadata"`
+	DeletedAt        *time.Time      `json:"deleted_at"`
+}
+
+// ImportAsset is an asset row read from an export archive. Owner and team are
+// not carried over: users and teams do not exist in the target org.
+type ImportAsset struct {
+	ID          int             `json:"id"`
+	ExternalKey string          `json:"external_key"`
+	Name        string          `json:"name"`
+	Description *string         `json:"description"`
+	ValidFrom   time.Time       `json:"valid_from"`
+	ValidTo     *time.Time      `json:"valid_to"`
+	IsActive    bool            `json:"is_active"`
+	CostCenter  *string         `json:"cost_center"`
+	Metadata    json.RawMessage `json:"metadata"`
+	DeletedAt   *time.Time      `json:"deleted_at"`
+}
+
+// ImportTag is a tag (identifier) row read from an export archive, attached
+// to exactly one of AssetID or LocationID in the source org.
+type ImportTag struct {
+	Type       string          `json:"type"`
+	Value      string          `json:"value"`
+	AssetID    *int            `json:"asset_id"`
+	LocationID *int            `json:"location_id"`
+	ValidFrom  time.Time       `json:"valid_from"`
+	ValidTo    *time.Time      `json:"valid_to"`
+	IsActive   bool            `json:"is_active"`
+	Metadata   json.RawMessage `json:"metadata"`
+	DeletedAt  *time.Time      `json:"deleted_at"`
+}
+
+// ImportData is what an import applies: the live locations, parents before
+// children, assets and tags of an export archive.
+type ImportData struct {
+	SourceOrgID int
+	Locations   []ImportLocation
+	Assets      []ImportAsset
+	Tags        []ImportTag
+}
+
+// ImportCounts is how many rows of one kind an import created and updated.
+// Skipped rows are tags whose asset or location is not in the archive.
+type ImportCounts struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Skipped int `json:"skipped,omitempty"`
+}
+
+// ImportResult is the response of POST /api/v1/orgs/{id}/import.
+type ImportResult struct {
+	SourceOrgID int          `json:"source_org_id"`
+	DryRun      bool         `json:"dry_run"`
+	Locations   ImportCounts `json:"locations"`
+	Assets      ImportCounts `json:"assets"`
+	Tags        ImportCounts `json:"tags"`
+}
@@ -0,0 +1,9 @@
+package organization
+
+// LinkDomains are extra frontend origins the org's emailed links (invitation,
+// password reset, email change) may point at, such as a white-label domain,
+// stored under organizations.metadata.link_domains. The environment's own
+// origins are always allowed and need not be listed.
+type LinkDomains struct {
+	Origins []string `json:"origins" example:"https://rfid.acme.example"`
+}
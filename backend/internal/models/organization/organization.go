@@ -22,6 +22,9 @@ type Organization struct {
 	// in the schema are not surfaced here until TRA-135/TRA-198 need them.
 	SubscriptionEnabled   bool       `json:"subscription_enabled"`
 	SubscriptionExpiresAt *time.Time `json:"subscription_expires_at,omitempty"`
+	// IsSandbox marks a test org cloned from SandboxOf. Fixed at creation.
+	IsSandbox bool `json:"is_sandbox"`
+	SandboxOf *int `json:"sandbox_of,omitempty"`
 }
 
 // CreateOrganizationRequest for POST /api/v1/orgs
@@ -31,7 +34,8 @@ type CreateOrganizationRequest struct {
 
 // AdminOrgListItem is a row in the superadmin all-orgs list (TRA-949). It
 // surfaces just enough for an operator to scan entitlement state across every
-// org and drill into one: name, the raw entitlement fields, and a member count.
+// org and drill into one: name, the raw entitlement fields, a member count, and
+// the partner that owns it.
 type AdminOrgListItem struct {
 	ID                    int        `json:"id"`
 	Name                  string     `json:"name"`
@@ -39,6 +43,7 @@ type AdminOrgListItem struct {
 	SubscriptionEnabled   bool       `json:"subscription_enabled"`
 	SubscriptionExpiresAt *time.Time `json:"subscription_expires_at,omitempty"`
 	MemberCount           int        `json:"member_count"`
+	PartnerID             *int       `json:"partner_id,omitempty"`
 }
 
 // UpdateEntitlementRequest is the superadmin entitlement edit payload (TRA-949).
@@ -112,8 +117,9 @@ type DeleteOrganizationRequest struct {
 
 // UserOrg represents an org in the user's org list (minimal)
 type UserOrg struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	IsSandbox bool   `json:"is_sandbox"`
 }
 
 // UserOrgWithRole represents the current org with role context
@@ -129,6 +135,8 @@ type UserOrgWithRole struct {
 	IsEntitled            bool       `json:"is_entitled"`
 	SubscriptionEnabled   bool       `json:"subscription_enabled"`
 	SubscriptionExpiresAt *time.Time `json:"subscription_expires_at,omitempty"`
+	// IsSandbox tells the UI to show the sandbox banner.
+	IsSandbox bool `json:"is_sandbox"`
 }
 
 // SetCurrentOrgRequest for POST /users/me/current-org
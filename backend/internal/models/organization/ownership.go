@@ -0,0 +1,60 @@
+package organization
+
+import "time"
+
+// Ownership transfer statuses. pending → accepted is the happy path; the
+// recipient can decline, the current owner can cancel, and a transfer not
+// answered by expires_at is marked expired.
+const (
+	TransferStatusPending   = "pending"
+	TransferStatusAccepted  = "accepted"
+	TransferStatusDeclined  = "declined"
+	TransferStatusCancelled = "cancelled"
+	TransferStatusExpired   = "expired"
+)
+
+// Actions on a pending transfer.
+const (
+	TransferActionAccept  = "accept"
+	TransferActionDecline = "decline"
+	TransferActionCancel  = "cancel"
+)
+
+// TransferTTL is how long the recipient has to confirm a transfer.
+const TransferTTL = 7 * 24 * time.Hour
+
+// DefaultPreviousOwnerRole is the role the previous owner is demoted to when
+// the transfer request does not name one.
+const DefaultPreviousOwnerRole = "manager"
+
+// OwnershipTransfer is one proposed hand-over of an org from its owner to
+// another member. Rows are kept after they are decided, as the record of who
+// owned the org when.
+type OwnershipTransfer struct {
+	ID                int        `json:"id"`
+	OrgID             int        `json:"org_id"`
+	FromUserID        *int       `json:"from_user_id,omitempty"`
+	ToUserID          *int       `json:"to_user_id,omitempty"`
+	PreviousOwnerRole string     `json:"previous_owner_role" example:"manager"`
+	Status            string     `json:"status" example:"pending"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	DecidedBy         *int       `json:"decided_by,omitempty"`
+	DecidedAt         *time.Time `json:"decided_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// TransferOwnershipRequest is the body of
+// POST /api/v1/orgs/{id}/transfer-ownership. PreviousOwnerRole is the role
+// the current owner keeps once the recipient accepts; the owner cannot stay
+// an admin, so control really changes hands.
+type TransferOwnershipRequest struct {
+	UserID            int    `json:"user_id" validate:"required,gt=0" example:"42"`
+	PreviousOwnerRole string `json:"previous_owner_role,omitempty" validate:"omitempty,oneof=viewer operator manager" example:"manager"`
+}
+
+// Ownership is the response of GET /api/v1/orgs/{id}/ownership.
+type Ownership struct {
+	OwnerUserID     *int               `json:"owner_user_id,omitempty" example:"7"`
+	PendingTransfer *OwnershipTransfer `json:"pending_transfer,omitempty"`
+}
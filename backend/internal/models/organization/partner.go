@@ -0,0 +1,100 @@
+package organization
+
+import "time"
+
+// Partner is a billing entity that owns one or more orgs: a reseller, or a
+// customer billed once for several orgs. Orgs point at their partner through
+// organizations.partner_id; an org with none is billed on its own.
+type Partner struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name" example:"Acme Holdings"`
+	Identifier   *string   `json:"identifier,omitempty" example:"acme-holdings"`
+	BillingEmail *string   `json:"billing_email,omitempty" example:"billing@acme.example"`
+	IsActive     bool      `json:"is_active"`
+	OrgCount     int       `json:"org_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CreatePartnerRequest is the body of POST /api/v1/admin/partners.
+type CreatePartnerRequest struct {
+	Name         string  `json:"name" validate:"required,min=1,max=255" example:"Acme Holdings"`
+	Identifier   *string `json:"identifier,omitempty" validate:"omitempty,min=1,max=255" example:"acme-holdings"`
+	BillingEmail *string `json:"billing_email,omitempty" validate:"omitempty,email,max=255" example:"billing@acme.example"`
+}
+
+// SetOrgPartnerRequest is the body of PUT /api/v1/orgs/{id}/partner. A null
+// or omitted partner_id detaches the org from its partner.
+type SetOrgPartnerRequest struct {
+	PartnerID *int `json:"partner_id" validate:"omitempty,gt=0" example:"12"`
+}
+
+// PartnerAdmin is a user who administers a partner and can read roll-up
+// reports across its orgs.
+type PartnerAdmin struct {
+	PartnerID int       `json:"partner_id"`
+	UserID    int       `json:"user_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddPartnerAdminRequest is the body of
+// POST /api/v1/admin/partners/{partnerId}/admins.
+type AddPartnerAdminRequest struct {
+	UserID int `json:"user_id" validate:"required,gt=0" example:"42"`
+}
+
+// Roll-up report window bounds, in days.
+const (
+	DefaultRollupDays = 30
+	MaxRollupDays     = 365
+)
+
+// RollupCounts are the figures a roll-up report gives for one org, or summed
+// across a partner's orgs. Utilization is the share of active assets seen by
+// a reader within the report window, from 0 to 1.
+type RollupCounts struct {
+	Assets           int     `json:"assets"`
+	ActiveAssets     int     `json:"active_assets"`
+	AssetsSeen       int     `json:"assets_seen"`
+	Utilization      float64 `json:"utilization" example:"0.82"`
+	OpenSensorAlerts int     `json:"open_sensor_alerts"`
+	OpenStockAlerts  int     `json:"open_stock_alerts"`
+	OpenAlerts       int     `json:"open_alerts"`
+}
+
+// Add sums o into c and recomputes the derived fields.
+func (c *RollupCounts) Add(o RollupCounts) {
+	c.Assets += o.Assets
+	c.ActiveAssets += o.ActiveAssets
+	c.AssetsSeen += o.AssetsSeen
+	c.OpenSensorAlerts += o.OpenSensorAlerts
+	c.OpenStockAlerts += o.OpenStockAlerts
+	c.Finish()
+}
+
+// Finish computes OpenAlerts and Utilization from the raw counts.
+func (c *RollupCounts) Finish() {
+	c.OpenAlerts = c.OpenSensorAlerts + c.OpenStockAlerts
+	c.Utilization = 0
+	if c.ActiveAssets > 0 {
+		c.Utilization = float64(c.AssetsSeen) / float64(c.ActiveAssets)
+	}
+}
+
+// OrgRollup is one org's line in a partner roll-up.
+type OrgRollup struct {
+	OrgID int    `json:"org_id"`
+	Name  string `json:"name"`
+	RollupCounts
+}
+
+// PartnerRollup is the response of GET /api/v1/partners/{partnerId}/rollup:
+// totals across the partner's live orgs, and the per-org breakdown.
+type PartnerRollup struct {
+	PartnerID int          `json:"partner_id"`
+	Since     time.Time    `json:"since"`
+	Totals    RollupCounts `json:"totals"`
+	Orgs      []OrgRollup  `json:"orgs"`
+}
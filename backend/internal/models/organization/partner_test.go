@@ -0,0 +1,27 @@
+package organization
+
+import "testing"
+
+func TestRollupCounts_Add(t *testing.T) {
+	var total RollupCounts
+	total.Add(RollupCounts{Assets: 10, ActiveAssets: 8, AssetsSeen: 6, OpenSensorAlerts: 1})
+	total.Add(RollupCounts{Assets: 3, ActiveAssets: 2, AssetsSeen: 0, OpenStockAlerts: 2})
+
+	if total.Assets != 13 || total.ActiveAssets != 10 || total.AssetsSeen != 6 {
+		t.Fatalf("counts = %+v", total)
+	}
+	if total.OpenAlerts != 3 {
+		t.Errorf("open alerts = %d, want 3", total.OpenAlerts)
+	}
+	if total.Utilization != 0.6 {
+		t.Errorf("utilization = %v, want 0.6", total.Utilization)
+	}
+}
+
+func TestRollupCounts_FinishWithoutActiveAssets(t *testing.T) {
+	c := RollupCounts{Assets: 4}
+	c.Finish()
+	if c.Utilization != 0 {
+		t.Errorf("utilization = %v, want 0", c.Utilization)
+	}
+}
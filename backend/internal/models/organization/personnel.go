@@ -0,0 +1,14 @@
+package organization
+
+// PersonnelSettings is how the org treats person-assets, the assets flagged
+// metadata.person = true (badged staff tracked for mustering), stored under
+// organizations.metadata.personnel. How long their scans are kept is a
+// retention setting (RetentionSettings.PersonnelScanDays).
+type PersonnelSettings struct {
+	// AnonymizeReports shows person-assets in location reports and kiosk
+	// dashboards under a pseudonym instead of their name and external_key,
+	// and keeps reports from being searched for them by name or key.
+	// Mustering roll calls still name people: they exist to account for
+	// each one.
+	AnonymizeReports bool `json:"anonymize_reports"`
+}
@@ -0,0 +1,31 @@
+package organization
+
+// PositioningSettings is the org's BLE zone-estimation configuration, stored
+// under organizations.metadata.positioning. A nil tuning field means "use the
+// system default" (positioning.DefaultConfig).
+//
+// The gateway-to-location mapping is the gateway's scan point: its
+// location_id names the zone and an optional numeric metadata.rssi_offset
+// (dB) calibrates a gateway that hears louder or quieter than its peers.
+type PositioningSettings struct {
+	// Enabled routes BLE gateway reads through the positioning engine: they
+	// no longer write one asset_scans row per advertisement, and the engine
+	// writes a row only when an asset's estimated zone changes (plus a
+	// periodic heartbeat while it stays put).
+	Enabled bool `json:"enabled"`
+	// SmoothingFactor is the weight (0 < f <= 1) of the newest RSSI sample in
+	// each gateway's exponential moving average; lower is smoother.
+	SmoothingFactor *float64 `json:"smoothing_factor,omitempty"`
+	// HysteresisDB is how many dB stronger another zone must be before the
+	// asset leaves its current zone.
+	HysteresisDB *int `json:"hysteresis_db,omitempty"`
+	// MinDwellSeconds is how long a new zone must stay the strongest before
+	// the change is written.
+	MinDwellSeconds *int `json:"min_dwell_seconds,omitempty"`
+	// StaleSeconds drops a gateway from the estimate once it has not heard
+	// the beacon for this long.
+	StaleSeconds *int `json:"stale_seconds,omitempty"`
+	// HeartbeatSeconds re-writes the current zone this often while the asset
+	// stays put, so last-seen times keep advancing.
+	HeartbeatSeconds *int `json:"heartbeat_seconds,omitempty"`
+}
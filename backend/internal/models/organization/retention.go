@@ -0,0 +1,57 @@
+package organization
+
+// System retention windows, in days, for orgs whose plan does not set one
+// (including every org without an active subscription). They match the
+// windows in force before per-org retention existed.
+const (
+	DefaultAuditRetentionDays = 90
+	DefaultScanRetentionDays  = 365
+)
+
+// RetentionSettings is the org's own retention choice, stored under
+// organizations.metadata.retention. A nil field means "use the plan window".
+// Settings may shorten a window below the plan's but never lengthen it.
+type RetentionSettings struct {
+	AuditDays *int `json:"audit_days,omitempty"`
+	ScanDays  *int `json:"scan_days,omitempty"`
+	// PersonnelScanDays shortens scan history for person-assets (badged
+	// staff) below ScanDays; nil keeps them with the other scans.
+	PersonnelScanDays *int `json:"personnel_scan_days,omitempty"`
+}
+
+// RetentionPolicy is a set of resolved retention windows in days: audit
+// covers webhook deliveries, scan covers asset scan history, and personnel
+// scan covers the scan history of person-assets, never longer than scan.
+type RetentionPolicy struct {
+	AuditDays         int `json:"audit_days"`
+	ScanDays          int `json:"scan_days"`
+	PersonnelScanDays int `json:"personnel_scan_days"`
+}
+
+// OrgRetention is everything needed to resolve one org's retention: the
+// plan's windows (Limits, already defaulted) and the org's settings.
+type OrgRetention struct {
+	OrgID    int
+	Limits   RetentionPolicy
+	Settings RetentionSettings
+}
+
+// Effective returns the windows the janitor enforces: each setting capped at
+// its plan limit, or the limit itself when unset. A setting above the limit
+// (left over from a plan downgrade) is clamped rather than honored. The
+// personnel window is capped at the effective scan window.
+func (o OrgRetention) Effective() RetentionPolicy {
+	scan := capDays(o.Settings.ScanDays, o.Limits.ScanDays)
+	return RetentionPolicy{
+		AuditDays:         capDays(o.Settings.AuditDays, o.Limits.AuditDays),
+		ScanDays:          scan,
+		PersonnelScanDays: capDays(o.Settings.PersonnelScanDays, scan),
+	}
+}
+
+func capDays(setting *int, limit int) int {
+	if setting == nil || *setting > limit {
+		return limit
+	}
+	return *setting
+}
@@ -0,0 +1,27 @@
+package organization
+
+import "testing"
+
+func intp(v int) *int { return &v }
+
+func TestOrgRetention_Effective(t *testing.T) {
+	limits := RetentionPolicy{AuditDays: 90, ScanDays: 365, PersonnelScanDays: 365}
+
+	cases := []struct {
+		name     string
+		settings RetentionSettings
+		want     RetentionPolicy
+	}{
+		{"unset uses plan", RetentionSettings{}, limits},
+		{"shorter honored", RetentionSettings{AuditDays: intp(30), ScanDays: intp(7)}, RetentionPolicy{AuditDays: 30, ScanDays: 7, PersonnelScanDays: 7}},
+		{"longer clamped", RetentionSettings{AuditDays: intp(400), ScanDays: intp(730)}, limits},
+		{"personnel shorter", RetentionSettings{PersonnelScanDays: intp(14)}, RetentionPolicy{AuditDays: 90, ScanDays: 365, PersonnelScanDays: 14}},
+		{"personnel capped at scan", RetentionSettings{ScanDays: intp(7), PersonnelScanDays: intp(14)}, RetentionPolicy{AuditDays: 90, ScanDays: 7, PersonnelScanDays: 7}},
+	}
+	for _, tc := range cases {
+		got := OrgRetention{OrgID: 1, Limits: limits, Settings: tc.settings}.Effective()
+		if got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
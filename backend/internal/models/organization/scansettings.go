@@ -0,0 +1,11 @@
+package organization
+
+// ScanSettings is the org-wide scan configuration, stored under
+// organizations.metadata.scans.
+type ScanSettings struct {
+	// SessionScans lets signed-in users submit scans and tag lookups over
+	// their session without a registered reader's credential, as the web
+	// app's handheld scanning does. Off (the default), a session scan must
+	// present a reader credential in the X-Reader-Key header.
+	SessionScans bool `json:"session_scans"`
+}
@@ -0,0 +1,10 @@
+package organization
+
+// TagSettings is the org-wide tag configuration, stored under
+// organizations.metadata.tags.
+type TagSettings struct {
+	// UniqueAcrossTypes rejects attaching a tag value that is already live
+	// under a different tag_type (e.g. the same string as both an RFID EPC
+	// and a barcode). Values are always unique within one type.
+	UniqueAcrossTypes bool `json:"unique_across_types"`
+}
@@ -1,6 +1,10 @@
 package report
 
-import "time"
+import (
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/location"
+)
 
 // AssetHistoryItem represents a single scan in the asset's history
 type AssetHistoryItem struct {
@@ -20,9 +24,12 @@ type AssetHistorySort struct {
 
 // AssetHistoryFilter contains query parameters for filtering
 type AssetHistoryFilter struct {
-	From   *time.Time
-	To     *time.Time
-	Limit  int
-	Offset int
-	Sorts  []AssetHistorySort
+	From *time.Time
+	To   *time.Time
+	// SubtreeScope is the caller's location access policy scope; when
+	// restricted it hides scans at locations outside it.
+	SubtreeScope location.SubtreeScope
+	Limit        int
+	Offset       int
+	Sorts        []AssetHistorySort
 }
@@ -0,0 +1,44 @@
+package report
+
+import "github.com/trakrf/platform/backend/internal/models/shared"
+
+// MoneyValue is an amount in a report: the decimal amount and currency as
+// shared.Money serializes them, plus Formatted for display in the org's
+// locale.
+type MoneyValue struct {
+	Amount    string `json:"amount" example:"1234.50"`
+	Currency  string `json:"currency" example:"EUR"`
+	Formatted string `json:"formatted" example:"€1,234.50"`
+}
+
+// NewMoneyValue is m as a MoneyValue formatted for locale.
+func NewMoneyValue(m shared.Money, locale string) MoneyValue {
+	return MoneyValue{Amount: m.Decimal(), Currency: m.Currency, Formatted: m.Format(locale)}
+}
+
+// AssetValueItem is one asset's financials in GET /api/v1/reports/asset-values.
+type AssetValueItem struct {
+	AssetID          int         `json:"asset_id"`
+	AssetExternalKey string      `json:"asset_external_key"`
+	AssetName        string      `json:"asset_name"`
+	PurchaseCost     *MoneyValue `json:"purchase_cost"`
+	BookValue        *MoneyValue `json:"book_value"`
+}
+
+// AssetValueTotal sums the assets recorded in one currency. Amounts in
+// different currencies are never added together.
+type AssetValueTotal struct {
+	Currency     string     `json:"currency" example:"EUR"`
+	AssetCount   int        `json:"asset_count"`
+	PurchaseCost MoneyValue `json:"purchase_cost"`
+	BookValue    MoneyValue `json:"book_value"`
+}
+
+// AssetValueFilter selects GET /api/v1/reports/asset-values: live assets
+// with financials, optionally in one Currency, formatted for Locale.
+type AssetValueFilter struct {
+	Currency string
+	Locale   string
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,35 @@
+package report
+
+import "time"
+
+// DefaultConditionThreshold is the rating below which an asset is reported
+// when GET /api/v1/reports/condition is called without `below`.
+const DefaultConditionThreshold = 3
+
+// ConditionBelowItem is an asset whose latest condition report rates it
+// below the threshold. Dropped is true when the report before it was at or
+// above the threshold, i.e. this report took the asset below it.
+type ConditionBelowItem struct {
+	AssetID          int       `json:"asset_id"`
+	AssetExternalKey string    `json:"asset_external_key"`
+	AssetName        string    `json:"asset_name"`
+	ReportID         int       `json:"report_id"`
+	Rating           int       `json:"rating" example:"2"`
+	PreviousRating   *int      `json:"previous_rating" example:"4"`
+	Dropped          bool      `json:"dropped"`
+	Notes            *string   `json:"notes,omitempty"`
+	PhotoCount       int       `json:"photo_count"`
+	ReportedBy       *int      `json:"reported_by"`
+	ReportedAt       time.Time `json:"reported_at"`
+}
+
+// ConditionBelowFilter selects GET /api/v1/reports/condition: assets whose
+// latest rating is below Below, optionally only those that dropped below it
+// (DroppedOnly) or were reported since Since.
+type ConditionBelowFilter struct {
+	Below       int
+	DroppedOnly bool
+	Since       *time.Time
+	Limit       int
+	Offset      int
+}
@@ -1,6 +1,10 @@
 package report
 
-import "time"
+import (
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/location"
+)
 
 // CurrentLocationItem represents a single asset's current location (internal projection)
 type CurrentLocationItem struct {
@@ -30,7 +34,10 @@ type CurrentLocationFilter struct {
 	AssetExternalKeys    []string // filter by asset external_key(s)
 	Q                    *string  // substring search (case-insensitive) on asset name, external_key, and active tag values
 	IncludeDeleted       bool     // when true, includes rows for soft-deleted assets (default false)
-	Sorts                []CurrentLocationSort
-	Limit                int
-	Offset               int
+	// SubtreeScope is the caller's location access policy scope; when
+	// restricted it hides assets whose current location is outside it.
+	SubtreeScope location.SubtreeScope
+	Sorts        []CurrentLocationSort
+	Limit        int
+	Offset       int
 }
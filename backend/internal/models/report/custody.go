@@ -0,0 +1,86 @@
+package report
+
+import (
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+)
+
+// Chain-of-custody event kinds. A location event spans a stay: consecutive
+// scans at one location, from At to Until. Check-out and check-in events are
+// the asset's assignments to and from an owner, with the handover signature
+// when one was captured. Disposal and transfer events are the sign-offs of
+// the named users who requested, decided or executed them.
+const (
+	CustodyLocation          = "location"
+	CustodyCheckOut          = "assignment.check_out"
+	CustodyCheckIn           = "assignment.check_in"
+	CustodyTagAssigned       = "tag_assigned"
+	CustodyTagUnassigned     = "tag_unassigned"
+	CustodyDisposalRequested = "disposal.requested"
+	CustodyDisposalDecided   = "disposal.decided"
+	CustodyDisposalExecuted  = "disposal.executed"
+	CustodyTransferRequested = "transfer.requested"
+	CustodyTransferDecided   = "transfer.decided"
+	CustodyTransferExecuted  = "transfer.executed"
+)
+
+// MaxCustodyRangeDays bounds the range of a custody document, and
+// MaxCustodyEvents the events it may hold.
+const (
+	MaxCustodyRangeDays = 366
+	MaxCustodyEvents    = 10000
+)
+
+// CustodyActor is the user who signed off an event.
+type CustodyActor struct {
+	UserID int    `json:"user_id"`
+	Name   string `json:"name,omitempty"`
+	Email  string `json:"email"`
+}
+
+// CustodyEvent is one entry of an asset's chain of custody. Only the fields
+// of its kind are set.
+type CustodyEvent struct {
+	Kind                string            `json:"kind" example:"location"`
+	At                  time.Time         `json:"at"`
+	Until               *time.Time        `json:"until,omitempty"`
+	Scans               int               `json:"scans,omitempty"`
+	LocationID          *int              `json:"location_id,omitempty"`
+	LocationExternalKey *string           `json:"location_external_key,omitempty"`
+	LocationName        *string           `json:"location_name,omitempty"`
+	TagType             *string           `json:"tag_type,omitempty"`
+	TagValue            *string           `json:"tag_value,omitempty"`
+	Status              *string           `json:"status,omitempty" example:"approved"`
+	Actor               *CustodyActor     `json:"actor,omitempty"`
+	Detail              *string           `json:"detail,omitempty"`
+	Signature           *CustodySignature `json:"signature,omitempty"`
+}
+
+// CustodySignature identifies the signature captured at a handover. SHA256
+// is asset.Signature.Digest, so the chained entry pins the exact signature;
+// Payload carries it for rendering and is not part of the JSON document.
+type CustodySignature struct {
+	SignerName string           `json:"signer_name"`
+	Format     string           `json:"format" example:"strokes"`
+	SHA256     string           `json:"sha256"`
+	Payload    *asset.Signature `json:"-"`
+}
+
+// CustodySubject identifies the asset a custody document covers.
+type CustodySubject struct {
+	AssetID     int        `json:"asset_id"`
+	ExternalKey string     `json:"external_key"`
+	Name        string     `json:"name"`
+	DisposedAt  *time.Time `json:"disposed_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// CustodyRecord is what storage gathers for a custody document: the asset,
+// its org and its events in time order.
+type CustodyRecord struct {
+	OrgID   int            `json:"org_id"`
+	OrgName string         `json:"org_name"`
+	Asset   CustodySubject `json:"asset"`
+	Events  []CustodyEvent `json:"events"`
+}
@@ -0,0 +1,45 @@
+package report
+
+import "time"
+
+// GET /api/v1/reports/dock-movements covers the DefaultDockMovementRangeDays
+// before `to` when `from` is omitted, and at most MaxDockMovementRangeDays.
+const (
+	DefaultDockMovementRangeDays = 7
+	MaxDockMovementRangeDays     = 92
+)
+
+// DockMovementItem is one crossing of a dock door: inbound items are
+// receipts, outbound items shipments.
+type DockMovementItem struct {
+	ID               int       `json:"id"`
+	DockDoorID       int       `json:"dock_door_id"`
+	DockDoorName     string    `json:"dock_door_name" example:"Dock 3"`
+	LocationID       int       `json:"location_id"`
+	AssetID          int       `json:"asset_id"`
+	AssetExternalKey string    `json:"asset_external_key"`
+	AssetName        string    `json:"asset_name"`
+	Direction        string    `json:"direction" example:"outbound"`
+	FirstReadAt      time.Time `json:"first_read_at"`
+	CrossedAt        time.Time `json:"crossed_at"`
+}
+
+// DockMovementFilter selects GET /api/v1/reports/dock-movements: crossings
+// in [From, To), only through DockDoorID or doors at LocationID when set,
+// and only in Direction when set. Zero fields match everything.
+type DockMovementFilter struct {
+	From       time.Time
+	To         time.Time
+	DockDoorID int
+	LocationID int
+	Direction  string
+	Limit      int
+	Offset     int
+}
+
+// DockMovementCounts totals a dock movement report's crossings by
+// direction, whatever its Direction filter.
+type DockMovementCounts struct {
+	Inbound  int `json:"inbound" example:"42"`
+	Outbound int `json:"outbound" example:"37"`
+}
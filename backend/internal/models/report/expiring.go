@@ -0,0 +1,23 @@
+package report
+
+import "time"
+
+// ExpiringRecord is an asset, location or tag whose validity window closes
+// soon. ExternalKey and Name are set for assets and locations; TagType and
+// Value for tags.
+type ExpiringRecord struct {
+	EntityType  string    `json:"entity_type" example:"asset"` // "asset", "location" or "tag"
+	EntityID    int       `json:"entity_id"`
+	ExternalKey *string   `json:"external_key,omitempty"`
+	Name        *string   `json:"name,omitempty"`
+	TagType     *string   `json:"tag_type,omitempty"`
+	Value       *string   `json:"value,omitempty"`
+	ValidTo     time.Time `json:"valid_to"`
+}
+
+// ExpiringFilter selects live records whose valid_to falls in (now, Until].
+type ExpiringFilter struct {
+	Until  time.Time
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,36 @@
+package report
+
+import "time"
+
+// ExpiringDocument is a document of the vault whose expiry date has passed
+// or falls soon. DaysLeft is negative once it has expired. The asset fields
+// are set for an asset's document and absent for an org-level one.
+type ExpiringDocument struct {
+	DocumentID       int       `json:"document_id"`
+	Type             string    `json:"type" example:"insurance"`
+	Title            string    `json:"title" example:"Fleet insurance 2026"`
+	Reference        *string   `json:"reference,omitempty" example:"POL-88231"`
+	AssetID          *int      `json:"asset_id,omitempty"`
+	AssetExternalKey *string   `json:"asset_external_key,omitempty"`
+	AssetName        *string   `json:"asset_name,omitempty"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	DaysLeft         int       `json:"days_left" example:"12"`
+	Status           string    `json:"status" example:"expiring"` // "expiring" or "expired"
+	HasFile          bool      `json:"has_file"`
+}
+
+// ExpiringDocumentFilter selects documents expiring at or before Until,
+// already-expired ones included, only of Type when set.
+type ExpiringDocumentFilter struct {
+	Now    time.Time
+	Until  time.Time
+	Type   string
+	Limit  int
+	Offset int
+}
+
+// ExpiringDocumentCounts totals an expiring-documents report by status.
+type ExpiringDocumentCounts struct {
+	Expired  int `json:"expired" example:"2"`
+	Expiring int `json:"expiring" example:"9"`
+}
@@ -0,0 +1,26 @@
+package report
+
+import "time"
+
+// InventorySnapshotItem is where one asset was at a past instant: the
+// location of its latest scan at or before that instant. Location fields are
+// null when the scan had no location or the location was not in effect then.
+type InventorySnapshotItem struct {
+	AssetID             int       `json:"asset_id"`
+	AssetExternalKey    string    `json:"asset_external_key"`
+	AssetName           string    `json:"asset_name"`
+	LocationID          *int      `json:"location_id"`
+	LocationExternalKey *string   `json:"location_external_key"`
+	LocationName        *string   `json:"location_name"`
+	LastSeen            time.Time `json:"last_seen"`
+}
+
+// InventorySnapshotFilter selects GET /api/v1/reports/inventory. Assets and
+// locations are included as they stood at At: in effect then and not yet
+// deleted, even if they have been deleted or expired since.
+type InventorySnapshotFilter struct {
+	At         time.Time
+	LocationID *int
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,99 @@
+package report
+
+import "time"
+
+// Bounds of the `days` window of GET /api/v1/reports/locations/{location_id}/activity.
+const (
+	DefaultActivityDays = 7
+	MaxActivityDays     = 92
+)
+
+// DailyActivity is one local calendar day at a location. AssetMinutes
+// counts the minutes each asset was seen there, summed over assets.
+type DailyActivity struct {
+	Date         string `json:"date" example:"2026-10-15"`
+	AssetsSeen   int    `json:"assets_seen"`
+	AssetMinutes int    `json:"asset_minutes"`
+}
+
+// ShiftActivity is one occurrence of a location shift, reported under the
+// local date it starts on. Utilization is AssetsSeen over the assets seen at
+// the location anywhere in the window, from 0 to 1. An occurrence still
+// running is Ongoing and counted up to now.
+type ShiftActivity struct {
+	Date        string    `json:"date" example:"2026-10-15"`
+	Shift       string    `json:"shift" example:"Night"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Ongoing     bool      `json:"ongoing"`
+	AssetsSeen  int       `json:"assets_seen"`
+	Utilization float64   `json:"utilization" example:"0.75"`
+}
+
+// LocationActivityReport is the response of
+// GET /api/v1/reports/locations/{location_id}/activity: the location's
+// sightings from local midnight Days-1 days ago until now, rolled up by
+// local day and by shift in TimeZone. AssetsSeen counts distinct assets
+// over the whole window.
+type LocationActivityReport struct {
+	LocationID int             `json:"location_id"`
+	TimeZone   string          `json:"time_zone" example:"America/Chicago"`
+	From       time.Time       `json:"from"`
+	To         time.Time       `json:"to"`
+	AssetsSeen int             `json:"assets_seen"`
+	Daily      []DailyActivity `json:"daily"`
+	Shifts     []ShiftActivity `json:"shifts"`
+}
+
+// MaxShiftPresenceDays bounds the `days` window of
+// GET /api/v1/reports/locations/{location_id}/shift-presence, which lists
+// every asset of every shift.
+const MaxShiftPresenceDays = 31
+
+// ShiftPresenceFilter selects the shift presence report: the days local
+// days ending with the one Now falls in, only the shift named Shift when it
+// is set. Person-assets (metadata.person = true, e.g. badged staff) are
+// listed only with IncludePersonnel.
+type ShiftPresenceFilter struct {
+	Days             int
+	Shift            string
+	IncludePersonnel bool
+	Now              time.Time
+}
+
+// ShiftPresent is an asset seen at the location during a shift occurrence.
+// MinutesSeen counts the minutes a reader saw it there within the shift.
+type ShiftPresent struct {
+	AssetID     int       `json:"asset_id"`
+	ExternalKey string    `json:"external_key"`
+	Name        string    `json:"name"`
+	Type        *string   `json:"type,omitempty" example:"forklift"`
+	Person      bool      `json:"person"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	MinutesSeen int       `json:"minutes_seen"`
+}
+
+// ShiftPresence is one shift occurrence and what was present: equipment
+// first, then personnel, each by name.
+type ShiftPresence struct {
+	Date           string         `json:"date" example:"2026-10-15"`
+	Shift          string         `json:"shift" example:"Night"`
+	Start          time.Time      `json:"start"`
+	End            time.Time      `json:"end"`
+	Ongoing        bool           `json:"ongoing"`
+	AssetCount     int            `json:"asset_count"`
+	PersonnelCount int            `json:"personnel_count"`
+	Present        []ShiftPresent `json:"present"`
+}
+
+// ShiftPresenceReport is the response of
+// GET /api/v1/reports/locations/{location_id}/shift-presence.
+type ShiftPresenceReport struct {
+	LocationID        int             `json:"location_id"`
+	TimeZone          string          `json:"time_zone" example:"America/Chicago"`
+	From              time.Time       `json:"from"`
+	To                time.Time       `json:"to"`
+	IncludesPersonnel bool            `json:"includes_personnel"`
+	Shifts            []ShiftPresence `json:"shifts"`
+}
@@ -0,0 +1,13 @@
+package report
+
+import "strconv"
+
+// MaskPerson is the pseudonym under which a report anonymizing personnel
+// (organization.PersonnelSettings.AnonymizeReports) shows a person-asset:
+// external_key "person-<asset_id>" and name "Person <asset_id>". The asset
+// id stays, so rows can still be told apart and followed across reports;
+// naming the person takes a read of the asset itself.
+func MaskPerson(assetID int) (externalKey, name string) {
+	id := strconv.Itoa(assetID)
+	return "person-" + id, "Person " + id
+}
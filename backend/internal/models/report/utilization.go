@@ -0,0 +1,151 @@
+package report
+
+import (
+	"sort"
+	"time"
+)
+
+// UtilizationFilter selects the window of GET /api/v1/reports/utilization.
+// An asset is active when a reader saw it on at least MinSeenDays distinct
+// days since Since, and idle otherwise. IdleLimit caps the idle asset list.
+type UtilizationFilter struct {
+	Since       time.Time
+	MinSeenDays int
+	IdleLimit   int
+}
+
+// AssetUtilization is one asset's activity within the window. Type is the
+// asset's metadata.asset_type; location and LastSeen are its latest sighting
+// ever, so idle assets still show where they were left. Moves counts the
+// times its reported location changed between sightings in the window.
+type AssetUtilization struct {
+	AssetID             int        `json:"asset_id"`
+	ExternalKey         string     `json:"external_key"`
+	Name                string     `json:"name"`
+	Type                *string    `json:"type,omitempty" example:"forklift"`
+	LocationID          *int       `json:"location_id,omitempty"`
+	LocationExternalKey *string    `json:"location_external_key,omitempty"`
+	LocationName        *string    `json:"location_name,omitempty"`
+	LastSeen            *time.Time `json:"last_seen,omitempty"`
+	SeenDays            int        `json:"seen_days"`
+	Moves               int        `json:"moves"`
+}
+
+// UtilizationCounts splits a group of assets into active and idle.
+// Utilization is the active share, from 0 to 1.
+type UtilizationCounts struct {
+	Assets      int     `json:"assets"`
+	Active      int     `json:"active"`
+	Idle        int     `json:"idle"`
+	Moves       int     `json:"moves"`
+	Utilization float64 `json:"utilization" example:"0.64"`
+}
+
+func (c *UtilizationCounts) add(a AssetUtilization, active bool) {
+	c.Assets++
+	c.Moves += a.Moves
+	if active {
+		c.Active++
+	} else {
+		c.Idle++
+	}
+	c.Utilization = float64(c.Active) / float64(c.Assets)
+}
+
+// TypeUtilization is the breakdown for one asset type; Type is null for
+// assets without one.
+type TypeUtilization struct {
+	Type *string `json:"type"`
+	UtilizationCounts
+}
+
+// LocationUtilization is the breakdown for the assets last seen at one
+// location; LocationID is null for assets never seen.
+type LocationUtilization struct {
+	LocationID          *int    `json:"location_id"`
+	LocationExternalKey *string `json:"location_external_key,omitempty"`
+	LocationName        *string `json:"location_name,omitempty"`
+	UtilizationCounts
+}
+
+// UtilizationReport is the response of GET /api/v1/reports/utilization.
+// IdleAssets lists the idle assets least recently seen first, never-seen
+// ones leading: the candidates to stop renting.
+type UtilizationReport struct {
+	Since       time.Time             `json:"since"`
+	MinSeenDays int                   `json:"min_seen_days"`
+	Totals      UtilizationCounts     `json:"totals"`
+	ByType      []TypeUtilization     `json:"by_type"`
+	ByLocation  []LocationUtilization `json:"by_location"`
+	IdleAssets  []AssetUtilization    `json:"idle_assets"`
+}
+
+// BuildUtilizationReport classifies assets under f and totals them overall,
+// by type and by last-seen location. Groups are ordered by utilization,
+// lowest first, so the least used equipment leads.
+func BuildUtilizationReport(assets []AssetUtilization, f UtilizationFilter) UtilizationReport {
+	r := UtilizationReport{
+		Since:       f.Since,
+		MinSeenDays: f.MinSeenDays,
+		ByType:      []TypeUtilization{},
+		ByLocation:  []LocationUtilization{},
+		IdleAssets:  []AssetUtilization{},
+	}
+
+	byType := map[string]int{}
+	byLocation := map[int]int{}
+	var idle []AssetUtilization
+	for _, a := range assets {
+		active := a.SeenDays >= f.MinSeenDays
+		r.Totals.add(a, active)
+
+		typeKey := "\x00"
+		if a.Type != nil {
+			typeKey = *a.Type
+		}
+		i, ok := byType[typeKey]
+		if !ok {
+			i = len(r.ByType)
+			byType[typeKey] = i
+			r.ByType = append(r.ByType, TypeUtilization{Type: a.Type})
+		}
+		r.ByType[i].add(a, active)
+
+		locKey := 0
+		if a.LocationID != nil {
+			locKey = *a.LocationID
+		}
+		j, ok := byLocation[locKey]
+		if !ok {
+			j = len(r.ByLocation)
+			byLocation[locKey] = j
+			r.ByLocation = append(r.ByLocation, LocationUtilization{
+				LocationID: a.LocationID, LocationExternalKey: a.LocationExternalKey, LocationName: a.LocationName,
+			})
+		}
+		r.ByLocation[j].add(a, active)
+
+		if !active {
+			idle = append(idle, a)
+		}
+	}
+
+	sort.SliceStable(r.ByType, func(i, j int) bool {
+		return r.ByType[i].Utilization < r.ByType[j].Utilization
+	})
+	sort.SliceStable(r.ByLocation, func(i, j int) bool {
+		return r.ByLocation[i].Utilization < r.ByLocation[j].Utilization
+	})
+	sort.SliceStable(idle, func(i, j int) bool {
+		a, b := idle[i].LastSeen, idle[j].LastSeen
+		if a == nil || b == nil {
+			return a == nil && b != nil
+		}
+		return a.Before(*b)
+	})
+	if len(idle) > f.IdleLimit {
+		idle = idle[:f.IdleLimit]
+	}
+	r.IdleAssets = append(r.IdleAssets, idle...)
+	return r
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildUtilizationReport(t *testing.T) {
+	forklift, scissor := "forklift", "scissor_lift"
+	bay, yard := 1, 2
+	old := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	older := old.AddDate(0, 0, -10)
+	assets := []AssetUtilization{
+		{AssetID: 1, Type: &forklift, LocationID: &bay, SeenDays: 12, Moves: 4},
+		{AssetID: 2, Type: &forklift, LocationID: &bay, SeenDays: 1, LastSeen: &old},
+		{AssetID: 3, Type: &scissor, LocationID: &yard, SeenDays: 0, LastSeen: &older},
+		{AssetID: 4, Type: &scissor},
+		{AssetID: 5, LocationID: &yard, SeenDays: 3, Moves: 1},
+	}
+
+	r := BuildUtilizationReport(assets, UtilizationFilter{MinSeenDays: 2, IdleLimit: 2})
+
+	assert.Equal(t, UtilizationCounts{Assets: 5, Active: 2, Idle: 3, Moves: 5, Utilization: 0.4}, r.Totals)
+
+	require.Len(t, r.ByType, 3)
+	assert.Equal(t, &scissor, r.ByType[0].Type, "least utilized type first")
+	assert.Equal(t, 2, r.ByType[0].Idle)
+	assert.Equal(t, 0.5, r.ByType[1].Utilization)
+	assert.Nil(t, r.ByType[2].Type)
+	assert.Equal(t, 1.0, r.ByType[2].Utilization)
+
+	require.Len(t, r.ByLocation, 3)
+	assert.Nil(t, r.ByLocation[0].LocationID, "never-seen assets group under a null location")
+	assert.Equal(t, 0.5, r.ByLocation[1].Utilization)
+	assert.Equal(t, 0.5, r.ByLocation[2].Utilization)
+
+	require.Len(t, r.IdleAssets, 2, "capped at IdleLimit")
+	assert.Equal(t, 4, r.IdleAssets[0].AssetID, "never seen leads")
+	assert.Equal(t, 3, r.IdleAssets[1].AssetID)
+}
+
+func TestBuildUtilizationReport_Empty(t *testing.T) {
+	r := BuildUtilizationReport(nil, UtilizationFilter{MinSeenDays: 1, IdleLimit: 50})
+
+	assert.Zero(t, r.Totals)
+	assert.NotNil(t, r.ByType)
+	assert.NotNil(t, r.ByLocation)
+	assert.NotNil(t, r.IdleAssets)
+}
@@ -32,9 +32,12 @@ type ScanDevice struct {
 	ValidFrom    time.Time  `json:"valid_from"`
 	ValidTo      *time.Time `json:"valid_to,omitempty"`
 	IsActive     bool       `json:"is_active"`
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
-	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	// CredentialIssuedAt is set while the device holds a reader credential.
+	CredentialIssuedAt   *time.Time `json:"credential_issued_at,omitempty"`
+	CredentialLastUsedAt *time.Time `json:"credential_last_used_at,omitempty"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty"`
+	DeletedAt            *time.Time `json:"deleted_at,omitempty"`
 }
 
 type CreateScanDeviceRequest struct {
@@ -61,6 +64,22 @@ type UpdateScanDeviceRequest struct {
 	IsActive     *bool           `json:"is_active,omitempty"`
 }
 
+// CredentialResponse carries a newly issued reader credential, shown only
+// this once. Submit it as the X-Reader-Key header on scans made over a user
+// session.
+type CredentialResponse struct {
+	ScanDeviceID int       `json:"scan_device_id"`
+	Credential   string    `json:"credential" example:"trakrf_3f9a..."`
+	IssuedAt     time.Time `json:"issued_at"`
+}
+
+// Reader is the registered scan device a presented credential resolves to.
+type Reader struct {
+	DeviceID int
+	OrgID    int
+	Name     string
+}
+
 type ScanDeviceResponse struct {
 	Data ScanDevice `json:"data"`
 }
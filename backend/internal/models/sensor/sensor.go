@@ -0,0 +1,147 @@
+// Package sensor holds the condition-sensor (cold-chain) models: readings
+// posted by loggers attached to assets, per-asset-type min/max thresholds,
+// and the alerts opened when a reading falls outside its threshold.
+package sensor
+
+import (
+	"regexp"
+	"time"
+)
+
+// MaxReadingsPerRequest bounds one POST /api/v1/sensor-readings body; loggers
+// uploading a larger buffer split it.
+const MaxReadingsPerRequest = 1000
+
+// Alert conditions.
+const (
+	ConditionBelowMin = "below_min"
+	ConditionAboveMax = "above_max"
+)
+
+// AssetTypeKey is the asset metadata key thresholds match on. Assets have no
+// type column; integrators set metadata.asset_type (e.g. "reefer_pallet").
+const AssetTypeKey = "asset_type"
+
+// metricPattern keeps metric names to lowercase snake case ("temperature",
+// "relative_humidity") so thresholds and readings agree on spelling.
+var metricPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// ValidMetric reports whether m is an acceptable metric name.
+func ValidMetric(m string) bool {
+	return metricPattern.MatchString(m)
+}
+
+// ReadingInput is one reading in an ingest request. The asset is named by its
+// external key, as in inventory save.
+type ReadingInput struct {
+	AssetIdentifier string    `json:"asset_identifier" validate:"required,min=1,max=255" example:"ASSET-0001"`
+	Metric          string    `json:"metric"           validate:"required,max=64" example:"temperature"`
+	Value           *float64  `json:"value"            validate:"required" example:"4.5"`
+	Unit            *string   `json:"unit,omitempty"   validate:"omitempty,min=1,max=16" example:"C"`
+	RecordedAt      time.Time `json:"recorded_at"      validate:"required" example:"2026-10-01T12:00:00Z"`
+	DeviceID        *string   `json:"device_id,omitempty" validate:"omitempty,min=1,max=255" example:"logger-17"`
+}
+
+// IngestRequest is the POST /api/v1/sensor-readings body.
+type IngestRequest struct {
+	Readings []ReadingInput `json:"readings" validate:"required,min=1,max=1000,dive"`
+}
+
+// Reading is one stored reading.
+type Reading struct {
+	AssetID    int       `json:"asset_id"`
+	Metric     string    `json:"metric"`
+	Value      float64   `json:"value"`
+	Unit       *string   `json:"unit"`
+	DeviceID   *string   `json:"device_id"`
+	RecordedAt time.Time `json:"recorded_at"`
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// NewReading is a resolved reading ready to persist.
+type NewReading struct {
+	AssetID    int
+	Metric     string
+	Value      float64
+	Unit       *string
+	DeviceID   *string
+	RecordedAt time.Time
+}
+
+// IngestResult reports what one ingest request did. Duplicates are readings
+// already stored for the same asset, metric and time (a re-uploaded buffer).
+type IngestResult struct {
+	Accepted       int `json:"accepted"        example:"10"`
+	Duplicates     int `json:"duplicates"      example:"0"`
+	AlertsOpened   int `json:"alerts_opened"   example:"1"`
+	AlertsResolved int `json:"alerts_resolved" example:"0"`
+}
+
+// Threshold is an org's allowed range for one metric on one asset type. A nil
+// bound is unchecked; at least one bound is set.
+type Threshold struct {
+	ID        int       `json:"id"`
+	OrgID     int       `json:"org_id"`
+	AssetType string    `json:"asset_type"`
+	Metric    string    `json:"metric"`
+	MinValue  *float64  `json:"min_value"`
+	MaxValue  *float64  `json:"max_value"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Check reports whether v is outside the threshold, and if so which bound it
+// crossed. A value equal to a bound is in range.
+func (t Threshold) Check(v float64) (condition string, limit float64, breached bool) {
+	if t.MinValue != nil && v < *t.MinValue {
+		return ConditionBelowMin, *t.MinValue, true
+	}
+	if t.MaxValue != nil && v > *t.MaxValue {
+		return ConditionAboveMax, *t.MaxValue, true
+	}
+	return "", 0, false
+}
+
+// ThresholdRequest is the create and replace body for a threshold.
+type ThresholdRequest struct {
+	AssetType string   `json:"asset_type" validate:"required,min=1,max=64" example:"reefer_pallet"`
+	Metric    string   `json:"metric"     validate:"required,max=64" example:"temperature"`
+	MinValue  *float64 `json:"min_value"  example:"2"`
+	MaxValue  *float64 `json:"max_value"  example:"8"`
+}
+
+// Alert is one excursion of an asset's metric outside its threshold. It stays
+// open (ResolvedAt nil) until an in-range reading arrives.
+type Alert struct {
+	ID            int        `json:"id"`
+	OrgID         int        `json:"org_id"`
+	AssetID       int        `json:"asset_id"`
+	ThresholdID   *int       `json:"threshold_id"`
+	Metric        string     `json:"metric"`
+	Condition     string     `json:"condition"`
+	LimitValue    float64    `json:"limit_value"`
+	TriggerValue  float64    `json:"trigger_value"`
+	ExtremeValue  float64    `json:"extreme_value"`
+	LastValue     float64    `json:"last_value"`
+	ReadingCount  int        `json:"reading_count"`
+	OpenedAt      time.Time  `json:"opened_at"`
+	LastReadingAt time.Time  `json:"last_reading_at"`
+	ResolvedAt    *time.Time `json:"resolved_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// AlertFilter narrows an alert listing. Open nil lists both.
+type AlertFilter struct {
+	AssetID *int
+	Open    *bool
+	Limit   int
+}
+
+// ReadingFilter narrows an asset's reading history.
+type ReadingFilter struct {
+	Metric *string
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+}
@@ -0,0 +1,49 @@
+package sensor
+
+import "testing"
+
+func fptr(v float64) *float64 { return &v }
+
+func TestThresholdCheck(t *testing.T) {
+	both := Threshold{MinValue: fptr(2), MaxValue: fptr(8)}
+	maxOnly := Threshold{MaxValue: fptr(-18)}
+
+	cases := []struct {
+		name      string
+		th        Threshold
+		v         float64
+		condition string
+		limit     float64
+		breached  bool
+	}{
+		{"in range", both, 5, "", 0, false},
+		{"at min is in range", both, 2, "", 0, false},
+		{"at max is in range", both, 8, "", 0, false},
+		{"below min", both, 1.5, ConditionBelowMin, 2, true},
+		{"above max", both, 9, ConditionAboveMax, 8, true},
+		{"max only, cold enough", maxOnly, -20, "", 0, false},
+		{"max only, thawing", maxOnly, -10, ConditionAboveMax, -18, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cond, limit, breached := c.th.Check(c.v)
+			if cond != c.condition || limit != c.limit || breached != c.breached {
+				t.Fatalf("Check(%v) = (%q, %v, %v), want (%q, %v, %v)",
+					c.v, cond, limit, breached, c.condition, c.limit, c.breached)
+			}
+		})
+	}
+}
+
+func TestValidMetric(t *testing.T) {
+	for _, m := range []string{"temperature", "relative_humidity", "co2", "t"} {
+		if !ValidMetric(m) {
+			t.Errorf("ValidMetric(%q) = false", m)
+		}
+	}
+	for _, m := range []string{"", "Temperature", "1temp", "temp-c", "temp c"} {
+		if ValidMetric(m) {
+			t.Errorf("ValidMetric(%q) = true", m)
+		}
+	}
+}
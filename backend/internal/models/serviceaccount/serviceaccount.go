@@ -0,0 +1,22 @@
+package serviceaccount
+
+import "time"
+
+// ServiceAccount is a non-human org principal. It owns API keys but has no
+// credentials of its own, so it can never sign in interactively.
+type ServiceAccount struct {
+	ID             int       `json:"id"`
+	OrgID          int       `json:"org_id"`
+	Name           string    `json:"name"`
+	Description    *string   `json:"description"`
+	CreatedBy      *int      `json:"created_by"`
+	ActiveKeyCount int       `json:"active_key_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CreateServiceAccountRequest is the POST /api/v1/orgs/{id}/service-accounts body.
+type CreateServiceAccountRequest struct {
+	Name        string  `json:"name"        validate:"required,min=1,max=255"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1024"`
+}
@@ -238,3 +238,42 @@ func TestFlexibleDate_MarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+// FuzzFlexibleDate_UnmarshalJSON checks that no request body can panic the
+// decoder, that an accepted value is never a sentinel, and that it survives
+// the public wire format at millisecond precision. Run with:
+//
+//	go test -run '^$' -fuzz FuzzFlexibleDate_UnmarshalJSON ./internal/models/shared/
+func FuzzFlexibleDate_UnmarshalJSON(f *testing.F) {
+	for _, s := range []string{
+		`"2025-12-14T10:30:00Z"`, `"2025-12-14T10:30:00.123456789+05:30"`, `null`, `""`,
+		`"0001-01-01T00:00:00Z"`, `"1970-01-01T00:00:00Z"`, `"1970-01-01T00:00:00.0001Z"`,
+		`"2025-12-14"`, `"0000-01-01T00:00:00+01:00"`, `12345`, `"`,
+	} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, b []byte) {
+		var fd FlexibleDate
+		if err := fd.UnmarshalJSON(b); err != nil || fd.IsZero() {
+			return
+		}
+		if fd.Equal(unixEpochUTC) {
+			t.Fatalf("accepted the epoch sentinel from %q", b)
+		}
+
+		out, err := fd.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON(%v): %v", fd.Time, err)
+		}
+		// The wire format is UTC at millisecond precision, so the value
+		// only comes back when that rendering is itself acceptable.
+		want := fd.UTC().Truncate(time.Millisecond)
+		if y := want.Year(); y < 1 || y > 9999 || want.Equal(unixEpochUTC) {
+			return
+		}
+		var back FlexibleDate
+		if err := back.UnmarshalJSON(out); err != nil || !back.Equal(want) {
+			t.Fatalf("%q -> %s -> %v, %v; want %v", b, out, back.Time, err, want)
+		}
+	})
+}
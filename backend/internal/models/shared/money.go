@@ -0,0 +1,211 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// currencyExponents holds the ISO 4217 minor-unit digits of the currencies
+// money fields accept.
+var currencyExponents = map[string]int{
+	"AED": 2, "AUD": 2, "BHD": 3, "BRL": 2, "CAD": 2, "CHF": 2, "CLP": 0,
+	"CNY": 2, "CZK": 2, "DKK": 2, "EUR": 2, "GBP": 2, "HKD": 2, "HUF": 2,
+	"IDR": 2, "ILS": 2, "INR": 2, "ISK": 0, "JOD": 3, "JPY": 0, "KRW": 0,
+	"KWD": 3, "MXN": 2, "MYR": 2, "NOK": 2, "NZD": 2, "OMR": 3, "PHP": 2,
+	"PLN": 2, "SAR": 2, "SEK": 2, "SGD": 2, "THB": 2, "TRY": 2, "TWD": 2,
+	"USD": 2, "VND": 0, "ZAR": 2,
+}
+
+// currencySymbols are the symbols Format uses; other currencies are shown
+// by code.
+var currencySymbols = map[string]string{
+	"EUR": "€", "GBP": "£", "INR": "₹", "JPY": "¥", "KRW": "₩", "USD": "$",
+}
+
+// numberLocale is how a locale writes an amount.
+type numberLocale struct {
+	group, decimal string
+	// symbolAfter puts the currency after the number. Symbols are set off
+	// from the number by a no-break space, where one is used at all.
+	symbolAfter bool
+	// symbolSpace sets a leading symbol off from the number.
+	symbolSpace bool
+}
+
+// numberLocales are the locales Format knows; others fall back to en-US.
+var numberLocales = map[string]numberLocale{
+	"en-US": {group: ",", decimal: "."},
+	"en-GB": {group: ",", decimal: "."},
+	"en-AU": {group: ",", decimal: "."},
+	"en-CA": {group: ",", decimal: "."},
+	"ja-JP": {group: ",", decimal: "."},
+	"de-DE": {group: ".", decimal: ",", symbolAfter: true},
+	"es-ES": {group: ".", decimal: ",", symbolAfter: true},
+	"it-IT": {group: ".", decimal: ",", symbolAfter: true},
+	"pt-BR": {group: ".", decimal: ",", symbolSpace: true},
+	"nl-NL": {group: ".", decimal: ",", symbolSpace: true},
+	"fr-FR": {group: "\u202f", decimal: ",", symbolAfter: true},
+	"de-CH": {group: "\u2019", decimal: ".", symbolSpace: true},
+}
+
+// IsCurrency reports whether code is an ISO 4217 code money fields accept.
+func IsCurrency(code string) bool {
+	_, ok := currencyExponents[code]
+	return ok
+}
+
+// IsLocale reports whether Format knows locale.
+func IsLocale(locale string) bool {
+	_, ok := numberLocales[locale]
+	return ok
+}
+
+// Money is an amount in a currency, held in the currency's minor units so
+// sums are exact. It serializes as {"amount":"1234.50","currency":"USD"}:
+// the amount is a decimal string with exactly the currency's minor digits,
+// so clients never round through a float.
+type Money struct {
+	// Amount is in minor units: cents for USD, yen for JPY.
+	Amount   int64  `json:"amount" swaggertype:"string" example:"1234.50"`
+	Currency string `json:"currency" example:"USD"`
+}
+
+// ParseMoney parses a decimal amount in currency, e.g. ("1234.5", "USD").
+// It rejects unknown currencies, more fraction digits than the currency
+// has, and amounts that overflow.
+func ParseMoney(amount, currency string) (Money, error) {
+	exp, ok := currencyExponents[currency]
+	if !ok {
+		return Money{}, fmt.Errorf("currency %q is not a supported ISO 4217 code", currency)
+	}
+	s := amount
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || !allDigits(whole) || (hasFrac && (frac == "" || !allDigits(frac))) {
+		return Money{}, fmt.Errorf("amount %q is not a decimal number", amount)
+	}
+	if len(frac) > exp {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimal places for %s", amount, exp, currency)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+	n, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("amount %q is out of range", amount)
+	}
+	if neg {
+		n = -n
+	}
+	return Money{Amount: n, Currency: currency}, nil
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal is the amount as a plain decimal string, e.g. "1234.50".
+func (m Money) Decimal() string {
+	return m.decimal("", ".")
+}
+
+func (m Money) decimal(group, point string) string {
+	exp := currencyExponents[m.Currency]
+	sign := ""
+	n := uint64(m.Amount)
+	if m.Amount < 0 {
+		sign = "-"
+		n = uint64(-(m.Amount + 1)) + 1
+	}
+	digits := strconv.FormatUint(n, 10)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	whole, frac := digits[:len(digits)-exp], digits[len(digits)-exp:]
+	if group != "" {
+		var b strings.Builder
+		for i, c := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				b.WriteString(group)
+			}
+			b.WriteRune(c)
+		}
+		whole = b.String()
+	}
+	if exp == 0 {
+		return sign + whole
+	}
+	return sign + whole + point + frac
+}
+
+// Format renders m for display in locale: "en-US" gives "$1,234.50",
+// "de-DE" gives "1.234,50 €" (with a no-break space). Unknown locales
+// format as en-US.
+func (m Money) Format(locale string) string {
+	loc, ok := numberLocales[locale]
+	if !ok {
+		loc = numberLocales["en-US"]
+	}
+	num := m.decimal(loc.group, loc.decimal)
+	sym, ok := currencySymbols[m.Currency]
+	if !ok {
+		sym = m.Currency
+		loc.symbolSpace = true
+	}
+	if loc.symbolAfter {
+		return num + "\u00a0" + sym
+	}
+	sign := ""
+	if strings.HasPrefix(num, "-") {
+		sign, num = "-", num[1:]
+	}
+	if loc.symbolSpace {
+		return sign + sym + "\u00a0" + num
+	}
+	return sign + sym + num
+}
+
+// Add is m plus o. Both must be in the same currency.
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("cannot add %s to %s", o.Currency, m.Currency)
+	}
+	if (o.Amount > 0 && m.Amount > math.MaxInt64-o.Amount) ||
+		(o.Amount < 0 && m.Amount < math.MinInt64-o.Amount) {
+		return Money{}, fmt.Errorf("sum of %s amounts is out of range", m.Currency)
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+// MarshalJSON writes the amount as a decimal string.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.Currency})
+}
+
+// UnmarshalJSON reads {"amount": ..., "currency": ...}, the amount either a
+// decimal string or a JSON number, parsed as ParseMoney does.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	var raw struct {
+		Amount   json.Number `json:"amount"`
+		Currency string      `json:"currency"`
+	}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(raw.Amount.String(), raw.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,85 @@
+package shared
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		amount, currency string
+		want             int64
+		wantErr          bool
+	}{
+		{"1234.5", "USD", 123450, false},
+		{"1234.50", "USD", 123450, false},
+		{"1234", "USD", 123400, false},
+		{"-0.01", "EUR", -1, false},
+		{"1500", "JPY", 1500, false},
+		{"1.234", "KWD", 1234, false},
+		{"1.5", "JPY", 0, true},
+		{"1.001", "USD", 0, true},
+		{"1.", "USD", 0, true},
+		{".5", "USD", 0, true},
+		{"1e3", "USD", 0, true},
+		{"12", "usd", 0, true},
+		{"12", "XYZ", 0, true},
+		{"99999999999999999999", "USD", 0, true},
+	}
+	for _, c := range cases {
+		m, err := ParseMoney(c.amount, c.currency)
+		if c.wantErr {
+			assert.Error(t, err, "%s %s", c.amount, c.currency)
+			continue
+		}
+		require.NoError(t, err, "%s %s", c.amount, c.currency)
+		assert.Equal(t, Money{Amount: c.want, Currency: c.currency}, m)
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	b, err := json.Marshal(Money{Amount: 123450, Currency: "USD"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"1234.50","currency":"USD"}`, string(b))
+
+	b, err = json.Marshal(Money{Amount: -5, Currency: "EUR"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"-0.05","currency":"EUR"}`, string(b))
+
+	var m Money
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"1234.50","currency":"USD"}`), &m))
+	assert.Equal(t, Money{Amount: 123450, Currency: "USD"}, m)
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":1500,"currency":"JPY"}`), &m))
+	assert.Equal(t, Money{Amount: 1500, Currency: "JPY"}, m)
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1.5","currency":"JPY"}`), &m))
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"1.50"}`), &m), "currency is required")
+}
+
+func TestMoneyFormat(t *testing.T) {
+	usd := Money{Amount: 123456789, Currency: "USD"}
+	eur := Money{Amount: 123456789, Currency: "EUR"}
+	assert.Equal(t, "$1,234,567.89", usd.Format("en-US"))
+	assert.Equal(t, "1.234.567,89\u00a0€", eur.Format("de-DE"))
+	assert.Equal(t, "1\u202f234\u202f567,89\u00a0€", eur.Format("fr-FR"))
+	assert.Equal(t, "€\u00a01.234.567,89", eur.Format("nl-NL"))
+	assert.Equal(t, "¥1,500", Money{Amount: 1500, Currency: "JPY"}.Format("ja-JP"))
+	assert.Equal(t, "CHF\u00a01\u2019234.50", Money{Amount: 123450, Currency: "CHF"}.Format("de-CH"))
+	assert.Equal(t, "-$0.05", Money{Amount: -5, Currency: "USD"}.Format("en-US"))
+	assert.Equal(t, "$999.00", Money{Amount: 99900, Currency: "USD"}.Format("xx-XX"), "unknown locale formats as en-US")
+	assert.Equal(t, "-9223372036854775.808", Money{Amount: math.MinInt64, Currency: "KWD"}.Decimal())
+}
+
+func TestMoneyAdd(t *testing.T) {
+	sum, err := Money{Amount: 150, Currency: "USD"}.Add(Money{Amount: 250, Currency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, Money{Amount: 400, Currency: "USD"}, sum)
+
+	_, err = Money{Amount: 1, Currency: "USD"}.Add(Money{Amount: 1, Currency: "EUR"})
+	assert.Error(t, err)
+	_, err = Money{Amount: math.MaxInt64, Currency: "USD"}.Add(Money{Amount: 1, Currency: "USD"})
+	assert.Error(t, err)
+}
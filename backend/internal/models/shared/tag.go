@@ -1,5 +1,11 @@
 package shared
 
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
 // DefaultTagType is the historical default surfaced when callers omitted
 // tag_type. TRA-739 (BB42 F2) tightened tag_type to spec-required on the
 // public API, so a write request without tag_type now returns 400
@@ -14,6 +20,23 @@ type Tag struct {
 	Value   string `json:"value" validate:"required,min=1,max=255,no_control_chars"`
 }
 
+// TagAssignment is one interval during which a tag identified an asset or a
+// location. A recycled tag has one assignment per asset it has been on;
+// UnassignedAt is nil while the assignment is current.
+type TagAssignment struct {
+	TagID               int        `json:"tag_id"`
+	TagType             string     `json:"tag_type" example:"rfid"`
+	Value               string     `json:"value"`
+	AssetID             *int       `json:"asset_id,omitempty"`
+	AssetExternalKey    *string    `json:"asset_external_key,omitempty" example:"ASSET-0001"`
+	AssetName           *string    `json:"asset_name,omitempty"`
+	LocationID          *int       `json:"location_id,omitempty"`
+	LocationExternalKey *string    `json:"location_external_key,omitempty" example:"WH-01"`
+	LocationName        *string    `json:"location_name,omitempty"`
+	AssignedAt          time.Time  `json:"assigned_at"`
+	UnassignedAt        *time.Time `json:"unassigned_at,omitempty"`
+}
+
 // TagRequest is the wire shape of a public tag-write body. Pointer
 // distinguishes "field omitted" (TRA-678) from "field supplied as null /
 // empty string" so the presence-tracking decoder can promote both omitted
@@ -25,6 +48,9 @@ type Tag struct {
 // spec subtype schemas (each of which lists tag_type as required) and let
 // loose clients land on a not-intended discriminator when the body
 // targeted a future variant.
+//
+// Value must also fit its tag_type (see ValidateTagValue); the check is a
+// struct-level validation registered by httputil.RegisterCustomValidations.
 type TagRequest struct {
 	TagType *string `json:"tag_type" validate:"required,oneof=rfid ble barcode" example:"rfid" extensions:"x-extensible-enum=true"`
 	Value   string  `json:"value" validate:"required,min=1,max=255,no_control_chars"`
@@ -40,3 +66,67 @@ func (t TagRequest) GetType() string {
 	}
 	return *t.TagType
 }
+
+var (
+	hexPattern = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+	macPattern = regexp.MustCompile(`^[0-9A-Fa-f]{2}(?:(?::[0-9A-Fa-f]{2}){5}|(?:-[0-9A-Fa-f]{2}){5}|[0-9A-Fa-f]{10})$`)
+)
+
+// gs1KeyLengths names the all-digit barcode lengths that are GS1 keys and so
+// carry a mod-10 check digit: GTIN-8/12/13/14 and SSCC.
+var gs1KeyLengths = map[int]string{8: "GTIN-8", 12: "GTIN-12", 13: "GTIN-13", 14: "GTIN-14", 18: "SSCC"}
+
+// ValidateTagValue checks value against the format of its tag type. RFID
+// values are EPC memory in hex, a whole number of 16-bit words; BLE values
+// are MAC addresses (colon, hyphen, or unseparated); barcodes may be any
+// symbology, but an all-digit value the length of a GS1 key must carry a
+// valid GS1 check digit. Unknown types are left to the oneof validator. The
+// returned error reads as a predicate on the value ("must be ...").
+func ValidateTagValue(tagType, value string) error {
+	switch tagType {
+	case "rfid":
+		if !hexPattern.MatchString(value) {
+			return fmt.Errorf("must be an EPC in hexadecimal (0-9, A-F) for tag_type rfid, e.g. 3074257BF7194E4000001A85")
+		}
+		if len(value)%4 != 0 {
+			return fmt.Errorf("must be a whole number of 16-bit EPC words (a multiple of 4 hex digits) for tag_type rfid; got %d digits", len(value))
+		}
+	case "ble":
+		if !macPattern.MatchString(value) {
+			return fmt.Errorf("must be a MAC address for tag_type ble, e.g. AA:BB:CC:DD:EE:FF")
+		}
+	case "barcode":
+		key, ok := gs1KeyLengths[len(value)]
+		if !ok || !isDigits(value) {
+			return nil
+		}
+		want := gs1CheckDigit(value[:len(value)-1])
+		if got := value[len(value)-1]; got != want {
+			return fmt.Errorf("has an invalid %s check digit for tag_type barcode: expected %c, got %c", key, want, got)
+		}
+	}
+	return nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// gs1CheckDigit computes the GS1 mod-10 check digit for the digits of a key
+// without its check digit: weights alternate 3,1 starting from the rightmost.
+func gs1CheckDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
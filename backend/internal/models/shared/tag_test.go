@@ -0,0 +1,72 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTagValue(t *testing.T) {
+	valid := []struct{ tagType, value string }{
+		{"rfid", "3074257BF7194E4000001A85"},
+		{"rfid", "e2801160"},
+		{"ble", "AA:BB:CC:DD:EE:FF"},
+		{"ble", "aa-bb-cc-dd-ee-ff"},
+		{"ble", "AABBCCDDEEFF"},
+		{"barcode", "036000291452"},       // UPC-A / GTIN-12
+		{"barcode", "4006381333931"},      // EAN-13
+		{"barcode", "96385074"},           // EAN-8
+		{"barcode", "10614141000019"},     // GTIN-14
+		{"barcode", "106141412345678908"}, // SSCC
+		{"barcode", "ABC-123/xyz"},        // Code 128, not a GS1 key
+		{"barcode", "12345"},              // digits, but no GS1 key length
+		{"unknown", "anything"},           // left to oneof
+	}
+	for _, c := range valid {
+		assert.NoError(t, ValidateTagValue(c.tagType, c.value), "%s:%s", c.tagType, c.value)
+	}
+
+	invalid := []struct{ tagType, value, msg string }{
+		{"rfid", "E2-007707", "must be an EPC in hexadecimal"},
+		{"rfid", "E28011", "got 6 digits"},
+		{"ble", "AA:BB:CC:DD:EE", "must be a MAC address"},
+		{"ble", "AA:BB-CC:DD:EE:FF", "must be a MAC address"},
+		{"barcode", "036000291453", "invalid GTIN-12 check digit for tag_type barcode: expected 2, got 3"},
+		{"barcode", "106141412345678907", "invalid SSCC check digit"},
+	}
+	for _, c := range invalid {
+		err := ValidateTagValue(c.tagType, c.value)
+		if assert.Error(t, err, "%s:%s", c.tagType, c.value) {
+			assert.Contains(t, err.Error(), c.msg)
+		}
+	}
+}
+
+// FuzzValidateTagValue checks that ValidateTagValue never panics and that
+// what it accepts for rfid and ble is storable as that type. Run with:
+//
+//	go test -run '^$' -fuzz FuzzValidateTagValue ./internal/models/shared/
+func FuzzValidateTagValue(f *testing.F) {
+	for _, s := range []string{"3074257BF7194E4000001A85", "e2801160", "AA:BB:CC:DD:EE:FF", "aa-bb-cc-dd-ee-ff", "036000291452", "106141412345678908", "ABC-123/xyz", "", "ÿ", "AA:BB-CC:DD:EE:FF"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		if ValidateTagValue("rfid", value) == nil {
+			if !hexPattern.MatchString(value) || len(value)%4 != 0 {
+				t.Fatalf("rfid accepted %q", value)
+			}
+		}
+		if ValidateTagValue("ble", value) == nil {
+			digits := strings.NewReplacer(":", "", "-", "").Replace(value)
+			if len(digits) != 12 || !hexPattern.MatchString(digits) {
+				t.Fatalf("ble accepted %q", value)
+			}
+		}
+		if ValidateTagValue("barcode", value) == nil && isDigits(value) {
+			if _, ok := gs1KeyLengths[len(value)]; ok && gs1CheckDigit(value[:len(value)-1]) != value[len(value)-1] {
+				t.Fatalf("barcode accepted %q with a bad check digit", value)
+			}
+		}
+	})
+}
@@ -0,0 +1,10 @@
+package shared
+
+import "time"
+
+// EffectiveAt reports whether the half-open validity window
+// [validFrom, validTo) contains at. A nil validTo is open-ended. It mirrors
+// the storage layer's temporal-validity predicate for rows already loaded.
+func EffectiveAt(validFrom time.Time, validTo *time.Time, at time.Time) bool {
+	return !validFrom.After(at) && (validTo == nil || validTo.After(at))
+}
@@ -0,0 +1,19 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEffectiveAt(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	assert.True(t, EffectiveAt(from, nil, from), "window opens at valid_from")
+	assert.True(t, EffectiveAt(from, nil, from.AddDate(5, 0, 0)), "nil valid_to is open-ended")
+	assert.False(t, EffectiveAt(from, nil, from.Add(-time.Second)))
+	assert.True(t, EffectiveAt(from, &to, to.Add(-time.Second)))
+	assert.False(t, EffectiveAt(from, &to, to), "window is closed at valid_to")
+}
@@ -0,0 +1,107 @@
+// Package stock holds the consumable-asset models: assets tracked by quantity
+// per location instead of individually, the adjustments that move their
+// stock, and the alerts raised when a location falls below its minimum.
+package stock
+
+import "time"
+
+// Adjustment kinds.
+const (
+	// KindAdjust applies a signed delta (receipt, issue, write-off).
+	KindAdjust = "adjust"
+	// KindCount replaces the level with a counted quantity (cycle count).
+	KindCount = "count"
+)
+
+// DefaultUnit is the unit of a consumable created without one.
+const DefaultUnit = "each"
+
+// Consumable marks an asset as tracked by quantity. MinLevel nil disables
+// stock alerts.
+type Consumable struct {
+	AssetID   int       `json:"asset_id"`
+	OrgID     int       `json:"org_id"`
+	Unit      string    `json:"unit"`
+	MinLevel  *int      `json:"min_level"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ConsumableRequest is the PUT /api/v1/assets/{asset_id}/consumable body.
+// Omitted unit keeps the current one (DefaultUnit for a new consumable).
+type ConsumableRequest struct {
+	Unit     *string `json:"unit,omitempty" validate:"omitempty,min=1,max=16" example:"box"`
+	MinLevel *int    `json:"min_level"      validate:"omitempty,min=0" example:"10"`
+}
+
+// Level is a consumable's stock at one location.
+type Level struct {
+	AssetID             int       `json:"asset_id"`
+	LocationID          int       `json:"location_id"`
+	LocationExternalKey string    `json:"location_external_key"`
+	Quantity            int       `json:"quantity"`
+	BelowMin            bool      `json:"below_min"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// AdjustmentRequest is the POST /api/v1/assets/{asset_id}/stock/adjustments
+// body. Exactly one of delta and quantity is set: delta adjusts the level,
+// quantity records a count that replaces it.
+type AdjustmentRequest struct {
+	LocationIdentifier string  `json:"location_identifier" validate:"required,min=1,max=255" example:"WH-01"`
+	Delta              *int    `json:"delta,omitempty" example:"-5"`
+	Quantity           *int    `json:"quantity,omitempty" validate:"omitempty,min=0" example:"40"`
+	Reason             *string `json:"reason,omitempty" validate:"omitempty,min=1,max=255" example:"issued to line 3"`
+}
+
+// NewAdjustment is a resolved adjustment ready to apply. Delta is used for
+// KindAdjust, Quantity for KindCount.
+type NewAdjustment struct {
+	LocationID int
+	Kind       string
+	Delta      int
+	Quantity   int
+	Reason     *string
+	UserID     *int
+}
+
+// Adjustment is one recorded change to a stock level.
+type Adjustment struct {
+	ID            int       `json:"id"`
+	AssetID       int       `json:"asset_id"`
+	LocationID    int       `json:"location_id"`
+	Kind          string    `json:"kind"`
+	Delta         int       `json:"delta"`
+	QuantityAfter int       `json:"quantity_after"`
+	Reason        *string   `json:"reason"`
+	CreatedBy     *int      `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Alert is one shortfall of a consumable at a location. It stays open
+// (ResolvedAt nil) until the level is back at or above MinLevel.
+type Alert struct {
+	ID         int        `json:"id"`
+	OrgID      int        `json:"org_id"`
+	AssetID    int        `json:"asset_id"`
+	LocationID int        `json:"location_id"`
+	MinLevel   int        `json:"min_level"`
+	Quantity   int        `json:"quantity"`
+	OpenedAt   time.Time  `json:"opened_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// AlertFilter narrows an alert listing. Open nil lists both.
+type AlertFilter struct {
+	AssetID *int
+	Open    *bool
+	Limit   int
+}
+
+// BelowMin reports whether quantity is short of minLevel. A nil minLevel
+// never is.
+func BelowMin(quantity int, minLevel *int) bool {
+	return minLevel != nil && quantity < *minLevel
+}
@@ -0,0 +1,25 @@
+package stock
+
+import "testing"
+
+func TestBelowMin(t *testing.T) {
+	ten := 10
+	cases := []struct {
+		name     string
+		quantity int
+		minLevel *int
+		want     bool
+	}{
+		{"no minimum", 0, nil, false},
+		{"below", 9, &ten, true},
+		{"at minimum is not below", 10, &ten, false},
+		{"above", 25, &ten, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := BelowMin(c.quantity, c.minLevel); got != c.want {
+				t.Fatalf("BelowMin(%d, %v) = %v, want %v", c.quantity, c.minLevel, got, c.want)
+			}
+		})
+	}
+}
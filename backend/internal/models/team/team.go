@@ -0,0 +1,84 @@
+// Package team models departments/teams within an org: named groups of org
+// members that assets and locations can be assigned to. Internal-only
+// (session-authenticated) surface.
+package team
+
+import "time"
+
+const (
+	RoleMember = "member"
+	RoleAdmin  = "admin"
+)
+
+type Team struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type Member struct {
+	UserID   int       `json:"user_id"`
+	Email    string    `json:"email"`
+	Name     string    `json:"name"`
+	Role     string    `json:"role"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// TeamWithMembers is the single-team read: the team plus its roster and the
+// number of assets and locations assigned to it.
+type TeamWithMembers struct {
+	Team
+	Members       []Member `json:"members"`
+	AssetCount    int      `json:"asset_count"`
+	LocationCount int      `json:"location_count"`
+}
+
+type CreateTeamRequest struct {
+	Name        string  `json:"name" validate:"required,min=1,max=255,no_control_chars" example:"Maintenance"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1024"`
+}
+
+// UpdateTeamRequest is a partial update; omitted fields are left unchanged.
+type UpdateTeamRequest struct {
+	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=255,no_control_chars"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=1024"`
+}
+
+type SetMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=member admin" example:"member"`
+}
+
+// Settings is the org-wide team configuration, stored under
+// organizations.metadata.teams.
+type Settings struct {
+	// StrictMode limits non-admin members to the assets and locations
+	// assigned to their own teams.
+	StrictMode bool `json:"strict_mode"`
+}
+
+// Scope is the team visibility of one caller in one org. When Restricted,
+// only rows assigned to one of TeamIDs are visible; unassigned rows are not.
+type Scope struct {
+	Restricted bool
+	TeamIDs    []int
+}
+
+// Allows reports whether a row assigned to teamID (nil = unassigned) is
+// visible under s.
+func (s Scope) Allows(teamID *int) bool {
+	if !s.Restricted {
+		return true
+	}
+	if teamID == nil {
+		return false
+	}
+	for _, id := range s.TeamIDs {
+		if id == *teamID {
+			return true
+		}
+	}
+	return false
+}
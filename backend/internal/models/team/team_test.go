@@ -0,0 +1,26 @@
+package team
+
+import "testing"
+
+func intp(v int) *int { return &v }
+
+func TestScope_Allows(t *testing.T) {
+	cases := []struct {
+		name   string
+		scope  Scope
+		teamID *int
+		want   bool
+	}{
+		{"unrestricted sees unassigned", Scope{}, nil, true},
+		{"unrestricted sees any team", Scope{}, intp(9), true},
+		{"restricted hides unassigned", Scope{Restricted: true, TeamIDs: []int{1}}, nil, false},
+		{"restricted sees own team", Scope{Restricted: true, TeamIDs: []int{1, 2}}, intp(2), true},
+		{"restricted hides other team", Scope{Restricted: true, TeamIDs: []int{1}}, intp(3), false},
+		{"restricted with no teams sees nothing", Scope{Restricted: true}, intp(1), false},
+	}
+	for _, tc := range cases {
+		if got := tc.scope.Allows(tc.teamID); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
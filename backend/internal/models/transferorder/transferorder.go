@@ -0,0 +1,189 @@
+// Package transferorder holds the models for transfer orders: a planned move
+// of a set of assets from one location to another, verified by scanning at
+// the origin before it ships and at the destination when it arrives.
+package transferorder
+
+import "time"
+
+// Order statuses. open → in_transit (ship) → received (receive) is the happy
+// path; an open or in-transit order can be cancelled.
+const (
+	StatusOpen      = "open"
+	StatusInTransit = "in_transit"
+	StatusReceived  = "received"
+	StatusCancelled = "cancelled"
+)
+
+// Actions on an order.
+const (
+	ActionShip    = "ship"
+	ActionReceive = "receive"
+	ActionCancel  = "cancel"
+)
+
+// Verification stages. Origin scans are taken while the order is open,
+// destination scans while it is in transit.
+const (
+	StageOrigin      = "origin"
+	StageDestination = "destination"
+)
+
+// Carrier tracking statuses, normalized across carriers.
+const (
+	TrackingPreTransit = "pre_transit"
+	TrackingInTransit  = "in_transit"
+	TrackingDelivered  = "delivered"
+	TrackingException  = "exception"
+	TrackingUnknown    = "unknown"
+)
+
+// Item is one asset of an order. Expected is false for an asset the order
+// did not list but a verification scan found.
+type Item struct {
+	AssetID              int        `json:"asset_id"`
+	AssetExternalKey     string     `json:"asset_external_key" example:"PAL-1"`
+	AssetName            string     `json:"asset_name" example:"Pallet 1"`
+	Expected             bool       `json:"expected"`
+	OriginScannedAt      *time.Time `json:"origin_scanned_at,omitempty"`
+	DestinationScannedAt *time.Time `json:"destination_scanned_at,omitempty"`
+}
+
+// Order is one transfer order. Items is filled by Get and left empty by
+// List; ExpectedCount is always set.
+type Order struct {
+	ID                    int        `json:"id"`
+	Reference             *string    `json:"reference,omitempty" example:"TO-1042"`
+	OriginLocationID      int        `json:"origin_location_id"`
+	DestinationLocationID int        `json:"destination_location_id"`
+	Status                string     `json:"status" example:"open"`
+	Note                  *string    `json:"note,omitempty"`
+	ExpectedCount         int        `json:"expected_count" example:"24"`
+	CreatedBy             *int       `json:"created_by,omitempty"`
+	ShippedBy             *int       `json:"shipped_by,omitempty"`
+	ShippedAt             *time.Time `json:"shipped_at,omitempty"`
+	ReceivedBy            *int       `json:"received_by,omitempty"`
+	ReceivedAt            *time.Time `json:"received_at,omitempty"`
+	CancelledAt           *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+	Tracking              *Tracking  `json:"tracking,omitempty"`
+	Items                 []Item     `json:"items,omitempty"`
+}
+
+// Tracking is an order's carrier shipment as last polled. Status and the
+// fields after it stay empty until the first successful poll, and only
+// carriers the server has a tracker for are polled at all.
+type Tracking struct {
+	Carrier        string     `json:"carrier" example:"dhl"`
+	TrackingNumber string     `json:"tracking_number" example:"00340434292135100186"`
+	Status         *string    `json:"status,omitempty" example:"in_transit"`
+	Detail         *string    `json:"detail,omitempty" example:"The shipment has been processed in the destination parcel center"`
+	ETA            *time.Time `json:"eta,omitempty"`
+	EventAt        *time.Time `json:"event_at,omitempty"`
+	CheckedAt      *time.Time `json:"checked_at,omitempty"`
+	Error          *string    `json:"error,omitempty"`
+}
+
+// CreateRequest is the body of POST /api/v1/transfer-orders.
+type CreateRequest struct {
+	Reference             *string `json:"reference,omitempty" validate:"omitempty,min=1,max=255,no_control_chars" example:"TO-1042"`
+	OriginLocationID      int     `json:"origin_location_id" validate:"required,gt=0"`
+	DestinationLocationID int     `json:"destination_location_id" validate:"required,gt=0,nefield=OriginLocationID"`
+	Note                  *string `json:"note,omitempty" validate:"omitempty,min=1,max=1024,no_control_chars"`
+	AssetIDs              []int   `json:"asset_ids" validate:"required,min=1,max=1000,unique,dive,gt=0"`
+}
+
+// SetTrackingRequest is the body of PUT
+// /api/v1/transfer-orders/{id}/tracking. Carrier is a code such as dhl,
+// matched case-insensitively.
+type SetTrackingRequest struct {
+	Carrier        string `json:"carrier" validate:"required,min=1,max=50,no_control_chars" example:"dhl"`
+	TrackingNumber string `json:"tracking_number" validate:"required,min=1,max=255,no_control_chars" example:"00340434292135100186"`
+}
+
+// TrackedOrder is an order due a carrier poll.
+type TrackedOrder struct {
+	ID             int
+	OrgID          int
+	Carrier        string
+	TrackingNumber string
+}
+
+// TrackingUpdate is what one carrier poll reports.
+type TrackingUpdate struct {
+	Status  string
+	Detail  string
+	ETA     *time.Time
+	EventAt *time.Time
+}
+
+// VerifyRequest is the body of POST /api/v1/transfer-orders/{id}/scans: the
+// EPCs read at one end of the move.
+type VerifyRequest struct {
+	Stage string   `json:"stage" validate:"required,oneof=origin destination" example:"origin"`
+	EPCs  []string `json:"epcs" validate:"required,min=1,max=1000,dive,min=1,max=255"`
+}
+
+// VerifyResult reports one verification scan: the expected assets it found,
+// the unexpected ones it added to the order, the EPCs that resolve to no
+// asset, and how many expected assets are still unscanned at this stage.
+type VerifyResult struct {
+	OrderID     int      `json:"order_id"`
+	Stage       string   `json:"stage" example:"origin"`
+	Matched     []int    `json:"matched"`
+	Extra       []int    `json:"extra"`
+	UnknownEPCs []string `json:"unknown_epcs"`
+	Remaining   int      `json:"remaining" example:"3"`
+}
+
+// ListFilter selects orders for GET /api/v1/transfer-orders. LocationID
+// matches either end.
+type ListFilter struct {
+	Status     string
+	LocationID int
+	Limit      int
+}
+
+// DiscrepancyReport compares an order's scans with its expected assets.
+// Missing items are expected assets not scanned at that end; extra items were
+// scanned there but not expected. Destination lists stay empty until the
+// order has shipped.
+type DiscrepancyReport struct {
+	OrderID              int    `json:"order_id"`
+	Status               string `json:"status" example:"received"`
+	MissingAtOrigin      []Item `json:"missing_at_origin"`
+	ExtraAtOrigin        []Item `json:"extra_at_origin"`
+	MissingAtDestination []Item `json:"missing_at_destination"`
+	ExtraAtDestination   []Item `json:"extra_at_destination"`
+}
+
+// Discrepancies builds o's discrepancy report from its items.
+func Discrepancies(o Order) DiscrepancyReport {
+	r := DiscrepancyReport{
+		OrderID:              o.ID,
+		Status:               o.Status,
+		MissingAtOrigin:      []Item{},
+		ExtraAtOrigin:        []Item{},
+		MissingAtDestination: []Item{},
+		ExtraAtDestination:   []Item{},
+	}
+	shipped := o.Status == StatusInTransit || o.Status == StatusReceived || o.ShippedAt != nil
+	for _, it := range o.Items {
+		switch {
+		case it.Expected && it.OriginScannedAt == nil:
+			r.MissingAtOrigin = append(r.MissingAtOrigin, it)
+		case !it.Expected && it.OriginScannedAt != nil:
+			r.ExtraAtOrigin = append(r.ExtraAtOrigin, it)
+		}
+		if !shipped {
+			continue
+		}
+		switch {
+		case it.Expected && it.DestinationScannedAt == nil:
+			r.MissingAtDestination = append(r.MissingAtDestination, it)
+		case !it.Expected && it.DestinationScannedAt != nil:
+			r.ExtraAtDestination = append(r.ExtraAtDestination, it)
+		}
+	}
+	return r
+}
@@ -0,0 +1,60 @@
+package transferorder
+
+import (
+	"testing"
+	"time"
+)
+
+func ids(items []Item) []int {
+	out := []int{}
+	for _, it := range items {
+		out = append(out, it.AssetID)
+	}
+	return out
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDiscrepancies(t *testing.T) {
+	now := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	items := []Item{
+		{AssetID: 1, Expected: true, OriginScannedAt: &now, DestinationScannedAt: &now},
+		{AssetID: 2, Expected: true, OriginScannedAt: &now},
+		{AssetID: 3, Expected: true},
+		{AssetID: 4, Expected: false, OriginScannedAt: &now, DestinationScannedAt: &now},
+		{AssetID: 5, Expected: false, DestinationScannedAt: &now},
+	}
+
+	r := Discrepancies(Order{ID: 9, Status: StatusReceived, Items: items})
+	if !equal(ids(r.MissingAtOrigin), []int{3}) {
+		t.Errorf("missing at origin = %v", ids(r.MissingAtOrigin))
+	}
+	if !equal(ids(r.ExtraAtOrigin), []int{4}) {
+		t.Errorf("extra at origin = %v", ids(r.ExtraAtOrigin))
+	}
+	if !equal(ids(r.MissingAtDestination), []int{2, 3}) {
+		t.Errorf("missing at destination = %v", ids(r.MissingAtDestination))
+	}
+	if !equal(ids(r.ExtraAtDestination), []int{4, 5}) {
+		t.Errorf("extra at destination = %v", ids(r.ExtraAtDestination))
+	}
+
+	// Until the order ships, nothing is missing at the destination yet.
+	r = Discrepancies(Order{ID: 9, Status: StatusOpen, Items: items[:3]})
+	if len(r.MissingAtDestination) != 0 || len(r.ExtraAtDestination) != 0 {
+		t.Errorf("open order reports destination discrepancies: %+v", r)
+	}
+	if r.MissingAtDestination == nil {
+		t.Error("lists should be empty, not null")
+	}
+}
@@ -0,0 +1,23 @@
+package user
+
+// Preferences are a user's personal settings, stored in users.settings.
+// Every field is optional; unset means the app's default.
+type Preferences struct {
+	// Timezone is the IANA zone times are shown in; unset uses the browser's.
+	Timezone string `json:"timezone,omitempty" validate:"omitempty,max=64" example:"America/Chicago"`
+	// Locale overrides the browser's Accept-Language (one of i18n.Supported).
+	Locale string `json:"locale,omitempty" validate:"omitempty,max=16" example:"es"`
+	// Notifications selects the channels notifications are delivered on;
+	// unset keeps every channel on.
+	Notifications *NotificationChannels `json:"notifications,omitempty"`
+	// DefaultOrgID is the org selected at sign-in; the user must belong to it.
+	DefaultOrgID *int `json:"default_org_id,omitempty" validate:"omitempty,min=1" example:"42"`
+	// TableDensities maps a table's key (e.g. "assets") to its row density.
+	TableDensities map[string]string `json:"table_densities,omitempty" validate:"omitempty,max=50,dive,keys,min=1,max=64,endkeys,oneof=compact comfortable spacious"`
+}
+
+// NotificationChannels turns each notification channel on or off.
+type NotificationChannels struct {
+	Email bool `json:"email"`
+	InApp bool `json:"in_app"`
+}
@@ -8,18 +8,20 @@ import (
 
 // User represents a user entity
 type User struct {
-	ID           int        `json:"id"`
-	Email        string     `json:"email"`
-	Name         string     `json:"name"`
-	PasswordHash string     `json:"-"` // Never expose in JSON
-	LastLoginAt  *time.Time `json:"last_login_at"`
-	Settings     any        `json:"settings"` // JSONB
-	Metadata     any        `json:"metadata"` // JSONB
-	CreatedAt    time.Time  `json:"created_at"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID           int         `json:"id"`
+	Email        string      `json:"email"`
+	Name         string      `json:"name"`
+	PasswordHash string      `json:"-"` // Never expose in JSON
+	LastLoginAt  *time.Time  `json:"last_login_at"`
+	Settings     Preferences `json:"settings"` // JSONB
+	Metadata     any         `json:"metadata"` // JSONB
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
 	// RBAC fields
 	IsSuperadmin bool `json:"is_superadmin"`
 	LastOrgID    *int `json:"last_org_id,omitempty"`
+	// AvatarURL is the public URL of the uploaded avatar; nil when unset.
+	AvatarURL *string `json:"avatar_url"`
 }
 
 // CreateUserRequest for POST /api/v1/users
@@ -35,8 +37,29 @@ type UpdateUserRequest struct {
 	Email *string `json:"email" validate:"omitempty,email"`
 }
 
+// UpdateProfileRequest for PATCH /api/v1/me/profile. Omitted fields are
+// left unchanged; email and password have their own confirmed flows.
+type UpdateProfileRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=255"`
+}
+
 // UserListResponse for GET /api/v1/users
 type UserListResponse struct {
 	Data       []User            `json:"data"`
 	Pagination shared.Pagination `json:"pagination"`
 }
+
+// ListFields are the User keys the users list `fields=` parameter can select.
+var ListFields = []string{
+	"id", "email", "name", "last_login_at", "settings", "metadata",
+	"created_at", "updated_at", "is_superadmin", "last_org_id", "avatar_url",
+}
+
+// ListSorts are the fields the users list `sort=` parameter accepts.
+var ListSorts = []string{"email", "name", "created_at", "updated_at", "last_login_at"}
+
+// ListSort is one (field, direction) entry.
+type ListSort struct {
+	Field string
+	Desc  bool
+}
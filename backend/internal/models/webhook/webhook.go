@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Endpoint is an org's registered webhook receiver. Secret is the HMAC signing
+// key; it is returned once on creation and never serialized afterwards.
+type Endpoint struct {
+	ID         int       `json:"id"`
+	OrgID      int       `json:"org_id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"`
+	EventTypes []string  `json:"event_types"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CreateEndpointRequest is the POST /api/v1/orgs/{id}/webhooks body. An empty
+// EventTypes subscribes to every event type.
+type CreateEndpointRequest struct {
+	URL        string   `json:"url"         validate:"required,url,max=2048"`
+	EventTypes []string `json:"event_types" validate:"omitempty,dive,required"`
+}
+
+// CreateEndpointResponse is returned once from POST; Secret is never shown
+// again.
+type CreateEndpointResponse struct {
+	Endpoint
+	Secret string `json:"secret"`
+}
+
+// Delivery statuses.
+const (
+	StatusPending   = "pending"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+)
+
+// Delivery is one queued POST of an event to an endpoint, as claimed by the
+// delivery job (URL and Secret are joined from the endpoint). ReplayOf is set
+// on a manual re-delivery.
+type Delivery struct {
+	ID         int64
+	OrgID      int
+	EndpointID int
+	EventType  string
+	Payload    []byte
+	Attempts   int
+	ReplayOf   *int64
+	URL        string
+	Secret     string
+}
+
+// Attempt is the outcome of one delivery POST. Signature and SentAt record
+// what was sent so the delivery log can show it.
+type Attempt struct {
+	StatusCode int    // 0 when no response was received
+	Err        string // empty on success
+	Signature  string // X-TrakRF-Signature header value
+	SentAt     time.Time
+}
+
+// Succeeded reports whether the receiver acknowledged with a 2xx.
+func (a Attempt) Succeeded() bool {
+	return a.Err == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// DeliveryLogEntry is one row of the org-facing delivery log. Payload is only
+// populated on the single-delivery read; the list omits it to stay small.
+type DeliveryLogEntry struct {
+	ID             int64           `json:"id"`
+	EndpointID     int             `json:"endpoint_id"`
+	EndpointURL    string          `json:"endpoint_url"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastStatusCode *int            `json:"last_status_code"`
+	LastError      *string         `json:"last_error"`
+	LastSignature  *string         `json:"last_signature"`
+	LastAttemptAt  *time.Time      `json:"last_attempt_at"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at"`
+	ReplayOf       *int64          `json:"replay_of"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Payload        json.RawMessage `json:"payload,omitempty" swaggertype:"object"`
+}
+
+// DeliveryLogFilter narrows the delivery log. Zero values do not filter.
+// From/To bound created_at, the time the event was queued.
+type DeliveryLogFilter struct {
+	EndpointID int
+	EventType  string
+	Status     string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
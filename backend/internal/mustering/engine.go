@@ -648,8 +648,7 @@ func (e *Engine) computeReport(ctx context.Context, orgID int, ev *muster.Event)
 		}
 		a.expected++
 
-		accounted := en.Status != "missing"
-		if accounted {
+		if en.Accounted() {
 			a.accounted++
 			if ts := accountedTimestamp(en); ts != nil {
 				if a.maxTS == nil || ts.After(*a.maxTS) {
@@ -0,0 +1,167 @@
+// Package objectstore keeps user-uploaded files (avatars, condition photos)
+// in an S3-compatible bucket. Objects are written once under a fresh key and
+// served straight from the bucket's public URL, so the API never proxies
+// their bytes.
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/util/awssig"
+)
+
+// Store puts and deletes objects by key.
+type Store interface {
+	// Put writes body under key with the given Content-Type.
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL is the public URL key is served from.
+	URL(key string) string
+	// KeyForURL reverses URL, reporting false for URLs outside this store.
+	KeyForURL(rawURL string) (string, bool)
+}
+
+// Config configures the S3 store.
+type Config struct {
+	Bucket string
+	Region string
+	// Endpoint is the S3 API base URL; buckets are addressed path-style under
+	// it, which every S3-compatible service (MinIO, R2) accepts.
+	Endpoint string
+	// PublicURL is the base objects are served from, e.g. a CDN in front of
+	// the bucket.
+	PublicURL   string
+	Credentials awssig.Credentials
+}
+
+// FromEnv builds the store from:
+//
+//	OBJECT_STORE_BUCKET      bucket name (unset disables uploads)
+//	OBJECT_STORE_REGION      region (or AWS_REGION)
+//	OBJECT_STORE_ENDPOINT    S3 API URL        (default https://s3.<region>.amazonaws.com)
+//	OBJECT_STORE_PUBLIC_URL  object base URL   (default <endpoint>/<bucket>)
+//	AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN
+//
+// It returns nil when no bucket is set, which disables the endpoints that
+// upload. A bucket without a region or credentials, or a malformed URL, is an
+// error, so the server refuses to boot instead of failing every upload.
+func FromEnv() (Store, error) {
+	c := Config{
+		Bucket:    strings.TrimSpace(os.Getenv("OBJECT_STORE_BUCKET")),
+		Region:    os.Getenv("OBJECT_STORE_REGION"),
+		Endpoint:  strings.TrimRight(os.Getenv("OBJECT_STORE_ENDPOINT"), "/"),
+		PublicURL: strings.TrimRight(os.Getenv("OBJECT_STORE_PUBLIC_URL"), "/"),
+		Credentials: awssig.Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		},
+	}
+	if c.Bucket == "" {
+		return nil, nil
+	}
+	if c.Region == "" {
+		c.Region = os.Getenv("AWS_REGION")
+	}
+	if c.Region == "" {
+		return nil, fmt.Errorf("OBJECT_STORE_REGION or AWS_REGION must be set when OBJECT_STORE_BUCKET is")
+	}
+	if c.Credentials.AccessKeyID == "" || c.Credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set when OBJECT_STORE_BUCKET is")
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = "https://s3." + c.Region + ".amazonaws.com"
+	}
+	for name, v := range map[string]string{"OBJECT_STORE_ENDPOINT": c.Endpoint, "OBJECT_STORE_PUBLIC_URL": c.PublicURL} {
+		if v == "" {
+			continue
+		}
+		if u, err := url.Parse(v); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%s must be an http(s) URL, got %q", name, v)
+		}
+	}
+	return NewS3(c), nil
+}
+
+type s3Store struct {
+	cfg    Config
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3 returns a Store backed by the S3 REST API.
+func NewS3(cfg Config) Store {
+	if cfg.PublicURL == "" {
+		cfg.PublicURL = cfg.Endpoint + "/" + cfg.Bucket
+	}
+	return &s3Store{cfg: cfg, client: &http.Client{Timeout: 30 * time.Second}, now: time.Now}
+}
+
+func (s *s3Store) objectURL(key string) string {
+	return s.cfg.Endpoint + "/" + s.cfg.Bucket + "/" + escapeKey(key)
+}
+
+func (s *s3Store) do(ctx context.Context, method, key, contentType string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build s3 request: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", awssig.PayloadHash(body))
+	awssig.Sign(req, body, s.cfg.Credentials, s.cfg.Region, "s3", s.now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("s3 %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 && !(method == http.MethodDelete && resp.StatusCode == http.StatusNotFound) {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("s3 %s %s: status %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key, contentType string, body []byte) error {
+	return s.do(ctx, http.MethodPut, key, contentType, body)
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, http.MethodDelete, key, "", nil)
+}
+
+func (s *s3Store) URL(key string) string {
+	return s.cfg.PublicURL + "/" + escapeKey(key)
+}
+
+func (s *s3Store) KeyForURL(rawURL string) (string, bool) {
+	rest, ok := strings.CutPrefix(rawURL, s.cfg.PublicURL+"/")
+	if !ok || rest == "" {
+		return "", false
+	}
+	key, err := url.PathUnescape(rest)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// escapeKey percent-encodes each path segment of key, keeping the slashes.
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
@@ -0,0 +1,108 @@
+package objectstore
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/util/awssig"
+)
+
+func TestFromEnv_DisabledWithoutBucket(t *testing.T) {
+	t.Setenv("OBJECT_STORE_BUCKET", "")
+	s, err := FromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestFromEnv_RequiresRegionAndCredentials(t *testing.T) {
+	t.Setenv("OBJECT_STORE_BUCKET", "avatars")
+	t.Setenv("OBJECT_STORE_REGION", "")
+	t.Setenv("AWS_REGION", "")
+	_, err := FromEnv()
+	assert.ErrorContains(t, err, "OBJECT_STORE_REGION")
+
+	t.Setenv("AWS_REGION", "us-east-2")
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	_, err = FromEnv()
+	assert.ErrorContains(t, err, "AWS_ACCESS_KEY_ID")
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("OBJECT_STORE_ENDPOINT", "minio:9000")
+	_, err = FromEnv()
+	assert.ErrorContains(t, err, "OBJECT_STORE_ENDPOINT")
+
+	t.Setenv("OBJECT_STORE_ENDPOINT", "")
+	s, err := FromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "https://s3.us-east-2.amazonaws.com/avatars/a/b.png", s.URL("a/b.png"))
+}
+
+func TestS3Store_PutAndDelete(t *testing.T) {
+	var gotMethod, gotPath, gotType, gotAuth, gotHash, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath = r.Method, r.URL.EscapedPath()
+		gotType = r.Header.Get("Content-Type")
+		gotAuth = r.Header.Get("Authorization")
+		gotHash = r.Header.Get("X-Amz-Content-Sha256")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	s := NewS3(Config{
+		Bucket:      "uploads",
+		Region:      "us-east-1",
+		Endpoint:    srv.URL,
+		PublicURL:   "https://cdn.example.com",
+		Credentials: awssig.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"},
+	})
+
+	require.NoError(t, s.Put(context.Background(), "avatars/7/a b.png", "image/png", []byte("png")))
+	assert.Equal(t, http.MethodPut, gotMethod)
+	assert.Equal(t, "/uploads/avatars/7/a%20b.png", gotPath)
+	assert.Equal(t, "image/png", gotType)
+	assert.Equal(t, "png", gotBody)
+	assert.Equal(t, awssig.PayloadHash([]byte("png")), gotHash)
+	assert.True(t, strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKID/"))
+	assert.Contains(t, gotAuth, "/us-east-1/s3/aws4_request")
+
+	require.NoError(t, s.Delete(context.Background(), "avatars/7/a b.png"))
+	assert.Equal(t, http.MethodDelete, gotMethod)
+
+	u := s.URL("avatars/7/a b.png")
+	assert.Equal(t, "https://cdn.example.com/avatars/7/a%20b.png", u)
+	key, ok := s.KeyForURL(u)
+	assert.True(t, ok)
+	assert.Equal(t, "avatars/7/a b.png", key)
+	_, ok = s.KeyForURL("https://elsewhere.example.com/avatars/7/x.png")
+	assert.False(t, ok)
+}
+
+func TestS3Store_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+	}))
+	defer srv.Close()
+
+	s := NewS3(Config{Bucket: "b", Region: "us-east-1", Endpoint: srv.URL})
+	err := s.Put(context.Background(), "k", "image/png", []byte("x"))
+	assert.ErrorContains(t, err, "status 403")
+	assert.ErrorContains(t, err, "AccessDenied")
+	assert.NoError(t, s.Delete(context.Background(), "k"), "deleting a missing object is not an error")
+}
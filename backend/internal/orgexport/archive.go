@@ -0,0 +1,124 @@
+package orgexport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// MaxArchiveBytes bounds the decompressed size of an archive read by
+// ReadArchive, so a small upload cannot inflate without limit.
+const MaxArchiveBytes = 256 << 20
+
+// ErrInvalidArchive wraps every reason ReadArchive rejects its input.
+var ErrInvalidArchive = errors.New("invalid export archive")
+
+// importDocument is the part of a JSON archive an import reads. Other tables
+// are skipped by the decoder without being kept.
+type importDocument struct {
+	FormatVersion *int `json:"format_version"`
+	OrgID         int  `json:"org_id"`
+	Tables        struct {
+		Locations []organization.ImportLocation `json:"locations"`
+		Assets    []organization.ImportAsset    `json:"assets"`
+		Tags      []organization.ImportTag      `json:"tags"`
+	} `json:"tables"`
+}
+
+// ReadArchive reads a JSON export archive, gzipped or not, into the data an
+// import applies: deleted rows are dropped and locations are ordered parents
+// first. SQL archives are not accepted; they restore with psql instead.
+func ReadArchive(r io.Reader) (*organization.ImportData, error) {
+	br := bufio.NewReader(r)
+	var src io.Reader = br
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+		defer gz.Close()
+		src = gz
+	}
+	limited := &io.LimitedReader{R: src, N: MaxArchiveBytes + 1}
+
+	var doc importDocument
+	if err := json.NewDecoder(limited).Decode(&doc); err != nil {
+		if limited.N <= 0 {
+			return nil, fmt.Errorf("%w: larger than %d bytes uncompressed", ErrInvalidArchive, MaxArchiveBytes)
+		}
+		// %w on both, so callers can still spot a read error such as an
+		// oversized request body.
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	if doc.FormatVersion == nil {
+		return nil, fmt.Errorf("%w: not a JSON export archive (no format_version)", ErrInvalidArchive)
+	}
+	if *doc.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("%w: format_version %d is not supported (want %d)", ErrInvalidArchive, *doc.FormatVersion, FormatVersion)
+	}
+
+	data := &organization.ImportData{SourceOrgID: doc.OrgID}
+	for _, a := range doc.Tables.Assets {
+		if a.DeletedAt == nil {
+			data.Assets = append(data.Assets, a)
+		}
+	}
+	for _, t := range doc.Tables.Tags {
+		if t.DeletedAt == nil {
+			data.Tags = append(data.Tags, t)
+		}
+	}
+	var live []organization.ImportLocation
+	for _, l := range doc.Tables.Locations {
+		if l.DeletedAt == nil {
+			live = append(live, l)
+		}
+	}
+	data.Locations = parentsFirst(live)
+	return data, nil
+}
+
+// parentsFirst orders locations so each comes after its parent. A parent
+// that is not among them (deleted in the source) is cleared, as is one on a
+// cycle, so every location still lands somewhere.
+func parentsFirst(locs []organization.ImportLocation) []organization.ImportLocation {
+	byID := make(map[int]int, len(locs))
+	for i, l := range locs {
+		byID[l.ID] = i
+	}
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(locs))
+	out := make([]organization.ImportLocation, 0, len(locs))
+
+	var visit func(i int)
+	visit = func(i int) {
+		state[i] = visiting
+		if p := locs[i].ParentLocationID; p != nil {
+			j, ok := byID[*p]
+			switch {
+			case !ok || state[j] == visiting:
+				locs[i].ParentLocationID = nil
+			case state[j] == unvisited:
+				visit(j)
+			}
+		}
+		state[i] = done
+		out = append(out, locs[i])
+	}
+	for i := range locs {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+	return out
+}
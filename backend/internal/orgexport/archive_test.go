@@ -0,0 +1,87 @@
+package orgexport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+func TestReadArchive_RoundTripsAnExport(t *testing.T) {
+	store := newFakeStore(organization.Export{ID: 5, OrgID: 1, Format: organization.ExportFormatJSON})
+	store.order = []string{"organizations", "locations", "assets", "tags", "asset_scans"}
+	store.tables = map[string][]string{
+		"organizations": {`{"id": 1, "name": "Acme"}`},
+		"locations": {
+			`{"id": 30, "external_key": "bay-1", "name": "Bay 1", "parent_location_id": 20, "valid_from": "2026-01-01T00:00:00+00:00", "is_active": true, "metadata": {}}`,
+			`{"id": 20, "external_key": "wh", "name": "Warehouse", "parent_location_id": null, "valid_from": "2026-01-01T00:00:00+00:00", "is_active": true, "metadata": {"zone": "A"}}`,
+			`{"id": 25, "external_key": "old", "name": "Old", "valid_from": "2026-01-01T00:00:00+00:00", "deleted_at": "2026-02-01T00:00:00+00:00"}`,
+		},
+		"assets": {
+			`{"id": 10, "external_key": "cart-1", "name": "Cart", "valid_from": "2026-01-01T00:00:00+00:00", "is_active": true, "metadata": {"color": "red"}, "owner_user_id": 99}`,
+		},
+		"tags": {
+			`{"id": 40, "type": "rfid", "value": "E2801160", "asset_id": 10, "location_id": null, "valid_from": "2026-01-01T00:00:00+00:00", "is_active": true}`,
+		},
+		"asset_scans": {`{"asset_id": 10, "timestamp": "2026-03-01T00:00:00+00:00"}`},
+	}
+	require.NoError(t, testJob(store).Run(context.Background()))
+
+	data, err := ReadArchive(bytes.NewReader(store.completed[5]))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, data.SourceOrgID)
+	require.Len(t, data.Locations, 2, "deleted locations are dropped")
+	assert.Equal(t, "wh", data.Locations[0].ExternalKey, "parents come first")
+	assert.Equal(t, "bay-1", data.Locations[1].ExternalKey)
+	assert.JSONEq(t, `{"zone": "A"}`, string(data.Locations[0].Metadata))
+	require.Len(t, data.Assets, 1)
+	assert.Equal(t, 10, data.Assets[0].ID)
+	assert.JSONEq(t, `{"color": "red"}`, string(data.Assets[0].Metadata))
+	require.Len(t, data.Tags, 1)
+	assert.Equal(t, 10, *data.Tags[0].AssetID)
+}
+
+func TestReadArchive_AcceptsUncompressedJSON(t *testing.T) {
+	data, err := ReadArchive(strings.NewReader(`{"format_version": 1, "org_id": 7, "tables": {"assets": []}}`))
+	require.NoError(t, err)
+	assert.Equal(t, 7, data.SourceOrgID)
+}
+
+func TestReadArchive_RejectsOtherInput(t *testing.T) {
+	for name, body := range map[string]string{
+		"sql archive":         "-- TrakRF organization export\nBEGIN;\n",
+		"no format_version":   `{"tables": {}}`,
+		"unsupported version": `{"format_version": 99, "tables": {}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadArchive(strings.NewReader(body))
+			assert.True(t, errors.Is(err, ErrInvalidArchive), "err = %v", err)
+		})
+	}
+}
+
+func TestParentsFirst_ClearsMissingAndCyclicParents(t *testing.T) {
+	p := func(id int) *int { return &id }
+	out := parentsFirst([]organization.ImportLocation{
+		{ID: 1, ParentLocationID: p(2)},
+		{ID: 2, ParentLocationID: p(1)},
+		{ID: 3, ParentLocationID: p(404)},
+	})
+
+	require.Len(t, out, 3)
+	seen := map[int]bool{}
+	for _, l := range out {
+		if l.ParentLocationID != nil {
+			assert.True(t, seen[*l.ParentLocationID], "location %d placed before its parent", l.ID)
+		}
+		seen[l.ID] = true
+	}
+	assert.Nil(t, out[2].ParentLocationID)
+}
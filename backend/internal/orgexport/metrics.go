@@ -0,0 +1,19 @@
+package orgexport
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	metricExports = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "org_exports_total",
+		Help: "Org data exports built, by format and outcome.",
+	}, []string{"format", "status"}) // status: completed, failed
+
+	metricArchiveBytes = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "org_export_archive_bytes",
+		Help:    "Size of completed org export archives (gzipped).",
+		Buckets: prometheus.ExponentialBuckets(1<<10, 4, 10), // 1 KiB .. 256 GiB
+	})
+)
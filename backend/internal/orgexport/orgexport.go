@@ -0,0 +1,292 @@
+// Package orgexport builds org data exports (full backups). The Job's Run
+// claims queued exports one at a time, dumps the org's rows through storage
+// into a gzipped JSON document or SQL script, and stores the archive for
+// download. It also deletes exports past their expiry. ReadArchive reads a
+// JSON archive back for an import into another org.
+//
+// A claim is a lease: an export whose replica dies mid-build is claimed again
+// once the lease runs out, so every queued export is eventually built.
+package orgexport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+const (
+	// FormatVersion is written into every archive so an importer can tell
+	// which layout it is reading.
+	FormatVersion = 1
+	// claimLease must outlast building the largest org's archive.
+	claimLease = 30 * time.Minute
+	// Retention of a finished export: long enough to download it after the
+	// notification, short enough that copies of a whole org do not pile up.
+	completedTTL = 7 * 24 * time.Hour
+	failedTTL    = 24 * time.Hour
+	// maxErrorLen bounds the error text kept on a failed export.
+	maxErrorLen = 1024
+)
+
+// Store is the storage surface the job needs; *storage.Storage satisfies it.
+type Store interface {
+	ClaimOrgExport(ctx context.Context, lease time.Duration) (*organization.Export, error)
+	SchemaVersion(ctx context.Context) (version uint, dirty bool, err error)
+	DumpOrgData(ctx context.Context, orgID int, w storage.OrgDumpWriter) error
+	CompleteOrgExport(ctx context.Context, id int, archive []byte, rowCount int64, expiresAt time.Time) error
+	FailOrgExport(ctx context.Context, id int, msg string, expiresAt time.Time) error
+	PruneOrgExports(ctx context.Context) (int64, error)
+}
+
+// Job is the org_export job.
+type Job struct {
+	store Store
+	log   zerolog.Logger
+	now   func() time.Time
+}
+
+// NewJob builds the export job over store.
+func NewJob(store Store, log *zerolog.Logger) *Job {
+	return &Job{
+		store: store,
+		log:   log.With().Str("component", "orgexport").Logger(),
+		now:   time.Now,
+	}
+}
+
+// Run is the job run: build queued exports until none are waiting, then prune
+// expired ones. An export's own failure is recorded on it, not returned; only
+// storage errors fail the job.
+func (j *Job) Run(ctx context.Context) error {
+	for {
+		e, err := j.store.ClaimOrgExport(ctx, claimLease)
+		if err != nil {
+			return err
+		}
+		if e == nil {
+			break
+		}
+		if err := j.export(ctx, *e); err != nil {
+			return err
+		}
+	}
+
+	pruned, err := j.store.PruneOrgExports(ctx)
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		j.log.Info().Int64("exports", pruned).Msg("pruned expired org exports")
+	}
+	return nil
+}
+
+func (j *Job) export(ctx context.Context, e organization.Export) error {
+	archive, rows, err := j.build(ctx, e)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Shutting down: leave the claim to lapse so another run retries.
+			return ctx.Err()
+		}
+		metricExports.WithLabelValues(e.Format, organization.ExportStatusFailed).Inc()
+		j.log.Warn().Err(err).Int("export_id", e.ID).Int("org_id", e.OrgID).Msg("org export failed")
+		msg := err.Error()
+		if len(msg) > maxErrorLen {
+			msg = msg[:maxErrorLen]
+		}
+		return j.store.FailOrgExport(ctx, e.ID, msg, j.now().Add(failedTTL))
+	}
+
+	metricExports.WithLabelValues(e.Format, organization.ExportStatusCompleted).Inc()
+	metricArchiveBytes.Observe(float64(len(archive)))
+	j.log.Info().Int("export_id", e.ID).Int("org_id", e.OrgID).Str("format", e.Format).
+		Int64("rows", rows).Int("bytes", len(archive)).Msg("org export completed")
+	return j.store.CompleteOrgExport(ctx, e.ID, archive, rows, j.now().Add(completedTTL))
+}
+
+// build writes e's archive and returns it with the number of rows it holds.
+func (j *Job) build(ctx context.Context, e organization.Export) ([]byte, int64, error) {
+	version, _, err := j.store.SchemaVersion(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	h := header{
+		FormatVersion: FormatVersion,
+		SchemaVersion: version,
+		OrgID:         e.OrgID,
+		ExportID:      e.ID,
+		ExportedAt:    j.now().UTC(),
+	}
+	var w archiveWriter
+	switch e.Format {
+	case organization.ExportFormatSQL:
+		w = &sqlWriter{w: gz}
+	default:
+		w = &jsonWriter{w: gz}
+	}
+
+	if err := w.begin(h); err != nil {
+		return nil, 0, err
+	}
+	if err := j.store.DumpOrgData(ctx, e.OrgID, w); err != nil {
+		return nil, 0, err
+	}
+	if err := w.end(); err != nil {
+		return nil, 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), w.rowCount(), nil
+}
+
+// header describes an archive; it is the JSON document's top-level fields and
+// the SQL script's leading comment.
+type header struct {
+	FormatVersion int       `json:"format_version"`
+	SchemaVersion uint      `json:"schema_version"`
+	OrgID         int       `json:"org_id"`
+	ExportID      int       `json:"export_id"`
+	ExportedAt    time.Time `json:"exported_at"`
+}
+
+type archiveWriter interface {
+	storage.OrgDumpWriter
+	begin(h header) error
+	end() error
+	rowCount() int64
+}
+
+// jsonWriter streams one JSON document:
+//
+//	{"format_version":1, ..., "tables":{"assets":[{...},...], ...}}
+type jsonWriter struct {
+	w      io.Writer
+	tables int
+	rows   int64
+	// inTable counts the rows written to the open table.
+	inTable int
+}
+
+func (jw *jsonWriter) begin(h header) error {
+	b, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	// Reopen the header object to append the tables map.
+	_, err = fmt.Fprintf(jw.w, `%s,"tables":{`, b[:len(b)-1])
+	return err
+}
+
+func (jw *jsonWriter) BeginTable(name string) error {
+	sep := ""
+	if jw.tables > 0 {
+		sep = "],"
+	}
+	jw.tables++
+	jw.inTable = 0
+	_, err := fmt.Fprintf(jw.w, "%s\n%q:[", sep, name)
+	return err
+}
+
+func (jw *jsonWriter) Row(row []byte) error {
+	if jw.inTable > 0 {
+		if _, err := io.WriteString(jw.w, ","); err != nil {
+			return err
+		}
+	}
+	jw.inTable++
+	jw.rows++
+	if _, err := io.WriteString(jw.w, "\n"); err != nil {
+		return err
+	}
+	_, err := jw.w.Write(row)
+	return err
+}
+
+func (jw *jsonWriter) end() error {
+	closing := "}}\n"
+	if jw.tables > 0 {
+		closing = "]}}\n"
+	}
+	_, err := io.WriteString(jw.w, closing)
+	return err
+}
+
+func (jw *jsonWriter) rowCount() int64 { return jw.rows }
+
+// sqlWriter streams a psql script that re-inserts every row. Rows are carried
+// as JSON and expanded with jsonb_populate_record, so every column type
+// round-trips without per-type quoting. Triggers are disabled for the
+// restore, as pg_dump --disable-triggers does, so rows keep their ids.
+type sqlWriter struct {
+	w     io.Writer
+	table string
+	rows  int64
+}
+
+func (sw *sqlWriter) begin(h header) error {
+	_, err := fmt.Fprintf(sw.w, `-- TrakRF organization export
+-- format_version: %d
+-- schema_version: %d
+-- org_id: %d
+-- export_id: %d
+-- exported_at: %s
+--
+-- Restore as a superuser into a database migrated to schema_version.
+-- Credentials (password hashes, API keys, tokens, webhook and connector
+-- secrets) are not exported.
+
+BEGIN;
+SET LOCAL session_replication_role = replica;
+`, h.FormatVersion, h.SchemaVersion, h.OrgID, h.ExportID, h.ExportedAt.Format(time.RFC3339))
+	return err
+}
+
+func (sw *sqlWriter) BeginTable(name string) error {
+	sw.table = name
+	_, err := fmt.Fprintf(sw.w, "\n-- %s\n", name)
+	return err
+}
+
+// Row inserts only the columns the row carries, so a table exported with a
+// column allowlist (users) restores with the defaults for the rest.
+func (sw *sqlWriter) Row(row []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(row, &fields); err != nil {
+		return fmt.Errorf("%s row: %w", sw.table, err)
+	}
+	cols := strings.Join(slices.Sorted(maps.Keys(fields)), ", ")
+	sw.rows++
+	_, err := fmt.Fprintf(sw.w,
+		"INSERT INTO trakrf.%[1]s (%[2]s) SELECT %[2]s FROM jsonb_populate_record(NULL::trakrf.%[1]s, %[3]s);\n",
+		sw.table, cols, quoteLiteral(string(row)))
+	return err
+}
+
+func (sw *sqlWriter) end() error {
+	_, err := io.WriteString(sw.w, "\nCOMMIT;\n")
+	return err
+}
+
+func (sw *sqlWriter) rowCount() int64 { return sw.rows }
+
+// quoteLiteral returns s as a standard-conforming SQL string literal.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
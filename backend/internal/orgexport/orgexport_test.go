@@ -0,0 +1,159 @@
+package orgexport
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+type fakeStore struct {
+	queue   []organization.Export
+	tables  map[string][]string
+	order   []string
+	dumpErr error
+
+	completed map[int][]byte
+	rowCounts map[int]int64
+	failed    map[int]string
+	pruned    bool
+}
+
+func newFakeStore(exports ...organization.Export) *fakeStore {
+	return &fakeStore{
+		queue: exports,
+		order: []string{"organizations", "assets", "tags"},
+		tables: map[string][]string{
+			"organizations": {`{"id": 1, "name": "Acme"}`},
+			"assets":        {`{"id": 10, "name": "O'Brien's cart"}`, `{"id": 11, "name": "Forklift"}`},
+		},
+		completed: map[int][]byte{},
+		rowCounts: map[int]int64{},
+		failed:    map[int]string{},
+	}
+}
+
+func (f *fakeStore) ClaimOrgExport(context.Context, time.Duration) (*organization.Export, error) {
+	if len(f.queue) == 0 {
+		return nil, nil
+	}
+	e := f.queue[0]
+	f.queue = f.queue[1:]
+	return &e, nil
+}
+
+func (f *fakeStore) SchemaVersion(context.Context) (uint, bool, error) { return 48, false, nil }
+
+func (f *fakeStore) DumpOrgData(_ context.Context, _ int, w storage.OrgDumpWriter) error {
+	if f.dumpErr != nil {
+		return f.dumpErr
+	}
+	for _, t := range f.order {
+		if err := w.BeginTable(t); err != nil {
+			return err
+		}
+		for _, r := range f.tables[t] {
+			if err := w.Row([]byte(r)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakeStore) CompleteOrgExport(_ context.Context, id int, archive []byte, rows int64, _ time.Time) error {
+	f.completed[id] = archive
+	f.rowCounts[id] = rows
+	return nil
+}
+
+func (f *fakeStore) FailOrgExport(_ context.Context, id int, msg string, _ time.Time) error {
+	f.failed[id] = msg
+	return nil
+}
+
+func (f *fakeStore) PruneOrgExports(context.Context) (int64, error) {
+	f.pruned = true
+	return 0, nil
+}
+
+func testJob(store Store) *Job {
+	log := zerolog.Nop()
+	j := NewJob(store, &log)
+	j.now = func() time.Time { return time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC) }
+	return j
+}
+
+func gunzip(t *testing.T, b []byte) []byte {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(b))
+	require.NoError(t, err)
+	out, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return out
+}
+
+func TestRun_JSONArchive(t *testing.T) {
+	store := newFakeStore(organization.Export{ID: 5, OrgID: 1, Format: organization.ExportFormatJSON})
+
+	require.NoError(t, testJob(store).Run(context.Background()))
+
+	require.Contains(t, store.completed, 5)
+	assert.Equal(t, int64(3), store.rowCounts[5])
+	assert.True(t, store.pruned)
+
+	var doc struct {
+		FormatVersion int                          `json:"format_version"`
+		SchemaVersion uint                         `json:"schema_version"`
+		OrgID         int                          `json:"org_id"`
+		Tables        map[string][]json.RawMessage `json:"tables"`
+	}
+	require.NoError(t, json.Unmarshal(gunzip(t, store.completed[5]), &doc))
+	assert.Equal(t, FormatVersion, doc.FormatVersion)
+	assert.Equal(t, uint(48), doc.SchemaVersion)
+	assert.Equal(t, 1, doc.OrgID)
+	assert.Len(t, doc.Tables["organizations"], 1)
+	assert.Len(t, doc.Tables["assets"], 2)
+	// Empty tables are present, so an importer can tell them from missing ones.
+	assert.Contains(t, doc.Tables, "tags")
+	assert.Empty(t, doc.Tables["tags"])
+}
+
+func TestRun_SQLArchive(t *testing.T) {
+	store := newFakeStore(organization.Export{ID: 6, OrgID: 1, Format: organization.ExportFormatSQL})
+
+	require.NoError(t, testJob(store).Run(context.Background()))
+
+	script := string(gunzip(t, store.completed[6]))
+	assert.Contains(t, script, "-- schema_version: 48\n")
+	assert.Contains(t, script, "BEGIN;\nSET LOCAL session_replication_role = replica;\n")
+	assert.Contains(t, script,
+		`INSERT INTO trakrf.assets (id, name) SELECT id, name FROM jsonb_populate_record(NULL::trakrf.assets, '{"id": 10, "name": "O''Brien''s cart"}');`)
+	assert.Contains(t, script, "\nCOMMIT;\n")
+	assert.Equal(t, int64(3), store.rowCounts[6])
+}
+
+func TestRun_DumpFailureIsRecordedOnTheExport(t *testing.T) {
+	store := newFakeStore(
+		organization.Export{ID: 7, OrgID: 1, Format: organization.ExportFormatJSON},
+		organization.Export{ID: 8, OrgID: 2, Format: organization.ExportFormatJSON},
+	)
+	store.dumpErr = errors.New("relation does not exist")
+
+	require.NoError(t, testJob(store).Run(context.Background()))
+
+	assert.Equal(t, "relation does not exist", store.failed[7])
+	assert.Equal(t, "relation does not exist", store.failed[8])
+	assert.Empty(t, store.completed)
+}
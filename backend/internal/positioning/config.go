@@ -0,0 +1,71 @@
+package positioning
+
+import (
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// Tuning is the resolved estimator configuration for one org: the system
+// defaults overlaid with the org's metadata.positioning settings.
+type Tuning struct {
+	SmoothingFactor float64
+	HysteresisDB    float64
+	MinDwell        time.Duration
+	Stale           time.Duration
+	Heartbeat       time.Duration
+}
+
+// DefaultTuning is the system tier: a fairly smooth average, a 6 dB margin
+// (roughly a doubling of distance indoors), 10s of dwell before a move is
+// believed, gateways forgotten after 30s of silence, and a 5 minute heartbeat.
+func DefaultTuning() Tuning {
+	return Tuning{
+		SmoothingFactor: 0.3,
+		HysteresisDB:    6,
+		MinDwell:        10 * time.Second,
+		Stale:           30 * time.Second,
+		Heartbeat:       5 * time.Minute,
+	}
+}
+
+// Resolve overlays the org's settings on the system defaults. Out-of-range
+// values are rejected by the settings handler, so they are not re-checked
+// here beyond ignoring a non-positive smoothing factor.
+func Resolve(s organization.PositioningSettings) Tuning {
+	t := DefaultTuning()
+	if s.SmoothingFactor != nil && *s.SmoothingFactor > 0 && *s.SmoothingFactor <= 1 {
+		t.SmoothingFactor = *s.SmoothingFactor
+	}
+	if s.HysteresisDB != nil {
+		t.HysteresisDB = float64(*s.HysteresisDB)
+	}
+	if s.MinDwellSeconds != nil {
+		t.MinDwell = time.Duration(*s.MinDwellSeconds) * time.Second
+	}
+	if s.StaleSeconds != nil {
+		t.Stale = time.Duration(*s.StaleSeconds) * time.Second
+	}
+	if s.HeartbeatSeconds != nil {
+		t.Heartbeat = time.Duration(*s.HeartbeatSeconds) * time.Second
+	}
+	return t
+}
+
+// SystemSettings renders DefaultTuning in the settings shape, so the UI can
+// show an unset field as "blank = system default (X)".
+func SystemSettings() organization.PositioningSettings {
+	t := DefaultTuning()
+	f := t.SmoothingFactor
+	h := int(t.HysteresisDB)
+	dwell := int(t.MinDwell.Seconds())
+	stale := int(t.Stale.Seconds())
+	hb := int(t.Heartbeat.Seconds())
+	return organization.PositioningSettings{
+		SmoothingFactor:  &f,
+		HysteresisDB:     &h,
+		MinDwellSeconds:  &dwell,
+		StaleSeconds:     &stale,
+		HeartbeatSeconds: &hb,
+	}
+}
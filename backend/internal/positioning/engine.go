@@ -0,0 +1,242 @@
+// Package positioning estimates which zone a BLE beacon is in from the RSSI
+// its gateways report. It sits on the ingest fan-out seam
+// (ingest.ReadEvaluator) beside geofence and mustering: for orgs with
+// positioning enabled, storage.PersistReads resolves BLE reads but does not
+// write them, and this engine writes one asset_scans row per zone change
+// (plus a periodic heartbeat) instead of one per advertisement.
+//
+// The gateway-to-zone mapping is the gateway's scan point (location_id, plus
+// an optional metadata.rssi_offset calibration). Each (beacon, gateway) pair
+// keeps an exponential moving average of RSSI; the loudest fresh gateway
+// scores its zone, and a challenger zone must beat the current one by a
+// hysteresis margin and hold the lead for a dwell time before the move is
+// written. Tuning is per org (organizations.metadata.positioning).
+//
+// State is in-memory and per-process, so single-replica only — the same
+// constraint as geofence/mustering/readstream (TRA-907). After a restart the
+// current zone is hydrated from the asset's latest scan, so a beacon that has
+// not moved does not produce a spurious move.
+package positioning
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+// cacheTTL bounds how long an org's settings and gateway zones are trusted
+// before a lazy refresh, so scan point and settings edits take effect within
+// half a minute without a DB round-trip per message.
+const cacheTTL = 30 * time.Second
+
+// engineStore is the storage surface the engine needs; *storage.Storage
+// satisfies it. Narrowed so engine_test.go can inject a fake.
+type engineStore interface {
+	GetPositioningSettings(ctx context.Context, orgID int) (*organization.PositioningSettings, error)
+	ListGatewayZones(ctx context.Context, orgID int) ([]storage.GatewayZone, error)
+	LastScanLocation(ctx context.Context, orgID, assetID int) (*int, error)
+	RecordPositionFix(ctx context.Context, orgID int, fix storage.PositionFix) (bool, error)
+}
+
+// orgState is the per-org cache plus the per-beacon tracks.
+type orgState struct {
+	mu sync.Mutex
+
+	enabled  bool
+	tuning   Tuning
+	zones    map[int]storage.GatewayZone // scan_point_id -> zone
+	loadedAt time.Time
+
+	tracks map[int]*track // asset_id -> track
+}
+
+// Engine implements ingest.ReadEvaluator.
+type Engine struct {
+	store engineStore
+	log   zerolog.Logger
+	now   func() time.Time
+
+	mu     sync.Mutex
+	states map[int]*orgState
+}
+
+// NewEngine builds an engine over real storage.
+func NewEngine(store *storage.Storage, log *zerolog.Logger) *Engine {
+	return newEngine(store, log)
+}
+
+// newEngine is the test-friendly constructor over the narrow interface.
+func newEngine(store engineStore, log *zerolog.Logger) *Engine {
+	return &Engine{
+		store:  store,
+		log:    log.With().Str("component", "positioning").Logger(),
+		now:    time.Now,
+		states: map[int]*orgState{},
+	}
+}
+
+func (e *Engine) state(orgID int) *orgState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	st := e.states[orgID]
+	if st == nil {
+		st = &orgState{tracks: map[int]*track{}}
+		e.states[orgID] = st
+	}
+	return st
+}
+
+// ensureLoaded refreshes the org's settings and zones once cacheTTL has
+// passed. On a lookup error the previous values are kept (and retried on the
+// next message); an org never loaded stays disabled.
+func (e *Engine) ensureLoaded(ctx context.Context, orgID int, st *orgState) {
+	if !st.loadedAt.IsZero() && e.now().Sub(st.loadedAt) < cacheTTL {
+		return
+	}
+	settings, err := e.store.GetPositioningSettings(ctx, orgID)
+	if err != nil {
+		e.log.Warn().Err(err).Int("org_id", orgID).Msg("positioning settings lookup failed")
+		metricErrors.Inc()
+		return
+	}
+	if settings == nil || !settings.Enabled {
+		st.enabled, st.loadedAt = false, e.now()
+		st.tracks = map[int]*track{}
+		return
+	}
+	zones, err := e.store.ListGatewayZones(ctx, orgID)
+	if err != nil {
+		e.log.Warn().Err(err).Int("org_id", orgID).Msg("gateway zone lookup failed")
+		metricErrors.Inc()
+		return
+	}
+	st.zones = make(map[int]storage.GatewayZone, len(zones))
+	for _, z := range zones {
+		st.zones[z.ScanPointID] = z
+	}
+	st.enabled, st.tuning, st.loadedAt = true, Resolve(*settings), e.now()
+}
+
+// Evaluate folds one message's BLE reads into the beacons' tracks and writes
+// any resulting fixes. Non-BLE reads, and reads without a usable RSSI or from
+// a gateway with no zone, are ignored. Never returns an error: writes are
+// best-effort and a failed write is retried on the beacon's next sample.
+func (e *Engine) Evaluate(ctx context.Context, orgID int, tagScanID int64, receivedAt time.Time, reads []storage.ResolvedRead) {
+	hasBLE := false
+	for _, rd := range reads {
+		if rd.BLE {
+			hasBLE = true
+			break
+		}
+	}
+	if !hasBLE {
+		return
+	}
+
+	st := e.state(orgID)
+	st.mu.Lock()
+	e.ensureLoaded(ctx, orgID, st)
+	if !st.enabled {
+		st.mu.Unlock()
+		return
+	}
+
+	touched := []int{}
+	for _, rd := range reads {
+		if !rd.BLE {
+			continue
+		}
+		metricSamples.Inc()
+		zone, ok := st.zones[rd.ScanPointID]
+		if rd.RSSI == 0 || !ok {
+			metricIgnored.Inc()
+			continue
+		}
+		tr := st.tracks[rd.AssetID]
+		if tr == nil {
+			tr = newTrack()
+			st.tracks[rd.AssetID] = tr
+		}
+		if !contains(touched, rd.AssetID) {
+			touched = append(touched, rd.AssetID)
+		}
+		tr.observe(rd.ScanPointID, zone.LocationID, float64(rd.RSSI)+zone.RSSIOffset, receivedAt, st.tuning)
+	}
+
+	var fixes []storage.PositionFix
+	for _, assetID := range touched {
+		tr := st.tracks[assetID]
+		if !tr.known {
+			loc, err := e.store.LastScanLocation(ctx, orgID, assetID)
+			if err != nil {
+				e.log.Warn().Err(err).Int("org_id", orgID).Int("asset_id", assetID).Msg("last scan location lookup failed")
+				metricErrors.Inc()
+				continue
+			}
+			tr.current, tr.known = loc, true
+			// The stored scan is as good as a fix just written: don't heartbeat
+			// straight away after a restart.
+			tr.lastWritten = receivedAt
+		}
+		d := tr.decide(receivedAt, st.tuning)
+		if !d.write {
+			continue
+		}
+		fixes = append(fixes, storage.PositionFix{
+			AssetID:        assetID,
+			LocationID:     d.location,
+			FromLocationID: d.from,
+			ScanPointID:    d.point,
+			RSSI:           d.rssi,
+			Move:           d.move,
+			TagScanID:      tagScanID,
+			At:             receivedAt,
+		})
+	}
+	st.mu.Unlock()
+
+	// Writes happen outside the org lock so DB latency never serializes the
+	// org's other messages behind this one.
+	for _, fix := range fixes {
+		if _, err := e.store.RecordPositionFix(ctx, orgID, fix); err != nil {
+			e.log.Error().Err(err).Int("org_id", orgID).Int("asset_id", fix.AssetID).Msg("position fix write failed")
+			metricErrors.Inc()
+			e.retryLater(orgID, fix.AssetID)
+			continue
+		}
+		if fix.Move {
+			metricFixes.WithLabelValues("move").Inc()
+			e.log.Debug().Int("org_id", orgID).Int("asset_id", fix.AssetID).
+				Int("location_id", fix.LocationID).Float64("rssi", fix.RSSI).Msg("beacon changed zone")
+		} else {
+			metricFixes.WithLabelValues("heartbeat").Inc()
+		}
+	}
+}
+
+// retryLater clears a track's lastWritten so its next sample rewrites the
+// current zone. The track already believes the move happened, so the retry
+// is written as a heartbeat for the new zone — the zone is right, only the
+// from/to detail of the lost event is gone.
+func (e *Engine) retryLater(orgID, assetID int) {
+	st := e.state(orgID)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if tr := st.tracks[assetID]; tr != nil {
+		tr.lastWritten = time.Time{}
+	}
+}
+
+func contains(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package positioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+)
+
+func ip(v int) *int { return &v }
+
+type fakeStore struct {
+	settings *organization.PositioningSettings
+	zones    []storage.GatewayZone
+	last     map[int]*int
+	fixes    []storage.PositionFix
+	writeErr error
+}
+
+func (f *fakeStore) GetPositioningSettings(context.Context, int) (*organization.PositioningSettings, error) {
+	return f.settings, nil
+}
+func (f *fakeStore) ListGatewayZones(context.Context, int) ([]storage.GatewayZone, error) {
+	return f.zones, nil
+}
+func (f *fakeStore) LastScanLocation(_ context.Context, _ int, assetID int) (*int, error) {
+	return f.last[assetID], nil
+}
+func (f *fakeStore) RecordPositionFix(_ context.Context, _ int, fix storage.PositionFix) (bool, error) {
+	if f.writeErr != nil {
+		return false, f.writeErr
+	}
+	f.fixes = append(f.fixes, fix)
+	return true, nil
+}
+
+// Gateways 1 and 2 cover zone 10 (gateway 2 hears 4 dB quiet and is
+// calibrated up); gateway 3 covers zone 20.
+func newTestEngine(settings organization.PositioningSettings) (*Engine, *fakeStore) {
+	store := &fakeStore{
+		settings: &settings,
+		zones: []storage.GatewayZone{
+			{ScanPointID: 1, LocationID: 10},
+			{ScanPointID: 2, LocationID: 10, RSSIOffset: 4},
+			{ScanPointID: 3, LocationID: 20},
+		},
+		last: map[int]*int{},
+	}
+	log := zerolog.Nop()
+	return newEngine(store, &log), store
+}
+
+var t0 = time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+
+func ble(assetID, scanPointID, rssi int) storage.ResolvedRead {
+	return storage.ResolvedRead{AssetID: assetID, ScanPointID: scanPointID, RSSI: rssi, BLE: true}
+}
+
+func TestEvaluate_WritesMovesNotNoise(t *testing.T) {
+	e, store := newTestEngine(organization.PositioningSettings{Enabled: true, MinDwellSeconds: ip(5)})
+	ctx := context.Background()
+	at := func(s int) time.Time { return t0.Add(time.Duration(s) * time.Second) }
+
+	// First sighting: zone 10 must hold for the dwell time before it is written.
+	e.Evaluate(ctx, 1, 100, at(0), []storage.ResolvedRead{ble(7, 1, -60), ble(7, 3, -80)})
+	assert.Empty(t, store.fixes)
+	e.Evaluate(ctx, 1, 101, at(5), []storage.ResolvedRead{ble(7, 1, -61)})
+	require.Len(t, store.fixes, 1)
+	fix := store.fixes[0]
+	assert.Equal(t, 10, fix.LocationID)
+	assert.Nil(t, fix.FromLocationID)
+	assert.Equal(t, 1, fix.ScanPointID)
+	assert.InDelta(t, -60.3, fix.RSSI, 0.001, "smoothed: 0.3*-61 + 0.7*-60")
+	assert.Equal(t, int64(101), fix.TagScanID)
+	assert.True(t, fix.Move)
+
+	// A single loud sample from zone 20 does not flip the zone: the average of
+	// gateway 3 is still within the hysteresis margin of zone 10.
+	e.Evaluate(ctx, 1, 102, at(6), []storage.ResolvedRead{ble(7, 3, -40)})
+	e.Evaluate(ctx, 1, 103, at(7), []storage.ResolvedRead{ble(7, 1, -62)})
+	assert.Len(t, store.fixes, 1, "noise is not written")
+
+	// Zone 20 stays clearly louder for the dwell time: one move is written.
+	for s := 8; s <= 14; s++ {
+		e.Evaluate(ctx, 1, int64(200+s), at(s), []storage.ResolvedRead{ble(7, 3, -45), ble(7, 1, -75)})
+	}
+	require.Len(t, store.fixes, 2)
+	assert.Equal(t, 20, store.fixes[1].LocationID)
+	assert.Equal(t, 10, *store.fixes[1].FromLocationID)
+	assert.True(t, store.fixes[1].Move)
+}
+
+func TestEvaluate_HydratesZoneAndHeartbeats(t *testing.T) {
+	e, store := newTestEngine(organization.PositioningSettings{Enabled: true, MinDwellSeconds: ip(0)})
+	store.last[7] = ip(10)
+	ctx := context.Background()
+
+	// After a restart the stored zone is the current one: no spurious move.
+	e.Evaluate(ctx, 1, 1, t0, []storage.ResolvedRead{ble(7, 2, -60)})
+	assert.Empty(t, store.fixes)
+
+	// Staying put only writes once the heartbeat interval has passed.
+	e.Evaluate(ctx, 1, 2, t0.Add(time.Minute), []storage.ResolvedRead{ble(7, 2, -60)})
+	assert.Empty(t, store.fixes)
+	e.Evaluate(ctx, 1, 3, t0.Add(5*time.Minute), []storage.ResolvedRead{ble(7, 2, -60)})
+	require.Len(t, store.fixes, 1)
+	assert.False(t, store.fixes[0].Move)
+	assert.Equal(t, 2, store.fixes[0].ScanPointID)
+}
+
+func TestEvaluate_FailedWriteIsRetried(t *testing.T) {
+	e, store := newTestEngine(organization.PositioningSettings{Enabled: true, MinDwellSeconds: ip(0)})
+	ctx := context.Background()
+
+	store.writeErr = errors.New("db down")
+	e.Evaluate(ctx, 1, 1, t0, []storage.ResolvedRead{ble(7, 3, -50)})
+	store.writeErr = nil
+	e.Evaluate(ctx, 1, 2, t0.Add(time.Second), []storage.ResolvedRead{ble(7, 3, -50)})
+	require.Len(t, store.fixes, 1)
+	assert.Equal(t, 20, store.fixes[0].LocationID)
+}
+
+func TestEvaluate_IgnoresWhenDisabledOrUnusable(t *testing.T) {
+	e, store := newTestEngine(organization.PositioningSettings{Enabled: false})
+	e.Evaluate(context.Background(), 1, 1, t0, []storage.ResolvedRead{ble(7, 1, -50)})
+	assert.Empty(t, store.fixes)
+
+	e, store = newTestEngine(organization.PositioningSettings{Enabled: true, MinDwellSeconds: ip(0)})
+	rfid := storage.ResolvedRead{AssetID: 7, ScanPointID: 1, RSSI: -50}
+	e.Evaluate(context.Background(), 1, 1, t0, []storage.ResolvedRead{
+		rfid,
+		ble(7, 1, 0),    // no usable RSSI
+		ble(7, 99, -50), // gateway without a zone
+	})
+	assert.Empty(t, store.fixes)
+}
+
+func TestResolve(t *testing.T) {
+	f := 0.5
+	tu := Resolve(organization.PositioningSettings{SmoothingFactor: &f, HysteresisDB: ip(3), StaleSeconds: ip(60)})
+	assert.Equal(t, 0.5, tu.SmoothingFactor)
+	assert.Equal(t, 3.0, tu.HysteresisDB)
+	assert.Equal(t, time.Minute, tu.Stale)
+	assert.Equal(t, DefaultTuning().MinDwell, tu.MinDwell)
+
+	bad := 0.0
+	assert.Equal(t, DefaultTuning().SmoothingFactor, Resolve(organization.PositioningSettings{SmoothingFactor: &bad}).SmoothingFactor)
+}
@@ -0,0 +1,29 @@
+package positioning
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Counters live on the default registry, which serve's /metrics handler exposes.
+var (
+	metricSamples = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "positioning_samples_total",
+		Help: "BLE reads evaluated by the positioning engine for orgs with positioning enabled.",
+	})
+
+	metricIgnored = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "positioning_samples_ignored_total",
+		Help: "BLE reads with no usable RSSI or from a gateway not mapped to a zone.",
+	})
+
+	metricFixes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "positioning_fixes_written_total",
+		Help: "Zone estimates written to asset_scans, by kind.",
+	}, []string{"kind"}) // move, heartbeat
+
+	metricErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "positioning_errors_total",
+		Help: "Lookup and write errors in the positioning engine (best-effort; do not block ingestion).",
+	})
+)
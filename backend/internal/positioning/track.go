@@ -0,0 +1,129 @@
+package positioning
+
+import "time"
+
+// gatewaySignal is one gateway's smoothed view of one beacon.
+type gatewaySignal struct {
+	locationID int
+	smoothed   float64 // dBm, offset-corrected
+	lastHeard  time.Time
+}
+
+// track is the estimator state for one beacon asset. It is a small state
+// machine: gateway samples feed per-gateway moving averages, the strongest
+// fresh zone is the candidate, and the candidate only becomes the asset's
+// zone once it beats the current zone by the hysteresis margin and holds
+// that lead for the dwell time.
+type track struct {
+	gateways map[int]*gatewaySignal // scan_point_id -> signal
+
+	// current is the last zone written (nil until the first fix). known is
+	// false until current has been hydrated from the latest stored scan.
+	current *int
+	known   bool
+	// lastWritten is when a fix for current was last written; zero forces a
+	// rewrite on the next sample (a failed write is retried this way).
+	lastWritten time.Time
+
+	candidate      *int
+	candidateSince time.Time
+}
+
+func newTrack() *track {
+	return &track{gateways: map[int]*gatewaySignal{}}
+}
+
+// observe folds one RSSI sample (already offset-corrected) from a gateway
+// into its moving average. A gateway that went stale restarts from the
+// sample rather than averaging against an outdated value.
+func (t *track) observe(scanPointID, locationID int, rssi float64, at time.Time, tu Tuning) {
+	g := t.gateways[scanPointID]
+	if g == nil || at.Sub(g.lastHeard) > tu.Stale || g.locationID != locationID {
+		t.gateways[scanPointID] = &gatewaySignal{locationID: locationID, smoothed: rssi, lastHeard: at}
+		return
+	}
+	g.smoothed = tu.SmoothingFactor*rssi + (1-tu.SmoothingFactor)*g.smoothed
+	g.lastHeard = at
+}
+
+// zoneScore is a zone's strength: its loudest fresh gateway.
+type zoneScore struct {
+	locationID  int
+	scanPointID int
+	rssi        float64
+}
+
+// scores returns the strength of every zone with a fresh gateway, and drops
+// gateways that have gone stale.
+func (t *track) scores(at time.Time, tu Tuning) map[int]zoneScore {
+	out := map[int]zoneScore{}
+	for id, g := range t.gateways {
+		if at.Sub(g.lastHeard) > tu.Stale {
+			delete(t.gateways, id)
+			continue
+		}
+		if best, ok := out[g.locationID]; !ok || g.smoothed > best.rssi ||
+			(g.smoothed == best.rssi && id < best.scanPointID) {
+			out[g.locationID] = zoneScore{locationID: g.locationID, scanPointID: id, rssi: g.smoothed}
+		}
+	}
+	return out
+}
+
+// decision is what decide wants written for the asset.
+type decision struct {
+	write    bool
+	move     bool // false: heartbeat for the unchanged zone
+	from     *int
+	location int
+	point    int
+	rssi     float64
+}
+
+// decide runs the zone state machine at time at. It mutates the track as if
+// the returned fix will be written; the caller clears lastWritten if the
+// write fails so the next sample retries it.
+func (t *track) decide(at time.Time, tu Tuning) decision {
+	scores := t.scores(at, tu)
+	var best *zoneScore
+	for _, s := range scores {
+		s := s
+		if best == nil || s.rssi > best.rssi || (s.rssi == best.rssi && s.locationID < best.locationID) {
+			best = &s
+		}
+	}
+	if best == nil {
+		return decision{}
+	}
+
+	if t.current != nil && *t.current == best.locationID {
+		t.candidate = nil
+		if at.Sub(t.lastWritten) < tu.Heartbeat {
+			return decision{}
+		}
+		t.lastWritten = at
+		return decision{write: true, location: best.locationID, point: best.scanPointID, rssi: best.rssi}
+	}
+
+	// A challenger must clear the margin over the current zone while that
+	// zone is still heard; once the current zone goes silent any zone wins.
+	if t.current != nil {
+		if cur, ok := scores[*t.current]; ok && best.rssi < cur.rssi+tu.HysteresisDB {
+			t.candidate = nil
+			return decision{}
+		}
+	}
+
+	if t.candidate == nil || *t.candidate != best.locationID {
+		loc := best.locationID
+		t.candidate, t.candidateSince = &loc, at
+	}
+	if at.Sub(t.candidateSince) < tu.MinDwell {
+		return decision{}
+	}
+
+	from := t.current
+	loc := best.locationID
+	t.current, t.candidate, t.lastWritten = &loc, nil, at
+	return decision{write: true, move: true, from: from, location: loc, point: best.scanPointID, rssi: best.rssi}
+}
@@ -0,0 +1,157 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProduction = "https://api.push.apple.com"
+	apnsSandbox    = "https://api.sandbox.push.apple.com"
+	// apnsTokenTTL is how long a provider token is reused. APNs rejects
+	// tokens older than an hour and throttles providers that mint them more
+	// often than every 20 minutes.
+	apnsTokenTTL = 50 * time.Minute
+)
+
+// APNsConfig is the token-based (.p8 signing key) provider configuration.
+type APNsConfig struct {
+	// KeyPEM is the contents of the AuthKey_<KeyID>.p8 file.
+	KeyPEM []byte
+	KeyID  string
+	TeamID string
+	// Topic is the app's bundle id.
+	Topic string
+	// Sandbox targets the development APNs environment (debug builds).
+	Sandbox bool
+}
+
+// APNs sends through Apple's HTTP/2 provider API with a signed provider
+// token, reused until apnsTokenTTL.
+type APNs struct {
+	cfg      APNsConfig
+	key      any
+	endpoint string
+	client   *http.Client
+	now      func() time.Time
+
+	mu       sync.Mutex
+	jwt      string
+	issuedAt time.Time
+}
+
+// NewAPNs builds a sender from cfg.
+func NewAPNs(cfg APNsConfig) (*APNs, error) {
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, fmt.Errorf("APNs requires a key id, team id and topic")
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(cfg.KeyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("APNs signing key: %w", err)
+	}
+	endpoint := apnsProduction
+	if cfg.Sandbox {
+		endpoint = apnsSandbox
+	}
+	return &APNs{
+		cfg:      cfg,
+		key:      key,
+		endpoint: endpoint,
+		// The default transport negotiates HTTP/2, which APNs requires.
+		client: &http.Client{Timeout: pushTimeout},
+		now:    time.Now,
+	}, nil
+}
+
+// Send delivers msg to one APNs device token.
+func (a *APNs) Send(ctx context.Context, token string, msg Message) error {
+	providerToken, err := a.providerToken()
+	if err != nil {
+		return err
+	}
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": msg.Title, "body": msg.Body},
+			"sound": "default",
+		},
+	}
+	for k, v := range msg.Data {
+		if k != "aps" {
+			payload[k] = v
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal apns payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		a.endpoint+"/3/device/"+url.PathEscape(token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", a.cfg.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("apns send: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	raw, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var ae struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(raw, &ae)
+	// BadDeviceToken is deliberately not treated as unregistered: it is also
+	// what a sandbox/production mismatch looks like, and that misconfiguration
+	// must not wipe every iOS registration.
+	if resp.StatusCode == http.StatusGone || ae.Reason == "Unregistered" {
+		return ErrUnregistered
+	}
+	if ae.Reason == "ExpiredProviderToken" || ae.Reason == "InvalidProviderToken" {
+		a.mu.Lock()
+		a.jwt = ""
+		a.mu.Unlock()
+	}
+	return fmt.Errorf("apns send: status %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+}
+
+// providerToken returns the cached provider token, re-signing it once it is
+// apnsTokenTTL old.
+func (a *APNs) providerToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	if a.jwt != "" && now.Sub(a.issuedAt) < apnsTokenTTL {
+		return a.jwt, nil
+	}
+	t := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.cfg.TeamID,
+		"iat": now.Unix(),
+	})
+	t.Header["kid"] = a.cfg.KeyID
+	signed, err := t.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("sign apns provider token: %w", err)
+	}
+	a.jwt, a.issuedAt = signed, now
+	return signed, nil
+}
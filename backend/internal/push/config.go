@@ -0,0 +1,58 @@
+package push
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FromEnv builds the pusher from:
+//
+//	PUSH_FCM_SERVICE_ACCOUNT  service account key file JSON (Android)
+//	PUSH_APNS_KEY             AuthKey_<id>.p8 PEM contents (iOS)
+//	PUSH_APNS_KEY_ID          key id                 (required with PUSH_APNS_KEY)
+//	PUSH_APNS_TEAM_ID         Apple developer team id (required with PUSH_APNS_KEY)
+//	PUSH_APNS_TOPIC           app bundle id           (required with PUSH_APNS_KEY)
+//	PUSH_APNS_SANDBOX         bool, development APNs environment (default false)
+//
+// Either platform may be configured alone. It returns nil when neither is,
+// which disables the notifier. A platform that is configured but malformed is
+// an error, so the server refuses to boot instead of silently not notifying.
+func FromEnv() (*Pusher, error) {
+	p := &Pusher{}
+
+	if sa := strings.TrimSpace(os.Getenv("PUSH_FCM_SERVICE_ACCOUNT")); sa != "" {
+		fcm, err := NewFCM([]byte(sa))
+		if err != nil {
+			return nil, fmt.Errorf("PUSH_FCM_SERVICE_ACCOUNT: %w", err)
+		}
+		p.Android = fcm
+	}
+
+	if key := strings.TrimSpace(os.Getenv("PUSH_APNS_KEY")); key != "" {
+		cfg := APNsConfig{
+			KeyPEM: []byte(key),
+			KeyID:  strings.TrimSpace(os.Getenv("PUSH_APNS_KEY_ID")),
+			TeamID: strings.TrimSpace(os.Getenv("PUSH_APNS_TEAM_ID")),
+			Topic:  strings.TrimSpace(os.Getenv("PUSH_APNS_TOPIC")),
+		}
+		if v := os.Getenv("PUSH_APNS_SANDBOX"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("PUSH_APNS_SANDBOX %q is not a bool", v)
+			}
+			cfg.Sandbox = b
+		}
+		apns, err := NewAPNs(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("PUSH_APNS_KEY: %w", err)
+		}
+		p.IOS = apns
+	}
+
+	if !p.Enabled() {
+		return nil, nil
+	}
+	return p, nil
+}
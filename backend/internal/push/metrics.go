@@ -0,0 +1,16 @@
+package push
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricSent = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "push_notifications_total",
+	Help: "Push notifications attempted, by platform and result.",
+}, []string{"platform", "result"}) // sent, unregistered, failed
+
+var metricOverdue = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "push_overdue_assets_total",
+	Help: "Overdue assets claimed for notification.",
+})
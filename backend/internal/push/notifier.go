@@ -0,0 +1,109 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/device"
+)
+
+// overdueBatch caps the assets claimed per org per run; the rest wait for the
+// next run.
+const overdueBatch = 100
+
+// notifierStore is the storage surface the notifier needs; *storage.Storage
+// satisfies it.
+type notifierStore interface {
+	ListOrgsWithMobileDevices(ctx context.Context) ([]int, error)
+	ClaimOverdueAssets(ctx context.Context, orgID int, now time.Time, limit int) ([]device.OverdueAsset, error)
+	DeleteMobileDeviceByToken(ctx context.Context, pushToken string) error
+}
+
+// Notifier is the overdue job: it claims assets whose due date passed and
+// pushes an "asset overdue" notice to the devices of the techs who look after
+// them.
+type Notifier struct {
+	store  notifierStore
+	pusher *Pusher
+	log    zerolog.Logger
+	now    func() time.Time
+}
+
+// NewNotifier builds the overdue job over pusher.
+func NewNotifier(store notifierStore, pusher *Pusher, log *zerolog.Logger) *Notifier {
+	return &Notifier{
+		store:  store,
+		pusher: pusher,
+		log:    log.With().Str("component", "push").Logger(),
+		now:    time.Now,
+	}
+}
+
+// Run notifies every org once. Push failures are logged, not returned; the
+// returned error joins the per-org storage failures.
+func (n *Notifier) Run(ctx context.Context) error {
+	orgs, err := n.store.ListOrgsWithMobileDevices(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, orgID := range orgs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		overdue, err := n.store.ClaimOverdueAssets(ctx, orgID, n.now(), overdueBatch)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("org %d: %w", orgID, err))
+			continue
+		}
+		for _, a := range overdue {
+			metricOverdue.Inc()
+			n.notify(ctx, a)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) notify(ctx context.Context, a device.OverdueAsset) {
+	msg := OverdueMessage(a)
+	for _, d := range a.Devices {
+		err := n.pusher.Send(ctx, d, msg)
+		switch {
+		case err == nil:
+			metricSent.WithLabelValues(d.Platform, "sent").Inc()
+		case errors.Is(err, ErrUnregistered):
+			metricSent.WithLabelValues(d.Platform, "unregistered").Inc()
+			if err := n.store.DeleteMobileDeviceByToken(ctx, d.PushToken); err != nil {
+				n.log.Warn().Err(err).Int("device_id", d.ID).Msg("failed to drop unregistered push token")
+			}
+		default:
+			metricSent.WithLabelValues(d.Platform, "failed").Inc()
+			n.log.Warn().Err(err).Int("org_id", a.OrgID).Int("asset_id", a.AssetID).
+				Int("device_id", d.ID).Msg("overdue push failed")
+		}
+	}
+}
+
+// OverdueMessage is the notice for one overdue asset. Data carries the ids
+// the app deep-links with.
+func OverdueMessage(a device.OverdueAsset) Message {
+	return Message{
+		Title: "Asset overdue",
+		Body: fmt.Sprintf("%s (%s) was due back %s.",
+			a.Name, a.ExternalKey, a.DueAt.UTC().Format("2006-01-02 15:04 UTC")),
+		Data: map[string]string{
+			"type":         string(events.AssetOverdue),
+			"org_id":       strconv.Itoa(a.OrgID),
+			"asset_id":     strconv.Itoa(a.AssetID),
+			"external_key": a.ExternalKey,
+			"due_at":       a.DueAt.UTC().Format(time.RFC3339),
+		},
+	}
+}
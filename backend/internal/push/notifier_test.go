@@ -0,0 +1,105 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/device"
+)
+
+type fakeNotifierStore struct {
+	orgs     []int
+	overdue  map[int][]device.OverdueAsset
+	claimErr map[int]error
+	claimed  []int
+	dropped  []string
+}
+
+func (f *fakeNotifierStore) ListOrgsWithMobileDevices(context.Context) ([]int, error) {
+	return f.orgs, nil
+}
+
+func (f *fakeNotifierStore) ClaimOverdueAssets(_ context.Context, orgID int, _ time.Time, _ int) ([]device.OverdueAsset, error) {
+	f.claimed = append(f.claimed, orgID)
+	if err := f.claimErr[orgID]; err != nil {
+		return nil, err
+	}
+	out := f.overdue[orgID]
+	delete(f.overdue, orgID)
+	return out, nil
+}
+
+func (f *fakeNotifierStore) DeleteMobileDeviceByToken(_ context.Context, token string) error {
+	f.dropped = append(f.dropped, token)
+	return nil
+}
+
+type sent struct {
+	token string
+	msg   Message
+}
+
+type fakeSender struct {
+	sent []sent
+	errs map[string]error
+}
+
+func (f *fakeSender) Send(_ context.Context, token string, msg Message) error {
+	if err := f.errs[token]; err != nil {
+		return err
+	}
+	f.sent = append(f.sent, sent{token, msg})
+	return nil
+}
+
+func TestNotifier_PushesOverdueAssets(t *testing.T) {
+	due := time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC)
+	store := &fakeNotifierStore{
+		orgs: []int{1, 2},
+		overdue: map[int][]device.OverdueAsset{
+			1: {{OrgID: 1, AssetID: 11, ExternalKey: "drill-7", Name: "Drill 7", DueAt: due, Devices: []device.Device{
+				{ID: 1, Platform: device.PlatformAndroid, PushToken: "fcm-a"},
+				{ID: 2, Platform: device.PlatformIOS, PushToken: "apns-b"},
+				{ID: 3, Platform: device.PlatformAndroid, PushToken: "fcm-gone"},
+			}}},
+		},
+		claimErr: map[int]error{2: errors.New("db down")},
+	}
+	android := &fakeSender{errs: map[string]error{"fcm-gone": ErrUnregistered}}
+	ios := &fakeSender{}
+	log := zerolog.New(io.Discard)
+	n := NewNotifier(store, &Pusher{Android: android, IOS: ios}, &log)
+
+	err := n.Run(context.Background())
+	require.Error(t, err, "org 2's claim failure is reported")
+	assert.Contains(t, err.Error(), "org 2")
+	assert.Equal(t, []int{1, 2}, store.claimed)
+
+	require.Len(t, android.sent, 1)
+	assert.Equal(t, "fcm-a", android.sent[0].token)
+	require.Len(t, ios.sent, 1)
+	msg := ios.sent[0].msg
+	assert.Equal(t, "Asset overdue", msg.Title)
+	assert.Equal(t, "Drill 7 (drill-7) was due back 2026-10-01 09:30 UTC.", msg.Body)
+	assert.Equal(t, "asset.overdue", msg.Data["type"])
+	assert.Equal(t, "11", msg.Data["asset_id"])
+
+	assert.Equal(t, []string{"fcm-gone"}, store.dropped)
+}
+
+func TestPusher_UnconfiguredPlatform(t *testing.T) {
+	p := &Pusher{Android: &fakeSender{}}
+	assert.True(t, p.Enabled())
+	err := p.Send(context.Background(), device.Device{Platform: device.PlatformIOS, PushToken: "x"}, Message{})
+	assert.ErrorContains(t, err, "not configured")
+
+	var none *Pusher
+	assert.False(t, none.Enabled())
+}
@@ -0,0 +1,61 @@
+// Package push delivers mobile push notifications to the handheld app: FCM
+// (HTTP v1 API) for Android installs and APNs (token-based auth) for iOS. The
+// Notifier job is its one producer today, telling techs when an asset they
+// look after is overdue.
+//
+// Delivery is best-effort. A push the service rejects is logged and counted,
+// never retried; a token the service reports as gone is unregistered so it is
+// not tried again.
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/trakrf/platform/backend/internal/models/device"
+)
+
+// ErrUnregistered is returned by a Sender when the push service says the
+// token no longer reaches an install (app uninstalled, token rotated).
+var ErrUnregistered = errors.New("push token is no longer registered")
+
+// Message is one notification.
+type Message struct {
+	Title string
+	Body  string
+	// Data is delivered to the app alongside the alert, for deep linking.
+	Data map[string]string
+}
+
+// Sender delivers a message to one push token of its platform.
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// Pusher routes each device to the sender for its platform. A nil sender
+// leaves that platform unconfigured.
+type Pusher struct {
+	Android Sender
+	IOS     Sender
+}
+
+// Enabled reports whether any platform is configured.
+func (p *Pusher) Enabled() bool {
+	return p != nil && (p.Android != nil || p.IOS != nil)
+}
+
+// Send delivers msg to d.
+func (p *Pusher) Send(ctx context.Context, d device.Device, msg Message) error {
+	var s Sender
+	switch d.Platform {
+	case device.PlatformAndroid:
+		s = p.Android
+	case device.PlatformIOS:
+		s = p.IOS
+	}
+	if s == nil {
+		return fmt.Errorf("push for platform %q is not configured", d.Platform)
+	}
+	return s.Send(ctx, d.PushToken, msg)
+}
@@ -0,0 +1,160 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFCM_MintsTokenOnceAndSends(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var exchanges int
+	var sends []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			exchanges++
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.Form.Get("grant_type"))
+			claims := jwt.MapClaims{}
+			_, err := jwt.ParseWithClaims(r.Form.Get("assertion"), claims, func(*jwt.Token) (any, error) {
+				return &key.PublicKey, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "push@proj.iam.gserviceaccount.com", claims["iss"])
+			assert.Equal(t, fcmScope, claims["scope"])
+			_, _ = w.Write([]byte(`{"access_token":"ya29.test","expires_in":3600}`))
+		case "/v1/projects/proj/messages:send":
+			assert.Equal(t, "Bearer ya29.test", r.Header.Get("Authorization"))
+			var body map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			sends = append(sends, body)
+			msg := body["message"].(map[string]any)
+			if msg["token"] == "gone" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"name":"projects/proj/messages/1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sa, _ := json.Marshal(FCMServiceAccount{
+		ProjectID: "proj", ClientEmail: "push@proj.iam.gserviceaccount.com",
+		PrivateKey: string(keyPEM), TokenURI: srv.URL + "/token",
+	})
+	f, err := NewFCM(sa)
+	require.NoError(t, err)
+	f.endpoint = srv.URL
+
+	msg := Message{Title: "Asset overdue", Body: "Drill 7", Data: map[string]string{"asset_id": "11"}}
+	require.NoError(t, f.Send(context.Background(), "tok-1", msg))
+	assert.ErrorIs(t, f.Send(context.Background(), "gone", msg), ErrUnregistered)
+
+	assert.Equal(t, 1, exchanges, "access token is cached")
+	require.Len(t, sends, 2)
+	sent := sends[0]["message"].(map[string]any)
+	assert.Equal(t, "tok-1", sent["token"])
+	assert.Equal(t, map[string]any{"asset_id": "11"}, sent["data"])
+
+	f.now = func() time.Time { return time.Now().Add(time.Hour) }
+	require.NoError(t, f.Send(context.Background(), "tok-1", msg))
+	assert.Equal(t, 2, exchanges, "expired access token is re-minted")
+}
+
+func TestAPNs_SendsWithProviderToken(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "bearer ")
+		tok, err := jwt.Parse(auth, func(*jwt.Token) (any, error) { return &key.PublicKey, nil })
+		assert.NoError(t, err)
+		if assert.NotNil(t, tok) {
+			assert.Equal(t, "KEY123", tok.Header["kid"])
+		}
+		assert.Equal(t, "com.trakrf.app", r.Header.Get("apns-topic"))
+		assert.Equal(t, "alert", r.Header.Get("apns-push-type"))
+
+		raw, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(raw))
+		token, _ := url.PathUnescape(strings.TrimPrefix(r.URL.Path, "/3/device/"))
+		switch token {
+		case "gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+		}
+	}))
+	defer srv.Close()
+
+	a, err := NewAPNs(APNsConfig{KeyPEM: keyPEM, KeyID: "KEY123", TeamID: "TEAM1", Topic: "com.trakrf.app"})
+	require.NoError(t, err)
+	a.endpoint = srv.URL
+
+	msg := Message{Title: "Asset overdue", Body: "Drill 7", Data: map[string]string{"asset_id": "11"}}
+	require.NoError(t, a.Send(context.Background(), "abc123", msg))
+	assert.JSONEq(t, `{"aps":{"alert":{"title":"Asset overdue","body":"Drill 7"},"sound":"default"},"asset_id":"11"}`, bodies[0])
+
+	assert.ErrorIs(t, a.Send(context.Background(), "gone", msg), ErrUnregistered)
+	err = a.Send(context.Background(), "bad", msg)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnregistered, "BadDeviceToken may be an environment mismatch")
+}
+
+func TestFromEnv(t *testing.T) {
+	for _, k := range []string{"PUSH_FCM_SERVICE_ACCOUNT", "PUSH_APNS_KEY", "PUSH_APNS_KEY_ID", "PUSH_APNS_TEAM_ID", "PUSH_APNS_TOPIC", "PUSH_APNS_SANDBOX"} {
+		t.Setenv(k, "")
+	}
+	p, err := FromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, p, "unset disables push")
+
+	t.Setenv("PUSH_FCM_SERVICE_ACCOUNT", `{"project_id":"proj"}`)
+	_, err = FromEnv()
+	assert.ErrorContains(t, err, "PUSH_FCM_SERVICE_ACCOUNT")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	t.Setenv("PUSH_FCM_SERVICE_ACCOUNT", "")
+	t.Setenv("PUSH_APNS_KEY", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	t.Setenv("PUSH_APNS_KEY_ID", "KEY123")
+	t.Setenv("PUSH_APNS_TEAM_ID", "TEAM1")
+	t.Setenv("PUSH_APNS_TOPIC", "com.trakrf.app")
+	t.Setenv("PUSH_APNS_SANDBOX", "true")
+	p, err = FromEnv()
+	require.NoError(t, err)
+	require.NotNil(t, p.IOS)
+	assert.Nil(t, p.Android)
+	assert.Equal(t, apnsSandbox, p.IOS.(*APNs).endpoint)
+}
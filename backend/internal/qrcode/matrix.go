@@ -0,0 +1,256 @@
+package qrcode
+
+// matrix is a symbol being built. function marks the modules of the finder,
+// timing and alignment patterns and the format and version areas, which
+// data and masks leave alone.
+type matrix struct {
+	version  int
+	size     int
+	dark     []bool
+	function []bool
+}
+
+func newMatrix(ver int) *matrix {
+	size := 17 + 4*ver
+	return &matrix{version: ver, size: size, dark: make([]bool, size*size), function: make([]bool, size*size)}
+}
+
+func (m *matrix) set(x, y int, dark bool) {
+	m.dark[y*m.size+x] = dark
+	m.function[y*m.size+x] = true
+}
+
+func (m *matrix) drawFunctionPatterns(align []int) {
+	for i := 0; i < m.size; i++ {
+		m.set(6, i, i%2 == 0)
+		m.set(i, 6, i%2 == 0)
+	}
+	m.drawFinder(3, 3)
+	m.drawFinder(m.size-4, 3)
+	m.drawFinder(3, m.size-4)
+
+	last := len(align) - 1
+	for i, y := range align {
+		for j, x := range align {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue // overlaps a finder
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					m.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	// Reserve the format areas; drawFormatBits fills them per mask.
+	m.drawFormatBits(0)
+	m.drawVersion()
+}
+
+// drawFinder draws a finder pattern centred on x, y with its separator.
+func (m *matrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= m.size || yy < 0 || yy >= m.size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			m.set(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+// drawFormatBits writes the 15-bit format information (level M and mask,
+// BCH-protected) in both its copies, and the dark module.
+func (m *matrix) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+	for i := 0; i <= 5; i++ {
+		m.set(8, i, bit(i))
+	}
+	m.set(8, 7, bit(6))
+	m.set(8, 8, bit(7))
+	m.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		m.set(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.set(8, m.size-15+i, bit(i))
+	}
+	m.set(8, m.size-8, true)
+}
+
+// formatBits is level M (00) and the mask, with its BCH(15,5) check bits,
+// XORed with the format mask pattern.
+func formatBits(mask int) int {
+	data := mask // level M's indicator is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawVersion writes the 18-bit version information of versions 7 and up
+// in both its copies.
+func (m *matrix) drawVersion() {
+	if m.version < 7 {
+		return
+	}
+	bits := versionBits(m.version)
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 == 1
+		a, b := m.size-11+i%3, i/3
+		m.set(a, b, dark)
+		m.set(b, a, dark)
+	}
+}
+
+// versionBits is the version with its BCH(18,6) check bits.
+func versionBits(ver int) int {
+	rem := ver
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return ver<<12 | rem
+}
+
+// drawCodewords places the codewords in the standard zigzag: two-module
+// columns from the right edge, alternately upwards and downwards, skipping
+// the vertical timing pattern and the function modules.
+func (m *matrix) drawCodewords(data []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if m.function[y*m.size+x] || i >= len(data)*8 {
+					continue
+				}
+				m.dark[y*m.size+x] = data[i/8]>>(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyMask XORs mask pattern n over the non-function modules; applying it
+// twice undoes it.
+func (m *matrix) applyMask(n int) {
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			if m.function[y*m.size+x] {
+				continue
+			}
+			var invert bool
+			switch n {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert {
+				m.dark[y*m.size+x] = !m.dark[y*m.size+x]
+			}
+		}
+	}
+}
+
+// penalty scores a masked symbol by the standard's four rules; Encode keeps
+// the mask with the lowest score.
+func (m *matrix) penalty() int {
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			x, y = y, x
+		}
+		return m.dark[y*m.size+x]
+	}
+	finderLike := [2][11]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+
+	p := 0
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < m.size; y++ {
+			// Rule 1: runs of five or more modules of one colour.
+			run := 1
+			for x := 1; x <= m.size; x++ {
+				if x < m.size && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					p += run - 2
+				}
+				run = 1
+			}
+			// Rule 3: 1:1:3:1:1 finder-like patterns with four light
+			// modules on one side.
+			for x := 0; x+11 <= m.size; x++ {
+				for _, pat := range finderLike {
+					match := true
+					for k, dark := range pat {
+						if at(x+k, y, transpose) != dark {
+							match = false
+							break
+						}
+					}
+					if match {
+						p += 40
+					}
+				}
+			}
+		}
+	}
+	// Rule 2: 2x2 blocks of one colour.
+	dark := 0
+	for y := 0; y < m.size; y++ {
+		for x := 0; x < m.size; x++ {
+			c := m.dark[y*m.size+x]
+			if c {
+				dark++
+			}
+			if x+1 < m.size && y+1 < m.size &&
+				c == m.dark[y*m.size+x+1] && c == m.dark[(y+1)*m.size+x] && c == m.dark[(y+1)*m.size+x+1] {
+				p += 3
+			}
+		}
+	}
+	// Rule 4: the share of dark modules, in 5% steps away from half.
+	total := m.size * m.size
+	p += abs(dark*20-total*10) / total * 10
+	return p
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,157 @@
+// Package qrcode encodes short byte strings, such as deep-link URLs, as QR
+// Code symbols (ISO/IEC 18004): byte mode at error correction level M, in
+// the smallest of versions 1-10 that fits, which holds up to 213 bytes.
+// Level M survives about 15% of the symbol being damaged or covered, which
+// suits labels stuck on walls and shelving.
+package qrcode
+
+import (
+	"errors"
+	"math"
+)
+
+// MaxBytes is the longest input Encode accepts (version 10-M).
+const MaxBytes = 213
+
+// ErrTooLong is returned by Encode for input longer than MaxBytes.
+var ErrTooLong = errors.New("qrcode: data too long")
+
+// Code is an encoded symbol: Size x Size modules, without the quiet zone
+// (four light modules on every side) a reader needs around it.
+type Code struct {
+	Size    int
+	modules []bool
+}
+
+// Dark reports whether the module at column x, row y is dark.
+func (c *Code) Dark(x, y int) bool {
+	return c.modules[y*c.Size+x]
+}
+
+// version is the level M block layout of one version: g1 blocks of g1Data
+// data codewords, then g2 blocks of g1Data+1, each followed by ecc
+// error-correction codewords.
+type version struct {
+	g1, g1Data, g2 int
+	ecc            int
+	align          []int
+}
+
+var versions = [...]version{
+	1:  {g1: 1, g1Data: 16, ecc: 10},
+	2:  {g1: 1, g1Data: 28, ecc: 16, align: []int{6, 18}},
+	3:  {g1: 1, g1Data: 44, ecc: 26, align: []int{6, 22}},
+	4:  {g1: 2, g1Data: 32, ecc: 18, align: []int{6, 26}},
+	5:  {g1: 2, g1Data: 43, ecc: 24, align: []int{6, 30}},
+	6:  {g1: 4, g1Data: 27, ecc: 16, align: []int{6, 34}},
+	7:  {g1: 4, g1Data: 31, ecc: 18, align: []int{6, 22, 38}},
+	8:  {g1: 2, g1Data: 38, g2: 2, ecc: 22, align: []int{6, 24, 42}},
+	9:  {g1: 3, g1Data: 36, g2: 2, ecc: 22, align: []int{6, 26, 46}},
+	10: {g1: 4, g1Data: 43, g2: 1, ecc: 26, align: []int{6, 28, 50}},
+}
+
+func (v version) dataCodewords() int { return v.g1*v.g1Data + v.g2*(v.g1Data+1) }
+
+// Encode returns data as a QR Code symbol.
+func Encode(data []byte) (*Code, error) {
+	ver := 0
+	for n := 1; n < len(versions); n++ {
+		if 4+countBits(n)+8*len(data) <= 8*versions[n].dataCodewords() {
+			ver = n
+			break
+		}
+	}
+	if ver == 0 {
+		return nil, ErrTooLong
+	}
+	codewords := interleave(versions[ver], dataCodewords(versions[ver], ver, data))
+
+	m := newMatrix(ver)
+	m.drawFunctionPatterns(versions[ver].align)
+	m.drawCodewords(codewords)
+	best, bestPenalty := -1, math.MaxInt
+	for mask := 0; mask < 8; mask++ {
+		m.applyMask(mask)
+		m.drawFormatBits(mask)
+		if p := m.penalty(); p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask) // XOR again to undo
+	}
+	m.applyMask(best)
+	m.drawFormatBits(best)
+	return &Code{Size: m.size, modules: m.dark}, nil
+}
+
+// countBits is the width of the byte-mode character count.
+func countBits(ver int) int {
+	if ver < 10 {
+		return 8
+	}
+	return 16
+}
+
+// dataCodewords packs data as one byte-mode segment, then the terminator and
+// the alternating pad bytes up to the version's data capacity.
+func dataCodewords(v version, ver int, data []byte) []byte {
+	var bits bitBuffer
+	bits.append(0b0100, 4)
+	bits.append(uint32(len(data)), countBits(ver))
+	for _, b := range data {
+		bits.append(uint32(b), 8)
+	}
+	capacity := 8 * v.dataCodewords()
+	bits.append(0, min(4, capacity-bits.n))
+	bits.append(0, (8-bits.n%8)%8)
+	for pad := uint32(0xEC); bits.n < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.append(pad, 8)
+	}
+	return bits.bytes
+}
+
+// interleave splits the data codewords into the version's blocks, appends
+// each block's error correction, and interleaves the blocks column-wise.
+func interleave(v version, data []byte) []byte {
+	gen := generator(v.ecc)
+	var blocks, eccs [][]byte
+	for i := 0; i < v.g1+v.g2; i++ {
+		n := v.g1Data
+		if i >= v.g1 {
+			n++
+		}
+		blocks = append(blocks, data[:n])
+		eccs = append(eccs, remainder(data[:n], gen))
+		data = data[n:]
+	}
+	var out []byte
+	for i := 0; i <= v.g1Data; i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecc; i++ {
+		for _, e := range eccs {
+			out = append(out, e[i])
+		}
+	}
+	return out
+}
+
+type bitBuffer struct {
+	bytes []byte
+	n     int
+}
+
+func (b *bitBuffer) append(v uint32, width int) {
+	for i := width - 1; i >= 0; i-- {
+		if b.n%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if v>>i&1 == 1 {
+			b.bytes[b.n/8] |= 0x80 >> (b.n % 8)
+		}
+		b.n++
+	}
+}
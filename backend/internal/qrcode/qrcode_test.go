@@ -0,0 +1,155 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The 1-M "HELLO WORLD" example of the standard's annex: its data codewords
+// and the error correction they get.
+func TestRemainder_StandardExample(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	assert.Equal(t, want, remainder(data, generator(10)))
+}
+
+func TestFormatBits_LevelM(t *testing.T) {
+	want := []string{
+		"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+		"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+	}
+	for mask, w := range want {
+		assert.Equal(t, w, fmt.Sprintf("%015b", formatBits(mask)), "mask %d", mask)
+	}
+}
+
+func TestVersionBits(t *testing.T) {
+	assert.Equal(t, 0x07C94, versionBits(7))
+	assert.Equal(t, 0x0A4D3, versionBits(10))
+}
+
+func TestEncode_PicksTheSmallestVersion(t *testing.T) {
+	for _, c := range []struct{ n, size int }{
+		{1, 21}, {14, 21}, {15, 25}, {84, 37}, {85, 41}, {MaxBytes, 57},
+	} {
+		code, err := Encode(bytes.Repeat([]byte("a"), c.n))
+		require.NoError(t, err)
+		assert.Equal(t, c.size, code.Size, "%d bytes", c.n)
+	}
+	_, err := Encode(bytes.Repeat([]byte("a"), MaxBytes+1))
+	assert.ErrorIs(t, err, ErrTooLong)
+}
+
+func TestEncode_RoundTrips(t *testing.T) {
+	for n := 0; n <= MaxBytes; n += 7 {
+		data := []byte(strings.Repeat("https://app.trakrf.id/locations/", 7)[:n])
+		code, err := Encode(data)
+		require.NoError(t, err)
+		assert.Equal(t, data, read(t, code), "%d bytes", n)
+	}
+}
+
+// read decodes a symbol Encode made: it finds the mask from the format
+// information, unmasks, collects the codewords, checks every block's error
+// correction and returns the byte segment.
+func read(t *testing.T, c *Code) []byte {
+	t.Helper()
+	ver := (c.Size - 17) / 4
+	var format int
+	for i := 14; i >= 9; i-- {
+		format = format<<1 | bit(c.Dark(14-i, 8))
+	}
+	format = format<<1 | bit(c.Dark(7, 8))
+	format = format<<1 | bit(c.Dark(8, 8))
+	format = format<<1 | bit(c.Dark(8, 7))
+	for i := 5; i >= 0; i-- {
+		format = format<<1 | bit(c.Dark(8, i))
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatBits(m) == format {
+			mask = m
+		}
+	}
+	require.NotEqual(t, -1, mask, "format information %015b", format)
+
+	m := newMatrix(ver)
+	m.drawFunctionPatterns(versions[ver].align)
+	copy(m.dark, c.modules)
+	m.applyMask(mask)
+
+	v := versions[ver]
+	total := v.dataCodewords() + (v.g1+v.g2)*v.ecc
+	// Walk the zigzag the same way drawCodewords does.
+	var bits []bool
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < m.size; vert++ {
+			y := vert
+			if upward {
+				y = m.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				if x := right - j; !m.function[y*m.size+x] {
+					bits = append(bits, m.dark[y*m.size+x])
+				}
+			}
+		}
+	}
+	codewords := make([]byte, total)
+	for i := range codewords {
+		for k := 0; k < 8; k++ {
+			codewords[i] = codewords[i]<<1 | byte(bit(bits[8*i+k]))
+		}
+	}
+
+	n := v.g1 + v.g2
+	blocks := make([][]byte, n)
+	k := 0
+	for i := 0; i <= v.g1Data; i++ {
+		for b := range blocks {
+			if i < v.g1Data || b >= v.g1 {
+				blocks[b] = append(blocks[b], codewords[k])
+				k++
+			}
+		}
+	}
+	var data []byte
+	gen := generator(v.ecc)
+	for b := range blocks {
+		var ecc []byte
+		for i := 0; i < v.ecc; i++ {
+			ecc = append(ecc, codewords[k+i*n+b])
+		}
+		require.Equal(t, remainder(blocks[b], gen), ecc, "block %d error correction", b)
+		data = append(data, blocks[b]...)
+	}
+
+	require.Equal(t, byte(0x40), data[0]&0xF0, "byte mode")
+	var length, start int
+	if ver < 10 {
+		length, start = int(data[0]&0x0F)<<4|int(data[1]>>4), 1
+	} else {
+		length, start = int(data[0]&0x0F)<<12|int(data[1])<<4|int(data[2]>>4), 2
+	}
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = data[start+i]<<4 | data[start+i+1]>>4
+	}
+	return out
+}
+
+func bit(dark bool) int {
+	if dark {
+		return 1
+	}
+	return 0
+}
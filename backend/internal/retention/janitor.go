@@ -0,0 +1,110 @@
+// Package retention enforces each org's data retention windows. The Janitor's
+// Run job resolves every live org's effective windows (plan limit, optionally
+// shortened by the org's settings) and deletes the audit trail and scan
+// history that has aged out, the scans of person-assets past their own
+// shorter window, along with offline sync mutation records past
+// their fixed replay window.
+//
+// Deletes are idempotent, so a run that fails part-way is finished by the next
+// one; one org's failure does not stop the rest from being pruned.
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/models/offline"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// janitorStore is the storage surface the janitor needs; *storage.Storage
+// satisfies it.
+type janitorStore interface {
+	ListOrgRetention(ctx context.Context) ([]organization.OrgRetention, error)
+	PruneWebhookDeliveries(ctx context.Context, orgID int, cutoff time.Time) (int64, error)
+	PruneAssetScans(ctx context.Context, orgID int, cutoff time.Time) (int64, error)
+	PrunePersonnelScans(ctx context.Context, orgID int, cutoff time.Time) (int64, error)
+	PruneSyncMutations(ctx context.Context, orgID int, cutoff time.Time) (int64, error)
+}
+
+// Janitor prunes data past each org's retention window.
+type Janitor struct {
+	store janitorStore
+	log   zerolog.Logger
+	now   func() time.Time
+}
+
+// NewJanitor builds a janitor over store.
+func NewJanitor(store janitorStore, log *zerolog.Logger) *Janitor {
+	return &Janitor{
+		store: store,
+		log:   log.With().Str("component", "retention").Logger(),
+		now:   time.Now,
+	}
+}
+
+// Run prunes every org once. The returned error joins the per-org failures.
+func (j *Janitor) Run(ctx context.Context) error {
+	orgs, err := j.store.ListOrgRetention(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := j.now()
+	var errs []error
+	for _, o := range orgs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := j.pruneOrg(ctx, o, now); err != nil {
+			errs = append(errs, fmt.Errorf("org %d: %w", o.OrgID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (j *Janitor) pruneOrg(ctx context.Context, o organization.OrgRetention, now time.Time) error {
+	p := o.Effective()
+
+	audit, err := j.store.PruneWebhookDeliveries(ctx, o.OrgID, cutoff(now, p.AuditDays))
+	if err != nil {
+		return err
+	}
+	metricPruned.WithLabelValues("audit").Add(float64(audit))
+
+	scans, err := j.store.PruneAssetScans(ctx, o.OrgID, cutoff(now, p.ScanDays))
+	if err != nil {
+		return err
+	}
+	metricPruned.WithLabelValues("scans").Add(float64(scans))
+
+	// Person scans inside the scan window but past the personnel one.
+	var personnel int64
+	if p.PersonnelScanDays < p.ScanDays {
+		personnel, err = j.store.PrunePersonnelScans(ctx, o.OrgID, cutoff(now, p.PersonnelScanDays))
+		if err != nil {
+			return err
+		}
+		metricPruned.WithLabelValues("personnel_scans").Add(float64(personnel))
+	}
+
+	syncs, err := j.store.PruneSyncMutations(ctx, o.OrgID, now.Add(-offline.ReplayWindow))
+	if err != nil {
+		return err
+	}
+	metricPruned.WithLabelValues("sync_mutations").Add(float64(syncs))
+
+	if audit > 0 || scans > 0 || personnel > 0 || syncs > 0 {
+		j.log.Info().Int("org_id", o.OrgID).Int64("audit", audit).Int64("scans", scans).
+			Int64("personnel_scans", personnel).Int64("sync_mutations", syncs).Msg("pruned data past retention")
+	}
+	return nil
+}
+
+func cutoff(now time.Time, days int) time.Time {
+	return now.AddDate(0, 0, -days)
+}
@@ -0,0 +1,123 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/offline"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+type fakeStore struct {
+	orgs      []organization.OrgRetention
+	failOrg   int
+	auditCuts map[int]time.Time
+	scanCuts  map[int]time.Time
+	// personCuts is keyed like the others; an org absent from it had no
+	// personnel prune.
+	personCuts map[int]time.Time
+	syncCuts   map[int]time.Time
+}
+
+func (f *fakeStore) ListOrgRetention(context.Context) ([]organization.OrgRetention, error) {
+	return f.orgs, nil
+}
+
+func (f *fakeStore) PruneWebhookDeliveries(_ context.Context, orgID int, cutoff time.Time) (int64, error) {
+	if orgID == f.failOrg {
+		return 0, errors.New("db down")
+	}
+	f.auditCuts[orgID] = cutoff
+	return 1, nil
+}
+
+func (f *fakeStore) PruneAssetScans(_ context.Context, orgID int, cutoff time.Time) (int64, error) {
+	f.scanCuts[orgID] = cutoff
+	return 2, nil
+}
+
+func (f *fakeStore) PrunePersonnelScans(_ context.Context, orgID int, cutoff time.Time) (int64, error) {
+	f.personCuts[orgID] = cutoff
+	return 1, nil
+}
+
+func (f *fakeStore) PruneSyncMutations(_ context.Context, orgID int, cutoff time.Time) (int64, error) {
+	f.syncCuts[orgID] = cutoff
+	return 0, nil
+}
+
+func newFakeStore(orgs ...organization.OrgRetention) *fakeStore {
+	return &fakeStore{orgs: orgs, auditCuts: map[int]time.Time{}, scanCuts: map[int]time.Time{}, personCuts: map[int]time.Time{}, syncCuts: map[int]time.Time{}}
+}
+
+func testJanitor(store janitorStore, now time.Time) *Janitor {
+	log := zerolog.Nop()
+	j := NewJanitor(store, &log)
+	j.now = func() time.Time { return now }
+	return j
+}
+
+func TestRun_PrunesEachOrgAtItsEffectiveWindow(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	thirty := 30
+	store := newFakeStore(
+		organization.OrgRetention{OrgID: 1, Limits: organization.RetentionPolicy{AuditDays: 90, ScanDays: 90}},
+		organization.OrgRetention{
+			OrgID:    2,
+			Limits:   organization.RetentionPolicy{AuditDays: 730, ScanDays: 730},
+			Settings: organization.RetentionSettings{ScanDays: &thirty},
+		},
+	)
+
+	require.NoError(t, testJanitor(store, now).Run(context.Background()))
+
+	assert.Equal(t, now.AddDate(0, 0, -90), store.auditCuts[1])
+	assert.Equal(t, now.AddDate(0, 0, -90), store.scanCuts[1])
+	assert.Equal(t, now.AddDate(0, 0, -730), store.auditCuts[2])
+	assert.Equal(t, now.AddDate(0, 0, -30), store.scanCuts[2])
+	// The sync replay window is fixed, not plan-dependent.
+	assert.Equal(t, now.Add(-offline.ReplayWindow), store.syncCuts[1])
+	assert.Equal(t, now.Add(-offline.ReplayWindow), store.syncCuts[2])
+}
+
+func TestRun_PrunesPersonnelScansAtTheirShorterWindow(t *testing.T) {
+	now := time.Date(2026, 6, 30, 12, 0, 0, 0, time.UTC)
+	limits := organization.RetentionPolicy{AuditDays: 90, ScanDays: 365, PersonnelScanDays: 365}
+	fourteen, sixty := 14, 60
+	store := newFakeStore(
+		organization.OrgRetention{OrgID: 1, Limits: limits},
+		organization.OrgRetention{OrgID: 2, Limits: limits,
+			Settings: organization.RetentionSettings{PersonnelScanDays: &fourteen}},
+		organization.OrgRetention{OrgID: 3, Limits: limits,
+			Settings: organization.RetentionSettings{ScanDays: &fourteen, PersonnelScanDays: &sixty}},
+	)
+
+	require.NoError(t, testJanitor(store, now).Run(context.Background()))
+
+	assert.NotContains(t, store.personCuts, 1, "no personnel window: person scans age out with the rest")
+	assert.Equal(t, now.AddDate(0, 0, -14), store.personCuts[2])
+	assert.Equal(t, now.AddDate(0, 0, -365), store.scanCuts[2])
+	assert.NotContains(t, store.personCuts, 3, "a personnel window longer than the scan window is moot")
+	assert.Equal(t, now.AddDate(0, 0, -14), store.scanCuts[3])
+}
+
+func TestRun_OneOrgFailingDoesNotStopOthers(t *testing.T) {
+	limits := organization.RetentionPolicy{AuditDays: 90, ScanDays: 365}
+	store := newFakeStore(
+		organization.OrgRetention{OrgID: 1, Limits: limits},
+		organization.OrgRetention{OrgID: 2, Limits: limits},
+	)
+	store.failOrg = 1
+
+	err := testJanitor(store, time.Now()).Run(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "org 1")
+	assert.Contains(t, store.scanCuts, 2)
+}
@@ -0,0 +1,11 @@
+package retention
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var metricPruned = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "retention_pruned_rows_total",
+	Help: "Rows deleted for being past their org's retention window, by kind.",
+}, []string{"kind"}) // audit, scans, personnel_scans, sync_mutations
@@ -0,0 +1,76 @@
+// Package scanguard watches how often each reader submits tag values the org
+// does not know. A fixed reader sees some foreign tags (neighbours' stock,
+// pallets in transit), so unknown values are counted, not refused outright;
+// a source that runs through its unknown-tag budget is throttled, which makes
+// enumerating identifier values through the scan endpoints impractical.
+package scanguard
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/trakrf/platform/backend/internal/ratelimit"
+)
+
+// metricTagLookups is labelled by source (middleware.ScanSource.Label), whose
+// cardinality is bounded by the registered readers, API keys and scanning
+// users.
+var metricTagLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "scan_tag_lookups_total",
+	Help: "Tag values submitted through the scan endpoints, by source and whether the org knows them.",
+}, []string{"source", "result"}) // known, unknown
+
+// Observe records a request's known and unknown tag values for source.
+func Observe(source string, known, unknown int) {
+	if known > 0 {
+		metricTagLookups.WithLabelValues(source, "known").Add(float64(known))
+	}
+	if unknown > 0 {
+		metricTagLookups.WithLabelValues(source, "unknown").Add(float64(unknown))
+	}
+}
+
+// DefaultConfig is the unknown-tag budget per source: a burst of 5000 (a
+// dock door reading a full trailer of someone else's pallets) refilling at
+// 1200 a minute.
+func DefaultConfig() ratelimit.Config {
+	return ratelimit.Config{
+		RatePerMinute: 1200,
+		Burst:         5000,
+		IdleTTL:       time.Hour,
+		SweepInterval: 10 * time.Minute,
+		Clock:         ratelimit.RealClock{},
+	}
+}
+
+// Budget meters unknown tag values per source.
+type Budget struct {
+	lim *ratelimit.Limiter
+}
+
+// NewBudget returns a Budget; it lives for the process, like the API rate
+// limiter.
+func NewBudget(cfg ratelimit.Config) *Budget {
+	return &Budget{lim: ratelimit.NewLimiter(cfg)}
+}
+
+// Record observes a request's known and unknown tag values for source and
+// charges the unknown ones to its budget; see Charge.
+func (b *Budget) Record(source string, known, unknown int) (bool, time.Duration) {
+	Observe(source, known, unknown)
+	return b.Charge(source, unknown)
+}
+
+// Charge spends unknown from source's budget. When the budget runs out it
+// reports false and how long until it refills enough to retry; the caller
+// withholds the results so a prober learns nothing from the request.
+func (b *Budget) Charge(source string, unknown int) (bool, time.Duration) {
+	for i := 0; i < unknown; i++ {
+		if d := b.lim.Allow(source); !d.Allowed {
+			return false, d.RetryAfter
+		}
+	}
+	return true, 0
+}
@@ -0,0 +1,76 @@
+package scanguard
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/ratelimit"
+)
+
+func testBudget(clock ratelimit.Clock) *Budget {
+	return NewBudget(ratelimit.Config{
+		RatePerMinute: 60,
+		Burst:         10,
+		IdleTTL:       time.Hour,
+		SweepInterval: time.Hour,
+		Clock:         clock,
+	})
+}
+
+func TestBudget_ThrottlesUnknownTagsPerSource(t *testing.T) {
+	clock := ratelimit.NewFakeClock(time.Unix(1_700_000_000, 0))
+	b := testBudget(clock)
+
+	ok, _ := b.Charge("scan_device:1", 10)
+	assert.True(t, ok, "the burst covers a full read")
+	ok, retry := b.Charge("scan_device:1", 1)
+	assert.False(t, ok)
+	assert.Greater(t, retry, time.Duration(0))
+
+	ok, _ = b.Charge("scan_device:2", 5)
+	assert.True(t, ok, "budgets are per source")
+	ok, _ = b.Charge("scan_device:1", 0)
+	assert.True(t, ok, "known tags are never throttled")
+
+	clock.Advance(2 * time.Second)
+	ok, _ = b.Charge("scan_device:1", 1)
+	assert.True(t, ok, "the budget refills")
+}
+
+// tagLookups reads scan_tag_lookups_total{source,result} from the default
+// registry.
+func tagLookups(t *testing.T, source, result string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, f := range families {
+		if f.GetName() != "scan_tag_lookups_total" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := map[string]string{}
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["source"] == source && labels["result"] == result {
+				return m.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestRecord_CountsKnownAndUnknown(t *testing.T) {
+	b := testBudget(ratelimit.NewFakeClock(time.Unix(1_700_000_000, 0)))
+	known := tagLookups(t, "api_key:metrics-test", "known")
+	unknown := tagLookups(t, "api_key:metrics-test", "unknown")
+
+	ok, _ := b.Record("api_key:metrics-test", 3, 2)
+	assert.True(t, ok)
+	assert.Equal(t, known+3, tagLookups(t, "api_key:metrics-test", "known"))
+	assert.Equal(t, unknown+2, tagLookups(t, "api_key:metrics-test", "unknown"))
+}
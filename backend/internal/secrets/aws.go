@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/util/awssig"
+)
+
+// AWSSource reads secrets from AWS Secrets Manager (GetSecretValue). A
+// secret's SecretString must be a JSON object.
+type AWSSource struct {
+	endpoint string
+	region   string
+	creds    awssig.Credentials
+	client   *http.Client
+}
+
+// NewAWSSource builds a source calling the Secrets Manager API at endpoint.
+func NewAWSSource(endpoint, region string, creds awssig.Credentials) *AWSSource {
+	return &AWSSource{
+		endpoint: endpoint,
+		region:   region,
+		creds:    creds,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Fetch implements Source. id is the secret's name or ARN; the current
+// version (AWSCURRENT) is read.
+func (s *AWSSource) Fetch(ctx context.Context, id string) (map[string]string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": id})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build secretsmanager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	awssig.Sign(req, body, s.creds, s.region, "secretsmanager", time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("secretsmanager: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 256<<10))
+	if err != nil {
+		return nil, fmt.Errorf("secretsmanager: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &e)
+		return nil, fmt.Errorf("secretsmanager: status %d: %s %s", resp.StatusCode, e.Type, e.Message)
+	}
+	var out struct {
+		SecretString *string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, fmt.Errorf("secretsmanager: %w", err)
+	}
+	if out.SecretString == nil {
+		return nil, fmt.Errorf("secretsmanager: secret has no SecretString")
+	}
+	return flatten([]byte(*out.SecretString))
+}
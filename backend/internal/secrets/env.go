@@ -0,0 +1,103 @@
+package secrets
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/util/awssig"
+)
+
+// Providers accepted in SECRETS_PROVIDER.
+const (
+	ProviderAWS   = "aws"
+	ProviderVault = "vault"
+)
+
+// DefaultRefreshInterval is how often secrets are re-read when
+// SECRETS_REFRESH_INTERVAL is unset.
+const DefaultRefreshInterval = 5 * time.Minute
+
+// FromEnv builds the loader from:
+//
+//	SECRETS_PROVIDER          aws|vault (unset: credentials come from the environment)
+//	SECRETS_APP_ID            secret holding any of PG_URL, JWT_SECRET, RESEND_API_KEY, SMTP_USERNAME, SMTP_PASSWORD
+//	SECRETS_DB_ID             optional database secret {"username", "password"}, e.g. an RDS-managed one
+//	SECRETS_REFRESH_INTERVAL  dur                     (default 5m)
+//
+//	aws:   SECRETS_AWS_REGION (or AWS_REGION), SECRETS_AWS_ENDPOINT
+//	       (default https://secretsmanager.<region>.amazonaws.com),
+//	       AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN
+//	vault: VAULT_ADDR, VAULT_TOKEN or VAULT_TOKEN_FILE, VAULT_NAMESPACE;
+//	       ids are <mount>/<path> in a KV v2 engine
+//
+// Unset SECRETS_PROVIDER returns a nil loader. Any other misconfiguration
+// is an error, so the server refuses to boot rather than starting without
+// the credentials it was told to use.
+func FromEnv() (*Loader, error) {
+	provider := strings.ToLower(strings.TrimSpace(os.Getenv("SECRETS_PROVIDER")))
+	if provider == "" {
+		return nil, nil
+	}
+	appID := strings.TrimSpace(os.Getenv("SECRETS_APP_ID"))
+	dbID := strings.TrimSpace(os.Getenv("SECRETS_DB_ID"))
+	if appID == "" && dbID == "" {
+		return nil, fmt.Errorf("SECRETS_APP_ID or SECRETS_DB_ID must be set with SECRETS_PROVIDER")
+	}
+	interval := DefaultRefreshInterval
+	if raw := os.Getenv("SECRETS_REFRESH_INTERVAL"); raw != "" {
+		v, err := time.ParseDuration(raw)
+		if err != nil || v < time.Second {
+			return nil, fmt.Errorf("SECRETS_REFRESH_INTERVAL must be a duration of at least 1s, got %q", raw)
+		}
+		interval = v
+	}
+
+	var source Source
+	switch provider {
+	case ProviderAWS:
+		region := os.Getenv("SECRETS_AWS_REGION")
+		if region == "" {
+			region = os.Getenv("AWS_REGION")
+		}
+		if region == "" {
+			return nil, fmt.Errorf("SECRETS_AWS_REGION or AWS_REGION must be set for SECRETS_PROVIDER=aws")
+		}
+		creds := awssig.Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		}
+		if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+			return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for SECRETS_PROVIDER=aws")
+		}
+		endpoint := strings.TrimRight(os.Getenv("SECRETS_AWS_ENDPOINT"), "/")
+		if endpoint == "" {
+			endpoint = "https://secretsmanager." + region + ".amazonaws.com"
+		}
+		if !httpURL(endpoint) {
+			return nil, fmt.Errorf("SECRETS_AWS_ENDPOINT must be an http(s) URL, got %q", endpoint)
+		}
+		source = NewAWSSource(endpoint, region, creds)
+	case ProviderVault:
+		addr := strings.TrimRight(os.Getenv("VAULT_ADDR"), "/")
+		if !httpURL(addr) {
+			return nil, fmt.Errorf("VAULT_ADDR must be an http(s) URL, got %q", addr)
+		}
+		token, tokenFile := os.Getenv("VAULT_TOKEN"), os.Getenv("VAULT_TOKEN_FILE")
+		if token == "" && tokenFile == "" {
+			return nil, fmt.Errorf("VAULT_TOKEN or VAULT_TOKEN_FILE must be set for SECRETS_PROVIDER=vault")
+		}
+		source = NewVaultSource(addr, token, tokenFile, os.Getenv("VAULT_NAMESPACE"))
+	default:
+		return nil, fmt.Errorf("SECRETS_PROVIDER %q is not one of aws, vault", provider)
+	}
+	return NewLoader(source, appID, dbID, interval), nil
+}
+
+func httpURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
@@ -0,0 +1,238 @@
+// Package secrets loads runtime credentials (database, JWT signing, email)
+// from a secrets manager, HashiCorp Vault or AWS Secrets Manager, instead of
+// the deployment's plain environment, and keeps them fresh while the server
+// runs.
+//
+// Credentials land in the process environment under the variable names the
+// rest of the backend already reads, so nothing downstream changes: a loaded
+// JWT_SECRET signs the next token, a loaded PG_URL authenticates the next
+// database connection (see storage.New), and OnChange hooks rebuild what
+// caches a credential, such as the email client.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Names are the variables a secret may set. Anything else in a secret is an
+// error, so a misspelled name fails the boot instead of being ignored.
+var Names = []string{
+	"PG_URL",
+	"JWT_SECRET",
+	"RESEND_API_KEY",
+	"SMTP_USERNAME",
+	"SMTP_PASSWORD",
+}
+
+// Source reads one secret as a flat name → value object.
+type Source interface {
+	Fetch(ctx context.Context, id string) (map[string]string, error)
+}
+
+// Loader applies an app secret, and optionally a database secret, to the
+// process environment.
+type Loader struct {
+	source Source
+	// appID names the secret holding variables from Names.
+	appID string
+	// dbID names a database credential secret in the RDS rotation format
+	// ({"username": ..., "password": ...}); its credentials replace PG_URL's.
+	dbID     string
+	interval time.Duration
+
+	mu        sync.Mutex
+	applied   map[string]string
+	onChange  []func(names []string)
+	checks    map[string][]func(value string) error
+	onReplace map[string][]func(previous string)
+}
+
+// NewLoader builds a loader reading appID and dbID (either may be empty)
+// from source, refreshing every interval.
+func NewLoader(source Source, appID, dbID string, interval time.Duration) *Loader {
+	return &Loader{source: source, appID: appID, dbID: dbID, interval: interval, applied: map[string]string{}}
+}
+
+// Interval is how often Refresh should run.
+func (l *Loader) Interval() time.Duration {
+	return l.interval
+}
+
+// OnChange registers fn to run after a refresh changes any variable, with
+// the names that changed.
+func (l *Loader) OnChange(fn func(names []string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onChange = append(l.onChange, fn)
+}
+
+// Validate registers check to vet every refreshed value of name before it is
+// applied. A value check rejects fails the whole refresh, so every variable
+// keeps its current value.
+func (l *Loader) Validate(name string, check func(value string) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.checks == nil {
+		l.checks = map[string][]func(string) error{}
+	}
+	l.checks[name] = append(l.checks[name], check)
+}
+
+// OnReplace registers fn to run with name's current value just before a
+// refresh replaces it, so a consumer can keep honoring the old value for a
+// while.
+func (l *Loader) OnReplace(name string, fn func(previous string)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.onReplace == nil {
+		l.onReplace = map[string][]func(string){}
+	}
+	l.onReplace[name] = append(l.onReplace[name], fn)
+}
+
+// Load fetches the secrets and sets their variables, returning the names
+// it set. Call once at boot, before configuration is read.
+func (l *Loader) Load(ctx context.Context) ([]string, error) {
+	values, err := l.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	names := make([]string, 0, len(values))
+	for name, v := range values {
+		if err := os.Setenv(name, v); err != nil {
+			return nil, err
+		}
+		l.applied[name] = v
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Refresh re-fetches the secrets and applies what changed, then runs the
+// OnChange hooks. It is a jobs.Func; a failed fetch, or a changed value a
+// Validate check rejects, keeps the values already applied.
+func (l *Loader) Refresh(ctx context.Context) error {
+	values, err := l.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	for name, v := range values {
+		if l.applied[name] == v {
+			continue
+		}
+		for _, check := range l.checks[name] {
+			if err := check(v); err != nil {
+				l.mu.Unlock()
+				return fmt.Errorf("refreshed %s rejected: %w", name, err)
+			}
+		}
+	}
+	var changed []string
+	for name, v := range values {
+		if l.applied[name] == v {
+			continue
+		}
+		for _, fn := range l.onReplace[name] {
+			fn(os.Getenv(name))
+		}
+		if err := os.Setenv(name, v); err != nil {
+			l.mu.Unlock()
+			return err
+		}
+		l.applied[name] = v
+		changed = append(changed, name)
+	}
+	hooks := slices.Clone(l.onChange)
+	l.mu.Unlock()
+	if len(changed) == 0 {
+		return nil
+	}
+	sort.Strings(changed)
+	for _, fn := range hooks {
+		fn(changed)
+	}
+	return nil
+}
+
+// fetch reads both secrets and returns the variables they set.
+func (l *Loader) fetch(ctx context.Context) (map[string]string, error) {
+	values := map[string]string{}
+	if l.appID != "" {
+		app, err := l.source.Fetch(ctx, l.appID)
+		if err != nil {
+			return nil, fmt.Errorf("secret %q: %w", l.appID, err)
+		}
+		for name, v := range app {
+			if !slices.Contains(Names, name) {
+				return nil, fmt.Errorf("secret %q: %s is not a variable secrets may set (%s)",
+					l.appID, name, strings.Join(Names, ", "))
+			}
+			if v == "" {
+				return nil, fmt.Errorf("secret %q: %s is empty", l.appID, name)
+			}
+			values[name] = v
+		}
+	}
+	if l.dbID != "" {
+		db, err := l.source.Fetch(ctx, l.dbID)
+		if err != nil {
+			return nil, fmt.Errorf("secret %q: %w", l.dbID, err)
+		}
+		base, ok := values["PG_URL"]
+		if !ok {
+			base = os.Getenv("PG_URL")
+		}
+		pgURL, err := withCredentials(base, db["username"], db["password"])
+		if err != nil {
+			return nil, fmt.Errorf("secret %q: %w", l.dbID, err)
+		}
+		values["PG_URL"] = pgURL
+	}
+	return values, nil
+}
+
+// withCredentials replaces the user and password of a postgres:// URL.
+func withCredentials(pgURL, username, password string) (string, error) {
+	if username == "" || password == "" {
+		return "", fmt.Errorf("database secret must have a username and a password")
+	}
+	u, err := url.Parse(pgURL)
+	if err != nil || (u.Scheme != "postgres" && u.Scheme != "postgresql") || u.Host == "" {
+		return "", fmt.Errorf("PG_URL must be a postgres:// URL to take database credentials from a secret")
+	}
+	u.User = url.UserPassword(username, password)
+	return u.String(), nil
+}
+
+// flatten decodes a secret's JSON object. String values are taken as is and
+// other scalars as their JSON text, so the numeric port of an RDS secret
+// does not fail the decode.
+func flatten(raw []byte) (map[string]string, error) {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, fmt.Errorf("secret is not a JSON object")
+	}
+	out := make(map[string]string, len(obj))
+	for k, v := range obj {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			out[k] = s
+			continue
+		}
+		out[k] = string(v)
+	}
+	return out, nil
+}
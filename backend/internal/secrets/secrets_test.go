@@ -0,0 +1,186 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves secrets from a map the test mutates.
+type fakeSource map[string]map[string]string
+
+func (f fakeSource) Fetch(_ context.Context, id string) (map[string]string, error) {
+	v, ok := f[id]
+	if !ok {
+		return nil, assert.AnError
+	}
+	return v, nil
+}
+
+func TestLoader_LoadAndRefresh(t *testing.T) {
+	t.Setenv("JWT_SECRET", "from-env")
+	t.Setenv("PG_URL", "postgres://placeholder:x@db:5432/trakrf?sslmode=require")
+	src := fakeSource{
+		"app": {"JWT_SECRET": "s1", "RESEND_API_KEY": "re_1"},
+		"db":  {"username": "app", "password": "p1", "engine": "postgres", "port": "5432"},
+	}
+	l := NewLoader(src, "app", "db", time.Minute)
+	var changes [][]string
+	l.OnChange(func(names []string) { changes = append(changes, names) })
+
+	names, err := l.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"JWT_SECRET", "PG_URL", "RESEND_API_KEY"}, names)
+	assert.Equal(t, "s1", os.Getenv("JWT_SECRET"), "the secret wins over the environment")
+	assert.Equal(t, "postgres://app:p1@db:5432/trakrf?sslmode=require", os.Getenv("PG_URL"))
+
+	require.NoError(t, l.Refresh(context.Background()))
+	assert.Empty(t, changes, "nothing changed")
+
+	src["db"]["password"] = "p2"
+	src["app"]["JWT_SECRET"] = "s2"
+	require.NoError(t, l.Refresh(context.Background()))
+	require.Len(t, changes, 1)
+	assert.Equal(t, []string{"JWT_SECRET", "PG_URL"}, changes[0])
+	assert.Equal(t, "postgres://app:p2@db:5432/trakrf?sslmode=require", os.Getenv("PG_URL"))
+
+	delete(src, "app")
+	assert.Error(t, l.Refresh(context.Background()))
+	assert.Equal(t, "s2", os.Getenv("JWT_SECRET"), "a failed refresh keeps what was applied")
+}
+
+func TestLoader_ValidateAndOnReplace(t *testing.T) {
+	t.Setenv("JWT_SECRET", "from-env")
+	src := fakeSource{"app": {"JWT_SECRET": "s1", "RESEND_API_KEY": "re_1"}}
+	l := NewLoader(src, "app", "", time.Minute)
+	l.Validate("JWT_SECRET", func(v string) error {
+		if v == "change-me" {
+			return assert.AnError
+		}
+		return nil
+	})
+	var replaced []string
+	l.OnReplace("JWT_SECRET", func(previous string) { replaced = append(replaced, previous) })
+	_, err := l.Load(context.Background())
+	require.NoError(t, err)
+
+	src["app"]["JWT_SECRET"] = "change-me"
+	src["app"]["RESEND_API_KEY"] = "re_2"
+	assert.Error(t, l.Refresh(context.Background()))
+	assert.Equal(t, "s1", os.Getenv("JWT_SECRET"), "a rejected value is not applied")
+	assert.Equal(t, "re_1", os.Getenv("RESEND_API_KEY"), "nor is anything refreshed with it")
+	assert.Empty(t, replaced)
+
+	src["app"]["JWT_SECRET"] = "s2"
+	require.NoError(t, l.Refresh(context.Background()))
+	assert.Equal(t, "s2", os.Getenv("JWT_SECRET"))
+	assert.Equal(t, []string{"s1"}, replaced, "OnReplace sees the value being replaced")
+}
+
+func TestLoader_Rejects(t *testing.T) {
+	t.Setenv("PG_URL", "host=db user=x")
+	for name, src := range map[string]fakeSource{
+		"unknown name":    {"app": {"JWT_SECRT": "x"}},
+		"empty value":     {"app": {"JWT_SECRET": ""}},
+		"db without pass": {"app": {}, "db": {"username": "app"}},
+		"db with dsn url": {"app": {}, "db": {"username": "app", "password": "p"}},
+	} {
+		_, err := NewLoader(src, "app", "db", time.Minute).Load(context.Background())
+		assert.Error(t, err, name)
+	}
+}
+
+func TestAWSSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-2/secretsmanager/aws4_request")
+		var req struct{ SecretId string }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.SecretId != "trakrf/db" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"ResourceNotFoundException","message":"not found"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"SecretString": `{"username":"app","password":"p","port":5432}`,
+		})
+	}))
+	defer srv.Close()
+
+	t.Setenv("SECRETS_PROVIDER", "aws")
+	t.Setenv("SECRETS_DB_ID", "trakrf/db")
+	t.Setenv("SECRETS_AWS_REGION", "us-east-2")
+	t.Setenv("SECRETS_AWS_ENDPOINT", srv.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	l, err := FromEnv()
+	require.NoError(t, err)
+
+	got, err := l.source.Fetch(context.Background(), "trakrf/db")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"username": "app", "password": "p", "port": "5432"}, got)
+
+	_, err = l.source.Fetch(context.Background(), "other")
+	assert.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+func TestVaultSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "agent-token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		assert.Equal(t, "/v1/secret/data/trakrf/backend", r.URL.Path)
+		_, _ = w.Write([]byte(`{"data":{"data":{"JWT_SECRET":"s"},"metadata":{"version":3}}}`))
+	}))
+	defer srv.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("agent-token\n"), 0o600))
+	src := NewVaultSource(srv.URL, "", tokenFile, "")
+	got, err := src.Fetch(context.Background(), "secret/trakrf/backend")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"JWT_SECRET": "s"}, got)
+
+	_, err = NewVaultSource(srv.URL, "wrong", "", "").Fetch(context.Background(), "secret/trakrf/backend")
+	assert.ErrorContains(t, err, "permission denied")
+	_, err = src.Fetch(context.Background(), "secret")
+	assert.Error(t, err, "an id needs a mount and a path")
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("SECRETS_PROVIDER", "")
+	l, err := FromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, l, "unset reads credentials from the environment")
+
+	t.Setenv("SECRETS_PROVIDER", "vault")
+	t.Setenv("SECRETS_APP_ID", "secret/trakrf/backend")
+	t.Setenv("VAULT_ADDR", "https://vault.internal:8200")
+	t.Setenv("VAULT_TOKEN", "t")
+	l, err = FromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultRefreshInterval, l.Interval())
+
+	for name, env := range map[string][2]string{
+		"unknown provider": {"SECRETS_PROVIDER", "gcp"},
+		"bad interval":     {"SECRETS_REFRESH_INTERVAL", "10ms"},
+		"bad vault addr":   {"VAULT_ADDR", "vault.internal"},
+		"no secret ids":    {"SECRETS_APP_ID", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(env[0], env[1])
+			_, err := FromEnv()
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultSource reads secrets from a HashiCorp Vault KV version 2 engine.
+type VaultSource struct {
+	addr      string
+	token     string
+	tokenFile string
+	namespace string
+	client    *http.Client
+}
+
+// NewVaultSource builds a source calling the Vault at addr. The token is
+// token, or when that is empty the contents of tokenFile, re-read on every
+// fetch so a Vault Agent sidecar can renew it.
+func NewVaultSource(addr, token, tokenFile, namespace string) *VaultSource {
+	return &VaultSource{
+		addr:      addr,
+		token:     token,
+		tokenFile: tokenFile,
+		namespace: namespace,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Fetch implements Source. id is <mount>/<path>, e.g. secret/trakrf/backend
+// for the path trakrf/backend in the KV engine mounted at secret/; the
+// latest version is read.
+func (s *VaultSource) Fetch(ctx context.Context, id string) (map[string]string, error) {
+	mount, path, ok := strings.Cut(strings.Trim(id, "/"), "/")
+	if !ok || mount == "" || path == "" {
+		return nil, fmt.Errorf("vault secret id must be <mount>/<path>")
+	}
+	token := s.token
+	if token == "" {
+		raw, err := os.ReadFile(s.tokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("read vault token: %w", err)
+		}
+		token = strings.TrimSpace(string(raw))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.addr+"/v1/"+mount+"/data/"+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if s.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", s.namespace)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256<<10))
+	if err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.Unmarshal(body, &e)
+		return nil, fmt.Errorf("vault: status %d: %s", resp.StatusCode, strings.Join(e.Errors, "; "))
+	}
+	var out struct {
+		Data struct {
+			Data json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return nil, fmt.Errorf("vault: %w", err)
+	}
+	return flatten(out.Data.Data)
+}
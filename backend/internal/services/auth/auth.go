@@ -11,7 +11,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/errorreport"
+	"github.com/trakrf/platform/backend/internal/i18n"
 	"github.com/trakrf/platform/backend/internal/models/auth"
 	"github.com/trakrf/platform/backend/internal/models/organization"
 	"github.com/trakrf/platform/backend/internal/models/user"
@@ -61,72 +65,70 @@ func (s *Service) Signup(ctx context.Context, request auth.SignupRequest, userAg
 	orgName := strings.TrimSpace(request.OrgName)
 	orgIdentifier := slugifyOrgName(orgName)
 
-	tx, err := s.db.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// TRA-971: persist the contact person's real name and phone (self-service
-	// signup requires them). Previously name was a copy of the email.
 	var usr user.User
-	userQuery := `
-		INSERT INTO trakrf.users (email, name, phone, password_hash)
-		VALUES ($1, $2, $3, $4)
-		RETURNING id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at
-	`
-	err = tx.QueryRow(ctx, userQuery, request.Email, request.Name, request.Phone, passwordHash).Scan(
-		&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
-		&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
-			return nil, fmt.Errorf("email already exists")
+	var org organization.Organization
+	err = s.storage.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// TRA-971: persist the contact person's real name and phone (self-service
+		// signup requires them). Previously name was a copy of the email.
+		userQuery := `
+			INSERT INTO trakrf.users (email, name, phone, password_hash)
+			VALUES ($1, $2, $3, $4)
+			RETURNING id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at
+		`
+		err := tx.QueryRow(ctx, userQuery, request.Email, request.Name, request.Phone, passwordHash).Scan(
+			&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
+			&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt)
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+				return fmt.Errorf("email already exists")
+			}
+			return fmt.Errorf("failed to create user: %w", err)
 		}
-		return nil, fmt.Errorf("failed to create user: %w", err)
-	}
 
-	// Create organization with user-provided name and a 1-month trial expiry.
-	// Invitation-based signup (signupWithInvitation) and internal CreateOrgWithAdmin
-	// leave subscription_expires_at NULL (perpetual). Only this self-service path sets it.
-	//
-	// TRA-971: also persist the company website and seed owner_user_id to the
-	// creating user (the org owner). owner_user_id is immutable in v1; the full
-	// transfer/reassign lifecycle is TRA-1004.
-	var org organization.Organization
-	orgQuery := `
-		INSERT INTO trakrf.organizations (name, identifier, website, owner_user_id, subscription_expires_at)
-		VALUES ($1, $2, $3, $4, now() + interval '1 month')
-		RETURNING id, name, identifier, metadata, valid_from, valid_to, is_active, created_at, updated_at, subscription_expires_at
-	`
-	err = tx.QueryRow(ctx, orgQuery, orgName, orgIdentifier, request.Website, usr.ID).Scan(
-		&org.ID, &org.Name, &org.Identifier, &org.Metadata,
-		&org.ValidFrom, &org.ValidTo, &org.IsActive,
-		&org.CreatedAt, &org.UpdatedAt, &org.SubscriptionExpiresAt)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
-			return nil, fmt.Errorf("organization identifier already taken")
+		// Create organization with user-provided name and a 1-month trial expiry.
+		// Invitation-based signup (signupWithInvitation) and internal CreateOrgWithAdmin
+		// leave subscription_expires_at NULL (perpetual). Only this self-service path sets it.
+		//
+		// TRA-971: also persist the company website and seed owner_user_id to the
+		// creating user (the org owner). It changes only through an ownership
+		// transfer (POST /api/v1/orgs/{id}/transfer-ownership).
+		orgQuery := `
+			INSERT INTO trakrf.organizations (name, identifier, website, owner_user_id, subscription_expires_at)
+			VALUES ($1, $2, $3, $4, now() + interval '1 month')
+			RETURNING id, name, identifier, metadata, valid_from, valid_to, is_active, created_at, updated_at, subscription_expires_at
+		`
+		err = tx.QueryRow(ctx, orgQuery, orgName, orgIdentifier, request.Website, usr.ID).Scan(
+			&org.ID, &org.Name, &org.Identifier, &org.Metadata,
+			&org.ValidFrom, &org.ValidTo, &org.IsActive,
+			&org.CreatedAt, &org.UpdatedAt, &org.SubscriptionExpiresAt)
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+				return fmt.Errorf("organization identifier already taken")
+			}
+			return fmt.Errorf("failed to create organization: %w", err)
 		}
-		return nil, fmt.Errorf("failed to create organization: %w", err)
-	}
 
-	orgUserQuery := `
-		INSERT INTO trakrf.org_users (org_id, user_id, role)
-		VALUES ($1, $2, 'admin')
-	`
-	_, err = tx.Exec(ctx, orgUserQuery, org.ID, usr.ID)
+		orgUserQuery := `
+			INSERT INTO trakrf.org_users (org_id, user_id, role)
+			VALUES ($1, $2, 'admin')
+		`
+		_, err = tx.Exec(ctx, orgUserQuery, org.ID, usr.ID)
+		if err != nil {
+			return fmt.Errorf("failed to link user to organization: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to link user to organization: %w", err)
-	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, err
 	}
 
 	// Notify superadmins of the new self-service trial signup (TRA-967).
 	// Fire-and-forget on a detached context so it never delays or fails the
 	// signup response; only this self-service path creates a trial org, so
 	// invitation-based signup and internal org creation do not reach here.
-	go s.notifyTrialSignup(context.Background(), org, usr.Email)
+	errorreport.SafeGo(context.Background(), errorreport.Tags{"job": "notify_trial_signup"}, func() {
+		s.notifyTrialSignup(context.Background(), org, usr.Email)
+	}, nil)
 
 	accessToken, refreshToken, expiresIn, err := s.MintTokenPair(ctx, usr.ID, usr.Email, &org.ID, userAgent, ip, generateJWT)
 	if err != nil {
@@ -212,57 +214,51 @@ func (s *Service) signupWithInvitation(ctx context.Context, request auth.SignupR
 		return nil, fmt.Errorf("email_mismatch:%s", info.Email)
 	}
 
-	// Start transaction
-	tx, err := s.db.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// Create user (NO personal org)
 	var usr user.User
-	userQuery := `
-		INSERT INTO trakrf.users (email, name, password_hash)
-		VALUES ($1, $2, $3)
-		RETURNING id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at
-	`
-	err = tx.QueryRow(ctx, userQuery, request.Email, request.Email, passwordHash).Scan(
-		&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
-		&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
-			return nil, fmt.Errorf("email already exists")
+	err = s.storage.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Create user (NO personal org)
+		userQuery := `
+			INSERT INTO trakrf.users (email, name, password_hash)
+			VALUES ($1, $2, $3)
+			RETURNING id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at
+		`
+		err := tx.QueryRow(ctx, userQuery, request.Email, request.Email, passwordHash).Scan(
+			&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
+			&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt)
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+				return fmt.Errorf("email already exists")
+			}
+			return fmt.Errorf("failed to create user: %w", err)
 		}
-		return nil, fmt.Errorf("failed to create user: %w", err)
-	}
 
-	// Add user to invited org
-	addOrgUserQuery := `
-		INSERT INTO trakrf.org_users (org_id, user_id, role)
-		VALUES ($1, $2, $3)
-	`
-	_, err = tx.Exec(ctx, addOrgUserQuery, info.OrgID, usr.ID, info.Role)
-	if err != nil {
-		return nil, fmt.Errorf("failed to add user to organization: %w", err)
-	}
+		// Add user to invited org
+		addOrgUserQuery := `
+			INSERT INTO trakrf.org_users (org_id, user_id, role)
+			VALUES ($1, $2, $3)
+		`
+		_, err = tx.Exec(ctx, addOrgUserQuery, info.OrgID, usr.ID, info.Role)
+		if err != nil {
+			return fmt.Errorf("failed to add user to organization: %w", err)
+		}
 
-	// Mark invitation as accepted
-	acceptQuery := `
-		UPDATE trakrf.org_invitations
-		SET accepted_at = NOW()
-		WHERE token = $1 AND accepted_at IS NULL
-	`
-	result, err := tx.Exec(ctx, acceptQuery, tokenHash)
+		// Mark invitation as accepted
+		acceptQuery := `
+			UPDATE trakrf.org_invitations
+			SET accepted_at = NOW()
+			WHERE token = $1 AND accepted_at IS NULL
+		`
+		result, err := tx.Exec(ctx, acceptQuery, tokenHash)
+		if err != nil {
+			return fmt.Errorf("failed to accept invitation: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("already_accepted")
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to accept invitation: %w", err)
-	}
-	if result.RowsAffected() == 0 {
-		return nil, fmt.Errorf("already_accepted")
-	}
-
-	// Commit transaction
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, err
 	}
 
 	accessToken, refreshToken, expiresIn, err := s.MintTokenPair(ctx, usr.ID, usr.Email, &info.OrgID, userAgent, ip, generateJWT)
@@ -331,7 +327,13 @@ func (s *Service) Login(ctx context.Context, request auth.LoginRequest, userAgen
 		}
 	}
 
-	accessToken, refreshToken, expiresIn, err := s.MintTokenPair(ctx, usr.ID, usr.Email, orgIDPtr, userAgent, ip, generateJWT)
+	// An org-agnostic pair carries no org, and refreshing it keeps it that
+	// way; requests are scoped by host (middleware.ScopeToHostOrg).
+	tokenOrgID := orgIDPtr
+	if request.OrgAgnostic {
+		tokenOrgID = nil
+	}
+	accessToken, refreshToken, expiresIn, err := s.MintTokenPair(ctx, usr.ID, usr.Email, tokenOrgID, userAgent, ip, generateJWT)
 	if err != nil {
 		return nil, err
 	}
@@ -345,8 +347,10 @@ func (s *Service) Login(ctx context.Context, request auth.LoginRequest, userAgen
 }
 
 // ForgotPassword initiates a password reset flow by sending an email with a reset token.
+// requestedOrigin only suggests where the link points (see linkOrigin); the
+// path is always the app's reset page.
 // Always returns nil to avoid leaking whether an email exists in the system.
-func (s *Service) ForgotPassword(ctx context.Context, emailAddr, resetURL string) error {
+func (s *Service) ForgotPassword(ctx context.Context, emailAddr, requestedOrigin string) error {
 	// Look up user by email
 	usr, err := s.storage.GetUserByEmail(ctx, emailAddr)
 	if err != nil {
@@ -382,7 +386,8 @@ func (s *Service) ForgotPassword(ctx context.Context, emailAddr, resetURL string
 
 	// Send email via Resend
 	if s.emailClient != nil {
-		if err := s.emailClient.SendPasswordResetEmail(emailAddr, resetURL, token); err != nil {
+		resetURL := applinks.PasswordResetURL(s.linkOrigin(ctx, usr.ID, requestedOrigin))
+		if err := s.emailClient.SendPasswordResetEmail(emailAddr, resetURL, token, email.Options{Locale: i18n.FromContext(ctx)}); err != nil {
 			fmt.Printf("Warning: failed to send password reset email: %v\n", err)
 			// Token is stored, but email failed - user can try again
 		}
@@ -391,6 +396,23 @@ func (s *Service) ForgotPassword(ctx context.Context, emailAddr, resetURL string
 	return nil
 }
 
+// linkOrigin returns the origin for a link mailed to the user. requested
+// wins only when the environment allows it or it is one of the link domains
+// of the user's preferred org; lookup failures fall back to the base URL.
+func (s *Service) linkOrigin(ctx context.Context, userID int, requested string) string {
+	links := applinks.FromEnv()
+	if requested == "" {
+		return links.Origin("", nil)
+	}
+	var orgOrigins []string
+	if orgID, err := s.storage.GetUserPreferredOrgID(ctx, userID); err == nil && orgID != nil {
+		if ld, err := s.storage.GetLinkDomains(ctx, *orgID); err == nil && ld != nil {
+			orgOrigins = ld.Origins
+		}
+	}
+	return links.Origin(requested, orgOrigins)
+}
+
 // ResetPassword validates a token and updates the user's password.
 func (s *Service) ResetPassword(ctx context.Context, token, newPassword string, hashPassword func(string) (string, error)) error {
 	// Look up token
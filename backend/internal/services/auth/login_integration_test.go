@@ -73,3 +73,49 @@ func TestLogin_PopulatesLastLoginAt(t *testing.T) {
 	assert.True(t, resp2.User.LastLoginAt.After(*resp.User.LastLoginAt),
 		"second login timestamp %v must be after first %v", resp2.User.LastLoginAt, resp.User.LastLoginAt)
 }
+
+// An org-agnostic login mints tokens bound to no org, even for a user with a
+// preferred org; the host the request arrives on picks the org instead.
+func TestLogin_OrgAgnosticTokenCarriesNoOrg(t *testing.T) {
+	t.Setenv("JWT_SECRET", "login-test")
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+
+	const email = "agnostic@example.com"
+	hash, err := password.Hash("s3cret!!")
+	require.NoError(t, err)
+
+	var userID int
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO trakrf.users (name, email, password_hash)
+		VALUES ($1, $2, $3) RETURNING id`,
+		"Agnostic", email, hash,
+	).Scan(&userID))
+	_, err = pool.Exec(ctx, `
+		INSERT INTO trakrf.org_users (org_id, user_id, role, status)
+		VALUES ($1, $2, 'admin', 'active')`, orgID, userID)
+	require.NoError(t, err)
+
+	svc := NewService(pool, store, nil)
+
+	var minted []*int
+	captureJWT := func(_ int, _ string, org *int) (string, error) {
+		minted = append(minted, org)
+		return "stub-token", nil
+	}
+	_, err = svc.Login(ctx, authmodels.LoginRequest{Email: email, Password: "s3cret!!"},
+		"", "", password.Compare, captureJWT)
+	require.NoError(t, err)
+	_, err = svc.Login(ctx, authmodels.LoginRequest{Email: email, Password: "s3cret!!", OrgAgnostic: true},
+		"", "", password.Compare, captureJWT)
+	require.NoError(t, err)
+
+	require.Len(t, minted, 2)
+	require.NotNil(t, minted[0], "a default login is bound to the preferred org")
+	assert.Equal(t, orgID, *minted[0])
+	assert.Nil(t, minted[1])
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/i18n"
+	"github.com/trakrf/platform/backend/internal/models/auth"
+	modelerrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/user"
+	"github.com/trakrf/platform/backend/internal/services/email"
+)
+
+// ErrCurrentPasswordIncorrect is returned when a profile change is attempted
+// with the wrong current password.
+var ErrCurrentPasswordIncorrect = errors.New("current password is incorrect")
+
+// ErrEmailUnchanged is returned when an email change names the address the
+// account already has.
+var ErrEmailUnchanged = errors.New("new email is the current email")
+
+// emailChangeTTL is how long an email change link stays valid, matching the
+// password reset link.
+const emailChangeTTL = 24 * time.Hour
+
+// ChangePassword replaces the user's password after checking the current one.
+// Every existing session is signed out and a fresh token pair is returned for
+// the caller's, so only the device that made the change stays signed in.
+func (s *Service) ChangePassword(ctx context.Context, userID int, orgID *int, currentPassword, newPassword, userAgent, ip string, comparePassword func(string, string) error, hashPassword func(string) (string, error), generateJWT func(int, string, *int) (string, error)) (*auth.RefreshResponse, error) {
+	usr, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup user: %w", err)
+	}
+	if usr == nil {
+		return nil, modelerrors.ErrUserNotFound
+	}
+	if err := comparePassword(currentPassword, usr.PasswordHash); err != nil {
+		return nil, ErrCurrentPasswordIncorrect
+	}
+
+	passwordHash, err := hashPassword(newPassword)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	if err := s.storage.UpdateUserPassword(ctx, userID, passwordHash); err != nil {
+		return nil, fmt.Errorf("failed to update password: %w", err)
+	}
+	if err := s.storage.RevokeUserRefreshTokens(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	// An outstanding reset link would otherwise still undo the change.
+	if err := s.storage.DeleteUserPasswordResetTokens(ctx, userID); err != nil {
+		fmt.Printf("Warning: failed to delete password reset tokens: %v\n", err)
+	}
+
+	accessToken, refreshToken, expiresIn, err := s.MintTokenPair(ctx, usr.ID, usr.Email, orgID, userAgent, ip, generateJWT)
+	if err != nil {
+		return nil, err
+	}
+	return &auth.RefreshResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    expiresIn,
+	}, nil
+}
+
+// RequestEmailChange checks the current password and mails a confirmation
+// link to newEmail. The account keeps its address until ConfirmEmailChange
+// runs with the mailed token; a new request replaces any pending one.
+// requestedOrigin is handled as in ForgotPassword.
+func (s *Service) RequestEmailChange(ctx context.Context, userID int, newEmail, currentPassword, requestedOrigin string, comparePassword func(string, string) error) error {
+	usr, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to lookup user: %w", err)
+	}
+	if usr == nil {
+		return modelerrors.ErrUserNotFound
+	}
+	if err := comparePassword(currentPassword, usr.PasswordHash); err != nil {
+		return ErrCurrentPasswordIncorrect
+	}
+	if strings.EqualFold(newEmail, usr.Email) {
+		return ErrEmailUnchanged
+	}
+	taken, err := s.storage.UserExistsByEmail(ctx, newEmail)
+	if err != nil {
+		return fmt.Errorf("failed to check email availability: %w", err)
+	}
+	if taken {
+		return modelerrors.ErrUserDuplicateEmail
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return fmt.Errorf("failed to generate email change token: %w", err)
+	}
+	hash := sha256.Sum256([]byte(token))
+	if err := s.storage.CreateEmailChangeToken(ctx, userID, newEmail, hex.EncodeToString(hash[:]), time.Now().Add(emailChangeTTL)); err != nil {
+		return err
+	}
+
+	if s.emailClient != nil {
+		confirmURL := applinks.EmailChangeURL(s.linkOrigin(ctx, userID, requestedOrigin))
+		if err := s.emailClient.SendEmailChangeEmail(newEmail, confirmURL, token, email.Options{Locale: i18n.FromContext(ctx)}); err != nil {
+			return fmt.Errorf("failed to send email change email: %w", err)
+		}
+	}
+	return nil
+}
+
+// ConfirmEmailChange applies the pending email change the token names and
+// returns the updated user.
+func (s *Service) ConfirmEmailChange(ctx context.Context, token string) (*user.User, error) {
+	hash := sha256.Sum256([]byte(token))
+	userID, err := s.storage.ConfirmEmailChange(ctx, hex.EncodeToString(hash[:]))
+	if err != nil {
+		return nil, err
+	}
+	usr, err := s.storage.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return usr, nil
+}
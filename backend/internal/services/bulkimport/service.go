@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"mime/multipart"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/trakrf/platform/backend/internal/errorreport"
 	"github.com/trakrf/platform/backend/internal/models/asset"
 	"github.com/trakrf/platform/backend/internal/models/bulkimport"
 	"github.com/trakrf/platform/backend/internal/models/shared"
@@ -25,16 +29,121 @@ func isEmptyRow(row []string) bool {
 
 const ProgressUpdateInterval = 10
 
+// CopyImportThreshold is the row count from which a fresh import is inserted
+// through the storage COPY path instead of row by row.
+const CopyImportThreshold = 500
+
+// interruptTimeout bounds the checkpoint write made while shutting down.
+const interruptTimeout = 5 * time.Second
+
 type Service struct {
 	storage   *storage.Storage
 	validator *Validator
+
+	// stopping is closed by Shutdown. Running imports check it between rows
+	// and park their job as interrupted instead of inserting further.
+	stopping chan struct{}
+	// mu orders track's inflight.Add against Shutdown closing stopping, so
+	// no import starts once Shutdown may be waiting.
+	mu       sync.Mutex
+	inflight sync.WaitGroup
 }
 
 func NewService(storage *storage.Storage) *Service {
 	return &Service{
 		storage:   storage,
 		validator: NewValidator(),
+		stopping:  make(chan struct{}),
+	}
+}
+
+// Shutdown stops every running import at its next row boundary, parking the
+// job as interrupted so ResumeInterrupted (on any replica) can finish it, and
+// waits for them to return or ctx to expire. New uploads are still accepted
+// until the HTTP server stops routing; their jobs are parked the same way.
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.isStopping() {
+		close(s.stopping)
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("bulk import drain: %w", ctx.Err())
+	}
+}
+
+func (s *Service) isStopping() bool {
+	select {
+	case <-s.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// track counts an import as in flight for Shutdown to wait on. It returns
+// false, counting nothing, once Shutdown has begun.
+func (s *Service) track() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isStopping() {
+		return false
+	}
+	s.inflight.Add(1)
+	return true
+}
+
+// ResumeInterrupted claims interrupted jobs and continues them in the
+// background. It runs as a periodic job, so imports parked by a replica that
+// shut down are picked up by whichever replica is still (or next) running.
+func (s *Service) ResumeInterrupted(ctx context.Context) error {
+	if s.isStopping() {
+		return nil
+	}
+
+	jobs, err := s.storage.ListInterruptedBulkImportJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, j := range jobs {
+		// Tracked before the claim, so a job is never claimed by a replica
+		// that is already shutting down and would not run it.
+		if !s.track() {
+			return nil
+		}
+		st, err := s.storage.ClaimInterruptedBulkImportJob(ctx, j.OrgID, j.ID)
+		if err != nil {
+			s.inflight.Done()
+			return err
+		}
+		if st == nil {
+			s.inflight.Done()
+			continue
+		}
+		if len(st.Records) == 0 {
+			s.storage.UpdateBulkImportJobStatus(ctx, j.OrgID, j.ID, "failed")
+			s.inflight.Done()
+			continue
+		}
+
+		fmt.Printf("Resuming bulk import job %d at row %d\n", j.ID, st.ResumeRow)
+		go func(jobID, orgID int, st *bulkimport.ResumeState) {
+			defer s.inflight.Done()
+			s.runImport(context.Background(), jobID, orgID, st.Records, st.Records[0], st)
+		}(j.ID, j.OrgID, st)
 	}
+
+	return nil
 }
 
 func (s *Service) ProcessUpload(
@@ -66,7 +175,16 @@ func (s *Service) ProcessUpload(
 		Message:   fmt.Sprintf("CSV upload accepted. Processing %d rows asynchronously.", totalRows),
 	}
 
-	go s.processCSVAsync(context.Background(), job.ID, orgID, records, headers)
+	if !s.track() {
+		// Shutdown has begun and will not wait for a new goroutine; the
+		// import parks the job before inserting a row, so run it here.
+		s.processCSVAsync(context.WithoutCancel(ctx), job.ID, orgID, records, headers)
+		return response, nil
+	}
+	go func() {
+		defer s.inflight.Done()
+		s.processCSVAsync(context.Background(), job.ID, orgID, records, headers)
+	}()
 
 	return response, nil
 }
@@ -78,31 +196,59 @@ func (s *Service) processCSVAsync(
 	records [][]string,
 	headers []string,
 ) {
-	defer func() {
-		if r := recover(); r != nil {
-			panicErr := bulkimport.ErrorDetail{
-				Row:   0,
-				Field: "system",
-				Error: fmt.Sprintf("Panic during processing: %v", r),
-			}
-			fmt.Printf("PANIC in processCSVAsync for job %d: %v\n", jobID, r)
-			s.storage.UpdateBulkImportJobProgress(ctx, orgID, jobID, 0, 1, 0, []bulkimport.ErrorDetail{panicErr})
-			s.storage.UpdateBulkImportJobStatus(ctx, orgID, jobID, "failed")
-		}
-	}()
+	s.runImport(ctx, jobID, orgID, records, headers, nil)
+}
+
+// runImport validates and inserts records for jobID. resume is nil for a
+// fresh job; for a resumed one the job is already processing, validation is
+// re-run (it is deterministic), and inserts continue from resume.ResumeRow
+// with the saved counters. A panic fails the job with the panic message
+// rather than leaving it processing.
+func (s *Service) runImport(
+	ctx context.Context,
+	jobID int,
+	orgID int,
+	records [][]string,
+	headers []string,
+	resume *bulkimport.ResumeState,
+) {
+	tags := errorreport.Tags{
+		"job":           "bulk_import",
+		"import_job_id": strconv.Itoa(jobID),
+		"org_id":        strconv.Itoa(orgID),
+	}
+	errorreport.Safely(ctx, tags, func() {
+		s.importRecords(ctx, jobID, orgID, records, headers, resume)
+	}, func(recovered any) {
+		s.failJob(ctx, jobID, orgID, fmt.Sprintf("Panic during processing: %v", recovered))
+	})
+}
+
+// failJob marks jobID failed with a single system error.
+func (s *Service) failJob(ctx context.Context, jobID, orgID int, msg string) {
+	detail := bulkimport.ErrorDetail{Row: 0, Field: "system", Error: msg}
+	s.storage.UpdateBulkImportJobProgress(ctx, orgID, jobID, 0, 1, 0, []bulkimport.ErrorDetail{detail})
+	s.storage.UpdateBulkImportJobStatus(ctx, orgID, jobID, "failed")
+}
 
+// importRecords is the body of runImport.
+func (s *Service) importRecords(
+	ctx context.Context,
+	jobID int,
+	orgID int,
+	records [][]string,
+	headers []string,
+	resume *bulkimport.ResumeState,
+) {
 	fmt.Printf("Starting processCSVAsync for job %d, orgID %d, records: %d\n", jobID, orgID, len(records))
 
-	if err := s.storage.UpdateBulkImportJobStatus(ctx, orgID, jobID, "processing"); err != nil {
-		fmt.Printf("Failed to update job status to processing for job %d: %v\n", jobID, err)
-		panicErr := bulkimport.ErrorDetail{
-			Row:   0,
-			Field: "system",
-			Error: fmt.Sprintf("Failed to update job status: %v", err),
+	// Claimed (resumed) jobs are already processing.
+	if resume == nil {
+		if err := s.storage.UpdateBulkImportJobStatus(ctx, orgID, jobID, "processing"); err != nil {
+			fmt.Printf("Failed to update job status to processing for job %d: %v\n", jobID, err)
+			s.failJob(ctx, jobID, orgID, fmt.Sprintf("Failed to update job status: %v", err))
+			return
 		}
-		s.storage.UpdateBulkImportJobProgress(ctx, orgID, jobID, 0, 1, 0, []bulkimport.ErrorDetail{panicErr})
-		s.storage.UpdateBulkImportJobStatus(ctx, orgID, jobID, "failed")
-		return
 	}
 
 	dataRows := records[1:]
@@ -163,7 +309,7 @@ func (s *Service) processCSVAsync(
 	for ek, rowNumbers := range externalKeyToRows {
 		if len(rowNumbers) > 1 {
 			for _, rowNum := range rowNumbers {
-				fmt.Printf("Duplicate external_key '%s' at row %d in CSV for job %d\n", ek, rowNum, jobID)
+				fmt.Printf("Duplicate external_key at row %d in CSV for job %d\n", rowNum, jobID)
 				allErrors = append(allErrors, bulkimport.ErrorDetail{
 					Row:   rowNum,
 					Field: "external_key",
@@ -184,7 +330,7 @@ func (s *Service) processCSVAsync(
 	for tag, rowNumbers := range tagToRows {
 		if len(rowNumbers) > 1 {
 			for _, rowNum := range rowNumbers {
-				fmt.Printf("Duplicate tag '%s' at row %d in CSV for job %d\n", tag, rowNum, jobID)
+				fmt.Printf("Duplicate tag at row %d in CSV for job %d\n", rowNum, jobID)
 				allErrors = append(allErrors, bulkimport.ErrorDetail{
 					Row:   rowNum,
 					Field: "tags",
@@ -203,51 +349,58 @@ func (s *Service) processCSVAsync(
 		return
 	}
 
-	// PHASE 5: Insert assets with tags one at a time
-	// Using per-row insertion to capture per-row errors for duplicate tags from DB
+	// PHASE 5: Insert assets with tags
 	fmt.Printf("All validations passed. Inserting %d assets for job %d\n", len(validRows), jobID)
 
+	requests := make([]asset.CreateAssetWithTagsRequest, len(validRows))
+	for i, pr := range validRows {
+		requests[i] = createRequest(pr.asset, pr.tagValues)
+	}
+
+	// Large fresh imports go through the COPY path in one transaction. It is
+	// all-or-nothing, so if any row fails (a key or tag already taken) the
+	// import reruns row by row below, which saves the good rows and reports
+	// each bad one. Resumed jobs stay row by row: part of them is committed.
+	if resume == nil && len(requests) >= CopyImportThreshold && !s.isStopping() {
+		assets, tags, err := s.storage.CopyCreateAssetsWithTags(ctx, orgID, requests)
+		if err == nil {
+			fmt.Printf("Successfully completed job %d with %d assets and %d tags\n", jobID, assets, tags)
+			s.storage.UpdateBulkImportJobProgress(ctx, orgID, jobID, assets, 0, tags, nil)
+			s.storage.UpdateBulkImportJobStatus(ctx, orgID, jobID, "completed")
+			return
+		}
+		fmt.Printf("COPY import failed for job %d, inserting row by row: %v\n", jobID, err)
+	}
+
+	// Per-row insertion captures per-row errors for duplicates from the DB.
 	var successCount int
 	var tagsCreated int
 	var insertErrors []bulkimport.ErrorDetail
+	startRow := 0
+	if resume != nil {
+		successCount = resume.ProcessedRows
+		tagsCreated = resume.TagsCreated
+		insertErrors = resume.Errors
+		startRow = resume.ResumeRow
+	}
 
-	for _, pr := range validRows {
-		// Convert tag values to TagRequest with type "rfid"
-		identifiers := make([]shared.TagRequest, len(pr.tagValues))
-		rfid := shared.DefaultTagType
-		for i, tag := range pr.tagValues {
-			identifiers[i] = shared.TagRequest{
-				TagType: &rfid,
-				Value:   tag,
-			}
-		}
-
-		// Build CreateAssetWithTagsRequest. The parsed row always has
-		// concrete ValidFrom / IsActive values; wrap them as pointers.
-		validFrom := shared.FlexibleDate{Time: pr.asset.ValidFrom}
-		isActive := pr.asset.IsActive
-		var descPtr *string
-		if pr.asset.Description != "" {
-			d := pr.asset.Description
-			descPtr = &d
-		}
-		request := asset.CreateAssetWithTagsRequest{
-			CreateAssetRequest: asset.CreateAssetRequest{
-				OrgID:       pr.asset.OrgID,
-				ExternalKey: pr.asset.ExternalKey,
-				Name:        pr.asset.Name,
-				Description: descPtr,
-				ValidFrom:   &validFrom,
-				IsActive:    &isActive,
-			},
-			Tags: identifiers,
-		}
-		if pr.asset.ValidTo != nil {
-			validTo := shared.FlexibleDate{Time: *pr.asset.ValidTo}
-			request.ValidTo = &validTo
+	for i := startRow; i < len(validRows); i++ {
+		pr := validRows[i]
+
+		if s.isStopping() {
+			fmt.Printf("Shutting down: interrupting job %d at row %d of %d\n", jobID, i, len(validRows))
+			s.interrupt(ctx, jobID, orgID, bulkimport.ResumeState{
+				Records:       records,
+				ResumeRow:     i,
+				ProcessedRows: successCount,
+				FailedRows:    len(insertErrors),
+				TagsCreated:   tagsCreated,
+				Errors:        insertErrors,
+			})
+			return
 		}
 
-		_, err := s.storage.CreateAssetWithTags(ctx, request)
+		_, err := s.storage.CreateAssetWithTags(ctx, requests[i])
 		if err != nil {
 			fmt.Printf("Insert error at row %d for job %d: %v\n", pr.rowNumber, jobID, err)
 			insertErrors = append(insertErrors, bulkimport.ErrorDetail{
@@ -277,3 +430,53 @@ func (s *Service) processCSVAsync(
 	s.storage.UpdateBulkImportJobProgress(ctx, orgID, jobID, successCount, 0, tagsCreated, nil)
 	s.storage.UpdateBulkImportJobStatus(ctx, orgID, jobID, "completed")
 }
+
+// createRequest builds the create request for a parsed CSV row, its tag
+// values becoming RFID tags.
+func createRequest(a *asset.Asset, tagValues []string) asset.CreateAssetWithTagsRequest {
+	tags := make([]shared.TagRequest, len(tagValues))
+	rfid := shared.DefaultTagType
+	for i, tag := range tagValues {
+		tags[i] = shared.TagRequest{
+			TagType: &rfid,
+			Value:   tag,
+		}
+	}
+
+	// The parsed row always has concrete ValidFrom / IsActive values; wrap
+	// them as pointers.
+	validFrom := shared.FlexibleDate{Time: a.ValidFrom}
+	isActive := a.IsActive
+	var descPtr *string
+	if a.Description != "" {
+		d := a.Description
+		descPtr = &d
+	}
+	request := asset.CreateAssetWithTagsRequest{
+		CreateAssetRequest: asset.CreateAssetRequest{
+			OrgID:       a.OrgID,
+			ExternalKey: a.ExternalKey,
+			Name:        a.Name,
+			Description: descPtr,
+			ValidFrom:   &validFrom,
+			IsActive:    &isActive,
+		},
+		Tags: tags,
+	}
+	if a.ValidTo != nil {
+		validTo := shared.FlexibleDate{Time: *a.ValidTo}
+		request.ValidTo = &validTo
+	}
+	return request
+}
+
+// interrupt parks the job for resume. The write gets its own deadline so a
+// slow database cannot hold up shutdown indefinitely; if it fails the job is
+// left processing, the same outcome as a crash.
+func (s *Service) interrupt(ctx context.Context, jobID, orgID int, st bulkimport.ResumeState) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), interruptTimeout)
+	defer cancel()
+	if err := s.storage.InterruptBulkImportJob(ctx, orgID, jobID, st); err != nil {
+		fmt.Printf("Failed to interrupt job %d: %v\n", jobID, err)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -297,7 +298,9 @@ func TestErrorRecovery_Panic(t *testing.T) {
 
 	status, err := store.GetBulkImportJobByID(ctx, job.ID, orgID)
 	require.NoError(t, err)
-	assert.Contains(t, []string{"pending", "processing", "failed"}, status.Status)
+	assert.Equal(t, "failed", status.Status, "a panic must fail the job, not leave it processing")
+	require.NotEmpty(t, status.Errors)
+	assert.Contains(t, status.Errors[0].Error, "Panic during processing")
 }
 
 func TestErrorRecovery_DatabaseFailure(t *testing.T) {
@@ -598,3 +601,138 @@ ASSET-TAG-002,Tagged Asset 2,More tags,2024-01-01,2024-12-31,true,"RFID_002,RFID
 	assert.NotNil(t, job)
 	assert.Equal(t, 2, job.TotalRows)
 }
+
+func TestShutdown_InterruptsAndResumes(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	defer testutil.CleanupAssets(t, pool)
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+
+	ctx := context.Background()
+
+	csvFactory := testutil.NewCSVFactory().
+		AddRow("RESUME-001", "Asset 1", "First asset", "2024-01-01", "2024-12-31", "true").
+		AddRow("RESUME-002", "Asset 2", "Second asset", "2024-01-01", "2024-12-31", "true")
+	records := csvFactory.Build()
+
+	job, err := store.CreateBulkImportJob(ctx, orgID, len(records)-1)
+	require.NoError(t, err)
+
+	// A replica already shutting down parks the job before its first insert.
+	stopped := NewService(store)
+	require.NoError(t, stopped.Shutdown(ctx))
+	stopped.processCSVAsync(ctx, job.ID, orgID, records, records[0])
+
+	jobStatus, err := store.GetBulkImportJobByID(ctx, job.ID, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, "interrupted", jobStatus.Status)
+	assert.Equal(t, 0, jobStatus.ProcessedRows)
+
+	// A stopping service never claims.
+	require.NoError(t, stopped.ResumeInterrupted(ctx))
+	jobStatus, err = store.GetBulkImportJobByID(ctx, job.ID, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, "interrupted", jobStatus.Status)
+
+	// A live replica claims and finishes it.
+	live := NewService(store)
+	require.NoError(t, live.ResumeInterrupted(ctx))
+	require.Eventually(t, func() bool {
+		s, err := store.GetBulkImportJobByID(ctx, job.ID, orgID)
+		return err == nil && s.Status == "completed"
+	}, 10*time.Second, 50*time.Millisecond)
+
+	jobStatus, err = store.GetBulkImportJobByID(ctx, job.ID, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, jobStatus.ProcessedRows)
+
+	var payloadCleared bool
+	err = pool.QueryRow(ctx, "SELECT payload IS NULL FROM trakrf.bulk_import_jobs WHERE id = $1", job.ID).Scan(&payloadCleared)
+	require.NoError(t, err)
+	assert.True(t, payloadCleared, "payload must be cleared once the job completes")
+
+	// Already claimed: a second resume pass finds nothing.
+	require.NoError(t, live.ResumeInterrupted(ctx))
+}
+
+func TestProcessCSVAsync_LargeImportUsesCopyPath(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	defer testutil.CleanupAssets(t, pool)
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+
+	ctx := context.Background()
+	service := NewService(store)
+
+	csvFactory := testutil.NewCSVFactory()
+	for i := 0; i < CopyImportThreshold; i++ {
+		csvFactory.AddRowWithTags(fmt.Sprintf("COPY-%04d", i), fmt.Sprintf("Copy Asset %d", i), "Bulk", "2024-01-01", "2024-12-31", "true", fmt.Sprintf("COPYTAG%04d", i))
+	}
+	records := csvFactory.Build()
+
+	job, err := store.CreateBulkImportJob(ctx, orgID, len(records)-1)
+	require.NoError(t, err)
+
+	service.processCSVAsync(ctx, job.ID, orgID, records, records[0])
+
+	jobStatus, err := store.GetBulkImportJobByID(ctx, job.ID, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", jobStatus.Status)
+	assert.Equal(t, CopyImportThreshold, jobStatus.ProcessedRows)
+	assert.Equal(t, CopyImportThreshold, jobStatus.TagsCreated)
+
+	var tagged int
+	err = pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM trakrf.tags t JOIN trakrf.assets a ON a.id = t.asset_id
+		WHERE a.org_id = $1 AND t.value = 'COPYTAG' || substr(a.external_key, 6)`, orgID).Scan(&tagged)
+	require.NoError(t, err)
+	assert.Equal(t, CopyImportThreshold, tagged, "each tag must land on its own row's asset")
+}
+
+func TestProcessCSVAsync_LargeImportFallsBackPerRow(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+
+	pool := store.Pool().(*pgxpool.Pool)
+	defer testutil.CleanupAssets(t, pool)
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	defer testutil.CleanupTestAccounts(t, pool)
+
+	ctx := context.Background()
+	service := NewService(store)
+
+	// An asset that already exists makes the COPY path fail as a whole.
+	count, errs := store.BatchCreateAssets(ctx, []asset.Asset{
+		testutil.NewAssetFactory(orgID).WithIdentifier("FALLBACK-0000").Build(),
+	})
+	require.Empty(t, errs)
+	require.Equal(t, 1, count)
+
+	csvFactory := testutil.NewCSVFactory()
+	for i := 0; i < CopyImportThreshold; i++ {
+		csvFactory.AddRow(fmt.Sprintf("FALLBACK-%04d", i), fmt.Sprintf("Fallback Asset %d", i), "Bulk", "2024-01-01", "2024-12-31", "true")
+	}
+	records := csvFactory.Build()
+
+	job, err := store.CreateBulkImportJob(ctx, orgID, len(records)-1)
+	require.NoError(t, err)
+
+	service.processCSVAsync(ctx, job.ID, orgID, records, records[0])
+
+	jobStatus, err := store.GetBulkImportJobByID(ctx, job.ID, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, "completed", jobStatus.Status)
+	assert.Equal(t, CopyImportThreshold-1, jobStatus.ProcessedRows)
+	require.Len(t, jobStatus.Errors, 1)
+	assert.Equal(t, 2, jobStatus.Errors[0].Row)
+	assert.Contains(t, jobStatus.Errors[0].Error, "FALLBACK-0000")
+}
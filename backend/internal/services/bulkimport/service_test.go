@@ -1,6 +1,11 @@
 package bulkimport
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
 
 func TestIsEmptyRow(t *testing.T) {
 	tests := []struct {
@@ -79,3 +84,51 @@ func TestIsEmptyRow(t *testing.T) {
 		})
 	}
 }
+
+func TestShutdown_WaitsForInflight(t *testing.T) {
+	s := NewService(nil)
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown with nothing in flight: %v", err)
+	}
+	if !s.isStopping() {
+		t.Fatal("isStopping = false after Shutdown")
+	}
+	// Idempotent.
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("second Shutdown: %v", err)
+	}
+
+	s = NewService(nil)
+	s.inflight.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown with stuck import: err = %v, want deadline exceeded", err)
+	}
+	s.inflight.Done()
+}
+
+func TestTrack_RefusesOnceShutdownBegins(t *testing.T) {
+	s := NewService(nil)
+	if !s.track() {
+		t.Fatal("track = false before Shutdown")
+	}
+	s.inflight.Done()
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if s.track() {
+		t.Fatal("track = true after Shutdown")
+	}
+	// Nothing was counted, so a later Shutdown does not wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown: %v", err)
+	}
+	// A stopped service claims nothing; the nil storage is never touched.
+	if err := s.ResumeInterrupted(context.Background()); err != nil {
+		t.Fatalf("ResumeInterrupted after Shutdown: %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package bulkimport
+
+import (
+	"bytes"
+	"testing"
+)
+
+// memFile is an in-memory multipart.File.
+type memFile struct{ *bytes.Reader }
+
+func (memFile) Close() error { return nil }
+
+// FuzzParseAndValidateCSV runs arbitrary uploads through the upload
+// validator. Whatever it accepts must be a header row plus 1..MaxRows data
+// rows, since the import job indexes rows on that assumption. Run with:
+//
+//	go test -run '^$' -fuzz FuzzParseAndValidateCSV ./internal/services/bulkimport/
+func FuzzParseAndValidateCSV(f *testing.F) {
+	for _, s := range []string{
+		"name\nForklift\n",
+		"external_key,name,tags\nFL-1,Forklift,\"E280,E281\"\n",
+		"name\n",
+		"",
+		"description\nno name column\n",
+		"name,name\na,b\n",
+		"name\n\"a\"\"b\"\n",
+		"name\r\nwindows\r\n",
+		"a,\"b\nc\",d\n",
+	} {
+		f.Add([]byte(s))
+	}
+	v := NewValidator()
+	f.Fuzz(func(t *testing.T, upload []byte) {
+		records, headers, err := v.ParseAndValidateCSV(memFile{bytes.NewReader(upload)})
+		if err != nil {
+			return
+		}
+		if len(records) < 2 || len(records)-1 > MaxRows {
+			t.Fatalf("accepted %d records", len(records))
+		}
+		if len(headers) != len(records[0]) {
+			t.Fatalf("headers %q are not the first record %q", headers, records[0])
+		}
+		for i, h := range headers {
+			if h != records[0][i] {
+				t.Fatalf("headers %q are not the first record %q", headers, records[0])
+			}
+		}
+	})
+}
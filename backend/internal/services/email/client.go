@@ -1,12 +1,14 @@
 package email
 
 import (
+	"context"
 	"fmt"
+	"html"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/resend/resend-go/v2"
 	"github.com/rs/zerolog/log"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
@@ -45,17 +47,84 @@ func isReservedTestRecipient(addr string) bool {
 	return false
 }
 
-// Client wraps the Resend email client
+// Client renders TrakRF's emails and sends them through a Mailer.
 type Client struct {
-	client *resend.Client
+	// mu guards the fields below, which Reconfigure replaces.
+	mu     sync.RWMutex
+	mailer Mailer
+	from   string
+	// missing is why the mailer cannot send, or nil.
+	missing error
 }
 
-// NewClient creates a new email client using the RESEND_API_KEY environment variable
+// NewClient creates an email client from the EMAIL_PROVIDER environment (see
+// MailerConfigFromEnv). An invalid setting leaves the client unconfigured;
+// config.Load has already refused to boot on it, so this only matters to
+// tests and tools.
 func NewClient() *Client {
-	apiKey := os.Getenv("RESEND_API_KEY")
-	return &Client{
-		client: resend.NewClient(apiKey),
+	cfg, err := MailerConfigFromEnv()
+	if err != nil {
+		return &Client{mailer: unconfiguredMailer{err: err}, from: DefaultFrom, missing: err}
 	}
+	return NewClientWithConfig(cfg)
+}
+
+// NewClientWithConfig creates an email client for cfg.
+func NewClientWithConfig(cfg MailerConfig) *Client {
+	return &Client{mailer: NewMailer(cfg), from: cfg.From, missing: cfg.Missing()}
+}
+
+// NewClientWithMailer creates an email client that sends through m as from.
+func NewClientWithMailer(m Mailer, from string) *Client {
+	return &Client{mailer: m, from: from}
+}
+
+// Reconfigure switches the client to cfg, for credentials refreshed while
+// the server runs (see the secrets package). Sends in flight finish on the
+// old mailer.
+func (c *Client) Reconfigure(cfg MailerConfig) {
+	mailer := NewMailer(cfg)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mailer, c.from, c.missing = mailer, cfg.From, cfg.Missing()
+}
+
+// Configured reports whether the provider has what it needs to send. Without
+// it every send fails, so the readiness probe reports email as unavailable.
+func (c *Client) Configured() bool {
+	return c.ConfigError() == nil
+}
+
+// ConfigError returns why the provider cannot send (e.g. "RESEND_API_KEY not
+// set"), or nil when it can.
+func (c *Client) ConfigError() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.missing
+}
+
+// send delivers msg from the configured sender.
+func (c *Client) send(msg Message) error {
+	c.mu.RLock()
+	mailer, from := c.mailer, c.from
+	c.mu.RUnlock()
+	msg.From = from
+	return mailer.Send(context.Background(), msg)
+}
+
+// sendTemplate renders a templated email and sends it to toEmail.
+func (c *Client) sendTemplate(toEmail, name string, opts Options, data TemplateData) error {
+	r, err := Render(name, opts, data)
+	if err != nil {
+		return err
+	}
+	return c.send(Message{
+		To:      []string{toEmail},
+		ReplyTo: opts.Branding.ReplyTo,
+		Subject: r.Subject,
+		HTML:    r.HTML,
+		Text:    r.Text,
+	})
 }
 
 // getEmailPrefix returns the appropriate email subject prefix based on APP_ENV.
@@ -86,17 +155,16 @@ func OrgNotifyOverride() []string {
 // getEnvironmentNotice returns an HTML notice for non-production environments.
 // Returns empty string for production/empty.
 func getEnvironmentNotice() string {
-	env := os.Getenv("APP_ENV")
-	if env == "" || env == "production" || env == "prod" {
+	env := environmentName()
+	if env == "" {
 		return ""
 	}
-	caser := cases.Title(language.English)
-	return fmt.Sprintf(`<p style="color: #6b7280; font-size: 12px;">This email was sent from the %s environment.</p>`, caser.String(env))
+	return fmt.Sprintf(`<p style="color: #6b7280; font-size: 12px;">This email was sent from the %s environment.</p>`, env)
 }
 
 // SendPasswordResetEmail sends a password reset email with a link containing the token.
 // resetURL should be the base URL for the reset page (e.g., "https://app.trakrf.id/#reset-password")
-func (c *Client) SendPasswordResetEmail(toEmail, resetURL, token string) error {
+func (c *Client) SendPasswordResetEmail(toEmail, resetURL, token string, opts Options) error {
 	fullResetURL := fmt.Sprintf("%s?token=%s", resetURL, token)
 
 	if isReservedTestRecipient(toEmail) {
@@ -108,29 +176,39 @@ func (c *Client) SendPasswordResetEmail(toEmail, resetURL, token string) error {
 		return nil
 	}
 
-	_, err := c.client.Emails.Send(&resend.SendEmailRequest{
-		From:    "TrakRF <noreply@trakrf.id>",
-		To:      []string{toEmail},
-		Subject: fmt.Sprintf("%s Reset your password", getEmailPrefix()),
-		Html: fmt.Sprintf(`
-			<h2>Reset your password</h2>
-			<p>Click the link below to reset your TrakRF password. This link expires in 24 hours.</p>
-			<p><a href="%s">Reset Password</a></p>
-			<p>If you didn't request this, you can safely ignore this email.</p>
-			%s
-		`, fullResetURL, getEnvironmentNotice()),
-	})
-
-	if err != nil {
+	if err := c.sendTemplate(toEmail, TemplatePasswordReset, opts, TemplateData{ActionURL: fullResetURL}); err != nil {
 		return fmt.Errorf("failed to send password reset email: %w", err)
 	}
 
 	return nil
 }
 
-// SendInvitationEmail sends an organization invitation email.
+// SendEmailChangeEmail asks the owner of a requested new address to confirm
+// it, with a link containing the token. confirmURL is the base URL for the
+// confirmation page (e.g., "https://app.trakrf.id/#confirm-email").
+func (c *Client) SendEmailChangeEmail(toEmail, confirmURL, token string, opts Options) error {
+	fullConfirmURL := fmt.Sprintf("%s?token=%s", confirmURL, token)
+
+	if isReservedTestRecipient(toEmail) {
+		log.Info().
+			Str("to", toEmail).
+			Str("kind", "email_change").
+			Str("app_env", os.Getenv("APP_ENV")).
+			Msg("email send stubbed: reserved test-fixture recipient")
+		return nil
+	}
+
+	if err := c.sendTemplate(toEmail, TemplateEmailChange, opts, TemplateData{ActionURL: fullConfirmURL}); err != nil {
+		return fmt.Errorf("failed to send email change email: %w", err)
+	}
+
+	return nil
+}
+
+// SendInvitationEmail sends an organization invitation email in the org's
+// branding, with replies going to the org's reply-to address when set.
 // baseURL should be the frontend origin (e.g., "https://app.trakrf.id")
-func (c *Client) SendInvitationEmail(toEmail, orgName, inviterName, role, token, baseURL string) error {
+func (c *Client) SendInvitationEmail(toEmail, orgName, inviterName, role, token, baseURL string, opts Options) error {
 	acceptURL := fmt.Sprintf("%s/#accept-invite?token=%s", baseURL, token)
 
 	if isReservedTestRecipient(toEmail) {
@@ -143,20 +221,12 @@ func (c *Client) SendInvitationEmail(toEmail, orgName, inviterName, role, token,
 		return nil
 	}
 
-	_, err := c.client.Emails.Send(&resend.SendEmailRequest{
-		From:    "TrakRF <noreply@trakrf.id>",
-		To:      []string{toEmail},
-		Subject: fmt.Sprintf("%s You've been invited to join %s", getEmailPrefix(), orgName),
-		Html: fmt.Sprintf(`
-			<h2>You've been invited to %s</h2>
-			<p>%s has invited you to join %s as a %s on TrakRF.</p>
-			<p><a href="%s">Accept Invitation</a></p>
-			<p>This invitation expires in 7 days.</p>
-			<p>If you don't have a TrakRF account yet, you'll be prompted to create one.</p>
-			%s
-		`, orgName, inviterName, orgName, role, acceptURL, getEnvironmentNotice()),
+	err := c.sendTemplate(toEmail, TemplateInvitation, opts, TemplateData{
+		ActionURL:   acceptURL,
+		OrgName:     orgName,
+		InviterName: inviterName,
+		Role:        role,
 	})
-
 	if err != nil {
 		return fmt.Errorf("failed to send invitation email: %w", err)
 	}
@@ -185,11 +255,10 @@ func (c *Client) SendTrialSignupNotification(toEmail, orgName, orgIdentifier, si
 		trialExpiry = trialExpiresAt.UTC().Format("2006-01-02 15:04 UTC")
 	}
 
-	_, err := c.client.Emails.Send(&resend.SendEmailRequest{
-		From:    "TrakRF <noreply@trakrf.id>",
+	err := c.send(Message{
 		To:      []string{toEmail},
 		Subject: fmt.Sprintf("%s New trial signup: %s", getEmailPrefix(), orgName),
-		Html: fmt.Sprintf(`
+		HTML: fmt.Sprintf(`
 			<h2>New self-service trial signup</h2>
 			<p>A new user signed up and started a 1-month trial. Reach out to qualify the account.</p>
 			<ul>
@@ -230,11 +299,10 @@ func (c *Client) SendOrgCreatedNotification(toEmail, orgName, orgIdentifier, cre
 		entitlement = "trial, expires " + trialExpiresAt.UTC().Format("2006-01-02 15:04 UTC")
 	}
 
-	_, err := c.client.Emails.Send(&resend.SendEmailRequest{
-		From:    "TrakRF <noreply@trakrf.id>",
+	err := c.send(Message{
 		To:      []string{toEmail},
 		Subject: fmt.Sprintf("%s New org created: %s", getEmailPrefix(), orgName),
-		Html: fmt.Sprintf(`
+		HTML: fmt.Sprintf(`
 			<h2>New organization created</h2>
 			<p>A new organization was created. Follow up to track what's driving signups.</p>
 			<ul>
@@ -268,11 +336,10 @@ func (c *Client) SendOrgDeletedNotification(toEmail, orgName, orgIdentifier, act
 		return nil
 	}
 
-	_, err := c.client.Emails.Send(&resend.SendEmailRequest{
-		From:    "TrakRF <noreply@trakrf.id>",
+	err := c.send(Message{
 		To:      []string{toEmail},
 		Subject: fmt.Sprintf("%s Org deleted: %s", getEmailPrefix(), orgName),
-		Html: fmt.Sprintf(`
+		HTML: fmt.Sprintf(`
 			<h2>Organization deleted</h2>
 			<p>An organization was deleted. Follow up for a churn postmortem — find out why they quit.</p>
 			<ul>
@@ -290,3 +357,40 @@ func (c *Client) SendOrgDeletedNotification(toEmail, orgName, orgIdentifier, act
 
 	return nil
 }
+
+// SendAssetTransferNotification tells a user they are now the owner of an
+// asset. actor names who made the transfer: a user's name, or the API key's
+// name for integration-driven transfers. Asset names are user-supplied, so
+// every value is HTML-escaped.
+func (c *Client) SendAssetTransferNotification(toEmail, orgName, assetName, assetExternalKey, actor string) error {
+	if isReservedTestRecipient(toEmail) {
+		log.Info().
+			Str("to", toEmail).
+			Str("kind", "asset_transfer").
+			Str("org", orgName).
+			Str("app_env", os.Getenv("APP_ENV")).
+			Msg("email send stubbed: reserved test-fixture recipient")
+		return nil
+	}
+
+	err := c.send(Message{
+		To:      []string{toEmail},
+		Subject: fmt.Sprintf("%s You now own %s", getEmailPrefix(), assetName),
+		HTML: fmt.Sprintf(`
+			<h2>An asset was transferred to you</h2>
+			<p>%s made you the owner of an asset in %s.</p>
+			<ul>
+				<li><strong>Asset:</strong> %s (%s)</li>
+			</ul>
+			<p>You can see everything you own under My Assets in TrakRF.</p>
+			%s
+		`, html.EscapeString(actor), html.EscapeString(orgName),
+			html.EscapeString(assetName), html.EscapeString(assetExternalKey), getEnvironmentNotice()),
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to send asset transfer notification: %w", err)
+	}
+
+	return nil
+}
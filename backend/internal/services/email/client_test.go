@@ -143,6 +143,7 @@ func TestSendInvitationEmail_StubsReservedDomain(t *testing.T) {
 		"member",
 		"token-xyz",
 		"https://app.preview.trakrf.id",
+		Options{},
 	); err != nil {
 		t.Fatalf("expected nil error for reserved recipient, got %v", err)
 	}
@@ -156,6 +157,7 @@ func TestSendPasswordResetEmail_StubsReservedDomain(t *testing.T) {
 		"fixture@example.com",
 		"https://app.preview.trakrf.id/#reset-password",
 		"token-xyz",
+		Options{},
 	); err != nil {
 		t.Fatalf("expected nil error for reserved recipient, got %v", err)
 	}
@@ -0,0 +1,127 @@
+package email
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Providers accepted in EMAIL_PROVIDER.
+const (
+	ProviderResend = "resend"
+	ProviderSMTP   = "smtp"
+	ProviderSES    = "ses"
+	// ProviderLog writes each message to the log instead of sending it. For
+	// local development and demos; nothing reaches an inbox.
+	ProviderLog = "log"
+)
+
+// DefaultFrom is the sender when EMAIL_FROM is unset.
+const DefaultFrom = "TrakRF <noreply@trakrf.id>"
+
+// MailerConfig selects and configures the Mailer behind Client. Credentials
+// a provider needs but lacks leave it unconfigured rather than failing boot,
+// as an unset RESEND_API_KEY always has; the readiness probe reports it.
+type MailerConfig struct {
+	// Provider is one of the Provider* constants (EMAIL_PROVIDER).
+	Provider string
+	// From is the sender address for every message (EMAIL_FROM).
+	From string
+
+	ResendAPIKey string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// SESRegion is the AWS region of the SES endpoint (SES_REGION, falling
+	// back to AWS_REGION). Credentials are the standard AWS variables.
+	SESRegion          string
+	SESAccessKeyID     string
+	SESSecretAccessKey string
+	SESSessionToken    string
+}
+
+// MailerConfigFromEnv reads the mailer config:
+//
+//	EMAIL_PROVIDER         resend|smtp|ses|log  (default resend)
+//	EMAIL_FROM             sender address       (default TrakRF <noreply@trakrf.id>)
+//	RESEND_API_KEY         resend
+//	SMTP_HOST              smtp
+//	SMTP_PORT              int                  (default 587; 465 is implicit TLS)
+//	SMTP_USERNAME          smtp, optional; with SMTP_PASSWORD enables AUTH PLAIN
+//	SMTP_PASSWORD          smtp, optional
+//	SES_REGION             ses (or AWS_REGION)
+//	AWS_ACCESS_KEY_ID      ses
+//	AWS_SECRET_ACCESS_KEY  ses
+//	AWS_SESSION_TOKEN      ses, optional
+//
+// An unknown provider, a malformed port or an unparseable sender is an error.
+func MailerConfigFromEnv() (MailerConfig, error) {
+	c := MailerConfig{
+		Provider:           strings.ToLower(strings.TrimSpace(os.Getenv("EMAIL_PROVIDER"))),
+		From:               os.Getenv("EMAIL_FROM"),
+		ResendAPIKey:       os.Getenv("RESEND_API_KEY"),
+		SMTPHost:           os.Getenv("SMTP_HOST"),
+		SMTPPort:           587,
+		SMTPUsername:       os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:       os.Getenv("SMTP_PASSWORD"),
+		SESRegion:          os.Getenv("SES_REGION"),
+		SESAccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SESSecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SESSessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if c.Provider == "" {
+		c.Provider = ProviderResend
+	}
+	if c.From == "" {
+		c.From = DefaultFrom
+	}
+	if c.SESRegion == "" {
+		c.SESRegion = os.Getenv("AWS_REGION")
+	}
+
+	switch c.Provider {
+	case ProviderResend, ProviderSMTP, ProviderSES, ProviderLog:
+	default:
+		return MailerConfig{}, fmt.Errorf("EMAIL_PROVIDER %q is not one of resend, smtp, ses, log", c.Provider)
+	}
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return MailerConfig{}, fmt.Errorf("EMAIL_FROM %q is not a valid address: %w", c.From, err)
+	}
+	if raw := os.Getenv("SMTP_PORT"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 || n > 65535 {
+			return MailerConfig{}, fmt.Errorf("SMTP_PORT must be a port number between 1 and 65535, got %q", raw)
+		}
+		c.SMTPPort = n
+	}
+	return c, nil
+}
+
+// Missing returns why the configured provider cannot send, or nil when it
+// can.
+func (c MailerConfig) Missing() error {
+	switch c.Provider {
+	case ProviderResend:
+		if c.ResendAPIKey == "" {
+			return errors.New("RESEND_API_KEY not set")
+		}
+	case ProviderSMTP:
+		if c.SMTPHost == "" {
+			return errors.New("SMTP_HOST not set")
+		}
+	case ProviderSES:
+		if c.SESRegion == "" || c.SESAccessKeyID == "" || c.SESSecretAccessKey == "" {
+			return errors.New("SES_REGION, AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+		}
+	case ProviderLog:
+	default:
+		return fmt.Errorf("unknown email provider %q", c.Provider)
+	}
+	return nil
+}
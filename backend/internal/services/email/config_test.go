@@ -0,0 +1,74 @@
+package email
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func clearMailerEnv(t *testing.T) {
+	t.Helper()
+	for _, k := range []string{
+		"EMAIL_PROVIDER", "EMAIL_FROM", "RESEND_API_KEY",
+		"SMTP_HOST", "SMTP_PORT", "SMTP_USERNAME", "SMTP_PASSWORD",
+		"SES_REGION", "AWS_REGION", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN",
+	} {
+		t.Setenv(k, "")
+	}
+}
+
+func TestMailerConfigFromEnv_Defaults(t *testing.T) {
+	clearMailerEnv(t)
+
+	c, err := MailerConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, ProviderResend, c.Provider)
+	assert.Equal(t, DefaultFrom, c.From)
+	assert.Equal(t, 587, c.SMTPPort)
+	assert.EqualError(t, c.Missing(), "RESEND_API_KEY not set")
+}
+
+func TestMailerConfigFromEnv_Overrides(t *testing.T) {
+	clearMailerEnv(t)
+	t.Setenv("EMAIL_PROVIDER", " SES ")
+	t.Setenv("EMAIL_FROM", "Acme <assets@acme.co>")
+	t.Setenv("SMTP_PORT", "465")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	c, err := MailerConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, ProviderSES, c.Provider)
+	assert.Equal(t, "Acme <assets@acme.co>", c.From)
+	assert.Equal(t, 465, c.SMTPPort)
+	assert.Equal(t, "eu-west-1", c.SESRegion, "AWS_REGION is the fallback for SES_REGION")
+	assert.NoError(t, c.Missing())
+}
+
+func TestMailerConfigFromEnv_RejectsInvalid(t *testing.T) {
+	cases := map[string][2]string{
+		"unknown provider": {"EMAIL_PROVIDER", "pigeon"},
+		"bad from":         {"EMAIL_FROM", "not an address"},
+		"bad port":         {"SMTP_PORT", "25a"},
+		"port range":       {"SMTP_PORT", "70000"},
+	}
+	for name, kv := range cases {
+		t.Run(name, func(t *testing.T) {
+			clearMailerEnv(t)
+			t.Setenv(kv[0], kv[1])
+			_, err := MailerConfigFromEnv()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), kv[0])
+		})
+	}
+}
+
+func TestMailerConfig_Missing(t *testing.T) {
+	assert.Error(t, MailerConfig{Provider: ProviderSMTP}.Missing())
+	assert.NoError(t, MailerConfig{Provider: ProviderSMTP, SMTPHost: "mail"}.Missing())
+	assert.Error(t, MailerConfig{Provider: ProviderSES, SESRegion: "us-east-1"}.Missing())
+	assert.NoError(t, MailerConfig{Provider: ProviderLog}.Missing())
+	assert.Error(t, MailerConfig{Provider: "pigeon"}.Missing())
+}
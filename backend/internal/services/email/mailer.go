@@ -0,0 +1,85 @@
+package email
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/resend/resend-go/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// Message is one outgoing email. HTML is always set; Text, when set, is sent
+// alongside it as the plain-text alternative.
+type Message struct {
+	From string
+	To   []string
+	// ReplyTo is where replies go instead of From; optional.
+	ReplyTo string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Mailer delivers a Message through one provider. Client renders the
+// templates; a Mailer only moves bytes.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer builds the Mailer cfg selects. A provider missing its settings
+// gets a Mailer that fails every send with the reason (see
+// MailerConfig.Missing).
+func NewMailer(cfg MailerConfig) Mailer {
+	if err := cfg.Missing(); err != nil {
+		return unconfiguredMailer{err: err}
+	}
+	switch cfg.Provider {
+	case ProviderSMTP:
+		return newSMTPMailer(cfg)
+	case ProviderSES:
+		return newSESMailer(cfg)
+	case ProviderLog:
+		return logMailer{}
+	default:
+		return resendMailer{client: resend.NewClient(cfg.ResendAPIKey)}
+	}
+}
+
+type resendMailer struct {
+	client *resend.Client
+}
+
+func (m resendMailer) Send(ctx context.Context, msg Message) error {
+	_, err := m.client.Emails.SendWithContext(ctx, &resend.SendEmailRequest{
+		From:    msg.From,
+		To:      msg.To,
+		ReplyTo: msg.ReplyTo,
+		Subject: msg.Subject,
+		Html:    msg.HTML,
+		Text:    msg.Text,
+	})
+	return err
+}
+
+// logMailer logs each message in place of sending it.
+type logMailer struct{}
+
+func (logMailer) Send(_ context.Context, msg Message) error {
+	log.Info().
+		Strs("to", msg.To).
+		Str("from", msg.From).
+		Str("reply_to", msg.ReplyTo).
+		Str("subject", msg.Subject).
+		Str("html", msg.HTML).
+		Str("text", msg.Text).
+		Msg("email not sent: log-only mailer")
+	return nil
+}
+
+type unconfiguredMailer struct {
+	err error
+}
+
+func (m unconfiguredMailer) Send(context.Context, Message) error {
+	return fmt.Errorf("email not configured: %w", m.err)
+}
@@ -0,0 +1,240 @@
+package email
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingMailer struct {
+	sent []Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestClient_SendsThroughMailer(t *testing.T) {
+	rec := &recordingMailer{}
+	c := NewClientWithMailer(rec, "Acme <assets@acme.co>")
+
+	require.NoError(t, c.SendAssetTransferNotification("owner@acme.co", "Acme", "<Forklift>", "FL-1", "Dana"))
+	require.Len(t, rec.sent, 1)
+	msg := rec.sent[0]
+	assert.Equal(t, "Acme <assets@acme.co>", msg.From)
+	assert.Equal(t, []string{"owner@acme.co"}, msg.To)
+	assert.Contains(t, msg.Subject, "You now own <Forklift>")
+	assert.Contains(t, msg.HTML, "&lt;Forklift&gt;")
+}
+
+func TestNewMailer_Unconfigured(t *testing.T) {
+	c := NewClientWithConfig(MailerConfig{Provider: ProviderSMTP, From: DefaultFrom})
+	assert.False(t, c.Configured())
+	assert.EqualError(t, c.ConfigError(), "SMTP_HOST not set")
+
+	err := c.SendPasswordResetEmail("user@acme.co", "https://app/#reset-password", "tok", Options{})
+	assert.ErrorContains(t, err, "SMTP_HOST not set")
+}
+
+func TestClient_Reconfigure(t *testing.T) {
+	c := NewClientWithConfig(MailerConfig{Provider: ProviderResend, From: DefaultFrom})
+	assert.False(t, c.Configured())
+
+	c.Reconfigure(MailerConfig{Provider: ProviderLog, From: "Acme <ops@acme.co>"})
+	assert.True(t, c.Configured(), "refreshed credentials take effect without a restart")
+	assert.NoError(t, c.SendPasswordResetEmail("user@acme.co", "https://app/#reset-password", "tok", Options{}))
+}
+
+func TestNewMailer_LogProviderSendsNothing(t *testing.T) {
+	c := NewClientWithConfig(MailerConfig{Provider: ProviderLog, From: DefaultFrom})
+	assert.True(t, c.Configured())
+	assert.NoError(t, c.SendPasswordResetEmail("user@acme.co", "https://app/#reset-password", "tok", Options{}))
+}
+
+func TestBuildMIME(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	raw, err := buildMIME(Message{
+		From:    DefaultFrom,
+		To:      []string{"a@acme.co", "b@acme.co"},
+		Subject: "Héllo\r\nBcc: evil@x.co",
+		HTML:    "<p>hi</p>",
+	}, now)
+	require.NoError(t, err)
+	out := string(raw)
+
+	assert.Contains(t, out, "From: \"TrakRF\" <noreply@trakrf.id>\r\n")
+	assert.Contains(t, out, "To: a@acme.co, b@acme.co\r\n")
+	assert.Contains(t, out, "Subject: =?utf-8?q?")
+	assert.NotContains(t, out, "\r\nBcc:", "a CR/LF in the subject must not start a header")
+	assert.Contains(t, out, "Date: Sun, 01 Mar 2026 12:00:00 +0000\r\n")
+	assert.Contains(t, out, "@trakrf.id>\r\n")
+	assert.True(t, strings.HasSuffix(out, "\r\n\r\n<p>hi</p>"))
+}
+
+func TestBuildMIME_MultipartWithReplyTo(t *testing.T) {
+	raw, err := buildMIME(Message{
+		From:    DefaultFrom,
+		To:      []string{"a@acme.co"},
+		ReplyTo: "ops@acme.co",
+		Subject: "Hi",
+		HTML:    "<p>hi</p>",
+		Text:    "hi",
+	}, time.Now())
+	require.NoError(t, err)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, "<ops@acme.co>", msg.Header.Get("Reply-To"))
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var types, bodies []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		b, err := io.ReadAll(p)
+		require.NoError(t, err)
+		types = append(types, p.Header.Get("Content-Type"))
+		bodies = append(bodies, string(b))
+	}
+	assert.Equal(t, []string{"text/plain; charset=UTF-8", "text/html; charset=UTF-8"}, types)
+	assert.Equal(t, []string{"hi", "<p>hi</p>"}, bodies)
+
+	_, err = buildMIME(Message{From: DefaultFrom, ReplyTo: "not an address"}, time.Now())
+	assert.ErrorContains(t, err, "invalid reply-to")
+}
+
+// fakeSMTP accepts one plain-text SMTP session and returns what it received.
+func fakeSMTP(t *testing.T) (port int, got <-chan string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	ch := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		var transcript strings.Builder
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+		reply("220 fake ESMTP")
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				ch <- transcript.String()
+				return
+			}
+			transcript.WriteString(line)
+			switch {
+			case inData:
+				if line == ".\r\n" {
+					inData = false
+					reply("250 queued")
+				}
+			case strings.HasPrefix(line, "EHLO"):
+				reply("250 fake")
+			case strings.HasPrefix(line, "DATA"):
+				inData = true
+				reply("354 go ahead")
+			case strings.HasPrefix(line, "QUIT"):
+				reply("221 bye")
+				ch <- transcript.String()
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, ch
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	port, got := fakeSMTP(t)
+	m := NewMailer(MailerConfig{Provider: ProviderSMTP, From: DefaultFrom, SMTPHost: "127.0.0.1", SMTPPort: port})
+
+	err := m.Send(context.Background(), Message{
+		From: DefaultFrom, To: []string{"user@acme.co"}, Subject: "Hi", HTML: "<p>hi</p>",
+	})
+	require.NoError(t, err)
+
+	transcript := <-got
+	assert.Contains(t, transcript, "MAIL FROM:<noreply@trakrf.id>")
+	assert.Contains(t, transcript, "RCPT TO:<user@acme.co>")
+	assert.Contains(t, transcript, "Subject: Hi\r\n")
+}
+
+func TestSMTPMailer_RejectsHeaderInjection(t *testing.T) {
+	m := NewMailer(MailerConfig{Provider: ProviderSMTP, From: DefaultFrom, SMTPHost: "127.0.0.1", SMTPPort: 1})
+	err := m.Send(context.Background(), Message{From: DefaultFrom, To: []string{"a@acme.co\r\nBcc: x@y.z"}})
+	assert.ErrorContains(t, err, "invalid recipient")
+}
+
+// AWS's published SigV4 test vector (get-vanilla).
+func TestSESMailer_Send(t *testing.T) {
+	var body sesSendEmailRequest
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/email/outbound-emails", r.URL.Path)
+		auth = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Write([]byte(`{"MessageId":"m-1"}`))
+	}))
+	defer srv.Close()
+
+	m := newSESMailer(MailerConfig{SESRegion: "eu-west-1", SESAccessKeyID: "AKID", SESSecretAccessKey: "s"})
+	m.endpoint = srv.URL
+	require.NoError(t, m.Send(context.Background(), Message{
+		From: DefaultFrom, To: []string{"user@acme.co"}, ReplyTo: "ops@acme.co",
+		Subject: "Hi", HTML: "<p>hi</p>", Text: "hi",
+	}))
+
+	assert.Equal(t, DefaultFrom, body.FromEmailAddress)
+	assert.Equal(t, []string{"user@acme.co"}, body.Destination.ToAddresses)
+	assert.Equal(t, []string{"ops@acme.co"}, body.ReplyToAddresses)
+	assert.Equal(t, "Hi", body.Content.Simple.Subject.Data)
+	assert.Equal(t, "<p>hi</p>", body.Content.Simple.Body.HTML.Data)
+	require.NotNil(t, body.Content.Simple.Body.Text)
+	assert.Equal(t, "hi", body.Content.Simple.Body.Text.Data)
+	assert.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/"), auth)
+	assert.Contains(t, auth, "/eu-west-1/ses/aws4_request")
+}
+
+func TestSESMailer_ReportsAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"Email address is not verified."}`))
+	}))
+	defer srv.Close()
+
+	m := newSESMailer(MailerConfig{SESRegion: "eu-west-1", SESAccessKeyID: "AKID", SESSecretAccessKey: "s"})
+	m.endpoint = srv.URL
+	err := m.Send(context.Background(), Message{From: DefaultFrom, To: []string{"user@acme.co"}})
+	assert.ErrorContains(t, err, "status "+strconv.Itoa(http.StatusBadRequest))
+	assert.ErrorContains(t, err, "not verified")
+}
@@ -0,0 +1,94 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/util/awssig"
+)
+
+// sesMailer sends through the Amazon SES v2 API (SendEmail, simple content).
+// Requests are signed with awssig rather than through the AWS SDK.
+type sesMailer struct {
+	endpoint string
+	region   string
+	creds    awssig.Credentials
+	client   *http.Client
+}
+
+func newSESMailer(cfg MailerConfig) *sesMailer {
+	return &sesMailer{
+		endpoint: fmt.Sprintf("https://email.%s.amazonaws.com", cfg.SESRegion),
+		region:   cfg.SESRegion,
+		creds: awssig.Credentials{
+			AccessKeyID:     cfg.SESAccessKeyID,
+			SecretAccessKey: cfg.SESSecretAccessKey,
+			SessionToken:    cfg.SESSessionToken,
+		},
+		client: &http.Client{Timeout: smtpTimeout},
+	}
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesSendEmailRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	ReplyToAddresses []string `json:"ReplyToAddresses,omitempty"`
+	Content          struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				HTML sesContent  `json:"Html"`
+				Text *sesContent `json:"Text,omitempty"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+func (m *sesMailer) Send(ctx context.Context, msg Message) error {
+	var in sesSendEmailRequest
+	in.FromEmailAddress = msg.From
+	in.Destination.ToAddresses = msg.To
+	in.Content.Simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	in.Content.Simple.Body.HTML = sesContent{Data: msg.HTML, Charset: "UTF-8"}
+	if msg.Text != "" {
+		in.Content.Simple.Body.Text = &sesContent{Data: msg.Text, Charset: "UTF-8"}
+	}
+	if msg.ReplyTo != "" {
+		in.ReplyToAddresses = []string{msg.ReplyTo}
+	}
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("ses: marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		m.endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ses: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	awssig.Sign(req, body, m.creds, m.region, "ses", time.Now())
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ses: send: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		return fmt.Errorf("ses: send: status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	}
+	return nil
+}
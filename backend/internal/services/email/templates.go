@@ -0,0 +1,193 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/trakrf/platform/backend/internal/i18n"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Templated emails. Each one is a pair of files under templates/: NAME.html.tmpl
+// defines "body" for the HTML part and NAME.txt.tmpl defines "subject" and
+// "body" for the plain-text part. Both are wrapped in the matching layout,
+// which draws the org's branding. Copy goes through the "t" function, which
+// looks the English text up in the i18n catalogs (i18n.Tf), so adding a
+// string means adding it to every locales/*.json.
+const (
+	TemplatePasswordReset = "password_reset"
+	TemplateInvitation    = "invitation"
+	TemplateEmailChange   = "email_change"
+)
+
+// DefaultPrimaryColor is the accent color when an org has not set one.
+const DefaultPrimaryColor = "#1d4ed8"
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+// TemplateNames lists the templated emails, for the admin preview.
+func TemplateNames() []string {
+	return []string{TemplatePasswordReset, TemplateInvitation, TemplateEmailChange}
+}
+
+// Branding is an org's look for its emails. The zero value is TrakRF's own.
+type Branding struct {
+	// LogoURL replaces the TrakRF wordmark in the header.
+	LogoURL string
+	// PrimaryColor (#rrggbb) tints the header rule and buttons.
+	PrimaryColor string
+	// ReplyTo receives replies instead of the no-reply sender.
+	ReplyTo string
+}
+
+// Options are the per-recipient settings for a templated email.
+type Options struct {
+	// Locale picks the catalog; unsupported locales render in English.
+	Locale   string
+	Branding Branding
+}
+
+// TemplateData is what a template can reference. ActionURL is the link the
+// email exists to deliver; the Org/Inviter/Role fields are set for
+// invitations only.
+type TemplateData struct {
+	ActionURL   string
+	OrgName     string
+	InviterName string
+	Role        string
+}
+
+// Rendered is a templated email ready to send.
+type Rendered struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
+// view is the value templates execute against.
+type view struct {
+	TemplateData
+	Branding
+	Locale string
+	// Environment is the title-cased APP_ENV outside production, which the
+	// layout prints as a notice; empty in production.
+	Environment string
+}
+
+type button struct {
+	URL, Label, Color string
+}
+
+type templateSet struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+var templates = mustParseTemplates()
+
+// placeholderFuncs stand in for the per-render functions at parse time;
+// render replaces them on a clone.
+var placeholderFuncs = map[string]any{
+	"t":      func(string, ...any) string { return "" },
+	"button": func(url, label, color string) button { return button{url, label, color} },
+}
+
+func mustParseTemplates() map[string]templateSet {
+	out := make(map[string]templateSet, len(TemplateNames()))
+	for _, name := range TemplateNames() {
+		h := htmltemplate.Must(htmltemplate.New("layout.html.tmpl").Funcs(placeholderFuncs).
+			ParseFS(templateFS, "templates/layout.html.tmpl", "templates/"+name+".html.tmpl"))
+		t := texttemplate.Must(texttemplate.New("layout.txt.tmpl").Funcs(placeholderFuncs).
+			ParseFS(templateFS, "templates/layout.txt.tmpl", "templates/"+name+".txt.tmpl"))
+		out[name] = templateSet{html: h, text: t}
+	}
+	return out
+}
+
+// Render renders the named email in opts.Locale with opts.Branding applied.
+// An empty PrimaryColor falls back to DefaultPrimaryColor.
+func Render(name string, opts Options, data TemplateData) (Rendered, error) {
+	set, ok := templates[name]
+	if !ok {
+		return Rendered{}, fmt.Errorf("unknown email template %q", name)
+	}
+	if opts.Branding.PrimaryColor == "" {
+		opts.Branding.PrimaryColor = DefaultPrimaryColor
+	}
+	v := view{
+		TemplateData: data,
+		Branding:     opts.Branding,
+		Locale:       opts.Locale,
+		Environment:  environmentName(),
+	}
+	if v.Locale == "" {
+		v.Locale = i18n.Default
+	}
+	funcs := map[string]any{
+		"t": func(format string, args ...any) string { return i18n.Tf(v.Locale, format, args...) },
+	}
+
+	// html/template refuses to clone a template that has run, so the parsed
+	// sets are never executed directly.
+	h, err := set.html.Clone()
+	if err != nil {
+		return Rendered{}, fmt.Errorf("clone %s template: %w", name, err)
+	}
+	var htmlBuf bytes.Buffer
+	if err := h.Funcs(funcs).Execute(&htmlBuf, v); err != nil {
+		return Rendered{}, fmt.Errorf("render %s html: %w", name, err)
+	}
+	t, err := set.text.Clone()
+	if err != nil {
+		return Rendered{}, fmt.Errorf("clone %s template: %w", name, err)
+	}
+	t = t.Funcs(funcs)
+	var textBuf, subjectBuf bytes.Buffer
+	if err := t.Execute(&textBuf, v); err != nil {
+		return Rendered{}, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := t.ExecuteTemplate(&subjectBuf, "subject", v); err != nil {
+		return Rendered{}, fmt.Errorf("render %s subject: %w", name, err)
+	}
+
+	return Rendered{
+		Subject: getEmailPrefix() + " " + strings.TrimSpace(subjectBuf.String()),
+		HTML:    htmlBuf.String(),
+		Text:    strings.TrimSpace(textBuf.String()) + "\n",
+	}, nil
+}
+
+// Preview renders the named email with placeholder data, for admins checking
+// their branding.
+func Preview(name, orgName string, opts Options) (Rendered, error) {
+	data := TemplateData{ActionURL: "https://app.trakrf.id/#reset-password?token=preview"}
+	switch name {
+	case TemplateInvitation:
+		data = TemplateData{
+			ActionURL:   "https://app.trakrf.id/#accept-invite?token=preview",
+			OrgName:     orgName,
+			InviterName: "Alex Example",
+			Role:        "member",
+		}
+	case TemplateEmailChange:
+		data = TemplateData{ActionURL: "https://app.trakrf.id/#confirm-email?token=preview"}
+	}
+	return Render(name, opts, data)
+}
+
+// environmentName returns the title-cased APP_ENV for non-production
+// environments, or "" for production/empty.
+func environmentName() string {
+	env := os.Getenv("APP_ENV")
+	if env == "" || env == "production" || env == "prod" {
+		return ""
+	}
+	return cases.Title(language.English).String(env)
+}
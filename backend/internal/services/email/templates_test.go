@@ -0,0 +1,84 @@
+package email
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Invitation(t *testing.T) {
+	t.Setenv("APP_ENV", "")
+	r, err := Render(TemplateInvitation, Options{}, TemplateData{
+		ActionURL:   "https://app.trakrf.id/#accept-invite?token=abc",
+		OrgName:     "<Acme>",
+		InviterName: "Dana",
+		Role:        "admin",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "[TrakRF] You've been invited to join <Acme>", r.Subject)
+	assert.Contains(t, r.HTML, "Dana has invited you to join &lt;Acme&gt; as a admin on TrakRF.")
+	assert.NotContains(t, r.HTML, "<Acme>")
+	assert.Contains(t, r.HTML, `href="https://app.trakrf.id/#accept-invite?token=abc"`)
+	assert.Contains(t, r.HTML, DefaultPrimaryColor)
+	assert.Contains(t, r.HTML, ">TrakRF</strong>")
+	assert.NotContains(t, r.HTML, "environment")
+
+	assert.Contains(t, r.Text, "Dana has invited you to join <Acme> as a admin on TrakRF.")
+	assert.Contains(t, r.Text, "https://app.trakrf.id/#accept-invite?token=abc")
+	assert.NotContains(t, r.Text, "<p>")
+}
+
+func TestRender_BrandingAndLocale(t *testing.T) {
+	t.Setenv("APP_ENV", "preview")
+	r, err := Render(TemplatePasswordReset, Options{
+		Locale: "es",
+		Branding: Branding{
+			LogoURL:      "https://acme.example/logo.png",
+			PrimaryColor: "#0f766e",
+		},
+	}, TemplateData{ActionURL: "https://app.trakrf.id/#reset-password?token=t"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "[TrakRF Preview] Restablece tu contraseña", r.Subject)
+	assert.Contains(t, r.HTML, `<html lang="es">`)
+	assert.Contains(t, r.HTML, `src="https://acme.example/logo.png"`)
+	assert.Contains(t, r.HTML, "#0f766e")
+	assert.NotContains(t, r.HTML, DefaultPrimaryColor)
+	assert.Contains(t, r.HTML, "Restablecer contraseña")
+	assert.Contains(t, r.HTML, "Este correo se envió desde el entorno Preview.")
+	assert.True(t, strings.HasSuffix(r.Text, "Este correo se envió desde el entorno Preview.\n"), r.Text)
+}
+
+func TestRender_UnknownTemplate(t *testing.T) {
+	_, err := Render("nope", Options{}, TemplateData{})
+	assert.ErrorContains(t, err, `unknown email template "nope"`)
+}
+
+func TestPreview_EveryTemplateRenders(t *testing.T) {
+	for _, name := range TemplateNames() {
+		for _, locale := range []string{"en", "es", "fr"} {
+			r, err := Preview(name, "Acme", Options{Locale: locale})
+			require.NoError(t, err, "%s/%s", name, locale)
+			assert.NotEmpty(t, r.Subject)
+			assert.Contains(t, r.HTML, "token=preview")
+			assert.Contains(t, r.Text, "token=preview")
+		}
+	}
+}
+
+func TestClient_InvitationUsesBrandingReplyTo(t *testing.T) {
+	rec := &recordingMailer{}
+	c := NewClientWithMailer(rec, DefaultFrom)
+
+	require.NoError(t, c.SendInvitationEmail("new@acme.co", "Acme", "Dana", "member", "tok",
+		"https://app.trakrf.id", Options{Locale: "fr", Branding: Branding{ReplyTo: "ops@acme.co"}}))
+	require.Len(t, rec.sent, 1)
+	msg := rec.sent[0]
+	assert.Equal(t, "ops@acme.co", msg.ReplyTo)
+	assert.Contains(t, msg.Subject, "Vous avez été invité à rejoindre Acme")
+	assert.Contains(t, msg.HTML, "Accepter l&#39;invitation")
+	assert.Contains(t, msg.Text, "https://app.trakrf.id/#accept-invite?token=tok")
+}
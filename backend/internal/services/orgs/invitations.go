@@ -8,15 +8,19 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/trakrf/platform/backend/internal/applinks"
+	"github.com/trakrf/platform/backend/internal/i18n"
 	"github.com/trakrf/platform/backend/internal/models"
 	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/services/email"
 )
 
 const invitationExpiryDays = 7
 
 // CreateInvitation creates an invitation and sends an email
-// baseURL is the frontend origin for building the accept link (e.g., "https://app.trakrf.id")
-func (s *Service) CreateInvitation(ctx context.Context, orgID int, req organization.CreateInvitationRequest, inviterUserID int, baseURL string) (*organization.CreateInvitationResponse, error) {
+// requestedOrigin is the caller's Origin header; the accept link only uses it
+// when applinks allows it for this org, else the environment's base URL.
+func (s *Service) CreateInvitation(ctx context.Context, orgID int, req organization.CreateInvitationRequest, inviterUserID int, requestedOrigin string) (*organization.CreateInvitationResponse, error) {
 	// Check if email is already a member
 	isMember, err := s.storage.IsEmailMember(ctx, orgID, req.Email)
 	if err != nil {
@@ -67,7 +71,15 @@ func (s *Service) CreateInvitation(ctx context.Context, orgID int, req organizat
 
 	// Send invitation email (with raw token, not hash)
 	if s.emailClient != nil {
-		if err := s.emailClient.SendInvitationEmail(req.Email, org.Name, inviter.Name, req.Role, rawToken, baseURL); err != nil {
+		opts, err := s.EmailOptions(ctx, orgID)
+		if err != nil {
+			return nil, err
+		}
+		baseURL, err := s.LinkOrigin(ctx, orgID, requestedOrigin)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.emailClient.SendInvitationEmail(req.Email, org.Name, inviter.Name, req.Role, rawToken, baseURL, opts); err != nil {
 			// Log error but don't fail the invitation creation
 			// The admin can resend if needed
 			fmt.Printf("warning: failed to send invitation email: %v\n", err)
@@ -93,8 +105,8 @@ func (s *Service) CancelInvitation(ctx context.Context, inviteID int) error {
 }
 
 // ResendInvitation generates a new token and resends the email, returns new expiry
-// baseURL is the frontend origin for building the accept link (e.g., "https://app.trakrf.id")
-func (s *Service) ResendInvitation(ctx context.Context, inviteID, orgID int, baseURL string) (time.Time, error) {
+// requestedOrigin is handled as in CreateInvitation.
+func (s *Service) ResendInvitation(ctx context.Context, inviteID, orgID int, requestedOrigin string) (time.Time, error) {
 	// Get the invitation
 	inv, err := s.storage.GetInvitationByID(ctx, inviteID)
 	if err != nil {
@@ -137,7 +149,15 @@ func (s *Service) ResendInvitation(ctx context.Context, inviteID, orgID int, bas
 	// Send email with new token
 	// Log error but don't fail - admin can retry if needed (matches CreateInvitation behavior)
 	if s.emailClient != nil {
-		if err := s.emailClient.SendInvitationEmail(inv.Email, org.Name, inviterName, inv.Role, rawToken, baseURL); err != nil {
+		opts, err := s.EmailOptions(ctx, orgID)
+		if err != nil {
+			return time.Time{}, err
+		}
+		baseURL, err := s.LinkOrigin(ctx, orgID, requestedOrigin)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if err := s.emailClient.SendInvitationEmail(inv.Email, org.Name, inviterName, inv.Role, rawToken, baseURL, opts); err != nil {
 			fmt.Printf("warning: failed to send invitation email: %v\n", err)
 		}
 	}
@@ -145,6 +165,36 @@ func (s *Service) ResendInvitation(ctx context.Context, inviteID, orgID int, bas
 	return newExpiry, nil
 }
 
+// EmailOptions returns how emails on the org's behalf are rendered: in the
+// caller's locale (the inviter's, as the invitee's is unknown) with the org's
+// branding and reply-to address.
+func (s *Service) EmailOptions(ctx context.Context, orgID int) (email.Options, error) {
+	eb, err := s.storage.GetEmailBranding(ctx, orgID)
+	if err != nil {
+		return email.Options{}, err
+	}
+	opts := email.Options{Locale: i18n.FromContext(ctx)}
+	if eb != nil {
+		opts.Branding = email.Branding{LogoURL: eb.LogoURL, PrimaryColor: eb.PrimaryColor, ReplyTo: eb.ReplyTo}
+	}
+	return opts, nil
+}
+
+// LinkOrigin returns the origin for links mailed on the org's behalf: the
+// requested one if the environment or the org's link domains allow it, else
+// the environment's base URL.
+func (s *Service) LinkOrigin(ctx context.Context, orgID int, requested string) (string, error) {
+	ld, err := s.storage.GetLinkDomains(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+	var orgOrigins []string
+	if ld != nil {
+		orgOrigins = ld.Origins
+	}
+	return applinks.FromEnv().Origin(requested, orgOrigins), nil
+}
+
 // GetInvitationOrgID returns the org_id for an invitation (for authorization)
 func (s *Service) GetInvitationOrgID(ctx context.Context, inviteID int) (int, error) {
 	return s.storage.GetInvitationOrgID(ctx, inviteID)
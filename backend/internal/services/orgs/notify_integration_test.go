@@ -40,7 +40,7 @@ func TestNotifyOrgCreated_NotifiesAllSuperadmins(t *testing.T) {
 	pool := store.Pool().(*pgxpool.Pool)
 	seedSuperadmins(t, pool, "ops1@example.com", "ops2@example.com")
 
-	svc := NewService(pool, store, email.NewClient())
+	svc := NewService(store, email.NewClient())
 	org := organization.Organization{Name: "Acme Co", Identifier: "acme-co"}
 
 	sent := svc.notifyOrgCreated(context.Background(), org, "creator@example.com")
@@ -55,7 +55,7 @@ func TestNotifyOrgDeleted_NotifiesAllSuperadmins(t *testing.T) {
 	pool := store.Pool().(*pgxpool.Pool)
 	seedSuperadmins(t, pool, "ops1@example.com", "ops2@example.com")
 
-	svc := NewService(pool, store, email.NewClient())
+	svc := NewService(store, email.NewClient())
 
 	sent := svc.notifyOrgDeleted(context.Background(), "Acme Co", "acme-co", "actor@example.com", time.Now())
 	require.Equal(t, 2, sent, "should notify exactly the two superadmins")
@@ -71,7 +71,7 @@ func TestNotifyOrgCreated_OverrideAddr(t *testing.T) {
 	pool := store.Pool().(*pgxpool.Pool)
 	seedSuperadmins(t, pool, "ops1@example.com", "ops2@example.com")
 
-	svc := NewService(pool, store, email.NewClient())
+	svc := NewService(store, email.NewClient())
 	org := organization.Organization{Name: "Acme Co", Identifier: "acme-co"}
 
 	sent := svc.notifyOrgCreated(context.Background(), org, "creator@example.com")
@@ -87,7 +87,7 @@ func TestNotifyOrgDeleted_OverrideAddr(t *testing.T) {
 	pool := store.Pool().(*pgxpool.Pool)
 	seedSuperadmins(t, pool, "ops1@example.com", "ops2@example.com")
 
-	svc := NewService(pool, store, email.NewClient())
+	svc := NewService(store, email.NewClient())
 
 	sent := svc.notifyOrgDeleted(context.Background(), "Acme Co", "acme-co", "actor@example.com", time.Now())
 	require.Equal(t, 1, sent, "override sends to exactly one address, not the two superadmins")
@@ -97,9 +97,8 @@ func TestNotifyOrgDeleted_OverrideAddr(t *testing.T) {
 func TestNotifyOrg_NilClientIsNoOp(t *testing.T) {
 	store, cleanup := testutil.SetupTestDB(t)
 	defer cleanup()
-	pool := store.Pool().(*pgxpool.Pool)
 
-	svc := NewService(pool, store, nil)
+	svc := NewService(store, nil)
 	require.Equal(t, 0, svc.notifyOrgCreated(context.Background(), organization.Organization{Name: "X", Identifier: "x"}, "c@example.com"))
 	require.Equal(t, 0, svc.notifyOrgDeleted(context.Background(), "X", "x", "a@example.com", time.Now()))
 }
@@ -29,7 +29,7 @@ func TestGetUserProfile_IncludesCurrentOrgIdentifier(t *testing.T) {
 		orgID, userID)
 	require.NoError(t, err)
 
-	svc := orgsservice.NewService(db.AdminPool, db.Store, nil)
+	svc := orgsservice.NewService(db.Store, nil)
 	profile, err := svc.GetUserProfile(ctx, userID)
 	require.NoError(t, err)
 	require.NotNil(t, profile.CurrentOrg)
@@ -53,7 +53,7 @@ func TestGetUserProfile_IncludesEntitlement(t *testing.T) {
 		orgID, userID)
 	require.NoError(t, err)
 
-	svc := orgsservice.NewService(db.AdminPool, db.Store, nil)
+	svc := orgsservice.NewService(db.Store, nil)
 	profile, err := svc.GetUserProfile(ctx, userID)
 	require.NoError(t, err)
 	require.NotNil(t, profile.CurrentOrg)
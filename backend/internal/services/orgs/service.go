@@ -8,7 +8,8 @@ import (
 	"strings"
 	"time"
 
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5"
+	"github.com/trakrf/platform/backend/internal/errorreport"
 	"github.com/trakrf/platform/backend/internal/models"
 	"github.com/trakrf/platform/backend/internal/models/organization"
 	"github.com/trakrf/platform/backend/internal/services/email"
@@ -16,59 +17,57 @@ import (
 )
 
 type Service struct {
-	db          *pgxpool.Pool
 	storage     *storage.Storage
 	emailClient *email.Client
 }
 
-func NewService(db *pgxpool.Pool, storage *storage.Storage, emailClient *email.Client) *Service {
-	return &Service{db: db, storage: storage, emailClient: emailClient}
+func NewService(storage *storage.Storage, emailClient *email.Client) *Service {
+	return &Service{storage: storage, emailClient: emailClient}
 }
 
-// CreateOrgWithAdmin creates a new team org and makes the creator an admin.
+// CreateOrgWithAdmin creates a new team org and makes the creator its owner
+// and an admin.
 // creatorEmail is used only for the best-effort superadmin notification (TRA-977).
 func (s *Service) CreateOrgWithAdmin(ctx context.Context, name string, creatorUserID int, creatorEmail string) (*organization.Organization, error) {
 	identifier := slugifyOrgName(name)
 
-	tx, err := s.db.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// Create org
 	var org organization.Organization
-	orgQuery := `
-		INSERT INTO trakrf.organizations (name, identifier)
-		VALUES ($1, $2)
-		RETURNING id, name, identifier, metadata,
-		          valid_from, valid_to, is_active, created_at, updated_at
-	`
-	err = tx.QueryRow(ctx, orgQuery, name, identifier).Scan(
-		&org.ID, &org.Name, &org.Identifier, &org.Metadata,
-		&org.ValidFrom, &org.ValidTo, &org.IsActive, &org.CreatedAt, &org.UpdatedAt)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
-			return nil, fmt.Errorf("organization identifier already taken")
+	err := s.storage.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Create org
+		orgQuery := `
+			INSERT INTO trakrf.organizations (name, identifier, owner_user_id)
+			VALUES ($1, $2, $3)
+			RETURNING id, name, identifier, metadata,
+			          valid_from, valid_to, is_active, created_at, updated_at
+		`
+		err := tx.QueryRow(ctx, orgQuery, name, identifier, creatorUserID).Scan(
+			&org.ID, &org.Name, &org.Identifier, &org.Metadata,
+			&org.ValidFrom, &org.ValidTo, &org.IsActive, &org.CreatedAt, &org.UpdatedAt)
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") {
+				return fmt.Errorf("organization identifier already taken")
+			}
+			return fmt.Errorf("failed to create organization: %w", err)
 		}
-		return nil, fmt.Errorf("failed to create organization: %w", err)
-	}
 
-	// Add creator as admin
-	orgUserQuery := `INSERT INTO trakrf.org_users (org_id, user_id, role) VALUES ($1, $2, 'admin')`
-	_, err = tx.Exec(ctx, orgUserQuery, org.ID, creatorUserID)
+		// Add creator as admin
+		orgUserQuery := `INSERT INTO trakrf.org_users (org_id, user_id, role) VALUES ($1, $2, 'admin')`
+		_, err = tx.Exec(ctx, orgUserQuery, org.ID, creatorUserID)
+		if err != nil {
+			return fmt.Errorf("failed to add creator to org: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to add creator to org: %w", err)
-	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return nil, err
 	}
 
 	// Notify superadmins of the new org (TRA-977). Fire-and-forget on a detached
 	// context so it never delays or fails the create. Internal creates leave
 	// subscription_expires_at NULL (perpetual).
-	go s.notifyOrgCreated(context.Background(), org, creatorEmail)
+	errorreport.SafeGo(context.Background(), errorreport.Tags{"job": "notify_org_created"}, func() {
+		s.notifyOrgCreated(context.Background(), org, creatorEmail)
+	}, nil)
 
 	return &org, nil
 }
@@ -105,7 +104,9 @@ func (s *Service) DeleteOrgWithConfirmation(ctx context.Context, orgID int, conf
 
 	// Notify superadmins of the churn (TRA-977). Fire-and-forget on a detached
 	// context so it never delays or fails the delete response.
-	go s.notifyOrgDeleted(context.Background(), origName, origIdentifier, actorEmail, deletedAt)
+	errorreport.SafeGo(context.Background(), errorreport.Tags{"job": "notify_org_deleted"}, func() {
+		s.notifyOrgDeleted(context.Background(), origName, origIdentifier, actorEmail, deletedAt)
+	}, nil)
 
 	return nil
 }
@@ -206,9 +207,10 @@ func (s *Service) GetUserProfile(ctx context.Context, userID int) (*organization
 			for _, org := range orgs {
 				if org.ID == currentOrgID {
 					cur := &organization.UserOrgWithRole{
-						ID:   org.ID,
-						Name: org.Name,
-						Role: string(role),
+						ID:        org.ID,
+						Name:      org.Name,
+						Role:      string(role),
+						IsSandbox: org.IsSandbox,
 					}
 					// TRA-922: include the org slug so the UI can pre-fill the
 					// required {org_slug}/ publish_topic prefix. Best-effort — a
@@ -266,7 +268,7 @@ func (s *Service) ListMembers(ctx context.Context, orgID int) ([]organization.Or
 	return members, nil
 }
 
-// UpdateMemberRole updates a member's role with last-admin protection
+// UpdateMemberRole updates a member's role with last-admin and owner protection
 func (s *Service) UpdateMemberRole(ctx context.Context, orgID, targetUserID int, newRole models.OrgRole) error {
 	// Get current role
 	currentRole, err := s.storage.GetUserOrgRole(ctx, targetUserID, orgID)
@@ -274,6 +276,13 @@ func (s *Service) UpdateMemberRole(ctx context.Context, orgID, targetUserID int,
 		return fmt.Errorf("member not found")
 	}
 
+	// The owner stays an admin until ownership is transferred
+	if newRole != models.RoleAdmin {
+		if err := s.checkNotOwner(ctx, orgID, targetUserID, "cannot demote the owner"); err != nil {
+			return err
+		}
+	}
+
 	// If demoting from admin, check if they're the last admin
 	if currentRole == models.RoleAdmin && newRole != models.RoleAdmin {
 		adminCount, err := s.storage.CountOrgAdmins(ctx, orgID)
@@ -288,7 +297,7 @@ func (s *Service) UpdateMemberRole(ctx context.Context, orgID, targetUserID int,
 	return s.storage.UpdateMemberRole(ctx, orgID, targetUserID, newRole)
 }
 
-// RemoveMember removes a member with last-admin and self-removal protection
+// RemoveMember removes a member with last-admin, owner and self-removal protection
 func (s *Service) RemoveMember(ctx context.Context, orgID, targetUserID, actorUserID int) error {
 	// Prevent self-removal
 	if targetUserID == actorUserID {
@@ -301,6 +310,10 @@ func (s *Service) RemoveMember(ctx context.Context, orgID, targetUserID, actorUs
 		return fmt.Errorf("member not found")
 	}
 
+	if err := s.checkNotOwner(ctx, orgID, targetUserID, "cannot remove the owner"); err != nil {
+		return err
+	}
+
 	// If removing an admin, check if they're the last admin
 	if targetRole == models.RoleAdmin {
 		adminCount, err := s.storage.CountOrgAdmins(ctx, orgID)
@@ -314,3 +327,15 @@ func (s *Service) RemoveMember(ctx context.Context, orgID, targetUserID, actorUs
 
 	return s.storage.RemoveMember(ctx, orgID, targetUserID)
 }
+
+// checkNotOwner returns an error with message msg when userID owns orgID.
+func (s *Service) checkNotOwner(ctx context.Context, orgID, userID int, msg string) error {
+	owner, err := s.storage.GetOrgOwnerID(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to check owner: %w", err)
+	}
+	if owner != nil && *owner == userID {
+		return errors.New(msg)
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+// Package signage renders printable location signs: a QR code of the
+// location's deep link with its name, external key and place in the tree,
+// six to an A4 page for cutting out.
+package signage
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/trakrf/platform/backend/internal/qrcode"
+)
+
+// Sign is one location's sign.
+type Sign struct {
+	URL         string
+	Name        string
+	ExternalKey string
+	// Path is the location's ancestors' names, root first.
+	Path []string
+}
+
+// Page layout in points: A4 portrait, a 2 x 3 grid of cells. All text is
+// Courier, whose fixed advance (0.6 em) lets lines be centred and cut to
+// the cell without font metrics.
+const (
+	pageWidth   = 595
+	pageHeight  = 842
+	pageMargin  = 36
+	columns     = 2
+	rows        = 3
+	perPage     = columns * rows
+	cellWidth   = (pageWidth - 2*pageMargin) / columns
+	cellHeight  = (pageHeight - 2*pageMargin) / rows
+	cellPadding = 12
+	qrSide      = 150
+	quietZone   = 4
+)
+
+// text lines under the code: font, size and the gap above each baseline.
+var lines = []struct {
+	font string
+	size float64
+	gap  float64
+	gray float64
+}{
+	{"F2", 14, 26, 0},   // name
+	{"F1", 11, 16, 0},   // external key
+	{"F1", 8, 13, 0.35}, // path
+	{"F1", 7, 11, 0.35}, // URL
+}
+
+// RenderPDF renders the signs in order. It fails only when a URL is too
+// long to encode.
+func RenderPDF(signs []Sign) ([]byte, error) {
+	var pages []string
+	for start := 0; start < len(signs) || start == 0; start += perPage {
+		var s bytes.Buffer
+		for i, sign := range signs[start:min(start+perPage, len(signs))] {
+			if err := drawSign(&s, sign, i%columns, i/columns); err != nil {
+				return nil, fmt.Errorf("sign %q: %w", sign.ExternalKey, err)
+			}
+		}
+		pages = append(pages, s.String())
+	}
+	return writePDF(pages), nil
+}
+
+// drawSign draws one sign into cell col, row (from the top left), with a
+// light dashed cut line around the cell.
+func drawSign(s *bytes.Buffer, sign Sign, col, row int) error {
+	code, err := qrcode.Encode([]byte(sign.URL))
+	if err != nil {
+		return err
+	}
+	x0 := float64(pageMargin + col*cellWidth)
+	top := float64(pageHeight - pageMargin - row*cellHeight)
+	fmt.Fprintf(s, "q 0.8 G 0.5 w [3 3] 0 d %.2f %.2f %d %d re S Q\n", x0, top-cellHeight, cellWidth, cellHeight)
+
+	// The code, with its quiet zone, centred at the top of the cell; runs
+	// of dark modules become one rectangle each.
+	module := float64(qrSide) / float64(code.Size+2*quietZone)
+	qx := x0 + (cellWidth-qrSide)/2 + quietZone*module
+	qy := top - cellPadding - quietZone*module
+	s.WriteString("q 0 g\n")
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; {
+			if !code.Dark(x, y) {
+				x++
+				continue
+			}
+			run := x
+			for run < code.Size && code.Dark(run, y) {
+				run++
+			}
+			fmt.Fprintf(s, "%.3f %.3f %.3f %.3f re\n", qx+float64(x)*module, qy-float64(y+1)*module, float64(run-x)*module, module)
+			x = run
+		}
+	}
+	s.WriteString("f Q\n")
+
+	texts := []string{
+		fit(sign.Name, lines[0].size, false),
+		fit(sign.ExternalKey, lines[1].size, false),
+		fit(strings.Join(sign.Path, " / "), lines[2].size, true),
+		fit(sign.URL, lines[3].size, false),
+	}
+	y := top - cellPadding - qrSide
+	for i, l := range lines {
+		y -= l.gap
+		if texts[i] == "" {
+			continue
+		}
+		width := float64(len([]rune(texts[i]))) * 0.6 * l.size
+		fmt.Fprintf(s, "BT %.2f g /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n",
+			l.gray, l.font, l.size, x0+(cellWidth-width)/2, y, escape(texts[i]))
+	}
+	return nil
+}
+
+// fit shortens text to the cell's width at size points, with an ellipsis at
+// the end, or at the start when keepEnd is set (for a path, whose nearest
+// ancestors matter most).
+func fit(text string, size float64, keepEnd bool) string {
+	r := []rune(text)
+	limit := int((cellWidth - 2*cellPadding) / (0.6 * size))
+	if len(r) <= limit {
+		return text
+	}
+	if keepEnd {
+		return "..." + string(r[len(r)-limit+3:])
+	}
+	return string(r[:limit-3]) + "..."
+}
+
+// writePDF writes a minimal PDF 1.4 file: a catalog, a page tree, Courier
+// and Courier-Bold, and one content stream per page.
+func writePDF(pages []string) []byte {
+	var buf bytes.Buffer
+	var offsets []int
+	obj := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// Objects 1-4 are fixed; page i is object 5+2i and its content 6+2i.
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	obj("<< /Type /Catalog /Pages 2 0 R >>")
+	obj(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	obj("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	for i, content := range pages {
+		obj(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, 6+2*i))
+		obj(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes()
+}
+
+// escape escapes a string literal and maps it to WinAnsi: Latin-1 runes
+// pass through as single bytes, anything else becomes '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x20:
+			b.WriteByte(' ')
+		case r < 0x80:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
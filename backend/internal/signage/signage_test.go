@@ -0,0 +1,105 @@
+package signage
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/qrcode"
+)
+
+func TestRenderPDF_Pages(t *testing.T) {
+	signs := make([]Sign, 7)
+	for i := range signs {
+		signs[i] = Sign{URL: fmt.Sprintf("https://app.trakrf.id/locations/%d", i+1), Name: fmt.Sprintf("Bay %d", i+1)}
+	}
+	pdf, err := RenderPDF(signs)
+	require.NoError(t, err)
+
+	assert.True(t, bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(pdf, []byte("%%EOF\n")))
+	assert.Contains(t, string(pdf), "/Count 2 >>")
+	assert.Equal(t, 2, bytes.Count(pdf, []byte("/Type /Page /Parent")))
+	assert.Equal(t, 7, bytes.Count(pdf, []byte("(Bay ")))
+
+	// Every xref offset points at its object.
+	start := bytes.LastIndex(pdf, []byte("startxref\n"))
+	xref, err := strconv.Atoi(strings.Fields(string(pdf[start+len("startxref\n"):]))[0])
+	require.NoError(t, err)
+	entries := strings.Split(string(pdf[xref:]), "\n")[3:]
+	for i := 1; i <= 4+2*2; i++ {
+		off, err := strconv.Atoi(strings.Fields(entries[i-1])[0])
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(pdf[off:], []byte(fmt.Sprintf("%d 0 obj\n", i))), "object %d", i)
+	}
+}
+
+func TestRenderPDF_Empty(t *testing.T) {
+	pdf, err := RenderPDF(nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(pdf), "/Count 1 >>")
+}
+
+func TestRenderPDF_TooLong(t *testing.T) {
+	_, err := RenderPDF([]Sign{{URL: strings.Repeat("x", qrcode.MaxBytes+1), ExternalKey: "LOC-1"}})
+	assert.ErrorIs(t, err, qrcode.ErrTooLong)
+}
+
+var rect = regexp.MustCompile(`(?m)^([\d.]+) ([\d.]+) ([\d.]+) ([\d.]+) re$`)
+
+func TestDrawSign_QRModules(t *testing.T) {
+	const url = "https://app.trakrf.id/locations/123456"
+	var s bytes.Buffer
+	require.NoError(t, drawSign(&s, Sign{URL: url, Name: "Dock"}, 0, 0))
+
+	code, err := qrcode.Encode([]byte(url))
+	require.NoError(t, err)
+	module := float64(qrSide) / float64(code.Size+2*quietZone)
+	qx := pageMargin + (cellWidth-qrSide)/2 + quietZone*module
+	qy := pageHeight - pageMargin - cellPadding - quietZone*module
+
+	dark := make([][]bool, code.Size)
+	for y := range dark {
+		dark[y] = make([]bool, code.Size)
+	}
+	for _, m := range rect.FindAllStringSubmatch(s.String(), -1) {
+		var v [4]float64
+		for i := range v {
+			v[i], _ = strconv.ParseFloat(m[i+1], 64)
+		}
+		x := int((v[0]-qx)/module + 0.5)
+		y := int((qy-v[1])/module+0.5) - 1
+		for n := 0; n < int(v[2]/module+0.5); n++ {
+			dark[y][x+n] = true
+		}
+	}
+	for y := 0; y < code.Size; y++ {
+		for x := 0; x < code.Size; x++ {
+			require.Equal(t, code.Dark(x, y), dark[y][x], "module %d,%d", x, y)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	size := 8.0
+	limit := int((cellWidth - 2*cellPadding) / (0.6 * size))
+	assert.Equal(t, "Warehouse", fit("Warehouse", 8, false))
+
+	long := strings.Repeat("a", limit) + "bc"
+	assert.Equal(t, strings.Repeat("a", limit-3)+"...", fit(long, 8, false))
+	got := fit(long, 8, true)
+	assert.Equal(t, limit, len(got))
+	assert.True(t, strings.HasPrefix(got, "...") && strings.HasSuffix(got, "bc"))
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `Bay \(north\) \\ 2`, escape(`Bay (north) \ 2`))
+	assert.Equal(t, "Caf\xe9 ?", escape("Café 倉"))
+	assert.Equal(t, "a b", escape("a\nb"))
+}
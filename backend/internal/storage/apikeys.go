@@ -23,32 +23,53 @@ func (s *Storage) CreateAPIKey(
 	scopes []string,
 	creator apikey.Creator,
 	expiresAt *time.Time,
+) (*apikey.APIKey, error) {
+	return s.CreateServiceAccountAPIKey(ctx, orgID, nil, name, secretHash, scopes, creator, expiresAt)
+}
+
+// CreateServiceAccountAPIKey is CreateAPIKey with an owning service account.
+// A nil serviceAccountID mints an unowned key. The caller verifies the
+// account is live in orgID.
+func (s *Storage) CreateServiceAccountAPIKey(
+	ctx context.Context,
+	orgID int,
+	serviceAccountID *int,
+	name string,
+	secretHash string,
+	scopes []string,
+	creator apikey.Creator,
+	expiresAt *time.Time,
 ) (*apikey.APIKey, error) {
 	if (creator.UserID == nil) == (creator.KeyID == nil) {
 		return nil, fmt.Errorf("creator must have exactly one of UserID/KeyID set")
 	}
+	stored, err := s.sealAPIKeySecretHash(secretHash)
+	if err != nil {
+		return nil, fmt.Errorf("seal api key secret: %w", err)
+	}
 	var k apikey.APIKey
-	err := s.pool.QueryRow(ctx, `
+	err = s.pool.QueryRow(ctx, `
         INSERT INTO trakrf.api_keys
-            (org_id, name, secret_hash, scopes, created_by, created_by_key_id, expires_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7)
-        RETURNING id, jti, secret_hash, org_id, name, scopes, created_by, created_by_key_id,
+            (org_id, name, secret_hash, scopes, created_by, created_by_key_id, service_account_id, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+        RETURNING id, jti, secret_hash, org_id, name, scopes, created_by, created_by_key_id, service_account_id,
                   created_at, expires_at, last_used_at, revoked_at
-    `, orgID, name, secretHash, scopes, creator.UserID, creator.KeyID, expiresAt).Scan(
+    `, orgID, name, stored, scopes, creator.UserID, creator.KeyID, serviceAccountID, expiresAt).Scan(
 		&k.ID, &k.JTI, &k.SecretHash, &k.OrgID, &k.Name, &k.Scopes,
-		&k.CreatedBy, &k.CreatedByKeyID,
+		&k.CreatedBy, &k.CreatedByKeyID, &k.ServiceAccountID,
 		&k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("insert api_keys: %w", err)
 	}
+	k.SecretHash = secretHash
 	return &k, nil
 }
 
 // ListActiveAPIKeys returns non-revoked keys for the given org, newest first.
 func (s *Storage) ListActiveAPIKeys(ctx context.Context, orgID int) ([]apikey.APIKey, error) {
 	rows, err := s.pool.Query(ctx, `
-        SELECT id, jti, org_id, name, scopes, created_by, created_by_key_id,
+        SELECT id, jti, org_id, name, scopes, created_by, created_by_key_id, service_account_id,
                created_at, expires_at, last_used_at, revoked_at
         FROM trakrf.api_keys
         WHERE org_id = $1 AND revoked_at IS NULL
@@ -64,7 +85,7 @@ func (s *Storage) ListActiveAPIKeys(ctx context.Context, orgID int) ([]apikey.AP
 		var k apikey.APIKey
 		if err := rows.Scan(
 			&k.ID, &k.JTI, &k.OrgID, &k.Name, &k.Scopes,
-			&k.CreatedBy, &k.CreatedByKeyID,
+			&k.CreatedBy, &k.CreatedByKeyID, &k.ServiceAccountID,
 			&k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan api_key row: %w", err)
@@ -80,7 +101,7 @@ func (s *Storage) ListActiveAPIKeys(ctx context.Context, orgID int) ([]apikey.AP
 // created in the same instant.
 func (s *Storage) ListActiveAPIKeysPaginated(ctx context.Context, orgID, limit, offset int) ([]apikey.APIKey, error) {
 	rows, err := s.pool.Query(ctx, `
-        SELECT id, jti, org_id, name, scopes, created_by, created_by_key_id,
+        SELECT id, jti, org_id, name, scopes, created_by, created_by_key_id, service_account_id,
                created_at, expires_at, last_used_at, revoked_at
         FROM trakrf.api_keys
         WHERE org_id = $1 AND revoked_at IS NULL
@@ -97,7 +118,7 @@ func (s *Storage) ListActiveAPIKeysPaginated(ctx context.Context, orgID, limit,
 		var k apikey.APIKey
 		if err := rows.Scan(
 			&k.ID, &k.JTI, &k.OrgID, &k.Name, &k.Scopes,
-			&k.CreatedBy, &k.CreatedByKeyID,
+			&k.CreatedBy, &k.CreatedByKeyID, &k.ServiceAccountID,
 			&k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan api_key row: %w", err)
@@ -126,13 +147,13 @@ func (s *Storage) CountActiveAPIKeys(ctx context.Context, orgID int) (int, error
 func (s *Storage) GetAPIKeyByJTI(ctx context.Context, jti string) (*apikey.APIKey, error) {
 	var k apikey.APIKey
 	err := s.pool.QueryRow(ctx, `
-        SELECT id, jti, secret_hash, org_id, name, scopes, created_by, created_by_key_id,
+        SELECT id, jti, secret_hash, org_id, name, scopes, created_by, created_by_key_id, service_account_id,
                created_at, expires_at, last_used_at, revoked_at
         FROM trakrf.api_keys
         WHERE jti = $1
     `, jti).Scan(
 		&k.ID, &k.JTI, &k.SecretHash, &k.OrgID, &k.Name, &k.Scopes,
-		&k.CreatedBy, &k.CreatedByKeyID,
+		&k.CreatedBy, &k.CreatedByKeyID, &k.ServiceAccountID,
 		&k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt,
 	)
 	if err != nil {
@@ -141,6 +162,9 @@ func (s *Storage) GetAPIKeyByJTI(ctx context.Context, jti string) (*apikey.APIKe
 		}
 		return nil, fmt.Errorf("get api_key by jti: %w", err)
 	}
+	if k.SecretHash, err = s.openAPIKeySecretHash(k.SecretHash); err != nil {
+		return nil, err
+	}
 	return &k, nil
 }
 
@@ -150,13 +174,13 @@ func (s *Storage) GetAPIKeyByJTI(ctx context.Context, jti string) (*apikey.APIKe
 func (s *Storage) GetAPIKeyByID(ctx context.Context, id int64) (*apikey.APIKey, error) {
 	var k apikey.APIKey
 	err := s.pool.QueryRow(ctx, `
-        SELECT id, jti, secret_hash, org_id, name, scopes, created_by, created_by_key_id,
+        SELECT id, jti, secret_hash, org_id, name, scopes, created_by, created_by_key_id, service_account_id,
                created_at, expires_at, last_used_at, revoked_at
         FROM trakrf.api_keys
         WHERE id = $1
     `, id).Scan(
 		&k.ID, &k.JTI, &k.SecretHash, &k.OrgID, &k.Name, &k.Scopes,
-		&k.CreatedBy, &k.CreatedByKeyID,
+		&k.CreatedBy, &k.CreatedByKeyID, &k.ServiceAccountID,
 		&k.CreatedAt, &k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt,
 	)
 	if err != nil {
@@ -165,6 +189,9 @@ func (s *Storage) GetAPIKeyByID(ctx context.Context, id int64) (*apikey.APIKey,
 		}
 		return nil, fmt.Errorf("get api_key by id: %w", err)
 	}
+	if k.SecretHash, err = s.openAPIKeySecretHash(k.SecretHash); err != nil {
+		return nil, err
+	}
 	return &k, nil
 }
 
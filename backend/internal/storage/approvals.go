@@ -0,0 +1,319 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/approval"
+)
+
+var (
+	// ErrApprovalState is returned when the approval request's status does
+	// not allow the action. Expired requests allow none.
+	ErrApprovalState = errors.New("the approval request's status does not allow this action")
+	// ErrApprovalSelfDecision is returned when the requester tries to approve
+	// or reject their own request.
+	ErrApprovalSelfDecision = errors.New("an approval request must be decided by a different admin")
+	// ErrApprovalNotRequester is returned when someone other than the
+	// requester tries to cancel a request.
+	ErrApprovalNotRequester = errors.New("only the requester can cancel an approval request")
+)
+
+// approvalStatusExpr reads a pending or approved request past its expiry as
+// expired.
+const approvalStatusExpr = `CASE WHEN r.status IN ('pending', 'approved') AND r.expires_at <= NOW()
+	            THEN 'expired' ELSE r.status END`
+
+const approvalRequestSelect = `
+	SELECT r.id, r.org_id, r.operation, r.method, r.path, r.body, r.summary, ` + approvalStatusExpr + `,
+	       r.requested_by, r.decided_by, r.decided_at, r.decision_note, r.expires_at, r.used_at,
+	       r.created_at, r.updated_at
+	FROM trakrf.approval_requests r`
+
+func scanApprovalRequest(row pgx.Row) (*approval.Request, error) {
+	var a approval.Request
+	if err := row.Scan(&a.ID, &a.OrgID, &a.Operation, &a.Method, &a.Path, &a.Body, &a.Summary, &a.Status,
+		&a.RequestedBy, &a.DecidedBy, &a.DecidedAt, &a.DecisionNote, &a.ExpiresAt, &a.UsedAt,
+		&a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// nextApprovalStatus returns the status action moves a request in status to.
+// Approve and reject need a pending request and cancel a pending or approved
+// one; an expired request cannot move.
+func nextApprovalStatus(status, action string) (string, error) {
+	var next string
+	var from []string
+	switch action {
+	case approval.ActionApprove:
+		next, from = approval.StatusApproved, []string{approval.StatusPending}
+	case approval.ActionReject:
+		next, from = approval.StatusRejected, []string{approval.StatusPending}
+	case approval.ActionCancel:
+		next, from = approval.StatusCancelled, []string{approval.StatusPending, approval.StatusApproved}
+	default:
+		return "", fmt.Errorf("unknown approval action %q", action)
+	}
+	for _, s := range from {
+		if status == s {
+			return next, nil
+		}
+	}
+	return "", ErrApprovalState
+}
+
+// ListApprovalRules returns orgID's approval rules.
+func (s *Storage) ListApprovalRules(ctx context.Context, orgID int) ([]approval.Rule, error) {
+	out := []approval.Rule{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT org_id, operation, threshold, ttl_hours, created_at, updated_at
+			FROM trakrf.approval_rules
+			WHERE org_id = $1
+			ORDER BY operation`, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var r approval.Rule
+			if err := rows.Scan(&r.OrgID, &r.Operation, &r.Threshold, &r.TTLHours, &r.CreatedAt, &r.UpdatedAt); err != nil {
+				return err
+			}
+			out = append(out, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval rules: %w", err)
+	}
+	return out, nil
+}
+
+// GetApprovalRule returns orgID's rule for op, or nil when op needs no
+// approval.
+func (s *Storage) GetApprovalRule(ctx context.Context, orgID int, op string) (*approval.Rule, error) {
+	var r *approval.Rule
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var rule approval.Rule
+		err := tx.QueryRow(ctx, `
+			SELECT org_id, operation, threshold, ttl_hours, created_at, updated_at
+			FROM trakrf.approval_rules
+			WHERE org_id = $1 AND operation = $2`, orgID, op).
+			Scan(&rule.OrgID, &rule.Operation, &rule.Threshold, &rule.TTLHours, &rule.CreatedAt, &rule.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r = &rule
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval rule: %w", err)
+	}
+	return r, nil
+}
+
+// SetApprovalRule creates or replaces orgID's rule for op. An unset TTL
+// falls back to approval.DefaultTTLHours.
+func (s *Storage) SetApprovalRule(ctx context.Context, orgID int, op string, req approval.RuleRequest) (*approval.Rule, error) {
+	ttl := approval.DefaultTTLHours
+	if req.TTLHours != nil {
+		ttl = *req.TTLHours
+	}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			INSERT INTO trakrf.approval_rules (org_id, operation, threshold, ttl_hours)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (org_id, operation)
+			DO UPDATE SET threshold = EXCLUDED.threshold, ttl_hours = EXCLUDED.ttl_hours`,
+			orgID, op, req.Threshold, ttl)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set approval rule: %w", err)
+	}
+	return s.GetApprovalRule(ctx, orgID, op)
+}
+
+// DeleteApprovalRule removes orgID's rule for op. Requests already parked
+// under it keep their status. Returns false when there was no rule.
+func (s *Storage) DeleteApprovalRule(ctx context.Context, orgID int, op string) (bool, error) {
+	var deleted bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			DELETE FROM trakrf.approval_rules WHERE org_id = $1 AND operation = $2`, orgID, op)
+		if err != nil {
+			return err
+		}
+		deleted = tag.RowsAffected() > 0
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete approval rule: %w", err)
+	}
+	return deleted, nil
+}
+
+// CreateApprovalRequest parks a gated request by userID as pending, expiring
+// req.TTLHours from now.
+func (s *Storage) CreateApprovalRequest(ctx context.Context, orgID, userID int, req approval.NewRequest) (*approval.Request, error) {
+	var body json.RawMessage
+	if len(req.Body) > 0 {
+		body = req.Body
+	}
+	var id int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, `
+			INSERT INTO trakrf.approval_requests
+			    (org_id, operation, method, path, body, body_sha256, summary, requested_by, expires_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW() + $9::int * INTERVAL '1 hour')
+			RETURNING id`,
+			orgID, req.Operation, req.Method, req.Path, body, req.BodySHA256, req.Summary, userID, req.TTLHours).Scan(&id)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create approval request: %w", err)
+	}
+	return s.GetApprovalRequest(ctx, orgID, id)
+}
+
+// ListApprovalRequests returns orgID's approval requests, newest first.
+// Filtering on approval.StatusExpired matches lapsed requests.
+func (s *Storage) ListApprovalRequests(ctx context.Context, orgID int, f approval.ListFilter) ([]approval.Request, error) {
+	var status, op any
+	if f.Status != "" {
+		status = f.Status
+	}
+	if f.Operation != "" {
+		op = f.Operation
+	}
+	out := []approval.Request{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, approvalRequestSelect+`
+			WHERE r.org_id = $1
+			  AND ($2::text IS NULL OR `+approvalStatusExpr+` = $2)
+			  AND ($3::text IS NULL OR r.operation = $3)
+			ORDER BY r.created_at DESC, r.id
+			LIMIT $4`, orgID, status, op, f.Limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			a, err := scanApprovalRequest(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval requests: %w", err)
+	}
+	return out, nil
+}
+
+// GetApprovalRequest returns one of orgID's approval requests, or nil.
+func (s *Storage) GetApprovalRequest(ctx context.Context, orgID, id int) (*approval.Request, error) {
+	var a *approval.Request
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		a, err = scanApprovalRequest(tx.QueryRow(ctx, approvalRequestSelect+`
+			WHERE r.id = $1 AND r.org_id = $2`, id, orgID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			a = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval request: %w", err)
+	}
+	return a, nil
+}
+
+// DecideApprovalRequest approves, rejects or cancels a request, recording
+// userID and note as the decision. Approve and reject must come from someone
+// other than the requester, and cancel from the requester. Returns (nil, nil)
+// when orgID has no such request, ErrApprovalState when its status does not
+// allow action, and ErrApprovalSelfDecision or ErrApprovalNotRequester.
+func (s *Storage) DecideApprovalRequest(ctx context.Context, orgID, id, userID int, action string, note *string) (*approval.Request, error) {
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var status string
+		var requestedBy *int
+		err := tx.QueryRow(ctx, `
+			SELECT `+approvalStatusExpr+`, r.requested_by FROM trakrf.approval_requests r
+			WHERE r.id = $1 AND r.org_id = $2
+			FOR UPDATE`, id, orgID).Scan(&status, &requestedBy)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("lock approval request: %w", err)
+		}
+		found = true
+		byRequester := requestedBy != nil && *requestedBy == userID
+		if action == approval.ActionCancel && !byRequester {
+			return ErrApprovalNotRequester
+		}
+		if action != approval.ActionCancel && byRequester {
+			return ErrApprovalSelfDecision
+		}
+		next, err := nextApprovalStatus(status, action)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.approval_requests
+			SET status = $2, decided_by = $3, decided_at = NOW(), decision_note = $4
+			WHERE id = $1`, id, next, userID, note)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrApprovalState) || errors.Is(err, ErrApprovalSelfDecision) ||
+			errors.Is(err, ErrApprovalNotRequester) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to %s approval request: %w", action, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.GetApprovalRequest(ctx, orgID, id)
+}
+
+// ConsumeApproval spends approved request id on a replay by userID. It
+// succeeds only when the request is approved, unexpired, was made by userID,
+// and names the same operation, method, path and body as req; it then reads
+// as used and cannot be replayed again.
+func (s *Storage) ConsumeApproval(ctx context.Context, orgID, id, userID int, req approval.NewRequest) (bool, error) {
+	var used bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE trakrf.approval_requests
+			SET status = 'used', used_at = NOW()
+			WHERE id = $1 AND org_id = $2 AND requested_by = $3
+			  AND status = 'approved' AND expires_at > NOW()
+			  AND operation = $4 AND method = $5 AND path = $6 AND body_sha256 = $7`,
+			id, orgID, userID, req.Operation, req.Method, req.Path, req.BodySHA256)
+		if err != nil {
+			return err
+		}
+		used = tag.RowsAffected() == 1
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to use approval: %w", err)
+	}
+	return used, nil
+}
@@ -0,0 +1,109 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/approval"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestApprovals_SecondAdminApprovesThenReplayRuns(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	var requester, approver int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('mgr', 'mgr@x', 'stub') RETURNING id`,
+	).Scan(&requester))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('adm', 'adm@x', 'stub') RETURNING id`,
+	).Scan(&approver))
+	a1 := testutil.CreateTestAsset(t, pool, orgID, "BULK-1")
+	a2 := testutil.CreateTestAsset(t, pool, orgID, "BULK-2")
+
+	rule, err := store.GetApprovalRule(ctx, orgID, approval.OpAssetsBulkDelete)
+	require.NoError(t, err)
+	assert.Nil(t, rule)
+
+	one := 1
+	rule, err = store.SetApprovalRule(ctx, orgID, approval.OpAssetsBulkDelete, approval.RuleRequest{Threshold: &one})
+	require.NoError(t, err)
+	assert.Equal(t, approval.DefaultTTLHours, rule.TTLHours)
+
+	nr := approval.NewRequest{
+		Operation:  approval.OpAssetsBulkDelete,
+		Method:     "POST",
+		Path:       "/api/v1/assets/bulk-delete",
+		Body:       []byte(`{"asset_ids":[1,2]}`),
+		BodySHA256: "abc",
+		Summary:    "Delete 2 assets",
+		TTLHours:   rule.TTLHours,
+	}
+	req, err := store.CreateApprovalRequest(ctx, orgID, requester, nr)
+	require.NoError(t, err)
+	assert.Equal(t, approval.StatusPending, req.Status)
+	assert.JSONEq(t, `{"asset_ids":[1,2]}`, string(req.Body))
+
+	used, err := store.ConsumeApproval(ctx, orgID, req.ID, requester, nr)
+	require.NoError(t, err)
+	assert.False(t, used, "a pending request cannot be used")
+
+	_, err = store.DecideApprovalRequest(ctx, orgID, req.ID, requester, approval.ActionApprove, nil)
+	assert.ErrorIs(t, err, storage.ErrApprovalSelfDecision)
+	_, err = store.DecideApprovalRequest(ctx, orgID, req.ID, approver, approval.ActionCancel, nil)
+	assert.ErrorIs(t, err, storage.ErrApprovalNotRequester)
+
+	req, err = store.DecideApprovalRequest(ctx, orgID, req.ID, approver, approval.ActionApprove, nil)
+	require.NoError(t, err)
+	assert.Equal(t, approval.StatusApproved, req.Status)
+
+	other := nr
+	other.BodySHA256 = "def"
+	used, err = store.ConsumeApproval(ctx, orgID, req.ID, requester, other)
+	require.NoError(t, err)
+	assert.False(t, used, "a different body must not match")
+	used, err = store.ConsumeApproval(ctx, orgID, req.ID, approver, nr)
+	require.NoError(t, err)
+	assert.False(t, used, "only the requester can replay")
+
+	used, err = store.ConsumeApproval(ctx, orgID, req.ID, requester, nr)
+	require.NoError(t, err)
+	assert.True(t, used)
+	used, err = store.ConsumeApproval(ctx, orgID, req.ID, requester, nr)
+	require.NoError(t, err)
+	assert.False(t, used, "an approval is spent once")
+
+	deleted, err := store.DeleteAssets(ctx, orgID, []int{a1.ID, a2.ID, a2.ID + 1000000}, team.Scope{}, location.SubtreeScope{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []int{a1.ID, a2.ID}, deleted)
+
+	// A request past its expiry reads as expired and cannot be approved.
+	late, err := store.CreateApprovalRequest(ctx, orgID, requester, nr)
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE trakrf.approval_requests SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, late.ID)
+	require.NoError(t, err)
+	_, err = store.DecideApprovalRequest(ctx, orgID, late.ID, approver, approval.ActionApprove, nil)
+	assert.ErrorIs(t, err, storage.ErrApprovalState)
+
+	expired, err := store.ListApprovalRequests(ctx, orgID, approval.ListFilter{Status: approval.StatusExpired, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, late.ID, expired[0].ID)
+
+	ok, err := store.DeleteApprovalRule(ctx, orgID, approval.OpAssetsBulkDelete)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
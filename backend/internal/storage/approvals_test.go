@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/approval"
+)
+
+func TestNextApprovalStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		action  string
+		want    string
+		wantErr error
+	}{
+		{"approve pending", approval.StatusPending, approval.ActionApprove, approval.StatusApproved, nil},
+		{"reject pending", approval.StatusPending, approval.ActionReject, approval.StatusRejected, nil},
+		{"cancel pending", approval.StatusPending, approval.ActionCancel, approval.StatusCancelled, nil},
+		{"cancel approved", approval.StatusApproved, approval.ActionCancel, approval.StatusCancelled, nil},
+		{"approved cannot be rejected", approval.StatusApproved, approval.ActionReject, "", ErrApprovalState},
+		{"expired cannot be approved", approval.StatusExpired, approval.ActionApprove, "", ErrApprovalState},
+		{"expired cannot be cancelled", approval.StatusExpired, approval.ActionCancel, "", ErrApprovalState},
+		{"used cannot be cancelled", approval.StatusUsed, approval.ActionCancel, "", ErrApprovalState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextApprovalStatus(tt.status, tt.action)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := nextApprovalStatus(approval.StatusPending, "escalate")
+	assert.Error(t, err)
+}
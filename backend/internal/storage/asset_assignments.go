@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+)
+
+// ErrAssetNotCheckedOut is returned when checking in an asset that has no
+// owner.
+var ErrAssetNotCheckedOut = errors.New("asset is not checked out")
+
+// insertAssetAssignment records a check-out or check-in, and the condition
+// report that came with it, inside the caller's transaction.
+func insertAssetAssignment(ctx context.Context, tx pgx.Tx, orgID, assetID int, action string, userID *int, rec asset.AssignmentRecord) error {
+	var id int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO trakrf.asset_assignments (org_id, asset_id, action, user_id, performed_by, note, signature)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		orgID, assetID, action, userID, rec.PerformedBy, rec.Note, rec.Signature).Scan(&id); err != nil {
+		return fmt.Errorf("record asset assignment: %w", err)
+	}
+	if rec.Condition != nil {
+		if _, err := insertConditionReport(ctx, tx, orgID, assetID, &id, rec.PerformedBy, *rec.Condition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckInAsset clears the asset's owner and records a check-in carrying rec.
+// It returns nil when the asset does not exist, and ErrAssetNotCheckedOut
+// when it has no owner.
+func (s *Storage) CheckInAsset(ctx context.Context, orgID, id int, rec asset.AssignmentRecord) (*asset.AssetView, error) {
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var owner *int
+		err := tx.QueryRow(ctx, `
+			SELECT owner_user_id FROM trakrf.assets
+			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+			FOR UPDATE`, id, orgID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+		if owner == nil {
+			return ErrAssetNotCheckedOut
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.assets SET owner_user_id = NULL, updated_at = NOW()
+			WHERE id = $1 AND org_id = $2`, id, orgID); err != nil {
+			return err
+		}
+		if err := insertAssetAssignment(ctx, tx, orgID, id, asset.AssignmentCheckIn, owner, rec); err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.AssetUpdated, orgID, id)
+	})
+	if err != nil {
+		if errors.Is(err, ErrAssetNotCheckedOut) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to check in asset: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.getAssetViewWithTagsByID(ctx, orgID, id)
+}
+
+// ListAssetAssignments returns the asset's check-outs and check-ins, newest
+// first.
+func (s *Storage) ListAssetAssignments(ctx context.Context, orgID, assetID int) ([]asset.Assignment, error) {
+	out := []asset.Assignment{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT id, asset_id, action, user_id, performed_by, note,
+			       signature->>'signer_name', signature IS NOT NULL, created_at
+			FROM trakrf.asset_assignments
+			WHERE org_id = $1 AND asset_id = $2
+			ORDER BY created_at DESC, id DESC`, orgID, assetID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var a asset.Assignment
+			if err := rows.Scan(&a.ID, &a.AssetID, &a.Action, &a.UserID, &a.PerformedBy, &a.Note,
+				&a.SignerName, &a.HasSignature, &a.CreatedAt); err != nil {
+				return err
+			}
+			out = append(out, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list asset assignments: %w", err)
+	}
+	return out, nil
+}
+
+// GetAssignmentSignature returns the signature captured with one of the
+// asset's assignments, or nil when there is no such assignment or it was
+// not signed.
+func (s *Storage) GetAssignmentSignature(ctx context.Context, orgID, assetID, assignmentID int) (*asset.Signature, error) {
+	var sig *asset.Signature
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT signature FROM trakrf.asset_assignments
+			WHERE id = $3 AND org_id = $1 AND asset_id = $2`, orgID, assetID, assignmentID).Scan(&sig)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assignment signature: %w", err)
+	}
+	return sig, nil
+}
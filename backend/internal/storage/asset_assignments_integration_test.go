@@ -0,0 +1,86 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestAssetAssignments_SignaturesReachCustody(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	var ownerID, clerkID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('Dana', 'dana@x', 'stub') RETURNING id`,
+	).Scan(&ownerID))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('Clerk', 'clerk@x', 'stub') RETURNING id`,
+	).Scan(&clerkID))
+	drill := testutil.CreateTestAsset(t, pool, orgID, "DRILL-1")
+	from := time.Now().Add(-time.Minute)
+
+	_, err := store.CheckInAsset(ctx, orgID, drill.ID, asset.AssignmentRecord{})
+	assert.ErrorIs(t, err, storage.ErrAssetNotCheckedOut)
+
+	sig := &asset.Signature{SignerName: "Dana", Width: 100, Height: 40,
+		Strokes: [][]asset.SignaturePoint{{{X: 1, Y: 1}, {X: 99, Y: 39}}}}
+	note := "For the site visit"
+	out, _, err := store.TransferAssetOwnership(ctx, orgID, drill.ID, ownerID,
+		asset.AssignmentRecord{PerformedBy: &clerkID, Note: &note, Signature: sig})
+	require.NoError(t, err)
+	require.NotNil(t, out)
+
+	// Re-transferring to the same owner records nothing.
+	_, _, err = store.TransferAssetOwnership(ctx, orgID, drill.ID, ownerID, asset.AssignmentRecord{})
+	require.NoError(t, err)
+
+	in, err := store.CheckInAsset(ctx, orgID, drill.ID, asset.AssignmentRecord{PerformedBy: &clerkID})
+	require.NoError(t, err)
+	require.NotNil(t, in)
+	assert.Nil(t, in.OwnerUserID)
+
+	list, err := store.ListAssetAssignments(ctx, orgID, drill.ID)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, asset.AssignmentCheckIn, list[0].Action)
+	assert.False(t, list[0].HasSignature)
+	assert.Equal(t, ownerID, *list[0].UserID, "check-in names the returning owner")
+	assert.Equal(t, asset.AssignmentCheckOut, list[1].Action)
+	assert.True(t, list[1].HasSignature)
+	assert.Equal(t, "Dana", *list[1].SignerName)
+
+	got, err := store.GetAssignmentSignature(ctx, orgID, drill.ID, list[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, sig, got)
+	got, err = store.GetAssignmentSignature(ctx, orgID, drill.ID, list[0].ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	rec, err := store.GetCustodyRecord(ctx, orgID, drill.ID, from, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, rec.Events, 2)
+	out1 := rec.Events[0]
+	assert.Equal(t, report.CustodyCheckOut, out1.Kind)
+	assert.Equal(t, "to Dana <dana@x>: For the site visit", *out1.Detail)
+	assert.Equal(t, clerkID, out1.Actor.UserID)
+	require.NotNil(t, out1.Signature)
+	assert.Equal(t, sig.Digest(), out1.Signature.SHA256)
+	assert.Equal(t, "strokes", out1.Signature.Format)
+	assert.Equal(t, report.CustodyCheckIn, rec.Events[1].Kind)
+	assert.Nil(t, rec.Events[1].Signature)
+}
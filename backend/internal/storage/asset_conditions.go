@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+)
+
+// ErrConditionPhotoLimit is returned when a condition report already holds
+// asset.MaxConditionPhotos photos.
+var ErrConditionPhotoLimit = fmt.Errorf("a condition report holds at most %d photos", asset.MaxConditionPhotos)
+
+// insertConditionReport records a condition report inside the caller's
+// transaction and returns its id.
+func insertConditionReport(ctx context.Context, tx pgx.Tx, orgID, assetID int, assignmentID, reportedBy *int, in asset.ConditionInput) (int, error) {
+	var id int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO trakrf.asset_condition_reports (org_id, asset_id, assignment_id, rating, notes, reported_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id`,
+		orgID, assetID, assignmentID, in.Rating, in.Notes, reportedBy).Scan(&id); err != nil {
+		return 0, fmt.Errorf("record condition report: %w", err)
+	}
+	return id, nil
+}
+
+// CreateConditionReport records an ad hoc condition report for the asset.
+// The caller has already checked that the asset exists.
+func (s *Storage) CreateConditionReport(ctx context.Context, orgID, assetID int, reportedBy *int, in asset.ConditionInput) (*asset.ConditionReport, error) {
+	var out *asset.ConditionReport
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		id, err := insertConditionReport(ctx, tx, orgID, assetID, nil, reportedBy, in)
+		if err != nil {
+			return err
+		}
+		reports, err := listConditionReports(ctx, tx, orgID, assetID, &id)
+		if err != nil {
+			return err
+		}
+		out = &reports[0]
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create condition report: %w", err)
+	}
+	return out, nil
+}
+
+// ListConditionReports returns the asset's condition reports with their
+// photos, newest first.
+func (s *Storage) ListConditionReports(ctx context.Context, orgID, assetID int) ([]asset.ConditionReport, error) {
+	var out []asset.ConditionReport
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		out, err = listConditionReports(ctx, tx, orgID, assetID, nil)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list condition reports: %w", err)
+	}
+	return out, nil
+}
+
+// listConditionReports loads the asset's condition reports, or only
+// reportID when set, and attaches their photos.
+func listConditionReports(ctx context.Context, tx pgx.Tx, orgID, assetID int, reportID *int) ([]asset.ConditionReport, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT id, asset_id, assignment_id, rating, notes, reported_by, created_at
+		FROM trakrf.asset_condition_reports
+		WHERE org_id = $1 AND asset_id = $2 AND ($3::bigint IS NULL OR id = $3)
+		ORDER BY created_at DESC, id DESC`, orgID, assetID, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("list condition reports: %w", err)
+	}
+	out := []asset.ConditionReport{}
+	index := map[int]int{}
+	ids := []int{}
+	for rows.Next() {
+		r := asset.ConditionReport{Photos: []asset.ConditionPhoto{}}
+		if err := rows.Scan(&r.ID, &r.AssetID, &r.AssignmentID, &r.Rating, &r.Notes, &r.ReportedBy, &r.CreatedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan condition report: %w", err)
+		}
+		index[r.ID] = len(out)
+		ids = append(ids, r.ID)
+		out = append(out, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list condition reports: %w", err)
+	}
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	rows, err = tx.Query(ctx, `
+		SELECT report_id, id, url, content_type, size_bytes, created_at
+		FROM trakrf.asset_condition_photos
+		WHERE org_id = $1 AND report_id = ANY($2)
+		ORDER BY created_at, id`, orgID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("list condition photos: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var reportID int
+		var p asset.ConditionPhoto
+		if err := rows.Scan(&reportID, &p.ID, &p.URL, &p.ContentType, &p.SizeBytes, &p.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan condition photo: %w", err)
+		}
+		r := &out[index[reportID]]
+		r.Photos = append(r.Photos, p)
+	}
+	return out, rows.Err()
+}
+
+// AddConditionPhoto attaches an uploaded photo to the asset's condition
+// report reportID. It returns nil when there is no such report, and
+// ErrConditionPhotoLimit when the report is full.
+func (s *Storage) AddConditionPhoto(ctx context.Context, orgID, assetID, reportID int, url, contentType string, sizeBytes int) (*asset.ConditionPhoto, error) {
+	var out *asset.ConditionPhoto
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT id FROM trakrf.asset_condition_reports
+			WHERE id = $3 AND org_id = $1 AND asset_id = $2
+			FOR UPDATE`, orgID, assetID, reportID).Scan(&reportID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var n int
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM trakrf.asset_condition_photos WHERE report_id = $1`, reportID).Scan(&n); err != nil {
+			return err
+		}
+		if n >= asset.MaxConditionPhotos {
+			return ErrConditionPhotoLimit
+		}
+		p := asset.ConditionPhoto{URL: url, ContentType: contentType, SizeBytes: sizeBytes}
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.asset_condition_photos (org_id, report_id, url, content_type, size_bytes)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at`, orgID, reportID, url, contentType, sizeBytes).Scan(&p.ID, &p.CreatedAt); err != nil {
+			return err
+		}
+		out = &p
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrConditionPhotoLimit) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to add condition photo: %w", err)
+	}
+	return out, nil
+}
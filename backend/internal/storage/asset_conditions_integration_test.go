@@ -0,0 +1,86 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestConditionReports_PhotosAssignmentsAndBelowThreshold(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	var userID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('Tech', 'tech@x', 'stub') RETURNING id`,
+	).Scan(&userID))
+	drill := testutil.CreateTestAsset(t, pool, orgID, "DRILL-1")
+	ladder := testutil.CreateTestAsset(t, pool, orgID, "LADDER-1")
+	cart := testutil.CreateTestAsset(t, pool, orgID, "CART-1")
+
+	// The drill goes out in good shape and comes back damaged.
+	_, _, err := store.TransferAssetOwnership(ctx, orgID, drill.ID, userID,
+		asset.AssignmentRecord{PerformedBy: &userID, Condition: &asset.ConditionInput{Rating: 4}})
+	require.NoError(t, err)
+	notes := "Chuck cracked"
+	_, err = store.CheckInAsset(ctx, orgID, drill.ID,
+		asset.AssignmentRecord{PerformedBy: &userID, Condition: &asset.ConditionInput{Rating: 2, Notes: &notes}})
+	require.NoError(t, err)
+
+	// The ladder has only ever been poor; the cart is fine.
+	poor, err := store.CreateConditionReport(ctx, orgID, ladder.ID, nil, asset.ConditionInput{Rating: 1})
+	require.NoError(t, err)
+	assert.Nil(t, poor.AssignmentID)
+	assert.Empty(t, poor.Photos)
+	_, err = store.CreateConditionReport(ctx, orgID, cart.ID, &userID, asset.ConditionInput{Rating: 5})
+	require.NoError(t, err)
+
+	reports, err := store.ListConditionReports(ctx, orgID, drill.ID)
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, 2, reports[0].Rating)
+	require.NotNil(t, reports[0].AssignmentID, "recorded with the check-in")
+	assignments, err := store.ListAssetAssignments(ctx, orgID, drill.ID)
+	require.NoError(t, err)
+	assert.Equal(t, assignments[0].ID, *reports[0].AssignmentID)
+
+	for i := 0; i < asset.MaxConditionPhotos; i++ {
+		p, err := store.AddConditionPhoto(ctx, orgID, drill.ID, reports[0].ID, "https://cdn/x.jpg", "image/jpeg", 1024)
+		require.NoError(t, err)
+		require.NotNil(t, p)
+	}
+	_, err = store.AddConditionPhoto(ctx, orgID, drill.ID, reports[0].ID, "https://cdn/y.jpg", "image/jpeg", 1024)
+	assert.ErrorIs(t, err, storage.ErrConditionPhotoLimit)
+	p, err := store.AddConditionPhoto(ctx, orgID, ladder.ID, reports[0].ID, "https://cdn/z.jpg", "image/jpeg", 1024)
+	require.NoError(t, err)
+	assert.Nil(t, p, "the report belongs to another asset")
+
+	items, total, err := store.ListConditionBelow(ctx, orgID, report.ConditionBelowFilter{Below: 3, Limit: 50})
+	require.NoError(t, err)
+	require.Equal(t, 2, total)
+	assert.Equal(t, ladder.ID, items[0].AssetID, "lowest rating first")
+	assert.Nil(t, items[0].PreviousRating)
+	assert.False(t, items[0].Dropped)
+	assert.Equal(t, drill.ID, items[1].AssetID)
+	assert.Equal(t, 4, *items[1].PreviousRating)
+	assert.True(t, items[1].Dropped)
+	assert.Equal(t, asset.MaxConditionPhotos, items[1].PhotoCount)
+
+	items, total, err = store.ListConditionBelow(ctx, orgID, report.ConditionBelowFilter{Below: 3, DroppedOnly: true, Limit: 50})
+	require.NoError(t, err)
+	require.Equal(t, 1, total)
+	assert.Equal(t, drill.ID, items[0].AssetID)
+}
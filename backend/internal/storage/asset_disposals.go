@@ -0,0 +1,262 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/assetdisposal"
+)
+
+var (
+	// ErrAssetDisposalAssetNotFound is returned when the asset to dispose of
+	// is not a live asset of the org.
+	ErrAssetDisposalAssetNotFound = errors.New("asset not found")
+	// ErrAssetDisposalOpen is returned when the asset already has a pending
+	// or approved disposal.
+	ErrAssetDisposalOpen = errors.New("the asset already has an open disposal")
+	// ErrAssetDisposed is returned when the asset has already been disposed
+	// of.
+	ErrAssetDisposed = errors.New("the asset has already been disposed of")
+	// ErrAssetDisposalState is returned when the disposal's status does not
+	// allow the action.
+	ErrAssetDisposalState = errors.New("the disposal's status does not allow this action")
+)
+
+const assetDisposalSelect = `
+	SELECT d.id, d.org_id, d.asset_id, a.external_key, a.name, d.reason, d.method, d.status,
+	       d.requested_by, d.decided_by, d.decided_at, d.decision_note, d.executed_by, d.executed_at,
+	       d.created_at, d.updated_at
+	FROM trakrf.asset_disposals d
+	JOIN trakrf.assets a ON a.id = d.asset_id`
+
+func scanAssetDisposal(row pgx.Row) (*assetdisposal.Disposal, error) {
+	var d assetdisposal.Disposal
+	if err := row.Scan(&d.ID, &d.OrgID, &d.AssetID, &d.AssetExternalKey, &d.AssetName,
+		&d.Reason, &d.Method, &d.Status,
+		&d.RequestedBy, &d.DecidedBy, &d.DecidedAt, &d.DecisionNote, &d.ExecutedBy, &d.ExecutedAt,
+		&d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// nextDisposalStatus returns the status action moves a disposal in status to.
+// Approve and reject need a pending disposal, cancel a pending or approved
+// one, and execute an approved one.
+func nextDisposalStatus(status, action string) (string, error) {
+	var next string
+	var from []string
+	switch action {
+	case assetdisposal.ActionApprove:
+		next, from = assetdisposal.StatusApproved, []string{assetdisposal.StatusPending}
+	case assetdisposal.ActionReject:
+		next, from = assetdisposal.StatusRejected, []string{assetdisposal.StatusPending}
+	case assetdisposal.ActionCancel:
+		next, from = assetdisposal.StatusCancelled,
+			[]string{assetdisposal.StatusPending, assetdisposal.StatusApproved}
+	case assetdisposal.ActionExecute:
+		next, from = assetdisposal.StatusCompleted, []string{assetdisposal.StatusApproved}
+	default:
+		return "", fmt.Errorf("unknown asset disposal action %q", action)
+	}
+	for _, s := range from {
+		if status == s {
+			return next, nil
+		}
+	}
+	return "", ErrAssetDisposalState
+}
+
+// CreateAssetDisposal opens a pending disposal of one of orgID's live assets.
+// Returns ErrAssetDisposalAssetNotFound, ErrAssetDisposed or
+// ErrAssetDisposalOpen.
+func (s *Storage) CreateAssetDisposal(ctx context.Context, orgID, userID int, req assetdisposal.CreateRequest) (*assetdisposal.Disposal, error) {
+	var id int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var disposed bool
+		err := tx.QueryRow(ctx, `
+			SELECT disposed_at IS NOT NULL FROM trakrf.assets
+			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+			FOR UPDATE`, req.AssetID, orgID).Scan(&disposed)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAssetDisposalAssetNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if disposed {
+			return ErrAssetDisposed
+		}
+		return tx.QueryRow(ctx, `
+			INSERT INTO trakrf.asset_disposals (org_id, asset_id, reason, method, requested_by)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id`,
+			orgID, req.AssetID, req.Reason, req.Method, userID).Scan(&id)
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_asset_disposals_open" {
+			return nil, ErrAssetDisposalOpen
+		}
+		if errors.Is(err, ErrAssetDisposalAssetNotFound) || errors.Is(err, ErrAssetDisposed) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create asset disposal: %w", err)
+	}
+	return s.GetAssetDisposal(ctx, orgID, id)
+}
+
+// ListAssetDisposals returns orgID's disposals, newest first.
+func (s *Storage) ListAssetDisposals(ctx context.Context, orgID int, f assetdisposal.ListFilter) ([]assetdisposal.Disposal, error) {
+	var status any
+	if f.Status != "" {
+		status = f.Status
+	}
+	out := []assetdisposal.Disposal{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, assetDisposalSelect+`
+			WHERE d.org_id = $1
+			  AND ($2::bigint IS NULL OR d.asset_id = $2)
+			  AND ($3::text IS NULL OR d.status = $3)
+			ORDER BY d.created_at DESC, d.id
+			LIMIT $4`, orgID, f.AssetID, status, f.Limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			d, err := scanAssetDisposal(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *d)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list asset disposals: %w", err)
+	}
+	return out, nil
+}
+
+// GetAssetDisposal returns one of orgID's disposals, or nil.
+func (s *Storage) GetAssetDisposal(ctx context.Context, orgID, id int) (*assetdisposal.Disposal, error) {
+	var d *assetdisposal.Disposal
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		d, err = scanAssetDisposal(tx.QueryRow(ctx, assetDisposalSelect+`
+			WHERE d.id = $1 AND d.org_id = $2`, id, orgID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			d = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset disposal: %w", err)
+	}
+	return d, nil
+}
+
+// lockAssetDisposalStatus reads a disposal's asset and status FOR UPDATE on
+// tx. found is false when orgID has no such disposal.
+func lockAssetDisposalStatus(ctx context.Context, tx pgx.Tx, orgID, id int) (assetID int, status string, found bool, err error) {
+	err = tx.QueryRow(ctx, `
+		SELECT asset_id, status FROM trakrf.asset_disposals
+		WHERE id = $1 AND org_id = $2
+		FOR UPDATE`, id, orgID).Scan(&assetID, &status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, "", false, nil
+	}
+	if err != nil {
+		return 0, "", false, fmt.Errorf("lock asset disposal: %w", err)
+	}
+	return assetID, status, true, nil
+}
+
+// DecideAssetDisposal approves, rejects or cancels a disposal, recording
+// userID and note as the decision. Returns (nil, nil) when orgID has no such
+// disposal and ErrAssetDisposalState when its status does not allow action.
+func (s *Storage) DecideAssetDisposal(ctx context.Context, orgID, id, userID int, action string, note *string) (*assetdisposal.Disposal, error) {
+	if action == assetdisposal.ActionExecute {
+		return nil, fmt.Errorf("use ExecuteAssetDisposal to execute a disposal")
+	}
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		_, status, ok, err := lockAssetDisposalStatus(ctx, tx, orgID, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		next, err := nextDisposalStatus(status, action)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.asset_disposals
+			SET status = $2, decided_by = $3, decided_at = NOW(), decision_note = $4
+			WHERE id = $1`, id, next, userID, note)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrAssetDisposalState) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to %s asset disposal: %w", action, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.GetAssetDisposal(ctx, orgID, id)
+}
+
+// ExecuteAssetDisposal completes an approved disposal: the asset is stamped
+// disposed_at and deactivated, after which every scan path drops its reads.
+// The asset and its history stay readable. Returns (nil, nil) when orgID has
+// no such disposal, ErrAssetDisposalState when it is not approved, and
+// ErrAssetDisposalAssetNotFound when the asset was deleted since approval.
+func (s *Storage) ExecuteAssetDisposal(ctx context.Context, orgID, id, userID int) (*assetdisposal.Disposal, error) {
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		assetID, status, ok, err := lockAssetDisposalStatus(ctx, tx, orgID, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		next, err := nextDisposalStatus(status, assetdisposal.ActionExecute)
+		if err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `
+			UPDATE trakrf.assets SET disposed_at = NOW(), is_active = false
+			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, assetID, orgID)
+		if err != nil {
+			return fmt.Errorf("dispose asset: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrAssetDisposalAssetNotFound
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.asset_disposals
+			SET status = $2, executed_by = $3, executed_at = NOW()
+			WHERE id = $1`, id, next, userID); err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.AssetUpdated, orgID, assetID)
+	})
+	if err != nil {
+		if errors.Is(err, ErrAssetDisposalState) || errors.Is(err, ErrAssetDisposalAssetNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to execute asset disposal: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.GetAssetDisposal(ctx, orgID, id)
+}
@@ -0,0 +1,73 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/assetdisposal"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestAssetDisposal_WorkflowBlocksLaterScans(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	var userID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('ops', 'ops@x', 'stub') RETURNING id`,
+	).Scan(&userID))
+	forklift := testutil.CreateTestAsset(t, pool, orgID, "FORKLIFT-3")
+	var locID int
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO trakrf.locations (org_id, external_key, name)
+		VALUES ($1, 'YARD', 'Yard') RETURNING id`, orgID).Scan(&locID))
+
+	req := assetdisposal.CreateRequest{AssetID: forklift.ID, Reason: "Mast cracked", Method: "scrapped"}
+	d, err := store.CreateAssetDisposal(ctx, orgID, userID, req)
+	require.NoError(t, err)
+	assert.Equal(t, assetdisposal.StatusPending, d.Status)
+	assert.Equal(t, "FORKLIFT-3", d.AssetExternalKey)
+
+	_, err = store.CreateAssetDisposal(ctx, orgID, userID, req)
+	assert.ErrorIs(t, err, storage.ErrAssetDisposalOpen)
+	_, err = store.ExecuteAssetDisposal(ctx, orgID, d.ID, userID)
+	assert.ErrorIs(t, err, storage.ErrAssetDisposalState, "a pending disposal cannot be executed")
+
+	note := "Approved at review"
+	d, err = store.DecideAssetDisposal(ctx, orgID, d.ID, userID, assetdisposal.ActionApprove, &note)
+	require.NoError(t, err)
+	assert.Equal(t, assetdisposal.StatusApproved, d.Status)
+	assert.Equal(t, note, *d.DecisionNote)
+
+	// Scans still land until the disposal is executed.
+	_, err = store.SaveInventoryScans(ctx, orgID, storage.SaveInventoryRequest{LocationID: locID, AssetIDs: []int{forklift.ID}})
+	require.NoError(t, err)
+
+	d, err = store.ExecuteAssetDisposal(ctx, orgID, d.ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, assetdisposal.StatusCompleted, d.Status)
+	require.NotNil(t, d.ExecutedAt)
+
+	_, err = store.SaveInventoryScans(ctx, orgID, storage.SaveInventoryRequest{LocationID: locID, AssetIDs: []int{forklift.ID}})
+	var accessErr *storage.InventoryAccessError
+	require.True(t, errors.As(err, &accessErr), "got %v", err)
+	assert.Equal(t, []int{forklift.ID}, accessErr.DisposedAssetIDs)
+
+	_, err = store.CreateAssetDisposal(ctx, orgID, userID, req)
+	assert.ErrorIs(t, err, storage.ErrAssetDisposed)
+
+	list, err := store.ListAssetDisposals(ctx, orgID, assetdisposal.ListFilter{AssetID: &forklift.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+}
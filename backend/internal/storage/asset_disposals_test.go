@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/assetdisposal"
+)
+
+func TestNextDisposalStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		action  string
+		want    string
+		wantErr error
+	}{
+		{"approve pending", assetdisposal.StatusPending, assetdisposal.ActionApprove, assetdisposal.StatusApproved, nil},
+		{"reject pending", assetdisposal.StatusPending, assetdisposal.ActionReject, assetdisposal.StatusRejected, nil},
+		{"cancel pending", assetdisposal.StatusPending, assetdisposal.ActionCancel, assetdisposal.StatusCancelled, nil},
+		{"cancel approved", assetdisposal.StatusApproved, assetdisposal.ActionCancel, assetdisposal.StatusCancelled, nil},
+		{"execute approved", assetdisposal.StatusApproved, assetdisposal.ActionExecute, assetdisposal.StatusCompleted, nil},
+		{"pending cannot execute", assetdisposal.StatusPending, assetdisposal.ActionExecute, "", ErrAssetDisposalState},
+		{"approved cannot be rejected", assetdisposal.StatusApproved, assetdisposal.ActionReject, "", ErrAssetDisposalState},
+		{"rejected cannot be approved", assetdisposal.StatusRejected, assetdisposal.ActionApprove, "", ErrAssetDisposalState},
+		{"completed cannot be cancelled", assetdisposal.StatusCompleted, assetdisposal.ActionCancel, "", ErrAssetDisposalState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextDisposalStatus(tt.status, tt.action)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := nextDisposalStatus(assetdisposal.StatusPending, "shred")
+	assert.Error(t, err)
+}
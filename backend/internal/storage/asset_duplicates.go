@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+)
+
+// FindAssetDuplicates returns pairs of orgID's live assets that are probably
+// the same asset, scored by asset.FindDuplicates and cut to f.Limit. The
+// serial is read from the first of metadata's serial_number, serial,
+// serialNumber or sn keys that is set.
+func (s *Storage) FindAssetDuplicates(ctx context.Context, orgID int, f asset.DuplicateFilter) ([]asset.DuplicateSuggestion, error) {
+	var (
+		cands    []asset.DuplicateCandidate
+		overlaps []asset.IdentifierOverlap
+	)
+	var teamIDs []int
+	if f.TeamScope.Restricted {
+		teamIDs = f.TeamScope.TeamIDs
+		if teamIDs == nil {
+			teamIDs = []int{}
+		}
+	}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT id, external_key, name,
+			       COALESCE(metadata->>'serial_number', metadata->>'serial',
+			                metadata->>'serialNumber', metadata->>'sn', ''),
+			       created_at
+			FROM trakrf.assets
+			WHERE org_id = $1 AND deleted_at IS NULL
+			  AND ($2::bigint[] IS NULL OR team_id = ANY($2))
+			  AND ($3::bigint[] IS NULL OR `+assetSubtreeClause("id", 3)+`)`,
+			orgID, teamIDs, scopeRoots(f.SubtreeScope))
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var c asset.DuplicateCandidate
+			if err := rows.Scan(&c.ID, &c.ExternalKey, &c.Name, &c.Serial, &c.CreatedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			cands = append(cands, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		// Live tag values are unique per org, so a value shared by two live
+		// assets means at least one of the tags was removed: the trace of
+		// the same physical tag being re-imported onto a second record.
+		rows, err = tx.Query(ctx, `
+			SELECT DISTINCT t1.asset_id, t2.asset_id, t1.type, t1.value
+			FROM trakrf.tags t1
+			JOIN trakrf.tags t2
+			  ON t2.org_id = t1.org_id AND t2.value = t1.value AND t2.asset_id > t1.asset_id
+			JOIN trakrf.assets a1 ON a1.id = t1.asset_id AND a1.deleted_at IS NULL
+			JOIN trakrf.assets a2 ON a2.id = t2.asset_id AND a2.deleted_at IS NULL
+			WHERE t1.org_id = $1`, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var o asset.IdentifierOverlap
+			if err := rows.Scan(&o.AssetA, &o.AssetB, &o.Type, &o.Value); err != nil {
+				return err
+			}
+			overlaps = append(overlaps, o)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find asset duplicates: %w", err)
+	}
+
+	out := asset.FindDuplicates(cands, overlaps, f.MinScore)
+	if f.Limit > 0 && len(out) > f.Limit {
+		out = out[:f.Limit]
+	}
+	return out, nil
+}
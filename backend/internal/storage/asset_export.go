@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+)
+
+// AssetStreamBatch is how many assets StreamAssets reads per page.
+const AssetStreamBatch = 1000
+
+// StreamAssets calls fn with every asset matching f, in id order, one page of
+// at most AssetStreamBatch at a time. f's sorts, limit and offset are
+// ignored. Pages are read by keyset (id after the last one seen), each in its
+// own short transaction, so memory stays flat however many rows match and no
+// snapshot is held open for the length of the stream; a row written mid-stream
+// is included if its id is still ahead. Tags are attached unless f.Fields
+// leaves them out.
+//
+// It stops at the first error from fn, or when ctx is cancelled, returning
+// that error.
+func (s *Storage) StreamAssets(ctx context.Context, orgID int, f asset.ListFilter, fn func([]asset.AssetView) error) error {
+	where, args, err := buildAssetsWhere(orgID, f)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf(`
+		SELECT
+			a.id, a.org_id, a.external_key, a.name, COALESCE(a.description, ''),
+			a.valid_from, a.valid_to, a.metadata,
+			a.is_active, a.created_at, a.updated_at, a.deleted_at,
+			a.owner_user_id, a.cost_center
+		FROM trakrf.assets a
+		WHERE %s AND a.id > $%d
+		ORDER BY a.id
+		LIMIT %d
+	`, where, len(args)+1, AssetStreamBatch)
+
+	after := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var page []asset.AssetView
+		err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+			rows, err := tx.Query(ctx, query, append(args, after)...)
+			if err != nil {
+				return err
+			}
+			page, err = scanAssetViews(rows)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("stream assets: %w", err)
+		}
+		if len(page) == 0 {
+			return nil
+		}
+		if wantsTags(f.Fields) {
+			if err := s.attachAssetTags(ctx, orgID, page); err != nil {
+				return err
+			}
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if len(page) < AssetStreamBatch {
+			return nil
+		}
+		after = page[len(page)-1].ID
+	}
+}
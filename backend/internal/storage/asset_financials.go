@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+)
+
+// scanFinancials reads asset_id, currency, purchase_cost, book_value,
+// updated_at into an asset.Financials.
+func scanFinancials(row pgx.Row) (*asset.Financials, error) {
+	var f asset.Financials
+	var purchase, book *int64
+	if err := row.Scan(&f.AssetID, &f.Currency, &purchase, &book, &f.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if purchase != nil {
+		f.PurchaseCost = &shared.Money{Amount: *purchase, Currency: f.Currency}
+	}
+	if book != nil {
+		f.BookValue = &shared.Money{Amount: *book, Currency: f.Currency}
+	}
+	return &f, nil
+}
+
+// GetAssetFinancials returns the asset's financial fields, or nil when none
+// were ever set.
+func (s *Storage) GetAssetFinancials(ctx context.Context, orgID, assetID int) (*asset.Financials, error) {
+	var out *asset.Financials
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		f, err := scanFinancials(tx.QueryRow(ctx, `
+			SELECT asset_id, currency, purchase_cost, book_value, updated_at
+			FROM trakrf.asset_financials
+			WHERE asset_id = $1 AND org_id = $2`, assetID, orgID))
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		out = f
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset financials: %w", err)
+	}
+	return out, nil
+}
+
+// SetAssetFinancials replaces the asset's financial fields. Amounts are in
+// minor units of currency; nil clears one.
+func (s *Storage) SetAssetFinancials(ctx context.Context, orgID, assetID int, currency string, purchaseCost, bookValue *int64) (*asset.Financials, error) {
+	var out *asset.Financials
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		out, err = scanFinancials(tx.QueryRow(ctx, `
+			INSERT INTO trakrf.asset_financials (asset_id, org_id, currency, purchase_cost, book_value)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (asset_id) DO UPDATE
+			SET currency = EXCLUDED.currency,
+			    purchase_cost = EXCLUDED.purchase_cost,
+			    book_value = EXCLUDED.book_value
+			RETURNING asset_id, currency, purchase_cost, book_value, updated_at`,
+			assetID, orgID, currency, purchaseCost, bookValue))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set asset financials: %w", err)
+	}
+	return out, nil
+}
+
+// ListAssetValues returns the live assets with financials, highest book
+// value first, the totals per currency across all of them, and the count.
+// Amounts are formatted for filter.Locale.
+func (s *Storage) ListAssetValues(ctx context.Context, orgID int, filter report.AssetValueFilter) ([]report.AssetValueItem, []report.AssetValueTotal, int, error) {
+	items := []report.AssetValueItem{}
+	totals := []report.AssetValueTotal{}
+	total := 0
+	value := func(amount *int64, currency string) *report.MoneyValue {
+		if amount == nil {
+			return nil
+		}
+		v := report.NewMoneyValue(shared.Money{Amount: *amount, Currency: currency}, filter.Locale)
+		return &v
+	}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT f.currency, COUNT(*),
+			       COALESCE(SUM(f.purchase_cost), 0)::BIGINT, COALESCE(SUM(f.book_value), 0)::BIGINT
+			FROM trakrf.asset_financials f
+			JOIN trakrf.assets a ON a.id = f.asset_id AND a.deleted_at IS NULL
+			WHERE f.org_id = $1 AND ($2::text = '' OR f.currency = $2)
+			GROUP BY f.currency
+			ORDER BY f.currency`, orgID, filter.Currency)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var t report.AssetValueTotal
+			var purchase, book int64
+			if err := rows.Scan(&t.Currency, &t.AssetCount, &purchase, &book); err != nil {
+				rows.Close()
+				return err
+			}
+			t.PurchaseCost = *value(&purchase, t.Currency)
+			t.BookValue = *value(&book, t.Currency)
+			totals = append(totals, t)
+			total += t.AssetCount
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		rows, err = tx.Query(ctx, `
+			SELECT a.id, a.external_key, a.name, f.currency, f.purchase_cost, f.book_value
+			FROM trakrf.asset_financials f
+			JOIN trakrf.assets a ON a.id = f.asset_id AND a.deleted_at IS NULL
+			WHERE f.org_id = $1 AND ($2::text = '' OR f.currency = $2)
+			ORDER BY f.book_value DESC NULLS LAST, a.id
+			LIMIT $3 OFFSET $4`, orgID, filter.Currency, filter.Limit, filter.Offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var item report.AssetValueItem
+			var currency string
+			var purchase, book *int64
+			if err := rows.Scan(&item.AssetID, &item.AssetExternalKey, &item.AssetName,
+				&currency, &purchase, &book); err != nil {
+				return err
+			}
+			item.PurchaseCost = value(purchase, currency)
+			item.BookValue = value(book, currency)
+			items = append(items, item)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to list asset values: %w", err)
+	}
+	return items, totals, total, nil
+}
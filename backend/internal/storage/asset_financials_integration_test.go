@@ -0,0 +1,104 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestCurrencySettings_Defaults(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	cs, err := store.GetCurrencySettings(ctx, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, organization.CurrencySettings{Currency: "USD", Locale: "en-US"}, *cs)
+
+	require.NoError(t, store.UpdateCurrencySettings(ctx, orgID, organization.CurrencySettings{Currency: "EUR", Locale: "de-DE"}))
+	cs, err = store.GetCurrencySettings(ctx, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, organization.CurrencySettings{Currency: "EUR", Locale: "de-DE"}, *cs)
+
+	missing, err := store.GetCurrencySettings(ctx, 999999999)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestAssetFinancials_SetAndReport(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	a, err := store.CreateAssetWithTags(ctx, keyedAsset(orgID, "FL-1", "Forklift"))
+	require.NoError(t, err)
+	b, err := store.CreateAssetWithTags(ctx, keyedAsset(orgID, "FL-2", "Pallet jack"))
+	require.NoError(t, err)
+	c, err := store.CreateAssetWithTags(ctx, keyedAsset(orgID, "FL-3", "Crane"))
+	require.NoError(t, err)
+
+	none, err := store.GetAssetFinancials(ctx, orgID, a.ID)
+	require.NoError(t, err)
+	assert.Nil(t, none)
+
+	cost, book := int64(1250000), int64(900050)
+	f, err := store.SetAssetFinancials(ctx, orgID, a.ID, "EUR", &cost, &book)
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", f.Currency)
+	require.NotNil(t, f.BookValue)
+	assert.Equal(t, "9000.50", f.BookValue.Decimal())
+	require.NotNil(t, f.UpdatedAt)
+
+	small := int64(35000)
+	_, err = store.SetAssetFinancials(ctx, orgID, b.ID, "EUR", &small, nil)
+	require.NoError(t, err)
+	yen := int64(5000000)
+	_, err = store.SetAssetFinancials(ctx, orgID, c.ID, "JPY", nil, &yen)
+	require.NoError(t, err)
+
+	items, totals, total, err := store.ListAssetValues(ctx, orgID, report.AssetValueFilter{Locale: "de-DE", Limit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	require.Len(t, items, 3)
+	assert.Equal(t, c.ID, items[0].AssetID, "highest book value first")
+	assert.Equal(t, "5.000.000\u00a0¥", items[0].BookValue.Formatted)
+	assert.Nil(t, items[0].PurchaseCost)
+	assert.Equal(t, b.ID, items[2].AssetID, "no book value sorts last")
+
+	require.Len(t, totals, 2)
+	assert.Equal(t, "EUR", totals[0].Currency)
+	assert.Equal(t, 2, totals[0].AssetCount)
+	assert.Equal(t, "12850.00", totals[0].PurchaseCost.Amount)
+	assert.Equal(t, "12.850,00\u00a0€", totals[0].PurchaseCost.Formatted)
+	assert.Equal(t, "JPY", totals[1].Currency)
+
+	// Clearing an amount and filtering by currency.
+	_, err = store.SetAssetFinancials(ctx, orgID, a.ID, "EUR", nil, &book)
+	require.NoError(t, err)
+	items, totals, total, err = store.ListAssetValues(ctx, orgID, report.AssetValueFilter{Currency: "EUR", Locale: "en-US", Limit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	require.Len(t, totals, 1)
+	assert.Equal(t, "€350.00", totals[0].PurchaseCost.Formatted)
+	assert.Nil(t, items[0].PurchaseCost)
+
+	// Deleted assets drop out of the report.
+	_, err = store.DeleteAsset(ctx, orgID, a.ID)
+	require.NoError(t, err)
+	_, _, total, err = store.ListAssetValues(ctx, orgID, report.AssetValueFilter{Locale: "en-US", Limit: 50})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+}
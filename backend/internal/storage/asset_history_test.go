@@ -50,7 +50,7 @@ func TestListAssetHistory_RunsInOrgContext(t *testing.T) {
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 781048918750452`).
 		WillReturnResult(pgxmock.NewResult("SET", 0))
 	mock.ExpectQuery(`LEAD\(s.timestamp\)`).
-		WithArgs(assetID, orgID, filter.From, filter.To, filter.Limit, filter.Offset).
+		WithArgs(assetID, orgID, filter.From, filter.To, filter.Limit, filter.Offset, nil).
 		WillReturnRows(rows)
 	mock.ExpectCommit()
 
@@ -78,7 +78,7 @@ func TestCountAssetHistory_RunsInOrgContext(t *testing.T) {
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 781048918750452`).
 		WillReturnResult(pgxmock.NewResult("SET", 0))
 	mock.ExpectQuery(`SELECT COUNT\(\*\)`).
-		WithArgs(assetID, orgID, filter.From, filter.To).
+		WithArgs(assetID, orgID, filter.From, filter.To, nil).
 		WillReturnRows(rows)
 	mock.ExpectCommit()
 
@@ -0,0 +1,318 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+)
+
+// Staging tables for CopyCreateAssetsWithTags. They are temporary and dropped
+// on commit, so concurrent imports never see each other's rows.
+const (
+	assetStageTable = "asset_import_stage"
+	tagStageTable   = "asset_tag_import_stage"
+)
+
+var (
+	assetStageColumns = []string{
+		"row_idx", "external_key", "name", "description", "valid_from", "valid_to",
+		"is_active", "metadata", "owner_user_id", "cost_center", "minted",
+	}
+	tagStageColumns = []string{"row_idx", "type", "value"}
+)
+
+// stagedAsset is one row of a COPY import with its defaults applied; an empty
+// externalKey is minted during staging.
+type stagedAsset struct {
+	externalKey string
+	name        string
+	description string
+	validFrom   time.Time
+	validTo     *time.Time
+	isActive    bool
+	metadata    any
+	ownerUserID *int
+	costCenter  *string
+	tags        []shared.TagRequest
+}
+
+// CopyCreateAssetsWithTags inserts requests, all for orgID, with their tags
+// in one transaction. Rows are streamed into temporary staging tables with
+// COPY and merged into assets and tags with one INSERT … SELECT each, so a
+// large import costs a handful of statements instead of one per row.
+//
+// It is all-or-nothing: an external_key repeated in the batch or already live
+// in the org fails the batch with a row-numbered error (row is the index into
+// requests), as does any other constraint, and nothing is written. Empty
+// external_keys are minted as ASSET-NNNN like CreateAssetWithTags. A sent
+// external_key of a soft-deleted asset is handled per the org's deleted-key
+// policy, as CreateAssetWithTags does. Returns the number of assets created
+// or restored, and of tags created.
+func (s *Storage) CopyCreateAssetsWithTags(ctx context.Context, orgID int, requests []asset.CreateAssetWithTagsRequest) (int, int, error) {
+	now := time.Now().UTC()
+	staged := make([]stagedAsset, len(requests))
+	for i, r := range requests {
+		if r.OrgID != orgID {
+			return 0, 0, fmt.Errorf("CopyCreateAssetsWithTags: heterogeneous OrgIDs in batch (expected %d, got %d)", orgID, r.OrgID)
+		}
+		// Same defaults CreateAssetWithTags applies for direct callers.
+		st := stagedAsset{
+			externalKey: r.ExternalKey,
+			name:        r.Name,
+			validFrom:   now,
+			isActive:    true,
+			ownerUserID: r.OwnerUserID,
+			costCenter:  r.CostCenter,
+			tags:        r.Tags,
+		}
+		if r.Description != nil {
+			st.description = *r.Description
+		}
+		if r.ValidFrom != nil && !r.ValidFrom.IsZero() {
+			st.validFrom = r.ValidFrom.ToTime()
+		}
+		if r.ValidTo != nil && !r.ValidTo.IsZero() {
+			t := r.ValidTo.ToTime()
+			st.validTo = &t
+		}
+		if r.IsActive != nil {
+			st.isActive = *r.IsActive
+		}
+		// A nil map in st.metadata would be stored as JSON null, not NULL.
+		if r.Metadata != nil {
+			st.metadata = r.Metadata
+		}
+		staged[i] = st
+	}
+	return s.copyCreateAssets(ctx, orgID, staged)
+}
+
+// copyCreateAssets is the COPY, check and merge behind CopyCreateAssetsWithTags
+// and BatchCreateAssets.
+func (s *Storage) copyCreateAssets(ctx context.Context, orgID int, staged []stagedAsset) (int, int, error) {
+	if len(staged) == 0 {
+		return 0, 0, nil
+	}
+
+	seq := 0
+	assetRows := make([][]any, len(staged))
+	var tagRows [][]any
+	for i, st := range staged {
+		minted := strings.TrimSpace(st.externalKey) == ""
+		if minted {
+			if seq == 0 {
+				var err error
+				if seq, err = s.GetNextAssetSequence(ctx, orgID); err != nil {
+					return 0, 0, fmt.Errorf("failed to generate external_key: %w", err)
+				}
+			}
+			st.externalKey = GenerateAssetExternalKey(seq)
+			seq++
+		}
+		var metadata []byte
+		if st.metadata != nil {
+			var err error
+			if metadata, err = json.Marshal(st.metadata); err != nil {
+				return 0, 0, fmt.Errorf("row %d: failed to serialize metadata: %w", i, err)
+			}
+		}
+		assetRows[i] = []any{
+			i, st.externalKey, st.name, st.description, st.validFrom, st.validTo,
+			st.isActive, metadata, st.ownerUserID, st.costCenter, minted,
+		}
+		for _, t := range st.tags {
+			tagRows = append(tagRows, []any{i, t.GetType(), t.Value})
+		}
+	}
+
+	var assetCount, tagCount int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if err := createImportStage(ctx, tx); err != nil {
+			return err
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{assetStageTable}, assetStageColumns, pgx.CopyFromRows(assetRows)); err != nil {
+			return fmt.Errorf("copy staged assets: %w", err)
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{tagStageTable}, tagStageColumns, pgx.CopyFromRows(tagRows)); err != nil {
+			return fmt.Errorf("copy staged tags: %w", err)
+		}
+		// Temp tables are never auto-analyzed; without statistics the
+		// conflict check below plans as if the stage were tiny.
+		if _, err := tx.Exec(ctx, "ANALYZE "+assetStageTable); err != nil {
+			return fmt.Errorf("analyze staged assets: %w", err)
+		}
+
+		// Report the first conflicting row by number, as BatchCreateAssets
+		// does, rather than surfacing the unique violation of the merge.
+		var row int
+		var externalKey string
+		err := tx.QueryRow(ctx, `
+			SELECT s.row_idx, s.external_key
+			FROM asset_import_stage s
+			WHERE EXISTS (
+				SELECT 1 FROM asset_import_stage d
+				WHERE d.external_key = s.external_key AND d.row_idx < s.row_idx)
+			   OR EXISTS (
+				SELECT 1 FROM trakrf.assets a
+				WHERE a.org_id = $1 AND a.external_key = s.external_key AND a.deleted_at IS NULL)
+			ORDER BY s.row_idx
+			LIMIT 1
+		`, orgID).Scan(&row, &externalKey)
+		if err == nil {
+			return fmt.Errorf("row %d: asset with external_key %s already exists", row, externalKey)
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("check staged external_keys: %w", err)
+		}
+
+		restored, err := restoreStagedAssets(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, `
+			WITH inserted AS (
+				INSERT INTO trakrf.assets
+				(org_id, external_key, name, description, valid_from, valid_to, is_active, metadata,
+				 owner_user_id, cost_center)
+				SELECT $1, external_key, name, description, valid_from, valid_to, is_active, metadata,
+				       owner_user_id, cost_center
+				FROM asset_import_stage
+				WHERE asset_id IS NULL
+				ORDER BY row_idx
+				RETURNING id, external_key
+			)
+			UPDATE asset_import_stage s SET asset_id = inserted.id
+			FROM inserted
+			WHERE inserted.external_key = s.external_key
+		`, orgID)
+		if err != nil {
+			return fmt.Errorf("merge staged assets: %w", err)
+		}
+		assetCount = restored + int(tag.RowsAffected())
+
+		tag, err = tx.Exec(ctx, `
+			INSERT INTO trakrf.tags (org_id, type, value, asset_id, is_active)
+			SELECT $1, t.type, t.value, s.asset_id, TRUE
+			FROM asset_tag_import_stage t
+			JOIN asset_import_stage s USING (row_idx)
+		`, orgID)
+		if err != nil {
+			return parseAssetWithTagsError(err, "")
+		}
+		tagCount = int(tag.RowsAffected())
+
+		var ids []int
+		if err := tx.QueryRow(ctx,
+			"SELECT array_agg(asset_id ORDER BY row_idx) FROM asset_import_stage",
+		).Scan(&ids); err != nil {
+			return fmt.Errorf("read merged asset ids: %w", err)
+		}
+		return s.publishAll(ctx, tx, events.AssetCreated, orgID, ids)
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return assetCount, tagCount, nil
+}
+
+// restoreStagedAssets applies orgID's deleted-key policy to the staged rows
+// whose external_key was sent rather than minted and belongs to a
+// soft-deleted asset: under block the first such row fails the batch, under
+// auto_restore each asset is restored with its row's fields and the row's
+// asset_id set, so the merge skips it. Live and in-batch duplicates must
+// already have been rejected. It returns the number of assets restored.
+func restoreStagedAssets(ctx context.Context, tx pgx.Tx, orgID int) (int, error) {
+	policy, err := deletedKeyPolicy(ctx, tx, orgID)
+	if err != nil || policy == organization.DeletedKeyAllowReuse {
+		return 0, err
+	}
+	if policy == organization.DeletedKeyBlock {
+		var row int
+		var externalKey string
+		err := tx.QueryRow(ctx, `
+			SELECT s.row_idx, s.external_key
+			FROM asset_import_stage s
+			WHERE NOT s.minted AND EXISTS (
+				SELECT 1 FROM trakrf.assets a
+				WHERE a.org_id = $1 AND a.external_key = s.external_key AND a.deleted_at IS NOT NULL)
+			ORDER BY s.row_idx
+			LIMIT 1
+		`, orgID).Scan(&row, &externalKey)
+		if err == nil {
+			return 0, fmt.Errorf("row %d: %w", row, deletedKeyError("asset", externalKey))
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return 0, fmt.Errorf("check deleted external_keys: %w", err)
+		}
+		return 0, nil
+	}
+	tag, err := tx.Exec(ctx, `
+		WITH restored AS (
+			UPDATE trakrf.assets a
+			SET name = s.name, description = s.description, valid_from = s.valid_from,
+			    valid_to = s.valid_to, is_active = s.is_active, metadata = s.metadata,
+			    owner_user_id = s.owner_user_id, cost_center = s.cost_center,
+			    deleted_at = NULL, updated_at = NOW()
+			FROM asset_import_stage s
+			WHERE NOT s.minted
+			  AND a.id = (
+				SELECT d.id FROM trakrf.assets d
+				WHERE d.org_id = $1 AND d.external_key = s.external_key AND d.deleted_at IS NOT NULL
+				ORDER BY d.deleted_at DESC, d.id DESC
+				LIMIT 1)
+			RETURNING a.id, a.external_key
+		)
+		UPDATE asset_import_stage s SET asset_id = restored.id
+		FROM restored
+		WHERE restored.external_key = s.external_key
+	`, orgID)
+	if err != nil {
+		return 0, fmt.Errorf("restore staged assets: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// createImportStage creates the staging tables on tx. Column types are loose
+// (text, not varchar(n)) so that COPY never fails on a long value; the merge
+// into the real tables enforces the limits.
+func createImportStage(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE asset_import_stage (
+			row_idx       INT PRIMARY KEY,
+			external_key  TEXT NOT NULL,
+			name          TEXT NOT NULL,
+			description   TEXT NOT NULL,
+			valid_from    TIMESTAMPTZ NOT NULL,
+			valid_to      TIMESTAMPTZ,
+			is_active     BOOLEAN NOT NULL,
+			metadata      JSONB,
+			owner_user_id BIGINT,
+			cost_center   TEXT,
+			minted        BOOLEAN NOT NULL,
+			asset_id      BIGINT
+		) ON COMMIT DROP
+	`); err != nil {
+		return fmt.Errorf("create asset stage: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		CREATE TEMP TABLE asset_tag_import_stage (
+			row_idx INT NOT NULL,
+			type    TEXT NOT NULL,
+			value   TEXT NOT NULL
+		) ON COMMIT DROP
+	`); err != nil {
+		return fmt.Errorf("create tag stage: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,443 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/assettransfer"
+)
+
+var (
+	// ErrAssetTransferAssetNotFound is returned when the asset to transfer is
+	// not a live asset of the sending org.
+	ErrAssetTransferAssetNotFound = errors.New("asset not found")
+	// ErrAssetTransferOpen is returned when the asset already has a pending
+	// or approved transfer.
+	ErrAssetTransferOpen = errors.New("the asset already has an open transfer")
+	// ErrAssetTransferWrongSide is returned when an org takes an action that
+	// belongs to the other side of the transfer (e.g. the sender approving).
+	ErrAssetTransferWrongSide = errors.New("this action belongs to the other org of the transfer")
+	// ErrAssetTransferState is returned when the transfer's status does not
+	// allow the action.
+	ErrAssetTransferState = errors.New("the transfer's status does not allow this action")
+	// ErrAssetTransferConflict is returned when executing would clash with the
+	// receiving org's data: a live asset with the same external key, or a tag
+	// value it already uses.
+	ErrAssetTransferConflict = errors.New("the receiving org already has an asset with this external key or one of its tags")
+	// ErrAssetTransferSandbox is returned when a transfer would cross between
+	// a sandbox org and a production org.
+	ErrAssetTransferSandbox = errors.New("assets cannot be transferred between sandbox and production orgs")
+)
+
+// scanHistoryBatch is how many asset_scans rows one insert copies on execute.
+const scanHistoryBatch = 5000
+
+const assetTransferSelect = `
+	SELECT t.id, t.source_org_id, so.name, COALESCE(so.identifier, ''),
+	       t.target_org_id, tgo.name, COALESCE(tgo.identifier, ''),
+	       t.asset_id, t.asset_external_key, t.asset_name, t.include_history, t.note, t.status,
+	       t.requested_by, t.decided_by, t.decided_at, t.executed_by, t.executed_at,
+	       t.target_asset_id, t.created_at, t.updated_at
+	FROM trakrf.asset_org_transfers t
+	JOIN trakrf.organizations so ON so.id = t.source_org_id
+	JOIN trakrf.organizations tgo ON tgo.id = t.target_org_id`
+
+func scanAssetTransfer(row pgx.Row) (*assettransfer.Transfer, error) {
+	var t assettransfer.Transfer
+	if err := row.Scan(&t.ID, &t.SourceOrg.ID, &t.SourceOrg.Name, &t.SourceOrg.Identifier,
+		&t.TargetOrg.ID, &t.TargetOrg.Name, &t.TargetOrg.Identifier,
+		&t.AssetID, &t.AssetExternalKey, &t.AssetName, &t.IncludeHistory, &t.Note, &t.Status,
+		&t.RequestedBy, &t.DecidedBy, &t.DecidedAt, &t.ExecutedBy, &t.ExecutedAt,
+		&t.TargetAssetID, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// nextTransferStatus returns the status action moves t to when orgID takes it.
+// The receiving org approves or rejects a pending transfer; the sending org
+// cancels an open one and executes an approved one.
+func nextTransferStatus(t *assettransfer.Transfer, orgID int, action string) (string, error) {
+	var side, next string
+	var from []string
+	switch action {
+	case assettransfer.ActionApprove:
+		side, next, from = "target", assettransfer.StatusApproved, []string{assettransfer.StatusPending}
+	case assettransfer.ActionReject:
+		side, next, from = "target", assettransfer.StatusRejected, []string{assettransfer.StatusPending}
+	case assettransfer.ActionCancel:
+		side, next, from = "source", assettransfer.StatusCancelled,
+			[]string{assettransfer.StatusPending, assettransfer.StatusApproved}
+	case assettransfer.ActionExecute:
+		side, next, from = "source", assettransfer.StatusCompleted, []string{assettransfer.StatusApproved}
+	default:
+		return "", fmt.Errorf("unknown asset transfer action %q", action)
+	}
+	if side == "target" && orgID != t.TargetOrg.ID || side == "source" && orgID != t.SourceOrg.ID {
+		return "", ErrAssetTransferWrongSide
+	}
+	for _, s := range from {
+		if t.Status == s {
+			return next, nil
+		}
+	}
+	return "", ErrAssetTransferState
+}
+
+// CreateAssetTransfer opens a pending transfer of one of orgID's live assets
+// to targetOrgID. Returns ErrAssetTransferAssetNotFound,
+// ErrAssetTransferOpen or ErrAssetTransferSandbox.
+func (s *Storage) CreateAssetTransfer(ctx context.Context, orgID, userID, targetOrgID int, req assettransfer.CreateRequest) (*assettransfer.Transfer, error) {
+	var id int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		// Sandboxes are write-isolated: nothing moves in or out of one. The
+		// flag never changes, so checking here covers execute too.
+		var crosses bool
+		if err := tx.QueryRow(ctx, `
+			SELECT bool_or(is_sandbox) IS DISTINCT FROM bool_and(is_sandbox)
+			FROM trakrf.organizations WHERE id IN ($1, $2)`,
+			orgID, targetOrgID).Scan(&crosses); err != nil {
+			return err
+		}
+		if crosses {
+			return ErrAssetTransferSandbox
+		}
+
+		var key, name string
+		err := tx.QueryRow(ctx, `
+			SELECT external_key, name FROM trakrf.assets
+			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+			FOR UPDATE`, req.AssetID, orgID).Scan(&key, &name)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrAssetTransferAssetNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, `
+			INSERT INTO trakrf.asset_org_transfers
+				(source_org_id, target_org_id, asset_id, asset_external_key, asset_name,
+				 include_history, note, requested_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`,
+			orgID, targetOrgID, req.AssetID, key, name, req.IncludeHistory, req.Note, userID).Scan(&id)
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == "idx_asset_org_transfers_open" {
+			return nil, ErrAssetTransferOpen
+		}
+		if errors.Is(err, ErrAssetTransferAssetNotFound) || errors.Is(err, ErrAssetTransferSandbox) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create asset transfer: %w", err)
+	}
+	return s.GetAssetTransfer(ctx, orgID, id)
+}
+
+// ListAssetTransfers returns the transfers orgID sends or receives, newest
+// first.
+func (s *Storage) ListAssetTransfers(ctx context.Context, orgID int, f assettransfer.ListFilter) ([]assettransfer.Transfer, error) {
+	var status any
+	if f.Status != "" {
+		status = f.Status
+	}
+	rows, err := s.pool.Query(ctx, assetTransferSelect+`
+		WHERE CASE $2::text
+			WHEN 'incoming' THEN t.target_org_id = $1
+			WHEN 'outgoing' THEN t.source_org_id = $1
+			ELSE t.source_org_id = $1 OR t.target_org_id = $1
+			END
+		  AND ($3::text IS NULL OR t.status = $3)
+		ORDER BY t.created_at DESC, t.id
+		LIMIT $4`, orgID, f.Direction, status, f.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list asset transfers: %w", err)
+	}
+	defer rows.Close()
+
+	out := []assettransfer.Transfer{}
+	for rows.Next() {
+		t, err := scanAssetTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset transfer: %w", err)
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+// GetAssetTransfer returns a transfer orgID sends or receives, or nil.
+func (s *Storage) GetAssetTransfer(ctx context.Context, orgID, id int) (*assettransfer.Transfer, error) {
+	t, err := scanAssetTransfer(s.pool.QueryRow(ctx, assetTransferSelect+`
+		WHERE t.id = $1 AND (t.source_org_id = $2 OR t.target_org_id = $2)`, id, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset transfer: %w", err)
+	}
+	return t, nil
+}
+
+// lockAssetTransfer reads a transfer orgID sends or receives FOR UPDATE on tx,
+// or nil.
+func lockAssetTransfer(ctx context.Context, tx pgx.Tx, orgID, id int) (*assettransfer.Transfer, error) {
+	t, err := scanAssetTransfer(tx.QueryRow(ctx, assetTransferSelect+`
+		WHERE t.id = $1 AND (t.source_org_id = $2 OR t.target_org_id = $2)
+		FOR UPDATE OF t`, id, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock asset transfer: %w", err)
+	}
+	return t, nil
+}
+
+// DecideAssetTransfer approves, rejects or cancels a transfer on behalf of
+// orgID. Returns (nil, nil) when orgID is on neither side, and
+// ErrAssetTransferWrongSide or ErrAssetTransferState when the action is not
+// orgID's to take now.
+func (s *Storage) DecideAssetTransfer(ctx context.Context, orgID, id, userID int, action string) (*assettransfer.Transfer, error) {
+	if action == assettransfer.ActionExecute {
+		return nil, fmt.Errorf("use ExecuteAssetTransfer to execute a transfer")
+	}
+	found := false
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		t, err := lockAssetTransfer(ctx, tx, orgID, id)
+		if err != nil || t == nil {
+			return err
+		}
+		found = true
+		next, err := nextTransferStatus(t, orgID, action)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.asset_org_transfers
+			SET status = $2, decided_by = $3, decided_at = NOW()
+			WHERE id = $1`, id, next, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrAssetTransferWrongSide) || errors.Is(err, ErrAssetTransferState) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to %s asset transfer: %w", action, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.GetAssetTransfer(ctx, orgID, id)
+}
+
+// setTxOrg switches the RLS org context of tx. Only the transfer executor
+// does this: it is the one write that must touch two orgs atomically, so it
+// runs on one WithTx transaction and scopes each phase to one org in turn.
+func setTxOrg(ctx context.Context, tx pgx.Tx, orgID int) error {
+	if _, err := tx.Exec(ctx, "SELECT set_config('app.current_org_id', $1, true)", strconv.Itoa(orgID)); err != nil {
+		return fmt.Errorf("failed to set org context: %w", err)
+	}
+	return nil
+}
+
+// transferTag is a live asset tag carried across on execute.
+type transferTag struct {
+	Type      string
+	Value     string
+	ValidFrom time.Time
+	ValidTo   *time.Time
+	IsActive  bool
+	Metadata  map[string]any
+}
+
+// ExecuteAssetTransfer moves an approved transfer's asset from orgID (the
+// sending org) to the receiving org in one transaction: the asset and its
+// tags are soft-deleted in the sender and recreated in the receiver with the
+// same external key, fields and tag values. Owner, cost center and team are
+// org-specific and do not carry over. With include_history, the asset's scan
+// history is copied into the receiving org without its locations and scan
+// points, which name the sender's sites; the sender keeps its own copy until
+// retention prunes it.
+//
+// Returns (nil, nil) when orgID is on neither side, ErrAssetTransferWrongSide
+// or ErrAssetTransferState when the transfer cannot be executed by orgID now,
+// and ErrAssetTransferConflict when the receiver already uses the external key
+// or a tag value; the transfer then stays approved so it can be retried once
+// the clash is resolved.
+func (s *Storage) ExecuteAssetTransfer(ctx context.Context, orgID, id, userID int) (*assettransfer.Transfer, error) {
+	found := false
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		t, err := lockAssetTransfer(ctx, tx, orgID, id)
+		if err != nil || t == nil {
+			return err
+		}
+		found = true
+		if _, err := nextTransferStatus(t, orgID, assettransfer.ActionExecute); err != nil {
+			return err
+		}
+
+		newID, err := s.moveTransferredAsset(ctx, tx, t)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return ErrAssetTransferConflict
+			}
+			if errors.Is(err, ErrAssetTransferAssetNotFound) {
+				return err
+			}
+			return fmt.Errorf("failed to execute asset transfer: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.asset_org_transfers
+			SET status = $2, executed_by = $3, executed_at = NOW(), target_asset_id = $4
+			WHERE id = $1`, id, assettransfer.StatusCompleted, userID, newID); err != nil {
+			return fmt.Errorf("failed to complete asset transfer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.GetAssetTransfer(ctx, orgID, id)
+}
+
+// moveTransferredAsset does the two-org part of an execute on tx and returns
+// the asset's id in the receiving org.
+func (s *Storage) moveTransferredAsset(ctx context.Context, tx pgx.Tx, t *assettransfer.Transfer) (int, error) {
+	src, dst := t.SourceOrg.ID, t.TargetOrg.ID
+
+	// Sending side: read what moves, then retire it.
+	if err := setTxOrg(ctx, tx, src); err != nil {
+		return 0, err
+	}
+	var (
+		key, name, description string
+		validFrom              time.Time
+		validTo                *time.Time
+		isActive               bool
+		metadata               map[string]any
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT external_key, name, COALESCE(description, ''), valid_from, valid_to, is_active, metadata
+		FROM trakrf.assets
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+		FOR UPDATE`, t.AssetID, src).Scan(&key, &name, &description, &validFrom, &validTo, &isActive, &metadata)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrAssetTransferAssetNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("read asset: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT type, value, valid_from, valid_to, is_active, metadata
+		FROM trakrf.tags
+		WHERE asset_id = $1 AND org_id = $2 AND deleted_at IS NULL
+		ORDER BY id`, t.AssetID, src)
+	if err != nil {
+		return 0, fmt.Errorf("read asset tags: %w", err)
+	}
+	tags, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (transferTag, error) {
+		var tg transferTag
+		err := row.Scan(&tg.Type, &tg.Value, &tg.ValidFrom, &tg.ValidTo, &tg.IsActive, &tg.Metadata)
+		return tg, err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("read asset tags: %w", err)
+	}
+
+	var (
+		scanTimes   []time.Time
+		scanTagIDs  []*int64
+		scanCreated []time.Time
+	)
+	if t.IncludeHistory {
+		rows, err := tx.Query(ctx, `
+			SELECT timestamp, tag_scan_id, created_at FROM trakrf.asset_scans
+			WHERE org_id = $1 AND asset_id = $2
+			ORDER BY timestamp`, src, t.AssetID)
+		if err != nil {
+			return 0, fmt.Errorf("read asset scans: %w", err)
+		}
+		for rows.Next() {
+			var at, created time.Time
+			var tagScanID *int64
+			if err := rows.Scan(&at, &tagScanID, &created); err != nil {
+				rows.Close()
+				return 0, fmt.Errorf("read asset scans: %w", err)
+			}
+			scanTimes = append(scanTimes, at)
+			scanTagIDs = append(scanTagIDs, tagScanID)
+			scanCreated = append(scanCreated, created)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, fmt.Errorf("read asset scans: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE trakrf.assets SET deleted_at = NOW()
+		WHERE id = $1 AND org_id = $2`, t.AssetID, src); err != nil {
+		return 0, fmt.Errorf("retire asset: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE trakrf.tags SET deleted_at = NOW()
+		WHERE asset_id = $1 AND org_id = $2 AND deleted_at IS NULL`, t.AssetID, src); err != nil {
+		return 0, fmt.Errorf("retire asset tags: %w", err)
+	}
+	if err := s.publish(ctx, tx, events.AssetDeleted, src, t.AssetID); err != nil {
+		return 0, err
+	}
+
+	// Receiving side: recreate it.
+	if err := setTxOrg(ctx, tx, dst); err != nil {
+		return 0, err
+	}
+	var newID int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO trakrf.assets
+			(org_id, external_key, name, description, valid_from, valid_to, is_active, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		dst, key, name, description, validFrom, validTo, isActive, metadata).Scan(&newID); err != nil {
+		return 0, fmt.Errorf("create asset: %w", err)
+	}
+	batch := &pgx.Batch{}
+	for _, tg := range tags {
+		batch.Queue(`
+			INSERT INTO trakrf.tags (org_id, type, value, asset_id, valid_from, valid_to, is_active, metadata)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+			dst, tg.Type, tg.Value, newID, tg.ValidFrom, tg.ValidTo, tg.IsActive, tg.Metadata)
+	}
+	if _, err := execBatch(ctx, tx, batch); err != nil {
+		return 0, fmt.Errorf("create asset tag: %w", err)
+	}
+	for start := 0; start < len(scanTimes); start += scanHistoryBatch {
+		end := min(start+scanHistoryBatch, len(scanTimes))
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO trakrf.asset_scans (timestamp, org_id, asset_id, tag_scan_id, created_at)
+			SELECT ts, $1, $2, tag_scan_id, created_at
+			FROM unnest($3::timestamptz[], $4::bigint[], $5::timestamptz[]) AS s(ts, tag_scan_id, created_at)`,
+			dst, newID, scanTimes[start:end], scanTagIDs[start:end], scanCreated[start:end]); err != nil {
+			return 0, fmt.Errorf("copy asset scans: %w", err)
+		}
+	}
+	if err := s.publish(ctx, tx, events.AssetCreated, dst, newID); err != nil {
+		return 0, err
+	}
+	return newID, nil
+}
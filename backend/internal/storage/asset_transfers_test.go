@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/assettransfer"
+)
+
+func TestNextTransferStatus(t *testing.T) {
+	const src, dst, other = 10, 20, 30
+	transfer := func(status string) *assettransfer.Transfer {
+		return &assettransfer.Transfer{
+			SourceOrg: assettransfer.OrgRef{ID: src},
+			TargetOrg: assettransfer.OrgRef{ID: dst},
+			Status:    status,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		status  string
+		org     int
+		action  string
+		want    string
+		wantErr error
+	}{
+		{"receiver approves pending", assettransfer.StatusPending, dst, assettransfer.ActionApprove, assettransfer.StatusApproved, nil},
+		{"receiver rejects pending", assettransfer.StatusPending, dst, assettransfer.ActionReject, assettransfer.StatusRejected, nil},
+		{"sender cancels pending", assettransfer.StatusPending, src, assettransfer.ActionCancel, assettransfer.StatusCancelled, nil},
+		{"sender cancels approved", assettransfer.StatusApproved, src, assettransfer.ActionCancel, assettransfer.StatusCancelled, nil},
+		{"sender executes approved", assettransfer.StatusApproved, src, assettransfer.ActionExecute, assettransfer.StatusCompleted, nil},
+		{"sender cannot approve", assettransfer.StatusPending, src, assettransfer.ActionApprove, "", ErrAssetTransferWrongSide},
+		{"receiver cannot execute", assettransfer.StatusApproved, dst, assettransfer.ActionExecute, "", ErrAssetTransferWrongSide},
+		{"bystander cannot cancel", assettransfer.StatusPending, other, assettransfer.ActionCancel, "", ErrAssetTransferWrongSide},
+		{"pending cannot execute", assettransfer.StatusPending, src, assettransfer.ActionExecute, "", ErrAssetTransferState},
+		{"approved cannot be rejected", assettransfer.StatusApproved, dst, assettransfer.ActionReject, "", ErrAssetTransferState},
+		{"completed cannot be cancelled", assettransfer.StatusCompleted, src, assettransfer.ActionCancel, "", ErrAssetTransferState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextTransferStatus(transfer(tt.status), tt.org, tt.action)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := nextTransferStatus(transfer(assettransfer.StatusPending), src, "teleport")
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// AssetBatchRefs is what an org already holds that a batch of new assets is
+// checked against by POST /api/v1/assets/validate.
+type AssetBatchRefs struct {
+	// ExternalKeys are the batch's external_keys already held by a live asset.
+	ExternalKeys map[string]bool
+	// BlockedExternalKeys are the batch's external_keys held only by a
+	// soft-deleted asset, when the org's deleted-key policy is block.
+	BlockedExternalKeys map[string]bool
+	// TagTypes maps each of the batch's tag values that is live in the org
+	// to the tag types it is live under.
+	TagTypes map[string][]string
+	// Members are the batch's owner_user_ids that are members of the org.
+	Members map[int]bool
+	// UniqueTagsAcrossTypes is the org's tag setting: a value live under one
+	// type cannot be attached under another.
+	UniqueTagsAcrossTypes bool
+}
+
+// LookupAssetBatchRefs returns which of keys, tagValues and userIDs already
+// exist in orgID, in one read so a large batch costs a fixed number of
+// queries.
+func (s *Storage) LookupAssetBatchRefs(ctx context.Context, orgID int, keys, tagValues []string, userIDs []int) (*AssetBatchRefs, error) {
+	refs := &AssetBatchRefs{
+		ExternalKeys:        map[string]bool{},
+		BlockedExternalKeys: map[string]bool{},
+		TagTypes:            map[string][]string{},
+		Members:             map[int]bool{},
+	}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT external_key FROM trakrf.assets
+			WHERE org_id = $1 AND external_key = ANY($2) AND deleted_at IS NULL`, orgID, keys)
+		if err != nil {
+			return err
+		}
+		keysHeld, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		for _, k := range keysHeld {
+			refs.ExternalKeys[k] = true
+		}
+
+		policy, err := deletedKeyPolicy(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+		if policy == organization.DeletedKeyBlock {
+			rows, err = tx.Query(ctx, `
+				SELECT DISTINCT external_key FROM trakrf.assets
+				WHERE org_id = $1 AND external_key = ANY($2) AND deleted_at IS NOT NULL`, orgID, keys)
+			if err != nil {
+				return err
+			}
+			keysDeleted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			if err != nil {
+				return err
+			}
+			for _, k := range keysDeleted {
+				if !refs.ExternalKeys[k] {
+					refs.BlockedExternalKeys[k] = true
+				}
+			}
+		}
+
+		rows, err = tx.Query(ctx, `
+			SELECT value, type FROM trakrf.tags
+			WHERE org_id = $1 AND value = ANY($2) AND deleted_at IS NULL
+			ORDER BY value, type`, orgID, tagValues)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var value, typ string
+			if err := rows.Scan(&value, &typ); err != nil {
+				rows.Close()
+				return err
+			}
+			refs.TagTypes[value] = append(refs.TagTypes[value], typ)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		rows, err = tx.Query(ctx, `
+			SELECT user_id FROM trakrf.org_users
+			WHERE org_id = $1 AND user_id = ANY($2) AND deleted_at IS NULL`, orgID, userIDs)
+		if err != nil {
+			return err
+		}
+		members, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		for _, id := range members {
+			refs.Members[id] = true
+		}
+
+		return tx.QueryRow(ctx, `
+			SELECT COALESCE((metadata->'tags'->>'unique_across_types')::boolean, false)
+			FROM trakrf.organizations WHERE id = $1`, orgID).Scan(&refs.UniqueTagsAcrossTypes)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up asset batch references: %w", err)
+	}
+	return refs, nil
+}
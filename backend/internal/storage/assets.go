@@ -5,17 +5,25 @@ import (
 	"database/sql"
 	stderrors "errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/trakrf/platform/backend/internal/events"
 	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/location"
 	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/models/team"
+	"github.com/trakrf/platform/backend/internal/util/filterexpr"
 )
 
+// CreateAsset inserts request, or restores the soft-deleted asset with its
+// external_key in its place when the org's deleted-key policy says so.
 func (s *Storage) CreateAsset(ctx context.Context, request asset.Asset) (*asset.Asset, error) {
 	// Auto-generate external_key if empty
-	if strings.TrimSpace(request.ExternalKey) == "" {
+	minted := strings.TrimSpace(request.ExternalKey) == ""
+	if minted {
 		seq, err := s.GetNextAssetSequence(ctx, request.OrgID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate external_key: %w", err)
@@ -28,19 +36,43 @@ func (s *Storage) CreateAsset(ctx context.Context, request asset.Asset) (*asset.
 	// non-pointer asset.Asset.Description (`string`) and surfaces a 500.
 	query := `
 	insert into trakrf.assets
-	(name, external_key, description, valid_from, valid_to, metadata, is_active, org_id)
-	values ($1, $2, $3, $4, $5, $6, $7, $8)
+	(name, external_key, description, valid_from, valid_to, metadata, is_active, org_id,
+	 owner_user_id, cost_center)
+	values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	returning id, org_id, external_key, name, COALESCE(description, ''), valid_from, valid_to,
-	          metadata, is_active, created_at, updated_at, deleted_at
+	          metadata, is_active, created_at, updated_at, deleted_at,
+	          owner_user_id, cost_center
+	`
+	restore := `
+	update trakrf.assets
+	set name = $1, external_key = $2, description = $3, valid_from = $4, valid_to = $5,
+	    metadata = $6, is_active = $7, owner_user_id = $9, cost_center = $10,
+	    deleted_at = null, updated_at = now()
+	where id = $11 and org_id = $8
+	returning id, org_id, external_key, name, COALESCE(description, ''), valid_from, valid_to,
+	          metadata, is_active, created_at, updated_at, deleted_at,
+	          owner_user_id, cost_center
 	`
 	var asset asset.Asset
 	err := s.WithOrgTx(ctx, request.OrgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query, request.Name, request.ExternalKey,
+		args := []any{request.Name, request.ExternalKey,
 			request.Description, request.ValidFrom, request.ValidTo, request.Metadata,
-			request.IsActive, request.OrgID,
-		).Scan(&asset.ID, &asset.OrgID, &asset.ExternalKey, &asset.Name,
+			request.IsActive, request.OrgID, request.OwnerUserID, request.CostCenter,
+		}
+		if !minted {
+			deletedID, err := claimDeletedKey(ctx, tx, "asset", request.OrgID, request.ExternalKey)
+			if err != nil {
+				return err
+			}
+			if deletedID != 0 {
+				query = restore
+				args = append(args, deletedID)
+			}
+		}
+		return tx.QueryRow(ctx, query, args...).Scan(&asset.ID, &asset.OrgID, &asset.ExternalKey, &asset.Name,
 			&asset.Description, &asset.ValidFrom, &asset.ValidTo, &asset.Metadata,
 			&asset.IsActive, &asset.CreatedAt, &asset.UpdatedAt, &asset.DeletedAt,
+			&asset.OwnerUserID, &asset.CostCenter,
 		)
 	})
 
@@ -48,6 +80,9 @@ func (s *Storage) CreateAsset(ctx context.Context, request asset.Asset) (*asset.
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
 			return nil, fmt.Errorf("asset with external_key %s already exists", request.ExternalKey)
 		}
+		if stderrors.Is(err, ErrDeletedKeyBlocked) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("failed to create asset: %w", err)
 	}
 
@@ -94,7 +129,7 @@ func (s *Storage) UpdateAsset(ctx context.Context, orgID, id int, request asset.
 		return nil, err
 	}
 
-	// Nil entries (only from ClearValidTo) pass through as SQL NULL.
+	// Nil entries (from ClearValidTo / ClearCostCenter) pass through as SQL NULL.
 	for key, value := range fields {
 		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", key, argPos))
 		args = append(args, value)
@@ -125,7 +160,10 @@ func (s *Storage) UpdateAsset(ctx context.Context, orgID, id int, request asset.
 
 	var updatedID int
 	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query, args...).Scan(&updatedID)
+		if err := tx.QueryRow(ctx, query, args...).Scan(&updatedID); err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.AssetUpdated, orgID, updatedID)
 	})
 
 	if err != nil {
@@ -170,12 +208,16 @@ func (s *Storage) RenameAsset(ctx context.Context, orgID, id int, newExternalKey
 			return nil
 		}
 
-		return tx.QueryRow(ctx, `
+		err = tx.QueryRow(ctx, `
 			UPDATE trakrf.assets
 			SET external_key = $3, updated_at = NOW()
 			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 			RETURNING id
 		`, id, orgID, newExternalKey).Scan(&updatedID)
+		if err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.AssetUpdated, orgID, updatedID)
 	})
 
 	if err != nil {
@@ -191,11 +233,58 @@ func (s *Storage) RenameAsset(ctx context.Context, orgID, id int, newExternalKey
 	return s.getAssetViewWithTagsByID(ctx, orgID, updatedID)
 }
 
+// TransferAssetOwnership sets the asset's owner_user_id. The caller has
+// already checked that newOwnerID is a member of orgID. Returns the previous
+// owner (nil when unowned) alongside the updated view, or (nil, nil, nil) when
+// the asset does not exist. Transferring to the current owner is a no-op that
+// leaves updated_at alone, matching RenameAsset; any other transfer records a
+// check-out in asset_assignments carrying rec.
+func (s *Storage) TransferAssetOwnership(ctx context.Context, orgID, id, newOwnerID int, rec asset.AssignmentRecord) (*asset.AssetView, *int, error) {
+	var previous *int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT owner_user_id FROM trakrf.assets
+			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+			FOR UPDATE
+		`, id, orgID).Scan(&previous)
+		if err != nil {
+			return err
+		}
+		if previous != nil && *previous == newOwnerID {
+			return nil
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.assets
+			SET owner_user_id = $3, updated_at = NOW()
+			WHERE id = $1 AND org_id = $2
+		`, id, orgID, newOwnerID); err != nil {
+			return err
+		}
+		if err := insertAssetAssignment(ctx, tx, orgID, id, asset.AssignmentCheckOut, &newOwnerID, rec); err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.AssetUpdated, orgID, id)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to transfer asset: %w", err)
+	}
+
+	view, err := s.getAssetViewWithTagsByID(ctx, orgID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return view, previous, nil
+}
+
 func (s *Storage) GetAssetByID(ctx context.Context, orgID int, id *int) (*asset.Asset, error) {
 	// TRA-674: COALESCE(description, '') — see CreateAsset comment.
 	query := `
 	select id, org_id, external_key, name, COALESCE(description, ''), valid_from, valid_to,
-	       metadata, is_active, created_at, updated_at, deleted_at
+	       metadata, is_active, created_at, updated_at, deleted_at,
+	       owner_user_id, cost_center
 	from trakrf.assets
 	where id = $1 and org_id = $2 and deleted_at is null
 	`
@@ -205,6 +294,7 @@ func (s *Storage) GetAssetByID(ctx context.Context, orgID int, id *int) (*asset.
 			&asset.ExternalKey, &asset.Name, &asset.Description,
 			&asset.ValidFrom, &asset.ValidTo, &asset.Metadata, &asset.IsActive,
 			&asset.CreatedAt, &asset.UpdatedAt, &asset.DeletedAt,
+			&asset.OwnerUserID, &asset.CostCenter,
 		)
 	})
 	if err != nil {
@@ -228,7 +318,8 @@ func (s *Storage) GetAssetsByIDs(ctx context.Context, orgID int, ids []int) ([]*
 	// TRA-674: COALESCE(description, '') — see CreateAsset comment.
 	query := `
 	SELECT id, org_id, external_key, name, COALESCE(description, ''), valid_from, valid_to,
-	       metadata, is_active, created_at, updated_at, deleted_at
+	       metadata, is_active, created_at, updated_at, deleted_at,
+	       owner_user_id, cost_center
 	FROM trakrf.assets
 	WHERE org_id = $1 AND id = ANY($2) AND deleted_at IS NULL
 	`
@@ -246,6 +337,7 @@ func (s *Storage) GetAssetsByIDs(ctx context.Context, orgID int, ids []int) ([]*
 			if err := rows.Scan(&a.ID, &a.OrgID, &a.ExternalKey, &a.Name,
 				&a.Description, &a.ValidFrom, &a.ValidTo, &a.Metadata, &a.IsActive,
 				&a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+				&a.OwnerUserID, &a.CostCenter,
 			); err != nil {
 				return fmt.Errorf("failed to scan asset: %w", err)
 			}
@@ -264,7 +356,8 @@ func (s *Storage) ListAllAssets(ctx context.Context, orgID int, limit int, offse
 	// TRA-674: COALESCE(description, '') — see CreateAsset comment.
 	query := `
 		select id, org_id, external_key, name, COALESCE(description, ''), valid_from, valid_to,
-		       metadata, is_active, created_at, updated_at, deleted_at
+		       metadata, is_active, created_at, updated_at, deleted_at,
+		       owner_user_id, cost_center
 		from trakrf.assets
 		where org_id = $1 and deleted_at is null
 		order by created_at desc
@@ -283,6 +376,7 @@ func (s *Storage) ListAllAssets(ctx context.Context, orgID int, limit int, offse
 			if err := rows.Scan(&a.ID, &a.OrgID, &a.ExternalKey, &a.Name,
 				&a.Description, &a.ValidFrom, &a.ValidTo, &a.Metadata, &a.IsActive,
 				&a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+				&a.OwnerUserID, &a.CostCenter,
 			); err != nil {
 				return fmt.Errorf("failed to scan asset: %w", err)
 			}
@@ -340,7 +434,10 @@ func (s *Storage) DeleteAsset(ctx context.Context, orgID, id int) (bool, error)
 			   SET deleted_at = (SELECT deleted_at FROM trakrf.assets WHERE id = $1 AND org_id = $2)
 			 WHERE asset_id = $1 AND org_id = $2 AND deleted_at IS NULL
 		`, id, orgID)
-		return err
+		if err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.AssetDeleted, orgID, id)
 	})
 	if err != nil {
 		return false, fmt.Errorf("could not delete asset: %w", err)
@@ -348,10 +445,73 @@ func (s *Storage) DeleteAsset(ctx context.Context, orgID, id int) (bool, error)
 	return rowsAffected > 0, nil
 }
 
+// DeleteAssets soft-deletes several assets in one transaction, cascading to
+// their tags as DeleteAsset does. Assets that are missing, already deleted,
+// or hidden by either scope are skipped; the ids actually deleted are
+// returned.
+func (s *Storage) DeleteAssets(ctx context.Context, orgID int, ids []int, scope team.Scope, subtree location.SubtreeScope) ([]int, error) {
+	var teamIDs []int
+	if scope.Restricted {
+		teamIDs = scope.TeamIDs
+		if teamIDs == nil {
+			teamIDs = []int{}
+		}
+	}
+	deleted := []int{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			UPDATE trakrf.assets
+			   SET deleted_at = NOW()
+			 WHERE org_id = $1 AND id = ANY($2) AND deleted_at IS NULL
+			   AND ($3::bigint[] IS NULL OR team_id = ANY($3))
+			   AND ($4::bigint[] IS NULL OR `+assetSubtreeClause("id", 4)+`)
+			RETURNING id
+		`, orgID, ids, teamIDs, scopeRoots(subtree))
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			deleted = append(deleted, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		if len(deleted) == 0 {
+			return nil
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.tags t
+			   SET deleted_at = a.deleted_at
+			  FROM trakrf.assets a
+			 WHERE a.id = t.asset_id AND t.org_id = $1 AND t.asset_id = ANY($2) AND t.deleted_at IS NULL
+		`, orgID, deleted)
+		if err != nil {
+			return err
+		}
+		for _, id := range deleted {
+			if err := s.publish(ctx, tx, events.AssetDeleted, orgID, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not delete assets: %w", err)
+	}
+	return deleted, nil
+}
+
 // BatchCreateAssets atomically inserts multiple assets in a single transaction.
 // This is an all-or-nothing operation: if ANY asset fails to insert,
 // the entire transaction is rolled back and ZERO assets are saved.
 // Returns the number of successful inserts and a slice of errors (with row numbers).
+// Rows go through the COPY staging path (see CopyCreateAssetsWithTags).
 func (s *Storage) BatchCreateAssets(ctx context.Context, assets []asset.Asset) (int, []error) {
 	if len(assets) == 0 {
 		return 0, nil
@@ -361,56 +521,35 @@ func (s *Storage) BatchCreateAssets(ctx context.Context, assets []asset.Asset) (
 	// implementation assumed this silently; make it enforceable so that a
 	// WithOrgTx-wrapped batch cannot accidentally mix tenants.
 	orgID := assets[0].OrgID
-	for _, a := range assets {
+	staged := make([]stagedAsset, len(assets))
+	for i, a := range assets {
 		if a.OrgID != orgID {
 			return 0, []error{fmt.Errorf("BatchCreateAssets: heterogeneous OrgIDs in batch (expected %d, got %d)", orgID, a.OrgID)}
 		}
-	}
-
-	// Auto-generate external_keys for assets with empty external_keys.
-	seq, err := s.GetNextAssetSequence(ctx, orgID)
-	if err != nil {
-		return 0, []error{fmt.Errorf("failed to get sequence for auto-generation: %w", err)}
-	}
-
-	for i := range assets {
-		if strings.TrimSpace(assets[i].ExternalKey) == "" {
-			assets[i].ExternalKey = GenerateAssetExternalKey(seq)
-			seq++
+		staged[i] = stagedAsset{
+			externalKey: a.ExternalKey,
+			name:        a.Name,
+			description: a.Description,
+			validFrom:   a.ValidFrom,
+			validTo:     a.ValidTo,
+			isActive:    a.IsActive,
+			metadata:    a.Metadata,
+			ownerUserID: a.OwnerUserID,
+			costCenter:  a.CostCenter,
 		}
 	}
 
 	// TRA-475: BatchCreateAssets is documented and tested as all-or-nothing
 	// insert — any duplicate external_key rolls the whole transaction back.
-	// The partial UNIQUE(org_id, external_key) WHERE deleted_at IS NULL fires
-	// on duplicate, the substring sniff converts it to a row-numbered
-	// error, and WithOrgTx rolls the transaction back. Upsert-on-bulk-import
-	// is intentionally out of scope (see TRA-475 spec).
-	query := `
-		INSERT INTO trakrf.assets
-		(name, external_key, description, valid_from, valid_to, metadata, is_active, org_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`
-
-	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		for i, a := range assets {
-			if _, err := tx.Exec(ctx, query,
-				a.Name, a.ExternalKey, a.Description,
-				a.ValidFrom, a.ValidTo, a.Metadata, a.IsActive, a.OrgID,
-			); err != nil {
-				if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
-					return fmt.Errorf("row %d: asset with external_key %s already exists", i, a.ExternalKey)
-				}
-				return fmt.Errorf("row %d: %w", i, err)
-			}
-		}
-		return nil
-	})
+	// The staged conflict check converts it to a row-numbered error.
+	// Upsert-on-bulk-import is intentionally out of scope (see TRA-475 spec);
+	// only a soft-deleted asset is restored, and only under the org's
+	// auto_restore deleted-key policy.
+	n, _, err := s.copyCreateAssets(ctx, orgID, staged)
 	if err != nil {
 		return 0, []error{err}
 	}
-
-	return len(assets), nil
+	return n, nil
 }
 
 // CheckDuplicateExternalKeys checks if any of the provided external_keys already exist in the database.
@@ -457,6 +596,9 @@ func mapReqToFields(req asset.UpdateAssetRequest) (map[string]any, error) {
 	// The owning org is fixed at creation; ownership transfers must use
 	// dedicated tooling, never a public PATCH body. external_key is also
 	// not writable here (TRA-664 / BB26 D7); see RenameAsset for that path.
+	// Nor is a location: trakrf.assets has no current_location_id column
+	// (see 000006_assets) and location is scan-derived, so an update never
+	// references another entity that would need an org or liveness check.
 	if req.Name != nil {
 		fields["name"] = *req.Name
 	}
@@ -486,13 +628,27 @@ func mapReqToFields(req asset.UpdateAssetRequest) (map[string]any, error) {
 	if req.IsActive != nil {
 		fields["is_active"] = *req.IsActive
 	}
+	// cost_center: explicit null clears to SQL NULL (the read view emits
+	// null either way). owner_user_id is never written here — see
+	// TransferAssetOwnership.
+	if req.ClearCostCenter {
+		fields["cost_center"] = nil
+	} else if req.CostCenter != nil {
+		fields["cost_center"] = *req.CostCenter
+	}
 
 	return fields, nil
 }
 
+// CreateAssetWithTags creates the asset and its tags in one transaction. A
+// caller-sent external_key of a soft-deleted asset is handled per the org's
+// deleted-key policy: under auto_restore that asset is restored with the
+// request's fields and tags (its old tags stay deleted) and returned.
 func (s *Storage) CreateAssetWithTags(ctx context.Context, request asset.CreateAssetWithTagsRequest) (*asset.AssetView, error) {
-	// Auto-generate external_key if empty
-	if strings.TrimSpace(request.ExternalKey) == "" {
+	// Auto-generate external_key if empty; a template draw happens in the
+	// insert's transaction below.
+	minted := request.AutoIdentifier || strings.TrimSpace(request.ExternalKey) == ""
+	if !request.AutoIdentifier && minted {
 		seq, err := s.GetNextAssetSequence(ctx, request.OrgID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate external_key: %w", err)
@@ -537,7 +693,38 @@ func (s *Storage) CreateAssetWithTags(ctx context.Context, request asset.CreateA
 	// data, not part of the asset resource. create_asset_with_tags no longer
 	// takes a location parameter (migration 000043).
 	err = s.WithOrgTx(ctx, request.OrgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query,
+		if request.AutoIdentifier {
+			key, err := nextAssetIdentifier(ctx, tx, request.OrgID)
+			if err != nil {
+				return err
+			}
+			request.ExternalKey = key
+		}
+		deletedID := 0
+		if !minted {
+			var err error
+			if deletedID, err = claimDeletedKey(ctx, tx, "asset", request.OrgID, request.ExternalKey); err != nil {
+				return err
+			}
+		}
+		if deletedID != 0 {
+			assetID = deletedID
+			if _, err := tx.Exec(ctx, `
+				UPDATE trakrf.assets
+				SET name = $3, description = $4, valid_from = $5, valid_to = $6, is_active = $7,
+				    metadata = $8, owner_user_id = $9, cost_center = $10,
+				    deleted_at = NULL, updated_at = NOW()
+				WHERE id = $1 AND org_id = $2`,
+				assetID, request.OrgID, request.Name, description, validFrom, validTo, isActive,
+				request.Metadata, request.OwnerUserID, request.CostCenter); err != nil {
+				return err
+			}
+			if err := restoreTags(ctx, tx, "asset", request.OrgID, assetID, tagsJSON); err != nil {
+				return err
+			}
+			return s.publish(ctx, tx, events.AssetCreated, request.OrgID, assetID)
+		}
+		err := tx.QueryRow(ctx, query,
 			request.OrgID,
 			request.ExternalKey,
 			request.Name,
@@ -548,9 +735,26 @@ func (s *Storage) CreateAssetWithTags(ctx context.Context, request asset.CreateA
 			request.Metadata,
 			tagsJSON,
 		).Scan(&assetID, &tagIDs)
+		if err != nil {
+			return err
+		}
+		// create_asset_with_tags predates ownership; set the two columns in
+		// the same transaction rather than widening the function signature.
+		if request.OwnerUserID != nil || request.CostCenter != nil {
+			if _, err := tx.Exec(ctx, `
+				UPDATE trakrf.assets SET owner_user_id = $3, cost_center = $4
+				WHERE id = $1 AND org_id = $2
+			`, assetID, request.OrgID, request.OwnerUserID, request.CostCenter); err != nil {
+				return err
+			}
+		}
+		return s.publish(ctx, tx, events.AssetCreated, request.OrgID, assetID)
 	})
 
 	if err != nil {
+		if stderrors.Is(err, ErrIdentifierSequenceExhausted) || stderrors.Is(err, ErrDeletedKeyBlocked) {
+			return nil, err
+		}
 		return nil, parseAssetWithTagsError(err, request.ExternalKey)
 	}
 
@@ -586,7 +790,8 @@ func (s *Storage) getAssetViewWithTagsByID(ctx context.Context, orgID, id int) (
 		SELECT
 			a.id, a.org_id, a.external_key, a.name, COALESCE(a.description, ''),
 			a.valid_from, a.valid_to, a.metadata,
-			a.is_active, a.created_at, a.updated_at, a.deleted_at
+			a.is_active, a.created_at, a.updated_at, a.deleted_at,
+			a.owner_user_id, a.cost_center
 		FROM trakrf.assets a
 		WHERE a.id = $1 AND a.org_id = $2 AND a.deleted_at IS NULL
 		LIMIT 1
@@ -597,6 +802,7 @@ func (s *Storage) getAssetViewWithTagsByID(ctx context.Context, orgID, id int) (
 			&a.ID, &a.OrgID, &a.ExternalKey, &a.Name, &a.Description,
 			&a.ValidFrom, &a.ValidTo, &a.Metadata,
 			&a.IsActive, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+			&a.OwnerUserID, &a.CostCenter,
 		)
 	})
 	if err != nil {
@@ -617,39 +823,118 @@ func (s *Storage) getAssetViewWithTagsByID(ctx context.Context, orgID, id int) (
 	}, nil
 }
 
-func (s *Storage) ListAssetViews(ctx context.Context, orgID, limit, offset int) ([]asset.AssetView, error) {
-	assets, err := s.ListAllAssets(ctx, orgID, limit, offset)
+// ListAssetViews returns a page of assets matching f with their tags and
+// current location, and the total number of matches, in two queries: one for
+// the page, its current locations and the total, one for the page's tags.
+//
+// The current location is the asset's latest bucket in the asset_scan_latest
+// continuous aggregate, probed per asset on the page rather than collapsed
+// for the whole org as the asset-locations report does.
+func (s *Storage) ListAssetViews(ctx context.Context, orgID int, f asset.ListFilter) ([]asset.AssetLocationView, int, error) {
+	where, args, err := buildAssetsWhere(orgID, f)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
+	orderBy := buildAssetsOrderBy(f.Sorts)
 
-	if len(assets) == 0 {
-		return []asset.AssetView{}, nil
-	}
+	// The window count runs over the filtered assets before LIMIT; the
+	// location lookups run after it, so only the page pays for them.
+	query := fmt.Sprintf(`
+		SELECT
+			a.id, a.org_id, a.external_key, a.name, COALESCE(a.description, ''),
+			a.valid_from, a.valid_to, a.metadata,
+			a.is_active, a.created_at, a.updated_at, a.deleted_at,
+			a.owner_user_id, a.cost_center,
+			a.total,
+			l.id, l.external_key, l.name, ls.last_seen
+		FROM (
+			SELECT a.*, COUNT(*) OVER () AS total
+			FROM trakrf.assets a
+			WHERE %s
+			ORDER BY %s
+			LIMIT $%d OFFSET $%d
+		) a
+		LEFT JOIN LATERAL (
+			SELECT location_id, last_seen
+			FROM trakrf.asset_scan_latest
+			WHERE org_id = $1 AND asset_id = a.id
+			ORDER BY last_seen DESC
+			LIMIT 1
+		) ls ON true
+		LEFT JOIN trakrf.locations l
+			ON l.id = ls.location_id AND l.org_id = $1 AND l.deleted_at IS NULL AND %s
+		ORDER BY %s
+	`, where, orderBy, len(args)+1, len(args)+2, temporallyEffective("l"), orderBy)
+	args = append(args, clampAssetListLimit(f.Limit), f.Offset)
 
-	assetIDs := make([]int, len(assets))
-	for i, a := range assets {
-		assetIDs[i] = a.ID
-	}
+	out := []asset.AssetLocationView{}
+	total := 0
+	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
 
-	tagMap, err := s.getTagsForAssets(ctx, orgID, assetIDs)
+		for rows.Next() {
+			var (
+				v        asset.AssetLocationView
+				locID    *int
+				locKey   *string
+				locName  *string
+				lastSeen *time.Time
+			)
+			a := &v.Asset
+			if err := rows.Scan(
+				&a.ID, &a.OrgID, &a.ExternalKey, &a.Name, &a.Description,
+				&a.ValidFrom, &a.ValidTo, &a.Metadata,
+				&a.IsActive, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+				&a.OwnerUserID, &a.CostCenter,
+				&total,
+				&locID, &locKey, &locName, &lastSeen,
+			); err != nil {
+				return fmt.Errorf("scan asset view: %w", err)
+			}
+			if locID != nil {
+				v.CurrentLocation = &asset.CurrentLocationRef{
+					ID: *locID, ExternalKey: *locKey, Name: *locName,
+					LastSeen: shared.NewPublicTime(*lastSeen),
+				}
+			}
+			out = append(out, v)
+		}
+		return rows.Err()
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, fmt.Errorf("list asset views: %w", err)
 	}
 
-	views := make([]asset.AssetView, len(assets))
-	for i, a := range assets {
-		ids := tagMap[a.ID]
-		if ids == nil {
-			ids = []shared.Tag{}
-		}
-		views[i] = asset.AssetView{
-			Asset: a,
-			Tags:  ids,
+	// A page past the end has no row to carry the total.
+	if len(out) == 0 && f.Offset > 0 {
+		if total, err = s.CountAssetsFiltered(ctx, orgID, f); err != nil {
+			return nil, 0, err
 		}
 	}
 
-	return views, nil
+	if len(out) > 0 && wantsTags(f.Fields) {
+		ids := make([]int, len(out))
+		for i, v := range out {
+			ids[i] = v.ID
+		}
+		tagMap, err := s.getTagsForAssets(ctx, orgID, ids)
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := range out {
+			out[i].Tags = tagMap[out[i].ID]
+		}
+	}
+	for i := range out {
+		if out[i].Tags == nil {
+			out[i].Tags = []shared.Tag{}
+		}
+	}
+	return out, total, nil
 }
 
 // GetAssetByExternalKey returns the live (non-deleted) asset with the given
@@ -661,7 +946,8 @@ func (s *Storage) GetAssetByExternalKey(
 		SELECT
 			a.id, a.org_id, a.external_key, a.name, COALESCE(a.description, ''),
 			a.valid_from, a.valid_to, a.metadata,
-			a.is_active, a.created_at, a.updated_at, a.deleted_at
+			a.is_active, a.created_at, a.updated_at, a.deleted_at,
+			a.owner_user_id, a.cost_center
 		FROM trakrf.assets a
 		WHERE a.org_id = $1 AND a.external_key = $2 AND a.deleted_at IS NULL
 		LIMIT 1
@@ -672,6 +958,7 @@ func (s *Storage) GetAssetByExternalKey(
 			&a.ID, &a.OrgID, &a.ExternalKey, &a.Name, &a.Description,
 			&a.ValidFrom, &a.ValidTo, &a.Metadata,
 			&a.IsActive, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+			&a.OwnerUserID, &a.CostCenter,
 		)
 	})
 	if err != nil {
@@ -745,14 +1032,18 @@ func (s *Storage) GetAssetIDsByExternalKeys(
 func (s *Storage) ListAssetsFiltered(
 	ctx context.Context, orgID int, f asset.ListFilter,
 ) ([]asset.AssetView, error) {
-	where, args := buildAssetsWhere(orgID, f)
+	where, args, err := buildAssetsWhere(orgID, f)
+	if err != nil {
+		return nil, err
+	}
 	orderBy := buildAssetsOrderBy(f.Sorts)
 
 	query := fmt.Sprintf(`
 		SELECT
 			a.id, a.org_id, a.external_key, a.name, COALESCE(a.description, ''),
 			a.valid_from, a.valid_to, a.metadata,
-			a.is_active, a.created_at, a.updated_at, a.deleted_at
+			a.is_active, a.created_at, a.updated_at, a.deleted_at,
+			a.owner_user_id, a.cost_center
 		FROM trakrf.assets a
 		WHERE %s
 		ORDER BY %s
@@ -761,57 +1052,78 @@ func (s *Storage) ListAssetsFiltered(
 
 	args = append(args, clampAssetListLimit(f.Limit), f.Offset)
 
-	out := []asset.AssetView{}
-	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+	var out []asset.AssetView
+	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
 		rows, err := tx.Query(ctx, query, args...)
 		if err != nil {
 			return err
 		}
-		defer rows.Close()
-
-		for rows.Next() {
-			var a asset.Asset
-			if err := rows.Scan(
-				&a.ID, &a.OrgID, &a.ExternalKey, &a.Name, &a.Description,
-				&a.ValidFrom, &a.ValidTo, &a.Metadata,
-				&a.IsActive, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
-			); err != nil {
-				return fmt.Errorf("scan asset: %w", err)
-			}
-			out = append(out, asset.AssetView{Asset: a, Tags: nil})
-		}
-		return rows.Err()
+		out, err = scanAssetViews(rows)
+		return err
 	})
 	if err != nil {
 		return nil, fmt.Errorf("list assets filtered: %w", err)
 	}
 
-	// Bulk-fetch tags for the returned assets.
-	if len(out) > 0 {
-		ids := make([]int, len(out))
-		for i, a := range out {
-			ids[i] = a.ID
-		}
-		tagMap, err := s.getTagsForAssets(ctx, orgID, ids)
-		if err != nil {
+	if wantsTags(f.Fields) {
+		if err := s.attachAssetTags(ctx, orgID, out); err != nil {
 			return nil, err
 		}
-		for i := range out {
-			out[i].Tags = tagMap[out[i].ID]
-			if out[i].Tags == nil {
-				out[i].Tags = []shared.Tag{}
-			}
+	}
+	return out, nil
+}
+
+// scanAssetViews reads the asset columns ListAssetsFiltered selects, in
+// order, into tagless views. It closes rows.
+func scanAssetViews(rows pgx.Rows) ([]asset.AssetView, error) {
+	defer rows.Close()
+	out := []asset.AssetView{}
+	for rows.Next() {
+		var a asset.Asset
+		if err := rows.Scan(
+			&a.ID, &a.OrgID, &a.ExternalKey, &a.Name, &a.Description,
+			&a.ValidFrom, &a.ValidTo, &a.Metadata,
+			&a.IsActive, &a.CreatedAt, &a.UpdatedAt, &a.DeletedAt,
+			&a.OwnerUserID, &a.CostCenter,
+		); err != nil {
+			return nil, fmt.Errorf("scan asset: %w", err)
 		}
+		out = append(out, asset.AssetView{Asset: a, Tags: nil})
 	}
+	return out, rows.Err()
+}
 
-	return out, nil
+// attachAssetTags bulk-fetches the tags of views and sets them, [] for an
+// asset with none.
+func (s *Storage) attachAssetTags(ctx context.Context, orgID int, views []asset.AssetView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	ids := make([]int, len(views))
+	for i, a := range views {
+		ids[i] = a.ID
+	}
+	tagMap, err := s.getTagsForAssets(ctx, orgID, ids)
+	if err != nil {
+		return err
+	}
+	for i := range views {
+		views[i].Tags = tagMap[views[i].ID]
+		if views[i].Tags == nil {
+			views[i].Tags = []shared.Tag{}
+		}
+	}
+	return nil
 }
 
 // CountAssetsFiltered returns total matching count (ignores limit/offset/sort).
 func (s *Storage) CountAssetsFiltered(
 	ctx context.Context, orgID int, f asset.ListFilter,
 ) (int, error) {
-	where, args := buildAssetsWhere(orgID, f)
+	where, args, err := buildAssetsWhere(orgID, f)
+	if err != nil {
+		return 0, err
+	}
 	query := fmt.Sprintf(`
 		SELECT COUNT(*)
 		FROM trakrf.assets a
@@ -819,7 +1131,7 @@ func (s *Storage) CountAssetsFiltered(
 	`, where)
 
 	var n int
-	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
 		return tx.QueryRow(ctx, query, args...).Scan(&n)
 	})
 	if err != nil {
@@ -828,19 +1140,38 @@ func (s *Storage) CountAssetsFiltered(
 	return n, nil
 }
 
-func buildAssetsWhere(orgID int, f asset.ListFilter) (string, []any) {
+// assetFilterColumns maps the fields of asset.FilterFields to the columns of
+// the assets list query.
+var assetFilterColumns = map[string]string{
+	"external_key":  "a.external_key",
+	"name":          "a.name",
+	"description":   "a.description",
+	"cost_center":   "a.cost_center",
+	"id":            "a.id",
+	"owner_user_id": "a.owner_user_id",
+	"team_id":       "a.team_id",
+	"is_active":     "a.is_active",
+	"valid_from":    "a.valid_from",
+	"valid_to":      "a.valid_to",
+	"created_at":    "a.created_at",
+	"updated_at":    "a.updated_at",
+	"metadata":      "a.metadata",
+}
+
+func buildAssetsWhere(orgID int, f asset.ListFilter) (string, []any, error) {
 	// TRA-659 / BB25 A3: include_deleted relaxes the soft-delete filter so
 	// callers reconciling against an external system of record can enumerate
 	// deleted rows alongside live ones. Temporal validity still applies.
 	// Orthogonal to is_active.
+	// AsOf moves the temporal validity check from now to that instant.
+	at, args := effectiveAtParam(f.AsOf, []any{orgID})
 	clauses := []string{
 		"a.org_id = $1",
-		temporallyEffective("a"),
+		temporallyEffectiveAt("a", at),
 	}
 	if !f.IncludeDeleted {
 		clauses = append(clauses, "a.deleted_at IS NULL")
 	}
-	args := []any{orgID}
 
 	if len(f.ExternalKeys) > 0 {
 		args = append(args, f.ExternalKeys)
@@ -850,6 +1181,26 @@ func buildAssetsWhere(orgID int, f asset.ListFilter) (string, []any) {
 		args = append(args, *f.IsActive)
 		clauses = append(clauses, fmt.Sprintf("a.is_active = $%d", len(args)))
 	}
+	if len(f.OwnerUserIDs) > 0 {
+		args = append(args, f.OwnerUserIDs)
+		clauses = append(clauses, fmt.Sprintf("a.owner_user_id = ANY($%d::bigint[])", len(args)))
+	}
+	if len(f.CostCenters) > 0 {
+		args = append(args, f.CostCenters)
+		clauses = append(clauses, fmt.Sprintf("a.cost_center = ANY($%d::text[])", len(args)))
+	}
+	if len(f.TeamIDs) > 0 {
+		args = append(args, f.TeamIDs)
+		clauses = append(clauses, fmt.Sprintf("a.team_id = ANY($%d::bigint[])", len(args)))
+	}
+	if f.TeamScope.Restricted {
+		args = append(args, f.TeamScope.TeamIDs)
+		clauses = append(clauses, fmt.Sprintf("a.team_id = ANY($%d::bigint[])", len(args)))
+	}
+	if f.SubtreeScope.Restricted {
+		args = append(args, f.SubtreeScope.RootIDs)
+		clauses = append(clauses, assetSubtreeClause("a.id", len(args)))
+	}
 	if f.Q != nil {
 		args = append(args, "%"+*f.Q+"%")
 		idx := len(args)
@@ -857,11 +1208,19 @@ func buildAssetsWhere(orgID int, f asset.ListFilter) (string, []any) {
 			"(a.name ILIKE $%d OR a.external_key ILIKE $%d OR a.description ILIKE $%d "+
 				"OR EXISTS (SELECT 1 FROM trakrf.tags i "+
 				"WHERE i.asset_id = a.id AND i.is_active = true "+
-				"AND i.deleted_at IS NULL AND "+temporallyEffective("i")+
+				"AND i.deleted_at IS NULL AND "+temporallyEffectiveAt("i", at)+
 				" AND i.value ILIKE $%d))",
 			idx, idx, idx, idx))
 	}
-	return strings.Join(clauses, " AND "), args
+	if f.Filter != nil {
+		frag, filterArgs, err := filterexpr.SQL(f.Filter, assetFilterColumns, args)
+		if err != nil {
+			return "", nil, err
+		}
+		args = filterArgs
+		clauses = append(clauses, frag)
+	}
+	return strings.Join(clauses, " AND "), args, nil
 }
 
 func buildAssetsOrderBy(sorts []asset.ListSort) string {
@@ -889,6 +1248,12 @@ func clampAssetListLimit(n int) int {
 	return n
 }
 
+// wantsTags reports whether a list response with sparse fieldset fields
+// shows tags, so the tag fetch can be skipped when it does not.
+func wantsTags(fields []string) bool {
+	return len(fields) == 0 || slices.Contains(fields, "tags")
+}
+
 func parseAssetWithTagsError(err error, externalKey string) error {
 	errStr := err.Error()
 
@@ -897,6 +1262,10 @@ func parseAssetWithTagsError(err error, externalKey string) error {
 		return fmt.Errorf("asset with external_key %s already exists", externalKey)
 	}
 
+	if isTagCrossTypeErr(err) {
+		return fmt.Errorf("one or more tag values already exist under another tag type")
+	}
+
 	if strings.Contains(errStr, "tags_org_id_type_value") ||
 		(strings.Contains(errStr, "duplicate key") && strings.Contains(errStr, "tags")) {
 		return fmt.Errorf("one or more tags already exist")
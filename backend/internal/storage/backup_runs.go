@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/backup"
+)
+
+const backupRunColumns = `id, status, schema_version, object_key, table_count, row_count, size_bytes,
+	error, started_at, finished_at`
+
+func scanBackupRun(row pgx.Row) (*backup.Run, error) {
+	var r backup.Run
+	var version *int64
+	if err := row.Scan(&r.ID, &r.Status, &version, &r.ObjectKey, &r.TableCount, &r.RowCount,
+		&r.SizeBytes, &r.Error, &r.StartedAt, &r.FinishedAt); err != nil {
+		return nil, err
+	}
+	if version != nil {
+		v := uint(*version)
+		r.SchemaVersion = &v
+	}
+	return &r, nil
+}
+
+// StartBackupRun records a backup run as running and returns it.
+func (s *Storage) StartBackupRun(ctx context.Context) (*backup.Run, error) {
+	r, err := scanBackupRun(s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.backup_runs DEFAULT VALUES
+		RETURNING `+backupRunColumns))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start backup run: %w", err)
+	}
+	return r, nil
+}
+
+// FinishBackupRun records the outcome of run: its status, what it dumped and
+// the error it failed with.
+func (s *Storage) FinishBackupRun(ctx context.Context, run backup.Run) error {
+	var version *int64
+	if run.SchemaVersion != nil {
+		v := int64(*run.SchemaVersion)
+		version = &v
+	}
+	_, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.backup_runs
+		SET status = $2, schema_version = $3, object_key = $4, table_count = $5,
+		    row_count = $6, size_bytes = $7, error = $8, finished_at = NOW()
+		WHERE id = $1`,
+		run.ID, run.Status, version, run.ObjectKey, run.TableCount, run.RowCount, run.SizeBytes, run.Error)
+	if err != nil {
+		return fmt.Errorf("failed to finish backup run: %w", err)
+	}
+	return nil
+}
+
+// LatestBackupRuns returns the most recent finished backup run and the most
+// recent successful one. Either is nil when there is none.
+func (s *Storage) LatestBackupRuns(ctx context.Context) (last, lastSucceeded *backup.Run, err error) {
+	query := func(where string) (*backup.Run, error) {
+		r, err := scanBackupRun(s.pool.QueryRow(ctx, `
+			SELECT `+backupRunColumns+`
+			FROM trakrf.backup_runs
+			WHERE finished_at IS NOT NULL`+where+`
+			ORDER BY finished_at DESC, id DESC
+			LIMIT 1`))
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return r, err
+	}
+	if last, err = query(""); err != nil {
+		return nil, nil, fmt.Errorf("failed to get latest backup run: %w", err)
+	}
+	if last == nil || last.Status == backup.StatusSucceeded {
+		return last, last, nil
+	}
+	if lastSucceeded, err = query(` AND status = '` + backup.StatusSucceeded + `'`); err != nil {
+		return nil, nil, fmt.Errorf("failed to get latest successful backup run: %w", err)
+	}
+	return last, lastSucceeded, nil
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// execBatch sends the statements queued on batch to tx in one round-trip and
+// returns their command tags in queue order. The first statement to fail
+// returns its error; it aborts the transaction, so none after it took
+// effect either.
+//
+// Repeated per-row writes (scan inserts, tag copies) should queue here
+// rather than loop over tx.Exec: with the pool's cached statements each row
+// costs a Bind/Execute in the same packet instead of a network round-trip.
+func execBatch(ctx context.Context, tx pgx.Tx, batch *pgx.Batch) ([]pgconn.CommandTag, error) {
+	tags := make([]pgconn.CommandTag, batch.Len())
+	br := tx.SendBatch(ctx, batch)
+	defer br.Close()
+	for i := range tags {
+		tag, err := br.Exec()
+		if err != nil {
+			return nil, err
+		}
+		tags[i] = tag
+	}
+	return tags, br.Close()
+}
@@ -117,7 +117,9 @@ func (s *Storage) UpdateBulkImportJobProgress(ctx context.Context, orgID int, jo
 func (s *Storage) UpdateBulkImportJobStatus(ctx context.Context, orgID int, jobID int, status string) error {
 	query := `
 		UPDATE trakrf.bulk_import_jobs
-		SET status = $3, completed_at = CASE WHEN $3 IN ('completed', 'failed') THEN NOW() ELSE completed_at END
+		SET status = $3,
+		    completed_at = CASE WHEN $3 IN ('completed', 'failed') THEN NOW() ELSE completed_at END,
+		    payload = CASE WHEN $3 IN ('completed', 'failed') THEN NULL ELSE payload END
 		WHERE id = $1 AND org_id = $2
 	`
 
@@ -140,3 +142,107 @@ func (s *Storage) UpdateBulkImportJobStatus(ctx context.Context, orgID int, jobI
 
 	return nil
 }
+
+// InterruptBulkImportJob parks a job the importer stopped at a row boundary
+// during shutdown: it records progress so far plus the CSV records and the
+// next row to insert, so ClaimInterruptedBulkImportJob can resume it.
+func (s *Storage) InterruptBulkImportJob(ctx context.Context, orgID int, jobID int, st bulkimport.ResumeState) error {
+	payload, err := json.Marshal(st.Records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	errorsJSON, err := json.Marshal(st.Errors)
+	if err != nil {
+		return fmt.Errorf("failed to marshal errors: %w", err)
+	}
+
+	query := `
+		UPDATE trakrf.bulk_import_jobs
+		SET status = 'interrupted', payload = $3, resume_row = $4,
+		    processed_rows = $5, failed_rows = $6, tags_created = $7, errors = $8
+		WHERE id = $1 AND org_id = $2 AND status = 'processing'
+	`
+
+	var rowsAffected int64
+	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		result, err := tx.Exec(ctx, query, jobID, orgID, payload, st.ResumeRow,
+			st.ProcessedRows, st.FailedRows, st.TagsCreated, errorsJSON)
+		if err != nil {
+			return err
+		}
+		rowsAffected = result.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to interrupt job: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("job not found or not processing: %d", jobID)
+	}
+
+	return nil
+}
+
+// InterruptedBulkImportJob identifies a parked job awaiting resume.
+type InterruptedBulkImportJob struct {
+	ID    int
+	OrgID int
+}
+
+// ListInterruptedBulkImportJobs returns every org's interrupted jobs, oldest
+// first. SECURITY DEFINER under the hood, so no org context is needed.
+func (s *Storage) ListInterruptedBulkImportJobs(ctx context.Context) ([]InterruptedBulkImportJob, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, org_id FROM trakrf.list_interrupted_bulk_import_jobs()`)
+	if err != nil {
+		return nil, fmt.Errorf("list interrupted bulk import jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var out []InterruptedBulkImportJob
+	for rows.Next() {
+		var j InterruptedBulkImportJob
+		if err := rows.Scan(&j.ID, &j.OrgID); err != nil {
+			return nil, fmt.Errorf("scan interrupted bulk import job: %w", err)
+		}
+		out = append(out, j)
+	}
+	return out, rows.Err()
+}
+
+// ClaimInterruptedBulkImportJob flips an interrupted job back to processing
+// and returns its saved state. The conditional UPDATE makes the claim atomic
+// across replicas: exactly one caller gets the state, the rest get nil.
+func (s *Storage) ClaimInterruptedBulkImportJob(ctx context.Context, orgID int, jobID int) (*bulkimport.ResumeState, error) {
+	query := `
+		UPDATE trakrf.bulk_import_jobs
+		SET status = 'processing'
+		WHERE id = $1 AND org_id = $2 AND status = 'interrupted'
+		RETURNING payload, resume_row, processed_rows, failed_rows, tags_created, errors
+	`
+
+	var st bulkimport.ResumeState
+	var payload, errorsJSON []byte
+
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, jobID, orgID).Scan(
+			&payload, &st.ResumeRow, &st.ProcessedRows, &st.FailedRows, &st.TagsCreated, &errorsJSON,
+		)
+	})
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Already claimed by another replica
+		}
+		return nil, fmt.Errorf("failed to claim bulk import job: %w", err)
+	}
+
+	if err := json.Unmarshal(payload, &st.Records); err != nil {
+		return nil, fmt.Errorf("failed to parse job payload: %w", err)
+	}
+	if err := json.Unmarshal(errorsJSON, &st.Errors); err != nil {
+		return nil, fmt.Errorf("failed to parse job errors: %w", err)
+	}
+
+	return &st, nil
+}
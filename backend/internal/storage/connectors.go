@@ -0,0 +1,393 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/connector"
+)
+
+// Like webhook_endpoints, the connector tables have no RLS: the sync job
+// claims connectors across orgs. Org-facing queries filter on org_id
+// explicitly; asset reads and writes go through WithOrgTx.
+
+const connectorColumns = `id, org_id, kind, name, config, credentials, field_mapping, direction,
+	conflict_policy, sync_interval_seconds, is_active, next_sync_at, remote_cursor,
+	last_sync_at, last_sync_status, last_error, created_by, created_at, updated_at`
+
+func scanConnector(row pgx.Row) (*connector.Connector, error) {
+	var c connector.Connector
+	if err := row.Scan(&c.ID, &c.OrgID, &c.Kind, &c.Name, &c.Config, &c.Credentials, &c.FieldMapping,
+		&c.Direction, &c.ConflictPolicy, &c.SyncIntervalSeconds, &c.IsActive, &c.NextSyncAt,
+		&c.RemoteCursor, &c.LastSyncAt, &c.LastSyncStatus, &c.LastError, &c.CreatedBy,
+		&c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func connectorNameError(err error, name string) error {
+	if strings.Contains(err.Error(), "idx_connectors_org_name") {
+		return fmt.Errorf("connector with name %s already exists", name)
+	}
+	return nil
+}
+
+// CreateConnector inserts a connector due for its first sync immediately.
+// c.Credentials must already be vault-sealed.
+func (s *Storage) CreateConnector(ctx context.Context, c connector.Connector) (*connector.Connector, error) {
+	created, err := scanConnector(s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.connectors
+			(org_id, kind, name, config, credentials, field_mapping, direction,
+			 conflict_policy, sync_interval_seconds, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING `+connectorColumns,
+		c.OrgID, c.Kind, c.Name, c.Config, c.Credentials, c.FieldMapping, c.Direction,
+		c.ConflictPolicy, c.SyncIntervalSeconds, c.CreatedBy))
+	if err != nil {
+		if nameErr := connectorNameError(err, c.Name); nameErr != nil {
+			return nil, nameErr
+		}
+		return nil, fmt.Errorf("failed to create connector: %w", err)
+	}
+	return created, nil
+}
+
+// ListConnectors returns the org's live connectors by name.
+func (s *Storage) ListConnectors(ctx context.Context, orgID int) ([]connector.Connector, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+connectorColumns+`
+		FROM trakrf.connectors
+		WHERE org_id = $1 AND deleted_at IS NULL
+		ORDER BY lower(name), id`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connectors: %w", err)
+	}
+	defer rows.Close()
+
+	out := []connector.Connector{}
+	for rows.Next() {
+		c, err := scanConnector(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan connector: %w", err)
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+// GetConnector returns a live connector, or nil when it does not exist in the
+// org.
+func (s *Storage) GetConnector(ctx context.Context, orgID, id int) (*connector.Connector, error) {
+	c, err := scanConnector(s.pool.QueryRow(ctx, `
+		SELECT `+connectorColumns+`
+		FROM trakrf.connectors
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, id, orgID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connector: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateConnector applies the non-nil fields of req; sealedCredentials, when
+// non-nil, replaces the stored credentials (req.Credentials is ignored). A
+// config change resets the remote cursor so the next run re-reads every
+// remote record. Returns nil when the connector does not exist in the org.
+func (s *Storage) UpdateConnector(ctx context.Context, orgID, id int, req connector.UpdateConnectorRequest, sealedCredentials []byte) (*connector.Connector, error) {
+	var config, mapping any
+	if req.Config != nil {
+		config = *req.Config
+	}
+	if req.FieldMapping != nil {
+		mapping = *req.FieldMapping
+	}
+	c, err := scanConnector(s.pool.QueryRow(ctx, `
+		UPDATE trakrf.connectors SET
+			name = COALESCE($3, name),
+			config = COALESCE($4::jsonb, config),
+			remote_cursor = CASE WHEN $4::jsonb IS NULL THEN remote_cursor END,
+			credentials = COALESCE($5, credentials),
+			field_mapping = COALESCE($6::jsonb, field_mapping),
+			direction = COALESCE($7, direction),
+			conflict_policy = COALESCE($8, conflict_policy),
+			sync_interval_seconds = COALESCE($9, sync_interval_seconds),
+			is_active = COALESCE($10, is_active)
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+		RETURNING `+connectorColumns,
+		id, orgID, req.Name, config, sealedCredentials, mapping, req.Direction,
+		req.ConflictPolicy, req.SyncIntervalSeconds, req.IsActive))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		if req.Name != nil {
+			if nameErr := connectorNameError(err, *req.Name); nameErr != nil {
+				return nil, nameErr
+			}
+		}
+		return nil, fmt.Errorf("failed to update connector: %w", err)
+	}
+	return c, nil
+}
+
+// DeleteConnector soft-deletes a connector, which stops its syncs. Assets it
+// created stay. Returns false when it does not exist in the org.
+func (s *Storage) DeleteConnector(ctx context.Context, orgID, id int) (bool, error) {
+	ct, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.connectors SET deleted_at = NOW(), is_active = false
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, id, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete connector: %w", err)
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// RequestConnectorSync makes an active connector due now. Returns false when
+// no active connector matched.
+func (s *Storage) RequestConnectorSync(ctx context.Context, orgID, id int) (bool, error) {
+	ct, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.connectors SET next_sync_at = NOW()
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL AND is_active`, id, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to request connector sync: %w", err)
+	}
+	return ct.RowsAffected() > 0, nil
+}
+
+// ListConnectorSyncRuns returns a connector's most recent runs, newest first.
+func (s *Storage) ListConnectorSyncRuns(ctx context.Context, orgID, connectorID, limit int) ([]connector.SyncRun, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, connector_id, started_at, finished_at, status, pulled, pushed, conflicts, failures, error
+		FROM trakrf.connector_sync_runs
+		WHERE org_id = $1 AND connector_id = $2
+		ORDER BY started_at DESC, id DESC
+		LIMIT $3`, orgID, connectorID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connector sync runs: %w", err)
+	}
+	defer rows.Close()
+
+	out := []connector.SyncRun{}
+	for rows.Next() {
+		var r connector.SyncRun
+		if err := rows.Scan(&r.ID, &r.ConnectorID, &r.StartedAt, &r.FinishedAt, &r.Status,
+			&r.Pulled, &r.Pushed, &r.Conflicts, &r.Failures, &r.Error); err != nil {
+			return nil, fmt.Errorf("failed to scan connector sync run: %w", err)
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// ClaimDueConnectors leases up to limit due active connectors by pushing their
+// next_sync_at out by lease, the same lease shape as webhook delivery: if this
+// process dies mid-sync the connector becomes due again when the lease runs
+// out. FinishConnectorSync sets the real next run. Sandbox orgs' connectors
+// never run, so a sandbox cannot write to the external system.
+func (s *Storage) ClaimDueConnectors(ctx context.Context, limit int, lease time.Duration) ([]connector.Connector, error) {
+	rows, err := s.pool.Query(ctx, `
+		UPDATE trakrf.connectors
+		SET next_sync_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM trakrf.connectors
+			WHERE deleted_at IS NULL AND is_active AND next_sync_at <= NOW()
+			  AND org_id NOT IN (SELECT id FROM trakrf.organizations WHERE is_sandbox)
+			ORDER BY next_sync_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+connectorColumns, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim connectors: %w", err)
+	}
+	defer rows.Close()
+
+	var out []connector.Connector
+	for rows.Next() {
+		c, err := scanConnector(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan connector: %w", err)
+		}
+		out = append(out, *c)
+	}
+	return out, rows.Err()
+}
+
+// ListConnectorAssets returns every live asset in the org with its link to
+// connectorID, if any. Fixed-asset registers run to thousands of rows, not
+// millions, so a run reads them all rather than tracking local changes
+// separately.
+func (s *Storage) ListConnectorAssets(ctx context.Context, orgID, connectorID int) ([]connector.LocalAsset, error) {
+	out := []connector.LocalAsset{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT a.id, a.org_id, a.external_key, a.name, COALESCE(a.description, ''), a.valid_from,
+			       a.valid_to, a.metadata, a.is_active, a.created_at, a.updated_at, a.cost_center,
+			       l.remote_id, l.remote_updated_at, l.local_synced_at
+			FROM trakrf.assets a
+			LEFT JOIN trakrf.connector_asset_links l ON l.asset_id = a.id AND l.connector_id = $2
+			WHERE a.org_id = $1 AND a.deleted_at IS NULL
+			ORDER BY a.id`, orgID, connectorID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var (
+				la              connector.LocalAsset
+				remoteID        *string
+				remoteUpdatedAt *time.Time
+				localSyncedAt   *time.Time
+			)
+			a := &la.Asset
+			if err := rows.Scan(&a.ID, &a.OrgID, &a.ExternalKey, &a.Name, &a.Description, &a.ValidFrom,
+				&a.ValidTo, &a.Metadata, &a.IsActive, &a.CreatedAt, &a.UpdatedAt, &a.CostCenter,
+				&remoteID, &remoteUpdatedAt, &localSyncedAt); err != nil {
+				return err
+			}
+			if remoteID != nil {
+				la.Link = &connector.Link{
+					ConnectorID:     connectorID,
+					AssetID:         a.ID,
+					RemoteID:        *remoteID,
+					RemoteUpdatedAt: *remoteUpdatedAt,
+					LocalSyncedAt:   *localSyncedAt,
+				}
+			}
+			out = append(out, la)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connector assets: %w", err)
+	}
+	return out, nil
+}
+
+const upsertConnectorLink = `
+	INSERT INTO trakrf.connector_asset_links
+		(connector_id, asset_id, remote_id, remote_updated_at, local_synced_at)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (connector_id, remote_id) DO UPDATE SET
+		asset_id = EXCLUDED.asset_id,
+		remote_updated_at = EXCLUDED.remote_updated_at,
+		local_synced_at = EXCLUDED.local_synced_at`
+
+// ApplyConnectorPull writes a pulled remote record onto an asset — creating
+// the asset when assetID is nil — and records the link, in one transaction.
+// Unmapped (nil) fields are left alone; mapped metadata keys merge into the
+// asset's metadata. external_key is only used on create: renames go through
+// RenameAsset. Returns the asset id.
+func (s *Storage) ApplyConnectorPull(ctx context.Context, orgID, connectorID int, assetID *int, f connector.AssetFields, rec connector.RemoteRecord) (int, error) {
+	var meta []byte
+	if f.Metadata != nil {
+		b, err := json.Marshal(f.Metadata)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode pulled metadata: %w", err)
+		}
+		meta = b
+	}
+
+	var (
+		id        int
+		updatedAt time.Time
+	)
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if assetID == nil {
+			if f.ExternalKey == nil {
+				return fmt.Errorf("remote record %s has no external_key value", rec.ID)
+			}
+			name := *f.ExternalKey
+			if f.Name != nil {
+				name = *f.Name
+			}
+			err := tx.QueryRow(ctx, `
+				INSERT INTO trakrf.assets (org_id, external_key, name, description, is_active, cost_center, metadata)
+				VALUES ($1, $2, $3, COALESCE($4::text, ''), COALESCE($5::boolean, true), $6,
+				        COALESCE($7::jsonb, '{}'))
+				RETURNING id, updated_at`,
+				orgID, *f.ExternalKey, name, f.Description, f.IsActive, f.CostCenter, meta,
+			).Scan(&id, &updatedAt)
+			if err != nil {
+				return err
+			}
+			if err := s.publish(ctx, tx, events.AssetCreated, orgID, id); err != nil {
+				return err
+			}
+		} else {
+			err := tx.QueryRow(ctx, `
+				UPDATE trakrf.assets SET
+					name = COALESCE($3::text, name),
+					description = COALESCE($4::text, description),
+					is_active = COALESCE($5::boolean, is_active),
+					cost_center = COALESCE($6::text, cost_center),
+					metadata = COALESCE(metadata, '{}') || COALESCE($7::jsonb, '{}'),
+					updated_at = NOW()
+				WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+				RETURNING id, updated_at`,
+				*assetID, orgID, f.Name, f.Description, f.IsActive, f.CostCenter, meta,
+			).Scan(&id, &updatedAt)
+			if err != nil {
+				return err
+			}
+			if err := s.publish(ctx, tx, events.AssetUpdated, orgID, id); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, upsertConnectorLink, connectorID, id, rec.ID, rec.UpdatedAt, updatedAt)
+		return err
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+			return 0, fmt.Errorf("asset for remote record %s already exists", rec.ID)
+		}
+		return 0, fmt.Errorf("failed to apply remote record %s: %w", rec.ID, err)
+	}
+	return id, nil
+}
+
+// SaveConnectorLink records a link after an asset was pushed to the remote.
+func (s *Storage) SaveConnectorLink(ctx context.Context, l connector.Link) error {
+	if _, err := s.pool.Exec(ctx, upsertConnectorLink,
+		l.ConnectorID, l.AssetID, l.RemoteID, l.RemoteUpdatedAt, l.LocalSyncedAt); err != nil {
+		return fmt.Errorf("failed to save connector link: %w", err)
+	}
+	return nil
+}
+
+// FinishConnectorSync records a run and schedules the connector's next one.
+// A nil cursor keeps the stored one.
+func (s *Storage) FinishConnectorSync(ctx context.Context, c connector.Connector, run connector.SyncRun, cursor *time.Time) error {
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO trakrf.connector_sync_runs
+				(connector_id, org_id, started_at, finished_at, status, pulled, pushed, conflicts, failures, error)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+			c.ID, c.OrgID, run.StartedAt, run.FinishedAt, run.Status, run.Pulled, run.Pushed,
+			run.Conflicts, run.Failures, run.Error); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE trakrf.connectors SET
+				last_sync_at = $2,
+				last_sync_status = $3,
+				last_error = $4,
+				remote_cursor = COALESCE($5, remote_cursor),
+				next_sync_at = $2 + make_interval(secs => sync_interval_seconds)
+			WHERE id = $1`,
+			c.ID, run.FinishedAt, run.Status, run.Error, cursor)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to finish connector sync: %w", err)
+	}
+	return nil
+}
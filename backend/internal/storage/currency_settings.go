@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// GetCurrencySettings returns the org's currency settings with defaults
+// applied, or nil when the org does not exist.
+func (s *Storage) GetCurrencySettings(ctx context.Context, orgID int) (*organization.CurrencySettings, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'currency' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get currency settings: %w", err)
+	}
+	var cs organization.CurrencySettings
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &cs); err != nil {
+			return nil, fmt.Errorf("failed to decode currency settings: %w", err)
+		}
+	}
+	cs = cs.WithDefaults()
+	return &cs, nil
+}
+
+// UpdateCurrencySettings replaces metadata.currency with cs. Other metadata
+// keys are preserved. Amounts already stored keep their own currency.
+func (s *Storage) UpdateCurrencySettings(ctx context.Context, orgID int, cs organization.CurrencySettings) error {
+	blob, err := json.Marshal(cs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal currency settings: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{currency}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update currency settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
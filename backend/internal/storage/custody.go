@@ -0,0 +1,316 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+// ErrCustodyTooLarge is returned when an asset's chain of custody over the
+// requested range holds more than report.MaxCustodyEvents events.
+var ErrCustodyTooLarge = errors.New("too many custody events in range; narrow the range")
+
+// custodyActor is the nullable user columns of a sign-off.
+type custodyActor struct {
+	id    *int
+	name  *string
+	email *string
+}
+
+func (a custodyActor) actor() *report.CustodyActor {
+	if a.id == nil {
+		return nil
+	}
+	out := &report.CustodyActor{UserID: *a.id}
+	if a.name != nil {
+		out.Name = *a.name
+	}
+	if a.email != nil {
+		out.Email = *a.email
+	}
+	return out
+}
+
+// GetCustodyRecord gathers asset assetID's chain of custody in [from, to):
+// its stays at locations from scan history, the tags attached to and removed
+// from it, its check-outs and check-ins with their signatures, and the
+// sign-offs on its disposals and inter-org transfers. Deleted
+// assets, locations and tags are included as they were recorded. It returns
+// nil when orgID has no such asset.
+func (s *Storage) GetCustodyRecord(ctx context.Context, orgID, assetID int, from, to time.Time) (*report.CustodyRecord, error) {
+	var rec *report.CustodyRecord
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		r := report.CustodyRecord{OrgID: orgID, Events: []report.CustodyEvent{}}
+		err := tx.QueryRow(ctx, `
+			SELECT o.name, a.id, a.external_key, a.name, a.disposed_at, a.deleted_at
+			FROM trakrf.assets a
+			JOIN trakrf.organizations o ON o.id = a.org_id
+			WHERE a.id = $2 AND a.org_id = $1`, orgID, assetID).Scan(
+			&r.OrgName, &r.Asset.AssetID, &r.Asset.ExternalKey, &r.Asset.Name,
+			&r.Asset.DisposedAt, &r.Asset.DeletedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get asset: %w", err)
+		}
+
+		for _, load := range []func(context.Context, pgx.Tx, int, int, time.Time, time.Time) ([]report.CustodyEvent, error){
+			custodyStays, custodyTags, custodyAssignments, custodyDisposals, custodyTransfers,
+		} {
+			events, err := load(ctx, tx, orgID, assetID, from, to)
+			if err != nil {
+				return err
+			}
+			r.Events = append(r.Events, events...)
+			if len(r.Events) > report.MaxCustodyEvents {
+				return ErrCustodyTooLarge
+			}
+		}
+		sort.SliceStable(r.Events, func(i, j int) bool {
+			return r.Events[i].At.Before(r.Events[j].At)
+		})
+		rec = &r
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrCustodyTooLarge) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to get custody record: %w", err)
+	}
+	return rec, nil
+}
+
+// custodyStays collapses the asset's scans into stays: each run of
+// consecutive scans at one location becomes one event.
+func custodyStays(ctx context.Context, tx pgx.Tx, orgID, assetID int, from, to time.Time) ([]report.CustodyEvent, error) {
+	rows, err := tx.Query(ctx, `
+		WITH s AS (
+			SELECT timestamp, location_id,
+			       CASE WHEN location_id IS DISTINCT FROM LAG(location_id) OVER (ORDER BY timestamp)
+			            THEN 1 ELSE 0 END AS brk
+			FROM trakrf.asset_scans
+			WHERE org_id = $1 AND asset_id = $2 AND timestamp >= $3 AND timestamp < $4
+		),
+		g AS (
+			SELECT timestamp, location_id, SUM(brk) OVER (ORDER BY timestamp) AS grp FROM s
+		)
+		SELECT MIN(g.timestamp), MAX(g.timestamp), COUNT(*), g.location_id, l.external_key, l.name
+		FROM g
+		LEFT JOIN trakrf.locations l ON l.id = g.location_id AND l.org_id = $1
+		GROUP BY g.grp, g.location_id, l.external_key, l.name
+		ORDER BY MIN(g.timestamp)
+		LIMIT $5`, orgID, assetID, from, to, report.MaxCustodyEvents+1)
+	if err != nil {
+		return nil, fmt.Errorf("list stays: %w", err)
+	}
+	defer rows.Close()
+
+	events := []report.CustodyEvent{}
+	for rows.Next() {
+		e := report.CustodyEvent{Kind: report.CustodyLocation}
+		var until time.Time
+		if err := rows.Scan(&e.At, &until, &e.Scans, &e.LocationID, &e.LocationExternalKey, &e.LocationName); err != nil {
+			return nil, fmt.Errorf("scan stay: %w", err)
+		}
+		e.Until = &until
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// custodyTags returns the tag attach and removal events that fall in range.
+func custodyTags(ctx context.Context, tx pgx.Tx, orgID, assetID int, from, to time.Time) ([]report.CustodyEvent, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT type, value, assigned_at, unassigned_at
+		FROM trakrf.tag_assignments
+		WHERE org_id = $1 AND asset_id = $2
+		  AND assigned_at < $4 AND (unassigned_at IS NULL OR unassigned_at >= $3)
+		ORDER BY assigned_at, id`, orgID, assetID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list tag assignments: %w", err)
+	}
+	defer rows.Close()
+
+	events := []report.CustodyEvent{}
+	for rows.Next() {
+		var tagType, value string
+		var assigned time.Time
+		var unassigned *time.Time
+		if err := rows.Scan(&tagType, &value, &assigned, &unassigned); err != nil {
+			return nil, fmt.Errorf("scan tag assignment: %w", err)
+		}
+		if !assigned.Before(from) {
+			events = append(events, report.CustodyEvent{
+				Kind: report.CustodyTagAssigned, At: assigned, TagType: &tagType, TagValue: &value,
+			})
+		}
+		if unassigned != nil && unassigned.Before(to) {
+			events = append(events, report.CustodyEvent{
+				Kind: report.CustodyTagUnassigned, At: *unassigned, TagType: &tagType, TagValue: &value,
+			})
+		}
+	}
+	return events, rows.Err()
+}
+
+// custodyAssignments returns the asset's check-outs and check-ins, naming
+// the owner in Detail and the recording user as Actor.
+func custodyAssignments(ctx context.Context, tx pgx.Tx, orgID, assetID int, from, to time.Time) ([]report.CustodyEvent, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT a.action, a.created_at, a.note, a.signature,
+		       COALESCE(ou.name || ' <' || ou.email || '>', 'user ' || a.user_id::text),
+		       pu.id, pu.name, pu.email
+		FROM trakrf.asset_assignments a
+		LEFT JOIN trakrf.users ou ON ou.id = a.user_id
+		LEFT JOIN trakrf.users pu ON pu.id = a.performed_by
+		WHERE a.org_id = $1 AND a.asset_id = $2 AND a.created_at >= $3 AND a.created_at < $4
+		ORDER BY a.created_at, a.id`, orgID, assetID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list assignments: %w", err)
+	}
+	defer rows.Close()
+
+	events := []report.CustodyEvent{}
+	for rows.Next() {
+		var action string
+		var note, owner *string
+		var sig *asset.Signature
+		var by custodyActor
+		e := report.CustodyEvent{}
+		if err := rows.Scan(&action, &e.At, &note, &sig, &owner, &by.id, &by.name, &by.email); err != nil {
+			return nil, fmt.Errorf("scan assignment: %w", err)
+		}
+		e.Kind = report.CustodyCheckOut
+		prefix := "to "
+		if action == asset.AssignmentCheckIn {
+			e.Kind = report.CustodyCheckIn
+			prefix = "from "
+		}
+		if owner != nil {
+			detail := prefix + *owner
+			if note != nil {
+				detail += ": " + *note
+			}
+			e.Detail = &detail
+		} else {
+			e.Detail = note
+		}
+		e.Actor = by.actor()
+		if sig != nil {
+			e.Signature = &report.CustodySignature{
+				SignerName: sig.SignerName, Format: sig.Format(), SHA256: sig.Digest(), Payload: sig,
+			}
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// custodyDisposals returns the sign-offs on the asset's disposals.
+func custodyDisposals(ctx context.Context, tx pgx.Tx, orgID, assetID int, from, to time.Time) ([]report.CustodyEvent, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT d.status, d.method || ': ' || d.reason, d.decision_note,
+		       d.created_at, d.decided_at, d.executed_at,
+		       ru.id, ru.name, ru.email, du.id, du.name, du.email, eu.id, eu.name, eu.email
+		FROM trakrf.asset_disposals d
+		LEFT JOIN trakrf.users ru ON ru.id = d.requested_by
+		LEFT JOIN trakrf.users du ON du.id = d.decided_by
+		LEFT JOIN trakrf.users eu ON eu.id = d.executed_by
+		WHERE d.org_id = $1 AND d.asset_id = $2
+		ORDER BY d.created_at, d.id`, orgID, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("list disposals: %w", err)
+	}
+	defer rows.Close()
+
+	events := []report.CustodyEvent{}
+	for rows.Next() {
+		var status, reason string
+		var note *string
+		var created time.Time
+		var decided, executed *time.Time
+		var req, dec, exe custodyActor
+		if err := rows.Scan(&status, &reason, &note, &created, &decided, &executed,
+			&req.id, &req.name, &req.email, &dec.id, &dec.name, &dec.email,
+			&exe.id, &exe.name, &exe.email); err != nil {
+			return nil, fmt.Errorf("scan disposal: %w", err)
+		}
+		events = appendSignOff(events, report.CustodyDisposalRequested, &created, nil, req, &reason, from, to)
+		decision := status
+		if decision == "completed" {
+			decision = "approved"
+		}
+		if decided != nil {
+			events = appendSignOff(events, report.CustodyDisposalDecided, decided, &decision, dec, note, from, to)
+		}
+		events = appendSignOff(events, report.CustodyDisposalExecuted, executed, nil, exe, nil, from, to)
+	}
+	return events, rows.Err()
+}
+
+// custodyTransfers returns the sign-offs on transfers of the asset to or
+// from another org. asset_org_transfers has no RLS, so both sides are
+// matched on orgID explicitly.
+func custodyTransfers(ctx context.Context, tx pgx.Tx, orgID, assetID int, from, to time.Time) ([]report.CustodyEvent, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT t.status,
+		       CASE WHEN t.source_org_id = $1 THEN 'to ' || tgo.name ELSE 'from ' || so.name END,
+		       t.created_at, t.decided_at, t.executed_at,
+		       ru.id, ru.name, ru.email, du.id, du.name, du.email, eu.id, eu.name, eu.email
+		FROM trakrf.asset_org_transfers t
+		JOIN trakrf.organizations so ON so.id = t.source_org_id
+		JOIN trakrf.organizations tgo ON tgo.id = t.target_org_id
+		LEFT JOIN trakrf.users ru ON ru.id = t.requested_by
+		LEFT JOIN trakrf.users du ON du.id = t.decided_by
+		LEFT JOIN trakrf.users eu ON eu.id = t.executed_by
+		WHERE (t.source_org_id = $1 AND t.asset_id = $2)
+		   OR (t.target_org_id = $1 AND t.target_asset_id = $2)
+		ORDER BY t.created_at, t.id`, orgID, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("list transfers: %w", err)
+	}
+	defer rows.Close()
+
+	events := []report.CustodyEvent{}
+	for rows.Next() {
+		var status, counterparty string
+		var created time.Time
+		var decided, executed *time.Time
+		var req, dec, exe custodyActor
+		if err := rows.Scan(&status, &counterparty, &created, &decided, &executed,
+			&req.id, &req.name, &req.email, &dec.id, &dec.name, &dec.email,
+			&exe.id, &exe.name, &exe.email); err != nil {
+			return nil, fmt.Errorf("scan transfer: %w", err)
+		}
+		events = appendSignOff(events, report.CustodyTransferRequested, &created, nil, req, &counterparty, from, to)
+		decision := status
+		if decision == "completed" {
+			decision = "approved"
+		}
+		if decided != nil {
+			events = appendSignOff(events, report.CustodyTransferDecided, decided, &decision, dec, &counterparty, from, to)
+		}
+		events = appendSignOff(events, report.CustodyTransferExecuted, executed, nil, exe, &counterparty, from, to)
+	}
+	return events, rows.Err()
+}
+
+// appendSignOff appends a sign-off event when at is set and falls in
+// [from, to).
+func appendSignOff(events []report.CustodyEvent, kind string, at *time.Time, status *string, by custodyActor, detail *string, from, to time.Time) []report.CustodyEvent {
+	if at == nil || at.Before(from) || !at.Before(to) {
+		return events
+	}
+	return append(events, report.CustodyEvent{
+		Kind: kind, At: *at, Status: status, Actor: by.actor(), Detail: detail,
+	})
+}
@@ -0,0 +1,152 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// ErrCustomDomainTaken is returned when the hostname is already claimed by
+// this org, or already verified by another one.
+var ErrCustomDomainTaken = errors.New("hostname is already in use")
+
+const customDomainSelect = `
+	SELECT id, hostname, verification_token, verified_at, created_at
+	FROM trakrf.org_custom_domains`
+
+func scanCustomDomain(row pgx.Row) (*organization.CustomDomain, error) {
+	var d organization.CustomDomain
+	var token string
+	if err := row.Scan(&d.ID, &d.Hostname, &token, &d.VerifiedAt, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+	d.VerificationRecord = organization.CustomDomainRecordPrefix + d.Hostname
+	d.VerificationValue = organization.CustomDomainValuePrefix + token
+	return &d, nil
+}
+
+// ListCustomDomains returns the org's custom domains, oldest first.
+func (s *Storage) ListCustomDomains(ctx context.Context, orgID int) ([]organization.CustomDomain, error) {
+	rows, err := s.pool.Query(ctx, customDomainSelect+`
+		WHERE org_id = $1
+		ORDER BY created_at, id`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom domains: %w", err)
+	}
+	defer rows.Close()
+
+	domains := []organization.CustomDomain{}
+	for rows.Next() {
+		d, err := scanCustomDomain(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan custom domain: %w", err)
+		}
+		domains = append(domains, *d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate custom domains: %w", err)
+	}
+	return domains, nil
+}
+
+// GetCustomDomain returns one of the org's custom domains, or nil when none
+// matches.
+func (s *Storage) GetCustomDomain(ctx context.Context, orgID, id int) (*organization.CustomDomain, error) {
+	d, err := scanCustomDomain(s.pool.QueryRow(ctx, customDomainSelect+`
+		WHERE org_id = $1 AND id = $2`, orgID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom domain: %w", err)
+	}
+	return d, nil
+}
+
+// CreateCustomDomain claims hostname for the org, unverified.
+func (s *Storage) CreateCustomDomain(ctx context.Context, orgID int, hostname, token string) (*organization.CustomDomain, error) {
+	d, err := scanCustomDomain(s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.org_custom_domains (org_id, hostname, verification_token)
+		VALUES ($1, $2, $3)
+		RETURNING id, hostname, verification_token, verified_at, created_at`, orgID, hostname, token))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrCustomDomainTaken
+		}
+		return nil, fmt.Errorf("failed to create custom domain: %w", err)
+	}
+	return d, nil
+}
+
+// MarkCustomDomainVerified records that the domain's TXT record was found.
+// It returns ErrCustomDomainTaken when another org verified the hostname
+// first, and nil when the domain does not exist.
+func (s *Storage) MarkCustomDomainVerified(ctx context.Context, orgID, id int) (*organization.CustomDomain, error) {
+	d, err := scanCustomDomain(s.pool.QueryRow(ctx, `
+		UPDATE trakrf.org_custom_domains SET verified_at = NOW()
+		WHERE org_id = $1 AND id = $2
+		RETURNING id, hostname, verification_token, verified_at, created_at`, orgID, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrCustomDomainTaken
+		}
+		return nil, fmt.Errorf("failed to verify custom domain: %w", err)
+	}
+	return d, nil
+}
+
+// DeleteCustomDomain removes one of the org's custom domains and reports
+// whether it existed.
+func (s *Storage) DeleteCustomDomain(ctx context.Context, orgID, id int) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM trakrf.org_custom_domains WHERE org_id = $1 AND id = $2`, orgID, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete custom domain: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ResolveCustomDomain returns the live org that verified hostname, or nil.
+func (s *Storage) ResolveCustomDomain(ctx context.Context, hostname string) (*organization.HostOrg, error) {
+	h := organization.HostOrg{Hostname: hostname}
+	err := s.pool.QueryRow(ctx, `
+		SELECT o.id, o.identifier
+		FROM trakrf.org_custom_domains d
+		JOIN trakrf.organizations o ON o.id = d.org_id
+		WHERE d.hostname = $1 AND d.verified_at IS NOT NULL AND o.deleted_at IS NULL`, hostname).
+		Scan(&h.OrgID, &h.Identifier)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve custom domain: %w", err)
+	}
+	return &h, nil
+}
+
+// ResolveOrgSubdomain returns the live org whose identifier is identifier, or
+// nil. Hostname is left for the caller, which knows the tenant domain.
+func (s *Storage) ResolveOrgSubdomain(ctx context.Context, identifier string) (*organization.HostOrg, error) {
+	var h organization.HostOrg
+	err := s.pool.QueryRow(ctx, `
+		SELECT id, identifier
+		FROM trakrf.organizations
+		WHERE identifier = $1 AND deleted_at IS NULL`, identifier).
+		Scan(&h.OrgID, &h.Identifier)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve org subdomain: %w", err)
+	}
+	return &h, nil
+}
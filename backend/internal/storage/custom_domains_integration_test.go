@@ -0,0 +1,95 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestCustomDomains_VerifiedHostnameResolves(t *testing.T) {
+	store := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+	pool := store.Pool().(*pgxpool.Pool)
+
+	orgs := testutil.NewOrgFactory()
+	acme := orgs.Create(t, pool)
+	squatter := orgs.Create(t, pool)
+
+	d, err := store.CreateCustomDomain(ctx, acme, "track.acme.example", "tok1")
+	require.NoError(t, err)
+	assert.Equal(t, "_trakrf-verify.track.acme.example", d.VerificationRecord)
+	assert.Equal(t, "trakrf-verify=tok1", d.VerificationValue)
+	assert.Nil(t, d.VerifiedAt)
+
+	_, err = store.CreateCustomDomain(ctx, acme, "track.acme.example", "tok2")
+	assert.ErrorIs(t, err, storage.ErrCustomDomainTaken, "an org claims a hostname once")
+
+	// An unverified claim blocks nobody and resolves to nobody.
+	claim, err := store.CreateCustomDomain(ctx, squatter, "track.acme.example", "tok3")
+	require.NoError(t, err)
+	host, err := store.ResolveCustomDomain(ctx, "track.acme.example")
+	require.NoError(t, err)
+	assert.Nil(t, host)
+
+	verified, err := store.MarkCustomDomainVerified(ctx, acme, d.ID)
+	require.NoError(t, err)
+	require.NotNil(t, verified.VerifiedAt)
+	host, err = store.ResolveCustomDomain(ctx, "track.acme.example")
+	require.NoError(t, err)
+	require.NotNil(t, host)
+	assert.Equal(t, acme, host.OrgID)
+	assert.Equal(t, "org-001", host.Identifier)
+
+	_, err = store.MarkCustomDomainVerified(ctx, squatter, claim.ID)
+	assert.ErrorIs(t, err, storage.ErrCustomDomainTaken, "only one org may verify a hostname")
+
+	// Domains are org-scoped.
+	got, err := store.GetCustomDomain(ctx, squatter, d.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+	found, err := store.DeleteCustomDomain(ctx, squatter, d.ID)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	list, err := store.ListCustomDomains(ctx, acme)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, d.ID, list[0].ID)
+
+	found, err = store.DeleteCustomDomain(ctx, acme, d.ID)
+	require.NoError(t, err)
+	assert.True(t, found)
+	host, err = store.ResolveCustomDomain(ctx, "track.acme.example")
+	require.NoError(t, err)
+	assert.Nil(t, host)
+}
+
+func TestResolveOrgSubdomain(t *testing.T) {
+	store := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+	pool := store.Pool().(*pgxpool.Pool)
+
+	acme := testutil.NewOrgFactory().Create(t, pool)
+
+	host, err := store.ResolveOrgSubdomain(ctx, "org-001")
+	require.NoError(t, err)
+	require.NotNil(t, host)
+	assert.Equal(t, acme, host.OrgID)
+	assert.Equal(t, "org-001", host.Identifier)
+
+	host, err = store.ResolveOrgSubdomain(ctx, "nope")
+	require.NoError(t, err)
+	assert.Nil(t, host)
+
+	require.NoError(t, store.SoftDeleteOrganization(ctx, acme))
+	host, err = store.ResolveOrgSubdomain(ctx, "org-001")
+	require.NoError(t, err)
+	assert.Nil(t, host, "deleted orgs do not resolve")
+}
@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/dashboard"
+)
+
+// metricResult is the cached part of a dashboard.Result; when it was
+// computed lives in its own column.
+type metricResult struct {
+	Value  *int64            `json:"value,omitempty"`
+	Series []dashboard.Point `json:"series,omitempty"`
+}
+
+// dashboardMetricKey is the cache key of q: its JSON with defaults applied,
+// so queries that differ only by spelled-out defaults share a result.
+func dashboardMetricKey(q dashboard.Query) (string, error) {
+	b, err := json.Marshal(q.WithDefaults())
+	return string(b), err
+}
+
+// requestedAtResolution is how stale requested_at may get before a read
+// bumps it, so a busy dashboard does not write on every page load.
+const requestedAtResolution = time.Hour
+
+// DashboardMetrics answers each query, already checked against its source,
+// from the metric cache as of now, computing inline and caching the results
+// that are missing or due by dashboard.NeedsCompute; refresh is the caller's
+// request for fresh counts. Each result carries when it was computed and
+// whether that is stale.
+func (s *Storage) DashboardMetrics(ctx context.Context, orgID int, queries []dashboard.Query, now time.Time, refresh bool) ([]dashboard.Result, error) {
+	keys := make([]string, len(queries))
+	for i, q := range queries {
+		var err error
+		if keys[i], err = dashboardMetricKey(q); err != nil {
+			return nil, fmt.Errorf("failed to key dashboard query: %w", err)
+		}
+	}
+
+	out := make([]dashboard.Result, len(queries))
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		cached := make(map[string]dashboard.Result, len(keys))
+		rows, err := tx.Query(ctx, `
+			SELECT query_key, result, computed_at
+			FROM trakrf.dashboard_metrics
+			WHERE org_id = $1 AND query_key = ANY($2)`, orgID, keys)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var key string
+			var m metricResult
+			var computedAt time.Time
+			if err := rows.Scan(&key, &m, &computedAt); err != nil {
+				rows.Close()
+				return err
+			}
+			cached[key] = dashboard.Result{Value: m.Value, Series: m.Series, ComputedAt: computedAt}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for i, q := range queries {
+			if r, ok := cached[keys[i]]; ok && !dashboard.NeedsCompute(r.ComputedAt, now, refresh) {
+				out[i] = r
+				continue
+			}
+			r, err := evaluateDashboardQuery(ctx, tx, orgID, q, now)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO trakrf.dashboard_metrics (org_id, query_key, query, result, computed_at, requested_at)
+				VALUES ($1, $2, $3, $4, $5, $5)
+				ON CONFLICT (org_id, query_key) DO UPDATE
+				SET result = EXCLUDED.result, computed_at = EXCLUDED.computed_at,
+				    requested_at = EXCLUDED.requested_at`,
+				orgID, keys[i], q.WithDefaults(), metricResult{Value: r.Value, Series: r.Series}, now); err != nil {
+				return err
+			}
+			// A dashboard asking for one query twice computes it once.
+			cached[keys[i]] = r
+			out[i] = r
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.dashboard_metrics SET requested_at = $3
+			WHERE org_id = $1 AND query_key = ANY($2) AND requested_at < $4`,
+			orgID, keys, now, now.Add(-requestedAtResolution))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dashboard metrics: %w", err)
+	}
+	for i := range out {
+		out[i].Stale = now.Sub(out[i].ComputedAt) > dashboard.RefreshInterval
+	}
+	return out, nil
+}
+
+// ListOrgsWithDueDashboardMetrics returns the live orgs holding a cached
+// dashboard result the metrics job should recompute or drop at now. Runs
+// with no org context, like the other job claims.
+func (s *Storage) ListOrgsWithDueDashboardMetrics(ctx context.Context, now time.Time) ([]int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT DISTINCT m.org_id
+		FROM trakrf.dashboard_metrics m
+		JOIN trakrf.organizations o ON o.id = m.org_id
+		WHERE o.deleted_at IS NULL AND m.computed_at <= $1
+		ORDER BY m.org_id`, now.Add(-dashboard.RefreshInterval))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orgs with due dashboard metrics: %w", err)
+	}
+	defer rows.Close()
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// RefreshDashboardMetrics drops orgID's cached results that no dashboard
+// has read within dashboard.RetainFor, or whose query no longer fits the
+// source catalog, and recomputes the rest that are older than
+// dashboard.RefreshInterval. Rows another replica is refreshing are
+// skipped.
+func (s *Storage) RefreshDashboardMetrics(ctx context.Context, orgID int, now time.Time) (refreshed, dropped int, err error) {
+	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			DELETE FROM trakrf.dashboard_metrics
+			WHERE org_id = $1 AND requested_at < $2`, orgID, now.Add(-dashboard.RetainFor))
+		if err != nil {
+			return err
+		}
+		dropped = int(tag.RowsAffected())
+
+		rows, err := tx.Query(ctx, `
+			SELECT query_key, query
+			FROM trakrf.dashboard_metrics
+			WHERE org_id = $1 AND computed_at <= $2
+			ORDER BY computed_at
+			FOR UPDATE SKIP LOCKED`, orgID, now.Add(-dashboard.RefreshInterval))
+		if err != nil {
+			return err
+		}
+		type due struct {
+			key   string
+			query dashboard.Query
+		}
+		var dues []due
+		for rows.Next() {
+			var d due
+			if err := rows.Scan(&d.key, &d.query); err != nil {
+				rows.Close()
+				return err
+			}
+			dues = append(dues, d)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for _, d := range dues {
+			if d.query.Check("") != nil {
+				if _, err := tx.Exec(ctx, `
+					DELETE FROM trakrf.dashboard_metrics WHERE org_id = $1 AND query_key = $2`,
+					orgID, d.key); err != nil {
+					return err
+				}
+				dropped++
+				continue
+			}
+			r, err := evaluateDashboardQuery(ctx, tx, orgID, d.query, now)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `
+				UPDATE trakrf.dashboard_metrics SET result = $3, computed_at = $4
+				WHERE org_id = $1 AND query_key = $2`,
+				orgID, d.key, metricResult{Value: r.Value, Series: r.Series}, now); err != nil {
+				return err
+			}
+			refreshed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to refresh dashboard metrics: %w", err)
+	}
+	return refreshed, dropped, nil
+}
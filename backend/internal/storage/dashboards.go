@@ -0,0 +1,471 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/dashboard"
+)
+
+const dashboardColumns = `id, owner_user_id, name, description, shared, is_default, widgets, created_at, updated_at`
+
+func scanDashboard(row pgx.Row) (*dashboard.Dashboard, error) {
+	var d dashboard.Dashboard
+	if err := row.Scan(&d.ID, &d.OwnerUserID, &d.Name, &d.Description, &d.Shared, &d.IsDefault,
+		&d.Widgets, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if d.Widgets == nil {
+		d.Widgets = []dashboard.Widget{}
+	}
+	return &d, nil
+}
+
+// clearDefaultDashboard unsets the org's default dashboard, other than
+// keepID, so another can take its place under the one-default index.
+func clearDefaultDashboard(ctx context.Context, tx pgx.Tx, orgID, keepID int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE trakrf.dashboards SET is_default = false
+		WHERE org_id = $1 AND is_default AND id <> $2`, orgID, keepID)
+	return err
+}
+
+// CreateDashboard saves a dashboard owned by userID. A default dashboard is
+// always shared and replaces the org's previous default.
+func (s *Storage) CreateDashboard(ctx context.Context, orgID, userID int, req dashboard.CreateRequest) (*dashboard.Dashboard, error) {
+	widgets := req.Widgets
+	if widgets == nil {
+		widgets = []dashboard.Widget{}
+	}
+	var d *dashboard.Dashboard
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if req.IsDefault {
+			if err := clearDefaultDashboard(ctx, tx, orgID, 0); err != nil {
+				return err
+			}
+		}
+		var err error
+		d, err = scanDashboard(tx.QueryRow(ctx, `
+			INSERT INTO trakrf.dashboards (org_id, owner_user_id, name, description, shared, is_default, widgets)
+			VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7)
+			RETURNING `+dashboardColumns,
+			orgID, userID, req.Name, req.Description, req.Shared || req.IsDefault, req.IsDefault, widgets))
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dashboard: %w", err)
+	}
+	return d, nil
+}
+
+// ListDashboards returns the dashboards userID can see, their own and the
+// org's shared ones, the default first and the rest by name.
+func (s *Storage) ListDashboards(ctx context.Context, orgID, userID int) ([]dashboard.Dashboard, error) {
+	out := []dashboard.Dashboard{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT `+dashboardColumns+`
+			FROM trakrf.dashboards
+			WHERE org_id = $1 AND (shared OR owner_user_id = $2)
+			ORDER BY is_default DESC, name, id`, orgID, userID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			d, err := scanDashboard(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *d)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dashboards: %w", err)
+	}
+	return out, nil
+}
+
+// GetDashboard returns one dashboard userID can see, or nil.
+func (s *Storage) GetDashboard(ctx context.Context, orgID, userID, id int) (*dashboard.Dashboard, error) {
+	return s.getDashboard(ctx, orgID, `id = $2 AND (shared OR owner_user_id = $3)`, id, userID)
+}
+
+// GetDefaultDashboard returns the org's default dashboard, or nil when it
+// has none.
+func (s *Storage) GetDefaultDashboard(ctx context.Context, orgID int) (*dashboard.Dashboard, error) {
+	return s.getDashboard(ctx, orgID, `is_default`)
+}
+
+func (s *Storage) getDashboard(ctx context.Context, orgID int, where string, args ...any) (*dashboard.Dashboard, error) {
+	var d *dashboard.Dashboard
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		d, err = scanDashboard(tx.QueryRow(ctx, `
+			SELECT `+dashboardColumns+`
+			FROM trakrf.dashboards
+			WHERE org_id = $1 AND `+where, append([]any{orgID}, args...)...))
+		if errors.Is(err, pgx.ErrNoRows) {
+			d = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dashboard: %w", err)
+	}
+	return d, nil
+}
+
+// UpdateDashboard applies the non-nil fields of req, returning nil when the
+// dashboard is not in orgID. Who may change it is the caller's check.
+// Making it the default shares it and replaces the org's previous default;
+// unsharing it stops it being the default.
+func (s *Storage) UpdateDashboard(ctx context.Context, orgID, id int, req dashboard.UpdateRequest) (*dashboard.Dashboard, error) {
+	isDefault, shared := req.IsDefault, req.Shared
+	if shared != nil && !*shared && isDefault == nil {
+		isDefault = shared
+	}
+	if isDefault != nil && *isDefault {
+		shared = isDefault
+	}
+
+	setClauses := []string{}
+	args := []any{id, orgID}
+	add := func(col string, val any) {
+		args = append(args, val)
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, len(args)))
+	}
+	if req.Name != nil {
+		add("name", *req.Name)
+	}
+	if req.Description != nil {
+		add("description", *req.Description)
+	}
+	if shared != nil {
+		add("shared", *shared)
+	}
+	if isDefault != nil {
+		add("is_default", *isDefault)
+	}
+	if req.Widgets != nil {
+		widgets := *req.Widgets
+		if widgets == nil {
+			widgets = []dashboard.Widget{}
+		}
+		add("widgets", widgets)
+	}
+	if len(setClauses) == 0 {
+		return s.getDashboard(ctx, orgID, `id = $2`, id)
+	}
+
+	var d *dashboard.Dashboard
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if isDefault != nil && *isDefault {
+			if err := clearDefaultDashboard(ctx, tx, orgID, id); err != nil {
+				return err
+			}
+		}
+		var err error
+		d, err = scanDashboard(tx.QueryRow(ctx, `
+			UPDATE trakrf.dashboards SET `+strings.Join(setClauses, ", ")+`
+			WHERE id = $1 AND org_id = $2
+			RETURNING `+dashboardColumns, args...))
+		if errors.Is(err, pgx.ErrNoRows) {
+			d = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update dashboard: %w", err)
+	}
+	return d, nil
+}
+
+// DeleteDashboard deletes a dashboard, reporting whether it existed. Who
+// may delete it is the caller's check.
+func (s *Storage) DeleteDashboard(ctx context.Context, orgID, id int) (bool, error) {
+	var found bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM trakrf.dashboards WHERE id = $1 AND org_id = $2`, id, orgID)
+		found = tag.RowsAffected() > 0
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete dashboard: %w", err)
+	}
+	return found, nil
+}
+
+// dashboardSource is how a dashboard source is counted: the rows of from
+// matching where ($1 is always the org), windowed on timeCol and filtered
+// to a location on locationCol when the source supports it.
+type dashboardSource struct {
+	from        string
+	where       string
+	timeCol     string
+	locationCol string
+	groups      map[string]dashboardGroup
+}
+
+// dashboardGroup is one group_by: the key and label of each group over the
+// source's rows and the joins they need. A day group is a series, ordered
+// by day and filled with empty days; the others are ordered largest first.
+type dashboardGroup struct {
+	key, label, join string
+	day              bool
+}
+
+// locationGroup groups by the location in col, joined as g.
+func locationGroup(col string) dashboardGroup {
+	return dashboardGroup{
+		key:   "COALESCE(" + col + "::text, '')",
+		label: "COALESCE(g.name, 'Unknown')",
+		join:  "LEFT JOIN trakrf.locations g ON g.id = " + col + " AND g.deleted_at IS NULL",
+	}
+}
+
+// columnGroup groups by a text column, labelled with its value.
+func columnGroup(col, none string) dashboardGroup {
+	return dashboardGroup{key: "COALESCE(" + col + ", '')", label: "COALESCE(" + col + ", '" + none + "')"}
+}
+
+// dayGroup groups by the UTC day of col.
+func dayGroup(col string) dashboardGroup {
+	day := "to_char(" + col + " AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+	return dashboardGroup{key: day, label: day, day: true}
+}
+
+// dashboardSources holds the SQL for every source in dashboard.Sources. It
+// is fixed: a widget picks a source, a group and parameters, never SQL.
+var dashboardSources = map[string]dashboardSource{
+	dashboard.SourceAssets: {
+		from:  "trakrf.assets x",
+		where: "x.org_id = $1 AND x.deleted_at IS NULL AND x.disposed_at IS NULL",
+		groups: map[string]dashboardGroup{
+			// The current location is the latest asset_scan_latest bucket,
+			// which needs its own org filter: RLS does not cover the CAGG.
+			"location": {
+				key:   "COALESCE(g.id::text, '')",
+				label: "COALESCE(g.name, 'Unknown')",
+				join: `LEFT JOIN LATERAL (
+					SELECT location_id FROM trakrf.asset_scan_latest
+					WHERE org_id = $1 AND asset_id = x.id
+					ORDER BY last_seen DESC
+					LIMIT 1
+				) ls ON true
+				LEFT JOIN trakrf.locations g ON g.id = ls.location_id AND g.deleted_at IS NULL`,
+			},
+			"team": {
+				key:   "COALESCE(x.team_id::text, '')",
+				label: "COALESCE(g.name, 'No team')",
+				join:  "LEFT JOIN trakrf.teams g ON g.id = x.team_id",
+			},
+			"owner": {
+				key:   "COALESCE(x.owner_user_id::text, '')",
+				label: "COALESCE(g.name, 'Unassigned')",
+				join:  "LEFT JOIN trakrf.users g ON g.id = x.owner_user_id",
+			},
+			"cost_center": columnGroup("x.cost_center", "None"),
+			"status": {
+				key:   "CASE WHEN x.is_active THEN 'active' ELSE 'inactive' END",
+				label: "CASE WHEN x.is_active THEN 'Active' ELSE 'Inactive' END",
+			},
+		},
+	},
+	dashboard.SourceScans: {
+		from:        "trakrf.asset_scans x",
+		where:       "x.org_id = $1",
+		timeCol:     "x.timestamp",
+		locationCol: "x.location_id",
+		groups: map[string]dashboardGroup{
+			"day":      dayGroup("x.timestamp"),
+			"location": locationGroup("x.location_id"),
+		},
+	},
+	dashboard.SourceStockAlerts: {
+		from:        "trakrf.stock_alerts x",
+		where:       "x.org_id = $1 AND x.resolved_at IS NULL",
+		locationCol: "x.location_id",
+		groups: map[string]dashboardGroup{
+			"location": locationGroup("x.location_id"),
+		},
+	},
+	dashboard.SourceSensorAlerts: {
+		from:  "trakrf.sensor_alerts x",
+		where: "x.org_id = $1 AND x.resolved_at IS NULL",
+		groups: map[string]dashboardGroup{
+			"metric":    columnGroup("x.metric", ""),
+			"condition": columnGroup("x.condition", ""),
+		},
+	},
+	dashboard.SourceDocuments: {
+		from:  "trakrf.documents d",
+		where: "d.org_id = $1 AND " + liveDocument,
+		groups: map[string]dashboardGroup{
+			"type": columnGroup("d.type", ""),
+			"expiry_status": {
+				key: `CASE WHEN d.expires_at IS NULL THEN ''
+				           WHEN d.expires_at <= NOW() THEN 'expired'
+				           WHEN d.remind_days > 0 AND d.expires_at - make_interval(days => d.remind_days) <= NOW() THEN 'expiring'
+				           ELSE 'valid' END`,
+				label: `CASE WHEN d.expires_at IS NULL THEN 'No expiry'
+				             WHEN d.expires_at <= NOW() THEN 'Expired'
+				             WHEN d.remind_days > 0 AND d.expires_at - make_interval(days => d.remind_days) <= NOW() THEN 'Expiring'
+				             ELSE 'Valid' END`,
+			},
+		},
+	},
+	dashboard.SourceTransferOrders: {
+		from:  "trakrf.transfer_orders x",
+		where: "x.org_id = $1",
+		groups: map[string]dashboardGroup{
+			"status":      columnGroup("x.status", ""),
+			"origin":      locationGroup("x.origin_location_id"),
+			"destination": locationGroup("x.destination_location_id"),
+		},
+	},
+	dashboard.SourceDockMovements: {
+		from:        "trakrf.dock_movements x JOIN trakrf.dock_doors dd ON dd.id = x.dock_door_id",
+		where:       "x.org_id = $1",
+		timeCol:     "x.crossed_at",
+		locationCol: "dd.location_id",
+		groups: map[string]dashboardGroup{
+			"day":       dayGroup("x.crossed_at"),
+			"direction": columnGroup("x.direction", ""),
+			"dock_door": {key: "x.dock_door_id::text", label: "dd.name"},
+		},
+	},
+}
+
+// buildDashboardQuery renders q, already checked against its source and
+// with defaults applied, as SQL and its arguments.
+func buildDashboardQuery(orgID int, q dashboard.Query, since time.Time) (string, []any, dashboardGroup, error) {
+	src, ok := dashboardSources[q.Source]
+	if !ok {
+		return "", nil, dashboardGroup{}, fmt.Errorf("unknown dashboard source %q", q.Source)
+	}
+	conds := []string{src.where}
+	args := []any{orgID}
+	if src.timeCol != "" {
+		args = append(args, since)
+		conds = append(conds, fmt.Sprintf("%s >= $%d", src.timeCol, len(args)))
+	}
+	if src.locationCol != "" && q.LocationID != nil {
+		args = append(args, *q.LocationID)
+		conds = append(conds, fmt.Sprintf("%s = $%d", src.locationCol, len(args)))
+	}
+	where := strings.Join(conds, " AND ")
+
+	if q.GroupBy == "" {
+		return `SELECT COUNT(*) FROM ` + src.from + ` WHERE ` + where, args, dashboardGroup{}, nil
+	}
+	g, ok := src.groups[q.GroupBy]
+	if !ok {
+		return "", nil, dashboardGroup{}, fmt.Errorf("%s cannot be grouped by %q", q.Source, q.GroupBy)
+	}
+	sql := fmt.Sprintf(`
+		SELECT %s AS key, %s AS label, COUNT(*)
+		FROM %s
+		%s
+		WHERE %s
+		GROUP BY 1, 2`, g.key, g.label, src.from, g.join, where)
+	if g.day {
+		return sql + ` ORDER BY 1`, args, g, nil
+	}
+	args = append(args, q.Limit)
+	return sql + fmt.Sprintf(` ORDER BY 3 DESC, 2 LIMIT $%d`, len(args)), args, g, nil
+}
+
+// windowStart is the start of a window of days UTC days ending with now's.
+func windowStart(now time.Time, days int) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d-days+1, 0, 0, 0, 0, time.UTC)
+}
+
+// fillDays returns series with a zero point for every UTC day from since
+// to now that it lacks.
+func fillDays(series []dashboard.Point, since, now time.Time) []dashboard.Point {
+	have := make(map[string]int64, len(series))
+	for _, p := range series {
+		have[p.Key] = p.Value
+	}
+	out := []dashboard.Point{}
+	end := now.UTC().Format(time.DateOnly)
+	for d := since.UTC(); ; d = d.AddDate(0, 0, 1) {
+		key := d.Format(time.DateOnly)
+		out = append(out, dashboard.Point{Key: key, Label: key, Value: have[key]})
+		if key >= end {
+			return out
+		}
+	}
+}
+
+// evaluateDashboardQuery evaluates q, already checked against its source,
+// as of now. A windowed query covers its last range_days UTC days, today
+// included.
+func evaluateDashboardQuery(ctx context.Context, tx pgx.Tx, orgID int, q dashboard.Query, now time.Time) (dashboard.Result, error) {
+	q = q.WithDefaults()
+	since := windowStart(now, q.RangeDays)
+	r := dashboard.Result{ComputedAt: now}
+	sql, args, g, err := buildDashboardQuery(orgID, q, since)
+	if err != nil {
+		return r, err
+	}
+	if q.GroupBy == "" {
+		var n int64
+		if err := tx.QueryRow(ctx, sql, args...).Scan(&n); err != nil {
+			return r, err
+		}
+		r.Value = &n
+		return r, nil
+	}
+	rows, err := tx.Query(ctx, sql, args...)
+	if err != nil {
+		return r, err
+	}
+	defer rows.Close()
+	series := []dashboard.Point{}
+	for rows.Next() {
+		var p dashboard.Point
+		if err := rows.Scan(&p.Key, &p.Label, &p.Value); err != nil {
+			return r, err
+		}
+		series = append(series, p)
+	}
+	if err := rows.Err(); err != nil {
+		return r, err
+	}
+	if g.day {
+		series = fillDays(series, since, now)
+	}
+	r.Series = series
+	return r, nil
+}
+
+// EvaluateDashboardQueries evaluates each query, already checked against
+// its source, live as of now, in one transaction, bypassing the metric
+// cache.
+func (s *Storage) EvaluateDashboardQueries(ctx context.Context, orgID int, queries []dashboard.Query, now time.Time) ([]dashboard.Result, error) {
+	out := make([]dashboard.Result, len(queries))
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		for i, q := range queries {
+			var err error
+			if out[i], err = evaluateDashboardQuery(ctx, tx, orgID, q, now); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate dashboard queries: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,153 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/dashboard"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestDashboards_VisibilityAndDefault(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	var alice, bob int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('alice', 'alice@x', 'stub') RETURNING id`,
+	).Scan(&alice))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('bob', 'bob@x', 'stub') RETURNING id`,
+	).Scan(&bob))
+
+	widgets := []dashboard.Widget{{
+		ID: "assets", Title: "Assets", Visualization: dashboard.VisualizationNumber,
+		Query: dashboard.Query{Source: dashboard.SourceAssets}, Layout: dashboard.Layout{W: 3, H: 2},
+	}}
+	private, err := store.CreateDashboard(ctx, orgID, alice, dashboard.CreateRequest{Name: "Mine", Widgets: widgets})
+	require.NoError(t, err)
+	require.Len(t, private.Widgets, 1)
+	assert.Equal(t, dashboard.SourceAssets, private.Widgets[0].Query.Source)
+	first, err := store.CreateDashboard(ctx, orgID, alice, dashboard.CreateRequest{Name: "Ops", IsDefault: true})
+	require.NoError(t, err)
+	assert.True(t, first.Shared, "a default dashboard is shared")
+	assert.Empty(t, first.Widgets)
+
+	got, err := store.GetDashboard(ctx, orgID, bob, private.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got, "bob cannot see alice's private dashboard")
+	list, err := store.ListDashboards(ctx, orgID, bob)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, first.ID, list[0].ID)
+
+	second, err := store.CreateDashboard(ctx, orgID, bob, dashboard.CreateRequest{Name: "Floor", IsDefault: true})
+	require.NoError(t, err)
+	def, err := store.GetDefaultDashboard(ctx, orgID)
+	require.NoError(t, err)
+	require.NotNil(t, def)
+	assert.Equal(t, second.ID, def.ID, "the new default replaces the old")
+
+	unshare := false
+	updated, err := store.UpdateDashboard(ctx, orgID, second.ID, dashboard.UpdateRequest{Shared: &unshare})
+	require.NoError(t, err)
+	assert.False(t, updated.IsDefault, "unsharing drops the default")
+	def, err = store.GetDefaultDashboard(ctx, orgID)
+	require.NoError(t, err)
+	assert.Nil(t, def)
+
+	found, err := store.DeleteDashboard(ctx, orgID, private.ID)
+	require.NoError(t, err)
+	assert.True(t, found)
+	found, err = store.DeleteDashboard(ctx, orgID, private.ID)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestDashboards_EvaluateQueries(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	testutil.CreateTestAsset(t, pool, orgID, "DASH-1")
+	testutil.CreateTestAsset(t, pool, orgID, "DASH-2")
+
+	now := time.Now()
+	results, err := store.EvaluateDashboardQueries(ctx, orgID, []dashboard.Query{
+		{Source: dashboard.SourceAssets},
+		{Source: dashboard.SourceAssets, GroupBy: "status"},
+		{Source: dashboard.SourceScans, GroupBy: "day", RangeDays: 3},
+		{Source: dashboard.SourceTransferOrders, GroupBy: "status"},
+		{Source: dashboard.SourceDocuments, GroupBy: "expiry_status"},
+		{Source: dashboard.SourceDockMovements, GroupBy: "dock_door"},
+		{Source: dashboard.SourceSensorAlerts},
+		{Source: dashboard.SourceStockAlerts, GroupBy: "location"},
+	}, now)
+	require.NoError(t, err)
+	require.Len(t, results, 8)
+
+	require.NotNil(t, results[0].Value)
+	assert.Equal(t, int64(2), *results[0].Value)
+	require.Len(t, results[1].Series, 1)
+	assert.Equal(t, dashboard.Point{Key: "active", Label: "Active", Value: 2}, results[1].Series[0])
+	require.Len(t, results[2].Series, 3, "a day series covers the whole window")
+	assert.Equal(t, now.UTC().Format(time.DateOnly), results[2].Series[2].Key)
+	assert.Empty(t, results[3].Series)
+	require.NotNil(t, results[6].Value)
+	assert.Zero(t, *results[6].Value)
+}
+
+func TestDashboardMetrics_CacheAndRefresh(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	testutil.CreateTestAsset(t, pool, orgID, "METRIC-1")
+	assets := []dashboard.Query{{Source: dashboard.SourceAssets}}
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	got, err := store.DashboardMetrics(ctx, orgID, assets, now, false)
+	require.NoError(t, err)
+	require.NotNil(t, got[0].Value)
+	assert.Equal(t, int64(1), *got[0].Value)
+	assert.True(t, now.Equal(got[0].ComputedAt))
+	assert.False(t, got[0].Stale)
+
+	testutil.CreateTestAsset(t, pool, orgID, "METRIC-2")
+	later := now.Add(10 * time.Minute)
+	got, err = store.DashboardMetrics(ctx, orgID, assets, later, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), *got[0].Value, "served from the cache")
+	assert.True(t, got[0].Stale)
+
+	orgs, err := store.ListOrgsWithDueDashboardMetrics(ctx, later)
+	require.NoError(t, err)
+	assert.Contains(t, orgs, orgID)
+	refreshed, dropped, err := store.RefreshDashboardMetrics(ctx, orgID, later)
+	require.NoError(t, err)
+	assert.Equal(t, 1, refreshed)
+	assert.Zero(t, dropped)
+
+	got, err = store.DashboardMetrics(ctx, orgID, assets, later, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), *got[0].Value)
+	assert.True(t, later.Equal(got[0].ComputedAt), "a refresh right after the job reuses its result")
+
+	_, dropped, err = store.RefreshDashboardMetrics(ctx, orgID, later.Add(dashboard.RetainFor+time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, dropped, "results nobody reads are dropped")
+}
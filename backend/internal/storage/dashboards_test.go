@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/models/dashboard"
+)
+
+// TestDashboardSources_MatchCatalog keeps the SQL in step with the source
+// catalog the API validates against.
+func TestDashboardSources_MatchCatalog(t *testing.T) {
+	if len(dashboardSources) != len(dashboard.Sources) {
+		t.Errorf("%d SQL sources for %d catalog sources", len(dashboardSources), len(dashboard.Sources))
+	}
+	for _, src := range dashboard.Sources {
+		sql, ok := dashboardSources[src.Name]
+		if !ok {
+			t.Errorf("%s: no SQL", src.Name)
+			continue
+		}
+		if (sql.timeCol != "") != src.Windowed {
+			t.Errorf("%s: windowed = %v, timeCol = %q", src.Name, src.Windowed, sql.timeCol)
+		}
+		if (sql.locationCol != "") != src.LocationFilter {
+			t.Errorf("%s: location filter = %v, locationCol = %q", src.Name, src.LocationFilter, sql.locationCol)
+		}
+		if len(sql.groups) != len(src.GroupBy) {
+			t.Errorf("%s: %d SQL groups for %d catalog groups", src.Name, len(sql.groups), len(src.GroupBy))
+		}
+		for _, g := range src.GroupBy {
+			if _, ok := sql.groups[g]; !ok {
+				t.Errorf("%s: no SQL for group %s", src.Name, g)
+			}
+		}
+	}
+}
+
+func TestBuildDashboardQuery(t *testing.T) {
+	since := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	loc := 3
+
+	sql, args, _, err := buildDashboardQuery(42, dashboard.Query{Source: dashboard.SourceAssets}.WithDefaults(), since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sql, "SELECT COUNT(*) FROM trakrf.assets x") || len(args) != 1 {
+		t.Errorf("ungrouped: sql = %s, args = %v", sql, args)
+	}
+
+	sql, args, g, err := buildDashboardQuery(42, dashboard.Query{Source: dashboard.SourceScans, GroupBy: "day", LocationID: &loc}.WithDefaults(), since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.day || !strings.Contains(sql, "x.timestamp >= $2") || !strings.Contains(sql, "x.location_id = $3") ||
+		strings.Contains(sql, "LIMIT") || len(args) != 3 {
+		t.Errorf("day series: sql = %s, args = %v", sql, args)
+	}
+
+	sql, args, _, err = buildDashboardQuery(42, dashboard.Query{Source: dashboard.SourceTransferOrders, GroupBy: "status"}.WithDefaults(), since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sql, "LIMIT $2") || len(args) != 2 || args[1] != dashboard.DefaultLimit {
+		t.Errorf("grouped: sql = %s, args = %v", sql, args)
+	}
+
+	if _, _, _, err := buildDashboardQuery(42, dashboard.Query{Source: dashboard.SourceAssets, GroupBy: "day"}, since); err == nil {
+		t.Error("want an error for a group the source lacks")
+	}
+}
+
+func TestFillDays(t *testing.T) {
+	now := time.Date(2026, 5, 3, 15, 0, 0, 0, time.UTC)
+	since := windowStart(now, 3)
+	if want := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC); !since.Equal(want) {
+		t.Fatalf("windowStart = %v, want %v", since, want)
+	}
+	got := fillDays([]dashboard.Point{{Key: "2026-05-02", Label: "2026-05-02", Value: 7}}, since, now)
+	want := []int64{0, 7, 0}
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+	for i, p := range got {
+		if p.Value != want[i] {
+			t.Errorf("day %s = %d, want %d", p.Key, p.Value, want[i])
+		}
+	}
+	if got[0].Key != "2026-05-01" || got[2].Key != "2026-05-03" {
+		t.Errorf("keys = %s..%s", got[0].Key, got[2].Key)
+	}
+}
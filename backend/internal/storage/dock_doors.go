@@ -0,0 +1,347 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/dockdoor"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+// Dock door write errors, mapped to 400s by the handler.
+var (
+	ErrDockDoorLocationNotFound  = errors.New("location not found")
+	ErrDockDoorScanPointNotFound = errors.New("scan point not found")
+	ErrDockDoorScanPointInUse    = errors.New("scan point already belongs to a dock door")
+	ErrDockDoorSameScanPoint     = errors.New("outer and inner scan points must differ")
+)
+
+const dockDoorColumns = `id, org_id, location_id, name, outer_scan_point_id, inner_scan_point_id,
+	window_seconds, is_active, created_at, updated_at`
+
+func scanDockDoor(row pgx.Row) (*dockdoor.Door, error) {
+	var d dockdoor.Door
+	if err := row.Scan(&d.ID, &d.OrgID, &d.LocationID, &d.Name, &d.OuterScanPointID, &d.InnerScanPointID,
+		&d.WindowSeconds, &d.IsActive, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// isDockDoorRejection reports whether err is one of the dock door write
+// errors, returned unwrapped.
+func isDockDoorRejection(err error) bool {
+	return errors.Is(err, ErrDockDoorLocationNotFound) || errors.Is(err, ErrDockDoorScanPointNotFound) ||
+		errors.Is(err, ErrDockDoorScanPointInUse) || errors.Is(err, ErrDockDoorSameScanPoint)
+}
+
+// checkDockDoorRefs verifies, on tx, that locationID is a live location of
+// the org and that outer and inner are two of its live scan points belonging
+// to no dock door other than doorID (0 for a new door).
+func checkDockDoorRefs(ctx context.Context, tx pgx.Tx, orgID, doorID, locationID, outer, inner int) error {
+	if outer == inner {
+		return ErrDockDoorSameScanPoint
+	}
+	var locOK bool
+	var points, taken int
+	if err := tx.QueryRow(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM trakrf.locations
+			        WHERE id = $2 AND org_id = $1 AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM trakrf.scan_points
+			 WHERE id IN ($3, $4) AND org_id = $1 AND deleted_at IS NULL),
+			(SELECT COUNT(*) FROM trakrf.dock_doors
+			 WHERE org_id = $1 AND id <> $5
+			   AND (outer_scan_point_id IN ($3, $4) OR inner_scan_point_id IN ($3, $4)))`,
+		orgID, locationID, outer, inner, doorID).Scan(&locOK, &points, &taken); err != nil {
+		return err
+	}
+	switch {
+	case !locOK:
+		return ErrDockDoorLocationNotFound
+	case points < 2:
+		return ErrDockDoorScanPointNotFound
+	case taken > 0:
+		return ErrDockDoorScanPointInUse
+	}
+	return nil
+}
+
+// dockDoorWriteError maps a constraint violation that slipped past
+// checkDockDoorRefs (a concurrent write) to its sentinel.
+func dockDoorWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" &&
+		strings.HasPrefix(pgErr.ConstraintName, "idx_dock_doors_") {
+		return ErrDockDoorScanPointInUse
+	}
+	return err
+}
+
+// CreateDockDoor registers a door pairing two of the org's scan points at
+// one of its locations. The window defaults to
+// dockdoor.DefaultWindowSeconds.
+func (s *Storage) CreateDockDoor(ctx context.Context, orgID int, req dockdoor.CreateDoorRequest) (*dockdoor.Door, error) {
+	window := dockdoor.DefaultWindowSeconds
+	if req.WindowSeconds != nil {
+		window = *req.WindowSeconds
+	}
+	var d *dockdoor.Door
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if err := checkDockDoorRefs(ctx, tx, orgID, 0, req.LocationID, req.OuterScanPointID, req.InnerScanPointID); err != nil {
+			return err
+		}
+		var err error
+		d, err = scanDockDoor(tx.QueryRow(ctx, `
+			INSERT INTO trakrf.dock_doors
+			  (org_id, location_id, name, outer_scan_point_id, inner_scan_point_id, window_seconds)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING `+dockDoorColumns,
+			orgID, req.LocationID, req.Name, req.OuterScanPointID, req.InnerScanPointID, window))
+		return dockDoorWriteError(err)
+	})
+	if err != nil {
+		if isDockDoorRejection(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create dock door: %w", err)
+	}
+	return d, nil
+}
+
+// GetDockDoor returns one door, or nil when it is not in orgID.
+func (s *Storage) GetDockDoor(ctx context.Context, orgID, id int) (*dockdoor.Door, error) {
+	var d *dockdoor.Door
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		d, err = scanDockDoor(tx.QueryRow(ctx, `
+			SELECT `+dockDoorColumns+`
+			FROM trakrf.dock_doors
+			WHERE id = $1 AND org_id = $2`, id, orgID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			d = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dock door: %w", err)
+	}
+	return d, nil
+}
+
+// ListDockDoors returns the org's doors by name, only those at locationID
+// when it is non-zero.
+func (s *Storage) ListDockDoors(ctx context.Context, orgID, locationID int) ([]dockdoor.Door, error) {
+	out := []dockdoor.Door{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT `+dockDoorColumns+`
+			FROM trakrf.dock_doors
+			WHERE org_id = $1 AND ($2 = 0 OR location_id = $2)
+			ORDER BY name, id`, orgID, locationID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			d, err := scanDockDoor(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *d)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dock doors: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateDockDoor applies a partial update. It returns nil when the door is
+// not in orgID, and one of the dock door write errors when the result would
+// reference another org's location or scan points, or share a scan point
+// with another door.
+func (s *Storage) UpdateDockDoor(ctx context.Context, orgID, id int, req dockdoor.UpdateDoorRequest) (*dockdoor.Door, error) {
+	setClauses := []string{}
+	args := []any{id, orgID}
+	add := func(col string, val any) {
+		args = append(args, val)
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, len(args)))
+	}
+	if req.LocationID != nil {
+		add("location_id", *req.LocationID)
+	}
+	if req.Name != nil {
+		add("name", *req.Name)
+	}
+	if req.OuterScanPointID != nil {
+		add("outer_scan_point_id", *req.OuterScanPointID)
+	}
+	if req.InnerScanPointID != nil {
+		add("inner_scan_point_id", *req.InnerScanPointID)
+	}
+	if req.WindowSeconds != nil {
+		add("window_seconds", *req.WindowSeconds)
+	}
+	if req.IsActive != nil {
+		add("is_active", *req.IsActive)
+	}
+	if len(setClauses) == 0 {
+		return s.GetDockDoor(ctx, orgID, id)
+	}
+
+	var d *dockdoor.Door
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		cur, err := scanDockDoor(tx.QueryRow(ctx, `
+			SELECT `+dockDoorColumns+`
+			FROM trakrf.dock_doors
+			WHERE id = $1 AND org_id = $2
+			FOR UPDATE`, id, orgID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if req.LocationID != nil || req.OuterScanPointID != nil || req.InnerScanPointID != nil {
+			loc, outer, inner := cur.LocationID, cur.OuterScanPointID, cur.InnerScanPointID
+			if req.LocationID != nil {
+				loc = *req.LocationID
+			}
+			if req.OuterScanPointID != nil {
+				outer = *req.OuterScanPointID
+			}
+			if req.InnerScanPointID != nil {
+				inner = *req.InnerScanPointID
+			}
+			if err := checkDockDoorRefs(ctx, tx, orgID, id, loc, outer, inner); err != nil {
+				return err
+			}
+		}
+		d, err = scanDockDoor(tx.QueryRow(ctx, fmt.Sprintf(`
+			UPDATE trakrf.dock_doors SET %s
+			WHERE id = $1 AND org_id = $2
+			RETURNING `+dockDoorColumns, strings.Join(setClauses, ", ")), args...))
+		return dockDoorWriteError(err)
+	})
+	if err != nil {
+		if isDockDoorRejection(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update dock door: %w", err)
+	}
+	return d, nil
+}
+
+// DeleteDockDoor removes a door and its movements. It returns false when
+// the door is not in orgID.
+func (s *Storage) DeleteDockDoor(ctx context.Context, orgID, id int) (bool, error) {
+	var n int64
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM trakrf.dock_doors WHERE id = $1 AND org_id = $2`, id, orgID)
+		n = tag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete dock door: %w", err)
+	}
+	return n > 0, nil
+}
+
+// RecordDockMovement writes one crossing detected by the direction engine
+// and raises dock_movement.recorded for it.
+func (s *Storage) RecordDockMovement(ctx context.Context, orgID int, m dockdoor.Movement) (*dockdoor.Movement, error) {
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var tagScanID *int64
+		if m.TagScanID != 0 {
+			tagScanID = &m.TagScanID
+		}
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.dock_movements
+			  (org_id, dock_door_id, asset_id, direction, first_read_at, crossed_at, tag_scan_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id`,
+			orgID, m.DockDoorID, m.AssetID, m.Direction, m.FirstReadAt, m.CrossedAt, tagScanID).Scan(&m.ID); err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.DockMovementRecorded, orgID, m.ID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record dock movement: %w", err)
+	}
+	return &m, nil
+}
+
+// dockMovementsWhere is the WHERE clause shared by the dock movement report's
+// count and list queries; $1-$5 are org, from, to, door and location.
+const dockMovementsWhere = `
+	WHERE m.org_id = $1 AND m.crossed_at >= $2 AND m.crossed_at < $3
+	  AND ($4 = 0 OR m.dock_door_id = $4)
+	  AND ($5 = 0 OR d.location_id = $5)`
+
+// ListDockMovements returns the crossings selected by filter, newest first,
+// with the total matching filter and the counts by direction over the same
+// doors and window. When the org anonymizes personnel, person-assets come
+// back under report.MaskPerson's pseudonym.
+func (s *Storage) ListDockMovements(ctx context.Context, orgID int, filter report.DockMovementFilter) ([]report.DockMovementItem, report.DockMovementCounts, int, error) {
+	items := []report.DockMovementItem{}
+	var counts report.DockMovementCounts
+	total := 0
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		anonymize, err := personnelAnonymized(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FILTER (WHERE $6 = '' OR m.direction = $6),
+			       COUNT(*) FILTER (WHERE m.direction = 'inbound'),
+			       COUNT(*) FILTER (WHERE m.direction = 'outbound')
+			FROM trakrf.dock_movements m
+			JOIN trakrf.dock_doors d ON d.id = m.dock_door_id`+dockMovementsWhere,
+			orgID, filter.From, filter.To, filter.DockDoorID, filter.LocationID, filter.Direction,
+		).Scan(&total, &counts.Inbound, &counts.Outbound); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, `
+			SELECT m.id, m.dock_door_id, d.name, d.location_id, m.asset_id, a.external_key, a.name,
+			       m.direction, m.first_read_at, m.crossed_at, `+maskedPerson("$9")+`
+			FROM trakrf.dock_movements m
+			JOIN trakrf.dock_doors d ON d.id = m.dock_door_id
+			JOIN trakrf.assets a ON a.id = m.asset_id`+dockMovementsWhere+`
+			  AND ($6 = '' OR m.direction = $6)
+			ORDER BY m.crossed_at DESC, m.id
+			LIMIT $7 OFFSET $8`,
+			orgID, filter.From, filter.To, filter.DockDoorID, filter.LocationID, filter.Direction,
+			filter.Limit, filter.Offset, anonymize)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var item report.DockMovementItem
+			var masked bool
+			if err := rows.Scan(&item.ID, &item.DockDoorID, &item.DockDoorName, &item.LocationID,
+				&item.AssetID, &item.AssetExternalKey, &item.AssetName, &item.Direction,
+				&item.FirstReadAt, &item.CrossedAt, &masked); err != nil {
+				return err
+			}
+			if masked {
+				item.AssetExternalKey, item.AssetName = report.MaskPerson(item.AssetID)
+			}
+			items = append(items, item)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, report.DockMovementCounts{}, 0, fmt.Errorf("failed to list dock movements: %w", err)
+	}
+	return items, counts, total, nil
+}
@@ -0,0 +1,115 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/dockdoor"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/models/scandevice"
+	"github.com/trakrf/platform/backend/internal/models/scanpoint"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestDockDoors_PairingAndMovements(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	ctx := context.Background()
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	store := db.Store
+
+	dev, err := store.CreateScanDevice(ctx, orgID, scandevice.CreateScanDeviceRequest{
+		Name: "Dock reader", Type: scandevice.DeviceTypeCS463,
+	})
+	require.NoError(t, err)
+	points, err := store.ListScanPointsByDevice(ctx, orgID, dev.ID)
+	require.NoError(t, err)
+	require.Len(t, points, 1)
+	outer := points[0].ID
+	port := 2
+	in, err := store.CreateScanPoint(ctx, orgID, dev.ID, scanpoint.CreateScanPointRequest{Name: "Inside", AntennaPort: &port})
+	require.NoError(t, err)
+	port = 3
+	spare, err := store.CreateScanPoint(ctx, orgID, dev.ID, scanpoint.CreateScanPointRequest{Name: "Spare", AntennaPort: &port})
+	require.NoError(t, err)
+	dock, err := store.CreateLocationWithTags(ctx, orgID, location.CreateLocationWithTagsRequest{
+		CreateLocationRequest: location.CreateLocationRequest{Name: "Dock 3", ExternalKey: "dock-3"},
+	})
+	require.NoError(t, err)
+
+	door, err := store.CreateDockDoor(ctx, orgID, dockdoor.CreateDoorRequest{
+		LocationID: dock.ID, Name: "Dock 3", OuterScanPointID: outer, InnerScanPointID: in.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, dockdoor.DefaultWindowSeconds, door.WindowSeconds)
+	assert.True(t, door.IsActive)
+
+	// A scan point belongs to one door, on either side.
+	_, err = store.CreateDockDoor(ctx, orgID, dockdoor.CreateDoorRequest{
+		LocationID: dock.ID, Name: "Dock 4", OuterScanPointID: spare.ID, InnerScanPointID: outer,
+	})
+	assert.ErrorIs(t, err, storage.ErrDockDoorScanPointInUse)
+	_, err = store.CreateDockDoor(ctx, orgID, dockdoor.CreateDoorRequest{
+		LocationID: dock.ID, Name: "Dock 4", OuterScanPointID: spare.ID, InnerScanPointID: 999999999,
+	})
+	assert.ErrorIs(t, err, storage.ErrDockDoorScanPointNotFound)
+	_, err = store.UpdateDockDoor(ctx, orgID, door.ID, dockdoor.UpdateDoorRequest{InnerScanPointID: &outer})
+	assert.ErrorIs(t, err, storage.ErrDockDoorSameScanPoint)
+
+	// Moving the inner side to the spare antenna is fine: the door may keep
+	// its own scan points.
+	window := 20
+	door, err = store.UpdateDockDoor(ctx, orgID, door.ID, dockdoor.UpdateDoorRequest{
+		InnerScanPointID: &spare.ID, WindowSeconds: &window,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, spare.ID, door.InnerScanPointID)
+	assert.Equal(t, 20, door.WindowSeconds)
+
+	pallet, err := store.CreateAssetWithTags(ctx, keyedAsset(orgID, "PAL-1", "Pallet 1"))
+	require.NoError(t, err)
+	t0 := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	for i, dir := range []string{dockdoor.DirectionInbound, dockdoor.DirectionOutbound, dockdoor.DirectionOutbound} {
+		at := t0.Add(time.Duration(i) * time.Hour)
+		m, err := store.RecordDockMovement(ctx, orgID, dockdoor.Movement{
+			DockDoorID: door.ID, AssetID: pallet.ID, Direction: dir,
+			FirstReadAt: at.Add(-2 * time.Second), CrossedAt: at,
+		})
+		require.NoError(t, err)
+		require.NotZero(t, m.ID)
+	}
+
+	items, counts, total, err := store.ListDockMovements(ctx, orgID, report.DockMovementFilter{
+		From: t0, To: t0.Add(24 * time.Hour), Direction: dockdoor.DirectionOutbound, Limit: 50,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, report.DockMovementCounts{Inbound: 1, Outbound: 2}, counts)
+	require.Len(t, items, 2)
+	assert.Equal(t, t0.Add(2*time.Hour), items[0].CrossedAt.UTC(), "newest first")
+	assert.Equal(t, "Dock 3", items[0].DockDoorName)
+	assert.Equal(t, dock.ID, items[0].LocationID)
+	assert.Equal(t, "PAL-1", items[0].AssetExternalKey)
+
+	_, _, total, err = store.ListDockMovements(ctx, orgID, report.DockMovementFilter{
+		From: t0, To: t0.Add(24 * time.Hour), LocationID: dock.ID + 1, Limit: 50,
+	})
+	require.NoError(t, err)
+	assert.Zero(t, total, "no doors at another location")
+
+	ok, err := store.DeleteDockDoor(ctx, orgID, door.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	_, counts, _, err = store.ListDockMovements(ctx, orgID, report.DockMovementFilter{
+		From: t0, To: t0.Add(24 * time.Hour), Limit: 50,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, report.DockMovementCounts{}, counts, "movements go with their door")
+}
@@ -0,0 +1,400 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/document"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+// Document write errors, mapped to 400s by the handler.
+var (
+	ErrDocumentAssetNotFound = errors.New("asset not found")
+	ErrDocumentDates         = errors.New("expires_at must be after issued_at")
+)
+
+const documentColumns = `id, asset_id, type, title, reference, issuer, issued_at, expires_at, remind_days,
+	notes, file_url, file_content_type, file_size_bytes, created_by, created_at, updated_at`
+
+// liveDocument limits a query on trakrf.documents d to org-level documents
+// and those of assets that are not soft-deleted.
+const liveDocument = `(d.asset_id IS NULL OR EXISTS (
+	SELECT 1 FROM trakrf.assets a WHERE a.id = d.asset_id AND a.deleted_at IS NULL))`
+
+func scanDocument(row pgx.Row) (*document.Document, error) {
+	var d document.Document
+	var fileURL, fileType *string
+	var fileSize *int
+	if err := row.Scan(&d.ID, &d.AssetID, &d.Type, &d.Title, &d.Reference, &d.Issuer, &d.IssuedAt,
+		&d.ExpiresAt, &d.RemindDays, &d.Notes, &fileURL, &fileType, &fileSize,
+		&d.CreatedBy, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if fileURL != nil {
+		d.File = &document.File{URL: *fileURL}
+		if fileType != nil {
+			d.File.ContentType = *fileType
+		}
+		if fileSize != nil {
+			d.File.SizeBytes = *fileSize
+		}
+	}
+	d.ExpiryStatus = document.ExpiryStatus(d.ExpiresAt, d.RemindDays, time.Now())
+	return &d, nil
+}
+
+// documentWriteError maps the issued/expiry date check to its sentinel.
+func documentWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23514" && pgErr.ConstraintName == "documents_expiry_after_issue" {
+		return ErrDocumentDates
+	}
+	return err
+}
+
+// CreateDocument files a document against one of the org's live assets, or
+// against the org when req.AssetID is nil. remind_days defaults to
+// document.DefaultRemindDays.
+func (s *Storage) CreateDocument(ctx context.Context, orgID, userID int, req document.CreateRequest) (*document.Document, error) {
+	remind := document.DefaultRemindDays
+	if req.RemindDays != nil {
+		remind = *req.RemindDays
+	}
+	var d *document.Document
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if req.AssetID != nil {
+			var ok bool
+			if err := tx.QueryRow(ctx, `
+				SELECT EXISTS (SELECT 1 FROM trakrf.assets
+				               WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL)`,
+				*req.AssetID, orgID).Scan(&ok); err != nil {
+				return err
+			}
+			if !ok {
+				return ErrDocumentAssetNotFound
+			}
+		}
+		var err error
+		d, err = scanDocument(tx.QueryRow(ctx, `
+			INSERT INTO trakrf.documents
+			  (org_id, asset_id, type, title, reference, issuer, issued_at, expires_at, remind_days, notes, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, NULLIF($11, 0))
+			RETURNING `+documentColumns,
+			orgID, req.AssetID, req.Type, req.Title, req.Reference, req.Issuer, req.IssuedAt, req.ExpiresAt,
+			remind, req.Notes, userID))
+		return documentWriteError(err)
+	})
+	if err != nil {
+		if errors.Is(err, ErrDocumentAssetNotFound) || errors.Is(err, ErrDocumentDates) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+	return d, nil
+}
+
+// GetDocument returns one document, or nil when it is not in orgID or
+// belongs to a deleted asset.
+func (s *Storage) GetDocument(ctx context.Context, orgID, id int) (*document.Document, error) {
+	var d *document.Document
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		d, err = scanDocument(tx.QueryRow(ctx, `
+			SELECT `+documentColumns+`
+			FROM trakrf.documents d
+			WHERE d.id = $1 AND d.org_id = $2 AND `+liveDocument, id, orgID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			d = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get document: %w", err)
+	}
+	return d, nil
+}
+
+// ListDocuments returns the documents selected by f, soonest expiry first
+// and those without an expiry date last.
+func (s *Storage) ListDocuments(ctx context.Context, orgID int, f document.ListFilter) ([]document.Document, error) {
+	out := []document.Document{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT `+documentColumns+`
+			FROM trakrf.documents d
+			WHERE d.org_id = $1 AND `+liveDocument+`
+			  AND ($2 = 0 OR d.asset_id = $2)
+			  AND (NOT $3 OR d.asset_id IS NULL)
+			  AND ($4 = '' OR d.type = $4)
+			ORDER BY d.expires_at NULLS LAST, d.id
+			LIMIT $5`, orgID, f.AssetID, f.OrgOnly, f.Type, f.Limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			d, err := scanDocument(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *d)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list documents: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateDocument applies a partial update, clearing the expiry notice
+// stamps when the expiry date or reminder window changes so the document
+// alerts again. It returns nil when the document is not in orgID.
+func (s *Storage) UpdateDocument(ctx context.Context, orgID, id int, req document.UpdateRequest) (*document.Document, error) {
+	setClauses := []string{}
+	args := []any{id, orgID}
+	add := func(col string, val any) {
+		args = append(args, val)
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, len(args)))
+	}
+	if req.Type != nil {
+		add("type", *req.Type)
+	}
+	if req.Title != nil {
+		add("title", *req.Title)
+	}
+	if req.Reference != nil {
+		add("reference", *req.Reference)
+	}
+	if req.Issuer != nil {
+		add("issuer", *req.Issuer)
+	}
+	if req.IssuedAt != nil {
+		add("issued_at", *req.IssuedAt)
+	}
+	if req.ExpiresAt != nil {
+		add("expires_at", *req.ExpiresAt)
+	}
+	if req.RemindDays != nil {
+		add("remind_days", *req.RemindDays)
+	}
+	if req.Notes != nil {
+		add("notes", *req.Notes)
+	}
+	if len(setClauses) == 0 {
+		return s.GetDocument(ctx, orgID, id)
+	}
+	if req.ExpiresAt != nil || req.RemindDays != nil {
+		setClauses = append(setClauses, "expiring_notified_at = NULL", "expired_notified_at = NULL")
+	}
+
+	var d *document.Document
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		d, err = scanDocument(tx.QueryRow(ctx, fmt.Sprintf(`
+			UPDATE trakrf.documents d SET %s
+			WHERE d.id = $1 AND d.org_id = $2 AND `+liveDocument+`
+			RETURNING `+documentColumns, strings.Join(setClauses, ", ")), args...))
+		if errors.Is(err, pgx.ErrNoRows) {
+			d = nil
+			return nil
+		}
+		return documentWriteError(err)
+	})
+	if err != nil {
+		if errors.Is(err, ErrDocumentDates) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update document: %w", err)
+	}
+	return d, nil
+}
+
+// SetDocumentFile records the scanned copy uploaded for a document and
+// returns the document with the URL of the file it replaced (nil when it had
+// none), so the caller can remove the old object. It returns a nil document
+// when the document is not in orgID.
+func (s *Storage) SetDocumentFile(ctx context.Context, orgID, id int, file document.File) (*document.Document, *string, error) {
+	var d *document.Document
+	var previous *string
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT d.file_url FROM trakrf.documents d
+			WHERE d.id = $1 AND d.org_id = $2 AND `+liveDocument+`
+			FOR UPDATE`, id, orgID).Scan(&previous)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		d, err = scanDocument(tx.QueryRow(ctx, `
+			UPDATE trakrf.documents
+			SET file_url = $3, file_content_type = $4, file_size_bytes = $5
+			WHERE id = $1 AND org_id = $2
+			RETURNING `+documentColumns, id, orgID, file.URL, file.ContentType, file.SizeBytes))
+		return err
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to set document file: %w", err)
+	}
+	return d, previous, nil
+}
+
+// DeleteDocument deletes a document and returns the URL of the file it held
+// (nil when none) so the caller can remove the object. found is false when
+// the document is not in orgID.
+func (s *Storage) DeleteDocument(ctx context.Context, orgID, id int) (found bool, fileURL *string, err error) {
+	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			DELETE FROM trakrf.documents
+			WHERE id = $1 AND org_id = $2
+			RETURNING file_url`, id, orgID).Scan(&fileURL)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		found = err == nil
+		return err
+	})
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to delete document: %w", err)
+	}
+	return found, fileURL, nil
+}
+
+// ListExpiringDocuments returns the live documents expiring at or before
+// filter.Until, expired ones included, soonest first, with the counts by
+// status and the total before pagination.
+func (s *Storage) ListExpiringDocuments(ctx context.Context, orgID int, filter report.ExpiringDocumentFilter) ([]report.ExpiringDocument, report.ExpiringDocumentCounts, int, error) {
+	items := []report.ExpiringDocument{}
+	var counts report.ExpiringDocumentCounts
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		const where = `
+			FROM trakrf.documents d
+			LEFT JOIN trakrf.assets a ON a.id = d.asset_id
+			WHERE d.org_id = $1 AND d.expires_at <= $3 AND ($4 = '' OR d.type = $4)
+			  AND (d.asset_id IS NULL OR a.deleted_at IS NULL)`
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(*) FILTER (WHERE d.expires_at <= $2),
+			       COUNT(*) FILTER (WHERE d.expires_at > $2)`+where,
+			orgID, filter.Now, filter.Until, filter.Type).Scan(&counts.Expired, &counts.Expiring); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, `
+			SELECT d.id, d.type, d.title, d.reference, d.asset_id, a.external_key, a.name,
+			       d.expires_at, d.expires_at <= $2, d.file_url IS NOT NULL`+where+`
+			ORDER BY d.expires_at, d.id
+			LIMIT $5 OFFSET $6`, orgID, filter.Now, filter.Until, filter.Type, filter.Limit, filter.Offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var it report.ExpiringDocument
+			var isExpired bool
+			if err := rows.Scan(&it.DocumentID, &it.Type, &it.Title, &it.Reference, &it.AssetID,
+				&it.AssetExternalKey, &it.AssetName, &it.ExpiresAt, &isExpired, &it.HasFile); err != nil {
+				return err
+			}
+			it.Status, it.DaysLeft = document.ExpiryExpiring, daysLeft(filter.Now, it.ExpiresAt)
+			if isExpired {
+				it.Status = document.ExpiryExpired
+			}
+			items = append(items, it)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, report.ExpiringDocumentCounts{}, 0, fmt.Errorf("failed to list expiring documents: %w", err)
+	}
+	return items, counts, counts.Expired + counts.Expiring, nil
+}
+
+// daysLeft is the whole days from now until at, rounded towards the past so
+// a document expiring later today has 0 left and one that expired an hour
+// ago has -1.
+func daysLeft(now, at time.Time) int {
+	d := at.Sub(now)
+	days := int(d / (24 * time.Hour))
+	if d < 0 && d%(24*time.Hour) != 0 {
+		days--
+	}
+	return days
+}
+
+// ListOrgsWithDueDocumentNotices returns the live orgs holding a document
+// due an expiry notice at now: the orgs the document expiry job has work in.
+// Runs with no org context, like the other job claims.
+func (s *Storage) ListOrgsWithDueDocumentNotices(ctx context.Context, now time.Time) ([]int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT DISTINCT d.org_id
+		FROM trakrf.documents d
+		JOIN trakrf.organizations o ON o.id = d.org_id
+		WHERE o.deleted_at IS NULL
+		  AND d.expires_at IS NOT NULL AND d.expired_notified_at IS NULL
+		  AND (d.expires_at <= $1
+		       OR (d.expiring_notified_at IS NULL AND d.remind_days > 0
+		           AND d.expires_at - make_interval(days => d.remind_days) <= $1))
+		ORDER BY d.org_id`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orgs with due document notices: %w", err)
+	}
+	defer rows.Close()
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// NotifyDocumentExpiry stamps orgID's live documents that expired by now
+// and raises document.expired for each, then does the same for those that
+// entered their reminder window with document.expiring. The stamp and the
+// events commit together, so each notice is raised once across replicas.
+func (s *Storage) NotifyDocumentExpiry(ctx context.Context, orgID int, now time.Time) (expiring, expired int, err error) {
+	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			UPDATE trakrf.documents d SET expired_notified_at = $2
+			WHERE d.org_id = $1 AND d.expired_notified_at IS NULL AND d.expires_at <= $2
+			  AND `+liveDocument+`
+			RETURNING d.id`, orgID, now)
+		if err != nil {
+			return fmt.Errorf("stamp expired documents: %w", err)
+		}
+		expiredIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return fmt.Errorf("stamp expired documents: %w", err)
+		}
+		if err := s.publishAll(ctx, tx, events.DocumentExpired, orgID, expiredIDs); err != nil {
+			return err
+		}
+
+		rows, err = tx.Query(ctx, `
+			UPDATE trakrf.documents d SET expiring_notified_at = $2
+			WHERE d.org_id = $1 AND d.expiring_notified_at IS NULL AND d.remind_days > 0
+			  AND d.expires_at > $2 AND d.expires_at - make_interval(days => d.remind_days) <= $2
+			  AND `+liveDocument+`
+			RETURNING d.id`, orgID, now)
+		if err != nil {
+			return fmt.Errorf("stamp expiring documents: %w", err)
+		}
+		expiringIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return fmt.Errorf("stamp expiring documents: %w", err)
+		}
+		if err := s.publishAll(ctx, tx, events.DocumentExpiring, orgID, expiringIDs); err != nil {
+			return err
+		}
+		expiring, expired = len(expiringIDs), len(expiredIDs)
+		return nil
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to notify document expiry: %w", err)
+	}
+	return expiring, expired, nil
+}
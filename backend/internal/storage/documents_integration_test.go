@@ -0,0 +1,136 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/document"
+	"github.com/trakrf/platform/backend/internal/models/report"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestDocuments_CRUDAndFile(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	ctx := context.Background()
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	store := db.Store
+	asset := preCreateAssetWithTag(t, db, orgID, "Forklift 1", "E2801160600002040000D001")
+
+	missing := 999999999
+	_, err := store.CreateDocument(ctx, orgID, 0, document.CreateRequest{
+		AssetID: &missing, Type: document.TypeInsurance, Title: "Fleet",
+	})
+	assert.ErrorIs(t, err, storage.ErrDocumentAssetNotFound)
+
+	issued := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := issued.AddDate(0, 0, -1)
+	_, err = store.CreateDocument(ctx, orgID, 0, document.CreateRequest{
+		Type: document.TypeLease, Title: "Lease", IssuedAt: &issued, ExpiresAt: &before,
+	})
+	assert.ErrorIs(t, err, storage.ErrDocumentDates)
+
+	expires := time.Now().AddDate(0, 0, 10).UTC().Truncate(time.Second)
+	d, err := store.CreateDocument(ctx, orgID, 0, document.CreateRequest{
+		AssetID: &asset, Type: document.TypeCalibrationCert, Title: "Load cell cert", ExpiresAt: &expires,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, document.DefaultRemindDays, d.RemindDays)
+	assert.Equal(t, document.ExpiryExpiring, d.ExpiryStatus)
+	assert.Nil(t, d.File)
+
+	org, err := store.CreateDocument(ctx, orgID, 0, document.CreateRequest{Type: document.TypeLease, Title: "Warehouse lease"})
+	require.NoError(t, err)
+	assert.Empty(t, org.ExpiryStatus)
+
+	list, err := store.ListDocuments(ctx, orgID, document.ListFilter{AssetID: asset, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	list, err = store.ListDocuments(ctx, orgID, document.ListFilter{OrgOnly: true, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, org.ID, list[0].ID)
+
+	d, prev, err := store.SetDocumentFile(ctx, orgID, d.ID, document.File{URL: "https://cdn.test/a.pdf", ContentType: "application/pdf", SizeBytes: 10})
+	require.NoError(t, err)
+	assert.Nil(t, prev)
+	require.NotNil(t, d.File)
+	_, prev, err = store.SetDocumentFile(ctx, orgID, d.ID, document.File{URL: "https://cdn.test/b.pdf", ContentType: "application/pdf", SizeBytes: 12})
+	require.NoError(t, err)
+	require.NotNil(t, prev)
+	assert.Equal(t, "https://cdn.test/a.pdf", *prev)
+
+	title := "Load cell cert 2026"
+	d, err = store.UpdateDocument(ctx, orgID, d.ID, document.UpdateRequest{Title: &title})
+	require.NoError(t, err)
+	assert.Equal(t, title, d.Title)
+
+	found, fileURL, err := store.DeleteDocument(ctx, orgID, d.ID)
+	require.NoError(t, err)
+	assert.True(t, found)
+	require.NotNil(t, fileURL)
+	assert.Equal(t, "https://cdn.test/b.pdf", *fileURL)
+	found, _, err = store.DeleteDocument(ctx, orgID, d.ID)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestDocuments_ExpiryNoticesAndReport(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	ctx := context.Background()
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	store := db.Store
+	now := time.Now().UTC()
+
+	at := func(days int) *time.Time { v := now.AddDate(0, 0, days); return &v }
+	create := func(title string, expires *time.Time, remind int) *document.Document {
+		d, err := store.CreateDocument(ctx, orgID, 0, document.CreateRequest{
+			Type: document.TypeInsurance, Title: title, ExpiresAt: expires, RemindDays: &remind,
+		})
+		require.NoError(t, err)
+		return d
+	}
+	expired := create("expired", at(-2), 30)
+	expiring := create("expiring", at(5), 30)
+	create("later", at(90), 30)
+	create("no reminder", at(5), 0)
+	create("never", nil, 30)
+
+	orgs, err := store.ListOrgsWithDueDocumentNotices(ctx, now)
+	require.NoError(t, err)
+	assert.Contains(t, orgs, orgID)
+
+	nExpiring, nExpired, err := store.NotifyDocumentExpiry(ctx, orgID, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, nExpiring)
+	assert.Equal(t, 1, nExpired)
+
+	// Each notice fires once.
+	nExpiring, nExpired, err = store.NotifyDocumentExpiry(ctx, orgID, now)
+	require.NoError(t, err)
+	assert.Zero(t, nExpiring+nExpired)
+
+	// Renewing re-arms the alerts.
+	_, err = store.UpdateDocument(ctx, orgID, expiring.ID, document.UpdateRequest{ExpiresAt: at(20)})
+	require.NoError(t, err)
+	nExpiring, _, err = store.NotifyDocumentExpiry(ctx, orgID, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, nExpiring)
+
+	items, counts, total, err := store.ListExpiringDocuments(ctx, orgID, report.ExpiringDocumentFilter{
+		Now: now, Until: now.AddDate(0, 0, 30), Limit: 50,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, report.ExpiringDocumentCounts{Expired: 1, Expiring: 2}, counts)
+	assert.Equal(t, 3, total)
+	require.Len(t, items, 3)
+	assert.Equal(t, expired.ID, items[0].DocumentID)
+	assert.Equal(t, document.ExpiryExpired, items[0].Status)
+	assert.Less(t, items[0].DaysLeft, 0)
+}
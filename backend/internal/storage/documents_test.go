@@ -0,0 +1,26 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaysLeft(t *testing.T) {
+	now := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		at   time.Time
+		want int
+	}{
+		{now.Add(30 * 24 * time.Hour), 30},
+		{now.Add(47 * time.Hour), 1},
+		{now.Add(time.Hour), 0},
+		{now, 0},
+		{now.Add(-time.Hour), -1},
+		{now.Add(-24 * time.Hour), -1},
+		{now.Add(-25 * time.Hour), -2},
+	} {
+		assert.Equal(t, tc.want, daysLeft(now, tc.at), tc.at.Sub(now).String())
+	}
+}
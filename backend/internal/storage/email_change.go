@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/trakrf/platform/backend/internal/models/errors"
+)
+
+// ErrEmailChangeTokenInvalid is returned by ConfirmEmailChange for a token
+// that is unknown or has expired.
+var ErrEmailChangeTokenInvalid = stderrors.New("invalid or expired email change link")
+
+// CreateEmailChangeToken replaces any pending email change for the user with
+// one to newEmail, identified by the SHA-256 hex of the mailed token.
+func (s *Storage) CreateEmailChangeToken(ctx context.Context, userID int, newEmail, tokenHash string, expiresAt time.Time) error {
+	return s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM trakrf.email_change_tokens WHERE user_id = $1`, userID); err != nil {
+			return fmt.Errorf("failed to delete pending email changes: %w", err)
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO trakrf.email_change_tokens (user_id, new_email, token_hash, expires_at)
+			VALUES ($1, $2, $3, $4)`, userID, newEmail, tokenHash, expiresAt)
+		if err != nil {
+			return fmt.Errorf("failed to create email change token: %w", err)
+		}
+		return nil
+	})
+}
+
+// ConfirmEmailChange moves the token's user to the token's new address and
+// drops every pending change for that user, returning the user ID. An
+// address taken since the change was requested is ErrUserDuplicateEmail.
+func (s *Storage) ConfirmEmailChange(ctx context.Context, tokenHash string) (int, error) {
+	var userID int
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var newEmail string
+		err := tx.QueryRow(ctx, `
+			SELECT user_id, new_email FROM trakrf.email_change_tokens
+			WHERE token_hash = $1 AND expires_at > NOW()
+			FOR UPDATE`, tokenHash).Scan(&userID, &newEmail)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				return ErrEmailChangeTokenInvalid
+			}
+			return fmt.Errorf("failed to get email change token: %w", err)
+		}
+
+		var taken bool
+		err = tx.QueryRow(ctx, `
+			SELECT EXISTS(SELECT 1 FROM trakrf.users
+			              WHERE LOWER(email) = LOWER($1) AND id <> $2 AND deleted_at IS NULL)`,
+			newEmail, userID).Scan(&taken)
+		if err != nil {
+			return fmt.Errorf("failed to check email availability: %w", err)
+		}
+		if taken {
+			return errors.ErrUserDuplicateEmail
+		}
+
+		result, err := tx.Exec(ctx, `
+			UPDATE trakrf.users SET email = $2, updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL`, userID, newEmail)
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
+				return errors.ErrUserDuplicateEmail
+			}
+			return fmt.Errorf("failed to update user email: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrEmailChangeTokenInvalid
+		}
+
+		if _, err := tx.Exec(ctx, `DELETE FROM trakrf.email_change_tokens WHERE user_id = $1`, userID); err != nil {
+			return fmt.Errorf("failed to delete email change tokens: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/epcis"
+)
+
+// ListEPCISScans returns scans matching f in stream order (oldest first),
+// with the tag values and keys needed to render them as EPCIS events. Only
+// RFID tags identify assets and locations, and a scan is rendered with the
+// tags assigned when it was recorded (tag_assignments), so a tag since
+// recycled onto another asset keeps identifying the old asset in its old
+// scans. A scan older than every assignment (a capture back-dating its
+// events) falls back to the live tags. Scans of since-deleted assets are
+// still history and are included.
+func (s *Storage) ListEPCISScans(ctx context.Context, orgID int, f epcis.Filter) ([]epcis.Scan, error) {
+	query := `
+		SELECT s.timestamp, s.created_at, s.asset_id, a.external_key,
+		       COALESCE((SELECT array_agg(ta.value ORDER BY ta.tag_id) FROM trakrf.tag_assignments ta
+		                 WHERE ta.org_id = $1 AND ta.asset_id = s.asset_id AND ta.type = 'rfid'
+		                   AND ta.assigned_at <= s.timestamp
+		                   AND (ta.unassigned_at IS NULL OR ta.unassigned_at > s.timestamp)),
+		                (SELECT array_agg(t.value ORDER BY t.id) FROM trakrf.tags t
+		                 WHERE t.org_id = $1 AND t.asset_id = s.asset_id
+		                   AND t.type = 'rfid' AND t.deleted_at IS NULL), '{}'),
+		       l.external_key,
+		       COALESCE((SELECT array_agg(ta.value ORDER BY ta.tag_id) FROM trakrf.tag_assignments ta
+		                 WHERE ta.org_id = $1 AND ta.location_id = s.location_id AND ta.type = 'rfid'
+		                   AND ta.assigned_at <= s.timestamp
+		                   AND (ta.unassigned_at IS NULL OR ta.unassigned_at > s.timestamp)),
+		                (SELECT array_agg(t.value ORDER BY t.id) FROM trakrf.tags t
+		                 WHERE t.org_id = $1 AND t.location_id = s.location_id
+		                   AND t.type = 'rfid' AND t.deleted_at IS NULL), '{}'),
+		       sp.identifier
+		FROM trakrf.asset_scans s
+		JOIN trakrf.assets a ON a.id = s.asset_id AND a.org_id = $1
+		LEFT JOIN trakrf.locations l ON l.id = s.location_id AND l.org_id = $1
+		LEFT JOIN trakrf.scan_points sp ON sp.id = s.scan_point_id AND sp.org_id = $1
+		WHERE s.org_id = $1
+		  AND ($2::timestamptz IS NULL OR s.timestamp >= $2)
+		  AND ($3::timestamptz IS NULL OR s.timestamp < $3)
+		  AND ($4::bigint[] IS NULL OR s.asset_id = ANY($4))
+		  AND ($5::bigint[] IS NULL OR s.location_id = ANY($5))
+		  AND ($6::timestamptz IS NULL OR (s.timestamp, s.asset_id) > ($6, $7::bigint))
+		ORDER BY s.timestamp, s.asset_id
+		LIMIT $8`
+
+	var afterAt any
+	afterID := 0
+	if f.After != nil {
+		afterAt, afterID = f.After.At, f.After.AssetID
+	}
+
+	scans := []epcis.Scan{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, orgID, f.From, f.To, f.AssetIDs, f.LocationIDs, afterAt, afterID, f.Limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var sc epcis.Scan
+			if err := rows.Scan(&sc.Timestamp, &sc.RecordedAt, &sc.AssetID, &sc.AssetExternalKey,
+				&sc.AssetTags, &sc.LocationExternalKey, &sc.LocationTags, &sc.ScanPointIdentifier); err != nil {
+				return err
+			}
+			scans = append(scans, sc)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list epcis scans: %w", err)
+	}
+	return scans, nil
+}
+
+// ResolveEPCISIdentifiers maps the tag values and external keys in l to live
+// assets and locations. Tag values must already be normalized. Tags, assets
+// and locations outside their validity window, and disposed assets, do not
+// resolve, so a capture naming them is rejected like one naming an unknown
+// identifier.
+func (s *Storage) ResolveEPCISIdentifiers(ctx context.Context, orgID int, l epcis.Lookup) (epcis.Resolved, error) {
+	res := epcis.Resolved{
+		AssetsByTag:    map[string]int{},
+		AssetsByKey:    map[string]int{},
+		LocationsByTag: map[string]int{},
+		LocationsByKey: map[string]int{},
+	}
+	queries := []struct {
+		sql  string
+		args []string
+		into map[string]int
+	}{
+		{`SELECT t.normalized_value, t.asset_id FROM trakrf.tags t
+		  JOIN trakrf.assets a ON a.id = t.asset_id AND a.org_id = $1 AND a.deleted_at IS NULL
+		    AND a.disposed_at IS NULL
+		  WHERE t.org_id = $1 AND t.deleted_at IS NULL AND t.normalized_value = ANY($2)
+		    AND ` + temporallyEffective("t") + ` AND ` + temporallyEffective("a"),
+			l.AssetTags, res.AssetsByTag},
+		{`SELECT external_key, id FROM trakrf.assets a
+		  WHERE org_id = $1 AND deleted_at IS NULL AND disposed_at IS NULL AND external_key = ANY($2)
+		    AND ` + temporallyEffective("a"),
+			l.AssetKeys, res.AssetsByKey},
+		{`SELECT t.normalized_value, t.location_id FROM trakrf.tags t
+		  JOIN trakrf.locations l ON l.id = t.location_id AND l.org_id = $1 AND l.deleted_at IS NULL
+		  WHERE t.org_id = $1 AND t.deleted_at IS NULL AND t.normalized_value = ANY($2)
+		    AND ` + temporallyEffective("t") + ` AND ` + temporallyEffective("l"),
+			l.LocationTags, res.LocationsByTag},
+		{`SELECT external_key, id FROM trakrf.locations l
+		  WHERE org_id = $1 AND deleted_at IS NULL AND external_key = ANY($2)
+		    AND ` + temporallyEffective("l"),
+			l.LocationKeys, res.LocationsByKey},
+	}
+
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		for _, q := range queries {
+			if len(q.args) == 0 {
+				continue
+			}
+			rows, err := tx.Query(ctx, q.sql, orgID, q.args)
+			if err != nil {
+				return err
+			}
+			for rows.Next() {
+				var k string
+				var id int
+				if err := rows.Scan(&k, &id); err != nil {
+					rows.Close()
+					return err
+				}
+				q.into[k] = id
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return epcis.Resolved{}, fmt.Errorf("failed to resolve epcis identifiers: %w", err)
+	}
+	return res, nil
+}
+
+// CaptureEPCISScans records captured sightings in asset_scans. A sighting
+// already recorded for the same (timestamp, asset) is skipped, so replaying a
+// capture document is harmless; the count of new rows is returned.
+func (s *Storage) CaptureEPCISScans(ctx context.Context, orgID int, scans []epcis.CapturedScan) (int, error) {
+	inserted := 0
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		batch := &pgx.Batch{}
+		for _, sc := range scans {
+			batch.Queue(`
+				INSERT INTO trakrf.asset_scans (timestamp, org_id, asset_id, location_id, scan_point_id, tag_scan_id)
+				VALUES ($1, $2, $3, $4, NULL, NULL)
+				ON CONFLICT DO NOTHING`, sc.Timestamp, orgID, sc.AssetID, sc.LocationID)
+		}
+		tags, err := execBatch(ctx, tx, batch)
+		if err != nil {
+			return fmt.Errorf("insert asset scans: %w", err)
+		}
+		for _, tag := range tags {
+			inserted += int(tag.RowsAffected())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to capture epcis scans: %w", err)
+	}
+	return inserted, nil
+}
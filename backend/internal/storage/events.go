@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+)
+
+// publish queues a change event on tx; Postgres delivers it to every
+// LISTENing replica when tx commits and drops it on rollback. It is a no-op on
+// storages built without event publishing (NewWithPool / pgxmock unit tests),
+// so mock expectations do not have to spell out the pg_notify call. The change
+// is also queued for the org's subscribed webhooks and, with the export outbox
+// enabled (EnableEventOutbox), for the event exporter.
+func (s *Storage) publish(ctx context.Context, tx pgx.Tx, typ events.Type, orgID, entityID int) error {
+	if !s.publishEvents {
+		return nil
+	}
+	if err := events.Publish(ctx, tx, events.Event{Type: typ, OrgID: orgID, EntityID: entityID}); err != nil {
+		return err
+	}
+	return s.enqueueChange(ctx, tx, typ, orgID, []int{entityID})
+}
+
+// publishAll is publish for many entities of one type written by the same
+// transaction. Each step is a single statement whatever the number of
+// entities, which keeps bulk writes from paying a round trip per row.
+func (s *Storage) publishAll(ctx context.Context, tx pgx.Tx, typ events.Type, orgID int, entityIDs []int) error {
+	if !s.publishEvents || len(entityIDs) == 0 {
+		return nil
+	}
+	evs := make([]events.Event, len(entityIDs))
+	for i, id := range entityIDs {
+		evs[i] = events.Event{Type: typ, OrgID: orgID, EntityID: id}
+	}
+	if err := events.PublishAll(ctx, tx, evs); err != nil {
+		return err
+	}
+	return s.enqueueChange(ctx, tx, typ, orgID, entityIDs)
+}
+
+// enqueueChange queues the change for webhooks and, when enabled, the outbox.
+func (s *Storage) enqueueChange(ctx context.Context, tx pgx.Tx, typ events.Type, orgID int, entityIDs []int) error {
+	if err := s.enqueueWebhooks(ctx, tx, typ, orgID, entityIDs); err != nil {
+		return err
+	}
+	if s.outbox {
+		return s.enqueueOutbox(ctx, tx, typ, orgID, entityIDs)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/trakrf/platform/backend/internal/util/fieldcrypt"
+)
+
+// apiKeySecretAAD binds an encrypted secret hash to its column.
+const apiKeySecretAAD = "api_keys.secret_hash"
+
+// EnableFieldEncryption encrypts sensitive columns with keys from now on:
+// API key secret hashes are sealed, password reset tokens stored as keyed
+// digests. Values written before stay readable; `server encryption rotate`
+// re-encrypts them. Call once at boot, before the store serves requests.
+// Connector credentials are sealed by connectors.Vault, not here.
+func (s *Storage) EnableFieldEncryption(keys *fieldcrypt.Keyring) {
+	s.fieldKeys = keys
+}
+
+// sealAPIKeySecretHash is the stored form of an API key's secret hash.
+func (s *Storage) sealAPIKeySecretHash(hash string) (string, error) {
+	if s.fieldKeys == nil {
+		return hash, nil
+	}
+	return s.fieldKeys.Encrypt([]byte(hash), apiKeySecretAAD)
+}
+
+// openAPIKeySecretHash reverses sealAPIKeySecretHash. A hash stored before
+// encryption was enabled is returned as is.
+func (s *Storage) openAPIKeySecretHash(stored string) (string, error) {
+	if !fieldcrypt.IsEncrypted(stored) {
+		return stored, nil
+	}
+	if s.fieldKeys == nil {
+		return "", fmt.Errorf("api key secret hash is encrypted but FIELD_ENCRYPTION_KEYS is unset")
+	}
+	hash, err := s.fieldKeys.Decrypt(stored, apiKeySecretAAD)
+	if err != nil {
+		return "", fmt.Errorf("api key secret hash: %w", err)
+	}
+	return string(hash), nil
+}
+
+// resetTokenStored is the stored form of a password reset token.
+func (s *Storage) resetTokenStored(token string) string {
+	if s.fieldKeys == nil {
+		return token
+	}
+	return s.fieldKeys.Digest(token)
+}
+
+// resetTokenCandidates are the stored forms token may have: its digest under
+// every key, and itself for a token issued before encryption was enabled.
+func (s *Storage) resetTokenCandidates(token string) []string {
+	if s.fieldKeys == nil {
+		return []string{token}
+	}
+	return append(s.fieldKeys.Digests(token), token)
+}
+
+// EncryptedColumnStatus counts one encrypted column's values by the id of
+// the key they are encrypted under. Values not encrypted with the keyring
+// (plaintext, or sealed with the legacy connector vault key) count under "".
+type EncryptedColumnStatus struct {
+	Column string
+	ByKey  map[string]int64
+}
+
+// FieldEncryptionStatus reports how the re-encryptable columns are
+// encrypted, so an operator can tell when no value needs an old key.
+// Password reset tokens are digests, which cannot be re-keyed; they expire
+// within the hour instead.
+func (s *Storage) FieldEncryptionStatus(ctx context.Context) ([]EncryptedColumnStatus, error) {
+	out := []EncryptedColumnStatus{}
+	for _, c := range []struct{ column, query string }{
+		{"api_keys.secret_hash", `SELECT secret_hash FROM trakrf.api_keys`},
+		{"connectors.credentials", `SELECT substring(credentials FOR 48) FROM trakrf.connectors`},
+	} {
+		rows, err := s.pool.Query(ctx, c.query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", c.column, err)
+		}
+		status := EncryptedColumnStatus{Column: c.column, ByKey: map[string]int64{}}
+		for rows.Next() {
+			var v []byte
+			if err := rows.Scan(&v); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan %s: %w", c.column, err)
+			}
+			id, _ := fieldcrypt.KeyID(string(v))
+			status.ByKey[id]++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", c.column, err)
+		}
+		out = append(out, status)
+	}
+	return out, nil
+}
+
+// RotateAPIKeySecretHashes re-encrypts every API key secret hash not already
+// under the primary key, plaintext ones included, and returns how many it
+// rewrote. A row changed concurrently is left for the next run.
+func (s *Storage) RotateAPIKeySecretHashes(ctx context.Context) (int64, error) {
+	if s.fieldKeys == nil {
+		return 0, fmt.Errorf("FIELD_ENCRYPTION_KEYS is unset")
+	}
+	rows, err := s.pool.Query(ctx, `SELECT id, secret_hash FROM trakrf.api_keys`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list api key secret hashes: %w", err)
+	}
+	type stored struct {
+		id    int64
+		value string
+	}
+	var stale []stored
+	for rows.Next() {
+		var r stored
+		if err := rows.Scan(&r.id, &r.value); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan api key secret hash: %w", err)
+		}
+		if !s.fieldKeys.Current(r.value) {
+			stale = append(stale, r)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list api key secret hashes: %w", err)
+	}
+
+	var n int64
+	for _, r := range stale {
+		hash, err := s.openAPIKeySecretHash(r.value)
+		if err != nil {
+			return n, fmt.Errorf("api key %d: %w", r.id, err)
+		}
+		sealed, err := s.sealAPIKeySecretHash(hash)
+		if err != nil {
+			return n, err
+		}
+		tag, err := s.pool.Exec(ctx, `
+			UPDATE trakrf.api_keys SET secret_hash = $3
+			WHERE id = $1 AND secret_hash = $2`, r.id, r.value, sealed)
+		if err != nil {
+			return n, fmt.Errorf("failed to rewrite api key secret hash: %w", err)
+		}
+		n += tag.RowsAffected()
+	}
+	return n, nil
+}
+
+// RotateConnectorCredentials passes every connector's sealed credentials to
+// reseal and stores what it returns when it reports a change. Returns how
+// many it rewrote; a row changed concurrently is left for the next run.
+func (s *Storage) RotateConnectorCredentials(ctx context.Context, reseal func(orgID int, sealed []byte) ([]byte, bool, error)) (int64, error) {
+	rows, err := s.pool.Query(ctx, `SELECT id, org_id, credentials FROM trakrf.connectors`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list connector credentials: %w", err)
+	}
+	type stored struct {
+		id, orgID int
+		sealed    []byte
+	}
+	var all []stored
+	for rows.Next() {
+		var r stored
+		if err := rows.Scan(&r.id, &r.orgID, &r.sealed); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan connector credentials: %w", err)
+		}
+		all = append(all, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("failed to list connector credentials: %w", err)
+	}
+
+	var n int64
+	for _, r := range all {
+		resealed, changed, err := reseal(r.orgID, r.sealed)
+		if err != nil {
+			return n, fmt.Errorf("connector %d: %w", r.id, err)
+		}
+		if !changed {
+			continue
+		}
+		tag, err := s.pool.Exec(ctx, `
+			UPDATE trakrf.connectors SET credentials = $3
+			WHERE id = $1 AND credentials = $2`, r.id, r.sealed, resealed)
+		if err != nil {
+			return n, fmt.Errorf("failed to rewrite connector credentials: %w", err)
+		}
+		n += tag.RowsAffected()
+	}
+	return n, nil
+}
@@ -0,0 +1,113 @@
+//go:build integration
+// +build integration
+
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/apikey"
+	"github.com/trakrf/platform/backend/internal/testutil"
+	"github.com/trakrf/platform/backend/internal/util/apisecret"
+	"github.com/trakrf/platform/backend/internal/util/fieldcrypt"
+)
+
+func testFieldKeys(t *testing.T, ids ...string) *fieldcrypt.Keyring {
+	t.Helper()
+	keys := make([]fieldcrypt.Key, len(ids))
+	for i, id := range ids {
+		keys[i] = fieldcrypt.Key{ID: id, Secret: bytes.Repeat([]byte(id[len(id)-1:]), 32)}
+	}
+	k, err := fieldcrypt.NewKeyring(keys)
+	require.NoError(t, err)
+	return k
+}
+
+func TestFieldEncryption_APIKeySecretHashRotation(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	userID := createTestUser(t, pool)
+	hash := apisecret.Hash("some-opaque-secret")
+
+	// Issued before encryption was enabled: stored as the bare hash.
+	plain, err := store.CreateAPIKey(ctx, orgID, "plain", hash, []string{"assets:read"}, apikey.Creator{UserID: &userID}, nil)
+	require.NoError(t, err)
+
+	store.EnableFieldEncryption(testFieldKeys(t, "k1"))
+	sealed, err := store.CreateAPIKey(ctx, orgID, "sealed", hash, []string{"assets:read"}, apikey.Creator{UserID: &userID}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, hash, sealed.SecretHash)
+
+	var stored string
+	require.NoError(t, pool.QueryRow(ctx, `SELECT secret_hash FROM trakrf.api_keys WHERE id = $1`, sealed.ID).Scan(&stored))
+	assert.True(t, fieldcrypt.IsEncrypted(stored), stored)
+	assert.NotContains(t, stored, hash)
+
+	for _, jti := range []string{plain.JTI, sealed.JTI} {
+		got, err := store.GetAPIKeyByJTI(ctx, jti)
+		require.NoError(t, err)
+		assert.Equal(t, hash, got.SecretHash, "both forms read back as the hash")
+	}
+
+	// Rotate onto k2 with k1 kept for decryption.
+	store.EnableFieldEncryption(testFieldKeys(t, "k2", "k1"))
+	n, err := store.RotateAPIKeySecretHashes(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n, "the plaintext and the k1 value")
+	n, err = store.RotateAPIKeySecretHashes(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, n, "a second run has nothing to do")
+
+	status, err := store.FieldEncryptionStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "api_keys.secret_hash", status[0].Column)
+	assert.Equal(t, map[string]int64{"k2": 2}, status[0].ByKey)
+
+	// Dropping k1 is now safe.
+	store.EnableFieldEncryption(testFieldKeys(t, "k2"))
+	got, err := store.GetAPIKeyByID(ctx, int64(plain.ID))
+	require.NoError(t, err)
+	assert.Equal(t, hash, got.SecretHash)
+}
+
+func TestFieldEncryption_PasswordResetTokenIsDigested(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+	userID := createTestUser(t, pool)
+	expires := time.Now().Add(time.Hour)
+
+	require.NoError(t, store.CreatePasswordResetToken(ctx, userID, "issued-before", expires))
+	store.EnableFieldEncryption(testFieldKeys(t, "k1"))
+	require.NoError(t, store.CreatePasswordResetToken(ctx, userID, "issued-under-k1", expires))
+
+	var stored int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT count(*) FROM trakrf.password_reset_tokens WHERE token = 'issued-under-k1'`).Scan(&stored))
+	assert.Zero(t, stored, "the token itself is not stored")
+
+	// After rotation both are still found, and read back as the token.
+	store.EnableFieldEncryption(testFieldKeys(t, "k2", "k1"))
+	for _, token := range []string{"issued-before", "issued-under-k1"} {
+		got, err := store.GetPasswordResetToken(ctx, token)
+		require.NoError(t, err)
+		require.NotNil(t, got, token)
+		assert.Equal(t, token, got.Token)
+		require.NoError(t, store.DeletePasswordResetToken(ctx, token))
+		got, err = store.GetPasswordResetToken(ctx, token)
+		require.NoError(t, err)
+		assert.Nil(t, got, token)
+	}
+}
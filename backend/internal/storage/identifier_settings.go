@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// GetIdentifierSettings returns the org's identifier settings (zero value
+// when unset), or nil when the org does not exist.
+func (s *Storage) GetIdentifierSettings(ctx context.Context, orgID int) (*organization.IdentifierSettings, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'identifiers' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identifier settings: %w", err)
+	}
+	var is organization.IdentifierSettings
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &is); err != nil {
+			return nil, fmt.Errorf("failed to decode identifier settings: %w", err)
+		}
+	}
+	return &is, nil
+}
+
+// UpdateIdentifierSettings replaces metadata.identifiers with is. Other
+// metadata keys are preserved.
+func (s *Storage) UpdateIdentifierSettings(ctx context.Context, orgID int, is organization.IdentifierSettings) error {
+	blob, err := json.Marshal(is)
+	if err != nil {
+		return fmt.Errorf("failed to marshal identifier settings: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{identifiers}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update identifier settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// deletedKeyPolicy reads orgID's deleted-key policy inside tx.
+func deletedKeyPolicy(ctx context.Context, tx pgx.Tx, orgID int) (string, error) {
+	var is organization.IdentifierSettings
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(metadata->'identifiers'->>'deleted_key_policy', '')
+		FROM trakrf.organizations WHERE id = $1`, orgID).Scan(&is.DeletedKeyPolicy)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("read deleted key policy: %w", err)
+	}
+	return is.Policy(), nil
+}
+
+// ErrDeletedKeyBlocked is returned, wrapped with the kind and key, by
+// creates under the block policy whose external_key belongs to a deleted
+// record.
+var ErrDeletedKeyBlocked = errors.New("already exists as a deleted record")
+
+// deletedKeyError is the conflict a create gets under the block policy.
+func deletedKeyError(kind, key string) error {
+	return fmt.Errorf("%s with external_key %s %w", kind, key, ErrDeletedKeyBlocked)
+}
+
+// claimDeletedKey applies orgID's deleted-key policy to creating a kind
+// ("asset" or "location") under the caller-sent key, inside tx. It returns
+// the id of the soft-deleted record the caller must restore in place of the
+// insert under auto_restore, 0 when the insert should go ahead, and
+// deletedKeyError under block. A key held by a live record is left to the
+// insert's unique violation.
+func claimDeletedKey(ctx context.Context, tx pgx.Tx, kind string, orgID int, key string) (int, error) {
+	policy, err := deletedKeyPolicy(ctx, tx, orgID)
+	if err != nil || policy == organization.DeletedKeyAllowReuse {
+		return 0, err
+	}
+	var id int
+	err = tx.QueryRow(ctx, fmt.Sprintf(`
+		SELECT id FROM trakrf.%[1]ss
+		WHERE org_id = $1 AND external_key = $2 AND deleted_at IS NOT NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM trakrf.%[1]ss
+			WHERE org_id = $1 AND external_key = $2 AND deleted_at IS NULL)
+		ORDER BY deleted_at DESC, id DESC
+		LIMIT 1
+		FOR UPDATE`, kind), orgID, key).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("check deleted %s keys: %w", kind, err)
+	}
+	if policy == organization.DeletedKeyBlock {
+		return 0, deletedKeyError(kind, key)
+	}
+	return id, nil
+}
+
+// restoreTags attaches tagsJSON (see tagsToJSON) to a restored asset or
+// location, as the create_*_with_tags functions do for a new one. The
+// record's old tags stay deleted.
+func restoreTags(ctx context.Context, tx pgx.Tx, kind string, orgID, id int, tagsJSON []byte) error {
+	_, err := tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO trakrf.tags (org_id, type, value, %s_id, is_active)
+		SELECT $1, COALESCE(t->>'type', 'rfid'), t->>'value', $2, TRUE
+		FROM jsonb_array_elements($3::jsonb) t`, kind), orgID, id, tagsJSON)
+	return err
+}
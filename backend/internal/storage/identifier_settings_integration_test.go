@@ -0,0 +1,151 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func keyedAsset(orgID int, key, name string) asset.CreateAssetWithTagsRequest {
+	return asset.CreateAssetWithTagsRequest{
+		CreateAssetRequest: asset.CreateAssetRequest{OrgID: orgID, ExternalKey: key, Name: name},
+	}
+}
+
+// deletedAsset creates an asset under key and soft-deletes it.
+func deletedAsset(t *testing.T, store *storage.Storage, orgID int, key string) int {
+	t.Helper()
+	ctx := context.Background()
+	a, err := store.CreateAssetWithTags(ctx, keyedAsset(orgID, key, "Old "+key))
+	require.NoError(t, err)
+	ok, err := store.DeleteAsset(ctx, orgID, a.ID)
+	require.NoError(t, err)
+	require.True(t, ok)
+	return a.ID
+}
+
+func setDeletedKeyPolicy(t *testing.T, store *storage.Storage, orgID int, policy string) {
+	t.Helper()
+	require.NoError(t, store.UpdateIdentifierSettings(context.Background(), orgID,
+		organization.IdentifierSettings{DeletedKeyPolicy: policy}))
+}
+
+func TestIdentifierSettings_DefaultAllowsReuse(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	settings, err := store.GetIdentifierSettings(ctx, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, organization.DeletedKeyAllowReuse, settings.Policy())
+
+	oldID := deletedAsset(t, store, orgID, "FL-1")
+	a, err := store.CreateAssetWithTags(ctx, keyedAsset(orgID, "FL-1", "New forklift"))
+	require.NoError(t, err)
+	assert.NotEqual(t, oldID, a.ID)
+
+	missing, err := store.GetIdentifierSettings(ctx, 999999999)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestIdentifierSettings_Block(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	setDeletedKeyPolicy(t, store, orgID, organization.DeletedKeyBlock)
+	deletedAsset(t, store, orgID, "FL-1")
+
+	_, err := store.CreateAssetWithTags(ctx, keyedAsset(orgID, "FL-1", "New forklift"))
+	assert.ErrorIs(t, err, storage.ErrDeletedKeyBlocked)
+
+	_, err = store.CreateAsset(ctx, asset.Asset{OrgID: orgID, ExternalKey: "FL-1", Name: "New forklift", IsActive: true})
+	assert.ErrorIs(t, err, storage.ErrDeletedKeyBlocked)
+
+	_, _, err = store.CopyCreateAssetsWithTags(ctx, orgID, []asset.CreateAssetWithTagsRequest{
+		keyedAsset(orgID, "FL-2", "Fresh"),
+		keyedAsset(orgID, "FL-1", "New forklift"),
+	})
+	require.ErrorIs(t, err, storage.ErrDeletedKeyBlocked)
+	assert.Contains(t, err.Error(), "row 1:")
+	fresh, err := store.GetAssetByExternalKey(ctx, orgID, "FL-2")
+	require.NoError(t, err)
+	assert.Nil(t, fresh, "a blocked batch writes nothing")
+
+	loc, err := store.CreateLocationWithTags(ctx, orgID, location.CreateLocationWithTagsRequest{
+		CreateLocationRequest: location.CreateLocationRequest{Name: "Dock", ExternalKey: "dock"},
+	})
+	require.NoError(t, err)
+	_, err = store.DeleteLocation(ctx, orgID, loc.ID)
+	require.NoError(t, err)
+	_, err = store.CreateLocationWithTags(ctx, orgID, location.CreateLocationWithTagsRequest{
+		CreateLocationRequest: location.CreateLocationRequest{Name: "Dock", ExternalKey: "dock"},
+	})
+	assert.ErrorIs(t, err, storage.ErrDeletedKeyBlocked)
+}
+
+func TestIdentifierSettings_AutoRestore(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	setDeletedKeyPolicy(t, store, orgID, organization.DeletedKeyAutoRestore)
+	oldID := deletedAsset(t, store, orgID, "FL-1")
+
+	rfid := "rfid"
+	req := keyedAsset(orgID, "FL-1", "Forklift, back")
+	req.Tags = []shared.TagRequest{{TagType: &rfid, Value: "E280AB12"}}
+	a, err := store.CreateAssetWithTags(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, oldID, a.ID)
+	assert.Equal(t, "Forklift, back", a.Name)
+	assert.Nil(t, a.DeletedAt)
+	require.Len(t, a.Tags, 1)
+	assert.Equal(t, "E280AB12", a.Tags[0].Value)
+
+	// A batch restores what it can and inserts the rest.
+	batchOld := deletedAsset(t, store, orgID, "FL-2")
+	n, _, err := store.CopyCreateAssetsWithTags(ctx, orgID, []asset.CreateAssetWithTagsRequest{
+		keyedAsset(orgID, "FL-3", "Brand new"),
+		keyedAsset(orgID, "FL-2", "Restored in bulk"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	restored, err := store.GetAssetByExternalKey(ctx, orgID, "FL-2")
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	assert.Equal(t, batchOld, restored.ID)
+	assert.Equal(t, "Restored in bulk", restored.Name)
+
+	loc, err := store.CreateLocationWithTags(ctx, orgID, location.CreateLocationWithTagsRequest{
+		CreateLocationRequest: location.CreateLocationRequest{Name: "Dock", ExternalKey: "dock"},
+	})
+	require.NoError(t, err)
+	_, err = store.DeleteLocation(ctx, orgID, loc.ID)
+	require.NoError(t, err)
+	back, err := store.CreateLocationWithTags(ctx, orgID, location.CreateLocationWithTagsRequest{
+		CreateLocationRequest: location.CreateLocationRequest{Name: "Dock 2", ExternalKey: "dock"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, loc.ID, back.ID)
+	assert.Equal(t, "Dock 2", back.Name)
+}
@@ -0,0 +1,121 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// ErrIdentifierSequenceExhausted is returned when auto-assigning an asset
+// identifier finds every candidate in a long run already taken.
+var ErrIdentifierSequenceExhausted = errors.New("no free identifier near the template's next value; raise next_value")
+
+// identifierSkipLimit bounds how many taken identifiers one draw skips.
+const identifierSkipLimit = 1000
+
+// identifierTemplateDefault is the template of an org without a stored
+// one: ASSET-NNNN continuing after the org's highest live ASSET- key, as an
+// omitted external_key does.
+const identifierTemplateDefault = `
+	SELECT 'ASSET-', 4, COALESCE(MAX(CAST(SUBSTRING(external_key FROM 'ASSET-([0-9]+)') AS BIGINT)), 0) + 1
+	FROM trakrf.assets
+	WHERE org_id = $1 AND external_key ~ '^ASSET-[0-9]+$' AND deleted_at IS NULL`
+
+// GetIdentifierTemplate returns the org's identifier template, the default
+// when none is stored, or nil when the org does not exist.
+func (s *Storage) GetIdentifierTemplate(ctx context.Context, orgID int) (*organization.IdentifierTemplate, error) {
+	var t *organization.IdentifierTemplate
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM trakrf.organizations WHERE id = $1 AND deleted_at IS NULL)`,
+			orgID).Scan(&exists); err != nil || !exists {
+			return err
+		}
+		var v organization.IdentifierTemplate
+		err := tx.QueryRow(ctx, `
+			SELECT prefix, padding, next_value FROM trakrf.identifier_templates
+			WHERE org_id = $1`, orgID).Scan(&v.Prefix, &v.Padding, &v.NextValue)
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = tx.QueryRow(ctx, identifierTemplateDefault, orgID).Scan(&v.Prefix, &v.Padding, &v.NextValue)
+		}
+		if err != nil {
+			return err
+		}
+		t = &v
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get identifier template: %w", err)
+	}
+	return t, nil
+}
+
+// UpdateIdentifierTemplate replaces the org's identifier template.
+func (s *Storage) UpdateIdentifierTemplate(ctx context.Context, orgID int, t organization.IdentifierTemplate) error {
+	return s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM trakrf.organizations WHERE id = $1 AND deleted_at IS NULL)`,
+			orgID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to update identifier template: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("organization not found")
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO trakrf.identifier_templates (org_id, prefix, padding, next_value)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (org_id) DO UPDATE
+			SET prefix = EXCLUDED.prefix, padding = EXCLUDED.padding, next_value = EXCLUDED.next_value`,
+			orgID, t.Prefix, t.Padding, t.NextValue); err != nil {
+			return fmt.Errorf("failed to update identifier template: %w", err)
+		}
+		return nil
+	})
+}
+
+// nextAssetIdentifier draws the org's next asset identifier inside the
+// caller's transaction. The template row stays locked until the caller
+// commits, so concurrent draws queue behind it, and a rolled-back create
+// leaves the sequence where it was. Identifiers already held by a live
+// asset (set by hand, or by an earlier template) are skipped.
+func nextAssetIdentifier(ctx context.Context, tx pgx.Tx, orgID int) (string, error) {
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO trakrf.identifier_templates (org_id, prefix, padding, next_value)
+		`+identifierTemplateDefault+`
+		ON CONFLICT (org_id) DO NOTHING`, orgID); err != nil {
+		return "", fmt.Errorf("seed identifier template: %w", err)
+	}
+	var t organization.IdentifierTemplate
+	if err := tx.QueryRow(ctx, `
+		SELECT prefix, padding, next_value FROM trakrf.identifier_templates
+		WHERE org_id = $1
+		FOR UPDATE`, orgID).Scan(&t.Prefix, &t.Padding, &t.NextValue); err != nil {
+		return "", fmt.Errorf("lock identifier template: %w", err)
+	}
+	for n := t.NextValue; n < t.NextValue+identifierSkipLimit; n++ {
+		key := t.Format(n)
+		var taken bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM trakrf.assets
+			               WHERE org_id = $1 AND external_key = $2 AND deleted_at IS NULL)`,
+			orgID, key).Scan(&taken); err != nil {
+			return "", fmt.Errorf("check identifier: %w", err)
+		}
+		if taken {
+			continue
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.identifier_templates SET next_value = $2
+			WHERE org_id = $1`, orgID, n+1); err != nil {
+			return "", fmt.Errorf("advance identifier template: %w", err)
+		}
+		return key, nil
+	}
+	return "", ErrIdentifierSequenceExhausted
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func autoAsset(orgID int, name string) asset.CreateAssetWithTagsRequest {
+	return asset.CreateAssetWithTagsRequest{
+		CreateAssetRequest: asset.CreateAssetRequest{OrgID: orgID, Name: name},
+		AutoIdentifier:     true,
+	}
+}
+
+func TestIdentifierTemplate_DefaultContinuesAssetKeys(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	testutil.CreateTestAsset(t, pool, orgID, "ASSET-0007")
+
+	tmpl, err := store.GetIdentifierTemplate(ctx, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, organization.IdentifierTemplate{Prefix: "ASSET-", Padding: 4, NextValue: 8}, *tmpl)
+
+	a, err := store.CreateAssetWithTags(ctx, autoAsset(orgID, "Pallet jack"))
+	require.NoError(t, err)
+	assert.Equal(t, "ASSET-0008", a.ExternalKey)
+
+	tmpl, err = store.GetIdentifierTemplate(ctx, orgID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 9, tmpl.NextValue)
+
+	missing, err := store.GetIdentifierTemplate(ctx, 999999999)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestIdentifierTemplate_SkipsTakenAndIsAtomic(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	require.NoError(t, store.UpdateIdentifierTemplate(ctx, orgID,
+		organization.IdentifierTemplate{Prefix: "WH-", Padding: 3, NextValue: 1}))
+	testutil.CreateTestAsset(t, pool, orgID, "WH-002")
+
+	a, err := store.CreateAssetWithTags(ctx, autoAsset(orgID, "Cart 1"))
+	require.NoError(t, err)
+	assert.Equal(t, "WH-001", a.ExternalKey)
+	a, err = store.CreateAssetWithTags(ctx, autoAsset(orgID, "Cart 2"))
+	require.NoError(t, err)
+	assert.Equal(t, "WH-003", a.ExternalKey, "a key already in use is skipped")
+
+	const n = 10
+	keys := make(chan string, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a, err := store.CreateAssetWithTags(ctx, autoAsset(orgID, "Cart"))
+			if assert.NoError(t, err) {
+				keys <- a.ExternalKey
+			}
+		}()
+	}
+	wg.Wait()
+	close(keys)
+	seen := map[string]bool{}
+	for k := range keys {
+		assert.False(t, seen[k], "duplicate identifier %s", k)
+		seen[k] = true
+	}
+	assert.Len(t, seen, n)
+
+	tmpl, err := store.GetIdentifierTemplate(ctx, orgID)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4+n, tmpl.NextValue)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/jackc/pgx/v5"
 
+	"github.com/trakrf/platform/backend/internal/events"
 	"github.com/trakrf/platform/backend/internal/models/scanread"
 )
 
@@ -74,7 +75,11 @@ func (s *Storage) InsertRawTagScan(ctx context.Context, topic string, payload []
 // PersistResult summarizes a PersistReads run for logging/metrics.
 type PersistResult struct {
 	Inserted int
-	Dropped  map[string]int // reason -> count: no_scan_point, no_asset, conflict
+	Dropped  map[string]int // reason -> count: no_scan_point, no_asset, not_valid, disposed, conflict
+	// Deferred counts membership-passing BLE reads that were not written to
+	// asset_scans because the org has positioning enabled: the positioning
+	// engine writes the estimated zone instead of every advertisement.
+	Deferred int
 	// Resolved is every read that passed the membership filter (registered rfid
 	// tag → asset AND registered scan_point), enriched with the data the geofence
 	// engine (TRA-901) needs. A read appears here even when its asset_scans insert
@@ -93,6 +98,9 @@ type ResolvedRead struct {
 	LocationID  *int
 	EPC         string
 	RSSI        int // scanread.Read.RSSI; 0 == parser sentinel for "no usable RSSI"
+	// BLE marks a read from a BLE gateway (scanread.Read.BLE set by the
+	// parser). The positioning engine only estimates zones from these.
+	BLE bool
 }
 
 // PersistReads writes asset_scans for parsed reads under org context (RLS).
@@ -105,78 +113,106 @@ type ResolvedRead struct {
 // resolves the same as an rfid EPC. Matching is leading-zero / case-insensitive
 // on the hex value (TRA-944), identical to the handheld getMatchingKey, so a tag
 // registered by its short barcode value resolves the reader's full-width EPC.
-// receivedAt (server time) is authoritative for asset_scans.timestamp; the
-// reader clock is ignored.
+// A read whose tag or asset is outside its valid_from/valid_to window at
+// receivedAt is dropped as not_valid, and a read of a disposed asset as
+// disposed, so it cannot put the asset back at a location. receivedAt (server time) is authoritative for asset_scans.timestamp; the
+// reader clock is ignored. When the org has positioning enabled, BLE reads are
+// resolved but not written (PersistResult.Deferred); the positioning engine
+// writes the smoothed zone estimate instead.
+//
+// A message costs a fixed number of round-trips however many reads it
+// carries: every distinct antenna and EPC is resolved in one pgx.Batch, and
+// the asset_scans inserts go out in a second.
 func (s *Storage) PersistReads(ctx context.Context, orgID, scanDeviceID int, tagScanID int64, receivedAt time.Time, reads []scanread.Read) (PersistResult, error) {
 	res := PersistResult{Dropped: map[string]int{}}
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		positioned, err := s.positioningEnabled(ctx, tx, orgID, reads)
+		if err != nil {
+			return err
+		}
+		points, assets, err := resolveReads(ctx, tx, orgID, scanDeviceID, receivedAt, reads)
+		if err != nil {
+			return err
+		}
+
+		var pending []ResolvedRead
 		for _, rd := range reads {
-			// Correlate the read to its scan_point by (device, antenna_port)
-			// (TRA-956). The device is the one the topic routed to; the antenna
-			// is on the read, defaulting to 1 for single-antenna devices. A read
-			// for an unprovisioned antenna is a clean no_scan_point miss.
-			antennaPort := rd.AntennaPort
-			if antennaPort < 1 {
-				antennaPort = 1
-			}
-			var scanPointID int
-			var locationID *int
-			err := tx.QueryRow(ctx,
-				`SELECT id, location_id
-				 FROM trakrf.scan_points
-				 WHERE org_id = $1 AND scan_device_id = $2 AND antenna_port = $3 AND deleted_at IS NULL`,
-				orgID, scanDeviceID, antennaPort,
-			).Scan(&scanPointID, &locationID)
-			if errors.Is(err, pgx.ErrNoRows) {
+			point, ok := points[readAntennaPort(rd)]
+			if !ok {
 				res.Dropped["no_scan_point"]++
 				continue
 			}
-			if err != nil {
-				return fmt.Errorf("resolve scan_point for device %d antenna %d: %w", scanDeviceID, antennaPort, err)
-			}
-
-			var assetID int
-			err = tx.QueryRow(ctx,
-				`SELECT asset_id FROM trakrf.tags
-				 WHERE org_id = $1
-				   AND normalized_value = trakrf.normalize_tag_value($2)
-				   AND asset_id IS NOT NULL AND deleted_at IS NULL
-				 LIMIT 1`,
-				orgID, rd.EPC,
-			).Scan(&assetID)
-			if errors.Is(err, pgx.ErrNoRows) {
+			// A tag or asset outside its validity window at receivedAt is a
+			// not_valid drop, distinct from an unregistered EPC.
+			a, ok := assets[rd.EPC]
+			switch {
+			case !ok:
 				res.Dropped["no_asset"]++
 				continue
-			}
-			if err != nil {
-				return fmt.Errorf("resolve asset for epc %q: %w", rd.EPC, err)
+			case a.disposed:
+				res.Dropped["disposed"]++
+				continue
+			case !a.effective:
+				res.Dropped["not_valid"]++
+				continue
 			}
 
 			// Membership passed: record the resolved read for the geofence engine
 			// before the dedup branch, so a within-message duplicate (conflict)
 			// still counts as a boundary observation.
-			res.Resolved = append(res.Resolved, ResolvedRead{
-				AssetID:     assetID,
-				ScanPointID: scanPointID,
-				LocationID:  locationID,
+			resolved := ResolvedRead{
+				AssetID:     a.assetID,
+				ScanPointID: point.id,
+				LocationID:  point.locationID,
 				EPC:         rd.EPC,
 				RSSI:        rd.RSSI,
-			})
+				BLE:         rd.BLE != nil,
+			}
+			res.Resolved = append(res.Resolved, resolved)
+			if positioned && rd.BLE != nil {
+				res.Deferred++
+				continue
+			}
+			pending = append(pending, resolved)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
 
-			ct, err := tx.Exec(ctx,
+		// The batch runs its inserts in order, so a within-message duplicate
+		// still meets its twin's row and conflicts.
+		batch := &pgx.Batch{}
+		for _, r := range pending {
+			batch.Queue(
 				`INSERT INTO trakrf.asset_scans
 				   (timestamp, org_id, asset_id, location_id, scan_point_id, tag_scan_id)
 				 VALUES ($1, $2, $3, $4, $5, $6)
 				 ON CONFLICT (timestamp, org_id, asset_id) DO NOTHING`,
-				receivedAt, orgID, assetID, locationID, scanPointID, tagScanID,
+				receivedAt, orgID, r.AssetID, r.LocationID, r.ScanPointID, tagScanID,
 			)
-			if err != nil {
-				return fmt.Errorf("insert asset_scan: %w", err)
-			}
-			if ct.RowsAffected() == 0 {
+		}
+		tags, err := execBatch(ctx, tx, batch)
+		if err != nil {
+			return fmt.Errorf("insert asset_scan: %w", err)
+		}
+		for i, r := range pending {
+			if tags[i].RowsAffected() == 0 {
 				res.Dropped["conflict"]++
 				continue
 			}
+			if s.outbox {
+				err := s.enqueueOutboxData(ctx, tx, events.ScanRecorded, orgID, &r.AssetID, map[string]any{
+					"timestamp":      receivedAt,
+					"asset_id":       r.AssetID,
+					"location_id":    r.LocationID,
+					"scan_point_id":  r.ScanPointID,
+					"scan_device_id": scanDeviceID,
+					"epc":            r.EPC,
+				})
+				if err != nil {
+					return err
+				}
+			}
 			res.Inserted++
 		}
 		return nil
@@ -186,3 +222,128 @@ func (s *Storage) PersistReads(ctx context.Context, orgID, scanDeviceID int, tag
 	}
 	return res, nil
 }
+
+// readAntennaPort is the antenna a read came in on, defaulting to 1 for
+// single-antenna devices.
+func readAntennaPort(rd scanread.Read) int {
+	if rd.AntennaPort < 1 {
+		return 1
+	}
+	return rd.AntennaPort
+}
+
+// readScanPoint is the scan_point a device antenna maps to.
+type readScanPoint struct {
+	id         int
+	locationID *int
+}
+
+// readAsset is the asset a read EPC resolves to, with its state at the
+// message's receivedAt.
+type readAsset struct {
+	assetID   int
+	effective bool
+	disposed  bool
+}
+
+// resolveReads correlates each distinct antenna in reads to its scan_point
+// (TRA-956) and each distinct EPC to its asset, in one batch. An antenna or
+// EPC that resolves to nothing is absent from the returned maps.
+func resolveReads(ctx context.Context, tx pgx.Tx, orgID, scanDeviceID int, receivedAt time.Time, reads []scanread.Read) (map[int]readScanPoint, map[string]readAsset, error) {
+	var ports []int
+	var epcs []string
+	seenPort := map[int]bool{}
+	seenEPC := map[string]bool{}
+	batch := &pgx.Batch{}
+	for _, rd := range reads {
+		if port := readAntennaPort(rd); !seenPort[port] {
+			seenPort[port] = true
+			ports = append(ports, port)
+			batch.Queue(
+				`SELECT id, location_id
+				 FROM trakrf.scan_points
+				 WHERE org_id = $1 AND scan_device_id = $2 AND antenna_port = $3 AND deleted_at IS NULL`,
+				orgID, scanDeviceID, port,
+			)
+		}
+	}
+	for _, rd := range reads {
+		if !seenEPC[rd.EPC] {
+			seenEPC[rd.EPC] = true
+			epcs = append(epcs, rd.EPC)
+			batch.Queue(
+				`SELECT i.asset_id, (`+temporallyEffectiveAt("i", "$3")+` AND `+temporallyEffectiveAt("a", "$3")+`) AS effective,
+				        a.disposed_at IS NOT NULL
+				 FROM trakrf.tags i
+				 JOIN trakrf.assets a ON a.id = i.asset_id AND a.org_id = i.org_id AND a.deleted_at IS NULL
+				 WHERE i.org_id = $1
+				   AND i.normalized_value = trakrf.normalize_tag_value($2)
+				   AND i.deleted_at IS NULL
+				 ORDER BY effective DESC
+				 LIMIT 1`,
+				orgID, rd.EPC, receivedAt,
+			)
+		}
+	}
+	if batch.Len() == 0 {
+		return nil, nil, nil
+	}
+
+	points := make(map[int]readScanPoint, len(ports))
+	assets := make(map[string]readAsset, len(epcs))
+	br := tx.SendBatch(ctx, batch)
+	defer br.Close()
+	for _, port := range ports {
+		var p readScanPoint
+		err := br.QueryRow().Scan(&p.id, &p.locationID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolve scan_point for device %d antenna %d: %w", scanDeviceID, port, err)
+		}
+		points[port] = p
+	}
+	for _, epc := range epcs {
+		var a readAsset
+		err := br.QueryRow().Scan(&a.assetID, &a.effective, &a.disposed)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("resolve asset for epc %q: %w", epc, err)
+		}
+		assets[epc] = a
+	}
+	if err := br.Close(); err != nil {
+		return nil, nil, err
+	}
+	return points, assets, nil
+}
+
+// positioningEnabled reports whether BLE reads in this message should be left
+// to the positioning engine. The org lookup is skipped for messages with no
+// BLE reads, which keeps the RFID path at its usual query count.
+func (s *Storage) positioningEnabled(ctx context.Context, tx pgx.Tx, orgID int, reads []scanread.Read) (bool, error) {
+	hasBLE := false
+	for _, rd := range reads {
+		if rd.BLE != nil {
+			hasBLE = true
+			break
+		}
+	}
+	if !hasBLE {
+		return false, nil
+	}
+	var enabled bool
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE((metadata->'positioning'->>'enabled')::boolean, false)
+		FROM trakrf.organizations WHERE id = $1`, orgID).Scan(&enabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("read positioning setting: %w", err)
+	}
+	return enabled, nil
+}
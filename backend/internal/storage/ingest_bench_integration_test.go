@@ -0,0 +1,107 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/scanread"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+// Ingest-burst benchmarks. They need the test database like the integration
+// tests:
+//
+//	go test -tags integration -run '^$' -bench . ./internal/storage/
+
+// benchTags and benchReads shape one burst: a reader message carrying
+// benchReads reads of benchTags registered tags, as a dock portal sees a
+// pallet go through.
+const (
+	benchTags  = 50
+	benchReads = 200
+)
+
+// BenchmarkPersistReads measures one reader message through PersistReads:
+// scan point and tag resolution plus the asset_scans writes.
+func BenchmarkPersistReads(b *testing.B) {
+	db := testutil.SetupTestDBFull(b)
+	orgID := testutil.CreateTestAccount(b, db.AdminPool)
+	dev := registerDevice(b, db, orgID, "bench-portal")
+
+	reads := make([]scanread.Read, benchReads)
+	for i := range benchTags {
+		registerRFIDTag(b, db, orgID, fmt.Sprintf("E2801190A5030065%08X", i))
+	}
+	for i := range reads {
+		reads[i] = scanread.Read{EPC: fmt.Sprintf("E2801190A5030065%08X", i%benchTags), AntennaPort: 1}
+	}
+
+	ctx := context.Background()
+	at := time.Now()
+	for b.Loop() {
+		// A fresh instant per message, so every message writes its scans
+		// instead of conflicting with the previous one.
+		at = at.Add(time.Millisecond)
+		if _, err := db.Store.PersistReads(ctx, orgID, dev.ID, 1, at, reads); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkAssetScanWrites compares writing one burst of asset_scans rows
+// statement by statement against queuing them in one pgx.Batch, the way the
+// storage hot paths now write.
+func BenchmarkAssetScanWrites(b *testing.B) {
+	db := testutil.SetupTestDBFull(b)
+	orgID := testutil.CreateTestAccount(b, db.AdminPool)
+	assetIDs := make([]int, benchTags)
+	for i := range assetIDs {
+		assetIDs[i] = testutil.CreateTestAsset(b, db.AdminPool, orgID, fmt.Sprintf("bench-%d", i)).ID
+	}
+
+	const insert = `
+		INSERT INTO trakrf.asset_scans (timestamp, org_id, asset_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (timestamp, org_id, asset_id) DO NOTHING`
+	ctx := context.Background()
+	at := time.Now()
+
+	b.Run("per_row", func(b *testing.B) {
+		for b.Loop() {
+			at = at.Add(time.Millisecond)
+			err := db.Store.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+				for _, id := range assetIDs {
+					if _, err := tx.Exec(ctx, insert, at, orgID, id); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		for b.Loop() {
+			at = at.Add(time.Millisecond)
+			err := db.Store.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+				batch := &pgx.Batch{}
+				for _, id := range assetIDs {
+					batch.Queue(insert, at, orgID, id)
+				}
+				return tx.SendBatch(ctx, batch).Close()
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -30,7 +30,7 @@ func publishTopic(key string) string { return "trakrf.id/" + key + "/reads" }
 // registerDevice creates a CS463 device publishing on trakrf.id/{key}/reads
 // (auto-provisions antenna-1 scan_point) and returns it so the caller can pass
 // its id to PersistReads.
-func registerDevice(t *testing.T, db *testutil.TestDB, orgID int, key string) *scandevice.ScanDevice {
+func registerDevice(t testing.TB, db *testutil.TestDB, orgID int, key string) *scandevice.ScanDevice {
 	t.Helper()
 	topic := publishTopic(key)
 	d, err := db.Store.CreateScanDevice(context.Background(), orgID, scandevice.CreateScanDeviceRequest{
@@ -41,7 +41,7 @@ func registerDevice(t *testing.T, db *testutil.TestDB, orgID int, key string) *s
 }
 
 // registerRFIDTag links an rfid tag value (EPC) to a new asset.
-func registerRFIDTag(t *testing.T, db *testutil.TestDB, orgID int, epc string) {
+func registerRFIDTag(t testing.TB, db *testutil.TestDB, orgID int, epc string) {
 	t.Helper()
 	registerTag(t, db, orgID, "rfid", epc)
 }
@@ -54,7 +54,7 @@ func registerBLETag(t *testing.T, db *testutil.TestDB, orgID int, mac string) {
 }
 
 // registerTag links a tag of the given type/value to a new asset.
-func registerTag(t *testing.T, db *testutil.TestDB, orgID int, tagType, value string) {
+func registerTag(t testing.TB, db *testutil.TestDB, orgID int, tagType, value string) {
 	t.Helper()
 	asset := testutil.CreateTestAsset(t, db.AdminPool, orgID, "asset-"+value)
 	_, err := db.AdminPool.Exec(context.Background(),
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/integration"
+)
+
+// MovedAssetsLookback bounds the cursorless first poll of the moved-assets
+// trigger: without a cursor only moves this recent are considered, so a
+// trigger's sample request never walks the whole scan history.
+const MovedAssetsLookback = 7 * 24 * time.Hour
+
+// Polling-trigger queries share one shape: with a cursor they take the oldest
+// limit items strictly after it (so repeated polls drain a backlog without
+// gaps); without one they take the newest limit items. Either way rows come
+// back newest first, the order polling tools expect.
+
+func cursorArgs(after *integration.Cursor) (*time.Time, int) {
+	if after == nil {
+		return nil, 0
+	}
+	return &after.At, after.ID
+}
+
+// ListNewAssets returns live assets created after the cursor, newest first.
+func (s *Storage) ListNewAssets(ctx context.Context, orgID int, after *integration.Cursor, limit int) ([]integration.NewAssetItem, error) {
+	at, id := cursorArgs(after)
+	query := `
+		SELECT * FROM (
+			SELECT a.id, a.external_key, a.name, COALESCE(a.description, ''), a.is_active, a.created_at
+			FROM trakrf.assets a
+			WHERE a.org_id = $1 AND a.deleted_at IS NULL
+			  AND ($2::timestamptz IS NULL OR (a.created_at, a.id) > ($2, $3::bigint))
+			ORDER BY
+				CASE WHEN $2::timestamptz IS NULL THEN a.created_at END DESC,
+				CASE WHEN $2::timestamptz IS NULL THEN a.id END DESC,
+				a.created_at ASC, a.id ASC
+			LIMIT $4
+		) page
+		ORDER BY created_at DESC, id DESC`
+
+	items := []integration.NewAssetItem{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, orgID, at, id, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var it integration.NewAssetItem
+			if err := rows.Scan(&it.ID, &it.ExternalKey, &it.Name, &it.Description, &it.IsActive, &it.CreatedAt); err != nil {
+				return err
+			}
+			items = append(items, it)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list new assets: %w", err)
+	}
+	return items, nil
+}
+
+// ListMovedAssets returns asset moves after the cursor, newest first. A move
+// is a scan that places the asset at a location other than the one its
+// previous located scan did (or its first located scan ever). Cursor IDs are
+// asset ids: (moved_at, asset_id) is unique per org, as asset_scans' primary
+// key.
+func (s *Storage) ListMovedAssets(ctx context.Context, orgID int, after *integration.Cursor, limit int) ([]integration.MovedAssetItem, error) {
+	at, id := cursorArgs(after)
+	query := `
+		WITH moves AS (
+			SELECT s.timestamp, s.asset_id, s.location_id, prev.location_id AS from_location_id
+			FROM trakrf.asset_scans s
+			LEFT JOIN LATERAL (
+				SELECT p.location_id
+				FROM trakrf.asset_scans p
+				WHERE p.org_id = $1 AND p.asset_id = s.asset_id
+				  AND p.timestamp < s.timestamp AND p.location_id IS NOT NULL
+				ORDER BY p.timestamp DESC
+				LIMIT 1
+			) prev ON true
+			WHERE s.org_id = $1 AND s.location_id IS NOT NULL
+			  AND prev.location_id IS DISTINCT FROM s.location_id
+			  AND (($2::timestamptz IS NULL AND s.timestamp >= $5)
+			       OR (s.timestamp, s.asset_id) > ($2, $3::bigint))
+			ORDER BY
+				CASE WHEN $2::timestamptz IS NULL THEN s.timestamp END DESC,
+				CASE WHEN $2::timestamptz IS NULL THEN s.asset_id END DESC,
+				s.timestamp ASC, s.asset_id ASC
+			LIMIT $4
+		)
+		SELECT m.timestamp, m.asset_id, a.external_key, a.name,
+		       m.from_location_id, fl.external_key, fl.name,
+		       m.location_id, tl.external_key, tl.name
+		FROM moves m
+		JOIN trakrf.assets a ON a.id = m.asset_id AND a.org_id = $1
+		LEFT JOIN trakrf.locations fl ON fl.id = m.from_location_id AND fl.org_id = $1
+		LEFT JOIN trakrf.locations tl ON tl.id = m.location_id AND tl.org_id = $1
+		ORDER BY m.timestamp DESC, m.asset_id DESC`
+
+	items := []integration.MovedAssetItem{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, orgID, at, id, limit, time.Now().Add(-MovedAssetsLookback))
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var it integration.MovedAssetItem
+			if err := rows.Scan(&it.MovedAt, &it.AssetID, &it.AssetExternalKey, &it.AssetName,
+				&it.FromLocationID, &it.FromLocationExternalKey, &it.FromLocationName,
+				&it.ToLocationID, &it.ToLocationExternalKey, &it.ToLocationName); err != nil {
+				return err
+			}
+			it.ID = fmt.Sprintf("%d-%d", it.AssetID, it.MovedAt.UnixNano())
+			items = append(items, it)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list moved assets: %w", err)
+	}
+	return items, nil
+}
@@ -6,12 +6,27 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/stock"
 )
 
+// cycleCountReason is the ledger reason on counts recorded by inventory save.
+var cycleCountReason = "cycle count"
+
 // SaveInventoryRequest represents the request to save inventory scans
 type SaveInventoryRequest struct {
 	LocationID int
 	AssetIDs   []int
+	// Counts are consumables counted at the location during the same cycle
+	// count; each replaces the consumable's stock level there.
+	Counts []StockCount
+	UserID *int
+}
+
+// StockCount is a counted quantity of one consumable.
+type StockCount struct {
+	AssetID  int
+	Quantity int
 }
 
 // SaveInventoryResult represents the result of saving inventory scans
@@ -20,6 +35,7 @@ type SaveInventoryResult struct {
 	LocationID   int       `json:"location_id"`
 	LocationName string    `json:"location_name"`
 	Timestamp    time.Time `json:"timestamp"`
+	Counted      int       `json:"counted,omitempty"`
 }
 
 // InventoryAccessError provides diagnostic context for 403 responses.
@@ -35,7 +51,7 @@ type SaveInventoryResult struct {
 // duplicates, soft-deleted IDs, and nonexistent IDs as well as genuine
 // cross-org, and the diagnostic was misleading in three of those four).
 type InventoryAccessError struct {
-	Reason     string // "location" or "assets"
+	Reason     string // "location", "location_validity" or "assets"
 	OrgID      int    `json:"-"`
 	LocationID int    `json:"-"`
 	AssetIDs   []int  `json:"-"`
@@ -47,13 +63,29 @@ type InventoryAccessError struct {
 	MissingAssetIDs     []int `json:"-"`
 	SoftDeletedAssetIDs []int `json:"-"`
 	CrossOrgAssetIDs    []int `json:"-"`
+	// OutOfValidityAssetIDs are live org assets whose valid_from/valid_to
+	// window does not contain the scan time.
+	OutOfValidityAssetIDs []int `json:"-"`
+	// DisposedAssetIDs are org assets retired by an executed disposal.
+	DisposedAssetIDs []int `json:"-"`
 }
 
 func (e *InventoryAccessError) Error() string {
 	switch e.Reason {
 	case "location":
 		return "location not found or access denied"
+	case "location_validity":
+		return "location is outside its validity window"
 	case "assets":
+		// Validity and disposal misses are the caller's own assets, so
+		// naming them leaks nothing; only report them when they are the
+		// sole cause.
+		if n := len(e.OutOfValidityAssetIDs); n > 0 && n == e.TotalCount-e.ValidCount {
+			return fmt.Sprintf("%d of %d assets are outside their validity window", n, e.TotalCount)
+		}
+		if n := len(e.DisposedAssetIDs); n > 0 && n == e.TotalCount-e.ValidCount {
+			return fmt.Sprintf("%d of %d assets have been disposed of", n, e.TotalCount)
+		}
 		// User-facing surface stays generic — listing missing vs. cross-org
 		// counts would let a caller probe other orgs by ID. Diagnostic detail
 		// goes to the handler log via the typed fields above.
@@ -82,9 +114,15 @@ func (e *InventoryAccessError) IsAccessDenied() bool {
 // access-denied guard for a payload that was actually well-formed, AND the
 // per-ID insert loop wrote redundant scan rows.
 //
+// Counts in req record consumables counted at the location in the same
+// transaction; a count naming an asset that is not a consumable fails the
+// save with ErrNotConsumable.
+//
 // On asset-validation failure the error names a real cause: each failing ID
-// is bucketed as missing, soft-deleted, or cross-org, and the bucket lists go
-// to the handler log. The user-facing surface stays generic ("N of M assets
+// is bucketed as missing, soft-deleted, cross-org, disposed, or outside its
+// validity window at the scan time, and the bucket lists go to the handler
+// log. A
+// location outside its validity window is rejected the same way. The user-facing surface stays generic ("N of M assets
 // are unavailable") so callers cannot probe other orgs by ID.
 func (s *Storage) SaveInventoryScans(ctx context.Context, orgID int, req SaveInventoryRequest) (*SaveInventoryResult, error) {
 	if len(req.AssetIDs) == 0 {
@@ -98,7 +136,9 @@ func (s *Storage) SaveInventoryScans(ctx context.Context, orgID int, req SaveInv
 
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
 		// 1. Validate location belongs to org and get its name
-		err := tx.QueryRow(ctx, `SELECT name FROM trakrf.locations WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, req.LocationID, orgID).Scan(&locationName)
+		var locationEffective bool
+		err := tx.QueryRow(ctx, `SELECT name, `+temporallyEffectiveAt("l", "$3")+` FROM trakrf.locations l WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`,
+			req.LocationID, orgID, timestamp).Scan(&locationName, &locationEffective)
 		if err != nil {
 			if err == pgx.ErrNoRows {
 				return &InventoryAccessError{
@@ -109,6 +149,13 @@ func (s *Storage) SaveInventoryScans(ctx context.Context, orgID int, req SaveInv
 			}
 			return fmt.Errorf("failed to validate location: %w", err)
 		}
+		if !locationEffective {
+			return &InventoryAccessError{
+				Reason:     "location_validity",
+				OrgID:      orgID,
+				LocationID: req.LocationID,
+			}
+		}
 
 		// 2. Classify every unique asset ID. The previous validation was a
 		// single COUNT(*) check that fired the same "access denied" for four
@@ -117,10 +164,12 @@ func (s *Storage) SaveInventoryScans(ctx context.Context, orgID int, req SaveInv
 		// query each ID's org_id and deleted_at and bucket the misses by
 		// cause.
 		rows, err := tx.Query(ctx, `
-			SELECT id, org_id, (deleted_at IS NOT NULL) AS is_deleted
-			FROM trakrf.assets
+			SELECT id, org_id, (deleted_at IS NOT NULL) AS is_deleted,
+			       (disposed_at IS NOT NULL) AS is_disposed,
+			       `+temporallyEffectiveAt("a", "$2")+` AS is_effective
+			FROM trakrf.assets a
 			WHERE id = ANY($1)
-		`, uniqueAssetIDs)
+		`, uniqueAssetIDs, timestamp)
 		if err != nil {
 			return fmt.Errorf("failed to validate assets: %w", err)
 		}
@@ -128,11 +177,13 @@ func (s *Storage) SaveInventoryScans(ctx context.Context, orgID int, req SaveInv
 			id        int
 			orgID     int
 			isDeleted bool
+			disposed  bool
+			effective bool
 		}
 		foundByID := make(map[int]assetRow, len(uniqueAssetIDs))
 		for rows.Next() {
 			var r assetRow
-			if err := rows.Scan(&r.id, &r.orgID, &r.isDeleted); err != nil {
+			if err := rows.Scan(&r.id, &r.orgID, &r.isDeleted, &r.disposed, &r.effective); err != nil {
 				rows.Close()
 				return fmt.Errorf("scan asset validation row: %w", err)
 			}
@@ -144,7 +195,7 @@ func (s *Storage) SaveInventoryScans(ctx context.Context, orgID int, req SaveInv
 		}
 		rows.Close()
 
-		var missing, softDeleted, crossOrg []int
+		var missing, softDeleted, crossOrg, disposed, outOfValidity []int
 		for _, id := range uniqueAssetIDs {
 			r, ok := foundByID[id]
 			switch {
@@ -154,27 +205,49 @@ func (s *Storage) SaveInventoryScans(ctx context.Context, orgID int, req SaveInv
 				crossOrg = append(crossOrg, id)
 			case r.isDeleted:
 				softDeleted = append(softDeleted, id)
+			case r.disposed:
+				disposed = append(disposed, id)
+			case !r.effective:
+				outOfValidity = append(outOfValidity, id)
 			}
 		}
-		invalidCount := len(missing) + len(softDeleted) + len(crossOrg)
+		invalidCount := len(missing) + len(softDeleted) + len(crossOrg) + len(disposed) + len(outOfValidity)
 		if invalidCount > 0 {
 			return &InventoryAccessError{
-				Reason:              "assets",
-				OrgID:               orgID,
-				AssetIDs:            uniqueAssetIDs,
-				ValidCount:          len(uniqueAssetIDs) - invalidCount,
-				TotalCount:          len(uniqueAssetIDs),
-				MissingAssetIDs:     missing,
-				SoftDeletedAssetIDs: softDeleted,
-				CrossOrgAssetIDs:    crossOrg,
+				Reason:                "assets",
+				OrgID:                 orgID,
+				AssetIDs:              uniqueAssetIDs,
+				ValidCount:            len(uniqueAssetIDs) - invalidCount,
+				TotalCount:            len(uniqueAssetIDs),
+				MissingAssetIDs:       missing,
+				SoftDeletedAssetIDs:   softDeleted,
+				CrossOrgAssetIDs:      crossOrg,
+				OutOfValidityAssetIDs: outOfValidity,
+				DisposedAssetIDs:      disposed,
 			}
 		}
 
 		// 3. Batch INSERT into asset_scans — one row per unique asset
 		insertQuery := `INSERT INTO trakrf.asset_scans (timestamp, org_id, asset_id, location_id, scan_point_id, tag_scan_id) VALUES ($1, $2, $3, $4, NULL, NULL)`
+		batch := &pgx.Batch{}
 		for _, assetID := range uniqueAssetIDs {
-			if _, err := tx.Exec(ctx, insertQuery, timestamp, orgID, assetID, req.LocationID); err != nil {
-				return fmt.Errorf("failed to insert asset scan for asset %d: %w", assetID, err)
+			batch.Queue(insertQuery, timestamp, orgID, assetID, req.LocationID)
+		}
+		if _, err := execBatch(ctx, tx, batch); err != nil {
+			return fmt.Errorf("failed to insert asset scans: %w", err)
+		}
+
+		// 4. Counted consumables replace their stock level at the location.
+		for _, c := range req.Counts {
+			_, err := s.applyStockAdjustment(ctx, tx, orgID, c.AssetID, stock.NewAdjustment{
+				LocationID: req.LocationID,
+				Kind:       stock.KindCount,
+				Quantity:   c.Quantity,
+				Reason:     &cycleCountReason,
+				UserID:     req.UserID,
+			})
+			if err != nil {
+				return err
 			}
 		}
 
@@ -190,6 +263,7 @@ func (s *Storage) SaveInventoryScans(ctx context.Context, orgID int, req SaveInv
 		LocationID:   req.LocationID,
 		LocationName: locationName,
 		Timestamp:    timestamp,
+		Counted:      len(req.Counts),
 	}, nil
 }
 
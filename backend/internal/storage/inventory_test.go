@@ -69,4 +69,33 @@ func TestInventoryErrorTypes(t *testing.T) {
 		assert.NotContains(t, err.Error(), "soft")
 		assert.True(t, err.IsAccessDenied())
 	})
+	t.Run("validity misses are named only when they are the sole cause", func(t *testing.T) {
+		err := &InventoryAccessError{
+			Reason:                "assets",
+			ValidCount:            1,
+			TotalCount:            3,
+			OutOfValidityAssetIDs: []int{2, 3},
+		}
+		assert.Equal(t, "2 of 3 assets are outside their validity window", err.Error())
+
+		err.MissingAssetIDs = []int{4}
+		err.ValidCount = 0
+		assert.Equal(t, "3 of 3 assets are unavailable; refresh and try again", err.Error())
+
+		loc := &InventoryAccessError{Reason: "location_validity", LocationID: 456}
+		assert.Equal(t, "location is outside its validity window", loc.Error())
+	})
+	t.Run("disposed assets are named only when they are the sole cause", func(t *testing.T) {
+		err := &InventoryAccessError{
+			Reason:           "assets",
+			ValidCount:       2,
+			TotalCount:       3,
+			DisposedAssetIDs: []int{3},
+		}
+		assert.Equal(t, "1 of 3 assets have been disposed of", err.Error())
+
+		err.CrossOrgAssetIDs = []int{4}
+		err.TotalCount = 4
+		assert.Equal(t, "2 of 4 assets are unavailable; refresh and try again", err.Error())
+	})
 }
@@ -200,40 +200,35 @@ func (s *Storage) GetInvitationByTokenHash(ctx context.Context, tokenHash string
 
 // AcceptInvitation marks invitation as accepted and adds user to org (atomic)
 func (s *Storage) AcceptInvitation(ctx context.Context, inviteID, userID, orgID int, role string) error {
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// Set accepted_at
-	acceptQuery := `
-		UPDATE trakrf.org_invitations
-		SET accepted_at = NOW()
-		WHERE id = $1 AND accepted_at IS NULL
-	`
-	result, err := tx.Exec(ctx, acceptQuery, inviteID)
-	if err != nil {
-		return fmt.Errorf("failed to mark invitation accepted: %w", err)
-	}
-	if result.RowsAffected() == 0 {
-		return fmt.Errorf("invitation already accepted")
-	}
-
-	// Add user to org
-	addQuery := `
-		INSERT INTO trakrf.org_users (org_id, user_id, role)
-		VALUES ($1, $2, $3)
-	`
-	_, err = tx.Exec(ctx, addQuery, orgID, userID, role)
-	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
-			return fmt.Errorf("already a member")
+	return s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		// Set accepted_at
+		acceptQuery := `
+			UPDATE trakrf.org_invitations
+			SET accepted_at = NOW()
+			WHERE id = $1 AND accepted_at IS NULL
+		`
+		result, err := tx.Exec(ctx, acceptQuery, inviteID)
+		if err != nil {
+			return fmt.Errorf("failed to mark invitation accepted: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("invitation already accepted")
 		}
-		return fmt.Errorf("failed to add user to org: %w", err)
-	}
 
-	return tx.Commit(ctx)
+		// Add user to org
+		addQuery := `
+			INSERT INTO trakrf.org_users (org_id, user_id, role)
+			VALUES ($1, $2, $3)
+		`
+		_, err = tx.Exec(ctx, addQuery, orgID, userID, role)
+		if err != nil {
+			if strings.Contains(err.Error(), "duplicate key") {
+				return fmt.Errorf("already a member")
+			}
+			return fmt.Errorf("failed to add user to org: %w", err)
+		}
+		return nil
+	})
 }
 
 // InvitationInfo contains invitation details for unauthenticated users
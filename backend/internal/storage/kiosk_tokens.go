@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/kiosk"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+const kioskTokenSelect = `
+	SELECT k.id, k.name, k.location_id, l.name, k.created_by, k.last_used_at, k.revoked_at, k.created_at
+	FROM trakrf.kiosk_tokens k
+	JOIN trakrf.locations l ON l.id = k.location_id`
+
+func scanKioskToken(row pgx.Row) (*kiosk.Token, error) {
+	var t kiosk.Token
+	if err := row.Scan(&t.ID, &t.Name, &t.LocationID, &t.LocationName, &t.CreatedBy,
+		&t.LastUsedAt, &t.RevokedAt, &t.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// CreateKioskToken records a kiosk token for one of orgID's locations.
+// tokenHash is the SHA-256 of the secret handed to the display. The caller
+// checks the location belongs to orgID.
+func (s *Storage) CreateKioskToken(ctx context.Context, orgID, userID int, req kiosk.CreateRequest, tokenHash string) (*kiosk.Token, error) {
+	var id int
+	if err := s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.kiosk_tokens (org_id, location_id, name, token_hash, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`, orgID, req.LocationID, req.Name, tokenHash, userID).Scan(&id); err != nil {
+		return nil, fmt.Errorf("failed to create kiosk token: %w", err)
+	}
+	t, err := scanKioskToken(s.pool.QueryRow(ctx, kioskTokenSelect+` WHERE k.id = $1`, id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kiosk token: %w", err)
+	}
+	return t, nil
+}
+
+// ListKioskTokens returns orgID's kiosk tokens, revoked ones included, newest
+// first.
+func (s *Storage) ListKioskTokens(ctx context.Context, orgID int) ([]kiosk.Token, error) {
+	rows, err := s.pool.Query(ctx, kioskTokenSelect+`
+		WHERE k.org_id = $1
+		ORDER BY k.created_at DESC, k.id`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list kiosk tokens: %w", err)
+	}
+	defer rows.Close()
+
+	out := []kiosk.Token{}
+	for rows.Next() {
+		t, err := scanKioskToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan kiosk token: %w", err)
+		}
+		out = append(out, *t)
+	}
+	return out, rows.Err()
+}
+
+// RevokeKioskToken revokes one of orgID's kiosk tokens. It reports false when
+// the token does not exist in orgID; revoking twice is a no-op that reports
+// true.
+func (s *Storage) RevokeKioskToken(ctx context.Context, orgID, id int) (bool, error) {
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.kiosk_tokens SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1 AND org_id = $2`, id, orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke kiosk token: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetKioskPrincipal resolves a presented token's hash, or returns nil when
+// it is unknown, revoked, or its org or location is gone. A hit stamps
+// last_used_at, at most once a minute per token.
+func (s *Storage) GetKioskPrincipal(ctx context.Context, tokenHash string) (*kiosk.Principal, error) {
+	var p kiosk.Principal
+	err := s.pool.QueryRow(ctx, `
+		SELECT k.id, k.org_id, k.location_id
+		FROM trakrf.kiosk_tokens k
+		JOIN trakrf.organizations o ON o.id = k.org_id AND o.deleted_at IS NULL
+		JOIN trakrf.locations l ON l.id = k.location_id AND l.deleted_at IS NULL
+		WHERE k.token_hash = $1 AND k.revoked_at IS NULL`, tokenHash).Scan(&p.TokenID, &p.OrgID, &p.LocationID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve kiosk token: %w", err)
+	}
+	if _, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.kiosk_tokens SET last_used_at = NOW()
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`,
+		p.TokenID); err != nil {
+		return nil, fmt.Errorf("failed to touch kiosk token: %w", err)
+	}
+	return &p, nil
+}
+
+// GetKioskDashboard returns what is currently at the principal's location:
+// the assets whose latest scan is there, as the current-locations report
+// resolves it. Returns nil when the location no longer exists.
+func (s *Storage) GetKioskDashboard(ctx context.Context, p kiosk.Principal) (*kiosk.Dashboard, error) {
+	loc, err := s.GetLocationByID(ctx, p.OrgID, p.LocationID)
+	if err != nil || loc == nil {
+		return nil, err
+	}
+	filter := report.CurrentLocationFilter{LocationIDs: []int{p.LocationID}, Limit: kiosk.MaxDashboardAssets}
+	items, err := s.ListCurrentLocations(ctx, p.OrgID, filter)
+	if err != nil {
+		return nil, err
+	}
+	count, err := s.CountCurrentLocations(ctx, p.OrgID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &kiosk.Dashboard{
+		Location: kiosk.DashboardLocation{ID: loc.ID, ExternalKey: loc.ExternalKey, Name: loc.Name},
+		Count:    count,
+		Assets:   make([]kiosk.DashboardAsset, 0, len(items)),
+	}
+	for _, it := range items {
+		d.Assets = append(d.Assets, kiosk.DashboardAsset{
+			ExternalKey: it.AssetExternalKey,
+			Name:        it.AssetName,
+			LastSeen:    it.LastSeen,
+		})
+	}
+	return d, nil
+}
@@ -0,0 +1,370 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+)
+
+// ErrLabelPrinterLocationNotFound is returned when a printer is registered
+// at, or moved to, a location that is not a live location of the org.
+var ErrLabelPrinterLocationNotFound = errors.New("location not found")
+
+const labelPrinterColumns = `id, org_id, location_id, name, host, port, dpi, encode_rfid, is_active,
+	created_at, updated_at`
+
+func scanLabelPrinter(row pgx.Row) (*labelprint.Printer, error) {
+	var p labelprint.Printer
+	if err := row.Scan(&p.ID, &p.OrgID, &p.LocationID, &p.Name, &p.Host, &p.Port, &p.DPI,
+		&p.EncodeRFID, &p.IsActive, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreateLabelPrinter registers a printer at one of the org's locations. Port
+// defaults to 9100 and dpi to 203. It returns
+// ErrLabelPrinterLocationNotFound when the location is not the org's.
+func (s *Storage) CreateLabelPrinter(ctx context.Context, orgID int, req labelprint.CreatePrinterRequest) (*labelprint.Printer, error) {
+	port, dpi := labelprint.DefaultPort, 203
+	if req.Port != nil {
+		port = *req.Port
+	}
+	if req.DPI != nil {
+		dpi = *req.DPI
+	}
+	var p *labelprint.Printer
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		p, err = scanLabelPrinter(tx.QueryRow(ctx, `
+			INSERT INTO trakrf.label_printers (org_id, location_id, name, host, port, dpi, encode_rfid)
+			SELECT $1, l.id, $3, $4, $5, $6, $7
+			FROM trakrf.locations l
+			WHERE l.id = $2 AND l.org_id = $1 AND l.deleted_at IS NULL
+			RETURNING `+labelPrinterColumns,
+			orgID, req.LocationID, req.Name, req.Host, port, dpi, req.EncodeRFID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrLabelPrinterLocationNotFound
+		}
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrLabelPrinterLocationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create label printer: %w", err)
+	}
+	return p, nil
+}
+
+// GetLabelPrinter returns one printer, or nil when it is not in orgID.
+func (s *Storage) GetLabelPrinter(ctx context.Context, orgID, id int) (*labelprint.Printer, error) {
+	var p *labelprint.Printer
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		p, err = scanLabelPrinter(tx.QueryRow(ctx, `
+			SELECT `+labelPrinterColumns+`
+			FROM trakrf.label_printers
+			WHERE id = $1 AND org_id = $2`, id, orgID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			p = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get label printer: %w", err)
+	}
+	return p, nil
+}
+
+// ListLabelPrinters returns the org's printers by name, only those at
+// locationID when it is non-zero.
+func (s *Storage) ListLabelPrinters(ctx context.Context, orgID, locationID int) ([]labelprint.Printer, error) {
+	out := []labelprint.Printer{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT `+labelPrinterColumns+`
+			FROM trakrf.label_printers
+			WHERE org_id = $1 AND ($2 = 0 OR location_id = $2)
+			ORDER BY name, id`, orgID, locationID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			p, err := scanLabelPrinter(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list label printers: %w", err)
+	}
+	return out, nil
+}
+
+// UpdateLabelPrinter applies a partial update. It returns nil when the
+// printer is not in orgID, and ErrLabelPrinterLocationNotFound when it is
+// moved to a location that is not the org's.
+func (s *Storage) UpdateLabelPrinter(ctx context.Context, orgID, id int, req labelprint.UpdatePrinterRequest) (*labelprint.Printer, error) {
+	setClauses := []string{}
+	args := []any{id, orgID}
+	add := func(col string, val any) {
+		args = append(args, val)
+		setClauses = append(setClauses, fmt.Sprintf("%s = $%d", col, len(args)))
+	}
+	if req.LocationID != nil {
+		add("location_id", *req.LocationID)
+	}
+	if req.Name != nil {
+		add("name", *req.Name)
+	}
+	if req.Host != nil {
+		add("host", *req.Host)
+	}
+	if req.Port != nil {
+		add("port", *req.Port)
+	}
+	if req.DPI != nil {
+		add("dpi", *req.DPI)
+	}
+	if req.EncodeRFID != nil {
+		add("encode_rfid", *req.EncodeRFID)
+	}
+	if req.IsActive != nil {
+		add("is_active", *req.IsActive)
+	}
+	if len(setClauses) == 0 {
+		return s.GetLabelPrinter(ctx, orgID, id)
+	}
+
+	var p *labelprint.Printer
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if req.LocationID != nil {
+			var ok bool
+			if err := tx.QueryRow(ctx, `
+				SELECT EXISTS (SELECT 1 FROM trakrf.locations
+				               WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL)`,
+				*req.LocationID, orgID).Scan(&ok); err != nil {
+				return err
+			}
+			if !ok {
+				return ErrLabelPrinterLocationNotFound
+			}
+		}
+		var err error
+		p, err = scanLabelPrinter(tx.QueryRow(ctx, fmt.Sprintf(`
+			UPDATE trakrf.label_printers SET %s
+			WHERE id = $1 AND org_id = $2
+			RETURNING `+labelPrinterColumns, strings.Join(setClauses, ", ")), args...))
+		if errors.Is(err, pgx.ErrNoRows) {
+			p = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrLabelPrinterLocationNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to update label printer: %w", err)
+	}
+	return p, nil
+}
+
+// DeleteLabelPrinter removes a printer and its print jobs. It returns false
+// when the printer is not in orgID.
+func (s *Storage) DeleteLabelPrinter(ctx context.Context, orgID, id int) (bool, error) {
+	var n int64
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `DELETE FROM trakrf.label_printers WHERE id = $1 AND org_id = $2`, id, orgID)
+		n = tag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete label printer: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ListLabelAssets returns what goes on the labels of the given assets, in
+// the order asked for, with each asset's active RFID tag as its EPC. Assets
+// that are not live in orgID are left out.
+func (s *Storage) ListLabelAssets(ctx context.Context, orgID int, ids []int) ([]labelprint.LabelAsset, error) {
+	out := []labelprint.LabelAsset{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT a.id, a.external_key, a.name,
+			       (SELECT t.value FROM trakrf.tags t
+			        WHERE t.org_id = a.org_id AND t.asset_id = a.id AND t.type = 'rfid'
+			          AND t.is_active AND t.deleted_at IS NULL
+			        ORDER BY t.valid_from DESC, t.id DESC
+			        LIMIT 1)
+			FROM unnest($2::BIGINT[]) WITH ORDINALITY AS req(id, ord)
+			JOIN trakrf.assets a ON a.id = req.id
+			WHERE a.org_id = $1 AND a.deleted_at IS NULL
+			ORDER BY req.ord`, orgID, ids)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var a labelprint.LabelAsset
+			if err := rows.Scan(&a.ID, &a.ExternalKey, &a.Name, &a.EPC); err != nil {
+				return err
+			}
+			out = append(out, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list label assets: %w", err)
+	}
+	return out, nil
+}
+
+const printJobColumns = `id, org_id, printer_id, status, asset_ids, copies, label_count, requested_by,
+	attempts, error, started_at, completed_at, created_at, updated_at`
+
+func scanPrintJob(row pgx.Row, withZPL bool) (*labelprint.Job, error) {
+	var j labelprint.Job
+	dest := []any{&j.ID, &j.OrgID, &j.PrinterID, &j.Status, &j.AssetIDs, &j.Copies, &j.LabelCount,
+		&j.RequestedBy, &j.Attempts, &j.Error, &j.StartedAt, &j.CompletedAt, &j.CreatedAt, &j.UpdatedAt}
+	if withZPL {
+		dest = append(dest, &j.ZPL)
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// CreatePrintJob queues job for the label_print job. The caller has
+// rendered its ZPL and checked the printer is the org's.
+func (s *Storage) CreatePrintJob(ctx context.Context, orgID int, job labelprint.Job) (*labelprint.Job, error) {
+	j, err := scanPrintJob(s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.print_jobs (org_id, printer_id, asset_ids, copies, label_count, zpl, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+printJobColumns,
+		orgID, job.PrinterID, job.AssetIDs, job.Copies, job.LabelCount, job.ZPL, job.RequestedBy), false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create print job: %w", err)
+	}
+	return j, nil
+}
+
+// GetPrintJob returns one job with its ZPL, or nil when it is not in orgID.
+func (s *Storage) GetPrintJob(ctx context.Context, orgID, id int) (*labelprint.Job, error) {
+	j, err := scanPrintJob(s.pool.QueryRow(ctx, `
+		SELECT `+printJobColumns+`, zpl
+		FROM trakrf.print_jobs
+		WHERE id = $1 AND org_id = $2`, id, orgID), true)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get print job: %w", err)
+	}
+	return j, nil
+}
+
+// ListPrintJobs returns orgID's print jobs, newest first, without their ZPL.
+func (s *Storage) ListPrintJobs(ctx context.Context, orgID int, f labelprint.JobFilter) ([]labelprint.Job, error) {
+	var status any
+	if f.Status != "" {
+		status = f.Status
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+printJobColumns+`
+		FROM trakrf.print_jobs
+		WHERE org_id = $1
+		  AND ($2 = 0 OR printer_id = $2)
+		  AND ($3::TEXT IS NULL OR status = $3)
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5`, orgID, f.PrinterID, status, f.Limit, f.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list print jobs: %w", err)
+	}
+	defer rows.Close()
+
+	out := []labelprint.Job{}
+	for rows.Next() {
+		j, err := scanPrintJob(rows, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan print job: %w", err)
+		}
+		out = append(out, *j)
+	}
+	return out, rows.Err()
+}
+
+// ClaimPrintJob marks the oldest due job printing, counts the attempt and
+// returns the job with its ZPL, or nil when none is due. A printing job whose
+// claim is older than lease is taken back.
+func (s *Storage) ClaimPrintJob(ctx context.Context, lease time.Duration) (*labelprint.Job, error) {
+	j, err := scanPrintJob(s.pool.QueryRow(ctx, `
+		UPDATE trakrf.print_jobs
+		SET status = 'printing', started_at = NOW(), attempts = attempts + 1
+		WHERE id = (
+			SELECT id FROM trakrf.print_jobs
+			WHERE (status = 'pending' AND next_attempt_at <= NOW())
+			   OR (status = 'printing' AND started_at < NOW() - make_interval(secs => $1))
+			ORDER BY next_attempt_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+printJobColumns+`, zpl`, lease.Seconds()), true)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim print job: %w", err)
+	}
+	return j, nil
+}
+
+// CompletePrintJob records that a job's ZPL reached the printer.
+func (s *Storage) CompletePrintJob(ctx context.Context, id int) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.print_jobs
+		SET status = 'completed', completed_at = NOW(), error = NULL
+		WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to complete print job: %w", err)
+	}
+	return nil
+}
+
+// RetryPrintJob puts a job whose printer could not be reached back in the
+// queue until at.
+func (s *Storage) RetryPrintJob(ctx context.Context, id int, msg string, at time.Time) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.print_jobs
+		SET status = 'pending', error = $2, next_attempt_at = $3
+		WHERE id = $1`, id, msg, at)
+	if err != nil {
+		return fmt.Errorf("failed to retry print job: %w", err)
+	}
+	return nil
+}
+
+// FailPrintJob records why a job will not be printed.
+func (s *Storage) FailPrintJob(ctx context.Context, id int, msg string) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.print_jobs
+		SET status = 'failed', error = $2, completed_at = NOW()
+		WHERE id = $1`, id, msg)
+	if err != nil {
+		return fmt.Errorf("failed to fail print job: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/labelprint"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestLabelPrinting_PrintersAndJobQueue(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	otherOrg := testutil.CreateTestAccount(t, pool)
+	var dock, foreign int
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO trakrf.locations (org_id, external_key, name)
+		VALUES ($1, 'DOCK', 'Dock') RETURNING id`, orgID).Scan(&dock))
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO trakrf.locations (org_id, external_key, name)
+		VALUES ($1, 'ELSEWHERE', 'Elsewhere') RETURNING id`, otherOrg).Scan(&foreign))
+
+	_, err := store.CreateLabelPrinter(ctx, orgID, labelprint.CreatePrinterRequest{LocationID: foreign, Name: "Z", Host: "10.0.0.9"})
+	assert.ErrorIs(t, err, storage.ErrLabelPrinterLocationNotFound)
+
+	p, err := store.CreateLabelPrinter(ctx, orgID, labelprint.CreatePrinterRequest{
+		LocationID: dock, Name: "Dock Zebra", Host: "10.0.0.9", EncodeRFID: true})
+	require.NoError(t, err)
+	assert.Equal(t, 9100, p.Port)
+	assert.Equal(t, 203, p.DPI)
+	assert.True(t, p.IsActive)
+
+	atDock, err := store.ListLabelPrinters(ctx, orgID, dock)
+	require.NoError(t, err)
+	assert.Len(t, atDock, 1)
+	none, err := store.ListLabelPrinters(ctx, otherOrg, 0)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	dpi := 300
+	p, err = store.UpdateLabelPrinter(ctx, orgID, p.ID, labelprint.UpdatePrinterRequest{DPI: &dpi})
+	require.NoError(t, err)
+	assert.Equal(t, 300, p.DPI)
+	_, err = store.UpdateLabelPrinter(ctx, orgID, p.ID, labelprint.UpdatePrinterRequest{LocationID: &foreign})
+	assert.ErrorIs(t, err, storage.ErrLabelPrinterLocationNotFound)
+
+	// Labels come back in the order asked for, with the active RFID tag.
+	drill := testutil.CreateTestAsset(t, pool, orgID, "DRILL-1")
+	ladder := testutil.CreateTestAsset(t, pool, orgID, "LADDER-1")
+	_, err = pool.Exec(ctx, `INSERT INTO trakrf.tags (org_id, asset_id, type, value) VALUES ($1, $2, 'rfid', 'E2801170000002')`, orgID, ladder.ID)
+	require.NoError(t, err)
+	assets, err := store.ListLabelAssets(ctx, orgID, []int{ladder.ID, drill.ID, 1})
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	assert.Equal(t, "LADDER-1", assets[0].ExternalKey)
+	require.NotNil(t, assets[0].EPC)
+	assert.Equal(t, "E2801170000002", *assets[0].EPC)
+	assert.Nil(t, assets[1].EPC)
+
+	var userID int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('ops', 'ops@x', 'stub') RETURNING id`,
+	).Scan(&userID))
+	job, err := store.CreatePrintJob(ctx, orgID, labelprint.Job{
+		PrinterID: p.ID, AssetIDs: []int{ladder.ID, drill.ID}, Copies: 1, LabelCount: 2,
+		ZPL: "^XA^XZ", RequestedBy: &userID})
+	require.NoError(t, err)
+	assert.Equal(t, labelprint.StatusPending, job.Status)
+	assert.Empty(t, job.ZPL, "only the single-job read returns the ZPL")
+
+	// Claim, fail to reach the printer, come back after the backoff.
+	claimed, err := store.ClaimPrintJob(ctx, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, 1, claimed.Attempts)
+	assert.Equal(t, "^XA^XZ", claimed.ZPL)
+	require.NoError(t, store.RetryPrintJob(ctx, job.ID, "connection refused", time.Now().Add(time.Hour)))
+	again, err := store.ClaimPrintJob(ctx, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, again, "not due until the backoff passes")
+
+	_, err = pool.Exec(ctx, `UPDATE trakrf.print_jobs SET next_attempt_at = NOW() WHERE id = $1`, job.ID)
+	require.NoError(t, err)
+	claimed, err = store.ClaimPrintJob(ctx, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, claimed)
+	assert.Equal(t, 2, claimed.Attempts)
+	require.NoError(t, store.CompletePrintJob(ctx, job.ID))
+
+	got, err := store.GetPrintJob(ctx, orgID, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, labelprint.StatusCompleted, got.Status)
+	assert.Nil(t, got.Error)
+	assert.Equal(t, []int{ladder.ID, drill.ID}, got.AssetIDs)
+	hidden, err := store.GetPrintJob(ctx, otherOrg, job.ID)
+	require.NoError(t, err)
+	assert.Nil(t, hidden)
+
+	failed := labelprint.JobFilter{Status: labelprint.StatusFailed, Limit: 10}
+	list, err := store.ListPrintJobs(ctx, orgID, failed)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+	list, err = store.ListPrintJobs(ctx, orgID, labelprint.JobFilter{PrinterID: p.ID, Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, list, 1)
+
+	deleted, err := store.DeleteLabelPrinter(ctx, orgID, p.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	gone, err := store.GetPrintJob(ctx, orgID, job.ID)
+	require.NoError(t, err)
+	assert.Nil(t, gone, "jobs go with their printer")
+}
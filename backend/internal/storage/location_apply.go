@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/location"
+)
+
+// errApplyDryRun rolls back a dry-run apply once its changes are planned.
+var errApplyDryRun = errors.New("dry run")
+
+// applyRow is a live location as an apply sees it.
+type applyRow struct {
+	id          int
+	name        string
+	description string
+	parentID    *int
+}
+
+// ApplyLocationTree makes orgID's location hierarchy match tree in one
+// transaction: locations are matched by external_key (or renamed_from), then
+// created, renamed, moved under their tree parent, or updated to the tree's
+// name and description. Nothing else about a location changes, and live
+// locations the tree does not mention are left alone and reported as
+// unmanaged. Parents are placed before their children, so a move never
+// closes a cycle. With dryRun the transaction is rolled back and only the
+// plan is returned. The tree must have passed location.CheckApplyTree.
+func (s *Storage) ApplyLocationTree(ctx context.Context, orgID int, tree []location.ApplyNode, dryRun bool) (*location.ApplyResult, error) {
+	var res location.ApplyResult
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		res = location.ApplyResult{DryRun: dryRun, Changes: []location.ApplyChange{}}
+
+		live := map[string]*applyRow{}
+		rows, err := tx.Query(ctx, `
+			SELECT id, external_key, name, COALESCE(description, ''), parent_location_id
+			FROM trakrf.locations
+			WHERE org_id = $1 AND deleted_at IS NULL`, orgID)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var key string
+			var r applyRow
+			if err := rows.Scan(&r.id, &key, &r.name, &r.description, &r.parentID); err != nil {
+				rows.Close()
+				return err
+			}
+			live[key] = &r
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		managed := map[int]bool{}
+		var walk func(nodes []location.ApplyNode, parentID *int, parentKey *string) error
+		walk = func(nodes []location.ApplyNode, parentID *int, parentKey *string) error {
+			for _, n := range nodes {
+				id, err := s.applyNode(ctx, tx, orgID, live, n, parentID, parentKey, &res)
+				if err != nil {
+					return fmt.Errorf("location %s: %w", n.ExternalKey, err)
+				}
+				managed[id] = true
+				key := n.ExternalKey
+				if err := walk(n.Children, &id, &key); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if err := walk(tree, nil, nil); err != nil {
+			return err
+		}
+
+		res.Unmanaged = []string{}
+		for key, r := range live {
+			if !managed[r.id] {
+				res.Unmanaged = append(res.Unmanaged, key)
+			}
+		}
+		sort.Strings(res.Unmanaged)
+
+		if dryRun {
+			return errApplyDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errApplyDryRun) {
+		return nil, fmt.Errorf("failed to apply location tree: %w", err)
+	}
+	return &res, nil
+}
+
+// applyNode brings one location in line with n under parentID, records the
+// change on res, and returns the location's id. live is kept up to date with
+// renames so later nodes see them.
+func (s *Storage) applyNode(ctx context.Context, tx pgx.Tx, orgID int, live map[string]*applyRow,
+	n location.ApplyNode, parentID *int, parentKey *string, res *location.ApplyResult) (int, error) {
+	change := location.ApplyChange{ExternalKey: n.ExternalKey, ParentExternalKey: parentKey}
+
+	row, ok := live[n.ExternalKey]
+	if !ok && n.RenamedFrom != nil {
+		if row, ok = live[*n.RenamedFrom]; ok {
+			if _, err := tx.Exec(ctx, `
+				UPDATE trakrf.locations SET external_key = $3, updated_at = NOW()
+				WHERE id = $1 AND org_id = $2`, row.id, orgID, n.ExternalKey); err != nil {
+				return 0, err
+			}
+			delete(live, *n.RenamedFrom)
+			live[n.ExternalKey] = row
+			change.RenamedFrom = n.RenamedFrom
+			change.Actions = append(change.Actions, location.ApplyActionRename)
+		}
+	}
+
+	if !ok {
+		var id int
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.locations
+				(org_id, external_key, name, description, parent_location_id, valid_from, is_active)
+			VALUES ($1, $2, $3, $4, $5, NOW(), true)
+			RETURNING id`,
+			orgID, n.ExternalKey, n.Name, n.Description, parentID).Scan(&id); err != nil {
+			return 0, err
+		}
+		live[n.ExternalKey] = &applyRow{id: id, name: n.Name, description: n.Description, parentID: parentID}
+		change.Actions = []string{location.ApplyActionCreate}
+		res.Changes = append(res.Changes, change)
+		return id, s.publish(ctx, tx, events.LocationCreated, orgID, id)
+	}
+
+	moved := !sameParent(row.parentID, parentID)
+	updated := row.name != n.Name || row.description != n.Description
+	if moved {
+		change.Actions = append(change.Actions, location.ApplyActionMove)
+	}
+	if updated {
+		change.Actions = append(change.Actions, location.ApplyActionUpdate)
+	}
+	if moved || updated {
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.locations
+			SET name = $3, description = $4, parent_location_id = $5, updated_at = NOW()
+			WHERE id = $1 AND org_id = $2`,
+			row.id, orgID, n.Name, n.Description, parentID); err != nil {
+			return 0, err
+		}
+		row.name, row.description, row.parentID = n.Name, n.Description, parentID
+	}
+	if len(change.Actions) == 0 {
+		res.Unchanged++
+		return row.id, nil
+	}
+	res.Changes = append(res.Changes, change)
+	return row.id, s.publish(ctx, tx, events.LocationUpdated, orgID, row.id)
+}
+
+func sameParent(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
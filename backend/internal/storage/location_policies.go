@@ -0,0 +1,222 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/trakrf/platform/backend/internal/models/location"
+)
+
+// locationSubtreeClause returns a predicate matching idCol against every live
+// location at or below the roots bound to $rootsArg (org bound to $1). UNION
+// rather than UNION ALL makes the walk terminate on a stored cycle.
+func locationSubtreeClause(idCol string, rootsArg int) string {
+	return locationSubtreeClauseFor(idCol, 1, rootsArg)
+}
+
+// locationSubtreeClauseFor is locationSubtreeClause for a query whose org is
+// bound to $orgArg.
+func locationSubtreeClauseFor(idCol string, orgArg, rootsArg int) string {
+	return fmt.Sprintf(`%[1]s IN (
+		WITH RECURSIVE granted AS (
+			SELECT id FROM trakrf.locations
+			WHERE org_id = $%[2]d AND id = ANY($%[3]d::bigint[]) AND deleted_at IS NULL
+			UNION
+			SELECT c.id FROM trakrf.locations c
+			JOIN granted g ON c.parent_location_id = g.id
+			WHERE c.org_id = $%[2]d AND c.deleted_at IS NULL
+		)
+		SELECT id FROM granted)`, idCol, orgArg, rootsArg)
+}
+
+// assetSubtreeClause returns a predicate matching assetCol against the assets
+// whose current location (their latest scan's) is at or below the roots bound
+// to $rootsArg (org bound to $1). An asset never scanned matches nothing.
+func assetSubtreeClause(assetCol string, rootsArg int) string {
+	return fmt.Sprintf(`%s IN (
+		SELECT cur.asset_id FROM (
+			SELECT asset_id, last(location_id, last_seen) AS location_id
+			FROM trakrf.asset_scan_latest
+			WHERE org_id = $1
+			GROUP BY asset_id
+		) cur
+		WHERE %s)`, assetCol, locationSubtreeClause("cur.location_id", rootsArg))
+}
+
+// scopeRoots is scope's roots as a query argument: NULL when the scope is
+// unrestricted, so a `$N::bigint[] IS NULL OR ...` predicate is a no-op.
+func scopeRoots(scope location.SubtreeScope) any {
+	if !scope.Restricted {
+		return nil
+	}
+	return scope.RootIDs
+}
+
+// ListLocationAccessPolicies returns the org's location access policies.
+func (s *Storage) ListLocationAccessPolicies(ctx context.Context, orgID int) ([]location.AccessPolicy, error) {
+	policies := []location.AccessPolicy{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT p.id, p.location_id, l.external_key, p.user_id, p.team_id, p.created_at
+			FROM trakrf.location_access_policies p
+			JOIN trakrf.locations l ON l.id = p.location_id
+			WHERE p.org_id = $1
+			ORDER BY l.external_key, p.id`, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var p location.AccessPolicy
+			if err := rows.Scan(&p.ID, &p.LocationID, &p.LocationExternalKey, &p.UserID, &p.TeamID, &p.CreatedAt); err != nil {
+				return err
+			}
+			policies = append(policies, p)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list location access policies: %w", err)
+	}
+	return policies, nil
+}
+
+// CreateLocationAccessPolicy inserts a policy. Returns nil when the location
+// (live) or the team does not exist in the org, and an "already exists" error
+// when the grantee already holds a policy on that location. The caller
+// verifies org membership of a user grantee.
+func (s *Storage) CreateLocationAccessPolicy(ctx context.Context, orgID int, req location.CreateAccessPolicyRequest) (*location.AccessPolicy, error) {
+	var out *location.AccessPolicy
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var p location.AccessPolicy
+		err := tx.QueryRow(ctx, `
+			WITH ins AS (
+				INSERT INTO trakrf.location_access_policies (org_id, location_id, user_id, team_id)
+				SELECT l.org_id, l.id, $3, $4
+				FROM trakrf.locations l
+				WHERE l.id = $2 AND l.org_id = $1 AND l.deleted_at IS NULL
+				  AND ($4::bigint IS NULL OR EXISTS (
+				      SELECT 1 FROM trakrf.teams t WHERE t.id = $4 AND t.org_id = $1))
+				RETURNING id, location_id, user_id, team_id, created_at
+			)
+			SELECT ins.id, ins.location_id, l.external_key, ins.user_id, ins.team_id, ins.created_at
+			FROM ins JOIN trakrf.locations l ON l.id = ins.location_id`,
+			orgID, req.LocationID, req.UserID, req.TeamID).Scan(
+			&p.ID, &p.LocationID, &p.LocationExternalKey, &p.UserID, &p.TeamID, &p.CreatedAt)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		out = &p
+		return nil
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_location_access_policies_") {
+			return nil, fmt.Errorf("a policy for this grantee on location %d already exists", req.LocationID)
+		}
+		return nil, fmt.Errorf("failed to create location access policy: %w", err)
+	}
+	return out, nil
+}
+
+// DeleteLocationAccessPolicy removes a policy. Returns false when it does not
+// exist in the org.
+func (s *Storage) DeleteLocationAccessPolicy(ctx context.Context, orgID, policyID int) (bool, error) {
+	var deleted bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `
+			DELETE FROM trakrf.location_access_policies
+			WHERE id = $1 AND org_id = $2`, policyID, orgID)
+		deleted = ct.RowsAffected() > 0
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete location access policy: %w", err)
+	}
+	return deleted, nil
+}
+
+// LocationSubtreeScopeForUser resolves the subtrees userID may see in orgID:
+// the roots of their own policies plus those of every team they belong to.
+// Org admins and superadmins are never restricted, and neither is a member
+// with no policies at all.
+func (s *Storage) LocationSubtreeScopeForUser(ctx context.Context, orgID, userID int) (location.SubtreeScope, error) {
+	var (
+		exempt bool
+		scope  location.SubtreeScope
+	)
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, `
+			SELECT COALESCE(ou.role::text = 'admin', false) OR COALESCE(u.is_superadmin, false),
+			       ARRAY(SELECT DISTINCT p.location_id
+			             FROM trakrf.location_access_policies p
+			             WHERE p.org_id = $1
+			               AND (p.user_id = $2 OR p.team_id IN (
+			                   SELECT m.team_id FROM trakrf.team_members m
+			                   WHERE m.org_id = $1 AND m.user_id = $2))
+			             ORDER BY p.location_id)
+			FROM (SELECT 1) one
+			LEFT JOIN trakrf.org_users ou
+			       ON ou.org_id = $1 AND ou.user_id = $2 AND ou.deleted_at IS NULL
+			LEFT JOIN trakrf.users u ON u.id = $2 AND u.deleted_at IS NULL`,
+			orgID, userID).Scan(&exempt, &scope.RootIDs)
+	})
+	if err != nil {
+		return location.SubtreeScope{}, fmt.Errorf("failed to resolve location scope: %w", err)
+	}
+	scope.Restricted = !exempt && len(scope.RootIDs) > 0
+	return scope, nil
+}
+
+// IsLocationInSubtrees reports whether locationID is one of rootIDs or a
+// descendant of one, walking up the parent chain.
+func (s *Storage) IsLocationInSubtrees(ctx context.Context, orgID, locationID int, rootIDs []int) (bool, error) {
+	query := `
+		WITH RECURSIVE chain AS (
+			SELECT id, parent_location_id
+			FROM trakrf.locations
+			WHERE id = $2 AND org_id = $1
+			UNION
+			SELECT p.id, p.parent_location_id
+			FROM trakrf.locations p
+			JOIN chain c ON p.id = c.parent_location_id
+			WHERE p.org_id = $1 AND p.deleted_at IS NULL
+		)
+		SELECT EXISTS(SELECT 1 FROM chain WHERE id = ANY($3::bigint[]))
+	`
+	var inside bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, orgID, locationID, rootIDs).Scan(&inside)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check location subtree: %w", err)
+	}
+	return inside, nil
+}
+
+// IsAssetInSubtrees reports whether assetID's current location (its latest
+// scan's) is at or below one of rootIDs. An asset never scanned, or last
+// scanned at a deleted location, is in no subtree.
+func (s *Storage) IsAssetInSubtrees(ctx context.Context, orgID, assetID int, rootIDs []int) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM (
+				SELECT last(location_id, last_seen) AS location_id
+				FROM trakrf.asset_scan_latest
+				WHERE org_id = $1 AND asset_id = $3
+			) cur
+			WHERE ` + locationSubtreeClause("cur.location_id", 2) + `)
+	`
+	var inside bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, orgID, rootIDs, assetID).Scan(&inside)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check asset subtree: %w", err)
+	}
+	return inside, nil
+}
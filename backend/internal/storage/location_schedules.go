@@ -0,0 +1,333 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+// locationZonesCTE is a recursive CTE, zones(id, tz, depth), giving every
+// location of org $1 the time zone it reports in: its own, else the nearest
+// ancestor's, else UTC. Queries using it must start WITH RECURSIVE.
+const locationZonesCTE = `
+	zones AS (
+		SELECT id, COALESCE(time_zone, '` + location.DefaultTimeZone + `') AS tz, 0 AS depth
+		FROM trakrf.locations
+		WHERE org_id = $1 AND parent_location_id IS NULL
+		UNION ALL
+		SELECT c.id, COALESCE(c.time_zone, z.tz), z.depth + 1
+		FROM trakrf.locations c
+		JOIN zones z ON c.parent_location_id = z.id
+		WHERE z.depth < 64
+	)`
+
+// getLocationSchedule reads a live location's schedule inside tx, or nil
+// when there is no such location.
+func getLocationSchedule(ctx context.Context, tx pgx.Tx, orgID, locationID int) (*location.Schedule, error) {
+	sched := location.Schedule{LocationID: locationID, Shifts: []location.Shift{}}
+	err := tx.QueryRow(ctx, `
+		SELECT time_zone, trakrf.location_time_zone(id)
+		FROM trakrf.locations
+		WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, locationID, orgID).
+		Scan(&sched.TimeZone, &sched.EffectiveTimeZone)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT name, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), weekdays::INT[]
+		FROM trakrf.location_shifts
+		WHERE location_id = $1 AND org_id = $2
+		ORDER BY start_time, name`, locationID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sh location.Shift
+		if err := rows.Scan(&sh.Name, &sh.Start, &sh.End, &sh.Weekdays); err != nil {
+			return nil, err
+		}
+		sched.Shifts = append(sched.Shifts, sh)
+	}
+	return &sched, rows.Err()
+}
+
+// GetLocationSchedule returns the location's time zone and shifts, or nil
+// when it is not a live location of orgID.
+func (s *Storage) GetLocationSchedule(ctx context.Context, orgID, locationID int) (*location.Schedule, error) {
+	var out *location.Schedule
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		out, err = getLocationSchedule(ctx, tx, orgID, locationID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location schedule: %w", err)
+	}
+	return out, nil
+}
+
+// SetLocationSchedule replaces the location's time zone and shifts and
+// returns the result, or nil when it is not a live location of orgID.
+// Shifts with no weekdays are stored as running every day.
+func (s *Storage) SetLocationSchedule(ctx context.Context, orgID, locationID int, in location.ScheduleInput) (*location.Schedule, error) {
+	var out *location.Schedule
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE trakrf.locations SET time_zone = $3
+			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, locationID, orgID, in.TimeZone)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return nil
+		}
+		if _, err := tx.Exec(ctx, `
+			DELETE FROM trakrf.location_shifts WHERE location_id = $1 AND org_id = $2`, locationID, orgID); err != nil {
+			return err
+		}
+		for _, sh := range in.Shifts {
+			weekdays := sh.Weekdays
+			if len(weekdays) == 0 {
+				weekdays = []int{1, 2, 3, 4, 5, 6, 7}
+			}
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO trakrf.location_shifts (location_id, org_id, name, start_time, end_time, weekdays)
+				VALUES ($1, $2, $3, $4::time, $5::time, $6::smallint[])`,
+				locationID, orgID, sh.Name, sh.Start, sh.End, weekdays); err != nil {
+				return err
+			}
+		}
+		out, err = getLocationSchedule(ctx, tx, orgID, locationID)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set location schedule: %w", err)
+	}
+	return out, nil
+}
+
+// locationWindow resolves a live location's effective time zone and the
+// start of a days-long report window ending at now: local midnight days-1
+// days before now's local date. ok is false when there is no such location.
+func locationWindow(ctx context.Context, tx pgx.Tx, orgID, locationID, days int, now time.Time) (tz string, from time.Time, ok bool, err error) {
+	err = tx.QueryRow(ctx, `
+		SELECT tz, (date_trunc('day', $3::timestamptz AT TIME ZONE tz) - make_interval(days => $4 - 1)) AT TIME ZONE tz
+		FROM (SELECT trakrf.location_time_zone(id) AS tz
+		      FROM trakrf.locations
+		      WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL) l`,
+		locationID, orgID, now, days).Scan(&tz, &from)
+	if err == pgx.ErrNoRows {
+		return "", time.Time{}, false, nil
+	}
+	return tz, from, err == nil, err
+}
+
+// shiftOccurrencesCTE is a CTE, occurrences(name, day, starts, ends), of
+// the shifts of location $2 in org $1 that start from $3 up to $4, with
+// their local date and instants in time zone $5. Wall-clock times are
+// converted per day, so occurrences keep their local hours across DST.
+const shiftOccurrencesCTE = `
+	occurrences AS (
+		SELECT * FROM (
+			SELECT sh.name, d::date AS day,
+			       (d::date + sh.start_time) AT TIME ZONE $5 AS starts,
+			       (d::date + sh.end_time
+			        + CASE WHEN sh.end_time <= sh.start_time THEN INTERVAL '1 day' ELSE INTERVAL '0' END
+			       ) AT TIME ZONE $5 AS ends
+			FROM trakrf.location_shifts sh
+			CROSS JOIN generate_series(($3::timestamptz AT TIME ZONE $5)::date::timestamp,
+			                           ($4::timestamptz AT TIME ZONE $5)::date::timestamp,
+			                           INTERVAL '1 day') d
+			WHERE sh.location_id = $2 AND sh.org_id = $1
+			  AND EXTRACT(ISODOW FROM d)::INT = ANY(sh.weekdays)
+		) o
+		WHERE o.starts >= $3 AND o.starts < $4
+	)`
+
+// GetLocationActivity rolls the location's sightings up by local day and by
+// shift occurrence, over the days local days ending with the one now falls
+// in. It returns nil when the location is not a live one of orgID.
+func (s *Storage) GetLocationActivity(ctx context.Context, orgID, locationID, days int, now time.Time) (*report.LocationActivityReport, error) {
+	var out *report.LocationActivityReport
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		r := report.LocationActivityReport{
+			LocationID: locationID,
+			To:         now,
+			Daily:      []report.DailyActivity{},
+			Shifts:     []report.ShiftActivity{},
+		}
+		var ok bool
+		var err error
+		r.TimeZone, r.From, ok, err = locationWindow(ctx, tx, orgID, locationID, days, now)
+		if err != nil || !ok {
+			return err
+		}
+
+		if err := tx.QueryRow(ctx, `
+			SELECT COUNT(DISTINCT asset_id) FROM trakrf.asset_scan_latest
+			WHERE org_id = $1 AND location_id = $2 AND bucket >= $3 AND bucket < $4`,
+			orgID, locationID, r.From, now).Scan(&r.AssetsSeen); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT to_char(d, 'YYYY-MM-DD'), COUNT(DISTINCT s.asset_id), COUNT(s.asset_id)
+			FROM generate_series(($3::timestamptz AT TIME ZONE $5)::date::timestamp,
+			                     ($4::timestamptz AT TIME ZONE $5)::date::timestamp,
+			                     INTERVAL '1 day') d
+			LEFT JOIN trakrf.asset_scan_latest s
+			       ON s.org_id = $1 AND s.location_id = $2
+			      AND s.bucket >= $3 AND s.bucket < $4
+			      AND (s.bucket AT TIME ZONE $5)::date = d::date
+			GROUP BY d
+			ORDER BY d`, orgID, locationID, r.From, now, r.TimeZone)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var d report.DailyActivity
+			if err := rows.Scan(&d.Date, &d.AssetsSeen, &d.AssetMinutes); err != nil {
+				rows.Close()
+				return err
+			}
+			r.Daily = append(r.Daily, d)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		rows, err = tx.Query(ctx, `
+			WITH `+shiftOccurrencesCTE+`
+			SELECT to_char(o.day, 'YYYY-MM-DD'), o.name, o.starts, o.ends, COUNT(DISTINCT s.asset_id)
+			FROM occurrences o
+			LEFT JOIN trakrf.asset_scan_latest s
+			       ON s.org_id = $1 AND s.location_id = $2
+			      AND s.bucket >= o.starts AND s.bucket < LEAST(o.ends, $4)
+			GROUP BY o.day, o.name, o.starts, o.ends
+			ORDER BY o.starts, o.name`, orgID, locationID, r.From, now, r.TimeZone)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var sa report.ShiftActivity
+			if err := rows.Scan(&sa.Date, &sa.Shift, &sa.Start, &sa.End, &sa.AssetsSeen); err != nil {
+				return err
+			}
+			sa.Ongoing = sa.End.After(now)
+			if r.AssetsSeen > 0 {
+				sa.Utilization = float64(sa.AssetsSeen) / float64(r.AssetsSeen)
+			}
+			r.Shifts = append(r.Shifts, sa)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		out = &r
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get location activity: %w", err)
+	}
+	return out, nil
+}
+
+// GetShiftPresence lists, for each occurrence of the location's shifts in
+// the filter's window, the live assets a reader saw at the location during
+// it. It returns nil when the location is not a live one of orgID.
+func (s *Storage) GetShiftPresence(ctx context.Context, orgID, locationID int, filter report.ShiftPresenceFilter) (*report.ShiftPresenceReport, error) {
+	var out *report.ShiftPresenceReport
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		r := report.ShiftPresenceReport{
+			LocationID:        locationID,
+			To:                filter.Now,
+			IncludesPersonnel: filter.IncludePersonnel,
+			Shifts:            []report.ShiftPresence{},
+		}
+		var ok bool
+		var err error
+		r.TimeZone, r.From, ok, err = locationWindow(ctx, tx, orgID, locationID, filter.Days, filter.Now)
+		if err != nil || !ok {
+			return err
+		}
+		anonymize, err := personnelAnonymized(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+
+		// Masked personnel are ordered by id, not by the names they hide.
+		rows, err := tx.Query(ctx, `
+			WITH `+shiftOccurrencesCTE+`
+			SELECT to_char(o.day, 'YYYY-MM-DD'), o.name, o.starts, o.ends,
+			       a.id, a.external_key, a.name, NULLIF(a.metadata->>'asset_type', ''),
+			       COALESCE(a.metadata->>'person' = 'true', false) AS person,
+			       MIN(s.bucket), MAX(s.last_seen), COUNT(s.asset_id)
+			FROM occurrences o
+			LEFT JOIN (trakrf.asset_scan_latest s
+			           JOIN trakrf.assets a
+			             ON a.id = s.asset_id AND a.org_id = $1 AND a.deleted_at IS NULL
+			            AND ($6 OR COALESCE(a.metadata->>'person' = 'true', false) = false))
+			       ON s.org_id = $1 AND s.location_id = $2
+			      AND s.bucket >= o.starts AND s.bucket < LEAST(o.ends, $4)
+			WHERE ($7::text = '' OR o.name = $7)
+			GROUP BY o.day, o.name, o.starts, o.ends, a.id
+			ORDER BY o.starts, o.name, person, CASE WHEN $8 AND a.metadata->>'person' = 'true' THEN NULL ELSE a.name END, a.id`,
+			orgID, locationID, r.From, filter.Now, r.TimeZone, filter.IncludePersonnel, filter.Shift, anonymize)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var occ report.ShiftPresence
+			var assetID, minutes *int
+			var key, name *string
+			var p report.ShiftPresent
+			var first, last *time.Time
+			if err := rows.Scan(&occ.Date, &occ.Shift, &occ.Start, &occ.End,
+				&assetID, &key, &name, &p.Type, &p.Person, &first, &last, &minutes); err != nil {
+				return err
+			}
+			n := len(r.Shifts)
+			if n == 0 || r.Shifts[n-1].Shift != occ.Shift || !r.Shifts[n-1].Start.Equal(occ.Start) {
+				occ.Ongoing = occ.End.After(filter.Now)
+				occ.Present = []report.ShiftPresent{}
+				r.Shifts = append(r.Shifts, occ)
+				n++
+			}
+			if assetID == nil {
+				continue
+			}
+			p.AssetID, p.ExternalKey, p.Name = *assetID, *key, *name
+			if anonymize && p.Person {
+				p.ExternalKey, p.Name = report.MaskPerson(p.AssetID)
+			}
+			p.FirstSeen, p.LastSeen, p.MinutesSeen = *first, *last, *minutes
+			cur := &r.Shifts[n-1]
+			cur.Present = append(cur.Present, p)
+			if p.Person {
+				cur.PersonnelCount++
+			} else {
+				cur.AssetCount++
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		out = &r
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shift presence: %w", err)
+	}
+	return out, nil
+}
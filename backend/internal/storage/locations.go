@@ -9,10 +9,14 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/trakrf/platform/backend/internal/events"
 	"github.com/trakrf/platform/backend/internal/models/location"
 	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/filterexpr"
 )
 
+// CreateLocation inserts request, or restores the soft-deleted location with
+// its external_key in its place when the org's deleted-key policy says so.
 func (s *Storage) CreateLocation(ctx context.Context, request location.Location) (*location.Location, error) {
 	// TRA-674: COALESCE(description, '') defends against legacy rows where the
 	// nullable text column holds SQL NULL — pgx cannot scan NULL into the
@@ -24,11 +28,30 @@ func (s *Storage) CreateLocation(ctx context.Context, request location.Location)
 	RETURNING id, org_id, name, external_key, parent_location_id,
 	          COALESCE(description, ''), valid_from, valid_to, is_active, created_at, updated_at, deleted_at
 	`
+	restore := `
+	UPDATE trakrf.locations
+	SET name = $1, external_key = $2, parent_location_id = $3, description = $4,
+	    valid_from = $5, valid_to = $6, is_active = $7, deleted_at = NULL, updated_at = NOW()
+	WHERE id = $9 AND org_id = $8
+	RETURNING id, org_id, name, external_key, parent_location_id,
+	          COALESCE(description, ''), valid_from, valid_to, is_active, created_at, updated_at, deleted_at
+	`
 	var loc location.Location
 	err := s.WithOrgTx(ctx, request.OrgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query, request.Name, request.ExternalKey, request.ParentID,
+		args := []any{request.Name, request.ExternalKey, request.ParentID,
 			request.Description, request.ValidFrom, request.ValidTo, request.IsActive, request.OrgID,
-		).Scan(&loc.ID, &loc.OrgID, &loc.Name, &loc.ExternalKey, &loc.ParentID,
+		}
+		if strings.TrimSpace(request.ExternalKey) != "" {
+			deletedID, err := claimDeletedKey(ctx, tx, "location", request.OrgID, request.ExternalKey)
+			if err != nil {
+				return err
+			}
+			if deletedID != 0 {
+				query = restore
+				args = append(args, deletedID)
+			}
+		}
+		return tx.QueryRow(ctx, query, args...).Scan(&loc.ID, &loc.OrgID, &loc.Name, &loc.ExternalKey, &loc.ParentID,
 			&loc.Description, &loc.ValidFrom, &loc.ValidTo,
 			&loc.IsActive, &loc.CreatedAt, &loc.UpdatedAt, &loc.DeletedAt,
 		)
@@ -38,6 +61,9 @@ func (s *Storage) CreateLocation(ctx context.Context, request location.Location)
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
 			return nil, fmt.Errorf("location with external_key %s already exists", request.ExternalKey)
 		}
+		if stderrors.Is(err, ErrDeletedKeyBlocked) {
+			return nil, err
+		}
 		if strings.Contains(err.Error(), "parent_location_id_fkey") {
 			return nil, fmt.Errorf("invalid parent_location_id: parent location does not exist")
 		}
@@ -115,7 +141,10 @@ func (s *Storage) UpdateLocation(ctx context.Context, orgID, id int, request loc
 
 	var updatedID int
 	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query, args...).Scan(&updatedID)
+		if err := tx.QueryRow(ctx, query, args...).Scan(&updatedID); err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.LocationUpdated, orgID, updatedID)
 	})
 
 	if err != nil {
@@ -187,12 +216,16 @@ func (s *Storage) RenameLocation(ctx context.Context, orgID, id int, newExternal
 			return err
 		}
 
-		return tx.QueryRow(ctx, `
+		err = tx.QueryRow(ctx, `
 			UPDATE trakrf.locations
 			SET external_key = $3, updated_at = NOW()
 			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
 			RETURNING id
 		`, id, orgID, newExternalKey).Scan(&updatedID)
+		if err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.LocationUpdated, orgID, updatedID)
 	})
 
 	if err != nil {
@@ -537,7 +570,10 @@ func (s *Storage) DeleteLocation(ctx context.Context, orgID, id int) (bool, erro
 			   SET deleted_at = (SELECT deleted_at FROM trakrf.locations WHERE id = $1 AND org_id = $2)
 			 WHERE location_id = $1 AND org_id = $2 AND deleted_at IS NULL
 		`, id, orgID)
-		return err
+		if err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.LocationDeleted, orgID, id)
 	})
 	if err != nil {
 		return false, fmt.Errorf("could not delete location: %w", err)
@@ -665,8 +701,9 @@ const ancestorsCTE = `
 // to parent), projected through LocationWithParent so every non-root carries
 // its parent's natural key and its tags — same shape as GET
 // /locations/{identifier}. Walks parent_location_id; both joins are scoped
-// to orgID for defence in depth.
-func (s *Storage) GetAncestors(ctx context.Context, orgID, id int) ([]location.LocationWithParent, error) {
+// to orgID for defence in depth. A restricted scope drops the ancestors above
+// the caller's granted subtrees.
+func (s *Storage) GetAncestors(ctx context.Context, orgID, id int, scope location.SubtreeScope) ([]location.LocationWithParent, error) {
 	query := ancestorsCTE + `
 		SELECT l.id, l.org_id, l.name, l.external_key, l.parent_location_id,
 		       COALESCE(l.description, ''), l.valid_from, l.valid_to, l.is_active, l.created_at, l.updated_at, l.deleted_at,
@@ -676,16 +713,18 @@ func (s *Storage) GetAncestors(ctx context.Context, orgID, id int) ([]location.L
 		LEFT JOIN trakrf.locations p
 			ON p.id = l.parent_location_id AND p.org_id = l.org_id
 		WHERE l.id != $2
+		  AND ($3::bigint[] IS NULL OR ` + locationSubtreeClause("l.id", 3) + `)
 		ORDER BY a.rdepth ASC
 	`
-	return s.scanHierarchyRows(ctx, query, "ancestor", orgID, orgID, id)
+	return s.scanHierarchyRows(ctx, query, "ancestor", orgID, orgID, id, scopeRoots(scope))
 }
 
 // ListAncestorsPaginated returns the ancestors of a location ordered root
 // first (depth-from-target ascending toward zero), with LIMIT/OFFSET applied.
 // The id ASC tiebreaker ensures fully-deterministic paging across requests
-// with the same offset.
-func (s *Storage) ListAncestorsPaginated(ctx context.Context, orgID, id, limit, offset int) ([]location.LocationWithParent, error) {
+// with the same offset. A restricted scope drops the ancestors above the
+// caller's granted subtrees.
+func (s *Storage) ListAncestorsPaginated(ctx context.Context, orgID, id int, scope location.SubtreeScope, limit, offset int) ([]location.LocationWithParent, error) {
 	query := ancestorsCTE + `
 		SELECT l.id, l.org_id, l.name, l.external_key, l.parent_location_id,
 		       COALESCE(l.description, ''), l.valid_from, l.valid_to, l.is_active, l.created_at, l.updated_at, l.deleted_at,
@@ -695,21 +734,23 @@ func (s *Storage) ListAncestorsPaginated(ctx context.Context, orgID, id, limit,
 		LEFT JOIN trakrf.locations p
 			ON p.id = l.parent_location_id AND p.org_id = l.org_id
 		WHERE l.id != $2
+		  AND ($5::bigint[] IS NULL OR ` + locationSubtreeClause("l.id", 5) + `)
 		ORDER BY a.rdepth ASC, l.id ASC
 		LIMIT $3 OFFSET $4
 	`
-	return s.scanHierarchyRows(ctx, query, "ancestor", orgID, orgID, id, limit, offset)
+	return s.scanHierarchyRows(ctx, query, "ancestor", orgID, orgID, id, limit, offset, scopeRoots(scope))
 }
 
 // CountAncestors returns the total number of ancestors of the given location,
 // matching the WHERE clause used by ListAncestorsPaginated.
-func (s *Storage) CountAncestors(ctx context.Context, orgID, id int) (int, error) {
+func (s *Storage) CountAncestors(ctx context.Context, orgID, id int, scope location.SubtreeScope) (int, error) {
 	query := ancestorsCTE + `
-		SELECT count(*) FROM ancestors WHERE id != $2
+		SELECT count(*) FROM ancestors
+		WHERE id != $2 AND ($3::bigint[] IS NULL OR ` + locationSubtreeClause("id", 3) + `)
 	`
 	var n int
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query, orgID, id).Scan(&n)
+		return tx.QueryRow(ctx, query, orgID, id, scopeRoots(scope)).Scan(&n)
 	})
 	return n, err
 }
@@ -914,18 +955,14 @@ func (s *Storage) scanHierarchyRows(
 	return out, nil
 }
 
-// CreateLocationWithTags creates a location with tags in a single transaction
+// CreateLocationWithTags creates a location with tags in a single transaction.
+// The external_key sequence lookup, the insert, and the read-back of the
+// created row all run on the same transaction (nested storage calls join it
+// via the transaction context), so the response is read from the transaction
+// that wrote the row rather than from a second connection after commit. A
+// caller-sent external_key of a soft-deleted location is handled per the
+// org's deleted-key policy, as CreateAssetWithTags does.
 func (s *Storage) CreateLocationWithTags(ctx context.Context, orgID int, request location.CreateLocationWithTagsRequest) (*location.LocationWithParent, error) {
-	// Auto-generate external_key if empty (TRA-665 / BB26 D3). Mirrors
-	// CreateAssetWithTags's ASSET-NNNN behavior.
-	if strings.TrimSpace(request.ExternalKey) == "" {
-		seq, err := s.GetNextLocationSequence(ctx, orgID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to generate external_key: %w", err)
-		}
-		request.ExternalKey = GenerateLocationExternalKey(seq)
-	}
-
 	tagsJSON, err := tagsToJSON(request.Tags)
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize tags: %w", err)
@@ -950,34 +987,75 @@ func (s *Storage) CreateLocationWithTags(ctx context.Context, orgID int, request
 
 	query := `SELECT * FROM trakrf.create_location_with_tags($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
-	var locationID int
-	var tagIDs []int
-
 	description := ""
 	if request.Description != nil {
 		description = *request.Description
 	}
 
-	err = s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query,
-			orgID,
-			request.ExternalKey,
-			request.Name,
-			description,
-			request.ParentID,
-			validFrom,
-			validTo,
-			isActive,
-			nil, // metadata - not used in CreateLocationRequest
-			tagsJSON,
-		).Scan(&locationID, &tagIDs)
-	})
+	var created *location.LocationWithParent
+	err = s.WithTx(ContextWithOrgID(ctx, orgID), func(ctx context.Context, tx pgx.Tx) error {
+		// Auto-generate external_key if empty (TRA-665 / BB26 D3). Mirrors
+		// CreateAssetWithTags's ASSET-NNNN behavior.
+		deletedID := 0
+		if strings.TrimSpace(request.ExternalKey) == "" {
+			seq, err := s.GetNextLocationSequence(ctx, orgID)
+			if err != nil {
+				return fmt.Errorf("failed to generate external_key: %w", err)
+			}
+			request.ExternalKey = GenerateLocationExternalKey(seq)
+		} else {
+			var err error
+			if deletedID, err = claimDeletedKey(ctx, tx, "location", orgID, request.ExternalKey); err != nil {
+				return err
+			}
+		}
+
+		var locationID int
+		if deletedID != 0 {
+			locationID = deletedID
+			if _, err := tx.Exec(ctx, `
+				UPDATE trakrf.locations
+				SET name = $3, description = $4, parent_location_id = $5, valid_from = $6,
+				    valid_to = $7, is_active = $8, deleted_at = NULL, updated_at = NOW()
+				WHERE id = $1 AND org_id = $2`,
+				locationID, orgID, request.Name, description, request.ParentID,
+				validFrom, validTo, isActive); err != nil {
+				return parseLocationWithTagsError(err, request.ExternalKey)
+			}
+			if err := restoreTags(ctx, tx, "location", orgID, locationID, tagsJSON); err != nil {
+				return parseLocationWithTagsError(err, request.ExternalKey)
+			}
+		} else {
+			var tagIDs []int
+			err := tx.QueryRow(ctx, query,
+				orgID,
+				request.ExternalKey,
+				request.Name,
+				description,
+				request.ParentID,
+				validFrom,
+				validTo,
+				isActive,
+				nil, // metadata - not used in CreateLocationRequest
+				tagsJSON,
+			).Scan(&locationID, &tagIDs)
+			if err != nil {
+				return parseLocationWithTagsError(err, request.ExternalKey)
+			}
+		}
+		if err := s.publish(ctx, tx, events.LocationCreated, orgID, locationID); err != nil {
+			return err
+		}
 
+		var err error
+		created, err = s.getLocationWithParentByID(ctx, orgID, locationID)
+		return err
+	})
 	if err != nil {
-		return nil, parseLocationWithTagsError(err, request.ExternalKey)
+		return nil, err
 	}
 
-	return s.getLocationWithParentByID(ctx, orgID, locationID)
+	return created, nil
 }
 
 // GetLocationViewByID fetches a location with its tags
@@ -1095,6 +1173,10 @@ func parseLocationWithTagsError(err error, externalKey string) error {
 		return fmt.Errorf("location with external_key %s already exists", externalKey)
 	}
 
+	if isTagCrossTypeErr(err) {
+		return fmt.Errorf("one or more tag values already exist under another tag type")
+	}
+
 	if strings.Contains(errStr, "tags_org_id_type_value") ||
 		(strings.Contains(errStr, "duplicate key") && strings.Contains(errStr, "tags")) {
 		return fmt.Errorf("one or more tags already exist")
@@ -1162,7 +1244,10 @@ func (s *Storage) GetLocationByExternalKey(
 func (s *Storage) ListLocationsFiltered(
 	ctx context.Context, orgID int, f location.ListFilter,
 ) ([]location.LocationWithParent, error) {
-	where, args := buildLocationsWhere(orgID, f)
+	where, args, err := buildLocationsWhere(orgID, f)
+	if err != nil {
+		return nil, err
+	}
 	orderBy := buildLocationsOrderBy(f.Sorts)
 
 	query := fmt.Sprintf(`
@@ -1217,7 +1302,7 @@ func (s *Storage) ListLocationsFiltered(
 	// Bulk-fetch tags for the returned locations, matching the
 	// assets-list pattern so the public list endpoint returns `[]` rather
 	// than `null` for locations without tags.
-	if len(out) > 0 {
+	if len(out) > 0 && wantsTags(f.Fields) {
 		ids := make([]int, len(out))
 		for i, l := range out {
 			ids[i] = l.ID
@@ -1241,7 +1326,10 @@ func (s *Storage) ListLocationsFiltered(
 func (s *Storage) CountLocationsFiltered(
 	ctx context.Context, orgID int, f location.ListFilter,
 ) (int, error) {
-	where, args := buildLocationsWhere(orgID, f)
+	where, args, err := buildLocationsWhere(orgID, f)
+	if err != nil {
+		return 0, err
+	}
 	query := fmt.Sprintf(`
 		SELECT COUNT(*)
 		FROM trakrf.locations l
@@ -1259,19 +1347,38 @@ func (s *Storage) CountLocationsFiltered(
 	return n, nil
 }
 
-func buildLocationsWhere(orgID int, f location.ListFilter) (string, []any) {
+// locationFilterColumns maps the fields of location.FilterFields to the columns of
+// the locations list query.
+var locationFilterColumns = map[string]string{
+	"external_key":        "l.external_key",
+	"name":                "l.name",
+	"description":         "l.description",
+	"id":                  "l.id",
+	"parent_id":           "l.parent_location_id",
+	"parent_external_key": "p.external_key",
+	"team_id":             "l.team_id",
+	"is_active":           "l.is_active",
+	"valid_from":          "l.valid_from",
+	"valid_to":            "l.valid_to",
+	"created_at":          "l.created_at",
+	"updated_at":          "l.updated_at",
+	"metadata":            "l.metadata",
+}
+
+func buildLocationsWhere(orgID int, f location.ListFilter) (string, []any, error) {
 	// TRA-659 / BB25 A3: include_deleted relaxes the soft-delete filter so
 	// callers reconciling against an external system of record can enumerate
 	// deleted rows alongside live ones. Temporal validity still applies.
 	// Orthogonal to is_active.
+	// AsOf moves the temporal validity check from now to that instant.
+	at, args := effectiveAtParam(f.AsOf, []any{orgID})
 	clauses := []string{
 		"l.org_id = $1",
-		temporallyEffective("l"),
+		temporallyEffectiveAt("l", at),
 	}
 	if !f.IncludeDeleted {
 		clauses = append(clauses, "l.deleted_at IS NULL")
 	}
-	args := []any{orgID}
 
 	if len(f.ParentIDs) > 0 {
 		args = append(args, f.ParentIDs)
@@ -1289,6 +1396,18 @@ func buildLocationsWhere(orgID int, f location.ListFilter) (string, []any) {
 		args = append(args, *f.IsActive)
 		clauses = append(clauses, fmt.Sprintf("l.is_active = $%d", len(args)))
 	}
+	if len(f.TeamIDs) > 0 {
+		args = append(args, f.TeamIDs)
+		clauses = append(clauses, fmt.Sprintf("l.team_id = ANY($%d::bigint[])", len(args)))
+	}
+	if f.TeamScope.Restricted {
+		args = append(args, f.TeamScope.TeamIDs)
+		clauses = append(clauses, fmt.Sprintf("l.team_id = ANY($%d::bigint[])", len(args)))
+	}
+	if f.SubtreeScope.Restricted {
+		args = append(args, f.SubtreeScope.RootIDs)
+		clauses = append(clauses, locationSubtreeClause("l.id", len(args)))
+	}
 	if f.Q != nil {
 		args = append(args, "%"+*f.Q+"%")
 		idx := len(args)
@@ -1296,11 +1415,19 @@ func buildLocationsWhere(orgID int, f location.ListFilter) (string, []any) {
 			"(l.name ILIKE $%d OR l.external_key ILIKE $%d OR l.description ILIKE $%d "+
 				"OR EXISTS (SELECT 1 FROM trakrf.tags i "+
 				"WHERE i.location_id = l.id AND i.is_active = true "+
-				"AND i.deleted_at IS NULL AND "+temporallyEffective("i")+
+				"AND i.deleted_at IS NULL AND "+temporallyEffectiveAt("i", at)+
 				" AND i.value ILIKE $%d))",
 			idx, idx, idx, idx))
 	}
-	return strings.Join(clauses, " AND "), args
+	if f.Filter != nil {
+		frag, filterArgs, err := filterexpr.SQL(f.Filter, locationFilterColumns, args)
+		if err != nil {
+			return "", nil, err
+		}
+		args = filterArgs
+		clauses = append(clauses, frag)
+	}
+	return strings.Join(clauses, " AND "), args, nil
 }
 
 func buildLocationsOrderBy(sorts []location.ListSort) string {
@@ -20,6 +20,13 @@ func setupLocationTest(t *testing.T) (*Storage, pgxmock.PgxPoolIface) {
 	return &Storage{pool: mock}, mock
 }
 
+// expectDeletedKeyPolicy expects the org's deleted-key policy read a create
+// makes for a sent external_key.
+func expectDeletedKeyPolicy(mock pgxmock.PgxPoolIface, policy string) {
+	mock.ExpectQuery(`deleted_key_policy`).WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"policy"}).AddRow(policy))
+}
+
 func TestCreateLocation(t *testing.T) {
 	storage, mock := setupLocationTest(t)
 
@@ -48,6 +55,7 @@ func TestCreateLocation(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
+	expectDeletedKeyPolicy(mock, "")
 	mock.ExpectQuery(`INSERT INTO trakrf.locations`).
 		WithArgs(
 			request.Name, request.ExternalKey, request.ParentID,
@@ -95,6 +103,7 @@ func TestCreateLocation_RootLocation(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
+	expectDeletedKeyPolicy(mock, "")
 	mock.ExpectQuery(`INSERT INTO trakrf.locations`).
 		WithArgs(
 			request.Name, request.ExternalKey, request.ParentID,
@@ -135,6 +144,7 @@ func TestCreateLocation_DuplicateExternalKey(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
+	expectDeletedKeyPolicy(mock, "")
 	mock.ExpectQuery(`INSERT INTO trakrf.locations`).
 		WithArgs(
 			request.Name, request.ExternalKey, request.ParentID,
@@ -174,6 +184,7 @@ func TestCreateLocation_InvalidParentID(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
+	expectDeletedKeyPolicy(mock, "")
 	mock.ExpectQuery(`INSERT INTO trakrf.locations`).
 		WithArgs(
 			request.Name, request.ExternalKey, request.ParentID,
@@ -191,6 +202,57 @@ func TestCreateLocation_InvalidParentID(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestCreateLocation_DeletedKeyBlocked(t *testing.T) {
+	storage, mock := setupLocationTest(t)
+
+	request := location.Location{Name: "Dock", ExternalKey: "dock", ValidFrom: time.Now(), IsActive: true, OrgID: 1}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
+	expectDeletedKeyPolicy(mock, "block")
+	mock.ExpectQuery(`SELECT id FROM trakrf.locations[\s\S]+deleted_at IS NOT NULL`).
+		WithArgs(1, "dock").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectRollback()
+
+	result, err := storage.CreateLocation(context.Background(), request)
+
+	assert.Nil(t, result)
+	assert.ErrorIs(t, err, ErrDeletedKeyBlocked)
+	assert.Contains(t, err.Error(), "location with external_key dock already exists")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCreateLocation_DeletedKeyAutoRestore(t *testing.T) {
+	storage, mock := setupLocationTest(t)
+
+	now := time.Now()
+	request := location.Location{Name: "Dock", ExternalKey: "dock", ValidFrom: now, IsActive: true, OrgID: 1}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
+	expectDeletedKeyPolicy(mock, "auto_restore")
+	mock.ExpectQuery(`SELECT id FROM trakrf.locations[\s\S]+deleted_at IS NOT NULL`).
+		WithArgs(1, "dock").
+		WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(`UPDATE trakrf.locations[\s\S]+deleted_at = NULL`).
+		WithArgs(request.Name, request.ExternalKey, request.ParentID, request.Description,
+			request.ValidFrom, request.ValidTo, request.IsActive, request.OrgID, 7).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "org_id", "name", "external_key", "parent_location_id",
+			"description", "valid_from", "valid_to", "is_active",
+			"created_at", "updated_at", "deleted_at",
+		}).AddRow(7, 1, "Dock", "dock", nil, "", now, nil, true, now.Add(-time.Hour), now, nil))
+	mock.ExpectCommit()
+
+	result, err := storage.CreateLocation(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, 7, result.ID)
+	assert.Nil(t, result.DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestUpdateLocation(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	require.NoError(t, err)
@@ -619,7 +681,7 @@ func TestGetAncestors(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
 	mock.ExpectQuery(`SELECT l.id, l.org_id, l.name, l.external_key`).
-		WithArgs(orgID, locationID).
+		WithArgs(orgID, locationID, nil).
 		WillReturnRows(rows)
 	mock.ExpectCommit()
 
@@ -632,7 +694,7 @@ func TestGetAncestors(t *testing.T) {
 		WillReturnRows(identifierRows)
 	mock.ExpectCommit()
 
-	results, err := storage.GetAncestors(context.Background(), orgID, locationID)
+	results, err := storage.GetAncestors(context.Background(), orgID, locationID, location.SubtreeScope{})
 
 	assert.NoError(t, err)
 	require.NotNil(t, results)
@@ -665,11 +727,11 @@ func TestGetAncestors_RootLocation(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
 	mock.ExpectQuery(`SELECT l.id, l.org_id, l.name, l.external_key`).
-		WithArgs(orgID, locationID).
+		WithArgs(orgID, locationID, nil).
 		WillReturnRows(rows)
 	mock.ExpectCommit()
 
-	results, err := storage.GetAncestors(context.Background(), orgID, locationID)
+	results, err := storage.GetAncestors(context.Background(), orgID, locationID, location.SubtreeScope{})
 
 	assert.NoError(t, err)
 	assert.Empty(t, results)
@@ -702,7 +764,7 @@ func TestListAncestorsPaginated(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
 	mock.ExpectQuery(`ORDER BY a.rdepth ASC, l.id ASC\s+LIMIT \$3 OFFSET \$4`).
-		WithArgs(orgID, locationID, limit, offset).
+		WithArgs(orgID, locationID, limit, offset, nil).
 		WillReturnRows(rows)
 	mock.ExpectCommit()
 
@@ -713,7 +775,7 @@ func TestListAncestorsPaginated(t *testing.T) {
 		WillReturnRows(pgxmock.NewRows([]string{"location_id", "id", "type", "value"}))
 	mock.ExpectCommit()
 
-	results, err := storage.ListAncestorsPaginated(context.Background(), orgID, locationID, limit, offset)
+	results, err := storage.ListAncestorsPaginated(context.Background(), orgID, locationID, location.SubtreeScope{}, limit, offset)
 
 	assert.NoError(t, err)
 	assert.Len(t, results, 1)
@@ -732,11 +794,11 @@ func TestCountAncestors(t *testing.T) {
 	mock.ExpectBegin()
 	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
 	mock.ExpectQuery(`SELECT count\(\*\) FROM ancestors`).
-		WithArgs(orgID, locationID).
+		WithArgs(orgID, locationID, nil).
 		WillReturnRows(pgxmock.NewRows([]string{"count"}).AddRow(2))
 	mock.ExpectCommit()
 
-	n, err := storage.CountAncestors(context.Background(), orgID, locationID)
+	n, err := storage.CountAncestors(context.Background(), orgID, locationID, location.SubtreeScope{})
 	assert.NoError(t, err)
 	assert.Equal(t, 2, n)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/trakrf/platform/backend/internal/models/maintenance"
+)
+
+// ErrMaintenanceOrgNotFound is returned when a maintenance window names an
+// org that does not exist.
+var ErrMaintenanceOrgNotFound = errors.New("organization not found")
+
+const maintenanceWindowColumns = `id, org_id, reason, starts_at, ends_at, created_by, created_at`
+
+// maintenanceWindowUnended matches windows in force or scheduled. A
+// cancelled window ends where it starts, so it never matches.
+const maintenanceWindowUnended = `(ends_at IS NULL OR (ends_at > NOW() AND ends_at > starts_at))`
+
+func scanMaintenanceWindow(row pgx.Row) (*maintenance.Window, error) {
+	var mw maintenance.Window
+	if err := row.Scan(&mw.ID, &mw.OrgID, &mw.Reason, &mw.StartsAt, &mw.EndsAt, &mw.CreatedBy, &mw.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &mw, nil
+}
+
+// ListMaintenanceWindows returns the windows that have not ended yet, both
+// in force and scheduled, soonest first.
+func (s *Storage) ListMaintenanceWindows(ctx context.Context) ([]maintenance.Window, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+maintenanceWindowColumns+`
+		FROM trakrf.maintenance_windows
+		WHERE `+maintenanceWindowUnended+`
+		ORDER BY starts_at, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []maintenance.Window{}
+	for rows.Next() {
+		mw, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, *mw)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
+// CreateMaintenanceWindow records a window opened by createdBy. A nil
+// StartsAt starts it now.
+func (s *Storage) CreateMaintenanceWindow(ctx context.Context, req maintenance.CreateWindowRequest, createdBy int) (*maintenance.Window, error) {
+	mw, err := scanMaintenanceWindow(s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.maintenance_windows (org_id, reason, starts_at, ends_at, created_by)
+		VALUES ($1, $2, COALESCE($3, NOW()), $4, $5)
+		RETURNING `+maintenanceWindowColumns,
+		req.OrgID, req.Reason, req.StartsAt, req.EndsAt, createdBy))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == "maintenance_windows_org_id_fkey" {
+			return nil, ErrMaintenanceOrgNotFound
+		}
+		return nil, fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+	return mw, nil
+}
+
+// EndMaintenanceWindow ends a window now, or cancels it if it has not
+// started. It returns nil when no unended window has this id.
+func (s *Storage) EndMaintenanceWindow(ctx context.Context, id int) (*maintenance.Window, error) {
+	mw, err := scanMaintenanceWindow(s.pool.QueryRow(ctx, `
+		UPDATE trakrf.maintenance_windows
+		SET ends_at = GREATEST(NOW(), starts_at)
+		WHERE id = $1 AND `+maintenanceWindowUnended+`
+		RETURNING `+maintenanceWindowColumns, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to end maintenance window: %w", err)
+	}
+	return mw, nil
+}
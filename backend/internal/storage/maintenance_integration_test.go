@@ -0,0 +1,68 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/models/maintenance"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestMaintenanceWindows_Lifecycle(t *testing.T) {
+	store := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+	pool := store.Pool().(*pgxpool.Pool)
+
+	orgID := testutil.NewOrgFactory().Create(t, pool)
+	var userID int
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO trakrf.users (name, email, password_hash)
+		VALUES ('Ops', 'ops@example.com', 'x') RETURNING id`).Scan(&userID))
+
+	global, err := store.CreateMaintenanceWindow(ctx, maintenance.CreateWindowRequest{Reason: "Database upgrade"}, userID)
+	require.NoError(t, err)
+	assert.Nil(t, global.OrgID)
+	assert.True(t, global.ActiveAt(time.Now()), "a window without starts_at starts now")
+
+	later := time.Now().Add(time.Hour)
+	scheduled, err := store.CreateMaintenanceWindow(ctx, maintenance.CreateWindowRequest{
+		OrgID: &orgID, Reason: "Data repair", StartsAt: &later,
+	}, userID)
+	require.NoError(t, err)
+	require.NotNil(t, scheduled.OrgID)
+	assert.Equal(t, orgID, *scheduled.OrgID)
+
+	missing := 1
+	_, err = store.CreateMaintenanceWindow(ctx, maintenance.CreateWindowRequest{OrgID: &missing, Reason: "x"}, userID)
+	assert.ErrorIs(t, err, storage.ErrMaintenanceOrgNotFound)
+
+	list, err := store.ListMaintenanceWindows(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, global.ID, list[0].ID, "soonest first")
+
+	ended, err := store.EndMaintenanceWindow(ctx, global.ID)
+	require.NoError(t, err)
+	require.NotNil(t, ended)
+	assert.False(t, ended.ActiveAt(time.Now().Add(time.Second)))
+
+	cancelled, err := store.EndMaintenanceWindow(ctx, scheduled.ID)
+	require.NoError(t, err)
+	require.NotNil(t, cancelled, "a scheduled window can be cancelled")
+	assert.False(t, cancelled.ActiveAt(later))
+
+	again, err := store.EndMaintenanceWindow(ctx, global.ID)
+	require.NoError(t, err)
+	assert.Nil(t, again, "an ended window cannot be ended twice")
+
+	list, err = store.ListMaintenanceWindows(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
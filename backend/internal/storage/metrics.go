@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector exports pgxpool.Stat as Prometheus metrics. Stats are read
+// at scrape time, so there is no sampling goroutine to manage.
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	maxConns          *prometheus.Desc
+	totalConns        *prometheus.Desc
+	acquiredConns     *prometheus.Desc
+	idleConns         *prometheus.Desc
+	constructingConns *prometheus.Desc
+	acquireCount      *prometheus.Desc
+	emptyAcquireCount *prometheus.Desc
+	canceledAcquire   *prometheus.Desc
+	acquireSeconds    *prometheus.Desc
+	newConns          *prometheus.Desc
+	lifetimeDestroys  *prometheus.Desc
+	idleDestroys      *prometheus.Desc
+}
+
+// NewPoolCollector returns a collector for pool's connection stats. Register it
+// on the default registry so serve's /metrics handler exposes it.
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc("db_pool_"+name, help, nil, nil)
+	}
+	return &poolCollector{
+		pool:              pool,
+		maxConns:          desc("max_conns", "Configured maximum pool size."),
+		totalConns:        desc("total_conns", "Connections currently in the pool (idle + acquired + constructing)."),
+		acquiredConns:     desc("acquired_conns", "Connections currently checked out."),
+		idleConns:         desc("idle_conns", "Idle connections in the pool."),
+		constructingConns: desc("constructing_conns", "Connections being established."),
+		acquireCount:      desc("acquires_total", "Successful connection acquires."),
+		emptyAcquireCount: desc("empty_acquires_total", "Acquires that had to wait for a connection (pool saturation signal)."),
+		canceledAcquire:   desc("canceled_acquires_total", "Acquires canceled by their context before a connection was available."),
+		acquireSeconds:    desc("acquire_seconds_total", "Cumulative time spent waiting to acquire connections."),
+		newConns:          desc("new_conns_total", "Connections opened."),
+		lifetimeDestroys:  desc("max_lifetime_destroys_total", "Connections closed for exceeding max lifetime."),
+		idleDestroys:      desc("max_idle_destroys_total", "Connections closed for exceeding max idle time."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		c.maxConns, c.totalConns, c.acquiredConns, c.idleConns, c.constructingConns,
+		c.acquireCount, c.emptyAcquireCount, c.canceledAcquire, c.acquireSeconds,
+		c.newConns, c.lifetimeDestroys, c.idleDestroys,
+	} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	st := c.pool.Stat()
+	gauge := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.GaugeValue, v)
+	}
+	counter := func(d *prometheus.Desc, v float64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, v)
+	}
+	gauge(c.maxConns, float64(st.MaxConns()))
+	gauge(c.totalConns, float64(st.TotalConns()))
+	gauge(c.acquiredConns, float64(st.AcquiredConns()))
+	gauge(c.idleConns, float64(st.IdleConns()))
+	gauge(c.constructingConns, float64(st.ConstructingConns()))
+	counter(c.acquireCount, float64(st.AcquireCount()))
+	counter(c.emptyAcquireCount, float64(st.EmptyAcquireCount()))
+	counter(c.canceledAcquire, float64(st.CanceledAcquireCount()))
+	counter(c.acquireSeconds, st.AcquireDuration().Seconds())
+	counter(c.newConns, float64(st.NewConnsCount()))
+	counter(c.lifetimeDestroys, float64(st.MaxLifetimeDestroyCount()))
+	counter(c.idleDestroys, float64(st.MaxIdleDestroyCount()))
+}
@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/device"
+)
+
+const mobileDeviceColumns = `id, user_id, platform, push_token, device_name, app_version, last_seen_at, created_at`
+
+func scanMobileDevice(row pgx.Row) (*device.Device, error) {
+	var d device.Device
+	if err := row.Scan(&d.ID, &d.UserID, &d.Platform, &d.PushToken, &d.DeviceName, &d.AppVersion,
+		&d.LastSeenAt, &d.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// RegisterMobileDevice records userID's push token. A token that is already
+// registered (the app re-registering on launch, or a handset signed in by a
+// different user) is updated in place and moves to userID.
+func (s *Storage) RegisterMobileDevice(ctx context.Context, userID int, req device.RegisterRequest) (*device.Device, error) {
+	d, err := scanMobileDevice(s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.mobile_devices (user_id, platform, push_token, device_name, app_version)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (push_token) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			platform = EXCLUDED.platform,
+			device_name = EXCLUDED.device_name,
+			app_version = EXCLUDED.app_version,
+			last_seen_at = CURRENT_TIMESTAMP
+		RETURNING `+mobileDeviceColumns,
+		userID, req.Platform, req.PushToken, req.DeviceName, req.AppVersion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to register mobile device: %w", err)
+	}
+	return d, nil
+}
+
+// ListMobileDevices returns userID's registered devices, most recently seen
+// first.
+func (s *Storage) ListMobileDevices(ctx context.Context, userID int) ([]device.Device, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+mobileDeviceColumns+`
+		FROM trakrf.mobile_devices
+		WHERE user_id = $1
+		ORDER BY last_seen_at DESC, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mobile devices: %w", err)
+	}
+	defer rows.Close()
+
+	out := []device.Device{}
+	for rows.Next() {
+		d, err := scanMobileDevice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan mobile device: %w", err)
+		}
+		out = append(out, *d)
+	}
+	return out, rows.Err()
+}
+
+// DeleteMobileDevice unregisters one of userID's devices. It reports false
+// when the device does not exist or belongs to another user.
+func (s *Storage) DeleteMobileDevice(ctx context.Context, userID, id int) (bool, error) {
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM trakrf.mobile_devices WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete mobile device: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteMobileDeviceByToken drops a push token the push service reported as
+// no longer valid (app uninstalled, token rotated).
+func (s *Storage) DeleteMobileDeviceByToken(ctx context.Context, pushToken string) error {
+	if _, err := s.pool.Exec(ctx,
+		`DELETE FROM trakrf.mobile_devices WHERE push_token = $1`, pushToken); err != nil {
+		return fmt.Errorf("failed to delete mobile device by token: %w", err)
+	}
+	return nil
+}
+
+// ListOrgsWithMobileDevices returns the live orgs with at least one member who
+// has a registered device: the orgs the overdue notifier has anyone to tell.
+// Runs with no org context; organizations and org_users have no RLS.
+func (s *Storage) ListOrgsWithMobileDevices(ctx context.Context) ([]int, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT DISTINCT ou.org_id
+		FROM trakrf.org_users ou
+		JOIN trakrf.organizations o ON o.id = ou.org_id
+		WHERE o.deleted_at IS NULL AND ou.status = 'active'
+		  AND EXISTS (SELECT 1 FROM trakrf.mobile_devices d WHERE d.user_id = ou.user_id)
+		ORDER BY ou.org_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orgs with mobile devices: %w", err)
+	}
+	defer rows.Close()
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// overdueCandidate is an asset carrying a due date it was not yet notified for.
+type overdueCandidate struct {
+	AssetID     int
+	ExternalKey string
+	Name        string
+	OwnerUserID *int
+	TeamID      *int
+	DueRaw      string
+	DueAt       time.Time
+}
+
+// pickOverdue keeps the candidates whose due date has passed, earliest due
+// first, up to limit. A due_at that is not RFC 3339 never becomes overdue.
+func pickOverdue(cands []overdueCandidate, now time.Time, limit int) []overdueCandidate {
+	var out []overdueCandidate
+	for _, c := range cands {
+		due, err := time.Parse(time.RFC3339, c.DueRaw)
+		if err != nil || due.After(now) {
+			continue
+		}
+		c.DueAt = due
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].DueAt.Before(out[j].DueAt) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// ClaimOverdueAssets records a notice for up to limit of orgID's live assets
+// whose metadata.due_at passed by now, raises asset.overdue for each, and
+// returns them with the devices to notify. The notice commits before anything
+// is pushed, so each due date is claimed once across replicas and a failed
+// push is not retried.
+//
+// Recipients are the asset's owner; for an unowned asset, the members of its
+// team; for an asset with neither, the org's managers and admins.
+func (s *Storage) ClaimOverdueAssets(ctx context.Context, orgID int, now time.Time, limit int) ([]device.OverdueAsset, error) {
+	var out []device.OverdueAsset
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT a.id, a.external_key, a.name, a.owner_user_id, a.team_id, a.metadata->>'`+device.DueAtKey+`'
+			FROM trakrf.assets a
+			WHERE a.org_id = $1 AND a.deleted_at IS NULL AND a.is_active
+			  AND a.metadata ? '`+device.DueAtKey+`'
+			  AND NOT EXISTS (
+				SELECT 1 FROM trakrf.asset_overdue_notices n
+				WHERE n.asset_id = a.id AND n.due_at = a.metadata->>'`+device.DueAtKey+`')`, orgID)
+		if err != nil {
+			return fmt.Errorf("list assets with due dates: %w", err)
+		}
+		var cands []overdueCandidate
+		for rows.Next() {
+			var c overdueCandidate
+			if err := rows.Scan(&c.AssetID, &c.ExternalKey, &c.Name, &c.OwnerUserID, &c.TeamID, &c.DueRaw); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan asset due date: %w", err)
+			}
+			cands = append(cands, c)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("list assets with due dates: %w", err)
+		}
+
+		for _, c := range pickOverdue(cands, now, limit) {
+			tag, err := tx.Exec(ctx, `
+				INSERT INTO trakrf.asset_overdue_notices (org_id, asset_id, due_at)
+				VALUES ($1, $2, $3)
+				ON CONFLICT DO NOTHING`, orgID, c.AssetID, c.DueRaw)
+			if err != nil {
+				return fmt.Errorf("record overdue notice: %w", err)
+			}
+			if tag.RowsAffected() == 0 {
+				// Another replica claimed it first.
+				continue
+			}
+			if err := s.publish(ctx, tx, events.AssetOverdue, orgID, c.AssetID); err != nil {
+				return err
+			}
+			devices, err := s.overdueRecipients(ctx, tx, orgID, c.OwnerUserID, c.TeamID)
+			if err != nil {
+				return err
+			}
+			out = append(out, device.OverdueAsset{
+				OrgID:       orgID,
+				AssetID:     c.AssetID,
+				ExternalKey: c.ExternalKey,
+				Name:        c.Name,
+				DueAt:       c.DueAt,
+				Devices:     devices,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim overdue assets: %w", err)
+	}
+	return out, nil
+}
+
+// overdueRecipients returns the devices of the active org members to notify
+// about an asset with ownerID and teamID (either may be nil). An owner who has
+// left the org is skipped rather than falling back to the team.
+func (s *Storage) overdueRecipients(ctx context.Context, tx pgx.Tx, orgID int, ownerID, teamID *int) ([]device.Device, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT d.id, d.user_id, d.platform, d.push_token, d.device_name, d.app_version, d.last_seen_at, d.created_at
+		FROM trakrf.mobile_devices d
+		JOIN trakrf.org_users ou ON ou.user_id = d.user_id AND ou.org_id = $1 AND ou.status = 'active'
+		WHERE CASE
+			WHEN $2::bigint IS NOT NULL THEN d.user_id = $2
+			WHEN $3::bigint IS NOT NULL THEN EXISTS (
+				SELECT 1 FROM trakrf.team_members tm
+				WHERE tm.org_id = $1 AND tm.team_id = $3 AND tm.user_id = d.user_id)
+			ELSE ou.role IN ('manager', 'admin')
+			END
+		ORDER BY d.id`, orgID, ownerID, teamID)
+	if err != nil {
+		return nil, fmt.Errorf("list overdue recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var out []device.Device
+	for rows.Next() {
+		d, err := scanMobileDevice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan overdue recipient: %w", err)
+		}
+		out = append(out, *d)
+	}
+	return out, rows.Err()
+}
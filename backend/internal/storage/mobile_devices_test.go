@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPickOverdue(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	cands := []overdueCandidate{
+		{AssetID: 1, DueRaw: "2026-10-01T11:00:00Z"},
+		{AssetID: 2, DueRaw: "2026-10-01T13:00:00Z"},      // not yet due
+		{AssetID: 3, DueRaw: "2026-10-01T08:00:00-02:00"}, // 10:00Z
+		{AssetID: 4, DueRaw: "next tuesday"},
+		{AssetID: 5, DueRaw: "2026-10-01T12:00:00Z"}, // due exactly now
+	}
+
+	got := pickOverdue(cands, now, 10)
+	ids := make([]int, len(got))
+	for i, c := range got {
+		ids[i] = c.AssetID
+	}
+	assert.Equal(t, []int{3, 1, 5}, ids)
+	assert.True(t, got[0].DueAt.Equal(now.Add(-2*time.Hour)))
+
+	assert.Len(t, pickOverdue(cands, now, 2), 2)
+	assert.Empty(t, pickOverdue(nil, now, 2))
+}
@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/offline"
+)
+
+// syncSettle holds back changes this recent from the feed. updated_at is
+// stamped when a transaction writes the row, not when it commits, so a slow
+// transaction can commit a row older than a cursor already handed out; the
+// delay gives in-flight writes time to land before the feed moves past them.
+const syncSettle = "5 seconds"
+
+// syncChangeRow is one row of the change feed query. Doc is the entity as
+// JSON, set for live rows only.
+type syncChangeRow struct {
+	Entity    string
+	ID        int
+	UpdatedAt time.Time
+	Live      bool
+	RemovedAt time.Time
+	Doc       []byte
+}
+
+// ListSyncChanges returns the org's asset, location and tag changes after
+// f.After in (updated_at, id) order. Deleted rows, and rows outside f.Scope,
+// come back as tombstones; a full sync (nil After) skips them.
+func (s *Storage) ListSyncChanges(ctx context.Context, orgID int, f offline.ChangesFilter) (*offline.Changes, error) {
+	args := []any{orgID}
+	after := "TRUE"
+	if f.After != nil {
+		args = append(args, f.After.At, f.After.ID)
+		after = "(%[1]s.updated_at, %[1]s.id) > ($2, $3)"
+	}
+	afterOf := func(alias string) string {
+		if f.After == nil {
+			return after
+		}
+		return fmt.Sprintf(after, alias)
+	}
+
+	assetVisible, locationVisible := "TRUE", "TRUE"
+	if f.Scope.Team.Restricted {
+		args = append(args, f.Scope.Team.TeamIDs)
+		assetVisible = fmt.Sprintf("a.team_id = ANY($%d::bigint[])", len(args))
+		locationVisible = fmt.Sprintf("l.team_id = ANY($%d::bigint[])", len(args))
+	}
+	if f.Scope.Locations.Restricted {
+		args = append(args, f.Scope.Locations.RootIDs)
+		locationVisible += " AND " + locationSubtreeClause("l.id", len(args))
+	}
+
+	limit := f.Limit
+	if limit <= 0 || limit > offline.MaxChangesLimit {
+		limit = offline.DefaultChangesLimit
+	}
+	args = append(args, limit+1)
+	limitArg := len(args)
+
+	liveOnly := ""
+	if f.After == nil {
+		liveOnly = "AND live"
+	}
+
+	query := fmt.Sprintf(`
+		WITH changes AS (
+			SELECT 'asset' AS entity, a.id, a.updated_at,
+			       (a.deleted_at IS NULL AND %[1]s) AS live,
+			       COALESCE(a.deleted_at, a.updated_at) AS removed_at,
+			       jsonb_build_object(
+			           'id', a.id, 'external_key', a.external_key, 'name', a.name,
+			           'description', COALESCE(a.description, ''), 'is_active', a.is_active,
+			           'metadata', COALESCE(a.metadata, '{}'::jsonb),
+			           'valid_from', a.valid_from, 'valid_to', a.valid_to,
+			           'updated_at', a.updated_at) AS doc
+			FROM trakrf.assets a
+			WHERE a.org_id = $1 AND %[3]s
+			UNION ALL
+			SELECT 'location', l.id, l.updated_at,
+			       (l.deleted_at IS NULL AND %[2]s),
+			       COALESCE(l.deleted_at, l.updated_at),
+			       jsonb_build_object(
+			           'id', l.id, 'external_key', l.external_key, 'name', l.name,
+			           'description', COALESCE(l.description, ''), 'parent_id', l.parent_location_id,
+			           'is_active', l.is_active, 'valid_from', l.valid_from, 'valid_to', l.valid_to,
+			           'updated_at', l.updated_at)
+			FROM trakrf.locations l
+			WHERE l.org_id = $1 AND %[4]s
+			UNION ALL
+			SELECT 'tag', t.id, t.updated_at,
+			       (t.deleted_at IS NULL AND (
+			           (a.id IS NOT NULL AND a.deleted_at IS NULL AND %[1]s) OR
+			           (l.id IS NOT NULL AND l.deleted_at IS NULL AND %[2]s))),
+			       COALESCE(t.deleted_at, t.updated_at),
+			       jsonb_build_object(
+			           'id', t.id, 'tag_type', t.type, 'value', t.value,
+			           'asset_id', t.asset_id, 'location_id', t.location_id,
+			           'is_active', t.is_active, 'updated_at', t.updated_at)
+			FROM trakrf.tags t
+			LEFT JOIN trakrf.assets a ON a.id = t.asset_id
+			LEFT JOIN trakrf.locations l ON l.id = t.location_id
+			WHERE t.org_id = $1 AND %[5]s
+		)
+		SELECT entity, id, updated_at, live, removed_at, CASE WHEN live THEN doc END
+		FROM changes
+		WHERE updated_at <= NOW() - INTERVAL '%[6]s' %[7]s
+		ORDER BY updated_at, id
+		LIMIT $%[8]d`,
+		assetVisible, locationVisible, afterOf("a"), afterOf("l"), afterOf("t"),
+		syncSettle, liveOnly, limitArg)
+
+	var rows []syncChangeRow
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rs, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rs.Close()
+		for rs.Next() {
+			var r syncChangeRow
+			if err := rs.Scan(&r.Entity, &r.ID, &r.UpdatedAt, &r.Live, &r.RemovedAt, &r.Doc); err != nil {
+				return err
+			}
+			rows = append(rows, r)
+		}
+		return rs.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync changes: %w", err)
+	}
+	return buildSyncChanges(rows, limit)
+}
+
+// buildSyncChanges splits up to limit feed rows into the page's entity lists.
+// A row past limit only signals that another page follows.
+func buildSyncChanges(rows []syncChangeRow, limit int) (*offline.Changes, error) {
+	out := &offline.Changes{
+		Assets:     []offline.Asset{},
+		Locations:  []offline.Location{},
+		Tags:       []offline.Tag{},
+		Tombstones: []offline.Tombstone{},
+	}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		out.HasMore = true
+	}
+	for _, r := range rows {
+		if !r.Live {
+			out.Tombstones = append(out.Tombstones, offline.Tombstone{Entity: r.Entity, ID: r.ID, RemovedAt: r.RemovedAt})
+			continue
+		}
+		var err error
+		switch r.Entity {
+		case offline.EntityAsset:
+			var a offline.Asset
+			if err = json.Unmarshal(r.Doc, &a); err == nil {
+				out.Assets = append(out.Assets, a)
+			}
+		case offline.EntityLocation:
+			var l offline.Location
+			if err = json.Unmarshal(r.Doc, &l); err == nil {
+				out.Locations = append(out.Locations, l)
+			}
+		case offline.EntityTag:
+			var t offline.Tag
+			if err = json.Unmarshal(r.Doc, &t); err == nil {
+				out.Tags = append(out.Tags, t)
+			}
+		default:
+			err = fmt.Errorf("unknown entity %q", r.Entity)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode sync %s %d: %w", r.Entity, r.ID, err)
+		}
+	}
+	if n := len(rows); n > 0 {
+		out.Last = &offline.Cursor{At: rows[n-1].UpdatedAt, ID: rows[n-1].ID}
+	}
+	return out, nil
+}
+
+// LockSyncTarget returns the asset or location a sync mutation targets, by id
+// or else by external key, and locks it until the enclosing transaction ends.
+// Deleted rows are returned (with DeletedAt set) so the caller can report the
+// conflict; a live row wins over deleted ones sharing its external key. Returns
+// nil when no row matches or the row is outside scope.
+func (s *Storage) LockSyncTarget(ctx context.Context, orgID int, entity string, id *int, externalKey string, scope offline.Scope) (*offline.Target, error) {
+	table, alias := "trakrf.assets", "a"
+	if entity == offline.EntityLocation {
+		table, alias = "trakrf.locations", "l"
+	}
+
+	args := []any{orgID}
+	where := fmt.Sprintf("%s.org_id = $1", alias)
+	if id != nil {
+		args = append(args, *id)
+		where += fmt.Sprintf(" AND %s.id = $%d", alias, len(args))
+	} else {
+		args = append(args, externalKey)
+		where += fmt.Sprintf(" AND %s.external_key = $%d", alias, len(args))
+	}
+	if scope.Team.Restricted {
+		args = append(args, scope.Team.TeamIDs)
+		where += fmt.Sprintf(" AND %s.team_id = ANY($%d::bigint[])", alias, len(args))
+	}
+	if entity == offline.EntityLocation && scope.Locations.Restricted {
+		args = append(args, scope.Locations.RootIDs)
+		where += " AND " + locationSubtreeClause("l.id", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %[1]s.id, %[1]s.external_key, %[1]s.updated_at, %[1]s.deleted_at
+		FROM %[2]s %[1]s
+		WHERE %[3]s
+		ORDER BY %[1]s.deleted_at IS NOT NULL, %[1]s.updated_at DESC
+		LIMIT 1
+		FOR UPDATE`, alias, table, where)
+
+	var t offline.Target
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, args...).Scan(&t.ID, &t.ExternalKey, &t.UpdatedAt, &t.DeletedAt)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock sync target: %w", err)
+	}
+	return &t, nil
+}
+
+// FindEntityTagID returns the id of the live tag of tagType and value on the
+// asset or location, or 0 when it has none.
+func (s *Storage) FindEntityTagID(ctx context.Context, orgID int, entity string, entityID int, tagType, value string) (int, error) {
+	column := "asset_id"
+	if entity == offline.EntityLocation {
+		column = "location_id"
+	}
+	var id int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, fmt.Sprintf(`
+			SELECT id FROM trakrf.tags
+			WHERE org_id = $1 AND %s = $2 AND type = $3 AND value = $4 AND deleted_at IS NULL`, column),
+			orgID, entityID, tagType, value).Scan(&id)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find tag: %w", err)
+	}
+	return id, nil
+}
+
+// RunSyncMutation applies one offline mutation exactly once. When the org has
+// already recorded an outcome for clientMutationID it is returned with
+// Replayed set and apply is not called. Otherwise apply runs in a transaction
+// (storage calls made with the context it receives join it) and its outcome is
+// recorded in the same transaction, so the edit and the record commit
+// together. An error from apply rolls both back.
+func (s *Storage) RunSyncMutation(ctx context.Context, orgID int, clientMutationID string, apply func(ctx context.Context) (*offline.MutationResult, error)) (*offline.MutationResult, error) {
+	var result *offline.MutationResult
+	err := s.WithTx(ContextWithOrgID(ctx, orgID), func(ctx context.Context, tx pgx.Tx) error {
+		// Serialize concurrent uploads of the same queue.
+		if _, err := tx.Exec(ctx,
+			`SELECT pg_advisory_xact_lock(hashtextextended('sync_mutations:' || $1::text || ':' || $2, 0))`,
+			orgID, clientMutationID); err != nil {
+			return err
+		}
+
+		var recorded []byte
+		err := tx.QueryRow(ctx, `
+			SELECT result FROM trakrf.sync_mutations
+			WHERE org_id = $1 AND client_mutation_id = $2`, orgID, clientMutationID).Scan(&recorded)
+		switch {
+		case err == nil:
+			result = &offline.MutationResult{}
+			if err := json.Unmarshal(recorded, result); err != nil {
+				return fmt.Errorf("decode recorded result: %w", err)
+			}
+			result.Replayed = true
+			return nil
+		case !errors.Is(err, pgx.ErrNoRows):
+			return err
+		}
+
+		result, err = apply(ctx)
+		if err != nil {
+			return err
+		}
+		doc, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			INSERT INTO trakrf.sync_mutations (org_id, client_mutation_id, result)
+			VALUES ($1, $2, $3)`, orgID, clientMutationID, doc)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run sync mutation: %w", err)
+	}
+	return result, nil
+}
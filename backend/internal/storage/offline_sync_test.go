@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/offline"
+)
+
+func TestBuildSyncChanges_SplitsEntitiesAndTombstones(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 123456000, time.UTC)
+	rows := []syncChangeRow{
+		{Entity: "asset", ID: 11, UpdatedAt: t0, Live: true,
+			Doc: []byte(`{"id":11,"external_key":"forklift-3","name":"Forklift 3","description":"","is_active":true,"metadata":{"asset_type":"vehicle"},"valid_from":"2026-01-01T00:00:00+00:00","valid_to":null,"updated_at":"2026-10-01T12:00:00.123456+00:00"}`)},
+		{Entity: "location", ID: 12, UpdatedAt: t0, Live: true,
+			Doc: []byte(`{"id":12,"external_key":"wh1","name":"Warehouse 1","description":"","parent_id":null,"is_active":true,"valid_from":"2026-01-01T00:00:00+00:00","valid_to":null,"updated_at":"2026-10-01T12:00:00.123456+00:00"}`)},
+		{Entity: "tag", ID: 13, UpdatedAt: t0.Add(time.Second), Live: true,
+			Doc: []byte(`{"id":13,"tag_type":"rfid","value":"E200","asset_id":11,"location_id":null,"is_active":true,"updated_at":"2026-10-01T12:00:01.123456+00:00"}`)},
+		{Entity: "asset", ID: 14, UpdatedAt: t0.Add(2 * time.Second), RemovedAt: t0.Add(2 * time.Second)},
+	}
+
+	got, err := buildSyncChanges(rows, 10)
+	require.NoError(t, err)
+
+	require.Len(t, got.Assets, 1)
+	assert.Equal(t, "forklift-3", got.Assets[0].ExternalKey)
+	assert.True(t, got.Assets[0].UpdatedAt.Equal(t0), "updated_at keeps microseconds")
+	require.Len(t, got.Locations, 1)
+	assert.Nil(t, got.Locations[0].ParentID)
+	require.Len(t, got.Tags, 1)
+	assert.Equal(t, 11, *got.Tags[0].AssetID)
+	assert.Equal(t, []offline.Tombstone{{Entity: "asset", ID: 14, RemovedAt: t0.Add(2 * time.Second)}}, got.Tombstones)
+	assert.False(t, got.HasMore)
+	assert.Equal(t, &offline.Cursor{At: t0.Add(2 * time.Second), ID: 14}, got.Last)
+}
+
+func TestBuildSyncChanges_ExtraRowMeansMore(t *testing.T) {
+	t0 := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	rows := []syncChangeRow{
+		{Entity: "asset", ID: 1, UpdatedAt: t0, RemovedAt: t0},
+		{Entity: "asset", ID: 2, UpdatedAt: t0, RemovedAt: t0},
+		{Entity: "asset", ID: 3, UpdatedAt: t0, RemovedAt: t0},
+	}
+
+	got, err := buildSyncChanges(rows, 2)
+	require.NoError(t, err)
+	assert.True(t, got.HasMore)
+	assert.Len(t, got.Tombstones, 2)
+	assert.Equal(t, 2, got.Last.ID)
+
+	empty, err := buildSyncChanges(nil, 2)
+	require.NoError(t, err)
+	assert.Nil(t, empty.Last)
+	assert.NotNil(t, empty.Assets)
+}
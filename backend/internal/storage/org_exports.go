@@ -0,0 +1,291 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// ErrOrgExportInProgress is returned by CreateOrgExport when the org already
+// has an export pending or running.
+var ErrOrgExportInProgress = errors.New("an export of this organization is already in progress")
+
+// orgExportTable is one table in an org export: the rows of the org, minus
+// omit (credential) columns, or only columns when set.
+type orgExportTable struct {
+	name    string
+	where   string
+	omit    []string
+	columns []string
+}
+
+// rowExpr is the jsonb expression for one exported row of t, aliased t.
+func (t orgExportTable) rowExpr() string {
+	if len(t.columns) > 0 {
+		pairs := make([]string, 0, len(t.columns))
+		for _, col := range t.columns {
+			pairs = append(pairs, "'"+col+"', t."+col)
+		}
+		return "jsonb_build_object(" + strings.Join(pairs, ", ") + ")"
+	}
+	row := "to_jsonb(t)"
+	for _, col := range t.omit {
+		row += " - '" + col + "'"
+	}
+	return row
+}
+
+// orgExportTables lists what an org export contains, parents before children
+// so a SQL archive restores without deferring foreign keys. Credentials (API
+// keys, refresh and kiosk tokens, invitations, webhook secrets, connector
+// credentials), operational queues (outbox, sync mutations, bulk import
+// jobs, exports) and caches (dashboard metrics) are left out. Users are
+// shared across orgs, so only the columns that identify a member are
+// exported, and a column added to users later stays out until listed here.
+var orgExportTables = []orgExportTable{
+	{name: "organizations", where: "id = $1"},
+	{name: "users", where: "id IN (SELECT user_id FROM trakrf.org_users WHERE org_id = $1)", columns: []string{"id", "email", "name"}},
+	{name: "org_users", where: "org_id = $1"},
+	{name: "locations", where: "org_id = $1"},
+	{name: "location_shifts", where: "org_id = $1"},
+	{name: "scan_devices", where: "org_id = $1", omit: []string{"credential_hash"}},
+	{name: "scan_points", where: "org_id = $1"},
+	{name: "assets", where: "org_id = $1"},
+	{name: "tags", where: "org_id = $1"},
+	{name: "tag_assignments", where: "org_id = $1"},
+	{name: "asset_scans", where: "org_id = $1"},
+	{name: "teams", where: "org_id = $1"},
+	{name: "team_members", where: "org_id = $1"},
+	{name: "location_access_policies", where: "org_id = $1"},
+	{name: "kits", where: "org_id = $1"},
+	{name: "kit_members", where: "org_id = $1"},
+	{name: "kit_verifications", where: "org_id = $1"},
+	{name: "alarm_devices", where: "org_id = $1"},
+	{name: "alarm_events", where: "org_id = $1"},
+	{name: "muster_events", where: "org_id = $1"},
+	{name: "muster_event_entries", where: "org_id = $1"},
+	{name: "service_accounts", where: "org_id = $1"},
+	{name: "sensor_thresholds", where: "org_id = $1"},
+	{name: "sensor_readings", where: "org_id = $1"},
+	{name: "sensor_alerts", where: "org_id = $1"},
+	{name: "consumables", where: "org_id = $1"},
+	{name: "stock_levels", where: "org_id = $1"},
+	{name: "stock_adjustments", where: "org_id = $1"},
+	{name: "stock_alerts", where: "org_id = $1"},
+	{name: "asset_disposals", where: "org_id = $1"},
+	{name: "asset_assignments", where: "org_id = $1"},
+	{name: "asset_condition_reports", where: "org_id = $1"},
+	{name: "asset_condition_photos", where: "org_id = $1"},
+	{name: "asset_financials", where: "org_id = $1"},
+	{name: "label_printers", where: "org_id = $1"},
+	{name: "dock_doors", where: "org_id = $1"},
+	{name: "dock_movements", where: "org_id = $1"},
+	{name: "transfer_orders", where: "org_id = $1"},
+	{name: "transfer_order_items", where: "org_id = $1"},
+	{name: "documents", where: "org_id = $1"},
+	{name: "dashboards", where: "org_id = $1"},
+	{name: "identifier_templates", where: "org_id = $1"},
+	{name: "approval_rules", where: "org_id = $1"},
+	{name: "approval_requests", where: "org_id = $1"},
+	{name: "org_ownership_transfers", where: "org_id = $1"},
+}
+
+// OrgDumpWriter receives an org's rows from DumpOrgData: BeginTable once per
+// exported table, in restore order, then Row for each of its rows as a JSON
+// object keyed by column name.
+type OrgDumpWriter interface {
+	BeginTable(name string) error
+	Row(row []byte) error
+}
+
+const orgExportColumns = `id, org_id, format, status, requested_by, error, size_bytes, row_count,
+	started_at, completed_at, expires_at, created_at`
+
+func scanOrgExport(row pgx.Row) (*organization.Export, error) {
+	var e organization.Export
+	if err := row.Scan(&e.ID, &e.OrgID, &e.Format, &e.Status, &e.RequestedBy, &e.Error,
+		&e.SizeBytes, &e.RowCount, &e.StartedAt, &e.CompletedAt, &e.ExpiresAt, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// CreateOrgExport queues an export of orgID for the org_export job. It
+// returns ErrOrgExportInProgress when one is already pending or running: an
+// export copies the whole org, so requests are not stacked.
+func (s *Storage) CreateOrgExport(ctx context.Context, orgID int, requestedBy *int, format string) (*organization.Export, error) {
+	e, err := scanOrgExport(s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.org_exports (org_id, format, requested_by)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (
+			SELECT 1 FROM trakrf.org_exports
+			WHERE org_id = $1 AND status IN ('pending', 'running'))
+		RETURNING `+orgExportColumns, orgID, format, requestedBy))
+	if err == pgx.ErrNoRows {
+		return nil, ErrOrgExportInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create org export: %w", err)
+	}
+	return e, nil
+}
+
+// ListOrgExports returns orgID's exports, newest first.
+func (s *Storage) ListOrgExports(ctx context.Context, orgID int) ([]organization.Export, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+orgExportColumns+`
+		FROM trakrf.org_exports
+		WHERE org_id = $1
+		ORDER BY created_at DESC, id DESC`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list org exports: %w", err)
+	}
+	defer rows.Close()
+
+	out := []organization.Export{}
+	for rows.Next() {
+		e, err := scanOrgExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan org export: %w", err)
+		}
+		out = append(out, *e)
+	}
+	return out, rows.Err()
+}
+
+// GetOrgExport returns one export without its archive, or nil when it is not
+// in orgID.
+func (s *Storage) GetOrgExport(ctx context.Context, orgID, id int) (*organization.Export, error) {
+	e, err := scanOrgExport(s.pool.QueryRow(ctx, `
+		SELECT `+orgExportColumns+`
+		FROM trakrf.org_exports
+		WHERE id = $1 AND org_id = $2`, id, orgID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get org export: %w", err)
+	}
+	return e, nil
+}
+
+// GetOrgExportArchive returns a completed, unexpired export's gzipped archive,
+// or nil when there is none.
+func (s *Storage) GetOrgExportArchive(ctx context.Context, orgID, id int) ([]byte, error) {
+	var archive []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT archive FROM trakrf.org_exports
+		WHERE id = $1 AND org_id = $2 AND status = 'completed' AND expires_at > NOW()`,
+		id, orgID).Scan(&archive)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get org export archive: %w", err)
+	}
+	return archive, nil
+}
+
+// ClaimOrgExport marks the oldest pending export running and returns it, or
+// nil when none is waiting. A running export whose claim is older than lease
+// is taken back, so an export whose replica died mid-build is retried.
+func (s *Storage) ClaimOrgExport(ctx context.Context, lease time.Duration) (*organization.Export, error) {
+	e, err := scanOrgExport(s.pool.QueryRow(ctx, `
+		UPDATE trakrf.org_exports
+		SET status = 'running', started_at = NOW()
+		WHERE id = (
+			SELECT id FROM trakrf.org_exports
+			WHERE status = 'pending'
+			   OR (status = 'running' AND started_at < NOW() - make_interval(secs => $1))
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+orgExportColumns, lease.Seconds()))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim org export: %w", err)
+	}
+	return e, nil
+}
+
+// DumpOrgData streams every exported row of orgID to w, one table at a time,
+// from a single transaction with the org's RLS context set.
+func (s *Storage) DumpOrgData(ctx context.Context, orgID int, w OrgDumpWriter) error {
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		for _, t := range orgExportTables {
+			if err := w.BeginTable(t.name); err != nil {
+				return err
+			}
+			rows, err := tx.Query(ctx, fmt.Sprintf(
+				`SELECT %s FROM trakrf.%s t WHERE %s`, t.rowExpr(), t.name, t.where), orgID)
+			if err != nil {
+				return fmt.Errorf("%s: %w", t.name, err)
+			}
+			for rows.Next() {
+				var b []byte
+				if err := rows.Scan(&b); err != nil {
+					rows.Close()
+					return fmt.Errorf("%s: %w", t.name, err)
+				}
+				if err := w.Row(b); err != nil {
+					rows.Close()
+					return err
+				}
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("%s: %w", t.name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to dump org data: %w", err)
+	}
+	return nil
+}
+
+// CompleteOrgExport stores a finished export's archive; the job deletes it
+// once expiresAt passes.
+func (s *Storage) CompleteOrgExport(ctx context.Context, id int, archive []byte, rowCount int64, expiresAt time.Time) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.org_exports
+		SET status = 'completed', archive = $2, size_bytes = $3, row_count = $4,
+		    completed_at = NOW(), expires_at = $5, error = NULL
+		WHERE id = $1`, id, archive, int64(len(archive)), rowCount, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to complete org export: %w", err)
+	}
+	return nil
+}
+
+// FailOrgExport records why an export could not be built.
+func (s *Storage) FailOrgExport(ctx context.Context, id int, msg string, expiresAt time.Time) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.org_exports
+		SET status = 'failed', error = $2, completed_at = NOW(), expires_at = $3
+		WHERE id = $1`, id, msg, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to fail org export: %w", err)
+	}
+	return nil
+}
+
+// PruneOrgExports deletes completed and failed exports past their expiry.
+func (s *Storage) PruneOrgExports(ctx context.Context) (int64, error) {
+	tag, err := s.pool.Exec(ctx, `
+		DELETE FROM trakrf.org_exports
+		WHERE status IN ('completed', 'failed') AND expires_at <= NOW()`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune org exports: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrgExportTables_UsersAreAnAllowlist(t *testing.T) {
+	var users *orgExportTable
+	for i := range orgExportTables {
+		if orgExportTables[i].name == "users" {
+			users = &orgExportTables[i]
+		}
+	}
+	require.NotNil(t, users)
+	assert.Equal(t, []string{"id", "email", "name"}, users.columns)
+	assert.Equal(t, "jsonb_build_object('id', t.id, 'email', t.email, 'name', t.name)", users.rowExpr())
+}
+
+func TestOrgExportTable_RowExprOmitsColumns(t *testing.T) {
+	tbl := orgExportTable{name: "scan_devices", omit: []string{"credential_hash"}}
+	assert.Equal(t, "to_jsonb(t) - 'credential_hash'", tbl.rowExpr())
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// errImportDryRun rolls back a dry-run import once its counts are in.
+var errImportDryRun = errors.New("dry run")
+
+// importMetadata turns an archived metadata value into a jsonb parameter,
+// with SQL NULL standing for "none".
+func importMetadata(raw []byte) []byte {
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	return raw
+}
+
+// ImportOrgData applies an export archive's locations, assets and tags to
+// orgID in one transaction. Rows are matched by natural key — external_key
+// for locations and assets, (type, value) for tags — so importing the same
+// archive twice updates rather than duplicates, and source ids are remapped
+// onto the ids the rows have in orgID. Existing rows the archive does not
+// mention are left alone. With dryRun the transaction is rolled back and only
+// the counts are returned.
+func (s *Storage) ImportOrgData(ctx context.Context, orgID int, data organization.ImportData, dryRun bool) (*organization.ImportResult, error) {
+	var res organization.ImportResult
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		res = organization.ImportResult{SourceOrgID: data.SourceOrgID, DryRun: dryRun}
+
+		if err := s.importRows(ctx, tx, orgID, data, &res); err != nil {
+			return err
+		}
+		if dryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		return nil, fmt.Errorf("failed to import org data: %w", err)
+	}
+	return &res, nil
+}
+
+// importRows upserts data's locations, assets and tags into orgID on tx and
+// tallies them on res. Locations must come parents first.
+func (s *Storage) importRows(ctx context.Context, tx pgx.Tx, orgID int, data organization.ImportData, res *organization.ImportResult) error {
+	locationIDs := make(map[int]int, len(data.Locations))
+	for _, l := range data.Locations {
+		var parent *int
+		if l.ParentLocationID != nil {
+			if id, ok := locationIDs[*l.ParentLocationID]; ok {
+				parent = &id
+			}
+		}
+		meta := importMetadata(l.Metadata)
+		var id int
+		err := tx.QueryRow(ctx, `
+			UPDATE trakrf.locations SET
+				name = $3, description = $4, parent_location_id = $5, valid_from = $6,
+				valid_to = $7, is_active = $8, metadata = COALESCE($9::jsonb, '{}')
+			WHERE org_id = $1 AND external_key = $2 AND deleted_at IS NULL
+			RETURNING id`,
+			orgID, l.ExternalKey, l.Name, l.Description, parent, l.ValidFrom, l.ValidTo, l.IsActive, meta,
+		).Scan(&id)
+		typ := events.LocationUpdated
+		if errors.Is(err, pgx.ErrNoRows) {
+			typ = events.LocationCreated
+			err = tx.QueryRow(ctx, `
+				INSERT INTO trakrf.locations
+					(org_id, external_key, name, description, parent_location_id, valid_from, valid_to, is_active, metadata)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9::jsonb, '{}'))
+				RETURNING id`,
+				orgID, l.ExternalKey, l.Name, l.Description, parent, l.ValidFrom, l.ValidTo, l.IsActive, meta,
+			).Scan(&id)
+		}
+		if err != nil {
+			return fmt.Errorf("location %s: %w", l.ExternalKey, err)
+		}
+		if typ == events.LocationCreated {
+			res.Locations.Created++
+		} else {
+			res.Locations.Updated++
+		}
+		locationIDs[l.ID] = id
+		if err := s.publish(ctx, tx, typ, orgID, id); err != nil {
+			return err
+		}
+	}
+
+	assetIDs := make(map[int]int, len(data.Assets))
+	for _, a := range data.Assets {
+		meta := importMetadata(a.Metadata)
+		var id int
+		err := tx.QueryRow(ctx, `
+			UPDATE trakrf.assets SET
+				name = $3, description = $4, valid_from = $5, valid_to = $6,
+				is_active = $7, cost_center = $8, metadata = COALESCE($9::jsonb, '{}')
+			WHERE org_id = $1 AND external_key = $2 AND deleted_at IS NULL
+			RETURNING id`,
+			orgID, a.ExternalKey, a.Name, a.Description, a.ValidFrom, a.ValidTo, a.IsActive, a.CostCenter, meta,
+		).Scan(&id)
+		typ := events.AssetUpdated
+		if errors.Is(err, pgx.ErrNoRows) {
+			typ = events.AssetCreated
+			err = tx.QueryRow(ctx, `
+				INSERT INTO trakrf.assets
+					(org_id, external_key, name, description, valid_from, valid_to, is_active, cost_center, metadata)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9::jsonb, '{}'))
+				RETURNING id`,
+				orgID, a.ExternalKey, a.Name, a.Description, a.ValidFrom, a.ValidTo, a.IsActive, a.CostCenter, meta,
+			).Scan(&id)
+		}
+		if err != nil {
+			return fmt.Errorf("asset %s: %w", a.ExternalKey, err)
+		}
+		if typ == events.AssetCreated {
+			res.Assets.Created++
+		} else {
+			res.Assets.Updated++
+		}
+		assetIDs[a.ID] = id
+		if err := s.publish(ctx, tx, typ, orgID, id); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range data.Tags {
+		var assetID, locationID *int
+		switch {
+		case t.AssetID != nil:
+			if id, ok := assetIDs[*t.AssetID]; ok {
+				assetID = &id
+			}
+		case t.LocationID != nil:
+			if id, ok := locationIDs[*t.LocationID]; ok {
+				locationID = &id
+			}
+		}
+		if assetID == nil && locationID == nil {
+			res.Tags.Skipped++
+			continue
+		}
+		meta := importMetadata(t.Metadata)
+		tag, err := tx.Exec(ctx, `
+			UPDATE trakrf.tags SET
+				asset_id = $4, location_id = $5, valid_from = $6, valid_to = $7,
+				is_active = $8, metadata = COALESCE($9::jsonb, '{}')
+			WHERE org_id = $1 AND type = $2 AND value = $3 AND deleted_at IS NULL`,
+			orgID, t.Type, t.Value, assetID, locationID, t.ValidFrom, t.ValidTo, t.IsActive, meta)
+		if err != nil {
+			return fmt.Errorf("tag %s %s: %w", t.Type, t.Value, err)
+		}
+		if tag.RowsAffected() > 0 {
+			res.Tags.Updated++
+			continue
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO trakrf.tags
+				(org_id, type, value, asset_id, location_id, valid_from, valid_to, is_active, metadata)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, COALESCE($9::jsonb, '{}'))`,
+			orgID, t.Type, t.Value, assetID, locationID, t.ValidFrom, t.ValidTo, t.IsActive, meta); err != nil {
+			return fmt.Errorf("tag %s %s: %w", t.Type, t.Value, err)
+		}
+		res.Tags.Created++
+	}
+	return nil
+}
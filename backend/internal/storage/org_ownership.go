@@ -0,0 +1,276 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+var (
+	// ErrOwnershipTransferState is returned when the transfer is no longer
+	// pending, or has expired.
+	ErrOwnershipTransferState = errors.New("the ownership transfer is no longer pending")
+	// ErrOwnershipTransferPending is returned when the org already has a
+	// pending transfer.
+	ErrOwnershipTransferPending = errors.New("the organization already has a pending ownership transfer")
+	// ErrOwnershipNotOwner is returned when someone other than the owner
+	// tries to start or cancel a transfer.
+	ErrOwnershipNotOwner = errors.New("only the organization owner can transfer or cancel a transfer of ownership")
+	// ErrOwnershipNotRecipient is returned when someone other than the
+	// recipient tries to accept or decline a transfer.
+	ErrOwnershipNotRecipient = errors.New("only the new owner can accept or decline an ownership transfer")
+	// ErrOwnershipTargetNotMember is returned when the new owner is not, or
+	// is no longer, a member of the org.
+	ErrOwnershipTargetNotMember = errors.New("the new owner must be a member of the organization")
+	// ErrOwnershipTargetIsOwner is returned when ownership is transferred to
+	// the current owner.
+	ErrOwnershipTargetIsOwner = errors.New("the user already owns the organization")
+)
+
+// ownershipStatusExpr reads a pending transfer past its expiry as expired.
+const ownershipStatusExpr = `CASE WHEN t.status = 'pending' AND t.expires_at <= NOW()
+	            THEN 'expired' ELSE t.status END`
+
+const ownershipTransferSelect = `
+	SELECT t.id, t.org_id, t.from_user_id, t.to_user_id, t.previous_owner_role, ` + ownershipStatusExpr + `,
+	       t.expires_at, t.decided_by, t.decided_at, t.created_at, t.updated_at
+	FROM trakrf.org_ownership_transfers t`
+
+func scanOwnershipTransfer(row pgx.Row) (*organization.OwnershipTransfer, error) {
+	var t organization.OwnershipTransfer
+	if err := row.Scan(&t.ID, &t.OrgID, &t.FromUserID, &t.ToUserID, &t.PreviousOwnerRole, &t.Status,
+		&t.ExpiresAt, &t.DecidedBy, &t.DecidedAt, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// GetOrgOwnerID returns the org's owner, or nil when none is recorded.
+func (s *Storage) GetOrgOwnerID(ctx context.Context, orgID int) (*int, error) {
+	var owner *int
+	err := s.pool.QueryRow(ctx,
+		`SELECT owner_user_id FROM trakrf.organizations WHERE id = $1 AND deleted_at IS NULL`,
+		orgID).Scan(&owner)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get organization owner: %w", err)
+	}
+	return owner, nil
+}
+
+// GetOrgOwnership returns the org's owner and its pending transfer, if any.
+// It returns nil when the org does not exist.
+func (s *Storage) GetOrgOwnership(ctx context.Context, orgID int) (*organization.Ownership, error) {
+	var o organization.Ownership
+	err := s.pool.QueryRow(ctx,
+		`SELECT owner_user_id FROM trakrf.organizations WHERE id = $1 AND deleted_at IS NULL`,
+		orgID).Scan(&o.OwnerUserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization owner: %w", err)
+	}
+
+	t, err := scanOwnershipTransfer(s.pool.QueryRow(ctx, ownershipTransferSelect+`
+		WHERE t.org_id = $1 AND t.status = 'pending' AND t.expires_at > NOW()`, orgID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get pending ownership transfer: %w", err)
+	}
+	o.PendingTransfer = t
+	return &o, nil
+}
+
+// GetOwnershipTransfer returns one transfer, or nil when it does not exist
+// in the org.
+func (s *Storage) GetOwnershipTransfer(ctx context.Context, orgID, id int) (*organization.OwnershipTransfer, error) {
+	t, err := scanOwnershipTransfer(s.pool.QueryRow(ctx, ownershipTransferSelect+`
+		WHERE t.id = $1 AND t.org_id = $2`, id, orgID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ownership transfer: %w", err)
+	}
+	return t, nil
+}
+
+// CreateOwnershipTransfer proposes handing orgID from fromUserID to
+// req.UserID. fromUserID must be the owner; in an org with no recorded owner
+// any admin may propose, and the route only lets admins through. The
+// recipient must be a member. A transfer whose expiry has passed no longer
+// blocks a new one. It returns nil when the org does not exist.
+func (s *Storage) CreateOwnershipTransfer(ctx context.Context, orgID, fromUserID int, req organization.TransferOwnershipRequest) (*organization.OwnershipTransfer, error) {
+	previousRole := req.PreviousOwnerRole
+	if previousRole == "" {
+		previousRole = organization.DefaultPreviousOwnerRole
+	}
+
+	var id int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var owner *int
+		err := tx.QueryRow(ctx, `
+			SELECT owner_user_id FROM trakrf.organizations
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE`, orgID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("lock organization: %w", err)
+		}
+		if owner != nil && *owner != fromUserID {
+			return ErrOwnershipNotOwner
+		}
+		if req.UserID == fromUserID {
+			return ErrOwnershipTargetIsOwner
+		}
+
+		var member bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM trakrf.org_users
+			               WHERE org_id = $1 AND user_id = $2 AND deleted_at IS NULL)`,
+			orgID, req.UserID).Scan(&member); err != nil {
+			return fmt.Errorf("check new owner membership: %w", err)
+		}
+		if !member {
+			return ErrOwnershipTargetNotMember
+		}
+
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.org_ownership_transfers SET status = 'expired'
+			WHERE org_id = $1 AND status = 'pending' AND expires_at <= NOW()`, orgID); err != nil {
+			return fmt.Errorf("expire stale transfers: %w", err)
+		}
+
+		err = tx.QueryRow(ctx, `
+			INSERT INTO trakrf.org_ownership_transfers
+				(org_id, from_user_id, to_user_id, previous_owner_role, expires_at)
+			VALUES ($1, $2, $3, $4, NOW() + $5::bigint * INTERVAL '1 second')
+			RETURNING id`,
+			orgID, fromUserID, req.UserID, previousRole, int64(organization.TransferTTL.Seconds())).Scan(&id)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrOwnershipTransferPending
+		}
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrOwnershipNotOwner) || errors.Is(err, ErrOwnershipTargetIsOwner) ||
+			errors.Is(err, ErrOwnershipTargetNotMember) || errors.Is(err, ErrOwnershipTransferPending) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create ownership transfer: %w", err)
+	}
+	if id == 0 {
+		return nil, nil
+	}
+	return s.GetOwnershipTransfer(ctx, orgID, id)
+}
+
+// DecideOwnershipTransfer applies action to a pending transfer on behalf of
+// userID. Only the recipient can accept or decline; only the proposer or the
+// current owner can cancel. Accepting makes the recipient the owner and an
+// admin and demotes the previous owner to the role named in the transfer, in
+// one transaction. It returns nil when the transfer does not exist in the org.
+func (s *Storage) DecideOwnershipTransfer(ctx context.Context, orgID, id, userID int, action string) (*organization.OwnershipTransfer, error) {
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		// Lock the org first, as CreateOwnershipTransfer does, so the two
+		// cannot deadlock.
+		var owner *int
+		err := tx.QueryRow(ctx, `
+			SELECT owner_user_id FROM trakrf.organizations
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE`, orgID).Scan(&owner)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("lock organization: %w", err)
+		}
+
+		var status, previousRole string
+		var from, to *int
+		err = tx.QueryRow(ctx, `
+			SELECT `+ownershipStatusExpr+`, t.from_user_id, t.to_user_id, t.previous_owner_role
+			FROM trakrf.org_ownership_transfers t
+			WHERE t.id = $1 AND t.org_id = $2
+			FOR UPDATE`, id, orgID).Scan(&status, &from, &to, &previousRole)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("lock ownership transfer: %w", err)
+		}
+		found = true
+
+		var next string
+		switch action {
+		case organization.TransferActionAccept, organization.TransferActionDecline:
+			if to == nil || *to != userID {
+				return ErrOwnershipNotRecipient
+			}
+			next = organization.TransferStatusAccepted
+			if action == organization.TransferActionDecline {
+				next = organization.TransferStatusDeclined
+			}
+		case organization.TransferActionCancel:
+			if (from == nil || *from != userID) && (owner == nil || *owner != userID) {
+				return ErrOwnershipNotOwner
+			}
+			next = organization.TransferStatusCancelled
+		default:
+			return fmt.Errorf("unknown ownership transfer action %q", action)
+		}
+		if status != organization.TransferStatusPending {
+			return ErrOwnershipTransferState
+		}
+
+		if next == organization.TransferStatusAccepted {
+			tag, err := tx.Exec(ctx, `
+				UPDATE trakrf.org_users SET role = 'admin', updated_at = NOW()
+				WHERE org_id = $1 AND user_id = $2 AND deleted_at IS NULL`, orgID, userID)
+			if err != nil {
+				return fmt.Errorf("promote new owner: %w", err)
+			}
+			if tag.RowsAffected() == 0 {
+				return ErrOwnershipTargetNotMember
+			}
+			if from != nil {
+				if _, err := tx.Exec(ctx, `
+					UPDATE trakrf.org_users SET role = $3, updated_at = NOW()
+					WHERE org_id = $1 AND user_id = $2 AND deleted_at IS NULL`,
+					orgID, *from, previousRole); err != nil {
+					return fmt.Errorf("demote previous owner: %w", err)
+				}
+			}
+			if _, err := tx.Exec(ctx,
+				`UPDATE trakrf.organizations SET owner_user_id = $2 WHERE id = $1`,
+				orgID, userID); err != nil {
+				return fmt.Errorf("set owner: %w", err)
+			}
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.org_ownership_transfers
+			SET status = $2, decided_by = $3, decided_at = NOW()
+			WHERE id = $1`, id, next, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrOwnershipTransferState) || errors.Is(err, ErrOwnershipNotOwner) ||
+			errors.Is(err, ErrOwnershipNotRecipient) || errors.Is(err, ErrOwnershipTargetNotMember) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to %s ownership transfer: %w", action, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.GetOwnershipTransfer(ctx, orgID, id)
+}
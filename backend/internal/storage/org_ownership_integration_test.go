@@ -0,0 +1,117 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestOwnershipTransfer_AcceptHandsOverAndDemotes(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	var owner, heir, outsider int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('owner', 'owner@x', 'stub') RETURNING id`,
+	).Scan(&owner))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('heir', 'heir@x', 'stub') RETURNING id`,
+	).Scan(&heir))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('out', 'out@x', 'stub') RETURNING id`,
+	).Scan(&outsider))
+	require.NoError(t, store.AddUserToOrg(ctx, orgID, owner, models.RoleAdmin))
+	require.NoError(t, store.AddUserToOrg(ctx, orgID, heir, models.RoleOperator))
+	_, err := pool.Exec(ctx, `UPDATE trakrf.organizations SET owner_user_id = $2 WHERE id = $1`, orgID, owner)
+	require.NoError(t, err)
+
+	_, err = store.CreateOwnershipTransfer(ctx, orgID, heir, organization.TransferOwnershipRequest{UserID: owner})
+	assert.ErrorIs(t, err, storage.ErrOwnershipNotOwner)
+	_, err = store.CreateOwnershipTransfer(ctx, orgID, owner, organization.TransferOwnershipRequest{UserID: outsider})
+	assert.ErrorIs(t, err, storage.ErrOwnershipTargetNotMember)
+	_, err = store.CreateOwnershipTransfer(ctx, orgID, owner, organization.TransferOwnershipRequest{UserID: owner})
+	assert.ErrorIs(t, err, storage.ErrOwnershipTargetIsOwner)
+
+	tr, err := store.CreateOwnershipTransfer(ctx, orgID, owner, organization.TransferOwnershipRequest{UserID: heir})
+	require.NoError(t, err)
+	assert.Equal(t, organization.TransferStatusPending, tr.Status)
+	assert.Equal(t, organization.DefaultPreviousOwnerRole, tr.PreviousOwnerRole)
+
+	_, err = store.CreateOwnershipTransfer(ctx, orgID, owner, organization.TransferOwnershipRequest{UserID: heir})
+	assert.ErrorIs(t, err, storage.ErrOwnershipTransferPending)
+
+	_, err = store.DecideOwnershipTransfer(ctx, orgID, tr.ID, owner, organization.TransferActionAccept)
+	assert.ErrorIs(t, err, storage.ErrOwnershipNotRecipient)
+
+	tr, err = store.DecideOwnershipTransfer(ctx, orgID, tr.ID, heir, organization.TransferActionAccept)
+	require.NoError(t, err)
+	assert.Equal(t, organization.TransferStatusAccepted, tr.Status)
+
+	got, err := store.GetOrgOwnership(ctx, orgID)
+	require.NoError(t, err)
+	require.NotNil(t, got.OwnerUserID)
+	assert.Equal(t, heir, *got.OwnerUserID)
+	assert.Nil(t, got.PendingTransfer)
+
+	role, err := store.GetUserOrgRole(ctx, heir, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleAdmin, role)
+	role, err = store.GetUserOrgRole(ctx, owner, orgID)
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleManager, role)
+
+	_, err = store.DecideOwnershipTransfer(ctx, orgID, tr.ID, heir, organization.TransferActionAccept)
+	assert.ErrorIs(t, err, storage.ErrOwnershipTransferState)
+}
+
+func TestOwnershipTransfer_ExpiredDoesNotBlock(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	var owner, heir int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('owner', 'owner@x', 'stub') RETURNING id`,
+	).Scan(&owner))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('heir', 'heir@x', 'stub') RETURNING id`,
+	).Scan(&heir))
+	require.NoError(t, store.AddUserToOrg(ctx, orgID, owner, models.RoleAdmin))
+	require.NoError(t, store.AddUserToOrg(ctx, orgID, heir, models.RoleViewer))
+
+	// With no recorded owner, an admin may propose.
+	tr, err := store.CreateOwnershipTransfer(ctx, orgID, owner, organization.TransferOwnershipRequest{UserID: heir, PreviousOwnerRole: "viewer"})
+	require.NoError(t, err)
+	_, err = pool.Exec(ctx, `UPDATE trakrf.org_ownership_transfers SET expires_at = NOW() - INTERVAL '1 minute' WHERE id = $1`, tr.ID)
+	require.NoError(t, err)
+
+	_, err = store.DecideOwnershipTransfer(ctx, orgID, tr.ID, heir, organization.TransferActionAccept)
+	assert.ErrorIs(t, err, storage.ErrOwnershipTransferState)
+
+	next, err := store.CreateOwnershipTransfer(ctx, orgID, owner, organization.TransferOwnershipRequest{UserID: heir})
+	require.NoError(t, err)
+	assert.NotEqual(t, tr.ID, next.ID)
+
+	next, err = store.DecideOwnershipTransfer(ctx, orgID, next.ID, owner, organization.TransferActionCancel)
+	require.NoError(t, err)
+	assert.Equal(t, organization.TransferStatusCancelled, next.Status)
+
+	old, err := store.GetOwnershipTransfer(ctx, orgID, tr.ID)
+	require.NoError(t, err)
+	assert.Equal(t, organization.TransferStatusExpired, old.Status)
+}
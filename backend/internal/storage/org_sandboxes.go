@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// ErrSandboxOfSandbox is returned when cloning an org that is itself a
+// sandbox: sandboxes are always cloned from the production org.
+var ErrSandboxOfSandbox = errors.New("organization is already a sandbox")
+
+// CreateSandboxOrg clones sourceOrgID into a new sandbox org with userID as
+// its admin, in one transaction. The sandbox gets the source's live
+// locations (with their hierarchy), assets and tags, and its entitlement, so
+// an integrator can exercise the API against realistic data. Scan history,
+// members, credentials, webhooks, connectors and devices are not copied.
+//
+// Returns (nil, nil) when the source org does not exist and
+// ErrSandboxOfSandbox when it is a sandbox.
+func (s *Storage) CreateSandboxOrg(ctx context.Context, sourceOrgID, userID int) (*organization.Organization, error) {
+	src, err := s.GetOrganizationByID(ctx, sourceOrgID)
+	if err != nil || src == nil {
+		return nil, err
+	}
+	if src.IsSandbox {
+		return nil, ErrSandboxOfSandbox
+	}
+
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate sandbox identifier: %w", err)
+	}
+	name := src.Name + " (sandbox)"
+	identifier := src.Identifier + "-sandbox-" + hex.EncodeToString(suffix)
+
+	var id int
+	err = s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := setTxOrg(ctx, tx, sourceOrgID); err != nil {
+			return err
+		}
+		data, err := readSandboxData(ctx, tx, sourceOrgID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.organizations
+				(name, identifier, is_sandbox, sandbox_of, subscription_enabled, subscription_expires_at, owner_user_id)
+			VALUES ($1, $2, true, $3, $4, $5, $6)
+			RETURNING id`,
+			name, identifier, sourceOrgID, src.SubscriptionEnabled, src.SubscriptionExpiresAt, userID).Scan(&id); err != nil {
+			return fmt.Errorf("insert sandbox org: %w", err)
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO trakrf.org_users (org_id, user_id, role) VALUES ($1, $2, 'admin')`,
+			id, userID); err != nil {
+			return fmt.Errorf("add sandbox admin: %w", err)
+		}
+
+		if err := setTxOrg(ctx, tx, id); err != nil {
+			return err
+		}
+		var res organization.ImportResult
+		return s.importRows(ctx, tx, id, *data, &res)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sandbox org: %w", err)
+	}
+	return s.GetOrganizationByID(ctx, id)
+}
+
+// readSandboxData reads orgID's live locations, assets and tags in the shape
+// an import applies. Locations come parents first; one whose parent is
+// deleted is read as a root.
+func readSandboxData(ctx context.Context, tx pgx.Tx, orgID int) (*organization.ImportData, error) {
+	data := &organization.ImportData{SourceOrgID: orgID}
+	if err := readSandboxRows(ctx, tx, &data.Locations, `
+		WITH RECURSIVE live AS (
+			SELECT * FROM trakrf.locations WHERE org_id = $1 AND deleted_at IS NULL
+		), tree AS (
+			SELECT l.id, 0 AS depth FROM live l
+			WHERE NOT EXISTS (SELECT 1 FROM live p WHERE p.id = l.parent_location_id)
+			UNION ALL
+			SELECT c.id, t.depth + 1 FROM live c JOIN tree t ON c.parent_location_id = t.id
+		)
+		SELECT to_jsonb(l) FROM tree JOIN live l USING (id)
+		ORDER BY tree.depth, l.id`, orgID); err != nil {
+		return nil, fmt.Errorf("read locations: %w", err)
+	}
+	if err := readSandboxRows(ctx, tx, &data.Assets, `
+		SELECT to_jsonb(a) FROM trakrf.assets a
+		WHERE a.org_id = $1 AND a.deleted_at IS NULL
+		ORDER BY a.id`, orgID); err != nil {
+		return nil, fmt.Errorf("read assets: %w", err)
+	}
+	if err := readSandboxRows(ctx, tx, &data.Tags, `
+		SELECT to_jsonb(t) FROM trakrf.tags t
+		WHERE t.org_id = $1 AND t.deleted_at IS NULL
+		ORDER BY t.id`, orgID); err != nil {
+		return nil, fmt.Errorf("read tags: %w", err)
+	}
+	return data, nil
+}
+
+// readSandboxRows decodes each row of a single-jsonb-column query into out.
+func readSandboxRows[T any](ctx context.Context, tx pgx.Tx, out *[]T, query string, orgID int) error {
+	rows, err := tx.Query(ctx, query, orgID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return err
+		}
+		var v T
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		*out = append(*out, v)
+	}
+	return rows.Err()
+}
+
+// OrgIsSandbox reports whether orgID is a sandbox org. A missing org is not.
+func (s *Storage) OrgIsSandbox(ctx context.Context, orgID int) (bool, error) {
+	var sandbox bool
+	err := s.pool.QueryRow(ctx,
+		`SELECT is_sandbox FROM trakrf.organizations WHERE id = $1`, orgID).Scan(&sandbox)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check sandbox org: %w", err)
+	}
+	return sandbox, nil
+}
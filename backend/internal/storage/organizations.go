@@ -14,7 +14,7 @@ import (
 // ListUserOrgs returns all organizations the user belongs to
 func (s *Storage) ListUserOrgs(ctx context.Context, userID int) ([]organization.UserOrg, error) {
 	query := `
-		SELECT o.id, o.name
+		SELECT o.id, o.name, o.is_sandbox
 		FROM trakrf.organizations o
 		JOIN trakrf.org_users ou ON o.id = ou.org_id
 		WHERE ou.user_id = $1
@@ -31,7 +31,7 @@ func (s *Storage) ListUserOrgs(ctx context.Context, userID int) ([]organization.
 	orgs := []organization.UserOrg{}
 	for rows.Next() {
 		var org organization.UserOrg
-		if err := rows.Scan(&org.ID, &org.Name); err != nil {
+		if err := rows.Scan(&org.ID, &org.Name, &org.IsSandbox); err != nil {
 			return nil, fmt.Errorf("failed to scan org: %w", err)
 		}
 		orgs = append(orgs, org)
@@ -45,17 +45,24 @@ func (s *Storage) ListUserOrgs(ctx context.Context, userID int) ([]organization.
 // RequireSuperadmin middleware. The member count is a left-join aggregate so
 // member-less orgs still appear (count 0).
 func (s *Storage) ListAllOrgs(ctx context.Context) ([]organization.AdminOrgListItem, error) {
+	return s.listAdminOrgs(ctx, "TRUE")
+}
+
+// listAdminOrgs returns the live orgs matching where, in the superadmin list
+// shape. where is a fixed SQL condition on o; args bind its placeholders.
+func (s *Storage) listAdminOrgs(ctx context.Context, where string, args ...any) ([]organization.AdminOrgListItem, error) {
 	query := `
 		SELECT o.id, o.name, o.identifier,
 		       o.subscription_enabled, o.subscription_expires_at,
-		       COUNT(ou.user_id) FILTER (WHERE ou.deleted_at IS NULL) AS member_count
+		       COUNT(ou.user_id) FILTER (WHERE ou.deleted_at IS NULL) AS member_count,
+		       o.partner_id
 		FROM trakrf.organizations o
 		LEFT JOIN trakrf.org_users ou ON ou.org_id = o.id
-		WHERE o.deleted_at IS NULL
-		GROUP BY o.id, o.name, o.identifier, o.subscription_enabled, o.subscription_expires_at
+		WHERE o.deleted_at IS NULL AND ` + where + `
+		GROUP BY o.id, o.name, o.identifier, o.subscription_enabled, o.subscription_expires_at, o.partner_id
 		ORDER BY o.name ASC
 	`
-	rows, err := s.pool.Query(ctx, query)
+	rows, err := s.pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list all orgs: %w", err)
 	}
@@ -65,7 +72,7 @@ func (s *Storage) ListAllOrgs(ctx context.Context) ([]organization.AdminOrgListI
 	for rows.Next() {
 		var o organization.AdminOrgListItem
 		if err := rows.Scan(&o.ID, &o.Name, &o.Identifier,
-			&o.SubscriptionEnabled, &o.SubscriptionExpiresAt, &o.MemberCount); err != nil {
+			&o.SubscriptionEnabled, &o.SubscriptionExpiresAt, &o.MemberCount, &o.PartnerID); err != nil {
 			return nil, fmt.Errorf("failed to scan admin org: %w", err)
 		}
 		orgs = append(orgs, o)
@@ -87,13 +94,13 @@ func (s *Storage) UpdateOrgEntitlement(ctx context.Context, id int, enabled bool
 		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING id, name, identifier, metadata,
 		          valid_from, valid_to, is_active, created_at, updated_at,
-		          subscription_enabled, subscription_expires_at
+		          subscription_enabled, subscription_expires_at, is_sandbox, sandbox_of
 	`
 	var org organization.Organization
 	err := s.pool.QueryRow(ctx, query, id, enabled, expiresAt).Scan(
 		&org.ID, &org.Name, &org.Identifier, &org.Metadata,
 		&org.ValidFrom, &org.ValidTo, &org.IsActive, &org.CreatedAt, &org.UpdatedAt,
-		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt)
+		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt, &org.IsSandbox, &org.SandboxOf)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -109,7 +116,7 @@ func (s *Storage) GetOrganizationByID(ctx context.Context, id int) (*organizatio
 	query := `
 		SELECT id, name, identifier, metadata,
 		       valid_from, valid_to, is_active, created_at, updated_at,
-		       subscription_enabled, subscription_expires_at
+		       subscription_enabled, subscription_expires_at, is_sandbox, sandbox_of
 		FROM trakrf.organizations
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -117,7 +124,7 @@ func (s *Storage) GetOrganizationByID(ctx context.Context, id int) (*organizatio
 	err := s.pool.QueryRow(ctx, query, id).Scan(
 		&org.ID, &org.Name, &org.Identifier, &org.Metadata,
 		&org.ValidFrom, &org.ValidTo, &org.IsActive, &org.CreatedAt, &org.UpdatedAt,
-		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt)
+		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt, &org.IsSandbox, &org.SandboxOf)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -135,7 +142,7 @@ func (s *Storage) GetOrganizationByIdentifier(ctx context.Context, identifier st
 	query := `
 		SELECT id, name, identifier, metadata,
 		       valid_from, valid_to, is_active, created_at, updated_at,
-		       subscription_enabled, subscription_expires_at
+		       subscription_enabled, subscription_expires_at, is_sandbox, sandbox_of
 		FROM trakrf.organizations
 		WHERE identifier = $1 AND deleted_at IS NULL
 	`
@@ -143,7 +150,7 @@ func (s *Storage) GetOrganizationByIdentifier(ctx context.Context, identifier st
 	err := s.pool.QueryRow(ctx, query, identifier).Scan(
 		&org.ID, &org.Name, &org.Identifier, &org.Metadata,
 		&org.ValidFrom, &org.ValidTo, &org.IsActive, &org.CreatedAt, &org.UpdatedAt,
-		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt)
+		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt, &org.IsSandbox, &org.SandboxOf)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -162,13 +169,13 @@ func (s *Storage) CreateOrganization(ctx context.Context, name, identifier strin
 		VALUES ($1, $2)
 		RETURNING id, name, identifier, metadata,
 		          valid_from, valid_to, is_active, created_at, updated_at,
-		          subscription_enabled, subscription_expires_at
+		          subscription_enabled, subscription_expires_at, is_sandbox, sandbox_of
 	`
 	var org organization.Organization
 	err := s.pool.QueryRow(ctx, query, name, identifier).Scan(
 		&org.ID, &org.Name, &org.Identifier, &org.Metadata,
 		&org.ValidFrom, &org.ValidTo, &org.IsActive, &org.CreatedAt, &org.UpdatedAt,
-		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt)
+		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt, &org.IsSandbox, &org.SandboxOf)
 
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
@@ -191,13 +198,13 @@ func (s *Storage) UpdateOrganization(ctx context.Context, id int, request organi
 		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING id, name, identifier, metadata,
 		          valid_from, valid_to, is_active, created_at, updated_at,
-		          subscription_enabled, subscription_expires_at
+		          subscription_enabled, subscription_expires_at, is_sandbox, sandbox_of
 	`
 	var org organization.Organization
 	err := s.pool.QueryRow(ctx, query, id, *request.Name).Scan(
 		&org.ID, &org.Name, &org.Identifier, &org.Metadata,
 		&org.ValidFrom, &org.ValidTo, &org.IsActive, &org.CreatedAt, &org.UpdatedAt,
-		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt)
+		&org.SubscriptionEnabled, &org.SubscriptionExpiresAt, &org.IsSandbox, &org.SandboxOf)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -303,3 +310,167 @@ func (s *Storage) SoftDeleteOrganizationWithMangle(ctx context.Context, id int,
 	}
 	return nil
 }
+
+// GetEmailBranding returns the org's email branding (zero value when unset),
+// or nil when the org does not exist.
+func (s *Storage) GetEmailBranding(ctx context.Context, orgID int) (*organization.EmailBranding, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'email_branding' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email branding: %w", err)
+	}
+	var eb organization.EmailBranding
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &eb); err != nil {
+			return nil, fmt.Errorf("failed to decode email branding: %w", err)
+		}
+	}
+	return &eb, nil
+}
+
+// UpdateEmailBranding replaces metadata.email_branding with eb. Other
+// metadata keys are preserved.
+func (s *Storage) UpdateEmailBranding(ctx context.Context, orgID int, eb organization.EmailBranding) error {
+	blob, err := json.Marshal(eb)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email branding: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{email_branding}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update email branding: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// GetAppTheme returns the org's app theme (zero value when unset), or nil
+// when the org does not exist.
+func (s *Storage) GetAppTheme(ctx context.Context, orgID int) (*organization.AppTheme, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'app_theme' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get app theme: %w", err)
+	}
+	var theme organization.AppTheme
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &theme); err != nil {
+			return nil, fmt.Errorf("failed to decode app theme: %w", err)
+		}
+	}
+	return &theme, nil
+}
+
+// UpdateAppTheme replaces metadata.app_theme with theme. Other metadata keys
+// are preserved.
+func (s *Storage) UpdateAppTheme(ctx context.Context, orgID int, theme organization.AppTheme) error {
+	blob, err := json.Marshal(theme)
+	if err != nil {
+		return fmt.Errorf("failed to marshal app theme: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{app_theme}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update app theme: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// FindBrandedApp resolves an SPA entry point to the themed org it belongs
+// to: the org whose identifier is identifier, else the one listing origin
+// among its link domains. Either argument may be empty. Orgs without an app
+// theme never match, so an entry point cannot be used to probe org names.
+// Returns nil when nothing matches.
+func (s *Storage) FindBrandedApp(ctx context.Context, identifier, origin string) (*organization.BrandedApp, error) {
+	if identifier == "" && origin == "" {
+		return nil, nil
+	}
+	var name string
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT name, metadata->'app_theme' FROM trakrf.organizations
+		WHERE deleted_at IS NULL
+		  AND metadata ? 'app_theme'
+		  AND (($1 <> '' AND identifier = $1)
+		       OR ($2 <> '' AND metadata->'link_domains'->'origins' ? $2))
+		ORDER BY (identifier = $1) DESC, id
+		LIMIT 1`, identifier, origin).Scan(&name, &blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find branded app: %w", err)
+	}
+	app := organization.BrandedApp{OrgName: name}
+	if err := json.Unmarshal(blob, &app.Theme); err != nil {
+		return nil, fmt.Errorf("failed to decode app theme: %w", err)
+	}
+	return &app, nil
+}
+
+// GetLinkDomains returns the org's link domains (empty when unset), or nil
+// when the org does not exist.
+func (s *Storage) GetLinkDomains(ctx context.Context, orgID int) (*organization.LinkDomains, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'link_domains' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get link domains: %w", err)
+	}
+	ld := organization.LinkDomains{Origins: []string{}}
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &ld); err != nil {
+			return nil, fmt.Errorf("failed to decode link domains: %w", err)
+		}
+	}
+	if ld.Origins == nil {
+		ld.Origins = []string{}
+	}
+	return &ld, nil
+}
+
+// UpdateLinkDomains replaces metadata.link_domains with ld. Other metadata
+// keys are preserved.
+func (s *Storage) UpdateLinkDomains(ctx context.Context, orgID int, ld organization.LinkDomains) error {
+	blob, err := json.Marshal(ld)
+	if err != nil {
+		return fmt.Errorf("failed to marshal link domains: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{link_domains}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update link domains: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
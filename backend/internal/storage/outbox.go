@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+)
+
+// OutboxEvent is one trakrf.event_outbox row: a committed change awaiting
+// export. Data is the entity row (to_jsonb) as of the writing transaction.
+type OutboxEvent struct {
+	ID        int64
+	OrgID     int
+	Type      events.Type
+	EntityID  *int
+	Data      json.RawMessage
+	CreatedAt time.Time
+	Attempts  int
+}
+
+// outboxEntityTables maps an event's entity prefix to the table whose row is
+// snapshotted into the outbox. Only these tables can be named in the INSERT,
+// so the table name is never caller-controlled.
+var outboxEntityTables = map[events.Type]string{
+	events.AssetCreated:      "trakrf.assets",
+	events.AssetUpdated:      "trakrf.assets",
+	events.AssetDeleted:      "trakrf.assets",
+	events.LocationCreated:   "trakrf.locations",
+	events.LocationUpdated:   "trakrf.locations",
+	events.LocationDeleted:   "trakrf.locations",
+	events.ScanDeviceCreated: "trakrf.scan_devices",
+	events.ScanDeviceUpdated: "trakrf.scan_devices",
+	events.ScanDeviceDeleted: "trakrf.scan_devices",
+
+	events.SensorAlertOpened:   "trakrf.sensor_alerts",
+	events.SensorAlertResolved: "trakrf.sensor_alerts",
+
+	events.StockAlertOpened:   "trakrf.stock_alerts",
+	events.StockAlertResolved: "trakrf.stock_alerts",
+
+	events.AssetOverdue: "trakrf.assets",
+
+	events.DockMovementRecorded: "trakrf.dock_movements",
+
+	events.DocumentExpiring: "trakrf.documents",
+	events.DocumentExpired:  "trakrf.documents",
+}
+
+// EnableEventOutbox makes every publishing write also append to
+// trakrf.event_outbox for the event exporter. Off by default so deployments
+// without an export sink don't accumulate rows nobody drains. Call once at
+// boot, before the store serves requests.
+func (s *Storage) EnableEventOutbox() {
+	s.outbox = true
+}
+
+// enqueueOutbox snapshots each entity row into the outbox on tx. The rows are
+// read inside the writing transaction, so the exported data is exactly what
+// committed (a soft delete exports the row with deleted_at set).
+// Sandbox orgs are never exported: their writes stay on the platform.
+func (s *Storage) enqueueOutbox(ctx context.Context, tx pgx.Tx, typ events.Type, orgID int, entityIDs []int) error {
+	table, ok := outboxEntityTables[typ]
+	if !ok {
+		return fmt.Errorf("no outbox entity table for event type %q", typ)
+	}
+	_, err := tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO trakrf.event_outbox (org_id, event_type, entity_id, data)
+		SELECT $1::bigint, $2::text, e.id, to_jsonb(e)
+		FROM unnest($3::bigint[]) WITH ORDINALITY AS ids(id, n)
+		JOIN %s e ON e.id = ids.id
+		WHERE NOT EXISTS (SELECT 1 FROM trakrf.organizations o WHERE o.id = $1 AND o.is_sandbox)
+		ORDER BY ids.n`, table),
+		orgID, string(typ), entityIDs)
+	if err != nil {
+		return fmt.Errorf("enqueue %s outbox event: %w", typ, err)
+	}
+	return nil
+}
+
+// enqueueOutboxData appends an event whose payload is built by the caller
+// (e.g. scan.recorded, which has no single entity row to snapshot). Like
+// enqueueOutbox, it skips sandbox orgs.
+func (s *Storage) enqueueOutboxData(ctx context.Context, tx pgx.Tx, typ events.Type, orgID int, entityID *int, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal %s outbox event: %w", typ, err)
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO trakrf.event_outbox (org_id, event_type, entity_id, data)
+		SELECT $1::bigint, $2::text, $3::bigint, $4::jsonb
+		WHERE NOT EXISTS (SELECT 1 FROM trakrf.organizations o WHERE o.id = $1 AND o.is_sandbox)`,
+		orgID, string(typ), entityID, payload)
+	if err != nil {
+		return fmt.Errorf("enqueue %s outbox event: %w", typ, err)
+	}
+	return nil
+}
+
+// DrainOutbox locks up to limit of the oldest unpublished outbox rows and hands
+// them to fn. When fn returns nil the rows are stamped published; otherwise
+// their attempt count and last_error are recorded, they stay pending for the
+// next drain, and fn's error is returned. FOR UPDATE SKIP LOCKED lets several replicas drain concurrently
+// without double-sending a row. Returns the number of rows handed to fn.
+func (s *Storage) DrainOutbox(ctx context.Context, limit int, fn func(ctx context.Context, batch []OutboxEvent) error) (int, error) {
+	var (
+		n       int
+		sendErr error
+	)
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT id, org_id, event_type, entity_id, data, created_at, attempts
+			FROM trakrf.event_outbox
+			WHERE published_at IS NULL
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED`, limit)
+		if err != nil {
+			return fmt.Errorf("select outbox batch: %w", err)
+		}
+		var batch []OutboxEvent
+		for rows.Next() {
+			var ev OutboxEvent
+			var typ string
+			if err := rows.Scan(&ev.ID, &ev.OrgID, &typ, &ev.EntityID, &ev.Data, &ev.CreatedAt, &ev.Attempts); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan outbox row: %w", err)
+			}
+			ev.Type = events.Type(typ)
+			batch = append(batch, ev)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate outbox batch: %w", err)
+		}
+		n = len(batch)
+		if n == 0 {
+			return nil
+		}
+
+		ids := make([]int64, n)
+		for i, ev := range batch {
+			ids[i] = ev.ID
+		}
+
+		if sendErr = fn(ctx, batch); sendErr != nil {
+			// Record the failure and commit it; the rows stay pending.
+			_, err := tx.Exec(ctx, `
+				UPDATE trakrf.event_outbox
+				SET attempts = attempts + 1, last_error = $2
+				WHERE id = ANY($1)`, ids, sendErr.Error())
+			if err != nil {
+				return fmt.Errorf("record outbox failure: %w", err)
+			}
+			n = 0
+			return nil
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.event_outbox
+			SET published_at = NOW(), attempts = attempts + 1, last_error = NULL
+			WHERE id = ANY($1)`, ids)
+		if err != nil {
+			return fmt.Errorf("mark outbox published: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if sendErr != nil {
+		return 0, sendErr
+	}
+	return n, nil
+}
+
+// PruneOutbox deletes rows published before cutoff and returns how many.
+func (s *Storage) PruneOutbox(ctx context.Context, cutoff time.Time) (int64, error) {
+	ct, err := s.pool.Exec(ctx,
+		`DELETE FROM trakrf.event_outbox WHERE published_at IS NOT NULL AND published_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("prune event outbox: %w", err)
+	}
+	return ct.RowsAffected(), nil
+}
@@ -0,0 +1,78 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestOutbox_WriteQueuesSnapshotAndDrainMarksPublished(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := context.Background()
+	db.Store.EnableEventOutbox()
+
+	assetID := preCreateAssetWithTag(t, db, orgID, "outbox-forklift", "E2801190A503006543E2FFFF")
+
+	var got []storage.OutboxEvent
+	n, err := db.Store.DrainOutbox(ctx, 10, func(_ context.Context, batch []storage.OutboxEvent) error {
+		got = batch
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	assert.Equal(t, events.AssetCreated, got[0].Type)
+	assert.Equal(t, orgID, got[0].OrgID)
+	require.NotNil(t, got[0].EntityID)
+	assert.Equal(t, assetID, *got[0].EntityID)
+
+	var data map[string]any
+	require.NoError(t, json.Unmarshal(got[0].Data, &data))
+	assert.Equal(t, "outbox-forklift", data["name"], "data is the committed entity row")
+
+	n, err = db.Store.DrainOutbox(ctx, 10, func(context.Context, []storage.OutboxEvent) error {
+		t.Fatal("published rows must not be drained again")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	pruned, err := db.Store.PruneOutbox(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), pruned)
+}
+
+func TestOutbox_FailedSendStaysPending(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := context.Background()
+	db.Store.EnableEventOutbox()
+
+	preCreateAssetWithTag(t, db, orgID, "outbox-retry", "E2801190A503006543E2EEEE")
+
+	errBroker := errors.New("broker down")
+	_, err := db.Store.DrainOutbox(ctx, 10, func(context.Context, []storage.OutboxEvent) error {
+		return errBroker
+	})
+	require.ErrorIs(t, err, errBroker)
+
+	var retried []storage.OutboxEvent
+	_, err = db.Store.DrainOutbox(ctx, 10, func(_ context.Context, batch []storage.OutboxEvent) error {
+		retried = batch
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, retried, 1)
+	assert.Equal(t, 1, retried[0].Attempts, "the failed attempt is recorded")
+}
@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// ErrPartnerAdminUser is returned when a partner admin grant names a user
+// that does not exist.
+var ErrPartnerAdminUser = errors.New("user not found")
+
+// IsPartnerAdmin reports whether userID administers partner partnerID.
+func (s *Storage) IsPartnerAdmin(ctx context.Context, partnerID, userID int) (bool, error) {
+	var ok bool
+	err := s.pool.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM trakrf.partner_admins pa
+			JOIN trakrf.partners p ON p.id = pa.partner_id AND p.deleted_at IS NULL
+			WHERE pa.partner_id = $1 AND pa.user_id = $2)`, partnerID, userID).Scan(&ok)
+	if err != nil {
+		return false, fmt.Errorf("failed to check partner admin: %w", err)
+	}
+	return ok, nil
+}
+
+// ListPartnerAdmins returns partnerID's admins by name.
+func (s *Storage) ListPartnerAdmins(ctx context.Context, partnerID int) ([]organization.PartnerAdmin, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT pa.partner_id, pa.user_id, u.name, u.email, pa.created_at
+		FROM trakrf.partner_admins pa
+		JOIN trakrf.users u ON u.id = pa.user_id AND u.deleted_at IS NULL
+		WHERE pa.partner_id = $1
+		ORDER BY u.name, u.id`, partnerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partner admins: %w", err)
+	}
+	defer rows.Close()
+
+	admins := []organization.PartnerAdmin{}
+	for rows.Next() {
+		var a organization.PartnerAdmin
+		if err := rows.Scan(&a.PartnerID, &a.UserID, &a.Name, &a.Email, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan partner admin: %w", err)
+		}
+		admins = append(admins, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate partner admins: %w", err)
+	}
+	return admins, nil
+}
+
+// AddPartnerAdmin makes userID an admin of partnerID; granting an existing
+// admin again is a no-op. It returns nil when the partner does not exist.
+func (s *Storage) AddPartnerAdmin(ctx context.Context, partnerID, userID, grantedBy int) (*organization.PartnerAdmin, error) {
+	p, err := s.GetPartner(ctx, partnerID)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	var a organization.PartnerAdmin
+	err = s.pool.QueryRow(ctx, `
+		WITH ins AS (
+			INSERT INTO trakrf.partner_admins (partner_id, user_id, created_by)
+			SELECT $1, id, $3 FROM trakrf.users WHERE id = $2 AND deleted_at IS NULL
+			ON CONFLICT (partner_id, user_id) DO NOTHING
+			RETURNING partner_id, user_id, created_at
+		)
+		SELECT x.partner_id, x.user_id, u.name, u.email, x.created_at
+		FROM (
+			SELECT partner_id, user_id, created_at FROM ins
+			UNION ALL
+			SELECT partner_id, user_id, created_at FROM trakrf.partner_admins
+			WHERE partner_id = $1 AND user_id = $2
+		) x
+		JOIN trakrf.users u ON u.id = x.user_id
+		LIMIT 1`, partnerID, userID, grantedBy).Scan(&a.PartnerID, &a.UserID, &a.Name, &a.Email, &a.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPartnerAdminUser
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrPartnerAdminUser
+		}
+		return nil, fmt.Errorf("failed to add partner admin: %w", err)
+	}
+	return &a, nil
+}
+
+// RemovePartnerAdmin revokes userID's admin grant on partnerID. It reports
+// whether a grant was removed.
+func (s *Storage) RemovePartnerAdmin(ctx context.Context, partnerID, userID int) (bool, error) {
+	tag, err := s.pool.Exec(ctx,
+		`DELETE FROM trakrf.partner_admins WHERE partner_id = $1 AND user_id = $2`, partnerID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove partner admin: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ListUserPartners returns the live partners userID administers.
+func (s *Storage) ListUserPartners(ctx context.Context, userID int) ([]organization.Partner, error) {
+	rows, err := s.pool.Query(ctx, partnerSelect+`
+		JOIN trakrf.partner_admins pa ON pa.partner_id = p.id
+		WHERE pa.user_id = $1 AND p.deleted_at IS NULL
+		ORDER BY p.name, p.id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user partners: %w", err)
+	}
+	defer rows.Close()
+
+	partners := []organization.Partner{}
+	for rows.Next() {
+		p, err := scanPartner(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan partner: %w", err)
+		}
+		partners = append(partners, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate partners: %w", err)
+	}
+	return partners, nil
+}
+
+// GetPartnerRollup totals assets, utilization and open alerts across the
+// live orgs partnerID owns, with one line per org. An asset counts as seen
+// when a reader saw it at or after since. Each org is read in its own org
+// transaction, so RLS applies as it does to any org-scoped read. It returns
+// nil when the partner does not exist.
+func (s *Storage) GetPartnerRollup(ctx context.Context, partnerID int, since time.Time) (*organization.PartnerRollup, error) {
+	p, err := s.GetPartner(ctx, partnerID)
+	if err != nil || p == nil {
+		return nil, err
+	}
+	orgs, err := s.listAdminOrgs(ctx, "o.partner_id = $1", partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	rollup := &organization.PartnerRollup{PartnerID: partnerID, Since: since, Orgs: []organization.OrgRollup{}}
+	for _, o := range orgs {
+		line := organization.OrgRollup{OrgID: o.ID, Name: o.Name}
+		c := &line.RollupCounts
+		err := s.WithOrgTx(ctx, o.ID, func(tx pgx.Tx) error {
+			return tx.QueryRow(ctx, `
+				SELECT
+					(SELECT COUNT(*) FROM trakrf.assets
+					 WHERE org_id = $1 AND deleted_at IS NULL),
+					(SELECT COUNT(*) FROM trakrf.assets
+					 WHERE org_id = $1 AND deleted_at IS NULL AND is_active),
+					(SELECT COUNT(DISTINCT l.asset_id) FROM trakrf.asset_scan_latest l
+					 JOIN trakrf.assets a ON a.id = l.asset_id AND a.deleted_at IS NULL AND a.is_active
+					 WHERE l.org_id = $1 AND l.bucket >= time_bucket(INTERVAL '1 minute', $2::timestamptz)),
+					(SELECT COUNT(*) FROM trakrf.sensor_alerts
+					 WHERE org_id = $1 AND resolved_at IS NULL),
+					(SELECT COUNT(*) FROM trakrf.stock_alerts
+					 WHERE org_id = $1 AND resolved_at IS NULL)`,
+				o.ID, since).Scan(&c.Assets, &c.ActiveAssets, &c.AssetsSeen, &c.OpenSensorAlerts, &c.OpenStockAlerts)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to roll up org %d: %w", o.ID, err)
+		}
+		c.Finish()
+		rollup.Totals.Add(line.RollupCounts)
+		rollup.Orgs = append(rollup.Orgs, line)
+	}
+	rollup.Totals.Finish()
+	return rollup, nil
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+var (
+	// ErrPartnerNotFound is returned when an org is moved to a partner that
+	// does not exist, was deleted, or is inactive.
+	ErrPartnerNotFound = errors.New("partner not found")
+	// ErrPartnerIdentifierTaken is returned when a new partner reuses an
+	// existing identifier.
+	ErrPartnerIdentifierTaken = errors.New("partner identifier already taken")
+)
+
+// Partners are internal billing entities with no per-org RLS; every access
+// here is superadmin-only, enforced by RequireSuperadmin.
+const partnerSelect = `
+	SELECT p.id, p.name, p.identifier, p.billing_email, p.is_active,
+	       (SELECT COUNT(*) FROM trakrf.organizations o
+	        WHERE o.partner_id = p.id AND o.deleted_at IS NULL),
+	       p.created_at, p.updated_at
+	FROM trakrf.partners p`
+
+func scanPartner(row pgx.Row) (*organization.Partner, error) {
+	var p organization.Partner
+	if err := row.Scan(&p.ID, &p.Name, &p.Identifier, &p.BillingEmail, &p.IsActive,
+		&p.OrgCount, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ListPartners returns every non-deleted partner, by name.
+func (s *Storage) ListPartners(ctx context.Context) ([]organization.Partner, error) {
+	rows, err := s.pool.Query(ctx, partnerSelect+`
+		WHERE p.deleted_at IS NULL
+		ORDER BY p.name, p.id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list partners: %w", err)
+	}
+	defer rows.Close()
+
+	partners := []organization.Partner{}
+	for rows.Next() {
+		p, err := scanPartner(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan partner: %w", err)
+		}
+		partners = append(partners, *p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate partners: %w", err)
+	}
+	return partners, nil
+}
+
+// GetPartner returns a non-deleted partner, or nil when none matches.
+func (s *Storage) GetPartner(ctx context.Context, id int) (*organization.Partner, error) {
+	p, err := scanPartner(s.pool.QueryRow(ctx, partnerSelect+`
+		WHERE p.id = $1 AND p.deleted_at IS NULL`, id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get partner: %w", err)
+	}
+	return p, nil
+}
+
+// CreatePartner creates an active partner.
+func (s *Storage) CreatePartner(ctx context.Context, req organization.CreatePartnerRequest) (*organization.Partner, error) {
+	var id int
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.partners (name, identifier, billing_email)
+		VALUES ($1, $2, $3)
+		RETURNING id`, req.Name, req.Identifier, req.BillingEmail).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrPartnerIdentifierTaken
+		}
+		return nil, fmt.Errorf("failed to create partner: %w", err)
+	}
+	return s.GetPartner(ctx, id)
+}
+
+// ListPartnerOrgs returns the live orgs owned by partner id, in the
+// superadmin org list shape. found is false when the partner does not exist.
+func (s *Storage) ListPartnerOrgs(ctx context.Context, id int) (orgs []organization.AdminOrgListItem, found bool, err error) {
+	p, err := s.GetPartner(ctx, id)
+	if err != nil || p == nil {
+		return nil, false, err
+	}
+	orgs, err = s.listAdminOrgs(ctx, "o.partner_id = $1", id)
+	if err != nil {
+		return nil, false, err
+	}
+	return orgs, true, nil
+}
+
+// SetOrgPartner moves org orgID under partnerID, or detaches it from its
+// partner when partnerID is nil. The target partner must exist and be
+// active. It returns nil when no live org matches.
+func (s *Storage) SetOrgPartner(ctx context.Context, orgID int, partnerID *int) (*organization.AdminOrgListItem, error) {
+	found := false
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if partnerID != nil {
+			var active bool
+			err := tx.QueryRow(ctx, `
+				SELECT is_active FROM trakrf.partners
+				WHERE id = $1 AND deleted_at IS NULL
+				FOR SHARE`, *partnerID).Scan(&active)
+			if errors.Is(err, pgx.ErrNoRows) || (err == nil && !active) {
+				return ErrPartnerNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("lock partner: %w", err)
+			}
+		}
+		tag, err := tx.Exec(ctx, `
+			UPDATE trakrf.organizations SET partner_id = $2, updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL`, orgID, partnerID)
+		if err != nil {
+			return err
+		}
+		found = tag.RowsAffected() == 1
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrPartnerNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to set org partner: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	orgs, err := s.listAdminOrgs(ctx, "o.id = $1", orgID)
+	if err != nil || len(orgs) == 0 {
+		return nil, err
+	}
+	return &orgs[0], nil
+}
@@ -0,0 +1,126 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestPartners_MoveOrgBetweenPartners(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	ident := "acme-holdings"
+	acme, err := store.CreatePartner(ctx, organization.CreatePartnerRequest{Name: "Acme Holdings", Identifier: &ident})
+	require.NoError(t, err)
+	assert.True(t, acme.IsActive)
+	assert.Equal(t, 0, acme.OrgCount)
+	_, err = store.CreatePartner(ctx, organization.CreatePartnerRequest{Name: "Acme again", Identifier: &ident})
+	assert.ErrorIs(t, err, storage.ErrPartnerIdentifierTaken)
+	other, err := store.CreatePartner(ctx, organization.CreatePartnerRequest{Name: "Other Reseller"})
+	require.NoError(t, err)
+
+	org, err := store.SetOrgPartner(ctx, orgID, &acme.ID)
+	require.NoError(t, err)
+	require.NotNil(t, org.PartnerID)
+	assert.Equal(t, acme.ID, *org.PartnerID)
+
+	orgs, found, err := store.ListPartnerOrgs(ctx, acme.ID)
+	require.NoError(t, err)
+	assert.True(t, found)
+	require.Len(t, orgs, 1)
+	assert.Equal(t, orgID, orgs[0].ID)
+
+	org, err = store.SetOrgPartner(ctx, orgID, &other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, *org.PartnerID)
+	orgs, _, err = store.ListPartnerOrgs(ctx, acme.ID)
+	require.NoError(t, err)
+	assert.Empty(t, orgs)
+
+	_, err = pool.Exec(ctx, `UPDATE trakrf.partners SET is_active = false WHERE id = $1`, acme.ID)
+	require.NoError(t, err)
+	_, err = store.SetOrgPartner(ctx, orgID, &acme.ID)
+	assert.ErrorIs(t, err, storage.ErrPartnerNotFound)
+
+	org, err = store.SetOrgPartner(ctx, orgID, nil)
+	require.NoError(t, err)
+	assert.Nil(t, org.PartnerID)
+
+	_, found, err = store.ListPartnerOrgs(ctx, other.ID+1000000)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestPartners_AdminsAndRollup(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgA := testutil.CreateTestAccount(t, pool)
+	var orgB, finance int
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.organizations (name, identifier) VALUES ('Org B', 'org-b') RETURNING id`,
+	).Scan(&orgB))
+	require.NoError(t, pool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('fin', 'fin@x', 'stub') RETURNING id`,
+	).Scan(&finance))
+	testutil.CreateTestAsset(t, pool, orgA, "ROLL-A1")
+	testutil.CreateTestAsset(t, pool, orgA, "ROLL-A2")
+	testutil.CreateTestAsset(t, pool, orgB, "ROLL-B1")
+
+	p, err := store.CreatePartner(ctx, organization.CreatePartnerRequest{Name: "Enterprise"})
+	require.NoError(t, err)
+	for _, id := range []int{orgA, orgB} {
+		_, err := store.SetOrgPartner(ctx, id, &p.ID)
+		require.NoError(t, err)
+	}
+
+	ok, err := store.IsPartnerAdmin(ctx, p.ID, finance)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	a, err := store.AddPartnerAdmin(ctx, p.ID, finance, finance)
+	require.NoError(t, err)
+	assert.Equal(t, "fin@x", a.Email)
+	_, err = store.AddPartnerAdmin(ctx, p.ID, finance, finance)
+	require.NoError(t, err, "granting twice is a no-op")
+	_, err = store.AddPartnerAdmin(ctx, p.ID, finance+1000000, finance)
+	assert.ErrorIs(t, err, storage.ErrPartnerAdminUser)
+	ok, err = store.IsPartnerAdmin(ctx, p.ID, finance)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	mine, err := store.ListUserPartners(ctx, finance)
+	require.NoError(t, err)
+	require.Len(t, mine, 1)
+	assert.Equal(t, 2, mine[0].OrgCount)
+
+	rollup, err := store.GetPartnerRollup(ctx, p.ID, time.Now().AddDate(0, 0, -30))
+	require.NoError(t, err)
+	require.Len(t, rollup.Orgs, 2)
+	assert.Equal(t, 3, rollup.Totals.Assets)
+	assert.Equal(t, 3, rollup.Totals.ActiveAssets)
+	assert.Equal(t, 0, rollup.Totals.AssetsSeen)
+	assert.Equal(t, 0.0, rollup.Totals.Utilization)
+
+	removed, err := store.RemovePartnerAdmin(ctx, p.ID, finance)
+	require.NoError(t, err)
+	assert.True(t, removed)
+	ok, err = store.IsPartnerAdmin(ctx, p.ID, finance)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
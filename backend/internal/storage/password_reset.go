@@ -17,14 +17,15 @@ type PasswordResetToken struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
-// CreatePasswordResetToken stores a new password reset token
+// CreatePasswordResetToken stores a new password reset token. With field
+// encryption enabled only its keyed digest is stored.
 func (s *Storage) CreatePasswordResetToken(ctx context.Context, userID int, token string, expiresAt time.Time) error {
 	query := `
 		INSERT INTO trakrf.password_reset_tokens (user_id, token, expires_at)
 		VALUES ($1, $2, $3)
 	`
 
-	_, err := s.pool.Exec(ctx, query, userID, token, expiresAt)
+	_, err := s.pool.Exec(ctx, query, userID, s.resetTokenStored(token), expiresAt)
 	if err != nil {
 		return fmt.Errorf("failed to create password reset token: %w", err)
 	}
@@ -37,11 +38,11 @@ func (s *Storage) GetPasswordResetToken(ctx context.Context, token string) (*Pas
 	query := `
 		SELECT id, user_id, token, expires_at, created_at
 		FROM trakrf.password_reset_tokens
-		WHERE token = $1 AND expires_at > NOW()
+		WHERE token = ANY($1) AND expires_at > NOW()
 	`
 
 	var t PasswordResetToken
-	err := s.pool.QueryRow(ctx, query, token).Scan(
+	err := s.pool.QueryRow(ctx, query, s.resetTokenCandidates(token)).Scan(
 		&t.ID, &t.UserID, &t.Token, &t.ExpiresAt, &t.CreatedAt,
 	)
 
@@ -51,15 +52,16 @@ func (s *Storage) GetPasswordResetToken(ctx context.Context, token string) (*Pas
 		}
 		return nil, fmt.Errorf("failed to get password reset token: %w", err)
 	}
+	t.Token = token
 
 	return &t, nil
 }
 
 // DeletePasswordResetToken removes a specific token (used after successful reset)
 func (s *Storage) DeletePasswordResetToken(ctx context.Context, token string) error {
-	query := `DELETE FROM trakrf.password_reset_tokens WHERE token = $1`
+	query := `DELETE FROM trakrf.password_reset_tokens WHERE token = ANY($1)`
 
-	_, err := s.pool.Exec(ctx, query, token)
+	_, err := s.pool.Exec(ctx, query, s.resetTokenCandidates(token))
 	if err != nil {
 		return fmt.Errorf("failed to delete password reset token: %w", err)
 	}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// GetPersonnelSettings returns the org's personnel settings, or nil when the
+// org does not exist.
+func (s *Storage) GetPersonnelSettings(ctx context.Context, orgID int) (*organization.PersonnelSettings, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'personnel' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personnel settings: %w", err)
+	}
+	var ps organization.PersonnelSettings
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &ps); err != nil {
+			return nil, fmt.Errorf("failed to decode personnel settings: %w", err)
+		}
+	}
+	return &ps, nil
+}
+
+// UpdatePersonnelSettings replaces metadata.personnel with ps. Other metadata
+// keys are preserved.
+func (s *Storage) UpdatePersonnelSettings(ctx context.Context, orgID int, ps organization.PersonnelSettings) error {
+	blob, err := json.Marshal(ps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal personnel settings: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{personnel}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update personnel settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// personnelAnonymized reports whether orgID's reports show person-assets
+// under a pseudonym, read on tx alongside the report itself.
+func personnelAnonymized(ctx context.Context, tx pgx.Tx, orgID int) (bool, error) {
+	var anonymize bool
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(metadata->'personnel'->>'anonymize_reports' = 'true', false)
+		FROM trakrf.organizations WHERE id = $1`, orgID).Scan(&anonymize)
+	if err == pgx.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read personnel settings: %w", err)
+	}
+	return anonymize, nil
+}
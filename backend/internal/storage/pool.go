@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PoolConfig tunes the pgx connection pool. Zero values are never applied;
+// DefaultPoolConfig supplies the baseline and PoolConfigFromEnv overlays the
+// PG_POOL_* / PG_STATEMENT_CACHE_MODE environment variables.
+type PoolConfig struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	// StatementCacheMode is the pgx default query exec mode. Poolers in
+	// transaction mode (PgBouncer) cannot hold prepared statements across
+	// transactions, so those deployments need "describe_exec" or "exec".
+	StatementCacheMode pgx.QueryExecMode
+	// SlowQueryThreshold is how long a query may run before it is logged as
+	// slow. Zero turns slow-query logging off.
+	SlowQueryThreshold time.Duration
+}
+
+// DefaultPoolConfig returns the production defaults: 25 max / 5 min conns,
+// 1h lifetime, 30m idle, 1m health checks, cached prepared statements, and
+// queries over 500ms logged as slow.
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxConns:           25,
+		MinConns:           5,
+		MaxConnLifetime:    time.Hour,
+		MaxConnIdleTime:    30 * time.Minute,
+		HealthCheckPeriod:  time.Minute,
+		StatementCacheMode: pgx.QueryExecModeCacheStatement,
+		SlowQueryThreshold: 500 * time.Millisecond,
+	}
+}
+
+// statementCacheModes maps the PG_STATEMENT_CACHE_MODE values to pgx exec modes.
+var statementCacheModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// PoolConfigFromEnv overlays the environment onto DefaultPoolConfig:
+//
+//	PG_POOL_MAX_CONNS           int   (default 25)
+//	PG_POOL_MIN_CONNS           int   (default 5)
+//	PG_POOL_MAX_CONN_LIFETIME   dur   (default 1h)
+//	PG_POOL_MAX_CONN_IDLE_TIME  dur   (default 30m)
+//	PG_POOL_HEALTH_CHECK_PERIOD dur   (default 1m)
+//	PG_STATEMENT_CACHE_MODE     cache_statement|cache_describe|describe_exec|exec|simple_protocol
+//	PG_SLOW_QUERY_THRESHOLD     dur   (default 500ms; 0 disables)
+//
+// Unlike the ingest/geofence knobs, an unparseable value is an error rather
+// than a silent fallback: a mis-sized pool is exactly the failure this config
+// exists to prevent, so the server refuses to boot instead.
+func PoolConfigFromEnv() (PoolConfig, error) {
+	c := DefaultPoolConfig()
+
+	for _, v := range []struct {
+		name string
+		dst  *int32
+	}{
+		{"PG_POOL_MAX_CONNS", &c.MaxConns},
+		{"PG_POOL_MIN_CONNS", &c.MinConns},
+	} {
+		raw := os.Getenv(v.name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || n < 0 {
+			return PoolConfig{}, fmt.Errorf("%s must be a non-negative integer, got %q", v.name, raw)
+		}
+		*v.dst = int32(n)
+	}
+
+	for _, v := range []struct {
+		name string
+		dst  *time.Duration
+	}{
+		{"PG_POOL_MAX_CONN_LIFETIME", &c.MaxConnLifetime},
+		{"PG_POOL_MAX_CONN_IDLE_TIME", &c.MaxConnIdleTime},
+		{"PG_POOL_HEALTH_CHECK_PERIOD", &c.HealthCheckPeriod},
+	} {
+		raw := os.Getenv(v.name)
+		if raw == "" {
+			continue
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return PoolConfig{}, fmt.Errorf("%s must be a positive duration, got %q", v.name, raw)
+		}
+		*v.dst = d
+	}
+
+	if raw := os.Getenv("PG_STATEMENT_CACHE_MODE"); raw != "" {
+		mode, ok := statementCacheModes[raw]
+		if !ok {
+			return PoolConfig{}, fmt.Errorf("PG_STATEMENT_CACHE_MODE %q is not one of cache_statement, cache_describe, describe_exec, exec, simple_protocol", raw)
+		}
+		c.StatementCacheMode = mode
+	}
+
+	if raw := os.Getenv("PG_SLOW_QUERY_THRESHOLD"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d < 0 {
+			return PoolConfig{}, fmt.Errorf("PG_SLOW_QUERY_THRESHOLD must be a non-negative duration, got %q", raw)
+		}
+		c.SlowQueryThreshold = d
+	}
+
+	if c.MaxConns < 1 {
+		return PoolConfig{}, fmt.Errorf("PG_POOL_MAX_CONNS must be at least 1")
+	}
+	if c.MinConns > c.MaxConns {
+		return PoolConfig{}, fmt.Errorf("PG_POOL_MIN_CONNS (%d) exceeds PG_POOL_MAX_CONNS (%d)", c.MinConns, c.MaxConns)
+	}
+
+	return c, nil
+}
+
+// apply copies the tuning onto a parsed pgxpool config.
+func (c PoolConfig) apply(config *pgxpool.Config) {
+	config.MaxConns = c.MaxConns
+	config.MinConns = c.MinConns
+	config.MaxConnLifetime = c.MaxConnLifetime
+	config.MaxConnIdleTime = c.MaxConnIdleTime
+	config.HealthCheckPeriod = c.HealthCheckPeriod
+	config.ConnConfig.DefaultQueryExecMode = c.StatementCacheMode
+	if c.SlowQueryThreshold > 0 {
+		config.ConnConfig.Tracer = &slowQueryTracer{threshold: c.SlowQueryThreshold}
+	}
+}
+
+// PoolStats is a JSON-friendly snapshot of pgxpool.Stat, surfaced on the
+// health endpoints so operators can see saturation without scraping /metrics.
+type PoolStats struct {
+	MaxConns             int32  `json:"max_conns"`
+	TotalConns           int32  `json:"total_conns"`
+	AcquiredConns        int32  `json:"acquired_conns"`
+	IdleConns            int32  `json:"idle_conns"`
+	ConstructingConns    int32  `json:"constructing_conns"`
+	AcquireCount         int64  `json:"acquire_count"`
+	EmptyAcquireCount    int64  `json:"empty_acquire_count"`
+	CanceledAcquireCount int64  `json:"canceled_acquire_count"`
+	AcquireDuration      string `json:"acquire_duration"`
+}
+
+// NewPoolStats snapshots pool. Returns nil for a nil pool (unit tests).
+func NewPoolStats(pool *pgxpool.Pool) *PoolStats {
+	if pool == nil {
+		return nil
+	}
+	st := pool.Stat()
+	return &PoolStats{
+		MaxConns:             st.MaxConns(),
+		TotalConns:           st.TotalConns(),
+		AcquiredConns:        st.AcquiredConns(),
+		IdleConns:            st.IdleConns(),
+		ConstructingConns:    st.ConstructingConns(),
+		AcquireCount:         st.AcquireCount(),
+		EmptyAcquireCount:    st.EmptyAcquireCount(),
+		CanceledAcquireCount: st.CanceledAcquireCount(),
+		AcquireDuration:      st.AcquireDuration().String(),
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolConfigFromEnv_Defaults(t *testing.T) {
+	cfg, err := PoolConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultPoolConfig(), cfg)
+}
+
+func TestPoolConfigFromEnv_Overrides(t *testing.T) {
+	t.Setenv("PG_POOL_MAX_CONNS", "60")
+	t.Setenv("PG_POOL_MIN_CONNS", "10")
+	t.Setenv("PG_POOL_MAX_CONN_LIFETIME", "15m")
+	t.Setenv("PG_POOL_MAX_CONN_IDLE_TIME", "2m")
+	t.Setenv("PG_POOL_HEALTH_CHECK_PERIOD", "30s")
+	t.Setenv("PG_STATEMENT_CACHE_MODE", "describe_exec")
+	t.Setenv("PG_SLOW_QUERY_THRESHOLD", "2s")
+
+	cfg, err := PoolConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, int32(60), cfg.MaxConns)
+	assert.Equal(t, int32(10), cfg.MinConns)
+	assert.Equal(t, 15*time.Minute, cfg.MaxConnLifetime)
+	assert.Equal(t, 2*time.Minute, cfg.MaxConnIdleTime)
+	assert.Equal(t, 30*time.Second, cfg.HealthCheckPeriod)
+	assert.Equal(t, pgx.QueryExecModeDescribeExec, cfg.StatementCacheMode)
+	assert.Equal(t, 2*time.Second, cfg.SlowQueryThreshold)
+}
+
+func TestPoolConfigFromEnv_SlowQueryThresholdZeroDisables(t *testing.T) {
+	t.Setenv("PG_SLOW_QUERY_THRESHOLD", "0")
+
+	cfg, err := PoolConfigFromEnv()
+	require.NoError(t, err)
+	assert.Zero(t, cfg.SlowQueryThreshold)
+
+	config, err := pgxpool.ParseConfig("postgres://u:p@localhost:5432/db")
+	require.NoError(t, err)
+	cfg.apply(config)
+	assert.Nil(t, config.ConnConfig.Tracer)
+}
+
+func TestPoolConfigFromEnv_RejectsInvalid(t *testing.T) {
+	cases := map[string][2]string{
+		"non-numeric max":   {"PG_POOL_MAX_CONNS", "lots"},
+		"zero max":          {"PG_POOL_MAX_CONNS", "0"},
+		"negative min":      {"PG_POOL_MIN_CONNS", "-1"},
+		"min above max":     {"PG_POOL_MIN_CONNS", "26"},
+		"bad duration":      {"PG_POOL_MAX_CONN_LIFETIME", "forever"},
+		"zero duration":     {"PG_POOL_MAX_CONN_IDLE_TIME", "0s"},
+		"unknown exec mode": {"PG_STATEMENT_CACHE_MODE", "prepared"},
+		"negative slow":     {"PG_SLOW_QUERY_THRESHOLD", "-1s"},
+	}
+	for name, kv := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(kv[0], kv[1])
+			_, err := PoolConfigFromEnv()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), kv[0])
+		})
+	}
+}
+
+func TestPoolConfig_Apply(t *testing.T) {
+	config, err := pgxpool.ParseConfig("postgres://u:p@localhost:5432/db")
+	require.NoError(t, err)
+
+	cfg := DefaultPoolConfig()
+	cfg.MaxConns = 40
+	cfg.StatementCacheMode = pgx.QueryExecModeExec
+	cfg.apply(config)
+
+	assert.Equal(t, int32(40), config.MaxConns)
+	assert.Equal(t, int32(5), config.MinConns)
+	assert.Equal(t, time.Hour, config.MaxConnLifetime)
+	assert.Equal(t, pgx.QueryExecModeExec, config.ConnConfig.DefaultQueryExecMode)
+	assert.Equal(t, &slowQueryTracer{threshold: 500 * time.Millisecond}, config.ConnConfig.Tracer)
+}
+
+func TestNewPoolStats_NilPool(t *testing.T) {
+	assert.Nil(t, NewPoolStats(nil))
+}
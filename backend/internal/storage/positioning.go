@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// GetPositioningSettings returns the org's BLE positioning settings (zero
+// value when unset), or nil when the org does not exist.
+func (s *Storage) GetPositioningSettings(ctx context.Context, orgID int) (*organization.PositioningSettings, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'positioning' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get positioning settings: %w", err)
+	}
+	var ps organization.PositioningSettings
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &ps); err != nil {
+			return nil, fmt.Errorf("failed to decode positioning settings: %w", err)
+		}
+	}
+	return &ps, nil
+}
+
+// UpdatePositioningSettings replaces metadata.positioning with ps. Nil tuning
+// fields are omitted so they fall back to the system defaults. Other metadata
+// keys are preserved.
+func (s *Storage) UpdatePositioningSettings(ctx context.Context, orgID int, ps organization.PositioningSettings) error {
+	blob, err := json.Marshal(ps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal positioning settings: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{positioning}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update positioning settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// GatewayZone maps a gateway's scan point to the zone (location) it covers,
+// with the gateway's RSSI calibration from scan_points.metadata.rssi_offset
+// (dB, added to every sample; 0 when unset or not a number).
+type GatewayZone struct {
+	ScanPointID int
+	LocationID  int
+	RSSIOffset  float64
+}
+
+// ListGatewayZones returns every live, currently-effective scan point that is
+// mapped to a location. Scan points without a location cannot place a beacon
+// and are left out.
+func (s *Storage) ListGatewayZones(ctx context.Context, orgID int) ([]GatewayZone, error) {
+	query := `
+		SELECT sp.id, sp.location_id,
+		       CASE WHEN jsonb_typeof(sp.metadata->'rssi_offset') = 'number'
+		            THEN (sp.metadata->>'rssi_offset')::float8 ELSE 0 END
+		FROM trakrf.scan_points sp
+		WHERE sp.org_id = $1 AND sp.location_id IS NOT NULL
+		  AND sp.deleted_at IS NULL AND sp.is_active
+		  AND ` + temporallyEffective("sp")
+
+	var out []GatewayZone
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, query, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var z GatewayZone
+			if err := rows.Scan(&z.ScanPointID, &z.LocationID, &z.RSSIOffset); err != nil {
+				return err
+			}
+			out = append(out, z)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gateway zones: %w", err)
+	}
+	return out, nil
+}
+
+// LastScanLocation returns the location of the asset's most recent scan, or
+// nil when it has none (or the latest scan had no location).
+func (s *Storage) LastScanLocation(ctx context.Context, orgID, assetID int) (*int, error) {
+	var locationID *int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, `
+			SELECT location_id FROM trakrf.asset_scans
+			WHERE org_id = $1 AND asset_id = $2
+			ORDER BY timestamp DESC
+			LIMIT 1`, orgID, assetID).Scan(&locationID)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last scan location: %w", err)
+	}
+	return locationID, nil
+}
+
+// PositionFix is one zone estimate written by the positioning engine: a move
+// into LocationID (FromLocationID is where the asset was, nil when unknown),
+// or a heartbeat for an unchanged zone. ScanPointID is the loudest gateway in
+// the zone and RSSI its smoothed strength.
+type PositionFix struct {
+	AssetID        int
+	LocationID     int
+	FromLocationID *int
+	ScanPointID    int
+	RSSI           float64
+	Move           bool
+	TagScanID      int64
+	At             time.Time
+}
+
+// RecordPositionFix writes a fix as an asset_scans row, so every consumer of
+// scans (current locations, history, exports) sees the estimate exactly as it
+// would a direct read. Returns false when a scan for the asset already exists
+// at that instant.
+func (s *Storage) RecordPositionFix(ctx context.Context, orgID int, fix PositionFix) (bool, error) {
+	inserted := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx,
+			`INSERT INTO trakrf.asset_scans
+			   (timestamp, org_id, asset_id, location_id, scan_point_id, tag_scan_id)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 ON CONFLICT (timestamp, org_id, asset_id) DO NOTHING`,
+			fix.At, orgID, fix.AssetID, fix.LocationID, fix.ScanPointID, fix.TagScanID,
+		)
+		if err != nil {
+			return err
+		}
+		if ct.RowsAffected() == 0 {
+			return nil
+		}
+		inserted = true
+		if s.outbox {
+			return s.enqueueOutboxData(ctx, tx, events.ScanRecorded, orgID, &fix.AssetID, map[string]any{
+				"timestamp":        fix.At,
+				"asset_id":         fix.AssetID,
+				"location_id":      fix.LocationID,
+				"scan_point_id":    fix.ScanPointID,
+				"source":           "positioning",
+				"from_location_id": fix.FromLocationID,
+				"location_changed": fix.Move,
+				"rssi":             fix.RSSI,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record position fix: %w", err)
+	}
+	return inserted, nil
+}
@@ -0,0 +1,99 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+// explainPlan returns the JSON plan of sql run with orgID's RLS context.
+// Sequential scans are disabled so a handful of fixture rows still plan like
+// a full table: the assertion is which index the planner reaches for, not
+// whether an index beats a scan of three rows.
+func explainPlan(t *testing.T, db *testutil.TestDB, orgID int, sql string, args ...any) string {
+	t.Helper()
+	var plan string
+	err := db.Store.WithOrgTx(context.Background(), orgID, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(context.Background(), `SET LOCAL enable_seqscan = off`); err != nil {
+			return err
+		}
+		return tx.QueryRow(context.Background(), `EXPLAIN (FORMAT JSON) `+sql, args...).Scan(&plan)
+	})
+	require.NoError(t, err)
+	return plan
+}
+
+// TestQueryPlans_HotPathsUseTheirIndexes pins each hot read path to the
+// index built for it (see migration 000075), so a dropped index or a query
+// rewritten past its index fails here rather than in production latency.
+func TestQueryPlans_HotPathsUseTheirIndexes(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := context.Background()
+
+	assetID := preCreateAssetWithTag(t, db, orgID, "Plan Asset", "E2801160600002084A5B6C01")
+	parentID := seedLocation(t, db.AdminPool, orgID, "PLAN-SITE", "Plan Site")
+	_, err := db.AdminPool.Exec(ctx, `
+		INSERT INTO trakrf.locations (org_id, external_key, name, parent_location_id, valid_from, is_active)
+		VALUES ($1, 'PLAN-BAY', 'Plan Bay', $2, now(), TRUE)`, orgID, parentID)
+	require.NoError(t, err)
+	// A scan row makes the hypertable create a chunk for the plan to read.
+	_, err = db.AdminPool.Exec(ctx, `
+		INSERT INTO trakrf.asset_scans (timestamp, org_id, asset_id)
+		VALUES ($1, $2, $3)`, time.Now(), orgID, assetID)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name  string
+		index string
+		sql   string
+		args  []any
+	}{
+		{
+			name:  "live assets by creation time",
+			index: "idx_assets_org_live_created",
+			sql: `SELECT id FROM trakrf.assets
+				WHERE org_id = $1 AND deleted_at IS NULL AND created_at >= $2
+				ORDER BY created_at`,
+			args: []any{orgID, time.Now().Add(-24 * time.Hour)},
+		},
+		{
+			name:  "tags by value",
+			index: "idx_tags_value_type_org",
+			sql: `SELECT id, asset_id FROM trakrf.tags
+				WHERE value = ANY($2) AND org_id = $1 AND deleted_at IS NULL`,
+			args: []any{orgID, []string{"E2801160600002084A5B6C01"}},
+		},
+		{
+			name:  "asset scan history",
+			index: "idx_asset_scans_asset_time",
+			sql: `SELECT timestamp, location_id FROM trakrf.asset_scans
+				WHERE asset_id = $1
+				ORDER BY timestamp DESC
+				LIMIT 50`,
+			args: []any{assetID},
+		},
+		{
+			name:  "location children",
+			index: "idx_locations_parent",
+			sql: `SELECT id FROM trakrf.locations
+				WHERE parent_location_id = $1 AND deleted_at IS NULL`,
+			args: []any{parentID},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			plan := explainPlan(t, db, orgID, c.sql, c.args...)
+			// Hypertable chunk indexes carry the parent index name as a suffix.
+			require.True(t, strings.Contains(plan, c.index), "plan does not use %s:\n%s", c.index, plan)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/logger"
+)
+
+// slowQuerySQLMax caps how much of a slow statement's SQL is logged.
+const slowQuerySQLMax = 1000
+
+type queryTraceKey struct{}
+
+// queryTrace is what TraceQueryStart hands TraceQueryEnd through the context.
+type queryTrace struct {
+	start time.Time
+	sql   string
+}
+
+// batchTrace is what TraceBatchStart hands TraceBatchEnd through the
+// context: the batch is logged by its first statement and size.
+type batchTrace struct {
+	start      time.Time
+	sql        string
+	statements int
+}
+
+type batchTraceKey struct{}
+
+// slowQueryTracer is a pgx.QueryTracer and pgx.BatchTracer that logs, at
+// warn, every query or batch that runs for threshold or longer. It logs
+// through logger.Ctx, so a slow query made for a request carries that
+// request's id, route, user and org. The SQL is logged without its
+// arguments, which may hold personal data.
+type slowQueryTracer struct {
+	threshold time.Duration
+}
+
+func (t *slowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, queryTrace{start: time.Now(), sql: data.SQL})
+}
+
+func (t *slowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	qt, ok := ctx.Value(queryTraceKey{}).(queryTrace)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(qt.start)
+	if elapsed < t.threshold {
+		return
+	}
+	ev := logger.Ctx(ctx).Warn().
+		Str("sql", compactSQL(qt.sql)).
+		Dur("duration_ms", elapsed).
+		Int64("duration_ms_int", elapsed.Milliseconds())
+	if data.Err != nil {
+		ev = ev.Err(data.Err)
+	}
+	ev.Msg("Slow query")
+}
+
+func (t *slowQueryTracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	bt := batchTrace{start: time.Now()}
+	if data.Batch != nil {
+		bt.statements = data.Batch.Len()
+		if bt.statements > 0 {
+			bt.sql = data.Batch.QueuedQueries[0].SQL
+		}
+	}
+	return context.WithValue(ctx, batchTraceKey{}, bt)
+}
+
+// TraceBatchQuery is a no-op: a batch is timed as a whole, since its
+// statements share one round-trip.
+func (t *slowQueryTracer) TraceBatchQuery(context.Context, *pgx.Conn, pgx.TraceBatchQueryData) {}
+
+func (t *slowQueryTracer) TraceBatchEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchEndData) {
+	bt, ok := ctx.Value(batchTraceKey{}).(batchTrace)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(bt.start)
+	if elapsed < t.threshold {
+		return
+	}
+	ev := logger.Ctx(ctx).Warn().
+		Str("sql", compactSQL(bt.sql)).
+		Int("statements", bt.statements).
+		Dur("duration_ms", elapsed).
+		Int64("duration_ms_int", elapsed.Milliseconds())
+	if data.Err != nil {
+		ev = ev.Err(data.Err)
+	}
+	ev.Msg("Slow batch")
+}
+
+// compactSQL collapses runs of whitespace in sql to single spaces, so a
+// multi-line statement logs as one readable line, and truncates it to
+// slowQuerySQLMax bytes.
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > slowQuerySQLMax {
+		sql = sql[:slowQuerySQLMax] + "…"
+	}
+	return sql
+}
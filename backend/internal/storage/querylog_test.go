@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/trakrf/platform/backend/internal/logger"
+)
+
+func TestSlowQueryTracer(t *testing.T) {
+	var buf bytes.Buffer
+	logger.SetForTest(zerolog.New(&buf))
+	tr := &slowQueryTracer{threshold: time.Hour}
+
+	ctx := tr.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "SELECT 1"})
+	tr.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	assert.Empty(t, buf.String(), "a query under the threshold is not logged")
+
+	tr.threshold = 0
+	ctx = tr.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{
+		SQL:  "SELECT *\n\t\tFROM trakrf.assets\n\t\tWHERE org_id = $1",
+		Args: []any{"secret"},
+	})
+	tr.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+	out := buf.String()
+	assert.Contains(t, out, `"level":"warn"`)
+	assert.Contains(t, out, `"message":"Slow query"`)
+	assert.Contains(t, out, `"sql":"SELECT * FROM trakrf.assets WHERE org_id = $1"`)
+	assert.Contains(t, out, `"error":"boom"`)
+	assert.NotContains(t, out, "secret", "arguments are never logged")
+}
+
+func TestSlowQueryTracer_Batch(t *testing.T) {
+	var buf bytes.Buffer
+	logger.SetForTest(zerolog.New(&buf))
+	tr := &slowQueryTracer{threshold: time.Hour}
+
+	batch := &pgx.Batch{}
+	batch.Queue("INSERT INTO trakrf.asset_scans\n\t(timestamp) VALUES ($1)", "secret")
+	batch.Queue("INSERT INTO trakrf.asset_scans\n\t(timestamp) VALUES ($1)", "secret")
+
+	ctx := tr.TraceBatchStart(context.Background(), nil, pgx.TraceBatchStartData{Batch: batch})
+	tr.TraceBatchEnd(ctx, nil, pgx.TraceBatchEndData{})
+	assert.Empty(t, buf.String(), "a batch under the threshold is not logged")
+
+	tr.threshold = 0
+	ctx = tr.TraceBatchStart(context.Background(), nil, pgx.TraceBatchStartData{Batch: batch})
+	tr.TraceBatchEnd(ctx, nil, pgx.TraceBatchEndData{})
+	out := buf.String()
+	assert.Contains(t, out, `"message":"Slow batch"`)
+	assert.Contains(t, out, `"sql":"INSERT INTO trakrf.asset_scans (timestamp) VALUES ($1)"`)
+	assert.Contains(t, out, `"statements":2`)
+	assert.NotContains(t, out, "secret", "arguments are never logged")
+}
+
+func TestCompactSQL(t *testing.T) {
+	assert.Equal(t, "SELECT 1 FROM x", compactSQL("  SELECT 1\n\tFROM   x \n"))
+
+	long := compactSQL(strings.Repeat("a ", slowQuerySQLMax))
+	assert.Equal(t, slowQuerySQLMax+len("…"), len(long))
+	assert.True(t, strings.HasSuffix(long, "…"))
+}
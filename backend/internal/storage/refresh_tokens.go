@@ -78,12 +78,6 @@ func (s *Storage) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (
 // RotateRefreshToken atomically marks the old token used and inserts the new one,
 // linking old.replaced_by → new.id. Returns the new row's ID.
 func (s *Storage) RotateRefreshToken(ctx context.Context, oldID int64, userID int, orgID *int, newHash string, expiresAt time.Time, userAgent, ipStr string) (int64, error) {
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("begin rotate tx: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
 	var ua, ip any
 	if userAgent != "" {
 		ua = userAgent
@@ -93,26 +87,28 @@ func (s *Storage) RotateRefreshToken(ctx context.Context, oldID int64, userID in
 	}
 
 	var newID int64
-	err = tx.QueryRow(ctx, `
-		INSERT INTO trakrf.refresh_tokens (user_id, org_id, token_hash, expires_at, user_agent, ip)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id
-	`, userID, orgID, newHash, expiresAt, ua, ip).Scan(&newID)
-	if err != nil {
-		return 0, fmt.Errorf("insert new refresh row: %w", err)
-	}
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.refresh_tokens (user_id, org_id, token_hash, expires_at, user_agent, ip)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, userID, orgID, newHash, expiresAt, ua, ip).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("insert new refresh row: %w", err)
+		}
 
-	_, err = tx.Exec(ctx, `
-		UPDATE trakrf.refresh_tokens
-		SET used_at = NOW(), replaced_by = $2
-		WHERE id = $1
-	`, oldID, newID)
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.refresh_tokens
+			SET used_at = NOW(), replaced_by = $2
+			WHERE id = $1
+		`, oldID, newID)
+		if err != nil {
+			return fmt.Errorf("mark old refresh row used: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("mark old refresh row used: %w", err)
-	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return 0, fmt.Errorf("commit rotate tx: %w", err)
+		return 0, err
 	}
 	return newID, nil
 }
@@ -129,6 +125,19 @@ func (s *Storage) RevokeRefreshToken(ctx context.Context, id int64) error {
 	return nil
 }
 
+// RevokeUserRefreshTokens revokes every live session refresh token of a user,
+// signing them out everywhere (after a password change).
+func (s *Storage) RevokeUserRefreshTokens(ctx context.Context, userID int) error {
+	_, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.refresh_tokens SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("revoke user refresh tokens: %w", err)
+	}
+	return nil
+}
+
 // RevokeRefreshTokenChain walks the replaced_by lineage forward from startID and
 // revokes every reachable row. Used on replay-detection: a presented used-token
 // signals the chain is compromised.
@@ -179,12 +188,6 @@ func (s *Storage) CreateAPIRefreshToken(ctx context.Context, apiKeyID int64, org
 // RotateAPIRefreshToken atomically marks the old api token used and inserts a
 // new api row, linking old.replaced_by → new.id. Returns the new row's ID.
 func (s *Storage) RotateAPIRefreshToken(ctx context.Context, oldID, apiKeyID int64, orgID *int, newHash string, expiresAt time.Time, userAgent, ipStr string) (int64, error) {
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("begin api rotate tx: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
 	var ua, ip any
 	if userAgent != "" {
 		ua = userAgent
@@ -194,26 +197,28 @@ func (s *Storage) RotateAPIRefreshToken(ctx context.Context, oldID, apiKeyID int
 	}
 
 	var newID int64
-	err = tx.QueryRow(ctx, `
-		INSERT INTO trakrf.refresh_tokens (token_type, api_key_id, org_id, token_hash, expires_at, user_agent, ip)
-		VALUES ('api', $1, $2, $3, $4, $5, $6)
-		RETURNING id
-	`, apiKeyID, orgID, newHash, expiresAt, ua, ip).Scan(&newID)
-	if err != nil {
-		return 0, fmt.Errorf("insert new api refresh row: %w", err)
-	}
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.refresh_tokens (token_type, api_key_id, org_id, token_hash, expires_at, user_agent, ip)
+			VALUES ('api', $1, $2, $3, $4, $5, $6)
+			RETURNING id
+		`, apiKeyID, orgID, newHash, expiresAt, ua, ip).Scan(&newID)
+		if err != nil {
+			return fmt.Errorf("insert new api refresh row: %w", err)
+		}
 
-	_, err = tx.Exec(ctx, `
-		UPDATE trakrf.refresh_tokens
-		SET used_at = NOW(), replaced_by = $2
-		WHERE id = $1
-	`, oldID, newID)
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.refresh_tokens
+			SET used_at = NOW(), replaced_by = $2
+			WHERE id = $1
+		`, oldID, newID)
+		if err != nil {
+			return fmt.Errorf("mark old api refresh row used: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("mark old api refresh row used: %w", err)
-	}
-
-	if err := tx.Commit(ctx); err != nil {
-		return 0, fmt.Errorf("commit api rotate tx: %w", err)
+		return 0, err
 	}
 	return newID, nil
 }
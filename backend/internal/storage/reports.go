@@ -32,6 +32,13 @@ func currentLocationsArgs(filter report.CurrentLocationFilter) (locIDsArg, locKe
 	return
 }
 
+// maskedPerson is the SQL predicate for an assets row a shown as a
+// pseudonym: a person-asset while the org anonymizes personnel, the latter
+// passed as the bool parameter param.
+func maskedPerson(param string) string {
+	return "(" + param + "::bool AND COALESCE(a.metadata->>'person' = 'true', false))"
+}
+
 // ListCurrentLocations returns paginated current asset locations.
 //
 // Latest-scan-per-asset is resolved from the asset_scan_latest continuous
@@ -40,6 +47,10 @@ func currentLocationsArgs(filter report.CurrentLocationFilter) (locIDsArg, locKe
 // row per asset with an outer last()/max(). This replaces the DISTINCT ON over
 // the asset_scans hypertable that TRA-1021 had to defuse with SkipScan-off.
 // org_id is filtered explicitly because RLS does not extend to the CAGG.
+//
+// When the org anonymizes personnel, person-assets come back under
+// report.MaskPerson's pseudonym and the q and asset external_key filters
+// do not match them.
 func (s *Storage) ListCurrentLocations(ctx context.Context, orgID int, filter report.CurrentLocationFilter) ([]report.CurrentLocationItem, error) {
 	orderBy := buildCurrentLocationsOrderBy(filter.Sorts)
 	query := buildCurrentLocationsQuery(orderBy)
@@ -48,7 +59,11 @@ func (s *Storage) ListCurrentLocations(ctx context.Context, orgID int, filter re
 
 	items := []report.CurrentLocationItem{}
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		rows, err := tx.Query(ctx, query, orgID, locIDsArg, locKeysArg, qArg, filter.Limit, filter.Offset, filter.IncludeDeleted, assetIDsArg, assetKeysArg)
+		anonymize, err := personnelAnonymized(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, query, orgID, locIDsArg, locKeysArg, qArg, filter.Limit, filter.Offset, filter.IncludeDeleted, assetIDsArg, assetKeysArg, anonymize, scopeRoots(filter.SubtreeScope))
 		if err != nil {
 			return fmt.Errorf("failed to list current locations: %w", err)
 		}
@@ -56,6 +71,7 @@ func (s *Storage) ListCurrentLocations(ctx context.Context, orgID int, filter re
 
 		for rows.Next() {
 			var item report.CurrentLocationItem
+			var masked bool
 			if err := rows.Scan(
 				&item.AssetID,
 				&item.AssetName,
@@ -65,9 +81,13 @@ func (s *Storage) ListCurrentLocations(ctx context.Context, orgID int, filter re
 				&item.LocationExternalKey,
 				&item.LastSeen,
 				&item.AssetDeletedAt,
+				&masked,
 			); err != nil {
 				return fmt.Errorf("failed to scan current location: %w", err)
 			}
+			if masked {
+				item.AssetExternalKey, item.AssetName = report.MaskPerson(item.AssetID)
+			}
 			items = append(items, item)
 		}
 
@@ -101,21 +121,26 @@ func (s *Storage) CountCurrentLocations(ctx context.Context, orgID int, filter r
 		LEFT JOIN trakrf.locations l ON l.id = ls.location_id AND l.org_id = $1 AND l.deleted_at IS NULL AND ` + temporallyEffective("l") + `
 		WHERE ($2::bigint[]  IS NULL OR l.id           = ANY($2::bigint[]))
 		  AND ($3::text[] IS NULL OR l.external_key = ANY($3::text[]))
-		  AND ($4::text IS NULL OR a.name ILIKE $4 OR a.external_key ILIKE $4
+		  AND ($4::text IS NULL OR (NOT ` + maskedPerson("$8") + ` AND (a.name ILIKE $4 OR a.external_key ILIKE $4
 			   OR EXISTS (
 				   SELECT 1 FROM trakrf.tags ai
 				   WHERE ai.asset_id = a.id AND ai.is_active = true AND ai.deleted_at IS NULL AND ` + temporallyEffective("ai") + ` AND ai.value ILIKE $4
-			   ))
+			   ))))
 		  AND (a.deleted_at IS NULL OR $5::bool)
 		  AND ($6::bigint[]  IS NULL OR a.id           = ANY($6::bigint[]))
-		  AND ($7::text[] IS NULL OR a.external_key = ANY($7::text[]))
+		  AND ($7::text[] IS NULL OR (NOT ` + maskedPerson("$8") + ` AND a.external_key = ANY($7::text[])))
+		  AND ($9::bigint[] IS NULL OR ` + locationSubtreeClause("l.id", 9) + `)
 	`
 
 	locIDsArg, locKeysArg, qArg, assetIDsArg, assetKeysArg := currentLocationsArgs(filter)
 
 	var count int
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query, orgID, locIDsArg, locKeysArg, qArg, filter.IncludeDeleted, assetIDsArg, assetKeysArg).Scan(&count)
+		anonymize, err := personnelAnonymized(ctx, tx, orgID)
+		if err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, query, orgID, locIDsArg, locKeysArg, qArg, filter.IncludeDeleted, assetIDsArg, assetKeysArg, anonymize, scopeRoots(filter.SubtreeScope)).Scan(&count)
 	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to count current locations: %w", err)
@@ -145,7 +170,9 @@ func buildCurrentLocationsOrderBy(sorts []report.CurrentLocationSort) string {
 		case "asset_last_seen":
 			col = "ls.last_seen"
 		case "asset_external_key":
-			col = "a.external_key"
+			// A masked person sorts by its pseudonym (report.MaskPerson),
+			// not the key it hides.
+			col = "CASE WHEN " + maskedPerson("$10") + " THEN 'person-' || a.id ELSE a.external_key END"
 		case "location_external_key":
 			col = "l.external_key"
 		default:
@@ -187,20 +214,22 @@ func buildCurrentLocationsQuery(orderBy string) string {
 			l.name          AS location_name,
 			l.external_key  AS location_external_key,
 			ls.last_seen,
-			a.deleted_at    AS asset_deleted_at
+			a.deleted_at    AS asset_deleted_at,
+			` + maskedPerson("$10") + ` AS masked
 		FROM latest_scans ls
 		JOIN trakrf.assets a ON a.id = ls.asset_id AND a.org_id = $1 AND ` + temporallyEffective("a") + `
 		LEFT JOIN trakrf.locations l ON l.id = ls.location_id AND l.org_id = $1 AND l.deleted_at IS NULL AND ` + temporallyEffective("l") + `
 		WHERE ($2::bigint[]  IS NULL OR l.id           = ANY($2::bigint[]))
 		  AND ($3::text[] IS NULL OR l.external_key = ANY($3::text[]))
-		  AND ($4::text IS NULL OR a.name ILIKE $4 OR a.external_key ILIKE $4
+		  AND ($4::text IS NULL OR (NOT ` + maskedPerson("$10") + ` AND (a.name ILIKE $4 OR a.external_key ILIKE $4
 			   OR EXISTS (
 				   SELECT 1 FROM trakrf.tags ai
 				   WHERE ai.asset_id = a.id AND ai.is_active = true AND ai.deleted_at IS NULL AND ` + temporallyEffective("ai") + ` AND ai.value ILIKE $4
-			   ))
+			   ))))
 		  AND (a.deleted_at IS NULL OR $7::bool)
 		  AND ($8::bigint[]  IS NULL OR a.id           = ANY($8::bigint[]))
-		  AND ($9::text[] IS NULL OR a.external_key = ANY($9::text[]))
+		  AND ($9::text[] IS NULL OR (NOT ` + maskedPerson("$10") + ` AND a.external_key = ANY($9::text[])))
+		  AND ($11::bigint[] IS NULL OR ` + locationSubtreeClause("l.id", 11) + `)
 		ORDER BY ` + orderBy + `
 		LIMIT $5 OFFSET $6
 	`
@@ -238,7 +267,9 @@ func buildAssetHistoryOrderBy(sorts []report.AssetHistorySort) string {
 	return strings.Join(out, ", ")
 }
 
-// ListAssetHistory returns paginated location history for a single asset
+// ListAssetHistory returns paginated location history for a single asset.
+// A restricted filter.SubtreeScope drops scans at locations outside it;
+// durations still run to the asset's next scan wherever that was.
 func (s *Storage) ListAssetHistory(ctx context.Context, assetID, orgID int, filter report.AssetHistoryFilter) ([]report.AssetHistoryItem, error) {
 	orderBy := buildAssetHistoryOrderBy(filter.Sorts)
 	query := `
@@ -268,6 +299,7 @@ func (s *Storage) ListAssetHistory(ctx context.Context, assetID, orgID int, filt
 			-- scans cleanly.
 			EXTRACT(EPOCH FROM (next_timestamp - timestamp))::BIGINT AS duration_seconds
 		FROM scans
+		WHERE $7::bigint[] IS NULL OR ` + locationSubtreeClauseFor("location_id", 2, 7) + `
 		ORDER BY ` + orderBy + `
 		LIMIT $5 OFFSET $6
 	`
@@ -280,7 +312,7 @@ func (s *Storage) ListAssetHistory(ctx context.Context, assetID, orgID int, filt
 	// 500 on every asset that has any scan history. (TRA-865.)
 	items := []report.AssetHistoryItem{}
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		rows, err := tx.Query(ctx, query, assetID, orgID, filter.From, filter.To, filter.Limit, filter.Offset)
+		rows, err := tx.Query(ctx, query, assetID, orgID, filter.From, filter.To, filter.Limit, filter.Offset, scopeRoots(filter.SubtreeScope))
 		if err != nil {
 			return fmt.Errorf("failed to list asset history: %w", err)
 		}
@@ -321,6 +353,7 @@ func (s *Storage) CountAssetHistory(ctx context.Context, assetID, orgID int, fil
 		  AND s.org_id = $2
 		  AND ($3::timestamptz IS NULL OR s.timestamp >= $3)
 		  AND ($4::timestamptz IS NULL OR s.timestamp <= $4)
+		  AND ($5::bigint[] IS NULL OR ` + locationSubtreeClauseFor("s.location_id", 2, 5) + `)
 	`
 
 	// Wrapped in WithOrgTx for parity with ListAssetHistory and the other
@@ -330,7 +363,7 @@ func (s *Storage) CountAssetHistory(ctx context.Context, assetID, orgID int, fil
 	// TRA-865 produced on the locations join.
 	var count int
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query, assetID, orgID, filter.From, filter.To).Scan(&count)
+		return tx.QueryRow(ctx, query, assetID, orgID, filter.From, filter.To, scopeRoots(filter.SubtreeScope)).Scan(&count)
 	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to count asset history: %w", err)
@@ -338,3 +371,183 @@ func (s *Storage) CountAssetHistory(ctx context.Context, assetID, orgID int, fil
 
 	return count, nil
 }
+
+// expiringRecordsCTE unions the live assets, locations and tags of org $1
+// whose valid_to falls after now and no later than $2.
+const expiringRecordsCTE = `
+	WITH expiring AS (
+		SELECT 'asset' AS entity_type, id, external_key, name, NULL::text AS tag_type, NULL::text AS value, valid_to
+		FROM trakrf.assets
+		WHERE org_id = $1 AND deleted_at IS NULL AND valid_to > NOW() AND valid_to <= $2
+		UNION ALL
+		SELECT 'location', id, external_key, name, NULL, NULL, valid_to
+		FROM trakrf.locations
+		WHERE org_id = $1 AND deleted_at IS NULL AND valid_to > NOW() AND valid_to <= $2
+		UNION ALL
+		SELECT 'tag', id, NULL, NULL, type, value, valid_to
+		FROM trakrf.tags
+		WHERE org_id = $1 AND deleted_at IS NULL AND valid_to > NOW() AND valid_to <= $2
+	)`
+
+// ListExpiringRecords returns the live assets, locations and tags whose
+// validity window closes before filter.Until, soonest first, with the total
+// count before pagination.
+func (s *Storage) ListExpiringRecords(ctx context.Context, orgID int, filter report.ExpiringFilter) ([]report.ExpiringRecord, int, error) {
+	items := []report.ExpiringRecord{}
+	total := 0
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, expiringRecordsCTE+` SELECT COUNT(*) FROM expiring`,
+			orgID, filter.Until).Scan(&total); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, expiringRecordsCTE+`
+			SELECT entity_type, id, external_key, name, tag_type, value, valid_to
+			FROM expiring
+			ORDER BY valid_to, entity_type, id
+			LIMIT $3 OFFSET $4`, orgID, filter.Until, filter.Limit, filter.Offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var item report.ExpiringRecord
+			if err := rows.Scan(&item.EntityType, &item.EntityID, &item.ExternalKey, &item.Name,
+				&item.TagType, &item.Value, &item.ValidTo); err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list expiring records: %w", err)
+	}
+	return items, total, nil
+}
+
+// inventorySnapshotCTE reconstructs each asset's location at $2 from raw
+// asset_scans: the newest scan at or before $2 per asset. The asset_scan_latest
+// CAGG only knows the latest sighting, so it cannot answer for a past
+// instant. Assets and locations are judged as of $2, so a record deleted or
+// expired since still appears where it was.
+var inventorySnapshotCTE = `
+	WITH snapshot AS (
+		SELECT DISTINCT ON (s.asset_id)
+			s.asset_id, s.location_id, s.timestamp
+		FROM trakrf.asset_scans s
+		WHERE s.org_id = $1 AND s.timestamp <= $2
+		ORDER BY s.asset_id, s.timestamp DESC
+	),
+	inventory AS (
+		SELECT
+			a.id           AS asset_id,
+			a.external_key AS asset_external_key,
+			a.name         AS asset_name,
+			l.id           AS location_id,
+			l.external_key AS location_external_key,
+			l.name         AS location_name,
+			sn.timestamp   AS last_seen
+		FROM snapshot sn
+		JOIN trakrf.assets a ON a.id = sn.asset_id AND a.org_id = $1
+			AND (a.deleted_at IS NULL OR a.deleted_at > $2) AND ` + temporallyEffectiveAt("a", "$2::timestamptz") + `
+		LEFT JOIN trakrf.locations l ON l.id = sn.location_id AND l.org_id = $1
+			AND (l.deleted_at IS NULL OR l.deleted_at > $2) AND ` + temporallyEffectiveAt("l", "$2::timestamptz") + `
+		WHERE ($3::bigint IS NULL OR l.id = $3)
+	)`
+
+// ListInventorySnapshot returns where filter.At found each asset, by
+// location then asset external key, with the total count before pagination.
+// It reaches back only as far as the org's retained scan history.
+func (s *Storage) ListInventorySnapshot(ctx context.Context, orgID int, filter report.InventorySnapshotFilter) ([]report.InventorySnapshotItem, int, error) {
+	items := []report.InventorySnapshotItem{}
+	total := 0
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, inventorySnapshotCTE+` SELECT COUNT(*) FROM inventory`,
+			orgID, filter.At, filter.LocationID).Scan(&total); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, inventorySnapshotCTE+`
+			SELECT asset_id, asset_external_key, asset_name,
+			       location_id, location_external_key, location_name, last_seen
+			FROM inventory
+			ORDER BY location_external_key NULLS LAST, asset_external_key, asset_id
+			LIMIT $4 OFFSET $5`, orgID, filter.At, filter.LocationID, filter.Limit, filter.Offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var item report.InventorySnapshotItem
+			if err := rows.Scan(&item.AssetID, &item.AssetExternalKey, &item.AssetName,
+				&item.LocationID, &item.LocationExternalKey, &item.LocationName, &item.LastSeen); err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list inventory snapshot: %w", err)
+	}
+	return items, total, nil
+}
+
+// conditionBelowCTE ranks each live asset's condition reports newest first
+// and keeps the latest below $2 alongside the rating before it. $3 is
+// dropped_only and $4 an optional since.
+const conditionBelowCTE = `
+	WITH ranked AS (
+		SELECT r.*, ROW_NUMBER() OVER w AS rn, LEAD(r.rating) OVER w AS previous_rating
+		FROM trakrf.asset_condition_reports r
+		WHERE r.org_id = $1
+		WINDOW w AS (PARTITION BY r.asset_id ORDER BY r.created_at DESC, r.id DESC)
+	),
+	below AS (
+		SELECT r.id, r.asset_id, a.external_key, a.name, r.rating, r.previous_rating,
+		       r.previous_rating IS NOT NULL AND r.previous_rating >= $2 AS dropped,
+		       r.notes, r.reported_by, r.created_at
+		FROM ranked r
+		JOIN trakrf.assets a ON a.id = r.asset_id AND a.org_id = $1 AND a.deleted_at IS NULL
+		WHERE r.rn = 1 AND r.rating < $2
+		  AND (NOT $3::boolean OR r.previous_rating >= $2)
+		  AND ($4::timestamptz IS NULL OR r.created_at >= $4)
+	)`
+
+// ListConditionBelow returns the assets whose latest condition report rates
+// them below filter.Below, lowest rating first then most recent, and the
+// total count.
+func (s *Storage) ListConditionBelow(ctx context.Context, orgID int, filter report.ConditionBelowFilter) ([]report.ConditionBelowItem, int, error) {
+	items := []report.ConditionBelowItem{}
+	total := 0
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, conditionBelowCTE+` SELECT COUNT(*) FROM below`,
+			orgID, filter.Below, filter.DroppedOnly, filter.Since).Scan(&total); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, conditionBelowCTE+`
+			SELECT b.asset_id, b.external_key, b.name, b.id, b.rating, b.previous_rating, b.dropped,
+			       b.notes, (SELECT COUNT(*) FROM trakrf.asset_condition_photos p WHERE p.report_id = b.id),
+			       b.reported_by, b.created_at
+			FROM below b
+			ORDER BY b.rating, b.created_at DESC, b.asset_id
+			LIMIT $5 OFFSET $6`, orgID, filter.Below, filter.DroppedOnly, filter.Since, filter.Limit, filter.Offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var item report.ConditionBelowItem
+			if err := rows.Scan(&item.AssetID, &item.AssetExternalKey, &item.AssetName, &item.ReportID,
+				&item.Rating, &item.PreviousRating, &item.Dropped, &item.Notes, &item.PhotoCount,
+				&item.ReportedBy, &item.ReportedAt); err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list condition report: %w", err)
+	}
+	return items, total, nil
+}
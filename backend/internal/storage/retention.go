@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+)
+
+// ListOrgRetention returns the retention inputs of every live org. Runs with
+// no org context: the plan lookup goes through trakrf.list_org_retention.
+func (s *Storage) ListOrgRetention(ctx context.Context) ([]organization.OrgRetention, error) {
+	rows, err := s.pool.Query(ctx,
+		`SELECT org_id, plan_audit_days, plan_scan_days, settings FROM trakrf.list_org_retention()`)
+	if err != nil {
+		return nil, fmt.Errorf("list org retention: %w", err)
+	}
+	defer rows.Close()
+
+	var out []organization.OrgRetention
+	for rows.Next() {
+		r, err := scanOrgRetention(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+// GetOrgRetention returns one org's retention inputs, or nil when the org
+// does not exist.
+func (s *Storage) GetOrgRetention(ctx context.Context, orgID int) (*organization.OrgRetention, error) {
+	r, err := scanOrgRetention(s.pool.QueryRow(ctx,
+		`SELECT org_id, plan_audit_days, plan_scan_days, settings FROM trakrf.list_org_retention($1)`, orgID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// scanOrgRetention applies the system defaults to unset plan windows.
+func scanOrgRetention(row pgx.Row) (organization.OrgRetention, error) {
+	var (
+		r                   organization.OrgRetention
+		planAudit, planScan *int
+		settings            []byte
+	)
+	if err := row.Scan(&r.OrgID, &planAudit, &planScan, &settings); err != nil {
+		if err == pgx.ErrNoRows {
+			return r, err
+		}
+		return r, fmt.Errorf("scan org retention: %w", err)
+	}
+	r.Limits = organization.RetentionPolicy{
+		AuditDays: organization.DefaultAuditRetentionDays,
+		ScanDays:  organization.DefaultScanRetentionDays,
+	}
+	if planAudit != nil {
+		r.Limits.AuditDays = *planAudit
+	}
+	if planScan != nil {
+		r.Limits.ScanDays = *planScan
+	}
+	r.Limits.PersonnelScanDays = r.Limits.ScanDays
+	if len(settings) > 0 {
+		if err := json.Unmarshal(settings, &r.Settings); err != nil {
+			return r, fmt.Errorf("decode retention settings for org %d: %w", r.OrgID, err)
+		}
+	}
+	return r, nil
+}
+
+// UpdateOrgRetentionSettings replaces metadata.retention with rs. Full-replace:
+// nil fields are omitted so they fall back to the plan window. Other metadata
+// keys are preserved via jsonb_set.
+func (s *Storage) UpdateOrgRetentionSettings(ctx context.Context, orgID int, rs organization.RetentionSettings) error {
+	blob, err := json.Marshal(rs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal retention settings: %w", err)
+	}
+	query := `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{retention}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+	result, err := s.pool.Exec(ctx, query, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update retention settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// PruneWebhookDeliveries deletes the org's delivered and failed webhook
+// deliveries created before cutoff. Pending rows are never pruned: they are
+// still owed to the receiver.
+func (s *Storage) PruneWebhookDeliveries(ctx context.Context, orgID int, cutoff time.Time) (int64, error) {
+	ct, err := s.pool.Exec(ctx, `
+		DELETE FROM trakrf.webhook_deliveries
+		WHERE org_id = $1 AND status <> 'pending' AND created_at < $2`, orgID, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("prune webhook deliveries: %w", err)
+	}
+	return ct.RowsAffected(), nil
+}
+
+// PruneAssetScans deletes the org's asset scans recorded before cutoff. The
+// delete runs under the org's RLS context; rows past the hypertable-wide
+// Timescale policy are dropped by Timescale itself.
+func (s *Storage) PruneAssetScans(ctx context.Context, orgID int, cutoff time.Time) (int64, error) {
+	var n int64
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx,
+			`DELETE FROM trakrf.asset_scans WHERE org_id = $1 AND timestamp < $2`, orgID, cutoff)
+		if err != nil {
+			return err
+		}
+		n = ct.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("prune asset scans: %w", err)
+	}
+	return n, nil
+}
+
+// PrunePersonnelScans deletes the scans of the org's person-assets
+// (metadata.person = true, deleted ones included) recorded before cutoff,
+// for a personnel window shorter than the org's scan window.
+func (s *Storage) PrunePersonnelScans(ctx context.Context, orgID int, cutoff time.Time) (int64, error) {
+	var n int64
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `
+			DELETE FROM trakrf.asset_scans s
+			USING trakrf.assets a
+			WHERE s.org_id = $1 AND s.timestamp < $2
+			  AND a.id = s.asset_id AND a.org_id = $1 AND a.metadata->>'person' = 'true'`, orgID, cutoff)
+		if err != nil {
+			return err
+		}
+		n = ct.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("prune personnel scans: %w", err)
+	}
+	return n, nil
+}
+
+// PruneSyncMutations deletes the org's offline sync mutation records created
+// before cutoff. A device replaying one of them afterwards is treated as new.
+func (s *Storage) PruneSyncMutations(ctx context.Context, orgID int, cutoff time.Time) (int64, error) {
+	var n int64
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx,
+			`DELETE FROM trakrf.sync_mutations WHERE org_id = $1 AND created_at < $2`, orgID, cutoff)
+		if err != nil {
+			return err
+		}
+		n = ct.RowsAffected()
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("prune sync mutations: %w", err)
+	}
+	return n, nil
+}
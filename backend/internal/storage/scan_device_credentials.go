@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/organization"
+	"github.com/trakrf/platform/backend/internal/models/scandevice"
+)
+
+// IssueScanDeviceCredential stores credentialHash (the SHA-256 of a new
+// reader credential) on one of orgID's live scan devices, replacing any
+// previous credential. It returns the issue time, or nil when the device
+// does not exist in orgID.
+func (s *Storage) IssueScanDeviceCredential(ctx context.Context, orgID, id int, credentialHash string) (*time.Time, error) {
+	var issuedAt time.Time
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, `
+			UPDATE trakrf.scan_devices
+			   SET credential_hash = $3, credential_issued_at = NOW(), credential_last_used_at = NULL
+			 WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL
+			RETURNING credential_issued_at`, id, orgID, credentialHash).Scan(&issuedAt)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue scan device credential: %w", err)
+	}
+	return &issuedAt, nil
+}
+
+// RevokeScanDeviceCredential clears the reader credential of one of orgID's
+// live scan devices. It reports false when the device does not exist in
+// orgID; revoking a device without a credential is a no-op that reports true.
+func (s *Storage) RevokeScanDeviceCredential(ctx context.Context, orgID, id int) (bool, error) {
+	var rowsAffected int64
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE trakrf.scan_devices
+			   SET credential_hash = NULL, credential_issued_at = NULL, credential_last_used_at = NULL
+			 WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, id, orgID)
+		rowsAffected = tag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke scan device credential: %w", err)
+	}
+	return rowsAffected > 0, nil
+}
+
+// GetScanReaderByCredential resolves a presented reader credential's hash
+// within orgID, or returns nil when no live, active, in-validity device of
+// orgID holds it. A hit stamps credential_last_used_at, at most once a
+// minute per device.
+func (s *Storage) GetScanReaderByCredential(ctx context.Context, orgID int, credentialHash string) (*scandevice.Reader, error) {
+	var rd scandevice.Reader
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `
+			SELECT id, org_id, name
+			  FROM trakrf.scan_devices
+			 WHERE credential_hash = $1 AND org_id = $2
+			   AND deleted_at IS NULL AND is_active
+			   AND (valid_to IS NULL OR valid_to > NOW())`, credentialHash, orgID).Scan(&rd.DeviceID, &rd.OrgID, &rd.Name); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+			UPDATE trakrf.scan_devices SET credential_last_used_at = NOW()
+			 WHERE id = $1 AND (credential_last_used_at IS NULL OR credential_last_used_at < NOW() - INTERVAL '1 minute')`,
+			rd.DeviceID)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reader credential: %w", err)
+	}
+	return &rd, nil
+}
+
+// GetScanSettings returns the org's scan settings (zero value when unset), or
+// nil when the org does not exist.
+func (s *Storage) GetScanSettings(ctx context.Context, orgID int) (*organization.ScanSettings, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'scans' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan settings: %w", err)
+	}
+	var ss organization.ScanSettings
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &ss); err != nil {
+			return nil, fmt.Errorf("failed to decode scan settings: %w", err)
+		}
+	}
+	return &ss, nil
+}
+
+// UpdateScanSettings replaces metadata.scans with ss. Other metadata keys are
+// preserved.
+func (s *Storage) UpdateScanSettings(ctx context.Context, orgID int, ss organization.ScanSettings) error {
+	blob, err := json.Marshal(ss)
+	if err != nil {
+		return fmt.Errorf("failed to marshal scan settings: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{scans}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update scan settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
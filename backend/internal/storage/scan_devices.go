@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/trakrf/platform/backend/internal/events"
 	"github.com/trakrf/platform/backend/internal/models/scandevice"
 )
 
@@ -13,12 +14,14 @@ import (
 // identical across every scan_devices query so scan targets line up.
 const scanDeviceColumns = `id, org_id, name, type, transport, publish_topic,
 	serial_number, model, COALESCE(description, ''), metadata,
-	valid_from, valid_to, is_active, created_at, updated_at, deleted_at`
+	valid_from, valid_to, is_active, credential_issued_at, credential_last_used_at,
+	created_at, updated_at, deleted_at`
 
 func scanScanDevice(row pgx.Row, d *scandevice.ScanDevice) error {
 	return row.Scan(&d.ID, &d.OrgID, &d.Name, &d.Type, &d.Transport, &d.PublishTopic,
 		&d.SerialNumber, &d.Model, &d.Description, &d.Metadata,
-		&d.ValidFrom, &d.ValidTo, &d.IsActive, &d.CreatedAt, &d.UpdatedAt, &d.DeletedAt)
+		&d.ValidFrom, &d.ValidTo, &d.IsActive, &d.CredentialIssuedAt, &d.CredentialLastUsedAt,
+		&d.CreatedAt, &d.UpdatedAt, &d.DeletedAt)
 }
 
 // CreateScanDevice inserts a scan device. transport defaults to mqtt;
@@ -58,7 +61,10 @@ func (s *Storage) CreateScanDevice(ctx context.Context, orgID int, req scandevic
 			(org_id, scan_device_id, name, antenna_port)
 			VALUES ($1, $2, 'Antenna 1', 1)`,
 			orgID, d.ID)
-		return err
+		if err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.ScanDeviceCreated, orgID, d.ID)
 	})
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
@@ -177,7 +183,10 @@ func (s *Storage) UpdateScanDevice(ctx context.Context, orgID, id int, req scand
 
 	var d scandevice.ScanDevice
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		return scanScanDevice(tx.QueryRow(ctx, query, args...), &d)
+		if err := scanScanDevice(tx.QueryRow(ctx, query, args...), &d); err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.ScanDeviceUpdated, orgID, d.ID)
 	})
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -211,7 +220,10 @@ func (s *Storage) DeleteScanDevice(ctx context.Context, orgID, id int) (bool, er
 			UPDATE trakrf.scan_points
 			   SET deleted_at = NOW()
 			 WHERE scan_device_id = $1 AND org_id = $2 AND deleted_at IS NULL`, id, orgID)
-		return err
+		if err != nil {
+			return err
+		}
+		return s.publish(ctx, tx, events.ScanDeviceDeleted, orgID, id)
 	})
 	if err != nil {
 		return false, fmt.Errorf("could not delete scan device: %w", err)
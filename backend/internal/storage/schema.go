@@ -0,0 +1,24 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SchemaVersion returns the migration version golang-migrate last recorded
+// and whether it was left dirty by a failed run. A database that has never
+// been migrated reports version 0.
+func (s *Storage) SchemaVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	var v int64
+	err = s.pool.QueryRow(ctx, `SELECT version, dirty FROM trakrf.schema_migrations LIMIT 1`).Scan(&v, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return uint(v), dirty, nil
+}
@@ -0,0 +1,52 @@
+//go:build integration
+// +build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestSeedAssetsAndScans(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := context.Background()
+	start := time.Now().Add(-48 * time.Hour).UTC().Truncate(time.Second)
+
+	ids, err := db.Store.SeedAssets(ctx, orgID, start, []storage.SeedAsset{
+		{ExternalKey: "SEED-1", Name: "Tote 1", Metadata: map[string]any{"category": "Returnable Tote"}, TagValue: "E28011600000000100000001"},
+		{ExternalKey: "SEED-2", Name: "Tote 2", Description: "no tag"},
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	var tags int
+	require.NoError(t, db.AdminPool.QueryRow(ctx,
+		`SELECT count(*) FROM trakrf.tags WHERE org_id = $1 AND asset_id = $2 AND value = 'E28011600000000100000001'`,
+		orgID, ids["SEED-1"]).Scan(&tags))
+	require.Equal(t, 1, tags)
+
+	var locID int
+	require.NoError(t, db.AdminPool.QueryRow(ctx,
+		`INSERT INTO trakrf.locations (org_id, external_key, name) VALUES ($1, 'SEED-LOC', 'Seed Loc') RETURNING id`,
+		orgID).Scan(&locID))
+
+	n, err := db.Store.SeedAssetScans(ctx, orgID, []storage.SeedScan{
+		{Timestamp: start.Add(time.Hour), AssetID: ids["SEED-1"], LocationID: locID},
+		{Timestamp: start.Add(2 * time.Hour), AssetID: ids["SEED-2"], LocationID: locID},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+
+	var scans int
+	require.NoError(t, db.AdminPool.QueryRow(ctx,
+		`SELECT count(*) FROM trakrf.asset_scans WHERE org_id = $1`, orgID).Scan(&scans))
+	require.Equal(t, 2, scans)
+}
@@ -0,0 +1,505 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/sensor"
+)
+
+// ErrSensorThresholdExists is returned when an org already has a threshold for
+// the asset type and metric.
+var ErrSensorThresholdExists = errors.New("a sensor threshold for this asset_type and metric already exists")
+
+const sensorThresholdColumns = `id, org_id, asset_type, metric, min_value, max_value, created_at, updated_at`
+
+const sensorAlertColumns = `id, org_id, asset_id, threshold_id, metric, condition, limit_value,
+	trigger_value, extreme_value, last_value, reading_count, opened_at, last_reading_at,
+	resolved_at, created_at, updated_at`
+
+func scanSensorThreshold(row pgx.Row) (*sensor.Threshold, error) {
+	var t sensor.Threshold
+	if err := row.Scan(&t.ID, &t.OrgID, &t.AssetType, &t.Metric, &t.MinValue, &t.MaxValue,
+		&t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func scanSensorAlert(row pgx.Row) (*sensor.Alert, error) {
+	var a sensor.Alert
+	if err := row.Scan(&a.ID, &a.OrgID, &a.AssetID, &a.ThresholdID, &a.Metric, &a.Condition,
+		&a.LimitValue, &a.TriggerValue, &a.ExtremeValue, &a.LastValue, &a.ReadingCount,
+		&a.OpenedAt, &a.LastReadingAt, &a.ResolvedAt, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func sensorThresholdError(err error) error {
+	if strings.Contains(err.Error(), "sensor_thresholds_org_type_metric") {
+		return ErrSensorThresholdExists
+	}
+	return nil
+}
+
+// sensorKey identifies one metric stream of one asset.
+type sensorKey struct {
+	assetID int
+	metric  string
+}
+
+// sensorAlertChange is an alert as it stands after a batch of readings. New
+// alerts are inserted, existing ones updated; opened/resolved say which
+// events to publish.
+type sensorAlertChange struct {
+	alert    sensor.Alert
+	isNew    bool
+	opened   bool
+	resolved bool
+}
+
+// planSensorAlerts walks readings in recorded order against the thresholds
+// and the currently open alerts and returns every alert that changed. A
+// reading outside its threshold opens an alert (or extends the open one for
+// the same condition); the first in-range reading resolves it. A reading
+// older than an open alert's latest reading is a late buffered upload and
+// does not move the alert.
+func planSensorAlerts(readings []sensor.NewReading, thresholds map[sensorKey]sensor.Threshold, open map[sensorKey]sensor.Alert) []*sensorAlertChange {
+	ordered := make([]sensor.NewReading, len(readings))
+	copy(ordered, readings)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].RecordedAt.Before(ordered[j].RecordedAt) })
+
+	var changes []*sensorAlertChange
+	current := map[sensorKey]*sensorAlertChange{}
+	for k, a := range open {
+		current[k] = &sensorAlertChange{alert: a}
+	}
+	touched := map[*sensorAlertChange]bool{}
+
+	for _, r := range ordered {
+		k := sensorKey{r.AssetID, r.Metric}
+		th, ok := thresholds[k]
+		if !ok {
+			continue
+		}
+		cond, limit, breached := th.Check(r.Value)
+
+		if cur := current[k]; cur != nil {
+			if r.RecordedAt.Before(cur.alert.LastReadingAt) {
+				continue
+			}
+			if !touched[cur] {
+				touched[cur] = true
+				changes = append(changes, cur)
+			}
+			cur.alert.LastValue = r.Value
+			cur.alert.LastReadingAt = r.RecordedAt
+			if breached && cond == cur.alert.Condition {
+				cur.alert.ReadingCount++
+				if (cond == sensor.ConditionBelowMin && r.Value < cur.alert.ExtremeValue) ||
+					(cond == sensor.ConditionAboveMax && r.Value > cur.alert.ExtremeValue) {
+					cur.alert.ExtremeValue = r.Value
+				}
+				continue
+			}
+			resolvedAt := r.RecordedAt
+			cur.alert.ResolvedAt = &resolvedAt
+			cur.resolved = true
+			delete(current, k)
+		}
+
+		if breached {
+			thresholdID := th.ID
+			ch := &sensorAlertChange{
+				alert: sensor.Alert{
+					AssetID:       r.AssetID,
+					ThresholdID:   &thresholdID,
+					Metric:        r.Metric,
+					Condition:     cond,
+					LimitValue:    limit,
+					TriggerValue:  r.Value,
+					ExtremeValue:  r.Value,
+					LastValue:     r.Value,
+					ReadingCount:  1,
+					OpenedAt:      r.RecordedAt,
+					LastReadingAt: r.RecordedAt,
+				},
+				isNew:  true,
+				opened: true,
+			}
+			current[k] = ch
+			touched[ch] = true
+			changes = append(changes, ch)
+		}
+	}
+	return changes
+}
+
+// IngestSensorReadings stores readings and opens or resolves sensor alerts
+// against the thresholds of each asset's type, in one org transaction.
+// Readings already stored (same asset, metric and recorded_at) are counted
+// as duplicates and do not touch alerts. Alert changes are published as
+// sensor_alert.opened / sensor_alert.resolved.
+func (s *Storage) IngestSensorReadings(ctx context.Context, orgID int, readings []sensor.NewReading) (*sensor.IngestResult, error) {
+	res := &sensor.IngestResult{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var stored []sensor.NewReading
+		assetSet := map[int]bool{}
+		for _, r := range readings {
+			tag, err := tx.Exec(ctx, `
+				INSERT INTO trakrf.sensor_readings (recorded_at, org_id, asset_id, metric, value, unit, device_id)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				ON CONFLICT DO NOTHING`,
+				r.RecordedAt, orgID, r.AssetID, r.Metric, r.Value, r.Unit, r.DeviceID)
+			if err != nil {
+				return fmt.Errorf("insert sensor reading for asset %d: %w", r.AssetID, err)
+			}
+			if tag.RowsAffected() == 0 {
+				res.Duplicates++
+				continue
+			}
+			res.Accepted++
+			stored = append(stored, r)
+			assetSet[r.AssetID] = true
+		}
+		if len(stored) == 0 {
+			return nil
+		}
+
+		assetIDs := make([]int, 0, len(assetSet))
+		for id := range assetSet {
+			assetIDs = append(assetIDs, id)
+		}
+		sort.Ints(assetIDs)
+
+		// Serialize alert planning per asset so concurrent uploads for the
+		// same asset cannot both open an alert (idx_sensor_alerts_open).
+		if _, err := tx.Exec(ctx, `
+			SELECT pg_advisory_xact_lock(hashtextextended('sensor_alerts:' || id::text, 0))
+			FROM unnest($1::bigint[]) AS id ORDER BY id`, assetIDs); err != nil {
+			return fmt.Errorf("lock sensor alerts: %w", err)
+		}
+
+		thresholds, err := s.sensorThresholdsForAssets(ctx, tx, orgID, assetIDs)
+		if err != nil {
+			return err
+		}
+		if len(thresholds) == 0 {
+			return nil
+		}
+		open, err := s.openSensorAlerts(ctx, tx, orgID, assetIDs)
+		if err != nil {
+			return err
+		}
+
+		for _, ch := range planSensorAlerts(stored, thresholds, open) {
+			if err := s.applySensorAlertChange(ctx, tx, orgID, ch); err != nil {
+				return err
+			}
+			if ch.opened {
+				res.AlertsOpened++
+			}
+			if ch.resolved {
+				res.AlertsResolved++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to ingest sensor readings: %w", err)
+	}
+	return res, nil
+}
+
+// sensorThresholdsForAssets returns the threshold that applies to each
+// (asset, metric), matched on the asset's metadata.asset_type.
+func (s *Storage) sensorThresholdsForAssets(ctx context.Context, tx pgx.Tx, orgID int, assetIDs []int) (map[sensorKey]sensor.Threshold, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT a.id, t.id, t.org_id, t.asset_type, t.metric, t.min_value, t.max_value, t.created_at, t.updated_at
+		FROM trakrf.assets a
+		JOIN trakrf.sensor_thresholds t
+		  ON t.org_id = a.org_id AND t.asset_type = a.metadata->>'`+sensor.AssetTypeKey+`'
+		WHERE a.org_id = $1 AND a.id = ANY($2)`, orgID, assetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("load sensor thresholds: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[sensorKey]sensor.Threshold{}
+	for rows.Next() {
+		var assetID int
+		var t sensor.Threshold
+		if err := rows.Scan(&assetID, &t.ID, &t.OrgID, &t.AssetType, &t.Metric, &t.MinValue, &t.MaxValue,
+			&t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan sensor threshold: %w", err)
+		}
+		out[sensorKey{assetID, t.Metric}] = t
+	}
+	return out, rows.Err()
+}
+
+func (s *Storage) openSensorAlerts(ctx context.Context, tx pgx.Tx, orgID int, assetIDs []int) (map[sensorKey]sensor.Alert, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT `+sensorAlertColumns+`
+		FROM trakrf.sensor_alerts
+		WHERE org_id = $1 AND asset_id = ANY($2) AND resolved_at IS NULL`, orgID, assetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("load open sensor alerts: %w", err)
+	}
+	defer rows.Close()
+
+	out := map[sensorKey]sensor.Alert{}
+	for rows.Next() {
+		a, err := scanSensorAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan sensor alert: %w", err)
+		}
+		out[sensorKey{a.AssetID, a.Metric}] = *a
+	}
+	return out, rows.Err()
+}
+
+func (s *Storage) applySensorAlertChange(ctx context.Context, tx pgx.Tx, orgID int, ch *sensorAlertChange) error {
+	a := ch.alert
+	if ch.isNew {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.sensor_alerts
+				(org_id, asset_id, threshold_id, metric, condition, limit_value, trigger_value,
+				 extreme_value, last_value, reading_count, opened_at, last_reading_at, resolved_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			RETURNING id`,
+			orgID, a.AssetID, a.ThresholdID, a.Metric, a.Condition, a.LimitValue, a.TriggerValue,
+			a.ExtremeValue, a.LastValue, a.ReadingCount, a.OpenedAt, a.LastReadingAt, a.ResolvedAt,
+		).Scan(&a.ID)
+		if err != nil {
+			return fmt.Errorf("insert sensor alert for asset %d: %w", a.AssetID, err)
+		}
+	} else {
+		_, err := tx.Exec(ctx, `
+			UPDATE trakrf.sensor_alerts SET
+				extreme_value = $2, last_value = $3, reading_count = $4,
+				last_reading_at = $5, resolved_at = $6
+			WHERE id = $1`,
+			a.ID, a.ExtremeValue, a.LastValue, a.ReadingCount, a.LastReadingAt, a.ResolvedAt)
+		if err != nil {
+			return fmt.Errorf("update sensor alert %d: %w", a.ID, err)
+		}
+	}
+	if ch.opened {
+		if err := s.publish(ctx, tx, events.SensorAlertOpened, orgID, a.ID); err != nil {
+			return err
+		}
+	}
+	if ch.resolved {
+		if err := s.publish(ctx, tx, events.SensorAlertResolved, orgID, a.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSensorReadings returns an asset's readings, newest first. found is
+// false when the asset is not a live asset of the org.
+func (s *Storage) ListSensorReadings(ctx context.Context, orgID, assetID int, f sensor.ReadingFilter) ([]sensor.Reading, bool, error) {
+	out := []sensor.Reading{}
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM trakrf.assets WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL)`,
+			assetID, orgID).Scan(&found)
+		if err != nil || !found {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT asset_id, metric, value, unit, device_id, recorded_at, received_at
+			FROM trakrf.sensor_readings
+			WHERE org_id = $1 AND asset_id = $2
+			  AND ($3::text IS NULL OR metric = $3)
+			  AND ($4::timestamptz IS NULL OR recorded_at >= $4)
+			  AND ($5::timestamptz IS NULL OR recorded_at < $5)
+			ORDER BY recorded_at DESC, metric
+			LIMIT $6`, orgID, assetID, f.Metric, f.From, f.To, f.Limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var r sensor.Reading
+			if err := rows.Scan(&r.AssetID, &r.Metric, &r.Value, &r.Unit, &r.DeviceID, &r.RecordedAt, &r.ReceivedAt); err != nil {
+				return err
+			}
+			out = append(out, r)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list sensor readings: %w", err)
+	}
+	return out, found, nil
+}
+
+// ListSensorAlerts returns the org's sensor alerts, most recently opened
+// first.
+func (s *Storage) ListSensorAlerts(ctx context.Context, orgID int, f sensor.AlertFilter) ([]sensor.Alert, error) {
+	out := []sensor.Alert{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT `+sensorAlertColumns+`
+			FROM trakrf.sensor_alerts
+			WHERE org_id = $1
+			  AND ($2::bigint IS NULL OR asset_id = $2)
+			  AND ($3::boolean IS NULL OR (resolved_at IS NULL) = $3)
+			ORDER BY opened_at DESC, id
+			LIMIT $4`, orgID, f.AssetID, f.Open, f.Limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			a, err := scanSensorAlert(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sensor alerts: %w", err)
+	}
+	return out, nil
+}
+
+// ListSensorThresholds returns the org's thresholds by asset type and metric.
+func (s *Storage) ListSensorThresholds(ctx context.Context, orgID int) ([]sensor.Threshold, error) {
+	out := []sensor.Threshold{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT `+sensorThresholdColumns+`
+			FROM trakrf.sensor_thresholds
+			WHERE org_id = $1
+			ORDER BY asset_type, metric`, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			t, err := scanSensorThreshold(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *t)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sensor thresholds: %w", err)
+	}
+	return out, nil
+}
+
+// CreateSensorThreshold adds a threshold. Returns ErrSensorThresholdExists
+// when the org already has one for the asset type and metric.
+func (s *Storage) CreateSensorThreshold(ctx context.Context, orgID int, req sensor.ThresholdRequest) (*sensor.Threshold, error) {
+	var t *sensor.Threshold
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		t, err = scanSensorThreshold(tx.QueryRow(ctx, `
+			INSERT INTO trakrf.sensor_thresholds (org_id, asset_type, metric, min_value, max_value)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING `+sensorThresholdColumns,
+			orgID, req.AssetType, req.Metric, req.MinValue, req.MaxValue))
+		return err
+	})
+	if err != nil {
+		if dupErr := sensorThresholdError(err); dupErr != nil {
+			return nil, dupErr
+		}
+		return nil, fmt.Errorf("failed to create sensor threshold: %w", err)
+	}
+	return t, nil
+}
+
+// UpdateSensorThreshold replaces a threshold and resolves the alerts open
+// against it: they were raised under the old limits, and the next reading
+// reopens one if it is still out of range. Returns nil when the threshold
+// does not exist in the org.
+func (s *Storage) UpdateSensorThreshold(ctx context.Context, orgID, id int, req sensor.ThresholdRequest) (*sensor.Threshold, error) {
+	var t *sensor.Threshold
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		t, err = scanSensorThreshold(tx.QueryRow(ctx, `
+			UPDATE trakrf.sensor_thresholds
+			SET asset_type = $3, metric = $4, min_value = $5, max_value = $6
+			WHERE id = $1 AND org_id = $2
+			RETURNING `+sensorThresholdColumns,
+			id, orgID, req.AssetType, req.Metric, req.MinValue, req.MaxValue))
+		if err != nil {
+			return err
+		}
+		return s.resolveThresholdAlerts(ctx, tx, orgID, id)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		if dupErr := sensorThresholdError(err); dupErr != nil {
+			return nil, dupErr
+		}
+		return nil, fmt.Errorf("failed to update sensor threshold: %w", err)
+	}
+	return t, nil
+}
+
+// DeleteSensorThreshold removes a threshold and resolves the alerts open
+// against it. Alert history stays, with threshold_id cleared. Returns false
+// when the threshold does not exist in the org.
+func (s *Storage) DeleteSensorThreshold(ctx context.Context, orgID, id int) (bool, error) {
+	var deleted bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if err := s.resolveThresholdAlerts(ctx, tx, orgID, id); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx,
+			`DELETE FROM trakrf.sensor_thresholds WHERE id = $1 AND org_id = $2`, id, orgID)
+		if err != nil {
+			return err
+		}
+		deleted = tag.RowsAffected() > 0
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete sensor threshold: %w", err)
+	}
+	return deleted, nil
+}
+
+// resolveThresholdAlerts resolves, as of now, every alert open against the
+// threshold and publishes sensor_alert.resolved for each.
+func (s *Storage) resolveThresholdAlerts(ctx context.Context, tx pgx.Tx, orgID, thresholdID int) error {
+	rows, err := tx.Query(ctx, `
+		UPDATE trakrf.sensor_alerts SET resolved_at = NOW()
+		WHERE org_id = $1 AND threshold_id = $2 AND resolved_at IS NULL
+		RETURNING id`, orgID, thresholdID)
+	if err != nil {
+		return fmt.Errorf("resolve sensor alerts of threshold %d: %w", thresholdID, err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return fmt.Errorf("resolve sensor alerts of threshold %d: %w", thresholdID, err)
+	}
+	for _, id := range ids {
+		if err := s.publish(ctx, tx, events.SensorAlertResolved, orgID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/sensor"
+)
+
+var sensorT0 = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func tempReading(assetID int, minute int, v float64) sensor.NewReading {
+	return sensor.NewReading{AssetID: assetID, Metric: "temperature", Value: v,
+		RecordedAt: sensorT0.Add(time.Duration(minute) * time.Minute)}
+}
+
+func coldChainThresholds(assetIDs ...int) map[sensorKey]sensor.Threshold {
+	lo, hi := 2.0, 8.0
+	out := map[sensorKey]sensor.Threshold{}
+	for _, id := range assetIDs {
+		out[sensorKey{id, "temperature"}] = sensor.Threshold{ID: 500, Metric: "temperature", MinValue: &lo, MaxValue: &hi}
+	}
+	return out
+}
+
+func TestPlanSensorAlerts_OpensExtendsAndResolves(t *testing.T) {
+	// Out of order on the wire; planned in recorded order.
+	readings := []sensor.NewReading{
+		tempReading(1, 3, 5),
+		tempReading(1, 1, 9),
+		tempReading(1, 0, 6),
+		tempReading(1, 2, 11),
+	}
+	changes := planSensorAlerts(readings, coldChainThresholds(1), nil)
+	require.Len(t, changes, 1)
+
+	ch := changes[0]
+	assert.True(t, ch.isNew)
+	assert.True(t, ch.opened)
+	assert.True(t, ch.resolved)
+	assert.Equal(t, sensor.ConditionAboveMax, ch.alert.Condition)
+	assert.Equal(t, 8.0, ch.alert.LimitValue)
+	assert.Equal(t, 9.0, ch.alert.TriggerValue)
+	assert.Equal(t, 11.0, ch.alert.ExtremeValue)
+	assert.Equal(t, 2, ch.alert.ReadingCount)
+	assert.Equal(t, sensorT0.Add(time.Minute), ch.alert.OpenedAt)
+	require.NotNil(t, ch.alert.ResolvedAt)
+	assert.Equal(t, sensorT0.Add(3*time.Minute), *ch.alert.ResolvedAt)
+	require.NotNil(t, ch.alert.ThresholdID)
+	assert.Equal(t, 500, *ch.alert.ThresholdID)
+}
+
+func TestPlanSensorAlerts_ExtendsOpenAlert(t *testing.T) {
+	open := map[sensorKey]sensor.Alert{
+		{1, "temperature"}: {ID: 77, AssetID: 1, Metric: "temperature", Condition: sensor.ConditionBelowMin,
+			LimitValue: 2, TriggerValue: 1, ExtremeValue: 1, LastValue: 1, ReadingCount: 1,
+			OpenedAt: sensorT0, LastReadingAt: sensorT0},
+	}
+	changes := planSensorAlerts([]sensor.NewReading{tempReading(1, 5, 0.5)}, coldChainThresholds(1), open)
+	require.Len(t, changes, 1)
+	ch := changes[0]
+	assert.False(t, ch.isNew)
+	assert.False(t, ch.opened)
+	assert.False(t, ch.resolved)
+	assert.Equal(t, 77, ch.alert.ID)
+	assert.Equal(t, 0.5, ch.alert.ExtremeValue)
+	assert.Equal(t, 2, ch.alert.ReadingCount)
+	assert.Nil(t, ch.alert.ResolvedAt)
+}
+
+func TestPlanSensorAlerts_ConditionFlipResolvesAndReopens(t *testing.T) {
+	open := map[sensorKey]sensor.Alert{
+		{1, "temperature"}: {ID: 77, AssetID: 1, Metric: "temperature", Condition: sensor.ConditionBelowMin,
+			LimitValue: 2, ExtremeValue: 1, OpenedAt: sensorT0, LastReadingAt: sensorT0},
+	}
+	changes := planSensorAlerts([]sensor.NewReading{tempReading(1, 5, 12)}, coldChainThresholds(1), open)
+	require.Len(t, changes, 2)
+	assert.Equal(t, 77, changes[0].alert.ID)
+	assert.True(t, changes[0].resolved)
+	assert.True(t, changes[1].isNew)
+	assert.Equal(t, sensor.ConditionAboveMax, changes[1].alert.Condition)
+}
+
+func TestPlanSensorAlerts_IgnoresLateAndUnthresholdedReadings(t *testing.T) {
+	open := map[sensorKey]sensor.Alert{
+		{1, "temperature"}: {ID: 77, AssetID: 1, Metric: "temperature", Condition: sensor.ConditionAboveMax,
+			LimitValue: 8, ExtremeValue: 10, OpenedAt: sensorT0, LastReadingAt: sensorT0.Add(10 * time.Minute)},
+	}
+	readings := []sensor.NewReading{
+		tempReading(1, 5, 5),  // older than the alert's latest reading: late upload
+		tempReading(2, 5, 30), // asset 2 has no threshold
+		{AssetID: 1, Metric: "humidity", Value: 99, RecordedAt: sensorT0},
+	}
+	assert.Empty(t, planSensorAlerts(readings, coldChainThresholds(1), open))
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/trakrf/platform/backend/internal/models/serviceaccount"
+)
+
+// Like api_keys, service_accounts has no RLS; every query filters on org_id
+// explicitly.
+
+const serviceAccountColumns = `
+	sa.id, sa.org_id, sa.name, sa.description, sa.created_by,
+	(SELECT COUNT(*) FROM trakrf.api_keys k
+	 WHERE k.service_account_id = sa.id AND k.revoked_at IS NULL),
+	sa.created_at, sa.updated_at`
+
+func scanServiceAccount(row pgx.Row) (*serviceaccount.ServiceAccount, error) {
+	var a serviceaccount.ServiceAccount
+	if err := row.Scan(&a.ID, &a.OrgID, &a.Name, &a.Description, &a.CreatedBy,
+		&a.ActiveKeyCount, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// ListServiceAccounts returns the org's live service accounts by name.
+func (s *Storage) ListServiceAccounts(ctx context.Context, orgID int) ([]serviceaccount.ServiceAccount, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+serviceAccountColumns+`
+		FROM trakrf.service_accounts sa
+		WHERE sa.org_id = $1 AND sa.deleted_at IS NULL
+		ORDER BY lower(sa.name), sa.id`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []serviceaccount.ServiceAccount{}
+	for rows.Next() {
+		a, err := scanServiceAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service account: %w", err)
+		}
+		accounts = append(accounts, *a)
+	}
+	return accounts, rows.Err()
+}
+
+// GetServiceAccount returns a live service account, or nil when it does not
+// exist in the org.
+func (s *Storage) GetServiceAccount(ctx context.Context, orgID, id int) (*serviceaccount.ServiceAccount, error) {
+	a, err := scanServiceAccount(s.pool.QueryRow(ctx, `
+		SELECT `+serviceAccountColumns+`
+		FROM trakrf.service_accounts sa
+		WHERE sa.id = $1 AND sa.org_id = $2 AND sa.deleted_at IS NULL`, id, orgID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service account: %w", err)
+	}
+	return a, nil
+}
+
+// CreateServiceAccount inserts a service account. Names are unique per org
+// (case-insensitive) among live accounts.
+func (s *Storage) CreateServiceAccount(ctx context.Context, orgID int, createdBy *int, req serviceaccount.CreateServiceAccountRequest) (*serviceaccount.ServiceAccount, error) {
+	a, err := scanServiceAccount(s.pool.QueryRow(ctx, `
+		WITH sa AS (
+			INSERT INTO trakrf.service_accounts (org_id, name, description, created_by)
+			VALUES ($1, $2, $3, $4)
+			RETURNING *
+		)
+		SELECT `+serviceAccountColumns+` FROM sa`,
+		orgID, req.Name, req.Description, createdBy))
+	if err != nil {
+		if strings.Contains(err.Error(), "idx_service_accounts_org_name") {
+			return nil, fmt.Errorf("service account with name %s already exists", req.Name)
+		}
+		return nil, fmt.Errorf("failed to create service account: %w", err)
+	}
+	return a, nil
+}
+
+// DeleteServiceAccount soft-deletes a service account and revokes every key it
+// owns in the same transaction, so its integrations stop working immediately.
+// Returns false when the account does not exist in the org.
+func (s *Storage) DeleteServiceAccount(ctx context.Context, orgID, id int) (bool, error) {
+	var deleted bool
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `
+			UPDATE trakrf.service_accounts SET deleted_at = NOW()
+			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, id, orgID)
+		if err != nil {
+			return err
+		}
+		deleted = ct.RowsAffected() > 0
+		if !deleted {
+			return nil
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.api_keys SET revoked_at = NOW()
+			WHERE service_account_id = $1 AND org_id = $2 AND revoked_at IS NULL`, id, orgID)
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete service account: %w", err)
+	}
+	return deleted, nil
+}
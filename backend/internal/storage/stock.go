@@ -0,0 +1,402 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/stock"
+)
+
+// ErrNotConsumable is returned when a stock change names an asset that is not
+// tracked by quantity.
+var ErrNotConsumable = errors.New("asset is not a consumable")
+
+// ErrInsufficientStock is returned when an adjustment would take a stock
+// level below zero.
+var ErrInsufficientStock = errors.New("adjustment would take the stock level below zero")
+
+const consumableColumns = `asset_id, org_id, unit, min_level, created_at, updated_at`
+
+const stockAlertColumns = `id, org_id, asset_id, location_id, min_level, quantity, opened_at,
+	resolved_at, created_at, updated_at`
+
+func scanConsumable(row pgx.Row) (*stock.Consumable, error) {
+	var c stock.Consumable
+	if err := row.Scan(&c.AssetID, &c.OrgID, &c.Unit, &c.MinLevel, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func scanStockAlert(row pgx.Row) (*stock.Alert, error) {
+	var a stock.Alert
+	if err := row.Scan(&a.ID, &a.OrgID, &a.AssetID, &a.LocationID, &a.MinLevel, &a.Quantity,
+		&a.OpenedAt, &a.ResolvedAt, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetConsumable returns the asset's consumable settings, or nil when the
+// asset is not tracked by quantity.
+func (s *Storage) GetConsumable(ctx context.Context, orgID, assetID int) (*stock.Consumable, error) {
+	var c *stock.Consumable
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		c, err = scanConsumable(tx.QueryRow(ctx, `
+			SELECT `+consumableColumns+` FROM trakrf.consumables
+			WHERE asset_id = $1 AND org_id = $2`, assetID, orgID))
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumable: %w", err)
+	}
+	return c, nil
+}
+
+// SetConsumable makes a live asset a consumable, or updates its unit and
+// minimum level, then re-checks every location's level against the new
+// minimum. Returns nil when the asset does not exist in the org.
+func (s *Storage) SetConsumable(ctx context.Context, orgID, assetID int, req stock.ConsumableRequest) (*stock.Consumable, error) {
+	var c *stock.Consumable
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM trakrf.assets WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL)`,
+			assetID, orgID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return nil
+		}
+		var err error
+		c, err = scanConsumable(tx.QueryRow(ctx, `
+			INSERT INTO trakrf.consumables (asset_id, org_id, unit, min_level)
+			VALUES ($1, $2, COALESCE($3, '`+stock.DefaultUnit+`'), $4)
+			ON CONFLICT (asset_id) DO UPDATE
+			SET unit = COALESCE($3, consumables.unit), min_level = EXCLUDED.min_level
+			RETURNING `+consumableColumns,
+			assetID, orgID, req.Unit, req.MinLevel))
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT location_id, quantity FROM trakrf.stock_levels
+			WHERE asset_id = $1 AND org_id = $2
+			ORDER BY location_id`, assetID, orgID)
+		if err != nil {
+			return err
+		}
+		type level struct{ locationID, quantity int }
+		levels, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (level, error) {
+			var l level
+			err := row.Scan(&l.locationID, &l.quantity)
+			return l, err
+		})
+		if err != nil {
+			return err
+		}
+		for _, l := range levels {
+			if err := s.checkStockAlert(ctx, tx, orgID, assetID, l.locationID, l.quantity, c.MinLevel); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set consumable: %w", err)
+	}
+	return c, nil
+}
+
+// DeleteConsumable returns an asset to individual tracking. Its stock levels
+// are dropped and its open alerts resolved; the adjustment ledger and alert
+// history stay. Returns false when the asset is not a consumable.
+func (s *Storage) DeleteConsumable(ctx context.Context, orgID, assetID int) (bool, error) {
+	var deleted bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			UPDATE trakrf.stock_alerts SET resolved_at = NOW()
+			WHERE org_id = $1 AND asset_id = $2 AND resolved_at IS NULL
+			RETURNING id`, orgID, assetID)
+		if err != nil {
+			return err
+		}
+		ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx,
+			`DELETE FROM trakrf.consumables WHERE asset_id = $1 AND org_id = $2`, assetID, orgID)
+		if err != nil {
+			return err
+		}
+		deleted = tag.RowsAffected() > 0
+		for _, id := range ids {
+			if err := s.publish(ctx, tx, events.StockAlertResolved, orgID, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete consumable: %w", err)
+	}
+	return deleted, nil
+}
+
+// ListStockLevels returns a consumable's stock at each location it has been
+// stocked at, by location external key. The bool is false when the asset is
+// not a consumable.
+func (s *Storage) ListStockLevels(ctx context.Context, orgID, assetID int) ([]stock.Level, bool, error) {
+	out := []stock.Level{}
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var minLevel *int
+		err := tx.QueryRow(ctx, `
+			SELECT min_level FROM trakrf.consumables WHERE asset_id = $1 AND org_id = $2`,
+			assetID, orgID).Scan(&minLevel)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+
+		rows, err := tx.Query(ctx, `
+			SELECT sl.asset_id, sl.location_id, l.external_key, sl.quantity, sl.updated_at
+			FROM trakrf.stock_levels sl
+			JOIN trakrf.locations l ON l.id = sl.location_id AND l.org_id = $2
+			WHERE sl.asset_id = $1 AND sl.org_id = $2
+			ORDER BY l.external_key`, assetID, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var l stock.Level
+			if err := rows.Scan(&l.AssetID, &l.LocationID, &l.LocationExternalKey, &l.Quantity, &l.UpdatedAt); err != nil {
+				return err
+			}
+			l.BelowMin = stock.BelowMin(l.Quantity, minLevel)
+			out = append(out, l)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list stock levels: %w", err)
+	}
+	return out, found, nil
+}
+
+// AdjustStock applies one adjustment to a consumable's level at a location,
+// records it in the ledger and opens or resolves the location's stock alert.
+// Returns ErrNotConsumable or ErrInsufficientStock, or nil when the location
+// does not exist in the org.
+func (s *Storage) AdjustStock(ctx context.Context, orgID, assetID int, adj stock.NewAdjustment) (*stock.Adjustment, error) {
+	var out *stock.Adjustment
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var exists bool
+		if err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM trakrf.locations WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL)`,
+			adj.LocationID, orgID).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return nil
+		}
+		var err error
+		out, err = s.applyStockAdjustment(ctx, tx, orgID, assetID, adj)
+		return err
+	})
+	if errors.Is(err, ErrNotConsumable) || errors.Is(err, ErrInsufficientStock) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to adjust stock: %w", err)
+	}
+	return out, nil
+}
+
+// applyStockAdjustment is AdjustStock inside the caller's transaction, for
+// inventory saves that record counts alongside scans. The consumable row is
+// locked so concurrent adjustments of one asset apply in turn.
+func (s *Storage) applyStockAdjustment(ctx context.Context, tx pgx.Tx, orgID, assetID int, adj stock.NewAdjustment) (*stock.Adjustment, error) {
+	var minLevel *int
+	err := tx.QueryRow(ctx, `
+		SELECT c.min_level FROM trakrf.consumables c
+		JOIN trakrf.assets a ON a.id = c.asset_id AND a.deleted_at IS NULL
+		WHERE c.asset_id = $1 AND c.org_id = $2
+		FOR UPDATE OF c`, assetID, orgID).Scan(&minLevel)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotConsumable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lock consumable %d: %w", assetID, err)
+	}
+
+	var current int
+	err = tx.QueryRow(ctx, `
+		SELECT quantity FROM trakrf.stock_levels WHERE asset_id = $1 AND location_id = $2`,
+		assetID, adj.LocationID).Scan(&current)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("read stock level: %w", err)
+	}
+
+	after := current + adj.Delta
+	if adj.Kind == stock.KindCount {
+		after = adj.Quantity
+	}
+	if after < 0 {
+		return nil, ErrInsufficientStock
+	}
+
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO trakrf.stock_levels (asset_id, location_id, org_id, quantity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (asset_id, location_id) DO UPDATE
+		SET quantity = EXCLUDED.quantity, updated_at = NOW()`,
+		assetID, adj.LocationID, orgID, after); err != nil {
+		return nil, fmt.Errorf("write stock level: %w", err)
+	}
+
+	out := stock.Adjustment{
+		AssetID: assetID, LocationID: adj.LocationID, Kind: adj.Kind,
+		Delta: after - current, QuantityAfter: after, Reason: adj.Reason, CreatedBy: adj.UserID,
+	}
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO trakrf.stock_adjustments
+			(org_id, asset_id, location_id, kind, delta, quantity_after, reason, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at`,
+		orgID, assetID, adj.LocationID, out.Kind, out.Delta, out.QuantityAfter, out.Reason, out.CreatedBy,
+	).Scan(&out.ID, &out.CreatedAt); err != nil {
+		return nil, fmt.Errorf("record stock adjustment: %w", err)
+	}
+
+	if err := s.checkStockAlert(ctx, tx, orgID, assetID, adj.LocationID, after, minLevel); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// checkStockAlert opens, updates or resolves the stock alert for a
+// consumable at a location given its quantity and minimum level, publishing
+// stock_alert.opened / stock_alert.resolved on a change.
+func (s *Storage) checkStockAlert(ctx context.Context, tx pgx.Tx, orgID, assetID, locationID, quantity int, minLevel *int) error {
+	var openID int
+	err := tx.QueryRow(ctx, `
+		SELECT id FROM trakrf.stock_alerts
+		WHERE org_id = $1 AND asset_id = $2 AND location_id = $3 AND resolved_at IS NULL`,
+		orgID, assetID, locationID).Scan(&openID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("load open stock alert: %w", err)
+	}
+	open := err == nil
+	below := stock.BelowMin(quantity, minLevel)
+
+	switch {
+	case below && !open:
+		var id int
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.stock_alerts (org_id, asset_id, location_id, min_level, quantity)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id`, orgID, assetID, locationID, *minLevel, quantity).Scan(&id); err != nil {
+			return fmt.Errorf("open stock alert for asset %d: %w", assetID, err)
+		}
+		return s.publish(ctx, tx, events.StockAlertOpened, orgID, id)
+	case below && open:
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.stock_alerts SET quantity = $2, min_level = $3 WHERE id = $1`,
+			openID, quantity, *minLevel); err != nil {
+			return fmt.Errorf("update stock alert %d: %w", openID, err)
+		}
+	case open:
+		if _, err := tx.Exec(ctx, `
+			UPDATE trakrf.stock_alerts SET quantity = $2, resolved_at = NOW() WHERE id = $1`,
+			openID, quantity); err != nil {
+			return fmt.Errorf("resolve stock alert %d: %w", openID, err)
+		}
+		return s.publish(ctx, tx, events.StockAlertResolved, orgID, openID)
+	}
+	return nil
+}
+
+// ListStockAdjustments returns a consumable's adjustment ledger, newest
+// first. found is false when the asset is not a consumable of the org.
+func (s *Storage) ListStockAdjustments(ctx context.Context, orgID, assetID, limit int) ([]stock.Adjustment, bool, error) {
+	out := []stock.Adjustment{}
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM trakrf.consumables WHERE asset_id = $1 AND org_id = $2)`,
+			assetID, orgID).Scan(&found)
+		if err != nil || !found {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT id, asset_id, location_id, kind, delta, quantity_after, reason, created_by, created_at
+			FROM trakrf.stock_adjustments
+			WHERE org_id = $1 AND asset_id = $2
+			ORDER BY created_at DESC, id
+			LIMIT $3`, orgID, assetID, limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var a stock.Adjustment
+			if err := rows.Scan(&a.ID, &a.AssetID, &a.LocationID, &a.Kind, &a.Delta, &a.QuantityAfter,
+				&a.Reason, &a.CreatedBy, &a.CreatedAt); err != nil {
+				return err
+			}
+			out = append(out, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to list stock adjustments: %w", err)
+	}
+	return out, found, nil
+}
+
+// ListStockAlerts returns the org's stock alerts, most recently opened first.
+func (s *Storage) ListStockAlerts(ctx context.Context, orgID int, f stock.AlertFilter) ([]stock.Alert, error) {
+	out := []stock.Alert{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+			SELECT `+stockAlertColumns+`
+			FROM trakrf.stock_alerts
+			WHERE org_id = $1
+			  AND ($2::bigint IS NULL OR asset_id = $2)
+			  AND ($3::boolean IS NULL OR (resolved_at IS NULL) = $3)
+			ORDER BY opened_at DESC, id
+			LIMIT $4`, orgID, f.AssetID, f.Open, f.Limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			a, err := scanStockAlert(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stock alerts: %w", err)
+	}
+	return out, nil
+}
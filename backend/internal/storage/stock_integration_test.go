@@ -0,0 +1,90 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/stock"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestStock_AdjustmentsAlertsAndCycleCount(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	gloves := testutil.CreateTestAsset(t, pool, orgID, "GLOVES-M")
+	tracked := testutil.CreateTestAsset(t, pool, orgID, "PALLET-1")
+	var locID int
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO trakrf.locations (org_id, external_key, name)
+		VALUES ($1, 'STORE-1', 'Stores') RETURNING id`, orgID).Scan(&locID))
+
+	_, err := store.AdjustStock(ctx, orgID, gloves.ID, stock.NewAdjustment{LocationID: locID, Kind: stock.KindAdjust, Delta: 5})
+	assert.True(t, errors.Is(err, storage.ErrNotConsumable), "got %v", err)
+
+	minLevel := 10
+	c, err := store.SetConsumable(ctx, orgID, gloves.ID, stock.ConsumableRequest{MinLevel: &minLevel})
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	assert.Equal(t, stock.DefaultUnit, c.Unit)
+
+	adj, err := store.AdjustStock(ctx, orgID, gloves.ID, stock.NewAdjustment{LocationID: locID, Kind: stock.KindAdjust, Delta: 4})
+	require.NoError(t, err)
+	require.NotNil(t, adj)
+	assert.Equal(t, 4, adj.QuantityAfter)
+
+	_, err = store.AdjustStock(ctx, orgID, gloves.ID, stock.NewAdjustment{LocationID: locID, Kind: stock.KindAdjust, Delta: -5})
+	assert.True(t, errors.Is(err, storage.ErrInsufficientStock), "got %v", err)
+
+	open := true
+	alerts, err := store.ListStockAlerts(ctx, orgID, stock.AlertFilter{AssetID: &gloves.ID, Open: &open, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1, "4 is below the minimum of 10")
+	assert.Equal(t, 4, alerts[0].Quantity)
+
+	// A cycle count of 40 replaces the level and resolves the alert.
+	res, err := store.SaveInventoryScans(ctx, orgID, storage.SaveInventoryRequest{
+		LocationID: locID,
+		AssetIDs:   []int{tracked.ID},
+		Counts:     []storage.StockCount{{AssetID: gloves.ID, Quantity: 40}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Counted)
+
+	levels, found, err := store.ListStockLevels(ctx, orgID, gloves.ID)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, levels, 1)
+	assert.Equal(t, 40, levels[0].Quantity)
+	assert.False(t, levels[0].BelowMin)
+
+	alerts, err = store.ListStockAlerts(ctx, orgID, stock.AlertFilter{AssetID: &gloves.ID, Open: &open, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	ledger, found, err := store.ListStockAdjustments(ctx, orgID, gloves.ID, 10)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, ledger, 2)
+	assert.Equal(t, stock.KindCount, ledger[0].Kind)
+	assert.Equal(t, 36, ledger[0].Delta)
+
+	// Counting a non-consumable fails the whole save.
+	_, err = store.SaveInventoryScans(ctx, orgID, storage.SaveInventoryRequest{
+		LocationID: locID,
+		AssetIDs:   []int{tracked.ID},
+		Counts:     []storage.StockCount{{AssetID: tracked.ID, Quantity: 1}},
+	})
+	assert.True(t, errors.Is(err, storage.ErrNotConsumable), "got %v", err)
+}
@@ -5,11 +5,12 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/trakrf/platform/backend/internal/util/fieldcrypt"
 )
 
 // PgxPool is an interface that both *pgxpool.Pool and pgxmock implement
@@ -30,11 +31,21 @@ type Storage struct {
 	// hands tests a superuser pool for fixture setup and cleanup. It is
 	// always nil in production, so Pool() returns the real pool there.
 	accessorPool PgxPool
+	// publishEvents enables pg_notify change events on writes (see events.go).
+	// On for real databases; off for NewWithPool so mock pools need no
+	// expectation for it.
+	publishEvents bool
+	// outbox additionally queues published changes in trakrf.event_outbox
+	// for the event exporter (see outbox.go). Set by EnableEventOutbox.
+	outbox bool
+	// fieldKeys encrypts sensitive columns (see field_encryption.go). Nil
+	// stores them in plaintext. Set by EnableFieldEncryption.
+	fieldKeys *fieldcrypt.Keyring
 }
 
 // New creates a new Storage instance with an initialized connection pool.
-// It reads the PG_URL environment variable and configures the pool with
-// production-ready settings for max connections, lifetime, and health checks.
+// It reads the PG_URL environment variable and sizes the pool from
+// PoolConfigFromEnv (max/min connections, lifetimes, statement cache mode).
 func New(ctx context.Context) (*Storage, error) {
 	pgURL := os.Getenv("PG_URL")
 	if pgURL == "" {
@@ -46,11 +57,12 @@ func New(ctx context.Context) (*Storage, error) {
 		return nil, fmt.Errorf("failed to parse PG_URL: %w", err)
 	}
 
-	config.MaxConns = 25
-	config.MinConns = 5
-	config.MaxConnLifetime = time.Hour
-	config.MaxConnIdleTime = 30 * time.Minute
-	config.HealthCheckPeriod = time.Minute
+	poolCfg, err := PoolConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid pool configuration: %w", err)
+	}
+	poolCfg.apply(config)
+	config.BeforeConnect = refreshCredentials(pgURL)
 
 	pool, err := pgxpool.NewWithConfig(ctx, config)
 	if err != nil {
@@ -64,9 +76,31 @@ func New(ctx context.Context) (*Storage, error) {
 
 	slog.Info("Database connection pool initialized",
 		"max_conns", config.MaxConns,
-		"min_conns", config.MinConns)
+		"min_conns", config.MinConns,
+		"max_conn_lifetime", config.MaxConnLifetime,
+		"statement_cache_mode", config.ConnConfig.DefaultQueryExecMode.String())
 
-	return &Storage{pool: pool}, nil
+	return &Storage{pool: pool, publishEvents: true}, nil
+}
+
+// refreshCredentials picks up a PG_URL changed since boot, by a secrets
+// manager refresh (see the secrets package), for each new connection. Only
+// the user and password are taken from it: a rotated password reaches new
+// connections while pooled ones live out MaxConnLifetime, but moving to
+// another host still takes a restart.
+func refreshCredentials(initial string) func(context.Context, *pgx.ConnConfig) error {
+	return func(_ context.Context, cc *pgx.ConnConfig) error {
+		current := os.Getenv("PG_URL")
+		if current == "" || current == initial {
+			return nil
+		}
+		parsed, err := pgx.ParseConfig(current)
+		if err != nil {
+			return fmt.Errorf("PG_URL changed to an invalid connection string: %w", err)
+		}
+		cc.User, cc.Password = parsed.User, parsed.Password
+		return nil
+	}
 }
 
 // NewWithPool creates a Storage instance with an existing pool.
@@ -82,7 +116,7 @@ func NewWithPool(pool PgxPool) *Storage {
 // accessorPool is the superuser pool returned by Pool(), used by tests as the
 // privileged escape hatch for cross-org fixture setup and TRUNCATE cleanup.
 func NewForTest(queryPool, accessorPool PgxPool) *Storage {
-	return &Storage{pool: queryPool, accessorPool: accessorPool}
+	return &Storage{pool: queryPool, accessorPool: accessorPool, publishEvents: true}
 }
 
 // Close gracefully closes the database connection pool and releases all resources.
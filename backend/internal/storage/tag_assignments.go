@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/shared"
+)
+
+// GetTagHistory returns every assignment of the identifier tagID belongs to
+// (its type and normalized value), oldest first, so a tag recycled across
+// several tag rows reads as one history. tagID may be a removed tag. The
+// bool is false when tagID is not one of orgID's tags.
+func (s *Storage) GetTagHistory(ctx context.Context, orgID, tagID int) ([]shared.TagAssignment, bool, error) {
+	history := []shared.TagAssignment{}
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var tagType, normalized string
+		err := tx.QueryRow(ctx, `
+			SELECT type, normalized_value FROM trakrf.tags
+			WHERE id = $1 AND org_id = $2`, tagID, orgID).Scan(&tagType, &normalized)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+
+		rows, err := tx.Query(ctx, `
+			SELECT ta.tag_id, ta.type, ta.value,
+			       ta.asset_id, a.external_key, a.name,
+			       ta.location_id, l.external_key, l.name,
+			       ta.assigned_at, ta.unassigned_at
+			FROM trakrf.tag_assignments ta
+			LEFT JOIN trakrf.assets a ON a.id = ta.asset_id AND a.org_id = $1
+			LEFT JOIN trakrf.locations l ON l.id = ta.location_id AND l.org_id = $1
+			WHERE ta.org_id = $1 AND ta.type = $2 AND ta.normalized_value = $3
+			ORDER BY ta.assigned_at, ta.id`, orgID, tagType, normalized)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var h shared.TagAssignment
+			if err := rows.Scan(&h.TagID, &h.TagType, &h.Value,
+				&h.AssetID, &h.AssetExternalKey, &h.AssetName,
+				&h.LocationID, &h.LocationExternalKey, &h.LocationName,
+				&h.AssignedAt, &h.UnassignedAt); err != nil {
+				return err
+			}
+			history = append(history, h)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get tag history: %w", err)
+	}
+	return history, found, nil
+}
@@ -0,0 +1,51 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestGetTagHistory_RecycledTag(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	assetA := testutil.CreateTestAsset(t, pool, orgID, "AST-A")
+	assetB := testutil.CreateTestAsset(t, pool, orgID, "AST-B")
+
+	value := "0000E28011600000A1"
+	first, err := store.AddTagToAsset(ctx, orgID, assetA.ID, rfidReq(value))
+	require.NoError(t, err)
+	removed, err := store.RemoveAssetTag(ctx, orgID, assetA.ID, first.ID)
+	require.NoError(t, err)
+	require.True(t, removed)
+	// Recycled onto B, registered without the leading zeros.
+	second, err := store.AddTagToAsset(ctx, orgID, assetB.ID, rfidReq(value[4:]))
+	require.NoError(t, err)
+
+	for _, id := range []int{first.ID, second.ID} {
+		history, found, err := store.GetTagHistory(ctx, orgID, id)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Len(t, history, 2, "either tag id reads the whole history")
+		assert.Equal(t, assetA.ID, *history[0].AssetID)
+		assert.Equal(t, "AST-A", *history[0].AssetExternalKey)
+		require.NotNil(t, history[0].UnassignedAt)
+		assert.Equal(t, assetB.ID, *history[1].AssetID)
+		assert.Nil(t, history[1].UnassignedAt)
+	}
+
+	_, found, err := store.GetTagHistory(ctx, orgID+1, second.ID)
+	require.NoError(t, err)
+	assert.False(t, found, "another org's tag is not found")
+}
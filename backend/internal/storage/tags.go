@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/trakrf/platform/backend/internal/models/asset"
 	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/organization"
 	"github.com/trakrf/platform/backend/internal/models/shared"
 )
 
@@ -82,10 +84,19 @@ func (s *Storage) GetTagsByLocationID(ctx context.Context, orgID, locationID int
 	return tags, nil
 }
 
+// ErrTagTargetNotFound is returned by AddTagToAsset and AddTagToLocation
+// when the asset or location is not a live one of the caller's org.
+var ErrTagTargetNotFound = errors.New("tag target not found in this organization")
+
+// AddTagToAsset attaches a tag to the asset. The insert only happens when
+// assetID is a live asset of orgID, so a caller cannot attach a tag to
+// another org's asset by id; ErrTagTargetNotFound otherwise.
 func (s *Storage) AddTagToAsset(ctx context.Context, orgID, assetID int, req shared.TagRequest) (*shared.Tag, error) {
 	query := `
 		INSERT INTO trakrf.tags (org_id, type, value, asset_id, is_active)
-		VALUES ($1, $2, $3, $4, TRUE)
+		SELECT $1, $2, $3, id, TRUE
+		FROM trakrf.assets
+		WHERE id = $4 AND org_id = $1 AND deleted_at IS NULL
 		RETURNING id, type, value
 	`
 
@@ -98,6 +109,9 @@ func (s *Storage) AddTagToAsset(ctx context.Context, orgID, assetID int, req sha
 		)
 	})
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTagTargetNotFound
+	}
 	if err != nil {
 		return nil, s.resolveTagError(ctx, orgID, err, tagType, req.Value)
 	}
@@ -105,10 +119,14 @@ func (s *Storage) AddTagToAsset(ctx context.Context, orgID, assetID int, req sha
 	return &tag, nil
 }
 
+// AddTagToLocation attaches a tag to the location, guarded like
+// AddTagToAsset.
 func (s *Storage) AddTagToLocation(ctx context.Context, orgID, locationID int, req shared.TagRequest) (*shared.Tag, error) {
 	query := `
 		INSERT INTO trakrf.tags (org_id, type, value, location_id, is_active)
-		VALUES ($1, $2, $3, $4, TRUE)
+		SELECT $1, $2, $3, id, TRUE
+		FROM trakrf.locations
+		WHERE id = $4 AND org_id = $1 AND deleted_at IS NULL
 		RETURNING id, type, value
 	`
 
@@ -121,6 +139,9 @@ func (s *Storage) AddTagToLocation(ctx context.Context, orgID, locationID int, r
 		)
 	})
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTagTargetNotFound
+	}
 	if err != nil {
 		return nil, s.resolveTagError(ctx, orgID, err, tagType, req.Value)
 	}
@@ -221,6 +242,12 @@ func (s *Storage) GetTagByID(ctx context.Context, orgID, tagID int) (*shared.Tag
 	return &tag, nil
 }
 
+// tagCrossTypeConstraint is the constraint name raised by the
+// enforce_tag_unique_across_types trigger (migration 000042) when an org with
+// tags.unique_across_types on attaches a value already live under another
+// tag type.
+const tagCrossTypeConstraint = "tags_org_value_unique_across_types"
+
 // isTagDuplicateErr reports whether err is the (org_id, type, value)
 // partial-unique-index violation on the tags table.
 func isTagDuplicateErr(err error) bool {
@@ -230,8 +257,17 @@ func isTagDuplicateErr(err error) bool {
 	return strings.Contains(err.Error(), "duplicate key")
 }
 
+// isTagCrossTypeErr reports whether err is the cross-type uniqueness
+// violation. Unlike isTagDuplicateErr it unwraps, since the create-with-tags
+// paths see the error wrapped by the stored function call.
+func isTagCrossTypeErr(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.ConstraintName == tagCrossTypeConstraint
+}
+
 // tagConflict describes the entity a tag value is already attached to.
 type tagConflict struct {
+	TagType     string
 	EntityType  string // "asset" or "location"
 	Name        string
 	ExternalKey string
@@ -245,7 +281,8 @@ func derefStr(s *string) string {
 }
 
 // lookupTagConflict finds the live tag row colliding on (orgID, tagType,
-// value) and returns the asset or location it is attached to. Returns
+// value) — or, with otherType, on value under any type but tagType — and
+// returns the asset or location it is attached to. Returns
 // (nil, nil) when no live collision is found — e.g. the conflicting row was
 // soft-deleted between the failed INSERT and this lookup.
 //
@@ -255,23 +292,25 @@ func derefStr(s *string) string {
 // attached tags, this code path should not produce a hit for an orphan; the
 // defense in depth covers the window between deploy and the sweep migration
 // running, and any future code path that bypasses the cascade.
-func (s *Storage) lookupTagConflict(ctx context.Context, orgID int, tagType, value string) (*tagConflict, error) {
+func (s *Storage) lookupTagConflict(ctx context.Context, orgID int, tagType, value string, otherType bool) (*tagConflict, error) {
 	query := `
-		SELECT t.asset_id, t.location_id,
+		SELECT t.type, t.asset_id, t.location_id,
 		       a.name, a.external_key,
 		       l.name, l.external_key
 		  FROM trakrf.tags t
 		  LEFT JOIN trakrf.assets    a ON a.id = t.asset_id    AND a.deleted_at IS NULL
 		  LEFT JOIN trakrf.locations l ON l.id = t.location_id AND l.deleted_at IS NULL
-		 WHERE t.org_id = $1 AND t.type = $2 AND t.value = $3
+		 WHERE t.org_id = $1 AND t.value = $3
+		   AND CASE WHEN $4 THEN t.type <> $2 ELSE t.type = $2 END
 		   AND t.deleted_at IS NULL
 		 LIMIT 1
 	`
+	var foundType string
 	var assetID, locationID *int
 	var assetName, assetKey, locName, locKey *string
 	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
-		return tx.QueryRow(ctx, query, orgID, tagType, value).Scan(
-			&assetID, &locationID, &assetName, &assetKey, &locName, &locKey,
+		return tx.QueryRow(ctx, query, orgID, tagType, value, otherType).Scan(
+			&foundType, &assetID, &locationID, &assetName, &assetKey, &locName, &locKey,
 		)
 	})
 	if err != nil {
@@ -282,9 +321,9 @@ func (s *Storage) lookupTagConflict(ctx context.Context, orgID int, tagType, val
 	}
 	switch {
 	case assetID != nil && assetName != nil:
-		return &tagConflict{EntityType: "asset", Name: derefStr(assetName), ExternalKey: derefStr(assetKey)}, nil
+		return &tagConflict{TagType: foundType, EntityType: "asset", Name: derefStr(assetName), ExternalKey: derefStr(assetKey)}, nil
 	case locationID != nil && locName != nil:
-		return &tagConflict{EntityType: "location", Name: derefStr(locName), ExternalKey: derefStr(locKey)}, nil
+		return &tagConflict{TagType: foundType, EntityType: "location", Name: derefStr(locName), ExternalKey: derefStr(locKey)}, nil
 	default:
 		// Tag row exists but parent is soft-deleted (or no parent). Fall back
 		// to the generic "already exists" message via resolveTagError — we
@@ -299,12 +338,23 @@ func (s *Storage) lookupTagConflict(ctx context.Context, orgID int, tagType, val
 // into a user-facing error. For the (org, type, value) unique-violation it
 // enriches the message by naming the entity already holding the tag;
 // everything else delegates to parseTagError. The enriched message keeps the
-// "already exists" substring the HTTP handlers match to produce a 409.
+// "already exists" substring the HTTP handlers match to produce a 409. The
+// cross-type violation is enriched the same way, naming the other type.
 func (s *Storage) resolveTagError(ctx context.Context, orgID int, err error, tagType, value string) error {
+	if isTagCrossTypeErr(err) {
+		conflict, lookupErr := s.lookupTagConflict(ctx, orgID, tagType, value, true)
+		if lookupErr != nil || conflict == nil {
+			return parseTagError(err, tagType, value)
+		}
+		return fmt.Errorf(
+			"tag value %s already exists as a %s tag — it is attached to %s %q (%s); this organization requires tag values to be unique across tag types",
+			value, conflict.TagType, conflict.EntityType, conflict.Name, conflict.ExternalKey,
+		)
+	}
 	if !isTagDuplicateErr(err) {
 		return parseTagError(err, tagType, value)
 	}
-	conflict, lookupErr := s.lookupTagConflict(ctx, orgID, tagType, value)
+	conflict, lookupErr := s.lookupTagConflict(ctx, orgID, tagType, value, false)
 	if lookupErr != nil || conflict == nil {
 		return parseTagError(err, tagType, value) // generic fallback
 	}
@@ -319,8 +369,12 @@ func parseTagError(err error, tagType, value string) error {
 		switch pgErr.ConstraintName {
 		case "tags_org_id_type_value_unique":
 			return fmt.Errorf("tag %s:%s already exists", tagType, value)
+		case tagCrossTypeConstraint:
+			return fmt.Errorf("tag value %s already exists under another tag type", value)
 		case "tag_target":
 			return fmt.Errorf("tag must be linked to exactly one asset or location")
+		case "tags_asset_org_fkey", "tags_location_org_fkey":
+			return ErrTagTargetNotFound
 		}
 	}
 
@@ -450,6 +504,7 @@ func normalizeEPC(epc string) string {
 // Returns a map of value -> LookupResult (nil if not found)
 // Note: Comparison is done with leading zeros stripped (normalized) to handle
 // cases where scanner returns EPCs with different leading zero counts than stored.
+// Tags, assets and locations outside their validity window do not resolve.
 func (s *Storage) LookupByTagValues(ctx context.Context, orgID int, tagType string, values []string) (map[string]*LookupResult, error) {
 	if len(values) == 0 {
 		return make(map[string]*LookupResult), nil
@@ -470,8 +525,9 @@ func (s *Storage) LookupByTagValues(ctx context.Context, orgID int, tagType stri
 	// Query using LTRIM to normalize stored values for comparison
 	query := `
 		SELECT value, asset_id, location_id
-		FROM trakrf.tags
+		FROM trakrf.tags i
 		WHERE org_id = $1 AND type = $2 AND LTRIM(value, '0') = ANY($3) AND deleted_at IS NULL
+		  AND ` + temporallyEffective("i") + `
 	`
 
 	// Collect tag data with normalized value for mapping
@@ -541,12 +597,13 @@ func (s *Storage) LookupByTagValues(ctx context.Context, orgID int, tagType stri
 	}
 
 	// Build result map keyed by ORIGINAL input values
+	now := time.Now()
 	result := make(map[string]*LookupResult)
 	for _, row := range tagRows {
 		var lookupResult *LookupResult
 
 		if row.assetID != nil {
-			if a, ok := assetMap[*row.assetID]; ok {
+			if a, ok := assetMap[*row.assetID]; ok && shared.EffectiveAt(a.ValidFrom, a.ValidTo, now) {
 				lookupResult = &LookupResult{
 					EntityType: "asset",
 					EntityID:   *row.assetID,
@@ -554,7 +611,7 @@ func (s *Storage) LookupByTagValues(ctx context.Context, orgID int, tagType stri
 				}
 			}
 		} else if row.locationID != nil {
-			if loc, ok := locationMap[*row.locationID]; ok {
+			if loc, ok := locationMap[*row.locationID]; ok && shared.EffectiveAt(loc.ValidFrom, loc.ValidTo, now) {
 				lookupResult = &LookupResult{
 					EntityType: "location",
 					EntityID:   *row.locationID,
@@ -576,13 +633,15 @@ func (s *Storage) LookupByTagValues(ctx context.Context, orgID int, tagType stri
 
 // LookupByTagValue finds an asset or location by its tag value
 // Note: Comparison is done with leading zeros stripped (normalized)
+// Tags, assets and locations outside their validity window do not resolve.
 func (s *Storage) LookupByTagValue(ctx context.Context, orgID int, tagType, value string) (*LookupResult, error) {
 	normalizedValue := normalizeEPC(value)
 
 	query := `
 		SELECT asset_id, location_id
-		FROM trakrf.tags
+		FROM trakrf.tags i
 		WHERE org_id = $1 AND type = $2 AND LTRIM(value, '0') = $3 AND deleted_at IS NULL
+		  AND ` + temporallyEffective("i") + `
 	`
 
 	var assetID, locationID *int
@@ -610,6 +669,9 @@ func (s *Storage) LookupByTagValue(ctx context.Context, orgID int, tagType, valu
 		if err != nil {
 			return nil, err
 		}
+		if a == nil || !shared.EffectiveAt(a.ValidFrom, a.ValidTo, time.Now()) {
+			return nil, nil
+		}
 		return &LookupResult{EntityType: "asset", EntityID: *assetID, Asset: a}, nil
 	}
 
@@ -618,8 +680,79 @@ func (s *Storage) LookupByTagValue(ctx context.Context, orgID int, tagType, valu
 		if err != nil {
 			return nil, err
 		}
+		if loc == nil || !shared.EffectiveAt(loc.ValidFrom, loc.ValidTo, time.Now()) {
+			return nil, nil
+		}
 		return &LookupResult{EntityType: "location", EntityID: *locationID, Location: loc}, nil
 	}
 
 	return nil, nil
 }
+
+// ErrTagValuesSharedAcrossTypes is returned by UpdateTagSettings when turning
+// on unique_across_types while live tags already share a value across types.
+var ErrTagValuesSharedAcrossTypes = errors.New("tag values are already in use under more than one tag type")
+
+// GetTagSettings returns the org's tag settings (zero value when unset), or
+// nil when the org does not exist.
+func (s *Storage) GetTagSettings(ctx context.Context, orgID int) (*organization.TagSettings, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'tags' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag settings: %w", err)
+	}
+	var ts organization.TagSettings
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &ts); err != nil {
+			return nil, fmt.Errorf("failed to decode tag settings: %w", err)
+		}
+	}
+	return &ts, nil
+}
+
+// UpdateTagSettings replaces metadata.tags with ts. Other metadata keys are
+// preserved. Turning unique_across_types on fails with
+// ErrTagValuesSharedAcrossTypes (wrapped, naming up to five of the values)
+// while existing live tags would violate it; the trigger only guards new
+// writes, so they must be cleaned up first.
+func (s *Storage) UpdateTagSettings(ctx context.Context, orgID int, ts organization.TagSettings) error {
+	blob, err := json.Marshal(ts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tag settings: %w", err)
+	}
+	return s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		if ts.UniqueAcrossTypes {
+			var values []string
+			err := tx.QueryRow(ctx, `
+				SELECT COALESCE(array_agg(value ORDER BY value), '{}') FROM (
+					SELECT value FROM trakrf.tags
+					WHERE org_id = $1 AND deleted_at IS NULL
+					GROUP BY value HAVING count(DISTINCT type) > 1
+					ORDER BY value LIMIT 5
+				) v`, orgID).Scan(&values)
+			if err != nil {
+				return fmt.Errorf("failed to check tag values: %w", err)
+			}
+			if len(values) > 0 {
+				return fmt.Errorf("%w: %s", ErrTagValuesSharedAcrossTypes, strings.Join(values, ", "))
+			}
+		}
+		result, err := tx.Exec(ctx, `
+			UPDATE trakrf.organizations
+			SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{tags}', $2::jsonb, true),
+			    updated_at = NOW()
+			WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+		if err != nil {
+			return fmt.Errorf("failed to update tag settings: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("organization not found")
+		}
+		return nil
+	})
+}
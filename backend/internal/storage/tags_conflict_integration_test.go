@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/storage"
 	"github.com/trakrf/platform/backend/internal/testutil"
 )
 
@@ -193,3 +194,38 @@ func TestAddTag_OrphanParentDoesNotLeakName(t *testing.T) {
 	assert.NotContains(t, err.Error(), "attached to asset",
 		"must fall back to the generic message, not the enriched one")
 }
+
+func TestAddTag_OtherOrgTargetRefused(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	var otherOrg int
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO trakrf.organizations (name, identifier, is_active)
+		VALUES ('Other Org', 'other-org', true) RETURNING id`).Scan(&otherOrg))
+	foreignAsset := testutil.CreateTestAsset(t, pool, otherOrg, "AST-FOREIGN")
+	foreignLoc := seedLocation(t, pool, otherOrg, "LOC-FOREIGN", "Foreign dock")
+
+	_, err := store.AddTagToAsset(ctx, orgID, foreignAsset.ID, rfidReq("E2000000FOREIGN01"))
+	assert.ErrorIs(t, err, storage.ErrTagTargetNotFound)
+	_, err = store.AddTagToLocation(ctx, orgID, foreignLoc, rfidReq("E2000000FOREIGN02"))
+	assert.ErrorIs(t, err, storage.ErrTagTargetNotFound)
+
+	// The database refuses it too, whatever path the write takes.
+	_, err = pool.Exec(ctx, `
+		INSERT INTO trakrf.tags (org_id, type, value, asset_id)
+		VALUES ($1, 'rfid', 'E2000000FOREIGN03', $2)`, orgID, foreignAsset.ID)
+	assert.ErrorContains(t, err, "tags_asset_org_fkey")
+	_, err = pool.Exec(ctx, `
+		INSERT INTO trakrf.tags (org_id, type, value, location_id)
+		VALUES ($1, 'rfid', 'E2000000FOREIGN04', $2)`, orgID, foreignLoc)
+	assert.ErrorContains(t, err, "tags_location_org_fkey")
+
+	var n int
+	require.NoError(t, pool.QueryRow(ctx,
+		`SELECT count(*) FROM trakrf.tags WHERE value LIKE 'E2000000FOREIGN%'`).Scan(&n))
+	assert.Zero(t, n)
+}
@@ -222,6 +222,34 @@ func TestAddTagToAsset_Duplicate(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestAddTagToAsset_OtherOrgAsset(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	storage := &Storage{pool: mock}
+
+	orgID := 1
+	assetID := 10 // lives in another org, so the guarded insert selects nothing
+	req := shared.TagRequest{
+		TagType: strPtr("rfid"),
+		Value:   "E20000009999",
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET LOCAL app.current_org_id = 1`).WillReturnResult(pgxmock.NewResult("SET", 0))
+	mock.ExpectQuery(`INSERT INTO trakrf.tags[\s\S]+FROM trakrf.assets[\s\S]+org_id = \$1`).
+		WithArgs(orgID, req.GetType(), req.Value, assetID).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "type", "value"}))
+	mock.ExpectRollback()
+
+	result, err := storage.AddTagToAsset(context.Background(), orgID, assetID, req)
+
+	assert.ErrorIs(t, err, ErrTagTargetNotFound)
+	assert.Nil(t, result)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestAddTagToLocation(t *testing.T) {
 	mock, err := pgxmock.NewPool()
 	require.NoError(t, err)
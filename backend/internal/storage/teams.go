@@ -0,0 +1,404 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/trakrf/platform/backend/internal/models/team"
+)
+
+const teamColumns = `
+	t.id, t.name, t.description, t.created_at, t.updated_at,
+	(SELECT count(*) FROM trakrf.team_members m WHERE m.team_id = t.id)
+`
+
+func scanTeam(row pgx.Row) (*team.Team, error) {
+	var t team.Team
+	if err := row.Scan(&t.ID, &t.Name, &t.Description, &t.CreatedAt, &t.UpdatedAt, &t.MemberCount); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// parseTeamError maps the case-insensitive name uniqueness violation to the
+// "already exists" error handlers translate to 409.
+func parseTeamError(err error, name string) error {
+	if strings.Contains(err.Error(), "idx_teams_org_name") {
+		return fmt.Errorf("team with name %s already exists", name)
+	}
+	return err
+}
+
+// ListTeams returns the org's teams ordered by name.
+func (s *Storage) ListTeams(ctx context.Context, orgID int) ([]team.Team, error) {
+	teams := []team.Team{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `SELECT `+teamColumns+`
+			FROM trakrf.teams t
+			WHERE t.org_id = $1
+			ORDER BY lower(t.name), t.id`, orgID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			t, err := scanTeam(rows)
+			if err != nil {
+				return err
+			}
+			teams = append(teams, *t)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	return teams, nil
+}
+
+// GetTeam returns the team with its roster and assignment counts, or nil when
+// the team does not exist in the org.
+func (s *Storage) GetTeam(ctx context.Context, orgID, teamID int) (*team.TeamWithMembers, error) {
+	var out *team.TeamWithMembers
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		t, err := scanTeam(tx.QueryRow(ctx, `SELECT `+teamColumns+`
+			FROM trakrf.teams t
+			WHERE t.id = $1 AND t.org_id = $2`, teamID, orgID))
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		tw := team.TeamWithMembers{Team: *t, Members: []team.Member{}}
+
+		if err := tx.QueryRow(ctx, `
+			SELECT
+				(SELECT count(*) FROM trakrf.assets WHERE org_id = $1 AND team_id = $2 AND deleted_at IS NULL),
+				(SELECT count(*) FROM trakrf.locations WHERE org_id = $1 AND team_id = $2 AND deleted_at IS NULL)`,
+			orgID, teamID).Scan(&tw.AssetCount, &tw.LocationCount); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+			SELECT m.user_id, u.email, u.name, m.role, m.created_at
+			FROM trakrf.team_members m
+			JOIN trakrf.users u ON u.id = m.user_id
+			WHERE m.team_id = $1
+			ORDER BY lower(u.name), m.user_id`, teamID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var m team.Member
+			if err := rows.Scan(&m.UserID, &m.Email, &m.Name, &m.Role, &m.JoinedAt); err != nil {
+				return err
+			}
+			tw.Members = append(tw.Members, m)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		out = &tw
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	return out, nil
+}
+
+// CreateTeam inserts a team. A name already used in the org (case-insensitive)
+// returns an "already exists" error.
+func (s *Storage) CreateTeam(ctx context.Context, orgID int, req team.CreateTeamRequest) (*team.Team, error) {
+	var out *team.Team
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var id int
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.teams (org_id, name, description)
+			VALUES ($1, $2, $3)
+			RETURNING id`, orgID, req.Name, req.Description).Scan(&id); err != nil {
+			return err
+		}
+		t, err := scanTeam(tx.QueryRow(ctx, `SELECT `+teamColumns+`
+			FROM trakrf.teams t WHERE t.id = $1`, id))
+		out = t
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create team: %w", parseTeamError(err, req.Name))
+	}
+	return out, nil
+}
+
+// UpdateTeam applies the non-nil fields of req. Returns nil when the team
+// does not exist in the org.
+func (s *Storage) UpdateTeam(ctx context.Context, orgID, teamID int, req team.UpdateTeamRequest) (*team.Team, error) {
+	var out *team.Team
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var id int
+		err := tx.QueryRow(ctx, `
+			UPDATE trakrf.teams
+			SET name = COALESCE($3, name),
+			    description = COALESCE($4, description)
+			WHERE id = $1 AND org_id = $2
+			RETURNING id`, teamID, orgID, req.Name, req.Description).Scan(&id)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		t, err := scanTeam(tx.QueryRow(ctx, `SELECT `+teamColumns+`
+			FROM trakrf.teams t WHERE t.id = $1`, id))
+		out = t
+		return err
+	})
+	if err != nil {
+		name := ""
+		if req.Name != nil {
+			name = *req.Name
+		}
+		return nil, fmt.Errorf("failed to update team: %w", parseTeamError(err, name))
+	}
+	return out, nil
+}
+
+// DeleteTeam removes a team and its memberships; assigned assets and
+// locations become unassigned. Returns false when the team does not exist.
+func (s *Storage) DeleteTeam(ctx context.Context, orgID, teamID int) (bool, error) {
+	var deleted bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `DELETE FROM trakrf.teams WHERE id = $1 AND org_id = $2`, teamID, orgID)
+		deleted = ct.RowsAffected() > 0
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete team: %w", err)
+	}
+	return deleted, nil
+}
+
+// SetTeamMember adds userID to the team with role, or changes the role of an
+// existing member. Returns false when the team does not exist in the org.
+// The caller verifies org membership of userID.
+func (s *Storage) SetTeamMember(ctx context.Context, orgID, teamID, userID int, role string) (bool, error) {
+	var ok bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `
+			INSERT INTO trakrf.team_members (org_id, team_id, user_id, role)
+			SELECT t.org_id, t.id, $3, $4
+			FROM trakrf.teams t
+			WHERE t.id = $1 AND t.org_id = $2
+			ON CONFLICT (team_id, user_id) DO UPDATE SET role = EXCLUDED.role`,
+			teamID, orgID, userID, role)
+		ok = ct.RowsAffected() > 0
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to set team member: %w", err)
+	}
+	return ok, nil
+}
+
+// RemoveTeamMember removes userID from the team. Returns false when the user
+// was not a member.
+func (s *Storage) RemoveTeamMember(ctx context.Context, orgID, teamID, userID int) (bool, error) {
+	var removed bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `
+			DELETE FROM trakrf.team_members
+			WHERE team_id = $1 AND org_id = $2 AND user_id = $3`, teamID, orgID, userID)
+		removed = ct.RowsAffected() > 0
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to remove team member: %w", err)
+	}
+	return removed, nil
+}
+
+// GetTeamMemberRole returns userID's role on the team, or "" when the user is
+// not a member.
+func (s *Storage) GetTeamMemberRole(ctx context.Context, orgID, teamID, userID int) (string, error) {
+	var role string
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `
+			SELECT role FROM trakrf.team_members
+			WHERE team_id = $1 AND org_id = $2 AND user_id = $3`, teamID, orgID, userID).Scan(&role)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get team member role: %w", err)
+	}
+	return role, nil
+}
+
+// teamAssignable names the tables a team can own rows of. The value is
+// interpolated into SQL, so it must only ever come from this map.
+var teamAssignable = map[string]string{
+	"asset":    "trakrf.assets",
+	"location": "trakrf.locations",
+}
+
+// assignToTeam sets team_id on a live row of kind. Reassigning from another
+// team is allowed (a row belongs to at most one team). Returns false when the
+// team or the row does not exist in the org.
+func (s *Storage) assignToTeam(ctx context.Context, kind string, orgID, teamID, id int) (bool, error) {
+	var ok bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `
+			UPDATE `+teamAssignable[kind]+` r
+			SET team_id = t.id, updated_at = NOW()
+			FROM trakrf.teams t
+			WHERE t.id = $1 AND t.org_id = $2
+			  AND r.id = $3 AND r.org_id = $2 AND r.deleted_at IS NULL`, teamID, orgID, id)
+		ok = ct.RowsAffected() > 0
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to assign %s to team: %w", kind, err)
+	}
+	return ok, nil
+}
+
+// unassignFromTeam clears team_id on a row currently assigned to teamID.
+// Returns false when the row is not assigned to that team.
+func (s *Storage) unassignFromTeam(ctx context.Context, kind string, orgID, teamID, id int) (bool, error) {
+	var ok bool
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `
+			UPDATE `+teamAssignable[kind]+`
+			SET team_id = NULL, updated_at = NOW()
+			WHERE id = $3 AND org_id = $2 AND team_id = $1 AND deleted_at IS NULL`, teamID, orgID, id)
+		ok = ct.RowsAffected() > 0
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to unassign %s from team: %w", kind, err)
+	}
+	return ok, nil
+}
+
+func (s *Storage) AssignAssetToTeam(ctx context.Context, orgID, teamID, assetID int) (bool, error) {
+	return s.assignToTeam(ctx, "asset", orgID, teamID, assetID)
+}
+
+func (s *Storage) UnassignAssetFromTeam(ctx context.Context, orgID, teamID, assetID int) (bool, error) {
+	return s.unassignFromTeam(ctx, "asset", orgID, teamID, assetID)
+}
+
+func (s *Storage) AssignLocationToTeam(ctx context.Context, orgID, teamID, locationID int) (bool, error) {
+	return s.assignToTeam(ctx, "location", orgID, teamID, locationID)
+}
+
+func (s *Storage) UnassignLocationFromTeam(ctx context.Context, orgID, teamID, locationID int) (bool, error) {
+	return s.unassignFromTeam(ctx, "location", orgID, teamID, locationID)
+}
+
+// teamIDOf returns the team_id of a row of kind (nil when unassigned or
+// missing). Used for strict-mode checks on by-id routes; existence is the
+// caller's concern.
+func (s *Storage) teamIDOf(ctx context.Context, kind string, orgID, id int) (*int, error) {
+	var teamID *int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, `SELECT team_id FROM `+teamAssignable[kind]+`
+			WHERE id = $1 AND org_id = $2`, id, orgID).Scan(&teamID)
+		if err == pgx.ErrNoRows {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s team: %w", kind, err)
+	}
+	return teamID, nil
+}
+
+func (s *Storage) GetAssetTeamID(ctx context.Context, orgID, assetID int) (*int, error) {
+	return s.teamIDOf(ctx, "asset", orgID, assetID)
+}
+
+func (s *Storage) GetLocationTeamID(ctx context.Context, orgID, locationID int) (*int, error) {
+	return s.teamIDOf(ctx, "location", orgID, locationID)
+}
+
+// GetTeamSettings returns the org's team settings (zero value when unset).
+func (s *Storage) GetTeamSettings(ctx context.Context, orgID int) (*team.Settings, error) {
+	var blob []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT metadata->'teams' FROM trakrf.organizations
+		WHERE id = $1 AND deleted_at IS NULL`, orgID).Scan(&blob)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team settings: %w", err)
+	}
+	var ts team.Settings
+	if len(blob) > 0 {
+		if err := json.Unmarshal(blob, &ts); err != nil {
+			return nil, fmt.Errorf("failed to decode team settings: %w", err)
+		}
+	}
+	return &ts, nil
+}
+
+// UpdateTeamSettings replaces metadata.teams with ts. Other metadata keys are
+// preserved.
+func (s *Storage) UpdateTeamSettings(ctx context.Context, orgID int, ts team.Settings) error {
+	blob, err := json.Marshal(ts)
+	if err != nil {
+		return fmt.Errorf("failed to marshal team settings: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.organizations
+		SET metadata = jsonb_set(COALESCE(metadata, '{}'::jsonb), '{teams}', $2::jsonb, true),
+		    updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, orgID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update team settings: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("organization not found")
+	}
+	return nil
+}
+
+// TeamScopeForUser resolves userID's team visibility in orgID. The caller is
+// restricted only when the org has strict mode on and the user is neither an
+// org admin nor a superadmin; a restricted caller sees the teams they belong
+// to.
+func (s *Storage) TeamScopeForUser(ctx context.Context, orgID, userID int) (team.Scope, error) {
+	var (
+		strict, exempt bool
+		scope          team.Scope
+	)
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, `
+			SELECT COALESCE((o.metadata->'teams'->>'strict_mode')::boolean, false),
+			       COALESCE(ou.role::text = 'admin', false) OR COALESCE(u.is_superadmin, false),
+			       ARRAY(SELECT m.team_id FROM trakrf.team_members m
+			             WHERE m.org_id = o.id AND m.user_id = $2 ORDER BY m.team_id)
+			FROM trakrf.organizations o
+			LEFT JOIN trakrf.org_users ou
+			       ON ou.org_id = o.id AND ou.user_id = $2 AND ou.deleted_at IS NULL
+			LEFT JOIN trakrf.users u ON u.id = $2 AND u.deleted_at IS NULL
+			WHERE o.id = $1`, orgID, userID).Scan(&strict, &exempt, &scope.TeamIDs)
+	})
+	if err == pgx.ErrNoRows {
+		return team.Scope{}, nil
+	}
+	if err != nil {
+		return team.Scope{}, fmt.Errorf("failed to resolve team scope: %w", err)
+	}
+	scope.Restricted = strict && !exempt
+	return scope, nil
+}
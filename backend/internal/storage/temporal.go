@@ -1,6 +1,9 @@
 package storage
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // temporallyEffective returns a SQL fragment matching rows that are currently
 // effective per the bitemporal validity columns (valid_from, valid_to).
@@ -12,8 +15,26 @@ import "fmt"
 // NULL valid_from is treated as "always-was" and NULL valid_to as "open-ended"
 // so rows with unset windows remain visible by default.
 func temporallyEffective(alias string) string {
+	return temporallyEffectiveAt(alias, "NOW()")
+}
+
+// temporallyEffectiveAt is temporallyEffective evaluated at the instant at, a
+// SQL expression such as NOW() or a bind parameter ("$5::timestamptz"). It
+// backs the as_of query parameter and scan-time validity checks.
+func temporallyEffectiveAt(alias, at string) string {
 	return fmt.Sprintf(
-		"(%[1]s.valid_from IS NULL OR %[1]s.valid_from <= NOW()) AND (%[1]s.valid_to IS NULL OR %[1]s.valid_to > NOW())",
-		alias,
+		"(%[1]s.valid_from IS NULL OR %[1]s.valid_from <= %[2]s) AND (%[1]s.valid_to IS NULL OR %[1]s.valid_to > %[2]s)",
+		alias, at,
 	)
 }
+
+// effectiveAtParam appends asOf to args and returns the SQL expression to
+// evaluate temporal validity at: the new bind parameter, or NOW() when asOf
+// is nil.
+func effectiveAtParam(asOf *time.Time, args []any) (string, []any) {
+	if asOf == nil {
+		return "NOW()", args
+	}
+	args = append(args, *asOf)
+	return fmt.Sprintf("$%d::timestamptz", len(args)), args
+}
@@ -0,0 +1,76 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/asset"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestListAssetViews_AsOf(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	// Effective from now for 24 hours.
+	a := testutil.CreateTestAsset(t, pool, orgID, "ASOF-1")
+
+	count := func(at *time.Time) int {
+		_, total, err := store.ListAssetViews(ctx, orgID, asset.ListFilter{AsOf: at, Limit: 50})
+		require.NoError(t, err)
+		return total
+	}
+	assert.Equal(t, 1, count(nil))
+	later := time.Now().Add(48 * time.Hour)
+	assert.Equal(t, 0, count(&later), "expired by then")
+	before := a.ValidFrom.Add(-time.Hour)
+	assert.Equal(t, 0, count(&before), "not yet effective")
+	within := a.ValidFrom.Add(time.Hour)
+	assert.Equal(t, 1, count(&within))
+}
+
+func TestSaveInventoryScans_RejectsOutOfValidity(t *testing.T) {
+	store, cleanup := testutil.SetupTestDB(t)
+	defer cleanup()
+	pool := store.Pool().(*pgxpool.Pool)
+	ctx := context.Background()
+
+	orgID := testutil.CreateTestAccount(t, pool)
+	live := testutil.CreateTestAsset(t, pool, orgID, "VAL-LIVE")
+	expired := testutil.CreateTestAsset(t, pool, orgID, "VAL-EXP")
+	_, err := pool.Exec(ctx, `UPDATE trakrf.assets SET valid_from = NOW() - INTERVAL '2 days', valid_to = NOW() - INTERVAL '1 day' WHERE id = $1`, expired.ID)
+	require.NoError(t, err)
+
+	var locID int
+	require.NoError(t, pool.QueryRow(ctx, `
+		INSERT INTO trakrf.locations (org_id, external_key, name, valid_from)
+		VALUES ($1, 'VAL-LOC', 'Dock', NOW() - INTERVAL '1 day') RETURNING id`, orgID).Scan(&locID))
+
+	_, err = store.SaveInventoryScans(ctx, orgID, storage.SaveInventoryRequest{LocationID: locID, AssetIDs: []int{live.ID, expired.ID}})
+	var accessErr *storage.InventoryAccessError
+	require.True(t, errors.As(err, &accessErr), "got %v", err)
+	assert.Equal(t, []int{expired.ID}, accessErr.OutOfValidityAssetIDs)
+	assert.Equal(t, "1 of 2 assets are outside their validity window", accessErr.Error())
+
+	res, err := store.SaveInventoryScans(ctx, orgID, storage.SaveInventoryRequest{LocationID: locID, AssetIDs: []int{live.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Count)
+
+	_, err = pool.Exec(ctx, `UPDATE trakrf.locations SET valid_to = NOW() - INTERVAL '1 hour' WHERE id = $1`, locID)
+	require.NoError(t, err)
+	_, err = store.SaveInventoryScans(ctx, orgID, storage.SaveInventoryRequest{LocationID: locID, AssetIDs: []int{live.ID}})
+	require.True(t, errors.As(err, &accessErr))
+	assert.Equal(t, "location_validity", accessErr.Reason)
+}
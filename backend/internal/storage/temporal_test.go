@@ -1,6 +1,9 @@
 package storage
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestTemporallyEffective(t *testing.T) {
 	tests := []struct {
@@ -34,3 +37,20 @@ func TestTemporallyEffective(t *testing.T) {
 		})
 	}
 }
+
+func TestEffectiveAtParam(t *testing.T) {
+	at, args := effectiveAtParam(nil, []any{42})
+	if at != "NOW()" || len(args) != 1 {
+		t.Fatalf("nil as_of: got %q, %d args", at, len(args))
+	}
+
+	asOf := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at, args = effectiveAtParam(&asOf, []any{42})
+	if at != "$2::timestamptz" || len(args) != 2 || args[1] != asOf {
+		t.Fatalf("as_of: got %q, %v", at, args)
+	}
+	want := "(a.valid_from IS NULL OR a.valid_from <= $2::timestamptz) AND (a.valid_to IS NULL OR a.valid_to > $2::timestamptz)"
+	if got := temporallyEffectiveAt("a", at); got != want {
+		t.Fatalf("temporallyEffectiveAt:\n  want: %s\n  got:  %s", want, got)
+	}
+}
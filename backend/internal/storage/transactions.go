@@ -2,15 +2,88 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
 )
 
+// ErrTxOrgMismatch is returned when a nested transaction asks for a different
+// RLS org than the enclosing transaction already set. Switching orgs mid-
+// transaction would leak one org's context into the other's writes, so the
+// nested call is rejected instead.
+var ErrTxOrgMismatch = errors.New("nested transaction org does not match enclosing transaction org")
+
+type (
+	txContextKey  struct{}
+	orgContextKey struct{}
+)
+
+// txState is what WithTx stores in the context it hands to its callback.
+// orgID is the RLS org in effect on tx, nil when none has been set.
+type txState struct {
+	tx    pgx.Tx
+	orgID *int
+}
+
+// TxFromContext returns the transaction opened by an enclosing WithTx or
+// WithOrgTx call, if any.
+func TxFromContext(ctx context.Context) (pgx.Tx, bool) {
+	st, ok := ctx.Value(txContextKey{}).(*txState)
+	if !ok {
+		return nil, false
+	}
+	return st.tx, true
+}
+
+// ContextWithOrgID returns a context that makes WithTx set the RLS org
+// context (app.current_org_id) automatically, as WithOrgTx does.
+func ContextWithOrgID(ctx context.Context, orgID int) context.Context {
+	return context.WithValue(ctx, orgContextKey{}, orgID)
+}
+
+// OrgIDFromContext returns the org attached by ContextWithOrgID, if any.
+func OrgIDFromContext(ctx context.Context) (int, bool) {
+	orgID, ok := ctx.Value(orgContextKey{}).(int)
+	return orgID, ok
+}
+
+// WithTx executes fn within a transaction, committing when fn returns nil and
+// rolling back otherwise. When ctx carries an org (ContextWithOrgID) the RLS
+// org context is set on the transaction before fn runs.
+//
+// Calls nest: when ctx already carries a transaction opened by WithTx or
+// WithOrgTx, fn runs inside a SAVEPOINT on that transaction instead of a new
+// one, so a failing inner step rolls back only its own writes and the outer
+// caller decides whether to continue. fn receives a context carrying the
+// active transaction; only calls made with that context join it.
+//
+// Usage:
+//
+//	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+//	    if _, err := tx.Exec(ctx, insertUser, ...); err != nil {
+//	        return err
+//	    }
+//	    return s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error { ... })
+//	})
+func (s *Storage) WithTx(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	var orgID *int
+	if id, ok := OrgIDFromContext(ctx); ok {
+		orgID = &id
+	}
+	return s.withTx(ctx, orgID, fn)
+}
+
 // WithOrgTx executes a function within a transaction with org context set for RLS.
 // The org context is set via SET LOCAL, which scopes it to this transaction only.
 // This ensures RLS policies can validate the org_id for INSERT/UPDATE operations.
 //
+// When ctx carries an enclosing WithTx transaction, fn runs in a savepoint on
+// it. The callback does not receive the derived context, so storage calls made
+// from inside fn with the caller's ctx do not join this transaction — they
+// nest only in the enclosing WithTx, if any. Use WithTx with ContextWithOrgID
+// when the callback itself needs to make nested storage calls.
+//
 // Usage:
 //
 //	var result MyType
@@ -18,19 +91,66 @@ import (
 //	    return tx.QueryRow(ctx, query, args...).Scan(&result.Field1, &result.Field2)
 //	})
 func (s *Storage) WithOrgTx(ctx context.Context, orgID int, fn func(tx pgx.Tx) error) error {
-	tx, err := s.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+	return s.withTx(ctx, &orgID, func(_ context.Context, tx pgx.Tx) error {
+		return fn(tx)
+	})
+}
+
+// withTx opens a transaction (or a savepoint when ctx already carries one),
+// sets the RLS org context when orgID is non-nil, and runs fn.
+func (s *Storage) withTx(ctx context.Context, orgID *int, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	outer, nested := ctx.Value(txContextKey{}).(*txState)
+
+	var (
+		tx  pgx.Tx
+		err error
+	)
+	if nested {
+		if orgID != nil && outer.orgID != nil && *orgID != *outer.orgID {
+			return fmt.Errorf("%w: have %d, want %d", ErrTxOrgMismatch, *outer.orgID, *orgID)
+		}
+		// pgx implements Begin on a Tx as SAVEPOINT; Commit releases it and
+		// Rollback rolls back to it.
+		tx, err = outer.tx.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to create savepoint: %w", err)
+		}
+	} else {
+		tx, err = s.pool.Begin(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
 	}
 	defer tx.Rollback(ctx)
 
-	// Set org context for RLS policies (scoped to this transaction only)
-	_, err = tx.Exec(ctx, fmt.Sprintf("SET LOCAL app.current_org_id = %d", orgID))
-	if err != nil {
-		return fmt.Errorf("failed to set org context: %w", err)
+	state := &txState{tx: tx}
+	if nested {
+		state.orgID = outer.orgID
 	}
 
-	if err := fn(tx); err != nil {
+	// SET LOCAL survives RELEASE SAVEPOINT, so an org set inside a savepoint
+	// would leak into later sibling calls on the enclosing transaction. Capture
+	// the enclosing value and restore it once the savepoint is released.
+	var restoreOrg *string
+	if orgID != nil && state.orgID == nil {
+		if nested {
+			var prev string
+			err = tx.QueryRow(ctx, "SELECT COALESCE(current_setting('app.current_org_id', true), '')").Scan(&prev)
+			if err != nil {
+				return fmt.Errorf("failed to read org context: %w", err)
+			}
+			restoreOrg = &prev
+		}
+
+		// Set org context for RLS policies (scoped to this transaction only)
+		_, err = tx.Exec(ctx, fmt.Sprintf("SET LOCAL app.current_org_id = %d", *orgID))
+		if err != nil {
+			return fmt.Errorf("failed to set org context: %w", err)
+		}
+		state.orgID = orgID
+	}
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, state), tx); err != nil {
 		return err
 	}
 
@@ -38,5 +158,12 @@ func (s *Storage) WithOrgTx(ctx context.Context, orgID int, fn func(tx pgx.Tx) e
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	if restoreOrg != nil {
+		_, err = outer.tx.Exec(ctx, "SELECT set_config('app.current_org_id', $1, true)", *restoreOrg)
+		if err != nil {
+			return fmt.Errorf("failed to restore org context: %w", err)
+		}
+	}
+
 	return nil
 }
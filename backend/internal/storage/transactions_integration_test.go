@@ -0,0 +1,150 @@
+//go:build integration
+// +build integration
+
+// WithTx / WithOrgTx nesting semantics, exercised against the RLS-enforced app
+// role so the org-context assertions mean what production means.
+
+package storage_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+const insertTxProbeLocation = `
+	INSERT INTO trakrf.locations (org_id, external_key, name, valid_from, is_active)
+	VALUES ($1, $2, $2, now(), true)`
+
+// txProbeExists reports whether a location with externalKey exists, read via
+// the superuser pool so the check is independent of the transaction under test.
+func txProbeExists(t *testing.T, db *testutil.TestDB, orgID int, externalKey string) bool {
+	t.Helper()
+	var n int
+	err := db.AdminPool.QueryRow(context.Background(),
+		`SELECT count(*) FROM trakrf.locations WHERE org_id = $1 AND external_key = $2`,
+		orgID, externalKey).Scan(&n)
+	require.NoError(t, err)
+	return n == 1
+}
+
+func TestWithTx_CommitsOuterTransaction(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := storage.ContextWithOrgID(context.Background(), orgID)
+
+	err := db.Store.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, insertTxProbeLocation, orgID, "tx-outer")
+		return err
+	})
+	require.NoError(t, err)
+	require.True(t, txProbeExists(t, db, orgID, "tx-outer"), "outer write must be committed")
+}
+
+func TestWithTx_NestedErrorRollsBackOnlySavepoint(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := storage.ContextWithOrgID(context.Background(), orgID)
+	errInner := errors.New("inner failed")
+
+	err := db.Store.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, insertTxProbeLocation, orgID, "tx-before"); err != nil {
+			return err
+		}
+
+		innerErr := db.Store.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, insertTxProbeLocation, orgID, "tx-inner"); err != nil {
+				return err
+			}
+			return errInner
+		})
+		require.ErrorIs(t, innerErr, errInner)
+
+		// The outer transaction is still usable after the savepoint rollback.
+		_, err := tx.Exec(ctx, insertTxProbeLocation, orgID, "tx-after")
+		return err
+	})
+	require.NoError(t, err)
+
+	require.True(t, txProbeExists(t, db, orgID, "tx-before"))
+	require.False(t, txProbeExists(t, db, orgID, "tx-inner"), "failed savepoint's write must be rolled back")
+	require.True(t, txProbeExists(t, db, orgID, "tx-after"))
+}
+
+func TestWithTx_OuterErrorRollsBackInnerWrites(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := storage.ContextWithOrgID(context.Background(), orgID)
+	errOuter := errors.New("outer failed")
+
+	err := db.Store.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		innerErr := db.Store.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, insertTxProbeLocation, orgID, "tx-released")
+			return err
+		})
+		require.NoError(t, innerErr)
+		return errOuter
+	})
+	require.ErrorIs(t, err, errOuter)
+	require.False(t, txProbeExists(t, db, orgID, "tx-released"),
+		"a released savepoint's writes must roll back with the outer transaction")
+}
+
+func TestWithTx_NestedWithOrgTxSetsOrgContext(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	bg := context.Background()
+
+	var locID int
+	err := db.AdminPool.QueryRow(bg, insertTxProbeLocation+` RETURNING id`, orgID, "tx-rls").Scan(&locID)
+	require.NoError(t, err)
+
+	// Outer transaction carries no org; the nested WithOrgTx must set it so
+	// the RLS policy on trakrf.locations admits the read.
+	err = db.Store.WithTx(bg, func(ctx context.Context, tx pgx.Tx) error {
+		err := db.Store.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+			var setting string
+			if err := tx.QueryRow(ctx, `SELECT current_setting('app.current_org_id')`).Scan(&setting); err != nil {
+				return err
+			}
+			require.Equal(t, strconv.Itoa(orgID), setting)
+
+			var n int
+			if err := tx.QueryRow(ctx, `SELECT count(*) FROM trakrf.locations WHERE id = $1`, locID).Scan(&n); err != nil {
+				return err
+			}
+			require.Equal(t, 1, n, "RLS must admit the row under the nested org context")
+			return nil
+		})
+		require.NoError(t, err)
+
+		// The savepoint's SET LOCAL must not leak into later sibling calls.
+		var setting string
+		err = tx.QueryRow(ctx, `SELECT COALESCE(current_setting('app.current_org_id', true), '')`).Scan(&setting)
+		require.NoError(t, err)
+		require.Empty(t, setting, "org context must be restored after the savepoint is released")
+		return nil
+	})
+	require.NoError(t, err)
+}
+
+func TestWithTx_NestedDifferentOrgRejected(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := storage.ContextWithOrgID(context.Background(), orgID)
+
+	err := db.Store.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		return db.Store.WithOrgTx(ctx, orgID+1, func(tx pgx.Tx) error {
+			t.Fatal("callback must not run under a mismatched org")
+			return nil
+		})
+	})
+	require.ErrorIs(t, err, storage.ErrTxOrgMismatch)
+}
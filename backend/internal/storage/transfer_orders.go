@@ -0,0 +1,439 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+)
+
+var (
+	// ErrTransferOrderLocationNotFound is returned when the origin or
+	// destination is not a live location of the org.
+	ErrTransferOrderLocationNotFound = errors.New("location not found")
+	// ErrTransferOrderAssetNotFound is returned when an expected asset is not
+	// a live asset of the org.
+	ErrTransferOrderAssetNotFound = errors.New("asset not found")
+	// ErrTransferOrderState is returned when the order's status does not
+	// allow the action or verification stage.
+	ErrTransferOrderState = errors.New("the transfer order's status does not allow this action")
+)
+
+const transferOrderSelect = `
+	SELECT o.id, o.reference, o.origin_location_id, o.destination_location_id, o.status, o.note,
+	       (SELECT COUNT(*) FROM trakrf.transfer_order_items i
+	        WHERE i.transfer_order_id = o.id AND i.expected),
+	       o.created_by, o.shipped_by, o.shipped_at, o.received_by, o.received_at, o.cancelled_at,
+	       o.created_at, o.updated_at, o.carrier, o.tracking_number, o.tracking_status, o.tracking_detail,
+	       o.tracking_eta, o.tracking_event_at, o.tracking_checked_at, o.tracking_error
+	FROM trakrf.transfer_orders o`
+
+func scanTransferOrder(row pgx.Row) (*transferorder.Order, error) {
+	var o transferorder.Order
+	var carrier, trackingNumber *string
+	var tr transferorder.Tracking
+	if err := row.Scan(&o.ID, &o.Reference, &o.OriginLocationID, &o.DestinationLocationID, &o.Status, &o.Note,
+		&o.ExpectedCount, &o.CreatedBy, &o.ShippedBy, &o.ShippedAt, &o.ReceivedBy, &o.ReceivedAt, &o.CancelledAt,
+		&o.CreatedAt, &o.UpdatedAt, &carrier, &trackingNumber, &tr.Status, &tr.Detail,
+		&tr.ETA, &tr.EventAt, &tr.CheckedAt, &tr.Error); err != nil {
+		return nil, err
+	}
+	if carrier != nil && trackingNumber != nil {
+		tr.Carrier, tr.TrackingNumber = *carrier, *trackingNumber
+		o.Tracking = &tr
+	}
+	return &o, nil
+}
+
+// CreateTransferOrder opens an order moving req.AssetIDs from the origin to
+// the destination location. Both locations and every asset must be live in
+// orgID.
+func (s *Storage) CreateTransferOrder(ctx context.Context, orgID, userID int, req transferorder.CreateRequest) (*transferorder.Order, error) {
+	var id int
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var locations, assets int
+		if err := tx.QueryRow(ctx, `
+			SELECT
+				(SELECT COUNT(*) FROM trakrf.locations
+				 WHERE id IN ($2, $3) AND org_id = $1 AND deleted_at IS NULL),
+				(SELECT COUNT(*) FROM trakrf.assets
+				 WHERE id = ANY($4) AND org_id = $1 AND deleted_at IS NULL)`,
+			orgID, req.OriginLocationID, req.DestinationLocationID, req.AssetIDs).Scan(&locations, &assets); err != nil {
+			return err
+		}
+		if locations < 2 {
+			return ErrTransferOrderLocationNotFound
+		}
+		if assets < len(req.AssetIDs) {
+			return ErrTransferOrderAssetNotFound
+		}
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO trakrf.transfer_orders
+			  (org_id, reference, origin_location_id, destination_location_id, note, created_by)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id`,
+			orgID, req.Reference, req.OriginLocationID, req.DestinationLocationID, req.Note, userID).Scan(&id); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO trakrf.transfer_order_items (transfer_order_id, org_id, asset_id)
+			SELECT $1, $2, unnest($3::bigint[])`, id, orgID, req.AssetIDs)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrTransferOrderLocationNotFound) || errors.Is(err, ErrTransferOrderAssetNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create transfer order: %w", err)
+	}
+	return s.GetTransferOrder(ctx, orgID, id)
+}
+
+// GetTransferOrder returns one order with its items, expected ones first, or
+// nil when it is not in orgID.
+func (s *Storage) GetTransferOrder(ctx context.Context, orgID, id int) (*transferorder.Order, error) {
+	var o *transferorder.Order
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var err error
+		o, err = scanTransferOrder(tx.QueryRow(ctx, transferOrderSelect+`
+			WHERE o.id = $1 AND o.org_id = $2`, id, orgID))
+		if errors.Is(err, pgx.ErrNoRows) {
+			o = nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, `
+			SELECT i.asset_id, a.external_key, a.name, i.expected,
+			       i.origin_scanned_at, i.destination_scanned_at
+			FROM trakrf.transfer_order_items i
+			JOIN trakrf.assets a ON a.id = i.asset_id
+			WHERE i.transfer_order_id = $1
+			ORDER BY i.expected DESC, a.external_key, i.asset_id`, id)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		o.Items = []transferorder.Item{}
+		for rows.Next() {
+			var it transferorder.Item
+			if err := rows.Scan(&it.AssetID, &it.AssetExternalKey, &it.AssetName, &it.Expected,
+				&it.OriginScannedAt, &it.DestinationScannedAt); err != nil {
+				return err
+			}
+			o.Items = append(o.Items, it)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transfer order: %w", err)
+	}
+	return o, nil
+}
+
+// ListTransferOrders returns the org's orders newest first, without items.
+func (s *Storage) ListTransferOrders(ctx context.Context, orgID int, f transferorder.ListFilter) ([]transferorder.Order, error) {
+	out := []transferorder.Order{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, transferOrderSelect+`
+			WHERE o.org_id = $1
+			  AND ($2 = '' OR o.status = $2)
+			  AND ($3 = 0 OR o.origin_location_id = $3 OR o.destination_location_id = $3)
+			ORDER BY o.created_at DESC, o.id DESC
+			LIMIT $4`, orgID, f.Status, f.LocationID, f.Limit)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			o, err := scanTransferOrder(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, *o)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transfer orders: %w", err)
+	}
+	return out, nil
+}
+
+// VerifyTransferOrder records a scan of req.EPCs at one end of the order:
+// origin scans while it is open, destination scans while it is in transit,
+// else ErrTransferOrderState. Each asset found is stamped as scanned at that
+// end; one the order did not list is added to it as unexpected. Returns nil
+// when the order is not in orgID.
+func (s *Storage) VerifyTransferOrder(ctx context.Context, orgID, id int, req transferorder.VerifyRequest) (*transferorder.VerifyResult, error) {
+	wantStatus, column := transferorder.StatusOpen, "origin_scanned_at"
+	if req.Stage == transferorder.StageDestination {
+		wantStatus, column = transferorder.StatusInTransit, "destination_scanned_at"
+	}
+	var res *transferorder.VerifyResult
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var status string
+		err := tx.QueryRow(ctx, `
+			SELECT status FROM trakrf.transfer_orders
+			WHERE id = $1 AND org_id = $2
+			FOR UPDATE`, id, orgID).Scan(&status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if status != wantStatus {
+			return ErrTransferOrderState
+		}
+
+		scans, err := resolveEPCs(ctx, tx, orgID, req.EPCs)
+		if err != nil {
+			return err
+		}
+		res = &transferorder.VerifyResult{
+			OrderID: id, Stage: req.Stage, Matched: []int{}, Extra: []int{}, UnknownEPCs: []string{},
+		}
+		seen := map[int]bool{}
+		for _, sc := range scans {
+			if sc.AssetID == 0 {
+				res.UnknownEPCs = append(res.UnknownEPCs, sc.EPC)
+				continue
+			}
+			if seen[sc.AssetID] {
+				continue
+			}
+			seen[sc.AssetID] = true
+			var expected bool
+			if err := tx.QueryRow(ctx, `
+				INSERT INTO trakrf.transfer_order_items (transfer_order_id, org_id, asset_id, expected, `+column+`)
+				VALUES ($1, $2, $3, false, NOW())
+				ON CONFLICT (transfer_order_id, asset_id) DO UPDATE
+				SET `+column+` = COALESCE(transfer_order_items.`+column+`, EXCLUDED.`+column+`)
+				RETURNING expected`, id, orgID, sc.AssetID).Scan(&expected); err != nil {
+				return err
+			}
+			if expected {
+				res.Matched = append(res.Matched, sc.AssetID)
+			} else {
+				res.Extra = append(res.Extra, sc.AssetID)
+			}
+		}
+		return tx.QueryRow(ctx, `
+			SELECT COUNT(*) FROM trakrf.transfer_order_items
+			WHERE transfer_order_id = $1 AND expected AND `+column+` IS NULL`, id).Scan(&res.Remaining)
+	})
+	if err != nil {
+		if errors.Is(err, ErrTransferOrderState) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to verify transfer order: %w", err)
+	}
+	return res, nil
+}
+
+// TransitionTransferOrder ships, receives or cancels an order. Ship needs an
+// open order, receive an in-transit one, and cancel either; otherwise it
+// returns ErrTransferOrderState. Discrepancies do not block a transition:
+// they stay on the order's report. Returns nil when the order is not in
+// orgID.
+func (s *Storage) TransitionTransferOrder(ctx context.Context, orgID, id, userID int, action string) (*transferorder.Order, error) {
+	var from []string
+	var set string
+	switch action {
+	case transferorder.ActionShip:
+		from = []string{transferorder.StatusOpen}
+		set = "status = 'in_transit', shipped_by = $3, shipped_at = NOW()"
+	case transferorder.ActionReceive:
+		from = []string{transferorder.StatusInTransit}
+		set = "status = 'received', received_by = $3, received_at = NOW(), tracking_next_check_at = NULL"
+	case transferorder.ActionCancel:
+		from = []string{transferorder.StatusOpen, transferorder.StatusInTransit}
+		set = "status = 'cancelled', cancelled_at = NOW(), tracking_next_check_at = NULL"
+	default:
+		return nil, fmt.Errorf("unknown transfer order action %q", action)
+	}
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var status string
+		err := tx.QueryRow(ctx, `
+			SELECT status FROM trakrf.transfer_orders
+			WHERE id = $1 AND org_id = $2
+			FOR UPDATE`, id, orgID).Scan(&status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+		allowed := false
+		for _, st := range from {
+			allowed = allowed || st == status
+		}
+		if !allowed {
+			return ErrTransferOrderState
+		}
+		args := []any{id, orgID}
+		if action != transferorder.ActionCancel {
+			args = append(args, userID)
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.transfer_orders SET `+set+`
+			WHERE id = $1 AND org_id = $2`, args...)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrTransferOrderState) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to %s transfer order: %w", action, err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.GetTransferOrder(ctx, orgID, id)
+}
+
+// SetTransferOrderTracking attaches a carrier and tracking number to an open
+// or in-transit order (else ErrTransferOrderState), replacing any earlier
+// ones and their polled status, and makes the order due a carrier poll. The
+// carrier code is stored lowercased. Returns nil when the order is not in
+// orgID.
+func (s *Storage) SetTransferOrderTracking(ctx context.Context, orgID, id int, req transferorder.SetTrackingRequest) (*transferorder.Order, error) {
+	found := false
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		var status string
+		err := tx.QueryRow(ctx, `
+			SELECT status FROM trakrf.transfer_orders
+			WHERE id = $1 AND org_id = $2
+			FOR UPDATE`, id, orgID).Scan(&status)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		found = true
+		if status != transferorder.StatusOpen && status != transferorder.StatusInTransit {
+			return ErrTransferOrderState
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.transfer_orders
+			SET carrier = $3, tracking_number = $4, tracking_status = NULL, tracking_detail = NULL,
+			    tracking_eta = NULL, tracking_event_at = NULL, tracking_checked_at = NULL,
+			    tracking_error = NULL, tracking_next_check_at = NOW()
+			WHERE id = $1 AND org_id = $2`,
+			id, orgID, strings.ToLower(strings.TrimSpace(req.Carrier)), strings.TrimSpace(req.TrackingNumber))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrTransferOrderState) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to set transfer order tracking: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+	return s.GetTransferOrder(ctx, orgID, id)
+}
+
+// ClearTransferOrderTracking removes an order's carrier tracking. It returns
+// false when the order is not in orgID.
+func (s *Storage) ClearTransferOrderTracking(ctx context.Context, orgID, id int) (bool, error) {
+	var n int64
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+			UPDATE trakrf.transfer_orders
+			SET carrier = NULL, tracking_number = NULL, tracking_status = NULL, tracking_detail = NULL,
+			    tracking_eta = NULL, tracking_event_at = NULL, tracking_checked_at = NULL,
+			    tracking_error = NULL, tracking_next_check_at = NULL
+			WHERE id = $1 AND org_id = $2`, id, orgID)
+		n = tag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to clear transfer order tracking: %w", err)
+	}
+	return n > 0, nil
+}
+
+// ClaimDueTransferOrderTracking leases up to limit orders due a poll by one
+// of carriers, pushing their next check out by lease so a poller that dies
+// mid-run does not leave them stuck; RecordTransferOrderTracking and
+// RecordTransferOrderTrackingError set the real next check.
+func (s *Storage) ClaimDueTransferOrderTracking(ctx context.Context, carriers []string, limit int, lease time.Duration) ([]transferorder.TrackedOrder, error) {
+	rows, err := s.pool.Query(ctx, `
+		UPDATE trakrf.transfer_orders
+		SET tracking_next_check_at = NOW() + make_interval(secs => $3)
+		WHERE id IN (
+			SELECT id FROM trakrf.transfer_orders
+			WHERE tracking_next_check_at <= NOW()
+			  AND status IN ('open', 'in_transit')
+			  AND carrier = ANY($1)
+			ORDER BY tracking_next_check_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id, org_id, carrier, tracking_number`, carriers, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim transfer order tracking: %w", err)
+	}
+	defer rows.Close()
+
+	var out []transferorder.TrackedOrder
+	for rows.Next() {
+		var o transferorder.TrackedOrder
+		if err := rows.Scan(&o.ID, &o.OrgID, &o.Carrier, &o.TrackingNumber); err != nil {
+			return nil, fmt.Errorf("scan tracked transfer order: %w", err)
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+// RecordTransferOrderTracking stores a successful poll of o and schedules
+// the next one after next, or none once the carrier reports the shipment
+// delivered. It is a no-op when the order's tracking changed since it was
+// claimed.
+func (s *Storage) RecordTransferOrderTracking(ctx context.Context, o transferorder.TrackedOrder, u transferorder.TrackingUpdate, next time.Duration) error {
+	err := s.WithOrgTx(ctx, o.OrgID, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE trakrf.transfer_orders
+			SET tracking_status = $5, tracking_detail = NULLIF($6, ''), tracking_eta = $7,
+			    tracking_event_at = $8, tracking_checked_at = NOW(), tracking_error = NULL,
+			    tracking_next_check_at = CASE WHEN $5 = 'delivered' THEN NULL
+			                                  ELSE NOW() + make_interval(secs => $9) END
+			WHERE id = $1 AND org_id = $2 AND carrier = $3 AND tracking_number = $4`,
+			o.ID, o.OrgID, o.Carrier, o.TrackingNumber, u.Status, u.Detail, u.ETA, u.EventAt, next.Seconds())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record transfer order tracking: %w", err)
+	}
+	return nil
+}
+
+// RecordTransferOrderTrackingError stores why a poll of o failed, keeping
+// the last good status, and schedules the next poll after next.
+func (s *Storage) RecordTransferOrderTrackingError(ctx context.Context, o transferorder.TrackedOrder, msg string, next time.Duration) error {
+	err := s.WithOrgTx(ctx, o.OrgID, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `
+			UPDATE trakrf.transfer_orders
+			SET tracking_error = $5, tracking_checked_at = NOW(),
+			    tracking_next_check_at = NOW() + make_interval(secs => $6)
+			WHERE id = $1 AND org_id = $2 AND carrier = $3 AND tracking_number = $4`,
+			o.ID, o.OrgID, o.Carrier, o.TrackingNumber, msg, next.Seconds())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record transfer order tracking error: %w", err)
+	}
+	return nil
+}
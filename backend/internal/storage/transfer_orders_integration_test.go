@@ -0,0 +1,207 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/transferorder"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestTransferOrders_VerifyAndDiscrepancies(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	ctx := context.Background()
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	store := db.Store
+	var userID int
+	require.NoError(t, db.AdminPool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('mover', 'mover@x', 'stub') RETURNING id`,
+	).Scan(&userID))
+
+	newLocation := func(key string) int {
+		l, err := store.CreateLocationWithTags(ctx, orgID, location.CreateLocationWithTagsRequest{
+			CreateLocationRequest: location.CreateLocationRequest{Name: key, ExternalKey: key},
+		})
+		require.NoError(t, err)
+		return l.ID
+	}
+	plant, depot := newLocation("plant"), newLocation("depot")
+	a1 := preCreateAssetWithTag(t, db, orgID, "Cage 1", "E2801160600002040000A001")
+	a2 := preCreateAssetWithTag(t, db, orgID, "Cage 2", "E2801160600002040000A002")
+	a3 := preCreateAssetWithTag(t, db, orgID, "Cage 3", "E2801160600002040000A003")
+
+	_, err := store.CreateTransferOrder(ctx, orgID, userID, transferorder.CreateRequest{
+		OriginLocationID: plant, DestinationLocationID: depot, AssetIDs: []int{a1, 999999999},
+	})
+	assert.ErrorIs(t, err, storage.ErrTransferOrderAssetNotFound)
+	_, err = store.CreateTransferOrder(ctx, orgID, userID, transferorder.CreateRequest{
+		OriginLocationID: plant, DestinationLocationID: 999999999, AssetIDs: []int{a1},
+	})
+	assert.ErrorIs(t, err, storage.ErrTransferOrderLocationNotFound)
+
+	o, err := store.CreateTransferOrder(ctx, orgID, userID, transferorder.CreateRequest{
+		OriginLocationID: plant, DestinationLocationID: depot, AssetIDs: []int{a1, a2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, transferorder.StatusOpen, o.Status)
+	assert.Equal(t, 2, o.ExpectedCount)
+	require.Len(t, o.Items, 2)
+
+	// Destination scans wait for the order to ship.
+	_, err = store.VerifyTransferOrder(ctx, orgID, o.ID, transferorder.VerifyRequest{
+		Stage: transferorder.StageDestination, EPCs: []string{"E2801160600002040000A001"},
+	})
+	assert.ErrorIs(t, err, storage.ErrTransferOrderState)
+
+	// At the plant: cage 1 plus cage 3, which is not on the order, and a tag
+	// nobody knows. Cage 1 read twice counts once.
+	res, err := store.VerifyTransferOrder(ctx, orgID, o.ID, transferorder.VerifyRequest{
+		Stage: transferorder.StageOrigin,
+		EPCs:  []string{"E2801160600002040000A001", "E2801160600002040000A003", "E2801160600002040000A001", "DEADBEEF"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{a1}, res.Matched)
+	assert.Equal(t, []int{a3}, res.Extra)
+	assert.Equal(t, []string{"DEADBEEF"}, res.UnknownEPCs)
+	assert.Equal(t, 1, res.Remaining, "cage 2 is still unscanned")
+
+	o, err = store.TransitionTransferOrder(ctx, orgID, o.ID, userID, transferorder.ActionShip)
+	require.NoError(t, err)
+	assert.Equal(t, transferorder.StatusInTransit, o.Status)
+	assert.NotNil(t, o.ShippedAt)
+	assert.Equal(t, &userID, o.ShippedBy)
+	_, err = store.TransitionTransferOrder(ctx, orgID, o.ID, userID, transferorder.ActionShip)
+	assert.ErrorIs(t, err, storage.ErrTransferOrderState)
+
+	// At the depot only cage 3 turns up.
+	res, err = store.VerifyTransferOrder(ctx, orgID, o.ID, transferorder.VerifyRequest{
+		Stage: transferorder.StageDestination, EPCs: []string{"E2801160600002040000A003"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, res.Matched)
+	assert.Equal(t, []int{a3}, res.Extra)
+	assert.Equal(t, 2, res.Remaining)
+
+	o, err = store.TransitionTransferOrder(ctx, orgID, o.ID, userID, transferorder.ActionReceive)
+	require.NoError(t, err)
+	assert.Equal(t, transferorder.StatusReceived, o.Status)
+	assert.Equal(t, 2, o.ExpectedCount, "extras do not count as expected")
+
+	r := transferorder.Discrepancies(*o)
+	itemIDs := func(items []transferorder.Item) []int {
+		out := []int{}
+		for _, it := range items {
+			out = append(out, it.AssetID)
+		}
+		return out
+	}
+	assert.Equal(t, []int{a2}, itemIDs(r.MissingAtOrigin))
+	assert.Equal(t, []int{a3}, itemIDs(r.ExtraAtOrigin))
+	assert.ElementsMatch(t, []int{a1, a2}, itemIDs(r.MissingAtDestination))
+	assert.Equal(t, []int{a3}, itemIDs(r.ExtraAtDestination))
+
+	_, err = store.TransitionTransferOrder(ctx, orgID, o.ID, userID, transferorder.ActionCancel)
+	assert.ErrorIs(t, err, storage.ErrTransferOrderState, "a received order is final")
+
+	list, err := store.ListTransferOrders(ctx, orgID, transferorder.ListFilter{LocationID: depot, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Empty(t, list[0].Items)
+
+	missing, err := store.GetTransferOrder(ctx, orgID, o.ID+1)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestTransferOrders_CarrierTracking(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	ctx := context.Background()
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	store := db.Store
+	var userID int
+	require.NoError(t, db.AdminPool.QueryRow(ctx,
+		`INSERT INTO trakrf.users (name, email, password_hash) VALUES ('carrier', 'carrier@x', 'stub') RETURNING id`,
+	).Scan(&userID))
+
+	var locs []int
+	for _, key := range []string{"hub", "branch"} {
+		l, err := store.CreateLocationWithTags(ctx, orgID, location.CreateLocationWithTagsRequest{
+			CreateLocationRequest: location.CreateLocationRequest{Name: key, ExternalKey: key},
+		})
+		require.NoError(t, err)
+		locs = append(locs, l.ID)
+	}
+	a := preCreateAssetWithTag(t, db, orgID, "Crate", "E2801160600002040000B001")
+	o, err := store.CreateTransferOrder(ctx, orgID, userID, transferorder.CreateRequest{
+		OriginLocationID: locs[0], DestinationLocationID: locs[1], AssetIDs: []int{a},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, o.Tracking)
+
+	o, err = store.SetTransferOrderTracking(ctx, orgID, o.ID, transferorder.SetTrackingRequest{
+		Carrier: " DHL ", TrackingNumber: "00340434292135100186",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, o.Tracking)
+	assert.Equal(t, "dhl", o.Tracking.Carrier)
+	assert.Nil(t, o.Tracking.Status, "not polled yet")
+
+	// Only carriers with a tracker are claimed, and a claimed order is leased.
+	due, err := store.ClaimDueTransferOrderTracking(ctx, []string{"ups"}, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = store.ClaimDueTransferOrderTracking(ctx, []string{"dhl"}, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, o.ID, due[0].ID)
+	again, err := store.ClaimDueTransferOrderTracking(ctx, []string{"dhl"}, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again)
+
+	require.NoError(t, store.RecordTransferOrderTrackingError(ctx, due[0], "dhl: 503", 0))
+	eta := time.Date(2026, 5, 3, 18, 0, 0, 0, time.UTC)
+	require.NoError(t, store.RecordTransferOrderTracking(ctx, due[0], transferorder.TrackingUpdate{
+		Status: transferorder.TrackingInTransit, Detail: "Arrived at hub", ETA: &eta,
+	}, 0))
+	o, err = store.GetTransferOrder(ctx, orgID, o.ID)
+	require.NoError(t, err)
+	require.NotNil(t, o.Tracking.Status)
+	assert.Equal(t, transferorder.TrackingInTransit, *o.Tracking.Status)
+	assert.Equal(t, eta, o.Tracking.ETA.UTC())
+	assert.Nil(t, o.Tracking.Error, "a good poll clears the last error")
+	assert.NotNil(t, o.Tracking.CheckedAt)
+	assert.Equal(t, transferorder.StatusOpen, o.Status, "tracking does not move the order")
+
+	// Delivered stops polling.
+	due, err = store.ClaimDueTransferOrderTracking(ctx, []string{"dhl"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.NoError(t, store.RecordTransferOrderTracking(ctx, due[0], transferorder.TrackingUpdate{
+		Status: transferorder.TrackingDelivered,
+	}, 0))
+	due, err = store.ClaimDueTransferOrderTracking(ctx, []string{"dhl"}, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	ok, err := store.ClearTransferOrderTracking(ctx, orgID, o.ID)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	o, err = store.GetTransferOrder(ctx, orgID, o.ID)
+	require.NoError(t, err)
+	assert.Nil(t, o.Tracking)
+
+	_, err = store.TransitionTransferOrder(ctx, orgID, o.ID, userID, transferorder.ActionCancel)
+	require.NoError(t, err)
+	_, err = store.SetTransferOrderTracking(ctx, orgID, o.ID, transferorder.SetTrackingRequest{
+		Carrier: "dhl", TrackingNumber: "1",
+	})
+	assert.ErrorIs(t, err, storage.ErrTransferOrderState)
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -10,16 +11,17 @@ import (
 	"github.com/trakrf/platform/backend/internal/models/user"
 )
 
-// ListUsers retrieves a paginated list of active users ordered by creation date.
-func (s *Storage) ListUsers(ctx context.Context, limit, offset int) ([]user.User, int, error) {
-	query := `
+// ListUsers retrieves a paginated list of active users ordered by sorts,
+// newest first when sorts is empty.
+func (s *Storage) ListUsers(ctx context.Context, limit, offset int, sorts []user.ListSort) ([]user.User, int, error) {
+	query := fmt.Sprintf(`
 		SELECT id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at,
-		       is_superadmin, last_org_id
+		       is_superadmin, last_org_id, avatar_url
 		FROM trakrf.users
 		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
+		ORDER BY %s
 		LIMIT $1 OFFSET $2
-	`
+	`, buildUsersOrderBy(sorts))
 
 	rows, err := s.pool.Query(ctx, query, limit, offset)
 	if err != nil {
@@ -32,7 +34,7 @@ func (s *Storage) ListUsers(ctx context.Context, limit, offset int) ([]user.User
 		var usr user.User
 		err := rows.Scan(&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
 			&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt,
-			&usr.IsSuperadmin, &usr.LastOrgID)
+			&usr.IsSuperadmin, &usr.LastOrgID, &usr.AvatarURL)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan user: %w", err)
 		}
@@ -48,6 +50,24 @@ func (s *Storage) ListUsers(ctx context.Context, limit, offset int) ([]user.User
 	return users, total, nil
 }
 
+// buildUsersOrderBy renders sorts, whose fields the handler allowlisted
+// against user.ListSorts. id breaks ties so pages never overlap.
+func buildUsersOrderBy(sorts []user.ListSort) string {
+	if len(sorts) == 0 {
+		return "created_at DESC, id DESC"
+	}
+	out := make([]string, 0, len(sorts)+1)
+	for _, s := range sorts {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		// NULLS LAST keeps never-logged-in users at the end either way.
+		out = append(out, s.Field+" "+dir+" NULLS LAST")
+	}
+	return strings.Join(append(out, "id ASC"), ", ")
+}
+
 // ListSuperadmins retrieves all active (non-deleted) superadmin users across
 // all orgs, ordered by email. Used for system-wide operator notifications
 // (e.g. self-service trial signup alerts, TRA-967). The users table is not
@@ -55,7 +75,7 @@ func (s *Storage) ListUsers(ctx context.Context, limit, offset int) ([]user.User
 func (s *Storage) ListSuperadmins(ctx context.Context) ([]user.User, error) {
 	query := `
 		SELECT id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at,
-		       is_superadmin, last_org_id
+		       is_superadmin, last_org_id, avatar_url
 		FROM trakrf.users
 		WHERE is_superadmin = true AND deleted_at IS NULL
 		ORDER BY email ASC
@@ -72,7 +92,7 @@ func (s *Storage) ListSuperadmins(ctx context.Context) ([]user.User, error) {
 		var usr user.User
 		err := rows.Scan(&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
 			&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt,
-			&usr.IsSuperadmin, &usr.LastOrgID)
+			&usr.IsSuperadmin, &usr.LastOrgID, &usr.AvatarURL)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan superadmin: %w", err)
 		}
@@ -89,7 +109,7 @@ func (s *Storage) ListSuperadmins(ctx context.Context) ([]user.User, error) {
 func (s *Storage) GetUserByID(ctx context.Context, id int) (*user.User, error) {
 	query := `
 		SELECT id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at,
-		       is_superadmin, last_org_id
+		       is_superadmin, last_org_id, avatar_url
 		FROM trakrf.users
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -98,7 +118,7 @@ func (s *Storage) GetUserByID(ctx context.Context, id int) (*user.User, error) {
 	err := s.pool.QueryRow(ctx, query, id).Scan(
 		&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
 		&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt,
-		&usr.IsSuperadmin, &usr.LastOrgID)
+		&usr.IsSuperadmin, &usr.LastOrgID, &usr.AvatarURL)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -114,7 +134,7 @@ func (s *Storage) GetUserByID(ctx context.Context, id int) (*user.User, error) {
 func (s *Storage) GetUserByEmail(ctx context.Context, email string) (*user.User, error) {
 	query := `
 		SELECT id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at,
-		       is_superadmin, last_org_id
+		       is_superadmin, last_org_id, avatar_url
 		FROM trakrf.users
 		WHERE email = $1 AND deleted_at IS NULL
 	`
@@ -123,7 +143,7 @@ func (s *Storage) GetUserByEmail(ctx context.Context, email string) (*user.User,
 	err := s.pool.QueryRow(ctx, query, email).Scan(
 		&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
 		&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt,
-		&usr.IsSuperadmin, &usr.LastOrgID)
+		&usr.IsSuperadmin, &usr.LastOrgID, &usr.AvatarURL)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -141,14 +161,14 @@ func (s *Storage) CreateUser(ctx context.Context, request user.CreateUserRequest
 		INSERT INTO trakrf.users (email, name, password_hash)
 		VALUES ($1, $2, $3)
 		RETURNING id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at,
-		          is_superadmin, last_org_id
+		          is_superadmin, last_org_id, avatar_url
 	`
 
 	var usr user.User
 	err := s.pool.QueryRow(ctx, query, request.Email, request.Name, request.PasswordHash).Scan(
 		&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
 		&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt,
-		&usr.IsSuperadmin, &usr.LastOrgID)
+		&usr.IsSuperadmin, &usr.LastOrgID, &usr.AvatarURL)
 
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
@@ -186,14 +206,14 @@ func (s *Storage) UpdateUser(ctx context.Context, id int, request user.UpdateUse
 		SET %s, updated_at = NOW()
 		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING id, email, name, password_hash, last_login_at, settings, metadata, created_at, updated_at,
-		          is_superadmin, last_org_id
+		          is_superadmin, last_org_id, avatar_url
 	`, strings.Join(updates, ", "))
 
 	var usr user.User
 	err := s.pool.QueryRow(ctx, query, args...).Scan(
 		&usr.ID, &usr.Email, &usr.Name, &usr.PasswordHash, &usr.LastLoginAt,
 		&usr.Settings, &usr.Metadata, &usr.CreatedAt, &usr.UpdatedAt,
-		&usr.IsSuperadmin, &usr.LastOrgID)
+		&usr.IsSuperadmin, &usr.LastOrgID, &usr.AvatarURL)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -205,6 +225,59 @@ func (s *Storage) UpdateUser(ctx context.Context, id int, request user.UpdateUse
 	return &usr, nil
 }
 
+// GetUserPreferences returns the user's preferences (zero value when unset),
+// or nil when the user does not exist.
+func (s *Storage) GetUserPreferences(ctx context.Context, userID int) (*user.Preferences, error) {
+	var prefs user.Preferences
+	err := s.pool.QueryRow(ctx, `
+		SELECT settings FROM trakrf.users
+		WHERE id = $1 AND deleted_at IS NULL`, userID).Scan(&prefs)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user preferences: %w", err)
+	}
+	return &prefs, nil
+}
+
+// UpdateUserPreferences replaces the user's preferences with prefs.
+func (s *Storage) UpdateUserPreferences(ctx context.Context, userID int, prefs user.Preferences) error {
+	blob, err := json.Marshal(prefs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user preferences: %w", err)
+	}
+	result, err := s.pool.Exec(ctx, `
+		UPDATE trakrf.users
+		SET settings = $2::jsonb, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`, userID, blob)
+	if err != nil {
+		return fmt.Errorf("failed to update user preferences: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return errors.ErrUserNotFound
+	}
+	return nil
+}
+
+// UpdateUserAvatar sets the user's avatar URL (nil clears it) and returns
+// the previous one, so the caller can delete the replaced object.
+func (s *Storage) UpdateUserAvatar(ctx context.Context, userID int, avatarURL *string) (previous *string, err error) {
+	err = s.pool.QueryRow(ctx, `
+		UPDATE trakrf.users u
+		SET avatar_url = $2, updated_at = NOW()
+		FROM (SELECT id, avatar_url FROM trakrf.users WHERE id = $1 FOR UPDATE) old
+		WHERE u.id = old.id AND u.deleted_at IS NULL
+		RETURNING old.avatar_url`, userID, avatarURL).Scan(&previous)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, errors.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to update user avatar: %w", err)
+	}
+	return previous, nil
+}
+
 // SoftDeleteUser marks a user as deleted by setting deleted_at timestamp.
 func (s *Storage) SoftDeleteUser(ctx context.Context, id int) error {
 	query := `UPDATE trakrf.users SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
@@ -0,0 +1,58 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/user"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestUserPreferences_RoundTrip(t *testing.T) {
+	store := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+
+	usr, err := store.CreateUser(ctx, user.CreateUserRequest{
+		Email:        "prefs@example.com",
+		Name:         "Prefs",
+		PasswordHash: "password-hash",
+	})
+	require.NoError(t, err)
+
+	// A new user has no preferences set.
+	p, err := store.GetUserPreferences(ctx, usr.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Preferences{}, *p)
+
+	want := user.Preferences{
+		Timezone:       "Europe/Madrid",
+		Locale:         "es",
+		Notifications:  &user.NotificationChannels{Email: false, InApp: true},
+		DefaultOrgID:   intp(7),
+		TableDensities: map[string]string{"assets": "compact"},
+	}
+	require.NoError(t, store.UpdateUserPreferences(ctx, usr.ID, want))
+	p, err = store.GetUserPreferences(ctx, usr.ID)
+	require.NoError(t, err)
+	require.Equal(t, want, *p)
+
+	// The typed settings come back on the user record too.
+	got, err := store.GetUserByID(ctx, usr.ID)
+	require.NoError(t, err)
+	require.Equal(t, want, got.Settings)
+
+	// Full replace clears what the new value leaves out.
+	require.NoError(t, store.UpdateUserPreferences(ctx, usr.ID, user.Preferences{Locale: "fr"}))
+	p, err = store.GetUserPreferences(ctx, usr.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Preferences{Locale: "fr"}, *p)
+
+	missing, err := store.GetUserPreferences(ctx, usr.ID+100000)
+	require.NoError(t, err)
+	require.Nil(t, missing)
+	require.ErrorIs(t, store.UpdateUserPreferences(ctx, usr.ID+100000, user.Preferences{}), errors.ErrUserNotFound)
+}
@@ -0,0 +1,84 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/user"
+	"github.com/trakrf/platform/backend/internal/storage"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestUpdateUserAvatar_ReturnsPrevious(t *testing.T) {
+	store := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+
+	usr, err := store.CreateUser(ctx, user.CreateUserRequest{Email: "avatar@example.com", Name: "A", PasswordHash: "password-hash"})
+	require.NoError(t, err)
+	require.Nil(t, usr.AvatarURL)
+
+	first, second := "https://cdn.test/a.png", "https://cdn.test/b.png"
+	prev, err := store.UpdateUserAvatar(ctx, usr.ID, &first)
+	require.NoError(t, err)
+	require.Nil(t, prev)
+
+	prev, err = store.UpdateUserAvatar(ctx, usr.ID, &second)
+	require.NoError(t, err)
+	require.Equal(t, first, *prev)
+
+	got, err := store.GetUserByID(ctx, usr.ID)
+	require.NoError(t, err)
+	require.Equal(t, second, *got.AvatarURL)
+
+	prev, err = store.UpdateUserAvatar(ctx, usr.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, second, *prev)
+
+	_, err = store.UpdateUserAvatar(ctx, 999999999, nil)
+	require.ErrorIs(t, err, errors.ErrUserNotFound)
+}
+
+func TestConfirmEmailChange(t *testing.T) {
+	store := testutil.SetupTestDatabase(t)
+	ctx := context.Background()
+
+	usr, err := store.CreateUser(ctx, user.CreateUserRequest{Email: "old@example.com", Name: "A", PasswordHash: "password-hash"})
+	require.NoError(t, err)
+	other, err := store.CreateUser(ctx, user.CreateUserRequest{Email: "other@example.com", Name: "B", PasswordHash: "password-hash"})
+	require.NoError(t, err)
+
+	// A later request replaces the pending one.
+	require.NoError(t, store.CreateEmailChangeToken(ctx, usr.ID, "first@example.com", "hash-1", time.Now().Add(time.Hour)))
+	require.NoError(t, store.CreateEmailChangeToken(ctx, usr.ID, "new@example.com", "hash-2", time.Now().Add(time.Hour)))
+	_, err = store.ConfirmEmailChange(ctx, "hash-1")
+	require.ErrorIs(t, err, storage.ErrEmailChangeTokenInvalid)
+
+	id, err := store.ConfirmEmailChange(ctx, "hash-2")
+	require.NoError(t, err)
+	require.Equal(t, usr.ID, id)
+	got, err := store.GetUserByID(ctx, usr.ID)
+	require.NoError(t, err)
+	require.Equal(t, "new@example.com", got.Email)
+
+	// Single use.
+	_, err = store.ConfirmEmailChange(ctx, "hash-2")
+	require.ErrorIs(t, err, storage.ErrEmailChangeTokenInvalid)
+
+	// Expired.
+	require.NoError(t, store.CreateEmailChangeToken(ctx, usr.ID, "later@example.com", "hash-3", time.Now().Add(-time.Minute)))
+	_, err = store.ConfirmEmailChange(ctx, "hash-3")
+	require.ErrorIs(t, err, storage.ErrEmailChangeTokenInvalid)
+
+	// Taken between request and confirmation.
+	require.NoError(t, store.CreateEmailChangeToken(ctx, usr.ID, "OTHER@example.com", "hash-4", time.Now().Add(time.Hour)))
+	_, err = store.ConfirmEmailChange(ctx, "hash-4")
+	require.ErrorIs(t, err, errors.ErrUserDuplicateEmail)
+	got, err = store.GetUserByID(ctx, other.ID)
+	require.NoError(t, err)
+	require.Equal(t, "other@example.com", got.Email)
+}
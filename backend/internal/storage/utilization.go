@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/location"
+	"github.com/trakrf/platform/backend/internal/models/report"
+)
+
+// utilizationQuery returns one row per live, currently effective asset.
+// latest_scans collapses the asset_scan_latest CAGG to each asset's newest
+// sighting ever, as the current-locations report does; window_scans keeps
+// the buckets since $2 and flags each one whose location differs from the
+// asset's previous bucket. A seen day is a calendar day in the time zone of
+// the location the asset was seen at (see locationZonesCTE), so a night
+// shift's sightings land on the site's date rather than UTC's. Assets never
+// seen in the window come back with zero seen_days and moves.
+var utilizationQuery = `
+	WITH RECURSIVE ` + locationZonesCTE + `,
+	latest_scans AS (
+		SELECT
+			asset_id,
+			last(location_id, last_seen) AS location_id,
+			max(last_seen)               AS last_seen
+		FROM trakrf.asset_scan_latest
+		WHERE org_id = $1
+		GROUP BY asset_id
+	),
+	window_scans AS (
+		SELECT
+			asset_id,
+			bucket,
+			location_id,
+			location_id IS DISTINCT FROM
+				LAG(location_id) OVER (PARTITION BY asset_id ORDER BY bucket, last_seen) AS moved,
+			ROW_NUMBER() OVER (PARTITION BY asset_id ORDER BY bucket, last_seen) AS n
+		FROM trakrf.asset_scan_latest
+		WHERE org_id = $1 AND bucket >= time_bucket(INTERVAL '1 minute', $2::timestamptz)
+	),
+	activity AS (
+		SELECT
+			w.asset_id,
+			COUNT(DISTINCT (w.bucket AT TIME ZONE COALESCE(z.tz, '` + location.DefaultTimeZone + `'))::date) AS seen_days,
+			COUNT(*) FILTER (WHERE w.moved AND w.n > 1) AS moves
+		FROM window_scans w
+		LEFT JOIN zones z ON z.id = w.location_id
+		GROUP BY w.asset_id
+	)
+	SELECT
+		a.id, a.external_key, a.name,
+		NULLIF(a.metadata->>'asset_type', ''),
+		l.id, l.external_key, l.name,
+		ls.last_seen,
+		COALESCE(w.seen_days, 0),
+		COALESCE(w.moves, 0)
+	FROM trakrf.assets a
+	LEFT JOIN latest_scans ls ON ls.asset_id = a.id
+	LEFT JOIN trakrf.locations l ON l.id = ls.location_id AND l.org_id = $1 AND l.deleted_at IS NULL AND ` + temporallyEffective("l") + `
+	LEFT JOIN activity w ON w.asset_id = a.id
+	WHERE a.org_id = $1 AND a.deleted_at IS NULL AND a.is_active AND ` + temporallyEffective("a") + `
+	ORDER BY a.id`
+
+// GetUtilizationReport classifies orgID's live assets as active or idle by
+// how many distinct days a reader saw them since filter.Since, and totals
+// them overall, by metadata.asset_type and by last-seen location.
+func (s *Storage) GetUtilizationReport(ctx context.Context, orgID int, filter report.UtilizationFilter) (*report.UtilizationReport, error) {
+	assets := []report.AssetUtilization{}
+	err := s.WithOrgTx(ctx, orgID, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, utilizationQuery, orgID, filter.Since)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var a report.AssetUtilization
+			if err := rows.Scan(&a.AssetID, &a.ExternalKey, &a.Name, &a.Type,
+				&a.LocationID, &a.LocationExternalKey, &a.LocationName,
+				&a.LastSeen, &a.SeenDays, &a.Moves); err != nil {
+				return err
+			}
+			assets = append(assets, a)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get utilization report: %w", err)
+	}
+	r := report.BuildUtilizationReport(assets, filter)
+	return &r, nil
+}
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/models/webhook"
+)
+
+// ErrWebhookEndpointGone is returned by RedeliverWebhook when the delivery's
+// endpoint has since been deleted or deactivated.
+var ErrWebhookEndpointGone = stderrors.New("webhook endpoint no longer active")
+
+// deliveryLogColumns selects a webhook.DeliveryLogEntry minus Payload.
+// next_attempt_at is only meaningful while the delivery is pending.
+const deliveryLogColumns = `
+	d.id, d.endpoint_id, e.url, d.event_type, d.status, d.attempts,
+	d.last_status_code, d.last_error, d.last_signature, d.last_attempt_at,
+	CASE WHEN d.status = 'pending' THEN d.next_attempt_at END,
+	d.delivered_at, d.replay_of, d.created_at`
+
+func deliveryLogScanArgs(e *webhook.DeliveryLogEntry) []any {
+	return []any{
+		&e.ID, &e.EndpointID, &e.EndpointURL, &e.EventType, &e.Status, &e.Attempts,
+		&e.LastStatusCode, &e.LastError, &e.LastSignature, &e.LastAttemptAt,
+		&e.NextAttemptAt, &e.DeliveredAt, &e.ReplayOf, &e.CreatedAt,
+	}
+}
+
+// buildDeliveryLogWhere returns the WHERE clause and args for f, with the org
+// bound to $1.
+func buildDeliveryLogWhere(orgID int, f webhook.DeliveryLogFilter) (string, []any) {
+	clauses := []string{"d.org_id = $1"}
+	args := []any{orgID}
+	add := func(clause string, v any) {
+		args = append(args, v)
+		clauses = append(clauses, fmt.Sprintf(clause, len(args)))
+	}
+	if f.EndpointID != 0 {
+		add("d.endpoint_id = $%d", f.EndpointID)
+	}
+	if f.EventType != "" {
+		add("d.event_type = $%d", f.EventType)
+	}
+	if f.Status != "" {
+		add("d.status = $%d", f.Status)
+	}
+	if f.From != nil {
+		add("d.created_at >= $%d", *f.From)
+	}
+	if f.To != nil {
+		add("d.created_at < $%d", *f.To)
+	}
+	return "WHERE " + strings.Join(clauses, " AND "), args
+}
+
+// ListWebhookDeliveries returns the org's delivery log, newest first, without
+// payloads.
+func (s *Storage) ListWebhookDeliveries(ctx context.Context, orgID int, f webhook.DeliveryLogFilter) ([]webhook.DeliveryLogEntry, error) {
+	where, args := buildDeliveryLogWhere(orgID, f)
+	args = append(args, f.Limit, f.Offset)
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		SELECT %s
+		FROM trakrf.webhook_deliveries d
+		JOIN trakrf.webhook_endpoints e ON e.id = d.endpoint_id
+		%s
+		ORDER BY d.created_at DESC, d.id DESC
+		LIMIT $%d OFFSET $%d`, deliveryLogColumns, where, len(args)-1, len(args)), args...)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	out := []webhook.DeliveryLogEntry{}
+	for rows.Next() {
+		var e webhook.DeliveryLogEntry
+		if err := rows.Scan(deliveryLogScanArgs(&e)...); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// CountWebhookDeliveries returns the number of log rows matching f, ignoring
+// its Limit and Offset.
+func (s *Storage) CountWebhookDeliveries(ctx context.Context, orgID int, f webhook.DeliveryLogFilter) (int, error) {
+	where, args := buildDeliveryLogWhere(orgID, f)
+	var n int
+	err := s.pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM trakrf.webhook_deliveries d `+where, args...).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count webhook deliveries: %w", err)
+	}
+	return n, nil
+}
+
+// GetWebhookDelivery returns one delivery with its payload, or nil when it is
+// not in the org.
+func (s *Storage) GetWebhookDelivery(ctx context.Context, orgID int, id int64) (*webhook.DeliveryLogEntry, error) {
+	var e webhook.DeliveryLogEntry
+	err := s.pool.QueryRow(ctx, `
+		SELECT `+deliveryLogColumns+`, d.payload
+		FROM trakrf.webhook_deliveries d
+		JOIN trakrf.webhook_endpoints e ON e.id = d.endpoint_id
+		WHERE d.id = $1 AND d.org_id = $2`, id, orgID).Scan(append(deliveryLogScanArgs(&e), &e.Payload)...)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get webhook delivery: %w", err)
+	}
+	return &e, nil
+}
+
+// RedeliverWebhook queues a new pending delivery carrying the original's
+// payload to the same endpoint, with replay_of pointing back at it. The
+// dispatcher signs it afresh at send time with the endpoint's current secret.
+// Returns nil when the delivery is not in the org, and ErrWebhookEndpointGone
+// when its endpoint was deleted or deactivated.
+func (s *Storage) RedeliverWebhook(ctx context.Context, orgID int, id int64) (*webhook.DeliveryLogEntry, error) {
+	var (
+		newID  int64
+		active bool
+	)
+	err := s.pool.QueryRow(ctx, `
+		WITH src AS (
+			SELECT d.id, d.org_id, d.endpoint_id, d.event_type, d.payload,
+			       (e.is_active AND e.deleted_at IS NULL) AS active
+			FROM trakrf.webhook_deliveries d
+			JOIN trakrf.webhook_endpoints e ON e.id = d.endpoint_id
+			WHERE d.id = $1 AND d.org_id = $2
+		), ins AS (
+			INSERT INTO trakrf.webhook_deliveries (org_id, endpoint_id, event_type, payload, replay_of)
+			SELECT org_id, endpoint_id, event_type, payload, id FROM src WHERE active
+			RETURNING id
+		)
+		SELECT COALESCE((SELECT id FROM ins), 0), src.active FROM src`, id, orgID).Scan(&newID, &active)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("redeliver webhook: %w", err)
+	}
+	if !active {
+		return nil, ErrWebhookEndpointGone
+	}
+	return s.GetWebhookDelivery(ctx, orgID, newID)
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/trakrf/platform/backend/internal/events"
+	"github.com/trakrf/platform/backend/internal/models/webhook"
+)
+
+const webhookEndpointColumns = `id, org_id, url, secret, event_types, is_active, created_at, updated_at`
+
+func scanWebhookEndpoint(row pgx.Row, e *webhook.Endpoint) error {
+	return row.Scan(&e.ID, &e.OrgID, &e.URL, &e.Secret, &e.EventTypes, &e.IsActive, &e.CreatedAt, &e.UpdatedAt)
+}
+
+// CreateWebhookEndpoint registers a receiver for orgID. eventTypes nil/empty
+// subscribes to every type.
+func (s *Storage) CreateWebhookEndpoint(ctx context.Context, orgID int, url, secret string, eventTypes []string) (*webhook.Endpoint, error) {
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	var e webhook.Endpoint
+	err := scanWebhookEndpoint(s.pool.QueryRow(ctx, `
+		INSERT INTO trakrf.webhook_endpoints (org_id, url, secret, event_types)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+webhookEndpointColumns,
+		orgID, url, secret, eventTypes), &e)
+	if err != nil {
+		return nil, fmt.Errorf("insert webhook endpoint: %w", err)
+	}
+	return &e, nil
+}
+
+// ListWebhookEndpoints returns the org's live endpoints, newest first.
+func (s *Storage) ListWebhookEndpoints(ctx context.Context, orgID int) ([]webhook.Endpoint, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT `+webhookEndpointColumns+`
+		FROM trakrf.webhook_endpoints
+		WHERE org_id = $1 AND deleted_at IS NULL
+		ORDER BY created_at DESC`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	out := []webhook.Endpoint{}
+	for rows.Next() {
+		var e webhook.Endpoint
+		if err := scanWebhookEndpoint(rows, &e); err != nil {
+			return nil, fmt.Errorf("scan webhook endpoint: %w", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// DeleteWebhookEndpoint soft-deletes the endpoint and fails its pending
+// deliveries, so the delivery job stops posting to a receiver the org removed.
+// Returns false when no live endpoint matched.
+func (s *Storage) DeleteWebhookEndpoint(ctx context.Context, orgID, id int) (bool, error) {
+	var deleted bool
+	err := s.WithTx(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		ct, err := tx.Exec(ctx, `
+			UPDATE trakrf.webhook_endpoints SET deleted_at = NOW(), is_active = false
+			WHERE id = $1 AND org_id = $2 AND deleted_at IS NULL`, id, orgID)
+		if err != nil {
+			return fmt.Errorf("delete webhook endpoint: %w", err)
+		}
+		deleted = ct.RowsAffected() > 0
+		if !deleted {
+			return nil
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE trakrf.webhook_deliveries
+			SET status = 'failed', last_error = 'endpoint deleted'
+			WHERE endpoint_id = $1 AND status = 'pending'`, id)
+		if err != nil {
+			return fmt.Errorf("fail pending webhook deliveries: %w", err)
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// enqueueWebhooks queues one delivery per entity and active endpoint of orgID
+// subscribed to typ, on the writing transaction — the webhook outbox. The
+// payload embeds the entity row as of this transaction.
+func (s *Storage) enqueueWebhooks(ctx context.Context, tx pgx.Tx, typ events.Type, orgID int, entityIDs []int) error {
+	table, ok := outboxEntityTables[typ]
+	if !ok {
+		return fmt.Errorf("no webhook entity table for event type %q", typ)
+	}
+	_, err := tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO trakrf.webhook_deliveries (org_id, endpoint_id, event_type, payload)
+		SELECT $1::bigint, w.id, $2::text, jsonb_build_object(
+			'type', $2::text,
+			'org_id', $1::bigint,
+			'entity_id', ids.id,
+			'occurred_at', NOW(),
+			'data', (SELECT to_jsonb(e) FROM %s e WHERE e.id = ids.id))
+		FROM unnest($3::bigint[]) WITH ORDINALITY AS ids(id, n)
+		CROSS JOIN trakrf.webhook_endpoints w
+		WHERE w.org_id = $1 AND w.is_active AND w.deleted_at IS NULL
+		  AND (cardinality(w.event_types) = 0 OR $2 = ANY(w.event_types))
+		ORDER BY ids.n, w.id`, table),
+		orgID, string(typ), entityIDs)
+	if err != nil {
+		return fmt.Errorf("enqueue %s webhooks: %w", typ, err)
+	}
+	return nil
+}
+
+// ClaimWebhookDeliveries leases up to limit due pending deliveries by pushing
+// their next_attempt_at out by lease, and returns them with the endpoint URL
+// and secret. The lease keeps other replicas off the rows while this one posts
+// them; if this process dies mid-send, the rows become due again when the
+// lease expires.
+func (s *Storage) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	rows, err := s.pool.Query(ctx, `
+		WITH claimed AS (
+			UPDATE trakrf.webhook_deliveries d
+			SET next_attempt_at = NOW() + make_interval(secs => $2)
+			WHERE d.id IN (
+				SELECT id FROM trakrf.webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED)
+			RETURNING d.id, d.org_id, d.endpoint_id, d.event_type, d.payload, d.attempts, d.replay_of
+		)
+		SELECT c.id, c.org_id, c.endpoint_id, c.event_type, c.payload, c.attempts, c.replay_of, e.url, e.secret
+		FROM claimed c
+		JOIN trakrf.webhook_endpoints e ON e.id = c.endpoint_id
+		ORDER BY c.id`, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var out []webhook.Delivery
+	for rows.Next() {
+		var d webhook.Delivery
+		if err := rows.Scan(&d.ID, &d.OrgID, &d.EndpointID, &d.EventType, &d.Payload, &d.Attempts, &d.ReplayOf, &d.URL, &d.Secret); err != nil {
+			return nil, fmt.Errorf("scan webhook delivery: %w", err)
+		}
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
+
+// RecordWebhookAttempt stores the outcome of posting d, along with the
+// signature and send time for the delivery log. A success marks it
+// delivered; a failure schedules the retry at retryIn, or marks it failed
+// once the attempt count reaches maxAttempts.
+func (s *Storage) RecordWebhookAttempt(ctx context.Context, d webhook.Delivery, a webhook.Attempt, maxAttempts int, retryIn time.Duration) error {
+	var statusCode *int
+	if a.StatusCode != 0 {
+		statusCode = &a.StatusCode
+	}
+	var signature *string
+	if a.Signature != "" {
+		signature = &a.Signature
+	}
+	sentAt := a.SentAt
+	if sentAt.IsZero() {
+		sentAt = time.Now()
+	}
+	var err error
+	if a.Succeeded() {
+		_, err = s.pool.Exec(ctx, `
+			UPDATE trakrf.webhook_deliveries
+			SET status = 'delivered', attempts = attempts + 1, delivered_at = NOW(),
+			    last_status_code = $2, last_error = NULL,
+			    last_signature = $3, last_attempt_at = $4
+			WHERE id = $1`, d.ID, statusCode, signature, sentAt)
+	} else {
+		_, err = s.pool.Exec(ctx, `
+			UPDATE trakrf.webhook_deliveries
+			SET attempts = attempts + 1,
+			    status = CASE WHEN attempts + 1 >= $3 THEN 'failed' ELSE 'pending' END,
+			    next_attempt_at = NOW() + make_interval(secs => $4),
+			    last_status_code = $2, last_error = $5,
+			    last_signature = $6, last_attempt_at = $7
+			WHERE id = $1`, d.ID, statusCode, maxAttempts, retryIn.Seconds(), a.Err, signature, sentAt)
+	}
+	if err != nil {
+		return fmt.Errorf("record webhook attempt: %w", err)
+	}
+	return nil
+}
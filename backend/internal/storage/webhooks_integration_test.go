@@ -0,0 +1,87 @@
+//go:build integration
+
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/webhook"
+	"github.com/trakrf/platform/backend/internal/testutil"
+)
+
+func TestWebhooks_WriteQueuesDeliveryForSubscribedEndpoints(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := context.Background()
+
+	all, err := db.Store.CreateWebhookEndpoint(ctx, orgID, "https://example.com/all", "whsec_a", nil)
+	require.NoError(t, err)
+	_, err = db.Store.CreateWebhookEndpoint(ctx, orgID, "https://example.com/locations", "whsec_b",
+		[]string{"location.created"})
+	require.NoError(t, err)
+
+	assetID := preCreateAssetWithTag(t, db, orgID, "webhook-pallet", "E2801190A503006543E2DDDD")
+
+	claimed, err := db.Store.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "only the catch-all endpoint subscribes to asset.created")
+	d := claimed[0]
+	assert.Equal(t, all.ID, d.EndpointID)
+	assert.Equal(t, "https://example.com/all", d.URL)
+	assert.Equal(t, "whsec_a", d.Secret)
+
+	var payload struct {
+		Type     string         `json:"type"`
+		EntityID int            `json:"entity_id"`
+		Data     map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(d.Payload, &payload))
+	assert.Equal(t, "asset.created", payload.Type)
+	assert.Equal(t, assetID, payload.EntityID)
+	assert.Equal(t, "webhook-pallet", payload.Data["name"])
+
+	again, err := db.Store.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, again, "a leased delivery is not claimed twice")
+
+	// A failure reschedules; reaching maxAttempts fails it for good.
+	require.NoError(t, db.Store.RecordWebhookAttempt(ctx, d, webhook.Attempt{StatusCode: 500, Err: "receiver returned 500"}, 1, 0))
+	var status string
+	var attempts int
+	require.NoError(t, db.AdminPool.QueryRow(ctx,
+		`SELECT status, attempts FROM trakrf.webhook_deliveries WHERE id = $1`, d.ID).Scan(&status, &attempts))
+	assert.Equal(t, webhook.StatusFailed, status)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestWebhooks_DeleteEndpointFailsPendingDeliveries(t *testing.T) {
+	db := testutil.SetupTestDBFull(t)
+	orgID := testutil.CreateTestAccount(t, db.AdminPool)
+	ctx := context.Background()
+
+	ep, err := db.Store.CreateWebhookEndpoint(ctx, orgID, "https://example.com/hook", "whsec_c", nil)
+	require.NoError(t, err)
+	preCreateAssetWithTag(t, db, orgID, "webhook-deleted", "E2801190A503006543E2CCCC")
+
+	deleted, err := db.Store.DeleteWebhookEndpoint(ctx, orgID+1, ep.ID)
+	require.NoError(t, err)
+	assert.False(t, deleted, "another org cannot delete the endpoint")
+
+	deleted, err = db.Store.DeleteWebhookEndpoint(ctx, orgID, ep.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	claimed, err := db.Store.ClaimWebhookDeliveries(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimed)
+
+	list, err := db.Store.ListWebhookEndpoints(ctx, orgID)
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
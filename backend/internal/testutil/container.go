@@ -0,0 +1,53 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// postgresImage is the timescaledb service image from docker-compose.yaml,
+// so the harness migrates against the same server and extensions (TimescaleDB
+// plus the contrib modules such as ltree) as dev and production.
+const postgresImage = "timescale/timescaledb-ha:pg17.9-ts2.26.4"
+
+// container is the throwaway Postgres started for this test binary when no
+// PG_URL is set. It is started at most once and shared by every test in the
+// package; testcontainers' reaper removes it when the binary exits.
+var container struct {
+	once sync.Once
+	url  string
+	err  error
+}
+
+// containerPostgresURL starts the shared container on first use and returns
+// the superuser URL of its postgres database.
+func containerPostgresURL(t testing.TB) string {
+	t.Helper()
+
+	container.once.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		c, err := postgres.Run(ctx, postgresImage,
+			postgres.WithDatabase("postgres"),
+			postgres.WithUsername("postgres"),
+			postgres.WithPassword("postgres"),
+			postgres.BasicWaitStrategies(),
+		)
+		if err != nil {
+			container.err = fmt.Errorf("failed to start %s (set PG_URL to use an existing server instead): %w", postgresImage, err)
+			return
+		}
+		container.url, container.err = c.ConnectionString(ctx, "sslmode=disable")
+	})
+
+	if container.err != nil {
+		t.Fatalf("Postgres test container: %v", container.err)
+	}
+	return container.url
+}
@@ -151,7 +151,7 @@ func (f *CSVFactory) Build() [][]string {
 	return f.rows
 }
 
-func CreateTestAsset(t *testing.T, pool *pgxpool.Pool, orgID int, externalKey string) *asset.Asset {
+func CreateTestAsset(t testing.TB, pool *pgxpool.Pool, orgID int, externalKey string) *asset.Asset {
 	t.Helper()
 	ctx := context.Background()
 
@@ -179,3 +179,246 @@ func CreateTestAsset(t *testing.T, pool *pgxpool.Pool, orgID int, externalKey st
 		IsActive:    true,
 	}
 }
+
+// FixtureEpoch is the default valid_from for rows built by the location
+// and identifier factories. It is fixed, so fixtures (and
+// golden files rendered from them) are identical on every run, and in the
+// past, so the rows are effective whenever the test runs.
+var FixtureEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// OrgFactory inserts organizations. Keys are sequential per factory
+// ("org-001", "org-002", ...), so a test that needs several orgs should
+// draw them all from one factory.
+type OrgFactory struct {
+	Name       string
+	Identifier string
+	IsActive   bool
+	seq        int
+}
+
+func NewOrgFactory() *OrgFactory {
+	return &OrgFactory{IsActive: true}
+}
+
+func (f *OrgFactory) WithName(name string) *OrgFactory {
+	f.Name = name
+	return f
+}
+
+func (f *OrgFactory) WithIdentifier(identifier string) *OrgFactory {
+	f.Identifier = identifier
+	return f
+}
+
+// Create inserts one organization and returns its ID. Name and Identifier
+// are used once when set; otherwise both derive from the next sequence
+// number.
+func (f *OrgFactory) Create(t testing.TB, pool *pgxpool.Pool) int {
+	t.Helper()
+	f.seq++
+	name, identifier := takeOr(&f.Name, fmt.Sprintf("Org %03d", f.seq)), takeOr(&f.Identifier, fmt.Sprintf("org-%03d", f.seq))
+
+	var id int
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO trakrf.organizations (name, identifier, is_active)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`, name, identifier, f.IsActive).Scan(&id)
+	if err != nil {
+		t.Fatalf("Failed to create test org %q: %v", identifier, err)
+	}
+	return id
+}
+
+// LocationFactory inserts locations in one org. External keys are
+// sequential per factory ("LOC-001", ...) and the name defaults to the key.
+type LocationFactory struct {
+	OrgID       int
+	ExternalKey string
+	Name        string
+	Description string
+	ParentID    *int
+	ValidFrom   time.Time
+	ValidTo     *time.Time
+	IsActive    bool
+	seq         int
+}
+
+func NewLocationFactory(orgID int) *LocationFactory {
+	return &LocationFactory{OrgID: orgID, ValidFrom: FixtureEpoch, IsActive: true}
+}
+
+func (f *LocationFactory) WithExternalKey(key string) *LocationFactory {
+	f.ExternalKey = key
+	return f
+}
+
+func (f *LocationFactory) WithName(name string) *LocationFactory {
+	f.Name = name
+	return f
+}
+
+// WithParent nests the following locations under parentID until it is
+// changed; pass 0 to go back to creating roots.
+func (f *LocationFactory) WithParent(parentID int) *LocationFactory {
+	f.ParentID = nil
+	if parentID != 0 {
+		f.ParentID = &parentID
+	}
+	return f
+}
+
+func (f *LocationFactory) WithValidity(from time.Time, to *time.Time) *LocationFactory {
+	f.ValidFrom, f.ValidTo = from, to
+	return f
+}
+
+// Create inserts one location and returns its ID. ExternalKey and Name are
+// used once when set; parent, validity and description carry over to the
+// next Create.
+func (f *LocationFactory) Create(t testing.TB, pool *pgxpool.Pool) int {
+	t.Helper()
+	f.seq++
+	key := takeOr(&f.ExternalKey, fmt.Sprintf("LOC-%03d", f.seq))
+	name := takeOr(&f.Name, key)
+
+	var id int
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO trakrf.locations (org_id, external_key, name, description, parent_location_id, valid_from, valid_to, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`, f.OrgID, key, name, f.Description, f.ParentID, f.ValidFrom, f.ValidTo, f.IsActive).Scan(&id)
+	if err != nil {
+		t.Fatalf("Failed to create test location %q: %v", key, err)
+	}
+	return id
+}
+
+// Create inserts the asset described by the factory and returns its ID.
+func (f *AssetFactory) Create(t testing.TB, pool *pgxpool.Pool) int {
+	t.Helper()
+	var id int
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO trakrf.assets (org_id, external_key, name, description, valid_from, valid_to, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, f.OrgID, f.ExternalKey, f.Name, f.Description, f.ValidFrom, f.ValidTo, f.IsActive).Scan(&id)
+	if err != nil {
+		t.Fatalf("Failed to create test asset %q: %v", f.ExternalKey, err)
+	}
+	return id
+}
+
+// IdentifierFactory inserts tag identifiers attached to an asset or a
+// location. Values default to sequential 24-hex-digit EPCs that sort in
+// creation order.
+type IdentifierFactory struct {
+	OrgID      int
+	Type       string
+	Value      string
+	AssetID    *int
+	LocationID *int
+	IsActive   bool
+	seq        int
+}
+
+func NewIdentifierFactory(orgID int) *IdentifierFactory {
+	return &IdentifierFactory{OrgID: orgID, Type: "rfid", IsActive: true}
+}
+
+// ForAsset attaches the following identifiers to assetID.
+func (f *IdentifierFactory) ForAsset(assetID int) *IdentifierFactory {
+	f.AssetID, f.LocationID = &assetID, nil
+	return f
+}
+
+// ForLocation attaches the following identifiers to locationID.
+func (f *IdentifierFactory) ForLocation(locationID int) *IdentifierFactory {
+	f.AssetID, f.LocationID = nil, &locationID
+	return f
+}
+
+// WithValue sets the type and value of the next identifier only.
+func (f *IdentifierFactory) WithValue(tagType, value string) *IdentifierFactory {
+	f.Type, f.Value = tagType, value
+	return f
+}
+
+// Create inserts one identifier and returns its ID. Call ForAsset or
+// ForLocation first; the tags table requires exactly one owner.
+func (f *IdentifierFactory) Create(t testing.TB, pool *pgxpool.Pool) int {
+	t.Helper()
+	f.seq++
+	tagType := f.Type
+	value := takeOr(&f.Value, fmt.Sprintf("E28011700000000000%06X", f.seq))
+	f.Type = "rfid"
+
+	var id int
+	err := pool.QueryRow(context.Background(), `
+		INSERT INTO trakrf.tags (org_id, type, value, asset_id, location_id, valid_from, is_active)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`, f.OrgID, tagType, value, f.AssetID, f.LocationID, FixtureEpoch, f.IsActive).Scan(&id)
+	if err != nil {
+		t.Fatalf("Failed to create test identifier %s:%s: %v", tagType, value, err)
+	}
+	return id
+}
+
+// ScanFactory inserts asset_scans rows for one org. Without At, consecutive
+// scans are one minute apart, the first an hour before the factory was made
+// (minute-aligned). Scans are recent rather than anchored at FixtureEpoch
+// because asset_scans is trimmed by retention; golden tests pin the returned
+// timestamps with Alias. Reports read the asset_scan_latest aggregate, so
+// call RefreshAssetScanLatest after seeding.
+type ScanFactory struct {
+	OrgID     int
+	Timestamp time.Time
+	next      time.Time
+}
+
+func NewScanFactory(orgID int) *ScanFactory {
+	return &ScanFactory{OrgID: orgID, next: time.Now().UTC().Truncate(time.Minute).Add(-time.Hour)}
+}
+
+// At sets the timestamp of the next scan; the ones after it follow at
+// one-minute steps.
+func (f *ScanFactory) At(ts time.Time) *ScanFactory {
+	f.Timestamp = ts
+	return f
+}
+
+// Create records assetID being seen at locationID (0 for no location) and
+// returns the scan timestamp.
+func (f *ScanFactory) Create(t testing.TB, pool *pgxpool.Pool, assetID, locationID int) time.Time {
+	t.Helper()
+	ts := f.next
+	if !f.Timestamp.IsZero() {
+		ts, f.Timestamp = f.Timestamp, time.Time{}
+	}
+	f.next = ts.Add(time.Minute)
+
+	var loc *int
+	if locationID != 0 {
+		loc = &locationID
+	}
+	_, err := pool.Exec(context.Background(), `
+		INSERT INTO trakrf.asset_scans (org_id, asset_id, location_id, timestamp)
+		VALUES ($1, $2, $3, $4)
+	`, f.OrgID, assetID, loc, ts)
+	if err != nil {
+		t.Fatalf("Failed to create test scan of asset %d: %v", assetID, err)
+	}
+	return ts
+}
+
+// takeOr returns *field and clears it, or def when *field is empty, so a
+// With* override applies to exactly one Create.
+func takeOr(field *string, def string) string {
+	if *field == "" {
+		return def
+	}
+	v := *field
+	*field = ""
+	return v
+}
@@ -0,0 +1,135 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// UpdateGoldenEnv names the environment variable that makes AssertGoldenJSON
+// rewrite golden files instead of comparing against them:
+//
+//	UPDATE_GOLDEN=1 go test -tags=integration ./internal/handlers/reports/
+//
+// It is an environment variable rather than a -update flag because go test
+// ./... hands flags to every package binary, and those that do not import
+// testutil would reject it.
+const UpdateGoldenEnv = "UPDATE_GOLDEN"
+
+// GoldenOption rewrites volatile values before a golden comparison.
+type GoldenOption func(*goldenConfig)
+
+type goldenConfig struct {
+	redact  map[string]bool
+	aliases map[string]string
+}
+
+// Redact replaces the value of every object member named by keys, at any
+// depth, with "<key>". Nulls are kept, so the golden file still records
+// whether the field was set. Use it for server-assigned values the test
+// cannot know, such as created_at or request ids.
+func Redact(keys ...string) GoldenOption {
+	return func(c *goldenConfig) {
+		for _, k := range keys {
+			c.redact[k] = true
+		}
+	}
+}
+
+// Alias replaces every scalar equal to value (compared in its fmt.Sprint
+// form) with placeholder. Use it for database ids the test holds, so the
+// golden file keeps which row points at which: Alias(forkliftID, "$FORKLIFT").
+func Alias(value any, placeholder string) GoldenOption {
+	return func(c *goldenConfig) {
+		c.aliases[fmt.Sprint(value)] = placeholder
+	}
+}
+
+// AssertGoldenJSON compares the JSON document got with
+// testdata/golden/<name>.json in the calling package. Both sides are
+// compared in normal form (sorted keys, two-space indent) after the options
+// are applied, so field order and whitespace never cause a failure. With
+// UPDATE_GOLDEN set, the file is written from got instead.
+func AssertGoldenJSON(t testing.TB, name string, got []byte, opts ...GoldenOption) {
+	t.Helper()
+
+	cfg := &goldenConfig{redact: map[string]bool{}, aliases: map[string]string{}}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	normalized, err := normalizeGoldenJSON(got, cfg)
+	if err != nil {
+		t.Fatalf("golden %s: response is not JSON: %v\n%s", name, err, got)
+	}
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, normalized, 0o644); err != nil {
+			t.Fatalf("golden %s: %v", name, err)
+		}
+		t.Logf("updated %s", path)
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden %s: %v (run with %s=1 to create it)", name, err, UpdateGoldenEnv)
+	}
+	assert.Equal(t, string(want), string(normalized), "golden %s differs; rerun with %s=1 if the change is intended", path, UpdateGoldenEnv)
+}
+
+func normalizeGoldenJSON(data []byte, cfg *goldenConfig) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(scrubGolden(v, cfg)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scrubGolden applies Redact and Alias to a decoded document. Maps marshal
+// with sorted keys, which gives the normal form for free.
+func scrubGolden(v any, cfg *goldenConfig) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, child := range x {
+			if cfg.redact[k] && child != nil {
+				x[k] = "<" + k + ">"
+				continue
+			}
+			x[k] = scrubGolden(child, cfg)
+		}
+		return x
+	case []any:
+		for i, child := range x {
+			x[i] = scrubGolden(child, cfg)
+		}
+		return x
+	case json.Number:
+		if alias, ok := cfg.aliases[x.String()]; ok {
+			return alias
+		}
+	case string:
+		if alias, ok := cfg.aliases[x]; ok {
+			return alias
+		}
+	}
+	return v
+}
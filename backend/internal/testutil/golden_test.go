@@ -0,0 +1,49 @@
+package testutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeGoldenJSON(t *testing.T) {
+	cfg := &goldenConfig{redact: map[string]bool{}, aliases: map[string]string{}}
+	Redact("created_at", "deleted_at")(cfg)
+	Alias(4711, "$ASSET")(cfg)
+	Alias("2026-05-14T12:34:56.000Z", "$T1")(cfg)
+
+	got, err := normalizeGoldenJSON([]byte(`{"total_count": 4711, "data": [
+		{"id": 4711, "created_at": "2026-05-14T12:34:56.789Z", "deleted_at": null, "last_seen": "2026-05-14T12:34:56.000Z", "note": "<b>"}
+	]}`), cfg)
+	require.NoError(t, err)
+	assert.Equal(t, `{
+  "data": [
+    {
+      "created_at": "<created_at>",
+      "deleted_at": null,
+      "id": "$ASSET",
+      "last_seen": "$T1",
+      "note": "<b>"
+    }
+  ],
+  "total_count": "$ASSET"
+}
+`, string(got))
+}
+
+func TestAssertGoldenJSON_Update(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	t.Setenv(UpdateGoldenEnv, "1")
+	AssertGoldenJSON(t, "report", []byte(`{"b": 2, "a": 1.50}`))
+	written, err := os.ReadFile(filepath.Join("testdata", "golden", "report.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "{\n  \"a\": 1.50,\n  \"b\": 2\n}\n", string(written))
+
+	// Compare mode accepts the same document in any key order or layout.
+	t.Setenv(UpdateGoldenEnv, "")
+	AssertGoldenJSON(t, "report", []byte(`{"a":1.50,"b":2}`))
+}
@@ -0,0 +1,39 @@
+package awssig
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign_MatchesAWSTestVector(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	require.NoError(t, err)
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	Sign(req, nil, Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}, "us-east-1", "service", now)
+
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+		"SignedHeaders=host;x-amz-date, "+
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		req.Header.Get("Authorization"))
+}
+
+func TestSign_SessionTokenIsSigned(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPut, "https://bucket.s3.amazonaws.com/a/b.png", nil)
+	require.NoError(t, err)
+	req.Header.Set("X-Amz-Content-Sha256", PayloadHash([]byte("x")))
+
+	Sign(req, []byte("x"), Credentials{AccessKeyID: "AKID", SecretAccessKey: "s", SessionToken: "tok"},
+		"eu-west-1", "s3", time.Now())
+
+	assert.Equal(t, "tok", req.Header.Get("X-Amz-Security-Token"))
+	assert.Contains(t, req.Header.Get("Authorization"),
+		"SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token,")
+}
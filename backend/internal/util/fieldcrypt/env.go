@@ -0,0 +1,151 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/trakrf/platform/backend/internal/util/awssig"
+)
+
+// FromEnv builds the keyring from FIELD_ENCRYPTION_KEYS, a comma-separated
+// list of id:key entries, primary first:
+//
+//	FIELD_ENCRYPTION_KEYS         2026-10:<base64 32 bytes>,2025-04:kms:<base64 KMS ciphertext>
+//	FIELD_ENCRYPTION_KMS_REGION   region of kms: entries (or AWS_REGION)
+//	FIELD_ENCRYPTION_KMS_ENDPOINT KMS API URL (default https://kms.<region>.amazonaws.com)
+//	AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN
+//
+// A kms: entry is a data key encrypted by AWS KMS (the CiphertextBlob of
+// GenerateDataKey); it is decrypted with KMS once, at boot. Unset returns a
+// nil keyring, which leaves values in plaintext. A malformed entry, or a KMS
+// key that cannot be decrypted, is an error so the server refuses to boot
+// instead of writing values it could not read back.
+func FromEnv(ctx context.Context) (*Keyring, error) {
+	raw := strings.TrimSpace(os.Getenv("FIELD_ENCRYPTION_KEYS"))
+	if raw == "" {
+		return nil, nil
+	}
+	var keys []Key
+	for _, item := range strings.Split(raw, ",") {
+		id, material, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok || id == "" || material == "" {
+			return nil, fmt.Errorf("FIELD_ENCRYPTION_KEYS: entry %q is not id:key", redact(item))
+		}
+		var secret []byte
+		if blob, isKMS := strings.CutPrefix(material, "kms:"); isKMS {
+			ciphertext, err := base64.StdEncoding.DecodeString(blob)
+			if err != nil {
+				return nil, fmt.Errorf("FIELD_ENCRYPTION_KEYS: key %q: KMS ciphertext is not valid base64", id)
+			}
+			kms, err := kmsFromEnv()
+			if err != nil {
+				return nil, err
+			}
+			if secret, err = kms.decrypt(ctx, ciphertext); err != nil {
+				return nil, fmt.Errorf("FIELD_ENCRYPTION_KEYS: key %q: %w", id, err)
+			}
+		} else {
+			var err error
+			if secret, err = base64.StdEncoding.DecodeString(material); err != nil {
+				return nil, fmt.Errorf("FIELD_ENCRYPTION_KEYS: key %q is not valid base64", id)
+			}
+		}
+		keys = append(keys, Key{ID: id, Secret: secret})
+	}
+	k, err := NewKeyring(keys)
+	if err != nil {
+		return nil, fmt.Errorf("FIELD_ENCRYPTION_KEYS: %w", err)
+	}
+	return k, nil
+}
+
+// redact keeps an entry's id and drops its key material.
+func redact(item string) string {
+	id, _, _ := strings.Cut(strings.TrimSpace(item), ":")
+	return id + ":…"
+}
+
+// kmsClient calls the AWS KMS Decrypt API.
+type kmsClient struct {
+	endpoint string
+	region   string
+	creds    awssig.Credentials
+	client   *http.Client
+}
+
+func kmsFromEnv() (*kmsClient, error) {
+	c := &kmsClient{
+		region:   os.Getenv("FIELD_ENCRYPTION_KMS_REGION"),
+		endpoint: strings.TrimRight(os.Getenv("FIELD_ENCRYPTION_KMS_ENDPOINT"), "/"),
+		creds: awssig.Credentials{
+			AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+		},
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+	if c.region == "" {
+		c.region = os.Getenv("AWS_REGION")
+	}
+	if c.region == "" {
+		return nil, fmt.Errorf("FIELD_ENCRYPTION_KMS_REGION or AWS_REGION must be set for kms: keys")
+	}
+	if c.creds.AccessKeyID == "" || c.creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for kms: keys")
+	}
+	if c.endpoint == "" {
+		c.endpoint = "https://kms." + c.region + ".amazonaws.com"
+	}
+	if u, err := url.Parse(c.endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("FIELD_ENCRYPTION_KMS_ENDPOINT must be an http(s) URL, got %q", c.endpoint)
+	}
+	return c, nil
+}
+
+func (c *kmsClient) decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"CiphertextBlob": base64.StdEncoding.EncodeToString(ciphertext)})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("build kms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Decrypt")
+	awssig.Sign(req, body, c.creds, c.region, "kms", time.Now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kms decrypt: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, fmt.Errorf("kms decrypt: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &e)
+		return nil, fmt.Errorf("kms decrypt: status %d: %s %s", resp.StatusCode, e.Type, e.Message)
+	}
+	var out struct {
+		Plaintext []byte `json:"Plaintext"`
+	}
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, fmt.Errorf("kms decrypt: %w", err)
+	}
+	return out.Plaintext, nil
+}
@@ -0,0 +1,182 @@
+// Package fieldcrypt encrypts sensitive column values before they are
+// stored: API key secret hashes, connector credentials and password reset
+// tokens. A database dump or a read-only SQL leak then yields nothing usable
+// without the keys, which live outside the database (env, or KMS).
+//
+// A Keyring holds one primary key, which encrypts, and any number of older
+// keys, which only decrypt, so keys rotate without downtime: add a new
+// primary, re-encrypt with `server encryption rotate`, then drop the old key.
+//
+// Values read back are sealed with AES-256-GCM as
+//
+//	enc:v1:<key id>:<base64 nonce||ciphertext>
+//
+// Values only ever looked up by equality (reset tokens) cannot use
+// randomized encryption; they are stored as a keyed digest (Digest), and
+// looked up under every key (Digests).
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// prefix marks an encrypted value; anything else is a plaintext value
+// written before encryption was enabled.
+const prefix = "enc:v1:"
+
+// ErrUndecryptable is returned when a value cannot be opened: its key is not
+// in the keyring, or the value was altered or moved to another column.
+var ErrUndecryptable = errors.New("encrypted value cannot be decrypted with the configured keys")
+
+// Key is one named 32-byte key.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+var keyID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+type entry struct {
+	aead   cipher.AEAD
+	digest []byte
+}
+
+// Keyring encrypts with its primary key and decrypts with any of its keys.
+type Keyring struct {
+	primary string
+	ids     []string
+	keys    map[string]entry
+}
+
+// NewKeyring builds a keyring whose primary is keys[0].
+func NewKeyring(keys []Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one key is required")
+	}
+	k := &Keyring{primary: keys[0].ID, keys: make(map[string]entry, len(keys))}
+	for _, key := range keys {
+		if !keyID.MatchString(key.ID) {
+			return nil, fmt.Errorf("key id %q must be 1-32 letters, digits, '-' or '_'", key.ID)
+		}
+		if _, dup := k.keys[key.ID]; dup {
+			return nil, fmt.Errorf("key id %q is listed twice", key.ID)
+		}
+		if len(key.Secret) != 32 {
+			return nil, fmt.Errorf("key %q must be 32 bytes, got %d", key.ID, len(key.Secret))
+		}
+		block, err := aes.NewCipher(key.Secret)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		// Digests use a key derived from the secret, never the AES key itself.
+		mac := hmac.New(sha256.New, key.Secret)
+		mac.Write([]byte("trakrf-fieldcrypt-digest"))
+		k.keys[key.ID] = entry{aead: aead, digest: mac.Sum(nil)}
+		k.ids = append(k.ids, key.ID)
+	}
+	return k, nil
+}
+
+// PrimaryID is the id of the key that encrypts.
+func (k *Keyring) PrimaryID() string {
+	return k.primary
+}
+
+// Encrypt seals plaintext under the primary key. aad names what the value
+// is (its column, and its org where it has one): a value copied elsewhere
+// fails to decrypt.
+func (k *Keyring) Encrypt(plaintext []byte, aad string) (string, error) {
+	e := k.keys[k.primary]
+	nonce := make([]byte, e.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := e.aead.Seal(nonce, nonce, plaintext, []byte(aad))
+	return prefix + k.primary + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value Encrypt produced with the same aad, under whichever
+// of the keyring's keys sealed it.
+func (k *Keyring) Decrypt(value, aad string) ([]byte, error) {
+	id, body, ok := split(value)
+	if !ok {
+		return nil, ErrUndecryptable
+	}
+	e, ok := k.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrUndecryptable, id)
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(body)
+	if err != nil || len(sealed) < e.aead.NonceSize() {
+		return nil, ErrUndecryptable
+	}
+	n := e.aead.NonceSize()
+	plaintext, err := e.aead.Open(nil, sealed[:n], sealed[n:], []byte(aad))
+	if err != nil {
+		return nil, ErrUndecryptable
+	}
+	return plaintext, nil
+}
+
+// Current reports whether value is encrypted under the primary key, so
+// rotation can leave it alone.
+func (k *Keyring) Current(value string) bool {
+	id, ok := KeyID(value)
+	return ok && id == k.primary
+}
+
+// Digest is the keyed SHA-256 of value under the primary key, hex encoded
+// (64 characters).
+func (k *Keyring) Digest(value string) string {
+	return digest(k.keys[k.primary].digest, value)
+}
+
+// Digests is value's digest under every key, primary first: the values to
+// look a stored digest up by while older keys are still in the keyring.
+func (k *Keyring) Digests(value string) []string {
+	out := make([]string, len(k.ids))
+	for i, id := range k.ids {
+		out[i] = digest(k.keys[id].digest, value)
+	}
+	return out
+}
+
+func digest(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted reports whether value is in the encrypted format, as opposed
+// to a plaintext value stored before encryption was enabled.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix)
+}
+
+// KeyID returns the id of the key value is encrypted under.
+func KeyID(value string) (string, bool) {
+	id, _, ok := split(value)
+	return id, ok
+}
+
+func split(value string) (id, body string, ok bool) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
@@ -0,0 +1,165 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyring(t *testing.T, ids ...string) *Keyring {
+	t.Helper()
+	keys := make([]Key, len(ids))
+	for i, id := range ids {
+		keys[i] = Key{ID: id, Secret: bytes.Repeat([]byte{byte(i + 1)}, 32)}
+	}
+	k, err := NewKeyring(keys)
+	require.NoError(t, err)
+	return k
+}
+
+func TestEncryptDecrypt_RoundTripIsBoundToAAD(t *testing.T) {
+	k := testKeyring(t, "k1")
+	value, err := k.Encrypt([]byte("s3cret"), "api_keys.secret_hash")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(value, "enc:v1:k1:"), value)
+	assert.NotContains(t, value, "s3cret")
+	assert.True(t, IsEncrypted(value))
+
+	plain, err := k.Decrypt(value, "api_keys.secret_hash")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", string(plain))
+
+	_, err = k.Decrypt(value, "connectors.credentials:1")
+	assert.ErrorIs(t, err, ErrUndecryptable, "a value moved to another column does not open")
+	_, err = k.Decrypt(value[:len(value)-4], "api_keys.secret_hash")
+	assert.ErrorIs(t, err, ErrUndecryptable)
+	_, err = k.Decrypt("0123abcd", "api_keys.secret_hash")
+	assert.ErrorIs(t, err, ErrUndecryptable)
+
+	again, err := k.Encrypt([]byte("s3cret"), "api_keys.secret_hash")
+	require.NoError(t, err)
+	assert.NotEqual(t, value, again, "encryption is randomized")
+}
+
+func TestRotation_OldKeysDecryptPrimaryEncrypts(t *testing.T) {
+	old := testKeyring(t, "k1")
+	value, err := old.Encrypt([]byte("s3cret"), "aad")
+	require.NoError(t, err)
+
+	rotated, err := NewKeyring([]Key{
+		{ID: "k2", Secret: bytes.Repeat([]byte{9}, 32)},
+		{ID: "k1", Secret: bytes.Repeat([]byte{1}, 32)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", rotated.PrimaryID())
+	assert.False(t, rotated.Current(value), "still under the old key")
+
+	plain, err := rotated.Decrypt(value, "aad")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", string(plain))
+
+	resealed, err := rotated.Encrypt(plain, "aad")
+	require.NoError(t, err)
+	assert.True(t, rotated.Current(resealed))
+
+	_, err = testKeyring(t, "k2").Decrypt(value, "aad")
+	assert.ErrorIs(t, err, ErrUndecryptable, "a dropped key no longer opens its values")
+	assert.ErrorContains(t, err, `unknown key "k1"`)
+}
+
+func TestDigests(t *testing.T) {
+	k := testKeyring(t, "k2", "k1")
+	d := k.Digest("token")
+	assert.Len(t, d, 64)
+	assert.Equal(t, d, k.Digests("token")[0], "primary first")
+	assert.Len(t, k.Digests("token"), 2)
+	assert.NotEqual(t, k.Digests("token")[0], k.Digests("token")[1])
+	assert.NotEqual(t, d, k.Digest("other"))
+	rotated, err := NewKeyring([]Key{
+		{ID: "k3", Secret: bytes.Repeat([]byte{9}, 32)},
+		{ID: "k2", Secret: bytes.Repeat([]byte{1}, 32)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, d, rotated.Digests("token")[1], "a digest taken before rotation is still found after it")
+}
+
+func TestNewKeyring_Rejects(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	for name, keys := range map[string][]Key{
+		"none":      nil,
+		"short key": {{ID: "k1", Secret: key[:16]}},
+		"bad id":    {{ID: "k 1", Secret: key}},
+		"duplicate": {{ID: "k1", Secret: key}, {ID: "k1", Secret: key}},
+	} {
+		_, err := NewKeyring(keys)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv("FIELD_ENCRYPTION_KEYS", "")
+	k, err := FromEnv(context.Background())
+	require.NoError(t, err)
+	assert.Nil(t, k, "unset leaves values in plaintext")
+
+	k1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	k2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	t.Setenv("FIELD_ENCRYPTION_KEYS", "new:"+k2+", old:"+k1)
+	k, err = FromEnv(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "new", k.PrimaryID())
+
+	for _, bad := range []string{"nokey", "k1:not-base64!", "k1:c2hvcnQ="} {
+		t.Setenv("FIELD_ENCRYPTION_KEYS", bad)
+		_, err := FromEnv(context.Background())
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestFromEnv_KMS(t *testing.T) {
+	dataKey := bytes.Repeat([]byte{5}, 32)
+	blob := []byte("kms-ciphertext-blob")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "TrentService.Decrypt", r.Header.Get("X-Amz-Target"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-2/kms/aws4_request")
+		body, _ := io.ReadAll(r.Body)
+		var req struct{ CiphertextBlob []byte }
+		require.NoError(t, json.Unmarshal(body, &req))
+		if !bytes.Equal(req.CiphertextBlob, blob) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"InvalidCiphertextException","message":"bad blob"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": dataKey})
+	}))
+	defer srv.Close()
+
+	t.Setenv("FIELD_ENCRYPTION_KMS_ENDPOINT", srv.URL)
+	t.Setenv("FIELD_ENCRYPTION_KMS_REGION", "us-east-2")
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKID")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("FIELD_ENCRYPTION_KEYS", "k1:kms:"+base64.StdEncoding.EncodeToString(blob))
+	k, err := FromEnv(context.Background())
+	require.NoError(t, err)
+
+	value, err := k.Encrypt([]byte("x"), "aad")
+	require.NoError(t, err)
+	direct, err := NewKeyring([]Key{{ID: "k1", Secret: dataKey}})
+	require.NoError(t, err)
+	plain, err := direct.Decrypt(value, "aad")
+	require.NoError(t, err, "the KMS-decrypted data key is the key in use")
+	assert.Equal(t, "x", string(plain))
+
+	t.Setenv("FIELD_ENCRYPTION_KEYS", "k1:kms:"+base64.StdEncoding.EncodeToString([]byte("other")))
+	_, err = FromEnv(context.Background())
+	assert.ErrorContains(t, err, "InvalidCiphertextException")
+}
@@ -0,0 +1,447 @@
+// Package filterexpr parses the `filter` query parameter of list endpoints:
+// a small boolean expression language such as
+//
+//	type eq "equipment" and metadata.manufacturer eq "Acme" and created_at gt 2024-01-01
+//
+// Parse checks every field and value against the endpoint's Fields, so a
+// parsed Expr is always well typed; SQL then compiles it into a
+// parameterised WHERE fragment. Values never reach the SQL text.
+//
+// Grammar (keywords and operators are lowercase):
+//
+//	expr  = term { "or" term }
+//	term  = unary { "and" unary }
+//	unary = "not" unary | "(" expr ")" | field op value | field "in" "(" value { "," value } ")"
+//	op    = "eq" | "ne" | "gt" | "ge" | "lt" | "le" | "contains"
+//	value = "quoted string" | number | true | false | null | date
+package filterexpr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits on one filter expression, so a filter cannot turn into an
+// arbitrarily expensive query.
+const (
+	MaxLength   = 2048
+	MaxTerms    = 32
+	MaxInValues = 100
+	maxDepth    = 16
+)
+
+// Type is the type of a filterable field.
+type Type int
+
+const (
+	String Type = iota + 1
+	Number
+	Bool
+	Time
+	// JSON fields are filtered by key: "metadata.manufacturer". The value's
+	// own type (string, number, boolean) decides how the key is compared.
+	JSON
+)
+
+// Fields declares the fields a list endpoint can filter on.
+type Fields map[string]Type
+
+// Op is a comparison operator.
+type Op string
+
+const (
+	Eq       Op = "eq"
+	Ne       Op = "ne"
+	Gt       Op = "gt"
+	Ge       Op = "ge"
+	Lt       Op = "lt"
+	Le       Op = "le"
+	Contains Op = "contains"
+	In       Op = "in"
+)
+
+var ops = map[string]Op{
+	"eq": Eq, "ne": Ne, "gt": Gt, "ge": Ge, "lt": Lt, "le": Le, "contains": Contains, "in": In,
+}
+
+// Expr is a parsed filter: *And, *Or, *Not or *Cmp.
+type Expr interface{ expr() }
+
+// And matches rows both sides match.
+type And struct{ Left, Right Expr }
+
+// Or matches rows either side matches.
+type Or struct{ Left, Right Expr }
+
+// Not matches rows X does not match.
+type Not struct{ X Expr }
+
+// Cmp compares one field with one value, or with a list for In.
+type Cmp struct {
+	Field string
+	// Key is the JSON key of a JSON field, "" otherwise.
+	Key string
+	// Type is the type the comparison runs in: the field's type, or for a
+	// JSON key the type of the value.
+	Type Type
+	Op   Op
+	// Values holds one value, or several for In. A value is a string,
+	// float64, bool or time.Time; nil is null and only pairs with eq/ne.
+	Values []any
+}
+
+func (*And) expr() {}
+func (*Or) expr()  {}
+func (*Not) expr() {}
+func (*Cmp) expr() {}
+
+// Error is a syntax or type error, with the byte offset it was found at.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (at position %d)", e.Msg, e.Pos)
+}
+
+// Parse parses src against fields. The error, when not nil, is an *Error.
+func Parse(src string, fields Fields) (Expr, error) {
+	if len(src) > MaxLength {
+		return nil, &Error{Pos: MaxLength, Msg: fmt.Sprintf("filter is longer than %d characters", MaxLength)}
+	}
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, fields: fields}
+	e, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	return e, nil
+}
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokWord
+	tokString
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokString:
+		return strconv.Quote(t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case c == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case c == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case c == '"':
+			start := i
+			var b strings.Builder
+			i++
+			for {
+				if i >= len(src) {
+					return nil, &Error{Pos: start, Msg: "unterminated string"}
+				}
+				if src[i] == '"' {
+					i++
+					break
+				}
+				if src[i] == '\\' && i+1 < len(src) && (src[i+1] == '"' || src[i+1] == '\\') {
+					i++
+				}
+				b.WriteByte(src[i])
+				i++
+			}
+			toks = append(toks, token{tokString, b.String(), start})
+		default:
+			start := i
+			for i < len(src) && !strings.ContainsRune(" \t\n\r(),\"", rune(src[i])) {
+				i++
+			}
+			toks = append(toks, token{tokWord, src[start:i], start})
+		}
+	}
+	return append(toks, token{tokEOF, "", len(src)}), nil
+}
+
+var (
+	fieldPattern   = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+	jsonKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_\-]{1,64}$`)
+)
+
+type parser struct {
+	toks   []token
+	i      int
+	fields Fields
+	terms  int
+}
+
+func (p *parser) peek() token { return p.toks[p.i] }
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) keyword(kw string) bool {
+	if t := p.peek(); t.kind == tokWord && t.text == kw {
+		p.i++
+		return true
+	}
+	return false
+}
+
+func (p *parser) or(depth int) (Expr, error) {
+	left, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) and(depth int) (Expr, error) {
+	left, err := p.unary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.unary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary(depth int) (Expr, error) {
+	if depth > maxDepth {
+		return nil, &Error{Pos: p.peek().pos, Msg: fmt.Sprintf("filter nests deeper than %d levels", maxDepth)}
+	}
+	if p.keyword("not") {
+		x, err := p.unary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return &Not{X: x}, nil
+	}
+	if t := p.peek(); t.kind == tokLParen {
+		p.next()
+		e, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t := p.next(); t.kind != tokRParen {
+			return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected \")\", found %s", t)}
+		}
+		return e, nil
+	}
+	return p.cmp()
+}
+
+func (p *parser) cmp() (Expr, error) {
+	ft := p.next()
+	if ft.kind != tokWord {
+		return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("expected a field name, found %s", ft)}
+	}
+	p.terms++
+	if p.terms > MaxTerms {
+		return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("filter has more than %d comparisons", MaxTerms)}
+	}
+	c := &Cmp{Field: ft.text}
+	if field, key, ok := strings.Cut(ft.text, "."); ok {
+		if p.fields[field] != JSON || !fieldPattern.MatchString(field) {
+			return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("unknown field %q", ft.text)}
+		}
+		if !jsonKeyPattern.MatchString(key) {
+			return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("invalid key %q: keys are 1-64 letters, digits, '_' or '-'", key)}
+		}
+		c.Field, c.Key = field, key
+	} else {
+		typ, ok := p.fields[ft.text]
+		if !ok || !fieldPattern.MatchString(ft.text) {
+			return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("unknown field %q", ft.text)}
+		}
+		if typ == JSON {
+			return nil, &Error{Pos: ft.pos, Msg: fmt.Sprintf("%s is filtered by key, e.g. %s.serial_number", ft.text, ft.text)}
+		}
+		c.Type = typ
+	}
+
+	ot := p.next()
+	op, ok := ops[ot.text]
+	if ot.kind != tokWord || !ok {
+		return nil, &Error{Pos: ot.pos, Msg: fmt.Sprintf("expected an operator (eq, ne, gt, ge, lt, le, contains, in), found %s", ot)}
+	}
+	c.Op = op
+
+	if op == In {
+		if t := p.next(); t.kind != tokLParen {
+			return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected \"(\" after in, found %s", t)}
+		}
+		for {
+			vt := p.next()
+			if err := p.value(c, vt); err != nil {
+				return nil, err
+			}
+			if len(c.Values) > MaxInValues {
+				return nil, &Error{Pos: vt.pos, Msg: fmt.Sprintf("in takes at most %d values", MaxInValues)}
+			}
+			t := p.next()
+			if t.kind == tokRParen {
+				break
+			}
+			if t.kind != tokComma {
+				return nil, &Error{Pos: t.pos, Msg: fmt.Sprintf("expected \",\" or \")\", found %s", t)}
+			}
+		}
+	} else if err := p.value(c, p.next()); err != nil {
+		return nil, err
+	}
+	return c, checkOp(c, ot.pos)
+}
+
+// value converts t to the comparison's type and appends it to c.Values.
+func (p *parser) value(c *Cmp, t token) error {
+	if t.kind != tokWord && t.kind != tokString {
+		return &Error{Pos: t.pos, Msg: fmt.Sprintf("expected a value, found %s", t)}
+	}
+	if t.kind == tokWord && t.text == "null" {
+		if c.Op != Eq && c.Op != Ne {
+			return &Error{Pos: t.pos, Msg: "null can only be compared with eq or ne"}
+		}
+		c.Values = append(c.Values, nil)
+		return nil
+	}
+
+	typ := c.Type
+	if c.Key != "" {
+		typ = jsonValueType(t)
+		if c.Type != 0 && typ != c.Type {
+			return &Error{Pos: t.pos, Msg: "in values must all be of the same type"}
+		}
+		c.Type = typ
+	}
+
+	switch typ {
+	case String:
+		if t.kind != tokString {
+			return &Error{Pos: t.pos, Msg: fmt.Sprintf("%s takes a quoted string, found %s", c.name(), t)}
+		}
+		c.Values = append(c.Values, t.text)
+	case Number:
+		f, err := strconv.ParseFloat(t.text, 64)
+		if t.kind != tokWord || err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return &Error{Pos: t.pos, Msg: fmt.Sprintf("%s takes a number, found %s", c.name(), t)}
+		}
+		c.Values = append(c.Values, f)
+	case Bool:
+		if t.kind != tokWord || (t.text != "true" && t.text != "false") {
+			return &Error{Pos: t.pos, Msg: fmt.Sprintf("%s takes true or false, found %s", c.name(), t)}
+		}
+		c.Values = append(c.Values, t.text == "true")
+	case Time:
+		ts, ok := parseTime(t.text)
+		if !ok {
+			return &Error{Pos: t.pos, Msg: fmt.Sprintf("%s takes a date (2024-01-31) or RFC 3339 timestamp, found %s", c.name(), t)}
+		}
+		c.Values = append(c.Values, ts)
+	default:
+		return &Error{Pos: t.pos, Msg: fmt.Sprintf("%s takes a quoted string, a number, true or false, found %s", c.name(), t)}
+	}
+	return nil
+}
+
+// jsonValueType is the type a JSON key is compared in, given the value it
+// is compared with. Bare dates compare as strings, which for ISO 8601
+// values is also chronological order.
+func jsonValueType(t token) Type {
+	if t.kind == tokWord {
+		if t.text == "true" || t.text == "false" {
+			return Bool
+		}
+		if f, err := strconv.ParseFloat(t.text, 64); err == nil && !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return Number
+		}
+		if _, ok := parseTime(t.text); ok {
+			return String
+		}
+		return 0
+	}
+	return String
+}
+
+func parseTime(s string) (time.Time, bool) {
+	if ts, err := time.Parse(time.RFC3339, s); err == nil {
+		return ts, true
+	}
+	if ts, err := time.Parse(time.DateOnly, s); err == nil {
+		return ts, true
+	}
+	return time.Time{}, false
+}
+
+func checkOp(c *Cmp, pos int) error {
+	switch {
+	case c.Type == Bool && c.Op != Eq && c.Op != Ne:
+		return &Error{Pos: pos, Msg: fmt.Sprintf("%s can only be compared with eq or ne", c.name())}
+	case c.Op == Contains && c.Type != String:
+		return &Error{Pos: pos, Msg: fmt.Sprintf("contains needs a string field, and %s is not one", c.name())}
+	}
+	return nil
+}
+
+func (c *Cmp) name() string {
+	if c.Key != "" {
+		return c.Field + "." + c.Key
+	}
+	return c.Field
+}
@@ -0,0 +1,112 @@
+package filterexpr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFields = Fields{
+	"name":       String,
+	"team_id":    Number,
+	"is_active":  Bool,
+	"created_at": Time,
+	"metadata":   JSON,
+}
+
+var testColumns = map[string]string{
+	"name":       "a.name",
+	"team_id":    "a.team_id",
+	"is_active":  "a.is_active",
+	"created_at": "a.created_at",
+	"metadata":   "a.metadata",
+}
+
+func compile(t *testing.T, src string) (string, []any) {
+	t.Helper()
+	e, err := Parse(src, testFields)
+	require.NoError(t, err)
+	sql, args, err := SQL(e, testColumns, []any{7})
+	require.NoError(t, err)
+	return sql, args
+}
+
+func TestSQL_CompilesToParameterisedFragment(t *testing.T) {
+	sql, args := compile(t, `metadata.manufacturer eq "Acme" and created_at gt 2024-01-01 or not is_active eq true`)
+
+	assert.Equal(t,
+		`(((a.metadata->>$2::text) = $3::text AND a.created_at > $4::timestamptz) OR `+
+			`(a.is_active = $5::boolean) IS NOT TRUE)`, sql)
+	assert.Equal(t, []any{7, "manufacturer", "Acme", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true}, args)
+}
+
+func TestSQL_Operators(t *testing.T) {
+	cases := []struct {
+		src  string
+		sql  string
+		args []any
+	}{
+		{`name ne "x"`, `a.name IS DISTINCT FROM $2::text`, []any{7, "x"}},
+		{`name eq null`, `a.name IS NULL`, []any{7}},
+		{`name contains "50%_off"`, `a.name ILIKE $2`, []any{7, `%50\%\_off%`}},
+		{`team_id in (1, 2)`, `a.team_id = ANY($2::numeric[])`, []any{7, []float64{1, 2}}},
+		{`metadata.weight ge 2.5`,
+			`(CASE WHEN jsonb_typeof(a.metadata->$2::text) = 'number' THEN (a.metadata->>$2::text)::numeric END) >= $3::numeric`,
+			[]any{7, "weight", 2.5}},
+		{`metadata.serial ne null`, `COALESCE(jsonb_typeof(a.metadata->$2::text), 'null') <> 'null'`, []any{7, "serial"}},
+		{`(name eq "a" or name eq "b")`, `(a.name = $2::text OR a.name = $3::text)`, []any{7, "a", "b"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.src, func(t *testing.T) {
+			sql, args := compile(t, tc.src)
+			assert.Equal(t, tc.sql, sql)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	cases := []struct {
+		src string
+		msg string
+		pos int
+	}{
+		{`owner eq "x"`, `unknown field "owner"`, 0},
+		{`name like "x"`, "expected an operator", 5},
+		{`name eq x`, "name takes a quoted string", 8},
+		{`team_id eq "3"`, "team_id takes a number", 11},
+		{`created_at gt yesterday`, "created_at takes a date", 14},
+		{`is_active gt true`, "is_active can only be compared with eq or ne", 10},
+		{`team_id contains 3`, "contains needs a string field", 8},
+		{`metadata eq "x"`, "metadata is filtered by key", 0},
+		{`metadata.a;b eq "x"`, `invalid key "a;b"`, 0},
+		{`metadata.k in ("a", 1)`, "in values must all be of the same type", 20},
+		{`name gt null`, "null can only be compared with eq or ne", 8},
+		{`(name eq "x"`, `expected ")"`, 12},
+		{`name eq "x" name eq "y"`, `unexpected "name"`, 12},
+		{`name eq "x`, "unterminated string", 8},
+	}
+	for _, tc := range cases {
+		t.Run(tc.src, func(t *testing.T) {
+			_, err := Parse(tc.src, testFields)
+			var fe *Error
+			require.ErrorAs(t, err, &fe)
+			assert.Contains(t, fe.Msg, tc.msg)
+			assert.Equal(t, tc.pos, fe.Pos)
+		})
+	}
+}
+
+func TestParse_Limits(t *testing.T) {
+	_, err := Parse(strings.Repeat(`name eq "x" or `, MaxTerms)+`name eq "x"`, testFields)
+	assert.ErrorContains(t, err, "more than 32 comparisons")
+
+	_, err = Parse(strings.Repeat("(", maxDepth+1)+`name eq "x"`+strings.Repeat(")", maxDepth+1), testFields)
+	assert.ErrorContains(t, err, "nests deeper")
+
+	_, err = Parse(strings.Repeat(" ", MaxLength+1), testFields)
+	assert.ErrorContains(t, err, "longer than")
+}
@@ -0,0 +1,133 @@
+package filterexpr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var sqlOps = map[Op]string{Eq: "=", Ne: "IS DISTINCT FROM", Gt: ">", Ge: ">=", Lt: "<", Le: "<="}
+
+var sqlCasts = map[Type]string{String: "text", Number: "numeric", Bool: "boolean", Time: "timestamptz"}
+
+// SQL compiles e into a WHERE fragment over columns, which maps each field
+// of the Fields e was parsed against to its SQL expression. Values are
+// appended to args and referenced as $n, so the fragment can be ANDed into a
+// query that already binds args. ne also matches rows where the field is
+// null, and contains is a case-insensitive substring match.
+func SQL(e Expr, columns map[string]string, args []any) (string, []any, error) {
+	c := &compiler{columns: columns, args: args}
+	out, err := c.expr(e)
+	return out, c.args, err
+}
+
+type compiler struct {
+	columns map[string]string
+	args    []any
+}
+
+func (c *compiler) bind(v any) string {
+	c.args = append(c.args, v)
+	return fmt.Sprintf("$%d", len(c.args))
+}
+
+func (c *compiler) expr(e Expr) (string, error) {
+	switch e := e.(type) {
+	case *And:
+		return c.binary(e.Left, "AND", e.Right)
+	case *Or:
+		return c.binary(e.Left, "OR", e.Right)
+	case *Not:
+		x, err := c.expr(e.X)
+		if err != nil {
+			return "", err
+		}
+		// A comparison with a null field is null, and NOT null is null too;
+		// a negated filter must still match those rows.
+		return "(" + x + ") IS NOT TRUE", nil
+	case *Cmp:
+		return c.cmp(e)
+	}
+	return "", fmt.Errorf("filterexpr: unexpected expression %T", e)
+}
+
+func (c *compiler) binary(l Expr, op string, r Expr) (string, error) {
+	ls, err := c.expr(l)
+	if err != nil {
+		return "", err
+	}
+	rs, err := c.expr(r)
+	if err != nil {
+		return "", err
+	}
+	return "(" + ls + " " + op + " " + rs + ")", nil
+}
+
+func (c *compiler) cmp(e *Cmp) (string, error) {
+	col, ok := c.columns[e.Field]
+	if !ok {
+		return "", fmt.Errorf("filterexpr: no column for field %q", e.Field)
+	}
+
+	if e.Key != "" {
+		key := c.bind(e.Key) + "::text"
+		if len(e.Values) == 1 && e.Values[0] == nil {
+			cmp := "="
+			if e.Op == Ne {
+				cmp = "<>"
+			}
+			// A missing key and a JSON null both count as null.
+			return fmt.Sprintf("COALESCE(jsonb_typeof(%s->%s), 'null') %s 'null'", col, key, cmp), nil
+		}
+		switch e.Type {
+		case Number:
+			// The CASE keeps the cast from failing on rows where the key
+			// holds something other than a number.
+			col = fmt.Sprintf("(CASE WHEN jsonb_typeof(%s->%s) = 'number' THEN (%s->>%s)::numeric END)", col, key, col, key)
+		case Bool:
+			col = fmt.Sprintf("(CASE WHEN jsonb_typeof(%s->%s) = 'boolean' THEN (%s->>%s)::boolean END)", col, key, col, key)
+		default:
+			col = fmt.Sprintf("(%s->>%s)", col, key)
+		}
+	}
+
+	switch {
+	case len(e.Values) == 1 && e.Values[0] == nil:
+		if e.Op == Ne {
+			return col + " IS NOT NULL", nil
+		}
+		return col + " IS NULL", nil
+	case e.Op == In:
+		return fmt.Sprintf("%s = ANY(%s::%s[])", col, c.bind(typedSlice(e.Type, e.Values)), sqlCasts[e.Type]), nil
+	case e.Op == Contains:
+		return fmt.Sprintf("%s ILIKE %s", col, c.bind("%"+escapeLike(e.Values[0].(string))+"%")), nil
+	}
+	return fmt.Sprintf("%s %s %s::%s", col, sqlOps[e.Op], c.bind(e.Values[0]), sqlCasts[e.Type]), nil
+}
+
+// typedSlice converts in values to a slice of their Go type, which pgx can
+// encode as a Postgres array.
+func typedSlice(t Type, vs []any) any {
+	switch t {
+	case Number:
+		return convert[float64](vs)
+	case Bool:
+		return convert[bool](vs)
+	case Time:
+		return convert[time.Time](vs)
+	}
+	return convert[string](vs)
+}
+
+func convert[T any](vs []any) []T {
+	out := make([]T, len(vs))
+	for i, v := range vs {
+		out[i] = v.(T)
+	}
+	return out
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
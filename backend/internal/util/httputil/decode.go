@@ -145,11 +145,8 @@ func precheckUnknownFields(raw map[string]json.RawMessage, dst any, skip []strin
 // in *JSONDecodeError so the caller does not surface encoding/json
 // internals to the client.
 func DecodeJSON(r *http.Request, dst any) error {
-	body, err := io.ReadAll(r.Body)
+	body, err := readJSONBody(r)
 	if err != nil {
-		return &JSONDecodeError{Cause: err}
-	}
-	if err := rejectNULByteBody(body); err != nil {
 		return err
 	}
 	if err := json.NewDecoder(bytes.NewReader(body)).Decode(dst); err != nil {
@@ -167,11 +164,8 @@ func DecodeJSON(r *http.Request, dst any) error {
 // caller can render one fields[] entry per invalid field. The strict
 // decoder only reports the first unknown key on its own.
 func DecodeJSONStrict(r *http.Request, dst any) error {
-	body, err := io.ReadAll(r.Body)
+	body, err := readJSONBody(r)
 	if err != nil {
-		return &JSONDecodeError{Cause: err}
-	}
-	if err := rejectNULByteBody(body); err != nil {
 		return err
 	}
 	var raw map[string]json.RawMessage
@@ -188,6 +182,100 @@ func DecodeJSONStrict(r *http.Request, dst any) error {
 	return nil
 }
 
+// DecodeJSONObjectStrict strictly decodes one JSON object already in memory
+// (e.g. one element of a batch) into dst, reporting which top-level keys were
+// present and which held an explicit null. Unknown keys fail with a
+// *JSONUnknownFieldsError naming all of them; a non-object fails with a
+// *JSONDecodeError.
+func DecodeJSONObjectStrict(body []byte, dst any) (nulls, present map[string]struct{}, err error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil || raw == nil {
+		return nil, nil, &JSONDecodeError{Cause: errors.New("value must be a JSON object")}
+	}
+	nulls, present = map[string]struct{}{}, map[string]struct{}{}
+	for k, v := range raw {
+		present[k] = struct{}{}
+		if bytes.Equal(bytes.TrimSpace(v), []byte("null")) {
+			nulls[k] = struct{}{}
+		}
+	}
+	if ufe := precheckUnknownFields(raw, dst, nil); ufe != nil {
+		return nulls, present, ufe
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return nulls, present, &JSONDecodeError{Cause: err}
+	}
+	return nulls, present, nil
+}
+
+// MaxJSONDepth caps how deeply a request body may nest objects and arrays.
+// encoding/json recurses once per level, so a small body of repeated `[`
+// can pin a goroutine's stack; no TrakRF schema — free-form metadata
+// included — comes close to this.
+const MaxJSONDepth = 32
+
+// JSONTooDeepError signals a body nested deeper than MaxJSONDepth.
+type JSONTooDeepError struct {
+	Limit int
+}
+
+func (e *JSONTooDeepError) Error() string {
+	return fmt.Sprintf("request body nests deeper than %d levels", e.Limit)
+}
+
+// readJSONBody reads the whole body and applies the byte-level screens every
+// decode helper shares. A read that trips the middleware.MaxBodySize cap
+// surfaces the *http.MaxBytesError (wrapped) so RespondDecodeError can map it
+// to 413.
+func readJSONBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, &JSONDecodeError{Cause: err}
+	}
+	if err := rejectNULByteBody(body); err != nil {
+		return nil, err
+	}
+	if err := checkJSONDepth(body, MaxJSONDepth); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// checkJSONDepth scans raw JSON for object/array nesting beyond limit
+// without decoding it. Brackets inside strings are skipped; malformed input
+// is left for the decoder to report.
+func checkJSONDepth(body []byte, limit int) error {
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range body {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+			if depth > limit {
+				return &JSONTooDeepError{Limit: limit}
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+	return nil
+}
+
 // rejectNULByteBody returns a JSONDecodeError when the raw body bytes
 // contain a NUL byte. Postgres TEXT columns reject NUL outright (SQLSTATE
 // 22021); a NUL anywhere in the body — including nested JSON strings
@@ -212,11 +300,8 @@ func rejectNULByteBody(body []byte) error {
 // A non-object body produces an empty key set and the usual strict-decode
 // failure.
 func DecodeJSONStrictWithPresence(r *http.Request, dst any) (map[string]struct{}, error) {
-	body, err := io.ReadAll(r.Body)
+	body, err := readJSONBody(r)
 	if err != nil {
-		return nil, &JSONDecodeError{Cause: err}
-	}
-	if err := rejectNULByteBody(body); err != nil {
 		return nil, err
 	}
 	present := map[string]struct{}{}
@@ -529,11 +614,8 @@ func RejectFields(w http.ResponseWriter, r *http.Request, requestID string, poli
 }
 
 func decodeStrictWithNullsTolerant(r *http.Request, dst any, drop []string) (map[string]struct{}, map[string]struct{}, error) {
-	body, err := io.ReadAll(r.Body)
+	body, err := readJSONBody(r)
 	if err != nil {
-		return nil, nil, &JSONDecodeError{Cause: err}
-	}
-	if err := rejectNULByteBody(body); err != nil {
 		return nil, nil, err
 	}
 
@@ -611,6 +693,19 @@ func decodeStrictWithNullsTolerant(r *http.Request, dst any, drop []string) (map
 // WriteValidationError (echoes fields[0].Message + "(and N more ...)" suffix).
 func RespondDecodeError(w http.ResponseWriter, r *http.Request, err error, requestID string) {
 	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			Respond413(w, r, mbe.Limit, requestID)
+			return
+		}
+
+		var tde *JSONTooDeepError
+		if errors.As(err, &tde) {
+			WriteJSONError(w, r, http.StatusBadRequest, apierrors.ErrBadRequest,
+				fmt.Sprintf("Request body must not nest objects or arrays more than %d levels deep", tde.Limit), requestID)
+			return
+		}
+
 		// TRA-707 / BB32 C3: literal `null` body — surface RFC 7396 wording
 		// rather than the generic "not valid JSON" fallback. `null` is
 		// structurally valid JSON, so the parse-error wording misdiagnoses
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
@@ -936,3 +937,54 @@ func TestDecodeJSONStrictWithNullsTolerant_EmptyDropListEquivalent(t *testing.T)
 		t.Fatalf("expected unknown-field error with empty drop list, got nil")
 	}
 }
+
+func TestDecodeJSON_RejectsDeepNesting(t *testing.T) {
+	deep := strings.Repeat("[", httputil.MaxJSONDepth+1) + strings.Repeat("]", httputil.MaxJSONDepth+1)
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"metadata":`+deep+`}`))
+	var got map[string]any
+	err := httputil.DecodeJSON(r, &got)
+	var tde *httputil.JSONTooDeepError
+	if !errors.As(err, &tde) {
+		t.Fatalf("expected *JSONTooDeepError, got %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	httputil.RespondDecodeError(w, r, err, "req-1")
+	if w.Code != 400 {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+}
+
+func TestDecodeJSON_BracketsInStringsDoNotCount(t *testing.T) {
+	s := strings.Repeat("[{", httputil.MaxJSONDepth)
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"`+s+`\"]"}`))
+	var got struct {
+		Name string `json:"name"`
+	}
+	if err := httputil.DecodeJSONStrict(r, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRespondDecodeError_BodyTooLarge_Is413(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", strings.NewReader(`{"name":"`+strings.Repeat("a", 64)+`"}`))
+	r.Body = http.MaxBytesReader(w, r.Body, 16)
+
+	var got struct {
+		Name string `json:"name"`
+	}
+	err := httputil.DecodeJSONStrict(r, &got)
+	httputil.RespondDecodeError(w, r, err, "req-1")
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("status = %d, want 413", w.Code)
+	}
+	var resp apierrors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode resp: %v", err)
+	}
+	if resp.Error.Type != string(apierrors.ErrPayloadTooLarge) {
+		t.Fatalf("type = %q, want payload_too_large", resp.Error.Type)
+	}
+}
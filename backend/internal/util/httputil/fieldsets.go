@@ -0,0 +1,97 @@
+package httputil
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	apierrors "github.com/trakrf/platform/backend/internal/models/errors"
+)
+
+// ParseSort parses a `sort` value (comma-separated, '-' prefix for DESC)
+// against allowed, for list endpoints that paginate outside
+// ParseListParams. Errors are *ListParamError, as from ParseListParams.
+func ParseSort(raw string, allowed []string) ([]SortField, error) {
+	return parseSort(raw, toSet(allowed))
+}
+
+// ParseFields parses a `fields` sparse-fieldset value (comma-separated
+// top-level keys of a list item) against allowed. A blank value selects
+// every field and returns nil. Errors are *ListParamError.
+func ParseFields(raw string, allowed []string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	if len(allowed) == 0 {
+		return nil, &ListParamError{Fields: []apierrors.FieldError{{
+			Field:   "fields",
+			Code:    "invalid_value",
+			Message: "fields parameter not supported on this endpoint",
+		}}}
+	}
+	allow := toSet(allowed)
+	parts := strings.Split(raw, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		field := strings.TrimSpace(p)
+		if _, ok := allow[field]; !ok {
+			return nil, &ListParamError{Fields: []apierrors.FieldError{{
+				Field:   "fields",
+				Code:    "invalid_value",
+				Message: fmt.Sprintf("unknown field: %s", field),
+			}}}
+		}
+		out = append(out, field)
+	}
+	return out, nil
+}
+
+// WriteSparseJSON writes v like WriteJSON, keeping only the named fields
+// (plus id, so every item stays addressable) on each item of v's top-level
+// `data` array. With no fields it is WriteJSON. Pagination and other
+// envelope keys are untouched.
+func WriteSparseJSON(w http.ResponseWriter, status int, v any, fields []string) error {
+	if len(fields) == 0 {
+		return WriteJSON(w, status, v)
+	}
+	raw, err := marshalUnescaped(v)
+	if err != nil {
+		return err
+	}
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return err
+	}
+	var items []map[string]json.RawMessage
+	if err := json.Unmarshal(envelope["data"], &items); err != nil {
+		return err
+	}
+
+	keep := toSet(fields)
+	keep["id"] = struct{}{}
+	for _, item := range items {
+		for k := range item {
+			if _, ok := keep[k]; !ok {
+				delete(item, k)
+			}
+		}
+	}
+	if envelope["data"], err = marshalUnescaped(items); err != nil {
+		return err
+	}
+	return WriteJSON(w, status, envelope)
+}
+
+// marshalUnescaped is json.Marshal without HTML escaping, matching the
+// bytes encodeBody writes.
+func marshalUnescaped(v any) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
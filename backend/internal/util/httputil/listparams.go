@@ -16,8 +16,8 @@ const (
 	maxListLimit     = 200
 )
 
-// ListAllowlist declares which filter and sort fields the endpoint accepts.
-// limit, offset, and sort are always allowed.
+// ListAllowlist declares which filter, sort and sparse-fieldset fields the
+// endpoint accepts. limit, offset, sort and fields are always allowed.
 //
 // BoolFilters is a subset of Filters; values for declared boolean filters
 // must be exact lowercase `true` or `false`. Mixed-case variants (True, TRUE,
@@ -29,6 +29,8 @@ type ListAllowlist struct {
 	Filters     []string
 	BoolFilters []string
 	Sorts       []string
+	// Fields names the item keys `fields=` may select; see WriteSparseJSON.
+	Fields []string
 }
 
 // SortField represents one entry in a sort list.
@@ -43,6 +45,8 @@ type ListParams struct {
 	Offset  int
 	Filters map[string][]string
 	Sorts   []SortField
+	// Fields is the sparse fieldset; nil selects every field.
+	Fields []string
 }
 
 // ListParamError reports one or more query-parameter validation failures.
@@ -147,6 +151,12 @@ func ParseListParams(r *http.Request, allow ListAllowlist) (ListParams, error) {
 				return out, err
 			}
 			out.Sorts = parsed
+		case "fields":
+			parsed, err := ParseFields(values[0], allow.Fields)
+			if err != nil {
+				return out, err
+			}
+			out.Fields = parsed
 		default:
 			if _, ok := filterAllow[key]; !ok {
 				// TRA-739 (BB42 F8): an unknown filter key is a *field-shaped*
@@ -304,6 +314,7 @@ var knownListFilters = map[string]struct{}{
 	"asset_external_key":    {},
 	"asset_id":              {},
 	"external_key":          {},
+	"filter":                {},
 	"include_deleted":       {},
 	"is_active":             {},
 	"location_external_key": {},
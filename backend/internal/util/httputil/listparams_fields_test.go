@@ -0,0 +1,56 @@
+package httputil_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/util/httputil"
+)
+
+func TestParseListParams_Fields(t *testing.T) {
+	allow := httputil.ListAllowlist{Fields: []string{"id", "name", "tags"}}
+
+	p, err := httputil.ParseListParams(httptest.NewRequest("GET", "/?fields=name,+tags", nil), allow)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "tags"}, p.Fields)
+
+	p, err = httputil.ParseListParams(httptest.NewRequest("GET", "/?fields=", nil), allow)
+	require.NoError(t, err)
+	assert.Nil(t, p.Fields)
+
+	_, err = httputil.ParseListParams(httptest.NewRequest("GET", "/?fields=name,secret", nil), allow)
+	var lpe *httputil.ListParamError
+	require.True(t, errors.As(err, &lpe))
+	assert.Equal(t, "fields", lpe.Fields[0].Field)
+	assert.Equal(t, "unknown field: secret", lpe.Fields[0].Message)
+
+	_, err = httputil.ParseListParams(httptest.NewRequest("GET", "/?fields=name", nil), httputil.ListAllowlist{})
+	require.True(t, errors.As(err, &lpe))
+	assert.Equal(t, "fields parameter not supported on this endpoint", lpe.Fields[0].Message)
+}
+
+func TestWriteSparseJSON_KeepsIDAndSelectedFields(t *testing.T) {
+	type item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+		Note string `json:"note"`
+	}
+	type envelope struct {
+		Data       []item `json:"data"`
+		TotalCount int    `json:"total_count"`
+	}
+	resp := envelope{Data: []item{{ID: 1, Name: "Dock <A>", Note: "x"}}, TotalCount: 1}
+
+	w := httptest.NewRecorder()
+	require.NoError(t, httputil.WriteSparseJSON(w, 200, resp, []string{"name"}))
+	assert.JSONEq(t, `{"data":[{"id":1,"name":"Dock <A>"}],"total_count":1}`, w.Body.String())
+	assert.Contains(t, w.Body.String(), "Dock <A>")
+
+	w = httptest.NewRecorder()
+	require.NoError(t, httputil.WriteSparseJSON(w, 200, resp, nil))
+	assert.JSONEq(t, `{"data":[{"id":1,"name":"Dock <A>","note":"x"}],"total_count":1}`, w.Body.String())
+}
@@ -1,8 +1,12 @@
 package httputil
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	apierrors "github.com/trakrf/platform/backend/internal/models/errors"
 )
@@ -56,6 +60,36 @@ func Respond415Detail(w http.ResponseWriter, r *http.Request, detail, requestID
 		detail, requestID)
 }
 
+// Respond413 writes the normalized payload-too-large response for a request
+// body that exceeded its middleware.MaxBodySize cap of limit bytes.
+func Respond413(w http.ResponseWriter, r *http.Request, limit int64, requestID string) {
+	WriteJSONError(w, r, http.StatusRequestEntityTooLarge, apierrors.ErrPayloadTooLarge,
+		fmt.Sprintf("Request body must not exceed %d bytes", limit), requestID)
+}
+
+// Respond429 writes the normalized rate-limited response, with Retry-After
+// rounded up to whole seconds and never below one.
+func Respond429(w http.ResponseWriter, r *http.Request, retryAfter time.Duration, requestID string) {
+	retrySec := int(math.Ceil(retryAfter.Seconds()))
+	if retrySec < 1 {
+		retrySec = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retrySec))
+	WriteJSONError(w, r, http.StatusTooManyRequests, apierrors.ErrRateLimited,
+		fmt.Sprintf("Retry after %d seconds", retrySec), requestID)
+}
+
+// Respond503 writes the normalized service-unavailable response, with
+// Retry-After rounded up to whole seconds and never below one.
+func Respond503(w http.ResponseWriter, r *http.Request, retryAfter time.Duration, detail, requestID string) {
+	retrySec := int(math.Ceil(retryAfter.Seconds()))
+	if retrySec < 1 {
+		retrySec = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(retrySec))
+	WriteJSONError(w, r, http.StatusServiceUnavailable, apierrors.ErrUnavailable, detail, requestID)
+}
+
 // RespondMissingOrgContext writes the canonical 422 envelope used when
 // auth has succeeded but the request lacks an active organization context.
 //
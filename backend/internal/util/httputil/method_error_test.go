@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	apierrors "github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
@@ -151,3 +152,27 @@ func TestRespondMissingOrgContext_EnvelopeShape(t *testing.T) {
 		t.Errorf("request_id = %q, want req-mo", resp.Error.RequestID)
 	}
 }
+
+// TestRespond503_KeepsDetail verifies the maintenance response: Retry-After is
+// rounded up, and the detail reaches the caller instead of the generic 5xx
+// message.
+func TestRespond503_KeepsDetail(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/v1/assets", nil)
+	httputil.Respond503(w, r, 1500*time.Millisecond, "TrakRF is read-only for maintenance: upgrade", "req-5")
+
+	if w.Code != 503 {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+	var resp apierrors.ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Error.Type != string(apierrors.ErrUnavailable) {
+		t.Errorf("type = %q, want %q", resp.Error.Type, apierrors.ErrUnavailable)
+	}
+	if resp.Error.Detail != "TrakRF is read-only for maintenance: upgrade" {
+		t.Errorf("detail = %q", resp.Error.Detail)
+	}
+}
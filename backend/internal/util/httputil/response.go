@@ -7,7 +7,9 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/trakrf/platform/backend/internal/i18n"
 	"github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/util/pii"
 )
 
 // modulePathPattern matches Go module paths (e.g. github.com/owner/repo/pkg.Func,
@@ -19,13 +21,16 @@ var modulePathPattern = regexp.MustCompile(`\b[a-z0-9][a-z0-9.-]*\.[a-z]{2,}/[A-
 
 // sanitizeDetail scrubs internal module paths from a detail string before it
 // reaches the client. The placeholder preserves message structure so callers
-// can still read the surrounding cause text.
+// can still read the surrounding cause text. Personal data and credentials,
+// such as a row Postgres quotes back in a constraint error, are redacted
+// first (see pii.Redact).
 //
 // TRA-739 (BB42 F1): a match preceded by "://" is part of a legitimate URL
 // (e.g. https://docs.trakrf.id/docs/api/data-model) and is preserved verbatim;
 // only bare host/path runs collapse to [internal]. Previously the regex
 // collided with any cited documentation URL.
 func sanitizeDetail(detail string) string {
+	detail = pii.Redact(detail)
 	matches := modulePathPattern.FindAllStringIndex(detail, -1)
 	if len(matches) == 0 {
 		return detail
@@ -63,6 +68,7 @@ type ErrorResponse struct {
 		Instance  string              `json:"instance"`
 		RequestID string              `json:"request_id"`
 		Fields    []errors.FieldError `json:"fields,omitempty"`
+		Locale    string              `json:"locale"`
 	} `json:"error"`
 }
 
@@ -75,15 +81,22 @@ type ErrorResponse struct {
 //     (e.g. "asset id 999 is invalid", err.Error() text). May be empty when
 //     the type alone fully describes the condition.
 //
+// detail (and fields[].message) are translated into the request's negotiated
+// locale (see i18n and middleware.Locale), which is echoed as error.locale.
+// type and title are never translated: clients branch on them.
+//
 // Module paths in detail are scrubbed before the response is written so that
 // internal package structure cannot leak through wrapped errors. 5xx
 // responses additionally replace detail with a fixed generic message
 // (TRA-673) so DB driver internals — pgx int4-encoding diagnostics, OIDs,
 // SQLSTATE chatter — never reach the client. The original detail is
-// retained in the server-side slog record for debugging.
+// retained in the server-side slog record for debugging. A 503 of type
+// service_unavailable is exempt: it is a deliberate refusal (maintenance)
+// whose detail is written for the caller.
 func WriteJSONError(w http.ResponseWriter, r *http.Request, status int, errType errors.ErrorType, detail, requestID string) {
 	rawDetail := detail
-	detail = sanitizeDetail(detail)
+	locale := i18n.FromContext(r.Context())
+	detail = i18n.T(locale, sanitizeDetail(detail))
 
 	resp := ErrorResponse{}
 	resp.Error.Type = string(errType)
@@ -91,15 +104,16 @@ func WriteJSONError(w http.ResponseWriter, r *http.Request, status int, errType
 	resp.Error.Status = status
 	resp.Error.Instance = r.URL.Path
 	resp.Error.RequestID = requestID
+	resp.Error.Locale = locale
 
-	if status >= 500 {
+	if status >= 500 && errType != errors.ErrUnavailable {
 		slog.Error("Error response",
 			"status", status,
 			"type", errType,
 			"detail", rawDetail,
 			"request_id", requestID,
 			"path", r.URL.Path)
-		resp.Error.Detail = genericServerErrorDetail
+		resp.Error.Detail = i18n.T(locale, genericServerErrorDetail)
 	} else {
 		resp.Error.Detail = detail
 		slog.Info("Client error",
@@ -124,7 +138,16 @@ func WriteJSON(w http.ResponseWriter, status int, data any) error {
 // WriteJSONErrorWithFields is WriteJSONError plus a populated fields[]
 // array. Used by RespondValidationError.
 func WriteJSONErrorWithFields(w http.ResponseWriter, r *http.Request, status int, errType errors.ErrorType, detail, requestID string, fields []errors.FieldError) {
-	detail = sanitizeDetail(detail)
+	locale := i18n.FromContext(r.Context())
+	detail = i18n.T(locale, sanitizeDetail(detail))
+	if len(fields) > 0 {
+		scrubbed := make([]errors.FieldError, len(fields))
+		for i, f := range fields {
+			f.Message = i18n.T(locale, pii.Redact(f.Message))
+			scrubbed[i] = f
+		}
+		fields = scrubbed
+	}
 	resp := ErrorResponse{}
 	resp.Error.Type = string(errType)
 	resp.Error.Title = errors.TitleForType(errType)
@@ -133,6 +156,7 @@ func WriteJSONErrorWithFields(w http.ResponseWriter, r *http.Request, status int
 	resp.Error.Instance = r.URL.Path
 	resp.Error.RequestID = requestID
 	resp.Error.Fields = fields
+	resp.Error.Locale = locale
 
 	slog.Info("Validation error",
 		"status", status,
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/trakrf/platform/backend/internal/i18n"
 	apierrors "github.com/trakrf/platform/backend/internal/models/errors"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
 )
@@ -131,3 +132,91 @@ func TestWriteJSONErrorWithFields_MixedURLAndModulePath(t *testing.T) {
 	assert.Contains(t, resp.Error.Detail, "[internal]")
 	assert.Contains(t, resp.Error.Detail, "https://docs.trakrf.id/docs/api/data-model")
 }
+
+func TestWriteJSONError_TranslatesDetailNotTitle(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/v1/assets/9", nil)
+	r = r.WithContext(i18n.WithLocale(r.Context(), "es"))
+
+	httputil.WriteJSONError(w, r, 404, apierrors.ErrNotFound, "Asset not found", "req-1")
+
+	var resp httputil.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Activo no encontrado", resp.Error.Detail)
+	assert.Equal(t, "Not found", resp.Error.Title, "title is a stable branch key and stays English")
+	assert.Equal(t, "es", resp.Error.Locale)
+}
+
+func TestWriteValidationError_TranslatesFieldMessages(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/v1/assets", nil)
+	r = r.WithContext(i18n.WithLocale(r.Context(), "fr"))
+
+	httputil.WriteValidationError(w, r, "req-1", []apierrors.FieldError{
+		{Field: "name", Code: "required", Message: "name is required"},
+		{Field: "external_key", Code: "required", Message: "external_key is required"},
+	})
+
+	var resp httputil.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "name est obligatoire (et 1 autre erreur de validation)", resp.Error.Detail)
+	require.Len(t, resp.Error.Fields, 2)
+	assert.Equal(t, "external_key est obligatoire", resp.Error.Fields[1].Message)
+	assert.Equal(t, "required", resp.Error.Fields[1].Code)
+}
+
+func TestWriteJSONError_DefaultLocaleIsEnglish(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/api/v1/assets/9", nil)
+
+	httputil.WriteJSONError(w, r, 404, apierrors.ErrNotFound, "Asset not found", "req-1")
+
+	var resp httputil.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Asset not found", resp.Error.Detail)
+	assert.Equal(t, "en", resp.Error.Locale)
+}
+
+// No personal data reaches a 4xx detail, even when a handler passes a raw
+// database error through as the detail: Postgres quotes the offending row
+// back in its messages.
+func TestWriteJSONError_4xxRedactsPersonalData(t *testing.T) {
+	details := map[string]string{
+		"unique violation": `failed to create user: ERROR: duplicate key value violates unique constraint "users_email_key" (SQLSTATE 23505): Key (email)=(dana.ortiz@acme.co) already exists.`,
+		"check violation":  `ERROR: new row for relation "users" violates check constraint "users_name_check" (SQLSTATE 23514): Failing row contains (7, Dana Ortiz, dana.ortiz@acme.co).`,
+		"invalid input":    `ERROR: invalid input syntax for type uuid: "Dana Ortiz" (SQLSTATE 22P02)`,
+		"badge tag":        `ERROR: tag value E2801160600002086E4BDA11 is already in use as a ble tag (SQLSTATE 23505)`,
+		"email in message": `user dana.ortiz@acme.co is already a member`,
+		"reset token":      `reset token 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08 has expired`,
+	}
+	leaks := []string{"dana.ortiz@", "Dana Ortiz", "E2801160600002086E4BDA11", "9f86d081884c7d659a2feaa0c55ad015"}
+
+	for name, detail := range details {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/api/v1/users", nil)
+			httputil.WriteJSONError(w, r, 409, apierrors.ErrConflict, detail, "req-1")
+
+			var resp httputil.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Contains(t, resp.Error.Detail, "<redacted>")
+			for _, leak := range leaks {
+				assert.NotContains(t, resp.Error.Detail, leak)
+			}
+		})
+	}
+}
+
+func TestWriteValidationError_RedactsPersonalDataFromFields(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/api/v1/users/invite", nil)
+	httputil.WriteValidationError(w, r, "req-1", []apierrors.FieldError{
+		{Field: "email", Code: "invalid_value", Message: "dana.ortiz@acme.co is already invited"},
+	})
+
+	var resp httputil.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.NotContains(t, w.Body.String(), "dana.ortiz@")
+	require.Len(t, resp.Error.Fields, 1)
+	assert.Equal(t, "<redacted>@acme.co is already invited", resp.Error.Fields[0].Message)
+}
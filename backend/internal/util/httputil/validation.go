@@ -13,6 +13,8 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	apierrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/filterexpr"
 )
 
 // JSONTagNameFunc makes validator.Field() report the JSON tag name (e.g.
@@ -57,6 +59,49 @@ func ValidateExternalKeyFilterValues(field string, values []string) *apierrors.F
 	return nil
 }
 
+// ParseFilterParam parses the `filter` list parameter against fields. It
+// returns a nil Expr when the parameter is absent or blank, and an
+// invalid_value FieldError naming the position of a syntax or type error.
+func ParseFilterParam(values []string, fields filterexpr.Fields) (filterexpr.Expr, *apierrors.FieldError) {
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+		return nil, nil
+	}
+	if len(values) > 1 {
+		return nil, &apierrors.FieldError{
+			Field:   "filter",
+			Code:    "invalid_value",
+			Message: "filter may be given once; combine conditions with and / or",
+		}
+	}
+	e, err := filterexpr.Parse(values[0], fields)
+	if err != nil {
+		return nil, &apierrors.FieldError{
+			Field:   "filter",
+			Code:    "invalid_value",
+			Message: "invalid filter: " + err.Error(),
+		}
+	}
+	return e, nil
+}
+
+// ParseAsOfParam parses the `as_of` query parameter: an RFC 3339 instant at
+// which the temporal validity window is evaluated instead of now. It returns
+// nil when the parameter is absent or blank.
+func ParseAsOfParam(values []string) (*time.Time, *apierrors.FieldError) {
+	if len(values) == 0 || strings.TrimSpace(values[0]) == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(values[0]))
+	if err != nil {
+		return nil, &apierrors.FieldError{
+			Field:   "as_of",
+			Code:    "invalid_value",
+			Message: "Invalid 'as_of' timestamp; expected RFC 3339, e.g. 2026-04-21T00:00:00.000Z",
+		}
+	}
+	return &t, nil
+}
+
 // ValidateValidityWindow enforces the half-open temporal validity contract
 // shared by every public resource that exposes paired `valid_from` /
 // `valid_to` columns (assets, locations). The window is open at `valid_to`
@@ -111,6 +156,22 @@ func RegisterCustomValidations(v *validator.Validate) {
 	_ = v.RegisterValidation("display_name", func(fl validator.FieldLevel) bool {
 		return isValidDisplayName(fl.Field().String())
 	})
+	v.RegisterStructValidation(validateTagRequest, shared.TagRequest{})
+}
+
+// validateTagRequest checks a tag value against its tag_type's format. It
+// reports as tag `tag_value` on `value` with the tag type as the param, so
+// messageForField can name the specific rule that failed. Values the field
+// validators already reject (empty, oversized, control characters) and
+// unknown tag types are skipped to avoid a second error for the same fault.
+func validateTagRequest(sl validator.StructLevel) {
+	req := sl.Current().Interface().(shared.TagRequest)
+	if req.TagType == nil || req.Value == "" || len(req.Value) > 255 || containsDisallowedControl(req.Value) {
+		return
+	}
+	if shared.ValidateTagValue(*req.TagType, req.Value) != nil {
+		sl.ReportError(req.Value, "value", "Value", "tag_value", *req.TagType)
+	}
 }
 
 // containsDisallowedControl reports whether s contains a C0 control
@@ -304,6 +365,13 @@ func messageForField(fe validator.FieldError) string {
 			// handled by minLength/required and produces code: too_short.
 			return fmt.Sprintf("%s must not start or end with whitespace, must not contain control characters (including tab, newline, carriage return), and must not be only whitespace", fe.Field())
 		}
+		if fe.Tag() == "tag_value" {
+			if v, ok := fe.Value().(string); ok {
+				if err := shared.ValidateTagValue(fe.Param(), v); err != nil {
+					return fmt.Sprintf("%s %s", fe.Field(), err)
+				}
+			}
+		}
 		return fmt.Sprintf("%s is not a valid value", fe.Field())
 	}
 	return fmt.Sprintf("%s failed validation", fe.Field())
@@ -349,6 +417,9 @@ func paramsForField(fe validator.FieldError) map[string]any {
 		if fe.Tag() == "external_key_pattern" {
 			return map[string]any{"pattern": ExternalKeyPattern.String()}
 		}
+		if fe.Tag() == "tag_value" {
+			return map[string]any{"tag_type": fe.Param()}
+		}
 	case "too_short":
 		// fe.Param() is "" when this code came from a relabeled `required`
 		// tag (TRA-637); the implicit minimum is 1 in that case.
@@ -403,12 +474,24 @@ func RespondValidationErrorWithPresence(w http.ResponseWriter, r *http.Request,
 }
 
 func respondValidationErrorCore(w http.ResponseWriter, r *http.Request, err error, requestID string, present, nulls map[string]struct{}) {
-	var ves validator.ValidationErrors
-	if !errors.As(err, &ves) {
+	fields := ValidationFieldErrors(err, present, nulls)
+	if fields == nil {
 		WriteJSONError(w, r, http.StatusBadRequest, apierrors.ErrBadRequest,
 			"Request validation failed", requestID)
 		return
 	}
+	WriteValidationError(w, r, requestID, fields)
+}
+
+// ValidationFieldErrors is the fields[] RespondValidationErrorWithPresence
+// would write for err, for callers that report violations without failing
+// the request (e.g. per-item results of a batch). It returns nil when err is
+// not a validator.ValidationErrors.
+func ValidationFieldErrors(err error, present, nulls map[string]struct{}) []apierrors.FieldError {
+	var ves validator.ValidationErrors
+	if !errors.As(err, &ves) {
+		return nil
+	}
 	fields := make([]apierrors.FieldError, 0, len(ves))
 	for _, fe := range ves {
 		code := codeForTag(fe)
@@ -436,7 +519,7 @@ func respondValidationErrorCore(w http.ResponseWriter, r *http.Request, err erro
 			Params:  paramsForFieldWithCode(fe, code),
 		})
 	}
-	WriteValidationError(w, r, requestID, fields)
+	return fields
 }
 
 // WriteValidationError writes a 400 validation_error envelope with the
@@ -10,6 +10,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apierrors "github.com/trakrf/platform/backend/internal/models/errors"
+	"github.com/trakrf/platform/backend/internal/models/shared"
+	"github.com/trakrf/platform/backend/internal/util/filterexpr"
 	"github.com/trakrf/platform/backend/internal/util/httputil"
 )
 
@@ -487,3 +489,83 @@ func TestDisplayNameValidator_AcceptsAndRejects(t *testing.T) {
 		})
 	}
 }
+
+// A tag value that does not fit its tag_type surfaces as invalid_value on
+// `value`, with a message naming the rule and the tag type in params. Nested
+// tags (create bodies dive into []TagRequest) are checked the same way.
+func TestTagRequestValidator_RejectsValueForType(t *testing.T) {
+	v := validator.New()
+	v.RegisterTagNameFunc(httputil.JSONTagNameFunc)
+	httputil.RegisterCustomValidations(v)
+
+	str := func(s string) *string { return &s }
+	type create struct {
+		Tags []shared.TagRequest `json:"tags" validate:"omitempty,dive"`
+	}
+
+	assert.NoError(t, v.Struct(shared.TagRequest{TagType: str("rfid"), Value: "E28011606000020A1B2C3D4E"}))
+	assert.NoError(t, v.Struct(shared.TagRequest{TagType: str("barcode"), Value: "4006381333931"}))
+
+	err := v.Struct(create{Tags: []shared.TagRequest{
+		{TagType: str("ble"), Value: "AA:BB:CC:DD:EE:FF"},
+		{TagType: str("barcode"), Value: "4006381333932"},
+	}})
+	require.Error(t, err)
+
+	w := httptest.NewRecorder()
+	httputil.RespondValidationError(w, httptest.NewRequest("POST", "/", nil), err, "req-1")
+
+	var resp apierrors.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Error.Fields, 1)
+	f := resp.Error.Fields[0]
+	assert.Equal(t, "value", f.Field)
+	assert.Equal(t, "invalid_value", f.Code)
+	assert.Equal(t, "value has an invalid GTIN-13 check digit for tag_type barcode: expected 1, got 2", f.Message)
+	assert.Equal(t, map[string]any{"tag_type": "barcode"}, f.Params)
+
+	// An empty value is reported once, by `required`, not again by tag_value.
+	err = v.Struct(shared.TagRequest{TagType: str("rfid"), Value: ""})
+	var ves validator.ValidationErrors
+	require.ErrorAs(t, err, &ves)
+	require.Len(t, ves, 1)
+	assert.Equal(t, "required", ves[0].Tag())
+}
+
+func TestParseFilterParam(t *testing.T) {
+	fields := filterexpr.Fields{"name": filterexpr.String}
+
+	e, fe := httputil.ParseFilterParam(nil, fields)
+	assert.Nil(t, e)
+	assert.Nil(t, fe)
+
+	e, fe = httputil.ParseFilterParam([]string{`name eq "Dock"`}, fields)
+	assert.NotNil(t, e)
+	assert.Nil(t, fe)
+
+	_, fe = httputil.ParseFilterParam([]string{`name eq Dock`}, fields)
+	require.NotNil(t, fe)
+	assert.Equal(t, "filter", fe.Field)
+	assert.Equal(t, "invalid_value", fe.Code)
+	assert.Equal(t, `invalid filter: name takes a quoted string, found "Dock" (at position 8)`, fe.Message)
+
+	_, fe = httputil.ParseFilterParam([]string{`name eq "a"`, `name eq "b"`}, fields)
+	require.NotNil(t, fe)
+	assert.Equal(t, "invalid_value", fe.Code)
+}
+
+func TestParseAsOfParam(t *testing.T) {
+	at, fe := httputil.ParseAsOfParam(nil)
+	assert.Nil(t, at)
+	assert.Nil(t, fe)
+
+	at, fe = httputil.ParseAsOfParam([]string{"2026-04-21T15:00:00Z"})
+	require.Nil(t, fe)
+	require.NotNil(t, at)
+	assert.Equal(t, 2026, at.Year())
+
+	_, fe = httputil.ParseAsOfParam([]string{"yesterday"})
+	require.NotNil(t, fe)
+	assert.Equal(t, "as_of", fe.Field)
+	assert.Equal(t, "invalid_value", fe.Code)
+}
@@ -64,7 +64,7 @@ func ValidateAccessToken(tokenString string) (*APIKeyClaims, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-		return []byte(getSecret()), nil
+		return verificationKeys(), nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("parse api-key jwt: %w", err)
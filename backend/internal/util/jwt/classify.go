@@ -30,7 +30,7 @@ func ClassifyToken(tokenString string) (TokenKind, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
 		}
-		return []byte(getSecret()), nil
+		return verificationKeys(), nil
 	})
 	if err != nil {
 		return TokenKindUnknown, fmt.Errorf("classify jwt: %w", err)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -47,7 +48,8 @@ func Generate(userID int, email string, orgID *int) (string, error) {
 // so a valid API-key JWT would otherwise parse cleanly against the session
 // claims struct with zero-value UserID / CurrentOrgID and slip through.
 // Reject them explicitly by issuer — session JWTs carry no iss, API-key JWTs
-// carry "trakrf-api-key".
+// carry "trakrf-api-key", asset share links "trakrf-asset-share" and org
+// export downloads "trakrf-org-export".
 func Validate(tokenString string) (*Claims, error) {
 	claims := &Claims{}
 
@@ -55,7 +57,7 @@ func Validate(tokenString string) (*Claims, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(getSecret()), nil
+		return verificationKeys(), nil
 	})
 
 	if err != nil {
@@ -66,8 +68,8 @@ func Validate(tokenString string) (*Claims, error) {
 		return nil, fmt.Errorf("invalid JWT token")
 	}
 
-	if claims.Issuer == apiKeyIssuer {
-		return nil, fmt.Errorf("api-key token cannot be used for session auth")
+	if claims.Issuer != "" {
+		return nil, fmt.Errorf("%s token cannot be used for session auth", claims.Issuer)
 	}
 
 	return claims, nil
@@ -78,6 +80,7 @@ func Validate(tokenString string) (*Claims, error) {
 // (or other known-weak values) sign tokens in production.
 const devSecret = "dev-secret-change-in-production"
 
+// getSecret is the secret tokens are signed with.
 func getSecret() string {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -86,6 +89,36 @@ func getSecret() string {
 	return secret
 }
 
+// previous is the signing secret a rotation replaced. It still verifies
+// tokens until its grace period ends, so sessions issued just before the
+// rotation survive it; it never signs.
+var previous struct {
+	sync.RWMutex
+	secret string
+	until  time.Time
+}
+
+// RetainPreviousSecret keeps secret, the JWT_SECRET a rotation is about to
+// replace, verifying tokens for grace. Only the new secret signs.
+func RetainPreviousSecret(secret string, grace time.Duration) {
+	previous.Lock()
+	defer previous.Unlock()
+	previous.secret, previous.until = secret, time.Now().Add(grace)
+}
+
+// verificationKeys is what a token's signature is checked against: the
+// current secret, plus the previous one during its grace period.
+func verificationKeys() any {
+	current := getSecret()
+	previous.RLock()
+	old, until := previous.secret, previous.until
+	previous.RUnlock()
+	if old == "" || old == current || time.Now().After(until) {
+		return []byte(current)
+	}
+	return jwt.VerificationKeySet{Keys: []jwt.VerificationKey{[]byte(current), []byte(old)}}
+}
+
 // knownWeakSecrets are signing secrets that must never sign tokens in a deployed
 // environment: the unset/empty fallback (resolves to devSecret), devSecret
 // itself, and the helm chart default "change-me" (helm/trakrf-backend
@@ -118,11 +151,18 @@ var devOrTestEnvs = map[string]bool{
 // Enforcing on preview is safe: preview already has a real secret (it boots
 // normally) and only fail-boots if its secret ever regresses to a weak value.
 func ValidateSecret() error {
+	return CheckSecret(os.Getenv("JWT_SECRET"))
+}
+
+// CheckSecret is ValidateSecret for a candidate value, so a JWT_SECRET
+// refreshed from a secrets manager is vetted before it replaces the current
+// one.
+func CheckSecret(secret string) error {
 	appEnv := os.Getenv("APP_ENV")
 	if devOrTestEnvs[appEnv] {
 		return nil
 	}
-	if knownWeakSecrets[os.Getenv("JWT_SECRET")] {
+	if knownWeakSecrets[secret] {
 		return fmt.Errorf("JWT_SECRET must be set to a real, non-default value in %q "+
 			"(it is unset or a known-weak default — \"\", %q, or \"change-me\"); "+
 			"refusing to start to avoid signing forgeable tokens", appEnv, devSecret)
@@ -122,6 +122,41 @@ func TestValidate_WrongSecret(t *testing.T) {
 	}
 }
 
+func TestValidate_PreviousSecretDuringGrace(t *testing.T) {
+	t.Setenv("JWT_SECRET", "secret1")
+	token, _ := Generate(1, "test@example.com", nil)
+
+	RetainPreviousSecret("secret1", time.Minute)
+	defer RetainPreviousSecret("", 0)
+	t.Setenv("JWT_SECRET", "secret2")
+	if _, err := Validate(token); err != nil {
+		t.Fatalf("token signed before the rotation rejected during grace: %v", err)
+	}
+
+	fresh, _ := Generate(1, "test@example.com", nil)
+	t.Setenv("JWT_SECRET", "secret1")
+	RetainPreviousSecret("", 0)
+	if _, err := Validate(fresh); err == nil {
+		t.Error("a token issued after the rotation must be signed with the new secret")
+	}
+
+	RetainPreviousSecret("secret1", -time.Second)
+	t.Setenv("JWT_SECRET", "secret2")
+	if _, err := Validate(token); err == nil {
+		t.Error("Validate should fail once the grace period is over")
+	}
+}
+
+func TestCheckSecret(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	if err := CheckSecret("change-me"); err == nil {
+		t.Error("CheckSecret accepted a known-weak secret in production")
+	}
+	if err := CheckSecret("a-real-rotated-secret"); err != nil {
+		t.Errorf("CheckSecret rejected a real secret: %v", err)
+	}
+}
+
 func TestGetSecret_Default(t *testing.T) {
 	os.Unsetenv("JWT_SECRET")
 
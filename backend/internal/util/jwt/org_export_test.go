@@ -0,0 +1,44 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAndValidateOrgExportToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-abc123")
+
+	exp := time.Now().Add(10 * time.Minute)
+	token, err := GenerateOrgExportToken(42, 7001, exp)
+	require.NoError(t, err)
+
+	claims, err := ValidateOrgExportToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, 42, claims.OrgID)
+	assert.Equal(t, 7001, claims.ExportID)
+
+	expired, err := GenerateOrgExportToken(42, 7001, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	_, err = ValidateOrgExportToken(expired)
+	assert.Error(t, err)
+}
+
+// A download URL must not pass as a session or share token, nor the reverse.
+func TestOrgExportTokenIsolation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-abc123")
+
+	download, err := GenerateOrgExportToken(42, 7001, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = Validate(download)
+	assert.Error(t, err)
+	_, err = ValidateAssetShareToken(download)
+	assert.Error(t, err)
+
+	share, err := GenerateAssetShareToken(42, 7001, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = ValidateOrgExportToken(share)
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package jwt
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	assetShareIssuer   = "trakrf-asset-share"
+	assetShareAudience = "trakrf-share"
+)
+
+// AssetShareClaims is the payload of a public asset share link: which asset of
+// which org the bearer may view, until exp.
+type AssetShareClaims struct {
+	OrgID   int `json:"org_id"`
+	AssetID int `json:"asset_id"`
+	jwt.RegisteredClaims
+}
+
+// GenerateAssetShareToken mints the token of a public, read-only link to one
+// asset. It is signed with the shared secret but carries its own issuer, so it
+// never validates as a session or API-key token.
+func GenerateAssetShareToken(orgID, assetID int, exp time.Time) (string, error) {
+	claims := &AssetShareClaims{
+		OrgID:   orgID,
+		AssetID: assetID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    assetShareIssuer,
+			Subject:   strconv.Itoa(assetID),
+			Audience:  jwt.ClaimStrings{assetShareAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(getSecret()))
+	if err != nil {
+		return "", fmt.Errorf("sign asset share jwt: %w", err)
+	}
+	return signed, nil
+}
+
+// ValidateAssetShareToken verifies signature, iss, aud and a required exp.
+func ValidateAssetShareToken(tokenString string) (*AssetShareClaims, error) {
+	claims := &AssetShareClaims{}
+
+	parser := jwt.NewParser(
+		jwt.WithIssuer(assetShareIssuer),
+		jwt.WithAudience(assetShareAudience),
+		jwt.WithExpirationRequired(),
+	)
+
+	token, err := parser.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (any, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return verificationKeys(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse asset share jwt: %w", err)
+	}
+	if !token.Valid || claims.OrgID <= 0 || claims.AssetID <= 0 {
+		return nil, fmt.Errorf("invalid asset share jwt")
+	}
+	return claims, nil
+}
@@ -0,0 +1,59 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateAndValidateAssetShareToken(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-abc123")
+
+	exp := time.Now().Add(7 * 24 * time.Hour)
+	token, err := GenerateAssetShareToken(42, 1001, exp)
+	require.NoError(t, err)
+
+	claims, err := ValidateAssetShareToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, 42, claims.OrgID)
+	assert.Equal(t, 1001, claims.AssetID)
+	assert.WithinDuration(t, exp, claims.ExpiresAt.Time, time.Second)
+}
+
+func TestValidateAssetShareTokenRejectsExpired(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-abc123")
+
+	token, err := GenerateAssetShareToken(42, 1001, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	_, err = ValidateAssetShareToken(token)
+	assert.Error(t, err)
+}
+
+// A share link is handed to the public, so it must not authenticate anything
+// else, and no other token may pass as a share link.
+func TestAssetShareTokenIsolation(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-abc123")
+
+	share, err := GenerateAssetShareToken(42, 1001, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	_, err = Validate(share)
+	assert.Error(t, err, "session Validate must reject share tokens")
+	_, err = ValidateAccessToken(share)
+	assert.Error(t, err, "api-key validation must reject share tokens")
+	kind, _ := ClassifyToken(share)
+	assert.Equal(t, TokenKindUnknown, kind)
+
+	session, err := Generate(1, "user@example.com", intPtr(42))
+	require.NoError(t, err)
+	_, err = ValidateAssetShareToken(session)
+	assert.Error(t, err)
+
+	exp := time.Now().Add(time.Hour)
+	apiToken, err := GenerateAccessToken("jti", 42, []string{"assets:read"}, &exp)
+	require.NoError(t, err)
+	_, err = ValidateAssetShareToken(apiToken)
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+// Package netguard keeps requests to org-supplied URLs (webhook endpoints,
+// connector base URLs) off the server's own network: loopback, private,
+// link-local and multicast addresses, which include the cloud metadata
+// service at 169.254.169.254.
+//
+// CheckURL rejects such a URL when it is saved; Control refuses the
+// connection when it is dialed, after the host is resolved, so a name that
+// later re-resolves to an internal address (DNS rebinding) is still caught.
+package netguard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrNotPublic is returned for a URL or address on a non-public network.
+var ErrNotPublic = errors.New("must not point at a loopback, private, link-local or multicast address")
+
+// nonPublic lists the ranges netip has no predicate for.
+var nonPublic = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT; also Alibaba Cloud metadata
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64 can reach any IPv4 address
+}
+
+// lookupTimeout bounds the save-time resolution of a URL's host.
+const lookupTimeout = 3 * time.Second
+
+// lookup resolves a host; tests replace it.
+var lookup = net.DefaultResolver.LookupNetIP
+
+// PublicAddr reports whether ip is publicly routable.
+func PublicAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, p := range nonPublic {
+		if p.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckURL validates an org-supplied URL when it is saved: absolute http(s),
+// and a host that is not, and does not resolve to, a non-public address. A
+// name that does not resolve right now is accepted; Control still applies
+// when it is dialed.
+func CheckURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return errors.New("must be an absolute http(s) URL")
+	}
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	if ip, err := netip.ParseAddr(host); err == nil {
+		if !PublicAddr(ip) {
+			return ErrNotPublic
+		}
+		return nil
+	}
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrNotPublic
+	}
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+	addrs, err := lookup(ctx, "ip", host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range addrs {
+		if !PublicAddr(ip) {
+			return ErrNotPublic
+		}
+	}
+	return nil
+}
+
+// Control is a net.Dialer Control hook that refuses non-public addresses. It
+// runs on the resolved address, once per connection attempt.
+func Control(_, address string, _ syscall.RawConn) error {
+	ap, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !PublicAddr(ap.Addr()) {
+		return fmt.Errorf("refusing to connect to %s: %w", ap.Addr(), ErrNotPublic)
+	}
+	return nil
+}
+
+// NewTransport returns an HTTP transport whose connections go through
+// Control. It ignores proxy environment variables: a proxy would make the
+// proxy's address the only one checked.
+func NewTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = nil
+	t.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   Control,
+	}).DialContext
+	return t
+}
@@ -0,0 +1,64 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublicAddr(t *testing.T) {
+	for _, s := range []string{"127.0.0.1", "10.0.0.5", "172.16.3.4", "192.168.1.1", "169.254.169.254",
+		"100.100.100.200", "0.0.0.0", "224.0.0.1", "::1", "fe80::1", "fd00::1", "::ffff:127.0.0.1", "64:ff9b::a9fe:a9fe"} {
+		assert.False(t, PublicAddr(netip.MustParseAddr(s)), s)
+	}
+	for _, s := range []string{"93.184.216.34", "8.8.8.8", "2606:4700::1111"} {
+		assert.True(t, PublicAddr(netip.MustParseAddr(s)), s)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	defer func(orig func(context.Context, string, string) ([]netip.Addr, error)) { lookup = orig }(lookup)
+	lookup = func(_ context.Context, _, host string) ([]netip.Addr, error) {
+		switch host {
+		case "erp.example.com":
+			return []netip.Addr{netip.MustParseAddr("93.184.216.34")}, nil
+		case "rebind.example.com":
+			return []netip.Addr{netip.MustParseAddr("93.184.216.34"), netip.MustParseAddr("169.254.169.254")}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	for raw, wantErr := range map[string]bool{
+		"https://erp.example.com/hooks":            false,
+		"https://unresolvable.example.com/hooks":   false,
+		"http://93.184.216.34:8080/hooks":          false,
+		"ftp://erp.example.com/hooks":              true,
+		"/hooks":                                   true,
+		"http://10.0.0.5:8080/hooks":               true,
+		"http://169.254.169.254/latest/meta-data/": true,
+		"http://[::1]/hooks":                       true,
+		"http://localhost:8080/hooks":              true,
+		"http://api.localhost/hooks":               true,
+		"https://rebind.example.com/hooks":         true,
+	} {
+		err := CheckURL(context.Background(), raw)
+		assert.Equal(t, wantErr, err != nil, "%s: %v", raw, err)
+	}
+}
+
+func TestNewTransport_RefusesLoopbackAtDialTime(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request must not reach a loopback server")
+	}))
+	defer srv.Close()
+
+	_, err := (&http.Client{Transport: NewTransport()}).Get(srv.URL)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotPublic)
+}
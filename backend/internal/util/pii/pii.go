@@ -0,0 +1,89 @@
+// Package pii redacts personal data and credentials from free text on its
+// way out of the process: log lines (see NewWriter, installed by
+// logger.Initialize), error response details (httputil.WriteJSONError) and
+// error reports (errorreport.BeforeSend).
+//
+// Redaction is by shape, so it also catches values nobody meant to log, such
+// as a row Postgres quotes back in a constraint error:
+//
+//   - email addresses keep their domain: <redacted>@acme.co
+//   - JWTs, Bearer/Basic credentials, API and webhook secrets, and hex
+//     strings of 48 or more characters (reset tokens, SHA-256 digests)
+//   - values of fields named like password, secret, token or api_key
+//   - tag values (epc, tag_value fields, and "tag value X" in messages):
+//     any tag may be a person's badge, and a log line cannot tell which, so
+//     lines identify tags by asset or tag id instead
+//   - the values in Postgres error text: Key (col)=(value), Failing row
+//     contains (...), invalid input syntax ...: "value"
+package pii
+
+import (
+	"io"
+	"regexp"
+)
+
+// Marker replaces a redacted value.
+const Marker = "<redacted>"
+
+// quoted matches the body of a double-quoted string, JSON escapes included,
+// so a rule never runs past the end of a JSON string in a log line.
+const quoted = `(?:[^"\\]|\\.)*?`
+
+// separator joins a field name to its value: key=value, key:value, or a
+// JSON "key":"value", escaped or not.
+const separator = `\s*=\s*|\\?"\s*:\s*\\?"?|:`
+
+var rules = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	// Postgres: Key (email)=(a@b.co) already exists.
+	{regexp.MustCompile(`Key \((` + quoted + `)\)=\(` + quoted + `\)`), `Key ($1)=(` + Marker + `)`},
+	// Postgres: Failing row contains (1, a@b.co, ...).
+	{regexp.MustCompile(`Failing row contains \(` + quoted + `\)`), `Failing row contains (` + Marker + `)`},
+	// Postgres: invalid input syntax for type uuid: "value".
+	{regexp.MustCompile(`(invalid input (?:syntax for type|value for enum) [\w.]+: )(\\?")` + quoted + `(\\?")`), `${1}${2}` + Marker + `${3}`},
+	// trakrf.enforce_tag_unique_across_types: tag value X is already in use.
+	{regexp.MustCompile(`\b(tag value )[^\s"\\]+( is already in use)`), `${1}` + Marker + `${2}`},
+	// Credentials by name, as key=value, key:value or "key":"value". A
+	// bare colon followed by a space is prose ("invalid token: expired").
+	{regexp.MustCompile(`(?i)\b([a-z_]*(?:password|passwd|secret|token|api_?key))(` + separator + `)[^\s"\\&,;]+`), `${1}${2}` + Marker},
+	// Tag values by name, one value or a JSON array of them.
+	{regexp.MustCompile(`(?i)("(?:epcs?|tag_values?)"\s*:\s*)\[[^\]]*\]`), `${1}["` + Marker + `"]`},
+	{regexp.MustCompile(`(?i)\b(epcs?|tag_values?)(` + separator + `)[^\s"\\&,;\[]+`), `${1}${2}` + Marker},
+	// Authorization header values; prose ("Bearer token is invalid") has no
+	// credential-length word after the scheme.
+	{regexp.MustCompile(`(?i)\b(Bearer|Basic) [A-Za-z0-9._~+/=-]{16,}`), `$1 ` + Marker},
+	// JWTs.
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`), Marker},
+	// API key and webhook secrets, reset tokens, SHA-256 digests. Shorter
+	// hex (git SHAs, trace ids, EPCs) is left alone.
+	{regexp.MustCompile(`(?i)\b(?:trakrf_|whsec_)?[0-9a-f]{48,}\b`), Marker},
+	// Email addresses.
+	{regexp.MustCompile(`[A-Za-z0-9._%+-]+@([A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,})`), Marker + `@$1`},
+}
+
+// Redact returns s with personal data and credentials replaced by Marker.
+func Redact(s string) string {
+	for _, r := range rules {
+		s = r.re.ReplaceAllString(s, r.repl)
+	}
+	return s
+}
+
+// NewWriter returns a writer that redacts each write before passing it to
+// w. Loggers write one whole line per call, which is what redaction needs.
+func NewWriter(w io.Writer) io.Writer {
+	return writer{w: w}
+}
+
+type writer struct {
+	w io.Writer
+}
+
+func (r writer) Write(p []byte) (int, error) {
+	if _, err := r.w.Write([]byte(Redact(string(p)))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
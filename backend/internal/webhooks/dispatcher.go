@@ -0,0 +1,171 @@
+// Package webhooks delivers queued org webhook events. Storage queues one
+// trakrf.webhook_deliveries row per subscribed endpoint inside the mutating
+// transaction (the outbox); the Dispatcher's Deliver job claims due rows,
+// POSTs them signed, and records each outcome, retrying with exponential
+// backoff until the receiver answers 2xx or the attempts run out.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/trakrf/platform/backend/internal/models/webhook"
+	"github.com/trakrf/platform/backend/internal/util/netguard"
+)
+
+const (
+	// MaxAttempts is how many POSTs a delivery gets before it is failed.
+	// With the backoff below that spans roughly 14 hours.
+	MaxAttempts = 12
+	batchSize   = 50
+	parallelism = 8
+	sendTimeout = 10 * time.Second
+	// claimLease must outlast a full batch of sends so a slow batch is not
+	// re-claimed by another replica mid-flight.
+	claimLease = 2 * time.Minute
+	baseRetry  = 30 * time.Second
+	maxRetry   = 6 * time.Hour
+)
+
+// deliveryStore is the storage surface the dispatcher needs;
+// *storage.Storage satisfies it.
+type deliveryStore interface {
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error)
+	RecordWebhookAttempt(ctx context.Context, d webhook.Delivery, a webhook.Attempt, maxAttempts int, retryIn time.Duration) error
+}
+
+// Dispatcher posts claimed deliveries to their endpoints.
+type Dispatcher struct {
+	store  deliveryStore
+	client *http.Client
+	log    zerolog.Logger
+	now    func() time.Time
+}
+
+// NewDispatcher builds a dispatcher with a bounded HTTP client. Redirects are
+// not followed: a receiver must answer at the URL the org registered. The
+// client will not connect to loopback, private or link-local addresses, so an
+// endpoint cannot be used to read the server's own network through the
+// delivery log.
+func NewDispatcher(store deliveryStore, log *zerolog.Logger) *Dispatcher {
+	return &Dispatcher{
+		store: store,
+		client: &http.Client{
+			Timeout:   sendTimeout,
+			Transport: netguard.NewTransport(),
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		log: log.With().Str("component", "webhooks").Logger(),
+		now: time.Now,
+	}
+}
+
+// RetryDelay is the wait after the attempts-th failed POST: 30s doubling per
+// attempt, capped at 6h.
+func RetryDelay(attempts int) time.Duration {
+	d := baseRetry
+	for i := 1; i < attempts && d < maxRetry; i++ {
+		d *= 2
+	}
+	return min(d, maxRetry)
+}
+
+// Deliver is the job run: claim due deliveries in batches and post them until
+// none are due. Per-delivery failures are recorded on the row, not returned;
+// only storage errors fail the run.
+func (d *Dispatcher) Deliver(ctx context.Context) error {
+	for {
+		batch, err := d.store.ClaimWebhookDeliveries(ctx, batchSize, claimLease)
+		if err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := d.sendBatch(ctx, batch); err != nil {
+			return err
+		}
+		if len(batch) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (d *Dispatcher) sendBatch(ctx context.Context, batch []webhook.Delivery) error {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		sem      = make(chan struct{}, parallelism)
+	)
+	for _, del := range batch {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(del webhook.Delivery) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			attempt := d.send(ctx, del)
+			result := "delivered"
+			if !attempt.Succeeded() {
+				result = "failed"
+				d.log.Debug().Int64("delivery_id", del.ID).Int("status", attempt.StatusCode).
+					Str("error", attempt.Err).Msg("webhook delivery attempt failed")
+			}
+			metricAttempts.WithLabelValues(result).Inc()
+
+			err := d.store.RecordWebhookAttempt(ctx, del, attempt, MaxAttempts, RetryDelay(del.Attempts+1))
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(del)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// send POSTs one delivery and reports the outcome, including the signature
+// it sent for the delivery log.
+func (d *Dispatcher) send(ctx context.Context, del webhook.Delivery) webhook.Attempt {
+	sentAt := d.now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, del.URL, bytes.NewReader(del.Payload))
+	if err != nil {
+		return webhook.Attempt{Err: fmt.Sprintf("build request: %v", err), SentAt: sentAt}
+	}
+	sig := Sign(del.Secret, sentAt, del.Payload)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TrakRF-Webhooks/1")
+	req.Header.Set(HeaderEvent, del.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(del.ID, 10))
+	req.Header.Set(HeaderSignature, sig)
+	if del.ReplayOf != nil {
+		req.Header.Set(HeaderReplayOf, strconv.FormatInt(*del.ReplayOf, 10))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return webhook.Attempt{Err: err.Error(), Signature: sig, SentAt: sentAt}
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	a := webhook.Attempt{StatusCode: resp.StatusCode, Signature: sig, SentAt: sentAt}
+	if !a.Succeeded() {
+		a.Err = fmt.Sprintf("receiver returned %d", resp.StatusCode)
+	}
+	return a
+}
@@ -0,0 +1,144 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/trakrf/platform/backend/internal/models/webhook"
+)
+
+type fakeDeliveryStore struct {
+	mu       sync.Mutex
+	due      []webhook.Delivery
+	recorded map[int64]webhook.Attempt
+	retryIn  map[int64]time.Duration
+}
+
+func (f *fakeDeliveryStore) ClaimWebhookDeliveries(_ context.Context, limit int, _ time.Duration) ([]webhook.Delivery, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := min(limit, len(f.due))
+	out := f.due[:n]
+	f.due = f.due[n:]
+	return out, nil
+}
+
+func (f *fakeDeliveryStore) RecordWebhookAttempt(_ context.Context, d webhook.Delivery, a webhook.Attempt, _ int, retryIn time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.recorded[d.ID] = a
+	f.retryIn[d.ID] = retryIn
+	return nil
+}
+
+func newFakeStore(ds ...webhook.Delivery) *fakeDeliveryStore {
+	return &fakeDeliveryStore{due: ds, recorded: map[int64]webhook.Attempt{}, retryIn: map[int64]time.Duration{}}
+}
+
+func testDispatcher(store deliveryStore) *Dispatcher {
+	log := zerolog.New(io.Discard)
+	d := NewDispatcher(store, &log)
+	d.now = func() time.Time { return time.Unix(1700000000, 0) }
+	// httptest servers listen on loopback, which the real transport refuses.
+	d.client.Transport = http.DefaultTransport
+	return d
+}
+
+func TestDeliver_RefusesLoopbackReceiver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("delivery must not reach a loopback receiver")
+	}))
+	defer srv.Close()
+
+	store := newFakeStore(webhook.Delivery{ID: 1, Payload: []byte(`{}`), URL: srv.URL})
+	log := zerolog.New(io.Discard)
+	require.NoError(t, NewDispatcher(store, &log).Deliver(context.Background()))
+	assert.False(t, store.recorded[1].Succeeded())
+	assert.Contains(t, store.recorded[1].Err, "refusing to connect")
+}
+
+func TestDeliver_SignsAndRecordsOutcome(t *testing.T) {
+	var gotSig, gotEvent, gotDelivery string
+	var gotBody []byte
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(HeaderSignature)
+		gotEvent = r.Header.Get(HeaderEvent)
+		gotDelivery = r.Header.Get(HeaderDelivery)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	payload := []byte(`{"type":"asset.created"}`)
+	store := newFakeStore(
+		webhook.Delivery{ID: 1, EventType: "asset.created", Payload: payload, URL: ok.URL, Secret: "s3cret"},
+		webhook.Delivery{ID: 2, EventType: "asset.created", Payload: payload, URL: failing.URL, Secret: "s3cret", Attempts: 2},
+	)
+
+	require.NoError(t, testDispatcher(store).Deliver(context.Background()))
+
+	assert.True(t, store.recorded[1].Succeeded())
+	assert.Equal(t, "asset.created", gotEvent)
+	assert.Equal(t, "1", gotDelivery)
+	assert.Equal(t, payload, gotBody)
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte("1700000000." + string(payload)))
+	assert.Equal(t, "t=1700000000,v1="+hex.EncodeToString(mac.Sum(nil)), gotSig)
+	assert.Equal(t, gotSig, store.recorded[1].Signature, "the sent signature is recorded for the delivery log")
+
+	assert.False(t, store.recorded[2].Succeeded())
+	assert.Equal(t, http.StatusServiceUnavailable, store.recorded[2].StatusCode)
+	assert.Equal(t, RetryDelay(3), store.retryIn[2], "retry delay grows with the attempt count")
+}
+
+func TestDeliver_RedirectIsNotFollowed(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("redirect must not be followed")
+	}))
+	defer target.Close()
+	redirector := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer redirector.Close()
+
+	store := newFakeStore(webhook.Delivery{ID: 1, Payload: []byte(`{}`), URL: redirector.URL})
+	require.NoError(t, testDispatcher(store).Deliver(context.Background()))
+	assert.Equal(t, http.StatusFound, store.recorded[1].StatusCode)
+	assert.False(t, store.recorded[1].Succeeded())
+}
+
+func TestDeliver_ReplaySetsReplayOfHeader(t *testing.T) {
+	var gotReplayOf string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReplayOf = r.Header.Get(HeaderReplayOf)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	original := int64(41)
+	store := newFakeStore(webhook.Delivery{ID: 42, Payload: []byte(`{}`), URL: srv.URL, ReplayOf: &original})
+	require.NoError(t, testDispatcher(store).Deliver(context.Background()))
+	assert.Equal(t, "41", gotReplayOf)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, RetryDelay(1))
+	assert.Equal(t, time.Minute, RetryDelay(2))
+	assert.Equal(t, 4*time.Minute, RetryDelay(4))
+	assert.Equal(t, 6*time.Hour, RetryDelay(20))
+}
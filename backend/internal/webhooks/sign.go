@@ -0,0 +1,31 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Request headers on every delivery.
+const (
+	HeaderSignature = "X-TrakRF-Signature"
+	HeaderEvent     = "X-TrakRF-Event"
+	HeaderDelivery  = "X-TrakRF-Delivery"
+	// HeaderReplayOf names the original delivery on a manual re-delivery, so
+	// receivers can tell a replay from a fresh event.
+	HeaderReplayOf = "X-TrakRF-Replay-Of"
+)
+
+// Sign returns the X-TrakRF-Signature value for body sent at ts:
+// "t=<unix>,v1=<hex HMAC-SHA256(secret, "<unix>.<body>")>". Binding the
+// timestamp into the MAC lets receivers reject replays outside a tolerance.
+func Sign(secret string, ts time.Time, body []byte) string {
+	unix := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + unix + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -28,9 +28,14 @@ import (
 	"syscall"
 
 	"github.com/trakrf/platform/backend/internal/buildinfo"
+	"github.com/trakrf/platform/backend/internal/cmd/backup"
+	"github.com/trakrf/platform/backend/internal/cmd/encryption"
+	"github.com/trakrf/platform/backend/internal/cmd/loadtest"
 	"github.com/trakrf/platform/backend/internal/cmd/migrate"
+	"github.com/trakrf/platform/backend/internal/cmd/seed"
 	"github.com/trakrf/platform/backend/internal/cmd/serve"
 	"github.com/trakrf/platform/backend/internal/logger"
+	"github.com/trakrf/platform/backend/internal/secrets"
 )
 
 //go:embed frontend/dist
@@ -57,33 +62,50 @@ const (
 	// this default matters for local docker / ad-hoc runs.
 	cmdServe command = iota
 	cmdMigrate
+	cmdSeed
+	cmdLoadTest
+	cmdBackup
+	cmdEncryption
 	cmdHelp
 	cmdUnknown
 )
 
-const usage = "usage: server [serve|migrate]"
+const usage = "usage: server [serve|migrate [up|expand|contract|down [N]|status|force VERSION|create NAME|lint]|seed [--org ID] [--assets N] [--days N] [--seed N] [--admin-email EMAIL]|loadtest [--base-url URL] [--token TOKEN] [--reader-key KEY] [--scenarios LIST] [--rate N] [--duration D] [--json FILE]|backup [--no-upload]|encryption [status|rotate]]"
 
-func parseCommand(args []string) (command, error) {
+// parseCommand picks the subcommand and returns the arguments that belong to
+// it. Only migrate, seed, loadtest, backup and encryption take any; they
+// validate them themselves.
+func parseCommand(args []string) (command, []string, error) {
 	if len(args) == 0 {
-		return cmdServe, nil
+		return cmdServe, nil, nil
+	}
+	switch args[0] {
+	case "migrate":
+		return cmdMigrate, args[1:], nil
+	case "seed":
+		return cmdSeed, args[1:], nil
+	case "loadtest":
+		return cmdLoadTest, args[1:], nil
+	case "backup":
+		return cmdBackup, args[1:], nil
+	case "encryption":
+		return cmdEncryption, args[1:], nil
 	}
 	if len(args) > 1 {
-		return cmdUnknown, fmt.Errorf("unexpected extra arguments: %v", args[1:])
+		return cmdUnknown, nil, fmt.Errorf("unexpected extra arguments: %v", args[1:])
 	}
 	switch args[0] {
 	case "serve":
-		return cmdServe, nil
-	case "migrate":
-		return cmdMigrate, nil
+		return cmdServe, nil, nil
 	case "-h", "--help":
-		return cmdHelp, nil
+		return cmdHelp, nil, nil
 	default:
-		return cmdUnknown, fmt.Errorf("unknown subcommand: %q", args[0])
+		return cmdUnknown, nil, fmt.Errorf("unknown subcommand: %q", args[0])
 	}
 }
 
 func main() {
-	cmd, err := parseCommand(os.Args[1:])
+	cmd, args, err := parseCommand(os.Args[1:])
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		fmt.Fprintln(os.Stderr, usage)
@@ -91,6 +113,8 @@ func main() {
 	}
 	if cmd == cmdHelp {
 		fmt.Println(usage)
+		fmt.Println(migrate.Usage)
+		fmt.Println(encryption.Usage)
 		os.Exit(0)
 	}
 
@@ -110,19 +134,41 @@ func main() {
 		GoVersion: runtime.Version(),
 	}
 
-	runErr := run(ctx, cmd, info)
+	runErr := run(ctx, cmd, args, info)
 	if runErr != nil {
 		log.Error().Err(runErr).Msg("Command failed")
 		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context, cmd command, info buildinfo.Info) error {
+func run(ctx context.Context, cmd command, args []string, info buildinfo.Info) error {
+	// Credentials kept in a secrets manager (SECRETS_PROVIDER) are loaded into
+	// the environment before any command reads it; serve keeps them fresh.
+	secretsLoader, err := secrets.FromEnv()
+	if err != nil {
+		return err
+	}
+	if secretsLoader != nil {
+		names, err := secretsLoader.Load(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Get().Info().Strs("variables", names).Msg("Loaded credentials from secrets manager")
+	}
+
 	switch cmd {
 	case cmdMigrate:
-		return migrate.Run(ctx, info)
+		return migrate.Run(ctx, info, args)
+	case cmdSeed:
+		return seed.Run(ctx, info, args)
+	case cmdLoadTest:
+		return loadtest.Run(ctx, info, args)
+	case cmdBackup:
+		return backup.Run(ctx, info, args)
+	case cmdEncryption:
+		return encryption.Run(ctx, info, args)
 	case cmdServe:
-		return serve.Run(ctx, info, frontendFS)
+		return serve.Run(ctx, info, frontendFS, secretsLoader)
 	}
 	return fmt.Errorf("unreachable command: %v", cmd)
 }
@@ -1,34 +1,44 @@
 package main
 
 import (
+	"slices"
 	"testing"
 )
 
 func TestParseCommand(t *testing.T) {
 	tests := []struct {
-		name    string
-		args    []string
-		want    command
-		wantErr bool
+		name     string
+		args     []string
+		want     command
+		wantArgs []string
+		wantErr  bool
 	}{
-		{"no args -> serve default", []string{}, cmdServe, false},
-		{"serve explicit", []string{"serve"}, cmdServe, false},
-		{"migrate explicit", []string{"migrate"}, cmdMigrate, false},
-		{"-h prints usage", []string{"-h"}, cmdHelp, false},
-		{"--help prints usage", []string{"--help"}, cmdHelp, false},
-		{"unknown subcommand is an error", []string{"bogus"}, cmdUnknown, true},
-		{"extra args after serve is an error", []string{"serve", "extra"}, cmdUnknown, true},
+		{"no args -> serve default", []string{}, cmdServe, nil, false},
+		{"serve explicit", []string{"serve"}, cmdServe, nil, false},
+		{"migrate explicit", []string{"migrate"}, cmdMigrate, []string{}, false},
+		{"migrate passes its args through", []string{"migrate", "down", "2"}, cmdMigrate, []string{"down", "2"}, false},
+		{"seed passes its flags through", []string{"seed", "--assets", "100"}, cmdSeed, []string{"--assets", "100"}, false},
+		{"loadtest passes its flags through", []string{"loadtest", "--rate", "5"}, cmdLoadTest, []string{"--rate", "5"}, false},
+		{"backup passes its flags through", []string{"backup", "--no-upload"}, cmdBackup, []string{"--no-upload"}, false},
+		{"encryption passes its args through", []string{"encryption", "rotate"}, cmdEncryption, []string{"rotate"}, false},
+		{"-h prints usage", []string{"-h"}, cmdHelp, nil, false},
+		{"--help prints usage", []string{"--help"}, cmdHelp, nil, false},
+		{"unknown subcommand is an error", []string{"bogus"}, cmdUnknown, nil, true},
+		{"extra args after serve is an error", []string{"serve", "extra"}, cmdUnknown, nil, true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := parseCommand(tt.args)
+			got, gotArgs, err := parseCommand(tt.args)
 			if (err != nil) != tt.wantErr {
 				t.Fatalf("parseCommand(%v) err = %v, wantErr = %v", tt.args, err, tt.wantErr)
 			}
 			if got != tt.want {
 				t.Errorf("parseCommand(%v) = %v, want %v", tt.args, got, tt.want)
 			}
+			if !slices.Equal(gotArgs, tt.wantArgs) {
+				t.Errorf("parseCommand(%v) args = %v, want %v", tt.args, gotArgs, tt.wantArgs)
+			}
 		})
 	}
 }
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS trakrf.event_outbox;
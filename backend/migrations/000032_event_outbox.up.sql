@@ -0,0 +1,26 @@
+-- Transactional outbox for the enterprise event export (Kafka / NATS).
+-- Storage writes a row here inside the same transaction as the entity mutation,
+-- so an event exists if and only if the write committed. The exporter drains
+-- unpublished rows in id order and stamps published_at only after the sink
+-- acknowledged them: at-least-once delivery, consumers dedupe on id.
+--
+-- System table, no RLS (like tag_scans): the exporter drains across orgs with
+-- no org context set. No GRANTs here: the infra init-grants job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE event_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id),
+    event_type TEXT NOT NULL,
+    entity_id BIGINT,
+    data JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMPTZ,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+-- The drain query: oldest unpublished first.
+CREATE INDEX idx_event_outbox_pending ON event_outbox (id) WHERE published_at IS NULL;
+-- The prune query: published rows past retention.
+CREATE INDEX idx_event_outbox_published_at ON event_outbox (published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trakrf.webhook_deliveries;
+DROP TABLE IF EXISTS trakrf.webhook_endpoints;
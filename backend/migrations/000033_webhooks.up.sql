@@ -0,0 +1,58 @@
+-- Org webhooks with a transactional delivery outbox.
+-- Storage inserts one webhook_deliveries row per matching endpoint inside the
+-- same transaction as the entity mutation, so a committed change always has
+-- its deliveries queued — a crash right after commit loses nothing. The
+-- webhook delivery job sends due rows, retrying with backoff until delivered
+-- or out of attempts.
+--
+-- Neither table has RLS: both are read by the delivery job with no org context
+-- set, so org isolation is app-layer (every org-facing query filters org_id),
+-- the same posture as api_keys. No GRANTs here: the infra init-grants job owns
+-- privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE webhook_endpoints (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id),
+    url TEXT NOT NULL,
+    -- HMAC signing key. Stored in plaintext by necessity: the server must
+    -- re-derive signatures, so it cannot keep only a hash.
+    secret TEXT NOT NULL,
+    -- Empty = every event type.
+    event_types TEXT[] NOT NULL DEFAULT '{}',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TRIGGER generate_webhook_endpoint_id_trigger
+    BEFORE INSERT ON webhook_endpoints
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_webhook_endpoints_updated_at
+    BEFORE UPDATE ON webhook_endpoints
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_webhook_endpoints_org_active ON webhook_endpoints (org_id)
+    WHERE deleted_at IS NULL AND is_active;
+
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id),
+    endpoint_id BIGINT NOT NULL REFERENCES webhook_endpoints(id),
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_status_code INT,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMPTZ
+);
+
+-- The delivery job's claim query: due pending rows, oldest first.
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at)
+    WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries (endpoint_id, created_at DESC);
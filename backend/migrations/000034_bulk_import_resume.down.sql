@@ -0,0 +1,15 @@
+SET search_path = trakrf, public;
+
+DROP FUNCTION IF EXISTS trakrf.list_interrupted_bulk_import_jobs();
+
+DROP INDEX IF EXISTS idx_bulk_import_jobs_interrupted;
+
+ALTER TABLE bulk_import_jobs
+    DROP COLUMN IF EXISTS resume_row,
+    DROP COLUMN IF EXISTS payload;
+
+UPDATE bulk_import_jobs SET status = 'failed', completed_at = NOW() WHERE status = 'interrupted';
+
+ALTER TABLE bulk_import_jobs DROP CONSTRAINT bulk_import_jobs_status_check;
+ALTER TABLE bulk_import_jobs ADD CONSTRAINT bulk_import_jobs_status_check
+    CHECK (status IN ('pending', 'processing', 'completed', 'failed'));
@@ -0,0 +1,37 @@
+-- Resumable bulk imports. On SIGTERM the importer stops at a row boundary and
+-- parks the job as 'interrupted' with everything needed to continue: the CSV
+-- records (payload) and the index of the next validated row (resume_row).
+-- Any replica's resume job then claims it and picks up where it left off.
+-- payload is cleared once the job reaches a terminal status.
+
+SET search_path = trakrf, public;
+
+ALTER TABLE bulk_import_jobs DROP CONSTRAINT bulk_import_jobs_status_check;
+ALTER TABLE bulk_import_jobs ADD CONSTRAINT bulk_import_jobs_status_check
+    CHECK (status IN ('pending', 'processing', 'interrupted', 'completed', 'failed'));
+
+ALTER TABLE bulk_import_jobs
+    ADD COLUMN payload    JSONB,
+    ADD COLUMN resume_row INT NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_bulk_import_jobs_interrupted ON bulk_import_jobs(created_at)
+    WHERE status = 'interrupted';
+
+COMMENT ON COLUMN bulk_import_jobs.payload IS 'CSV records (header first) saved when the job is interrupted; NULL otherwise';
+COMMENT ON COLUMN bulk_import_jobs.resume_row IS 'Index of the next validated row to insert when an interrupted job resumes';
+
+-- The resume job runs with no org context, so it lists parked jobs through a
+-- SECURITY DEFINER function (same pattern as list_active_scan_topics) and then
+-- claims each one under that job's org.
+CREATE OR REPLACE FUNCTION trakrf.list_interrupted_bulk_import_jobs()
+RETURNS TABLE (id bigint, org_id bigint)
+LANGUAGE sql
+STABLE
+SECURITY DEFINER
+SET search_path = trakrf, public
+AS $$
+    SELECT j.id, j.org_id
+    FROM trakrf.bulk_import_jobs j
+    WHERE j.status = 'interrupted'
+    ORDER BY j.created_at;
+$$;
@@ -0,0 +1,14 @@
+-- Revert per-org retention. Restores the 000008 365d policy on asset_scans;
+-- rows the janitor already deleted are not restored.
+SET search_path = trakrf, public;
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_org_created;
+
+DROP FUNCTION IF EXISTS trakrf.list_org_retention(BIGINT);
+
+SELECT remove_retention_policy('asset_scans', if_exists => true);
+SELECT add_retention_policy('asset_scans', INTERVAL '365 days');
+
+ALTER TABLE subscription_plans
+    DROP COLUMN IF EXISTS scan_retention_days,
+    DROP COLUMN IF EXISTS audit_retention_days;
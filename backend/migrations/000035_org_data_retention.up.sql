@@ -0,0 +1,55 @@
+-- Per-org data retention. Each plan carries a retention window for the org's
+-- audit trail (webhook_deliveries — the durable per-org log of every change
+-- event) and its scan history (asset_scans). An org admin may shorten either
+-- window below the plan's, never lengthen it; the choice lives in
+-- organizations.metadata.retention. The retention janitor job deletes rows past
+-- each org's effective window.
+--
+-- asset_scans' Timescale policy becomes the outer bound: it is raised to the
+-- longest window any plan offers (730d) and the janitor trims orgs on shorter
+-- plans. Orgs without an active subscription keep today's windows (365d scans,
+-- see organization.DefaultScanRetentionDays), so deploying this deletes nothing
+-- the 000008 policy would have kept.
+SET search_path = trakrf, public;
+
+ALTER TABLE subscription_plans
+    ADD COLUMN audit_retention_days INT CHECK (audit_retention_days > 0),
+    ADD COLUMN scan_retention_days  INT CHECK (scan_retention_days > 0);
+
+COMMENT ON COLUMN subscription_plans.audit_retention_days IS 'Days of webhook_deliveries kept for orgs on this plan; NULL = system default.';
+COMMENT ON COLUMN subscription_plans.scan_retention_days  IS 'Days of asset_scans kept for orgs on this plan; NULL = system default. Must not exceed the asset_scans Timescale retention policy.';
+
+UPDATE subscription_plans SET audit_retention_days = 90,  scan_retention_days = 90  WHERE owner_org_id IS NULL AND name = 'Free';
+UPDATE subscription_plans SET audit_retention_days = 365, scan_retention_days = 365 WHERE owner_org_id IS NULL AND name = 'Starter';
+UPDATE subscription_plans SET audit_retention_days = 730, scan_retention_days = 730 WHERE owner_org_id IS NULL AND name IN ('Professional', 'Enterprise');
+
+SELECT remove_retention_policy('asset_scans', if_exists => true);
+SELECT add_retention_policy('asset_scans', INTERVAL '730 days');
+
+-- The janitor runs with no org context and subscriptions is RLS-scoped, so
+-- the plan join goes through a SECURITY DEFINER function (same pattern as
+-- org_is_entitled). p_org_id NULL lists every live org.
+CREATE OR REPLACE FUNCTION trakrf.list_org_retention(p_org_id BIGINT DEFAULT NULL)
+RETURNS TABLE (org_id bigint, plan_audit_days int, plan_scan_days int, settings jsonb)
+LANGUAGE sql
+STABLE
+SECURITY DEFINER
+SET search_path = trakrf, public
+AS $$
+    SELECT o.id, p.audit_retention_days, p.scan_retention_days, o.metadata->'retention'
+    FROM trakrf.organizations o
+    LEFT JOIN trakrf.subscriptions s
+           ON s.org_id = o.id
+          AND s.status = 'active'
+          AND (s.current_period_end IS NULL OR now() < s.current_period_end)
+    LEFT JOIN trakrf.subscription_plans p ON p.id = s.plan_id
+    WHERE o.deleted_at IS NULL
+      AND (p_org_id IS NULL OR o.id = p_org_id)
+    ORDER BY o.id;
+$$;
+
+COMMENT ON FUNCTION trakrf.list_org_retention(BIGINT) IS 'Plan retention windows plus metadata.retention per live org. SECURITY DEFINER so the retention janitor can read subscriptions with no org context.';
+
+-- The janitor's audit prune: terminal deliveries per org by age.
+CREATE INDEX idx_webhook_deliveries_org_created ON webhook_deliveries (org_id, created_at)
+    WHERE status <> 'pending';
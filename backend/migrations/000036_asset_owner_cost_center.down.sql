@@ -0,0 +1,8 @@
+SET search_path = trakrf, public;
+
+DROP INDEX IF EXISTS idx_assets_org_cost_center;
+DROP INDEX IF EXISTS idx_assets_org_owner;
+
+ALTER TABLE assets
+    DROP COLUMN IF EXISTS cost_center,
+    DROP COLUMN IF EXISTS owner_user_id;
@@ -0,0 +1,23 @@
+-- Asset ownership and cost-center assignment. owner_user_id names the org
+-- member accountable for the asset (set on create, changed only through
+-- POST /assets/{id}/transfer so the new owner is always notified);
+-- cost_center is a free-form finance code. Both are optional.
+--
+-- Membership of the owner in the asset's org is enforced by the app layer:
+-- org_users has no single-column key to reference, and a member leaving the
+-- org must not cascade into asset writes. A user hard-delete nulls ownership.
+SET search_path = trakrf, public;
+
+ALTER TABLE assets
+    ADD COLUMN owner_user_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN cost_center   VARCHAR(64);
+
+-- GET /me/assets and ?owner_user_id=: live assets per owner.
+CREATE INDEX idx_assets_org_owner ON assets (org_id, owner_user_id)
+    WHERE deleted_at IS NULL AND owner_user_id IS NOT NULL;
+-- ?cost_center= filter.
+CREATE INDEX idx_assets_org_cost_center ON assets (org_id, cost_center)
+    WHERE deleted_at IS NULL AND cost_center IS NOT NULL;
+
+COMMENT ON COLUMN assets.owner_user_id IS 'Org member accountable for the asset; changed via the transfer action, which notifies the new owner.';
+COMMENT ON COLUMN assets.cost_center IS 'Free-form finance cost-center code.';
@@ -0,0 +1,7 @@
+SET search_path = trakrf, public;
+
+ALTER TABLE locations DROP COLUMN IF EXISTS team_id;
+ALTER TABLE assets DROP COLUMN IF EXISTS team_id;
+
+DROP TABLE IF EXISTS team_members;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,59 @@
+-- Teams: departments within an org. A team has members (role 'member' or
+-- 'admin'), and assets and locations may each be assigned to at most one team.
+-- When the org enables strict mode (organizations.metadata.teams.strict_mode)
+-- non-admin members only see assets and locations assigned to their teams;
+-- that filter is applied by the app layer on top of org RLS.
+-- No GRANTs here: the infra init-grants job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE teams (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id),
+    name TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_team_id_trigger
+    BEFORE INSERT ON teams
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_teams_updated_at
+    BEFORE UPDATE ON teams
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_teams_org_name ON teams (org_id, lower(name));
+
+ALTER TABLE teams ENABLE ROW LEVEL SECURITY;
+CREATE POLICY org_isolation_teams ON teams
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+-- Org membership of the user is enforced by the app layer (same reasoning as
+-- assets.owner_user_id). Removing a user from the org does not cascade here;
+-- a former member loses access through the org membership checks anyway.
+CREATE TABLE team_members (
+    org_id BIGINT NOT NULL REFERENCES organizations(id),
+    team_id BIGINT NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('member', 'admin')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_org_user ON team_members (org_id, user_id);
+
+ALTER TABLE team_members ENABLE ROW LEVEL SECURITY;
+CREATE POLICY org_isolation_team_members ON team_members
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+ALTER TABLE assets ADD COLUMN team_id BIGINT REFERENCES teams(id) ON DELETE SET NULL;
+ALTER TABLE locations ADD COLUMN team_id BIGINT REFERENCES teams(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_assets_org_team ON assets (org_id, team_id)
+    WHERE deleted_at IS NULL AND team_id IS NOT NULL;
+CREATE INDEX idx_locations_org_team ON locations (org_id, team_id)
+    WHERE deleted_at IS NULL AND team_id IS NOT NULL;
+
+COMMENT ON COLUMN assets.team_id IS 'Owning team; assigned via PUT /teams/{team_id}/assets/{asset_id}.';
+COMMENT ON COLUMN locations.team_id IS 'Owning team; assigned via PUT /teams/{team_id}/locations/{location_id}.';
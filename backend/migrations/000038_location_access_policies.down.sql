@@ -0,0 +1,3 @@
+SET search_path = trakrf, public;
+
+DROP TABLE IF EXISTS location_access_policies;
@@ -0,0 +1,40 @@
+-- Location access policies (ABAC on top of org roles). A policy grants one
+-- user, or every member of one team, access to the location subtree rooted at
+-- location_id. A member with at least one policy (direct or through a team)
+-- is confined to the union of their granted subtrees; members with none keep
+-- role-only access, and org admins are never confined.
+--
+-- Subtrees are resolved with parent_location_id walks appended to the
+-- location queries, the same recursion the tree endpoints use since TRA-684.
+-- ltree stays uninstalled (see 000001): a materialized path would have to be
+-- re-derived on every rename/reparent, and policies name a root by id, so a
+-- rename never changes what a policy covers.
+-- No GRANTs here: the infra init-grants job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE location_access_policies (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id),
+    location_id BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    user_id BIGINT REFERENCES users(id) ON DELETE CASCADE,
+    team_id BIGINT REFERENCES teams(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT location_access_policies_one_grantee CHECK ((user_id IS NULL) <> (team_id IS NULL))
+);
+
+CREATE TRIGGER generate_location_access_policy_id_trigger
+    BEFORE INSERT ON location_access_policies
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE UNIQUE INDEX idx_location_access_policies_user
+    ON location_access_policies (location_id, user_id) WHERE user_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_location_access_policies_team
+    ON location_access_policies (location_id, team_id) WHERE team_id IS NOT NULL;
+CREATE INDEX idx_location_access_policies_org_user
+    ON location_access_policies (org_id, user_id) WHERE user_id IS NOT NULL;
+CREATE INDEX idx_location_access_policies_org_team
+    ON location_access_policies (org_id, team_id) WHERE team_id IS NOT NULL;
+
+ALTER TABLE location_access_policies ENABLE ROW LEVEL SECURITY;
+CREATE POLICY org_isolation_location_access_policies ON location_access_policies
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
@@ -0,0 +1,5 @@
+SET search_path = trakrf, public;
+
+ALTER TABLE api_keys DROP COLUMN IF EXISTS service_account_id;
+
+DROP TABLE IF EXISTS service_accounts;
@@ -0,0 +1,40 @@
+-- Service accounts: non-human org principals that own API keys, so an
+-- integration's credentials and audit trail are not tied to the employee who
+-- set it up. A service account has no email or password and no org_users row,
+-- so it can never sign in interactively; it acts only through its keys.
+-- Like api_keys, this table is app-layer enforced (no RLS): the API-key auth
+-- path resolves the owning account before any org session GUC is set.
+-- No GRANTs here: the infra init-grants job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE service_accounts (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TRIGGER generate_service_account_id_trigger
+    BEFORE INSERT ON service_accounts
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_service_accounts_updated_at
+    BEFORE UPDATE ON service_accounts
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_service_accounts_org_name ON service_accounts (org_id, lower(name))
+    WHERE deleted_at IS NULL;
+
+-- Owner of the key, distinct from created_by / created_by_key_id (who minted
+-- it). NULL for keys that predate service accounts or were minted unowned.
+ALTER TABLE api_keys ADD COLUMN service_account_id BIGINT REFERENCES service_accounts(id);
+
+CREATE INDEX idx_api_keys_service_account ON api_keys (service_account_id)
+    WHERE service_account_id IS NOT NULL;
+
+COMMENT ON TABLE service_accounts IS 'Non-human org principals that own API keys; cannot log in';
+COMMENT ON COLUMN api_keys.service_account_id IS 'Owning service account; audit logs attribute the key''s requests to it.';
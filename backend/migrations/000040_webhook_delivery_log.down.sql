@@ -0,0 +1,8 @@
+SET search_path = trakrf, public;
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_org_created;
+
+ALTER TABLE webhook_deliveries
+    DROP COLUMN IF EXISTS replay_of,
+    DROP COLUMN IF EXISTS last_attempt_at,
+    DROP COLUMN IF EXISTS last_signature;
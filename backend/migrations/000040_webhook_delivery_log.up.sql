@@ -0,0 +1,18 @@
+-- Webhook delivery log: keep the exact signature header and time of the most
+-- recent POST on each delivery, so an org can see precisely what was sent and
+-- when, and link a manual re-delivery back to the delivery it replays.
+-- A replay is a new pending row carrying the original payload; the original
+-- row is never mutated, so the log stays an honest record of every send.
+-- No GRANTs here: the infra init-grants job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE webhook_deliveries
+    ADD COLUMN last_signature TEXT,
+    ADD COLUMN last_attempt_at TIMESTAMPTZ,
+    ADD COLUMN replay_of BIGINT REFERENCES webhook_deliveries(id) ON DELETE SET NULL;
+
+-- Org-wide browse, newest first.
+CREATE INDEX idx_webhook_deliveries_org_created ON webhook_deliveries (org_id, created_at DESC);
+
+COMMENT ON COLUMN webhook_deliveries.last_signature IS 'X-TrakRF-Signature header of the most recent POST.';
+COMMENT ON COLUMN webhook_deliveries.replay_of IS 'Delivery this row re-sends, when created by a manual redeliver.';
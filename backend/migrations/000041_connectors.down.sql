@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS trakrf.connector_sync_runs;
+DROP TABLE IF EXISTS trakrf.connector_asset_links;
+DROP TABLE IF EXISTS trakrf.connectors;
@@ -0,0 +1,88 @@
+-- ERP connectors: per-org links to an external system of record (first kind:
+-- NetSuite fixed assets) that the connector sync job reconciles with assets in
+-- both directions. Credentials are sealed by the application vault (AES-GCM,
+-- CONNECTOR_VAULT_KEY) before they reach the database; only ciphertext is
+-- stored.
+--
+-- None of these tables has RLS: the sync job claims due connectors across
+-- every org with no org context set, so org isolation is app-layer (every
+-- org-facing query filters org_id), the same posture as webhook_endpoints.
+-- Asset writes made by the job still go through the RLS-scoped org
+-- transaction. No GRANTs here: the infra init-grants job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE connectors (
+    id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('netsuite')),
+    name VARCHAR(255) NOT NULL,
+    -- Non-secret, kind-specific settings (account id, record type).
+    config JSONB NOT NULL DEFAULT '{}',
+    -- Vault-sealed credential JSON; never returned by the API.
+    credentials BYTEA NOT NULL,
+    -- [{"local": "...", "remote": "...", "direction": "both"}, ...]
+    field_mapping JSONB NOT NULL DEFAULT '[]',
+    direction TEXT NOT NULL DEFAULT 'both' CHECK (direction IN ('pull', 'push', 'both')),
+    conflict_policy TEXT NOT NULL DEFAULT 'remote_wins'
+        CHECK (conflict_policy IN ('remote_wins', 'local_wins', 'newest_wins')),
+    sync_interval_seconds INT NOT NULL DEFAULT 3600 CHECK (sync_interval_seconds >= 300),
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    next_sync_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Newest remote modification time pulled so far; the next pull asks the
+    -- remote only for records changed after it.
+    remote_cursor TIMESTAMPTZ,
+    last_sync_at TIMESTAMPTZ,
+    last_sync_status TEXT,
+    last_error TEXT,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE TRIGGER generate_connector_id_trigger
+    BEFORE INSERT ON connectors
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_connectors_updated_at
+    BEFORE UPDATE ON connectors
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_connectors_org_name ON connectors (org_id, lower(name))
+    WHERE deleted_at IS NULL;
+
+-- The sync job's claim query: due active connectors, oldest first.
+CREATE INDEX idx_connectors_due ON connectors (next_sync_at)
+    WHERE deleted_at IS NULL AND is_active;
+
+-- One row per asset a connector has reconciled. remote_updated_at and
+-- local_synced_at are each side's modification time as of the last sync, so
+-- the next run can tell which side changed since (and a conflict is both).
+CREATE TABLE connector_asset_links (
+    connector_id BIGINT NOT NULL REFERENCES connectors(id) ON DELETE CASCADE,
+    asset_id BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    remote_id TEXT NOT NULL,
+    remote_updated_at TIMESTAMPTZ NOT NULL,
+    local_synced_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (connector_id, remote_id),
+    UNIQUE (connector_id, asset_id)
+);
+
+CREATE TABLE connector_sync_runs (
+    id BIGSERIAL PRIMARY KEY,
+    connector_id BIGINT NOT NULL REFERENCES connectors(id) ON DELETE CASCADE,
+    org_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('succeeded', 'partial', 'failed')),
+    pulled INT NOT NULL DEFAULT 0,
+    pushed INT NOT NULL DEFAULT 0,
+    conflicts INT NOT NULL DEFAULT 0,
+    failures INT NOT NULL DEFAULT 0,
+    error TEXT
+);
+
+CREATE INDEX idx_connector_sync_runs_connector ON connector_sync_runs (connector_id, started_at DESC);
+
+COMMENT ON TABLE connectors IS 'Per-org ERP connector config; credentials are vault-sealed';
+COMMENT ON TABLE connector_asset_links IS 'Asset <-> remote record pairing and per-side sync watermarks';
@@ -0,0 +1,4 @@
+SET search_path = trakrf, public;
+
+DROP TRIGGER IF EXISTS trg_tags_unique_across_types ON tags;
+DROP FUNCTION IF EXISTS trakrf.enforce_tag_unique_across_types();
@@ -0,0 +1,48 @@
+-- Optional cross-type tag uniqueness. tags_org_id_type_value_unique keeps a
+-- value unique per type, so by default the same string may be both an RFID
+-- EPC and a barcode. Orgs that set metadata.tags.unique_across_types = true
+-- want a value to identify one thing regardless of how it was read; a partial
+-- index cannot depend on org metadata, so the rule is enforced by trigger.
+--
+-- The trigger takes a transaction-scoped advisory lock on (org, value) so two
+-- concurrent inserts of the same value under different types serialize
+-- instead of both passing the check. Violations raise unique_violation with
+-- CONSTRAINT tags_org_value_unique_across_types, which storage maps to 409.
+-- No GRANTs here: the infra init-grants job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE OR REPLACE FUNCTION trakrf.enforce_tag_unique_across_types() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+DECLARE
+    other_type TEXT;
+BEGIN
+    IF NEW.deleted_at IS NOT NULL THEN
+        RETURN NEW;
+    END IF;
+    IF NOT COALESCE((SELECT (o.metadata->'tags'->>'unique_across_types')::boolean
+                     FROM trakrf.organizations o WHERE o.id = NEW.org_id), false) THEN
+        RETURN NEW;
+    END IF;
+
+    PERFORM pg_advisory_xact_lock(hashtextextended('tags:' || NEW.org_id || ':' || NEW.value, 0));
+
+    SELECT t.type INTO other_type
+    FROM trakrf.tags t
+    WHERE t.org_id = NEW.org_id AND t.value = NEW.value AND t.type <> NEW.type
+      AND t.deleted_at IS NULL AND t.id <> NEW.id
+    LIMIT 1;
+
+    IF other_type IS NOT NULL THEN
+        RAISE EXCEPTION 'tag value % is already in use as a % tag', NEW.value, other_type
+            USING ERRCODE = 'unique_violation',
+                  CONSTRAINT = 'tags_org_value_unique_across_types';
+    END IF;
+    RETURN NEW;
+END;
+$$;
+
+-- Named so it sorts after generate_tag_id_trigger: NEW.id is assigned by then.
+CREATE TRIGGER trg_tags_unique_across_types
+    BEFORE INSERT OR UPDATE OF type, value, deleted_at ON tags
+    FOR EACH ROW
+    EXECUTE FUNCTION trakrf.enforce_tag_unique_across_types();
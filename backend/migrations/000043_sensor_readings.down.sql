@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS trakrf.sensor_alerts;
+DROP TABLE IF EXISTS trakrf.sensor_thresholds;
+DROP TABLE IF EXISTS trakrf.sensor_readings;
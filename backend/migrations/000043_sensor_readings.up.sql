@@ -0,0 +1,119 @@
+-- Condition sensor readings for cold-chain tracking: temperature (and other
+-- condition) loggers attached to assets post readings to
+-- POST /api/v1/sensor-readings. Each org sets min/max limits per asset type
+-- and metric; a reading outside its limit opens a sensor alert, and the first
+-- in-range reading after it resolves the alert. Alert open/resolve is published
+-- like any other entity change (NOTIFY, webhooks, event outbox).
+--
+-- There is no asset type column: an asset's type is metadata.asset_type, the
+-- same free-form key integrators already set on import.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+-- ============================================================================
+-- sensor_readings (hypertable, composite content PK like asset_scans: a
+-- logger re-uploading its buffer does not duplicate readings)
+-- ============================================================================
+CREATE TABLE sensor_readings (
+    recorded_at     TIMESTAMPTZ NOT NULL,
+    org_id          BIGINT NOT NULL REFERENCES organizations(id),
+    asset_id        BIGINT NOT NULL REFERENCES assets(id),
+    metric          TEXT NOT NULL,
+    value           DOUBLE PRECISION NOT NULL,
+    unit            TEXT,
+    device_id       TEXT,
+    received_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (recorded_at, org_id, asset_id, metric)
+);
+
+CREATE INDEX idx_sensor_readings_asset_metric_time ON sensor_readings(asset_id, metric, recorded_at DESC);
+CREATE INDEX idx_sensor_readings_org_time ON sensor_readings(org_id, recorded_at DESC);
+
+SELECT create_hypertable('sensor_readings', 'recorded_at');
+SELECT set_chunk_time_interval('sensor_readings', INTERVAL '1 day');
+SELECT add_retention_policy('sensor_readings', INTERVAL '365 days');
+
+COMMENT ON TABLE sensor_readings IS 'TimescaleDB hypertable of condition sensor readings (temperature, humidity, ...) per asset';
+COMMENT ON COLUMN sensor_readings.recorded_at IS 'When the logger took the reading (logger clock)';
+COMMENT ON COLUMN sensor_readings.received_at IS 'When the platform received it; loggers upload buffered readings late';
+COMMENT ON COLUMN sensor_readings.device_id IS 'Caller-supplied logger identifier; informational';
+
+-- RLS: see asset_scans (000008) for why RLS on a hypertable parent is sound.
+ALTER TABLE sensor_readings ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_sensor_readings ON sensor_readings
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+-- ============================================================================
+-- sensor_thresholds
+-- ============================================================================
+CREATE TABLE sensor_thresholds (
+    id          BIGINT PRIMARY KEY,
+    org_id      BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_type  TEXT NOT NULL,
+    metric      TEXT NOT NULL,
+    min_value   DOUBLE PRECISION,
+    max_value   DOUBLE PRECISION,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT sensor_thresholds_has_limit CHECK (min_value IS NOT NULL OR max_value IS NOT NULL),
+    CONSTRAINT sensor_thresholds_min_le_max CHECK (min_value IS NULL OR max_value IS NULL OR min_value <= max_value),
+    CONSTRAINT sensor_thresholds_org_type_metric UNIQUE (org_id, asset_type, metric)
+);
+
+CREATE TRIGGER generate_sensor_threshold_id_trigger
+    BEFORE INSERT ON sensor_thresholds
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_sensor_thresholds_updated_at
+    BEFORE UPDATE ON sensor_thresholds
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+COMMENT ON COLUMN sensor_thresholds.asset_type IS 'Matched against assets.metadata->>''asset_type''';
+
+ALTER TABLE sensor_thresholds ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_sensor_thresholds ON sensor_thresholds
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+-- ============================================================================
+-- sensor_alerts: one row per excursion. At most one open alert per asset and
+-- metric; later out-of-range readings update it instead of opening another.
+-- ============================================================================
+CREATE TABLE sensor_alerts (
+    id              BIGINT PRIMARY KEY,
+    org_id          BIGINT NOT NULL REFERENCES organizations(id),
+    asset_id        BIGINT NOT NULL REFERENCES assets(id),
+    threshold_id    BIGINT REFERENCES sensor_thresholds(id) ON DELETE SET NULL,
+    metric          TEXT NOT NULL,
+    condition       TEXT NOT NULL CHECK (condition IN ('below_min', 'above_max')),
+    limit_value     DOUBLE PRECISION NOT NULL,
+    trigger_value   DOUBLE PRECISION NOT NULL,
+    extreme_value   DOUBLE PRECISION NOT NULL,
+    last_value      DOUBLE PRECISION NOT NULL,
+    reading_count   INT NOT NULL DEFAULT 1,
+    opened_at       TIMESTAMPTZ NOT NULL,
+    last_reading_at TIMESTAMPTZ NOT NULL,
+    resolved_at     TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_sensor_alert_id_trigger
+    BEFORE INSERT ON sensor_alerts
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_sensor_alerts_updated_at
+    BEFORE UPDATE ON sensor_alerts
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_sensor_alerts_open ON sensor_alerts(asset_id, metric) WHERE resolved_at IS NULL;
+CREATE INDEX idx_sensor_alerts_org_opened ON sensor_alerts(org_id, opened_at DESC);
+
+COMMENT ON COLUMN sensor_alerts.extreme_value IS 'Furthest-out-of-range value seen during the excursion';
+
+ALTER TABLE sensor_alerts ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_sensor_alerts ON sensor_alerts
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS trakrf.idx_tags_org_updated;
+DROP INDEX IF EXISTS trakrf.idx_locations_org_updated;
+DROP INDEX IF EXISTS trakrf.idx_assets_org_updated;
+DROP TABLE IF EXISTS trakrf.sync_mutations;
@@ -0,0 +1,37 @@
+-- Offline sync for the handheld app: devices that lose signal queue their
+-- edits and replay them through POST /api/v1/sync/mutations when they are
+-- back online. Every queued mutation carries a client-generated id; the
+-- outcome of the first attempt is recorded here so a replayed batch (the
+-- response was lost, the app was killed mid-upload) returns the recorded
+-- outcome instead of applying the edit twice.
+--
+-- Rows are pruned by the retention janitor once they are older than the
+-- replay window.
+--
+-- The change feed (GET /api/v1/sync/changes) pages assets, locations and tags
+-- by (updated_at, id); the indexes below keep an incremental pull from
+-- scanning the whole org.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE sync_mutations (
+    org_id              BIGINT NOT NULL REFERENCES organizations(id),
+    client_mutation_id  TEXT NOT NULL,
+    result              JSONB NOT NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id, client_mutation_id)
+);
+
+CREATE INDEX idx_sync_mutations_org_created ON sync_mutations(org_id, created_at);
+
+COMMENT ON COLUMN sync_mutations.result IS 'Outcome returned for the first attempt, replayed verbatim on retries';
+
+ALTER TABLE sync_mutations ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_sync_mutations ON sync_mutations
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+CREATE INDEX idx_assets_org_updated ON assets(org_id, updated_at, id);
+CREATE INDEX idx_locations_org_updated ON locations(org_id, updated_at, id);
+CREATE INDEX idx_tags_org_updated ON tags(org_id, updated_at, id);
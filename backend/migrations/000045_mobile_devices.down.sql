@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS trakrf.idx_assets_org_due;
+DROP TABLE IF EXISTS trakrf.asset_overdue_notices;
+DROP TABLE IF EXISTS trakrf.mobile_devices;
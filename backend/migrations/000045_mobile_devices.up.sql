@@ -0,0 +1,62 @@
+-- Mobile push notifications. Techs register the handheld app's push token
+-- (FCM on Android, APNs on iOS) against their user; the overdue notifier job
+-- pushes "asset overdue" notices to them.
+--
+-- mobile_devices belongs to a user, not an org, like refresh_tokens: one
+-- registration covers every org the user is a member of. The notifier
+-- resolves recipients (the asset's owner, else its team, else the org's
+-- managers and admins) under the org's RLS context. A push token identifies one app install, so it is unique: a
+-- handset that changes hands moves to the new user on re-registration.
+--
+-- An asset is overdue once its metadata.due_at (RFC 3339) has passed.
+-- asset_overdue_notices records which due date each asset was already
+-- notified for, so every due date notifies once; setting a new due_at re-arms
+-- the notice.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE mobile_devices (
+    id            BIGINT PRIMARY KEY,
+    user_id       BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    platform      TEXT NOT NULL CHECK (platform IN ('ios', 'android')),
+    push_token    TEXT NOT NULL,
+    device_name   TEXT,
+    app_version   TEXT,
+    last_seen_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_mobile_device_id_trigger
+    BEFORE INSERT ON mobile_devices
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_mobile_devices_updated_at
+    BEFORE UPDATE ON mobile_devices
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_mobile_devices_push_token ON mobile_devices (push_token);
+CREATE INDEX idx_mobile_devices_user ON mobile_devices (user_id);
+
+COMMENT ON COLUMN mobile_devices.push_token IS 'FCM registration token (android) or APNs device token (ios). Removed when the push service reports it unregistered.';
+COMMENT ON COLUMN mobile_devices.last_seen_at IS 'Last time the app (re-)registered this token';
+
+CREATE TABLE asset_overdue_notices (
+    org_id       BIGINT NOT NULL REFERENCES organizations(id),
+    asset_id     BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    due_at       TEXT NOT NULL,
+    notified_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (asset_id, due_at)
+);
+
+COMMENT ON COLUMN asset_overdue_notices.due_at IS 'metadata.due_at value, verbatim, the notice was sent for';
+
+ALTER TABLE asset_overdue_notices ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_asset_overdue_notices ON asset_overdue_notices
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+-- The notifier's per-org scan for assets carrying a due date.
+CREATE INDEX idx_assets_org_due ON assets (org_id)
+    WHERE deleted_at IS NULL AND metadata ? 'due_at';
@@ -0,0 +1,52 @@
+-- Asset transfers between orgs, for equipment sold between business units
+-- that each run their own org. The sending org's admin initiates a transfer,
+-- the receiving org's admin approves (or rejects) it, and the sending admin
+-- executes it: the asset is deleted from the sending org and recreated in the
+-- receiving one with its external key, fields and tags, plus (optionally) a
+-- copy of its scan history.
+--
+-- No RLS: a transfer is read and decided by both orgs, so org isolation is
+-- app-layer (every query filters on source_org_id or target_org_id), the
+-- same posture as connectors and webhook_endpoints. The asset fields shown to
+-- the receiving org before execution are snapshotted here because it cannot
+-- read the sending org's asset.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE asset_org_transfers (
+    id                  BIGINT PRIMARY KEY,
+    source_org_id       BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    target_org_id       BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_id            BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    asset_external_key  VARCHAR(255) NOT NULL,
+    asset_name          VARCHAR(255) NOT NULL,
+    include_history     BOOLEAN NOT NULL DEFAULT false,
+    note                TEXT,
+    status              TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'completed')),
+    requested_by        BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_by          BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_at          TIMESTAMPTZ,
+    executed_by         BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    executed_at         TIMESTAMPTZ,
+    -- The asset's id in the receiving org once executed.
+    target_asset_id     BIGINT REFERENCES assets(id) ON DELETE SET NULL,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT asset_org_transfers_distinct_orgs CHECK (source_org_id <> target_org_id)
+);
+
+CREATE TRIGGER generate_asset_org_transfer_id_trigger
+    BEFORE INSERT ON asset_org_transfers
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_asset_org_transfers_updated_at
+    BEFORE UPDATE ON asset_org_transfers
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+-- At most one open (pending or approved) transfer per asset.
+CREATE UNIQUE INDEX idx_asset_org_transfers_open ON asset_org_transfers (asset_id)
+    WHERE status IN ('pending', 'approved');
+CREATE INDEX idx_asset_org_transfers_source ON asset_org_transfers (source_org_id, created_at DESC);
+CREATE INDEX idx_asset_org_transfers_target ON asset_org_transfers (target_org_id, created_at DESC);
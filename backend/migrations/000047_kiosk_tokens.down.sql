@@ -0,0 +1 @@
+DROP TABLE IF EXISTS trakrf.kiosk_tokens;
@@ -0,0 +1,39 @@
+-- Kiosk display tokens. A lobby or warehouse TV shows one location's live
+-- inventory without a user session: an org admin mints a long-lived token
+-- bound to that location, and the display presents it to the read-only
+-- /api/v1/kiosk surface.
+--
+-- Only the SHA-256 of the token is stored, as with api_keys.secret_hash.
+-- Tokens do not expire; an admin revokes one by setting revoked_at. No RLS,
+-- like api_keys: the token is resolved before any org context exists, and
+-- the management queries filter by org_id in the app layer.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE kiosk_tokens (
+    id            BIGINT PRIMARY KEY,
+    org_id        BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    location_id   BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    name          VARCHAR(255) NOT NULL,
+    token_hash    TEXT NOT NULL,
+    created_by    BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at  TIMESTAMPTZ,
+    revoked_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_kiosk_token_id_trigger
+    BEFORE INSERT ON kiosk_tokens
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_kiosk_tokens_updated_at
+    BEFORE UPDATE ON kiosk_tokens
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_kiosk_tokens_token_hash ON kiosk_tokens (token_hash);
+CREATE INDEX idx_kiosk_tokens_org ON kiosk_tokens (org_id, created_at DESC);
+
+COMMENT ON COLUMN kiosk_tokens.token_hash IS 'SHA-256 hex of the token; the token itself is shown once, at creation';
+COMMENT ON COLUMN kiosk_tokens.last_used_at IS 'Last kiosk request with this token, to the minute';
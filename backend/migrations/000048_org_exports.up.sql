@@ -0,0 +1,47 @@
+-- Org data exports (full backup). An org admin requests an export; the
+-- org_export job builds a gzipped archive of the org's rows, either JSON or a
+-- SQL script, and stores it here until expires_at. The archive is downloaded
+-- through a short-lived signed URL minted by the export's GET endpoint.
+--
+-- The platform has no object store, so the archive lives in the row (TOAST
+-- keeps it out of line). Completed exports expire after a few days and the
+-- job deletes them, so the table stays small.
+--
+-- No RLS: the job claims pending exports across orgs before it has an org
+-- context, and the API queries filter by org_id in the app layer.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE org_exports (
+    id            BIGINT PRIMARY KEY,
+    org_id        BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    format        TEXT NOT NULL CHECK (format IN ('json', 'sql')),
+    status        TEXT NOT NULL DEFAULT 'pending'
+                  CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    requested_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    error         TEXT,
+    archive       BYTEA,
+    size_bytes    BIGINT,
+    row_count     BIGINT,
+    started_at    TIMESTAMPTZ,
+    completed_at  TIMESTAMPTZ,
+    expires_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_org_export_id_trigger
+    BEFORE INSERT ON org_exports
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_org_exports_updated_at
+    BEFORE UPDATE ON org_exports
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_org_exports_org ON org_exports (org_id, created_at DESC);
+CREATE INDEX idx_org_exports_queue ON org_exports (created_at)
+    WHERE status IN ('pending', 'running');
+
+COMMENT ON COLUMN org_exports.archive IS 'gzipped export; NULL until completed';
+COMMENT ON COLUMN org_exports.expires_at IS 'When the job deletes a completed or failed export';
@@ -0,0 +1,7 @@
+SET search_path = trakrf, public;
+
+DROP INDEX IF EXISTS idx_organizations_sandbox_of;
+
+ALTER TABLE organizations
+    DROP COLUMN IF EXISTS sandbox_of,
+    DROP COLUMN IF EXISTS is_sandbox;
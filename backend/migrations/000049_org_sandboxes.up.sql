@@ -0,0 +1,21 @@
+-- Sandbox orgs. An org admin clones their org into a sandbox: a new org with
+-- the same locations, assets and tags but no scan history, which integrators
+-- can write to freely while building against the API.
+--
+-- is_sandbox is fixed when the org is created; the app never flips it, so a
+-- sandbox cannot turn into a production org or the other way round.
+-- sandbox_of records the org it was cloned from, for display; it is cleared
+-- if that org is hard-deleted.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE organizations
+    ADD COLUMN is_sandbox BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN sandbox_of BIGINT REFERENCES organizations(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_organizations_sandbox_of ON organizations(sandbox_of)
+    WHERE sandbox_of IS NOT NULL;
+
+COMMENT ON COLUMN organizations.is_sandbox IS 'Sandbox (test) org: API responses carry a banner flag and writes never leave the platform (no event export, no connector sync, no cross-org transfers).';
+COMMENT ON COLUMN organizations.sandbox_of IS 'The org this sandbox was cloned from; NULL for production orgs.';
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS trakrf.idx_tags_asset_live;
+DROP INDEX IF EXISTS trakrf.idx_asset_scan_latest_org_asset;
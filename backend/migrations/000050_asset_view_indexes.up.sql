@@ -0,0 +1,15 @@
+-- The asset views list (GET /api/v1/assets/views) pages an org's assets with
+-- each asset's current location, then loads the page's tags in one more
+-- query. These indexes keep both lookups to index probes per asset on the
+-- page, so a page stays fast however many assets the org has.
+--
+-- idx_asset_scan_latest_org_asset serves the per-asset "latest bucket" probe
+-- on the asset_scan_latest continuous aggregate, which otherwise has only the
+-- bucket index Timescale creates. idx_tags_asset_live serves the page's tag
+-- fetch, which reads live tags by asset in creation order.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE INDEX idx_asset_scan_latest_org_asset ON asset_scan_latest (org_id, asset_id, last_seen DESC);
+CREATE INDEX idx_tags_asset_live ON tags (asset_id, created_at) WHERE deleted_at IS NULL;
@@ -0,0 +1,5 @@
+SET search_path = trakrf, public;
+
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_settings_is_object,
+    ALTER COLUMN settings DROP NOT NULL;
@@ -0,0 +1,13 @@
+-- users.settings holds the user's preferences (GET/PUT /api/v1/me/preferences,
+-- modelled by user.Preferences). Until now it was an untyped blob nothing
+-- wrote; the API validates its shape, and the table now guarantees it is at
+-- least an object so the typed read never meets a scalar or null.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+UPDATE users SET settings = '{}' WHERE settings IS NULL OR jsonb_typeof(settings) <> 'object';
+
+ALTER TABLE users
+    ALTER COLUMN settings SET NOT NULL,
+    ADD CONSTRAINT users_settings_is_object CHECK (jsonb_typeof(settings) = 'object');
@@ -0,0 +1,4 @@
+SET search_path = trakrf, public;
+
+DROP TABLE IF EXISTS email_change_tokens;
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_url;
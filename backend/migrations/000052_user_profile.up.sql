@@ -0,0 +1,25 @@
+-- Self-service profile management (/api/v1/me/*).
+--
+-- users.avatar_url is the public URL of the caller's uploaded avatar in the
+-- object store; NULL means none.
+--
+-- email_change_tokens holds pending address changes: the new address waits
+-- here until the link mailed to it is followed, so an account never moves to
+-- an address its owner cannot read. Only the SHA-256 of the token is stored,
+-- as for invitations.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE users ADD COLUMN avatar_url TEXT;
+
+CREATE TABLE email_change_tokens (
+    id          BIGSERIAL PRIMARY KEY,
+    user_id     BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    new_email   VARCHAR(255) NOT NULL,
+    token_hash  VARCHAR(64) NOT NULL UNIQUE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_email_change_tokens_user_id ON email_change_tokens(user_id);
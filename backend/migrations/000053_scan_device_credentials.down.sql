@@ -0,0 +1,8 @@
+SET search_path = trakrf, public;
+
+DROP INDEX IF EXISTS idx_scan_devices_credential_hash;
+
+ALTER TABLE scan_devices
+    DROP COLUMN IF EXISTS credential_last_used_at,
+    DROP COLUMN IF EXISTS credential_issued_at,
+    DROP COLUMN IF EXISTS credential_hash;
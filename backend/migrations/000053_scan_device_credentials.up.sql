@@ -0,0 +1,23 @@
+-- Reader credentials. A scan submitted over a user session (tag lookups,
+-- inventory saves, EPCIS capture) must also name the registered reader it
+-- came from, so a signed-in user cannot probe tag values from a browser
+-- console. An org admin issues each scan device one credential, shown once;
+-- issuing again rotates it and revoking clears it.
+--
+-- Only the SHA-256 of the credential is stored, as with kiosk_tokens. The
+-- lookup is always scoped to the caller's org, so the existing RLS policy
+-- applies unchanged.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE scan_devices
+    ADD COLUMN credential_hash          TEXT,
+    ADD COLUMN credential_issued_at     TIMESTAMPTZ,
+    ADD COLUMN credential_last_used_at  TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX idx_scan_devices_credential_hash
+    ON scan_devices (credential_hash) WHERE credential_hash IS NOT NULL;
+
+COMMENT ON COLUMN scan_devices.credential_hash IS 'SHA-256 hex of the reader credential; the credential itself is shown once, when issued';
+COMMENT ON COLUMN scan_devices.credential_last_used_at IS 'Last scan submitted with the credential, to the minute';
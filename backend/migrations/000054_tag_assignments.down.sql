@@ -0,0 +1,5 @@
+SET search_path = trakrf, public;
+
+DROP TRIGGER IF EXISTS trg_tags_record_assignment ON tags;
+DROP FUNCTION IF EXISTS trakrf.record_tag_assignment();
+DROP TABLE IF EXISTS trakrf.tag_assignments;
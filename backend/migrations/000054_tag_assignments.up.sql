@@ -0,0 +1,87 @@
+-- Tag assignment history. A tag row is soft-deleted when it is removed from
+-- an asset or location, and the same physical tag is often recycled onto a
+-- new asset as a fresh row, so tags alone cannot say which asset a value
+-- identified at a given time. tag_assignments keeps one row per interval a
+-- tag identified an asset or location; unassigned_at is NULL while it is
+-- current.
+--
+-- Rows are written only by the record_tag_assignment trigger, so every path
+-- that attaches, moves or retires a tag (handlers, imports, transfers,
+-- cascading soft deletes) is covered. Existing tags are backfilled from
+-- created_at/deleted_at.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE tag_assignments (
+    id                BIGINT PRIMARY KEY,
+    org_id            BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    tag_id            BIGINT NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    type              VARCHAR(50) NOT NULL,
+    value             VARCHAR(255) NOT NULL,
+    normalized_value  TEXT NOT NULL,
+    asset_id          BIGINT REFERENCES assets(id) ON DELETE CASCADE,
+    location_id       BIGINT REFERENCES locations(id) ON DELETE CASCADE,
+    assigned_at       TIMESTAMPTZ NOT NULL,
+    unassigned_at     TIMESTAMPTZ,
+
+    CONSTRAINT tag_assignment_target CHECK (
+        (asset_id IS NOT NULL AND location_id IS NULL) OR
+        (asset_id IS NULL AND location_id IS NOT NULL)
+    )
+);
+
+CREATE TRIGGER generate_tag_assignment_id_trigger
+    BEFORE INSERT ON tag_assignments
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+-- History of one identifier (type + normalized value), and the open
+-- assignment of a tag row, which the trigger closes.
+CREATE INDEX idx_tag_assignments_identifier ON tag_assignments (org_id, type, normalized_value, assigned_at);
+CREATE UNIQUE INDEX idx_tag_assignments_open ON tag_assignments (tag_id) WHERE unassigned_at IS NULL;
+CREATE INDEX idx_tag_assignments_asset ON tag_assignments (asset_id, assigned_at) WHERE asset_id IS NOT NULL;
+CREATE INDEX idx_tag_assignments_location ON tag_assignments (location_id, assigned_at) WHERE location_id IS NOT NULL;
+
+ALTER TABLE tag_assignments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_tag_assignments ON tag_assignments
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+CREATE OR REPLACE FUNCTION trakrf.record_tag_assignment() RETURNS TRIGGER
+LANGUAGE plpgsql AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NULL
+           AND OLD.asset_id IS NOT DISTINCT FROM NEW.asset_id
+           AND OLD.location_id IS NOT DISTINCT FROM NEW.location_id
+           AND OLD.type = NEW.type AND OLD.value = NEW.value THEN
+            RETURN NULL;
+        END IF;
+        UPDATE trakrf.tag_assignments
+           SET unassigned_at = COALESCE(NEW.deleted_at, CURRENT_TIMESTAMP)
+         WHERE tag_id = NEW.id AND unassigned_at IS NULL;
+    END IF;
+
+    IF NEW.deleted_at IS NULL THEN
+        INSERT INTO trakrf.tag_assignments
+            (org_id, tag_id, type, value, normalized_value, asset_id, location_id, assigned_at)
+        VALUES
+            (NEW.org_id, NEW.id, NEW.type, NEW.value, NEW.normalized_value,
+             NEW.asset_id, NEW.location_id, CURRENT_TIMESTAMP);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER trg_tags_record_assignment
+    AFTER INSERT OR UPDATE OF asset_id, location_id, type, value, deleted_at ON tags
+    FOR EACH ROW
+    EXECUTE FUNCTION trakrf.record_tag_assignment();
+
+INSERT INTO tag_assignments
+    (org_id, tag_id, type, value, normalized_value, asset_id, location_id, assigned_at, unassigned_at)
+SELECT org_id, id, type, value, normalized_value, asset_id, location_id, created_at, deleted_at
+FROM tags;
+
+COMMENT ON TABLE tag_assignments IS 'Which asset or location each tag identified, and when; maintained by trg_tags_record_assignment';
+COMMENT ON COLUMN tag_assignments.unassigned_at IS 'When the tag was removed, moved or retired; NULL while current';
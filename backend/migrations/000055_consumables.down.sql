@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS trakrf.stock_alerts;
+DROP TABLE IF EXISTS trakrf.stock_adjustments;
+DROP TABLE IF EXISTS trakrf.stock_levels;
+DROP TABLE IF EXISTS trakrf.consumables;
@@ -0,0 +1,119 @@
+-- Consumable assets: stock tracked by quantity per location rather than one
+-- row per physical item. An asset becomes a consumable when it has a
+-- consumables row; its stock at each location lives in stock_levels and every
+-- change to a level is recorded in stock_adjustments (a signed adjustment or
+-- a counted quantity from a cycle count). A location whose quantity drops
+-- below the consumable's min_level opens a stock alert, resolved when the
+-- level recovers. Alert open/resolve is published like any other entity
+-- change (NOTIFY, webhooks, event outbox).
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+-- ============================================================================
+-- consumables
+-- ============================================================================
+CREATE TABLE consumables (
+    asset_id    BIGINT PRIMARY KEY REFERENCES assets(id) ON DELETE CASCADE,
+    org_id      BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    unit        TEXT NOT NULL DEFAULT 'each',
+    min_level   INT CHECK (min_level >= 0),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_consumables_updated_at
+    BEFORE UPDATE ON consumables
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_consumables_org ON consumables(org_id);
+
+COMMENT ON COLUMN consumables.min_level IS 'Quantity below which a location opens a stock alert; NULL disables alerts';
+
+ALTER TABLE consumables ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_consumables ON consumables
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+-- ============================================================================
+-- stock_levels: current quantity of a consumable at a location
+-- ============================================================================
+CREATE TABLE stock_levels (
+    asset_id    BIGINT NOT NULL REFERENCES consumables(asset_id) ON DELETE CASCADE,
+    location_id BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    org_id      BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    quantity    INT NOT NULL CHECK (quantity >= 0),
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (asset_id, location_id)
+);
+
+CREATE INDEX idx_stock_levels_org_location ON stock_levels(org_id, location_id);
+
+ALTER TABLE stock_levels ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_stock_levels ON stock_levels
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+-- ============================================================================
+-- stock_adjustments: append-only ledger of level changes
+-- ============================================================================
+CREATE TABLE stock_adjustments (
+    id              BIGINT PRIMARY KEY,
+    org_id          BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_id        BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    location_id     BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    kind            TEXT NOT NULL CHECK (kind IN ('adjust', 'count')),
+    delta           INT NOT NULL,
+    quantity_after  INT NOT NULL CHECK (quantity_after >= 0),
+    reason          TEXT,
+    created_by      BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_stock_adjustment_id_trigger
+    BEFORE INSERT ON stock_adjustments
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE INDEX idx_stock_adjustments_asset_created ON stock_adjustments(asset_id, created_at DESC);
+
+COMMENT ON COLUMN stock_adjustments.kind IS 'adjust: caller-supplied delta; count: counted quantity replaced the level';
+
+ALTER TABLE stock_adjustments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_stock_adjustments ON stock_adjustments
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+-- ============================================================================
+-- stock_alerts: one row per shortfall. At most one open alert per consumable
+-- and location.
+-- ============================================================================
+CREATE TABLE stock_alerts (
+    id          BIGINT PRIMARY KEY,
+    org_id      BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_id    BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    location_id BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    min_level   INT NOT NULL,
+    quantity    INT NOT NULL,
+    opened_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_stock_alert_id_trigger
+    BEFORE INSERT ON stock_alerts
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_stock_alerts_updated_at
+    BEFORE UPDATE ON stock_alerts
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_stock_alerts_open ON stock_alerts(asset_id, location_id) WHERE resolved_at IS NULL;
+CREATE INDEX idx_stock_alerts_org_opened ON stock_alerts(org_id, opened_at DESC);
+
+COMMENT ON COLUMN stock_alerts.quantity IS 'Latest quantity while open; the recovered quantity once resolved';
+
+ALTER TABLE stock_alerts ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_stock_alerts ON stock_alerts
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
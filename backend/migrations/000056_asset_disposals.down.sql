@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trakrf.asset_disposals;
+ALTER TABLE trakrf.assets DROP COLUMN IF EXISTS disposed_at;
@@ -0,0 +1,50 @@
+-- Asset disposal: retiring an asset that has been sold, scrapped, lost, etc.
+-- An operator requests the disposal with a reason and method, an org admin
+-- approves (or rejects) it, and an operator executes it once the asset has
+-- physically left. Executing stamps assets.disposed_at and deactivates the
+-- asset; scan paths drop reads of a disposed asset so a stray read of its tag
+-- cannot put it back at a location.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE assets ADD COLUMN disposed_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN assets.disposed_at IS 'When an approved disposal was executed; scans of a disposed asset are dropped';
+
+CREATE TABLE asset_disposals (
+    id                  BIGINT PRIMARY KEY,
+    org_id              BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_id            BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    reason              TEXT NOT NULL,
+    method              TEXT NOT NULL
+        CHECK (method IN ('sold', 'scrapped', 'recycled', 'donated', 'returned', 'lost', 'stolen', 'other')),
+    status              TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'completed')),
+    requested_by        BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_by          BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_at          TIMESTAMPTZ,
+    decision_note       TEXT,
+    executed_by         BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    executed_at         TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_asset_disposal_id_trigger
+    BEFORE INSERT ON asset_disposals
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_asset_disposals_updated_at
+    BEFORE UPDATE ON asset_disposals
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+-- At most one open (pending or approved) disposal per asset.
+CREATE UNIQUE INDEX idx_asset_disposals_open ON asset_disposals (asset_id)
+    WHERE status IN ('pending', 'approved');
+CREATE INDEX idx_asset_disposals_org_created ON asset_disposals (org_id, created_at DESC);
+
+ALTER TABLE asset_disposals ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_asset_disposals ON asset_disposals
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trakrf.approval_requests;
+DROP TABLE IF EXISTS trakrf.approval_rules;
@@ -0,0 +1,70 @@
+-- Two-person approval for sensitive operations. An org admin configures, per
+-- operation, whether it needs a second admin's sign-off: deleting more than
+-- a threshold of assets at once, changing org settings, or granting the admin
+-- role. A gated request that matches a rule is parked as a pending approval
+-- request instead of running; once a different admin approves it, the
+-- requester replays the identical request with X-Approval-ID and it runs.
+-- Requests that are not decided and used within the rule's TTL expire.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE approval_rules (
+    org_id              BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    operation           TEXT NOT NULL
+        CHECK (operation IN ('assets.bulk_delete', 'org.settings', 'org.admin_grant')),
+    threshold           INT CHECK (threshold >= 0),
+    ttl_hours           INT NOT NULL DEFAULT 72 CHECK (ttl_hours BETWEEN 1 AND 720),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (org_id, operation)
+);
+
+COMMENT ON COLUMN approval_rules.threshold IS 'assets.bulk_delete only: deletes of at most this many assets skip approval; NULL gates every bulk delete';
+
+CREATE TRIGGER update_approval_rules_updated_at
+    BEFORE UPDATE ON approval_rules
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+ALTER TABLE approval_rules ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_approval_rules ON approval_rules
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+CREATE TABLE approval_requests (
+    id                  BIGINT PRIMARY KEY,
+    org_id              BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    operation           TEXT NOT NULL,
+    method              TEXT NOT NULL,
+    path                TEXT NOT NULL,
+    body                JSONB,
+    body_sha256         TEXT NOT NULL,
+    summary             TEXT NOT NULL,
+    status              TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'cancelled', 'used')),
+    requested_by        BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_by          BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_at          TIMESTAMPTZ,
+    decision_note       TEXT,
+    expires_at          TIMESTAMPTZ NOT NULL,
+    used_at             TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON COLUMN approval_requests.body_sha256 IS 'Hex SHA-256 of the request body; the replayed request must match it exactly';
+
+CREATE TRIGGER generate_approval_request_id_trigger
+    BEFORE INSERT ON approval_requests
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_approval_requests_updated_at
+    BEFORE UPDATE ON approval_requests
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_approval_requests_org_created ON approval_requests (org_id, created_at DESC);
+
+ALTER TABLE approval_requests ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_approval_requests ON approval_requests
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
@@ -0,0 +1,6 @@
+DELETE FROM trakrf.approval_rules WHERE operation = 'org.ownership_transfer';
+ALTER TABLE trakrf.approval_rules DROP CONSTRAINT approval_rules_operation_check;
+ALTER TABLE trakrf.approval_rules ADD CONSTRAINT approval_rules_operation_check
+    CHECK (operation IN ('assets.bulk_delete', 'org.settings', 'org.admin_grant'));
+
+DROP TABLE IF EXISTS trakrf.org_ownership_transfers;
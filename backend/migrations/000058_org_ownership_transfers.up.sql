@@ -0,0 +1,59 @@
+-- Org ownership transfer. organizations.owner_user_id (000025) was seeded at
+-- signup and immutable; it becomes transferable here. The owner proposes a
+-- transfer to another member, the new owner confirms it, and on confirmation
+-- the new owner is made an admin and the previous owner is demoted to the
+-- role chosen when the transfer was proposed. The owner cannot be removed or
+-- demoted, so an org always keeps the person responsible for it. Transfer
+-- rows double as the audit trail of who handed the org to whom, and when.
+--
+-- Orgs created before ownership was tracked get their longest-standing admin
+-- as owner.
+--
+-- Like org_users and org_invitations, the table has no RLS: the recipient
+-- reads and confirms a transfer without it being their current org.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+UPDATE organizations o
+   SET owner_user_id = (
+       SELECT ou.user_id FROM org_users ou
+        WHERE ou.org_id = o.id AND ou.role = 'admin' AND ou.deleted_at IS NULL
+        ORDER BY ou.created_at, ou.user_id
+        LIMIT 1)
+ WHERE o.owner_user_id IS NULL AND o.deleted_at IS NULL;
+
+COMMENT ON COLUMN organizations.owner_user_id IS 'The org owner: seeded to the creating admin, changed only by a confirmed ownership transfer. The owner cannot be removed or demoted.';
+
+CREATE TABLE org_ownership_transfers (
+    id                  BIGINT PRIMARY KEY,
+    org_id              BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    from_user_id        BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    to_user_id          BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    previous_owner_role org_role NOT NULL DEFAULT 'manager',
+    status              TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'accepted', 'declined', 'cancelled', 'expired')),
+    expires_at          TIMESTAMPTZ NOT NULL,
+    decided_by          BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    decided_at          TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_org_ownership_transfer_id_trigger
+    BEFORE INSERT ON org_ownership_transfers
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_org_ownership_transfers_updated_at
+    BEFORE UPDATE ON org_ownership_transfers
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+-- At most one pending transfer per org.
+CREATE UNIQUE INDEX idx_org_ownership_transfers_pending ON org_ownership_transfers (org_id)
+    WHERE status = 'pending';
+CREATE INDEX idx_org_ownership_transfers_to_user ON org_ownership_transfers (to_user_id);
+
+-- Ownership transfer can be made to need a second admin's approval.
+ALTER TABLE approval_rules DROP CONSTRAINT approval_rules_operation_check;
+ALTER TABLE approval_rules ADD CONSTRAINT approval_rules_operation_check
+    CHECK (operation IN ('assets.bulk_delete', 'org.settings', 'org.admin_grant', 'org.ownership_transfer'));
@@ -0,0 +1,5 @@
+SET search_path = trakrf, public;
+
+DROP INDEX IF EXISTS idx_organizations_partner;
+
+COMMENT ON COLUMN organizations.partner_id IS 'TRA-947: dormant forward-hook for ThingsBoard-style partner tenancy / whitelabel. No behavior attached yet.';
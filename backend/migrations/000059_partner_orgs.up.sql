@@ -0,0 +1,11 @@
+-- Partners become live billing entities: a partner (reseller, or a customer
+-- billed for several orgs) owns any number of orgs through
+-- organizations.partner_id, and a superadmin lists a partner's orgs and moves
+-- orgs between partners. The index serves the per-partner org list.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE INDEX idx_organizations_partner ON organizations(partner_id) WHERE partner_id IS NOT NULL;
+
+COMMENT ON COLUMN organizations.partner_id IS 'The billing entity (partner) that owns this org; NULL = billed on its own. Set by superadmins via PUT /api/v1/orgs/{id}/partner.';
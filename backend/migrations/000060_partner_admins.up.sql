@@ -0,0 +1,17 @@
+-- Partner admins: users who administer a partner (billing entity) and can
+-- read roll-up reports across every org it owns, without being a member of
+-- those orgs. Granted and revoked by superadmins. Like partners, the table is
+-- internal and has no per-org RLS.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE partner_admins (
+    partner_id BIGINT NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    user_id    BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_by BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (partner_id, user_id)
+);
+
+CREATE INDEX idx_partner_admins_user ON partner_admins(user_id);
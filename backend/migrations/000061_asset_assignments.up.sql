@@ -0,0 +1,37 @@
+-- Asset check-out / check-in history. Assigning an asset to an owner (POST
+-- /assets/{id}/transfer) is a check-out to that user; POST
+-- /assets/{id}/check-in clears the owner. Each records a row here, with the
+-- handover's electronic signature when the caller captured one: either a
+-- PNG image or the pen strokes, stored verbatim as JSON so custody documents
+-- can hash and redraw it. Ownership set before this migration has no row.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE asset_assignments (
+    id            BIGINT PRIMARY KEY,
+    org_id        BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_id      BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    action        TEXT NOT NULL CHECK (action IN ('check_out', 'check_in')),
+    -- The new owner on check-out; the owner handing the asset back on check-in.
+    user_id       BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    -- The session user who recorded it; NULL for API-key callers.
+    performed_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    note          TEXT,
+    signature     JSONB,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_asset_assignment_id_trigger
+    BEFORE INSERT ON asset_assignments
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE INDEX idx_asset_assignments_asset ON asset_assignments (asset_id, created_at);
+
+ALTER TABLE asset_assignments ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_asset_assignments ON asset_assignments
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+COMMENT ON TABLE asset_assignments IS 'Asset check-out (owner assigned) and check-in (owner cleared) history';
+COMMENT ON COLUMN asset_assignments.signature IS 'Handover signature: {signer_name, image_png} or {signer_name, width, height, strokes}';
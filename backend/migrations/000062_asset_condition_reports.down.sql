@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trakrf.asset_condition_photos;
+DROP TABLE IF EXISTS trakrf.asset_condition_reports;
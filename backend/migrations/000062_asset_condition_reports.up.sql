@@ -0,0 +1,55 @@
+-- Asset condition assessments. A condition report rates an asset from 1
+-- (unusable) to 5 (as new) with optional notes, either ad hoc or as part of
+-- a check-out / check-in (assignment_id). Photos are uploaded to the object
+-- store afterwards and listed in asset_condition_photos by public URL.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE asset_condition_reports (
+    id             BIGINT PRIMARY KEY,
+    org_id         BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_id       BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    assignment_id  BIGINT REFERENCES asset_assignments(id) ON DELETE SET NULL,
+    rating         SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    notes          TEXT,
+    -- The session user who recorded it; NULL for API-key callers.
+    reported_by    BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_asset_condition_report_id_trigger
+    BEFORE INSERT ON asset_condition_reports
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE INDEX idx_asset_condition_reports_asset ON asset_condition_reports (asset_id, created_at);
+CREATE INDEX idx_asset_condition_reports_org ON asset_condition_reports (org_id, created_at);
+
+ALTER TABLE asset_condition_reports ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_asset_condition_reports ON asset_condition_reports
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+CREATE TABLE asset_condition_photos (
+    id            BIGINT PRIMARY KEY,
+    org_id        BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    report_id     BIGINT NOT NULL REFERENCES asset_condition_reports(id) ON DELETE CASCADE,
+    url           TEXT NOT NULL,
+    content_type  TEXT NOT NULL,
+    size_bytes    INTEGER NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_asset_condition_photo_id_trigger
+    BEFORE INSERT ON asset_condition_photos
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE INDEX idx_asset_condition_photos_report ON asset_condition_photos (report_id);
+
+ALTER TABLE asset_condition_photos ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_asset_condition_photos ON asset_condition_photos
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+COMMENT ON TABLE asset_condition_reports IS 'Asset condition assessments: a 1-5 rating with notes, ad hoc or at check-out/check-in';
+COMMENT ON TABLE asset_condition_photos IS 'Photo evidence for condition reports, stored in the object store';
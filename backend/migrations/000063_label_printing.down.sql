@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trakrf.print_jobs;
+DROP TABLE IF EXISTS trakrf.label_printers;
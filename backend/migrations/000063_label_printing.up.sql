@@ -0,0 +1,77 @@
+-- Label printing to networked Zebra printers. An org admin registers each
+-- printer at a location by host and raw-print port (9100); an operator
+-- queues a print job of asset labels, rendered to ZPL when queued; the
+-- label_print job claims queued jobs and streams their ZPL to the printer
+-- over TCP, retrying a few times when the printer cannot be reached.
+--
+-- print_jobs has no RLS: the job claims queued jobs across orgs before it
+-- has an org context (as org_exports), and the API queries filter by org_id
+-- in the app layer. label_printers is org-scoped as usual.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE label_printers (
+    id           BIGINT PRIMARY KEY,
+    org_id       BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    location_id  BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    name         VARCHAR(255) NOT NULL,
+    host         VARCHAR(255) NOT NULL,
+    port         INTEGER NOT NULL DEFAULT 9100 CHECK (port BETWEEN 1 AND 65535),
+    dpi          INTEGER NOT NULL DEFAULT 203 CHECK (dpi IN (203, 300, 600)),
+    encode_rfid  BOOLEAN NOT NULL DEFAULT false,
+    is_active    BOOLEAN NOT NULL DEFAULT true,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_label_printer_id_trigger
+    BEFORE INSERT ON label_printers
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_label_printers_updated_at
+    BEFORE UPDATE ON label_printers
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_label_printers_location ON label_printers (org_id, location_id);
+
+ALTER TABLE label_printers ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_label_printers ON label_printers
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+CREATE TABLE print_jobs (
+    id               BIGINT PRIMARY KEY,
+    org_id           BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    printer_id       BIGINT NOT NULL REFERENCES label_printers(id) ON DELETE CASCADE,
+    status           TEXT NOT NULL DEFAULT 'pending'
+                     CHECK (status IN ('pending', 'printing', 'completed', 'failed')),
+    asset_ids        BIGINT[] NOT NULL,
+    copies           INTEGER NOT NULL DEFAULT 1,
+    label_count      INTEGER NOT NULL,
+    zpl              TEXT NOT NULL,
+    requested_by     BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    attempts         INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    error            TEXT,
+    started_at       TIMESTAMPTZ,
+    completed_at     TIMESTAMPTZ,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_print_job_id_trigger
+    BEFORE INSERT ON print_jobs
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_print_jobs_updated_at
+    BEFORE UPDATE ON print_jobs
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_print_jobs_org ON print_jobs (org_id, created_at DESC);
+CREATE INDEX idx_print_jobs_queue ON print_jobs (next_attempt_at)
+    WHERE status IN ('pending', 'printing');
+
+COMMENT ON TABLE label_printers IS 'Networked ZPL label printers, one location each';
+COMMENT ON COLUMN label_printers.encode_rfid IS 'Write the asset''s RFID EPC to the label''s inlay (RFID printers)';
+COMMENT ON COLUMN print_jobs.zpl IS 'The job''s labels, rendered when queued and sent verbatim';
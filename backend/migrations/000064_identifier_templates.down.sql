@@ -0,0 +1 @@
+DROP TABLE IF EXISTS trakrf.identifier_templates;
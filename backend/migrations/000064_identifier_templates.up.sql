@@ -0,0 +1,27 @@
+-- Per-org identifier template for auto-assigned asset external_keys: a
+-- prefix, a zero-padded width and the next sequence value. POST
+-- /api/v1/assets?auto_identifier=true takes the next value under a row lock
+-- in the same transaction as the insert, so concurrent creates never draw
+-- the same identifier and a failed create does not burn one. An org without
+-- a row gets ASSET-NNNN, continuing after its highest existing ASSET- key.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE identifier_templates (
+    org_id      BIGINT PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    prefix      VARCHAR(32) NOT NULL DEFAULT 'ASSET-' CHECK (prefix ~ '^[A-Za-z0-9-]*$'),
+    padding     INTEGER NOT NULL DEFAULT 4 CHECK (padding BETWEEN 1 AND 12),
+    next_value  BIGINT NOT NULL DEFAULT 1 CHECK (next_value >= 1),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_identifier_templates_updated_at
+    BEFORE UPDATE ON identifier_templates
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+ALTER TABLE identifier_templates ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_identifier_templates ON identifier_templates
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
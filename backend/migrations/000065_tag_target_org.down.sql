@@ -0,0 +1,6 @@
+ALTER TABLE trakrf.tags
+    DROP CONSTRAINT IF EXISTS tags_location_org_fkey,
+    DROP CONSTRAINT IF EXISTS tags_asset_org_fkey;
+
+ALTER TABLE trakrf.locations DROP CONSTRAINT IF EXISTS locations_id_org_id_key;
+ALTER TABLE trakrf.assets DROP CONSTRAINT IF EXISTS assets_id_org_id_key;
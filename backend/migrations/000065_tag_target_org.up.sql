@@ -0,0 +1,40 @@
+-- A tag's asset or location must belong to the tag's org. tags.asset_id and
+-- tags.location_id reference the target by id alone, and foreign-key checks
+-- are not subject to RLS, so a write naming another org's asset id passed.
+-- Composite keys on (id, org_id) let tags reference its target together
+-- with its own org_id; the single-column keys stay for ON DELETE behavior
+-- and existing plans.
+--
+-- The new constraints are added NOT VALID, so they guard every write from
+-- now on, and are validated straight away when no existing tag crosses
+-- orgs. A database holding such rows keeps them until they are cleaned up
+-- and the constraint is validated by hand.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE assets ADD CONSTRAINT assets_id_org_id_key UNIQUE (id, org_id);
+ALTER TABLE locations ADD CONSTRAINT locations_id_org_id_key UNIQUE (id, org_id);
+
+ALTER TABLE tags
+    ADD CONSTRAINT tags_asset_org_fkey FOREIGN KEY (asset_id, org_id)
+        REFERENCES assets(id, org_id) NOT VALID,
+    ADD CONSTRAINT tags_location_org_fkey FOREIGN KEY (location_id, org_id)
+        REFERENCES locations(id, org_id) NOT VALID;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM tags t JOIN assets a ON a.id = t.asset_id
+        WHERE a.org_id <> t.org_id
+    ) THEN
+        ALTER TABLE tags VALIDATE CONSTRAINT tags_asset_org_fkey;
+    END IF;
+    IF NOT EXISTS (
+        SELECT 1 FROM tags t JOIN locations l ON l.id = t.location_id
+        WHERE l.org_id <> t.org_id
+    ) THEN
+        ALTER TABLE tags VALIDATE CONSTRAINT tags_location_org_fkey;
+    END IF;
+END
+$$;
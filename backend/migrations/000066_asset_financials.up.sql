@@ -0,0 +1,34 @@
+-- Asset financial fields: purchase cost and book value, in one currency per
+-- asset. Amounts are BIGINT minor units of that currency (cents for USD, yen
+-- for JPY) so sums are exact; the API writes them as decimal strings. A row
+-- without a currency named on write takes the org's currency setting
+-- (organizations.metadata.currency, USD by default). Reports total each
+-- currency separately: amounts are never converted.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE asset_financials (
+    asset_id       BIGINT PRIMARY KEY,
+    org_id         BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    currency       CHAR(3) NOT NULL CHECK (currency ~ '^[A-Z]{3}$'),
+    purchase_cost  BIGINT CHECK (purchase_cost >= 0),
+    book_value     BIGINT CHECK (book_value >= 0),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (asset_id, org_id) REFERENCES assets(id, org_id) ON DELETE CASCADE
+);
+
+CREATE TRIGGER update_asset_financials_updated_at
+    BEFORE UPDATE ON asset_financials
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_asset_financials_org ON asset_financials (org_id);
+
+ALTER TABLE asset_financials ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_asset_financials ON asset_financials
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+COMMENT ON COLUMN asset_financials.purchase_cost IS 'Purchase cost in minor units of currency';
+COMMENT ON COLUMN asset_financials.book_value IS 'Current book value in minor units of currency';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS trakrf.location_shifts;
+DROP FUNCTION IF EXISTS trakrf.location_time_zone(BIGINT);
+ALTER TABLE trakrf.locations DROP COLUMN IF EXISTS time_zone;
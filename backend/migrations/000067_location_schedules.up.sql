@@ -0,0 +1,53 @@
+-- Location time zones and shifts, for reports bucketed in a site's local
+-- time. locations.time_zone is an IANA zone; NULL inherits the nearest
+-- ancestor's, and a tree with none set reports in UTC. location_shifts are
+-- recurring working periods in that local time: an end_time at or before
+-- start_time runs past midnight. Shift occurrences are computed from local
+-- wall-clock times, so they keep their hours across DST changes.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE locations ADD COLUMN time_zone TEXT CHECK (time_zone <> '');
+
+COMMENT ON COLUMN locations.time_zone IS 'IANA time zone reports bucket this location in; NULL inherits the parent''s';
+
+-- location_time_zone is the zone location p_location_id reports in: its own,
+-- else its nearest ancestor's, else UTC.
+CREATE OR REPLACE FUNCTION trakrf.location_time_zone(p_location_id BIGINT)
+RETURNS TEXT
+LANGUAGE sql
+STABLE
+AS $$
+    WITH RECURSIVE up AS (
+        SELECT id, parent_location_id, time_zone, 0 AS depth
+        FROM trakrf.locations WHERE id = p_location_id
+        UNION ALL
+        SELECT l.id, l.parent_location_id, l.time_zone, up.depth + 1
+        FROM trakrf.locations l
+        JOIN up ON l.id = up.parent_location_id
+        WHERE up.time_zone IS NULL AND up.depth < 64
+    )
+    SELECT COALESCE((SELECT time_zone FROM up WHERE time_zone IS NOT NULL ORDER BY depth LIMIT 1), 'UTC');
+$$;
+
+CREATE TABLE location_shifts (
+    location_id  BIGINT NOT NULL,
+    org_id       BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name         VARCHAR(64) NOT NULL CHECK (name <> ''),
+    start_time   TIME NOT NULL,
+    end_time     TIME NOT NULL,
+    -- ISO days of the week the shift starts on, 1 (Monday) to 7 (Sunday).
+    weekdays     SMALLINT[] NOT NULL DEFAULT '{1,2,3,4,5,6,7}'
+                 CHECK (cardinality(weekdays) > 0 AND weekdays <@ '{1,2,3,4,5,6,7}'::SMALLINT[]),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (location_id, name),
+    FOREIGN KEY (location_id, org_id) REFERENCES locations(id, org_id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_location_shifts_org ON location_shifts (org_id);
+
+ALTER TABLE location_shifts ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_location_shifts ON location_shifts
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS trakrf.idx_assets_org_person;
+ALTER TABLE trakrf.assets DROP CONSTRAINT IF EXISTS assets_person_flag_boolean;
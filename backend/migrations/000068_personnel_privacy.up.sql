@@ -0,0 +1,20 @@
+-- Personnel tracking. A person-asset is an asset with metadata.person = true:
+-- a badged person rather than equipment, tracked for mustering. The flag is
+-- now typed, a JSON boolean, so that being a person never depends on how a
+-- string was spelled. NOT VALID: existing rows are left as they are and only
+-- writes from here on are checked.
+--
+-- Privacy settings for person-assets live in organizations.metadata:
+-- retention.personnel_scan_days (their scans' retention window, which the
+-- retention janitor enforces) and personnel.anonymize_reports.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE assets ADD CONSTRAINT assets_person_flag_boolean
+    CHECK (NOT metadata ? 'person' OR jsonb_typeof(metadata->'person') = 'boolean') NOT VALID;
+
+-- The janitor's personnel prune and the mustering queries find an org's
+-- persons by the flag.
+CREATE INDEX idx_assets_org_person ON assets (org_id, id)
+    WHERE metadata->>'person' = 'true';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trakrf.dock_movements;
+DROP TABLE IF EXISTS trakrf.dock_doors;
@@ -0,0 +1,73 @@
+-- Dock door direction detection. A dock door pairs two scan points (antennas)
+-- across one opening: the outer one faces the yard or trailer, the inner one
+-- the building. The direction ingest evaluator watches which of the pair
+-- hears a tag first in a pass through the door and records a dock_movements
+-- row per crossing: outer then inner is inbound (receiving), inner then outer
+-- outbound (shipping). A scan point belongs to at most one door.
+--
+-- Deleting a door deletes its movements; deactivating it stops detection and
+-- keeps them.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE dock_doors (
+    id                   BIGINT PRIMARY KEY,
+    org_id               BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    location_id          BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    name                 VARCHAR(255) NOT NULL,
+    outer_scan_point_id  BIGINT NOT NULL REFERENCES scan_points(id) ON DELETE CASCADE,
+    inner_scan_point_id  BIGINT NOT NULL REFERENCES scan_points(id) ON DELETE CASCADE,
+    window_seconds       INTEGER NOT NULL DEFAULT 10 CHECK (window_seconds BETWEEN 1 AND 300),
+    is_active            BOOLEAN NOT NULL DEFAULT true,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT dock_doors_distinct_scan_points CHECK (outer_scan_point_id <> inner_scan_point_id)
+);
+
+CREATE TRIGGER generate_dock_door_id_trigger
+    BEFORE INSERT ON dock_doors
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_dock_doors_updated_at
+    BEFORE UPDATE ON dock_doors
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_dock_doors_location ON dock_doors (org_id, location_id);
+CREATE UNIQUE INDEX idx_dock_doors_outer_scan_point ON dock_doors (outer_scan_point_id);
+CREATE UNIQUE INDEX idx_dock_doors_inner_scan_point ON dock_doors (inner_scan_point_id);
+
+ALTER TABLE dock_doors ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_dock_doors ON dock_doors
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+CREATE TABLE dock_movements (
+    id             BIGINT PRIMARY KEY,
+    org_id         BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    dock_door_id   BIGINT NOT NULL REFERENCES dock_doors(id) ON DELETE CASCADE,
+    asset_id       BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    direction      TEXT NOT NULL CHECK (direction IN ('inbound', 'outbound')),
+    first_read_at  TIMESTAMPTZ NOT NULL,
+    crossed_at     TIMESTAMPTZ NOT NULL,
+    tag_scan_id    BIGINT,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER generate_dock_movement_id_trigger
+    BEFORE INSERT ON dock_movements
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE INDEX idx_dock_movements_org ON dock_movements (org_id, crossed_at DESC);
+CREATE INDEX idx_dock_movements_door ON dock_movements (dock_door_id, crossed_at DESC);
+
+ALTER TABLE dock_movements ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_dock_movements ON dock_movements
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+COMMENT ON TABLE dock_doors IS 'Paired outer/inner scan points across a dock door opening';
+COMMENT ON COLUMN dock_doors.window_seconds IS 'Longest gap between reads of one pass through the door';
+COMMENT ON TABLE dock_movements IS 'Directional crossings of a dock door, inbound (outer then inner) or outbound';
+COMMENT ON COLUMN dock_movements.first_read_at IS 'When the pass was first heard, on the side it came from';
+COMMENT ON COLUMN dock_movements.crossed_at IS 'When the other side first heard it';
@@ -0,0 +1,71 @@
+-- Transfer orders between locations. An order names an origin and a
+-- destination location and the assets expected to travel between them. It
+-- is verified by scanning twice: at the origin while it is open, then at the
+-- destination while it is in transit. Each transfer_order_items row records
+-- when the asset was scanned at each end; an asset scanned that the order
+-- did not list is added with expected = false, so the discrepancy report
+-- (missing and extra assets at either end) derives from the items alone.
+--
+-- open → in_transit (ship) → received (receive); an open or in-transit order
+-- can be cancelled.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE transfer_orders (
+    id                       BIGINT PRIMARY KEY,
+    org_id                   BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    reference                VARCHAR(255),
+    origin_location_id       BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    destination_location_id  BIGINT NOT NULL REFERENCES locations(id) ON DELETE CASCADE,
+    status                   TEXT NOT NULL DEFAULT 'open'
+                             CHECK (status IN ('open', 'in_transit', 'received', 'cancelled')),
+    note                     TEXT,
+    created_by               BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    shipped_by               BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    shipped_at               TIMESTAMPTZ,
+    received_by              BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    received_at              TIMESTAMPTZ,
+    cancelled_at             TIMESTAMPTZ,
+    created_at               TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at               TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT transfer_orders_distinct_locations CHECK (origin_location_id <> destination_location_id)
+);
+
+CREATE TRIGGER generate_transfer_order_id_trigger
+    BEFORE INSERT ON transfer_orders
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_transfer_orders_updated_at
+    BEFORE UPDATE ON transfer_orders
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_transfer_orders_org ON transfer_orders (org_id, created_at DESC);
+CREATE INDEX idx_transfer_orders_origin ON transfer_orders (origin_location_id);
+CREATE INDEX idx_transfer_orders_destination ON transfer_orders (destination_location_id);
+
+ALTER TABLE transfer_orders ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_transfer_orders ON transfer_orders
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+CREATE TABLE transfer_order_items (
+    transfer_order_id       BIGINT NOT NULL REFERENCES transfer_orders(id) ON DELETE CASCADE,
+    org_id                  BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_id                BIGINT NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    expected                BOOLEAN NOT NULL DEFAULT true,
+    origin_scanned_at       TIMESTAMPTZ,
+    destination_scanned_at  TIMESTAMPTZ,
+    PRIMARY KEY (transfer_order_id, asset_id)
+);
+
+CREATE INDEX idx_transfer_order_items_asset ON transfer_order_items (asset_id);
+
+ALTER TABLE transfer_order_items ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_transfer_order_items ON transfer_order_items
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+COMMENT ON TABLE transfer_orders IS 'Planned moves of a set of assets from one location to another';
+COMMENT ON TABLE transfer_order_items IS 'Assets of a transfer order: expected ones, and extras scanned at either end';
+COMMENT ON COLUMN transfer_order_items.expected IS 'False for an asset the order did not list but a verification scan found';
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS trakrf.idx_transfer_orders_tracking_due;
+
+ALTER TABLE trakrf.transfer_orders
+    DROP CONSTRAINT IF EXISTS transfer_orders_tracking_pair,
+    DROP COLUMN IF EXISTS tracking_next_check_at,
+    DROP COLUMN IF EXISTS tracking_error,
+    DROP COLUMN IF EXISTS tracking_checked_at,
+    DROP COLUMN IF EXISTS tracking_event_at,
+    DROP COLUMN IF EXISTS tracking_eta,
+    DROP COLUMN IF EXISTS tracking_detail,
+    DROP COLUMN IF EXISTS tracking_status,
+    DROP COLUMN IF EXISTS tracking_number,
+    DROP COLUMN IF EXISTS carrier;
@@ -0,0 +1,32 @@
+-- Carrier tracking on transfer orders. An order moving between sites by a
+-- third-party carrier can carry the carrier and its tracking number; the
+-- carrier poller asks the carrier's API for the shipment's status and ETA
+-- and keeps the latest on the order. Tracking is informational: the order's
+-- own status still moves by ship and receive.
+--
+-- tracking_next_check_at schedules the poller. It is set when tracking is
+-- attached, pushed out by each poll, and cleared once the carrier reports
+-- the shipment delivered or the order is received or cancelled.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+ALTER TABLE transfer_orders
+    ADD COLUMN carrier                 VARCHAR(50),
+    ADD COLUMN tracking_number         VARCHAR(255),
+    ADD COLUMN tracking_status         TEXT
+        CHECK (tracking_status IN ('pre_transit', 'in_transit', 'delivered', 'exception', 'unknown')),
+    ADD COLUMN tracking_detail         TEXT,
+    ADD COLUMN tracking_eta            TIMESTAMPTZ,
+    ADD COLUMN tracking_event_at       TIMESTAMPTZ,
+    ADD COLUMN tracking_checked_at     TIMESTAMPTZ,
+    ADD COLUMN tracking_error          TEXT,
+    ADD COLUMN tracking_next_check_at  TIMESTAMPTZ,
+    ADD CONSTRAINT transfer_orders_tracking_pair CHECK ((carrier IS NULL) = (tracking_number IS NULL));
+
+CREATE INDEX idx_transfer_orders_tracking_due ON transfer_orders (tracking_next_check_at)
+    WHERE tracking_next_check_at IS NOT NULL;
+
+COMMENT ON COLUMN transfer_orders.carrier IS 'Carrier code, e.g. dhl; polled only when the server has a tracker for it';
+COMMENT ON COLUMN transfer_orders.tracking_status IS 'Carrier shipment status, normalized across carriers';
+COMMENT ON COLUMN transfer_orders.tracking_error IS 'Why the last poll failed; cleared by the next successful one';
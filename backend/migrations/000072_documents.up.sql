@@ -0,0 +1,62 @@
+-- Document vault. A document is an insurance policy, lease, calibration
+-- certificate or similar paper kept against one asset (asset_id set) or the
+-- org as a whole (asset_id NULL), with an optional scanned copy in the object
+-- store and an optional expiry date.
+--
+-- The expiry job raises document.expiring once a document enters its
+-- remind_days window and document.expired once expires_at passes, stamping
+-- expiring_notified_at / expired_notified_at so each fires once per expiry
+-- date. Changing expires_at or remind_days clears both stamps, so a renewed
+-- document alerts again ahead of its new date.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE documents (
+    id                    BIGINT PRIMARY KEY,
+    org_id                BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    asset_id              BIGINT REFERENCES assets(id) ON DELETE CASCADE,
+    type                  TEXT NOT NULL
+                          CHECK (type IN ('insurance', 'lease', 'calibration_cert', 'warranty', 'registration', 'other')),
+    title                 VARCHAR(255) NOT NULL,
+    reference             VARCHAR(255),
+    issuer                VARCHAR(255),
+    issued_at             TIMESTAMPTZ,
+    expires_at            TIMESTAMPTZ,
+    remind_days           INT NOT NULL DEFAULT 30 CHECK (remind_days BETWEEN 0 AND 365),
+    notes                 TEXT,
+    file_url              TEXT,
+    file_content_type     TEXT,
+    file_size_bytes       INT,
+    expiring_notified_at  TIMESTAMPTZ,
+    expired_notified_at   TIMESTAMPTZ,
+    created_by            BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at            TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT documents_expiry_after_issue CHECK (expires_at > issued_at)
+);
+
+CREATE TRIGGER generate_document_id_trigger
+    BEFORE INSERT ON documents
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_documents_updated_at
+    BEFORE UPDATE ON documents
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_documents_org_expires ON documents (org_id, expires_at);
+CREATE INDEX idx_documents_asset ON documents (asset_id);
+CREATE INDEX idx_documents_expiry_due ON documents (expires_at)
+    WHERE expires_at IS NOT NULL AND expired_notified_at IS NULL;
+
+ALTER TABLE documents ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_documents ON documents
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+COMMENT ON TABLE documents IS 'Insurance, lease, calibration and other documents of an asset or the org, with expiry alerts';
+COMMENT ON COLUMN documents.asset_id IS 'The asset the document covers; NULL for an org-level document';
+COMMENT ON COLUMN documents.reference IS 'Policy, contract or certificate number';
+COMMENT ON COLUMN documents.remind_days IS 'Days before expires_at to raise document.expiring; 0 raises only document.expired';
+COMMENT ON COLUMN documents.expiring_notified_at IS 'When document.expiring was raised for the current expires_at';
+COMMENT ON COLUMN documents.expired_notified_at IS 'When document.expired was raised for the current expires_at';
@@ -0,0 +1,46 @@
+-- Custom dashboards. A dashboard is a named grid of widgets, each a saved
+-- query over one of the fixed dashboard sources plus how to draw its result.
+-- The server evaluates the queries; widgets never carry SQL.
+--
+-- A dashboard is private to its owner until shared with the org. At most one
+-- shared dashboard per org is the default, shown to members who have not
+-- picked one.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE dashboards (
+    id             BIGINT PRIMARY KEY,
+    org_id         BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    owner_user_id  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    name           VARCHAR(255) NOT NULL,
+    description    TEXT,
+    shared         BOOLEAN NOT NULL DEFAULT false,
+    is_default     BOOLEAN NOT NULL DEFAULT false,
+    widgets        JSONB NOT NULL DEFAULT '[]',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT dashboards_default_is_shared CHECK (NOT is_default OR shared)
+);
+
+CREATE TRIGGER generate_dashboard_id_trigger
+    BEFORE INSERT ON dashboards
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_dashboards_updated_at
+    BEFORE UPDATE ON dashboards
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_dashboards_org_owner ON dashboards (org_id, owner_user_id);
+CREATE UNIQUE INDEX idx_dashboards_org_default ON dashboards (org_id) WHERE is_default;
+
+ALTER TABLE dashboards ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_dashboards ON dashboards
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+COMMENT ON TABLE dashboards IS 'Custom dashboards: widget layouts over saved, server-evaluated queries';
+COMMENT ON COLUMN dashboards.owner_user_id IS 'Who created the dashboard; NULL once that user is deleted';
+COMMENT ON COLUMN dashboards.shared IS 'Visible to every org member, not just the owner';
+COMMENT ON COLUMN dashboards.is_default IS 'The org''s default dashboard; at most one, and always shared';
+COMMENT ON COLUMN dashboards.widgets IS 'Array of widgets: id, title, visualization, query and grid layout';
@@ -0,0 +1,34 @@
+-- Cached dashboard widget results. Counting a large org's assets or scans on
+-- every page load is too expensive, so each evaluated widget query is kept
+-- per org under a canonical key of the query and served from here.
+--
+-- The dashboard metrics job recomputes a result once it is older than the
+-- refresh interval, for queries some dashboard asked for within the
+-- retention window (requested_at), and drops the rest. Reads recompute
+-- inline only results that are far too old or that the caller asked to
+-- refresh. Nothing here is authoritative, so it is left out of org exports.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE dashboard_metrics (
+    org_id        BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    query_key     TEXT NOT NULL,
+    query         JSONB NOT NULL,
+    result        JSONB NOT NULL,
+    computed_at   TIMESTAMPTZ NOT NULL,
+    requested_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (org_id, query_key)
+);
+
+CREATE INDEX idx_dashboard_metrics_computed ON dashboard_metrics (computed_at);
+
+ALTER TABLE dashboard_metrics ENABLE ROW LEVEL SECURITY;
+
+CREATE POLICY org_isolation_dashboard_metrics ON dashboard_metrics
+    USING (org_id = current_setting('app.current_org_id')::BIGINT);
+
+COMMENT ON TABLE dashboard_metrics IS 'Cached dashboard widget results, refreshed by the dashboard metrics job';
+COMMENT ON COLUMN dashboard_metrics.query_key IS 'Canonical JSON of the widget query, defaults applied';
+COMMENT ON COLUMN dashboard_metrics.computed_at IS 'When the cached counts were taken';
+COMMENT ON COLUMN dashboard_metrics.requested_at IS 'When a dashboard last read this result, to the hour; unread results stop being refreshed';
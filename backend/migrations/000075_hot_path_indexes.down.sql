@@ -0,0 +1,6 @@
+SET search_path = trakrf, public;
+
+DROP INDEX IF EXISTS trakrf.idx_tags_value_type_org;
+
+CREATE INDEX IF NOT EXISTS idx_assets_org ON assets (org_id);
+DROP INDEX IF EXISTS trakrf.idx_assets_org_live_created;
@@ -0,0 +1,40 @@
+-- Custom domains: hostnames a customer points at TrakRF to serve the app
+-- from (track.acme.example). A domain is claimed by adding it, then proven by
+-- publishing verification_token in a DNS TXT record; only verified domains
+-- resolve to their org. Unverified claims never block another org, so a
+-- hostname is unique among verified domains only.
+--
+-- No row level security: the Host-based resolver runs before any org
+-- context exists (like api_keys lookups by jti), and every other query is
+-- scoped by org_id in storage.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE org_custom_domains (
+    id                  BIGINT PRIMARY KEY,
+    org_id              BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    hostname            VARCHAR(253) NOT NULL,
+    verification_token  VARCHAR(64) NOT NULL,
+    verified_at         TIMESTAMPTZ,
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT org_custom_domains_hostname_lower CHECK (hostname = lower(hostname))
+);
+
+CREATE TRIGGER generate_org_custom_domain_id_trigger
+    BEFORE INSERT ON org_custom_domains
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_org_custom_domains_updated_at
+    BEFORE UPDATE ON org_custom_domains
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE UNIQUE INDEX idx_org_custom_domains_org_hostname ON org_custom_domains (org_id, hostname);
+CREATE UNIQUE INDEX idx_org_custom_domains_verified_hostname ON org_custom_domains (hostname)
+    WHERE verified_at IS NOT NULL;
+
+COMMENT ON TABLE org_custom_domains IS 'Hostnames an org serves the app from; resolved by the Host header once verified';
+COMMENT ON COLUMN org_custom_domains.hostname IS 'Lowercase hostname without port or trailing dot';
+COMMENT ON COLUMN org_custom_domains.verification_token IS 'Value expected in the _trakrf-verify.<hostname> TXT record';
+COMMENT ON COLUMN org_custom_domains.verified_at IS 'When the TXT record was last found; NULL until then';
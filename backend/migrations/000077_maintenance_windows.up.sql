@@ -0,0 +1,38 @@
+-- Maintenance windows: periods during which the API refuses writes, for
+-- migrations and incident response. A window with no org_id covers every
+-- org; one with an org_id covers that org only. A window with no ends_at
+-- stays in force until a superadmin ends it. Ended windows are kept as a
+-- record of when the platform was read-only.
+--
+-- No row level security: windows are read by middleware before any org
+-- context exists and written only by superadmins.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE maintenance_windows (
+    id          BIGINT PRIMARY KEY,
+    org_id      BIGINT REFERENCES organizations(id) ON DELETE CASCADE,
+    reason      TEXT NOT NULL,
+    starts_at   TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    ends_at     TIMESTAMPTZ,
+    created_by  BIGINT REFERENCES users(id) ON DELETE SET NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT maintenance_windows_ends_after_start CHECK (ends_at IS NULL OR ends_at >= starts_at)
+);
+
+CREATE TRIGGER generate_maintenance_window_id_trigger
+    BEFORE INSERT ON maintenance_windows
+    FOR EACH ROW EXECUTE FUNCTION trakrf.generate_obfuscated_id();
+
+CREATE TRIGGER update_maintenance_windows_updated_at
+    BEFORE UPDATE ON maintenance_windows
+    FOR EACH ROW EXECUTE FUNCTION trakrf.update_updated_at_column();
+
+CREATE INDEX idx_maintenance_windows_ends_at ON maintenance_windows (ends_at);
+
+COMMENT ON TABLE maintenance_windows IS 'Periods during which the API rejects mutations with 503, globally or for one org';
+COMMENT ON COLUMN maintenance_windows.org_id IS 'Org the window covers; NULL for every org';
+COMMENT ON COLUMN maintenance_windows.reason IS 'Shown to callers in the 503 response';
+COMMENT ON COLUMN maintenance_windows.ends_at IS 'When writes reopen; NULL until a superadmin ends the window';
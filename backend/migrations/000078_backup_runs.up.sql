@@ -0,0 +1,32 @@
+-- Backup runs: one row per run of `server backup`, which dumps the trakrf
+-- schema to object storage and restores the dump into a scratch schema to
+-- prove it loads. /readyz reads the latest rows to report a failing or stale
+-- backup.
+--
+-- System table, no RLS: written by the backup command and read by the
+-- readiness check, neither with an org context.
+--
+-- No in-migration GRANTs: the infra init-grants Job owns privileges.
+SET search_path = trakrf, public;
+
+CREATE TABLE backup_runs (
+    id              BIGSERIAL PRIMARY KEY,
+    status          TEXT NOT NULL DEFAULT 'running',
+    schema_version  BIGINT,
+    object_key      TEXT,
+    table_count     INT NOT NULL DEFAULT 0,
+    row_count       BIGINT NOT NULL DEFAULT 0,
+    size_bytes      BIGINT NOT NULL DEFAULT 0,
+    error           TEXT,
+    started_at      TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at     TIMESTAMPTZ,
+    CONSTRAINT backup_runs_status_check CHECK (status IN ('running', 'succeeded', 'failed'))
+);
+
+-- The readiness query: latest finished run, latest successful run.
+CREATE INDEX idx_backup_runs_finished_at ON backup_runs (finished_at DESC)
+    WHERE finished_at IS NOT NULL;
+
+COMMENT ON TABLE backup_runs IS 'Runs of the logical backup and restore verification command';
+COMMENT ON COLUMN backup_runs.object_key IS 'Key of the uploaded dump; NULL when the run did not upload';
+COMMENT ON COLUMN backup_runs.error IS 'Why the run failed';
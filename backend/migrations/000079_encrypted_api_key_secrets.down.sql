@@ -0,0 +1,8 @@
+-- Fails while any secret hash is still encrypted (longer than 64
+-- characters); those keys cannot be turned back into bare hashes, so revoke
+-- and reissue them, or restore from backup, before rolling back.
+SET search_path = trakrf, public;
+
+ALTER TABLE api_keys ALTER COLUMN secret_hash TYPE VARCHAR(64);
+
+COMMENT ON COLUMN api_keys.secret_hash IS 'SHA-256 hex of the opaque client_secret shown once at creation (TRA-847). The plaintext secret is never stored.';
@@ -0,0 +1,13 @@
+-- migrate:phase contract
+--
+-- Field encryption (FIELD_ENCRYPTION_KEYS) stores API key secret hashes
+-- AES-GCM sealed, as enc:v1:<key id>:<base64>, which does not fit the
+-- VARCHAR(64) that held the bare SHA-256 hex. Widening to TEXT rewrites
+-- nothing and breaks no reader; it is a contract migration only because the
+-- linter cannot tell a widening from a narrowing, and nothing needs it
+-- before the rollout finishes: encryption is switched on afterwards.
+SET search_path = trakrf, public;
+
+ALTER TABLE api_keys ALTER COLUMN secret_hash TYPE TEXT;
+
+COMMENT ON COLUMN api_keys.secret_hash IS 'SHA-256 hex of the opaque client_secret shown once at creation (TRA-847), AES-GCM sealed when field encryption is on. The plaintext secret is never stored.';
@@ -4,7 +4,55 @@
 // the standalone `server migrate` subcommand).
 package migrations
 
-import "embed"
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
 
 //go:embed *.sql
 var FS embed.FS
+
+// LatestVersion returns the highest migration version embedded in FS — the
+// schema version this binary expects the database to be at.
+func LatestVersion() (uint, error) {
+	return LatestVersionIn(FS)
+}
+
+// Versions returns every migration version embedded in FS, ascending.
+func Versions() ([]uint, error) {
+	return versionsIn(FS)
+}
+
+// LatestVersionIn returns the highest migration version among the *.up.sql
+// files in fsys, or 0 when there are none.
+func LatestVersionIn(fsys fs.FS) (uint, error) {
+	versions, err := versionsIn(fsys)
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+func versionsIn(fsys fs.FS) ([]uint, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var versions []uint
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok || !strings.HasSuffix(e.Name(), ".up.sql") {
+			continue
+		}
+		v, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version prefix: %w", e.Name(), err)
+		}
+		versions = append(versions, uint(v))
+	}
+	// ReadDir sorts by name and versions are zero-padded, so this is ascending.
+	return versions, nil
+}
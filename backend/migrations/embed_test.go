@@ -1,6 +1,7 @@
 package migrations
 
 import (
+	"fmt"
 	"io/fs"
 	"strings"
 	"testing"
@@ -25,3 +26,17 @@ func TestFSContainsMigrations(t *testing.T) {
 		t.Fatalf("expected at least one *.up.sql file among %d entries", len(entries))
 	}
 }
+
+func TestLatestVersion(t *testing.T) {
+	v, err := LatestVersion()
+	if err != nil {
+		t.Fatalf("LatestVersion: %v", err)
+	}
+	if v == 0 {
+		t.Fatal("expected a non-zero latest version")
+	}
+	matches, err := fs.Glob(FS, fmt.Sprintf("%06d_*.up.sql", v))
+	if err != nil || len(matches) != 1 {
+		t.Fatalf("expected exactly one up migration for version %d, got %v (err %v)", v, matches, err)
+	}
+}
@@ -0,0 +1,273 @@
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Phase says when a migration may run relative to a rolling deploy.
+//
+// An expand migration only adds: the code already running keeps working
+// against the new schema, so it is applied before the new code rolls out.
+// A contract migration removes or tightens what the previous release still
+// uses (dropping a column, SET NOT NULL), so it is applied only once every
+// replica runs the new code. A migration is expand unless it says
+//
+//	-- migrate:phase contract
+type Phase string
+
+const (
+	PhaseExpand   Phase = "expand"
+	PhaseContract Phase = "contract"
+)
+
+// LintFrom is the first version Lint checks. Earlier migrations predate the
+// expand/contract convention and are left as applied.
+const LintFrom = 78
+
+// Migration is one embedded up migration.
+type Migration struct {
+	Version uint
+	Name    string // file name of the up migration
+	Phase   Phase
+}
+
+// Violation is a statement an expand migration may not contain.
+type Violation struct {
+	File string
+	Line int
+	Rule string
+	SQL  string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", v.File, v.Line, v.Rule, v.SQL)
+}
+
+// Migrations returns the embedded up migrations with their phases,
+// ascending.
+func Migrations() ([]Migration, error) {
+	return MigrationsIn(FS)
+}
+
+// MigrationsIn returns the up migrations in fsys with their phases,
+// ascending.
+func MigrationsIn(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var list []Migration
+	for _, e := range entries {
+		prefix, _, ok := strings.Cut(e.Name(), "_")
+		if !ok || !strings.HasSuffix(e.Name(), ".up.sql") {
+			continue
+		}
+		v, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: invalid version prefix: %w", e.Name(), err)
+		}
+		src, err := fs.ReadFile(fsys, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		phase, err := phaseOf(string(src))
+		if err != nil {
+			return nil, fmt.Errorf("migration %s: %w", e.Name(), err)
+		}
+		list = append(list, Migration{Version: uint(v), Name: e.Name(), Phase: phase})
+	}
+	return list, nil
+}
+
+var phaseDirective = regexp.MustCompile(`(?m)^\s*--\s*migrate:phase\s+(\S+)\s*$`)
+
+func phaseOf(src string) (Phase, error) {
+	m := phaseDirective.FindStringSubmatch(src)
+	if m == nil {
+		return PhaseExpand, nil
+	}
+	switch p := Phase(strings.ToLower(m[1])); p {
+	case PhaseExpand, PhaseContract:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown phase %q (want expand or contract)", m[1])
+	}
+}
+
+// Lint reports the destructive statements in the expand migrations of fsys
+// from LintFrom on: anything the release that is still running during a
+// rolling deploy could trip over. Such a statement belongs in a contract
+// migration, shipped in a later release than the code that stops using what
+// it removes. Down migrations are not checked.
+func Lint(fsys fs.FS) ([]Violation, error) {
+	list, err := MigrationsIn(fsys)
+	if err != nil {
+		return nil, err
+	}
+	var violations []Violation
+	for _, m := range list {
+		if m.Version < LintFrom || m.Phase == PhaseContract {
+			continue
+		}
+		src, err := fs.ReadFile(fsys, m.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, stmt := range splitStatements(string(src)) {
+			for _, rule := range destructiveRules(stmt.text) {
+				violations = append(violations, Violation{File: m.Name, Line: stmt.line, Rule: rule, SQL: stmt.text})
+			}
+		}
+	}
+	return violations, nil
+}
+
+var (
+	dropObject   = regexp.MustCompile(`^DROP (TABLE|SCHEMA|TYPE|VIEW|MATERIALIZED VIEW|FUNCTION|SEQUENCE|DOMAIN)\b`)
+	alterTable   = regexp.MustCompile(`^ALTER TABLE (IF EXISTS )?(ONLY )?\S+ (.*)$`)
+	alterRename  = regexp.MustCompile(`^ALTER (TYPE|VIEW|MATERIALIZED VIEW|FUNCTION|SEQUENCE|SCHEMA|DOMAIN) .* RENAME\b`)
+	alterType    = regexp.MustCompile(`^ALTER (COLUMN )?\S+ (SET DATA )?TYPE\b`)
+	setNotNull   = regexp.MustCompile(`^ALTER (COLUMN )?\S+ SET NOT NULL\b`)
+	addTableItem = regexp.MustCompile(`^ADD (CONSTRAINT|PRIMARY KEY|UNIQUE|FOREIGN KEY|CHECK|EXCLUDE)\b`)
+)
+
+// destructiveRules names the rules an upper-cased, whitespace-collapsed
+// statement breaks.
+func destructiveRules(stmt string) []string {
+	switch {
+	case dropObject.MatchString(stmt):
+		return []string{"drop"}
+	case strings.HasPrefix(stmt, "TRUNCATE "):
+		return []string{"truncate"}
+	case alterRename.MatchString(stmt):
+		return []string{"rename"}
+	}
+	m := alterTable.FindStringSubmatch(stmt)
+	if m == nil {
+		return nil
+	}
+	var rules []string
+	for _, action := range splitTopLevel(m[3]) {
+		switch {
+		case strings.HasPrefix(action, "DROP ") && !strings.HasPrefix(action, "DROP CONSTRAINT "):
+			rules = append(rules, "drop")
+		case strings.HasPrefix(action, "RENAME ") && !strings.HasPrefix(action, "RENAME CONSTRAINT "):
+			rules = append(rules, "rename")
+		case alterType.MatchString(action):
+			rules = append(rules, "alter_type")
+		case setNotNull.MatchString(action):
+			rules = append(rules, "set_not_null")
+		case strings.HasPrefix(action, "ADD ") && !addTableItem.MatchString(action) &&
+			strings.Contains(action, " NOT NULL") &&
+			!strings.Contains(action, " DEFAULT ") && !strings.Contains(action, " GENERATED "):
+			rules = append(rules, "not_null_without_default")
+		}
+	}
+	return rules
+}
+
+// splitTopLevel splits s on the commas outside parentheses.
+func splitTopLevel(s string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(s[start:]))
+}
+
+type statement struct {
+	line int    // line the statement starts on
+	text string // upper case, comments dropped, literals emptied, spaces collapsed
+}
+
+var dollarQuote = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// splitStatements splits a migration into statements. Comments are dropped
+// and the bodies of string and dollar-quoted literals emptied, so neither a
+// function body nor a COMMENT ON text is mistaken for a statement.
+func splitStatements(src string) []statement {
+	var stmts []statement
+	var b strings.Builder
+	line, start := 1, 0
+	flush := func() {
+		if text := strings.Join(strings.Fields(b.String()), " "); text != "" {
+			stmts = append(stmts, statement{line: start, text: strings.ToUpper(text)})
+		}
+		b.Reset()
+		start = 0
+	}
+	// skip moves past src[i:end], keeping the line count, and writes repl.
+	skip := func(i, end int, repl string) int {
+		end = min(end, len(src))
+		line += strings.Count(src[i:end], "\n")
+		b.WriteString(repl)
+		return end - 1
+	}
+
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			b.WriteByte(' ')
+			continue
+		case strings.HasPrefix(src[i:], "--"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			i = skip(i, i+end, " ")
+			continue
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				end = len(src)
+			}
+			i = skip(i, i+2+end+2, " ")
+			continue
+		case c == ';':
+			flush()
+			continue
+		}
+		if start == 0 && c != ' ' && c != '\t' && c != '\r' {
+			start = line
+		}
+		switch {
+		case c == '\'':
+			j := i + 1
+			for j < len(src) && (src[j] != '\'' || strings.HasPrefix(src[j:], "''")) {
+				if src[j] == '\'' {
+					j++
+				}
+				j++
+			}
+			i = skip(i, j+1, "''")
+		case c == '$' && dollarQuote.MatchString(src[i:]):
+			tag := dollarQuote.FindString(src[i:])
+			end := strings.Index(src[i+len(tag):], tag)
+			if end < 0 {
+				end = len(src)
+			}
+			i = skip(i, i+len(tag)+end+len(tag), "$$")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	flush()
+	return stmts
+}
@@ -0,0 +1,115 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrationsPassLint(t *testing.T) {
+	violations, err := Lint(FS)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	for _, v := range violations {
+		t.Errorf("%s (move it to a migration marked -- migrate:phase contract)", v)
+	}
+}
+
+func TestLint_Rules(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{"create table", "CREATE TABLE widgets (id BIGINT PRIMARY KEY, name TEXT NOT NULL);", nil},
+		{"add nullable column", "ALTER TABLE assets ADD COLUMN notes TEXT;", nil},
+		{"add not null with default", "ALTER TABLE assets ADD COLUMN grade INT NOT NULL DEFAULT 0;", nil},
+		{"add not null", "alter table assets\n  add column grade int not null;", []string{"not_null_without_default"}},
+		{"add check constraint", "ALTER TABLE assets ADD CONSTRAINT grade_positive CHECK (grade IS NOT NULL);", nil},
+		{"set not null", "ALTER TABLE assets ALTER COLUMN name SET NOT NULL;", []string{"set_not_null"}},
+		{"drop not null", "ALTER TABLE assets ALTER COLUMN name DROP NOT NULL;", nil},
+		{"drop column", "ALTER TABLE assets DROP COLUMN IF EXISTS legacy;", []string{"drop"}},
+		{"drop constraint", "ALTER TABLE assets DROP CONSTRAINT assets_name_key;", nil},
+		{"several actions", "ALTER TABLE assets ADD COLUMN a INT NOT NULL, DROP COLUMN b, ALTER COLUMN c TYPE BIGINT;",
+			[]string{"not_null_without_default", "drop", "alter_type"}},
+		{"drop table", "DROP TABLE IF EXISTS trakrf.widgets;", []string{"drop"}},
+		{"drop index", "DROP INDEX IF EXISTS idx_widgets_name;", nil},
+		{"rename column", "ALTER TABLE assets RENAME COLUMN name TO title;", []string{"rename"}},
+		{"rename constraint", "ALTER TABLE assets RENAME CONSTRAINT a_key TO b_key;", nil},
+		{"rename type value", "ALTER TYPE asset_state RENAME VALUE 'lost' TO 'missing';", []string{"rename"}},
+		{"truncate", "TRUNCATE assets;", []string{"truncate"}},
+		{"function body", "CREATE FUNCTION f() RETURNS void AS $fn$ BEGIN DROP TABLE t; END $fn$ LANGUAGE plpgsql;", nil},
+		{"string literal", "COMMENT ON TABLE assets IS 'DROP TABLE assets; is not run';", nil},
+		{"comments", "-- DROP TABLE assets;\n/* TRUNCATE assets; */ SELECT 1;", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"000100_change.up.sql": {Data: []byte(tt.sql)}}
+			violations, err := Lint(fsys)
+			if err != nil {
+				t.Fatalf("Lint: %v", err)
+			}
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.Rule)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("rules = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("rules = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLint_ContractAndLegacyMigrationsAreExempt(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000010_legacy.up.sql":        {Data: []byte("DROP TABLE old_widgets;")},
+		"000100_drop_legacy.up.sql":   {Data: []byte("-- migrate:phase contract\nALTER TABLE assets DROP COLUMN legacy;")},
+		"000100_drop_legacy.down.sql": {Data: []byte("ALTER TABLE assets ADD COLUMN legacy TEXT NOT NULL;")},
+		"000101_widgets.up.sql":       {Data: []byte("SET search_path = trakrf, public;\n\nCREATE TABLE w (id INT);\n\nDROP TABLE old_widgets;\n")},
+	}
+	violations, err := Lint(fsys)
+	if err != nil {
+		t.Fatalf("Lint: %v", err)
+	}
+	if len(violations) != 1 {
+		t.Fatalf("violations = %v, want one", violations)
+	}
+	if v := violations[0]; v.File != "000101_widgets.up.sql" || v.Line != 5 || v.Rule != "drop" {
+		t.Errorf("violation = %s, want 000101_widgets.up.sql:5: drop", v)
+	}
+}
+
+func TestMigrationsIn_Phases(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000100_a.up.sql": {Data: []byte("CREATE TABLE a (id INT);")},
+		"000101_b.up.sql": {Data: []byte("-- migrate:phase contract\nDROP TABLE a;")},
+		"000102_c.up.sql": {Data: []byte("--migrate:phase Expand\nSELECT 1;")},
+	}
+	list, err := MigrationsIn(fsys)
+	if err != nil {
+		t.Fatalf("MigrationsIn: %v", err)
+	}
+	want := []Migration{
+		{Version: 100, Name: "000100_a.up.sql", Phase: PhaseExpand},
+		{Version: 101, Name: "000101_b.up.sql", Phase: PhaseContract},
+		{Version: 102, Name: "000102_c.up.sql", Phase: PhaseExpand},
+	}
+	if len(list) != len(want) {
+		t.Fatalf("list = %+v, want %+v", list, want)
+	}
+	for i := range want {
+		if list[i] != want[i] {
+			t.Errorf("list[%d] = %+v, want %+v", i, list[i], want[i])
+		}
+	}
+
+	fsys["000103_d.up.sql"] = &fstest.MapFile{Data: []byte("-- migrate:phase later\n")}
+	if _, err := MigrationsIn(fsys); err == nil {
+		t.Error("an unknown phase must be an error")
+	}
+}
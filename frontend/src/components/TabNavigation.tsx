@@ -4,6 +4,7 @@ import type { TabType } from '@/stores';
 import { ReaderState } from '@/worker/types/reader';
 import { Package2, Search, Settings, HelpCircle, Package, MapPinned, BarChart3, RadioTower, Siren, Radio, SlidersHorizontal, Boxes } from 'lucide-react';
 import { appVersion } from '@/version';
+import { getAppConfig } from '@/lib/appConfig';
 
 interface NavItemProps {
   id: TabType;
@@ -112,6 +113,7 @@ export default function TabNavigation() {
     }
   };
 
+  const theme = getAppConfig().theme;
   const deviceStatus = getDeviceStatus();
   
   return (
@@ -120,9 +122,13 @@ export default function TabNavigation() {
       <div className="p-6 border-b border-gray-200 dark:border-gray-700">
         <div className="relative group">
           <div className="flex items-center">
-            <img src="/logo.png" alt="TrakRF Logo" className="w-8 h-8 mr-3" />
+            <img
+              src={theme?.logoUrl || '/logo.png'}
+              alt={`${theme?.orgName ?? 'TrakRF'} Logo`}
+              className="w-8 h-8 mr-3"
+            />
             <div>
-              <h1 className="text-lg font-bold text-gray-900 dark:text-gray-100">TrakRF</h1>
+              <h1 className="text-lg font-bold text-gray-900 dark:text-gray-100">{theme?.orgName ?? 'TrakRF'}</h1>
               <p className="text-sm text-gray-500 dark:text-gray-400">Handheld Tag Reader</p>
               <p className="text-xs text-gray-400 dark:text-gray-500 mt-0.5">{appVersion}</p>
             </div>
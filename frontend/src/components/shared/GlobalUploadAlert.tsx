@@ -31,7 +31,7 @@ export function GlobalUploadAlert() {
     enabled: !!activeJobId,
     refetchInterval: (query) => {
       const status = query.state.data?.status;
-      if (status === 'pending' || status === 'processing') {
+      if (status === 'pending' || status === 'processing' || status === 'interrupted') {
         return 2000;
       }
       return false;
@@ -90,6 +90,7 @@ export function GlobalUploadAlert() {
   switch (jobStatus.status) {
     case 'pending':
     case 'processing':
+    case 'interrupted':
       return <ProcessingAlert jobStatus={jobStatus} onDismiss={handleDismiss} />;
 
     case 'completed':
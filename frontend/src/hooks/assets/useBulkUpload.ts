@@ -29,7 +29,7 @@ export function useBulkUpload() {
     enabled: !!jobId,
     refetchInterval: (query) => {
       const status = query.state.data?.status;
-      return status === 'processing' || status === 'pending' ? 2000 : false;
+      return status === 'processing' || status === 'pending' || status === 'interrupted' ? 2000 : false;
     },
   });
 
@@ -1,5 +1,5 @@
 import { describe, it, expect, afterEach } from 'vitest';
-import { getAppConfig, isNonProd } from './appConfig';
+import { applyAppTheme, getAppConfig, isNonProd } from './appConfig';
 
 afterEach(() => {
   delete (window as Window).__APP_CONFIG__;
@@ -22,6 +22,28 @@ describe('getAppConfig', () => {
   });
 });
 
+describe('app theme', () => {
+  it('returns the injected theme', () => {
+    window.__APP_CONFIG__ = {
+      environmentLabel: '',
+      theme: { orgName: 'Acme', primaryColor: '#0f766e' },
+    };
+    expect(getAppConfig().theme).toEqual({ orgName: 'Acme', primaryColor: '#0f766e' });
+  });
+
+  it('has no theme on unbranded loads', () => {
+    window.__APP_CONFIG__ = { environmentLabel: '' };
+    expect(getAppConfig().theme).toBeUndefined();
+  });
+
+  it('publishes only the colors that are set', () => {
+    const root = document.createElement('div');
+    applyAppTheme({ orgName: 'Acme', accentColor: '#f59e0b' }, root);
+    expect(root.style.getPropertyValue('--app-accent')).toBe('#f59e0b');
+    expect(root.style.getPropertyValue('--app-primary')).toBe('');
+  });
+});
+
 describe('isNonProd', () => {
   it.each(['preview', 'GKE pre-prod', 'staging', 'dev'])(
     'is true for non-prod label %j',
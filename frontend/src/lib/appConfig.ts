@@ -3,14 +3,25 @@
 // environment identity out of the build: one immutable bundle renders the
 // correct banner in any environment based on the pod's ENVIRONMENT_LABEL.
 
+// White-label branding, injected only when the page was loaded on an org's
+// themed entry point (its subdomain, /o/<identifier> path or link domain).
+export interface AppTheme {
+  orgName: string;
+  logoUrl?: string;
+  primaryColor?: string;
+  accentColor?: string;
+}
+
 export interface AppConfig {
   environmentLabel: string;
+  theme?: AppTheme;
 }
 
 declare global {
   interface Window {
     __APP_CONFIG__?: {
       environmentLabel?: string;
+      theme?: AppTheme;
     };
   }
 }
@@ -21,9 +32,22 @@ export function getAppConfig(): AppConfig {
   const raw = typeof window !== 'undefined' ? window.__APP_CONFIG__ : undefined;
   return {
     environmentLabel: raw?.environmentLabel ?? '',
+    theme: raw?.theme,
   };
 }
 
+// Publishes the theme's colors as CSS custom properties (--app-primary,
+// --app-accent) on the root element, for styles that opt into branding.
+// The backend only accepts #rrggbb colors, so they are safe to set verbatim.
+export function applyAppTheme(
+  theme: AppTheme | undefined,
+  root: HTMLElement = document.documentElement
+): void {
+  if (!theme) return;
+  if (theme.primaryColor) root.style.setProperty('--app-primary', theme.primaryColor);
+  if (theme.accentColor) root.style.setProperty('--app-accent', theme.accentColor);
+}
+
 // True for any deployed non-production environment (preview, GKE dry-run, etc.).
 // Drives both the environment banner's visibility and the SPA's test-hook gate,
 // mirroring the backend testhandler's "APP_ENV != production" rule.
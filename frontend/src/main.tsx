@@ -27,7 +27,11 @@ import { QueryClientProvider } from '@tanstack/react-query';
 import App from './App';
 import './styles/globals.css';
 import { queryClient } from '@/lib/queryClient';
-import { getAppConfig, isNonProd } from '@/lib/appConfig';
+import { applyAppTheme, getAppConfig, isNonProd } from '@/lib/appConfig';
+
+// White-label loads arrive with the org's theme already in the page config;
+// apply its colors before the first render so there is no unbranded flash.
+applyAppTheme(getAppConfig().theme);
 
 // Function to initialize the app - only called in non-test environments
 function initializeApp() {
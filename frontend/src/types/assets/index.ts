@@ -108,9 +108,10 @@ export interface BulkUploadResponse {
 }
 
 /**
- * Job status enum - matches backend job states
+ * Job status enum - matches backend job states. 'interrupted' means the
+ * server shut down mid-import; another replica resumes it shortly.
  */
-export type JobStatus = 'pending' | 'processing' | 'completed' | 'failed';
+export type JobStatus = 'pending' | 'processing' | 'interrupted' | 'completed' | 'failed';
 
 /**
  * Job status response - matches JobStatusResponse